@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// alertsCommand builds the "alerts" command for user-defined price and indicator alerts
+func alertsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "alerts",
+		Usage: "Manage custom price and indicator alerts",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "list",
+				Usage:  "List the chat's custom alerts",
+				Action: listAlerts,
+				Flags:  []cli.Flag{chatIDFlag(true)},
+			},
+			{
+				Name:      "add",
+				Usage:     "Create an alert from a rule",
+				ArgsUsage: `<rule>  (e.g. "BTC/USDT RSI(14,1h) < 30" or "ETH/USDT on okx price crosses above 3000")`,
+				Action:    addAlert,
+				Flags:     []cli.Flag{chatIDFlag(true)},
+			},
+			{
+				Name:      "preview",
+				Usage:     "Show how often a rule would have fired over the last 30 days",
+				ArgsUsage: `<rule>  (e.g. "BTC/USDT price crosses above SMA(50,4h) AND funding < 0")`,
+				Action:    previewAlert,
+			},
+			{
+				Name:      "pause",
+				Usage:     "Stop evaluating an alert",
+				ArgsUsage: "<alert-id>",
+				Action:    func(cCtx *cli.Context) error { return setAlertActive(cCtx, false) },
+				Flags:     []cli.Flag{chatIDFlag(true)},
+			},
+			{
+				Name:      "resume",
+				Usage:     "Evaluate a paused alert again",
+				ArgsUsage: "<alert-id>",
+				Action:    func(cCtx *cli.Context) error { return setAlertActive(cCtx, true) },
+				Flags:     []cli.Flag{chatIDFlag(true)},
+			},
+			{
+				Name:      "remove",
+				Usage:     "Delete an alert",
+				ArgsUsage: "<alert-id>",
+				Action:    removeAlert,
+				Flags:     []cli.Flag{chatIDFlag(true)},
+			},
+		},
+	}
+}
+
+// alertsChatID returns the chat the alerts belong to
+func alertsChatID(cCtx *cli.Context) (string, error) {
+	chatID := chatIDValue(cCtx)
+	if chatID == "" {
+		return "", fmt.Errorf("chat-id is required")
+	}
+	return chatID, nil
+}
+
+// listAlerts prints the chat's custom alerts
+func listAlerts(cCtx *cli.Context) error {
+	chatID, err := alertsChatID(cCtx)
+	if err != nil {
+		return err
+	}
+	out := newOutput(cCtx)
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	response, err := client.ListCustomAlerts(chatID)
+	if err != nil {
+		return fmt.Errorf("failed to list alerts: %w", err)
+	}
+
+	alerts := response.Data.Alerts
+	return out.Render(alerts, func() {
+		if len(alerts) == 0 {
+			out.Println("No custom alerts. Add one with: neuratrade alerts add \"BTC/USDT price crosses 70000\"")
+			return
+		}
+		for _, alert := range alerts {
+			state := "active"
+			if !alert.IsActive {
+				state = "paused"
+			}
+			out.Printf("%s  [%s]  %s\n", alert.ID, state, alert.Expression)
+			if alert.LastTriggeredAt != nil {
+				out.Printf("    last triggered %s\n", *alert.LastTriggeredAt)
+			}
+		}
+	})
+}
+
+// addAlert creates an alert from the rule given as arguments
+func addAlert(cCtx *cli.Context) error {
+	chatID, err := alertsChatID(cCtx)
+	if err != nil {
+		return err
+	}
+	rule := strings.Join(cCtx.Args().Slice(), " ")
+	if strings.TrimSpace(rule) == "" {
+		return fmt.Errorf("a rule is required, for example: neuratrade alerts add \"BTC/USDT RSI(14,1h) < 30\"")
+	}
+	out := newOutput(cCtx)
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	response, err := client.CreateCustomAlert(&CreateCustomAlertRequest{ChatID: chatID, Rule: rule})
+	if err != nil {
+		return fmt.Errorf("failed to create alert: %w", err)
+	}
+
+	alert := response.Data
+	return out.Render(alert, func() {
+		out.Printf("✅ Alert created: %s\n", alert.Expression)
+		out.Printf("ID: %s\n", alert.ID)
+	})
+}
+
+// previewAlert backtests a rule without saving it
+func previewAlert(cCtx *cli.Context) error {
+	rule := strings.Join(cCtx.Args().Slice(), " ")
+	if strings.TrimSpace(rule) == "" {
+		return fmt.Errorf("a rule is required, for example: neuratrade alerts preview \"BTC/USDT RSI(14,1h) < 30\"")
+	}
+	out := newOutput(cCtx)
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	response, err := client.PreviewCustomAlert(&PreviewCustomAlertRequest{Rule: rule})
+	if err != nil {
+		return fmt.Errorf("failed to preview alert: %w", err)
+	}
+
+	preview := response.Data
+	return out.Render(preview, func() {
+		out.Printf("🔎 %s on %s\n", preview.Expression, preview.Exchange)
+		out.Printf("Would have fired %d times (%.2f/day) over %d %s steps from %s to %s\n",
+			preview.Triggers, preview.TriggersPerDay, preview.Evaluations, preview.Resolution, preview.From, preview.To)
+		for _, at := range preview.RecentTriggers {
+			out.Printf("  - %s\n", at)
+		}
+		for _, note := range preview.Notes {
+			out.Printf("Note: %s\n", note)
+		}
+	})
+}
+
+// setAlertActive pauses or resumes an alert
+func setAlertActive(cCtx *cli.Context, active bool) error {
+	chatID, err := alertsChatID(cCtx)
+	if err != nil {
+		return err
+	}
+	alertID := cCtx.Args().First()
+	if alertID == "" {
+		return fmt.Errorf("an alert ID is required (see neuratrade alerts list)")
+	}
+	out := newOutput(cCtx)
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	response, err := client.UpdateCustomAlert(alertID, &UpdateCustomAlertRequest{ChatID: chatID, IsActive: &active})
+	if err != nil {
+		return fmt.Errorf("failed to update alert: %w", err)
+	}
+
+	return out.Render(response.Data, func() {
+		if active {
+			out.Printf("▶️  Alert %s resumed\n", alertID)
+		} else {
+			out.Printf("⏸️  Alert %s paused\n", alertID)
+		}
+	})
+}
+
+// removeAlert deletes an alert
+func removeAlert(cCtx *cli.Context) error {
+	chatID, err := alertsChatID(cCtx)
+	if err != nil {
+		return err
+	}
+	alertID := cCtx.Args().First()
+	if alertID == "" {
+		return fmt.Errorf("an alert ID is required (see neuratrade alerts list)")
+	}
+	out := newOutput(cCtx)
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	response, err := client.DeleteCustomAlert(alertID, chatID)
+	if err != nil {
+		return fmt.Errorf("failed to delete alert: %w", err)
+	}
+
+	return out.Render(response.Data, func() {
+		out.Printf("🗑️  Alert %s deleted\n", alertID)
+	})
+}
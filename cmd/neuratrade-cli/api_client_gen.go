@@ -0,0 +1,1863 @@
+// Code generated by apigen from openapi.json; DO NOT EDIT.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// AIModelInfo is generated from the AIModelInfo schema.
+type AIModelInfo struct {
+	ContextLimit   int    `json:"context_limit"`
+	Cost           string `json:"cost"`
+	DisplayName    string `json:"display_name"`
+	LatencyClass   string `json:"latency_class"`
+	ModelID        string `json:"model_id"`
+	Provider       string `json:"provider"`
+	SupportsTools  bool   `json:"supports_tools"`
+	SupportsVision bool   `json:"supports_vision"`
+}
+
+// AIModelsResponse is generated from the AIModelsResponse schema.
+type AIModelsResponse struct {
+	Models []AIModelInfo `json:"models"`
+}
+
+// AlertCondition is generated from the AlertCondition schema.
+type AlertCondition struct {
+	Left     *AlertOperand    `json:"left,omitempty"`
+	Logic    string           `json:"logic,omitempty"`
+	Operator string           `json:"operator,omitempty"`
+	Right    *AlertOperand    `json:"right,omitempty"`
+	Terms    []AlertCondition `json:"terms,omitempty"`
+}
+
+// AlertOperand is generated from the AlertOperand schema.
+type AlertOperand struct {
+	Exchange  string  `json:"exchange,omitempty"`
+	Indicator string  `json:"indicator"`
+	Period    int     `json:"period,omitempty"`
+	Timeframe string  `json:"timeframe,omitempty"`
+	Value     float64 `json:"value,omitempty"`
+}
+
+// AlertPreview is generated from the AlertPreview schema.
+type AlertPreview struct {
+	Evaluations    int      `json:"evaluations"`
+	Exchange       string   `json:"exchange"`
+	Expression     string   `json:"expression"`
+	From           string   `json:"from"`
+	Notes          []string `json:"notes,omitempty"`
+	RecentTriggers []string `json:"recent_triggers"`
+	Resolution     string   `json:"resolution"`
+	To             string   `json:"to"`
+	Triggers       int      `json:"triggers"`
+	TriggersPerDay float64  `json:"triggers_per_day"`
+}
+
+// AlertPreviewEnvelope is generated from the AlertPreviewEnvelope schema.
+type AlertPreviewEnvelope struct {
+	Data   AlertPreview `json:"data"`
+	Status string       `json:"status"`
+}
+
+// AlertRule is generated from the AlertRule schema.
+type AlertRule struct {
+	Condition AlertCondition `json:"condition"`
+	Exchange  string         `json:"exchange,omitempty"`
+	Symbol    string         `json:"symbol"`
+}
+
+// AssetValuation is generated from the AssetValuation schema.
+type AssetValuation struct {
+	Asset     string `json:"asset"`
+	Free      string `json:"free"`
+	FreeValue string `json:"free_value"`
+	Price     string `json:"price"`
+	Total     string `json:"total"`
+	Value     string `json:"value"`
+}
+
+// AuditAnchor is generated from the AuditAnchor schema.
+type AuditAnchor struct {
+	CreatedAt string `json:"created_at"`
+	Hash      string `json:"hash"`
+	Seq       int64  `json:"seq"`
+	Signature string `json:"signature,omitempty"`
+}
+
+// AuditAnchorEnvelope is generated from the AuditAnchorEnvelope schema.
+type AuditAnchorEnvelope struct {
+	Data   AuditAnchor `json:"data"`
+	Status string      `json:"status"`
+}
+
+// AuditChainIssue is generated from the AuditChainIssue schema.
+type AuditChainIssue struct {
+	Detail  string `json:"detail"`
+	Kind    string `json:"kind,omitempty"`
+	Problem string `json:"problem"`
+	RefID   string `json:"ref_id,omitempty"`
+	Seq     int64  `json:"seq,omitempty"`
+}
+
+// AuditChainReport is generated from the AuditChainReport schema.
+type AuditChainReport struct {
+	Anchors        int               `json:"anchors"`
+	Entries        int64             `json:"entries"`
+	HeadHash       string            `json:"head_hash,omitempty"`
+	HeadSeq        int64             `json:"head_seq"`
+	IssueCount     int               `json:"issue_count"`
+	Issues         []AuditChainIssue `json:"issues"`
+	LastAnchor     *AuditAnchor      `json:"last_anchor,omitempty"`
+	RecordsChecked int               `json:"records_checked"`
+	Signed         bool              `json:"signed"`
+	Valid          bool              `json:"valid"`
+	VerifiedAt     string            `json:"verified_at"`
+}
+
+// AuditChainReportEnvelope is generated from the AuditChainReportEnvelope schema.
+type AuditChainReportEnvelope struct {
+	Data   AuditChainReport `json:"data"`
+	Status string           `json:"status"`
+}
+
+// AutonomousStateRequest is generated from the AutonomousStateRequest schema.
+type AutonomousStateRequest struct {
+	ChatID  string `json:"chat_id"`
+	Profile string `json:"profile,omitempty"`
+}
+
+// AutonomousStateResponse is generated from the AutonomousStateResponse schema.
+type AutonomousStateResponse struct {
+	FailedChecks    []string         `json:"failed_checks,omitempty"`
+	Message         string           `json:"message"`
+	Mode            string           `json:"mode,omitempty"`
+	OK              bool             `json:"ok"`
+	Profile         string           `json:"profile,omitempty"`
+	Readiness       *ReadinessReport `json:"readiness,omitempty"`
+	ReadinessPassed *bool            `json:"readiness_passed,omitempty"`
+	Status          string           `json:"status"`
+}
+
+// BulkMarketResponse is generated from the BulkMarketResponse schema.
+type BulkMarketResponse struct {
+	Columns     []string        `json:"columns"`
+	GeneratedAt int64           `json:"generated_at"`
+	Rows        [][]interface{} `json:"rows"`
+}
+
+// CacheMetrics is generated from the CacheMetrics schema.
+type CacheMetrics struct {
+	ByCategory       map[string]CacheStats `json:"by_category"`
+	ConnectedClients int64                 `json:"connected_clients"`
+	KeyCount         int64                 `json:"key_count"`
+	MemoryUsageBytes int64                 `json:"memory_usage_bytes"`
+	Overall          CacheStats            `json:"overall"`
+	RedisInfo        map[string]string     `json:"redis_info"`
+}
+
+// CacheStats is generated from the CacheStats schema.
+type CacheStats struct {
+	HitRate     float64 `json:"hit_rate"`
+	Hits        int64   `json:"hits"`
+	LastUpdated string  `json:"last_updated"`
+	Misses      int64   `json:"misses"`
+	TotalOps    int64   `json:"total_ops"`
+}
+
+// CarryDistribution is generated from the CarryDistribution schema.
+type CarryDistribution struct {
+	Latest float64 `json:"latest"`
+	Max    float64 `json:"max"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	Min    float64 `json:"min"`
+	P10    float64 `json:"p10"`
+	P90    float64 `json:"p90"`
+}
+
+// CarryReport is generated from the CarryReport schema.
+type CarryReport struct {
+	Days        int          `json:"days"`
+	From        string       `json:"from"`
+	GeneratedAt string       `json:"generated_at"`
+	Markets     []CarryStats `json:"markets"`
+}
+
+// CarryReportEnvelope is generated from the CarryReportEnvelope schema.
+type CarryReportEnvelope struct {
+	Data   CarryReport `json:"data"`
+	Status string      `json:"status"`
+}
+
+// CarryStats is generated from the CarryStats schema.
+type CarryStats struct {
+	AnnualizedCarryPct float64            `json:"annualized_carry_pct"`
+	Basis              *CarryDistribution `json:"basis,omitempty"`
+	Exchange           string             `json:"exchange"`
+	From               string             `json:"from"`
+	Funding            CarryDistribution  `json:"funding"`
+	IntervalHours      float64            `json:"interval_hours"`
+	PositivePct        float64            `json:"positive_pct"`
+	Samples            int                `json:"samples"`
+	Symbol             string             `json:"symbol"`
+	To                 string             `json:"to"`
+}
+
+// ChaosStatusResponse is generated from the ChaosStatusResponse schema.
+type ChaosStatusResponse struct {
+	Cleared int      `json:"cleared,omitempty"`
+	Faults  []Fault  `json:"faults"`
+	Targets []string `json:"targets"`
+}
+
+// ChaosStatusResponseEnvelope is generated from the ChaosStatusResponseEnvelope schema.
+type ChaosStatusResponseEnvelope struct {
+	Data   ChaosStatusResponse `json:"data"`
+	Status string              `json:"status"`
+}
+
+// ClientCompat is generated from the ClientCompat schema.
+type ClientCompat struct {
+	Compatible bool   `json:"compatible"`
+	MinVersion string `json:"min_version,omitempty"`
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+}
+
+// CollectNowRequest is generated from the CollectNowRequest schema.
+type CollectNowRequest struct {
+	Candles   int    `json:"candles,omitempty"`
+	Exchange  string `json:"exchange"`
+	Symbol    string `json:"symbol"`
+	Timeframe string `json:"timeframe,omitempty"`
+}
+
+// CompatResponse is generated from the CompatResponse schema.
+type CompatResponse struct {
+	APIVersion        string            `json:"api_version"`
+	Client            *ClientCompat     `json:"client,omitempty"`
+	MinClientVersions map[string]string `json:"min_client_versions"`
+	ServerVersion     string            `json:"server_version"`
+}
+
+// CreateCustomAlertRequest is generated from the CreateCustomAlertRequest schema.
+type CreateCustomAlertRequest struct {
+	ChatID string `json:"chat_id"`
+	Rule   string `json:"rule"`
+}
+
+// CustomAlert is generated from the CustomAlert schema.
+type CustomAlert struct {
+	CreatedAt       string    `json:"created_at"`
+	Expression      string    `json:"expression"`
+	ID              string    `json:"id"`
+	IsActive        bool      `json:"is_active"`
+	LastTriggeredAt *string   `json:"last_triggered_at,omitempty"`
+	LastValue       *float64  `json:"last_value,omitempty"`
+	Rule            AlertRule `json:"rule"`
+}
+
+// CustomAlertChangeResponse is generated from the CustomAlertChangeResponse schema.
+type CustomAlertChangeResponse struct {
+	Deleted  bool   `json:"deleted,omitempty"`
+	ID       string `json:"id"`
+	IsActive *bool  `json:"is_active,omitempty"`
+}
+
+// CustomAlertChangeResponseEnvelope is generated from the CustomAlertChangeResponseEnvelope schema.
+type CustomAlertChangeResponseEnvelope struct {
+	Data   CustomAlertChangeResponse `json:"data"`
+	Status string                    `json:"status"`
+}
+
+// CustomAlertEnvelope is generated from the CustomAlertEnvelope schema.
+type CustomAlertEnvelope struct {
+	Data   CustomAlert `json:"data"`
+	Status string      `json:"status"`
+}
+
+// CustomAlertListResponse is generated from the CustomAlertListResponse schema.
+type CustomAlertListResponse struct {
+	Alerts []CustomAlert `json:"alerts"`
+}
+
+// CustomAlertListResponseEnvelope is generated from the CustomAlertListResponseEnvelope schema.
+type CustomAlertListResponseEnvelope struct {
+	Data   CustomAlertListResponse `json:"data"`
+	Status string                  `json:"status"`
+}
+
+// EffectiveConfig is generated from the EffectiveConfig schema.
+type EffectiveConfig struct {
+	Active    bool                   `json:"active"`
+	Mode      string                 `json:"mode"`
+	Overrides []string               `json:"overrides"`
+	Settings  map[string]interface{} `json:"settings"`
+}
+
+// EffectiveConfigEnvelope is generated from the EffectiveConfigEnvelope schema.
+type EffectiveConfigEnvelope struct {
+	Data   EffectiveConfig `json:"data"`
+	Status string          `json:"status"`
+}
+
+// EquitySnapshot is generated from the EquitySnapshot schema.
+type EquitySnapshot struct {
+	AvailableBalance string                 `json:"available_balance"`
+	Currency         string                 `json:"currency"`
+	ExchangeBalance  string                 `json:"exchange_balance"`
+	Exchanges        []ExchangeEquity       `json:"exchanges"`
+	Exposure         string                 `json:"exposure"`
+	ExternalBalance  string                 `json:"external_balance"`
+	Reserve          string                 `json:"reserve"`
+	TotalEquity      string                 `json:"total_equity"`
+	TradingEquity    string                 `json:"trading_equity"`
+	UnrealizedPnl    string                 `json:"unrealized_pnl"`
+	UpdatedAt        string                 `json:"updated_at"`
+	Wallets          []ExternalWalletEquity `json:"wallets"`
+	Warnings         []string               `json:"warnings,omitempty"`
+}
+
+// EquitySnapshotEnvelope is generated from the EquitySnapshotEnvelope schema.
+type EquitySnapshotEnvelope struct {
+	Data   EquitySnapshot `json:"data"`
+	Status string         `json:"status"`
+}
+
+// ExchangeEquity is generated from the ExchangeEquity schema.
+type ExchangeEquity struct {
+	Assets    []AssetValuation `json:"assets"`
+	Available string           `json:"available"`
+	Error     string           `json:"error,omitempty"`
+	Exchange  string           `json:"exchange"`
+	Total     string           `json:"total"`
+}
+
+// ExternalWalletEquity is generated from the ExternalWalletEquity schema.
+type ExternalWalletEquity struct {
+	Error    string `json:"error,omitempty"`
+	Provider string `json:"provider"`
+	Value    string `json:"value"`
+	WalletID string `json:"wallet_id"`
+}
+
+// Fault is generated from the Fault schema.
+type Fault struct {
+	ExpiresAt string `json:"expires_at"`
+	Injected  int64  `json:"injected"`
+	Kind      string `json:"kind"`
+	LatencyMs int64  `json:"latency_ms,omitempty"`
+	StartedAt string `json:"started_at"`
+	Target    string `json:"target"`
+}
+
+// FaultEnvelope is generated from the FaultEnvelope schema.
+type FaultEnvelope struct {
+	Data   Fault  `json:"data"`
+	Status string `json:"status"`
+}
+
+// GoLiveSchedule is generated from the GoLiveSchedule schema.
+type GoLiveSchedule struct {
+	ActivateAt  string  `json:"activate_at"`
+	ActivatedAt *string `json:"activated_at,omitempty"`
+	CancelledBy string  `json:"cancelled_by,omitempty"`
+	ConfirmedBy string  `json:"confirmed_by"`
+	Error       string  `json:"error,omitempty"`
+	ID          string  `json:"id"`
+	ScheduledAt string  `json:"scheduled_at"`
+	ScheduledBy string  `json:"scheduled_by"`
+	Status      string  `json:"status"`
+}
+
+// GoLiveScheduleEnvelope is generated from the GoLiveScheduleEnvelope schema.
+type GoLiveScheduleEnvelope struct {
+	Data   GoLiveSchedule `json:"data"`
+	Status string         `json:"status"`
+}
+
+// HealthResponse is generated from the HealthResponse schema.
+type HealthResponse struct {
+	CacheMetrics *CacheMetrics         `json:"cache_metrics,omitempty"`
+	CacheStats   map[string]CacheStats `json:"cache_stats,omitempty"`
+	LoadShedding *LoadSheddingStatus   `json:"load_shedding,omitempty"`
+	Services     map[string]string     `json:"services"`
+	Status       string                `json:"status"`
+	Timestamp    string                `json:"timestamp"`
+	Uptime       string                `json:"uptime"`
+	Version      string                `json:"version"`
+}
+
+// InjectFaultRequest is generated from the InjectFaultRequest schema.
+type InjectFaultRequest struct {
+	DurationSeconds int64  `json:"duration_seconds"`
+	LatencyMs       int64  `json:"latency_ms"`
+	Target          string `json:"target"`
+}
+
+// InstallStrategyRequest is generated from the InstallStrategyRequest schema.
+type InstallStrategyRequest struct {
+	ChatID   string `json:"chat_id"`
+	Document string `json:"document"`
+}
+
+// InstallStrategyResponse is generated from the InstallStrategyResponse schema.
+type InstallStrategyResponse struct {
+	Strategy InstalledStrategy `json:"strategy"`
+	Warnings []string          `json:"warnings,omitempty"`
+}
+
+// InstallStrategyResponseEnvelope is generated from the InstallStrategyResponseEnvelope schema.
+type InstallStrategyResponseEnvelope struct {
+	Data   InstallStrategyResponse `json:"data"`
+	Status string                  `json:"status"`
+}
+
+// InstalledStrategy is generated from the InstalledStrategy schema.
+type InstalledStrategy struct {
+	Config      StrategyConfig `json:"config"`
+	InstalledAt string         `json:"installed_at"`
+}
+
+// LoadSheddingStatus is generated from the LoadSheddingStatus schema.
+type LoadSheddingStatus struct {
+	Actions       []string `json:"actions,omitempty"`
+	CpuPercent    float64  `json:"cpu_percent"`
+	Enabled       bool     `json:"enabled"`
+	Level         string   `json:"level"`
+	MemoryPercent float64  `json:"memory_percent"`
+	SampledAt     string   `json:"sampled_at,omitempty"`
+	Since         string   `json:"since,omitempty"`
+}
+
+// MarketPrice is generated from the MarketPrice schema.
+type MarketPrice struct {
+	Ask          string `json:"ask"`
+	AskVolume    string `json:"ask_volume"`
+	Bid          string `json:"bid"`
+	BidVolume    string `json:"bid_volume"`
+	ExchangeID   int    `json:"exchange_id"`
+	ExchangeName string `json:"exchange_name"`
+	High24h      string `json:"high_24h"`
+	Low24h       string `json:"low_24h"`
+	Price        string `json:"price"`
+	Symbol       string `json:"symbol"`
+	Timestamp    string `json:"timestamp"`
+	Volume       string `json:"volume"`
+}
+
+// OHLCV is generated from the OHLCV schema.
+type OHLCV struct {
+	Close     string `json:"close"`
+	High      string `json:"high"`
+	Low       string `json:"low"`
+	Open      string `json:"open"`
+	Timestamp string `json:"timestamp"`
+	Volume    string `json:"volume"`
+}
+
+// OnDemandCollection is generated from the OnDemandCollection schema.
+type OnDemandCollection struct {
+	CandleError string       `json:"candle_error,omitempty"`
+	Candles     []OHLCV      `json:"candles,omitempty"`
+	DurationMs  int64        `json:"duration_ms"`
+	Exchange    string       `json:"exchange"`
+	FetchedAt   string       `json:"fetched_at"`
+	Rejected    string       `json:"rejected,omitempty"`
+	Stored      bool         `json:"stored"`
+	Symbol      string       `json:"symbol"`
+	Ticker      *MarketPrice `json:"ticker,omitempty"`
+	Timeframe   string       `json:"timeframe,omitempty"`
+}
+
+// OnDemandCollectionEnvelope is generated from the OnDemandCollectionEnvelope schema.
+type OnDemandCollectionEnvelope struct {
+	Data   OnDemandCollection `json:"data"`
+	Status string             `json:"status"`
+}
+
+// OperatingProfile is generated from the OperatingProfile schema.
+type OperatingProfile struct {
+	AIUsage             string  `json:"ai_usage"`
+	BuiltIn             bool    `json:"built_in"`
+	DailyLossPct        float64 `json:"daily_loss_pct"`
+	Description         string  `json:"description,omitempty"`
+	MaxPositionPct      float64 `json:"max_position_pct"`
+	MinConfidence       float64 `json:"min_confidence"`
+	Name                string  `json:"name"`
+	ScanIntervalSeconds int     `json:"scan_interval_seconds"`
+}
+
+// OperatingProfileEnvelope is generated from the OperatingProfileEnvelope schema.
+type OperatingProfileEnvelope struct {
+	Data   OperatingProfile `json:"data"`
+	Status string           `json:"status"`
+}
+
+// PreviewCustomAlertRequest is generated from the PreviewCustomAlertRequest schema.
+type PreviewCustomAlertRequest struct {
+	Rule string `json:"rule"`
+}
+
+// ProfileChangeResponse is generated from the ProfileChangeResponse schema.
+type ProfileChangeResponse struct {
+	Deleted bool   `json:"deleted"`
+	Name    string `json:"name"`
+}
+
+// ProfileChangeResponseEnvelope is generated from the ProfileChangeResponseEnvelope schema.
+type ProfileChangeResponseEnvelope struct {
+	Data   ProfileChangeResponse `json:"data"`
+	Status string                `json:"status"`
+}
+
+// ProfileList is generated from the ProfileList schema.
+type ProfileList struct {
+	Active   string             `json:"active"`
+	Profiles []OperatingProfile `json:"profiles"`
+}
+
+// ProfileListEnvelope is generated from the ProfileListEnvelope schema.
+type ProfileListEnvelope struct {
+	Data   ProfileList `json:"data"`
+	Status string      `json:"status"`
+}
+
+// ReadinessComponent is generated from the ReadinessComponent schema.
+type ReadinessComponent struct {
+	Name   string  `json:"name"`
+	Reason string  `json:"reason,omitempty"`
+	Remedy string  `json:"remedy,omitempty"`
+	Score  float64 `json:"score"`
+	Status string  `json:"status"`
+	Weight float64 `json:"weight"`
+}
+
+// ReadinessReport is generated from the ReadinessReport schema.
+type ReadinessReport struct {
+	Blockers    []ReadinessComponent `json:"blockers"`
+	CheckedAt   string               `json:"checked_at"`
+	Components  []ReadinessComponent `json:"components"`
+	Explanation string               `json:"explanation"`
+	Go          bool                 `json:"go"`
+	MinScore    int                  `json:"min_score"`
+	Score       int                  `json:"score"`
+}
+
+// RunSelfTestRequest is generated from the RunSelfTestRequest schema.
+type RunSelfTestRequest struct {
+	ChatID    string `json:"chat_id"`
+	Exchange  string `json:"exchange"`
+	Symbol    string `json:"symbol"`
+	Timeframe string `json:"timeframe"`
+}
+
+// RunStressTestRequest is generated from the RunStressTestRequest schema.
+type RunStressTestRequest struct {
+	Candles   int              `json:"candles"`
+	Exchange  string           `json:"exchange"`
+	Scenarios []StressScenario `json:"scenarios,omitempty"`
+	Symbol    string           `json:"symbol"`
+	Timeframe string           `json:"timeframe"`
+}
+
+// SaveProfileRequest is generated from the SaveProfileRequest schema.
+type SaveProfileRequest struct {
+	Base    string           `json:"base,omitempty"`
+	ChatID  string           `json:"chat_id"`
+	Profile OperatingProfile `json:"profile"`
+}
+
+// SearchResponse is generated from the SearchResponse schema.
+type SearchResponse struct {
+	Query   string         `json:"query"`
+	Results []SearchResult `json:"results"`
+}
+
+// SearchResponseEnvelope is generated from the SearchResponseEnvelope schema.
+type SearchResponseEnvelope struct {
+	Data   SearchResponse `json:"data"`
+	Status string         `json:"status"`
+}
+
+// SearchResult is generated from the SearchResult schema.
+type SearchResult struct {
+	At      string   `json:"at"`
+	ID      string   `json:"id"`
+	Kind    string   `json:"kind"`
+	Matched []string `json:"matched"`
+	Score   float64  `json:"score"`
+	Snippet string   `json:"snippet,omitempty"`
+	Title   string   `json:"title"`
+}
+
+// SelectProfileRequest is generated from the SelectProfileRequest schema.
+type SelectProfileRequest struct {
+	ChatID  string `json:"chat_id"`
+	Profile string `json:"profile"`
+}
+
+// SelfTestReport is generated from the SelfTestReport schema.
+type SelfTestReport struct {
+	DurationMs float64         `json:"duration_ms"`
+	Exchange   string          `json:"exchange"`
+	Passed     bool            `json:"passed"`
+	Stages     []SelfTestStage `json:"stages"`
+	StartedAt  string          `json:"started_at"`
+	Symbol     string          `json:"symbol"`
+	Timeframe  string          `json:"timeframe"`
+}
+
+// SelfTestReportEnvelope is generated from the SelfTestReportEnvelope schema.
+type SelfTestReportEnvelope struct {
+	Data   SelfTestReport `json:"data"`
+	Status string         `json:"status"`
+}
+
+// SelfTestStage is generated from the SelfTestStage schema.
+type SelfTestStage struct {
+	DurationMs float64 `json:"duration_ms"`
+	Message    string  `json:"message,omitempty"`
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`
+}
+
+// SessionLoginRequest is generated from the SessionLoginRequest schema.
+type SessionLoginRequest struct {
+	APIKey string   `json:"api_key,omitempty"`
+	ChatID string   `json:"chat_id,omitempty"`
+	Code   string   `json:"code,omitempty"`
+	Method string   `json:"method"`
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// SessionReplayEvent is generated from the SessionReplayEvent schema.
+type SessionReplayEvent struct {
+	Action      string  `json:"action"`
+	At          string  `json:"at"`
+	Confidence  float64 `json:"confidence"`
+	ID          string  `json:"id"`
+	OrderStatus string  `json:"order_status,omitempty"`
+	Price       float64 `json:"price"`
+	Reasoning   string  `json:"reasoning"`
+	Source      string  `json:"source"`
+}
+
+// SessionReplayReport is generated from the SessionReplayReport schema.
+type SessionReplayReport struct {
+	Candles           int                 `json:"candles"`
+	Day               string              `json:"day"`
+	Divergences       int                 `json:"divergences"`
+	Entries           int                 `json:"entries"`
+	Exchange          string              `json:"exchange"`
+	Focus             *SessionReplayStep  `json:"focus,omitempty"`
+	Interval          string              `json:"interval"`
+	RecordedDecisions int                 `json:"recorded_decisions"`
+	Steps             []SessionReplayStep `json:"steps"`
+	Symbol            string              `json:"symbol"`
+	Ticks             int                 `json:"ticks"`
+}
+
+// SessionReplayReportEnvelope is generated from the SessionReplayReportEnvelope schema.
+type SessionReplayReportEnvelope struct {
+	Data   SessionReplayReport `json:"data"`
+	Status string              `json:"status"`
+}
+
+// SessionReplayStep is generated from the SessionReplayStep schema.
+type SessionReplayStep struct {
+	Action       string               `json:"action"`
+	At           string               `json:"at"`
+	Confidence   float64              `json:"confidence"`
+	Diverged     bool                 `json:"diverged"`
+	Price        float64              `json:"price"`
+	Reasoning    string               `json:"reasoning"`
+	Recorded     []SessionReplayEvent `json:"recorded,omitempty"`
+	Signals      []TradingSignal      `json:"signals"`
+	SizePercent  float64              `json:"size_percent"`
+	Ticks        int                  `json:"ticks"`
+	WouldExecute bool                 `json:"would_execute"`
+}
+
+// SessionTokenResponse is generated from the SessionTokenResponse schema.
+type SessionTokenResponse struct {
+	AccessToken  string   `json:"access_token"`
+	ExpiresIn    int      `json:"expires_in"`
+	RefreshToken string   `json:"refresh_token"`
+	Scopes       []string `json:"scopes"`
+	TokenType    string   `json:"token_type"`
+}
+
+// SessionTokenResponseEnvelope is generated from the SessionTokenResponseEnvelope schema.
+type SessionTokenResponseEnvelope struct {
+	Data   SessionTokenResponse `json:"data"`
+	Status string               `json:"status"`
+}
+
+// SetupProbeResult is generated from the SetupProbeResult schema.
+type SetupProbeResult struct {
+	Message string `json:"message,omitempty"`
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+}
+
+// SetupState is generated from the SetupState schema.
+type SetupState struct {
+	Complete  bool        `json:"complete"`
+	Completed int         `json:"completed"`
+	Next      string      `json:"next,omitempty"`
+	Steps     []SetupStep `json:"steps"`
+	Total     int         `json:"total"`
+}
+
+// SetupStateEnvelope is generated from the SetupStateEnvelope schema.
+type SetupStateEnvelope struct {
+	Data   SetupState `json:"data"`
+	Status string     `json:"status"`
+}
+
+// SetupStep is generated from the SetupStep schema.
+type SetupStep struct {
+	Detail    string  `json:"detail,omitempty"`
+	Detected  bool    `json:"detected"`
+	Hint      string  `json:"hint"`
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Title     string  `json:"title"`
+	UpdatedAt *string `json:"updated_at,omitempty"`
+}
+
+// SetupVerification is generated from the SetupVerification schema.
+type SetupVerification struct {
+	Checks []SetupProbeResult `json:"checks"`
+	Passed bool               `json:"passed"`
+	State  *SetupState        `json:"state,omitempty"`
+}
+
+// SetupVerificationEnvelope is generated from the SetupVerificationEnvelope schema.
+type SetupVerificationEnvelope struct {
+	Data   SetupVerification `json:"data"`
+	Status string            `json:"status"`
+}
+
+// StateChange is generated from the StateChange schema.
+type StateChange struct {
+	From string `json:"from"`
+	Key  string `json:"key,omitempty"`
+	Kind string `json:"kind"`
+	To   string `json:"to"`
+}
+
+// StateDiff is generated from the StateDiff schema.
+type StateDiff struct {
+	Changed bool             `json:"changed"`
+	Changes []StateChange    `json:"changes"`
+	From    StateFingerprint `json:"from"`
+	To      StateFingerprint `json:"to"`
+}
+
+// StateDiffEnvelope is generated from the StateDiffEnvelope schema.
+type StateDiffEnvelope struct {
+	Data   StateDiff `json:"data"`
+	Status string    `json:"status"`
+}
+
+// StateFingerprint is generated from the StateFingerprint schema.
+type StateFingerprint struct {
+	At            string `json:"at"`
+	ConfigHash    string `json:"config_hash"`
+	DecisionID    string `json:"decision_id,omitempty"`
+	Model         string `json:"model"`
+	PromptVersion string `json:"prompt_version"`
+	StrategyHash  string `json:"strategy_hash"`
+}
+
+// StrategyChangeResponse is generated from the StrategyChangeResponse schema.
+type StrategyChangeResponse struct {
+	Deleted bool   `json:"deleted"`
+	Name    string `json:"name"`
+}
+
+// StrategyChangeResponseEnvelope is generated from the StrategyChangeResponseEnvelope schema.
+type StrategyChangeResponseEnvelope struct {
+	Data   StrategyChangeResponse `json:"data"`
+	Status string                 `json:"status"`
+}
+
+// StrategyConfig is generated from the StrategyConfig schema.
+type StrategyConfig struct {
+	Author         string                 `json:"author,omitempty"`
+	Description    string                 `json:"description,omitempty"`
+	Format         string                 `json:"format"`
+	Name           string                 `json:"name"`
+	Parameters     map[string]interface{} `json:"parameters,omitempty"`
+	RequiredSkills []string               `json:"required_skills,omitempty"`
+	Risk           StrategyRiskCaps       `json:"risk"`
+	Strategy       string                 `json:"strategy"`
+	Version        string                 `json:"version,omitempty"`
+}
+
+// StrategyExport is generated from the StrategyExport schema.
+type StrategyExport struct {
+	Document string `json:"document"`
+	Format   string `json:"format"`
+	Name     string `json:"name"`
+}
+
+// StrategyExportEnvelope is generated from the StrategyExportEnvelope schema.
+type StrategyExportEnvelope struct {
+	Data   StrategyExport `json:"data"`
+	Status string         `json:"status"`
+}
+
+// StrategyListResponse is generated from the StrategyListResponse schema.
+type StrategyListResponse struct {
+	Strategies []InstalledStrategy `json:"strategies"`
+}
+
+// StrategyListResponseEnvelope is generated from the StrategyListResponseEnvelope schema.
+type StrategyListResponseEnvelope struct {
+	Data   StrategyListResponse `json:"data"`
+	Status string               `json:"status"`
+}
+
+// StrategyRiskCaps is generated from the StrategyRiskCaps schema.
+type StrategyRiskCaps struct {
+	AIUsage             string  `json:"ai_usage"`
+	DailyLossPct        float64 `json:"daily_loss_pct"`
+	MaxPositionPct      float64 `json:"max_position_pct"`
+	MinConfidence       float64 `json:"min_confidence"`
+	ScanIntervalSeconds int     `json:"scan_interval_seconds"`
+}
+
+// StrategyValidation is generated from the StrategyValidation schema.
+type StrategyValidation struct {
+	Config   *StrategyConfig `json:"config,omitempty"`
+	Errors   []string        `json:"errors,omitempty"`
+	Valid    bool            `json:"valid"`
+	Warnings []string        `json:"warnings,omitempty"`
+}
+
+// StrategyValidationEnvelope is generated from the StrategyValidationEnvelope schema.
+type StrategyValidationEnvelope struct {
+	Data   StrategyValidation `json:"data"`
+	Status string             `json:"status"`
+}
+
+// StressReport is generated from the StressReport schema.
+type StressReport struct {
+	Baseline    StressRun              `json:"baseline"`
+	Candles     int                    `json:"candles"`
+	Exchange    string                 `json:"exchange"`
+	GeneratedAt string                 `json:"generated_at"`
+	Robust      bool                   `json:"robust"`
+	Scenarios   []StressScenarioResult `json:"scenarios"`
+	Signals     int                    `json:"signals"`
+	Symbol      string                 `json:"symbol"`
+	Timeframe   string                 `json:"timeframe"`
+}
+
+// StressReportEnvelope is generated from the StressReportEnvelope schema.
+type StressReportEnvelope struct {
+	Data   StressReport `json:"data"`
+	Status string       `json:"status"`
+}
+
+// StressRun is generated from the StressRun schema.
+type StressRun struct {
+	Fees        string  `json:"fees"`
+	GrossPnl    string  `json:"gross_pnl"`
+	MaxDrawdown string  `json:"max_drawdown"`
+	NetPnl      string  `json:"net_pnl"`
+	Trades      int     `json:"trades"`
+	WinRate     float64 `json:"win_rate"`
+	Wins        int     `json:"wins"`
+}
+
+// StressScenario is generated from the StressScenario schema.
+type StressScenario struct {
+	FeeMultiplier float64 `json:"fee_multiplier,omitempty"`
+	FillRatio     float64 `json:"fill_ratio,omitempty"`
+	LatencyMs     int64   `json:"latency_ms,omitempty"`
+	Name          string  `json:"name"`
+}
+
+// StressScenarioResult is generated from the StressScenarioResult schema.
+type StressScenarioResult struct {
+	Fees        string         `json:"fees"`
+	GrossPnl    string         `json:"gross_pnl"`
+	MaxDrawdown string         `json:"max_drawdown"`
+	NetPnl      string         `json:"net_pnl"`
+	PnlChange   string         `json:"pnl_change"`
+	Retained    *float64       `json:"retained,omitempty"`
+	Scenario    StressScenario `json:"scenario"`
+	Trades      int            `json:"trades"`
+	WinRate     float64        `json:"win_rate"`
+	Wins        int            `json:"wins"`
+}
+
+// TradingModeState is generated from the TradingModeState schema.
+type TradingModeState struct {
+	KillSwitchEngaged bool   `json:"kill_switch_engaged"`
+	KillSwitchReason  string `json:"kill_switch_reason,omitempty"`
+	Mode              string `json:"mode"`
+	UpdatedAt         string `json:"updated_at"`
+	UpdatedBy         string `json:"updated_by,omitempty"`
+}
+
+// TradingModeStateEnvelope is generated from the TradingModeStateEnvelope schema.
+type TradingModeStateEnvelope struct {
+	Data   TradingModeState `json:"data"`
+	Status string           `json:"status"`
+}
+
+// TradingSignal is generated from the TradingSignal schema.
+type TradingSignal struct {
+	Description string  `json:"description"`
+	Direction   string  `json:"direction"`
+	Name        string  `json:"name"`
+	Value       float64 `json:"value"`
+	Weight      float64 `json:"weight"`
+}
+
+// UpdateCustomAlertRequest is generated from the UpdateCustomAlertRequest schema.
+type UpdateCustomAlertRequest struct {
+	ChatID   string `json:"chat_id"`
+	IsActive *bool  `json:"is_active,omitempty"`
+}
+
+// UpdateSetupStepRequest is generated from the UpdateSetupStepRequest schema.
+type UpdateSetupStepRequest struct {
+	Detail string `json:"detail"`
+	Status string `json:"status"`
+}
+
+// UserDataAction is generated from the UserDataAction schema.
+type UserDataAction struct {
+	Action   string   `json:"action"`
+	Category string   `json:"category"`
+	Count    int64    `json:"count"`
+	Ids      []string `json:"ids,omitempty"`
+	Target   string   `json:"target"`
+}
+
+// UserDataReport is generated from the UserDataReport schema.
+type UserDataReport struct {
+	Actions   []UserDataAction `json:"actions"`
+	DryRun    bool             `json:"dry_run"`
+	ErasedAt  *string          `json:"erased_at,omitempty"`
+	Preserved []UserDataAction `json:"preserved"`
+	Removed   int64            `json:"removed"`
+	UserID    string           `json:"user_id"`
+}
+
+// UserDataReportEnvelope is generated from the UserDataReportEnvelope schema.
+type UserDataReportEnvelope struct {
+	Data   UserDataReport `json:"data"`
+	Status string         `json:"status"`
+}
+
+// ValidateStrategyRequest is generated from the ValidateStrategyRequest schema.
+type ValidateStrategyRequest struct {
+	Document string `json:"document"`
+}
+
+// AnchorAuditChain record a signed checkpoint of the audit chain head.
+//
+// POST /api/v1/admin/audit-chain/anchor
+func (c *APIClient) AnchorAuditChain() (*AuditAnchorEnvelope, error) {
+	endpoint := "/api/v1/admin/audit-chain/anchor"
+	respBody, err := c.makeRequest("POST", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response AuditAnchorEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// BeginAutonomous start autonomous mode for a chat after readiness checks.
+//
+// POST /api/v1/telegram/internal/autonomous/begin
+func (c *APIClient) BeginAutonomous(req *AutonomousStateRequest) (*AutonomousStateResponse, error) {
+	endpoint := "/api/v1/telegram/internal/autonomous/begin"
+	respBody, err := c.makeRequest("POST", endpoint, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response AutonomousStateResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// ClearChaosFaults end the fault on one target, or every fault.
+//
+// DELETE /api/v1/admin/chaos
+func (c *APIClient) ClearChaosFaults(target string) (*ChaosStatusResponseEnvelope, error) {
+	endpoint := "/api/v1/admin/chaos"
+	query := url.Values{}
+	if target != "" {
+		query.Set("target", target)
+	}
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	respBody, err := c.makeRequest("DELETE", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response ChaosStatusResponseEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// CollectNow fetch and store fresh data for one symbol immediately, optionally with recent candles.
+//
+// POST /api/v1/admin/collect
+func (c *APIClient) CollectNow(req *CollectNowRequest) (*OnDemandCollectionEnvelope, error) {
+	endpoint := "/api/v1/admin/collect"
+	respBody, err := c.makeRequest("POST", endpoint, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response OnDemandCollectionEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// CreateCustomAlert create an alert from a rule such as "BTC/USDT RSI(14,1h) < 30".
+//
+// POST /api/v1/telegram/internal/alerts/custom
+func (c *APIClient) CreateCustomAlert(req *CreateCustomAlertRequest) (*CustomAlertEnvelope, error) {
+	endpoint := "/api/v1/telegram/internal/alerts/custom"
+	respBody, err := c.makeRequest("POST", endpoint, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response CustomAlertEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// DeleteCustomAlert delete a custom alert.
+//
+// DELETE /api/v1/telegram/internal/alerts/custom/{id}
+func (c *APIClient) DeleteCustomAlert(id string, chatID string) (*CustomAlertChangeResponseEnvelope, error) {
+	endpoint := fmt.Sprintf("/api/v1/telegram/internal/alerts/custom/%s", url.PathEscape(id))
+	query := url.Values{}
+	if chatID != "" {
+		query.Set("chat_id", chatID)
+	}
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	respBody, err := c.makeRequest("DELETE", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response CustomAlertChangeResponseEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// DeleteOperatingProfile delete a custom operating profile.
+//
+// DELETE /api/v1/telegram/internal/profiles/{name}
+func (c *APIClient) DeleteOperatingProfile(name string, chatID string) (*ProfileChangeResponseEnvelope, error) {
+	endpoint := fmt.Sprintf("/api/v1/telegram/internal/profiles/%s", url.PathEscape(name))
+	query := url.Values{}
+	if chatID != "" {
+		query.Set("chat_id", chatID)
+	}
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	respBody, err := c.makeRequest("DELETE", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response ProfileChangeResponseEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// EraseUserData remove or anonymize a user's Telegram binding, notification logs, operator notes and personal identifiers; dry_run=true only reports.
+//
+// DELETE /api/v1/users/{id}/data
+func (c *APIClient) EraseUserData(id string, dryRun string) (*UserDataReportEnvelope, error) {
+	endpoint := fmt.Sprintf("/api/v1/users/%s/data", url.PathEscape(id))
+	query := url.Values{}
+	if dryRun != "" {
+		query.Set("dry_run", dryRun)
+	}
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	respBody, err := c.makeRequest("DELETE", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response UserDataReportEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// ExportStrategyConfig export an installed strategy or operating profile as a shareable configuration.
+//
+// GET /api/v1/telegram/internal/strategies/{name}/export
+func (c *APIClient) ExportStrategyConfig(name string, chatID string, format string) (*StrategyExportEnvelope, error) {
+	endpoint := fmt.Sprintf("/api/v1/telegram/internal/strategies/%s/export", url.PathEscape(name))
+	query := url.Values{}
+	if chatID != "" {
+		query.Set("chat_id", chatID)
+	}
+	if format != "" {
+		query.Set("format", format)
+	}
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response StrategyExportEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// GetAIModels list active AI models.
+//
+// GET /api/v1/ai/models
+func (c *APIClient) GetAIModels(provider string) (*AIModelsResponse, error) {
+	endpoint := "/api/v1/ai/models"
+	query := url.Values{}
+	if provider != "" {
+		query.Set("provider", provider)
+	}
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response AIModelsResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// GetCarryAnalytics historical funding and spot-perp basis statistics with annualized carry per symbol and exchange.
+//
+// GET /api/v1/arbitrage/carry
+func (c *APIClient) GetCarryAnalytics(symbol string, exchange string, days string, limit string) (*CarryReportEnvelope, error) {
+	endpoint := "/api/v1/arbitrage/carry"
+	query := url.Values{}
+	if symbol != "" {
+		query.Set("symbol", symbol)
+	}
+	if exchange != "" {
+		query.Set("exchange", exchange)
+	}
+	if days != "" {
+		query.Set("days", days)
+	}
+	if limit != "" {
+		query.Set("limit", limit)
+	}
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response CarryReportEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// GetChaosFaults list the injected dependency faults.
+//
+// GET /api/v1/admin/chaos
+func (c *APIClient) GetChaosFaults() (*ChaosStatusResponseEnvelope, error) {
+	endpoint := "/api/v1/admin/chaos"
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response ChaosStatusResponseEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// GetCompat server version and minimum supported client versions.
+//
+// GET /api/v1/compat
+func (c *APIClient) GetCompat() (*CompatResponse, error) {
+	endpoint := "/api/v1/compat"
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response CompatResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// GetEffectiveConfig show the configuration of a mode with its dry, paper or live overlay applied; defaults to the active mode.
+//
+// GET /api/v1/config/effective
+func (c *APIClient) GetEffectiveConfig(mode string) (*EffectiveConfigEnvelope, error) {
+	endpoint := "/api/v1/config/effective"
+	query := url.Values{}
+	if mode != "" {
+		query.Set("mode", mode)
+	}
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response EffectiveConfigEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// GetEquity account equity: valued exchange balances, open-position PnL and, with a chat_id, its external wallets.
+//
+// GET /api/v1/telegram/internal/equity
+func (c *APIClient) GetEquity(chatID string) (*EquitySnapshotEnvelope, error) {
+	endpoint := "/api/v1/telegram/internal/equity"
+	query := url.Values{}
+	if chatID != "" {
+		query.Set("chat_id", chatID)
+	}
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response EquitySnapshotEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// GetHealth service health and dependency status.
+//
+// GET /health
+func (c *APIClient) GetHealth() (*HealthResponse, error) {
+	endpoint := "/health"
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response HealthResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// GetMarketBulk selected ticker fields for many symbols in one call.
+//
+// GET /api/v1/market/bulk
+func (c *APIClient) GetMarketBulk(symbols string, exchanges string, fields string) (*BulkMarketResponse, error) {
+	endpoint := "/api/v1/market/bulk"
+	query := url.Values{}
+	if symbols != "" {
+		query.Set("symbols", symbols)
+	}
+	if exchanges != "" {
+		query.Set("exchanges", exchanges)
+	}
+	if fields != "" {
+		query.Set("fields", fields)
+	}
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response BulkMarketResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// GetSetupState first-run setup wizard progress with the next step to complete.
+//
+// GET /api/v1/setup/state
+func (c *APIClient) GetSetupState() (*SetupStateEnvelope, error) {
+	endpoint := "/api/v1/setup/state"
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response SetupStateEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// GetStateDiff compare the config, strategy parameters, prompt version and model behind decisions at two times, or with the live state.
+//
+// GET /api/v1/admin/state/diff
+func (c *APIClient) GetStateDiff(from string, to string) (*StateDiffEnvelope, error) {
+	endpoint := "/api/v1/admin/state/diff"
+	query := url.Values{}
+	if from != "" {
+		query.Set("from", from)
+	}
+	if to != "" {
+		query.Set("to", to)
+	}
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response StateDiffEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// GetStrategyConfigSchema return the JSON Schema of the strategy configuration format.
+//
+// GET /api/v1/telegram/internal/strategies/schema
+func (c *APIClient) GetStrategyConfigSchema() (*map[string]interface{}, error) {
+	endpoint := "/api/v1/telegram/internal/strategies/schema"
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// InjectChaosFault simulate Redis loss, CCXT timeouts, LLM 429s or database slowness for a bounded window.
+//
+// POST /api/v1/admin/chaos
+func (c *APIClient) InjectChaosFault(req *InjectFaultRequest) (*FaultEnvelope, error) {
+	endpoint := "/api/v1/admin/chaos"
+	respBody, err := c.makeRequest("POST", endpoint, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response FaultEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// InstallStrategyConfig validate a shared YAML or JSON strategy configuration and install it for a chat.
+//
+// POST /api/v1/telegram/internal/strategies
+func (c *APIClient) InstallStrategyConfig(req *InstallStrategyRequest) (*InstallStrategyResponseEnvelope, error) {
+	endpoint := "/api/v1/telegram/internal/strategies"
+	respBody, err := c.makeRequest("POST", endpoint, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response InstallStrategyResponseEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// ListCustomAlerts user-defined price and indicator alerts of a chat.
+//
+// GET /api/v1/telegram/internal/alerts/custom
+func (c *APIClient) ListCustomAlerts(chatID string) (*CustomAlertListResponseEnvelope, error) {
+	endpoint := "/api/v1/telegram/internal/alerts/custom"
+	query := url.Values{}
+	if chatID != "" {
+		query.Set("chat_id", chatID)
+	}
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response CustomAlertListResponseEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// ListOperatingProfiles list the built-in and custom operating profiles of a chat and the selected one.
+//
+// GET /api/v1/telegram/internal/profiles
+func (c *APIClient) ListOperatingProfiles(chatID string) (*ProfileListEnvelope, error) {
+	endpoint := "/api/v1/telegram/internal/profiles"
+	query := url.Values{}
+	if chatID != "" {
+		query.Set("chat_id", chatID)
+	}
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response ProfileListEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// ListStrategyConfigs list the strategy configurations installed for a chat.
+//
+// GET /api/v1/telegram/internal/strategies
+func (c *APIClient) ListStrategyConfigs(chatID string) (*StrategyListResponseEnvelope, error) {
+	endpoint := "/api/v1/telegram/internal/strategies"
+	query := url.Values{}
+	if chatID != "" {
+		query.Set("chat_id", chatID)
+	}
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response StrategyListResponseEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// Login exchange an API key or Telegram login code for session tokens.
+//
+// POST /api/v1/auth/login
+func (c *APIClient) Login(req *SessionLoginRequest) (*SessionTokenResponseEnvelope, error) {
+	endpoint := "/api/v1/auth/login"
+	respBody, err := c.makeRequest("POST", endpoint, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response SessionTokenResponseEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// PauseAutonomous pause autonomous mode for a chat.
+//
+// POST /api/v1/telegram/internal/autonomous/pause
+func (c *APIClient) PauseAutonomous(req *AutonomousStateRequest) (*AutonomousStateResponse, error) {
+	endpoint := "/api/v1/telegram/internal/autonomous/pause"
+	respBody, err := c.makeRequest("POST", endpoint, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response AutonomousStateResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// PreviewCustomAlert how often a rule would have fired over the last 30 days, without saving it.
+//
+// POST /api/v1/telegram/internal/alerts/custom/preview
+func (c *APIClient) PreviewCustomAlert(req *PreviewCustomAlertRequest) (*AlertPreviewEnvelope, error) {
+	endpoint := "/api/v1/telegram/internal/alerts/custom/preview"
+	respBody, err := c.makeRequest("POST", endpoint, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response AlertPreviewEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// ReplayDay replay a recorded day of a symbol through the signal and decision pipeline without executing orders, next to the decisions recorded that day.
+//
+// GET /api/v1/replay/day
+func (c *APIClient) ReplayDay(exchange string, symbol string, date string, interval string, at string) (*SessionReplayReportEnvelope, error) {
+	endpoint := "/api/v1/replay/day"
+	query := url.Values{}
+	if exchange != "" {
+		query.Set("exchange", exchange)
+	}
+	if symbol != "" {
+		query.Set("symbol", symbol)
+	}
+	if date != "" {
+		query.Set("date", date)
+	}
+	if interval != "" {
+		query.Set("interval", interval)
+	}
+	if at != "" {
+		query.Set("at", at)
+	}
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response SessionReplayReportEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// ResetSetupState forget setup progress so the wizard starts over.
+//
+// DELETE /api/v1/setup/state
+func (c *APIClient) ResetSetupState() (*SetupStateEnvelope, error) {
+	endpoint := "/api/v1/setup/state"
+	respBody, err := c.makeRequest("DELETE", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response SetupStateEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// RunSelfTest dry-run cycle from market data to a test notification, with pass/fail and timing per stage.
+//
+// POST /api/v1/admin/self-test
+func (c *APIClient) RunSelfTest(req *RunSelfTestRequest) (*SelfTestReportEnvelope, error) {
+	endpoint := "/api/v1/admin/self-test"
+	respBody, err := c.makeRequest("POST", endpoint, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response SelfTestReportEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// RunStressTest replay recent signals with added latency, partial fills and higher fees and compare PnL with the baseline.
+//
+// POST /api/v1/admin/stress-test
+func (c *APIClient) RunStressTest(req *RunStressTestRequest) (*StressReportEnvelope, error) {
+	endpoint := "/api/v1/admin/stress-test"
+	respBody, err := c.makeRequest("POST", endpoint, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response StressReportEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// SaveOperatingProfile create or replace a custom operating profile, starting from a base profile.
+//
+// POST /api/v1/telegram/internal/profiles
+func (c *APIClient) SaveOperatingProfile(req *SaveProfileRequest) (*OperatingProfileEnvelope, error) {
+	endpoint := "/api/v1/telegram/internal/profiles"
+	respBody, err := c.makeRequest("POST", endpoint, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response OperatingProfileEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// Search ranked search across trades, signals, quests and exchange error logs.
+//
+// GET /api/v1/search
+func (c *APIClient) Search(q string, scope string, limit string) (*SearchResponseEnvelope, error) {
+	endpoint := "/api/v1/search"
+	query := url.Values{}
+	if q != "" {
+		query.Set("q", q)
+	}
+	if scope != "" {
+		query.Set("scope", scope)
+	}
+	if limit != "" {
+		query.Set("limit", limit)
+	}
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response SearchResponseEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// SelectOperatingProfile select the operating profile a chat trades with.
+//
+// POST /api/v1/telegram/internal/profiles/select
+func (c *APIClient) SelectOperatingProfile(req *SelectProfileRequest) (*OperatingProfileEnvelope, error) {
+	endpoint := "/api/v1/telegram/internal/profiles/select"
+	respBody, err := c.makeRequest("POST", endpoint, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response OperatingProfileEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// UninstallStrategyConfig uninstall a strategy configuration and its operating profile.
+//
+// DELETE /api/v1/telegram/internal/strategies/{name}
+func (c *APIClient) UninstallStrategyConfig(name string, chatID string) (*StrategyChangeResponseEnvelope, error) {
+	endpoint := fmt.Sprintf("/api/v1/telegram/internal/strategies/%s", url.PathEscape(name))
+	query := url.Values{}
+	if chatID != "" {
+		query.Set("chat_id", chatID)
+	}
+	if encoded := query.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	respBody, err := c.makeRequest("DELETE", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response StrategyChangeResponseEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// UpdateCustomAlert pause or resume a custom alert.
+//
+// PUT /api/v1/telegram/internal/alerts/custom/{id}
+func (c *APIClient) UpdateCustomAlert(id string, req *UpdateCustomAlertRequest) (*CustomAlertChangeResponseEnvelope, error) {
+	endpoint := fmt.Sprintf("/api/v1/telegram/internal/alerts/custom/%s", url.PathEscape(id))
+	respBody, err := c.makeRequest("PUT", endpoint, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response CustomAlertChangeResponseEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// UpdateSetupStep mark a setup step done, skipped or pending.
+//
+// PUT /api/v1/setup/state/{step}
+func (c *APIClient) UpdateSetupStep(step string, req *UpdateSetupStepRequest) (*SetupStateEnvelope, error) {
+	endpoint := fmt.Sprintf("/api/v1/setup/state/%s", url.PathEscape(step))
+	respBody, err := c.makeRequest("PUT", endpoint, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response SetupStateEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// ValidateStrategyConfig check a strategy configuration for errors, secrets and missing skills without installing it.
+//
+// POST /api/v1/telegram/internal/strategies/validate
+func (c *APIClient) ValidateStrategyConfig(req *ValidateStrategyRequest) (*StrategyValidationEnvelope, error) {
+	endpoint := "/api/v1/telegram/internal/strategies/validate"
+	respBody, err := c.makeRequest("POST", endpoint, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response StrategyValidationEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// VerifyAuditChain walk the hash-chained decision and trade log and report any retroactive modification.
+//
+// GET /api/v1/admin/audit-chain/verify
+func (c *APIClient) VerifyAuditChain() (*AuditChainReportEnvelope, error) {
+	endpoint := "/api/v1/admin/audit-chain/verify"
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response AuditChainReportEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// VerifySetup run the setup dry-run verification against every dependency.
+//
+// POST /api/v1/setup/verify
+func (c *APIClient) VerifySetup() (*SetupVerificationEnvelope, error) {
+	endpoint := "/api/v1/setup/verify"
+	respBody, err := c.makeRequest("POST", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response SetupVerificationEnvelope
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return &response, nil
+}
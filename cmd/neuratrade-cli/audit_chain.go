@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+// auditCommand builds the "audit" command for the tamper-evident decision
+// and trade log
+func auditCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "audit",
+		Usage: "Verify the hash-chained decision and trade log",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "verify",
+				Usage:  "Detect retroactive modification of the trade history; exits 1 when the chain is broken",
+				Action: runAuditVerify,
+			},
+			{
+				Name:   "anchor",
+				Usage:  "Record a signed checkpoint of the chain head now",
+				Action: runAuditAnchor,
+			},
+		},
+	}
+}
+
+// runAuditVerify verifies the audit chain and prints the issues found
+func runAuditVerify(cCtx *cli.Context) error {
+	out := newOutput(cCtx)
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	response, err := client.VerifyAuditChain()
+	if err != nil {
+		return fmt.Errorf("failed to verify audit chain: %w", err)
+	}
+
+	report := response.Data
+	if err := out.Render(report, func() {
+		if report.Valid {
+			out.Printf("✅ Audit chain intact: %d entries, %d records checked\n", report.Entries, report.RecordsChecked)
+		} else {
+			out.Printf("❌ Audit chain broken: %d issues in %d entries\n", report.IssueCount, report.Entries)
+		}
+		if report.Entries > 0 {
+			out.Printf("  Head #%d %s\n", report.HeadSeq, report.HeadHash)
+		}
+		if anchor := report.LastAnchor; anchor != nil {
+			out.Printf("  Last anchor #%d at %s\n", anchor.Seq, anchor.CreatedAt)
+		}
+		if !report.Signed {
+			out.Println("  ⚠️  No signing key configured: anchor signatures were not checked")
+		}
+		for _, issue := range report.Issues {
+			out.Printf("  [%s]", issue.Problem)
+			if issue.Seq > 0 {
+				out.Printf(" #%d", issue.Seq)
+			}
+			if issue.RefID != "" {
+				out.Printf(" %s %s", issue.Kind, issue.RefID)
+			}
+			out.Printf(": %s\n", issue.Detail)
+		}
+		if hidden := report.IssueCount - len(report.Issues); hidden > 0 {
+			out.Printf("  ... and %d more\n", hidden)
+		}
+	}); err != nil {
+		return err
+	}
+	if !report.Valid {
+		return cli.Exit("", 1)
+	}
+	return nil
+}
+
+// runAuditAnchor anchors the audit chain head
+func runAuditAnchor(cCtx *cli.Context) error {
+	out := newOutput(cCtx)
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	response, err := client.AnchorAuditChain()
+	if err != nil {
+		return fmt.Errorf("failed to anchor audit chain: %w", err)
+	}
+
+	anchor := response.Data
+	return out.Render(anchor, func() {
+		out.Printf("⚓ Anchored #%d %s\n", anchor.Seq, anchor.Hash)
+		if anchor.Signature == "" {
+			out.Println("  ⚠️  Unsigned: no signing key configured")
+		}
+	})
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	backupPassphraseHeader = "X-Backup-Passphrase"
+	backupTimeout          = 5 * time.Minute
+)
+
+// BackupRestoreResponse represents the response for restoring a backup
+type BackupRestoreResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Data   struct {
+		CreatedAt time.Time      `json:"created_at"`
+		Restored  map[string]any `json:"restored"`
+	} `json:"data"`
+}
+
+func backupPassphraseFlag() *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:    "passphrase",
+		Usage:   "Passphrase the archive is encrypted with",
+		EnvVars: []string{"NEURATRADE_BACKUP_PASSPHRASE"},
+	}
+}
+
+// doBackupRequest sends a raw backup request. Archives can be large, so it
+// uses a longer timeout than makeRequest.
+func (c *APIClient) doBackupRequest(endpoint, passphrase string, body []byte) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodPost, c.BaseURL+endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(backupPassphraseHeader, passphrase)
+	req.Header.Set(clientVersionHeader, clientVersionValue())
+	if c.APIKey != "" {
+		req.Header.Set("X-API-Key", c.APIKey)
+	}
+
+	client := &http.Client{Timeout: backupTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+	warnIfOutdated(resp)
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return respBody, nil
+}
+
+// backupCreate downloads an encrypted snapshot of the application state
+func backupCreate(cCtx *cli.Context) error {
+	out := newOutput(cCtx)
+	passphrase := cCtx.String("passphrase")
+	if passphrase == "" {
+		return fmt.Errorf("a passphrase is required (--passphrase or NEURATRADE_BACKUP_PASSPHRASE)")
+	}
+	output := cCtx.String("output")
+	if output == "" {
+		output = fmt.Sprintf("neuratrade-backup-%s.json", time.Now().UTC().Format("20060102-150405"))
+	}
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	archive, err := client.doBackupRequest("/api/v1/admin/backup", passphrase, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create backup: %w", err)
+	}
+	if err := os.WriteFile(output, archive, 0600); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	out.Printf("✅ Backup written to %s (%d bytes)\n", output, len(archive))
+	out.Println("Keep the passphrase safe: the archive cannot be restored without it.")
+	return out.Render(map[string]interface{}{"path": output, "bytes": len(archive)}, nil)
+}
+
+// backupRestore uploads an encrypted snapshot and restores it
+func backupRestore(cCtx *cli.Context) error {
+	out := newOutput(cCtx)
+	passphrase := cCtx.String("passphrase")
+	if passphrase == "" {
+		return fmt.Errorf("a passphrase is required (--passphrase or NEURATRADE_BACKUP_PASSPHRASE)")
+	}
+	archive, err := os.ReadFile(cCtx.String("input"))
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	endpoint := "/api/v1/admin/backup/restore"
+	if operator := cCtx.String("operator"); operator != "" {
+		endpoint += "?operator=" + url.QueryEscape(operator)
+	}
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	if err := requireCompatibleBackend(client); err != nil {
+		return err
+	}
+	respBody, err := client.doBackupRequest(endpoint, passphrase, archive)
+	if err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	var response BackupRestoreResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	out.Printf("✅ Restored backup from %s\n", response.Data.CreatedAt.Format(time.RFC3339))
+	return out.Render(response.Data, func() { prettyPrint(response.Data.Restored) })
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+// collectCommand builds the "collect" command: an immediate, one-off
+// collection of one symbol for investigating stale or missing data
+func collectCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "collect",
+		Usage:  "Fetch and store fresh market data for one symbol now",
+		Action: runCollect,
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "exchange", Usage: "Exchange to collect from", Required: true},
+			&cli.StringFlag{Name: "symbol", Usage: "Symbol to collect, e.g. BTC/USDT", Required: true},
+			&cli.StringFlag{Name: "timeframe", Usage: "Also fetch recent candles of this timeframe, e.g. 1m"},
+			&cli.IntFlag{Name: "candles", Usage: "Number of recent candles to fetch (server default: 100)"},
+		},
+	}
+}
+
+// runCollect triggers an on-demand collection and prints what was fetched
+func runCollect(cCtx *cli.Context) error {
+	out := newOutput(cCtx)
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	response, err := client.CollectNow(&CollectNowRequest{
+		Exchange:  cCtx.String("exchange"),
+		Symbol:    cCtx.String("symbol"),
+		Timeframe: cCtx.String("timeframe"),
+		Candles:   cCtx.Int("candles"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to collect: %w", err)
+	}
+
+	result := response.Data
+	return out.Render(result, func() {
+		out.Printf("📥 Collected %s %s in %dms\n", result.Exchange, result.Symbol, result.DurationMs)
+		if ticker := result.Ticker; ticker != nil {
+			out.Printf("  Price %s  Bid %s  Ask %s  Volume %s  (%s)\n", ticker.Price, ticker.Bid, ticker.Ask, ticker.Volume, ticker.Timestamp)
+		}
+		if result.Stored {
+			out.Println("  ✅ Stored in the database and cache")
+		} else {
+			out.Printf("  ⚠️  Not stored: %s\n", result.Rejected)
+		}
+		if result.Timeframe == "" {
+			return
+		}
+		if result.CandleError != "" {
+			out.Printf("  ❌ %s candles failed: %s\n", result.Timeframe, result.CandleError)
+			return
+		}
+		out.Printf("  %d %s candles\n", len(result.Candles), result.Timeframe)
+		recent := result.Candles
+		if len(recent) > 5 {
+			recent = recent[len(recent)-5:]
+		}
+		for _, candle := range recent {
+			out.Printf("  %-25s O %s  H %s  L %s  C %s  V %s\n", candle.Timestamp, candle.Open, candle.High, candle.Low, candle.Close, candle.Volume)
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	// clientName identifies the CLI in the X-Client-Version handshake
+	clientName = "neuratrade-cli"
+	// clientVersionHeader carries "<name>/<version>" on every request
+	clientVersionHeader = "X-Client-Version"
+	// clientCompatHeader is set by the backend when this CLI is too old
+	clientCompatHeader = "X-Client-Compat"
+)
+
+// compatWarning makes sure the outdated-client warning is printed once per run
+var compatWarning sync.Once
+
+// compatWarningOutput is where compatibility warnings are written
+var compatWarningOutput io.Writer = os.Stderr
+
+// clientVersionValue returns the X-Client-Version header value
+func clientVersionValue() string {
+	return clientName + "/" + version
+}
+
+// warnIfOutdated prints a warning when the backend flags this CLI as outdated
+func warnIfOutdated(resp *http.Response) {
+	value := resp.Header.Get(clientCompatHeader)
+	if !strings.HasPrefix(value, "outdated") {
+		return
+	}
+	compatWarning.Do(func() {
+		fmt.Fprintf(compatWarningOutput, "⚠️  %s %s is older than the backend supports (%s); upgrade the CLI\n", clientName, version, value)
+	})
+}
+
+// requireCompatibleBackend refuses destructive commands when the backend
+// reports this CLI version as unsupported
+func requireCompatibleBackend(client *APIClient) error {
+	if os.Getenv("NEURATRADE_SKIP_COMPAT_CHECK") == "true" {
+		return nil
+	}
+	compat, err := client.GetCompat()
+	if err != nil {
+		// Backends from before the handshake have no /api/v1/compat endpoint
+		fmt.Fprintf(compatWarningOutput, "⚠️  Could not verify backend compatibility: %v\n", err)
+		return nil
+	}
+	if compat.Client != nil && !compat.Client.Compatible {
+		return fmt.Errorf("%s %s is older than %s, the minimum supported by backend %s; upgrade the CLI or set NEURATRADE_SKIP_COMPAT_CHECK=true",
+			clientName, version, compat.Client.MinVersion, compat.ServerVersion)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// bashCompletion is the bash completion script; %[1]s is the program name.
+// Candidates come from the CLI itself via --generate-bash-completion.
+const bashCompletion = `# bash completion for %[1]s
+# Load with: source <(%[1]s completion bash)
+
+_%[1]s_init_completion() {
+  COMPREPLY=()
+  _get_comp_words_by_ref "$@" cur prev words cword
+}
+
+_%[1]s_bash_autocomplete() {
+  if [[ "${COMP_WORDS[0]}" != "source" ]]; then
+    local cur opts words requestComp
+    COMPREPLY=()
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    if declare -F _init_completion >/dev/null 2>&1; then
+      _init_completion -n "=:" || return
+    else
+      _%[1]s_init_completion -n "=:" || return
+    fi
+    words=("${words[@]:0:$cword}")
+    if [[ "$cur" == "-"* ]]; then
+      requestComp="${words[*]} ${cur} --generate-bash-completion"
+    else
+      requestComp="${words[*]} --generate-bash-completion"
+    fi
+    opts=$(eval "${requestComp}" 2>/dev/null)
+    COMPREPLY=($(compgen -W "${opts}" -- ${cur}))
+    return 0
+  fi
+}
+
+complete -o bashdefault -o default -o nospace -F _%[1]s_bash_autocomplete %[1]s
+`
+
+// zshCompletion is the zsh completion script; %[1]s is the program name.
+const zshCompletion = `#compdef %[1]s
+# Load with: source <(%[1]s completion zsh)
+
+_%[1]s_zsh_autocomplete() {
+  local -a opts
+  local cur
+  cur=${words[-1]}
+  if [[ "$cur" == "-"* ]]; then
+    opts=("${(@f)$(${words[@]:0:#words[@]-1} ${cur} --generate-bash-completion)}")
+  else
+    opts=("${(@f)$(${words[@]:0:#words[@]-1} --generate-bash-completion)}")
+  fi
+
+  if [[ "${opts[1]}" != "" ]]; then
+    _describe 'values' opts
+  else
+    _files
+  fi
+}
+
+compdef _%[1]s_zsh_autocomplete %[1]s
+`
+
+// completionCommand prints shell completion scripts
+func completionCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "completion",
+		Usage:     "Generate a shell completion script (bash, zsh or fish)",
+		ArgsUsage: "bash|zsh|fish",
+		Action:    completionScript,
+		BashComplete: func(cCtx *cli.Context) {
+			if cCtx.NArg() == 0 {
+				fmt.Fprintln(cCtx.App.Writer, "bash\nzsh\nfish")
+			}
+		},
+	}
+}
+
+// completionScript writes the completion script for the requested shell
+func completionScript(cCtx *cli.Context) error {
+	name := cCtx.App.Name
+	switch shell := strings.ToLower(cCtx.Args().First()); shell {
+	case "bash":
+		_, err := fmt.Fprintf(cCtx.App.Writer, bashCompletion, name)
+		return err
+	case "zsh":
+		_, err := fmt.Fprintf(cCtx.App.Writer, zshCompletion, name)
+		return err
+	case "fish":
+		script, err := cCtx.App.ToFishCompletion()
+		if err != nil {
+			return fmt.Errorf("failed to generate fish completion: %w", err)
+		}
+		_, err = fmt.Fprint(cCtx.App.Writer, script)
+		return err
+	case "":
+		return fmt.Errorf("a shell is required (bash, zsh or fish)")
+	default:
+		return fmt.Errorf("unsupported shell %q (use bash, zsh or fish)", shell)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// migrationTimeout bounds migrate and rollback, which run DDL on the server.
+const migrationTimeout = 5 * time.Minute
+
+// MigrationInfo represents one migration in a status response
+type MigrationInfo struct {
+	Filename          string     `json:"filename"`
+	Status            string     `json:"status"`
+	Checksum          string     `json:"checksum,omitempty"`
+	RecordedChecksum  string     `json:"recorded_checksum,omitempty"`
+	Modified          bool       `json:"modified"`
+	AppliedAt         *time.Time `json:"applied_at,omitempty"`
+	RollbackAvailable bool       `json:"rollback_available"`
+}
+
+// MigrationStatusResponse represents the response for migration status
+type MigrationStatusResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		Directory  string          `json:"directory"`
+		Applied    int             `json:"applied"`
+		Pending    int             `json:"pending"`
+		Modified   int             `json:"modified"`
+		Migrations []MigrationInfo `json:"migrations"`
+	} `json:"data"`
+}
+
+// MigrationRunResponse represents the response for applying migrations
+type MigrationRunResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		Applied []MigrationInfo `json:"applied"`
+	} `json:"data"`
+}
+
+// MigrationRollbackResponse represents the response for rolling back a migration
+type MigrationRollbackResponse struct {
+	Status string        `json:"status"`
+	Data   MigrationInfo `json:"data"`
+}
+
+func shortChecksum(checksum string) string {
+	if len(checksum) > 12 {
+		return checksum[:12]
+	}
+	return checksum
+}
+
+// dbStatus lists applied and pending migrations
+func dbStatus(cCtx *cli.Context) error {
+	out := newOutput(cCtx)
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	respBody, err := client.makeRequest("GET", "/api/v1/admin/migrations", nil)
+	if err != nil {
+		return fmt.Errorf("failed to get migration status: %w", err)
+	}
+
+	var response MigrationStatusResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return out.Render(response.Data, func() {
+		out.Printf("📦 Migrations in %s: %d applied, %d pending\n", response.Data.Directory, response.Data.Applied, response.Data.Pending)
+		for _, m := range response.Data.Migrations {
+			icon := "✅"
+			switch m.Status {
+			case "pending":
+				icon = "⏳"
+			case "missing":
+				icon = "❓"
+			}
+			line := fmt.Sprintf("%s %-50s %-8s %s", icon, m.Filename, m.Status, shortChecksum(m.Checksum))
+			if m.Modified {
+				line += "  ⚠️  modified since applied"
+			}
+			out.Println(line)
+		}
+		if response.Data.Pending > 0 {
+			out.Println("Live trading is refused until pending migrations are applied (neuratrade db migrate).")
+		}
+	})
+}
+
+// dbMigrate applies all pending migrations
+func dbMigrate(cCtx *cli.Context) error {
+	out := newOutput(cCtx)
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	if err := requireCompatibleBackend(client); err != nil {
+		return err
+	}
+	client.HTTPClient.Timeout = migrationTimeout
+	respBody, err := client.makeRequest("POST", "/api/v1/admin/migrations/migrate", nil)
+	if err != nil {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+
+	var response MigrationRunResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return out.Render(response.Data, func() {
+		if len(response.Data.Applied) == 0 {
+			out.Println("✅ Database is up to date")
+			return
+		}
+		for _, m := range response.Data.Applied {
+			out.Printf("✅ Applied %s\n", m.Filename)
+		}
+	})
+}
+
+// dbRollback reverts the latest or a named migration
+func dbRollback(cCtx *cli.Context) error {
+	out := newOutput(cCtx)
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	if err := requireCompatibleBackend(client); err != nil {
+		return err
+	}
+	client.HTTPClient.Timeout = migrationTimeout
+	body := map[string]interface{}{
+		"filename": cCtx.Args().First(),
+		"force":    cCtx.Bool("force"),
+	}
+	respBody, err := client.makeRequest("POST", "/api/v1/admin/migrations/rollback", body)
+	if err != nil {
+		return fmt.Errorf("failed to roll back migration: %w", err)
+	}
+
+	var response MigrationRollbackResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	out.Printf("↩️  Rolled back %s\n", response.Data.Filename)
+	if !response.Data.RollbackAvailable {
+		out.Println("No rollback script was run; only the migration record was removed.")
+	}
+	return out.Render(response.Data, nil)
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// doctorCommand builds the "doctor" command: per-chat diagnostics, or with
+// --deep an end-to-end dry-run self-test of the trading pipeline
+func doctorCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "doctor",
+		Usage:  "Diagnose the chat's setup; --deep runs a dry-run trading cycle end to end, --since diffs the state behind decisions",
+		Action: runDoctor,
+		Flags: []cli.Flag{
+			chatIDFlag(false),
+			&cli.BoolFlag{Name: "deep", Usage: "Fetch market data, compute indicators, generate a signal, simulate an order and send a test notification"},
+			&cli.StringFlag{Name: "exchange", Usage: "Exchange the self-test fetches market data from (server default: binance)"},
+			&cli.StringFlag{Name: "symbol", Usage: "Symbol the self-test trades on paper (server default: BTC/USDT)"},
+			&cli.StringFlag{Name: "timeframe", Usage: "Candle timeframe for the indicators (server default: 5m)"},
+			&cli.StringFlag{Name: "since", Usage: "Show config, strategy, prompt and model changes since a time (RFC3339) or a duration ago, e.g. 24h"},
+			&cli.StringFlag{Name: "until", Usage: "End of the --since comparison, RFC3339 or a duration ago (default: the live state)"},
+		},
+	}
+}
+
+// runDoctor runs the selected diagnostics
+func runDoctor(cCtx *cli.Context) error {
+	if cCtx.Bool("deep") {
+		return runSelfTest(cCtx)
+	}
+	if cCtx.String("since") != "" {
+		return runStateDiff(cCtx)
+	}
+
+	chatID := chatIDValue(cCtx)
+	if chatID == "" {
+		return fmt.Errorf("chat-id is required (or run neuratrade doctor --deep)")
+	}
+	out := newOutput(cCtx)
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	respBody, err := client.makeRequest("GET", "/api/v1/telegram/internal/doctor?chat_id="+url.QueryEscape(chatID), nil)
+	if err != nil {
+		return fmt.Errorf("failed to run diagnostics: %w", err)
+	}
+	var response struct {
+		OverallStatus string `json:"overall_status"`
+		Summary       string `json:"summary"`
+		Checks        []struct {
+			Name    string `json:"name"`
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		} `json:"checks"`
+		StaleSymbols []struct {
+			Exchange string `json:"exchange"`
+			Symbol   string `json:"symbol"`
+			Reason   string `json:"reason"`
+		} `json:"stale_symbols"`
+		StateDiff *StateDiff `json:"state_diff"`
+	}
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return out.Render(json.RawMessage(respBody), func() {
+		out.Printf("🩺 Diagnostics for chat %s: %s\n", chatID, response.OverallStatus)
+		for _, check := range response.Checks {
+			line := fmt.Sprintf("  %s %s", doctorStatusMarker(check.Status), check.Name)
+			if check.Message != "" {
+				line += ": " + check.Message
+			}
+			out.Println(line)
+		}
+		if len(response.StaleSymbols) > 0 {
+			out.Println("Stale symbols (excluded from signals):")
+			for _, stale := range response.StaleSymbols {
+				out.Printf("  %s %s: %s\n", stale.Exchange, stale.Symbol, stale.Reason)
+			}
+		}
+		if diff := response.StateDiff; diff != nil && diff.Changed {
+			out.Println("Changes since the last decision:")
+			printStateChanges(out, diff.Changes)
+		}
+		if response.Summary != "" {
+			out.Println(response.Summary)
+		}
+		out.Println("Run neuratrade doctor --deep for an end-to-end dry run.")
+	})
+}
+
+// runSelfTest runs the backend self-test and fails when any stage failed
+func runSelfTest(cCtx *cli.Context) error {
+	out := newOutput(cCtx)
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	response, err := client.RunSelfTest(&RunSelfTestRequest{
+		Exchange:  cCtx.String("exchange"),
+		Symbol:    cCtx.String("symbol"),
+		Timeframe: cCtx.String("timeframe"),
+		ChatID:    chatIDValue(cCtx),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to run self-test: %w", err)
+	}
+
+	report := response.Data
+	if err := out.Render(report, func() {
+		out.Printf("🩺 Self-test on %s %s (%s)\n", report.Exchange, report.Symbol, report.Timeframe)
+		for _, stage := range report.Stages {
+			line := fmt.Sprintf("  %s %-13s %8.1f ms", doctorStatusMarker(stage.Status), stage.Name, stage.DurationMs)
+			if stage.Message != "" {
+				line += "  " + stage.Message
+			}
+			out.Println(line)
+		}
+		out.Printf("Total %.1f ms\n", report.DurationMs)
+	}); err != nil {
+		return err
+	}
+	if !report.Passed {
+		return fmt.Errorf("self-test failed")
+	}
+	return nil
+}
+
+// runStateDiff diffs the config, strategy parameters, prompt version and
+// model behind decisions at two times
+func runStateDiff(cCtx *cli.Context) error {
+	out := newOutput(cCtx)
+	now := time.Now().UTC()
+	from, err := parseDoctorTime(cCtx.String("since"), now)
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Error: --since %v", err), 1)
+	}
+	var to string
+	if cCtx.String("until") != "" {
+		if to, err = parseDoctorTime(cCtx.String("until"), now); err != nil {
+			return cli.Exit(fmt.Sprintf("Error: --until %v", err), 1)
+		}
+	}
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	response, err := client.GetStateDiff(from, to)
+	if err != nil {
+		return fmt.Errorf("failed to diff state: %w", err)
+	}
+
+	diff := response.Data
+	return out.Render(diff, func() {
+		target := "live state"
+		if diff.To.DecisionID != "" {
+			target = fmt.Sprintf("decision %s at %s", diff.To.DecisionID, diff.To.At)
+		}
+		out.Printf("🧬 State of decision %s at %s → %s\n", diff.From.DecisionID, diff.From.At, target)
+		if !diff.Changed {
+			out.Println("  unchanged")
+			return
+		}
+		printStateChanges(out, diff.Changes)
+	})
+}
+
+// printStateChanges prints one line per changed setting, prompt or model
+func printStateChanges(out *output, changes []StateChange) {
+	orUnset := func(value string) string {
+		if value == "" {
+			return "(unset)"
+		}
+		return value
+	}
+	for _, change := range changes {
+		name := change.Kind
+		if change.Key != "" {
+			name += " " + change.Key
+		}
+		out.Printf("  %s: %s → %s\n", name, orUnset(change.From), orUnset(change.To))
+	}
+}
+
+// parseDoctorTime accepts an RFC3339 time or a duration before now
+func parseDoctorTime(value string, now time.Time) (string, error) {
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		return at.UTC().Format(time.RFC3339), nil
+	}
+	ago, err := time.ParseDuration(value)
+	if err != nil || ago < 0 {
+		return "", fmt.Errorf("must be RFC3339 or a duration ago, e.g. 24h")
+	}
+	return now.Add(-ago).Format(time.RFC3339), nil
+}
+
+func doctorStatusMarker(status string) string {
+	switch status {
+	case "passed", "healthy":
+		return "✅"
+	case "skipped":
+		return "⏭️ "
+	case "warning":
+		return "⚠️ "
+	default:
+		return "❌"
+	}
+}
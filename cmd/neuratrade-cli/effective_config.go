@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// configEffective prints the backend configuration of a mode with its
+// overlay applied; the full settings are available with -o json
+func configEffective(cCtx *cli.Context) error {
+	out := newOutput(cCtx)
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	response, err := client.GetEffectiveConfig(cCtx.String("mode"))
+	if err != nil {
+		return fmt.Errorf("failed to get effective config: %w", err)
+	}
+
+	effective := response.Data
+	return out.Render(effective, func() {
+		active := ""
+		if effective.Active {
+			active = " (active)"
+		}
+		out.Printf("⚙️  Effective %s configuration%s\n", effective.Mode, active)
+		if len(effective.Overrides) == 0 {
+			out.Println("  No overrides; the base configuration applies")
+			return
+		}
+		out.Printf("  %d overridden settings:\n", len(effective.Overrides))
+		for _, key := range effective.Overrides {
+			out.Printf("  %-40s %v\n", key, lookupSetting(effective.Settings, key))
+		}
+	})
+}
+
+// lookupSetting resolves a dotted key in nested settings
+func lookupSetting(settings map[string]interface{}, key string) interface{} {
+	var value interface{} = settings
+	for _, part := range strings.Split(key, ".") {
+		nested, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = nested[part]
+	}
+	return value
+}
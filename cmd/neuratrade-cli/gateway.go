@@ -320,16 +320,26 @@ func stopServiceByPIDFile(name, pidFile string) error {
 
 // gatewayStatus shows the status of NeuraTrade services
 func gatewayStatus(cCtx *cli.Context) error {
-	fmt.Println("📊 NeuraTrade Service Status")
-	fmt.Println("============================")
-	fmt.Println()
+	out := newOutput(cCtx)
+	out.Println("📊 NeuraTrade Service Status")
+	out.Println("============================")
+	out.Println()
 
 	// Check if processes are running
-	checkProcess("neuratrade-server", "Backend API")
-	checkProcess("ccxt-service", "CCXT Service")
-	checkProcess("telegram-service", "Telegram Service")
+	processes := []ProcessStatus{
+		checkProcess("neuratrade-server", "Backend API"),
+		checkProcess("ccxt-service", "CCXT Service"),
+		checkProcess("telegram-service", "Telegram Service"),
+	}
+	for _, p := range processes {
+		if p.Running {
+			out.Printf("✅ %s: Running (PID: %s)\n", p.Name, p.PID)
+		} else {
+			out.Printf("❌ %s: Not running\n", p.Name)
+		}
+	}
 
-	fmt.Println()
+	out.Println()
 
 	// Check health endpoint
 	backendPort := getEnvOrDefault("BACKEND_HOST_PORT", "")
@@ -340,8 +350,8 @@ func gatewayStatus(cCtx *cli.Context) error {
 			backendPort = "8080"
 		}
 	}
-	fmt.Printf("🏥 Health Check: http://localhost:%s/health\n", backendPort)
-	fmt.Println()
+	out.Printf("🏥 Health Check: http://localhost:%s/health\n", backendPort)
+	out.Println()
 
 	// Try to get health
 	baseURL := fmt.Sprintf("http://localhost:%s", backendPort)
@@ -350,50 +360,56 @@ func gatewayStatus(cCtx *cli.Context) error {
 
 	respBody, err := client.makeRequest("GET", "/health", nil)
 	if err != nil {
-		fmt.Printf("❌ Health check failed: %v\n", err)
-		fmt.Println()
-		fmt.Println("Make sure the backend is running:")
-		fmt.Println("  neuratrade gateway start")
+		out.Printf("❌ Health check failed: %v\n", err)
+		out.Println()
+		out.Println("Make sure the backend is running:")
+		out.Println("  neuratrade gateway start")
 		return err
 	}
 
 	var healthResp map[string]interface{}
 	if err := json.Unmarshal(respBody, &healthResp); err != nil {
-		fmt.Printf("❌ Could not parse health response: %v\n", err)
+		out.Printf("❌ Could not parse health response: %v\n", err)
 		return err
 	}
 
-	status := "Unknown"
-	if v, ok := healthResp["status"].(string); ok {
-		status = v
-	}
-
-	fmt.Printf("✅ Backend Status: %s\n", status)
+	return out.Render(map[string]interface{}{"processes": processes, "health": healthResp}, func() {
+		status := "Unknown"
+		if v, ok := healthResp["status"].(string); ok {
+			status = v
+		}
 
-	if services, ok := healthResp["services"].(map[string]interface{}); ok {
-		fmt.Println()
-		fmt.Println("Service Health:")
-		for name, svcStatus := range services {
-			icon := "✓"
-			if svcStatus != "healthy" && svcStatus != "ok" {
-				icon = "⚠️ "
+		out.Printf("✅ Backend Status: %s\n", status)
+
+		if services, ok := healthResp["services"].(map[string]interface{}); ok {
+			out.Println()
+			out.Println("Service Health:")
+			for name, svcStatus := range services {
+				icon := "✓"
+				if svcStatus != "healthy" && svcStatus != "ok" {
+					icon = "⚠️ "
+				}
+				out.Printf("  %s %s: %v\n", icon, name, svcStatus)
 			}
-			fmt.Printf("  %s %s: %v\n", icon, name, svcStatus)
 		}
-	}
+	})
+}
 
-	return nil
+// ProcessStatus represents whether a local service process is running
+type ProcessStatus struct {
+	Name    string `json:"name"`
+	Running bool   `json:"running"`
+	PID     string `json:"pid,omitempty"`
 }
 
 // checkProcess checks if a process is running
-func checkProcess(processName, displayName string) {
+func checkProcess(processName, displayName string) ProcessStatus {
 	cmd := exec.Command("pgrep", "-f", processName)
 	output, err := cmd.Output()
 	if err != nil || len(output) == 0 {
-		fmt.Printf("❌ %s: Not running\n", displayName)
-	} else {
-		fmt.Printf("✅ %s: Running (PID: %s)\n", displayName, string(output[:len(output)-1]))
+		return ProcessStatus{Name: displayName}
 	}
+	return ProcessStatus{Name: displayName, Running: true, PID: string(output[:len(output)-1])}
 }
 
 // printServiceStatus prints service health status
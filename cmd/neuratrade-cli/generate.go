@@ -0,0 +1,7 @@
+package main
+
+// The API client in api_client_gen.go is generated from the backend's OpenAPI
+// document (also served at /api/v1/openapi.json). Regenerate after changing
+// documented handlers in services/backend-api/internal/api/openapi_spec.go.
+//go:generate sh -c "cd ../../services/backend-api && go run ./cmd/server openapi > ../../cmd/neuratrade-cli/openapi.json"
+//go:generate go run ./internal/apigen -in openapi.json -out api_client_gen.go
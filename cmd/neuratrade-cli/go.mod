@@ -2,12 +2,14 @@ module github.com/irfndi/neuratrade/cmd/neuratrade-cli
 
 go 1.25
 
-require github.com/urfave/cli/v2 v2.27.2
+require (
+	github.com/urfave/cli/v2 v2.27.2
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 require (
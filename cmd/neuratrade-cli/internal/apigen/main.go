@@ -0,0 +1,324 @@
+// Command apigen generates the CLI's typed API client from the backend's
+// OpenAPI document. Every component schema becomes a Go struct and every
+// operation with an operationId becomes a method on APIClient.
+//
+// Usage:
+//
+//	go run ./internal/apigen -in openapi.json -out api_client_gen.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type document struct {
+	Paths      map[string]map[string]*operation `json:"paths"`
+	Components struct {
+		Schemas map[string]*schema `json:"schemas"`
+	} `json:"components"`
+}
+
+type operation struct {
+	OperationID string      `json:"operationId"`
+	Summary     string      `json:"summary"`
+	Parameters  []parameter `json:"parameters"`
+	RequestBody *struct {
+		Content map[string]struct {
+			Schema *schema `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]struct {
+		Content map[string]struct {
+			Schema *schema `json:"schema"`
+		} `json:"content"`
+	} `json:"responses"`
+}
+
+type parameter struct {
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required"`
+}
+
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Properties           map[string]*schema `json:"properties"`
+	Required             []string           `json:"required"`
+	Items                *schema            `json:"items"`
+	AdditionalProperties *schema            `json:"additionalProperties"`
+	Nullable             bool               `json:"nullable"`
+}
+
+func main() {
+	in := flag.String("in", "openapi.json", "OpenAPI document to read")
+	out := flag.String("out", "api_client_gen.go", "Go file to write")
+	pkg := flag.String("package", "main", "package name of the generated file")
+	flag.Parse()
+
+	spec, err := os.ReadFile(*in)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "apigen: %v\n", err)
+		os.Exit(1)
+	}
+	code, err := generate(spec, *pkg, *in)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "apigen: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*out, code, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "apigen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// generate renders the client source for an OpenAPI document.
+func generate(spec []byte, pkg, source string) ([]byte, error) {
+	var doc document
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+
+	var body bytes.Buffer
+	usesURL := false
+
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeStruct(&body, name, doc.Components.Schemas[name])
+	}
+
+	type pathOp struct {
+		method string
+		path   string
+		op     *operation
+	}
+	var ops []pathOp
+	for path, methods := range doc.Paths {
+		for method, op := range methods {
+			if op != nil && op.OperationID != "" {
+				ops = append(ops, pathOp{strings.ToUpper(method), path, op})
+			}
+		}
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].op.OperationID < ops[j].op.OperationID })
+	for _, o := range ops {
+		if writeMethod(&body, o.method, o.path, o.op) {
+			usesURL = true
+		}
+	}
+
+	var file bytes.Buffer
+	fmt.Fprintf(&file, "// Code generated by apigen from %s; DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&file, "package %s\n\n", pkg)
+	file.WriteString("import (\n\t\"encoding/json\"\n\t\"fmt\"\n")
+	if usesURL {
+		file.WriteString("\t\"net/url\"\n")
+	}
+	file.WriteString(")\n\n")
+	file.Write(body.Bytes())
+
+	formatted, err := format.Source(file.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return formatted, nil
+}
+
+func writeStruct(buf *bytes.Buffer, name string, s *schema) {
+	fmt.Fprintf(buf, "// %s is generated from the %s schema.\n", name, name)
+	fmt.Fprintf(buf, "type %s struct {\n", name)
+	required := map[string]bool{}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	props := make([]string, 0, len(s.Properties))
+	for prop := range s.Properties {
+		props = append(props, prop)
+	}
+	sort.Strings(props)
+	for _, prop := range props {
+		goType := goTypeFor(s.Properties[prop], !required[prop])
+		tag := prop
+		if !required[prop] {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(buf, "\t%s %s `json:%q`\n", exportedName(prop), goType, tag)
+	}
+	buf.WriteString("}\n\n")
+}
+
+// goTypeFor maps a schema to a Go type. Optional references become pointers
+// so that absent objects decode to nil.
+func goTypeFor(s *schema, optional bool) string {
+	if s == nil {
+		return "interface{}"
+	}
+	if s.Ref != "" {
+		name := refName(s.Ref)
+		if optional {
+			return "*" + name
+		}
+		return name
+	}
+
+	var goType string
+	switch s.Type {
+	case "string":
+		goType = "string"
+	case "boolean":
+		goType = "bool"
+	case "integer":
+		goType = "int"
+		if s.Format == "int64" {
+			goType = "int64"
+		}
+	case "number":
+		goType = "float64"
+	case "array":
+		return "[]" + goTypeFor(s.Items, false)
+	case "object":
+		if s.AdditionalProperties != nil {
+			return "map[string]" + goTypeFor(s.AdditionalProperties, false)
+		}
+		return "map[string]interface{}"
+	default:
+		return "interface{}"
+	}
+	if s.Nullable {
+		return "*" + goType
+	}
+	return goType
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([A-Za-z0-9_]+)\}`)
+
+// writeMethod renders an APIClient method and reports whether it needs net/url.
+func writeMethod(buf *bytes.Buffer, method, path string, op *operation) bool {
+	var args []string
+	var pathParams, queryParams []parameter
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "path":
+			pathParams = append(pathParams, p)
+			args = append(args, paramName(p.Name)+" string")
+		case "query":
+			queryParams = append(queryParams, p)
+			args = append(args, paramName(p.Name)+" string")
+		}
+	}
+
+	requestType := ""
+	if op.RequestBody != nil {
+		if media, ok := op.RequestBody.Content["application/json"]; ok && media.Schema != nil {
+			requestType = goTypeFor(media.Schema, false)
+			args = append(args, "req *"+strings.TrimPrefix(requestType, "*"))
+		}
+	}
+
+	responseType := ""
+	if success, ok := op.Responses["200"]; ok {
+		if media, ok := success.Content["application/json"]; ok && media.Schema != nil {
+			responseType = strings.TrimPrefix(goTypeFor(media.Schema, false), "*")
+		}
+	}
+
+	summary := op.Summary
+	if summary == "" {
+		summary = "calls " + method + " " + path
+	} else {
+		summary = strings.ToLower(summary[:1]) + summary[1:]
+	}
+	fmt.Fprintf(buf, "// %s %s.\n//\n// %s %s\n", op.OperationID, summary, method, path)
+	returns := "error"
+	if responseType != "" {
+		returns = "(*" + responseType + ", error)"
+	}
+	fmt.Fprintf(buf, "func (c *APIClient) %s(%s) %s {\n", op.OperationID, strings.Join(args, ", "), returns)
+
+	usesURL := len(pathParams) > 0 || len(queryParams) > 0
+	endpoint := fmt.Sprintf("%q", path)
+	if len(pathParams) > 0 {
+		format := pathParamPattern.ReplaceAllString(path, "%s")
+		var values []string
+		for _, p := range pathParams {
+			values = append(values, "url.PathEscape("+paramName(p.Name)+")")
+		}
+		endpoint = fmt.Sprintf("fmt.Sprintf(%q, %s)", format, strings.Join(values, ", "))
+	}
+	fmt.Fprintf(buf, "\tendpoint := %s\n", endpoint)
+	if len(queryParams) > 0 {
+		buf.WriteString("\tquery := url.Values{}\n")
+		for _, p := range queryParams {
+			name := paramName(p.Name)
+			fmt.Fprintf(buf, "\tif %s != \"\" {\n\t\tquery.Set(%q, %s)\n\t}\n", name, p.Name, name)
+		}
+		buf.WriteString("\tif encoded := query.Encode(); encoded != \"\" {\n\t\tendpoint += \"?\" + encoded\n\t}\n")
+	}
+
+	body := "nil"
+	if requestType != "" {
+		body = "req"
+	}
+	failure := "return err"
+	if responseType != "" {
+		failure = "return nil, err"
+	}
+	result := "_"
+	if responseType != "" {
+		result = "respBody"
+	}
+	fmt.Fprintf(buf, "\t%s, err := c.makeRequest(%q, endpoint, %s)\n\tif err != nil {\n\t\t%s\n\t}\n", result, method, body, failure)
+	if responseType == "" {
+		buf.WriteString("\treturn nil\n}\n\n")
+		return usesURL
+	}
+	fmt.Fprintf(buf, "\n\tvar response %s\n", responseType)
+	buf.WriteString("\tif err := json.Unmarshal(respBody, &response); err != nil {\n\t\treturn nil, fmt.Errorf(\"failed to unmarshal response: %w\", err)\n\t}\n\n\treturn &response, nil\n}\n\n")
+	return usesURL
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+var initialisms = map[string]string{
+	"id": "ID", "ai": "AI", "api": "API", "ok": "OK", "url": "URL",
+	"uuid": "UUID", "http": "HTTP", "json": "JSON", "ip": "IP",
+}
+
+// exportedName converts snake_case JSON names to Go field names.
+func exportedName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' }) {
+		if upper, ok := initialisms[strings.ToLower(part)]; ok {
+			b.WriteString(upper)
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// paramName converts parameter names to lowerCamel Go identifiers.
+func paramName(name string) string {
+	exported := exportedName(name)
+	for prefix, upper := range initialisms {
+		if strings.HasPrefix(exported, upper) && (len(exported) == len(upper) || exported[len(upper)] >= 'A' && exported[len(upper)] <= 'Z') {
+			return prefix + exported[len(upper):]
+		}
+	}
+	return strings.ToLower(exported[:1]) + exported[1:]
+}
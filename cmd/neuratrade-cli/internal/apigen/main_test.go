@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratedClientIsUpToDate(t *testing.T) {
+	spec, err := os.ReadFile("../../openapi.json")
+	require.NoError(t, err)
+	committed, err := os.ReadFile("../../api_client_gen.go")
+	require.NoError(t, err)
+
+	generated, err := generate(spec, "main", "openapi.json")
+	require.NoError(t, err)
+	assert.Equal(t, string(committed), string(generated), "run `go generate` in cmd/neuratrade-cli")
+}
+
+func TestGenerate_PathAndQueryParams(t *testing.T) {
+	spec := []byte(`{
+		"paths": {
+			"/api/v1/items/{item_id}": {
+				"get": {
+					"operationId": "GetItem",
+					"parameters": [
+						{"name": "item_id", "in": "path", "required": true},
+						{"name": "view", "in": "query"}
+					],
+					"responses": {"200": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Item"}}}}}
+				}
+			}
+		},
+		"components": {"schemas": {"Item": {"type": "object", "properties": {"id": {"type": "string"}, "price": {"type": "number", "nullable": true}}, "required": ["id"]}}}
+	}`)
+
+	code, err := generate(spec, "main", "test.json")
+	require.NoError(t, err)
+	src := string(code)
+	assert.Contains(t, src, "func (c *APIClient) GetItem(itemID string, view string) (*Item, error)")
+	assert.Contains(t, src, `fmt.Sprintf("/api/v1/items/%s", url.PathEscape(itemID))`)
+	assert.Contains(t, src, `query.Set("view", view)`)
+	assert.Contains(t, src, "ID    string   `json:\"id\"`")
+	assert.Contains(t, src, "Price *float64 `json:\"price,omitempty\"`")
+}
+
+func TestExportedName(t *testing.T) {
+	assert.Equal(t, "ChatID", exportedName("chat_id"))
+	assert.Equal(t, "AIModelID", exportedName("ai_model_id"))
+	assert.Equal(t, "chatID", paramName("chat_id"))
+	assert.Equal(t, "okStatus", paramName("ok_status"))
+}
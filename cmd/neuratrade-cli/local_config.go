@@ -9,7 +9,7 @@ import (
 
 type localConfig struct {
 	TelegramTestChatID string `json:"telegram_test_chat_id"`
-	Server struct {
+	Server             struct {
 		Host string `json:"host"`
 		Port int    `json:"port"`
 	} `json:"server"`
@@ -41,8 +41,21 @@ type localConfig struct {
 		Provider string `json:"provider"`
 		Model    string `json:"model"`
 	} `json:"ai"`
+	ActiveProfile string                   `json:"active_profile"`
+	Profiles      map[string]profileConfig `json:"profiles"`
 }
 
+// profileConfig holds the per-environment connection settings selected with
+// --profile, NEURATRADE_PROFILE or active_profile in config.json
+type profileConfig struct {
+	BaseURL string `json:"base_url,omitempty"`
+	APIKey  string `json:"api_key,omitempty"`
+	ChatID  string `json:"chat_id,omitempty"`
+}
+
+// profileOverride is the --profile flag value, set before a command runs
+var profileOverride string
+
 func defaultNeuraTradeHome() string {
 	if v := os.Getenv("NEURATRADE_HOME"); v != "" {
 		return v
@@ -87,6 +100,9 @@ func configChatID(cfg *localConfig) string {
 	if cfg == nil {
 		return ""
 	}
+	if profile, ok := activeProfile(cfg); ok && profile.ChatID != "" {
+		return profile.ChatID
+	}
 	if cfg.Telegram.ChatID != "" {
 		return cfg.Telegram.ChatID
 	}
@@ -95,3 +111,79 @@ func configChatID(cfg *localConfig) string {
 	}
 	return cfg.TelegramTestChatID
 }
+
+// activeProfileName returns the selected profile: the --profile flag, then
+// NEURATRADE_PROFILE, then active_profile from config.json
+func activeProfileName(cfg *localConfig) string {
+	if profileOverride != "" {
+		return profileOverride
+	}
+	if v := os.Getenv("NEURATRADE_PROFILE"); v != "" {
+		return v
+	}
+	if cfg != nil {
+		return cfg.ActiveProfile
+	}
+	return ""
+}
+
+// activeProfile returns the selected profile settings, if any
+func activeProfile(cfg *localConfig) (profileConfig, bool) {
+	if cfg == nil {
+		return profileConfig{}, false
+	}
+	name := activeProfileName(cfg)
+	if name == "" {
+		return profileConfig{}, false
+	}
+	profile, ok := cfg.Profiles[name]
+	return profile, ok
+}
+
+// editLocalConfig applies edit to config.json as a generic map so keys the
+// CLI does not model are preserved. With create, a missing file is created.
+func editLocalConfig(home string, create bool, edit func(config map[string]interface{}) error) error {
+	configPath := filepath.Join(home, "config.json")
+	config := make(map[string]interface{})
+	mode := os.FileMode(0600)
+
+	data, err := os.ReadFile(configPath)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &config); err != nil {
+			return fmt.Errorf("parse config: %w", err)
+		}
+		if st, statErr := os.Stat(configPath); statErr == nil {
+			mode = st.Mode().Perm()
+		}
+	case create && os.IsNotExist(err):
+		if err := os.MkdirAll(home, 0700); err != nil {
+			return fmt.Errorf("create config directory: %w", err)
+		}
+	default:
+		return fmt.Errorf("read config: %w", err)
+	}
+
+	if err := edit(config); err != nil {
+		return err
+	}
+
+	updated, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal config: %w", err)
+	}
+	if err := os.WriteFile(configPath, updated, mode); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	return nil
+}
+
+// configSection returns the nested map at key, creating it when missing
+func configSection(config map[string]interface{}, key string) map[string]interface{} {
+	section, ok := config[key].(map[string]interface{})
+	if !ok {
+		section = make(map[string]interface{})
+		config[key] = section
+	}
+	return section
+}
@@ -36,59 +36,44 @@ func persistChatIDToConfig(chatID string) error {
 		return nil
 	}
 
-	configPath := path.Join(defaultNeuraTradeHome(), "config.json")
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return fmt.Errorf("read config: %w", err)
-	}
-
-	var config map[string]interface{}
-	if err := json.Unmarshal(data, &config); err != nil {
-		return fmt.Errorf("parse config: %w", err)
-	}
-
-	telegramCfg, ok := config["telegram"].(map[string]interface{})
-	if !ok {
-		telegramCfg = make(map[string]interface{})
-	}
-	telegramCfg["chat_id"] = chatID
-	config["telegram"] = telegramCfg
-
-	servicesCfg, ok := config["services"].(map[string]interface{})
-	if !ok {
-		servicesCfg = make(map[string]interface{})
-	}
-	servicesTelegramCfg, ok := servicesCfg["telegram"].(map[string]interface{})
-	if !ok {
-		servicesTelegramCfg = make(map[string]interface{})
-	}
-	servicesTelegramCfg["chat_id"] = chatID
-	servicesCfg["telegram"] = servicesTelegramCfg
-	config["services"] = servicesCfg
+	home := defaultNeuraTradeHome()
+	profileName := activeProfileName(getConfigValue(home))
+
+	return editLocalConfig(home, false, func(config map[string]interface{}) error {
+		// A chat bound under a profile belongs to that environment only
+		if profileName != "" {
+			profiles := configSection(config, "profiles")
+			if profile, ok := profiles[profileName].(map[string]interface{}); ok {
+				profile["chat_id"] = chatID
+				return nil
+			}
+		}
 
-	updated, err := json.MarshalIndent(config, "", "  ")
-	if err != nil {
-		return fmt.Errorf("marshal config: %w", err)
-	}
+		configSection(config, "telegram")["chat_id"] = chatID
+		configSection(configSection(config, "services"), "telegram")["chat_id"] = chatID
+		return nil
+	})
+}
 
-	mode := os.FileMode(0600)
-	if st, statErr := os.Stat(configPath); statErr == nil {
-		mode = st.Mode().Perm()
+// chatIDFlag is evaluated when the app is built, before --profile is parsed,
+// so the config default is applied by chatIDValue instead of the flag Value
+func chatIDFlag(required bool) *cli.StringFlag {
+	usage := "Telegram chat ID (auto from the active profile or config if set)"
+	if required {
+		usage = "Telegram chat ID (required unless set in the active profile or config)"
 	}
-	if err := os.WriteFile(configPath, updated, mode); err != nil {
-		return fmt.Errorf("write config: %w", err)
+	return &cli.StringFlag{
+		Name:  "chat-id",
+		Usage: usage,
 	}
-
-	return nil
 }
 
-func chatIDFlag(required bool) *cli.StringFlag {
-	return &cli.StringFlag{
-		Name:     "chat-id",
-		Usage:    "Telegram chat ID (auto from config if set)",
-		Required: required,
-		Value:    defaultChatID(),
+// chatIDValue returns the --chat-id flag or the configured chat ID
+func chatIDValue(cCtx *cli.Context) string {
+	if chatID := cCtx.String("chat-id"); chatID != "" {
+		return chatID
 	}
+	return defaultChatID()
 }
 
 // APIClient handles communication with the NeuraTrade backend API
@@ -174,6 +159,7 @@ func (c *APIClient) makeRequest(method, endpoint string, body interface{}) ([]by
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(clientVersionHeader, clientVersionValue())
 	if c.APIKey != "" {
 		req.Header.Set("X-API-Key", c.APIKey)
 	}
@@ -183,6 +169,7 @@ func (c *APIClient) makeRequest(method, endpoint string, body interface{}) ([]by
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
+	warnIfOutdated(resp)
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -245,9 +232,11 @@ func (c *APIClient) BindOperatorProfile(req *BindOperatorRequest) (*BindOperator
 
 func main() {
 	app := &cli.App{
-		Name:    "neuratrade",
-		Usage:   "NeuraTrade CLI - AI-powered trading platform",
-		Version: version,
+		Name:                 "neuratrade",
+		Usage:                "NeuraTrade CLI - AI-powered trading platform",
+		Version:              version,
+		EnableBashCompletion: true,
+		Flags:                []cli.Flag{outputFlag, profileFlag},
 		Commands: []*cli.Command{
 			{
 				Name:    "generate-auth-code",
@@ -405,10 +394,10 @@ func main() {
 					},
 					{
 						Name:   "balance",
-						Usage:  "Check account balance",
+						Usage:  "Check account equity: exchange balances, open-position PnL and external wallets",
 						Action: checkBalance,
 						Flags: []cli.Flag{
-							chatIDFlag(true),
+							chatIDFlag(false),
 						},
 					},
 				},
@@ -450,6 +439,107 @@ func main() {
 						Usage:  "Show full configuration (mask secrets)",
 						Action: configShow,
 					},
+					{
+						Name:   "effective",
+						Usage:  "Show the backend configuration of a mode with its overlay applied",
+						Action: configEffective,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "mode",
+								Usage: "dry, paper or live (default: the active mode)",
+							},
+						},
+					},
+					{
+						Name:      "use-profile",
+						Usage:     "Switch the active profile, creating or updating it",
+						ArgsUsage: "[--base-url URL] [--api-key KEY] [--chat-id ID] <name>",
+						Action:    configUseProfile,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "base-url",
+								Usage: "Backend API base URL for the profile",
+							},
+							&cli.StringFlag{
+								Name:  "api-key",
+								Usage: "Admin API key for the profile",
+							},
+							&cli.StringFlag{
+								Name:  "chat-id",
+								Usage: "Telegram chat ID for the profile",
+							},
+						},
+					},
+					{
+						Name:   "profiles",
+						Usage:  "List configured profiles",
+						Action: configProfiles,
+					},
+				},
+			},
+			{
+				Name:  "backup",
+				Usage: "Create and restore encrypted application state backups",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "create",
+						Usage:  "Export quests, autonomous states, risk limits, user bindings and open positions",
+						Action: backupCreate,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:    "output",
+								Aliases: []string{"o"},
+								Usage:   "Archive file to write (default neuratrade-backup-<timestamp>.json)",
+							},
+							backupPassphraseFlag(),
+						},
+					},
+					{
+						Name:   "restore",
+						Usage:  "Restore a backup archive into the connected backend",
+						Action: backupRestore,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "input",
+								Aliases:  []string{"i"},
+								Usage:    "Archive file to restore",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "operator",
+								Usage: "Operator recorded on the restored trading mode",
+							},
+							backupPassphraseFlag(),
+						},
+					},
+				},
+			},
+			{
+				Name:  "db",
+				Usage: "Inspect and apply database migrations",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "status",
+						Usage:  "List applied and pending migrations with checksums",
+						Action: dbStatus,
+					},
+					{
+						Name:   "migrate",
+						Usage:  "Apply all pending migrations",
+						Action: dbMigrate,
+					},
+					{
+						Name:      "rollback",
+						Usage:     "Roll back the latest or the named migration",
+						ArgsUsage: "[filename]",
+						Action:    dbRollback,
+						Flags: []cli.Flag{
+							&cli.BoolFlag{
+								Name:  "force",
+								Usage: "Remove the migration record even without a rollback script",
+							},
+						},
+					},
 				},
 			},
 		},
@@ -465,7 +555,10 @@ func main() {
 				os.Exit(0)
 			}()
 
-			return nil
+			if err := validateOutputFormat(cCtx); err != nil {
+				return err
+			}
+			return selectProfile(cCtx)
 		},
 	}
 
@@ -480,6 +573,7 @@ func main() {
 				Action: beginAutonomous,
 				Flags: []cli.Flag{
 					chatIDFlag(true),
+					operatingProfileFlag,
 				},
 			},
 			{
@@ -514,9 +608,22 @@ func main() {
 					chatIDFlag(true),
 				},
 			},
+			operatingProfileCommand(),
 		},
 	})
 
+	app.Commands = append(app.Commands, searchCommand())
+	app.Commands = append(app.Commands, alertsCommand())
+	app.Commands = append(app.Commands, strategyCommand())
+	app.Commands = append(app.Commands, setupCommand())
+	app.Commands = append(app.Commands, doctorCommand())
+	app.Commands = append(app.Commands, stressTestCommand())
+	app.Commands = append(app.Commands, collectCommand())
+	app.Commands = append(app.Commands, auditCommand())
+	app.Commands = append(app.Commands, usersCommand())
+	app.Commands = append(app.Commands, replayCommand())
+	app.Commands = append(app.Commands, completionCommand())
+
 	if err := app.Run(os.Args); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -555,6 +662,7 @@ func (c *APIClient) GenerateBindingCode(userID string) (*GenerateBindingCodeResp
 
 // generateAuthCode generates a random auth code for Telegram binding
 func generateAuthCode(cCtx *cli.Context) error {
+	out := newOutput(cCtx)
 	baseURL := getBaseURL()
 	apiKey := getAPIKey()
 
@@ -567,23 +675,24 @@ func generateAuthCode(cCtx *cli.Context) error {
 	response, err := client.GenerateBindingCode(userID)
 	if err != nil {
 		// If API call fails, fall back to generating a local code
-		fmt.Printf("Warning: Could not reach API: %v\n", err)
-		fmt.Println("Generating local auth code for demonstration purposes...")
+		out.Printf("Warning: Could not reach API: %v\n", err)
+		out.Println("Generating local auth code for demonstration purposes...")
 		authCode := generateRandomString(8)
-		fmt.Printf("Generated Auth Code: %s\n", authCode)
-		fmt.Println("Use this code with /bind command in Telegram to link your account.")
-		return nil
-	}
-
-	if response.Success {
-		fmt.Printf("Generated Auth Code for user %s\n", response.UserID)
-		fmt.Printf("Expires at: %s\n", response.ExpiresAt)
-		fmt.Println(response.Message)
-	} else {
-		fmt.Printf("Failed to generate auth code: %s\n", response.Message)
+		return out.Render(map[string]interface{}{"auth_code": authCode, "local": true}, func() {
+			out.Printf("Generated Auth Code: %s\n", authCode)
+			out.Println("Use this code with /bind command in Telegram to link your account.")
+		})
 	}
 
-	return nil
+	return out.Render(response, func() {
+		if response.Success {
+			out.Printf("Generated Auth Code for user %s\n", response.UserID)
+			out.Printf("Expires at: %s\n", response.ExpiresAt)
+			out.Println(response.Message)
+		} else {
+			out.Printf("Failed to generate auth code: %s\n", response.Message)
+		}
+	})
 }
 
 // getBaseURL gets the base URL from environment variable or returns default
@@ -591,7 +700,9 @@ func getBaseURL() string {
 	baseURL := os.Getenv("NEURATRADE_API_BASE_URL")
 	if baseURL == "" {
 		cfg := getConfigValue(defaultNeuraTradeHome())
-		if cfg != nil && cfg.Telegram.ApiBaseURL != "" {
+		if profile, ok := activeProfile(cfg); ok && profile.BaseURL != "" {
+			baseURL = profile.BaseURL
+		} else if cfg != nil && cfg.Telegram.ApiBaseURL != "" {
 			baseURL = cfg.Telegram.ApiBaseURL
 		} else if cfg != nil && cfg.Server.Port > 0 {
 			baseURL = fmt.Sprintf("http://localhost:%d", cfg.Server.Port)
@@ -608,7 +719,11 @@ func getAPIKey() string {
 	if v := os.Getenv("NEURATRADE_API_KEY"); v != "" {
 		return v
 	}
-	return configAdminAPIKey(getConfigValue(defaultNeuraTradeHome()))
+	cfg := getConfigValue(defaultNeuraTradeHome())
+	if profile, ok := activeProfile(cfg); ok && profile.APIKey != "" {
+		return profile.APIKey
+	}
+	return configAdminAPIKey(cfg)
 }
 
 // generateRandomString generates a cryptographically secure random string of specified length
@@ -630,9 +745,10 @@ func generateRandomString(length int) string {
 
 // status shows the system status
 func status(cCtx *cli.Context) error {
-	fmt.Println("NeuraTrade System Status")
-	fmt.Println("=======================")
-	fmt.Println("Version:", version)
+	out := newOutput(cCtx)
+	out.Println("NeuraTrade System Status")
+	out.Println("=======================")
+	out.Println("Version:", version)
 
 	baseURL := getBaseURL()
 	apiKey := getAPIKey()
@@ -642,52 +758,57 @@ func status(cCtx *cli.Context) error {
 	// Try to get real status from /health endpoint
 	respBody, err := client.makeRequest("GET", "/health", nil)
 	if err != nil {
-		fmt.Printf("⚠️  Warning: Could not reach API at %s\n", baseURL)
-		fmt.Println("   Ensure the backend is running: neuratrade gateway start")
-		fmt.Println("\nSimulated status (backend may not be running):")
-		fmt.Println("  Status: Unknown (API unreachable)")
-		return nil
+		out.Printf("⚠️  Warning: Could not reach API at %s\n", baseURL)
+		out.Println("   Ensure the backend is running: neuratrade gateway start")
+		out.Println("\nSimulated status (backend may not be running):")
+		out.Println("  Status: Unknown (API unreachable)")
+		return out.Render(map[string]interface{}{"version": version, "status": "unreachable", "api_base_url": baseURL}, nil)
 	}
 
 	var healthResp map[string]interface{}
 	if err := json.Unmarshal(respBody, &healthResp); err != nil {
-		fmt.Printf("⚠️  Warning: Could not parse API response: %v\n", err)
+		out.Printf("⚠️  Warning: Could not parse API response: %v\n", err)
 		return nil
 	}
-
-	// Display real status from API
-	status := "Unknown"
-	if v, ok := healthResp["status"].(string); ok {
-		status = v
+	if healthResp == nil {
+		healthResp = make(map[string]interface{})
 	}
+	healthResp["version"] = version
 
-	fmt.Printf("  Status: %s\n", status)
-	fmt.Println("\nConnected Services:")
-
-	// Show service status if available
-	if services, ok := healthResp["services"].(map[string]interface{}); ok {
-		for name, status := range services {
-			fmt.Printf("  - %s: %v\n", name, status)
+	return out.Render(healthResp, func() {
+		// Display real status from API
+		status := "Unknown"
+		if v, ok := healthResp["status"].(string); ok {
+			status = v
 		}
-	} else {
-		fmt.Println("  - Backend API: Connected ✓")
-		fmt.Println("  - Database: Connected ✓")
-		fmt.Println("  - Redis: Connected ✓")
-		fmt.Println("  - Telegram: Ready ✓")
-		fmt.Println("  - AI Providers: Configured ✓")
-	}
 
-	if ts, ok := healthResp["timestamp"].(string); ok {
-		fmt.Printf("\nChecked at: %s\n", ts)
-	}
+		out.Printf("  Status: %s\n", status)
+		out.Println("\nConnected Services:")
 
-	return nil
+		// Show service status if available
+		if services, ok := healthResp["services"].(map[string]interface{}); ok {
+			for name, status := range services {
+				out.Printf("  - %s: %v\n", name, status)
+			}
+		} else {
+			out.Println("  - Backend API: Connected ✓")
+			out.Println("  - Database: Connected ✓")
+			out.Println("  - Redis: Connected ✓")
+			out.Println("  - Telegram: Ready ✓")
+			out.Println("  - AI Providers: Configured ✓")
+		}
+
+		if ts, ok := healthResp["timestamp"].(string); ok {
+			out.Printf("\nChecked at: %s\n", ts)
+		}
+	})
 }
 
 // health checks system health
 func health(cCtx *cli.Context) error {
-	fmt.Println("Health Check Results")
-	fmt.Println("===================")
+	out := newOutput(cCtx)
+	out.Println("Health Check Results")
+	out.Println("===================")
 
 	baseURL := getBaseURL()
 	apiKey := getAPIKey()
@@ -697,56 +818,57 @@ func health(cCtx *cli.Context) error {
 	// Get real health status from API
 	respBody, err := client.makeRequest("GET", "/health", nil)
 	if err != nil {
-		fmt.Printf("❌ Error: Could not reach API at %s\n", baseURL)
-		fmt.Println("   Ensure the backend is running: neuratrade gateway start")
+		out.Printf("❌ Error: Could not reach API at %s\n", baseURL)
+		out.Println("   Ensure the backend is running: neuratrade gateway start")
 		return cli.Exit("Backend API unreachable", 1)
 	}
 
 	var healthResp map[string]interface{}
 	if err := json.Unmarshal(respBody, &healthResp); err != nil {
-		fmt.Printf("❌ Error: Could not parse API response: %v\n", err)
+		out.Printf("❌ Error: Could not parse API response: %v\n", err)
 		return cli.Exit("Invalid API response", 1)
 	}
 
-	// Display real health status
-	status := "Unknown"
-	if v, ok := healthResp["status"].(string); ok {
-		status = v
-	}
+	return out.Render(healthResp, func() {
+		// Display real health status
+		status := "Unknown"
+		if v, ok := healthResp["status"].(string); ok {
+			status = v
+		}
 
-	statusIcon := "✓"
-	if status != "healthy" && status != "ok" {
-		statusIcon = "⚠️"
-	}
+		statusIcon := "✓"
+		if status != "healthy" && status != "ok" {
+			statusIcon = "⚠️"
+		}
 
-	fmt.Printf("%s Backend API: %s\n", statusIcon, status)
+		out.Printf("%s Backend API: %s\n", statusIcon, status)
 
-	// Show detailed service health if available
-	if services, ok := healthResp["services"].(map[string]interface{}); ok {
-		fmt.Println("\nService Health:")
-		for name, svcStatus := range services {
-			icon := "✓"
-			if svcStatus != "healthy" && svcStatus != "ok" {
-				icon = "⚠️"
+		// Show detailed service health if available
+		if services, ok := healthResp["services"].(map[string]interface{}); ok {
+			out.Println("\nService Health:")
+			for name, svcStatus := range services {
+				icon := "✓"
+				if svcStatus != "healthy" && svcStatus != "ok" {
+					icon = "⚠️"
+				}
+				out.Printf("  %s %s: %v\n", icon, name, svcStatus)
 			}
-			fmt.Printf("  %s %s: %v\n", icon, name, svcStatus)
+		} else {
+			out.Println("✓ Database Connection: Healthy")
+			out.Println("✓ Redis Connection: Healthy")
+			out.Println("✓ Exchange Connections: Healthy")
+			out.Println("✓ AI Provider Connectivity: Healthy")
 		}
-	} else {
-		fmt.Println("✓ Database Connection: Healthy")
-		fmt.Println("✓ Redis Connection: Healthy")
-		fmt.Println("✓ Exchange Connections: Healthy")
-		fmt.Println("✓ AI Provider Connectivity: Healthy")
-	}
-
-	if ts, ok := healthResp["timestamp"].(string); ok {
-		fmt.Printf("\nChecked at: %s\n", ts)
-	}
 
-	return nil
+		if ts, ok := healthResp["timestamp"].(string); ok {
+			out.Printf("\nChecked at: %s\n", ts)
+		}
+	})
 }
 
 // buildPrompt builds a prompt from skill.md and context
 func buildPrompt(cCtx *cli.Context) error {
+	out := newOutput(cCtx)
 	skill := cCtx.String("skill")
 	context := cCtx.String("context")
 
@@ -754,24 +876,25 @@ func buildPrompt(cCtx *cli.Context) error {
 		return cli.Exit("Error: skill name is required", 1)
 	}
 
-	fmt.Printf("Building prompt for skill: %s\n", skill)
+	out.Printf("Building prompt for skill: %s\n", skill)
 	if context != "" {
-		fmt.Printf("With context: %s\n", context)
+		out.Printf("With context: %s\n", context)
 	}
 
 	// In a real implementation, this would read the skill.md file
 	// and build a prompt based on the skill definition and provided context
 	prompt := fmt.Sprintf("You are an expert trading assistant. Skill: %s. Context: %s", skill, context)
 
-	fmt.Printf("\nBuilt Prompt:\n%s\n", prompt)
-
-	return nil
+	return out.Render(map[string]string{"skill": skill, "context": context, "prompt": prompt}, func() {
+		out.Printf("\nBuilt Prompt:\n%s\n", prompt)
+	})
 }
 
 // bindOperator binds an operator profile to Telegram
 func bindOperator(cCtx *cli.Context) error {
+	out := newOutput(cCtx)
 	authCode := cCtx.String("auth-code")
-	chatID := cCtx.String("chat-id")
+	chatID := chatIDValue(cCtx)
 
 	if authCode == "" {
 		return cli.Exit("Error: auth-code is required", 1)
@@ -793,50 +916,36 @@ func bindOperator(cCtx *cli.Context) error {
 	response, err := client.VerifyBindingCode(request)
 	if err != nil {
 		// If API call fails, inform the user
-		fmt.Printf("Warning: Could not reach API: %v\n", err)
-		fmt.Println("This is a simulated binding operation for demonstration purposes...")
-		fmt.Printf("Would bind operator with auth code: %s\n", authCode)
+		out.Printf("Warning: Could not reach API: %v\n", err)
+		out.Println("This is a simulated binding operation for demonstration purposes...")
+		out.Printf("Would bind operator with auth code: %s\n", authCode)
 		if chatID != "" {
-			fmt.Printf("To chat ID: %s\n", chatID)
+			out.Printf("To chat ID: %s\n", chatID)
 		}
 		return nil
 	}
 
 	if response.Success {
-		fmt.Printf("✅ Operator binding successful!\n")
-		fmt.Println(response.Message)
+		out.Printf("✅ Operator binding successful!\n")
+		out.Println(response.Message)
 		if chatID != "" {
 			if err := persistChatIDToConfig(chatID); err != nil {
-				fmt.Printf("⚠️  Warning: failed to persist chat ID to config: %v\n", err)
+				out.Printf("⚠️  Warning: failed to persist chat ID to config: %v\n", err)
 			} else {
-				fmt.Printf("Saved chat ID to %s\n", path.Join(defaultNeuraTradeHome(), "config.json"))
+				out.Printf("Saved chat ID to %s\n", path.Join(defaultNeuraTradeHome(), "config.json"))
 			}
 		}
 	} else {
-		fmt.Printf("❌ Operator binding failed: %s\n", response.Error)
+		out.Printf("❌ Operator binding failed: %s\n", response.Error)
 	}
 
-	return nil
-}
-
-// BeginAutonomousRequest represents the request to start autonomous mode
-type BeginAutonomousRequest struct {
-	ChatID string `json:"chat_id"`
-}
-
-// BeginAutonomousResponse represents the response from starting autonomous mode
-type BeginAutonomousResponse struct {
-	Ok              bool     `json:"ok"`
-	Status          string   `json:"status,omitempty"`
-	Mode            string   `json:"mode,omitempty"`
-	Message         string   `json:"message,omitempty"`
-	ReadinessPassed bool     `json:"readiness_passed"`
-	FailedChecks    []string `json:"failed_checks,omitempty"`
+	return out.Render(response, nil)
 }
 
 // beginAutonomous starts autonomous trading mode
 func beginAutonomous(cCtx *cli.Context) error {
-	chatID := cCtx.String("chat-id")
+	out := newOutput(cCtx)
+	chatID := chatIDValue(cCtx)
 
 	if chatID == "" {
 		return cli.Exit("Error: chat-id is required", 1)
@@ -847,58 +956,44 @@ func beginAutonomous(cCtx *cli.Context) error {
 
 	client := NewAPIClient(baseURL, apiKey)
 
-	request := BeginAutonomousRequest{
-		ChatID: chatID,
-	}
-
-	respBody, err := client.makeRequest("POST", "/api/v1/telegram/internal/autonomous/begin", request)
+	response, err := client.BeginAutonomous(&AutonomousStateRequest{ChatID: chatID, Profile: cCtx.String("operating-profile")})
 	if err != nil {
-		fmt.Printf("Warning: Could not reach API: %v\n", err)
-		fmt.Println("This is a simulated autonomous mode start for demonstration purposes...")
-		fmt.Printf("Would start autonomous mode for chat ID: %s\n", chatID)
+		out.Printf("Warning: Could not reach API: %v\n", err)
+		out.Println("This is a simulated autonomous mode start for demonstration purposes...")
+		out.Printf("Would start autonomous mode for chat ID: %s\n", chatID)
 		return nil
 	}
 
-	var response BeginAutonomousResponse
-	if err := json.Unmarshal(respBody, &response); err != nil {
-		return fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	if response.Ok {
-		fmt.Printf("✅ Autonomous mode started successfully!\n")
-		fmt.Printf("Status: %s\n", response.Status)
-		fmt.Printf("Mode: %s\n", response.Mode)
-		fmt.Println(response.Message)
+	if response.OK {
+		out.Printf("✅ Autonomous mode started successfully!\n")
+		out.Printf("Status: %s\n", response.Status)
+		out.Printf("Mode: %s\n", response.Mode)
+		if response.Profile != "" {
+			out.Printf("Operating profile: %s\n", response.Profile)
+		}
+		out.Println(response.Message)
 		if err := persistChatIDToConfig(chatID); err != nil {
-			fmt.Printf("⚠️  Warning: failed to persist chat ID to config: %v\n", err)
+			out.Printf("⚠️  Warning: failed to persist chat ID to config: %v\n", err)
 		}
 	} else {
-		fmt.Printf("❌ Autonomous mode start failed\n")
-		fmt.Printf("Status: %s\n", response.Status)
+		out.Printf("❌ Autonomous mode start failed\n")
+		out.Printf("Status: %s\n", response.Status)
 		if len(response.FailedChecks) > 0 {
-			fmt.Printf("Failed checks: %v\n", response.FailedChecks)
+			out.Printf("Failed checks: %v\n", response.FailedChecks)
 		}
-		fmt.Println(response.Message)
+		out.Println(response.Message)
+	}
+	if response.Readiness != nil {
+		out.Println(response.Readiness.Explanation)
 	}
 
-	return nil
-}
-
-// PauseAutonomousRequest represents the request to pause autonomous mode
-type PauseAutonomousRequest struct {
-	ChatID string `json:"chat_id"`
-}
-
-// PauseAutonomousResponse represents the response from pausing autonomous mode
-type PauseAutonomousResponse struct {
-	Ok      bool   `json:"ok"`
-	Status  string `json:"status,omitempty"`
-	Message string `json:"message,omitempty"`
+	return out.Render(response, nil)
 }
 
 // pauseAutonomous pauses autonomous trading mode
 func pauseAutonomous(cCtx *cli.Context) error {
-	chatID := cCtx.String("chat-id")
+	out := newOutput(cCtx)
+	chatID := chatIDValue(cCtx)
 
 	if chatID == "" {
 		return cli.Exit("Error: chat-id is required", 1)
@@ -909,34 +1004,25 @@ func pauseAutonomous(cCtx *cli.Context) error {
 
 	client := NewAPIClient(baseURL, apiKey)
 
-	request := PauseAutonomousRequest{
-		ChatID: chatID,
-	}
-
-	respBody, err := client.makeRequest("POST", "/api/v1/telegram/internal/autonomous/pause", request)
+	response, err := client.PauseAutonomous(&AutonomousStateRequest{ChatID: chatID})
 	if err != nil {
-		fmt.Printf("Warning: Could not reach API: %v\n", err)
-		fmt.Println("This is a simulated autonomous mode pause for demonstration purposes...")
-		fmt.Printf("Would pause autonomous mode for chat ID: %s\n", chatID)
+		out.Printf("Warning: Could not reach API: %v\n", err)
+		out.Println("This is a simulated autonomous mode pause for demonstration purposes...")
+		out.Printf("Would pause autonomous mode for chat ID: %s\n", chatID)
 		return nil
 	}
 
-	var response PauseAutonomousResponse
-	if err := json.Unmarshal(respBody, &response); err != nil {
-		return fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	if response.Ok {
-		fmt.Printf("✅ Autonomous mode paused successfully!\n")
-		fmt.Printf("Status: %s\n", response.Status)
-		fmt.Println(response.Message)
+	if response.OK {
+		out.Printf("✅ Autonomous mode paused successfully!\n")
+		out.Printf("Status: %s\n", response.Status)
+		out.Println(response.Message)
 	} else {
-		fmt.Printf("❌ Autonomous mode pause failed\n")
-		fmt.Printf("Status: %s\n", response.Status)
-		fmt.Println(response.Message)
+		out.Printf("❌ Autonomous mode pause failed\n")
+		out.Printf("Status: %s\n", response.Status)
+		out.Println(response.Message)
 	}
 
-	return nil
+	return out.Render(response, nil)
 }
 
 // GetAutonomousStatusRequest represents the request to get autonomous status
@@ -956,7 +1042,8 @@ type GetAutonomousStatusResponse struct {
 
 // getAutonomousStatus gets the autonomous trading status
 func getAutonomousStatus(cCtx *cli.Context) error {
-	chatID := cCtx.String("chat-id")
+	out := newOutput(cCtx)
+	chatID := chatIDValue(cCtx)
 
 	if chatID == "" {
 		return cli.Exit("Error: chat-id is required", 1)
@@ -970,9 +1057,9 @@ func getAutonomousStatus(cCtx *cli.Context) error {
 	// For status, we'll use the doctor endpoint which gives us the status
 	respBody, err := client.makeRequest("GET", fmt.Sprintf("/api/v1/telegram/internal/doctor?chat_id=%s", chatID), nil)
 	if err != nil {
-		fmt.Printf("Warning: Could not reach API: %v\n", err)
-		fmt.Println("This is a simulated status check for demonstration purposes...")
-		fmt.Printf("Would get status for chat ID: %s\n", chatID)
+		out.Printf("Warning: Could not reach API: %v\n", err)
+		out.Println("This is a simulated status check for demonstration purposes...")
+		out.Printf("Would get status for chat ID: %s\n", chatID)
 		return nil
 	}
 
@@ -981,49 +1068,49 @@ func getAutonomousStatus(cCtx *cli.Context) error {
 		return fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	fmt.Printf("📊 Autonomous Mode Status for Chat ID: %s\n", chatID)
-
-	overallStatus := "unknown"
-	if v, ok := response["overall_status"].(string); ok {
-		overallStatus = v
-	}
-	summary := "No summary available"
-	if v, ok := response["summary"].(string); ok {
-		summary = v
-	}
-	checkedAt := "unknown"
-	if v, ok := response["checked_at"].(string); ok {
-		checkedAt = v
-	}
+	return out.Render(response, func() {
+		out.Printf("📊 Autonomous Mode Status for Chat ID: %s\n", chatID)
 
-	fmt.Printf("Overall Status: %s\n", overallStatus)
-	fmt.Printf("Summary: %s\n", summary)
-	fmt.Printf("Checked At: %s\n", checkedAt)
+		overallStatus := "unknown"
+		if v, ok := response["overall_status"].(string); ok {
+			overallStatus = v
+		}
+		summary := "No summary available"
+		if v, ok := response["summary"].(string); ok {
+			summary = v
+		}
+		checkedAt := "unknown"
+		if v, ok := response["checked_at"].(string); ok {
+			checkedAt = v
+		}
 
-	if checks, ok := response["checks"].([]interface{}); ok && len(checks) > 0 {
-		fmt.Println("\nDetailed Checks:")
-		for _, check := range checks {
-			if checkMap, ok := check.(map[string]interface{}); ok {
-				name := "unknown"
-				if v, ok := checkMap["name"].(string); ok {
-					name = v
-				}
-				status := "unknown"
-				if v, ok := checkMap["status"].(string); ok {
-					status = v
-				}
-				message := ""
-				if v, ok := checkMap["message"].(string); ok {
-					message = v
+		out.Printf("Overall Status: %s\n", overallStatus)
+		out.Printf("Summary: %s\n", summary)
+		out.Printf("Checked At: %s\n", checkedAt)
+
+		if checks, ok := response["checks"].([]interface{}); ok && len(checks) > 0 {
+			out.Println("\nDetailed Checks:")
+			for _, check := range checks {
+				if checkMap, ok := check.(map[string]interface{}); ok {
+					name := "unknown"
+					if v, ok := checkMap["name"].(string); ok {
+						name = v
+					}
+					status := "unknown"
+					if v, ok := checkMap["status"].(string); ok {
+						status = v
+					}
+					message := ""
+					if v, ok := checkMap["message"].(string); ok {
+						message = v
+					}
+					out.Printf("  • %s: %s - %s\n", name, status, message)
 				}
-				fmt.Printf("  • %s: %s - %s\n", name, status, message)
 			}
+		} else {
+			out.Println("\nNo detailed checks available")
 		}
-	} else {
-		fmt.Println("\nNo detailed checks available")
-	}
-
-	return nil
+	})
 }
 
 // GetPortfolioResponse represents the response for portfolio data
@@ -1047,7 +1134,8 @@ type PortfolioPosition struct {
 
 // getPortfolio gets the portfolio status
 func getPortfolio(cCtx *cli.Context) error {
-	chatID := cCtx.String("chat-id")
+	out := newOutput(cCtx)
+	chatID := chatIDValue(cCtx)
 
 	if chatID == "" {
 		return cli.Exit("Error: chat-id is required", 1)
@@ -1060,9 +1148,9 @@ func getPortfolio(cCtx *cli.Context) error {
 
 	respBody, err := client.makeRequest("GET", fmt.Sprintf("/api/v1/telegram/internal/portfolio?chat_id=%s", chatID), nil)
 	if err != nil {
-		fmt.Printf("Warning: Could not reach API: %v\n", err)
-		fmt.Println("This is a simulated portfolio check for demonstration purposes...")
-		fmt.Printf("Would get portfolio for chat ID: %s\n", chatID)
+		out.Printf("Warning: Could not reach API: %v\n", err)
+		out.Println("This is a simulated portfolio check for demonstration purposes...")
+		out.Printf("Would get portfolio for chat ID: %s\n", chatID)
 		return nil
 	}
 
@@ -1071,23 +1159,23 @@ func getPortfolio(cCtx *cli.Context) error {
 		return fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	fmt.Printf("💼 Portfolio Status for Chat ID: %s\n", chatID)
-	fmt.Printf("Total Equity: %s\n", response.TotalEquity)
-	fmt.Printf("Available Balance: %s\n", response.AvailableBalance)
-	fmt.Printf("Exposure: %s\n", response.Exposure)
-	fmt.Printf("Last Updated: %s\n", response.UpdatedAt)
+	return out.Render(response, func() {
+		out.Printf("💼 Portfolio Status for Chat ID: %s\n", chatID)
+		out.Printf("Total Equity: %s\n", response.TotalEquity)
+		out.Printf("Available Balance: %s\n", response.AvailableBalance)
+		out.Printf("Exposure: %s\n", response.Exposure)
+		out.Printf("Last Updated: %s\n", response.UpdatedAt)
 
-	if len(response.Positions) > 0 {
-		fmt.Println("\nPositions:")
-		for _, pos := range response.Positions {
-			fmt.Printf("  • %s: %s %s @ %s (Mark: %s, PnL: %s)\n",
-				pos.Symbol, pos.Side, pos.Size, pos.EntryPrice, pos.MarkPrice, pos.UnrealizedPnL)
+		if len(response.Positions) > 0 {
+			out.Println("\nPositions:")
+			for _, pos := range response.Positions {
+				out.Printf("  • %s: %s %s @ %s (Mark: %s, PnL: %s)\n",
+					pos.Symbol, pos.Side, pos.Size, pos.EntryPrice, pos.MarkPrice, pos.UnrealizedPnL)
+			}
+		} else {
+			out.Println("\nNo active positions")
 		}
-	} else {
-		fmt.Println("\nNo active positions")
-	}
-
-	return nil
+	})
 }
 
 // QuestProgress represents quest progress information
@@ -1109,7 +1197,8 @@ type GetQuestsResponse struct {
 
 // getQuests gets the quest progress
 func getQuests(cCtx *cli.Context) error {
-	chatID := cCtx.String("chat-id")
+	out := newOutput(cCtx)
+	chatID := chatIDValue(cCtx)
 
 	if chatID == "" {
 		return cli.Exit("Error: chat-id is required", 1)
@@ -1122,9 +1211,9 @@ func getQuests(cCtx *cli.Context) error {
 
 	respBody, err := client.makeRequest("GET", fmt.Sprintf("/api/v1/telegram/internal/quests?chat_id=%s", chatID), nil)
 	if err != nil {
-		fmt.Printf("Warning: Could not reach API: %v\n", err)
-		fmt.Println("This is a simulated quests check for demonstration purposes...")
-		fmt.Printf("Would get quests for chat ID: %s\n", chatID)
+		out.Printf("Warning: Could not reach API: %v\n", err)
+		out.Println("This is a simulated quests check for demonstration purposes...")
+		out.Printf("Would get quests for chat ID: %s\n", chatID)
 		return nil
 	}
 
@@ -1133,55 +1222,25 @@ func getQuests(cCtx *cli.Context) error {
 		return fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	fmt.Printf("🎯 Quest Progress for Chat ID: %s\n", chatID)
-	fmt.Printf("Last Updated: %s\n", response.UpdatedAt)
+	return out.Render(response, func() {
+		out.Printf("🎯 Quest Progress for Chat ID: %s\n", chatID)
+		out.Printf("Last Updated: %s\n", response.UpdatedAt)
 
-	if len(response.Quests) > 0 {
-		for _, quest := range response.Quests {
-			progressPercent := 0
-			if quest.MaxProgress > 0 {
-				progressPercent = (quest.Progress * 100) / quest.MaxProgress
-			}
-			fmt.Printf("  • %s: %s [%d/%d] (%d%%)\n", quest.Title, quest.Status, quest.Progress, quest.MaxProgress, progressPercent)
-			if quest.Description != "" {
-				fmt.Printf("    %s\n", quest.Description)
+		if len(response.Quests) > 0 {
+			for _, quest := range response.Quests {
+				progressPercent := 0
+				if quest.MaxProgress > 0 {
+					progressPercent = (quest.Progress * 100) / quest.MaxProgress
+				}
+				out.Printf("  • %s: %s [%d/%d] (%d%%)\n", quest.Title, quest.Status, quest.Progress, quest.MaxProgress, progressPercent)
+				if quest.Description != "" {
+					out.Printf("    %s\n", quest.Description)
+				}
 			}
+		} else {
+			out.Println("No active quests")
 		}
-	} else {
-		fmt.Println("No active quests")
-	}
-
-	return nil
-}
-
-// AIModel represents an AI model
-type AIModel struct {
-	ID             string `json:"id"`
-	DisplayName    string `json:"display_name"`
-	Provider       string `json:"provider"`
-	Cost           string `json:"cost"`
-	SupportsTools  bool   `json:"supports_tools"`
-	SupportsVision bool   `json:"supports_vision"`
-}
-
-// AIModelsResponse represents the response from the AI models endpoint
-type AIModelsResponse struct {
-	Models []AIModel `json:"models"`
-}
-
-// GetAIModels retrieves available AI models from the API
-func (c *APIClient) GetAIModels() (*AIModelsResponse, error) {
-	respBody, err := c.makeRequest("GET", "/api/v1/ai/models", nil)
-	if err != nil {
-		return nil, err
-	}
-
-	var response AIModelsResponse
-	if err := json.Unmarshal(respBody, &response); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	return &response, nil
+	})
 }
 
 // AIProvider represents an AI provider
@@ -1241,74 +1300,53 @@ func (c *APIClient) GetPortfolio() (*PortfolioResponse, error) {
 	return &response, nil
 }
 
-// BalanceResponse represents the balance response
-type BalanceResponse struct {
-	TotalBalance string `json:"total_balance"`
-	Available    string `json:"available"`
-	Locked       string `json:"locked"`
-	Currency     string `json:"currency"`
-}
-
-// GetBalance retrieves account balance from the API
-func (c *APIClient) GetBalance() (*BalanceResponse, error) {
-	respBody, err := c.makeRequest("GET", "/api/v1/telegram/internal/wallets", nil)
-	if err != nil {
-		return nil, err
-	}
-
-	var response BalanceResponse
-	if err := json.Unmarshal(respBody, &response); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	return &response, nil
-}
-
 // listAIModels lists available AI models
 func listAIModels(cCtx *cli.Context) error {
-	fmt.Println("Available AI Models")
-	fmt.Println("==================")
+	out := newOutput(cCtx)
+	out.Println("Available AI Models")
+	out.Println("==================")
 
 	baseURL := getBaseURL()
 	apiKey := getAPIKey()
 
 	client := NewAPIClient(baseURL, apiKey)
 
-	response, err := client.GetAIModels()
+	response, err := client.GetAIModels("")
 	if err != nil {
 		// API call failed - show error and exit
-		fmt.Printf("Error: Could not reach API: %v\n", err)
-		fmt.Println("\nMake sure the NeuraTrade backend is running:")
-		fmt.Println("  neuratrade gateway start")
-		fmt.Println("\nOr check your configuration:")
-		fmt.Println("  neuratrade config status")
+		out.Printf("Error: Could not reach API: %v\n", err)
+		out.Println("\nMake sure the NeuraTrade backend is running:")
+		out.Println("  neuratrade gateway start")
+		out.Println("\nOr check your configuration:")
+		out.Println("  neuratrade config status")
 		return err
 	}
 
-	if len(response.Models) == 0 {
-		fmt.Println("No AI models available.")
-		return nil
-	}
-
-	for _, model := range response.Models {
-		caps := []string{}
-		if model.SupportsTools {
-			caps = append(caps, "tools")
-		}
-		if model.SupportsVision {
-			caps = append(caps, "vision")
+	return out.Render(response.Models, func() {
+		if len(response.Models) == 0 {
+			out.Println("No AI models available.")
+			return
 		}
 
-		fmt.Printf("- %s (%s): %s\n", model.ID, model.Provider, strings.Join(caps, ", "))
-	}
+		for _, model := range response.Models {
+			caps := []string{}
+			if model.SupportsTools {
+				caps = append(caps, "tools")
+			}
+			if model.SupportsVision {
+				caps = append(caps, "vision")
+			}
 
-	return nil
+			out.Printf("- %s (%s): %s\n", model.ModelID, model.Provider, strings.Join(caps, ", "))
+		}
+	})
 }
 
 // listAIProviders lists available AI providers
 func listAIProviders(cCtx *cli.Context) error {
-	fmt.Println("Available AI Providers")
-	fmt.Println("=====================")
+	out := newOutput(cCtx)
+	out.Println("Available AI Providers")
+	out.Println("=====================")
 
 	baseURL := getBaseURL()
 	apiKey := getAPIKey()
@@ -1317,34 +1355,35 @@ func listAIProviders(cCtx *cli.Context) error {
 
 	response, err := client.GetAIProviders()
 	if err != nil {
-		fmt.Printf("Error: Could not reach API: %v\n", err)
-		fmt.Println("\nMake sure the NeuraTrade backend is running:")
-		fmt.Println("  neuratrade gateway start")
+		out.Printf("Error: Could not reach API: %v\n", err)
+		out.Println("\nMake sure the NeuraTrade backend is running:")
+		out.Println("  neuratrade gateway start")
 		return err
 	}
 
-	if len(response.Providers) == 0 {
-		fmt.Println("No AI providers available.")
-		return nil
-	}
-
-	for _, provider := range response.Providers {
-		status := "active"
-		if !provider.IsActive {
-			status = "inactive"
+	return out.Render(response.Providers, func() {
+		if len(response.Providers) == 0 {
+			out.Println("No AI providers available.")
+			return
 		}
-		fmt.Printf("- %s (%s) [%s]\n", provider.Name, provider.ID, status)
-	}
 
-	return nil
+		for _, provider := range response.Providers {
+			status := "active"
+			if !provider.IsActive {
+				status = "inactive"
+			}
+			out.Printf("- %s (%s) [%s]\n", provider.Name, provider.ID, status)
+		}
+	})
 }
 
 // viewPortfolio shows portfolio status
 func viewPortfolio(cCtx *cli.Context) error {
-	fmt.Println("Portfolio Overview")
-	fmt.Println("==================")
+	out := newOutput(cCtx)
+	out.Println("Portfolio Overview")
+	out.Println("==================")
 
-	chatID := cCtx.String("chat-id")
+	chatID := chatIDValue(cCtx)
 	if chatID == "" {
 		return cli.Exit("Error: chat-id is required", 1)
 	}
@@ -1356,9 +1395,9 @@ func viewPortfolio(cCtx *cli.Context) error {
 
 	respBody, err := client.makeRequest("GET", fmt.Sprintf("/api/v1/telegram/internal/portfolio?chat_id=%s", chatID), nil)
 	if err != nil {
-		fmt.Printf("Error: Could not reach API: %v\n", err)
-		fmt.Println("\nMake sure the NeuraTrade backend is running:")
-		fmt.Println("  neuratrade gateway start")
+		out.Printf("Error: Could not reach API: %v\n", err)
+		out.Println("\nMake sure the NeuraTrade backend is running:")
+		out.Println("  neuratrade gateway start")
 		return err
 	}
 
@@ -1367,30 +1406,54 @@ func viewPortfolio(cCtx *cli.Context) error {
 		return fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	prettyPrint(response)
-	return nil
+	return out.Render(response, func() { prettyPrint(response) })
 }
 
-// checkBalance shows account balance
+// checkBalance shows the account equity from the same valuation the
+// portfolio, reports and risk limits use
 func checkBalance(cCtx *cli.Context) error {
-	fmt.Println("Account Balance")
-	fmt.Println("===============")
+	out := newOutput(cCtx)
+	out.Println("Account Balance")
+	out.Println("===============")
 
 	baseURL := getBaseURL()
 	apiKey := getAPIKey()
 
 	client := NewAPIClient(baseURL, apiKey)
 
-	response, err := client.GetBalance()
+	response, err := client.GetEquity(chatIDValue(cCtx))
 	if err != nil {
-		fmt.Printf("Error: Could not reach API: %v\n", err)
-		fmt.Println("\nMake sure the NeuraTrade backend is running:")
-		fmt.Println("  neuratrade gateway start")
+		out.Printf("Error: Could not reach API: %v\n", err)
+		out.Println("\nMake sure the NeuraTrade backend is running:")
+		out.Println("  neuratrade gateway start")
 		return err
 	}
 
-	prettyPrint(response)
-	return nil
+	equity := response.Data
+	return out.Render(equity, func() {
+		fmt.Printf("Total equity:      %s %s\n", equity.TotalEquity, equity.Currency)
+		fmt.Printf("Available:         %s %s\n", equity.AvailableBalance, equity.Currency)
+		fmt.Printf("Exchange balances: %s %s\n", equity.ExchangeBalance, equity.Currency)
+		fmt.Printf("Unrealized PnL:    %s %s\n", equity.UnrealizedPnl, equity.Currency)
+		fmt.Printf("External wallets:  %s %s\n", equity.ExternalBalance, equity.Currency)
+		if equity.Reserve != "" && equity.Reserve != "0" {
+			fmt.Printf("Reserve:           %s %s (not traded)\n", equity.Reserve, equity.Currency)
+			fmt.Printf("Trading equity:    %s %s\n", equity.TradingEquity, equity.Currency)
+		}
+		for _, exchange := range equity.Exchanges {
+			if exchange.Error != "" {
+				fmt.Printf("\n%s: unavailable (%s)\n", exchange.Exchange, exchange.Error)
+				continue
+			}
+			fmt.Printf("\n%s: %s %s\n", exchange.Exchange, exchange.Total, equity.Currency)
+			for _, asset := range exchange.Assets {
+				fmt.Printf("  %-8s %s = %s\n", asset.Asset, asset.Total, asset.Value)
+			}
+		}
+		for _, warning := range equity.Warnings {
+			fmt.Printf("Warning: %s\n", warning)
+		}
+	})
 }
 
 // ExchangeConfig represents an exchange configuration
@@ -1434,8 +1497,9 @@ type ExchangeRemoveResponse struct {
 
 // listExchanges lists all configured exchanges
 func listExchanges(cCtx *cli.Context) error {
-	fmt.Println("Configured Exchanges")
-	fmt.Println("====================")
+	out := newOutput(cCtx)
+	out.Println("Configured Exchanges")
+	out.Println("====================")
 
 	baseURL := getBaseURL()
 	apiKey := getAPIKey()
@@ -1445,8 +1509,8 @@ func listExchanges(cCtx *cli.Context) error {
 	// Get exchanges from backend API
 	respBody, err := client.makeRequest("GET", "/api/v1/exchanges", nil)
 	if err != nil {
-		fmt.Printf("⚠️  Warning: Could not reach API: %v\n", err)
-		fmt.Println("\nFalling back to local configuration...")
+		out.Printf("⚠️  Warning: Could not reach API: %v\n", err)
+		out.Println("\nFalling back to local configuration...")
 
 		// Fallback: read from config file
 		homeDir, err := os.UserHomeDir()
@@ -1465,24 +1529,29 @@ func listExchanges(cCtx *cli.Context) error {
 							if apiKeysObj, ok := exchangesObj["api_keys"].(map[string]interface{}); ok {
 								apiKeys = apiKeysObj
 							}
-							fmt.Printf("\nFound %d configured exchanges:\n", len(enabled))
+							local := ExchangesListResponse{}
 							for _, ex := range enabled {
 								if name, ok := ex.(string); ok {
-									hasAuth := apiKeys[name] != nil
-									fmt.Printf("  - %s%s\n", name, map[bool]string{true: " 🔑", false: ""}[hasAuth])
+									local.Exchanges = append(local.Exchanges, ExchangeConfig{Name: name, Enabled: true, HasAuth: apiKeys[name] != nil})
 								}
 							}
-							return nil
+							local.Count = len(local.Exchanges)
+							return out.Render(local, func() {
+								out.Printf("\nFound %d configured exchanges:\n", len(enabled))
+								for _, ex := range local.Exchanges {
+									out.Printf("  - %s%s\n", ex.Name, map[bool]string{true: " 🔑", false: ""}[ex.HasAuth])
+								}
+							})
 						}
 					}
 				}
 			}
 		}
 
-		fmt.Println("No exchanges configured.")
-		fmt.Println("\nAdd an exchange with:")
-		fmt.Println("  neuratrade exchanges add --name binance")
-		fmt.Println("  neuratrade exchanges add --name bybit --api-key YOUR_KEY --secret YOUR_SECRET")
+		out.Println("No exchanges configured.")
+		out.Println("\nAdd an exchange with:")
+		out.Println("  neuratrade exchanges add --name binance")
+		out.Println("  neuratrade exchanges add --name bybit --api-key YOUR_KEY --secret YOUR_SECRET")
 		return nil
 	}
 
@@ -1491,29 +1560,30 @@ func listExchanges(cCtx *cli.Context) error {
 		return fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	fmt.Printf("\nFound %d configured exchanges:\n\n", response.Count)
-	for _, ex := range response.Exchanges {
-		authIcon := "  "
-		if ex.HasAuth {
-			authIcon = "🔑 "
-		}
-		statusIcon := "✓"
-		if !ex.Enabled {
-			statusIcon = "⚠️"
+	return out.Render(response, func() {
+		out.Printf("\nFound %d configured exchanges:\n\n", response.Count)
+		for _, ex := range response.Exchanges {
+			authIcon := "  "
+			if ex.HasAuth {
+				authIcon = "🔑 "
+			}
+			statusIcon := "✓"
+			if !ex.Enabled {
+				statusIcon = "⚠️"
+			}
+			out.Printf("  %s%s %s [%s]\n", authIcon, statusIcon, ex.Name, map[bool]string{true: "active", false: "inactive"}[ex.Enabled])
 		}
-		fmt.Printf("  %s%s %s [%s]\n", authIcon, statusIcon, ex.Name, map[bool]string{true: "active", false: "inactive"}[ex.Enabled])
-	}
 
-	fmt.Println("\nLegend:")
-	fmt.Println("  🔑 = Has API credentials (private data access)")
-	fmt.Println("  ✓  = Active and loading market data")
-	fmt.Println("  ⚠️  = Configured but disabled")
-
-	return nil
+		out.Println("\nLegend:")
+		out.Println("  🔑 = Has API credentials (private data access)")
+		out.Println("  ✓  = Active and loading market data")
+		out.Println("  ⚠️  = Configured but disabled")
+	})
 }
 
 // addExchange adds a new exchange
 func addExchange(cCtx *cli.Context) error {
+	out := newOutput(cCtx)
 	name := cCtx.String("name")
 	apiKey := cCtx.String("api-key")
 	secret := cCtx.String("secret")
@@ -1522,12 +1592,12 @@ func addExchange(cCtx *cli.Context) error {
 		return cli.Exit("Error: exchange name is required", 1)
 	}
 
-	fmt.Printf("Adding exchange: %s\n", name)
+	out.Printf("Adding exchange: %s\n", name)
 	if apiKey != "" {
-		fmt.Println("  - API key: configured ✓")
+		out.Println("  - API key: configured ✓")
 	}
 	if secret != "" {
-		fmt.Println("  - API secret: configured ✓")
+		out.Println("  - API secret: configured ✓")
 	}
 
 	baseURL := getBaseURL()
@@ -1543,8 +1613,8 @@ func addExchange(cCtx *cli.Context) error {
 
 	respBody, err := client.makeRequest("POST", "/api/v1/exchanges", request)
 	if err != nil {
-		fmt.Printf("⚠️  Warning: Could not reach API: %v\n", err)
-		fmt.Println("\nFalling back to local configuration...")
+		out.Printf("⚠️  Warning: Could not reach API: %v\n", err)
+		out.Println("\nFalling back to local configuration...")
 
 		// Fallback: update config file directly
 		homeDir, err := os.UserHomeDir()
@@ -1611,12 +1681,12 @@ func addExchange(cCtx *cli.Context) error {
 			return fmt.Errorf("failed to write config: %w", err)
 		}
 
-		fmt.Printf("\n✅ Exchange %s added successfully!\n", name)
-		fmt.Println("\nNote: Configuration saved locally.")
-		fmt.Println("To apply changes, restart the CCXT service:")
-		fmt.Println("  neuratrade gateway restart")
-		fmt.Println("\nOr reload exchanges:")
-		fmt.Println("  neuratrade exchanges reload")
+		out.Printf("\n✅ Exchange %s added successfully!\n", name)
+		out.Println("\nNote: Configuration saved locally.")
+		out.Println("To apply changes, restart the CCXT service:")
+		out.Println("  neuratrade gateway restart")
+		out.Println("\nOr reload exchanges:")
+		out.Println("  neuratrade exchanges reload")
 		return nil
 	}
 
@@ -1626,25 +1696,26 @@ func addExchange(cCtx *cli.Context) error {
 	}
 
 	if response.Success {
-		fmt.Printf("\n✅ Exchange %s added successfully!\n", response.Name)
-		fmt.Println(response.Message)
-		fmt.Println("\nMarket data will be available shortly.")
+		out.Printf("\n✅ Exchange %s added successfully!\n", response.Name)
+		out.Println(response.Message)
+		out.Println("\nMarket data will be available shortly.")
 	} else {
-		fmt.Printf("❌ Failed to add exchange: %s\n", response.Message)
+		out.Printf("❌ Failed to add exchange: %s\n", response.Message)
 	}
 
-	return nil
+	return out.Render(response, nil)
 }
 
 // removeExchange removes an exchange
 func removeExchange(cCtx *cli.Context) error {
+	out := newOutput(cCtx)
 	name := cCtx.String("name")
 
 	if name == "" {
 		return cli.Exit("Error: exchange name is required", 1)
 	}
 
-	fmt.Printf("Removing exchange: %s\n", name)
+	out.Printf("Removing exchange: %s\n", name)
 
 	baseURL := getBaseURL()
 	apiKeyGlobal := getAPIKey()
@@ -1657,8 +1728,8 @@ func removeExchange(cCtx *cli.Context) error {
 
 	respBody, err := client.makeRequest("DELETE", "/api/v1/exchanges", request)
 	if err != nil {
-		fmt.Printf("⚠️  Warning: Could not reach API: %v\n", err)
-		fmt.Println("\nFalling back to local configuration...")
+		out.Printf("⚠️  Warning: Could not reach API: %v\n", err)
+		out.Println("\nFalling back to local configuration...")
 
 		// Fallback: update config file directly
 		configPath := path.Join(os.Getenv("HOME"), ".neuratrade", "config.json")
@@ -1705,10 +1776,10 @@ func removeExchange(cCtx *cli.Context) error {
 			return fmt.Errorf("failed to write config: %w", err)
 		}
 
-		fmt.Printf("\n✅ Exchange %s removed successfully!\n", name)
-		fmt.Println("\nNote: Configuration saved locally.")
-		fmt.Println("To apply changes, restart the CCXT service:")
-		fmt.Println("  neuratrade gateway restart")
+		out.Printf("\n✅ Exchange %s removed successfully!\n", name)
+		out.Println("\nNote: Configuration saved locally.")
+		out.Println("To apply changes, restart the CCXT service:")
+		out.Println("  neuratrade gateway restart")
 		return nil
 	}
 
@@ -1718,18 +1789,19 @@ func removeExchange(cCtx *cli.Context) error {
 	}
 
 	if response.Success {
-		fmt.Printf("\n✅ Exchange %s removed successfully!\n", name)
-		fmt.Println(response.Message)
+		out.Printf("\n✅ Exchange %s removed successfully!\n", name)
+		out.Println(response.Message)
 	} else {
-		fmt.Printf("❌ Failed to remove exchange: %s\n", response.Message)
+		out.Printf("❌ Failed to remove exchange: %s\n", response.Message)
 	}
 
-	return nil
+	return out.Render(response, nil)
 }
 
 // reloadExchanges reloads the CCXT service configuration
 func reloadExchanges(cCtx *cli.Context) error {
-	fmt.Println("Reloading exchange configuration...")
+	out := newOutput(cCtx)
+	out.Println("Reloading exchange configuration...")
 
 	baseURL := getBaseURL()
 	apiKeyGlobal := getAPIKey()
@@ -1739,10 +1811,10 @@ func reloadExchanges(cCtx *cli.Context) error {
 	// Send reload request to CCXT service
 	respBody, err := client.makeRequest("POST", "/api/v1/exchanges/reload", nil)
 	if err != nil {
-		fmt.Printf("⚠️  Warning: Could not reach API: %v\n", err)
-		fmt.Println("\nManual reload required:")
-		fmt.Println("  1. Stop CCXT service: docker compose restart ccxt-service")
-		fmt.Println("  2. Or restart all services: neuratrade gateway restart")
+		out.Printf("⚠️  Warning: Could not reach API: %v\n", err)
+		out.Println("\nManual reload required:")
+		out.Println("  1. Stop CCXT service: docker compose restart ccxt-service")
+		out.Println("  2. Or restart all services: neuratrade gateway restart")
 		return nil
 	}
 
@@ -1752,13 +1824,13 @@ func reloadExchanges(cCtx *cli.Context) error {
 	}
 
 	if response.Success {
-		fmt.Printf("\n✅ Exchange configuration reloaded!\n")
-		fmt.Println(response.Message)
+		out.Printf("\n✅ Exchange configuration reloaded!\n")
+		out.Println(response.Message)
 	} else {
-		fmt.Printf("❌ Failed to reload configuration: %s\n", response.Message)
+		out.Printf("❌ Failed to reload configuration: %s\n", response.Message)
 	}
 
-	return nil
+	return out.Render(response, nil)
 }
 
 // prettyPrint prints data in a nicely formatted JSON
@@ -1903,38 +1975,39 @@ func configInit(cCtx *cli.Context) error {
 
 // configStatus shows the configuration status
 func configStatus(cCtx *cli.Context) error {
+	out := newOutput(cCtx)
 	configPath := os.ExpandEnv("$HOME/.neuratrade/config.json")
 
-	fmt.Println("NeuraTrade Configuration Status")
-	fmt.Println("================================")
+	out.Println("NeuraTrade Configuration Status")
+	out.Println("================================")
 
 	// Check config file
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		fmt.Println("✗ Configuration file not found")
-		fmt.Printf("  Run: neuratrade config init\n")
+		out.Println("✗ Configuration file not found")
+		out.Printf("  Run: neuratrade config init\n")
 		return nil
 	}
 
-	fmt.Println("✓ Configuration file exists")
+	out.Println("✓ Configuration file exists")
 
 	// Read and parse config
 	content, err := os.ReadFile(configPath)
 	if err != nil {
-		fmt.Printf("✗ Cannot read config: %v\n", err)
+		out.Printf("✗ Cannot read config: %v\n", err)
 		return err
 	}
 
 	if string(content) == "{}" || len(content) < 5 {
-		fmt.Println("✗ Configuration file is empty")
-		fmt.Printf("  Run: neuratrade config init\n")
+		out.Println("✗ Configuration file is empty")
+		out.Printf("  Run: neuratrade config init\n")
 		return nil
 	}
 
-	fmt.Println("✓ Configuration file has content")
+	out.Println("✓ Configuration file has content")
 
 	var config map[string]interface{}
 	if err := json.Unmarshal(content, &config); err != nil {
-		fmt.Printf("✗ Invalid JSON: %v\n", err)
+		out.Printf("✗ Invalid JSON: %v\n", err)
 		return err
 	}
 
@@ -1956,63 +2029,72 @@ func configStatus(cCtx *cli.Context) error {
 		return true
 	}
 
-	fmt.Println("")
-	fmt.Println("Configuration Sections:")
-
-	if checkSection("services.ccxt", true) {
-		fmt.Println("  ✓ CCXT service configured")
-	} else {
-		fmt.Println("  ✗ CCXT service missing")
+	sections := map[string]bool{
+		"ccxt":         checkSection("services.ccxt", true),
+		"telegram":     checkSection("services.telegram", true),
+		"ai":           checkSection("ai", true),
+		"security":     checkSection("security", true),
+		"binance_keys": checkSection("services.ccxt.exchanges.binance.api_key", false),
 	}
 
-	if checkSection("services.telegram", true) {
-		fmt.Println("  ✓ Telegram service configured")
-	} else {
-		fmt.Println("  ✗ Telegram service missing")
-	}
+	return out.Render(map[string]interface{}{"path": configPath, "sections": sections}, func() {
+		out.Println("")
+		out.Println("Configuration Sections:")
 
-	if checkSection("ai", true) {
-		fmt.Println("  ✓ AI service configured")
-	} else {
-		fmt.Println("  ✗ AI service missing")
-	}
+		if sections["ccxt"] {
+			out.Println("  ✓ CCXT service configured")
+		} else {
+			out.Println("  ✗ CCXT service missing")
+		}
 
-	if checkSection("security", true) {
-		fmt.Println("  ✓ Security configured")
-	} else {
-		fmt.Println("  ✗ Security missing")
-	}
+		if sections["telegram"] {
+			out.Println("  ✓ Telegram service configured")
+		} else {
+			out.Println("  ✗ Telegram service missing")
+		}
 
-	// Check Binance keys
-	if checkSection("services.ccxt.exchanges.binance.api_key", false) {
-		fmt.Println("  ✓ Binance API keys configured")
-	} else {
-		fmt.Println("  ⚠ Binance API keys not configured (use /connect_exchange binance)")
-	}
+		if sections["ai"] {
+			out.Println("  ✓ AI service configured")
+		} else {
+			out.Println("  ✗ AI service missing")
+		}
 
-	fmt.Println("")
-	fmt.Println("Use 'neuratrade config show' to view full configuration.")
+		if sections["security"] {
+			out.Println("  ✓ Security configured")
+		} else {
+			out.Println("  ✗ Security missing")
+		}
 
-	return nil
+		// Check Binance keys
+		if sections["binance_keys"] {
+			out.Println("  ✓ Binance API keys configured")
+		} else {
+			out.Println("  ⚠ Binance API keys not configured (use /connect_exchange binance)")
+		}
+
+		out.Println("")
+		out.Println("Use 'neuratrade config show' to view full configuration.")
+	})
 }
 
 // configShow displays the full configuration with masked secrets
 func configShow(cCtx *cli.Context) error {
+	out := newOutput(cCtx)
 	configPath := os.ExpandEnv("$HOME/.neuratrade/config.json")
 
 	content, err := os.ReadFile(configPath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			fmt.Println("Configuration file not found.")
-			fmt.Println("Run: neuratrade config init")
+			out.Println("Configuration file not found.")
+			out.Println("Run: neuratrade config init")
 			return nil
 		}
 		return err
 	}
 
 	if string(content) == "{}" || len(content) < 5 {
-		fmt.Println("Configuration file is empty.")
-		fmt.Println("Run: neuratrade config init")
+		out.Println("Configuration file is empty.")
+		out.Println("Run: neuratrade config init")
 		return nil
 	}
 
@@ -2024,8 +2106,7 @@ func configShow(cCtx *cli.Context) error {
 	// Mask sensitive values
 	maskSecretsInConfig(config)
 
-	prettyPrint(config)
-	return nil
+	return out.Render(config, func() { prettyPrint(config) })
 }
 
 // maskSecretsInConfig masks sensitive values in a config map
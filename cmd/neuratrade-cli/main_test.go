@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -203,9 +204,9 @@ func TestListAIModels(t *testing.T) {
 		assert.Equal(t, "GET", r.Method)
 
 		response := AIModelsResponse{
-			Models: []AIModel{
+			Models: []AIModelInfo{
 				{
-					ID:             "gpt-4-turbo",
+					ModelID:        "gpt-4-turbo",
 					DisplayName:    "GPT-4 Turbo",
 					Provider:       "openai",
 					Cost:           "0.01",
@@ -213,7 +214,7 @@ func TestListAIModels(t *testing.T) {
 					SupportsVision: true,
 				},
 				{
-					ID:             "claude-3-opus",
+					ModelID:        "claude-3-opus",
 					DisplayName:    "Claude 3 Opus",
 					Provider:       "anthropic",
 					Cost:           "0.015",
@@ -409,3 +410,596 @@ func TestMakeRequest(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, strings.Contains(string(resp), "success"))
 }
+
+func TestDoBackupRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/admin/backup", r.URL.Path)
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "secret", r.Header.Get(backupPassphraseHeader))
+		assert.Equal(t, "test-key", r.Header.Get("X-API-Key"))
+		fmt.Fprint(w, `{"format":"neuratrade-backup"}`)
+	}))
+	defer server.Close()
+
+	client := NewAPIClient(server.URL, "test-key")
+	resp, err := client.doBackupRequest("/api/v1/admin/backup", "secret", nil)
+	assert.NoError(t, err)
+	assert.Contains(t, string(resp), "neuratrade-backup")
+}
+
+func TestOutputRender(t *testing.T) {
+	data := map[string]interface{}{"status": "ok", "count": 2}
+
+	tests := []struct {
+		format string
+		want   string
+		table  bool
+	}{
+		{format: outputJSON, want: "{\n  \"count\": 2,\n  \"status\": \"ok\"\n}\n"},
+		{format: outputYAML, want: "count: 2\nstatus: ok\n"},
+		{format: outputTable, want: "human\n", table: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			out := &output{format: tt.format, stdout: &stdout, stderr: &stderr}
+
+			out.Println("Fetching status...")
+			tableCalled := false
+			err := out.Render(data, func() {
+				tableCalled = true
+				out.Println("human")
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.table, tableCalled)
+			if tt.table {
+				assert.Equal(t, "Fetching status...\n"+tt.want, stdout.String())
+				assert.Empty(t, stderr.String())
+			} else {
+				assert.Equal(t, tt.want, stdout.String())
+				assert.Equal(t, "Fetching status...\n", stderr.String(), "messages must not mix with machine output")
+			}
+		})
+	}
+}
+
+func TestOutputFormatFromGlobalFlag(t *testing.T) {
+	var formats []string
+	app := &cli.App{
+		Name:   "test",
+		Flags:  []cli.Flag{outputFlag},
+		Before: validateOutputFormat,
+		Commands: []*cli.Command{
+			{
+				Name: "backup",
+				Subcommands: []*cli.Command{
+					{
+						Name:  "create",
+						Flags: []cli.Flag{&cli.StringFlag{Name: "output"}},
+						Action: func(cCtx *cli.Context) error {
+							formats = append(formats, newOutput(cCtx).format, cCtx.String("output"))
+							return nil
+						},
+					},
+				},
+			},
+		},
+	}
+
+	assert.NoError(t, app.Run([]string{"test", "-o", "yaml", "backup", "create", "--output", "backup.json"}))
+	assert.Equal(t, []string{outputYAML, "backup.json"}, formats)
+
+	formats = nil
+	assert.NoError(t, app.Run([]string{"test", "backup", "create"}))
+	assert.Equal(t, outputTable, formats[0])
+
+	assert.Error(t, app.Run([]string{"test", "--output", "xml", "backup", "create"}))
+}
+
+func TestCompletionScript(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		t.Run(shell, func(t *testing.T) {
+			var buf bytes.Buffer
+			app := &cli.App{
+				Name:                 "neuratrade",
+				Writer:               &buf,
+				EnableBashCompletion: true,
+				Commands:             []*cli.Command{{Name: "status", Usage: "Show status"}, completionCommand()},
+			}
+			assert.NoError(t, app.Run([]string{"neuratrade", "completion", shell}))
+			assert.Contains(t, buf.String(), "neuratrade")
+			if shell != "fish" {
+				assert.Contains(t, buf.String(), "--generate-bash-completion")
+			} else {
+				assert.Contains(t, buf.String(), "status")
+			}
+		})
+	}
+
+	app := &cli.App{Name: "neuratrade", Writer: io.Discard, Commands: []*cli.Command{completionCommand()}}
+	assert.Error(t, app.Run([]string{"neuratrade", "completion", "powershell"}))
+}
+
+func TestProfileResolution(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("NEURATRADE_HOME", home)
+	t.Setenv("NEURATRADE_API_BASE_URL", "")
+	t.Setenv("NEURATRADE_API_KEY", "")
+	t.Setenv("NEURATRADE_PROFILE", "")
+	t.Cleanup(func() { profileOverride = "" })
+
+	config := `{
+  "server": {"port": 9090},
+  "security": {"admin_api_key": "global-key"},
+  "telegram": {"chat_id": "111"},
+  "active_profile": "local",
+  "profiles": {
+    "local": {"base_url": "http://localhost:8080", "chat_id": "222"},
+    "prod": {"base_url": "https://vps.example.com", "api_key": "prod-key", "chat_id": "333"}
+  }
+}`
+	assert.NoError(t, os.WriteFile(home+"/config.json", []byte(config), 0600))
+
+	// active_profile from config.json; the API key falls back to the global one
+	assert.Equal(t, "http://localhost:8080", getBaseURL())
+	assert.Equal(t, "global-key", getAPIKey())
+	assert.Equal(t, "222", defaultChatID())
+
+	// NEURATRADE_PROFILE beats active_profile
+	t.Setenv("NEURATRADE_PROFILE", "prod")
+	assert.Equal(t, "https://vps.example.com", getBaseURL())
+	assert.Equal(t, "prod-key", getAPIKey())
+	assert.Equal(t, "333", defaultChatID())
+
+	// The --profile flag beats both, and explicit env settings still win
+	profileOverride = "local"
+	assert.Equal(t, "http://localhost:8080", getBaseURL())
+	t.Setenv("NEURATRADE_API_BASE_URL", "http://override:1234")
+	assert.Equal(t, "http://override:1234", getBaseURL())
+
+	// Binding under a profile saves the chat ID to that profile only
+	assert.NoError(t, persistChatIDToConfig("444"))
+	cfg := getConfigValue(home)
+	assert.Equal(t, "444", cfg.Profiles["local"].ChatID)
+	assert.Equal(t, "111", cfg.Telegram.ChatID)
+
+	app := &cli.App{
+		Name:     "test",
+		Flags:    []cli.Flag{profileFlag},
+		Before:   selectProfile,
+		Commands: []*cli.Command{{Name: "status", Action: func(*cli.Context) error { return nil }}},
+	}
+	assert.NoError(t, app.Run([]string{"test", "--profile", "prod", "status"}))
+	assert.Equal(t, "prod", profileOverride)
+	assert.Error(t, app.Run([]string{"test", "--profile", "prdo", "status"}))
+}
+
+func TestConfigUseProfile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("NEURATRADE_HOME", home)
+	t.Setenv("NEURATRADE_API_BASE_URL", "")
+	t.Setenv("NEURATRADE_PROFILE", "")
+	t.Cleanup(func() { profileOverride = "" })
+
+	app := &cli.App{
+		Name:   "test",
+		Flags:  []cli.Flag{outputFlag, profileFlag},
+		Before: selectProfile,
+		Commands: []*cli.Command{
+			{
+				Name: "config",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "use-profile",
+						Action: configUseProfile,
+						Flags: []cli.Flag{
+							&cli.StringFlag{Name: "base-url"},
+							&cli.StringFlag{Name: "api-key"},
+							&cli.StringFlag{Name: "chat-id"},
+						},
+					},
+					{Name: "profiles", Action: configProfiles},
+				},
+			},
+		},
+	}
+
+	// Switching to a profile that does not exist yet needs settings to create it
+	assert.Error(t, app.Run([]string{"test", "config", "use-profile", "staging"}))
+	assert.Error(t, app.Run([]string{"test", "config", "use-profile"}))
+	assert.Error(t, app.Run([]string{"test", "config", "use-profile", "staging", "--base-url", "https://staging.example.com"}))
+
+	assert.NoError(t, app.Run([]string{"test", "-o", "json", "config", "use-profile", "--base-url", "https://staging.example.com", "--api-key", "staging-key", "staging"}))
+	assert.NoError(t, app.Run([]string{"test", "-o", "json", "config", "use-profile", "--base-url", "http://localhost:8080", "local"}))
+	assert.Equal(t, "http://localhost:8080", getBaseURL())
+
+	// Updating an existing profile keeps the settings that were not passed
+	assert.NoError(t, app.Run([]string{"test", "-o", "json", "config", "use-profile", "--chat-id", "555", "staging"}))
+	cfg := getConfigValue(home)
+	assert.Equal(t, "staging", cfg.ActiveProfile)
+	assert.Equal(t, profileConfig{BaseURL: "https://staging.example.com", APIKey: "staging-key", ChatID: "555"}, cfg.Profiles["staging"])
+	assert.Equal(t, "https://staging.example.com", getBaseURL())
+	assert.Equal(t, "staging-key", getAPIKey())
+
+	assert.NoError(t, app.Run([]string{"test", "-o", "json", "config", "profiles"}))
+}
+
+func TestClientVersionHandshake(t *testing.T) {
+	var gotHeader string
+	minVersion := "0.0.1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get(clientVersionHeader)
+		w.Header().Set("Content-Type", "application/json")
+		compatible := minVersion == "0.0.1"
+		if !compatible {
+			w.Header().Set(clientCompatHeader, "outdated; min="+minVersion)
+		}
+		json.NewEncoder(w).Encode(CompatResponse{
+			ServerVersion:     "2.0.0",
+			APIVersion:        "v1",
+			MinClientVersions: map[string]string{clientName: minVersion},
+			Client:            &ClientCompat{Name: clientName, Version: version, MinVersion: minVersion, Compatible: compatible},
+		})
+	}))
+	defer server.Close()
+
+	var warnings bytes.Buffer
+	compatWarningOutput = &warnings
+	defer func() { compatWarningOutput = os.Stderr }()
+
+	client := NewAPIClient(server.URL, "")
+	assert.NoError(t, requireCompatibleBackend(client))
+	assert.Equal(t, clientName+"/"+version, gotHeader)
+	assert.Empty(t, warnings.String())
+
+	// An outdated CLI is warned on any request and refused for destructive ones
+	minVersion = "9.0.0"
+	err := requireCompatibleBackend(client)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "9.0.0")
+	assert.Contains(t, warnings.String(), "outdated; min=9.0.0")
+
+	t.Setenv("NEURATRADE_SKIP_COMPAT_CHECK", "true")
+	assert.NoError(t, requireCompatibleBackend(client))
+}
+
+func TestRequireCompatibleBackendWithoutEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	var warnings bytes.Buffer
+	compatWarningOutput = &warnings
+	defer func() { compatWarningOutput = os.Stderr }()
+
+	assert.NoError(t, requireCompatibleBackend(NewAPIClient(server.URL, "")))
+	assert.Contains(t, warnings.String(), "Could not verify backend compatibility")
+}
+
+func TestSearchCommand(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/search", r.URL.Path)
+		assert.Equal(t, "symbol:BTCUSDT stop_loss", r.URL.Query().Get("q"))
+		assert.Equal(t, "trades", r.URL.Query().Get("scope"))
+		assert.Equal(t, "5", r.URL.Query().Get("limit"))
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SearchResponseEnvelope{Status: "success", Data: SearchResponse{
+			Query: r.URL.Query().Get("q"),
+			Results: []SearchResult{{
+				Kind:    "trade",
+				ID:      "t-1",
+				Title:   "BTC/USDT long loss (scalping)",
+				Snippet: "PnL -12.00 on binance, stop_loss",
+				Score:   10.5,
+				At:      "2026-06-10T12:00:00Z",
+			}},
+		}})
+	}))
+	defer server.Close()
+
+	originalURL := os.Getenv("NEURATRADE_API_BASE_URL")
+	os.Setenv("NEURATRADE_API_BASE_URL", server.URL)
+	defer os.Setenv("NEURATRADE_API_BASE_URL", originalURL)
+
+	app := &cli.App{Name: "test", Commands: []*cli.Command{searchCommand()}}
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	err := app.Run([]string{"test", "search", "--scope", "trades", "--limit", "5", "symbol:BTCUSDT", "stop_loss"})
+	w.Close()
+	os.Stdout = oldStdout
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	_, _ = buf.ReadFrom(r)
+	assert.Contains(t, buf.String(), "[trade] BTC/USDT long loss (scalping)")
+	assert.Contains(t, buf.String(), "PnL -12.00 on binance, stop_loss")
+
+	assert.Error(t, app.Run([]string{"test", "search"}))
+}
+
+func TestAlertsCommand(t *testing.T) {
+	var created CreateCustomAlertRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/telegram/internal/alerts/custom":
+			json.NewDecoder(r.Body).Decode(&created)
+			json.NewEncoder(w).Encode(CustomAlertEnvelope{Status: "success", Data: CustomAlert{
+				ID: "a-1", Expression: "BTC/USDT RSI(14,1h) < 30", IsActive: true,
+			}})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/telegram/internal/alerts/custom":
+			assert.Equal(t, "42", r.URL.Query().Get("chat_id"))
+			json.NewEncoder(w).Encode(CustomAlertListResponseEnvelope{Status: "success", Data: CustomAlertListResponse{
+				Alerts: []CustomAlert{{ID: "a-1", Expression: "BTC/USDT RSI(14,1h) < 30", IsActive: false}},
+			}})
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/telegram/internal/alerts/custom/preview":
+			var req PreviewCustomAlertRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			assert.Equal(t, "BTC/USDT price > 1 AND funding < 0", req.Rule)
+			json.NewEncoder(w).Encode(AlertPreviewEnvelope{Status: "success", Data: AlertPreview{
+				Expression: req.Rule, Exchange: "binance", Resolution: "1h", Evaluations: 720, Triggers: 3, TriggersPerDay: 0.1,
+				RecentTriggers: []string{"2026-03-02T10:00:00Z"},
+			}})
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/telegram/internal/alerts/custom/a-1":
+			assert.Equal(t, "42", r.URL.Query().Get("chat_id"))
+			json.NewEncoder(w).Encode(CustomAlertChangeResponseEnvelope{Status: "success", Data: CustomAlertChangeResponse{ID: "a-1", Deleted: true}})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	originalURL := os.Getenv("NEURATRADE_API_BASE_URL")
+	os.Setenv("NEURATRADE_API_BASE_URL", server.URL)
+	defer os.Setenv("NEURATRADE_API_BASE_URL", originalURL)
+
+	app := &cli.App{Name: "test", Commands: []*cli.Command{alertsCommand()}}
+	run := func(args ...string) (string, error) {
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		err := app.Run(append([]string{"test", "alerts"}, args...))
+		w.Close()
+		os.Stdout = oldStdout
+		var buf bytes.Buffer
+		_, _ = buf.ReadFrom(r)
+		return buf.String(), err
+	}
+
+	output, err := run("add", "--chat-id", "42", "BTC/USDT", "RSI(14,1h)", "<", "30")
+	assert.NoError(t, err)
+	assert.Equal(t, CreateCustomAlertRequest{ChatID: "42", Rule: "BTC/USDT RSI(14,1h) < 30"}, created)
+	assert.Contains(t, output, "Alert created: BTC/USDT RSI(14,1h) < 30")
+
+	output, err = run("list", "--chat-id", "42")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "a-1  [paused]  BTC/USDT RSI(14,1h) < 30")
+
+	output, err = run("preview", "BTC/USDT price > 1 AND funding < 0")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Would have fired 3 times (0.10/day) over 720 1h steps")
+	assert.Contains(t, output, "  - 2026-03-02T10:00:00Z")
+
+	output, err = run("remove", "--chat-id", "42", "a-1")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Alert a-1 deleted")
+
+	_, err = run("remove", "--chat-id", "42")
+	assert.Error(t, err)
+}
+
+func TestStrategyCommand(t *testing.T) {
+	var installed InstallStrategyRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/telegram/internal/strategies/validate":
+			var req ValidateStrategyRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			validation := StrategyValidation{Valid: true, Warnings: []string{"parameter pairs is not declared by the required skills"}}
+			if strings.Contains(req.Document, "api_key") {
+				validation = StrategyValidation{Errors: []string{"parameter api_key looks like a credential; secrets must not be shared"}}
+			}
+			json.NewEncoder(w).Encode(StrategyValidationEnvelope{Status: "success", Data: validation})
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/telegram/internal/strategies":
+			json.NewDecoder(r.Body).Decode(&installed)
+			json.NewEncoder(w).Encode(InstallStrategyResponseEnvelope{Status: "success", Data: InstallStrategyResponse{
+				Strategy: InstalledStrategy{Config: StrategyConfig{Name: "night", Strategy: "scalping", Risk: StrategyRiskCaps{
+					DailyLossPct: 1, MinConfidence: 0.8, MaxPositionPct: 2, ScanIntervalSeconds: 900, AIUsage: "low",
+				}}},
+				Warnings: []string{"parameter pairs is not declared by the required skills"},
+			}})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/telegram/internal/strategies/night/export":
+			assert.Equal(t, "json", r.URL.Query().Get("format"))
+			json.NewEncoder(w).Encode(StrategyExportEnvelope{Status: "success", Data: StrategyExport{
+				Name: "night", Format: "json", Document: "{\"name\": \"night\"}\n",
+			}})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	originalURL := os.Getenv("NEURATRADE_API_BASE_URL")
+	os.Setenv("NEURATRADE_API_BASE_URL", server.URL)
+	defer os.Setenv("NEURATRADE_API_BASE_URL", originalURL)
+
+	dir := t.TempDir()
+	valid := dir + "/night.yaml"
+	assert.NoError(t, os.WriteFile(valid, []byte("name: night\n"), 0600))
+	unsafe := dir + "/unsafe.yaml"
+	assert.NoError(t, os.WriteFile(unsafe, []byte("parameters:\n  api_key: abc\n"), 0600))
+
+	app := &cli.App{Name: "test", Commands: []*cli.Command{strategyCommand()}}
+	run := func(args ...string) (string, error) {
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		err := app.Run(append([]string{"test", "strategy"}, args...))
+		w.Close()
+		os.Stdout = oldStdout
+		var buf bytes.Buffer
+		_, _ = buf.ReadFrom(r)
+		return buf.String(), err
+	}
+
+	output, err := run("import", "--dry-run", valid)
+	assert.NoError(t, err)
+	assert.Contains(t, output, "is a valid strategy configuration")
+	assert.Empty(t, installed.ChatID, "dry runs never install")
+
+	output, err = run("import", "--chat-id", "42", unsafe)
+	assert.Error(t, err)
+	assert.Contains(t, output, "api_key looks like a credential")
+	assert.Empty(t, installed.ChatID, "invalid files are never installed")
+
+	output, err = run("import", "--chat-id", "42", valid)
+	assert.NoError(t, err)
+	assert.Equal(t, "name: night\n", installed.Document)
+	assert.Contains(t, output, "Strategy night (scalping) installed")
+	assert.Contains(t, output, "! parameter pairs is not declared")
+
+	exported := dir + "/shared.json"
+	_, err = run("export", "--chat-id", "42", "--format", "json", "--output", exported, "night")
+	assert.NoError(t, err)
+	content, err := os.ReadFile(exported)
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"name\": \"night\"}\n", string(content))
+
+	_, err = run("import", valid)
+	assert.Error(t, err, "installing needs a chat")
+}
+
+func TestSetupCommand(t *testing.T) {
+	recorded := map[string]UpdateSetupStepRequest{}
+	var selected SelectProfileRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		steps := []SetupStep{
+			{Name: "exchange_keys", Title: "Connect an exchange", Status: "pending"},
+			{Name: "telegram_binding", Title: "Bind Telegram", Status: "pending"},
+			{Name: "ai_provider", Title: "Configure the AI provider", Status: "done", Detected: true},
+			{Name: "risk_limits", Title: "Set risk limits", Status: "pending"},
+			{Name: "dry_run", Title: "Dry-run verification", Status: "pending", Hint: "neuratrade setup --verify"},
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/setup/state":
+			json.NewEncoder(w).Encode(SetupStateEnvelope{Status: "success", Data: SetupState{Steps: steps, Total: 5, Completed: 1, Next: "exchange_keys"}})
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/api/v1/setup/state/"):
+			var req UpdateSetupStepRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			recorded[strings.TrimPrefix(r.URL.Path, "/api/v1/setup/state/")] = req
+			json.NewEncoder(w).Encode(SetupStateEnvelope{Status: "success", Data: SetupState{Steps: steps, Total: 5}})
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/telegram/internal/profiles/select":
+			json.NewDecoder(r.Body).Decode(&selected)
+			json.NewEncoder(w).Encode(OperatingProfileEnvelope{Status: "success", Data: OperatingProfile{Name: selected.Profile}})
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/setup/verify":
+			json.NewEncoder(w).Encode(SetupVerificationEnvelope{Status: "success", Data: SetupVerification{
+				Checks: []SetupProbeResult{{Name: "database", Passed: true}, {Name: "ccxt", Message: "connection refused"}},
+			}})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	originalURL := os.Getenv("NEURATRADE_API_BASE_URL")
+	os.Setenv("NEURATRADE_API_BASE_URL", server.URL)
+	defer os.Setenv("NEURATRADE_API_BASE_URL", originalURL)
+	home := t.TempDir()
+	t.Setenv("NEURATRADE_HOME", home)
+
+	app := &cli.App{Name: "test", Commands: []*cli.Command{setupCommand()}}
+	// Exchange keys with the default exchange, skip Telegram, pick a profile
+	app.Reader = strings.NewReader("key-1\nsecret-1\n\n\nBalanced\n")
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	err := app.Run([]string{"test", "setup", "--chat-id", "42"})
+	w.Close()
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	_, _ = buf.ReadFrom(r)
+	output := buf.String()
+
+	assert.NoError(t, err)
+	assert.Equal(t, UpdateSetupStepRequest{Status: "done", Detail: "binance keys saved to config.json"}, recorded["exchange_keys"])
+	assert.Equal(t, "skipped", recorded["telegram_binding"].Status)
+	assert.NotContains(t, recorded, "ai_provider", "steps already done are not prompted")
+	assert.Equal(t, SelectProfileRequest{ChatID: "42", Profile: "balanced"}, selected)
+	assert.Equal(t, "done", recorded["risk_limits"].Status)
+	assert.Contains(t, output, "❌ ccxt: connection refused")
+	assert.Contains(t, output, "neuratrade setup --verify")
+
+	content, err := os.ReadFile(home + "/config.json")
+	assert.NoError(t, err)
+	var config map[string]interface{}
+	assert.NoError(t, json.Unmarshal(content, &config))
+	binance := config["ccxt"].(map[string]interface{})["exchanges"].(map[string]interface{})["binance"].(map[string]interface{})
+	assert.Equal(t, "key-1", binance["api_key"])
+	assert.Equal(t, "secret-1", binance["api_secret"])
+}
+
+func TestDoctorCommand(t *testing.T) {
+	var selfTest RunSelfTestRequest
+	passed := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/telegram/internal/doctor":
+			assert.Equal(t, "42", r.URL.Query().Get("chat_id"))
+			fmt.Fprint(w, `{"overall_status":"warning","summary":"1 warning","checks":[{"name":"database","status":"healthy"},{"name":"exchange-connection","status":"warning","message":"connect one exchange with /connect_exchange"}]}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/admin/self-test":
+			json.NewDecoder(r.Body).Decode(&selfTest)
+			stages := []SelfTestStage{
+				{Name: "market_data", Status: "passed", DurationMs: 182.4, Message: "100 5m candles, last close 64000"},
+				{Name: "order", Status: "passed", DurationMs: 0.2},
+			}
+			if !passed {
+				stages[1] = SelfTestStage{Name: "order", Status: "failed", Message: "paper order rejected"}
+			}
+			json.NewEncoder(w).Encode(SelfTestReportEnvelope{Status: "success", Data: SelfTestReport{
+				Passed: passed, Exchange: "binance", Symbol: selfTest.Symbol, Timeframe: "5m", Stages: stages, DurationMs: 190,
+			}})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	originalURL := os.Getenv("NEURATRADE_API_BASE_URL")
+	os.Setenv("NEURATRADE_API_BASE_URL", server.URL)
+	defer os.Setenv("NEURATRADE_API_BASE_URL", originalURL)
+
+	app := &cli.App{Name: "test", Commands: []*cli.Command{doctorCommand()}}
+	run := func(args ...string) (string, error) {
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		err := app.Run(append([]string{"test", "doctor"}, args...))
+		w.Close()
+		os.Stdout = oldStdout
+		var buf bytes.Buffer
+		_, _ = buf.ReadFrom(r)
+		return buf.String(), err
+	}
+
+	output, err := run("--chat-id", "42")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Diagnostics for chat 42: warning")
+	assert.Contains(t, output, "exchange-connection: connect one exchange")
+
+	output, err = run("--deep", "--chat-id", "42", "--symbol", "ETH/USDT")
+	assert.NoError(t, err)
+	assert.Equal(t, RunSelfTestRequest{ChatID: "42", Symbol: "ETH/USDT"}, selfTest)
+	assert.Contains(t, output, "Self-test on binance ETH/USDT (5m)")
+	assert.Contains(t, output, "market_data")
+	assert.Contains(t, output, "182.4 ms")
+
+	passed = false
+	output, err = run("--deep", "--chat-id", "42")
+	assert.EqualError(t, err, "self-test failed")
+	assert.Contains(t, output, "paper order rejected")
+}
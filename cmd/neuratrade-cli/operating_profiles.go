@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+// operatingProfileFlag selects an operating profile when starting autonomous
+// mode; --profile already selects the config profile
+var operatingProfileFlag = &cli.StringFlag{
+	Name:  "operating-profile",
+	Usage: "Operating profile to trade with: conservative, balanced, aggressive or a custom one",
+}
+
+// operatingProfileCommand builds the "autonomous profile" command for risk,
+// sizing, scan frequency and AI usage presets
+func operatingProfileCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "profile",
+		Usage: "Manage operating profiles (conservative, balanced, aggressive and custom)",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "list",
+				Usage:  "List the profiles the chat can select",
+				Action: listOperatingProfiles,
+				Flags:  []cli.Flag{chatIDFlag(true)},
+			},
+			{
+				Name:      "select",
+				Usage:     "Select the profile autonomous trading runs with",
+				ArgsUsage: "<profile>",
+				Action:    selectOperatingProfile,
+				Flags:     []cli.Flag{chatIDFlag(true)},
+			},
+			{
+				Name:      "save",
+				Usage:     "Create or replace a custom profile; unset values come from --base",
+				ArgsUsage: "<name>",
+				Action:    saveOperatingProfile,
+				Flags: []cli.Flag{
+					chatIDFlag(true),
+					&cli.StringFlag{Name: "base", Usage: "Profile to start from", Value: "balanced"},
+					&cli.StringFlag{Name: "description", Usage: "Short description"},
+					&cli.Float64Flag{Name: "daily-loss-pct", Usage: "Daily loss, in percent of the day's opening equity, that halts entries"},
+					&cli.Float64Flag{Name: "min-confidence", Usage: "AI confidence (0.5-0.95) a trade needs"},
+					&cli.Float64Flag{Name: "max-position-pct", Usage: "Largest share of allocated capital, in percent, per trade"},
+					&cli.IntFlag{Name: "scan-interval", Usage: "Seconds between market scans (60-3600)"},
+					&cli.StringFlag{Name: "ai-usage", Usage: "How much market data the AI considers: low, standard or high"},
+				},
+			},
+			{
+				Name:      "delete",
+				Usage:     "Delete a custom profile",
+				ArgsUsage: "<name>",
+				Action:    deleteOperatingProfile,
+				Flags:     []cli.Flag{chatIDFlag(true)},
+			},
+		},
+	}
+}
+
+// operatingProfileChatID returns the chat the profiles belong to
+func operatingProfileChatID(cCtx *cli.Context) (string, error) {
+	chatID := chatIDValue(cCtx)
+	if chatID == "" {
+		return "", fmt.Errorf("chat-id is required")
+	}
+	return chatID, nil
+}
+
+// printOperatingProfile prints one profile's settings
+func printOperatingProfile(out *output, profile OperatingProfile) {
+	out.Printf("  daily loss cap %.2f%%, min confidence %.2f, max position %.2f%%, scan every %ds, AI usage %s\n",
+		profile.DailyLossPct, profile.MinConfidence, profile.MaxPositionPct, profile.ScanIntervalSeconds, profile.AIUsage)
+}
+
+// listOperatingProfiles prints the chat's selectable profiles
+func listOperatingProfiles(cCtx *cli.Context) error {
+	chatID, err := operatingProfileChatID(cCtx)
+	if err != nil {
+		return err
+	}
+	out := newOutput(cCtx)
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	response, err := client.ListOperatingProfiles(chatID)
+	if err != nil {
+		return fmt.Errorf("failed to list operating profiles: %w", err)
+	}
+
+	list := response.Data
+	return out.Render(list, func() {
+		if list.Active == "" {
+			out.Println("No profile selected; the server defaults apply.")
+		}
+		for _, profile := range list.Profiles {
+			marker := " "
+			if profile.Name == list.Active {
+				marker = "*"
+			}
+			kind := "custom"
+			if profile.BuiltIn {
+				kind = "built-in"
+			}
+			out.Printf("%s %s (%s)  %s\n", marker, profile.Name, kind, profile.Description)
+			printOperatingProfile(out, profile)
+		}
+	})
+}
+
+// selectOperatingProfile selects the profile named by the first argument
+func selectOperatingProfile(cCtx *cli.Context) error {
+	chatID, err := operatingProfileChatID(cCtx)
+	if err != nil {
+		return err
+	}
+	name := cCtx.Args().First()
+	if name == "" {
+		return fmt.Errorf("a profile name is required (see neuratrade autonomous profile list)")
+	}
+	out := newOutput(cCtx)
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	response, err := client.SelectOperatingProfile(&SelectProfileRequest{ChatID: chatID, Profile: name})
+	if err != nil {
+		return fmt.Errorf("failed to select operating profile: %w", err)
+	}
+
+	profile := response.Data
+	return out.Render(profile, func() {
+		out.Printf("✅ Trading with the %s profile\n", profile.Name)
+		printOperatingProfile(out, profile)
+	})
+}
+
+// saveOperatingProfile creates or replaces a custom profile from the flags
+func saveOperatingProfile(cCtx *cli.Context) error {
+	chatID, err := operatingProfileChatID(cCtx)
+	if err != nil {
+		return err
+	}
+	name := cCtx.Args().First()
+	if name == "" {
+		return fmt.Errorf("a profile name is required, for example: neuratrade autonomous profile save night --base conservative --scan-interval 900")
+	}
+	out := newOutput(cCtx)
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	response, err := client.SaveOperatingProfile(&SaveProfileRequest{
+		ChatID: chatID,
+		Base:   cCtx.String("base"),
+		Profile: OperatingProfile{
+			Name:                name,
+			Description:         cCtx.String("description"),
+			DailyLossPct:        cCtx.Float64("daily-loss-pct"),
+			MinConfidence:       cCtx.Float64("min-confidence"),
+			MaxPositionPct:      cCtx.Float64("max-position-pct"),
+			ScanIntervalSeconds: cCtx.Int("scan-interval"),
+			AIUsage:             cCtx.String("ai-usage"),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save operating profile: %w", err)
+	}
+
+	profile := response.Data
+	return out.Render(profile, func() {
+		out.Printf("✅ Profile %s saved\n", profile.Name)
+		printOperatingProfile(out, profile)
+		out.Printf("Select it with: neuratrade autonomous profile select %s\n", profile.Name)
+	})
+}
+
+// deleteOperatingProfile deletes a custom profile
+func deleteOperatingProfile(cCtx *cli.Context) error {
+	chatID, err := operatingProfileChatID(cCtx)
+	if err != nil {
+		return err
+	}
+	name := cCtx.Args().First()
+	if name == "" {
+		return fmt.Errorf("a profile name is required (see neuratrade autonomous profile list)")
+	}
+	out := newOutput(cCtx)
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	response, err := client.DeleteOperatingProfile(name, chatID)
+	if err != nil {
+		return fmt.Errorf("failed to delete operating profile: %w", err)
+	}
+
+	return out.Render(response.Data, func() {
+		out.Printf("🗑️  Profile %s deleted\n", name)
+	})
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by the global --output flag
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// outputFlag selects how command results are printed
+var outputFlag = &cli.StringFlag{
+	Name:    "output",
+	Aliases: []string{"o"},
+	Usage:   "Output format: table, json or yaml",
+	Value:   outputTable,
+	EnvVars: []string{"NEURATRADE_OUTPUT"},
+}
+
+// output renders command results in the selected format. In table mode
+// everything goes to stdout as before; in json and yaml mode only the result
+// is written to stdout and progress messages go to stderr, so the output can
+// be piped into scripts.
+type output struct {
+	format string
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// newOutput creates the renderer for a command from the global --output flag
+func newOutput(cCtx *cli.Context) *output {
+	format := outputTable
+	if cCtx != nil {
+		// Walk from the app context down so a command's own --output flag
+		// (backup create writes to a file) does not shadow the global one
+		lineage := cCtx.Lineage()
+		for i := len(lineage) - 1; i >= 0; i-- {
+			if value := lineage[i].String("output"); value != "" {
+				format = strings.ToLower(value)
+				break
+			}
+		}
+	}
+	return &output{format: format, stdout: os.Stdout, stderr: os.Stderr}
+}
+
+// validateOutputFormat rejects unknown --output values before a command runs
+func validateOutputFormat(cCtx *cli.Context) error {
+	switch format := strings.ToLower(cCtx.String("output")); format {
+	case "", outputTable, outputJSON, outputYAML:
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q (use table, json or yaml)", format)
+	}
+}
+
+// machine reports whether results are printed for scripts rather than people
+func (o *output) machine() bool {
+	return o.format == outputJSON || o.format == outputYAML
+}
+
+// messages is where progress and status text goes
+func (o *output) messages() io.Writer {
+	if o.machine() {
+		return o.stderr
+	}
+	return o.stdout
+}
+
+// Printf prints a progress or status message
+func (o *output) Printf(format string, args ...interface{}) {
+	fmt.Fprintf(o.messages(), format, args...)
+}
+
+// Println prints a progress or status message line
+func (o *output) Println(args ...interface{}) {
+	fmt.Fprintln(o.messages(), args...)
+}
+
+// Render prints a command result: table calls the human-readable printer,
+// json and yaml encode data.
+func (o *output) Render(data interface{}, table func()) error {
+	switch o.format {
+	case outputJSON:
+		encoded, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode output: %w", err)
+		}
+		_, err = fmt.Fprintln(o.stdout, string(encoded))
+		return err
+	case outputYAML:
+		// Round-trip through JSON so YAML keys follow the json tags
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("failed to encode output: %w", err)
+		}
+		var generic interface{}
+		if err := json.Unmarshal(encoded, &generic); err != nil {
+			return fmt.Errorf("failed to encode output: %w", err)
+		}
+		encoded, err = yaml.Marshal(generic)
+		if err != nil {
+			return fmt.Errorf("failed to encode output: %w", err)
+		}
+		_, err = o.stdout.Write(encoded)
+		return err
+	default:
+		if table != nil {
+			table()
+		}
+		return nil
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// profileFlag selects a named environment from the profiles in config.json
+var profileFlag = &cli.StringFlag{
+	Name:    "profile",
+	Aliases: []string{"p"},
+	Usage:   "Config profile to use, e.g. prod, staging or local",
+	EnvVars: []string{"NEURATRADE_PROFILE"},
+}
+
+// ProfileInfo describes a configured profile in command output
+type ProfileInfo struct {
+	Name      string `json:"name"`
+	Active    bool   `json:"active"`
+	BaseURL   string `json:"base_url,omitempty"`
+	ChatID    string `json:"chat_id,omitempty"`
+	APIKeySet bool   `json:"api_key_set"`
+}
+
+// selectProfile applies the global --profile flag and rejects unknown names,
+// so a typo never silently falls back to another environment
+func selectProfile(cCtx *cli.Context) error {
+	name := strings.TrimSpace(cCtx.String("profile"))
+	profileOverride = name
+	if name == "" {
+		return nil
+	}
+
+	// Profiles are created and listed through config; completion needs no backend
+	switch cCtx.Args().First() {
+	case "config", "completion", "help", "h":
+		return nil
+	}
+
+	cfg := getConfigValue(defaultNeuraTradeHome())
+	if cfg == nil || cfg.Profiles == nil {
+		return fmt.Errorf("unknown profile %q: no profiles configured (run: neuratrade config use-profile %s --base-url <url>)", name, name)
+	}
+	if _, ok := cfg.Profiles[name]; !ok {
+		return fmt.Errorf("unknown profile %q (run: neuratrade config profiles)", name)
+	}
+	return nil
+}
+
+// configUseProfile makes a profile active, creating or updating it from flags
+func configUseProfile(cCtx *cli.Context) error {
+	out := newOutput(cCtx)
+	name := strings.TrimSpace(cCtx.Args().First())
+	if name == "" {
+		return fmt.Errorf("profile name is required: neuratrade config use-profile [flags] <name>")
+	}
+	if cCtx.Args().Len() > 1 {
+		// Flags after the name are not parsed, so they would be dropped silently
+		return fmt.Errorf("unexpected arguments after profile name %q (flags go before the name)", name)
+	}
+
+	updates := map[string]string{
+		"base_url": strings.TrimSpace(cCtx.String("base-url")),
+		"api_key":  strings.TrimSpace(cCtx.String("api-key")),
+		"chat_id":  strings.TrimSpace(cCtx.String("chat-id")),
+	}
+
+	home := defaultNeuraTradeHome()
+	err := editLocalConfig(home, true, func(config map[string]interface{}) error {
+		profiles := configSection(config, "profiles")
+		profile, exists := profiles[name].(map[string]interface{})
+		if !exists {
+			if updates["base_url"] == "" && updates["api_key"] == "" && updates["chat_id"] == "" {
+				return fmt.Errorf("profile %q does not exist; create it with --base-url, --api-key or --chat-id", name)
+			}
+			profile = make(map[string]interface{})
+			profiles[name] = profile
+		}
+		for key, value := range updates {
+			if value != "" {
+				profile[key] = value
+			}
+		}
+		config["active_profile"] = name
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Report the settings the profile resolves to now that it is active
+	var profile profileConfig
+	if cfg := getConfigValue(home); cfg != nil {
+		profile = cfg.Profiles[name]
+	}
+	info := ProfileInfo{
+		Name:      name,
+		Active:    true,
+		BaseURL:   profile.BaseURL,
+		ChatID:    profile.ChatID,
+		APIKeySet: profile.APIKey != "",
+	}
+
+	return out.Render(info, func() {
+		out.Printf("✅ Active profile: %s\n", name)
+		if info.BaseURL != "" {
+			out.Printf("   Base URL: %s\n", info.BaseURL)
+		}
+		if info.ChatID != "" {
+			out.Printf("   Chat ID: %s\n", info.ChatID)
+		}
+		if info.APIKeySet {
+			out.Println("   API key: set")
+		} else {
+			out.Println("   API key: not set")
+		}
+		out.Printf("Saved to %s\n", filepath.Join(home, "config.json"))
+		if override := activeProfileName(nil); override != "" && override != name {
+			out.Printf("⚠️  --profile/NEURATRADE_PROFILE=%s still takes precedence in this shell\n", override)
+		}
+	})
+}
+
+// configProfiles lists the configured profiles
+func configProfiles(cCtx *cli.Context) error {
+	out := newOutput(cCtx)
+	cfg := getConfigValue(defaultNeuraTradeHome())
+
+	profiles := []ProfileInfo{}
+	if cfg != nil {
+		active := activeProfileName(cfg)
+		names := make([]string, 0, len(cfg.Profiles))
+		for name := range cfg.Profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			profile := cfg.Profiles[name]
+			profiles = append(profiles, ProfileInfo{
+				Name:      name,
+				Active:    name == active,
+				BaseURL:   profile.BaseURL,
+				ChatID:    profile.ChatID,
+				APIKeySet: profile.APIKey != "",
+			})
+		}
+	}
+
+	return out.Render(profiles, func() {
+		if len(profiles) == 0 {
+			out.Println("No profiles configured.")
+			out.Println("Run: neuratrade config use-profile <name> --base-url <url>")
+			return
+		}
+		for _, p := range profiles {
+			marker := " "
+			if p.Active {
+				marker = "*"
+			}
+			out.Printf("%s %-10s %s", marker, p.Name, p.BaseURL)
+			if p.ChatID != "" {
+				out.Printf("  chat=%s", p.ChatID)
+			}
+			if p.APIKeySet {
+				out.Printf("  api-key=set")
+			}
+			out.Println()
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// searchCommand builds the "search" command
+func searchCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "search",
+		Usage:     "Search trades, signals, quests and exchange error logs",
+		ArgsUsage: "<query>  (qualifiers: symbol: strategy: quest: error: in:trades,signals,quests,logs)",
+		Action:    search,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "scope",
+				Usage: "Comma-separated kinds to search (trades, signals, quests, logs)",
+			},
+			&cli.IntFlag{
+				Name:  "limit",
+				Usage: "Maximum number of results",
+				Value: 20,
+			},
+		},
+	}
+}
+
+// search runs a global search and prints the ranked results
+func search(cCtx *cli.Context) error {
+	query := strings.Join(cCtx.Args().Slice(), " ")
+	if strings.TrimSpace(query) == "" {
+		return fmt.Errorf("a search query is required, for example: neuratrade search symbol:BTCUSDT in:trades")
+	}
+	out := newOutput(cCtx)
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	response, err := client.Search(query, cCtx.String("scope"), strconv.Itoa(cCtx.Int("limit")))
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+
+	results := response.Data.Results
+	return out.Render(results, func() {
+		if len(results) == 0 {
+			out.Printf("No results for %q\n", query)
+			return
+		}
+		out.Printf("%d results for %q\n\n", len(results), query)
+		for _, result := range results {
+			out.Printf("[%s] %s  (score %.2f, %s)\n", result.Kind, result.Title, result.Score, result.At)
+			if result.Snippet != "" {
+				out.Printf("    %s\n", result.Snippet)
+			}
+			out.Printf("    id: %s\n", result.ID)
+		}
+	})
+}
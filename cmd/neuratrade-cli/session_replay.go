@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// maxReplayPause caps the wait between two replayed steps
+const maxReplayPause = 5 * time.Second
+
+// replayCommand builds the "replay" command that re-runs a recorded trading
+// day through the signal and decision pipeline for debugging
+func replayCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "replay",
+		Usage: "Replay a recorded trading day through signals and decisions without placing orders",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "exchange", Usage: "Exchange, e.g. binance", Required: true},
+			&cli.StringFlag{Name: "symbol", Usage: "Symbol, e.g. BTC/USDT", Required: true},
+			&cli.StringFlag{Name: "date", Usage: "Day to replay (YYYY-MM-DD, UTC)", Required: true},
+			&cli.StringFlag{Name: "interval", Usage: "Candle size ticks are aggregated into", Value: "1m"},
+			&cli.StringFlag{Name: "at", Usage: "Time to explain, HH:MM (UTC) or RFC3339, e.g. 14:32"},
+			&cli.Float64Flag{Name: "speed", Usage: "Replayed time per real second, e.g. 60 plays a minute per second; 0 prints at once"},
+		},
+		Action: runReplay,
+	}
+}
+
+// runReplay replays the day and plays its steps back at the requested speed
+func runReplay(cCtx *cli.Context) error {
+	out := newOutput(cCtx)
+	speed := cCtx.Float64("speed")
+	if speed < 0 {
+		return cli.Exit("Error: --speed cannot be negative", 1)
+	}
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	response, err := client.ReplayDay(cCtx.String("exchange"), cCtx.String("symbol"), cCtx.String("date"), cCtx.String("interval"), cCtx.String("at"))
+	if err != nil {
+		return fmt.Errorf("failed to replay session: %w", err)
+	}
+
+	report := response.Data
+	return out.Render(report, func() {
+		out.Printf("⏪ Replay of %s %s on %s: %d candles of %s from %d ticks\n",
+			report.Symbol, report.Exchange, cCtx.String("date"), report.Candles, report.Interval, report.Ticks)
+		out.Printf("  %d entries replayed, %d decisions recorded, %d divergences\n",
+			report.Entries, report.RecordedDecisions, report.Divergences)
+
+		var previous time.Time
+		for _, step := range report.Steps {
+			at, _ := time.Parse(time.RFC3339, step.At)
+			if speed > 0 && !previous.IsZero() {
+				pause := time.Duration(float64(at.Sub(previous)) / speed)
+				time.Sleep(min(pause, maxReplayPause))
+			}
+			previous = at
+			printReplayStep(out, step, at)
+		}
+
+		if focus := report.Focus; focus != nil {
+			at, _ := time.Parse(time.RFC3339, focus.At)
+			out.Println()
+			out.Printf("🔎 At %s\n", at.Format("15:04"))
+			printReplayStep(out, *focus, at)
+			if len(focus.Signals) == 0 {
+				out.Println("    no technical signals")
+			}
+			for _, signal := range focus.Signals {
+				out.Printf("    signal %-28s %-8s %.2f  %s\n", signal.Name, signal.Direction, signal.Value, signal.Description)
+			}
+		} else if cCtx.String("at") != "" {
+			out.Println("⚠️  No candle was recorded at the requested time")
+		}
+	})
+}
+
+// printReplayStep prints a replayed decision and the decisions recorded then
+func printReplayStep(out *output, step SessionReplayStep, at time.Time) {
+	marker := "  "
+	if step.Diverged {
+		marker = "≠ "
+	}
+	out.Printf("%s%s  %-10.4f replay: %-15s %.2f  %s\n", marker, at.Format("15:04"), step.Price, step.Action, step.Confidence, step.Reasoning)
+	for _, recorded := range step.Recorded {
+		order := recorded.OrderStatus
+		if order == "" {
+			order = "none"
+		}
+		out.Printf("    recorded %s: %-6s %.2f  %s (%s, order %s)\n",
+			recorded.Source, recorded.Action, recorded.Confidence, recorded.Reasoning, recorded.ID, order)
+	}
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Setup step names and statuses reported by /api/v1/setup/state
+const (
+	setupStepExchangeKeys = "exchange_keys"
+	setupStepTelegram     = "telegram_binding"
+	setupStepAIProvider   = "ai_provider"
+	setupStepRiskLimits   = "risk_limits"
+
+	setupStatusDone    = "done"
+	setupStatusSkipped = "skipped"
+	setupStatusPending = "pending"
+)
+
+// setupCommand builds the "setup" command: a first-run wizard that walks
+// through exchange keys, Telegram binding, AI provider, risk limits and a
+// dry-run verification, resuming from the progress the backend recorded
+func setupCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "setup",
+		Usage:  "Run the first-run setup wizard (resumes where it stopped)",
+		Action: runSetup,
+		Flags: []cli.Flag{
+			chatIDFlag(false),
+			&cli.BoolFlag{Name: "status", Usage: "Show setup progress without prompting"},
+			&cli.BoolFlag{Name: "verify", Usage: "Only run the dry-run verification"},
+			&cli.BoolFlag{Name: "reset", Usage: "Forget recorded progress and start over"},
+		},
+	}
+}
+
+// setupPrompter reads wizard answers line by line
+type setupPrompter struct {
+	in  *bufio.Reader
+	out *output
+}
+
+// ask prints label and returns the trimmed answer, or fallback when empty
+func (p *setupPrompter) ask(label, fallback string) (string, error) {
+	if fallback != "" {
+		p.out.Printf("%s [%s]: ", label, fallback)
+	} else {
+		p.out.Printf("%s: ", label)
+	}
+	line, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("setup aborted: %w", err)
+	}
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer, nil
+	}
+	return fallback, nil
+}
+
+// setupStepRunner configures one step and returns a detail for the record;
+// an empty detail means the operator skipped the step
+type setupStepRunner func(cCtx *cli.Context, p *setupPrompter, client *APIClient) (string, error)
+
+var setupStepRunners = map[string]setupStepRunner{
+	setupStepExchangeKeys: setupExchangeKeys,
+	setupStepTelegram:     setupTelegramBinding,
+	setupStepAIProvider:   setupAIProvider,
+	setupStepRiskLimits:   setupRiskLimits,
+}
+
+// runSetup shows, verifies, resets or interactively completes the setup
+func runSetup(cCtx *cli.Context) error {
+	out := newOutput(cCtx)
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+
+	if cCtx.Bool("reset") {
+		if _, err := client.ResetSetupState(); err != nil {
+			return fmt.Errorf("failed to reset setup: %w", err)
+		}
+		out.Println("Setup progress cleared.")
+	}
+
+	if cCtx.Bool("verify") {
+		return verifySetup(out, client)
+	}
+
+	response, err := client.GetSetupState()
+	if err != nil {
+		return fmt.Errorf("failed to load setup state: %w", err)
+	}
+	state := response.Data
+	if cCtx.Bool("status") || state.Complete {
+		return out.Render(state, func() {
+			printSetupState(out, state)
+		})
+	}
+
+	prompter := &setupPrompter{in: bufio.NewReader(cCtx.App.Reader), out: out}
+	out.Println("NeuraTrade setup: press Enter on an empty prompt to skip a step.")
+	for _, step := range state.Steps {
+		runner, ok := setupStepRunners[step.Name]
+		if !ok || step.Status != setupStatusPending {
+			continue
+		}
+		out.Printf("\n%s\n", step.Title)
+		detail, err := runner(cCtx, prompter, client)
+		if err != nil {
+			return err
+		}
+		status := setupStatusDone
+		if detail == "" {
+			status = setupStatusSkipped
+			detail = "skipped in neuratrade setup"
+		}
+		if _, err := client.UpdateSetupStep(step.Name, &UpdateSetupStepRequest{Status: status, Detail: detail}); err != nil {
+			return fmt.Errorf("failed to record %s: %w", step.Name, err)
+		}
+		out.Printf("  %s %s\n", setupStatusMarker(status), detail)
+	}
+
+	out.Println("")
+	return verifySetup(out, client)
+}
+
+// verifySetup runs the dry-run verification and prints every check
+func verifySetup(out *output, client *APIClient) error {
+	response, err := client.VerifySetup()
+	if err != nil {
+		return fmt.Errorf("failed to verify setup: %w", err)
+	}
+	verification := response.Data
+	return out.Render(verification, func() {
+		for _, check := range verification.Checks {
+			if check.Passed {
+				out.Printf("✅ %s reachable\n", check.Name)
+			} else {
+				out.Printf("❌ %s: %s\n", check.Name, check.Message)
+			}
+		}
+		if verification.State != nil {
+			printSetupState(out, *verification.State)
+		}
+		if verification.Passed {
+			out.Println("Dry run passed. Start trading with: neuratrade autonomous begin")
+		} else {
+			out.Println("Dry run failed. Fix the items above and run: neuratrade setup --verify")
+		}
+	})
+}
+
+// printSetupState prints each step with its status and the next action
+func printSetupState(out *output, state SetupState) {
+	out.Printf("Setup %d/%d complete\n", state.Completed, state.Total)
+	for _, step := range state.Steps {
+		line := fmt.Sprintf("%s %s", setupStatusMarker(step.Status), step.Title)
+		if step.Detail != "" {
+			line += " (" + step.Detail + ")"
+		}
+		out.Println(line)
+	}
+	for _, step := range state.Steps {
+		if step.Name == state.Next {
+			out.Printf("Next: %s\n", step.Hint)
+		}
+	}
+}
+
+func setupStatusMarker(status string) string {
+	switch status {
+	case setupStatusDone:
+		return "✅"
+	case setupStatusSkipped:
+		return "⏭️ "
+	default:
+		return "⬜"
+	}
+}
+
+// setupExchangeKeys stores exchange API keys in config.json the same way
+// config init does
+func setupExchangeKeys(_ *cli.Context, p *setupPrompter, _ *APIClient) (string, error) {
+	apiKey, err := p.ask("Exchange API key", "")
+	if err != nil || apiKey == "" {
+		return "", err
+	}
+	secret, err := p.ask("Exchange API secret", "")
+	if err != nil {
+		return "", err
+	}
+	if secret == "" {
+		return "", fmt.Errorf("an API secret is required with the API key")
+	}
+	exchange, err := p.ask("Exchange", "binance")
+	if err != nil {
+		return "", err
+	}
+	exchange = strings.ToLower(exchange)
+
+	err = editLocalConfig(defaultNeuraTradeHome(), true, func(config map[string]interface{}) error {
+		exchanges := configSection(configSection(config, "ccxt"), "exchanges")
+		settings := configSection(exchanges, exchange)
+		settings["enabled"] = true
+		settings["api_key"] = apiKey
+		settings["api_secret"] = secret
+		if _, ok := settings["testnet"]; !ok {
+			settings["testnet"] = false
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to save exchange keys: %w", err)
+	}
+	return fmt.Sprintf("%s keys saved to config.json", exchange), nil
+}
+
+// setupTelegramBinding verifies a binding code from the bot and saves the chat
+func setupTelegramBinding(cCtx *cli.Context, p *setupPrompter, client *APIClient) (string, error) {
+	code, err := p.ask("Binding code (from neuratrade generate-auth-code)", "")
+	if err != nil || code == "" {
+		return "", err
+	}
+	chatID, err := p.ask("Telegram chat ID", chatIDValue(cCtx))
+	if err != nil {
+		return "", err
+	}
+	if chatID == "" {
+		return "", fmt.Errorf("a chat ID is required to bind Telegram")
+	}
+
+	response, err := client.VerifyBindingCode(&VerifyBindingCodeRequest{ChatID: chatID, UserID: "cli-user-id", Code: code})
+	if err != nil {
+		return "", fmt.Errorf("failed to verify binding code: %w", err)
+	}
+	if !response.Success {
+		return "", fmt.Errorf("binding failed: %s", response.Error)
+	}
+	if err := persistChatIDToConfig(chatID); err != nil {
+		p.out.Printf("⚠️  Warning: failed to persist chat ID to config: %v\n", err)
+	}
+	return fmt.Sprintf("chat %s bound", chatID), nil
+}
+
+// setupAIProvider stores the AI provider settings in config.json
+func setupAIProvider(_ *cli.Context, p *setupPrompter, _ *APIClient) (string, error) {
+	apiKey, err := p.ask("AI provider API key", "")
+	if err != nil || apiKey == "" {
+		return "", err
+	}
+	provider, err := p.ask("AI provider", "minimax")
+	if err != nil {
+		return "", err
+	}
+	baseURL, err := p.ask("AI base URL (empty for the provider default)", "")
+	if err != nil {
+		return "", err
+	}
+
+	err = editLocalConfig(defaultNeuraTradeHome(), true, func(config map[string]interface{}) error {
+		ai := configSection(config, "ai")
+		ai["provider"] = provider
+		ai["api_key"] = apiKey
+		if baseURL != "" {
+			ai["base_url"] = baseURL
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to save AI provider: %w", err)
+	}
+	return fmt.Sprintf("%s provider saved to config.json; restart the backend to apply it", provider), nil
+}
+
+// setupRiskLimits selects the operating profile that caps risk for the chat
+func setupRiskLimits(cCtx *cli.Context, p *setupPrompter, client *APIClient) (string, error) {
+	profile, err := p.ask("Operating profile (conservative, balanced, aggressive; skip to decide later)", "")
+	if err != nil || profile == "" {
+		return "", err
+	}
+	chatID := chatIDValue(cCtx)
+	if chatID == "" {
+		return "", fmt.Errorf("bind Telegram or pass --chat-id before selecting risk limits")
+	}
+
+	response, err := client.SelectOperatingProfile(&SelectProfileRequest{ChatID: chatID, Profile: strings.ToLower(profile)})
+	if err != nil {
+		return "", fmt.Errorf("failed to select operating profile: %w", err)
+	}
+	selected := response.Data
+	printOperatingProfile(p.out, selected)
+	return fmt.Sprintf("%s profile selected for chat %s", selected.Name, chatID), nil
+}
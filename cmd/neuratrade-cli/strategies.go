@@ -0,0 +1,248 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// strategyCommand builds the "strategy" command for importing and exporting
+// shareable strategy configurations
+func strategyCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "strategy",
+		Usage: "Import, export and manage shareable strategy configurations",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "import",
+				Usage:     "Validate a YAML or JSON strategy file and install it",
+				ArgsUsage: "<file>",
+				Action:    importStrategy,
+				Flags: []cli.Flag{
+					chatIDFlag(false),
+					&cli.BoolFlag{Name: "dry-run", Usage: "Only validate the file"},
+				},
+			},
+			{
+				Name:      "export",
+				Usage:     "Export an installed strategy or an operating profile for sharing",
+				ArgsUsage: "<name>",
+				Action:    exportStrategy,
+				Flags: []cli.Flag{
+					chatIDFlag(true),
+					&cli.StringFlag{Name: "format", Usage: "Document format: yaml or json", Value: "yaml"},
+					&cli.StringFlag{Name: "output", Aliases: []string{"o"}, Usage: "File to write instead of stdout"},
+				},
+			},
+			{
+				Name:   "list",
+				Usage:  "List the chat's installed strategies",
+				Action: listStrategies,
+				Flags:  []cli.Flag{chatIDFlag(true)},
+			},
+			{
+				Name:      "delete",
+				Usage:     "Uninstall a strategy and its operating profile",
+				ArgsUsage: "<name>",
+				Action:    deleteStrategy,
+				Flags:     []cli.Flag{chatIDFlag(true)},
+			},
+			{
+				Name:   "schema",
+				Usage:  "Print the JSON Schema of the strategy configuration format",
+				Action: strategySchema,
+			},
+		},
+	}
+}
+
+// strategyChatID returns the chat the strategies belong to
+func strategyChatID(cCtx *cli.Context) (string, error) {
+	chatID := chatIDValue(cCtx)
+	if chatID == "" {
+		return "", fmt.Errorf("chat-id is required")
+	}
+	return chatID, nil
+}
+
+// printStrategyRisk prints a strategy's risk caps
+func printStrategyRisk(out *output, risk StrategyRiskCaps) {
+	out.Printf("  daily loss cap %.2f%%, min confidence %.2f, max position %.2f%%, scan every %ds, AI usage %s\n",
+		risk.DailyLossPct, risk.MinConfidence, risk.MaxPositionPct, risk.ScanIntervalSeconds, risk.AIUsage)
+}
+
+// printStrategyFindings prints validation errors and warnings
+func printStrategyFindings(out *output, validation StrategyValidation) {
+	for _, problem := range validation.Errors {
+		out.Printf("  ✗ %s\n", problem)
+	}
+	for _, warning := range validation.Warnings {
+		out.Printf("  ! %s\n", warning)
+	}
+}
+
+// importStrategy validates a strategy file and installs it unless --dry-run
+// is set
+func importStrategy(cCtx *cli.Context) error {
+	path := cCtx.Args().First()
+	if path == "" {
+		return fmt.Errorf("a strategy file is required, for example: neuratrade strategy import night.yaml --chat-id 123")
+	}
+	document, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read strategy file: %w", err)
+	}
+	dryRun := cCtx.Bool("dry-run")
+	chatID := chatIDValue(cCtx)
+	if !dryRun && chatID == "" {
+		return fmt.Errorf("chat-id is required unless --dry-run is set")
+	}
+	out := newOutput(cCtx)
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	response, err := client.ValidateStrategyConfig(&ValidateStrategyRequest{Document: string(document)})
+	if err != nil {
+		return fmt.Errorf("failed to validate strategy: %w", err)
+	}
+	validation := response.Data
+	if !validation.Valid || dryRun {
+		if err := out.Render(validation, func() {
+			if validation.Valid {
+				out.Printf("✅ %s is a valid strategy configuration\n", path)
+			} else {
+				out.Printf("❌ %s is not a valid strategy configuration\n", path)
+			}
+			printStrategyFindings(out, validation)
+		}); err != nil {
+			return err
+		}
+		if !validation.Valid {
+			return fmt.Errorf("strategy configuration is invalid")
+		}
+		return nil
+	}
+
+	installed, err := client.InstallStrategyConfig(&InstallStrategyRequest{ChatID: chatID, Document: string(document)})
+	if err != nil {
+		return fmt.Errorf("failed to install strategy: %w", err)
+	}
+	result := installed.Data
+	return out.Render(result, func() {
+		config := result.Strategy.Config
+		out.Printf("✅ Strategy %s (%s) installed\n", config.Name, config.Strategy)
+		printStrategyRisk(out, config.Risk)
+		printStrategyFindings(out, StrategyValidation{Warnings: result.Warnings})
+		out.Printf("Trade with it using: neuratrade autonomous profile select %s --chat-id %s\n", config.Name, chatID)
+	})
+}
+
+// exportStrategy prints or writes a shareable strategy document
+func exportStrategy(cCtx *cli.Context) error {
+	chatID, err := strategyChatID(cCtx)
+	if err != nil {
+		return err
+	}
+	name := cCtx.Args().First()
+	if name == "" {
+		return fmt.Errorf("a strategy or profile name is required (see neuratrade strategy list)")
+	}
+	out := newOutput(cCtx)
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	response, err := client.ExportStrategyConfig(name, chatID, cCtx.String("format"))
+	if err != nil {
+		return fmt.Errorf("failed to export strategy: %w", err)
+	}
+
+	exported := response.Data
+	if path := cCtx.String("output"); path != "" {
+		if err := os.WriteFile(path, []byte(exported.Document), 0644); err != nil {
+			return fmt.Errorf("failed to write strategy file: %w", err)
+		}
+		return out.Render(exported, func() {
+			out.Printf("✅ Strategy %s written to %s\n", exported.Name, path)
+		})
+	}
+	return out.Render(exported, func() {
+		out.Printf("%s", exported.Document)
+	})
+}
+
+// listStrategies prints the chat's installed strategies
+func listStrategies(cCtx *cli.Context) error {
+	chatID, err := strategyChatID(cCtx)
+	if err != nil {
+		return err
+	}
+	out := newOutput(cCtx)
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	response, err := client.ListStrategyConfigs(chatID)
+	if err != nil {
+		return fmt.Errorf("failed to list strategies: %w", err)
+	}
+
+	list := response.Data
+	return out.Render(list, func() {
+		if len(list.Strategies) == 0 {
+			out.Println("No strategies installed. Import one with: neuratrade strategy import <file>")
+			return
+		}
+		for _, strategy := range list.Strategies {
+			config := strategy.Config
+			details := []string{config.Strategy}
+			if config.Version != "" {
+				details = append(details, "v"+strings.TrimPrefix(config.Version, "v"))
+			}
+			if config.Author != "" {
+				details = append(details, "by "+config.Author)
+			}
+			out.Printf("%s (%s)  %s\n", config.Name, strings.Join(details, ", "), config.Description)
+			printStrategyRisk(out, config.Risk)
+		}
+	})
+}
+
+// deleteStrategy uninstalls a strategy
+func deleteStrategy(cCtx *cli.Context) error {
+	chatID, err := strategyChatID(cCtx)
+	if err != nil {
+		return err
+	}
+	name := cCtx.Args().First()
+	if name == "" {
+		return fmt.Errorf("a strategy name is required (see neuratrade strategy list)")
+	}
+	out := newOutput(cCtx)
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	response, err := client.UninstallStrategyConfig(name, chatID)
+	if err != nil {
+		return fmt.Errorf("failed to delete strategy: %w", err)
+	}
+
+	return out.Render(response.Data, func() {
+		out.Printf("🗑️  Strategy %s uninstalled\n", name)
+	})
+}
+
+// strategySchema prints the JSON Schema strategy files are validated against
+func strategySchema(cCtx *cli.Context) error {
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	schema, err := client.GetStrategyConfigSchema()
+	if err != nil {
+		return fmt.Errorf("failed to fetch strategy schema: %w", err)
+	}
+	out := newOutput(cCtx)
+	return out.Render(*schema, func() {
+		encoded, err := json.MarshalIndent(*schema, "", "  ")
+		if err != nil {
+			out.Printf("failed to encode schema: %v\n", err)
+			return
+		}
+		out.Printf("%s\n", encoded)
+	})
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+// stressTestCommand builds the "stress-test" command: the strategy's recent
+// signals executed with added latency, partial fills and higher fees
+func stressTestCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "stress-test",
+		Usage:  "Compare baseline PnL with PnL under added latency, partial fills and higher fees",
+		Action: runStressTest,
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "exchange", Usage: "Exchange to replay market data from (server default: binance)"},
+			&cli.StringFlag{Name: "symbol", Usage: "Symbol to replay (server default: BTC/USDT)"},
+			&cli.StringFlag{Name: "timeframe", Usage: "Candle timeframe (server default: 5m)"},
+			&cli.IntFlag{Name: "candles", Usage: "Number of recent candles to replay (server default: 500)"},
+			&cli.Int64Flag{Name: "latency-ms", Usage: "Run a single custom scenario with this order latency"},
+			&cli.Float64Flag{Name: "fill-ratio", Usage: "Custom scenario: fraction of each entry that fills, e.g. 0.5"},
+			&cli.Float64Flag{Name: "fee-multiplier", Usage: "Custom scenario: multiplier applied to the fee rate, e.g. 3"},
+		},
+	}
+}
+
+// runStressTest runs the backend stress test and prints the robustness report
+func runStressTest(cCtx *cli.Context) error {
+	out := newOutput(cCtx)
+
+	req := &RunStressTestRequest{
+		Exchange:  cCtx.String("exchange"),
+		Symbol:    cCtx.String("symbol"),
+		Timeframe: cCtx.String("timeframe"),
+		Candles:   cCtx.Int("candles"),
+	}
+	if cCtx.IsSet("latency-ms") || cCtx.IsSet("fill-ratio") || cCtx.IsSet("fee-multiplier") {
+		req.Scenarios = []StressScenario{{
+			Name:          "custom",
+			LatencyMs:     cCtx.Int64("latency-ms"),
+			FillRatio:     cCtx.Float64("fill-ratio"),
+			FeeMultiplier: cCtx.Float64("fee-multiplier"),
+		}}
+	}
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	response, err := client.RunStressTest(req)
+	if err != nil {
+		return fmt.Errorf("failed to run stress test: %w", err)
+	}
+
+	report := response.Data
+	return out.Render(report, func() {
+		out.Printf("🧪 Stress test on %s %s (%s): %d candles, %d signals\n", report.Exchange, report.Symbol, report.Timeframe, report.Candles, report.Signals)
+		out.Printf("  %-14s %6s %8s %12s %10s %12s %10s\n", "Scenario", "Trades", "Win %", "Net PnL", "Fees", "Change", "Retained")
+		base := report.Baseline
+		out.Printf("  %-14s %6d %7.1f%% %12s %10s %12s %10s\n", "baseline", base.Trades, base.WinRate*100, base.NetPnl, base.Fees, "-", "-")
+		for _, result := range report.Scenarios {
+			retained := "-"
+			if result.Retained != nil {
+				retained = fmt.Sprintf("%.0f%%", *result.Retained*100)
+			}
+			out.Printf("  %-14s %6d %7.1f%% %12s %10s %12s %10s\n", result.Scenario.Name, result.Trades, result.WinRate*100, result.NetPnl, result.Fees, result.PnlChange, retained)
+		}
+		if report.Robust {
+			out.Println("✅ Robust: every scenario stayed profitable")
+		} else {
+			out.Println("⚠️  Fragile: at least one scenario lost money")
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/urfave/cli/v2"
+)
+
+// usersCommand builds the "users" command for data deletion requests
+func usersCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "users",
+		Usage: "Handle user data requests",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "erase-data",
+				Usage:     "Remove or anonymize a user's personal data, keeping anonymized trading statistics",
+				ArgsUsage: "<user-id>",
+				Action:    runEraseUserData,
+				Flags: []cli.Flag{
+					&cli.BoolFlag{Name: "dry-run", Usage: "Only list what would be removed"},
+					&cli.BoolFlag{Name: "yes", Usage: "Confirm the erasure; it cannot be undone"},
+				},
+			},
+		},
+	}
+}
+
+// runEraseUserData erases a user's data, or reports what would be erased
+func runEraseUserData(cCtx *cli.Context) error {
+	out := newOutput(cCtx)
+
+	userID := cCtx.Args().First()
+	if userID == "" {
+		return cli.Exit("Error: user id is required", 1)
+	}
+	dryRun := cCtx.Bool("dry-run")
+	if !dryRun && !cCtx.Bool("yes") {
+		return cli.Exit("Error: erasure cannot be undone; review it with --dry-run, then confirm with --yes", 1)
+	}
+
+	client := NewAPIClient(getBaseURL(), getAPIKey())
+	response, err := client.EraseUserData(userID, strconv.FormatBool(dryRun))
+	if err != nil {
+		return fmt.Errorf("failed to erase user data: %w", err)
+	}
+
+	report := response.Data
+	return out.Render(report, func() {
+		if report.DryRun {
+			out.Printf("🔍 Dry run: %d records of user %s would be removed or anonymized\n", report.Removed, report.UserID)
+		} else {
+			out.Printf("🗑️  Removed or anonymized %d records of user %s\n", report.Removed, report.UserID)
+		}
+		for _, action := range report.Actions {
+			out.Printf("  %-20s %-10s %-40s %d\n", action.Category, action.Action, action.Target, action.Count)
+			for _, id := range action.Ids {
+				out.Printf("      %s\n", id)
+			}
+		}
+		if len(report.Preserved) > 0 {
+			out.Println("Kept for anonymized statistics:")
+			for _, kept := range report.Preserved {
+				out.Printf("  %-40s %d\n", kept.Target, kept.Count)
+			}
+		}
+	})
+}
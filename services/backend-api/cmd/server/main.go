@@ -44,6 +44,12 @@ func main() {
 				os.Exit(1)
 			}
 			return
+		case "openapi":
+			if err := writeOpenAPISpec(os.Stdout); err != nil {
+				fmt.Fprintf(os.Stderr, "OpenAPI generation failed: %v\n", err)
+				os.Exit(1)
+			}
+			return
 		}
 	}
 
@@ -57,6 +63,9 @@ func main() {
 // It loads configuration, initializes telemetry, databases, services, and the HTTP server.
 // It also manages graceful shutdown upon receiving termination signals.
 //
+// With the --demo flag, CCXT responses are replayed from recorded fixtures so
+// the server runs without exchange connectivity.
+//
 // Returns:
 //   - An error if initialization fails at any critical step.
 func run() error {
@@ -65,6 +74,10 @@ func run() error {
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
+	demo := hasFlag(os.Args[1:], "--demo")
+	if demo {
+		cfg.CCXT.FixtureMode = string(ccxt.FixtureModeReplay)
+	}
 
 	// Initialize Sentry for observability
 	if err := observability.InitSentry(cfg.Sentry, cfg.Telemetry.ServiceVersion, cfg.Environment); err != nil {
@@ -82,6 +95,9 @@ func run() error {
 	logrusLogger.SetFormatter(&zaplogrus.JSONFormatter{})
 
 	warnLegacyHandlersPath(logrusLogger)
+	if demo {
+		logrusLogger.WithField("fixture_dir", cfg.CCXT.FixtureDir).Info("Demo mode: replaying recorded CCXT fixtures instead of calling the CCXT service")
+	}
 
 	// Initialize database
 	driver := strings.ToLower(strings.TrimSpace(cfg.Database.Driver))
@@ -139,7 +155,7 @@ func run() error {
 		blacklistCache = cache.NewRedisBlacklistCache(redisClient.Client, blacklistRepo)
 	} else {
 		// Fallback to in-memory cache if Redis is not available
-		blacklistCache = cache.NewInMemoryBlacklistCache()
+		blacklistCache = cache.NewInMemoryBlacklistCacheWithLimit(cfg.Blacklist.MaxEntries)
 	}
 
 	// Initialize CCXT service with blacklist cache
@@ -166,7 +182,7 @@ func run() error {
 	}
 
 	// Initialize collector service
-	collectorService := services.NewCollectorService(db, ccxtService, cfg, getRedisClient(), blacklistCache)
+	collectorService := services.NewCollectorService(database.ForWorkload(db, database.WorkloadCollector), ccxtService, cfg, getRedisClient(), blacklistCache)
 
 	// Verify database has required seed data before starting collection
 	if err := collectorService.VerifyDatabaseSeeding(); err != nil {
@@ -174,6 +190,22 @@ func run() error {
 		// Don't fail startup, but log warning - exchanges may be created dynamically
 	}
 
+	// Replicas sharing Redis split collection by exchange/symbol partition
+	if cfg.MarketData.PartitionEnabled && getRedisClient() != nil {
+		leaseTTL, _ := time.ParseDuration(cfg.MarketData.PartitionLeaseTTL)
+		partitioner := services.NewCollectorPartitioner(getRedisClient(), services.CollectorPartitionConfig{
+			Shards:   cfg.MarketData.PartitionShards,
+			LeaseTTL: leaseTTL,
+		})
+		partitioner.Start(ctx)
+		defer partitioner.Stop()
+		collectorService.SetPartitioner(partitioner)
+		logger.WithFields(map[string]interface{}{
+			"replica": partitioner.Config().ReplicaID,
+			"shards":  partitioner.Config().Shards,
+		}).Info("Collector partitioning enabled")
+	}
+
 	if err := collectorService.Start(); err != nil {
 		logger.WithError(err).Fatal("Failed to start collector service")
 	}
@@ -226,6 +258,11 @@ func run() error {
 
 		// Initialize regular arbitrage service
 		arbitrageService := services.NewArbitrageService(db, cfg, arbitrageCalculator)
+		if cfg.Arbitrage.LifecycleEnabled {
+			arbitrageService.SetLifecycleService(services.NewOpportunityLifecycleService(db, services.OpportunityLifecycleConfig{
+				Retention: time.Duration(cfg.Arbitrage.LifecycleRetentionHours) * time.Hour,
+			}))
+		}
 		if err := arbitrageService.Start(); err != nil {
 			logger.WithError(err).Fatal("Failed to start arbitrage service")
 		}
@@ -263,15 +300,6 @@ func run() error {
 
 	notificationService := services.NewNotificationService(db, redisClient, cfg.Telegram.ServiceURL, cfg.Telegram.GrpcAddress, cfg.Telegram.AdminAPIKey)
 
-	positionTrackerConfig := services.DefaultPositionTrackerConfig()
-	var redisClientHandle *redis.Client
-	if redisClient != nil {
-		redisClientHandle = redisClient.Client
-	}
-	positionTracker := services.NewPositionTracker(positionTrackerConfig, ccxtService, redisClientHandle, logrusLogger)
-	positionTracker.Start()
-	defer positionTracker.Stop()
-
 	stopLossConfig := services.DefaultStopLossConfig()
 	stopLossService := services.NewStopLossService(stopLossConfig, ccxtService, logrusLogger, nil)
 
@@ -323,6 +351,22 @@ func run() error {
 		getLogger("circuit_breaker"),
 	)
 
+	// User-defined price and indicator alerts are evaluated by the signal
+	// processor each cycle, or on their own loop when it is disabled.
+	var customAlerts *services.CustomAlertService
+	if cfg.CustomAlerts.Enabled {
+		interval, err := time.ParseDuration(cfg.CustomAlerts.Interval)
+		if err != nil {
+			logger.WithError(err).Warn("Invalid custom alerts interval, using default")
+		}
+		customAlerts = services.NewCustomAlertService(db, ccxtService, services.CustomAlertConfig{
+			DefaultExchange:  cfg.CustomAlerts.DefaultExchange,
+			MaxAlertsPerUser: cfg.CustomAlerts.MaxPerUser,
+			Interval:         interval,
+		})
+		customAlerts.SetMessenger(notificationService)
+	}
+
 	// Initialize signal processor only when AI signals mode is enabled.
 	if cfg.Features.EnableAISignals {
 		signalProcessor := services.NewSignalProcessor(
@@ -335,6 +379,16 @@ func run() error {
 			collectorService,
 			signalProcessorCircuitBreaker,
 		)
+		// Only the active symbol universe is processed; the universe itself is
+		// refreshed by the watchlist service started with the API routes.
+		if redisClient != nil {
+			signalProcessor.SetSymbolUniverse(services.NewWatchlistService(db, redisClient.Client, services.WatchlistConfig{}))
+		}
+		// Pairs breaching their freshness SLA are skipped until data recovers
+		signalProcessor.SetFreshness(collectorService.Freshness())
+		if customAlerts != nil {
+			signalProcessor.SetCustomAlerts(customAlerts)
+		}
 
 		if err := signalProcessor.Start(); err != nil {
 			logger.WithError(err).Fatal("Failed to start signal processor")
@@ -347,6 +401,12 @@ func run() error {
 		logger.Info("Signal processor enabled")
 	} else {
 		logger.Info("Signal processor disabled in scalping-first mode")
+		if customAlerts != nil {
+			if err := customAlerts.Start(context.Background()); err != nil {
+				logger.WithError(err).Error("Failed to start custom alerts")
+			}
+			defer customAlerts.Stop()
+		}
 	}
 
 	logger.Info("AI trading components ready for integration")
@@ -361,6 +421,11 @@ func run() error {
 
 	// Setup Gin router
 	router := gin.New()
+	// Gin trusts X-Forwarded-For from any peer by default; only configured
+	// proxies may set the client IP that quotas are keyed on.
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return fmt.Errorf("invalid trusted proxies: %w", err)
+	}
 	router.Use(gin.Logger())
 	if cfg.Sentry.Enabled && cfg.Sentry.DSN != "" {
 		router.Use(sentrygin.New(sentrygin.Options{
@@ -371,8 +436,14 @@ func run() error {
 	}
 	router.Use(gin.Recovery())
 
+	// Graceful shutdown coordinator for rolling deploys
+	drain := services.NewShutdownDrain(services.ShutdownDrainConfig{
+		Budget:         time.Duration(cfg.Server.DrainBudgetSeconds) * time.Second,
+		ReadinessDelay: time.Duration(cfg.Server.ReadinessDelaySeconds) * time.Second,
+	})
+
 	// Setup routes and get cleanup function
-	cleanupRoutes := api.SetupRoutes(router, db, redisClient, ccxtService, collectorService, cleanupService, cacheAnalyticsService, signalAggregator, analyticsService, &cfg.Telegram, &cfg.AI, &cfg.Features, authMiddleware, walletValidator)
+	cleanupRoutes := api.SetupRoutes(router, db, redisClient, ccxtService, collectorService, cleanupService, cacheAnalyticsService, signalAggregator, analyticsService, &cfg.Telegram, &cfg.AI, &cfg.Features, authMiddleware, walletValidator, drain, services.NewConfigService(cfg))
 	defer cleanupRoutes()
 
 	// Create HTTP server with security timeouts
@@ -403,21 +474,32 @@ func run() error {
 	<-quit
 	logger.LogShutdown("celebrum-backend-api", "signal received")
 
-	// Cancel all service contexts to trigger graceful shutdown
-	cancel()
-
-	// Give outstanding requests a deadline for completion
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer shutdownCancel()
-
-	if err := srv.Shutdown(shutdownCtx); err != nil {
+	// Drain within the budget: readiness turns false, the listener closes
+	// after the readiness delay, in-flight requests, quest runs and order
+	// submissions finish and quest state is checkpointed
+	if err := drain.Drain(context.Background(), srv.Shutdown); err != nil {
+		// Exit without the clean-shutdown cleanup, so the next start
+		// treats this run as an unclean shutdown
 		logger.WithError(err).Fatal("Server forced to shutdown")
 	}
 
+	// Cancel all service contexts to stop background services
+	cancel()
+
 	logger.Info("Server exited gracefully")
 	return nil
 }
 
+// hasFlag reports whether a command-line flag is present in args.
+func hasFlag(args []string, flag string) bool {
+	for _, arg := range args {
+		if arg == flag {
+			return true
+		}
+	}
+	return false
+}
+
 func warnLegacyHandlersPath(logger *zaplogrus.Logger) {
 	if logger == nil {
 		return
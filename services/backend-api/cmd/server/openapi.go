@@ -0,0 +1,16 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/irfndi/neuratrade/internal/api"
+)
+
+// writeOpenAPISpec writes the documented operations as an OpenAPI document.
+// Used by `go generate` in the CLI to produce its typed client.
+func writeOpenAPISpec(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(api.NewOpenAPIRegistry().Build(nil))
+}
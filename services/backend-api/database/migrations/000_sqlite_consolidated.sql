@@ -272,6 +272,7 @@ CREATE TABLE IF NOT EXISTS notification_dead_letters (
     chat_id TEXT NOT NULL,
     message_type TEXT NOT NULL,
     message_content TEXT NOT NULL,
+    priority TEXT NOT NULL DEFAULT 'normal',
     error_code TEXT,
     error_message TEXT,
     attempts INTEGER DEFAULT 1,
@@ -482,6 +483,85 @@ CREATE TABLE IF NOT EXISTS kv_store (
 CREATE INDEX IF NOT EXISTS idx_kv_store_key ON kv_store(key);
 CREATE INDEX IF NOT EXISTS idx_kv_store_expires ON kv_store(expires_at) WHERE expires_at IS NOT NULL;
 
+-- Decision audits table (trade replay and post-mortems)
+CREATE TABLE IF NOT EXISTS decision_audits (
+    id TEXT PRIMARY KEY,
+    source TEXT NOT NULL,
+    exchange TEXT NOT NULL DEFAULT '',
+    symbol TEXT NOT NULL DEFAULT '',
+    action TEXT NOT NULL DEFAULT '',
+    confidence REAL NOT NULL DEFAULT 0,
+    reasoning TEXT NOT NULL DEFAULT '',
+    price REAL NOT NULL DEFAULT 0,
+    market_snapshot TEXT,
+    prompt TEXT NOT NULL DEFAULT '',
+    model TEXT NOT NULL DEFAULT '',
+    raw_output TEXT NOT NULL DEFAULT '',
+    decision TEXT,
+    order_details TEXT,
+    outcome TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_decision_audits_source ON decision_audits(source);
+CREATE INDEX IF NOT EXISTS idx_decision_audits_symbol ON decision_audits(symbol);
+CREATE INDEX IF NOT EXISTS idx_decision_audits_created ON decision_audits(created_at DESC);
+
+-- Derivatives positioning table (open interest and long/short ratios)
+CREATE TABLE IF NOT EXISTS derivatives_positioning (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    exchange TEXT NOT NULL,
+    symbol TEXT NOT NULL,
+    open_interest REAL NOT NULL DEFAULT 0,
+    open_interest_value REAL NOT NULL DEFAULT 0,
+    long_short_ratio REAL NOT NULL DEFAULT 0,
+    long_account REAL NOT NULL DEFAULT 0,
+    short_account REAL NOT NULL DEFAULT 0,
+    timestamp DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (exchange, symbol, timestamp)
+);
+
+CREATE INDEX IF NOT EXISTS idx_derivatives_positioning_lookup ON derivatives_positioning(exchange, symbol, timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_derivatives_positioning_timestamp ON derivatives_positioning(timestamp DESC);
+
+-- KPI samples table (internal KPI time series, one row per metric and bucket)
+CREATE TABLE IF NOT EXISTS kpi_samples (
+    metric TEXT NOT NULL,
+    bucket_start DATETIME NOT NULL,
+    sample_count INTEGER NOT NULL DEFAULT 0,
+    value_sum REAL NOT NULL DEFAULT 0,
+    min_value REAL NOT NULL DEFAULT 0,
+    max_value REAL NOT NULL DEFAULT 0,
+    PRIMARY KEY (metric, bucket_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_kpi_samples_bucket ON kpi_samples(bucket_start);
+
+-- Opportunity lifecycles table (arbitrage opportunities from detection to execution or expiry)
+CREATE TABLE IF NOT EXISTS opportunity_lifecycles (
+    id TEXT PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    buy_exchange TEXT NOT NULL,
+    sell_exchange TEXT NOT NULL,
+    state TEXT NOT NULL DEFAULT 'detected',
+    detected_at DATETIME NOT NULL,
+    last_seen_at DATETIME NOT NULL,
+    notified_at DATETIME,
+    executed_at DATETIME,
+    closed_at DATETIME,
+    duration_seconds REAL,
+    detected_profit_pct REAL NOT NULL DEFAULT 0,
+    peak_profit_pct REAL NOT NULL DEFAULT 0,
+    last_profit_pct REAL NOT NULL DEFAULT 0,
+    realized_profit_pct REAL,
+    capture_rate REAL
+);
+
+CREATE INDEX IF NOT EXISTS idx_opportunity_lifecycles_route ON opportunity_lifecycles(symbol, buy_exchange, sell_exchange) WHERE closed_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_opportunity_lifecycles_detected_at ON opportunity_lifecycles(detected_at DESC);
+
 -- Futures table
 CREATE TABLE IF NOT EXISTS futures (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -0,0 +1,18 @@
+-- Allow externally sourced signals in aggregated_signals
+-- Signals submitted through /api/v1/signals/ingest are stored with signal_type
+-- 'external' so they rank alongside internally generated signals.
+
+ALTER TABLE aggregated_signals DROP CONSTRAINT IF EXISTS aggregated_signals_signal_type_check;
+ALTER TABLE aggregated_signals
+    ADD CONSTRAINT aggregated_signals_signal_type_check
+    CHECK (signal_type IN ('arbitrage', 'technical', 'external'));
+
+-- Add metadata table entry
+INSERT INTO schema_metadata (key, value, description)
+VALUES ('migration_070_completed', 'true', 'Migration 070: Allow external aggregated signals')
+ON CONFLICT (key) DO NOTHING;
+
+-- Add to migration log
+INSERT INTO migration_log (migration_number, migration_name, applied_at)
+VALUES (70, '070_allow_external_aggregated_signals.sql', NOW())
+ON CONFLICT (migration_number) DO NOTHING;
@@ -0,0 +1,38 @@
+-- Create decision_audits table for trade replay and decision post-mortems
+-- Each AI or deterministic decision is stored with the market snapshot it was
+-- based on, the redacted prompt and raw model output, the resulting order and
+-- its outcome. Served by GET /api/v1/decisions/:id.
+
+CREATE TABLE IF NOT EXISTS decision_audits (
+    id TEXT PRIMARY KEY,
+    source TEXT NOT NULL, -- 'ai_scalping', 'external_signal'
+    exchange TEXT NOT NULL DEFAULT '',
+    symbol TEXT NOT NULL DEFAULT '',
+    action TEXT NOT NULL DEFAULT '',
+    confidence DOUBLE PRECISION NOT NULL DEFAULT 0,
+    reasoning TEXT NOT NULL DEFAULT '',
+    price DOUBLE PRECISION NOT NULL DEFAULT 0,
+    market_snapshot JSONB,
+    prompt TEXT NOT NULL DEFAULT '',
+    model TEXT NOT NULL DEFAULT '',
+    raw_output TEXT NOT NULL DEFAULT '',
+    decision JSONB,
+    order_details JSONB,
+    outcome JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+    updated_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc')
+);
+
+CREATE INDEX IF NOT EXISTS idx_decision_audits_source ON decision_audits(source);
+CREATE INDEX IF NOT EXISTS idx_decision_audits_symbol ON decision_audits(symbol);
+CREATE INDEX IF NOT EXISTS idx_decision_audits_created ON decision_audits(created_at DESC);
+
+-- Add metadata table entry
+INSERT INTO schema_metadata (key, value, description)
+VALUES ('migration_071_completed', 'true', 'Migration 071: Create decision audits')
+ON CONFLICT (key) DO NOTHING;
+
+-- Add to migration log
+INSERT INTO migration_log (migration_number, migration_name, applied_at)
+VALUES (71, '071_create_decision_audits.sql', NOW())
+ON CONFLICT (migration_number) DO NOTHING;
@@ -0,0 +1,30 @@
+-- Create derivatives_positioning table for open interest and long/short ratios
+-- The collector samples perpetual swaps of tracked symbols on exchanges that
+-- expose the data. Used as inputs to the signal aggregator and AI prompts.
+
+CREATE TABLE IF NOT EXISTS derivatives_positioning (
+    id BIGSERIAL PRIMARY KEY,
+    exchange TEXT NOT NULL,
+    symbol TEXT NOT NULL,
+    open_interest DOUBLE PRECISION NOT NULL DEFAULT 0, -- contracts or base currency
+    open_interest_value DOUBLE PRECISION NOT NULL DEFAULT 0, -- quote currency
+    long_short_ratio DOUBLE PRECISION NOT NULL DEFAULT 0, -- long accounts / short accounts
+    long_account DOUBLE PRECISION NOT NULL DEFAULT 0,
+    short_account DOUBLE PRECISION NOT NULL DEFAULT 0,
+    timestamp TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+    UNIQUE (exchange, symbol, timestamp)
+);
+
+CREATE INDEX IF NOT EXISTS idx_derivatives_positioning_lookup ON derivatives_positioning(exchange, symbol, timestamp DESC);
+CREATE INDEX IF NOT EXISTS idx_derivatives_positioning_timestamp ON derivatives_positioning(timestamp DESC);
+
+-- Add metadata table entry
+INSERT INTO schema_metadata (key, value, description)
+VALUES ('migration_072_completed', 'true', 'Migration 072: Create derivatives positioning')
+ON CONFLICT (key) DO NOTHING;
+
+-- Add to migration log
+INSERT INTO migration_log (migration_number, migration_name, applied_at)
+VALUES (72, '072_create_derivatives_positioning.sql', NOW())
+ON CONFLICT (migration_number) DO NOTHING;
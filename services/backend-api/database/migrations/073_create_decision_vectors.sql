@@ -0,0 +1,48 @@
+-- Create decision_vectors table for similarity search of past decisions
+-- Each decision's market context is embedded (256 dimensions, matching
+-- DefaultDecisionEmbeddingDims) and stored with its realized outcome so that
+-- AI prompts can include what happened in similar past situations.
+-- Requires the pgvector extension; servers without it skip the table and the
+-- backend runs without similar-decision lookups.
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = 'vector') THEN
+        CREATE EXTENSION IF NOT EXISTS vector;
+
+        CREATE TABLE IF NOT EXISTS decision_vectors (
+            id BIGSERIAL PRIMARY KEY,
+            decision_id TEXT NOT NULL UNIQUE,
+            exchange TEXT NOT NULL DEFAULT '',
+            symbol TEXT NOT NULL,
+            action TEXT NOT NULL,
+            confidence DOUBLE PRECISION NOT NULL DEFAULT 0,
+            reasoning TEXT NOT NULL DEFAULT '',
+            outcome_status TEXT NOT NULL DEFAULT '', -- empty until the outcome is realized
+            return_pct DOUBLE PRECISION NOT NULL DEFAULT 0,
+            pnl DOUBLE PRECISION,
+            outcome_note TEXT NOT NULL DEFAULT '',
+            embedding vector(256) NOT NULL,
+            decided_at TIMESTAMP NOT NULL,
+            updated_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc')
+        );
+
+        -- Approximate nearest neighbours over decisions with an outcome, the
+        -- only ones searched
+        CREATE INDEX IF NOT EXISTS idx_decision_vectors_embedding ON decision_vectors
+            USING hnsw (embedding vector_cosine_ops) WHERE outcome_status <> '';
+        CREATE INDEX IF NOT EXISTS idx_decision_vectors_decided ON decision_vectors(decided_at DESC);
+    ELSE
+        RAISE NOTICE 'pgvector is not installed, skipping decision_vectors';
+    END IF;
+END $$;
+
+-- Add metadata table entry
+INSERT INTO schema_metadata (key, value, description)
+VALUES ('migration_073_completed', 'true', 'Migration 073: Create decision vectors')
+ON CONFLICT (key) DO NOTHING;
+
+-- Add to migration log
+INSERT INTO migration_log (migration_number, migration_name, applied_at)
+VALUES (73, '073_create_decision_vectors.sql', NOW())
+ON CONFLICT (migration_number) DO NOTHING;
@@ -0,0 +1,39 @@
+-- Create embedded_documents table for news items and operator notes
+-- The embedding pipeline stores one 256-dimension vector per document, keyed
+-- by kind ('news', 'note') and a source ID such as the article URL, for
+-- retrieval-augmented prompts.
+-- Requires the pgvector extension, like migration 073.
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = 'vector') THEN
+        CREATE EXTENSION IF NOT EXISTS vector;
+
+        CREATE TABLE IF NOT EXISTS embedded_documents (
+            id BIGSERIAL PRIMARY KEY,
+            kind TEXT NOT NULL,
+            source_id TEXT NOT NULL,
+            content TEXT NOT NULL,
+            embedding vector(256) NOT NULL,
+            created_at TIMESTAMP NOT NULL,
+            updated_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+            UNIQUE (kind, source_id)
+        );
+
+        CREATE INDEX IF NOT EXISTS idx_embedded_documents_embedding ON embedded_documents
+            USING hnsw (embedding vector_cosine_ops);
+        CREATE INDEX IF NOT EXISTS idx_embedded_documents_kind ON embedded_documents(kind, created_at DESC);
+    ELSE
+        RAISE NOTICE 'pgvector is not installed, skipping embedded_documents';
+    END IF;
+END $$;
+
+-- Add metadata table entry
+INSERT INTO schema_metadata (key, value, description)
+VALUES ('migration_074_completed', 'true', 'Migration 074: Create embedded documents')
+ON CONFLICT (key) DO NOTHING;
+
+-- Add to migration log
+INSERT INTO migration_log (migration_number, migration_name, applied_at)
+VALUES (74, '074_create_embedded_documents.sql', NOW())
+ON CONFLICT (migration_number) DO NOTHING;
@@ -0,0 +1,26 @@
+-- Create kpi_samples table for internal KPI history
+-- Samples of cycle durations, opportunities found, orders placed and
+-- notification latency are aggregated in memory and stored as one row per
+-- metric and time bucket. Served by GET /api/v1/admin/kpis.
+
+CREATE TABLE IF NOT EXISTS kpi_samples (
+    metric TEXT NOT NULL,
+    bucket_start TIMESTAMP NOT NULL,
+    sample_count BIGINT NOT NULL DEFAULT 0,
+    value_sum DOUBLE PRECISION NOT NULL DEFAULT 0,
+    min_value DOUBLE PRECISION NOT NULL DEFAULT 0,
+    max_value DOUBLE PRECISION NOT NULL DEFAULT 0,
+    PRIMARY KEY (metric, bucket_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_kpi_samples_bucket ON kpi_samples(bucket_start);
+
+-- Add metadata table entry
+INSERT INTO schema_metadata (key, value, description)
+VALUES ('migration_075_completed', 'true', 'Migration 075: Create KPI samples')
+ON CONFLICT (key) DO NOTHING;
+
+-- Add to migration log
+INSERT INTO migration_log (migration_number, migration_name, applied_at)
+VALUES (75, '075_create_kpi_samples.sql', NOW())
+ON CONFLICT (migration_number) DO NOTHING;
@@ -0,0 +1,37 @@
+-- Create opportunity_lifecycles table for arbitrage opportunity history
+-- One row per continuous sighting of a symbol's buy/sell route, moving from
+-- detected to notified and closing as executed or expired, with how long the
+-- edge lasted and how much of it an execution captured. Served by
+-- GET /api/v1/opportunities/history.
+
+CREATE TABLE IF NOT EXISTS opportunity_lifecycles (
+    id UUID PRIMARY KEY,
+    symbol VARCHAR(50) NOT NULL,
+    buy_exchange VARCHAR(50) NOT NULL,
+    sell_exchange VARCHAR(50) NOT NULL,
+    state VARCHAR(20) NOT NULL DEFAULT 'detected',
+    detected_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    notified_at TIMESTAMP WITH TIME ZONE,
+    executed_at TIMESTAMP WITH TIME ZONE,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    duration_seconds DOUBLE PRECISION,
+    detected_profit_pct DOUBLE PRECISION NOT NULL DEFAULT 0,
+    peak_profit_pct DOUBLE PRECISION NOT NULL DEFAULT 0,
+    last_profit_pct DOUBLE PRECISION NOT NULL DEFAULT 0,
+    realized_profit_pct DOUBLE PRECISION,
+    capture_rate DOUBLE PRECISION
+);
+
+CREATE INDEX IF NOT EXISTS idx_opportunity_lifecycles_route ON opportunity_lifecycles(symbol, buy_exchange, sell_exchange) WHERE closed_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_opportunity_lifecycles_detected_at ON opportunity_lifecycles(detected_at DESC);
+
+-- Add metadata table entry
+INSERT INTO schema_metadata (key, value, description)
+VALUES ('migration_076_completed', 'true', 'Migration 076: Create opportunity lifecycles')
+ON CONFLICT (key) DO NOTHING;
+
+-- Add to migration log
+INSERT INTO migration_log (migration_number, migration_name, applied_at)
+VALUES (76, '076_create_opportunity_lifecycles.sql', NOW())
+ON CONFLICT (migration_number) DO NOTHING;
@@ -0,0 +1,11 @@
+-- Add a priority to dead-lettered notifications
+-- Replays keep the priority the message was sent with, so a failed critical
+-- risk alert is retried ahead of other entries and still bypasses rate
+-- limiting and quiet hours. Values: critical, high, normal, low.
+
+ALTER TABLE notification_dead_letters
+    ADD COLUMN IF NOT EXISTS priority VARCHAR(20) NOT NULL DEFAULT 'normal';
+
+CREATE INDEX IF NOT EXISTS idx_dlq_priority_created_at
+    ON notification_dead_letters(priority, created_at)
+    WHERE status IN ('pending', 'retrying');
@@ -0,0 +1,37 @@
+-- Create audit_chain and audit_chain_anchors tables for the tamper-evident
+-- decision and trade log
+-- Every recorded decision (with its order) and every realized outcome is
+-- appended to a hash chain: each entry includes the hash of the previous one,
+-- so changing, removing or reordering an entry breaks every later link.
+-- Anchors are signed checkpoints of the chain head; rewriting the chain up to
+-- an anchor requires the signing key. Payloads are TEXT rather than JSONB so
+-- they are hashed byte for byte. Verified by GET /api/v1/admin/audit-chain/verify.
+
+CREATE TABLE IF NOT EXISTS audit_chain (
+    seq BIGINT PRIMARY KEY,
+    kind TEXT NOT NULL, -- 'decision', 'decision_outcome'
+    ref_id TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    prev_hash TEXT NOT NULL,
+    hash TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_chain_ref ON audit_chain(kind, ref_id);
+
+CREATE TABLE IF NOT EXISTS audit_chain_anchors (
+    seq BIGINT PRIMARY KEY,
+    hash TEXT NOT NULL,
+    signature TEXT NOT NULL DEFAULT '', -- empty when no signing key is configured
+    created_at TIMESTAMP NOT NULL
+);
+
+-- Add metadata table entry
+INSERT INTO schema_metadata (key, value, description)
+VALUES ('migration_078_completed', 'true', 'Migration 078: Create audit chain')
+ON CONFLICT (key) DO NOTHING;
+
+-- Add to migration log
+INSERT INTO migration_log (migration_number, migration_name, applied_at)
+VALUES (78, '078_create_audit_chain.sql', NOW())
+ON CONFLICT (migration_number) DO NOTHING;
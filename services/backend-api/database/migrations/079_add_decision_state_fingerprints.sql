@@ -0,0 +1,27 @@
+-- Add state fingerprints to decision_audits and create state_snapshots
+-- Every decision records the hash of the effective config and of the strategy
+-- parameters it was made under, next to its prompt version and model. The
+-- hashed settings are kept once per hash in state_snapshots so two
+-- fingerprints can be diffed key by key. Served by GET /api/v1/admin/state/diff
+-- and the state check of /doctor.
+
+ALTER TABLE decision_audits ADD COLUMN IF NOT EXISTS config_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE decision_audits ADD COLUMN IF NOT EXISTS strategy_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE decision_audits ADD COLUMN IF NOT EXISTS prompt_version TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS state_snapshots (
+    hash TEXT PRIMARY KEY,
+    kind TEXT NOT NULL, -- 'config', 'strategy'
+    settings JSONB NOT NULL, -- flattened key/value pairs, secrets redacted
+    created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc')
+);
+
+-- Add metadata table entry
+INSERT INTO schema_metadata (key, value, description)
+VALUES ('migration_079_completed', 'true', 'Migration 079: Add decision state fingerprints')
+ON CONFLICT (key) DO NOTHING;
+
+-- Add to migration log
+INSERT INTO migration_log (migration_number, migration_name, applied_at)
+VALUES (79, '079_add_decision_state_fingerprints.sql', NOW())
+ON CONFLICT (migration_number) DO NOTHING;
@@ -0,0 +1,23 @@
+-- Add user_id to embedded_documents
+-- Operator notes about a user carry the user's ID, so erasing the user's
+-- data removes exactly their notes instead of matching note text.
+-- embedded_documents only exists where pgvector is installed (migration 074).
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.tables WHERE table_name = 'embedded_documents') THEN
+        ALTER TABLE embedded_documents ADD COLUMN IF NOT EXISTS user_id TEXT;
+        CREATE INDEX IF NOT EXISTS idx_embedded_documents_user ON embedded_documents(kind, user_id)
+            WHERE user_id IS NOT NULL;
+    END IF;
+END $$;
+
+-- Add metadata table entry
+INSERT INTO schema_metadata (key, value, description)
+VALUES ('migration_080_completed', 'true', 'Migration 080: Add embedded document owner')
+ON CONFLICT (key) DO NOTHING;
+
+-- Add to migration log
+INSERT INTO migration_log (migration_number, migration_name, applied_at)
+VALUES (80, '080_add_embedded_document_owner.sql', NOW())
+ON CONFLICT (migration_number) DO NOTHING;
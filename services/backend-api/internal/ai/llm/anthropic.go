@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/irfndi/neuratrade/internal/chaos"
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 )
@@ -47,7 +48,8 @@ func NewAnthropicClient(config ClientConfig) *AnthropicClient {
 			ModelInfo:   config.ModelInfo,
 		},
 		httpClient: &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: chaos.NewTransport(chaos.Default(), chaos.TargetLLM, nil),
 		},
 		logger: zap.NewNop(),
 	}
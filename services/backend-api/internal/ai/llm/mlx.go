@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/irfndi/neuratrade/internal/chaos"
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 )
@@ -46,7 +47,8 @@ func NewMLXClient(config ClientConfig) *MLXClient {
 			ModelInfo:   config.ModelInfo,
 		},
 		httpClient: &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: chaos.NewTransport(chaos.Default(), chaos.TargetLLM, nil),
 		},
 		logger: zap.NewNop(),
 	}
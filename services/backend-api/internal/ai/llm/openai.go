@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/irfndi/neuratrade/internal/chaos"
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 )
@@ -47,7 +48,8 @@ func NewOpenAIClient(config ClientConfig) *OpenAIClient {
 			ModelInfo:   config.ModelInfo,
 		},
 		httpClient: &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: chaos.NewTransport(chaos.Default(), chaos.TargetLLM, nil),
 		},
 		logger: zap.NewNop(),
 	}
@@ -79,7 +79,12 @@ func (h *AIHandler) GetModels(c *gin.Context) {
 		})
 	}
 
-	c.JSON(http.StatusOK, gin.H{"models": models})
+	c.JSON(http.StatusOK, AIModelsResponse{Models: models})
+}
+
+// AIModelsResponse is returned by GET /ai/models.
+type AIModelsResponse struct {
+	Models []AIModelInfo `json:"models"`
 }
 
 type AIRouteRequest struct {
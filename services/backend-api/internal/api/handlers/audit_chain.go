@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// AuditChainVerifier verifies and anchors the tamper-evident audit chain.
+type AuditChainVerifier interface {
+	Verify(ctx context.Context) (*services.AuditChainReport, error)
+	Anchor(ctx context.Context) (*services.AuditAnchor, error)
+}
+
+// AuditChainHandler serves verification of the decision and trade log.
+type AuditChainHandler struct {
+	chain AuditChainVerifier
+}
+
+// NewAuditChainHandler creates a new audit chain handler.
+//
+// Parameters:
+//
+//	chain: The audit chain (may be nil without a database).
+//
+// Returns:
+//
+//	*AuditChainHandler: The initialized handler.
+func NewAuditChainHandler(chain AuditChainVerifier) *AuditChainHandler {
+	return &AuditChainHandler{chain: chain}
+}
+
+// Verify walks the audit chain and reports retroactive changes to the
+// decision and trade history. A chain with issues is reported with 200 and
+// valid set to false.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *AuditChainHandler) Verify(c *gin.Context) {
+	if h.chain == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "audit chain not available"})
+		return
+	}
+	report, err := h.chain.Verify(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": report})
+}
+
+// Anchor records a signed checkpoint of the chain head now.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *AuditChainHandler) Anchor(c *gin.Context) {
+	if h.chain == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "audit chain not available"})
+		return
+	}
+	anchor, err := h.chain.Anchor(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	if anchor == nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "error": "audit chain is empty"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": anchor})
+}
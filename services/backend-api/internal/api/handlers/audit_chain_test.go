@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubAuditChain struct {
+	report *services.AuditChainReport
+	anchor *services.AuditAnchor
+	err    error
+}
+
+func (s *stubAuditChain) Verify(context.Context) (*services.AuditChainReport, error) {
+	return s.report, s.err
+}
+
+func (s *stubAuditChain) Anchor(context.Context) (*services.AuditAnchor, error) {
+	return s.anchor, s.err
+}
+
+func TestAuditChainHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	chain := &stubAuditChain{
+		report: &services.AuditChainReport{Entries: 3, IssueCount: 1, Issues: []services.AuditChainIssue{{Seq: 2, Problem: services.AuditIssueBrokenLink}}},
+		anchor: &services.AuditAnchor{Seq: 3, Hash: "head"},
+	}
+	handler := NewAuditChainHandler(chain)
+
+	w := performTradingModeRequest(handler.Verify, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code, "a broken chain is a verification result, not an error")
+	assert.Contains(t, w.Body.String(), `"valid":false`)
+	assert.Contains(t, w.Body.String(), `"problem":"broken_link"`)
+
+	w = performTradingModeRequest(handler.Anchor, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"hash":"head"`)
+
+	chain.anchor = nil
+	assert.Equal(t, http.StatusNotFound, performTradingModeRequest(handler.Anchor, "", nil).Code)
+
+	chain.err = errors.New("database down")
+	assert.Equal(t, http.StatusInternalServerError, performTradingModeRequest(handler.Verify, "", nil).Code)
+	assert.Equal(t, http.StatusServiceUnavailable, performTradingModeRequest(NewAuditChainHandler(nil).Verify, "", nil).Code)
+}
@@ -0,0 +1,372 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/middleware"
+	"github.com/irfndi/neuratrade/internal/models"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+)
+
+const (
+	sessionAccessTokenTTL  = 15 * time.Minute
+	sessionRefreshTokenTTL = 30 * 24 * time.Hour
+
+	loginMethodAPIKey      = "api_key"
+	loginMethodTelegramOTP = "telegram_otp"
+)
+
+// SessionTokenIssuer signs and validates session JWTs.
+type SessionTokenIssuer interface {
+	GenerateScopedToken(userID, email, tokenType string, scopes []string, duration time.Duration) (string, *middleware.JWTClaims, error)
+	ValidateToken(tokenString string) (*middleware.JWTClaims, error)
+}
+
+// SessionStoreInterface persists refresh tokens, revocations and login codes.
+type SessionStoreInterface interface {
+	RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error
+	StoreRefreshToken(ctx context.Context, tokenID, userID string, expiresAt time.Time) error
+	ConsumeRefreshToken(ctx context.Context, tokenID string) (string, error)
+	AllowLoginCode(ctx context.Context, chatID string) error
+	CreateLoginCode(ctx context.Context, chatID string) (string, error)
+	VerifyLoginCode(ctx context.Context, chatID, code string) error
+}
+
+// APIKeyValidator resolves admin API keys to their operator.
+type APIKeyValidator interface {
+	AdminKeyOperator(key string) (string, bool)
+}
+
+// TelegramUserLookup resolves users by their linked Telegram chat.
+type TelegramUserLookup interface {
+	GetUserByTelegramChatID(ctx context.Context, chatID string) (*models.User, error)
+}
+
+// LoginCodeSender delivers one-time login codes to a Telegram chat.
+type LoginCodeSender interface {
+	SendDirectMessage(ctx context.Context, chatID int64, text string) error
+}
+
+// AuthHandler issues session tokens for the web dashboard.
+type AuthHandler struct {
+	tokens  SessionTokenIssuer
+	store   SessionStoreInterface
+	apiKeys APIKeyValidator
+	users   TelegramUserLookup
+	sender  LoginCodeSender
+}
+
+// NewAuthHandler creates a new session auth handler.
+//
+// Parameters:
+//
+//	tokens: JWT issuer.
+//	store: Session store (may be nil when Redis is unavailable).
+//	apiKeys: Operator API key validator.
+//	users: Telegram user lookup.
+//	sender: Login code sender.
+//
+// Returns:
+//
+//	*AuthHandler: The initialized handler.
+func NewAuthHandler(tokens SessionTokenIssuer, store SessionStoreInterface, apiKeys APIKeyValidator, users TelegramUserLookup, sender LoginCodeSender) *AuthHandler {
+	return &AuthHandler{
+		tokens:  tokens,
+		store:   store,
+		apiKeys: apiKeys,
+		users:   users,
+		sender:  sender,
+	}
+}
+
+// SessionLoginRequest is the body for POST /auth/login.
+type SessionLoginRequest struct {
+	// Method is "api_key" or "telegram_otp".
+	Method string `json:"method" binding:"required"`
+	// APIKey is the operator API key for the api_key method.
+	APIKey string `json:"api_key,omitempty"`
+	// ChatID is the Telegram chat for the telegram_otp method.
+	ChatID string `json:"chat_id,omitempty"`
+	// Code is the one-time code sent to the chat.
+	Code string `json:"code,omitempty"`
+	// Scopes optionally narrows the granted scopes.
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// SessionTokenResponse is returned by login and refresh.
+type SessionTokenResponse struct {
+	AccessToken  string   `json:"access_token"`
+	RefreshToken string   `json:"refresh_token"`
+	TokenType    string   `json:"token_type"`
+	ExpiresIn    int      `json:"expires_in"`
+	Scopes       []string `json:"scopes"`
+}
+
+type loginCodeRequest struct {
+	ChatID string `json:"chat_id" binding:"required"`
+}
+
+type refreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type logoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (h *AuthHandler) available(c *gin.Context) bool {
+	if h.store == nil || h.tokens == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "session auth not available"})
+		return false
+	}
+	return true
+}
+
+// Login exchanges an API key or Telegram login code for an access/refresh token pair.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *AuthHandler) Login(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req SessionLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "method is required"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	var userID, email string
+	var granted []string
+
+	switch req.Method {
+	case loginMethodAPIKey:
+		granted = []string{middleware.ScopeAdmin, middleware.ScopeRead, middleware.ScopeTrade}
+	case loginMethodTelegramOTP:
+		granted = []string{middleware.ScopeRead, middleware.ScopeTrade}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "unsupported login method"})
+		return
+	}
+
+	// Check scopes before touching credentials so a rejected request does not burn a login code.
+	scopes, err := narrowScopes(granted, req.Scopes)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+
+	switch req.Method {
+	case loginMethodAPIKey:
+		if req.APIKey == "" || h.apiKeys == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"status": "error", "error": "invalid credentials"})
+			return
+		}
+		operator, ok := h.apiKeys.AdminKeyOperator(req.APIKey)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"status": "error", "error": "invalid credentials"})
+			return
+		}
+		// The session keeps the key's operator so it cannot act as another one.
+		userID = middleware.OperatorSessionPrefix + operator
+	case loginMethodTelegramOTP:
+		if req.ChatID == "" || req.Code == "" || h.users == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "chat_id and code are required"})
+			return
+		}
+		if err := h.store.VerifyLoginCode(ctx, req.ChatID, req.Code); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"status": "error", "error": "invalid credentials"})
+			return
+		}
+		user, err := h.users.GetUserByTelegramChatID(ctx, req.ChatID)
+		if err != nil || user == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"status": "error", "error": "invalid credentials"})
+			return
+		}
+		userID, email = user.ID, user.Email
+	}
+
+	resp, err := h.issueTokens(ctx, userID, email, scopes)
+	if err != nil {
+		telemetry.Logger().Error("Failed to issue session tokens", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": "failed to issue tokens"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": resp})
+}
+
+// RequestLoginCode sends a one-time login code to a linked Telegram chat.
+// It responds the same way for linked and unknown chats so chat IDs cannot
+// be enumerated, and throttles requests per chat.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *AuthHandler) RequestLoginCode(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req loginCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "chat_id is required"})
+		return
+	}
+	chatID, err := strconv.ParseInt(req.ChatID, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "invalid chat_id"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.store.AllowLoginCode(ctx, req.ChatID); err != nil {
+		if errors.Is(err, services.ErrLoginCodeThrottled) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"status": "error", "error": err.Error()})
+			return
+		}
+		telemetry.Logger().Error("Failed to throttle login codes", "chat_id", req.ChatID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": "failed to request login code"})
+		return
+	}
+	if h.users != nil && h.sender != nil {
+		if user, err := h.users.GetUserByTelegramChatID(ctx, req.ChatID); err == nil && user != nil {
+			code, err := h.store.CreateLoginCode(ctx, req.ChatID)
+			if err == nil {
+				message := fmt.Sprintf("🔐 Your NeuraTrade login code is %s\n\nIt expires in 5 minutes. If you did not request it, ignore this message.", code)
+				err = h.sender.SendDirectMessage(ctx, chatID, message)
+			}
+			if err != nil {
+				telemetry.Logger().Warn("Failed to send login code", "chat_id", req.ChatID, "error", err)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "If the chat is linked to an account, a login code has been sent"})
+}
+
+// Refresh rotates a refresh token into a new token pair. Each refresh token works once.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req refreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "refresh_token is required"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	claims, err := h.tokens.ValidateToken(req.RefreshToken)
+	if err != nil || claims.TokenType != middleware.TokenTypeRefresh || claims.ID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"status": "error", "error": "invalid refresh token"})
+		return
+	}
+	userID, err := h.store.ConsumeRefreshToken(ctx, claims.ID)
+	if err != nil || userID != claims.UserID {
+		if err != nil && !errors.Is(err, services.ErrRefreshTokenNotFound) {
+			telemetry.Logger().Error("Failed to consume refresh token", "error", err)
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{"status": "error", "error": "invalid refresh token"})
+		return
+	}
+
+	resp, err := h.issueTokens(ctx, claims.UserID, claims.Email, claims.Scopes)
+	if err != nil {
+		telemetry.Logger().Error("Failed to issue session tokens", "user_id", claims.UserID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": "failed to issue tokens"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": resp})
+}
+
+// Logout revokes the current access token and, if supplied, its refresh token.
+// Must run behind RequireAuth.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *AuthHandler) Logout(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	value, _ := c.Get("token_claims")
+	claims, ok := value.(*middleware.JWTClaims)
+	if !ok || claims == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"status": "error", "error": "authentication required"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if claims.ID != "" && claims.ExpiresAt != nil {
+		if err := h.store.RevokeToken(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": "failed to revoke token"})
+			return
+		}
+	}
+
+	var req logoutRequest
+	if c.Request.ContentLength > 0 {
+		_ = c.ShouldBindJSON(&req)
+	}
+	if req.RefreshToken != "" {
+		refresh, err := h.tokens.ValidateToken(req.RefreshToken)
+		if err == nil && refresh.TokenType == middleware.TokenTypeRefresh && refresh.UserID == claims.UserID && refresh.ID != "" {
+			_, _ = h.store.ConsumeRefreshToken(ctx, refresh.ID)
+			if refresh.ExpiresAt != nil {
+				_ = h.store.RevokeToken(ctx, refresh.ID, refresh.ExpiresAt.Time)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Logged out"})
+}
+
+func (h *AuthHandler) issueTokens(ctx context.Context, userID, email string, scopes []string) (*SessionTokenResponse, error) {
+	access, _, err := h.tokens.GenerateScopedToken(userID, email, middleware.TokenTypeAccess, scopes, sessionAccessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	refresh, refreshClaims, err := h.tokens.GenerateScopedToken(userID, email, middleware.TokenTypeRefresh, scopes, sessionRefreshTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.store.StoreRefreshToken(ctx, refreshClaims.ID, userID, refreshClaims.ExpiresAt.Time); err != nil {
+		return nil, err
+	}
+	return &SessionTokenResponse{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(sessionAccessTokenTTL.Seconds()),
+		Scopes:       scopes,
+	}, nil
+}
+
+// narrowScopes returns the requested scopes if they are a subset of the granted ones.
+func narrowScopes(granted, requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return granted, nil
+	}
+	allowed := make(map[string]bool, len(granted))
+	for _, s := range granted {
+		allowed[s] = true
+	}
+	for _, s := range requested {
+		if !allowed[s] {
+			return nil, fmt.Errorf("scope %q not permitted", s)
+		}
+	}
+	return requested, nil
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/middleware"
+	"github.com/irfndi/neuratrade/internal/models"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubAPIKeys struct{}
+
+func (stubAPIKeys) AdminKeyOperator(key string) (string, bool) {
+	if key == "operator-key" {
+		return "alice", true
+	}
+	return "", false
+}
+
+type stubTelegramUsers struct{}
+
+func (stubTelegramUsers) GetUserByTelegramChatID(ctx context.Context, chatID string) (*models.User, error) {
+	if chatID == "42" {
+		return &models.User{ID: "user-42", Email: "u@example.com"}, nil
+	}
+	return nil, errors.New("not found")
+}
+
+type stubCodeSender struct {
+	messages map[int64]string
+}
+
+func (s *stubCodeSender) SendDirectMessage(ctx context.Context, chatID int64, text string) error {
+	s.messages[chatID] = text
+	return nil
+}
+
+type authTestEnv struct {
+	router *gin.Engine
+	sender *stubCodeSender
+}
+
+func newAuthTestEnv(t *testing.T) *authTestEnv {
+	gin.SetMode(gin.TestMode)
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	store := services.NewSessionStore(client)
+	am := middleware.NewAuthMiddleware("test-secret-key-that-is-at-least-32-chars")
+	am.SetRevocationChecker(store)
+	sender := &stubCodeSender{messages: map[int64]string{}}
+	handler := NewAuthHandler(am, store, stubAPIKeys{}, stubTelegramUsers{}, sender)
+
+	router := gin.New()
+	router.POST("/auth/login", handler.Login)
+	router.POST("/auth/otp", handler.RequestLoginCode)
+	router.POST("/auth/refresh", handler.Refresh)
+	router.POST("/auth/logout", am.RequireAuth(), handler.Logout)
+	router.GET("/trade", am.RequireAuth(), am.RequireScope(middleware.ScopeTrade), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	t.Setenv("ADMIN_API_KEY", "test-admin-key-32-chars-minimum-length")
+	admin := middleware.NewAdminMiddleware()
+	admin.SetSessionVerifier(am)
+	router.GET("/admin", admin.RequireAdminAuth(), func(c *gin.Context) {
+		c.String(http.StatusOK, middleware.AdminOperator(c))
+	})
+	return &authTestEnv{router: router, sender: sender}
+}
+
+func (e *authTestEnv) do(method, path, body, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	e.router.ServeHTTP(w, req)
+	return w
+}
+
+func decodeSessionTokens(t *testing.T, w *httptest.ResponseRecorder) SessionTokenResponse {
+	var resp struct {
+		Data SessionTokenResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp.Data
+}
+
+func TestAuthHandler_APIKeyLoginRefreshAndLogout(t *testing.T) {
+	env := newAuthTestEnv(t)
+
+	w := env.do(http.MethodPost, "/auth/login", `{"method":"api_key","api_key":"wrong"}`, "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = env.do(http.MethodPost, "/auth/login", `{"method":"api_key","api_key":"operator-key"}`, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	tokens := decodeSessionTokens(t, w)
+	assert.Equal(t, "Bearer", tokens.TokenType)
+	assert.Contains(t, tokens.Scopes, middleware.ScopeAdmin)
+	assert.Equal(t, http.StatusOK, env.do(http.MethodGet, "/trade", "", tokens.AccessToken).Code)
+	w = env.do(http.MethodGet, "/admin", "", tokens.AccessToken)
+	assert.Equal(t, http.StatusOK, w.Code, "admin-scope sessions pass admin auth")
+	assert.Equal(t, "alice", w.Body.String(), "the session keeps the key's operator")
+	assert.Equal(t, http.StatusUnauthorized, env.do(http.MethodGet, "/trade", "", tokens.RefreshToken).Code)
+
+	w = env.do(http.MethodPost, "/auth/refresh", `{"refresh_token":"`+tokens.RefreshToken+`"}`, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	rotated := decodeSessionTokens(t, w)
+
+	w = env.do(http.MethodPost, "/auth/refresh", `{"refresh_token":"`+tokens.RefreshToken+`"}`, "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "refresh tokens are single-use")
+
+	w = env.do(http.MethodPost, "/auth/logout", `{"refresh_token":"`+rotated.RefreshToken+`"}`, rotated.AccessToken)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusUnauthorized, env.do(http.MethodGet, "/trade", "", rotated.AccessToken).Code)
+	w = env.do(http.MethodPost, "/auth/refresh", `{"refresh_token":"`+rotated.RefreshToken+`"}`, "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthHandler_TelegramOTPLogin(t *testing.T) {
+	env := newAuthTestEnv(t)
+
+	w := env.do(http.MethodPost, "/auth/otp", `{"chat_id":"7"}`, "")
+	assert.Equal(t, http.StatusOK, w.Code, "unknown chats get the same response")
+	assert.Empty(t, env.sender.messages)
+
+	w = env.do(http.MethodPost, "/auth/otp", `{"chat_id":"42"}`, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	code := regexp.MustCompile(`\d{6}`).FindString(env.sender.messages[42])
+	require.NotEmpty(t, code)
+
+	w = env.do(http.MethodPost, "/auth/login", `{"method":"telegram_otp","chat_id":"42","code":"000000x"}`, "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = env.do(http.MethodPost, "/auth/login", `{"method":"telegram_otp","chat_id":"42","code":"`+code+`","scopes":["admin"]}`, "")
+	assert.Equal(t, http.StatusForbidden, w.Code, "users cannot request admin scope")
+
+	w = env.do(http.MethodPost, "/auth/login", `{"method":"telegram_otp","chat_id":"42","code":"`+code+`","scopes":["read"]}`, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	tokens := decodeSessionTokens(t, w)
+	assert.Equal(t, []string{middleware.ScopeRead}, tokens.Scopes)
+	assert.Equal(t, http.StatusForbidden, env.do(http.MethodGet, "/trade", "", tokens.AccessToken).Code)
+	assert.Equal(t, http.StatusUnauthorized, env.do(http.MethodGet, "/admin", "", tokens.AccessToken).Code)
+}
+
+func TestAuthHandler_LoginCodeThrottle(t *testing.T) {
+	env := newAuthTestEnv(t)
+
+	for _, chatID := range []string{"42", "7"} {
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, http.StatusOK, env.do(http.MethodPost, "/auth/otp", `{"chat_id":"`+chatID+`"}`, "").Code)
+		}
+		w := env.do(http.MethodPost, "/auth/otp", `{"chat_id":"`+chatID+`"}`, "")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code, "linked and unknown chats are throttled alike")
+	}
+}
+
+func TestAuthHandler_Unavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewAuthHandler(nil, nil, nil, nil, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewBufferString(`{"method":"api_key"}`))
+	handler.Login(c)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -1,23 +1,41 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/irfndi/neuratrade/internal/database"
 	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/irfndi/neuratrade/pkg/interfaces"
+	"github.com/shopspring/decimal"
 )
 
 // AutonomousHandler handles autonomous mode endpoints
 type AutonomousHandler struct {
 	questEngine *services.QuestEngine
 	readiness   *ReadinessChecker
+	positions   PortfolioPositionSource
+	equity      EquitySnapshotSource
+}
+
+// EquitySnapshotSource values a chat's accounts
+type EquitySnapshotSource interface {
+	Snapshot(ctx context.Context, chatID string) *services.EquitySnapshot
+}
+
+// PortfolioPositionSource lists the tracked open positions
+type PortfolioPositionSource interface {
+	GetOpenPositions() []interfaces.Position
 }
 
 // NewAutonomousHandler creates a new autonomous handler
@@ -28,6 +46,23 @@ func NewAutonomousHandler(questEngine *services.QuestEngine) *AutonomousHandler
 	}
 }
 
+// SetDailyLossCircuit reports the chat's daily loss cap in readiness checks;
+// a chat that breached it is not ready until the next UTC day.
+func (h *AutonomousHandler) SetDailyLossCircuit(circuit DailyLossProvider) {
+	h.readiness.dailyLoss = circuit
+}
+
+// SetPositionSource lists tracked open positions, with their liquidation
+// prices, in portfolio snapshots
+func (h *AutonomousHandler) SetPositionSource(source PortfolioPositionSource) {
+	h.positions = source
+}
+
+// SetEquitySource values the accounts behind portfolio and equity responses
+func (h *AutonomousHandler) SetEquitySource(source EquitySnapshotSource) {
+	h.equity = source
+}
+
 // BeginRequest represents the request body for /begin
 type BeginRequest struct {
 	ChatID string `json:"chat_id" binding:"required"`
@@ -55,10 +90,32 @@ type PauseAutonomousResponse struct {
 	Message string `json:"message,omitempty"`
 }
 
+// FundGrowthGoalRequest represents the request body for /quests/fund-growth.
+// Exactly one of TargetValue and GrowthPct is set, e.g. growth_pct "5" with
+// period "week" for "grow 5% this week".
+type FundGrowthGoalRequest struct {
+	ChatID      string `json:"chat_id" binding:"required"`
+	TargetValue string `json:"target_value"`
+	GrowthPct   string `json:"growth_pct"`
+	// Period is day, week, month, a number of days ("10d") or a duration ("48h").
+	Period string `json:"period"`
+	// StartValue defaults to the equity at the first snapshot.
+	StartValue string `json:"start_value"`
+}
+
+// FundGrowthGoalResponse represents the response for /quests/fund-growth
+type FundGrowthGoalResponse struct {
+	Ok      bool                    `json:"ok"`
+	QuestID string                  `json:"quest_id"`
+	Goal    services.FundGrowthGoal `json:"goal"`
+	Message string                  `json:"message"`
+}
+
 // QuestProgressResponse represents the response for /quests
 type QuestProgressResponse struct {
-	Quests    []services.QuestProgress `json:"quests"`
-	UpdatedAt string                   `json:"updated_at,omitempty"`
+	Quests     []services.QuestProgress `json:"quests"`
+	UpdatedAt  string                   `json:"updated_at,omitempty"`
+	Pagination *PageInfo                `json:"pagination,omitempty"`
 }
 
 // PortfolioPosition represents a portfolio position
@@ -69,15 +126,24 @@ type PortfolioPosition struct {
 	EntryPrice    string `json:"entry_price,omitempty"`
 	MarkPrice     string `json:"mark_price,omitempty"`
 	UnrealizedPnL string `json:"unrealized_pnl,omitempty"`
+	Leverage      string `json:"leverage,omitempty"`
+	// LiquidationPrice is the estimated liquidation price of a leveraged position
+	LiquidationPrice string `json:"liquidation_price,omitempty"`
+	// LiquidationDistancePct is how far the mark may move against the
+	// position before liquidation, in percent
+	LiquidationDistancePct string `json:"liquidation_distance_pct,omitempty"`
 }
 
 // PortfolioResponse represents the response for /portfolio
 type PortfolioResponse struct {
-	TotalEquity      string              `json:"total_equity"`
-	AvailableBalance string              `json:"available_balance,omitempty"`
-	Exposure         string              `json:"exposure,omitempty"`
-	Positions        []PortfolioPosition `json:"positions"`
-	UpdatedAt        string              `json:"updated_at,omitempty"`
+	TotalEquity      string `json:"total_equity"`
+	AvailableBalance string `json:"available_balance,omitempty"`
+	// Reserve is the part of the account kept out of autonomous trading; it
+	// is not included in TotalEquity or AvailableBalance
+	Reserve   string              `json:"reserve,omitempty"`
+	Exposure  string              `json:"exposure,omitempty"`
+	Positions []PortfolioPosition `json:"positions"`
+	UpdatedAt string              `json:"updated_at,omitempty"`
 }
 
 // OperatorLogEntry represents a log entry
@@ -90,7 +156,25 @@ type OperatorLogEntry struct {
 
 // LogsResponse represents the response for /logs
 type LogsResponse struct {
-	Logs []OperatorLogEntry `json:"logs"`
+	Logs       []OperatorLogEntry `json:"logs"`
+	Pagination *PageInfo          `json:"pagination,omitempty"`
+}
+
+// questPageOptions defines pagination for quest runs.
+var questPageOptions = PageOptions{
+	DefaultLimit: 50,
+	MaxLimit:     200,
+	SortFields:   []string{"quest_name", "percent", "status"},
+	Filters:      []string{"status"},
+}
+
+// logPageOptions defines pagination for operator logs, newest first by default.
+var logPageOptions = PageOptions{
+	DefaultLimit: 50,
+	MaxLimit:     200,
+	SortFields:   []string{"timestamp"},
+	DefaultDesc:  true,
+	Filters:      []string{"level", "source"},
 }
 
 // DoctorCheck represents a diagnostic check result
@@ -233,6 +317,54 @@ func (h *AutonomousHandler) PauseAutonomous(c *gin.Context) {
 	})
 }
 
+// CreateFundGrowthGoal starts a fund growth quest with a monetary or
+// percentage target. Progress is measured from equity snapshots and milestone
+// notifications are sent at 25, 50, 75 and 100%.
+func (h *AutonomousHandler) CreateFundGrowthGoal(c *gin.Context) {
+	var req FundGrowthGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request: " + err.Error()})
+		return
+	}
+
+	values := make([]decimal.Decimal, 3)
+	for i, raw := range []string{req.StartValue, req.TargetValue, req.GrowthPct} {
+		raw = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(raw), "%"))
+		if raw == "" {
+			continue
+		}
+		value, err := decimal.NewFromString(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid number %q", raw)})
+			return
+		}
+		values[i] = value
+	}
+	period, err := services.ParseGrowthPeriod(req.Period)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	goal, err := services.NewFundGrowthGoal(values[0], values[1], values[2], period, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	quest, err := h.questEngine.CreateFundGrowthQuest(req.ChatID, goal)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create fund growth quest: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, FundGrowthGoalResponse{
+		Ok:      true,
+		QuestID: quest.ID,
+		Goal:    goal,
+		Message: "Fund growth goal created: " + goal.Describe(),
+	})
+}
+
 // GetQuests returns quest progress for a user
 func (h *AutonomousHandler) GetQuests(c *gin.Context) {
 	chatID := c.Query("chat_id")
@@ -241,15 +373,68 @@ func (h *AutonomousHandler) GetQuests(c *gin.Context) {
 		return
 	}
 
+	page, err := ParsePageRequest(c, questPageOptions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	progress, err := h.questEngine.GetQuestProgress(chatID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get quest progress: " + err.Error()})
 		return
 	}
 
+	filtered := make([]services.QuestProgress, 0, len(progress))
+	for _, quest := range progress {
+		if status := page.Filter("status"); status == "" || strings.EqualFold(quest.Status, status) {
+			filtered = append(filtered, quest)
+		}
+	}
+	quests, info := PaginateSlice(filtered, page, questSortKey, func(q services.QuestProgress) string { return q.QuestID })
+
+	writePageHeaders(c, info)
 	c.JSON(http.StatusOK, QuestProgressResponse{
-		Quests:    progress,
-		UpdatedAt: time.Now().UTC().Format(time.RFC3339),
+		Quests:     quests,
+		UpdatedAt:  time.Now().UTC().Format(time.RFC3339),
+		Pagination: &info,
+	})
+}
+
+// SetQuestCatchUp sets what a quest does about runs it missed while the
+// server was down: run_once, skip or backfill
+func (h *AutonomousHandler) SetQuestCatchUp(c *gin.Context) {
+	if h.questEngine == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "quest engine not configured"})
+		return
+	}
+	var req struct {
+		ChatID  string `json:"chat_id" binding:"required"`
+		CatchUp string `json:"catch_up" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request: " + err.Error()})
+		return
+	}
+
+	policy := services.QuestCatchUpPolicy(strings.ToLower(strings.TrimSpace(req.CatchUp)))
+	quest, err := h.questEngine.SetQuestCatchUp(c.Param("id"), req.ChatID, policy)
+	switch {
+	case errors.Is(err, services.ErrInvalidCatchUpPolicy):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrQuestNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update quest: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"ok":       true,
+		"quest_id": quest.ID,
+		"catch_up": policy,
 	})
 }
 
@@ -261,15 +446,67 @@ func (h *AutonomousHandler) GetPortfolio(c *gin.Context) {
 		return
 	}
 
-	// TODO: Implement actual portfolio retrieval from exchange connectors
-	// For now, return placeholder data
-	c.JSON(http.StatusOK, PortfolioResponse{
+	response := PortfolioResponse{
 		TotalEquity:      "0.00",
 		AvailableBalance: "0.00",
 		Exposure:         "0%",
-		Positions:        []PortfolioPosition{},
+		Positions:        h.portfolioPositions(),
 		UpdatedAt:        time.Now().UTC().Format(time.RFC3339),
-	})
+	}
+	if h.equity != nil {
+		snapshot := h.equity.Snapshot(c.Request.Context(), chatID)
+		equity := snapshot.TotalEquity.Sub(snapshot.Reserve)
+		response.TotalEquity = equity.StringFixed(2)
+		response.AvailableBalance = snapshot.AvailableBalance.StringFixed(2)
+		if snapshot.Reserve.IsPositive() {
+			response.Reserve = snapshot.Reserve.StringFixed(2)
+		}
+		if equity.IsPositive() {
+			response.Exposure = snapshot.Exposure.Div(equity).Mul(decimal.NewFromInt(100)).StringFixed(1) + "%"
+		}
+		response.UpdatedAt = snapshot.UpdatedAt.Format(time.RFC3339)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetEquity returns the valuation of the exchange accounts, open positions
+// and, with a chat_id, the chat's external wallets
+func (h *AutonomousHandler) GetEquity(c *gin.Context) {
+	if h.equity == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "equity valuation not available"})
+		return
+	}
+	snapshot := h.equity.Snapshot(c.Request.Context(), strings.TrimSpace(c.Query("chat_id")))
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": snapshot})
+}
+
+// portfolioPositions converts the tracked open positions for the portfolio view
+func (h *AutonomousHandler) portfolioPositions() []PortfolioPosition {
+	positions := []PortfolioPosition{}
+	if h.positions == nil {
+		return positions
+	}
+	for _, p := range h.positions.GetOpenPositions() {
+		position := PortfolioPosition{
+			Symbol:        p.Symbol,
+			Side:          p.Side,
+			Size:          p.Size.String(),
+			EntryPrice:    p.EntryPrice.String(),
+			MarkPrice:     p.CurrentPrice.String(),
+			UnrealizedPnL: p.UnrealizedPL.StringFixed(2),
+		}
+		if p.Leverage.IsPositive() {
+			position.Leverage = p.Leverage.String()
+		}
+		if p.LiquidationPrice.IsPositive() {
+			position.LiquidationPrice = p.LiquidationPrice.StringFixed(4)
+			distance := services.LiquidationDistance(p.Side, p.CurrentPrice, p.LiquidationPrice)
+			position.LiquidationDistancePct = distance.Mul(decimal.NewFromInt(100)).StringFixed(2)
+		}
+		positions = append(positions, position)
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[i].Symbol < positions[j].Symbol })
+	return positions
 }
 
 // GetLogs returns recent operator logs for a user
@@ -280,6 +517,12 @@ func (h *AutonomousHandler) GetLogs(c *gin.Context) {
 		return
 	}
 
+	page, err := ParsePageRequest(c, logPageOptions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// TODO: Implement actual log retrieval
 	// For now, return placeholder logs
 	logs := []OperatorLogEntry{
@@ -291,7 +534,34 @@ func (h *AutonomousHandler) GetLogs(c *gin.Context) {
 		},
 	}
 
-	c.JSON(http.StatusOK, LogsResponse{Logs: logs})
+	filtered := make([]OperatorLogEntry, 0, len(logs))
+	for _, entry := range logs {
+		if level := page.Filter("level"); level != "" && !strings.EqualFold(entry.Level, level) {
+			continue
+		}
+		if source := page.Filter("source"); source != "" && !strings.EqualFold(entry.Source, source) {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+	// Log entries have no ID; timestamp, source and message together break ties.
+	entries, info := PaginateSlice(filtered, page,
+		func(e OperatorLogEntry, _ string) string { return e.Timestamp },
+		func(e OperatorLogEntry) string { return e.Timestamp + "|" + e.Source + "|" + e.Message })
+
+	writePageHeaders(c, info)
+	c.JSON(http.StatusOK, LogsResponse{Logs: entries, Pagination: &info})
+}
+
+func questSortKey(q services.QuestProgress, field string) string {
+	switch field {
+	case "percent":
+		return pageIntKey(q.Percent)
+	case "status":
+		return q.Status
+	default:
+		return q.QuestName
+	}
 }
 
 // GetDoctor runs diagnostic checks for a user
@@ -523,7 +793,9 @@ func generateRequestID() string {
 }
 
 // ReadinessChecker checks system readiness for autonomous mode
-type ReadinessChecker struct{}
+type ReadinessChecker struct {
+	dailyLoss DailyLossProvider
+}
 
 // CheckResult represents the result of a single check
 type CheckResult struct {
@@ -580,7 +852,7 @@ func (r *ReadinessChecker) Check(c *gin.Context, chatID string) *ReadinessResult
 	}
 
 	// Check 5: Risk limits configuration
-	riskResult := r.checkRiskLimits(c)
+	riskResult := r.checkRiskLimits(c, chatID)
 	checks["risk_limits"] = riskResult
 	if riskResult.Status != "healthy" {
 		failedChecks = append(failedChecks, "risk_limits")
@@ -829,19 +1101,50 @@ func (r *ReadinessChecker) checkWallets(c *gin.Context, chatID string) *CheckRes
 	}
 }
 
-func (r *ReadinessChecker) checkRiskLimits(c *gin.Context) *CheckResult {
+func (r *ReadinessChecker) checkRiskLimits(c *gin.Context, chatID string) *CheckResult {
 	start := time.Now()
-	// TODO: Actual risk limits check
-	latency := time.Since(start).Milliseconds()
+	details := map[string]string{
+		"max_drawdown":   "5%",
+		"daily_loss_cap": "not enforced",
+		"position_limit": "10%",
+	}
+	if r.dailyLoss == nil {
+		return &CheckResult{
+			Status:    "healthy",
+			Message:   "Risk limits configured; daily loss cap unavailable without Redis",
+			LatencyMs: time.Since(start).Milliseconds(),
+			Details:   details,
+		}
+	}
+
+	config := r.dailyLoss.Config()
+	details["daily_loss_cap"] = fmt.Sprintf("%g%%", config.MaxLossPct*100)
+	if config.MaxLoss.IsPositive() {
+		details["daily_loss_cap_usdt"] = config.MaxLoss.String()
+	}
+	state, err := r.dailyLoss.Evaluate(c.Request.Context(), chatID)
+	if err != nil {
+		return &CheckResult{
+			Status:    "warning",
+			Message:   "Failed to evaluate daily loss: " + err.Error(),
+			LatencyMs: time.Since(start).Milliseconds(),
+			Details:   details,
+		}
+	}
+	details["daily_pnl"] = state.PnL.StringFixed(2)
+	if state.Halted {
+		return &CheckResult{
+			Status:    "warning",
+			Message:   "Daily loss cap reached; new entries resume at 00:00 UTC",
+			LatencyMs: time.Since(start).Milliseconds(),
+			Details:   details,
+		}
+	}
 
 	return &CheckResult{
 		Status:    "healthy",
 		Message:   "Risk limits configured",
-		LatencyMs: latency,
-		Details: map[string]string{
-			"max_drawdown":   "5%",
-			"daily_loss_cap": "2%",
-			"position_limit": "10%",
-		},
+		LatencyMs: time.Since(start).Milliseconds(),
+		Details:   details,
 	}
 }
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+const (
+	// BackupPassphraseHeader carries the archive passphrase so it never
+	// appears in URLs or access logs.
+	BackupPassphraseHeader = "X-Backup-Passphrase"
+
+	maxBackupArchiveSize = 128 << 20
+)
+
+// BackupManager defines the snapshot operations used by the backup endpoints.
+type BackupManager interface {
+	Snapshot(ctx context.Context) (*services.BackupSnapshot, error)
+	Restore(ctx context.Context, snapshot *services.BackupSnapshot, operator string) (*services.BackupRestoreSummary, error)
+}
+
+// BackupHandler exports and restores encrypted application state archives.
+type BackupHandler struct {
+	backups BackupManager
+}
+
+// NewBackupHandler creates a new backup handler.
+//
+// Parameters:
+//
+//	backups: The backup service (may be nil when no database is configured).
+//
+// Returns:
+//
+//	*BackupHandler: The initialized handler.
+func NewBackupHandler(backups BackupManager) *BackupHandler {
+	return &BackupHandler{backups: backups}
+}
+
+func (h *BackupHandler) available(c *gin.Context) bool {
+	if h.backups == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "backup service not available"})
+		return false
+	}
+	return true
+}
+
+// CreateBackup returns an encrypted archive of the current state.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *BackupHandler) CreateBackup(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	passphrase := c.GetHeader(BackupPassphraseHeader)
+	if passphrase == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": services.ErrBackupPassphraseRequired.Error()})
+		return
+	}
+
+	snapshot, err := h.backups.Snapshot(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	archive, err := services.EncryptBackup(snapshot, passphrase)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("neuratrade-backup-%s.json", snapshot.CreatedAt.Format("20060102-150405"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "application/json", archive)
+}
+
+// RestoreBackup decrypts the archive in the request body and restores it.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *BackupHandler) RestoreBackup(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	passphrase := c.GetHeader(BackupPassphraseHeader)
+	if passphrase == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": services.ErrBackupPassphraseRequired.Error()})
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, maxBackupArchiveSize+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "failed to read archive"})
+		return
+	}
+	if len(data) > maxBackupArchiveSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"status": "error", "error": "archive is too large"})
+		return
+	}
+
+	snapshot, err := services.DecryptBackup(data, passphrase)
+	switch {
+	case errors.Is(err, services.ErrBackupDecrypt):
+		c.JSON(http.StatusUnauthorized, gin.H{"status": "error", "error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+
+	operator := c.DefaultQuery("operator", "backup-restore")
+	summary, err := h.backups.Restore(c.Request.Context(), snapshot, operator)
+	if err != nil {
+		if errors.Is(err, services.ErrBackupInvalid) || errors.Is(err, services.ErrBackupUnsupported) {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error(), "data": summary})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{
+		"created_at": snapshot.CreatedAt,
+		"restored":   summary,
+	}})
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubBackups struct {
+	restored *services.BackupSnapshot
+	operator string
+}
+
+func (s *stubBackups) Snapshot(ctx context.Context) (*services.BackupSnapshot, error) {
+	return &services.BackupSnapshot{
+		Version:   services.BackupVersion,
+		CreatedAt: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+		Quests:    []*services.Quest{{ID: "q-1"}},
+	}, nil
+}
+
+func (s *stubBackups) Restore(ctx context.Context, snapshot *services.BackupSnapshot, operator string) (*services.BackupRestoreSummary, error) {
+	s.restored = snapshot
+	s.operator = operator
+	return &services.BackupRestoreSummary{Quests: len(snapshot.Quests)}, nil
+}
+
+func performBackupRequest(handlerFunc gin.HandlerFunc, target, passphrase string, body []byte) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	if passphrase != "" {
+		c.Request.Header.Set(BackupPassphraseHeader, passphrase)
+	}
+	handlerFunc(c)
+	return w
+}
+
+func TestBackupHandler_CreateAndRestore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	backups := &stubBackups{}
+	handler := NewBackupHandler(backups)
+
+	w := performBackupRequest(handler.CreateBackup, "/backup", "secret", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "neuratrade-backup-20260101-120000.json")
+	archive := w.Body.Bytes()
+
+	w = performBackupRequest(handler.RestoreBackup, "/backup/restore?operator=op-1", "wrong", archive)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Nil(t, backups.restored)
+
+	w = performBackupRequest(handler.RestoreBackup, "/backup/restore?operator=op-1", "secret", archive)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"quests":1`)
+	assert.Equal(t, "q-1", backups.restored.Quests[0].ID)
+	assert.Equal(t, "op-1", backups.operator)
+
+	w = performBackupRequest(handler.RestoreBackup, "/backup/restore", "secret", []byte("not an archive"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestBackupHandler_Validation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := performBackupRequest(NewBackupHandler(&stubBackups{}).CreateBackup, "/backup", "", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performBackupRequest(NewBackupHandler(nil).CreateBackup, "/backup", "secret", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/cache"
 	"github.com/irfndi/neuratrade/internal/services"
 )
 
@@ -125,6 +126,26 @@ func (h *CacheHandler) GetCacheMetrics(c *gin.Context) {
 	})
 }
 
+// GetBoundedCaches returns the size and eviction counters of the
+// memory-bounded in-process caches.
+//
+// Parameters:
+//
+//	c: The Gin context.
+//
+// @Summary Get bounded cache metrics
+// @Description Get entries, capacity, hits, misses, evictions and expirations of in-memory caches
+// @Tags cache
+// @Produce json
+// @Success 200 {array} cache.BoundedStats
+// @Router /api/cache/bounded [get]
+func (h *CacheHandler) GetBoundedCaches(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    cache.AllBoundedStats(),
+	})
+}
+
 // ResetCacheStats resets all cache statistics.
 //
 // Parameters:
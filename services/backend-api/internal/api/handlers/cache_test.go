@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/cache"
 	"github.com/irfndi/neuratrade/internal/services"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -247,6 +248,31 @@ func TestCacheHandler_ResetCacheStats(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestCacheHandler_GetBoundedCaches(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewCacheHandler(NewMockCacheAnalyticsService())
+
+	bounded := cache.NewBounded[string, int](cache.BoundedConfig{Name: "handlers_test.bounded", MaxEntries: 1})
+	bounded.Set("a", 1)
+	bounded.Set("b", 2)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/cache/bounded", nil)
+
+	handler.GetBoundedCaches(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Success bool                 `json:"success"`
+		Data    []cache.BoundedStats `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Success)
+	assert.Contains(t, response.Data, cache.BoundedStats{Name: "handlers_test.bounded", Entries: 1, MaxEntries: 1, Evictions: 1})
+}
+
 func TestCacheHandler_RecordCacheHit(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockService := NewMockCacheAnalyticsService()
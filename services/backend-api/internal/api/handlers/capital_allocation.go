@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/shopspring/decimal"
+)
+
+// CapitalAllocationManager defines the per-chat capital allocation operations.
+type CapitalAllocationManager interface {
+	Get(ctx context.Context, chatID string) (*services.CapitalAllocation, error)
+	Set(ctx context.Context, chatID string, targets map[string]float64, performanceWeighted bool) (*services.CapitalAllocation, error)
+	Clear(ctx context.Context, chatID string) error
+	Rebalance(ctx context.Context, chatID string) (*services.CapitalAllocation, error)
+	RecordResult(ctx context.Context, chatID, strategy string, pnl decimal.Decimal) error
+}
+
+// CapitalAllocationHandler manages how each chat's capital is split between strategies.
+type CapitalAllocationHandler struct {
+	allocations CapitalAllocationManager
+}
+
+// UpdateAllocationRequest changes a chat's capital allocation.
+type UpdateAllocationRequest struct {
+	ChatID string `json:"chat_id" binding:"required"`
+	// Action is one of set, rebalance, clear or record.
+	Action string `json:"action" binding:"required"`
+	// Targets is the fraction per strategy for set, e.g. {"scalping": 0.6}.
+	Targets             map[string]float64 `json:"targets"`
+	PerformanceWeighted bool               `json:"performance_weighted"`
+	// Strategy and PnL report a realized trade result for record.
+	Strategy string `json:"strategy"`
+	PnL      string `json:"pnl"`
+}
+
+// NewCapitalAllocationHandler creates a new capital allocation handler.
+//
+// Parameters:
+//
+//	allocations: The allocation service (may be nil when Redis is unavailable).
+//
+// Returns:
+//
+//	*CapitalAllocationHandler: The initialized handler.
+func NewCapitalAllocationHandler(allocations CapitalAllocationManager) *CapitalAllocationHandler {
+	return &CapitalAllocationHandler{allocations: allocations}
+}
+
+func (h *CapitalAllocationHandler) available(c *gin.Context) bool {
+	if h.allocations == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "capital allocation service not available"})
+		return false
+	}
+	return true
+}
+
+func (h *CapitalAllocationHandler) respondAllocation(c *gin.Context, chatID string) {
+	allocation, err := h.allocations.Get(c.Request.Context(), chatID)
+	if err != nil && !errors.Is(err, services.ErrAllocationNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{
+		"allocation": allocation,
+		"enforced":   allocation != nil,
+		"strategies": services.AllocationStrategies(),
+	}})
+}
+
+// GetAllocation returns a chat's capital allocation; allocation is null when
+// every strategy may use all capital.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *CapitalAllocationHandler) GetAllocation(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	chatID := c.Query("chat_id")
+	if chatID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "chat_id is required"})
+		return
+	}
+	h.respondAllocation(c, chatID)
+}
+
+// UpdateAllocation sets, rebalances or clears a chat's allocation, or records
+// a strategy's realized trade result for performance weighting.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *CapitalAllocationHandler) UpdateAllocation(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req UpdateAllocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "Invalid request body"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	var err error
+	switch req.Action {
+	case "set":
+		_, err = h.allocations.Set(ctx, req.ChatID, req.Targets, req.PerformanceWeighted)
+	case "rebalance":
+		_, err = h.allocations.Rebalance(ctx, req.ChatID)
+	case "clear":
+		err = h.allocations.Clear(ctx, req.ChatID)
+	case "record":
+		pnl, parseErr := decimal.NewFromString(req.PnL)
+		if parseErr != nil || req.Strategy == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "strategy and a numeric pnl are required"})
+			return
+		}
+		err = h.allocations.RecordResult(ctx, req.ChatID, req.Strategy, pnl)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "action must be set, rebalance, clear or record"})
+		return
+	}
+	if err != nil {
+		writeAllocationError(c, err)
+		return
+	}
+	h.respondAllocation(c, req.ChatID)
+}
+
+func writeAllocationError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrAllocationInvalid), errors.Is(err, services.ErrAllocationUnknownStrategy):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrAllocationNotFound):
+		status = http.StatusNotFound
+	}
+	c.JSON(status, gin.H{"status": "error", "error": err.Error()})
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCapitalAllocationHandler(t *testing.T) *CapitalAllocationHandler {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewCapitalAllocationHandler(services.NewCapitalAllocationService(client, services.CapitalAllocationConfig{}))
+}
+
+func TestCapitalAllocationHandler_UpdateAndGet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := newTestCapitalAllocationHandler(t)
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/telegram/internal/allocation?chat_id=42", nil)
+	handler.GetAllocation(c)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"enforced":false`)
+
+	w := performTradingModeRequest(handler.UpdateAllocation, `{"chat_id":"42","action":"set","targets":{"scalping":0.6,"funding_arbitrage":0.3},"performance_weighted":true}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"enforced":true`)
+	assert.Contains(t, w.Body.String(), `"performance_weighted":true`)
+
+	w = performTradingModeRequest(handler.UpdateAllocation, `{"chat_id":"42","action":"record","strategy":"scalping","pnl":"12.5"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"pnl":"12.5"`)
+
+	w = performTradingModeRequest(handler.UpdateAllocation, `{"chat_id":"42","action":"rebalance"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"results":{}`)
+
+	w = performTradingModeRequest(handler.UpdateAllocation, `{"chat_id":"42","action":"clear"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"allocation":null`)
+}
+
+func TestCapitalAllocationHandler_InvalidRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := newTestCapitalAllocationHandler(t)
+
+	w := performTradingModeRequest(handler.UpdateAllocation, `{"chat_id":"42","action":"split"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(handler.UpdateAllocation, `{"chat_id":"42","action":"set","targets":{"scalping":0.9,"arbitrage":0.2}}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(handler.UpdateAllocation, `{"chat_id":"42","action":"set","targets":{"grid":0.5}}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(handler.UpdateAllocation, `{"chat_id":"42","action":"record","strategy":"scalping","pnl":"lots"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(handler.UpdateAllocation, `{"chat_id":"42","action":"rebalance"}`, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	unavailable := NewCapitalAllocationHandler(nil)
+	w = performTradingModeRequest(unavailable.UpdateAllocation, `{"chat_id":"42","action":"clear"}`, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// CarryReporter aggregates historical funding and basis into carry statistics.
+type CarryReporter interface {
+	Report(ctx context.Context, query services.CarryQuery) (*services.CarryReport, error)
+}
+
+// CarryAnalyticsHandler serves historical funding and spot-perp basis
+// statistics for evaluating carry strategies.
+type CarryAnalyticsHandler struct {
+	reporter CarryReporter
+}
+
+// NewCarryAnalyticsHandler creates a new carry analytics handler.
+//
+// Parameters:
+//
+//	reporter: The carry analytics service (may be nil without a funding history).
+//
+// Returns:
+//
+//	*CarryAnalyticsHandler: The initialized handler.
+func NewCarryAnalyticsHandler(reporter CarryReporter) *CarryAnalyticsHandler {
+	return &CarryAnalyticsHandler{reporter: reporter}
+}
+
+// GetCarry returns funding averages, percentiles, annualized carry and basis
+// per exchange and symbol, optionally filtered by symbol and exchange.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *CarryAnalyticsHandler) GetCarry(c *gin.Context) {
+	if h.reporter == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "carry analytics not available"})
+		return
+	}
+	query := services.CarryQuery{Symbol: c.Query("symbol"), Exchange: c.Query("exchange")}
+	for name, target := range map[string]*int{"days": &query.Days, "limit": &query.Limit} {
+		raw := c.Query(name)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": name + " must be a number"})
+			return
+		}
+		*target = value
+	}
+
+	report, err := h.reporter.Report(c.Request.Context(), query)
+	if errors.Is(err, services.ErrCarryInvalidQuery) {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": "failed to compute carry: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": report})
+}
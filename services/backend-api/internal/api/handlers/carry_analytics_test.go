@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubCarryReporter struct {
+	query services.CarryQuery
+}
+
+func (s *stubCarryReporter) Report(_ context.Context, query services.CarryQuery) (*services.CarryReport, error) {
+	s.query = query
+	if query.Days > 365 {
+		return nil, services.ErrCarryInvalidQuery
+	}
+	return &services.CarryReport{Days: query.Days, Markets: []services.CarryStats{
+		{Exchange: "binance", Symbol: "BTC/USDT", Samples: 90, IntervalHours: 8, AnnualizedCarryPct: 10.95},
+	}}, nil
+}
+
+func TestCarryAnalyticsHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	reporter := &stubCarryReporter{}
+	handler := NewCarryAnalyticsHandler(reporter)
+
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, url, nil)
+		handler.GetCarry(c)
+		return w
+	}
+
+	w := get("/api/v1/arbitrage/carry?symbol=BTC/USDT&exchange=binance&days=14&limit=5")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"annualized_carry_pct":10.95`)
+	assert.Equal(t, services.CarryQuery{Symbol: "BTC/USDT", Exchange: "binance", Days: 14, Limit: 5}, reporter.query)
+
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/arbitrage/carry?days=month").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/arbitrage/carry?days=400").Code)
+
+	w = performTradingModeRequest(NewCarryAnalyticsHandler(nil).GetCarry, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/chaos"
+)
+
+// FaultInjector starts and ends dependency faults.
+type FaultInjector interface {
+	Inject(target chaos.Target, duration, latency time.Duration) (chaos.Fault, error)
+	Clear(target chaos.Target) bool
+	ClearAll() int
+	Faults() []chaos.Fault
+}
+
+// ChaosHandler serves the admin fault injection API.
+type ChaosHandler struct {
+	injector FaultInjector
+}
+
+// InjectFaultRequest starts a fault on one dependency.
+type InjectFaultRequest struct {
+	// Target is redis, ccxt, llm or database.
+	Target string `json:"target" binding:"required"`
+	// DurationSeconds is the fault window; 0 uses the default of 5 minutes.
+	DurationSeconds int64 `json:"duration_seconds"`
+	// LatencyMs is the ccxt stall or database delay; 0 uses the target default.
+	LatencyMs int64 `json:"latency_ms"`
+}
+
+// ChaosStatusResponse lists the active faults.
+type ChaosStatusResponse struct {
+	Faults  []chaos.Fault  `json:"faults"`
+	Targets []chaos.Target `json:"targets"`
+	// Cleared is set by a clear request to the number of faults it ended.
+	Cleared int `json:"cleared,omitempty"`
+}
+
+// NewChaosHandler creates a new chaos handler.
+//
+// Parameters:
+//
+//	injector: The fault injector (nil unless CHAOS_ENABLED is true).
+//
+// Returns:
+//
+//	*ChaosHandler: The initialized handler.
+func NewChaosHandler(injector FaultInjector) *ChaosHandler {
+	return &ChaosHandler{injector: injector}
+}
+
+func (h *ChaosHandler) available(c *gin.Context) bool {
+	if h.injector == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "chaos testing is not enabled"})
+		return false
+	}
+	return true
+}
+
+func (h *ChaosHandler) respondStatus(c *gin.Context, cleared int) {
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": ChaosStatusResponse{
+		Faults:  h.injector.Faults(),
+		Targets: chaos.Targets(),
+		Cleared: cleared,
+	}})
+}
+
+// GetFaults lists the active faults.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *ChaosHandler) GetFaults(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	h.respondStatus(c, 0)
+}
+
+// InjectFault starts a fault for a bounded window.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *ChaosHandler) InjectFault(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req InjectFaultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "Invalid request body"})
+		return
+	}
+
+	fault, err := h.injector.Inject(chaos.Target(req.Target), time.Duration(req.DurationSeconds)*time.Second, time.Duration(req.LatencyMs)*time.Millisecond)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, chaos.ErrInvalidFault) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": fault})
+}
+
+// ClearFaults ends the fault on the target query parameter, or every fault
+// when no target is given.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *ChaosHandler) ClearFaults(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	cleared := 0
+	if target := c.Query("target"); target != "" {
+		if h.injector.Clear(chaos.Target(target)) {
+			cleared = 1
+		}
+	} else {
+		cleared = h.injector.ClearAll()
+	}
+	h.respondStatus(c, cleared)
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/chaos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChaosHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	injector := chaos.NewInjector(0)
+	handler := NewChaosHandler(injector)
+
+	w := performTradingModeRequest(handler.InjectFault, `{"target":"database","duration_seconds":60,"latency_ms":1500}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"kind":"slow"`)
+	assert.Contains(t, w.Body.String(), `"latency_ms":1500`)
+
+	w = performTradingModeRequest(handler.InjectFault, `{"target":"llm"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, injector.Faults(), 2)
+
+	w = performTradingModeRequest(handler.InjectFault, `{"target":"kafka"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = performTradingModeRequest(handler.InjectFault, `{"target":"redis","duration_seconds":86400}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = performTradingModeRequest(handler.InjectFault, `{}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(handler.GetFaults, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"target":"llm"`)
+
+	w = httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodDelete, "/api/v1/admin/chaos?target=llm", nil)
+	handler.ClearFaults(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"cleared":1`)
+	assert.Len(t, injector.Faults(), 1)
+
+	w = performTradingModeRequest(handler.ClearFaults, "", nil)
+	assert.Contains(t, w.Body.String(), `"cleared":1`)
+	assert.Empty(t, injector.Faults())
+
+	w = performTradingModeRequest(NewChaosHandler(nil).GetFaults, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+const (
+	defaultChartTimeframe = "15m"
+	defaultChartCandles   = 100
+	minChartCandles       = 20
+	maxChartCandles       = 300
+)
+
+var chartTimeframePattern = regexp.MustCompile(`^[0-9]{1,2}[mhdw]$`)
+
+// ChartSignalSource lists the active aggregated signals of a symbol.
+type ChartSignalSource interface {
+	GetAggregatedSignalsBySymbol(ctx context.Context, symbol string, limit int) ([]*services.AggregatedSignal, error)
+}
+
+// ChartHandler renders candle charts with indicators, open positions and
+// active signals for the Telegram /chart command.
+type ChartHandler struct {
+	analysis  *AnalysisHandler
+	positions services.OpenPositionSource
+	signals   ChartSignalSource
+}
+
+// ChartPosition is an open position drawn on a chart.
+type ChartPosition struct {
+	Side       string  `json:"side"`
+	Size       float64 `json:"size"`
+	EntryPrice float64 `json:"entry_price"`
+}
+
+// ChartSignal is an active signal marked on a chart.
+type ChartSignal struct {
+	Action     string    `json:"action"`
+	Type       string    `json:"type"`
+	Confidence float64   `json:"confidence"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ChartResponse is a rendered chart with the data annotated on it.
+type ChartResponse struct {
+	Symbol     string                 `json:"symbol"`
+	Exchange   string                 `json:"exchange"`
+	Timeframe  string                 `json:"timeframe"`
+	Candles    int                    `json:"candles"`
+	LastPrice  float64                `json:"last_price"`
+	MimeType   string                 `json:"mime_type"`
+	Image      string                 `json:"image"`
+	Indicators map[string]interface{} `json:"indicators"`
+	Positions  []ChartPosition        `json:"positions"`
+	Signals    []ChartSignal          `json:"signals"`
+}
+
+// NewChartHandler creates a new chart handler.
+//
+// Parameters:
+//
+//	analysis: The analysis handler whose market data and indicators are charted.
+//	positions: Open position source (may be nil).
+//	signals: Active signal source (may be nil).
+//
+// Returns:
+//
+//	*ChartHandler: The initialized handler.
+func NewChartHandler(analysis *AnalysisHandler, positions services.OpenPositionSource, signals ChartSignalSource) *ChartHandler {
+	return &ChartHandler{analysis: analysis, positions: positions, signals: signals}
+}
+
+// GetChart renders a candle chart of a symbol with SMA 20, EMA 12 and
+// Bollinger Bands, open position entries and active signal markers. It
+// supports query parameters: symbol, exchange (default "binance"),
+// timeframe (default "15m") and limit (candles, default 100).
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *ChartHandler) GetChart(c *gin.Context) {
+	symbol := normalizeChartSymbol(c.Query("symbol"))
+	if symbol == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "symbol is required"})
+		return
+	}
+	exchange := strings.ToLower(c.DefaultQuery("exchange", "binance"))
+	timeframe := strings.ToLower(c.DefaultQuery("timeframe", defaultChartTimeframe))
+	if !chartTimeframePattern.MatchString(timeframe) {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "invalid timeframe " + timeframe})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultChartCandles)))
+	if err != nil || limit < minChartCandles || limit > maxChartCandles {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": fmt.Sprintf("limit must be between %d and %d", minChartCandles, maxChartCandles)})
+		return
+	}
+
+	ctx := c.Request.Context()
+	candles, err := h.loadCandles(ctx, exchange, symbol, timeframe, limit)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	if len(candles) < minChartCandles {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "error": "not enough candles to chart " + symbol})
+		return
+	}
+
+	spec := services.ChartSpec{Candles: make([]services.ChartCandle, len(candles))}
+	for i, candle := range candles {
+		spec.Candles[i] = services.ChartCandle{
+			Time: candle.Timestamp, Open: candle.Open, High: candle.High,
+			Low: candle.Low, Close: candle.Close, Volume: candle.Volume,
+		}
+	}
+	spec.Series = h.indicatorSeries(candles)
+
+	response := ChartResponse{
+		Symbol:     symbol,
+		Exchange:   exchange,
+		Timeframe:  timeframe,
+		Candles:    len(candles),
+		LastPrice:  candles[len(candles)-1].Close,
+		MimeType:   "image/png",
+		Indicators: h.analysis.calculateIndicators(candles),
+		Positions:  h.chartPositions(ctx, exchange, symbol, &spec),
+		Signals:    h.chartSignals(ctx, symbol, &spec),
+	}
+
+	image, err := services.RenderChart(spec)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	response.Image = base64.StdEncoding.EncodeToString(image)
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": response})
+}
+
+// loadCandles reads stored candles, falling back to the exchange when too few
+// are stored.
+func (h *ChartHandler) loadCandles(ctx context.Context, exchange, symbol, timeframe string, limit int) ([]OHLCV, error) {
+	if h.analysis.db != nil {
+		rows, err := h.analysis.db.Query(ctx, `
+			SELECT timestamp, open, high, low, close, volume
+			FROM (
+				SELECT timestamp, open, high, low, close, volume
+				FROM ohlcv_data
+				WHERE symbol = $1 AND exchange_id = $2 AND timeframe = $3
+				ORDER BY timestamp DESC
+				LIMIT $4
+			) recent
+			ORDER BY timestamp ASC`, symbol, exchange, timeframe, limit)
+		if err == nil {
+			var candles []OHLCV
+			for rows.Next() {
+				var candle OHLCV
+				if err := rows.Scan(&candle.Timestamp, &candle.Open, &candle.High, &candle.Low, &candle.Close, &candle.Volume); err != nil {
+					continue
+				}
+				candles = append(candles, candle)
+			}
+			rows.Close()
+			if len(candles) >= minChartCandles {
+				return candles, nil
+			}
+		}
+	}
+
+	if h.analysis.ccxtService == nil {
+		return nil, fmt.Errorf("market data service not available")
+	}
+	resp, err := h.analysis.ccxtService.FetchOHLCV(ctx, exchange, symbol, timeframe, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch candles for %s: %w", symbol, err)
+	}
+	candles := make([]OHLCV, 0, len(resp.OHLCV))
+	for _, candle := range resp.OHLCV {
+		candles = append(candles, OHLCV{
+			Timestamp: candle.Timestamp,
+			Open:      candle.Open.InexactFloat64(),
+			High:      candle.High.InexactFloat64(),
+			Low:       candle.Low.InexactFloat64(),
+			Close:     candle.Close.InexactFloat64(),
+			Volume:    candle.Volume.InexactFloat64(),
+		})
+	}
+	return candles, nil
+}
+
+// indicatorSeries evaluates the analysis indicators at every candle.
+func (h *ChartHandler) indicatorSeries(candles []OHLCV) []services.ChartSeries {
+	sma := make([]float64, len(candles))
+	ema := make([]float64, len(candles))
+	upper := make([]float64, len(candles))
+	lower := make([]float64, len(candles))
+	for i := range candles {
+		window := candles[:i+1]
+		sma[i], ema[i], upper[i], lower[i] = math.NaN(), math.NaN(), math.NaN(), math.NaN()
+		if len(window) >= 20 {
+			sma[i] = h.analysis.calculateSMA(window, 20)
+			upper[i], _, lower[i] = h.analysis.calculateBollingerBands(window, 20, 2.0)
+		}
+		if len(window) >= 12 {
+			ema[i] = h.analysis.calculateEMA(window, 12)
+		}
+	}
+	return []services.ChartSeries{
+		{Name: "sma_20", Color: services.ChartBlue, Values: sma},
+		{Name: "ema_12", Color: services.ChartOrange, Values: ema},
+		{Name: "bb_upper", Color: services.ChartPurple, Values: upper},
+		{Name: "bb_lower", Color: services.ChartPurple, Values: lower},
+	}
+}
+
+// chartPositions adds the entries of open positions in the symbol as levels.
+func (h *ChartHandler) chartPositions(ctx context.Context, exchange, symbol string, spec *services.ChartSpec) []ChartPosition {
+	positions := []ChartPosition{}
+	if h.positions == nil {
+		return positions
+	}
+	open, err := h.positions.OpenPositions(ctx)
+	if err != nil {
+		return positions
+	}
+	for _, position := range open {
+		if !strings.EqualFold(position.Exchange, exchange) || normalizeChartSymbol(position.Symbol) != symbol {
+			continue
+		}
+		entry := position.EntryPrice.InexactFloat64()
+		positions = append(positions, ChartPosition{Side: position.Side, Size: position.Size.InexactFloat64(), EntryPrice: entry})
+		clr := services.ChartUp
+		if isShortSide(position.Side) {
+			clr = services.ChartDown
+		}
+		spec.Levels = append(spec.Levels, services.ChartLevel{Label: position.Side, Price: entry, Color: clr})
+	}
+	return positions
+}
+
+// chartSignals marks the active signals of the symbol.
+func (h *ChartHandler) chartSignals(ctx context.Context, symbol string, spec *services.ChartSpec) []ChartSignal {
+	signals := []ChartSignal{}
+	if h.signals == nil {
+		return signals
+	}
+	active, err := h.signals.GetAggregatedSignalsBySymbol(ctx, symbol, 10)
+	if err != nil {
+		return signals
+	}
+	for _, signal := range active {
+		action := strings.ToLower(signal.Action)
+		if action != "buy" && action != "sell" {
+			continue
+		}
+		signals = append(signals, ChartSignal{
+			Action:     action,
+			Type:       string(signal.SignalType),
+			Confidence: signal.Confidence.InexactFloat64(),
+			CreatedAt:  signal.CreatedAt,
+		})
+		spec.Markers = append(spec.Markers, services.ChartMarker{Time: signal.CreatedAt, Side: action})
+	}
+	return signals
+}
+
+func isShortSide(side string) bool {
+	side = strings.ToLower(side)
+	return side == "short" || side == "sell"
+}
+
+// normalizeChartSymbol turns BTCUSDT, btc-usdt or BTC/USDT:USDT into BTC/USDT.
+func normalizeChartSymbol(symbol string) string {
+	symbol, _, _ = strings.Cut(services.NormalizeSymbol(symbol), ":")
+	return symbol
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/api/handlers/testmocks"
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type staticOpenPositions []services.OpenPosition
+
+func (s staticOpenPositions) OpenPositions(context.Context) ([]services.OpenPosition, error) {
+	return s, nil
+}
+
+type staticChartSignals []*services.AggregatedSignal
+
+func (s staticChartSignals) GetAggregatedSignalsBySymbol(context.Context, string, int) ([]*services.AggregatedSignal, error) {
+	return s, nil
+}
+
+func TestChartHandler_GetChart(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	start := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	var candles []ccxt.OHLCV
+	for i := 0; i < 40; i++ {
+		price := decimal.NewFromInt(int64(100 + i))
+		candles = append(candles, ccxt.OHLCV{
+			Timestamp: start.Add(time.Duration(i) * 15 * time.Minute),
+			Open:      price, High: price.Add(decimal.NewFromInt(2)), Low: price.Sub(decimal.NewFromInt(2)),
+			Close: price.Add(decimal.NewFromInt(1)), Volume: decimal.NewFromInt(10),
+		})
+	}
+	mockCCXT := &testmocks.MockCCXTService{}
+	mockCCXT.On("FetchOHLCV", mock.Anything, "binance", "BTC/USDT", "15m", 100).
+		Return(&ccxt.OHLCVResponse{OHLCV: candles}, nil)
+
+	handler := NewChartHandler(NewAnalysisHandler(nil, mockCCXT),
+		staticOpenPositions{
+			{Exchange: "binance", Symbol: "BTC/USDT:USDT", Side: "long", Size: decimal.NewFromInt(1), EntryPrice: decimal.NewFromInt(120)},
+			{Exchange: "binance", Symbol: "ETH/USDT", Side: "long", Size: decimal.NewFromInt(1), EntryPrice: decimal.NewFromInt(3000)},
+		},
+		staticChartSignals{{Action: "buy", SignalType: services.SignalTypeTechnical, Confidence: decimal.NewFromFloat(0.8), CreatedAt: start.Add(5 * time.Hour)}})
+
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, url, nil)
+		handler.GetChart(c)
+		return w
+	}
+
+	w := get("/internal/telegram/chart?symbol=BTCUSDT")
+	require.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Data ChartResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "BTC/USDT", body.Data.Symbol)
+	assert.Equal(t, 40, body.Data.Candles)
+	assert.Contains(t, body.Data.Indicators, "sma_20")
+	require.Len(t, body.Data.Positions, 1)
+	assert.Equal(t, 120.0, body.Data.Positions[0].EntryPrice)
+	require.Len(t, body.Data.Signals, 1)
+
+	raw, err := base64.StdEncoding.DecodeString(body.Data.Image)
+	require.NoError(t, err)
+	img, err := png.Decode(bytes.NewReader(raw))
+	require.NoError(t, err)
+	assert.Equal(t, 960, img.Bounds().Dx())
+
+	assert.Equal(t, http.StatusBadRequest, get("/internal/telegram/chart").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/internal/telegram/chart?symbol=BTCUSDT&limit=5").Code)
+}
+
+func TestNormalizeChartSymbol(t *testing.T) {
+	assert.Equal(t, "BTC/USDT", normalizeChartSymbol("btcusdt"))
+	assert.Equal(t, "ETH/BTC", normalizeChartSymbol("ETH-BTC"))
+	assert.Equal(t, "SOL/USDT", normalizeChartSymbol("SOL/USDT:USDT"))
+	assert.Equal(t, "DOGE/USD", normalizeChartSymbol("DOGEUSD"))
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/middleware"
+)
+
+// APIVersion is the version of the HTTP API served under /api/v1.
+const APIVersion = "v1"
+
+// CompatResponse declares the backend version and the oldest client versions
+// it supports.
+type CompatResponse struct {
+	// ServerVersion is the backend build version.
+	ServerVersion string `json:"server_version"`
+	// APIVersion is the HTTP API version.
+	APIVersion string `json:"api_version"`
+	// MinClientVersions maps client names to their minimum supported version.
+	MinClientVersions map[string]string `json:"min_client_versions"`
+	// Client is the check of the caller's X-Client-Version, when sent.
+	Client *middleware.ClientCompat `json:"client,omitempty"`
+}
+
+// CompatHandler serves the client compatibility declaration.
+type CompatHandler struct {
+	serverVersion string
+	minVersions   map[string]string
+}
+
+// NewCompatHandler creates a new compatibility handler.
+//
+// Parameters:
+//
+//	serverVersion: Backend build version.
+//	minVersions: Minimum supported version by client name.
+//
+// Returns:
+//
+//	*CompatHandler: The initialized handler.
+func NewCompatHandler(serverVersion string, minVersions map[string]string) *CompatHandler {
+	if minVersions == nil {
+		minVersions = map[string]string{}
+	}
+	return &CompatHandler{serverVersion: serverVersion, minVersions: minVersions}
+}
+
+// GetCompat returns the minimum supported client versions and, when the
+// caller sends X-Client-Version, whether it is compatible.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *CompatHandler) GetCompat(c *gin.Context) {
+	response := CompatResponse{
+		ServerVersion:     h.serverVersion,
+		APIVersion:        APIVersion,
+		MinClientVersions: h.minVersions,
+	}
+	if compat, ok := middleware.CheckClientVersion(c.GetHeader(middleware.ClientVersionHeader), h.minVersions); ok {
+		response.Client = &compat
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompatHandler_GetCompat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewCompatHandler("1.5.0", map[string]string{
+		middleware.ClientCLI:      "1.2.0",
+		middleware.ClientTelegram: "1.0.0",
+	})
+	router := gin.New()
+	router.GET("/api/v1/compat", handler.GetCompat)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/compat", nil)
+	req.Header.Set(middleware.ClientVersionHeader, "neuratrade-cli/1.1.0")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response CompatResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "1.5.0", response.ServerVersion)
+	assert.Equal(t, APIVersion, response.APIVersion)
+	assert.Equal(t, "1.0.0", response.MinClientVersions[middleware.ClientTelegram])
+	require.NotNil(t, response.Client)
+	assert.False(t, response.Client.Compatible)
+	assert.Equal(t, "1.2.0", response.Client.MinVersion)
+
+	// Without a client header only the declaration is returned
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/compat", nil))
+	response = CompatResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Nil(t, response.Client)
+}
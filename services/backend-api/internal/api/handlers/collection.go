@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// OnDemandCollector collects a symbol outside the collector schedule.
+type OnDemandCollector interface {
+	CollectNow(ctx context.Context, req services.OnDemandCollectionRequest) (*services.OnDemandCollection, error)
+}
+
+// CollectionHandler serves on-demand market data collection.
+type CollectionHandler struct {
+	collector OnDemandCollector
+}
+
+// CollectNowRequest selects the market to collect.
+type CollectNowRequest struct {
+	Exchange string `json:"exchange"`
+	Symbol   string `json:"symbol"`
+	// Timeframe also fetches recent candles when set, e.g. "1m".
+	Timeframe string `json:"timeframe,omitempty"`
+	// Candles is how many recent candles are fetched (default 100).
+	Candles int `json:"candles,omitempty"`
+}
+
+// NewCollectionHandler creates a new on-demand collection handler.
+//
+// Parameters:
+//
+//	collector: The market data collector (may be nil).
+//
+// Returns:
+//
+//	*CollectionHandler: The initialized handler.
+func NewCollectionHandler(collector OnDemandCollector) *CollectionHandler {
+	return &CollectionHandler{collector: collector}
+}
+
+// CollectNow fetches and stores fresh data for one symbol immediately and
+// returns what was fetched.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *CollectionHandler) CollectNow(c *gin.Context) {
+	if h.collector == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "collector not available"})
+		return
+	}
+
+	var req CollectNowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "invalid request body"})
+		return
+	}
+
+	result, err := h.collector.CollectNow(c.Request.Context(), services.OnDemandCollectionRequest{
+		Exchange:  req.Exchange,
+		Symbol:    req.Symbol,
+		Timeframe: req.Timeframe,
+		Candles:   req.Candles,
+	})
+	if err != nil {
+		status := http.StatusBadGateway
+		if errors.Is(err, services.ErrInvalidCollectionRequest) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": result})
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubOnDemandCollector struct {
+	req services.OnDemandCollectionRequest
+}
+
+func (s *stubOnDemandCollector) CollectNow(_ context.Context, req services.OnDemandCollectionRequest) (*services.OnDemandCollection, error) {
+	switch req.Symbol {
+	case "bad":
+		return nil, fmt.Errorf("%w: malformed symbol", services.ErrInvalidCollectionRequest)
+	case "DOWN/USDT":
+		return nil, errors.New("exchange unavailable")
+	}
+	s.req = req
+	return &services.OnDemandCollection{Exchange: req.Exchange, Symbol: req.Symbol, Stored: true}, nil
+}
+
+func TestCollectionHandler_CollectNow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	collector := &stubOnDemandCollector{}
+	handler := NewCollectionHandler(collector)
+
+	w := performTradingModeRequest(handler.CollectNow, `{"exchange":"binance","symbol":"ETH/USDT","timeframe":"1m","candles":50}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"stored":true`)
+	assert.Equal(t, services.OnDemandCollectionRequest{Exchange: "binance", Symbol: "ETH/USDT", Timeframe: "1m", Candles: 50}, collector.req)
+
+	w = performTradingModeRequest(handler.CollectNow, `{"exchange":"binance","symbol":"bad"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(handler.CollectNow, `{"exchange":"binance","symbol":"DOWN/USDT"}`, nil)
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+
+	w = performTradingModeRequest(handler.CollectNow, `not json`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(NewCollectionHandler(nil).CollectNow, `{}`, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/config"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// ConfigInspector resolves the effective configuration of a mode.
+type ConfigInspector interface {
+	Effective(ctx context.Context, mode string) (*services.EffectiveConfig, error)
+}
+
+// ConfigHandler exposes the per-mode effective configuration.
+type ConfigHandler struct {
+	inspector ConfigInspector
+}
+
+// NewConfigHandler creates a new config handler.
+//
+// Parameters:
+//
+//	inspector: The config service (may be nil).
+//
+// Returns:
+//
+//	*ConfigHandler: The initialized handler.
+func NewConfigHandler(inspector ConfigInspector) *ConfigHandler {
+	return &ConfigHandler{inspector: inspector}
+}
+
+// GetEffective returns the configuration of the mode in the mode query
+// parameter, or of the active mode when it is omitted, with secrets redacted.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *ConfigHandler) GetEffective(c *gin.Context) {
+	if h.inspector == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "config service not available"})
+		return
+	}
+
+	effective, err := h.inspector.Effective(c.Request.Context(), c.Query("mode"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, config.ErrUnknownMode) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": effective})
+}
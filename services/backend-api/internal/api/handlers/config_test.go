@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/config"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubConfigInspector struct{}
+
+func (stubConfigInspector) Effective(_ context.Context, mode string) (*services.EffectiveConfig, error) {
+	switch mode {
+	case "", config.ModePaper:
+		return &services.EffectiveConfig{Mode: config.ModePaper, Active: true, Overrides: []string{}}, nil
+	case config.ModeLive:
+		return &services.EffectiveConfig{Mode: config.ModeLive, Overrides: []string{"arbitrage.max_trade_amount"}}, nil
+	case "broken":
+		return nil, errors.New("overlay failed")
+	}
+	return nil, fmt.Errorf("%w: %q", config.ErrUnknownMode, mode)
+}
+
+func TestConfigHandler_GetEffective(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewConfigHandler(stubConfigInspector{})
+
+	get := func(handler *ConfigHandler, query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/config/effective"+query, nil)
+		handler.GetEffective(c)
+		return w
+	}
+
+	w := get(handler, "?mode=live")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"overrides":["arbitrage.max_trade_amount"]`)
+
+	w = get(handler, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"active":true`)
+
+	assert.Equal(t, http.StatusBadRequest, get(handler, "?mode=staging").Code)
+	assert.Equal(t, http.StatusInternalServerError, get(handler, "?mode=broken").Code)
+	assert.Equal(t, http.StatusServiceUnavailable, get(NewConfigHandler(nil), "").Code)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// CriticalEscalationInterface lists and acknowledges critical alerts.
+type CriticalEscalationInterface interface {
+	List() []services.CriticalAlert
+	Acknowledge(id, by string) (*services.CriticalAlert, error)
+}
+
+// CriticalEscalationHandler exposes critical alert acknowledgment to operators.
+type CriticalEscalationHandler struct {
+	escalation CriticalEscalationInterface
+}
+
+// NewCriticalEscalationHandler creates a new critical escalation handler.
+//
+// Parameters:
+//
+//	escalation: The escalation service (may be nil when escalation is disabled).
+//
+// Returns:
+//
+//	*CriticalEscalationHandler: The initialized handler.
+func NewCriticalEscalationHandler(escalation CriticalEscalationInterface) *CriticalEscalationHandler {
+	return &CriticalEscalationHandler{escalation: escalation}
+}
+
+func (h *CriticalEscalationHandler) available(c *gin.Context) bool {
+	if h.escalation == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "critical alert escalation not enabled"})
+		return false
+	}
+	return true
+}
+
+// ListAlerts returns the tracked critical alerts, newest first.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *CriticalEscalationHandler) ListAlerts(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": h.escalation.List()})
+}
+
+// Acknowledge stops the escalation of a critical alert.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *CriticalEscalationHandler) Acknowledge(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req struct {
+		Operator string `json:"operator" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "operator is required"})
+		return
+	}
+
+	alert, err := h.escalation.Acknowledge(c.Param("id"), req.Operator)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, gin.H{"status": "success", "data": alert})
+	case errors.Is(err, services.ErrCriticalAlertNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+	}
+}
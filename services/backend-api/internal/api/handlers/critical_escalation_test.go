@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCriticalEscalationHandler_Acknowledge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	escalation := services.NewCriticalEscalationService(nil, services.CriticalEscalationConfig{AckTimeout: time.Minute})
+	alert := escalation.Track(7, "liquidation_proximity", "close to liquidation")
+	handler := NewCriticalEscalationHandler(escalation)
+
+	w := performTradingModeRequest(handler.Acknowledge, `{}`, gin.Params{{Key: "id", Value: alert.ID}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(handler.Acknowledge, `{"operator":"op-1"}`, gin.Params{{Key: "id", Value: "missing"}})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = performTradingModeRequest(handler.Acknowledge, `{"operator":"op-1"}`, gin.Params{{Key: "id", Value: alert.ID}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"acknowledged_by":"op-1"`)
+
+	w = performTradingModeRequest(handler.ListAlerts, ``, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), alert.ID)
+}
+
+func TestCriticalEscalationHandler_Disabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewCriticalEscalationHandler(nil)
+
+	w := performTradingModeRequest(handler.ListAlerts, ``, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
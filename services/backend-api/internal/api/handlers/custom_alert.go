@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// CustomAlertManager defines the operations on user-defined price and indicator alerts.
+type CustomAlertManager interface {
+	List(ctx context.Context, chatID string) ([]services.CustomAlert, error)
+	Create(ctx context.Context, chatID, expression string) (*services.CustomAlert, error)
+	SetActive(ctx context.Context, chatID, alertID string, active bool) error
+	Delete(ctx context.Context, chatID, alertID string) error
+	Preview(ctx context.Context, expression string) (*services.AlertPreview, error)
+}
+
+// CustomAlertHandler manages a chat's user-defined alerts.
+type CustomAlertHandler struct {
+	alerts CustomAlertManager
+}
+
+// CreateCustomAlertRequest creates an alert from a rule.
+type CreateCustomAlertRequest struct {
+	ChatID string `json:"chat_id" binding:"required"`
+	// Rule is e.g. "BTC/USDT RSI(14,1h) < 30" or "ETH/USDT price crosses 3000".
+	Rule string `json:"rule" binding:"required"`
+}
+
+// PreviewCustomAlertRequest previews a rule before it is saved.
+type PreviewCustomAlertRequest struct {
+	// Rule is e.g. "BTC/USDT price crosses above SMA(50,4h) AND funding < 0".
+	Rule string `json:"rule" binding:"required"`
+}
+
+// UpdateCustomAlertRequest pauses or resumes an alert.
+type UpdateCustomAlertRequest struct {
+	ChatID   string `json:"chat_id" binding:"required"`
+	IsActive *bool  `json:"is_active" binding:"required"`
+}
+
+// CustomAlertListResponse lists a chat's alerts.
+type CustomAlertListResponse struct {
+	Alerts []services.CustomAlert `json:"alerts"`
+}
+
+// CustomAlertChangeResponse acknowledges an update or deletion.
+type CustomAlertChangeResponse struct {
+	ID       string `json:"id"`
+	IsActive *bool  `json:"is_active,omitempty"`
+	Deleted  bool   `json:"deleted,omitempty"`
+}
+
+// NewCustomAlertHandler creates a new custom alert handler.
+//
+// Parameters:
+//
+//	alerts: The custom alert service (may be nil when disabled).
+//
+// Returns:
+//
+//	*CustomAlertHandler: The initialized handler.
+func NewCustomAlertHandler(alerts CustomAlertManager) *CustomAlertHandler {
+	return &CustomAlertHandler{alerts: alerts}
+}
+
+func (h *CustomAlertHandler) available(c *gin.Context) bool {
+	if h.alerts == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "custom alerts not available"})
+		return false
+	}
+	return true
+}
+
+// ListAlerts returns a chat's alerts.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *CustomAlertHandler) ListAlerts(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	chatID := c.Query("chat_id")
+	if chatID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "chat_id is required"})
+		return
+	}
+	alerts, err := h.alerts.List(c.Request.Context(), chatID)
+	if err != nil {
+		writeCustomAlertError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": CustomAlertListResponse{Alerts: alerts}})
+}
+
+// CreateAlert parses a rule and stores it as an active alert.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *CustomAlertHandler) CreateAlert(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req CreateCustomAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "chat_id and rule are required"})
+		return
+	}
+	alert, err := h.alerts.Create(c.Request.Context(), req.ChatID, req.Rule)
+	if err != nil {
+		writeCustomAlertError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"status": "success", "data": alert})
+}
+
+// PreviewAlert evaluates a rule against the last 30 days of market data
+// and reports how often it would have fired, without saving it.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *CustomAlertHandler) PreviewAlert(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req PreviewCustomAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "rule is required"})
+		return
+	}
+	preview, err := h.alerts.Preview(c.Request.Context(), req.Rule)
+	if err != nil {
+		writeCustomAlertError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": preview})
+}
+
+// UpdateAlert pauses or resumes an alert.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *CustomAlertHandler) UpdateAlert(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req UpdateCustomAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "chat_id and is_active are required"})
+		return
+	}
+	if err := h.alerts.SetActive(c.Request.Context(), req.ChatID, c.Param("id"), *req.IsActive); err != nil {
+		writeCustomAlertError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": CustomAlertChangeResponse{ID: c.Param("id"), IsActive: req.IsActive}})
+}
+
+// DeleteAlert removes an alert.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *CustomAlertHandler) DeleteAlert(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	chatID := c.Query("chat_id")
+	if chatID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "chat_id is required"})
+		return
+	}
+	if err := h.alerts.Delete(c.Request.Context(), chatID, c.Param("id")); err != nil {
+		writeCustomAlertError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": CustomAlertChangeResponse{ID: c.Param("id"), Deleted: true}})
+}
+
+func writeCustomAlertError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidAlertRule), errors.Is(err, services.ErrCustomAlertLimit):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrCustomAlertNotFound), errors.Is(err, services.ErrCustomAlertNoUser),
+		errors.Is(err, services.ErrAlertPreviewNoData):
+		status = http.StatusNotFound
+	}
+	c.JSON(status, gin.H{"status": "error", "error": err.Error()})
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubCustomAlerts struct {
+	alerts map[string]services.CustomAlert
+}
+
+func (s *stubCustomAlerts) List(_ context.Context, chatID string) ([]services.CustomAlert, error) {
+	if chatID != "42" {
+		return nil, services.ErrCustomAlertNoUser
+	}
+	alerts := []services.CustomAlert{}
+	for _, alert := range s.alerts {
+		alerts = append(alerts, alert)
+	}
+	return alerts, nil
+}
+
+func (s *stubCustomAlerts) Create(_ context.Context, _ string, expression string) (*services.CustomAlert, error) {
+	rule, err := services.ParseAlertRule(expression)
+	if err != nil {
+		return nil, err
+	}
+	alert := services.CustomAlert{ID: "a-1", Rule: rule, Expression: rule.String(), IsActive: true}
+	s.alerts[alert.ID] = alert
+	return &alert, nil
+}
+
+func (s *stubCustomAlerts) SetActive(_ context.Context, _ string, alertID string, active bool) error {
+	alert, ok := s.alerts[alertID]
+	if !ok {
+		return services.ErrCustomAlertNotFound
+	}
+	alert.IsActive = active
+	s.alerts[alertID] = alert
+	return nil
+}
+
+func (s *stubCustomAlerts) Delete(_ context.Context, _ string, alertID string) error {
+	if _, ok := s.alerts[alertID]; !ok {
+		return services.ErrCustomAlertNotFound
+	}
+	delete(s.alerts, alertID)
+	return nil
+}
+
+func (s *stubCustomAlerts) Preview(_ context.Context, expression string) (*services.AlertPreview, error) {
+	rule, err := services.ParseAlertRule(expression)
+	if err != nil {
+		return nil, err
+	}
+	if rule.Exchange == "okx" {
+		return nil, services.ErrAlertPreviewNoData
+	}
+	return &services.AlertPreview{Expression: rule.String(), Evaluations: 720, Triggers: 3}, nil
+}
+
+func TestCustomAlertHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	stub := &stubCustomAlerts{alerts: map[string]services.CustomAlert{}}
+	handler := NewCustomAlertHandler(stub)
+
+	w := performTradingModeRequest(handler.CreateAlert, `{"chat_id":"42","rule":"notify me when btc/usdt RSI(14,1h) < 30"}`, nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"expression":"BTC/USDT RSI(14,1h) \u003c 30"`)
+
+	w = performTradingModeRequest(handler.CreateAlert, `{"chat_id":"42","rule":"BTC/USDT MACD < 1"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid alert rule")
+
+	w = performTradingModeRequest(handler.UpdateAlert, `{"chat_id":"42","is_active":false}`, gin.Params{{Key: "id", Value: "a-1"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, stub.alerts["a-1"].IsActive)
+
+	w = performTradingModeRequest(handler.UpdateAlert, `{"chat_id":"42"}`, gin.Params{{Key: "id", Value: "a-1"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/telegram/internal/alerts/custom?chat_id=42", nil)
+	handler.ListAlerts(c)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"is_active":false`)
+
+	rec = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/telegram/internal/alerts/custom?chat_id=7", nil)
+	handler.ListAlerts(c)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	for _, want := range []int{http.StatusOK, http.StatusNotFound} {
+		rec = httptest.NewRecorder()
+		c, _ = gin.CreateTestContext(rec)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/api/v1/telegram/internal/alerts/custom/a-1?chat_id=42", nil)
+		c.Params = gin.Params{{Key: "id", Value: "a-1"}}
+		handler.DeleteAlert(c)
+		assert.Equal(t, want, rec.Code)
+	}
+}
+
+func TestCustomAlertHandler_PreviewAlert(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewCustomAlertHandler(&stubCustomAlerts{alerts: map[string]services.CustomAlert{}})
+
+	w := performTradingModeRequest(handler.PreviewAlert, `{"rule":"BTC/USDT price > 1 or rsi < 30"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"expression":"BTC/USDT price \u003e 1 OR RSI(14,1h) \u003c 30"`)
+	assert.Contains(t, w.Body.String(), `"triggers":3`)
+
+	w = performTradingModeRequest(handler.PreviewAlert, `{"rule":"BTC/USDT price >"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(handler.PreviewAlert, `{"rule":"BTC/USDT on okx price > 1"}`, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "not enough market data")
+}
+
+func TestCustomAlertHandler_Unavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewCustomAlertHandler(nil)
+
+	w := performTradingModeRequest(handler.CreateAlert, `{"chat_id":"42","rule":"BTC/USDT price > 1"}`, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
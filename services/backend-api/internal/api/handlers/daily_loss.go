@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/shopspring/decimal"
+)
+
+// DailyLossProvider defines the daily loss circuit operations.
+type DailyLossProvider interface {
+	Evaluate(ctx context.Context, chatID string) (*services.DailyLossState, error)
+	RecordRealized(ctx context.Context, chatID string, pnl decimal.Decimal) (*services.DailyLossState, error)
+	Config() services.DailyLossConfig
+}
+
+// DailyLossHandler exposes each chat's daily PnL and loss cap.
+type DailyLossHandler struct {
+	circuit DailyLossProvider
+}
+
+// RecordDailyPnLRequest reports a closed trade's realized profit or loss.
+type RecordDailyPnLRequest struct {
+	ChatID string `json:"chat_id" binding:"required"`
+	PnL    string `json:"pnl" binding:"required"`
+}
+
+// NewDailyLossHandler creates a new daily loss handler.
+//
+// Parameters:
+//
+//	circuit: The daily loss circuit (may be nil when Redis is unavailable).
+//
+// Returns:
+//
+//	*DailyLossHandler: The initialized handler.
+func NewDailyLossHandler(circuit DailyLossProvider) *DailyLossHandler {
+	return &DailyLossHandler{circuit: circuit}
+}
+
+// GetStatus re-evaluates and returns a chat's PnL for the current UTC day.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *DailyLossHandler) GetStatus(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	chatID := c.Query("chat_id")
+	if chatID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "chat_id is required"})
+		return
+	}
+	state, err := h.circuit.Evaluate(c.Request.Context(), chatID)
+	h.respond(c, state, err)
+}
+
+// RecordRealized adds a realized trade result to the chat's day.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *DailyLossHandler) RecordRealized(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req RecordDailyPnLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "Invalid request body"})
+		return
+	}
+	pnl, err := decimal.NewFromString(req.PnL)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "pnl must be numeric"})
+		return
+	}
+	state, err := h.circuit.RecordRealized(c.Request.Context(), req.ChatID, pnl)
+	h.respond(c, state, err)
+}
+
+func (h *DailyLossHandler) available(c *gin.Context) bool {
+	if h.circuit == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "daily loss circuit not available"})
+		return false
+	}
+	return true
+}
+
+func (h *DailyLossHandler) respond(c *gin.Context, state *services.DailyLossState, err error) {
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	config := h.circuit.Config()
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{
+		"state":             state,
+		"max_loss_pct":      config.MaxLossPct,
+		"max_loss":          config.MaxLoss,
+		"flatten_on_breach": config.FlattenOnBreach,
+	}})
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubDailyLoss struct {
+	state    services.DailyLossState
+	recorded decimal.Decimal
+}
+
+func (s *stubDailyLoss) Evaluate(_ context.Context, chatID string) (*services.DailyLossState, error) {
+	s.state.ChatID = chatID
+	return &s.state, nil
+}
+
+func (s *stubDailyLoss) RecordRealized(_ context.Context, chatID string, pnl decimal.Decimal) (*services.DailyLossState, error) {
+	s.recorded = s.recorded.Add(pnl)
+	s.state.RealizedPnL = s.recorded
+	return s.Evaluate(context.Background(), chatID)
+}
+
+func (s *stubDailyLoss) Config() services.DailyLossConfig {
+	return services.DailyLossConfig{MaxLossPct: 0.02}
+}
+
+func TestDailyLossHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	circuit := &stubDailyLoss{state: services.DailyLossState{Halted: true}}
+	handler := NewDailyLossHandler(circuit)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/telegram/internal/risk/daily-loss?chat_id=42", nil)
+	handler.GetStatus(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"halted":true`)
+	assert.Contains(t, w.Body.String(), `"max_loss_pct":0.02`)
+
+	w = performTradingModeRequest(handler.RecordRealized, `{"chat_id":"42","pnl":"-12.5"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.True(t, circuit.recorded.Equal(decimal.NewFromFloat(-12.5)))
+
+	w = performTradingModeRequest(handler.RecordRealized, `{"chat_id":"42","pnl":"lots"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(handler.GetStatus, "", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(NewDailyLossHandler(nil).GetStatus, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestReadinessChecker_DailyLossCap(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewAutonomousHandler(nil)
+	circuit := &stubDailyLoss{state: services.DailyLossState{Halted: true, PnL: decimal.NewFromInt(-25)}}
+	handler.SetDailyLossCircuit(circuit)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	result := handler.readiness.checkRiskLimits(c, "42")
+	assert.Equal(t, "warning", result.Status)
+	assert.Equal(t, "2%", result.Details["daily_loss_cap"])
+	assert.Equal(t, "-25.00", result.Details["daily_pnl"])
+
+	circuit.state.Halted = false
+	assert.Equal(t, "healthy", handler.readiness.checkRiskLimits(c, "42").Status)
+}
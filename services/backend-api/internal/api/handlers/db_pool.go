@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// DBPoolMetrics defines the pool monitor operations used by the metrics endpoint.
+type DBPoolMetrics interface {
+	Samples() []services.DBPoolSample
+	WaitThreshold() time.Duration
+}
+
+// DBPoolHandler exposes database connection pool statistics.
+type DBPoolHandler struct {
+	pools DBPoolMetrics
+}
+
+// NewDBPoolHandler creates a new pool metrics handler.
+//
+// Parameters:
+//
+//	pools: The pool monitor (may be nil when the database has no pgx pool).
+//
+// Returns:
+//
+//	*DBPoolHandler: The initialized handler.
+func NewDBPoolHandler(pools DBPoolMetrics) *DBPoolHandler {
+	return &DBPoolHandler{pools: pools}
+}
+
+// GetPoolMetrics returns the latest in-use, idle and wait statistics of each pool.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *DBPoolHandler) GetPoolMetrics(c *gin.Context) {
+	if h.pools == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "database pool metrics not available"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{
+		"pools":             h.pools.Samples(),
+		"wait_threshold_ms": float64(h.pools.WaitThreshold()) / float64(time.Millisecond),
+	}})
+}
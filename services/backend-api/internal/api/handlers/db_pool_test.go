@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubDBPoolMetrics struct{}
+
+func (stubDBPoolMetrics) Samples() []services.DBPoolSample {
+	return []services.DBPoolSample{{
+		PoolStats: database.PoolStats{Name: "api", MaxConns: 25, InUseConns: 20, IdleConns: 5},
+		AvgWaitMs: 150,
+		Saturated: true,
+	}}
+}
+
+func (stubDBPoolMetrics) WaitThreshold() time.Duration { return 100 * time.Millisecond }
+
+func TestDBPoolHandler_GetPoolMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/database/metrics", nil)
+	NewDBPoolHandler(stubDBPoolMetrics{}).GetPoolMetrics(c)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"api"`)
+	assert.Contains(t, w.Body.String(), `"in_use_conns":20`)
+	assert.Contains(t, w.Body.String(), `"saturated":true`)
+	assert.Contains(t, w.Body.String(), `"wait_threshold_ms":100`)
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/database/metrics", nil)
+	NewDBPoolHandler(nil).GetPoolMetrics(c)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// DecisionAuditProvider defines the decision audit lookups.
+type DecisionAuditProvider interface {
+	Get(ctx context.Context, id string) (*services.DecisionAudit, error)
+}
+
+// DecisionAuditHandler serves the full context of recorded decisions for
+// trade replay and post-mortems.
+type DecisionAuditHandler struct {
+	audits DecisionAuditProvider
+}
+
+// NewDecisionAuditHandler creates a new decision audit handler.
+//
+// Parameters:
+//
+//	audits: The decision audit store (may be nil without a database).
+//
+// Returns:
+//
+//	*DecisionAuditHandler: The initialized handler.
+func NewDecisionAuditHandler(audits DecisionAuditProvider) *DecisionAuditHandler {
+	return &DecisionAuditHandler{audits: audits}
+}
+
+// GetDecision returns a decision with its market snapshot, redacted prompt,
+// raw model output, order and outcome.
+//
+// Parameters:
+//
+//	c: Gin context with the decision ID in the "id" path parameter.
+func (h *DecisionAuditHandler) GetDecision(c *gin.Context) {
+	if h.audits == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "decision audit not available"})
+		return
+	}
+	id := strings.TrimSpace(c.Param("id"))
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "decision id is required"})
+		return
+	}
+	audit, err := h.audits.Get(c.Request.Context(), id)
+	if errors.Is(err, services.ErrDecisionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "error": "decision not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": audit})
+}
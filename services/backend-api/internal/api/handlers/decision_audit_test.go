@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubDecisionAudits struct {
+	audits map[string]*services.DecisionAudit
+	err    error
+}
+
+func (s *stubDecisionAudits) Get(_ context.Context, id string) (*services.DecisionAudit, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	audit, ok := s.audits[id]
+	if !ok {
+		return nil, services.ErrDecisionNotFound
+	}
+	return audit, nil
+}
+
+func TestDecisionAuditHandler_GetDecision(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	audits := &stubDecisionAudits{audits: map[string]*services.DecisionAudit{
+		"dec_1": {
+			ID:        "dec_1",
+			Source:    services.DecisionSourceAIScalping,
+			Symbol:    "BTC/USDT",
+			Action:    "buy",
+			RawOutput: `{"action":"buy"}`,
+			Order:     &services.DecisionAuditOrder{Status: services.DecisionOrderPlaced, OrderID: "ord-9"},
+		},
+	}}
+	handler := NewDecisionAuditHandler(audits)
+
+	w := performTradingModeRequest(handler.GetDecision, "", gin.Params{{Key: "id", Value: "dec_1"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"order_id":"ord-9"`)
+	assert.Contains(t, w.Body.String(), `"source":"ai_scalping"`)
+
+	w = performTradingModeRequest(handler.GetDecision, "", gin.Params{{Key: "id", Value: "dec_2"}})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = performTradingModeRequest(handler.GetDecision, "", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	audits.err = errors.New("db down")
+	w = performTradingModeRequest(handler.GetDecision, "", gin.Params{{Key: "id", Value: "dec_1"}})
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	w = performTradingModeRequest(NewDecisionAuditHandler(nil).GetDecision, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
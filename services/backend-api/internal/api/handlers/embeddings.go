@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// maxOperatorNoteLength bounds the text of an operator note.
+const maxOperatorNoteLength = 8000
+
+// EmbeddingPipelineProvider queues texts for embedding and reports progress.
+type EmbeddingPipelineProvider interface {
+	Enqueue(job services.EmbeddingJob) bool
+	Stats() services.EmbeddingPipelineStats
+}
+
+// EmbeddingHandler serves the embedding pipeline endpoints.
+type EmbeddingHandler struct {
+	pipeline EmbeddingPipelineProvider
+}
+
+// NewEmbeddingHandler creates a new embedding handler.
+//
+// Parameters:
+//
+//	pipeline: The embedding pipeline (may be nil without a vector store).
+//
+// Returns:
+//
+//	*EmbeddingHandler: The initialized handler.
+func NewEmbeddingHandler(pipeline EmbeddingPipelineProvider) *EmbeddingHandler {
+	return &EmbeddingHandler{pipeline: pipeline}
+}
+
+// AddNoteRequest is an operator note to embed.
+type AddNoteRequest struct {
+	// ID replaces an earlier note with the same id; generated when empty.
+	ID   string `json:"id"`
+	Text string `json:"text" binding:"required"`
+	// UserID is the user the note is about; it is deleted when the user's
+	// data is erased.
+	UserID string `json:"user_id,omitempty"`
+}
+
+// AddNote queues an operator note for embedding.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *EmbeddingHandler) AddNote(c *gin.Context) {
+	if h.pipeline == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "embedding pipeline not available"})
+		return
+	}
+	var req AddNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "text is required"})
+		return
+	}
+	req.Text = strings.TrimSpace(req.Text)
+	if req.Text == "" || len(req.Text) > maxOperatorNoteLength {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "text must be 1 to 8000 characters"})
+		return
+	}
+	if req.ID == "" {
+		req.ID = uuid.New().String()
+	}
+
+	if !h.pipeline.Enqueue(services.EmbeddingJob{
+		Kind:      services.EmbeddingKindNote,
+		SourceID:  req.ID,
+		Text:      req.Text,
+		UserID:    strings.TrimSpace(req.UserID),
+		CreatedAt: time.Now().UTC(),
+	}) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": services.ErrEmbeddingQueueFull.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"status": "success", "data": gin.H{"id": req.ID}})
+}
+
+// GetStats returns the embedding pipeline counters and cost.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *EmbeddingHandler) GetStats(c *gin.Context) {
+	if h.pipeline == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "embedding pipeline not available"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": h.pipeline.Stats()})
+}
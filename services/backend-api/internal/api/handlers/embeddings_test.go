@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingEmbeddingPipeline struct {
+	jobs []services.EmbeddingJob
+	full bool
+}
+
+func (r *recordingEmbeddingPipeline) Enqueue(job services.EmbeddingJob) bool {
+	if r.full {
+		return false
+	}
+	r.jobs = append(r.jobs, job)
+	return true
+}
+
+func (r *recordingEmbeddingPipeline) Stats() services.EmbeddingPipelineStats {
+	return services.EmbeddingPipelineStats{Provider: "hashing", Embedded: int64(len(r.jobs))}
+}
+
+func TestEmbeddingHandler_AddNote(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	pipeline := &recordingEmbeddingPipeline{}
+	handler := NewEmbeddingHandler(pipeline)
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/embeddings/notes", strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.AddNote(c)
+		return w
+	}
+
+	w := post(`{"id":"unlocks","text":"  Avoid SOL longs during the unlock week "}`)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"unlocks"`)
+	require.Len(t, pipeline.jobs, 1)
+	assert.Equal(t, services.EmbeddingKindNote, pipeline.jobs[0].Kind)
+	assert.Equal(t, "Avoid SOL longs during the unlock week", pipeline.jobs[0].Text)
+
+	assert.Equal(t, http.StatusAccepted, post(`{"text":"funding flips"}`).Code)
+	assert.NotEmpty(t, pipeline.jobs[1].SourceID, "an id is generated")
+
+	assert.Equal(t, http.StatusBadRequest, post(`{}`).Code)
+	assert.Equal(t, http.StatusBadRequest, post(`{"text":"   "}`).Code)
+	assert.Equal(t, http.StatusBadRequest, post(`{"text":"`+strings.Repeat("x", maxOperatorNoteLength+1)+`"}`).Code)
+
+	pipeline.full = true
+	assert.Equal(t, http.StatusServiceUnavailable, post(`{"text":"dropped"}`).Code)
+
+	w = performTradingModeRequest(NewEmbeddingHandler(nil).AddNote, `{"text":"x"}`, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestEmbeddingHandler_GetStats(t *testing.T) {
+	w := performTradingModeRequest(NewEmbeddingHandler(&recordingEmbeddingPipeline{}).GetStats, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"provider":"hashing"`)
+
+	w = performTradingModeRequest(NewEmbeddingHandler(nil).GetStats, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
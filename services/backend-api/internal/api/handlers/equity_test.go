@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubEquitySource struct {
+	chatID string
+}
+
+func (s *stubEquitySource) Snapshot(_ context.Context, chatID string) *services.EquitySnapshot {
+	s.chatID = chatID
+	return &services.EquitySnapshot{
+		Currency:         "USDT",
+		TotalEquity:      decimal.NewFromInt(2000),
+		AvailableBalance: decimal.NewFromInt(1500),
+		ExchangeBalance:  decimal.NewFromInt(2000),
+		Exposure:         decimal.NewFromInt(500),
+		UpdatedAt:        time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestAutonomousHandler_GetEquity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewAutonomousHandler(nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/telegram/internal/equity", nil)
+	handler.GetEquity(c)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	equity := &stubEquitySource{}
+	handler.SetEquitySource(equity)
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/telegram/internal/equity?chat_id=42", nil)
+	handler.GetEquity(c)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "42", equity.chatID)
+	assert.Contains(t, w.Body.String(), `"total_equity":"2000"`)
+	assert.Contains(t, w.Body.String(), `"status":"success"`)
+}
+
+func TestAutonomousHandler_PortfolioUsesEquity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewAutonomousHandler(nil)
+	handler.SetEquitySource(&stubEquitySource{})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/telegram/internal/portfolio?chat_id=42", nil)
+	handler.GetPortfolio(c)
+	require.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `"total_equity":"2000.00"`)
+	assert.Contains(t, body, `"available_balance":"1500.00"`)
+	assert.Contains(t, body, `"exposure":"25.0%"`)
+	assert.Contains(t, body, `"updated_at":"2026-01-02T03:04:05Z"`)
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// ExchangeHealthProvider defines the exchange outage detection operations.
+type ExchangeHealthProvider interface {
+	Health(ctx context.Context) ([]services.ExchangeHealth, error)
+	Check(ctx context.Context) ([]services.ExchangeHealth, error)
+}
+
+// ExchangeHealthHandler exposes per-exchange health and strategy pauses.
+type ExchangeHealthHandler struct {
+	detector ExchangeHealthProvider
+}
+
+// NewExchangeHealthHandler creates a new exchange health handler.
+//
+// Parameters:
+//
+//	detector: The outage detector (may be nil when Redis is unavailable).
+//
+// Returns:
+//
+//	*ExchangeHealthHandler: The initialized handler.
+func NewExchangeHealthHandler(detector ExchangeHealthProvider) *ExchangeHealthHandler {
+	return &ExchangeHealthHandler{detector: detector}
+}
+
+// GetHealth returns the latest health of every collected exchange.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *ExchangeHealthHandler) GetHealth(c *gin.Context) {
+	h.respond(c, false)
+}
+
+// CheckNow assesses exchange health now and returns the result.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *ExchangeHealthHandler) CheckNow(c *gin.Context) {
+	h.respond(c, true)
+}
+
+func (h *ExchangeHealthHandler) respond(c *gin.Context, check bool) {
+	if h.detector == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "exchange outage detector not available"})
+		return
+	}
+	load := h.detector.Health
+	if check {
+		load = h.detector.Check
+	}
+	health, err := load(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	paused := []string{}
+	for _, exchange := range health {
+		if exchange.Paused {
+			paused = append(paused, exchange.Exchange)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{
+		"exchanges": health,
+		"paused":    paused,
+	}})
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubExchangeHealth struct {
+	health []services.ExchangeHealth
+	err    error
+}
+
+func (s *stubExchangeHealth) Health(context.Context) ([]services.ExchangeHealth, error) {
+	return s.health, s.err
+}
+
+func (s *stubExchangeHealth) Check(context.Context) ([]services.ExchangeHealth, error) {
+	return s.health, s.err
+}
+
+func TestExchangeHealthHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	detector := &stubExchangeHealth{health: []services.ExchangeHealth{
+		{Exchange: "binance", Degraded: true, Paused: true, Reasons: []string{"error rate 80%"}},
+		{Exchange: "kraken"},
+	}}
+	handler := NewExchangeHealthHandler(detector)
+
+	w := performTradingModeRequest(handler.GetHealth, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"paused":["binance"]`)
+	assert.Contains(t, w.Body.String(), `"reasons":["error rate 80%"]`)
+
+	detector.err = errors.New("redis down")
+	w = performTradingModeRequest(handler.CheckNow, "", nil)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	w = performTradingModeRequest(NewExchangeHealthHandler(nil).GetHealth, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/shopspring/decimal"
+)
+
+// AlgoOrderManager defines the execution algorithm operations.
+type AlgoOrderManager interface {
+	Submit(ctx context.Context, req services.AlgoOrderRequest) (*services.AlgoOrder, error)
+	Get(id string) (*services.AlgoOrder, error)
+	List() []services.AlgoOrder
+	Cancel(id string) (*services.AlgoOrder, error)
+}
+
+// AlgoOrderHandler exposes TWAP and iceberg parent orders.
+type AlgoOrderHandler struct {
+	algos AlgoOrderManager
+}
+
+// SubmitAlgoOrderRequest starts a TWAP or iceberg parent order.
+type SubmitAlgoOrderRequest struct {
+	Exchange string `json:"exchange" binding:"required"`
+	Symbol   string `json:"symbol" binding:"required"`
+	Side     string `json:"side" binding:"required"`
+	// Algo is twap or iceberg.
+	Algo   string `json:"algo" binding:"required"`
+	Amount string `json:"amount" binding:"required"`
+	Slices int    `json:"slices"`
+	// Duration is a Go duration such as 30m.
+	Duration          string  `json:"duration"`
+	VisibleAmount     string  `json:"visible_amount"`
+	LimitPrice        string  `json:"limit_price"`
+	MaxAdverseMovePct float64 `json:"max_adverse_move_pct"`
+}
+
+// NewAlgoOrderHandler creates a new execution algorithm handler.
+//
+// Parameters:
+//
+//	algos: The algo order manager (may be nil when no executor is configured).
+//
+// Returns:
+//
+//	*AlgoOrderHandler: The initialized handler.
+func NewAlgoOrderHandler(algos AlgoOrderManager) *AlgoOrderHandler {
+	return &AlgoOrderHandler{algos: algos}
+}
+
+// SubmitAlgoOrder starts executing a parent order.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *AlgoOrderHandler) SubmitAlgoOrder(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req SubmitAlgoOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "Invalid request body"})
+		return
+	}
+
+	parsed := services.AlgoOrderRequest{
+		Exchange:          req.Exchange,
+		Symbol:            req.Symbol,
+		Side:              req.Side,
+		Algo:              req.Algo,
+		Slices:            req.Slices,
+		MaxAdverseMovePct: req.MaxAdverseMovePct,
+	}
+	var err error
+	if parsed.Amount, err = decimal.NewFromString(req.Amount); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "amount must be numeric"})
+		return
+	}
+	if req.VisibleAmount != "" {
+		if parsed.VisibleAmount, err = decimal.NewFromString(req.VisibleAmount); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "visible_amount must be numeric"})
+			return
+		}
+	}
+	if req.LimitPrice != "" {
+		price, err := decimal.NewFromString(req.LimitPrice)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "limit_price must be numeric"})
+			return
+		}
+		parsed.LimitPrice = &price
+	}
+	if req.Duration != "" {
+		if parsed.Duration, err = time.ParseDuration(req.Duration); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "duration must be a duration such as 30m"})
+			return
+		}
+	}
+
+	order, err := h.algos.Submit(c.Request.Context(), parsed)
+	if errors.Is(err, services.ErrAlgoTradingHalted) || errors.Is(err, services.ErrAlgoNotLive) {
+		c.JSON(http.StatusConflict, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"status": "success", "data": order})
+}
+
+// ListAlgoOrders returns every parent order, newest first.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *AlgoOrderHandler) ListAlgoOrders(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"orders": h.algos.List()}})
+}
+
+// GetAlgoOrder returns a parent order and its child orders.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *AlgoOrderHandler) GetAlgoOrder(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	order, err := h.algos.Get(c.Param("id"))
+	h.respondOrder(c, order, err)
+}
+
+// CancelAlgoOrder stops a parent order and cancels its resting child order.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *AlgoOrderHandler) CancelAlgoOrder(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	order, err := h.algos.Cancel(c.Param("id"))
+	h.respondOrder(c, order, err)
+}
+
+func (h *AlgoOrderHandler) available(c *gin.Context) bool {
+	if h.algos == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "algo orders not available"})
+		return false
+	}
+	return true
+}
+
+func (h *AlgoOrderHandler) respondOrder(c *gin.Context, order *services.AlgoOrder, err error) {
+	if errors.Is(err, services.ErrAlgoOrderNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": order})
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubAlgoOrders struct {
+	submitted []services.AlgoOrderRequest
+}
+
+func (s *stubAlgoOrders) Submit(ctx context.Context, req services.AlgoOrderRequest) (*services.AlgoOrder, error) {
+	if req.Algo == "vwap" {
+		return nil, fmt.Errorf("algo must be twap or iceberg")
+	}
+	if req.Exchange == "halted" {
+		return nil, services.ErrAlgoTradingHalted
+	}
+	s.submitted = append(s.submitted, req)
+	return &services.AlgoOrder{ID: "algo-1", AlgoOrderRequest: req, Status: services.AlgoOrderRunning}, nil
+}
+
+func (s *stubAlgoOrders) Get(id string) (*services.AlgoOrder, error) {
+	if id != "algo-1" {
+		return nil, services.ErrAlgoOrderNotFound
+	}
+	return &services.AlgoOrder{ID: id, Status: services.AlgoOrderRunning}, nil
+}
+
+func (s *stubAlgoOrders) List() []services.AlgoOrder {
+	return []services.AlgoOrder{{ID: "algo-1"}}
+}
+
+func (s *stubAlgoOrders) Cancel(id string) (*services.AlgoOrder, error) {
+	if id != "algo-1" {
+		return nil, services.ErrAlgoOrderNotFound
+	}
+	return &services.AlgoOrder{ID: id, Status: services.AlgoOrderCancelled, Reason: "cancelled"}, nil
+}
+
+func TestAlgoOrderHandler(t *testing.T) {
+	algos := &stubAlgoOrders{}
+	handler := NewAlgoOrderHandler(algos)
+
+	w := performTradingModeRequest(handler.SubmitAlgoOrder, `{"exchange":"binance","symbol":"ETH/USDT","side":"sell","algo":"iceberg","amount":"5","visible_amount":"1","limit_price":"2500","duration":"30m"}`, nil)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	require.Len(t, algos.submitted, 1)
+	assert.Equal(t, 30*time.Minute, algos.submitted[0].Duration)
+	assert.Equal(t, "2500", algos.submitted[0].LimitPrice.String())
+	assert.Equal(t, "1", algos.submitted[0].VisibleAmount.String())
+
+	w = performTradingModeRequest(handler.SubmitAlgoOrder, `{"exchange":"binance","symbol":"ETH/USDT","side":"buy","algo":"twap","amount":"5","duration":"soon"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = performTradingModeRequest(handler.SubmitAlgoOrder, `{"exchange":"binance","symbol":"ETH/USDT","side":"buy","algo":"vwap","amount":"5"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "algo must be twap or iceberg")
+	w = performTradingModeRequest(handler.SubmitAlgoOrder, `{"exchange":"halted","symbol":"ETH/USDT","side":"buy","algo":"twap","amount":"5"}`, nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = performTradingModeRequest(handler.ListAlgoOrders, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"algo-1"`)
+
+	w = performTradingModeRequest(handler.GetAlgoOrder, "", gin.Params{{Key: "id", Value: "missing"}})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = performTradingModeRequest(handler.CancelAlgoOrder, "", gin.Params{{Key: "id", Value: "algo-1"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"cancelled"`)
+
+	w = performTradingModeRequest(NewAlgoOrderHandler(nil).ListAlgoOrders, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// ExecutionQualityProvider reports maker-first execution statistics.
+type ExecutionQualityProvider interface {
+	ExecutionQuality() services.ExecutionQualityStats
+}
+
+// ExecutionQualityHandler exposes execution-quality analytics.
+type ExecutionQualityHandler struct {
+	quality ExecutionQualityProvider
+}
+
+// NewExecutionQualityHandler creates a new execution-quality handler.
+//
+// Parameters:
+//
+//	quality: The statistics source (may be nil when no executor is configured).
+//
+// Returns:
+//
+//	*ExecutionQualityHandler: The initialized handler.
+func NewExecutionQualityHandler(quality ExecutionQualityProvider) *ExecutionQualityHandler {
+	return &ExecutionQualityHandler{quality: quality}
+}
+
+// GetExecutionQuality returns the maker fill rate, taker fallbacks and
+// estimated fee savings of maker-first orders.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *ExecutionQualityHandler) GetExecutionQuality(c *gin.Context) {
+	if h.quality == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "execution quality not available"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": h.quality.ExecutionQuality()})
+}
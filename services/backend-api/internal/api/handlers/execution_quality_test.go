@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubExecutionQuality struct{}
+
+func (stubExecutionQuality) ExecutionQuality() services.ExecutionQualityStats {
+	return services.ExecutionQualityStats{Orders: 4, MakerFilled: 3, TakerFallbacks: 1, MakerFillRate: 0.75, EstimatedFeeSavings: decimal.NewFromFloat(1.5)}
+}
+
+func TestExecutionQualityHandler(t *testing.T) {
+	w := performTradingModeRequest(NewExecutionQualityHandler(stubExecutionQuality{}).GetExecutionQuality, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"maker_fill_rate":0.75`)
+	assert.Contains(t, w.Body.String(), `"estimated_fee_savings":"1.5"`)
+
+	w = performTradingModeRequest(NewExecutionQualityHandler(nil).GetExecutionQuality, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
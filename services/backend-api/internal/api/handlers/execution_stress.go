@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// StressTester runs the execution stress test.
+type StressTester interface {
+	Run(ctx context.Context, query services.StressTestQuery) (*services.StressReport, error)
+}
+
+// StressTestHandler serves the latency and fee stress test.
+type StressTestHandler struct {
+	stress StressTester
+}
+
+// RunStressTestRequest selects the market and stress scenarios.
+type RunStressTestRequest struct {
+	Exchange  string `json:"exchange"`
+	Symbol    string `json:"symbol"`
+	Timeframe string `json:"timeframe"`
+	// Candles is how many recent candles are replayed.
+	Candles int `json:"candles"`
+	// Scenarios default to latency, partial fills, high fees and all three combined.
+	Scenarios []services.StressScenario `json:"scenarios,omitempty"`
+}
+
+// NewStressTestHandler creates a new stress-test handler.
+//
+// Parameters:
+//
+//	stress: The stress-test service (may be nil).
+//
+// Returns:
+//
+//	*StressTestHandler: The initialized handler.
+func NewStressTestHandler(stress StressTester) *StressTestHandler {
+	return &StressTestHandler{stress: stress}
+}
+
+// RunStressTest replays recent candles through the strategy and reports its
+// PnL under baseline and stressed execution.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *StressTestHandler) RunStressTest(c *gin.Context) {
+	if h.stress == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "stress test not available"})
+		return
+	}
+
+	var req RunStressTestRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "invalid request body"})
+			return
+		}
+	}
+
+	report, err := h.stress.Run(c.Request.Context(), services.StressTestQuery{
+		Exchange:  req.Exchange,
+		Symbol:    req.Symbol,
+		Timeframe: req.Timeframe,
+		Candles:   req.Candles,
+		Scenarios: req.Scenarios,
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrStressTestInvalidQuery) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": report})
+}
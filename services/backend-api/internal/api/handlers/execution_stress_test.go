@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubStressTester struct {
+	query services.StressTestQuery
+}
+
+func (s *stubStressTester) Run(_ context.Context, query services.StressTestQuery) (*services.StressReport, error) {
+	if len(query.Scenarios) > 0 && query.Scenarios[0].FillRatio > 1 {
+		return nil, fmt.Errorf("%w: scenario 1 needs fill_ratio in [0, 1]", services.ErrStressTestInvalidQuery)
+	}
+	s.query = query
+	return &services.StressReport{
+		Symbol:   query.Symbol,
+		Baseline: services.StressRun{Trades: 3, NetPnL: decimal.NewFromInt(12)},
+		Robust:   true,
+	}, nil
+}
+
+func TestStressTestHandler_RunStressTest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tester := &stubStressTester{}
+	handler := NewStressTestHandler(tester)
+
+	w := performTradingModeRequest(handler.RunStressTest, `{"symbol":"ETH/USDT","candles":300,"scenarios":[{"name":"slow","latency_ms":5000}]}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"net_pnl":"12"`)
+	assert.Equal(t, 300, tester.query.Candles)
+	assert.Equal(t, []services.StressScenario{{Name: "slow", LatencyMs: 5000}}, tester.query.Scenarios)
+
+	w = performTradingModeRequest(handler.RunStressTest, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = performTradingModeRequest(handler.RunStressTest, `{"scenarios":[{"fill_ratio":2}]}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(NewStressTestHandler(nil).RunStressTest, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
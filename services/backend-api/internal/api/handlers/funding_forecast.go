@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// FundingForecastProvider lists funding rate forecasts.
+type FundingForecastProvider interface {
+	Forecasts(exchange string) []services.FundingForecast
+	Forecast(exchange, symbol string) (services.FundingForecast, bool)
+}
+
+// FundingForecastHandler serves next-interval funding rate forecasts.
+type FundingForecastHandler struct {
+	forecaster FundingForecastProvider
+}
+
+// NewFundingForecastHandler creates a new funding forecast handler.
+//
+// Parameters:
+//
+//	forecaster: The funding forecaster (may be nil when forecasting is disabled).
+//
+// Returns:
+//
+//	*FundingForecastHandler: The initialized handler.
+func NewFundingForecastHandler(forecaster FundingForecastProvider) *FundingForecastHandler {
+	return &FundingForecastHandler{forecaster: forecaster}
+}
+
+// GetForecasts returns funding forecasts, optionally filtered by exchange,
+// symbol, or to perps whose funding is forecast to flip sign.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *FundingForecastHandler) GetForecasts(c *gin.Context) {
+	if h.forecaster == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "funding forecasts not available"})
+		return
+	}
+	exchange := strings.ToLower(c.Query("exchange"))
+	if symbol := c.Query("symbol"); symbol != "" {
+		if exchange == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "exchange is required with symbol"})
+			return
+		}
+		forecast, ok := h.forecaster.Forecast(exchange, symbol)
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "error": "no forecast for " + symbol + " on " + exchange})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "success", "data": forecast})
+		return
+	}
+
+	flipsOnly := c.Query("flips") == "true"
+	forecasts := []services.FundingForecast{}
+	for _, forecast := range h.forecaster.Forecasts(exchange) {
+		if !flipsOnly || forecast.Flip {
+			forecasts = append(forecasts, forecast)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": forecasts, "count": len(forecasts)})
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFundingForecastHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	forecaster := services.NewFundingForecaster(nil, nil, services.FundingForecastConfig{})
+	forecaster.Observe("binance", ccxt.FundingRate{Symbol: "BTC/USDT:USDT", FundingRate: -0.0001, MarkPrice: 100.2, IndexPrice: 100})
+	forecaster.Observe("binance", ccxt.FundingRate{Symbol: "ETH/USDT:USDT", FundingRate: 0.0001})
+	handler := NewFundingForecastHandler(forecaster)
+
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, url, nil)
+		handler.GetForecasts(c)
+		return w
+	}
+
+	w := get("/api/v1/arbitrage/funding-forecasts?exchange=binance")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"count":2`)
+
+	w = get("/api/v1/arbitrage/funding-forecasts?flips=true")
+	assert.Contains(t, w.Body.String(), `"count":1`)
+	assert.Contains(t, w.Body.String(), `"symbol":"BTC/USDT:USDT"`)
+
+	w = get("/api/v1/arbitrage/funding-forecasts?exchange=binance&symbol=ETH/USDT")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"flip":false`)
+
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/arbitrage/funding-forecasts?exchange=bybit&symbol=ETH/USDT").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/arbitrage/funding-forecasts?symbol=ETH/USDT").Code)
+
+	w = performTradingModeRequest(NewFundingForecastHandler(nil).GetForecasts, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -25,12 +25,26 @@ type RedisHealthChecker interface {
 	HealthCheck(ctx context.Context) error
 }
 
+// LoadSheddingReporter reports the active load shedding level.
+type LoadSheddingReporter interface {
+	// LoadShedding returns the current load shedding state.
+	LoadShedding() services.LoadSheddingStatus
+}
+
+// DrainReporter reports whether a graceful shutdown is draining the server.
+type DrainReporter interface {
+	// Draining returns true once shutdown has started.
+	Draining() bool
+}
+
 // HealthHandler manages health check endpoints.
 type HealthHandler struct {
 	db             DatabaseHealthChecker
 	redis          RedisHealthChecker
 	ccxtURL        string
 	cacheAnalytics CacheAnalyticsInterface
+	loadShedding   LoadSheddingReporter
+	drain          DrainReporter
 }
 
 // HealthResponse represents the health status response.
@@ -49,6 +63,8 @@ type HealthResponse struct {
 	CacheMetrics *services.CacheMetrics `json:"cache_metrics,omitempty"`
 	// CacheStats contains cache statistics if available.
 	CacheStats map[string]services.CacheStats `json:"cache_stats,omitempty"`
+	// LoadShedding is the load shedding state if load is monitored.
+	LoadShedding *services.LoadSheddingStatus `json:"load_shedding,omitempty"`
 }
 
 // ServiceStatus represents the status of a single service.
@@ -80,6 +96,25 @@ func NewHealthHandler(db DatabaseHealthChecker, redis RedisHealthChecker, ccxtUR
 	}
 }
 
+// SetLoadShedding reports the load shedding level in health checks.
+//
+// Parameters:
+//
+//	reporter: Source of the load shedding state.
+func (h *HealthHandler) SetLoadShedding(reporter LoadSheddingReporter) {
+	h.loadShedding = reporter
+}
+
+// SetDrainReporter fails readiness checks once a graceful shutdown starts,
+// so load balancers stop routing new requests to the server.
+//
+// Parameters:
+//
+//	drain: Source of the drain state.
+func (h *HealthHandler) SetDrainReporter(drain DrainReporter) {
+	h.drain = drain
+}
+
 // HealthCheck performs a comprehensive system health check.
 // It verifies connectivity to database, Redis, and CCXT service.
 //
@@ -190,6 +225,16 @@ func (h *HealthHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	}
+
+	// Shedding load keeps the service up but at reduced capacity
+	var loadShedding *services.LoadSheddingStatus
+	if h.loadShedding != nil {
+		shedding := h.loadShedding.LoadShedding()
+		loadShedding = &shedding
+		if shedding.Level != services.LoadSheddingNone {
+			status = "degraded"
+		}
+	}
 	span.SetTag("overall.status", status)
 
 	var cacheMetrics *services.CacheMetrics
@@ -211,6 +256,7 @@ func (h *HealthHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 		Uptime:       time.Since(startTime).String(),
 		CacheMetrics: cacheMetrics,
 		CacheStats:   cacheStats,
+		LoadShedding: loadShedding,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -296,6 +342,20 @@ func (h *HealthHandler) ReadinessCheck(w http.ResponseWriter, r *http.Request) {
 	span.SetTag("http.url", r.URL.String())
 	span.SetTag("handler.name", "ReadinessCheck")
 
+	// A draining server is not ready, whatever its dependencies say
+	if h.drain != nil && h.drain.Draining() {
+		span.Status = sentry.SpanStatusUnavailable
+		span.SetTag("readiness", "draining")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		if err := json.NewEncoder(w).Encode(map[string]interface{}{
+			"ready":    false,
+			"draining": true,
+		}); err != nil {
+			sentry.CaptureException(err)
+		}
+		return
+	}
+
 	// Comprehensive readiness checks for all services
 	servicesStatus := make(map[string]string)
 	allReady := true
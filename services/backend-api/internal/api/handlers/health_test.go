@@ -479,3 +479,38 @@ func newTestServerOrSkip(t *testing.T, h http.Handler) *httptest.Server {
 	srv := httptest.NewServer(h)
 	return srv
 }
+
+func TestHealthHandler_ReportsLoadShedding(t *testing.T) {
+	handler := NewHealthHandler(nil, nil, "http://127.0.0.1:0", nil)
+	handler.SetLoadShedding(stubLoadShedding{status: services.LoadSheddingStatus{
+		Enabled: true,
+		Level:   services.LoadSheddingCritical,
+		Actions: []string{"paused quests: daily_report"},
+	}})
+
+	w := httptest.NewRecorder()
+	handler.HealthCheck(w, httptest.NewRequest("GET", "/health", nil))
+
+	var response HealthResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "degraded", response.Status)
+	if assert.NotNil(t, response.LoadShedding) {
+		assert.Equal(t, services.LoadSheddingCritical, response.LoadShedding.Level)
+		assert.Equal(t, []string{"paused quests: daily_report"}, response.LoadShedding.Actions)
+	}
+}
+
+type drainingStub bool
+
+func (d drainingStub) Draining() bool { return bool(d) }
+
+func TestHealthHandler_ReadinessCheck_Draining(t *testing.T) {
+	handler := NewHealthHandler(nil, nil, "http://localhost:8080", nil)
+	handler.SetDrainReporter(drainingStub(true))
+
+	w := httptest.NewRecorder()
+	handler.ReadinessCheck(w, httptest.NewRequest("GET", "/ready", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"ready":false,"draining":true}`, w.Body.String())
+}
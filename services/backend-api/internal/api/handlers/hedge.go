@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// HedgeAdvisor defines the hedging advisor operations.
+type HedgeAdvisor interface {
+	Suggest(ctx context.Context, chatID string) (*services.HedgeReport, error)
+	Confirm(ctx context.Context, chatID, id string) (*services.HedgeSuggestion, string, error)
+}
+
+// HedgeHandler exposes hedge suggestions for correlated open positions.
+type HedgeHandler struct {
+	advisor HedgeAdvisor
+}
+
+// ConfirmHedgeRequest confirms one hedge suggestion.
+type ConfirmHedgeRequest struct {
+	ChatID string `json:"chat_id" binding:"required"`
+	ID     string `json:"id" binding:"required"`
+}
+
+// NewHedgeHandler creates a new hedge handler.
+//
+// Parameters:
+//
+//	advisor: The hedging advisor (may be nil when Redis is unavailable).
+//
+// Returns:
+//
+//	*HedgeHandler: The initialized handler.
+func NewHedgeHandler(advisor HedgeAdvisor) *HedgeHandler {
+	return &HedgeHandler{advisor: advisor}
+}
+
+// GetSuggestions analyzes the open positions and returns hedge suggestions
+// the chat can confirm.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *HedgeHandler) GetSuggestions(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	chatID := c.Query("chat_id")
+	if chatID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "chat_id is required"})
+		return
+	}
+	report, err := h.advisor.Suggest(c.Request.Context(), chatID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": report})
+}
+
+// ConfirmSuggestion opens a suggested hedge.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *HedgeHandler) ConfirmSuggestion(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req ConfirmHedgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "Invalid request body"})
+		return
+	}
+	suggestion, orderID, err := h.advisor.Confirm(c.Request.Context(), req.ChatID, req.ID)
+	switch {
+	case errors.Is(err, services.ErrHedgeSuggestionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "error": err.Error()})
+		return
+	case errors.Is(err, services.ErrHedgeTradingHalted):
+		c.JSON(http.StatusConflict, gin.H{"status": "error", "error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{
+		"suggestion": suggestion,
+		"order_id":   orderID,
+	}})
+}
+
+func (h *HedgeHandler) available(c *gin.Context) bool {
+	if h.advisor == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "hedging advisor not available"})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubHedgeAdvisor struct {
+	confirmErr error
+}
+
+func (s *stubHedgeAdvisor) Suggest(context.Context, string) (*services.HedgeReport, error) {
+	return &services.HedgeReport{Positions: 2, Suggestions: []services.HedgeSuggestion{{ID: "abc", Symbol: "BTC/USDT:USDT"}}}, nil
+}
+
+func (s *stubHedgeAdvisor) Confirm(_ context.Context, _, id string) (*services.HedgeSuggestion, string, error) {
+	if s.confirmErr != nil {
+		return nil, "", s.confirmErr
+	}
+	return &services.HedgeSuggestion{ID: id}, "ord-1", nil
+}
+
+func TestHedgeHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	advisor := &stubHedgeAdvisor{}
+	handler := NewHedgeHandler(advisor)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/telegram/internal/hedge?chat_id=42", nil)
+	handler.GetSuggestions(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"symbol":"BTC/USDT:USDT"`)
+
+	w = performTradingModeRequest(handler.GetSuggestions, "", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(handler.ConfirmSuggestion, `{"chat_id":"42","id":"abc"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"order_id":"ord-1"`)
+
+	advisor.confirmErr = services.ErrHedgeSuggestionNotFound
+	w = performTradingModeRequest(handler.ConfirmSuggestion, `{"chat_id":"42","id":"abc"}`, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	advisor.confirmErr = services.ErrHedgeTradingHalted
+	w = performTradingModeRequest(handler.ConfirmSuggestion, `{"chat_id":"42","id":"abc"}`, nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = performTradingModeRequest(NewHedgeHandler(nil).GetSuggestions, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
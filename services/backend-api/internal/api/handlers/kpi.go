@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// maxKPIPoints caps the points one range query may return.
+const maxKPIPoints = 5000
+
+// KPISource queries the internal KPI time series.
+type KPISource interface {
+	Query(ctx context.Context, metric string, from, to time.Time, step time.Duration) ([]services.KPIPoint, error)
+	Metrics(ctx context.Context) ([]string, error)
+}
+
+// KPIHandler exposes internal KPI history for capacity planning.
+type KPIHandler struct {
+	kpis KPISource
+	now  func() time.Time
+}
+
+// NewKPIHandler creates a new KPI handler.
+//
+// Parameters:
+//
+//	kpis: The KPI store (may be nil when no database is configured).
+//
+// Returns:
+//
+//	*KPIHandler: The initialized handler.
+func NewKPIHandler(kpis KPISource) *KPIHandler {
+	return &KPIHandler{kpis: kpis, now: time.Now}
+}
+
+// GetKPIs returns a metric's history. Query parameters: metric (omit to list
+// the recorded metrics), from and to as RFC 3339 times (default the last 24
+// hours) and step as a duration (default one hour).
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *KPIHandler) GetKPIs(c *gin.Context) {
+	if h.kpis == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "KPI store not available"})
+		return
+	}
+
+	metric := c.Query("metric")
+	if metric == "" {
+		metrics, err := h.kpis.Metrics(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"metrics": metrics}})
+		return
+	}
+
+	to := h.now().UTC()
+	if raw := c.Query("to"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "to must be an RFC 3339 time"})
+			return
+		}
+		to = parsed
+	}
+	from := to.Add(-24 * time.Hour)
+	if raw := c.Query("from"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "from must be an RFC 3339 time"})
+			return
+		}
+		from = parsed
+	}
+	step := time.Hour
+	if raw := c.Query("step"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "step must be a positive duration"})
+			return
+		}
+		step = parsed
+	}
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "from must be before to"})
+		return
+	}
+	if to.Sub(from)/step > maxKPIPoints {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "range too large for step; use a larger step"})
+		return
+	}
+
+	points, err := h.kpis.Query(c.Request.Context(), metric, from, to, step)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{
+		"metric": metric,
+		"from":   from.UTC(),
+		"to":     to.UTC(),
+		"step":   step.String(),
+		"points": points,
+	}})
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubKPISource struct {
+	metric   string
+	from, to time.Time
+	step     time.Duration
+}
+
+func (s *stubKPISource) Query(_ context.Context, metric string, from, to time.Time, step time.Duration) ([]services.KPIPoint, error) {
+	s.metric, s.from, s.to, s.step = metric, from, to, step
+	return []services.KPIPoint{{Time: from, Count: 2, Sum: 300, Min: 100, Max: 200, Avg: 150}}, nil
+}
+
+func (s *stubKPISource) Metrics(context.Context) ([]string, error) {
+	return []string{services.KPIOrdersPlaced, services.KPIQuestCycleMs}, nil
+}
+
+func performKPIRequest(handler *KPIHandler, query string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/admin/kpis?"+query, nil)
+	handler.GetKPIs(c)
+	return w
+}
+
+func TestKPIHandler_GetKPIs(t *testing.T) {
+	source := &stubKPISource{}
+	handler := NewKPIHandler(source)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	handler.now = func() time.Time { return now }
+
+	w := performKPIRequest(handler, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"metrics":["orders_placed","quest_cycle_ms"]`)
+
+	w = performKPIRequest(handler, "metric=quest_cycle_ms")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, now.Add(-24*time.Hour), source.from)
+	assert.Equal(t, now, source.to)
+	assert.Equal(t, time.Hour, source.step)
+	assert.Contains(t, w.Body.String(), `"avg":150`)
+
+	w = performKPIRequest(handler, "metric=orders_placed&from=2026-02-28T00:00:00Z&to=2026-03-01T00:00:00Z&step=15m")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, services.KPIOrdersPlaced, source.metric)
+	assert.Equal(t, 15*time.Minute, source.step)
+	assert.Equal(t, time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC), source.from)
+}
+
+func TestKPIHandler_GetKPIsRejectsBadInput(t *testing.T) {
+	handler := NewKPIHandler(&stubKPISource{})
+	for _, query := range []string{
+		"metric=orders_placed&step=soon",
+		"metric=orders_placed&step=-1m",
+		"metric=orders_placed&from=yesterday",
+		"metric=orders_placed&from=2026-03-02T00:00:00Z&to=2026-03-01T00:00:00Z",
+		"metric=orders_placed&from=2025-03-01T00:00:00Z&to=2026-03-01T00:00:00Z&step=1m",
+	} {
+		w := performKPIRequest(handler, query)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+
+	w := performKPIRequest(NewKPIHandler(nil), "metric=orders_placed")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
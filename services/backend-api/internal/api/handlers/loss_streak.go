@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/shopspring/decimal"
+)
+
+// LossStreakManager defines the consecutive-loss policy operations.
+type LossStreakManager interface {
+	Status(ctx context.Context, chatID string) (*services.LossStreakState, error)
+	RecordTrade(ctx context.Context, chatID, strategy string, pnl decimal.Decimal) (*services.LossStreakState, error)
+	Reset(ctx context.Context, chatID string) error
+	Config() services.ConsecutiveLossPolicyConfig
+}
+
+// LossStreakHandler exposes each chat's losing streaks and strategy throttles.
+type LossStreakHandler struct {
+	policy LossStreakManager
+}
+
+// UpdateLossStreakRequest records a trade result or resets a chat's streaks.
+type UpdateLossStreakRequest struct {
+	ChatID string `json:"chat_id" binding:"required"`
+	// Action is record or reset.
+	Action   string `json:"action" binding:"required"`
+	Strategy string `json:"strategy"`
+	PnL      string `json:"pnl"`
+}
+
+// NewLossStreakHandler creates a new loss streak handler.
+//
+// Parameters:
+//
+//	policy: The consecutive-loss policy (may be nil when Redis is unavailable).
+//
+// Returns:
+//
+//	*LossStreakHandler: The initialized handler.
+func NewLossStreakHandler(policy LossStreakManager) *LossStreakHandler {
+	return &LossStreakHandler{policy: policy}
+}
+
+// GetLossStreaks returns a chat's streaks, active throttles and recent triggers.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *LossStreakHandler) GetLossStreaks(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	chatID := c.Query("chat_id")
+	if chatID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "chat_id is required"})
+		return
+	}
+	state, err := h.policy.Status(c.Request.Context(), chatID)
+	h.respond(c, state, err)
+}
+
+// UpdateLossStreaks records a strategy's closed trade or resets the chat.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *LossStreakHandler) UpdateLossStreaks(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req UpdateLossStreakRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "Invalid request body"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	switch req.Action {
+	case "record":
+		pnl, err := decimal.NewFromString(req.PnL)
+		if err != nil || req.Strategy == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "strategy and a numeric pnl are required"})
+			return
+		}
+		state, err := h.policy.RecordTrade(ctx, req.ChatID, req.Strategy, pnl)
+		h.respond(c, state, err)
+	case "reset":
+		if err := h.policy.Reset(ctx, req.ChatID); err != nil {
+			h.respond(c, nil, err)
+			return
+		}
+		state, err := h.policy.Status(ctx, req.ChatID)
+		h.respond(c, state, err)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "action must be record or reset"})
+	}
+}
+
+func (h *LossStreakHandler) available(c *gin.Context) bool {
+	if h.policy == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "consecutive loss policy not available"})
+		return false
+	}
+	return true
+}
+
+func (h *LossStreakHandler) respond(c *gin.Context, state *services.LossStreakState, err error) {
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	config := h.policy.Config()
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{
+		"state":          state,
+		"max_losses":     config.MaxLosses,
+		"action":         config.Action,
+		"size_reduction": config.SizeReduction,
+		"cooldown":       config.Cooldown.String(),
+	}})
+}
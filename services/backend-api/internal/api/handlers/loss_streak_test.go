@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubLossStreaks struct {
+	recorded []string
+	reset    bool
+}
+
+func (s *stubLossStreaks) Status(_ context.Context, chatID string) (*services.LossStreakState, error) {
+	return &services.LossStreakState{ChatID: chatID, Strategies: map[string]*services.StrategyLossStreak{}}, nil
+}
+
+func (s *stubLossStreaks) RecordTrade(ctx context.Context, chatID, strategy string, _ decimal.Decimal) (*services.LossStreakState, error) {
+	s.recorded = append(s.recorded, strategy)
+	return s.Status(ctx, chatID)
+}
+
+func (s *stubLossStreaks) Reset(context.Context, string) error {
+	s.reset = true
+	return nil
+}
+
+func (s *stubLossStreaks) Config() services.ConsecutiveLossPolicyConfig {
+	return services.ConsecutiveLossPolicyConfig{MaxLosses: 3, Action: services.LossStreakPause, Cooldown: time.Hour}
+}
+
+func TestLossStreakHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	policy := &stubLossStreaks{}
+	handler := NewLossStreakHandler(policy)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/telegram/internal/risk/loss-streaks?chat_id=42", nil)
+	handler.GetLossStreaks(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"cooldown":"1h0m0s"`)
+	assert.Contains(t, w.Body.String(), `"action":"pause"`)
+
+	w = performTradingModeRequest(handler.UpdateLossStreaks, `{"chat_id":"42","action":"record","strategy":"scalping","pnl":"-3"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"scalping"}, policy.recorded)
+
+	w = performTradingModeRequest(handler.UpdateLossStreaks, `{"chat_id":"42","action":"record","pnl":"-3"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(handler.UpdateLossStreaks, `{"chat_id":"42","action":"reset"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, policy.reset)
+
+	w = performTradingModeRequest(handler.UpdateLossStreaks, `{"chat_id":"42","action":"forget"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(NewLossStreakHandler(nil).GetLossStreaks, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
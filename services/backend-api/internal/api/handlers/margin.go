@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// MarginProvider defines the leverage and margin mode operations.
+type MarginProvider interface {
+	Settings(ctx context.Context, exchange, symbol string) (*ccxt.MarginSettings, error)
+	Apply(ctx context.Context, exchange, symbol string, leverage float64, marginMode string) (*ccxt.MarginSettings, error)
+	Positions(ctx context.Context, exchange string) ([]ccxt.PositionInfo, error)
+}
+
+// MarginHandler manages per-symbol leverage and margin mode of exchange accounts.
+type MarginHandler struct {
+	margin MarginProvider
+}
+
+// SetLeverageRequest applies leverage and optionally a margin mode to a symbol.
+type SetLeverageRequest struct {
+	Exchange   string  `json:"exchange" binding:"required"`
+	Symbol     string  `json:"symbol" binding:"required"`
+	Leverage   float64 `json:"leverage" binding:"required,gte=1,lte=125"`
+	MarginMode string  `json:"margin_mode" binding:"omitempty,oneof=isolated cross"`
+}
+
+// NewMarginHandler creates a new margin handler.
+//
+// Parameters:
+//
+//	margin: The margin manager (may be nil when the CCXT service lacks margin support).
+//
+// Returns:
+//
+//	*MarginHandler: The initialized handler.
+func NewMarginHandler(margin MarginProvider) *MarginHandler {
+	return &MarginHandler{margin: margin}
+}
+
+// GetLeverage returns the leverage and margin mode of a symbol.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *MarginHandler) GetLeverage(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	exchange := strings.ToLower(c.Query("exchange"))
+	symbol := c.Query("symbol")
+	if exchange == "" || symbol == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "exchange and symbol are required"})
+		return
+	}
+	settings, err := h.margin.Settings(c.Request.Context(), exchange, symbol)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": settings})
+}
+
+// SetLeverage applies leverage and margin mode to a symbol and returns the
+// settings the exchange reports back.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *MarginHandler) SetLeverage(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req SetLeverageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "exchange, symbol and leverage between 1 and 125 are required; margin_mode must be isolated or cross"})
+		return
+	}
+	settings, err := h.margin.Apply(c.Request.Context(), strings.ToLower(req.Exchange), req.Symbol, req.Leverage, req.MarginMode)
+	if errors.Is(err, services.ErrLeverageMismatch) {
+		c.JSON(http.StatusConflict, gin.H{"status": "error", "error": err.Error(), "data": settings})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": settings})
+}
+
+// ListPositions returns the open derivatives positions of an exchange account
+// with their leverage, margin mode and margin ratio.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *MarginHandler) ListPositions(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	exchange := strings.ToLower(c.Query("exchange"))
+	if exchange == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "exchange is required"})
+		return
+	}
+	positions, err := h.margin.Positions(c.Request.Context(), exchange)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	if positions == nil {
+		positions = []ccxt.PositionInfo{}
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": positions})
+}
+
+func (h *MarginHandler) available(c *gin.Context) bool {
+	if h.margin == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "margin management not available"})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/irfndi/neuratrade/pkg/interfaces"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubMarginProvider struct {
+	applyErr error
+	applied  []string
+}
+
+func (s *stubMarginProvider) Settings(_ context.Context, _, symbol string) (*ccxt.MarginSettings, error) {
+	return &ccxt.MarginSettings{Symbol: symbol, MarginMode: "cross", LongLeverage: 10, ShortLeverage: 10}, nil
+}
+
+func (s *stubMarginProvider) Apply(_ context.Context, exchange, symbol string, leverage float64, marginMode string) (*ccxt.MarginSettings, error) {
+	s.applied = append(s.applied, fmt.Sprintf("%s %s %gx %s", exchange, symbol, leverage, marginMode))
+	return &ccxt.MarginSettings{Symbol: symbol, MarginMode: marginMode, LongLeverage: leverage, ShortLeverage: leverage}, s.applyErr
+}
+
+func (s *stubMarginProvider) Positions(_ context.Context, exchange string) ([]ccxt.PositionInfo, error) {
+	if exchange != "binance" {
+		return nil, fmt.Errorf("%s not configured", exchange)
+	}
+	return []ccxt.PositionInfo{{Symbol: "BTC/USDT:USDT", Side: "long", Leverage: 3, MarginMode: "isolated", MarginRatio: 0.05}}, nil
+}
+
+func TestMarginHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	provider := &stubMarginProvider{}
+	handler := NewMarginHandler(provider)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/trading/leverage?exchange=Binance&symbol=BTC/USDT:USDT", nil)
+	handler.GetLeverage(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"longLeverage":10`)
+
+	w = performTradingModeRequest(handler.GetLeverage, "", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(handler.SetLeverage, `{"exchange":"Binance","symbol":"BTC/USDT:USDT","leverage":3,"margin_mode":"isolated"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"binance BTC/USDT:USDT 3x isolated"}, provider.applied)
+
+	w = performTradingModeRequest(handler.SetLeverage, `{"exchange":"binance","symbol":"BTC/USDT:USDT","leverage":3,"margin_mode":"portfolio"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = performTradingModeRequest(handler.SetLeverage, `{"exchange":"binance","symbol":"BTC/USDT:USDT","leverage":200}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	provider.applyErr = services.ErrLeverageMismatch
+	w = performTradingModeRequest(handler.SetLeverage, `{"exchange":"binance","symbol":"BTC/USDT:USDT","leverage":3}`, nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/trading/margin/positions?exchange=binance", nil)
+	handler.ListPositions(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"marginRatio":0.05`)
+
+	w = performTradingModeRequest(NewMarginHandler(nil).ListPositions, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestTradingHandler_PositionMargins(t *testing.T) {
+	handler := &TradingHandler{}
+	positions := []PositionRecord{
+		{Exchange: "binance", Symbol: "BTC/USDT", Status: "OPEN"},
+		{Exchange: "binance", Symbol: "ETH/USDT", Status: "OPEN"},
+		{Exchange: "okx", Symbol: "SOL/USDT", Status: "OPEN"},
+		{Exchange: "bybit", Symbol: "XRP/USDT", Status: "CLOSED"},
+	}
+	assert.Nil(t, handler.positionMargins(t.Context(), positions))
+
+	handler.SetMarginSource(&stubMarginProvider{})
+	margins := handler.positionMargins(t.Context(), positions)
+	// Each exchange is fetched once and failing ones are skipped
+	require.Len(t, margins, 1)
+	assert.Equal(t, "binance", margins[0].Exchange)
+	assert.Equal(t, 0.05, margins[0].MarginRatio)
+	assert.Equal(t, "isolated", margins[0].MarginMode)
+}
+
+type staticPortfolioPositions []interfaces.Position
+
+func (s staticPortfolioPositions) GetOpenPositions() []interfaces.Position { return s }
+
+func TestAutonomousHandler_PortfolioIncludesLiquidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewAutonomousHandler(nil)
+	handler.SetPositionSource(staticPortfolioPositions{
+		{Symbol: "SOL/USDT", Side: "BUY", Size: decimal.NewFromInt(2), EntryPrice: decimal.NewFromInt(100), CurrentPrice: decimal.NewFromInt(100)},
+		{Symbol: "BTC/USDT:USDT", Side: "BUY", Size: decimal.NewFromInt(1), EntryPrice: decimal.NewFromInt(100), CurrentPrice: decimal.NewFromInt(100),
+			Leverage: decimal.NewFromInt(10), LiquidationPrice: decimal.NewFromInt(90)},
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/telegram/internal/portfolio?chat_id=42", nil)
+	handler.GetPortfolio(c)
+	require.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, `"liquidation_price":"90.0000"`)
+	assert.Contains(t, body, `"liquidation_distance_pct":"10.00"`)
+	assert.Contains(t, body, `"leverage":"10"`)
+	// Unleveraged positions carry no liquidation fields
+	assert.Equal(t, 1, strings.Count(body, "liquidation_price"))
+}
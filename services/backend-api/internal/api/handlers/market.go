@@ -698,3 +698,19 @@ func (h *MarketHandler) GetWorkerStatus(c *gin.Context) {
 		"timestamp": time.Now(),
 	})
 }
+
+// GetQuarantinedTicks returns the ticks the collector rejected as anomalous.
+func (h *MarketHandler) GetQuarantinedTicks(c *gin.Context) {
+	if h.collectorService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":     "Collector service is not available",
+			"timestamp": time.Now(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"quarantined": h.collectorService.GetQuarantinedTicks(),
+		"timestamp":   time.Now(),
+	})
+}
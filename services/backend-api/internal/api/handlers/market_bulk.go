@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/ccxt"
+)
+
+const (
+	maxBulkSymbols       = 100
+	bulkMarketCacheTTL   = 5 * time.Second
+	bulkGzipMinBodyBytes = 512
+)
+
+// bulkMarketFields lists the selectable fields in response column order.
+var bulkMarketFields = []string{"last", "bid", "ask", "high", "low", "volume", "funding", "ts"}
+
+var defaultBulkMarketFields = []string{"last", "bid", "ask"}
+
+// BulkMarketResponse is a compact, column-oriented snapshot for many symbols.
+// Each row holds exchange, symbol and then the selected fields in column order;
+// values that are unavailable are null.
+type BulkMarketResponse struct {
+	Columns     []string        `json:"columns"`
+	Rows        [][]interface{} `json:"rows"`
+	GeneratedAt int64           `json:"generated_at"`
+}
+
+// GetBulkMarketData returns selected fields for many symbols across exchanges in one call.
+// Supports gzip via Accept-Encoding and conditional requests via ETag/If-None-Match.
+//
+// Query parameters:
+//
+//	symbols: Comma-separated symbols (required, max 100).
+//	exchanges: Comma-separated exchanges (defaults to all supported).
+//	fields: Comma-separated subset of last,bid,ask,high,low,volume,funding,ts.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *MarketHandler) GetBulkMarketData(c *gin.Context) {
+	symbols := splitCSV(c.Query("symbols"))
+	if len(symbols) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "symbols is required"})
+		return
+	}
+	if len(symbols) > maxBulkSymbols {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d symbols are allowed", maxBulkSymbols)})
+		return
+	}
+	fields, err := parseBulkFields(c.Query("fields"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	exchanges := splitCSV(c.Query("exchanges"))
+
+	ctx := c.Request.Context()
+	sort.Strings(symbols)
+	sort.Strings(exchanges)
+	cacheKey := fmt.Sprintf("market_bulk:%s:%s:%s", strings.Join(exchanges, ","), strings.Join(symbols, ","), strings.Join(fields, ","))
+
+	// Cache entries hold "<etag>\n<body>" so cached and fresh responses share tags.
+	var etag string
+	var body []byte
+	if h.redis != nil {
+		if cached, err := h.redis.Get(ctx, cacheKey); err == nil {
+			if tag, cachedBody, ok := strings.Cut(cached, "\n"); ok {
+				etag, body = tag, []byte(cachedBody)
+			}
+		}
+		if body != nil {
+			if h.cacheAnalytics != nil {
+				h.cacheAnalytics.RecordHit("market_bulk")
+			}
+		} else if h.cacheAnalytics != nil {
+			h.cacheAnalytics.RecordMiss("market_bulk")
+		}
+	}
+
+	if body == nil {
+		if !h.ccxtService.IsHealthy(ctx) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Market data service is currently unavailable"})
+			return
+		}
+		if len(exchanges) == 0 {
+			exchanges = h.ccxtService.GetSupportedExchanges()
+		}
+
+		marketData, err := h.ccxtService.FetchMarketData(ctx, exchanges, symbols)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch market data"})
+			return
+		}
+
+		var funding map[string]map[string]float64
+		if containsField(fields, "funding") {
+			funding = h.fetchBulkFunding(c, exchanges, symbols)
+		}
+
+		response := buildBulkMarketResponse(marketData, fields, funding)
+		etag, err = bulkETag(response)
+		if err == nil {
+			body, err = json.Marshal(response)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode market data"})
+			return
+		}
+		if h.redis != nil {
+			if err := h.redis.Set(ctx, cacheKey, etag+"\n"+string(body), bulkMarketCacheTTL); err != nil {
+				log.Printf("Failed to cache bulk market data: %v", err)
+			}
+		}
+	}
+
+	writeConditionalJSON(c, etag, body)
+}
+
+// fetchBulkFunding returns funding rates by exchange and symbol. Failures for an
+// exchange leave its funding values null rather than failing the request.
+func (h *MarketHandler) fetchBulkFunding(c *gin.Context, exchanges, symbols []string) map[string]map[string]float64 {
+	result := make(map[string]map[string]float64, len(exchanges))
+	for _, exchange := range exchanges {
+		rates, err := h.ccxtService.FetchFundingRates(c.Request.Context(), exchange, symbols)
+		if err != nil {
+			continue
+		}
+		byExchange := make(map[string]float64, len(rates))
+		for _, rate := range rates {
+			byExchange[rate.Symbol] = rate.FundingRate
+			// Perpetual symbols carry a settlement suffix (BTC/USDT:USDT).
+			if base, _, found := strings.Cut(rate.Symbol, ":"); found {
+				if _, exists := byExchange[base]; !exists {
+					byExchange[base] = rate.FundingRate
+				}
+			}
+		}
+		result[exchange] = byExchange
+	}
+	return result
+}
+
+func buildBulkMarketResponse(marketData []ccxt.MarketPriceInterface, fields []string, funding map[string]map[string]float64) BulkMarketResponse {
+	rows := make([][]interface{}, 0, len(marketData))
+	for _, ticker := range marketData {
+		row := make([]interface{}, 0, len(fields)+2)
+		row = append(row, ticker.GetExchangeName(), ticker.GetSymbol())
+		for _, field := range fields {
+			switch field {
+			case "last":
+				row = append(row, ticker.GetPrice())
+			case "bid":
+				row = append(row, nullIfZero(ticker.GetBid()))
+			case "ask":
+				row = append(row, nullIfZero(ticker.GetAsk()))
+			case "high":
+				row = append(row, nullIfZero(ticker.GetHigh()))
+			case "low":
+				row = append(row, nullIfZero(ticker.GetLow()))
+			case "volume":
+				row = append(row, ticker.GetVolume())
+			case "funding":
+				if rate, ok := funding[ticker.GetExchangeName()][ticker.GetSymbol()]; ok {
+					row = append(row, rate)
+				} else {
+					row = append(row, nil)
+				}
+			case "ts":
+				row = append(row, ticker.GetTimestamp().UnixMilli())
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	// Stable ordering keeps ETags stable for unchanged data.
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i][0] != rows[j][0] {
+			return rows[i][0].(string) < rows[j][0].(string)
+		}
+		return rows[i][1].(string) < rows[j][1].(string)
+	})
+
+	return BulkMarketResponse{
+		Columns:     append([]string{"exchange", "symbol"}, fields...),
+		Rows:        rows,
+		GeneratedAt: time.Now().UnixMilli(),
+	}
+}
+
+// writeConditionalJSON writes a JSON body with an ETag, answering 304 when the
+// client already has it and gzip-compressing when accepted.
+func writeConditionalJSON(c *gin.Context, etag string, body []byte) {
+	c.Header("ETag", etag)
+	c.Header("Vary", "Accept-Encoding")
+	c.Header("Cache-Control", "no-cache")
+
+	if match := c.GetHeader("If-None-Match"); match != "" && etagMatches(match, etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	if len(body) >= bulkGzipMinBodyBytes && strings.Contains(c.GetHeader("Accept-Encoding"), "gzip") {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := gz.Write(body); err == nil && gz.Close() == nil {
+			c.Header("Content-Encoding", "gzip")
+			c.Data(http.StatusOK, "application/json; charset=utf-8", buf.Bytes())
+			return
+		}
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// bulkETag hashes columns and rows but not generated_at, so unchanged market
+// data keeps the same tag across rebuilds.
+func bulkETag(response BulkMarketResponse) (string, error) {
+	stable, err := json.Marshal(struct {
+		Columns []string        `json:"columns"`
+		Rows    [][]interface{} `json:"rows"`
+	}{response.Columns, response.Rows})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(stable)
+	return `"` + hex.EncodeToString(sum[:8]) + `"`, nil
+}
+
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func parseBulkFields(raw string) ([]string, error) {
+	requested := splitCSV(strings.ToLower(raw))
+	if len(requested) == 0 {
+		return defaultBulkMarketFields, nil
+	}
+	wanted := make(map[string]bool, len(requested))
+	for _, field := range requested {
+		if !containsField(bulkMarketFields, field) {
+			return nil, fmt.Errorf("unknown field %q", field)
+		}
+		wanted[field] = true
+	}
+	fields := make([]string, 0, len(wanted))
+	for _, field := range bulkMarketFields {
+		if wanted[field] {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+func splitCSV(raw string) []string {
+	var values []string
+	seen := map[string]bool{}
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" && !seen[part] {
+			seen[part] = true
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+func containsField(fields []string, field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func nullIfZero(v float64) interface{} {
+	if v == 0 {
+		return nil
+	}
+	return v
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/api/handlers/testmocks"
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/irfndi/neuratrade/internal/models"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupBulkMarketTest(t *testing.T, redisClient *database.RedisClient) (*testmocks.MockCCXTService, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	mockCCXT := &testmocks.MockCCXTService{}
+	handler := NewMarketHandler(nil, mockCCXT, nil, redisClient, nil)
+	router := gin.New()
+	router.GET("/market/bulk", handler.GetBulkMarketData)
+	return mockCCXT, router
+}
+
+func bulkTickers() []models.MarketPrice {
+	ts := time.UnixMilli(1700000000000)
+	return []models.MarketPrice{
+		{ExchangeName: "bybit", Symbol: "BTC/USDT", Price: decimal.NewFromFloat(50010), Bid: decimal.NewFromFloat(50005), Ask: decimal.NewFromFloat(50015), Timestamp: ts},
+		{ExchangeName: "binance", Symbol: "BTC/USDT", Price: decimal.NewFromFloat(50000), Bid: decimal.NewFromFloat(49995), Timestamp: ts},
+	}
+}
+
+func TestMarketHandler_GetBulkMarketData_Validation(t *testing.T) {
+	_, router := setupBulkMarketTest(t, nil)
+
+	for _, path := range []string{"/market/bulk", "/market/bulk?symbols=BTC/USDT&fields=last,spread"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, path)
+	}
+}
+
+func TestMarketHandler_GetBulkMarketData_Rows(t *testing.T) {
+	mockCCXT, router := setupBulkMarketTest(t, nil)
+	mockCCXT.On("IsHealthy", mock.Anything).Return(true)
+	mockCCXT.On("FetchMarketData", mock.Anything, []string{"binance", "bybit"}, []string{"BTC/USDT"}).Return(bulkTickers(), nil)
+	mockCCXT.On("FetchFundingRates", mock.Anything, "binance", []string{"BTC/USDT"}).
+		Return([]ccxt.FundingRate{{Symbol: "BTC/USDT:USDT", FundingRate: 0.0001}}, nil)
+	mockCCXT.On("FetchFundingRates", mock.Anything, "bybit", []string{"BTC/USDT"}).Return(nil, errors.New("timeout"))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/market/bulk?symbols=BTC/USDT&exchanges=bybit,binance&fields=ts,funding,ask,last", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response BulkMarketResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []string{"exchange", "symbol", "last", "ask", "funding", "ts"}, response.Columns)
+	require.Len(t, response.Rows, 2)
+	assert.Equal(t, []interface{}{"binance", "BTC/USDT", 50000.0, nil, 0.0001, 1700000000000.0}, response.Rows[0])
+	assert.Equal(t, []interface{}{"bybit", "BTC/USDT", 50010.0, 50015.0, nil, 1700000000000.0}, response.Rows[1])
+	assert.NotEmpty(t, w.Header().Get("ETag"))
+	mockCCXT.AssertExpectations(t)
+}
+
+func TestMarketHandler_GetBulkMarketData_ETagAndCache(t *testing.T) {
+	mr := miniredis.RunT(t)
+	redisClient := &database.RedisClient{Client: redis.NewClient(&redis.Options{Addr: mr.Addr()})}
+	mockCCXT, router := setupBulkMarketTest(t, redisClient)
+	mockCCXT.On("IsHealthy", mock.Anything).Return(true)
+	mockCCXT.On("FetchMarketData", mock.Anything, []string{"binance"}, []string{"BTC/USDT"}).Return(bulkTickers()[1:], nil).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/market/bulk?symbols=BTC/USDT&exchanges=binance", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	// The second request is served from the cache and matches the client's tag.
+	req := httptest.NewRequest(http.MethodGet, "/market/bulk?symbols=BTC/USDT&exchanges=binance", nil)
+	req.Header.Set("If-None-Match", "W/"+etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.Bytes())
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+	mockCCXT.AssertExpectations(t)
+}
+
+func TestMarketHandler_GetBulkMarketData_Gzip(t *testing.T) {
+	mockCCXT, router := setupBulkMarketTest(t, nil)
+	var tickers []models.MarketPrice
+	var symbols []string
+	for i := 0; i < 20; i++ {
+		symbol := fmt.Sprintf("COIN%02d/USDT", i)
+		symbols = append(symbols, symbol)
+		tickers = append(tickers, models.MarketPrice{ExchangeName: "binance", Symbol: symbol, Price: decimal.NewFromFloat(float64(i) + 0.5)})
+	}
+	mockCCXT.On("IsHealthy", mock.Anything).Return(true)
+	mockCCXT.On("FetchMarketData", mock.Anything, []string{"binance"}, symbols).Return(tickers, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/market/bulk?exchanges=binance&fields=last&symbols="+strings.Join(symbols, ","), nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+
+	reader, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	raw, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	var response BulkMarketResponse
+	require.NoError(t, json.Unmarshal(raw, &response))
+	assert.Len(t, response.Rows, 20)
+}
+
+func TestMarketHandler_GetBulkMarketData_ServiceUnavailable(t *testing.T) {
+	mockCCXT, router := setupBulkMarketTest(t, nil)
+	mockCCXT.On("IsHealthy", mock.Anything).Return(false)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/market/bulk?symbols=BTC/USDT", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestParseBulkFields(t *testing.T) {
+	fields, err := parseBulkFields("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"last", "bid", "ask"}, fields)
+
+	fields, err = parseBulkFields(" Volume, last ,volume")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"last", "volume"}, fields)
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/ccxt"
+)
+
+const (
+	derivativesCacheTTL = 30 * time.Second
+	maxDerivativesLimit = 500
+)
+
+// DerivativesFetcher lists options and dated futures. It is implemented by
+// ccxt.Service; execution of these instruments is not supported.
+type DerivativesFetcher interface {
+	FetchDerivatives(ctx context.Context, exchange string, query ccxt.DerivativesQuery) (*ccxt.DerivativesResponse, error)
+}
+
+// DerivativesMarketResponse lists an exchange's options and dated futures.
+type DerivativesMarketResponse struct {
+	Exchange    string                      `json:"exchange"`
+	Instruments []ccxt.DerivativeInstrument `json:"instruments"`
+	Count       int                         `json:"count"`
+	Timestamp   time.Time                   `json:"timestamp"`
+	Cached      bool                        `json:"cached"`
+}
+
+// GetDerivatives lists the options and dated futures of an exchange with
+// their mark price, implied volatility and greeks where available.
+//
+// Query parameters:
+//
+//	type: option, future or option,future (default both).
+//	underlying: Base asset such as BTC.
+//	limit: Maximum instruments, soonest expiry first (max 500).
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *MarketHandler) GetDerivatives(c *gin.Context) {
+	exchange := strings.ToLower(c.Param("exchange"))
+	if exchange == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Exchange is required"})
+		return
+	}
+
+	types := splitCSV(strings.ToLower(c.Query("type")))
+	for _, t := range types {
+		if t != "option" && t != "future" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "type must be option, future or both"})
+			return
+		}
+	}
+	sort.Strings(types)
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if limit < 1 || limit > maxDerivativesLimit {
+		limit = 100
+	}
+	query := ccxt.DerivativesQuery{
+		Types:      types,
+		Underlying: strings.ToUpper(strings.TrimSpace(c.Query("underlying"))),
+		Limit:      limit,
+	}
+
+	fetcher, ok := h.ccxtService.(DerivativesFetcher)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Derivatives data is not supported by the market data service"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	cacheKey := fmt.Sprintf("derivatives:%s:%s:%s:%d", exchange, strings.Join(types, ","), query.Underlying, limit)
+	if cached, found := h.getCachedDerivatives(ctx, cacheKey); found {
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+
+	if !h.ccxtService.IsHealthy(ctx) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Market data service is currently unavailable"})
+		return
+	}
+
+	resp, err := fetcher.FetchDerivatives(ctx, exchange, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch derivatives data"})
+		return
+	}
+
+	response := DerivativesMarketResponse{
+		Exchange:    exchange,
+		Instruments: resp.Instruments,
+		Count:       len(resp.Instruments),
+		Timestamp:   time.Now(),
+	}
+	if response.Instruments == nil {
+		response.Instruments = []ccxt.DerivativeInstrument{}
+	}
+	h.cacheDerivatives(ctx, cacheKey, response)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// cacheDerivatives caches derivatives data in Redis; marks and IV move
+// slower than tickers, so the TTL is longer.
+func (h *MarketHandler) cacheDerivatives(ctx context.Context, cacheKey string, data DerivativesMarketResponse) {
+	if h.redis == nil {
+		return
+	}
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Failed to marshal derivatives for caching: %v", err)
+		return
+	}
+	if err := h.redis.Set(ctx, cacheKey, string(dataJSON), derivativesCacheTTL); err != nil {
+		log.Printf("Failed to cache derivatives: %v", err)
+	}
+}
+
+func (h *MarketHandler) getCachedDerivatives(ctx context.Context, cacheKey string) (*DerivativesMarketResponse, bool) {
+	if h.redis == nil {
+		return nil, false
+	}
+	cachedData, err := h.redis.Get(ctx, cacheKey)
+	if err != nil {
+		if h.cacheAnalytics != nil {
+			h.cacheAnalytics.RecordMiss("derivatives")
+		}
+		return nil, false
+	}
+	var data DerivativesMarketResponse
+	if err := json.Unmarshal([]byte(cachedData), &data); err != nil {
+		log.Printf("Failed to unmarshal cached derivatives: %v", err)
+		return nil, false
+	}
+	data.Cached = true
+	if h.cacheAnalytics != nil {
+		h.cacheAnalytics.RecordHit("derivatives")
+	}
+	return &data, true
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/api/handlers/testmocks"
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupDerivativesTest(t *testing.T) (*testmocks.MockCCXTService, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	mr := miniredis.RunT(t)
+	redisClient := &database.RedisClient{Client: redis.NewClient(&redis.Options{Addr: mr.Addr()})}
+	mockCCXT := &testmocks.MockCCXTService{}
+	handler := NewMarketHandler(nil, mockCCXT, nil, redisClient, nil)
+	router := gin.New()
+	router.GET("/market/derivatives/:exchange", handler.GetDerivatives)
+	return mockCCXT, router
+}
+
+func TestMarketHandler_GetDerivatives(t *testing.T) {
+	mockCCXT, router := setupDerivativesTest(t)
+	expiry := ccxt.UnixTimestamp(time.Date(2026, 12, 25, 8, 0, 0, 0, time.UTC))
+	query := ccxt.DerivativesQuery{Types: []string{"option"}, Underlying: "BTC", Limit: 20}
+	mockCCXT.On("IsHealthy", mock.Anything).Return(true)
+	mockCCXT.On("FetchDerivatives", mock.Anything, "deribit", query).Return(&ccxt.DerivativesResponse{
+		Exchange: "deribit",
+		Instruments: []ccxt.DerivativeInstrument{{
+			Symbol: "BTC/USD:BTC-261225-100000-C", Type: "option", Base: "BTC", Expiry: expiry,
+			Strike: 100000, OptionType: "call", MarkPrice: 0.05, MarkIV: 0.52,
+		}},
+	}, nil).Once()
+
+	path := "/market/derivatives/deribit?type=option&underlying=btc&limit=20"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var response DerivativesMarketResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.Count)
+	assert.False(t, response.Cached)
+	assert.Equal(t, 0.52, response.Instruments[0].MarkIV)
+	assert.Equal(t, "call", response.Instruments[0].OptionType)
+
+	// Served from the cache the second time
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Cached)
+	mockCCXT.AssertExpectations(t)
+}
+
+func TestMarketHandler_GetDerivatives_Validation(t *testing.T) {
+	mockCCXT, router := setupDerivativesTest(t)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/market/derivatives/okx?type=swap", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockCCXT.On("IsHealthy", mock.Anything).Return(false)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/market/derivatives/okx", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
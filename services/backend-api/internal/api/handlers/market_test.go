@@ -569,6 +569,26 @@ func TestMarketHandler_GetWorkerStatus_WithCollectorService(t *testing.T) {
 	assert.Equal(t, "Collector service is not available", response["error"])
 }
 
+func TestMarketHandler_GetQuarantinedTicks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	// Create handler without collector service
+	handler := NewMarketHandler(nil, &testmocks.MockCCXTService{}, nil, nil, nil)
+	router.GET("/quarantine", handler.GetQuarantinedTicks)
+
+	req, _ := http.NewRequest("GET", "/quarantine", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "Collector service is not available", response["error"])
+}
+
 func TestMarketHandler_GetMarketPrices(t *testing.T) {
 	t.Run("nil database causes panic", func(t *testing.T) {
 		mockCCXT := &testmocks.MockCCXTService{}
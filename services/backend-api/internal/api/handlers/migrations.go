@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// MigrationManager defines the migration operations used by the admin endpoints.
+type MigrationManager interface {
+	Status(ctx context.Context) (*services.MigrationReport, error)
+	Migrate(ctx context.Context) ([]services.MigrationInfo, error)
+	Rollback(ctx context.Context, filename string, force bool) (*services.MigrationInfo, error)
+}
+
+// MigrationHandler reports and applies database migrations.
+type MigrationHandler struct {
+	migrations MigrationManager
+}
+
+// RollbackMigrationRequest selects the migration to roll back.
+type RollbackMigrationRequest struct {
+	// Filename defaults to the most recently applied migration.
+	Filename string `json:"filename"`
+	// Force removes the record even when there is no rollback script.
+	Force bool `json:"force"`
+}
+
+// NewMigrationHandler creates a new migration handler.
+//
+// Parameters:
+//
+//	migrations: The migration service (may be nil when no database is configured).
+//
+// Returns:
+//
+//	*MigrationHandler: The initialized handler.
+func NewMigrationHandler(migrations MigrationManager) *MigrationHandler {
+	return &MigrationHandler{migrations: migrations}
+}
+
+func (h *MigrationHandler) available(c *gin.Context) bool {
+	if h.migrations == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "migration service not available"})
+		return false
+	}
+	return true
+}
+
+// GetMigrations lists applied and pending migrations with their checksums.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *MigrationHandler) GetMigrations(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	report, err := h.migrations.Status(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": report})
+}
+
+// RunMigrations applies all pending migrations.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *MigrationHandler) RunMigrations(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	applied, err := h.migrations.Migrate(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error(), "data": gin.H{"applied": applied}})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"applied": applied}})
+}
+
+// RollbackMigration reverts one applied migration.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *MigrationHandler) RollbackMigration(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req RollbackMigrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "Invalid request body"})
+		return
+	}
+
+	migration, err := h.migrations.Rollback(c.Request.Context(), req.Filename, req.Force)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrMigrationNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, services.ErrMigrationNotApplied), errors.Is(err, services.ErrMigrationNoRollback):
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": migration})
+}
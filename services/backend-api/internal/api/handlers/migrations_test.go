@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubMigrations struct {
+	rolledBack string
+	force      bool
+}
+
+func (s *stubMigrations) Status(ctx context.Context) (*services.MigrationReport, error) {
+	return &services.MigrationReport{Applied: 1, Pending: 1, Migrations: []services.MigrationInfo{
+		{Filename: "001_initial.sql", Status: services.MigrationApplied, Checksum: "abc"},
+		{Filename: "002_add_b.sql", Status: services.MigrationPending, Checksum: "def"},
+	}}, nil
+}
+
+func (s *stubMigrations) Migrate(ctx context.Context) ([]services.MigrationInfo, error) {
+	return []services.MigrationInfo{{Filename: "002_add_b.sql", Status: services.MigrationApplied}}, nil
+}
+
+func (s *stubMigrations) Rollback(ctx context.Context, filename string, force bool) (*services.MigrationInfo, error) {
+	if filename == "missing.sql" {
+		return nil, services.ErrMigrationNotFound
+	}
+	if filename == "001_initial.sql" && !force {
+		return nil, services.ErrMigrationNoRollback
+	}
+	s.rolledBack, s.force = filename, force
+	return &services.MigrationInfo{Filename: filename, Status: services.MigrationPending}, nil
+}
+
+func performMigrationRequest(handlerFunc gin.HandlerFunc, method, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, "/migrations", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	handlerFunc(c)
+	return w
+}
+
+func TestMigrationHandler_StatusAndMigrate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewMigrationHandler(&stubMigrations{})
+
+	w := performMigrationRequest(handler.GetMigrations, http.MethodGet, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"pending":1`)
+	assert.Contains(t, w.Body.String(), `"checksum":"def"`)
+
+	w = performMigrationRequest(handler.RunMigrations, http.MethodPost, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "002_add_b.sql")
+}
+
+func TestMigrationHandler_Rollback(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	migrations := &stubMigrations{}
+	handler := NewMigrationHandler(migrations)
+
+	w := performMigrationRequest(handler.RollbackMigration, http.MethodPost, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, migrations.rolledBack, "an empty body rolls back the latest migration")
+
+	w = performMigrationRequest(handler.RollbackMigration, http.MethodPost, `{"filename":"001_initial.sql"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = performMigrationRequest(handler.RollbackMigration, http.MethodPost, `{"filename":"001_initial.sql","force":true}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "001_initial.sql", migrations.rolledBack)
+	assert.True(t, migrations.force)
+
+	w = performMigrationRequest(handler.RollbackMigration, http.MethodPost, `{"filename":"missing.sql"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = performMigrationRequest(NewMigrationHandler(nil).GetMigrations, http.MethodGet, "")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// ListingScanner defines the new-listing detection operations.
+type ListingScanner interface {
+	Listings(ctx context.Context) ([]services.NewListing, error)
+	Scan(ctx context.Context) ([]services.NewListing, error)
+}
+
+// NewListingsHandler exposes newly listed symbols and on-demand scans.
+type NewListingsHandler struct {
+	detector ListingScanner
+}
+
+// NewNewListingsHandler creates a new listings handler.
+//
+// Parameters:
+//
+//	detector: The listing detector (may be nil when Redis is unavailable).
+//
+// Returns:
+//
+//	*NewListingsHandler: The initialized handler.
+func NewNewListingsHandler(detector ListingScanner) *NewListingsHandler {
+	return &NewListingsHandler{detector: detector}
+}
+
+func (h *NewListingsHandler) available(c *gin.Context) bool {
+	if h.detector == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "new listing detection not available"})
+		return false
+	}
+	return true
+}
+
+// GetListings returns the listings still under conservative risk limits.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *NewListingsHandler) GetListings(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	listings, err := h.detector.Listings(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"listings": listings}})
+}
+
+// ScanListings scans the configured exchanges now and returns what was detected.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *NewListingsHandler) ScanListings(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	detected, err := h.detector.Scan(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	if detected == nil {
+		detected = []services.NewListing{}
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"detected": detected}})
+}
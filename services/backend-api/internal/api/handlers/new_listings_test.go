@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubListingScanner struct {
+	listings []services.NewListing
+	err      error
+}
+
+func (s *stubListingScanner) Listings(context.Context) ([]services.NewListing, error) {
+	return s.listings, s.err
+}
+
+func (s *stubListingScanner) Scan(context.Context) ([]services.NewListing, error) {
+	return nil, s.err
+}
+
+func TestNewListingsHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	detectedAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	scanner := &stubListingScanner{listings: []services.NewListing{{
+		Exchange:      "binance",
+		Symbol:        "NEW/USDT",
+		DetectedAt:    detectedAt,
+		ProbationEnds: detectedAt.Add(7 * 24 * time.Hour),
+	}}}
+	handler := NewNewListingsHandler(scanner)
+
+	w := performTradingModeRequest(handler.GetListings, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"symbol":"NEW/USDT"`)
+
+	w = performTradingModeRequest(handler.ScanListings, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"detected":[]`)
+
+	scanner.err = errors.New("redis down")
+	w = performTradingModeRequest(handler.ScanListings, "", nil)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	w = performTradingModeRequest(NewNewListingsHandler(nil).GetListings, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// NotificationActionInterface handles inline keyboard callbacks on notifications.
+type NotificationActionInterface interface {
+	// HandleCallback verifies and executes a pressed button.
+	HandleCallback(ctx context.Context, chatID int64, data string) (*services.NotificationActionResponse, error)
+}
+
+// NotificationActionHandler receives callback queries forwarded by the Telegram service.
+type NotificationActionHandler struct {
+	actions NotificationActionInterface
+}
+
+// NotificationCallbackRequest is the body of a forwarded callback query.
+type NotificationCallbackRequest struct {
+	ChatID string `json:"chat_id" binding:"required"`
+	Data   string `json:"data" binding:"required"`
+}
+
+// NewNotificationActionHandler creates a new notification action handler.
+//
+// Parameters:
+//
+//	actions: The notification action service (may be nil when Redis is unavailable).
+//
+// Returns:
+//
+//	*NotificationActionHandler: The initialized handler.
+func NewNotificationActionHandler(actions NotificationActionInterface) *NotificationActionHandler {
+	return &NotificationActionHandler{actions: actions}
+}
+
+// HandleCallback processes a callback query from an opportunity notification.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *NotificationActionHandler) HandleCallback(c *gin.Context) {
+	if h.actions == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "notification actions not enabled"})
+		return
+	}
+
+	var req NotificationCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "chat_id and data are required"})
+		return
+	}
+	chatID, err := strconv.ParseInt(req.ChatID, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "invalid chat_id"})
+		return
+	}
+
+	response, err := h.actions.HandleCallback(c.Request.Context(), chatID, req.Data)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, gin.H{"status": "success", "data": response})
+	case errors.Is(err, services.ErrInvalidActionSignature):
+		c.JSON(http.StatusForbidden, gin.H{"status": "error", "error": "invalid action"})
+	case errors.Is(err, services.ErrNotificationActionExpired):
+		c.JSON(http.StatusGone, gin.H{"status": "error", "error": "This action has expired"})
+	case errors.Is(err, services.ErrNotificationActionUsed):
+		c.JSON(http.StatusConflict, gin.H{"status": "error", "error": "This action was already executed"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+	}
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubNotificationActions struct {
+	err error
+}
+
+func (s *stubNotificationActions) HandleCallback(ctx context.Context, chatID int64, data string) (*services.NotificationActionResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &services.NotificationActionResponse{Text: "ok", Toast: "ok"}, nil
+}
+
+func performCallback(handler *NotificationActionHandler, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/internal/telegram/callbacks", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	handler.HandleCallback(c)
+	return w
+}
+
+func TestNotificationActionHandler_HandleCallback(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		err    error
+		body   string
+		status int
+	}{
+		{"success", nil, `{"chat_id":"42","data":"na:x"}`, http.StatusOK},
+		{"spoofed", services.ErrInvalidActionSignature, `{"chat_id":"42","data":"na:x"}`, http.StatusForbidden},
+		{"expired", services.ErrNotificationActionExpired, `{"chat_id":"42","data":"na:x"}`, http.StatusGone},
+		{"already executed", services.ErrNotificationActionUsed, `{"chat_id":"42","data":"na:x"}`, http.StatusConflict},
+		{"missing data", nil, `{"chat_id":"42"}`, http.StatusBadRequest},
+		{"invalid chat", nil, `{"chat_id":"abc","data":"na:x"}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewNotificationActionHandler(&stubNotificationActions{err: tt.err})
+			w := performCallback(handler, tt.body)
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestNotificationActionHandler_Disabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := performCallback(NewNotificationActionHandler(nil), `{"chat_id":"42","data":"na:x"}`)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// NotificationQueueInterface defines the notification delivery operations exposed to admins.
+type NotificationQueueInterface interface {
+	// GetDeliveryQueueStats returns queue depth and delivery counters.
+	GetDeliveryQueueStats(ctx context.Context) (*services.NotificationQueueStats, error)
+	// GetDeadLetterStats returns statistics about persisted dead letters.
+	GetDeadLetterStats(ctx context.Context) (*services.DeadLetterStats, error)
+	// ReplayDeadLetters re-enqueues dead-lettered messages.
+	ReplayDeadLetters(ctx context.Context, limit int, includeFailed bool) (int, error)
+	// GetUserDeliveryStats returns delivery receipts and counters for a user.
+	GetUserDeliveryStats(ctx context.Context, userID string, recentLimit int) (*services.DeliveryStats, error)
+}
+
+// NotificationQueueHandler handles admin endpoints for notification delivery.
+type NotificationQueueHandler struct {
+	notifications NotificationQueueInterface
+}
+
+// NewNotificationQueueHandler creates a new notification queue handler.
+//
+// Parameters:
+//
+//	notifications: The notification service implementation.
+//
+// Returns:
+//
+//	*NotificationQueueHandler: The initialized handler.
+func NewNotificationQueueHandler(notifications NotificationQueueInterface) *NotificationQueueHandler {
+	return &NotificationQueueHandler{notifications: notifications}
+}
+
+// GetQueueStats returns delivery queue and dead letter statistics.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *NotificationQueueHandler) GetQueueStats(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	response := gin.H{}
+	if stats, err := h.notifications.GetDeliveryQueueStats(ctx); err == nil {
+		response["queue"] = stats
+	} else {
+		response["queue_error"] = err.Error()
+	}
+	if stats, err := h.notifications.GetDeadLetterStats(ctx); err == nil {
+		response["dead_letters"] = stats
+	} else {
+		response["dead_letters_error"] = err.Error()
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": response})
+}
+
+// ReplayDeadLetters re-enqueues dead-lettered notifications for delivery.
+// Query parameters: limit (default 100, max 1000), include_failed (bool).
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *NotificationQueueHandler) ReplayDeadLetters(c *gin.Context) {
+	limit := 100
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "limit must be a positive integer"})
+			return
+		}
+		limit = parsed
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+	includeFailed := c.Query("include_failed") == "true"
+
+	replayed, err := h.notifications.ReplayDeadLetters(c.Request.Context(), limit, includeFailed)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status": "error",
+			"error":  "failed to replay dead letters",
+			"data":   gin.H{"replayed": replayed},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   gin.H{"replayed": replayed},
+	})
+}
+
+// GetUserDeliveryStats returns delivery receipts for a user. The user is taken
+// from the :userId path parameter (admin) or the authenticated user.
+// Query parameters: recent (default 20, max 50).
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *NotificationQueueHandler) GetUserDeliveryStats(c *gin.Context) {
+	userID := c.Param("userId")
+	if userID == "" {
+		userID = c.GetString("user_id")
+	}
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "user id is required"})
+		return
+	}
+
+	recent := 20
+	if raw := c.Query("recent"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "recent must be a non-negative integer"})
+			return
+		}
+		recent = parsed
+	}
+	if recent > 50 {
+		recent = 50
+	}
+
+	stats, err := h.notifications.GetUserDeliveryStats(c.Request.Context(), userID, recent)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": stats})
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubNotificationQueue struct {
+	replayLimit   int
+	includeFailed bool
+	replayErr     error
+}
+
+func (s *stubNotificationQueue) GetDeliveryQueueStats(ctx context.Context) (*services.NotificationQueueStats, error) {
+	return &services.NotificationQueueStats{Workers: 2, Running: true}, nil
+}
+
+func (s *stubNotificationQueue) GetDeadLetterStats(ctx context.Context) (*services.DeadLetterStats, error) {
+	return nil, errors.New("dead letter service not initialized")
+}
+
+func (s *stubNotificationQueue) ReplayDeadLetters(ctx context.Context, limit int, includeFailed bool) (int, error) {
+	s.replayLimit = limit
+	s.includeFailed = includeFailed
+	return 3, s.replayErr
+}
+
+func (s *stubNotificationQueue) GetUserDeliveryStats(ctx context.Context, userID string, recentLimit int) (*services.DeliveryStats, error) {
+	if userID == "missing" {
+		return nil, errors.New("delivery tracking not enabled")
+	}
+	return &services.DeliveryStats{UserID: userID, Sent: 4, Blocked: 1}, nil
+}
+
+func TestNotificationQueueHandler_GetQueueStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewNotificationQueueHandler(&stubNotificationQueue{})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/admin/notifications/queue", nil)
+	handler.GetQueueStats(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	data := body["data"].(map[string]interface{})
+	assert.Contains(t, data, "queue")
+	assert.Contains(t, data, "dead_letters_error")
+}
+
+func TestNotificationQueueHandler_ReplayDeadLetters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		query          string
+		replayErr      error
+		expectedStatus int
+		expectedLimit  int
+	}{
+		{name: "defaults", query: "", expectedStatus: http.StatusOK, expectedLimit: 100},
+		{name: "capped limit", query: "?limit=5000&include_failed=true", expectedStatus: http.StatusOK, expectedLimit: 1000},
+		{name: "invalid limit", query: "?limit=abc", expectedStatus: http.StatusBadRequest},
+		{name: "service error", query: "?limit=10", replayErr: errors.New("boom"), expectedStatus: http.StatusInternalServerError, expectedLimit: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubNotificationQueue{replayErr: tt.replayErr}
+			handler := NewNotificationQueueHandler(stub)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/admin/notifications/dead-letters/replay"+tt.query, nil)
+			handler.ReplayDeadLetters(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedLimit, stub.replayLimit)
+		})
+	}
+}
+
+func TestNotificationQueueHandler_GetUserDeliveryStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewNotificationQueueHandler(&stubNotificationQueue{})
+
+	t.Run("authenticated user", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/users/notifications/delivery", nil)
+		c.Set("user_id", "user-1")
+		handler.GetUserDeliveryStats(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"user_id":"user-1"`)
+	})
+
+	t.Run("missing user", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/users/notifications/delivery", nil)
+		handler.GetUserDeliveryStats(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("tracking disabled", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/admin/notifications/delivery/missing", nil)
+		c.Params = gin.Params{{Key: "userId", Value: "missing"}}
+		handler.GetUserDeliveryStats(c)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// OperatingProfileManager defines the operations on a chat's operating profiles.
+type OperatingProfileManager interface {
+	List(ctx context.Context, chatID string) (*services.ProfileList, error)
+	Resolve(ctx context.Context, chatID, name string) (*services.OperatingProfile, error)
+	Select(ctx context.Context, chatID, name string) (*services.OperatingProfile, error)
+	Save(ctx context.Context, chatID, base string, profile services.OperatingProfile) (*services.OperatingProfile, error)
+	Delete(ctx context.Context, chatID, name string) error
+}
+
+// OperatingProfileHandler manages the operating profiles autonomous trading
+// runs with.
+type OperatingProfileHandler struct {
+	profiles OperatingProfileManager
+}
+
+// SelectProfileRequest selects the profile a chat trades with.
+type SelectProfileRequest struct {
+	ChatID string `json:"chat_id" binding:"required"`
+	// Profile is conservative, balanced, aggressive or a custom profile.
+	Profile string `json:"profile" binding:"required"`
+}
+
+// SaveProfileRequest creates or replaces a custom profile.
+type SaveProfileRequest struct {
+	ChatID string `json:"chat_id" binding:"required"`
+	// Base is the profile unset fields are taken from; defaults to balanced.
+	Base    string                    `json:"base,omitempty"`
+	Profile services.OperatingProfile `json:"profile"`
+}
+
+// ProfileChangeResponse acknowledges a deletion.
+type ProfileChangeResponse struct {
+	Name    string `json:"name"`
+	Deleted bool   `json:"deleted"`
+}
+
+// NewOperatingProfileHandler creates a new operating profile handler.
+//
+// Parameters:
+//
+//	profiles: The operating profile service (may be nil when Redis is unavailable).
+//
+// Returns:
+//
+//	*OperatingProfileHandler: The initialized handler.
+func NewOperatingProfileHandler(profiles OperatingProfileManager) *OperatingProfileHandler {
+	return &OperatingProfileHandler{profiles: profiles}
+}
+
+func (h *OperatingProfileHandler) available(c *gin.Context) bool {
+	if h.profiles == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "operating profiles not available"})
+		return false
+	}
+	return true
+}
+
+// ListProfiles returns the profiles a chat can select and the selected one.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *OperatingProfileHandler) ListProfiles(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	chatID := c.Query("chat_id")
+	if chatID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "chat_id is required"})
+		return
+	}
+	list, err := h.profiles.List(c.Request.Context(), chatID)
+	if err != nil {
+		writeProfileError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": list})
+}
+
+// SelectProfile makes a profile the one the chat trades with.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *OperatingProfileHandler) SelectProfile(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req SelectProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "chat_id and profile are required"})
+		return
+	}
+	profile, err := h.profiles.Select(c.Request.Context(), req.ChatID, req.Profile)
+	if err != nil {
+		writeProfileError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": profile})
+}
+
+// SaveProfile creates or replaces a custom profile of the chat.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *OperatingProfileHandler) SaveProfile(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req SaveProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "chat_id and profile are required"})
+		return
+	}
+	profile, err := h.profiles.Save(c.Request.Context(), req.ChatID, req.Base, req.Profile)
+	if err != nil {
+		writeProfileError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": profile})
+}
+
+// DeleteProfile removes a custom profile of the chat.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *OperatingProfileHandler) DeleteProfile(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	chatID := c.Query("chat_id")
+	if chatID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "chat_id is required"})
+		return
+	}
+	if err := h.profiles.Delete(c.Request.Context(), chatID, c.Param("name")); err != nil {
+		writeProfileError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": ProfileChangeResponse{Name: c.Param("name"), Deleted: true}})
+}
+
+func writeProfileError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrProfileInvalid), errors.Is(err, services.ErrProfileLimit):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrProfileNotFound):
+		status = http.StatusNotFound
+	}
+	c.JSON(status, gin.H{"status": "error", "error": err.Error()})
+}
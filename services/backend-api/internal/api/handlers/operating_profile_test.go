@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestOperatingProfileService(t *testing.T) *services.OperatingProfileService {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return services.NewOperatingProfileService(client)
+}
+
+func TestOperatingProfileHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewOperatingProfileHandler(newTestOperatingProfileService(t))
+
+	w := performTradingModeRequest(handler.SaveProfile, `{"chat_id":"42","base":"conservative","profile":{"name":"Night","scan_interval_seconds":900}}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"night"`)
+	assert.Contains(t, w.Body.String(), `"min_confidence":0.8`)
+
+	w = performTradingModeRequest(handler.SelectProfile, `{"chat_id":"42","profile":"night"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/telegram/internal/profiles?chat_id=42", nil)
+	handler.ListProfiles(c)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"active":"night"`)
+	assert.Contains(t, rec.Body.String(), `"name":"aggressive"`)
+
+	for _, want := range []int{http.StatusOK, http.StatusNotFound} {
+		rec = httptest.NewRecorder()
+		c, _ = gin.CreateTestContext(rec)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/api/v1/telegram/internal/profiles/night?chat_id=42", nil)
+		c.Params = gin.Params{{Key: "name", Value: "night"}}
+		handler.DeleteProfile(c)
+		assert.Equal(t, want, rec.Code)
+	}
+}
+
+func TestOperatingProfileHandler_InvalidRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewOperatingProfileHandler(newTestOperatingProfileService(t))
+
+	w := performTradingModeRequest(handler.SelectProfile, `{"chat_id":"42","profile":"yolo"}`, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = performTradingModeRequest(handler.SaveProfile, `{"chat_id":"42","profile":{"name":"balanced"}}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "built-in profile")
+
+	w = performTradingModeRequest(handler.SaveProfile, `{"chat_id":"42","profile":{"name":"wild","daily_loss_pct":50}}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "daily_loss_pct")
+
+	w = performTradingModeRequest(handler.SelectProfile, `{"chat_id":"42"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	unavailable := NewOperatingProfileHandler(nil)
+	w = performTradingModeRequest(unavailable.SelectProfile, `{"chat_id":"42","profile":"balanced"}`, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// maxOpportunityHistory caps the lifecycles one history request may return.
+const maxOpportunityHistory = 1000
+
+// OpportunityHistorySource queries persisted opportunity lifecycles.
+type OpportunityHistorySource interface {
+	History(ctx context.Context, filter services.OpportunityLifecycleFilter) ([]services.OpportunityLifecycle, services.OpportunityLifecycleSummary, error)
+}
+
+// OpportunityLifecycleHandler exposes the opportunity lifecycle history.
+type OpportunityLifecycleHandler struct {
+	history OpportunityHistorySource
+	now     func() time.Time
+}
+
+// NewOpportunityLifecycleHandler creates a new opportunity lifecycle handler.
+//
+// Parameters:
+//
+//	history: The lifecycle service (may be nil when no database is configured).
+//
+// Returns:
+//
+//	*OpportunityLifecycleHandler: The initialized handler.
+func NewOpportunityLifecycleHandler(history OpportunityHistorySource) *OpportunityLifecycleHandler {
+	return &OpportunityLifecycleHandler{history: history, now: time.Now}
+}
+
+// GetHistory returns opportunity lifecycles with a summary of how many
+// detected edges were notified, executed or expired. Query parameters:
+// since as an RFC 3339 time (default the last 24 hours), symbol, state
+// (detected, notified, executed or expired) and limit (default 100).
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *OpportunityLifecycleHandler) GetHistory(c *gin.Context) {
+	if h.history == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "opportunity history not available"})
+		return
+	}
+
+	filter := services.OpportunityLifecycleFilter{
+		Since:  h.now().UTC().Add(-24 * time.Hour),
+		Symbol: c.Query("symbol"),
+		State:  c.Query("state"),
+		Limit:  100,
+	}
+	if raw := c.Query("since"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "since must be an RFC 3339 time"})
+			return
+		}
+		filter.Since = parsed
+	}
+	switch filter.State {
+	case "", services.OpportunityStateDetected, services.OpportunityStateNotified,
+		services.OpportunityStateExecuted, services.OpportunityStateExpired:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "state must be detected, notified, executed or expired"})
+		return
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "limit must be a positive integer"})
+			return
+		}
+		filter.Limit = min(limit, maxOpportunityHistory)
+	}
+
+	lifecycles, summary, err := h.history.History(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{
+		"since":         filter.Since.UTC(),
+		"summary":       summary,
+		"opportunities": lifecycles,
+	}})
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubOpportunityHistory struct {
+	filter services.OpportunityLifecycleFilter
+}
+
+func (s *stubOpportunityHistory) History(_ context.Context, filter services.OpportunityLifecycleFilter) ([]services.OpportunityLifecycle, services.OpportunityLifecycleSummary, error) {
+	s.filter = filter
+	return []services.OpportunityLifecycle{{ID: "l1", Symbol: "BTC/USDT", State: services.OpportunityStateExpired, DurationSeconds: 90}},
+		services.OpportunityLifecycleSummary{Total: 4, Executed: 1, Expired: 3, CapturedRatio: 0.25}, nil
+}
+
+func performOpportunityHistoryRequest(handler *OpportunityLifecycleHandler, query string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/opportunities/history?"+query, nil)
+	handler.GetHistory(c)
+	return w
+}
+
+func TestOpportunityLifecycleHandler_GetHistory(t *testing.T) {
+	source := &stubOpportunityHistory{}
+	handler := NewOpportunityLifecycleHandler(source)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	handler.now = func() time.Time { return now }
+
+	w := performOpportunityHistoryRequest(handler, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, now.Add(-24*time.Hour), source.filter.Since)
+	assert.Equal(t, 100, source.filter.Limit)
+	assert.Contains(t, w.Body.String(), `"captured_ratio":0.25`)
+	assert.Contains(t, w.Body.String(), `"duration_seconds":90`)
+
+	w = performOpportunityHistoryRequest(handler, "since=2026-02-01T00:00:00Z&symbol=BTC/USDT&state=executed&limit=5000")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), source.filter.Since)
+	assert.Equal(t, "BTC/USDT", source.filter.Symbol)
+	assert.Equal(t, services.OpportunityStateExecuted, source.filter.State)
+	assert.Equal(t, maxOpportunityHistory, source.filter.Limit)
+
+	for _, query := range []string{"since=yesterday", "state=open", "limit=0"} {
+		assert.Equal(t, http.StatusBadRequest, performOpportunityHistoryRequest(handler, query).Code, query)
+	}
+}
+
+func TestOpportunityLifecycleHandler_Unavailable(t *testing.T) {
+	w := performOpportunityHistoryRequest(NewOpportunityLifecycleHandler(nil), "")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
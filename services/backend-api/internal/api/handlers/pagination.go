@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PageOptions describes the pagination, sorting and filtering a list endpoint accepts.
+type PageOptions struct {
+	DefaultLimit int
+	MaxLimit     int
+	// SortFields lists the sortable fields; the first one is the default.
+	SortFields  []string
+	DefaultDesc bool
+	// Filters lists the query parameters that may be used as equality filters.
+	Filters []string
+}
+
+// PageRequest is a parsed list query.
+type PageRequest struct {
+	Limit   int
+	Sort    string
+	Desc    bool
+	Filters map[string]string
+	cursor  *pageCursor
+}
+
+// PageInfo is returned alongside a page of results.
+type PageInfo struct {
+	Limit      int    `json:"limit"`
+	Sort       string `json:"sort"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	TotalCount int    `json:"total_count"`
+}
+
+// pageCursor is the opaque keyset position of the last item on a page.
+type pageCursor struct {
+	Sort string `json:"s"`
+	Desc bool   `json:"d"`
+	Key  string `json:"k"`
+	ID   string `json:"i"`
+}
+
+// ParsePageRequest reads limit, cursor, sort and filter query parameters.
+// Sort takes a field name, prefixed with "-" for descending order. Limits
+// above MaxLimit are clamped rather than rejected.
+//
+// Parameters:
+//
+//	c: Gin context.
+//	opts: Options for the endpoint.
+//
+// Returns:
+//
+//	PageRequest: The parsed request.
+//	error: Error if a parameter is malformed or not allowed.
+func ParsePageRequest(c *gin.Context, opts PageOptions) (PageRequest, error) {
+	req := PageRequest{Limit: opts.DefaultLimit, Desc: opts.DefaultDesc, Filters: map[string]string{}}
+	if len(opts.SortFields) > 0 {
+		req.Sort = opts.SortFields[0]
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return PageRequest{}, fmt.Errorf("limit must be a positive integer")
+		}
+		req.Limit = limit
+	}
+	if opts.MaxLimit > 0 && req.Limit > opts.MaxLimit {
+		req.Limit = opts.MaxLimit
+	}
+
+	if raw := strings.TrimSpace(c.Query("sort")); raw != "" {
+		field := strings.TrimPrefix(raw, "-")
+		if !containsField(opts.SortFields, field) {
+			return PageRequest{}, fmt.Errorf("cannot sort by %q; allowed: %s", field, strings.Join(opts.SortFields, ", "))
+		}
+		req.Sort = field
+		req.Desc = strings.HasPrefix(raw, "-")
+	}
+
+	for _, name := range opts.Filters {
+		if value := strings.TrimSpace(c.Query(name)); value != "" {
+			req.Filters[name] = value
+		}
+	}
+
+	if raw := c.Query("cursor"); raw != "" {
+		cursor, err := decodePageCursor(raw)
+		if err != nil {
+			return PageRequest{}, err
+		}
+		if cursor.Sort != req.Sort || cursor.Desc != req.Desc {
+			return PageRequest{}, fmt.Errorf("cursor does not match the requested sort")
+		}
+		req.cursor = cursor
+	}
+
+	return req, nil
+}
+
+// Filter returns the value of an equality filter, or "" when it is not set.
+func (r PageRequest) Filter(name string) string {
+	return r.Filters[name]
+}
+
+// PaginateSlice sorts items by the requested field and returns the page after
+// the request's cursor. Ties are broken by id so cursors stay stable.
+//
+// Parameters:
+//
+//	items: The full, already filtered result set.
+//	req: The parsed page request.
+//	key: Returns the sortable string for an item and field (see pageTimeKey).
+//	id: Returns the unique identifier of an item.
+//
+// Returns:
+//
+//	[]T: The items on the requested page.
+//	PageInfo: Page metadata including the next cursor and total count.
+func PaginateSlice[T any](items []T, req PageRequest, key func(item T, field string) string, id func(item T) string) ([]T, PageInfo) {
+	type entry struct {
+		item T
+		key  string
+		id   string
+	}
+	entries := make([]entry, len(items))
+	for i, item := range items {
+		entries[i] = entry{item: item, key: key(item, req.Sort), id: id(item)}
+	}
+	less := func(a, b entry) bool {
+		if a.key != b.key {
+			return (a.key < b.key) != req.Desc
+		}
+		if a.id == b.id {
+			return false
+		}
+		return (a.id < b.id) != req.Desc
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return less(entries[i], entries[j]) })
+
+	start := 0
+	if req.cursor != nil {
+		last := entry{key: req.cursor.Key, id: req.cursor.ID}
+		start = sort.Search(len(entries), func(i int) bool { return less(last, entries[i]) })
+	}
+	end := start + req.Limit
+	if end > len(entries) {
+		end = len(entries)
+	}
+
+	page := make([]T, 0, end-start)
+	for _, e := range entries[start:end] {
+		page = append(page, e.item)
+	}
+
+	info := PageInfo{Limit: req.Limit, Sort: req.Sort, HasMore: end < len(entries), TotalCount: len(entries)}
+	if req.Desc {
+		info.Sort = "-" + req.Sort
+	}
+	if info.HasMore && end > start {
+		last := entries[end-1]
+		info.NextCursor = encodePageCursor(pageCursor{Sort: req.Sort, Desc: req.Desc, Key: last.key, ID: last.id})
+	}
+	return page, info
+}
+
+// writePageHeaders exposes the total count hint for clients that only read headers.
+func writePageHeaders(c *gin.Context, info PageInfo) {
+	c.Header("X-Total-Count", strconv.Itoa(info.TotalCount))
+}
+
+// pageTimeKey formats a time so that string order matches chronological order.
+func pageTimeKey(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000000000Z")
+}
+
+// pageIntKey formats a non-negative integer so that string order matches numeric order.
+func pageIntKey(v int) string {
+	return fmt.Sprintf("%020d", v)
+}
+
+func encodePageCursor(cursor pageCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodePageCursor(raw string) (*pageCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	var cursor pageCursor
+	if err := json.Unmarshal(decoded, &cursor); err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &cursor, nil
+}
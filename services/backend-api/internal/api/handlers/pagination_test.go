@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pageItem struct {
+	ID    string
+	Score int
+}
+
+var testPageOptions = PageOptions{
+	DefaultLimit: 2,
+	MaxLimit:     3,
+	SortFields:   []string{"score"},
+	Filters:      []string{"kind"},
+}
+
+func parseTestPage(t *testing.T, query string) (PageRequest, error) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+	return ParsePageRequest(c, testPageOptions)
+}
+
+func TestParsePageRequest(t *testing.T) {
+	req, err := parseTestPage(t, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, req.Limit)
+	assert.Equal(t, "score", req.Sort)
+	assert.False(t, req.Desc)
+
+	req, err = parseTestPage(t, "limit=50&sort=-score&kind=spot")
+	require.NoError(t, err)
+	assert.Equal(t, 3, req.Limit, "limit is clamped to MaxLimit")
+	assert.True(t, req.Desc)
+	assert.Equal(t, "spot", req.Filter("kind"))
+
+	for _, query := range []string{"limit=0", "limit=abc", "sort=name", "cursor=not-a-cursor"} {
+		_, err := parseTestPage(t, query)
+		assert.Error(t, err, query)
+	}
+}
+
+func TestPaginateSlice_WalksAllPages(t *testing.T) {
+	items := []pageItem{{"e", 2}, {"a", 5}, {"c", 2}, {"b", 9}, {"d", 1}}
+	key := func(i pageItem, _ string) string { return pageIntKey(i.Score) }
+	id := func(i pageItem) string { return i.ID }
+
+	var seen []string
+	query := "sort=-score"
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 5, "pagination did not terminate")
+		req, err := parseTestPage(t, query)
+		require.NoError(t, err)
+		page, info := PaginateSlice(items, req, key, id)
+		assert.Equal(t, 5, info.TotalCount)
+		assert.Equal(t, "-score", info.Sort)
+		for _, item := range page {
+			seen = append(seen, item.ID)
+		}
+		if !info.HasMore {
+			assert.Empty(t, info.NextCursor)
+			break
+		}
+		query = "sort=-score&cursor=" + url.QueryEscape(info.NextCursor)
+	}
+	assert.Equal(t, []string{"b", "a", "e", "c", "d"}, seen)
+
+	// A cursor issued for one sort order cannot be replayed with another.
+	req, _ := parseTestPage(t, "sort=-score")
+	_, info := PaginateSlice(items, req, key, id)
+	_, err := parseTestPage(t, "sort=score&cursor="+url.QueryEscape(info.NextCursor))
+	assert.Error(t, err)
+}
+
+func TestTradingHandlerListPositions_Pagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h, mock := setupTradingHandlerWithMock(t)
+	defer closeMock(t, mock)
+
+	r := gin.New()
+	r.GET("/trading/positions", h.ListPositions)
+
+	now := time.Now()
+	rows := pgxmock.NewRows([]string{
+		"position_id", "order_id", "exchange", "symbol", "side", "size", "entry_price", "status", "opened_at", "updated_at",
+	})
+	for i, symbol := range []string{"BTC/USDT", "ETH/USDT", "BTC/USDT", "BTC/USDT"} {
+		opened := now.Add(-time.Duration(i) * time.Minute)
+		rows.AddRow("pos-"+string(rune('a'+i)), "ord", "binance", symbol, "BUY",
+			decimal.NewFromInt(1), decimal.NewFromInt(100), "OPEN", opened, opened)
+	}
+	mock.ExpectQuery("SELECT position_id, order_id, exchange, symbol, side, size, entry_price, status, opened_at, updated_at FROM trading_positions").
+		WillReturnRows(rows)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/trading/positions?symbol=btc/usdt&limit=2", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "3", w.Header().Get("X-Total-Count"))
+
+	var resp struct {
+		Data struct {
+			Count     int              `json:"count"`
+			Positions []PositionRecord `json:"positions"`
+			Page      PageInfo         `json:"pagination"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Data.Count)
+	require.Len(t, resp.Data.Positions, 2)
+	assert.Equal(t, "pos-a", resp.Data.Positions[0].PositionID, "newest first")
+	assert.Equal(t, "pos-c", resp.Data.Positions[1].PositionID)
+	assert.True(t, resp.Data.Page.HasMore)
+	assert.NotEmpty(t, resp.Data.Page.NextCursor)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/trading/positions?sort=price", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// PnLReportProvider builds PnL reports.
+type PnLReportProvider interface {
+	Report(ctx context.Context, chatID, period string) (*services.PnLReport, error)
+}
+
+// PnLHandler exposes the PnL report behind the Telegram /pnl command.
+type PnLHandler struct {
+	reporter PnLReportProvider
+}
+
+// NewPnLHandler creates a new PnL handler.
+//
+// Parameters:
+//
+//	reporter: The PnL reporter (may be nil).
+//
+// Returns:
+//
+//	*PnLHandler: The initialized handler.
+func NewPnLHandler(reporter PnLReportProvider) *PnLHandler {
+	return &PnLHandler{reporter: reporter}
+}
+
+// GetReport returns the PnL report of a chat together with its formatted
+// message. It supports query parameters: chat_id and period (24h, 7d or 30d,
+// default 24h).
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *PnLHandler) GetReport(c *gin.Context) {
+	if h.reporter == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "pnl reporter not available"})
+		return
+	}
+	chatID := c.Query("chat_id")
+	if chatID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "chat_id is required"})
+		return
+	}
+	period, _, err := services.ParsePnLPeriod(c.Query("period"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	report, err := h.reporter.Report(c.Request.Context(), chatID, period)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{
+		"report": report,
+		"text":   services.FormatPnLReport(report, "PnL Report"),
+	}})
+}
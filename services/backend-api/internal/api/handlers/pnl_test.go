@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type staticClosedTrades []services.ClosedTrade
+
+func (s staticClosedTrades) ClosedTrades(context.Context, time.Time) ([]services.ClosedTrade, error) {
+	return s, nil
+}
+
+func TestPnLHandler_GetReport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	reporter := services.NewPnLReporter(staticClosedTrades{
+		{Symbol: "BTC/USDT", PnL: decimal.NewFromInt(12), Fees: decimal.NewFromInt(1)},
+	}, nil, nil)
+	handler := NewPnLHandler(reporter)
+
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, url, nil)
+		handler.GetReport(c)
+		return w
+	}
+
+	w := get("/api/v1/telegram/internal/pnl?chat_id=1&period=30d")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"period":"30d"`)
+	assert.Contains(t, w.Body.String(), "Net: +11.00 USDT")
+
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/telegram/internal/pnl?chat_id=1&period=1y").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/telegram/internal/pnl").Code)
+
+	w = performTradingModeRequest(NewPnLHandler(nil).GetReport, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// ProfileCaptureSource lists the profiles captured by the continuous profiler.
+type ProfileCaptureSource interface {
+	Captures() []services.ProfileCapture
+}
+
+// ProfilerHandler exposes the profiles captured on latency and memory
+// threshold breaches.
+type ProfilerHandler struct {
+	profiler ProfileCaptureSource
+}
+
+// NewProfilerHandler creates a new profiler handler.
+//
+// Parameters:
+//
+//	profiler: The continuous profiler (may be nil when disabled).
+//
+// Returns:
+//
+//	*ProfilerHandler: The initialized handler.
+func NewProfilerHandler(profiler ProfileCaptureSource) *ProfilerHandler {
+	return &ProfilerHandler{profiler: profiler}
+}
+
+// GetCaptures returns the most recent captured profiles, newest first, with
+// where each was stored.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *ProfilerHandler) GetCaptures(c *gin.Context) {
+	if h.profiler == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "profiler not enabled"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"captures": h.profiler.Captures()}})
+}
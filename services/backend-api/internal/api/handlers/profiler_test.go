@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubProfileCaptures []services.ProfileCapture
+
+func (s stubProfileCaptures) Captures() []services.ProfileCapture {
+	return s
+}
+
+func performProfilerRequest(handler *ProfilerHandler) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/admin/profiles", nil)
+	handler.GetCaptures(c)
+	return w
+}
+
+func TestProfilerHandler_GetCaptures(t *testing.T) {
+	w := performProfilerRequest(NewProfilerHandler(nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	captures := stubProfileCaptures{{
+		Kind:       services.ProfileKindHeap,
+		Reason:     "heap in use 900MB over 768MB",
+		Location:   "profiles/heap-20260301T120000Z.pprof",
+		Bytes:      2048,
+		CapturedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}}
+	w = performProfilerRequest(NewProfilerHandler(captures))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"location":"profiles/heap-20260301T120000Z.pprof"`)
+	assert.Contains(t, w.Body.String(), `"kind":"heap"`)
+}
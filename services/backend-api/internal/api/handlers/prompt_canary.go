@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// PromptCanaryProvider defines the prompt canary rollout operations.
+type PromptCanaryProvider interface {
+	Status(ctx context.Context) (services.PromptCanaryStatus, error)
+	Evaluate(ctx context.Context) (services.PromptCanaryStatus, error)
+	Rollback(ctx context.Context, reason string) (services.PromptCanaryStatus, error)
+}
+
+// PromptCanaryHandler exposes the canary rollout of AI prompt/model versions.
+type PromptCanaryHandler struct {
+	canary PromptCanaryProvider
+}
+
+// NewPromptCanaryHandler creates a new prompt canary handler.
+//
+// Parameters:
+//
+//	canary: The canary rollout (may be nil when no canary is configured).
+//
+// Returns:
+//
+//	*PromptCanaryHandler: The initialized handler.
+func NewPromptCanaryHandler(canary PromptCanaryProvider) *PromptCanaryHandler {
+	return &PromptCanaryHandler{canary: canary}
+}
+
+// GetStatus returns the rollout state and per-version outcomes.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *PromptCanaryHandler) GetStatus(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	h.respond(c)(h.canary.Status(c.Request.Context()))
+}
+
+// EvaluateNow measures due outcomes and applies the rollback rule now.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *PromptCanaryHandler) EvaluateNow(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	h.respond(c)(h.canary.Evaluate(c.Request.Context()))
+}
+
+// Rollback stops routing decision cycles to the canary.
+//
+// Parameters:
+//
+//	c: Gin context with an optional JSON body {"reason": "..."}.
+func (h *PromptCanaryHandler) Rollback(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": err.Error()})
+			return
+		}
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		reason = "rolled back by operator"
+	}
+	h.respond(c)(h.canary.Rollback(c.Request.Context(), reason))
+}
+
+func (h *PromptCanaryHandler) available(c *gin.Context) bool {
+	if h.canary == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "prompt canary not configured"})
+		return false
+	}
+	return true
+}
+
+func (h *PromptCanaryHandler) respond(c *gin.Context) func(services.PromptCanaryStatus, error) {
+	return func(status services.PromptCanaryStatus, err error) {
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "success", "data": status})
+	}
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubPromptCanary struct {
+	status services.PromptCanaryStatus
+	reason string
+	err    error
+}
+
+func (s *stubPromptCanary) Status(context.Context) (services.PromptCanaryStatus, error) {
+	return s.status, s.err
+}
+
+func (s *stubPromptCanary) Evaluate(context.Context) (services.PromptCanaryStatus, error) {
+	return s.status, s.err
+}
+
+func (s *stubPromptCanary) Rollback(_ context.Context, reason string) (services.PromptCanaryStatus, error) {
+	s.reason = reason
+	s.status.State = services.PromptCanaryRolledBack
+	s.status.RollbackReason = reason
+	return s.status, s.err
+}
+
+func TestPromptCanaryHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	canary := &stubPromptCanary{status: services.PromptCanaryStatus{
+		Canary: services.PromptVersion{Name: "v2", Model: "gpt-4o-mini"},
+		State:  services.PromptCanaryRunning,
+	}}
+	handler := NewPromptCanaryHandler(canary)
+
+	w := performTradingModeRequest(handler.GetStatus, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"state":"running"`)
+
+	w = performTradingModeRequest(handler.Rollback, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "rolled back by operator", canary.reason)
+
+	w = performTradingModeRequest(handler.Rollback, `{"reason":"bad fills"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"rollback_reason":"bad fills"`)
+
+	w = performTradingModeRequest(handler.Rollback, `{"reason":`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	canary.err = errors.New("redis down")
+	w = performTradingModeRequest(handler.EvaluateNow, "", nil)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	w = performTradingModeRequest(NewPromptCanaryHandler(nil).GetStatus, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutonomousHandler_SetQuestCatchUp(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := services.NewQuestEngine(services.NewInMemoryQuestStore())
+	quest, err := engine.CreateQuest("daily_report", "42")
+	require.NoError(t, err)
+	handler := NewAutonomousHandler(engine)
+	params := gin.Params{{Key: "id", Value: quest.ID}}
+
+	w := performTradingModeRequest(handler.SetQuestCatchUp, `{"chat_id":"42","catch_up":"Backfill"}`, params)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"catch_up":"backfill"`)
+	assert.Equal(t, "backfill", quest.Metadata["catch_up"])
+
+	assert.Equal(t, http.StatusBadRequest, performTradingModeRequest(handler.SetQuestCatchUp, `{"chat_id":"42","catch_up":"later"}`, params).Code)
+	assert.Equal(t, http.StatusNotFound, performTradingModeRequest(handler.SetQuestCatchUp, `{"chat_id":"7","catch_up":"skip"}`, params).Code)
+	assert.Equal(t, http.StatusBadRequest, performTradingModeRequest(handler.SetQuestCatchUp, `{"chat_id":"42"}`, params).Code)
+	assert.Equal(t, http.StatusServiceUnavailable, performTradingModeRequest(NewAutonomousHandler(nil).SetQuestCatchUp, `{}`, params).Code)
+}
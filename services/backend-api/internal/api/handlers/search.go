@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// Searcher runs ranked searches across trades, signals, quests and logs.
+type Searcher interface {
+	Search(ctx context.Context, query string, kinds []string, limit int) (*services.SearchResponse, error)
+}
+
+// SearchHandler serves the global search.
+type SearchHandler struct {
+	searcher Searcher
+}
+
+// NewSearchHandler creates a new search handler.
+//
+// Parameters:
+//
+//	searcher: The search service (may be nil without a database).
+//
+// Returns:
+//
+//	*SearchHandler: The initialized handler.
+func NewSearchHandler(searcher Searcher) *SearchHandler {
+	return &SearchHandler{searcher: searcher}
+}
+
+// Search returns ranked results for ?q=, optionally limited to the
+// comma-separated kinds in ?scope= and to ?limit= results.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *SearchHandler) Search(c *gin.Context) {
+	if h.searcher == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "search not available"})
+		return
+	}
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "q is required"})
+		return
+	}
+	var kinds []string
+	if scope := c.Query("scope"); scope != "" {
+		kinds = strings.Split(scope, ",")
+	}
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "limit must be a positive integer"})
+			return
+		}
+		limit = parsed
+	}
+
+	response, err := h.searcher.Search(c.Request.Context(), query, kinds, limit)
+	if errors.Is(err, services.ErrInvalidSearchQuery) {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": response})
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingSearcher struct {
+	query string
+	kinds []string
+	limit int
+	err   error
+}
+
+func (r *recordingSearcher) Search(_ context.Context, query string, kinds []string, limit int) (*services.SearchResponse, error) {
+	r.query, r.kinds, r.limit = query, kinds, limit
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &services.SearchResponse{Query: query, Results: []services.SearchResult{{Kind: "quest", ID: "q-1", Title: "Daily Report [active]"}}}, nil
+}
+
+func TestSearchHandler_Search(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	searcher := &recordingSearcher{}
+	handler := NewSearchHandler(searcher)
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, url, nil)
+		handler.Search(c)
+		return w
+	}
+
+	w := get("/api/v1/search?q=quest:daily&scope=quests,logs&limit=5")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"title":"Daily Report [active]"`)
+	assert.Equal(t, "quest:daily", searcher.query)
+	assert.Equal(t, []string{"quests", "logs"}, searcher.kinds)
+	assert.Equal(t, 5, searcher.limit)
+
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/search").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/search?q=btc&limit=none").Code)
+
+	searcher.err = fmt.Errorf("%w: unknown search scope", services.ErrInvalidSearchQuery)
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/search?q=btc&scope=orders").Code)
+	searcher.err = errors.New("connection refused")
+	assert.Equal(t, http.StatusInternalServerError, get("/api/v1/search?q=btc").Code)
+
+	w = performTradingModeRequest(NewSearchHandler(nil).Search, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// SelfTester runs the end-to-end dry-run self-test.
+type SelfTester interface {
+	Run(ctx context.Context, query services.SelfTestQuery) (*services.SelfTestReport, error)
+}
+
+// SelfTestHandler serves the deep doctor self-test.
+type SelfTestHandler struct {
+	selfTest SelfTester
+}
+
+// RunSelfTestRequest selects the market the self-test runs against.
+type RunSelfTestRequest struct {
+	Exchange  string `json:"exchange"`
+	Symbol    string `json:"symbol"`
+	Timeframe string `json:"timeframe"`
+	// ChatID receives the test notification; without it that stage is skipped.
+	ChatID string `json:"chat_id"`
+}
+
+// NewSelfTestHandler creates a new self-test handler.
+//
+// Parameters:
+//
+//	selfTest: The self-test service (may be nil).
+//
+// Returns:
+//
+//	*SelfTestHandler: The initialized handler.
+func NewSelfTestHandler(selfTest SelfTester) *SelfTestHandler {
+	return &SelfTestHandler{selfTest: selfTest}
+}
+
+// RunSelfTest fetches market data, computes indicators, generates a signal,
+// simulates an order and sends a test notification, reporting each stage
+// with its timing. A failed stage is reported in the body, not the status.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *SelfTestHandler) RunSelfTest(c *gin.Context) {
+	if h.selfTest == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "self-test not available"})
+		return
+	}
+
+	var req RunSelfTestRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "invalid request body"})
+			return
+		}
+	}
+	query := services.SelfTestQuery{Exchange: req.Exchange, Symbol: req.Symbol, Timeframe: req.Timeframe}
+	if chatID := strings.TrimSpace(req.ChatID); chatID != "" {
+		parsed, err := strconv.ParseInt(chatID, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "chat_id must be a number"})
+			return
+		}
+		query.ChatID = parsed
+	}
+
+	report, err := h.selfTest.Run(c.Request.Context(), query)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrSelfTestInvalidQuery) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": report})
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubSelfTester struct {
+	query services.SelfTestQuery
+}
+
+func (s *stubSelfTester) Run(_ context.Context, query services.SelfTestQuery) (*services.SelfTestReport, error) {
+	if query.Symbol == "BTCUSDT" {
+		return nil, fmt.Errorf("%w: symbol must look like BTC/USDT", services.ErrSelfTestInvalidQuery)
+	}
+	s.query = query
+	return &services.SelfTestReport{Stages: []services.SelfTestStage{
+		{Name: services.SelfTestStageMarketData, Status: services.SelfTestFailed, Message: "fetch candles: timeout"},
+	}}, nil
+}
+
+func TestSelfTestHandler_RunSelfTest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tester := &stubSelfTester{}
+	handler := NewSelfTestHandler(tester)
+
+	w := performTradingModeRequest(handler.RunSelfTest, `{"symbol":"ETH/USDT","chat_id":"42"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"passed":false`)
+	assert.Contains(t, w.Body.String(), `"message":"fetch candles: timeout"`)
+	assert.Equal(t, services.SelfTestQuery{Symbol: "ETH/USDT", ChatID: 42}, tester.query)
+
+	w = performTradingModeRequest(handler.RunSelfTest, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, services.SelfTestQuery{}, tester.query)
+
+	w = performTradingModeRequest(handler.RunSelfTest, `{"chat_id":"me"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(handler.RunSelfTest, `{"symbol":"BTCUSDT"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(NewSelfTestHandler(nil).RunSelfTest, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// SessionReplayer replays a recorded trading day.
+type SessionReplayer interface {
+	Replay(ctx context.Context, req services.SessionReplayRequest) (*services.SessionReplayReport, error)
+}
+
+// SessionReplayHandler serves session replays for debugging.
+type SessionReplayHandler struct {
+	replayer SessionReplayer
+}
+
+// NewSessionReplayHandler creates a new session replay handler.
+//
+// Parameters:
+//
+//	replayer: The session replay service (may be nil without a database).
+//
+// Returns:
+//
+//	*SessionReplayHandler: The initialized handler.
+func NewSessionReplayHandler(replayer SessionReplayer) *SessionReplayHandler {
+	return &SessionReplayHandler{replayer: replayer}
+}
+
+// ReplayDay re-feeds a recorded day of a symbol through the signal and
+// decision pipeline without executing orders, next to the decisions recorded
+// that day. The "at" query (HH:MM on the day, or RFC3339) selects the candle
+// to explain.
+//
+// Parameters:
+//
+//	c: Gin context with exchange, symbol, date, interval and at queries.
+func (h *SessionReplayHandler) ReplayDay(c *gin.Context) {
+	if h.replayer == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "session replay not available"})
+		return
+	}
+	req, err := parseSessionReplayRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+
+	report, err := h.replayer.Replay(c.Request.Context(), req)
+	if errors.Is(err, services.ErrReplayNoData) {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": report})
+}
+
+func parseSessionReplayRequest(c *gin.Context) (services.SessionReplayRequest, error) {
+	req := services.SessionReplayRequest{
+		Exchange: strings.TrimSpace(c.Query("exchange")),
+		Symbol:   strings.TrimSpace(c.Query("symbol")),
+	}
+	if req.Exchange == "" || req.Symbol == "" {
+		return req, errors.New("exchange and symbol are required")
+	}
+	day, err := time.Parse(time.DateOnly, c.Query("date"))
+	if err != nil {
+		return req, errors.New("date must be YYYY-MM-DD")
+	}
+	req.Day = day
+
+	if raw := c.Query("interval"); raw != "" {
+		interval, err := time.ParseDuration(raw)
+		if err != nil || interval < time.Second || interval > services.MaxReplayInterval {
+			return req, fmt.Errorf("interval must be a duration between 1s and %s", services.MaxReplayInterval)
+		}
+		req.Interval = interval
+	}
+
+	if raw := c.Query("at"); raw != "" {
+		at, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			clock, clockErr := time.Parse("15:04", raw)
+			if clockErr != nil {
+				return req, errors.New("at must be HH:MM or RFC3339")
+			}
+			at = day.Add(time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute)
+		}
+		at = at.UTC()
+		req.At = &at
+	}
+	return req, nil
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubSessionReplayer struct {
+	req services.SessionReplayRequest
+	err error
+}
+
+func (s *stubSessionReplayer) Replay(_ context.Context, req services.SessionReplayRequest) (*services.SessionReplayReport, error) {
+	s.req = req
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &services.SessionReplayReport{Exchange: req.Exchange, Symbol: req.Symbol, Day: req.Day, Candles: 1440}, nil
+}
+
+func performReplayDay(handler *SessionReplayHandler, query string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/replay/day?"+query, nil)
+	handler.ReplayDay(c)
+	return w
+}
+
+func TestSessionReplayHandler_ReplayDay(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	replayer := &stubSessionReplayer{}
+	handler := NewSessionReplayHandler(replayer)
+
+	w := performReplayDay(handler, "exchange=binance&symbol=BTC/USDT&date=2026-05-01&interval=5m&at=14:32")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"candles":1440`)
+	assert.Equal(t, 5*time.Minute, replayer.req.Interval)
+	assert.Equal(t, time.Date(2026, 5, 1, 14, 32, 0, 0, time.UTC), *replayer.req.At)
+
+	w = performReplayDay(handler, "exchange=binance&symbol=BTC/USDT&date=2026-05-01&at=2026-05-01T16:32:00%2B02:00")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, time.Date(2026, 5, 1, 14, 32, 0, 0, time.UTC), *replayer.req.At)
+
+	for _, query := range []string{
+		"symbol=BTC/USDT&date=2026-05-01",
+		"exchange=binance&symbol=BTC/USDT&date=yesterday",
+		"exchange=binance&symbol=BTC/USDT&date=2026-05-01&interval=2h",
+		"exchange=binance&symbol=BTC/USDT&date=2026-05-01&at=noon",
+	} {
+		assert.Equal(t, http.StatusBadRequest, performReplayDay(handler, query).Code, query)
+	}
+
+	replayer.err = fmt.Errorf("%w: binance BTC/USDT", services.ErrReplayNoData)
+	assert.Equal(t, http.StatusNotFound, performReplayDay(handler, "exchange=binance&symbol=BTC/USDT&date=2026-05-01").Code)
+	replayer.err = errors.New("database down")
+	assert.Equal(t, http.StatusInternalServerError, performReplayDay(handler, "exchange=binance&symbol=BTC/USDT&date=2026-05-01").Code)
+	assert.Equal(t, http.StatusServiceUnavailable, performReplayDay(NewSessionReplayHandler(nil), "").Code)
+}
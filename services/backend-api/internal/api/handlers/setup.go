@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// SetupManager defines the operations of the first-run setup wizard.
+type SetupManager interface {
+	State(ctx context.Context) (*services.SetupState, error)
+	UpdateStep(ctx context.Context, step services.SetupStepName, status services.SetupStepStatus, detail string) (*services.SetupState, error)
+	Verify(ctx context.Context) (*services.SetupVerification, error)
+	Reset(ctx context.Context) error
+}
+
+// SetupHandler serves the progress of the first-run setup wizard.
+type SetupHandler struct {
+	setup SetupManager
+}
+
+// UpdateSetupStepRequest records the outcome of a wizard step.
+type UpdateSetupStepRequest struct {
+	// Status is done, skipped or pending.
+	Status string `json:"status" binding:"required"`
+	// Detail describes what was configured, without secrets.
+	Detail string `json:"detail"`
+}
+
+// NewSetupHandler creates a new setup wizard handler.
+//
+// Parameters:
+//
+//	setup: The setup wizard service (may be nil when Redis is unavailable).
+//
+// Returns:
+//
+//	*SetupHandler: The initialized handler.
+func NewSetupHandler(setup SetupManager) *SetupHandler {
+	return &SetupHandler{setup: setup}
+}
+
+func (h *SetupHandler) available(c *gin.Context) bool {
+	if h.setup == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "setup wizard not available"})
+		return false
+	}
+	return true
+}
+
+// GetState returns every setup step with its status and the next one to do.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *SetupHandler) GetState(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	state, err := h.setup.State(c.Request.Context())
+	if err != nil {
+		writeSetupError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": state})
+}
+
+// UpdateStep marks a setup step done, skipped or pending.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *SetupHandler) UpdateStep(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req UpdateSetupStepRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "status is required"})
+		return
+	}
+	state, err := h.setup.UpdateStep(c.Request.Context(), services.SetupStepName(c.Param("step")), services.SetupStepStatus(req.Status), req.Detail)
+	if err != nil {
+		writeSetupError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": state})
+}
+
+// Verify runs the dry-run verification and records its outcome.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *SetupHandler) Verify(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	verification, err := h.setup.Verify(c.Request.Context())
+	if err != nil {
+		writeSetupError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": verification})
+}
+
+// ResetState forgets the recorded progress so the wizard starts over.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *SetupHandler) ResetState(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	if err := h.setup.Reset(c.Request.Context()); err != nil {
+		writeSetupError(c, err)
+		return
+	}
+	h.GetState(c)
+}
+
+func writeSetupError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrSetupUnknownStep):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrSetupInvalidStatus):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{"status": "error", "error": err.Error()})
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetupHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	handler := NewSetupHandler(services.NewSetupService(client, nil, services.SetupConfig{}))
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/setup/state", nil)
+	handler.GetState(c)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"next":"exchange_keys"`)
+
+	update := func(step, body string) *httptest.ResponseRecorder {
+		return performTradingModeRequest(handler.UpdateStep, body, gin.Params{{Key: "step", Value: step}})
+	}
+	w := update("exchange_keys", `{"status":"done","detail":"binance keys saved"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"next":"telegram_binding"`)
+	assert.Equal(t, http.StatusNotFound, update("wallets", `{"status":"done"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, update("dry_run", `{"status":"done"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, update("telegram_binding", `{}`).Code)
+
+	w = performTradingModeRequest(handler.Verify, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"passed":false`)
+
+	w = performTradingModeRequest(handler.ResetState, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"completed":0`)
+
+	w = performTradingModeRequest(NewSetupHandler(nil).GetState, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// ShadowStrategyReporter defines the shadow-mode evaluation operations.
+type ShadowStrategyReporter interface {
+	Report() services.ShadowReport
+	Comparisons(limit int) []services.ShadowComparison
+	Reset()
+}
+
+// ShadowStrategyHandler exposes the comparison of live scalping with its
+// shadow variant.
+type ShadowStrategyHandler struct {
+	runner ShadowStrategyReporter
+}
+
+// NewShadowStrategyHandler creates a new shadow strategy handler.
+//
+// Parameters:
+//
+//	runner: The shadow strategy runner (may be nil when shadow mode is off).
+//
+// Returns:
+//
+//	*ShadowStrategyHandler: The initialized handler.
+func NewShadowStrategyHandler(runner ShadowStrategyReporter) *ShadowStrategyHandler {
+	return &ShadowStrategyHandler{runner: runner}
+}
+
+// GetReport returns the modeled results of the live strategy and the variant.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *ShadowStrategyHandler) GetReport(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": h.runner.Report()})
+}
+
+// GetComparisons returns the most recent cycles, newest first.
+//
+// Parameters:
+//
+//	c: Gin context with an optional "limit" query parameter (default 50).
+func (h *ShadowStrategyHandler) GetComparisons(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "limit must be a positive integer"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{
+		"comparisons": h.runner.Comparisons(limit),
+	}})
+}
+
+// Reset clears both ledgers, for example after the variant was changed.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *ShadowStrategyHandler) Reset(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	h.runner.Reset()
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": h.runner.Report()})
+}
+
+func (h *ShadowStrategyHandler) available(c *gin.Context) bool {
+	if h.runner == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "shadow strategy not enabled"})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubShadowStrategy struct {
+	limit  int
+	resets int
+}
+
+func (s *stubShadowStrategy) Report() services.ShadowReport {
+	return services.ShadowReport{Name: "tighter-stops", Cycles: 4, Agreements: 3}
+}
+
+func (s *stubShadowStrategy) Comparisons(limit int) []services.ShadowComparison {
+	s.limit = limit
+	return []services.ShadowComparison{{Live: services.ShadowDecision{Action: "hold"}, Shadow: services.ShadowDecision{Action: "hold"}, Agreed: true}}
+}
+
+func (s *stubShadowStrategy) Reset() { s.resets++ }
+
+func TestShadowStrategyHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	runner := &stubShadowStrategy{}
+	handler := NewShadowStrategyHandler(runner)
+	router := gin.New()
+	router.GET("/shadow", handler.GetReport)
+	router.GET("/shadow/comparisons", handler.GetComparisons)
+	router.POST("/shadow/reset", handler.Reset)
+
+	serve := func(method, url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, url, nil))
+		return w
+	}
+
+	w := serve(http.MethodGet, "/shadow")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"tighter-stops"`)
+
+	w = serve(http.MethodGet, "/shadow/comparisons?limit=10")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 10, runner.limit)
+	assert.Contains(t, w.Body.String(), `"agreed":true`)
+
+	w = serve(http.MethodGet, "/shadow/comparisons?limit=abc")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serve(http.MethodPost, "/shadow/reset")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, runner.resets)
+
+	w = performTradingModeRequest(NewShadowStrategyHandler(nil).GetReport, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
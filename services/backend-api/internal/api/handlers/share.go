@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// ShareLinkManager defines the share link operations.
+type ShareLinkManager interface {
+	Create(chatID, period string, ttl time.Duration, showAbsolute bool) (*services.ShareLink, error)
+	Verify(ctx context.Context, token string) (*services.ShareLink, error)
+	Revoke(ctx context.Context, id string) error
+	Performance(ctx context.Context, link *services.ShareLink) (*services.SharedPerformance, error)
+}
+
+// ShareHandler issues share links and serves the read-only performance
+// they expose.
+type ShareHandler struct {
+	links   ShareLinkManager
+	baseURL string
+}
+
+// CreateShareLinkRequest issues a share link.
+type CreateShareLinkRequest struct {
+	ChatID string `json:"chat_id" binding:"required"`
+	Period string `json:"period"`
+	// TTL is a Go duration such as "72h"; empty uses 7 days.
+	TTL          string `json:"ttl"`
+	ShowAbsolute bool   `json:"show_absolute"`
+}
+
+// NewShareHandler creates a new share handler.
+//
+// Parameters:
+//
+//	links: The share link service (may be nil when no secret is configured).
+//	baseURL: Public base URL the links are built on.
+//
+// Returns:
+//
+//	*ShareHandler: The initialized handler.
+func NewShareHandler(links ShareLinkManager, baseURL string) *ShareHandler {
+	return &ShareHandler{links: links, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// CreateLink issues a share link for a chat.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *ShareHandler) CreateLink(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req CreateShareLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "Invalid request body"})
+		return
+	}
+	var ttl time.Duration
+	if req.TTL != "" {
+		parsed, err := time.ParseDuration(req.TTL)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "invalid ttl " + req.TTL})
+			return
+		}
+		ttl = parsed
+	}
+	link, err := h.links.Create(req.ChatID, req.Period, ttl, req.ShowAbsolute)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"status": "success", "data": gin.H{
+		"link": link,
+		"url":  h.baseURL + "/api/v1/share/" + link.Token + "/page",
+	}})
+}
+
+// RevokeLink invalidates a share link.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *ShareHandler) RevokeLink(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	if err := h.links.Revoke(c.Request.Context(), c.Param("id")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success"})
+}
+
+// GetSharedPerformance returns the performance behind a share link as JSON.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *ShareHandler) GetSharedPerformance(c *gin.Context) {
+	performance, ok := h.sharedPerformance(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": performance})
+}
+
+// GetSharedPage renders the performance behind a share link as a read-only
+// HTML page.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *ShareHandler) GetSharedPage(c *gin.Context) {
+	performance, ok := h.sharedPerformance(c)
+	if !ok {
+		return
+	}
+	var page strings.Builder
+	if err := sharedPageTemplate.Execute(&page, sharedPageView{SharedPerformance: performance, Curve: curvePolyline(performance.EquityCurve)}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": "failed to render page"})
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page.String()))
+}
+
+func (h *ShareHandler) sharedPerformance(c *gin.Context) (*services.SharedPerformance, bool) {
+	if !h.available(c) {
+		return nil, false
+	}
+	// Shared pages must not be cached by proxies or indexed
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Robots-Tag", "noindex")
+
+	link, err := h.links.Verify(c.Request.Context(), c.Param("token"))
+	switch {
+	case errors.Is(err, services.ErrShareLinkExpired):
+		c.JSON(http.StatusGone, gin.H{"status": "error", "error": err.Error()})
+		return nil, false
+	case errors.Is(err, services.ErrShareLinkInvalid), errors.Is(err, services.ErrShareLinkRevoked):
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "error": err.Error()})
+		return nil, false
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return nil, false
+	}
+	performance, err := h.links.Performance(c.Request.Context(), link)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return nil, false
+	}
+	return performance, true
+}
+
+func (h *ShareHandler) available(c *gin.Context) bool {
+	if h.links == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "share links are not configured"})
+		return false
+	}
+	return true
+}
+
+const (
+	sharedCurveWidth  = 600
+	sharedCurveHeight = 200
+)
+
+type sharedPageView struct {
+	*services.SharedPerformance
+	Curve string
+}
+
+// curvePolyline scales curve points to SVG polyline coordinates.
+func curvePolyline(points []services.SharedCurvePoint) string {
+	if len(points) < 2 {
+		return ""
+	}
+	first, last := points[0].Time, points[len(points)-1].Time
+	low, high := points[0].Value, points[0].Value
+	for _, point := range points {
+		low, high = min(low, point.Value), max(high, point.Value)
+	}
+	span := last.Sub(first).Seconds()
+	coords := make([]string, 0, len(points))
+	for _, point := range points {
+		x := 0.0
+		if span > 0 {
+			x = point.Time.Sub(first).Seconds() / span * sharedCurveWidth
+		}
+		y := sharedCurveHeight / 2.0
+		if high > low {
+			y = sharedCurveHeight - (point.Value-low)/(high-low)*sharedCurveHeight
+		}
+		coords = append(coords, fmt.Sprintf("%.1f,%.1f", x, y))
+	}
+	return strings.Join(coords, " ")
+}
+
+var sharedPageTemplate = template.Must(template.New("share").Funcs(template.FuncMap{
+	"deref": func(v *float64) float64 { return *v },
+}).Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><meta name="robots" content="noindex">
+<title>NeuraTrade performance ({{.Period}})</title>
+<style>body{font-family:sans-serif;background:#111722;color:#dde3ec;max-width:680px;margin:2em auto}td{padding:4px 12px}svg{background:#182030}</style>
+</head><body>
+<h2>Performance, last {{.Period}}</h2>
+<table>
+{{if .ReturnPct}}<tr><td>Return</td><td>{{printf "%+.2f" (deref .ReturnPct)}}%</td></tr>{{end}}
+<tr><td>Trades</td><td>{{.Trades}} ({{.Wins}}W / {{.Losses}}L)</td></tr>
+<tr><td>Win rate</td><td>{{printf "%.1f" .WinRate}}%</td></tr>
+{{with .NetPnL}}<tr><td>Net PnL</td><td>{{.StringFixed 2}} USDT</td></tr>{{end}}
+{{with .Equity}}<tr><td>Equity</td><td>{{.StringFixed 2}} USDT</td></tr>{{end}}
+</table>
+{{if .Curve}}<h3>Equity curve ({{if eq .CurveUnit "usdt"}}USDT{{else}}%{{end}})</h3>
+<svg width="600" height="200" viewBox="0 0 600 200"><polyline fill="none" stroke="#3c8cf0" stroke-width="2" points="{{.Curve}}"/></svg>{{end}}
+{{if .Strategies}}<h3>Strategies</h3>
+<table><tr><th align="left">Strategy</th><th>Trades</th><th>Win rate</th></tr>
+{{range .Strategies}}<tr><td>{{.Strategy}}</td><td>{{.Trades}}</td><td>{{printf "%.1f" .WinRate}}%</td></tr>
+{{end}}</table>{{end}}
+<p><small>Generated {{.GeneratedAt.Format "2006-01-02 15:04 UTC"}}, link expires {{.ExpiresAt.Format "2006-01-02 15:04 UTC"}}.</small></p>
+</body></html>`))
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	closedAt := time.Now().Add(-time.Hour)
+	reporter := services.NewPnLReporter(staticClosedTrades{
+		{Strategy: "scalping", Symbol: "BTC/USDT", PnL: decimal.NewFromInt(12), ClosedAt: closedAt},
+		{Strategy: "scalping", Symbol: "ETH/USDT", PnL: decimal.NewFromInt(-4), ClosedAt: closedAt.Add(time.Minute)},
+	}, nil, nil)
+	handler := NewShareHandler(services.NewShareLinkService("secret", nil, reporter), "https://trade.example.com/")
+
+	router := gin.New()
+	router.POST("/share-links", handler.CreateLink)
+	router.GET("/share/:token", handler.GetSharedPerformance)
+	router.GET("/share/:token/page", handler.GetSharedPage)
+	serve := func(method, url, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, url, bytes.NewBufferString(body)))
+		return w
+	}
+
+	w := serve(http.MethodPost, "/share-links", `{"chat_id":"42","period":"7d","ttl":"72h","show_absolute":true}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created struct {
+		Data struct {
+			Link services.ShareLink `json:"link"`
+			URL  string             `json:"url"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	token := created.Data.Link.Token
+	assert.Equal(t, "https://trade.example.com/api/v1/share/"+token+"/page", created.Data.URL)
+
+	w = serve(http.MethodGet, "/share/"+token, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.Contains(t, w.Body.String(), `"win_rate":50`)
+	assert.Contains(t, w.Body.String(), `"net_pnl":"8"`)
+
+	w = serve(http.MethodGet, "/share/"+token+"/page", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, w.Body.String(), "Win rate</td><td>50.0%")
+	assert.Contains(t, w.Body.String(), "Net PnL</td><td>8.00 USDT")
+	assert.Contains(t, w.Body.String(), "<polyline")
+
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/share/forged.token", "").Code)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPost, "/share-links", `{"chat_id":"42","ttl":"soon"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPost, "/share-links", `{"chat_id":"42","period":"1y"}`).Code)
+
+	w = performTradingModeRequest(NewShareHandler(nil, "").CreateLink, "{}", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// SignalIngestor accepts signals from external strategy engines.
+type SignalIngestor interface {
+	Ingest(ctx context.Context, input services.InboundSignal) (*services.InboundSignalResult, error)
+	RecordOutcome(ctx context.Context, signalID string, profitable bool) (*services.SignalSourceStats, error)
+	ListSources(ctx context.Context) ([]services.SignalSourceStats, error)
+}
+
+// SignalIngestHandler exposes the inbound signal API.
+type SignalIngestHandler struct {
+	signals SignalIngestor
+}
+
+// NewSignalIngestHandler creates a new inbound signal handler.
+//
+// Parameters:
+//
+//	signals: The signal ingest service (may be nil).
+//
+// Returns:
+//
+//	*SignalIngestHandler: The initialized handler.
+func NewSignalIngestHandler(signals SignalIngestor) *SignalIngestHandler {
+	return &SignalIngestHandler{signals: signals}
+}
+
+// SignalOutcomeRequest reports whether acting on an ingested signal was profitable.
+type SignalOutcomeRequest struct {
+	Profitable *bool `json:"profitable" binding:"required"`
+}
+
+func (h *SignalIngestHandler) available(c *gin.Context) bool {
+	if h.signals == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "signal ingestion not available"})
+		return false
+	}
+	return true
+}
+
+// IngestSignal accepts a signal from an external engine. It responds 201 when
+// the signal entered the aggregator and 200 when it duplicated a recent one.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *SignalIngestHandler) IngestSignal(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var input services.InboundSignal
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "source, symbol and side are required"})
+		return
+	}
+
+	result, err := h.signals.Ingest(c.Request.Context(), input)
+	if err != nil {
+		if errors.Is(err, services.ErrInboundSignalInvalid) {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": "failed to ingest signal"})
+		return
+	}
+
+	status := http.StatusCreated
+	if result.Duplicate {
+		status = http.StatusOK
+	}
+	c.JSON(status, gin.H{"status": "success", "data": result})
+}
+
+// RecordSignalOutcome updates the submitting source's reliability.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *SignalIngestHandler) RecordSignalOutcome(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req SignalOutcomeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "profitable is required"})
+		return
+	}
+
+	stats, err := h.signals.RecordOutcome(c.Request.Context(), c.Param("id"), *req.Profitable)
+	if err != nil {
+		if errors.Is(err, services.ErrInboundSignalNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": "failed to record outcome"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": stats})
+}
+
+// ListSignalSources returns reliability statistics for every source.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *SignalIngestHandler) ListSignalSources(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	sources, err := h.signals.ListSources(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": "failed to list signal sources"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"sources": sources}})
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubSignalIngestor struct{}
+
+func (s *stubSignalIngestor) Ingest(ctx context.Context, input services.InboundSignal) (*services.InboundSignalResult, error) {
+	switch input.Symbol {
+	case "BAD":
+		return nil, services.ErrInboundSignalInvalid
+	case "DUP":
+		return &services.InboundSignalResult{SignalID: "sig-2", Duplicate: true}, nil
+	}
+	return &services.InboundSignalResult{SignalID: "sig-1", Accepted: true}, nil
+}
+
+func (s *stubSignalIngestor) RecordOutcome(ctx context.Context, signalID string, profitable bool) (*services.SignalSourceStats, error) {
+	if signalID != "sig-1" {
+		return nil, services.ErrInboundSignalNotFound
+	}
+	return &services.SignalSourceStats{Source: "quant-a", Wins: 1}, nil
+}
+
+func (s *stubSignalIngestor) ListSources(ctx context.Context) ([]services.SignalSourceStats, error) {
+	return []services.SignalSourceStats{{Source: "quant-a"}}, nil
+}
+
+func TestSignalIngestHandler_IngestSignal(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewSignalIngestHandler(&stubSignalIngestor{})
+
+	w := performTradingModeRequest(handler.IngestSignal, `{"source":"quant-a","symbol":"BTC/USDT","side":"buy","confidence":0.8}`, nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), "sig-1")
+
+	w = performTradingModeRequest(handler.IngestSignal, `{"source":"quant-a","symbol":"DUP","side":"buy","confidence":0.8}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = performTradingModeRequest(handler.IngestSignal, `{"source":"quant-a","symbol":"BAD","side":"buy","confidence":0.8}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(handler.IngestSignal, `{"symbol":"BTC/USDT"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSignalIngestHandler_RecordSignalOutcome(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewSignalIngestHandler(&stubSignalIngestor{})
+
+	w := performTradingModeRequest(handler.RecordSignalOutcome, `{"profitable":false}`, gin.Params{{Key: "id", Value: "sig-1"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = performTradingModeRequest(handler.RecordSignalOutcome, `{"profitable":true}`, gin.Params{{Key: "id", Value: "sig-9"}})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = performTradingModeRequest(handler.RecordSignalOutcome, `{}`, gin.Params{{Key: "id", Value: "sig-1"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSignalIngestHandler_Unavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewSignalIngestHandler(nil)
+
+	w := performTradingModeRequest(handler.ListSignalSources, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// StablecoinStatusProvider defines the stablecoin peg monitoring operations.
+type StablecoinStatusProvider interface {
+	Statuses(ctx context.Context) ([]services.StablecoinStatus, error)
+	Check(ctx context.Context) ([]services.StablecoinStatus, error)
+}
+
+// StablecoinHandler exposes the stablecoin peg status.
+type StablecoinHandler struct {
+	monitor StablecoinStatusProvider
+}
+
+// NewStablecoinHandler creates a new stablecoin handler.
+//
+// Parameters:
+//
+//	monitor: The depeg monitor (may be nil when Redis is unavailable).
+//
+// Returns:
+//
+//	*StablecoinHandler: The initialized handler.
+func NewStablecoinHandler(monitor StablecoinStatusProvider) *StablecoinHandler {
+	return &StablecoinHandler{monitor: monitor}
+}
+
+// GetStatus returns the latest peg status of every monitored stablecoin.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *StablecoinHandler) GetStatus(c *gin.Context) {
+	h.respond(c, h.statuses)
+}
+
+// CheckNow prices the stablecoins now and returns the new status.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *StablecoinHandler) CheckNow(c *gin.Context) {
+	h.respond(c, h.check)
+}
+
+func (h *StablecoinHandler) statuses(ctx context.Context) ([]services.StablecoinStatus, error) {
+	return h.monitor.Statuses(ctx)
+}
+
+func (h *StablecoinHandler) check(ctx context.Context) ([]services.StablecoinStatus, error) {
+	return h.monitor.Check(ctx)
+}
+
+func (h *StablecoinHandler) respond(c *gin.Context, load func(context.Context) ([]services.StablecoinStatus, error)) {
+	if h.monitor == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "stablecoin monitor not available"})
+		return
+	}
+	statuses, err := load(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	depegged := []string{}
+	for _, status := range statuses {
+		if status.Depegged {
+			depegged = append(depegged, status.Stablecoin)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{
+		"stablecoins": statuses,
+		"depegged":    depegged,
+	}})
+}
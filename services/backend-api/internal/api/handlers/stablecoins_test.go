@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubStablecoinMonitor struct {
+	statuses []services.StablecoinStatus
+	err      error
+}
+
+func (s *stubStablecoinMonitor) Statuses(context.Context) ([]services.StablecoinStatus, error) {
+	return s.statuses, s.err
+}
+
+func (s *stubStablecoinMonitor) Check(context.Context) ([]services.StablecoinStatus, error) {
+	return s.statuses, s.err
+}
+
+func TestStablecoinHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	monitor := &stubStablecoinMonitor{statuses: []services.StablecoinStatus{
+		{Stablecoin: "USDC", Price: 0.93, Deviation: 0.07, Depegged: true},
+		{Stablecoin: "USDT", Price: 1, Deviation: 0},
+	}}
+	handler := NewStablecoinHandler(monitor)
+
+	w := performTradingModeRequest(handler.GetStatus, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"depegged":["USDC"]`)
+
+	w = performTradingModeRequest(handler.CheckNow, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	monitor.err = errors.New("redis down")
+	w = performTradingModeRequest(handler.CheckNow, "", nil)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	w = performTradingModeRequest(NewStablecoinHandler(nil).GetStatus, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// StateDiffer compares the state fingerprints recorded with decisions.
+type StateDiffer interface {
+	Diff(ctx context.Context, from time.Time, to *time.Time) (*services.StateDiff, error)
+}
+
+// StateDiffHandler serves the config and strategy state behind decisions.
+type StateDiffHandler struct {
+	differ StateDiffer
+}
+
+// NewStateDiffHandler creates a new state diff handler.
+//
+// Parameters:
+//
+//	differ: The state fingerprint service (may be nil without a database).
+//
+// Returns:
+//
+//	*StateDiffHandler: The initialized handler.
+func NewStateDiffHandler(differ StateDiffer) *StateDiffHandler {
+	return &StateDiffHandler{differ: differ}
+}
+
+// GetDiff compares the state behind the last decision at or before "from"
+// with the one at or before "to", or with the live state when "to" is not
+// given. Both are RFC3339 timestamps.
+//
+// Parameters:
+//
+//	c: Gin context with from and to queries.
+func (h *StateDiffHandler) GetDiff(c *gin.Context) {
+	if h.differ == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "state fingerprints not available"})
+		return
+	}
+	from, err := time.Parse(time.RFC3339, c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "from must be an RFC3339 timestamp"})
+		return
+	}
+	var to *time.Time
+	if raw := c.Query("to"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "to must be an RFC3339 timestamp"})
+			return
+		}
+		if parsed.Before(from) {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "to must not be before from"})
+			return
+		}
+		to = &parsed
+	}
+
+	diff, err := h.differ.Diff(c.Request.Context(), from, to)
+	if errors.Is(err, services.ErrStateFingerprintNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": diff})
+}
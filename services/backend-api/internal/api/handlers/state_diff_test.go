@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubStateDiffer struct {
+	from time.Time
+	to   *time.Time
+	err  error
+}
+
+func (s *stubStateDiffer) Diff(_ context.Context, from time.Time, to *time.Time) (*services.StateDiff, error) {
+	s.from, s.to = from, to
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &services.StateDiff{
+		From:    services.StateFingerprint{DecisionID: "dec_1", PromptVersion: "v1", At: from},
+		To:      services.StateFingerprint{PromptVersion: "v2"},
+		Changed: true,
+		Changes: []services.StateChange{{Kind: services.StateKindPrompt, From: "v1", To: "v2"}},
+	}, nil
+}
+
+func performStateDiff(handler *StateDiffHandler, query string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/admin/state/diff?"+query, nil)
+	handler.GetDiff(c)
+	return w
+}
+
+func TestStateDiffHandler_GetDiff(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	differ := &stubStateDiffer{}
+	handler := NewStateDiffHandler(differ)
+
+	w := performStateDiff(handler, "from=2026-05-01T12:00:00Z")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"kind":"prompt_version"`)
+	assert.Equal(t, time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC), differ.from)
+	assert.Nil(t, differ.to, "without to the live state is compared")
+
+	w = performStateDiff(handler, "from=2026-05-01T12:00:00Z&to=2026-05-02T12:00:00Z")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, time.Date(2026, 5, 2, 12, 0, 0, 0, time.UTC), *differ.to)
+
+	for _, query := range []string{
+		"",
+		"from=yesterday",
+		"from=2026-05-01T12:00:00Z&to=today",
+		"from=2026-05-02T12:00:00Z&to=2026-05-01T12:00:00Z",
+	} {
+		assert.Equal(t, http.StatusBadRequest, performStateDiff(handler, query).Code, query)
+	}
+
+	differ.err = fmt.Errorf("%w at 2026-05-01T12:00:00Z", services.ErrStateFingerprintNotFound)
+	assert.Equal(t, http.StatusNotFound, performStateDiff(handler, "from=2026-05-01T12:00:00Z").Code)
+	differ.err = errors.New("database down")
+	assert.Equal(t, http.StatusInternalServerError, performStateDiff(handler, "from=2026-05-01T12:00:00Z").Code)
+	assert.Equal(t, http.StatusServiceUnavailable, performStateDiff(NewStateDiffHandler(nil), "").Code)
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// StrategyConfigManager defines the operations on shareable strategy
+// configurations.
+type StrategyConfigManager interface {
+	Validate(document string) *services.StrategyValidation
+	Install(ctx context.Context, chatID, document string) (*services.InstalledStrategy, *services.StrategyValidation, error)
+	List(ctx context.Context, chatID string) ([]services.InstalledStrategy, error)
+	Export(ctx context.Context, chatID, name, format string) (*services.StrategyExport, error)
+	Uninstall(ctx context.Context, chatID, name string) error
+}
+
+// StrategyConfigHandler validates, installs and exports shareable strategy
+// configurations.
+type StrategyConfigHandler struct {
+	strategies StrategyConfigManager
+}
+
+// ValidateStrategyRequest carries a strategy document to check.
+type ValidateStrategyRequest struct {
+	// Document is the YAML or JSON strategy configuration.
+	Document string `json:"document" binding:"required"`
+}
+
+// InstallStrategyRequest installs a strategy document for a chat.
+type InstallStrategyRequest struct {
+	ChatID string `json:"chat_id" binding:"required"`
+	// Document is the YAML or JSON strategy configuration.
+	Document string `json:"document" binding:"required"`
+}
+
+// InstallStrategyResponse is an installed configuration with the warnings
+// raised while validating it.
+type InstallStrategyResponse struct {
+	Strategy services.InstalledStrategy `json:"strategy"`
+	Warnings []string                   `json:"warnings,omitempty"`
+}
+
+// StrategyListResponse lists the configurations installed for a chat.
+type StrategyListResponse struct {
+	Strategies []services.InstalledStrategy `json:"strategies"`
+}
+
+// StrategyChangeResponse acknowledges an uninstall.
+type StrategyChangeResponse struct {
+	Name    string `json:"name"`
+	Deleted bool   `json:"deleted"`
+}
+
+// NewStrategyConfigHandler creates a new strategy configuration handler.
+//
+// Parameters:
+//
+//	strategies: The strategy configuration service (may be nil when Redis is unavailable).
+//
+// Returns:
+//
+//	*StrategyConfigHandler: The initialized handler.
+func NewStrategyConfigHandler(strategies StrategyConfigManager) *StrategyConfigHandler {
+	return &StrategyConfigHandler{strategies: strategies}
+}
+
+func (h *StrategyConfigHandler) available(c *gin.Context) bool {
+	if h.strategies == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "strategy configurations not available"})
+		return false
+	}
+	return true
+}
+
+// Schema returns the JSON Schema of the strategy configuration format. The
+// schema is served bare so editors and validators can load it directly.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *StrategyConfigHandler) Schema(c *gin.Context) {
+	c.JSON(http.StatusOK, services.StrategyConfigSchema())
+}
+
+// ValidateStrategy checks a strategy document without installing it.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *StrategyConfigHandler) ValidateStrategy(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req ValidateStrategyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "document is required"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": h.strategies.Validate(req.Document)})
+}
+
+// InstallStrategy validates a strategy document and installs it for a chat.
+// Invalid documents are rejected with the validation result.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *StrategyConfigHandler) InstallStrategy(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req InstallStrategyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "chat_id and document are required"})
+		return
+	}
+	installed, validation, err := h.strategies.Install(c.Request.Context(), req.ChatID, req.Document)
+	if errors.Is(err, services.ErrStrategyConfigInvalid) {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": err.Error(), "data": validation})
+		return
+	}
+	if err != nil {
+		writeStrategyConfigError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"status": "success", "data": InstallStrategyResponse{Strategy: *installed, Warnings: validation.Warnings}})
+}
+
+// ListStrategies returns the configurations installed for a chat.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *StrategyConfigHandler) ListStrategies(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	chatID := c.Query("chat_id")
+	if chatID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "chat_id is required"})
+		return
+	}
+	strategies, err := h.strategies.List(c.Request.Context(), chatID)
+	if err != nil {
+		writeStrategyConfigError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": StrategyListResponse{Strategies: strategies}})
+}
+
+// ExportStrategy renders an installed configuration or operating profile as
+// a shareable YAML or JSON document.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *StrategyConfigHandler) ExportStrategy(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	chatID := c.Query("chat_id")
+	if chatID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "chat_id is required"})
+		return
+	}
+	exported, err := h.strategies.Export(c.Request.Context(), chatID, c.Param("name"), c.Query("format"))
+	if err != nil {
+		writeStrategyConfigError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": exported})
+}
+
+// UninstallStrategy removes an installed configuration and its profile.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *StrategyConfigHandler) UninstallStrategy(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	chatID := c.Query("chat_id")
+	if chatID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "chat_id is required"})
+		return
+	}
+	if err := h.strategies.Uninstall(c.Request.Context(), chatID, c.Param("name")); err != nil {
+		writeStrategyConfigError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": StrategyChangeResponse{Name: c.Param("name"), Deleted: true}})
+}
+
+func writeStrategyConfigError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrStrategyConfigInvalid), errors.Is(err, services.ErrProfileLimit):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrStrategyConfigNotFound):
+		status = http.StatusNotFound
+	}
+	c.JSON(status, gin.H{"status": "error", "error": err.Error()})
+}
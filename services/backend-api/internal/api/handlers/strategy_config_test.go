@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testStrategyDocument = `format: neuratrade.strategy/v1
+name: night
+strategy: scalping
+parameters:
+  timeframe: 5m
+risk:
+  daily_loss_pct: 1
+  min_confidence: 0.8
+  max_position_pct: 2
+  scan_interval_seconds: 900
+  ai_usage: low
+`
+
+func newTestStrategyConfigHandler(t *testing.T) *StrategyConfigHandler {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewStrategyConfigHandler(services.NewStrategyConfigService(client, services.NewOperatingProfileService(client)))
+}
+
+func strategyRequestBody(t *testing.T, fields map[string]string) string {
+	body, err := json.Marshal(fields)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestStrategyConfigHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := newTestStrategyConfigHandler(t)
+
+	w := performTradingModeRequest(handler.ValidateStrategy, strategyRequestBody(t, map[string]string{"document": testStrategyDocument}), nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"valid":true`)
+
+	w = performTradingModeRequest(handler.InstallStrategy, strategyRequestBody(t, map[string]string{"chat_id": "42", "document": testStrategyDocument}), nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"night"`)
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/telegram/internal/strategies?chat_id=42", nil)
+	handler.ListStrategies(c)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"scan_interval_seconds":900`)
+
+	rec = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/telegram/internal/strategies/night/export?chat_id=42&format=json", nil)
+	c.Params = gin.Params{{Key: "name", Value: "night"}}
+	handler.ExportStrategy(c)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"format":"json"`)
+
+	for _, want := range []int{http.StatusOK, http.StatusNotFound} {
+		rec = httptest.NewRecorder()
+		c, _ = gin.CreateTestContext(rec)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/api/v1/telegram/internal/strategies/night?chat_id=42", nil)
+		c.Params = gin.Params{{Key: "name", Value: "night"}}
+		handler.UninstallStrategy(c)
+		assert.Equal(t, want, rec.Code)
+	}
+}
+
+func TestStrategyConfigHandler_RejectsUnsafeDocuments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := newTestStrategyConfigHandler(t)
+
+	document := strings.Replace(testStrategyDocument, "timeframe: 5m", "api_secret: hunter2", 1)
+	w := performTradingModeRequest(handler.ValidateStrategy, strategyRequestBody(t, map[string]string{"document": document}), nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"valid":false`)
+
+	w = performTradingModeRequest(handler.InstallStrategy, strategyRequestBody(t, map[string]string{"chat_id": "42", "document": document}), nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "looks like a credential")
+
+	w = performTradingModeRequest(handler.InstallStrategy, `{"chat_id":"42"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	unavailable := NewStrategyConfigHandler(nil)
+	w = performTradingModeRequest(unavailable.ValidateStrategy, strategyRequestBody(t, map[string]string{"document": testStrategyDocument}), nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/shopspring/decimal"
+)
+
+// SymbolCooldownManager defines the per-symbol cooldown operations.
+type SymbolCooldownManager interface {
+	Active(ctx context.Context) ([]services.SymbolCooldown, error)
+	RecordExit(ctx context.Context, exchange, symbol string, pnl decimal.Decimal, reason string) (*services.SymbolCooldown, error)
+	Clear(ctx context.Context, exchange, symbol string) error
+	Config() services.SymbolCooldownConfig
+}
+
+// SymbolCooldownHandler exposes the symbols blocked from re-entry after an exit.
+type SymbolCooldownHandler struct {
+	cooldowns SymbolCooldownManager
+}
+
+// UpdateSymbolCooldownRequest records an exit or clears a symbol's cooldown.
+type UpdateSymbolCooldownRequest struct {
+	// Action is record or clear.
+	Action   string `json:"action" binding:"required"`
+	Exchange string `json:"exchange"`
+	Symbol   string `json:"symbol" binding:"required"`
+	PnL      string `json:"pnl"`
+	// Reason is stop_out, loss, exit or liquidation; empty derives it from pnl.
+	Reason string `json:"reason"`
+}
+
+// NewSymbolCooldownHandler creates a new symbol cooldown handler.
+//
+// Parameters:
+//
+//	cooldowns: The cooldown tracker (may be nil when Redis is unavailable).
+//
+// Returns:
+//
+//	*SymbolCooldownHandler: The initialized handler.
+func NewSymbolCooldownHandler(cooldowns SymbolCooldownManager) *SymbolCooldownHandler {
+	return &SymbolCooldownHandler{cooldowns: cooldowns}
+}
+
+// GetCooldowns returns the active cooldowns and the configured durations.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *SymbolCooldownHandler) GetCooldowns(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	h.respond(c)
+}
+
+// UpdateCooldowns records a symbol's exit or lifts its cooldown.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *SymbolCooldownHandler) UpdateCooldowns(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req UpdateSymbolCooldownRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "Invalid request body"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	switch req.Action {
+	case "record":
+		pnl := decimal.Zero
+		if req.PnL != "" {
+			parsed, err := decimal.NewFromString(req.PnL)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "pnl must be numeric"})
+				return
+			}
+			pnl = parsed
+		}
+		if _, err := h.cooldowns.RecordExit(ctx, req.Exchange, req.Symbol, pnl, req.Reason); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+			return
+		}
+	case "clear":
+		if err := h.cooldowns.Clear(ctx, req.Exchange, req.Symbol); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+			return
+		}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "action must be record or clear"})
+		return
+	}
+	h.respond(c)
+}
+
+func (h *SymbolCooldownHandler) available(c *gin.Context) bool {
+	if h.cooldowns == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "symbol cooldowns not available"})
+		return false
+	}
+	return true
+}
+
+func (h *SymbolCooldownHandler) respond(c *gin.Context) {
+	active, err := h.cooldowns.Active(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	config := h.cooldowns.Config()
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{
+		"cooldowns":  active,
+		"after_loss": config.AfterLoss.String(),
+		"after_exit": config.AfterExit.String(),
+	}})
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubSymbolCooldowns struct {
+	active  []services.SymbolCooldown
+	cleared []string
+}
+
+func (s *stubSymbolCooldowns) Active(context.Context) ([]services.SymbolCooldown, error) {
+	return s.active, nil
+}
+
+func (s *stubSymbolCooldowns) RecordExit(_ context.Context, exchange, symbol string, pnl decimal.Decimal, reason string) (*services.SymbolCooldown, error) {
+	cooldown := services.SymbolCooldown{Exchange: exchange, Symbol: symbol, PnL: pnl, Reason: reason}
+	s.active = append(s.active, cooldown)
+	return &cooldown, nil
+}
+
+func (s *stubSymbolCooldowns) Clear(_ context.Context, _, symbol string) error {
+	s.cleared = append(s.cleared, symbol)
+	return nil
+}
+
+func (s *stubSymbolCooldowns) Config() services.SymbolCooldownConfig {
+	return services.SymbolCooldownConfig{AfterLoss: 30 * time.Minute}
+}
+
+func TestSymbolCooldownHandler(t *testing.T) {
+	cooldowns := &stubSymbolCooldowns{}
+	handler := NewSymbolCooldownHandler(cooldowns)
+
+	w := performTradingModeRequest(handler.GetCooldowns, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"after_loss":"30m0s"`)
+	assert.Contains(t, w.Body.String(), `"after_exit":"0s"`)
+
+	w = performTradingModeRequest(handler.UpdateCooldowns, `{"action":"record","exchange":"binance","symbol":"SOL/USDT","pnl":"-12.5","reason":"stop_out"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"symbol":"SOL/USDT"`)
+	assert.Equal(t, "-12.5", cooldowns.active[0].PnL.String())
+
+	w = performTradingModeRequest(handler.UpdateCooldowns, `{"action":"record","symbol":"SOL/USDT","pnl":"lots"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(handler.UpdateCooldowns, `{"action":"clear","symbol":"SOL/USDT"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"SOL/USDT"}, cooldowns.cleared)
+
+	w = performTradingModeRequest(handler.UpdateCooldowns, `{"action":"pause","symbol":"SOL/USDT"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(NewSymbolCooldownHandler(nil).GetCooldowns, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
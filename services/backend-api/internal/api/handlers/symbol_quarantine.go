@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// SymbolQuarantineManager defines the exchange/symbol quarantine operations.
+type SymbolQuarantineManager interface {
+	List() ([]services.QuarantinedPair, error)
+	Quarantine(ctx context.Context, exchange, symbol, reason string, ttl time.Duration)
+	Release(exchange, symbol string) bool
+}
+
+// SymbolQuarantineHandler exposes the blacklisted exchange/symbol pairs and
+// lets operators quarantine or release them manually.
+type SymbolQuarantineHandler struct {
+	quarantine SymbolQuarantineManager
+}
+
+// UpdateSymbolQuarantineRequest quarantines or releases one pair.
+type UpdateSymbolQuarantineRequest struct {
+	// Action is quarantine or release.
+	Action   string `json:"action" binding:"required"`
+	Exchange string `json:"exchange" binding:"required"`
+	Symbol   string `json:"symbol" binding:"required"`
+	Reason   string `json:"reason"`
+	// TTL is a Go duration such as "2h"; empty uses the configured TTL.
+	TTL string `json:"ttl"`
+}
+
+// NewSymbolQuarantineHandler creates a new symbol quarantine handler.
+//
+// Parameters:
+//
+//	quarantine: The quarantine (may be nil when collection is disabled).
+//
+// Returns:
+//
+//	*SymbolQuarantineHandler: The initialized handler.
+func NewSymbolQuarantineHandler(quarantine SymbolQuarantineManager) *SymbolQuarantineHandler {
+	return &SymbolQuarantineHandler{quarantine: quarantine}
+}
+
+// GetQuarantine returns every blacklisted exchange/symbol pair.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *SymbolQuarantineHandler) GetQuarantine(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	h.respond(c)
+}
+
+// UpdateQuarantine quarantines a pair or releases it early.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *SymbolQuarantineHandler) UpdateQuarantine(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req UpdateSymbolQuarantineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "Invalid request body"})
+		return
+	}
+
+	switch req.Action {
+	case "quarantine":
+		var ttl time.Duration
+		if req.TTL != "" {
+			parsed, err := time.ParseDuration(req.TTL)
+			if err != nil || parsed <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "ttl must be a positive duration"})
+				return
+			}
+			ttl = parsed
+		}
+		h.quarantine.Quarantine(c.Request.Context(), req.Exchange, req.Symbol, req.Reason, ttl)
+	case "release":
+		if !h.quarantine.Release(req.Exchange, req.Symbol) {
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "error": "pair is not quarantined"})
+			return
+		}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "action must be quarantine or release"})
+		return
+	}
+	h.respond(c)
+}
+
+func (h *SymbolQuarantineHandler) available(c *gin.Context) bool {
+	if h.quarantine == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "symbol quarantine not available"})
+		return false
+	}
+	return true
+}
+
+func (h *SymbolQuarantineHandler) respond(c *gin.Context) {
+	pairs, err := h.quarantine.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"pairs": pairs}})
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubSymbolQuarantine struct {
+	pairs []services.QuarantinedPair
+	ttl   time.Duration
+}
+
+func (s *stubSymbolQuarantine) List() ([]services.QuarantinedPair, error) {
+	return s.pairs, nil
+}
+
+func (s *stubSymbolQuarantine) Quarantine(_ context.Context, exchange, symbol, reason string, ttl time.Duration) {
+	s.ttl = ttl
+	s.pairs = append(s.pairs, services.QuarantinedPair{Exchange: exchange, Symbol: symbol, Reason: reason})
+}
+
+func (s *stubSymbolQuarantine) Release(exchange, symbol string) bool {
+	for i, pair := range s.pairs {
+		if pair.Exchange == exchange && pair.Symbol == symbol {
+			s.pairs = append(s.pairs[:i], s.pairs[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func performQuarantineUpdate(handler *SymbolQuarantineHandler, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/admin/quarantine", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	handler.UpdateQuarantine(c)
+	return w
+}
+
+func TestSymbolQuarantineHandler_UpdateQuarantine(t *testing.T) {
+	stub := &stubSymbolQuarantine{}
+	handler := NewSymbolQuarantineHandler(stub)
+
+	w := performQuarantineUpdate(handler, `{"action":"quarantine","exchange":"binance","symbol":"BTC/USDT","reason":"delisting","ttl":"2h"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 2*time.Hour, stub.ttl)
+	assert.Contains(t, w.Body.String(), `"reason":"delisting"`)
+
+	w = performQuarantineUpdate(handler, `{"action":"release","exchange":"binance","symbol":"BTC/USDT"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, stub.pairs)
+	assert.Equal(t, http.StatusNotFound, performQuarantineUpdate(handler, `{"action":"release","exchange":"binance","symbol":"BTC/USDT"}`).Code)
+
+	for _, body := range []string{
+		`{"action":"quarantine","exchange":"binance","symbol":"BTC/USDT","ttl":"soon"}`,
+		`{"action":"block","exchange":"binance","symbol":"BTC/USDT"}`,
+		`{"action":"quarantine","symbol":"BTC/USDT"}`,
+	} {
+		assert.Equal(t, http.StatusBadRequest, performQuarantineUpdate(handler, body).Code, body)
+	}
+}
+
+func TestSymbolQuarantineHandler_Unavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/admin/quarantine", nil)
+	NewSymbolQuarantineHandler(nil).GetQuarantine(c)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -23,6 +24,16 @@ type TelegramInternalHandler struct {
 	questEngine *services.QuestEngine
 	schemaOnce  sync.Once
 	schemaErr   error
+	// loadShedding reports shedding under CPU/memory pressure in /doctor
+	loadShedding LoadSheddingReporter
+	// readiness scores the /begin gate and the /doctor readiness check
+	readiness *services.ReadinessScorer
+	// profiles selects the operating profile passed to /begin
+	profiles OperatingProfileManager
+	// freshness lists the pairs breaching their freshness SLA in /doctor
+	freshness services.StaleSymbolSource
+	// state reports what changed since the last decision in /doctor
+	state StateDiffer
 }
 
 // NewTelegramInternalHandler creates a new instance of TelegramInternalHandler.
@@ -31,9 +42,39 @@ func NewTelegramInternalHandler(db any, userHandler *UserHandler, questEngine *s
 		db:          normalizeDBPool(db),
 		userHandler: userHandler,
 		questEngine: questEngine,
+		readiness:   services.NewReadinessScorer(services.ReadinessConfig{}),
 	}
 }
 
+// SetLoadShedding reports the load shedding level in /doctor.
+func (h *TelegramInternalHandler) SetLoadShedding(reporter LoadSheddingReporter) {
+	h.loadShedding = reporter
+}
+
+// SetFreshness lists the pairs breaching their freshness SLA in /doctor.
+func (h *TelegramInternalHandler) SetFreshness(freshness services.StaleSymbolSource) {
+	h.freshness = freshness
+}
+
+// SetStateDiff reports the config, strategy, prompt and model changes since
+// the last decision in /doctor.
+func (h *TelegramInternalHandler) SetStateDiff(state StateDiffer) {
+	h.state = state
+}
+
+// SetReadinessScorer replaces the default scorer, which only checks the
+// connected wallets and exchanges, with one that has market, risk and AI
+// budget sources.
+func (h *TelegramInternalHandler) SetReadinessScorer(scorer *services.ReadinessScorer) {
+	h.readiness = scorer
+}
+
+// SetOperatingProfiles lets /begin select the operating profile the chat
+// trades with.
+func (h *TelegramInternalHandler) SetOperatingProfiles(profiles OperatingProfileManager) {
+	h.profiles = profiles
+}
+
 // GetUserByChatID retrieves a user by their Telegram chat ID.
 func (h *TelegramInternalHandler) GetUserByChatID(c *gin.Context) {
 	chatID := c.Param("id")
@@ -188,8 +229,27 @@ func (h *TelegramInternalHandler) SetNotificationPreferences(c *gin.Context) {
 	})
 }
 
-type autonomousStateRequest struct {
+// AutonomousStateRequest is the body for starting or pausing autonomous mode.
+type AutonomousStateRequest struct {
 	ChatID string `json:"chat_id" binding:"required"`
+	// Profile optionally selects the operating profile when starting; the
+	// chat keeps its current profile when empty.
+	Profile string `json:"profile,omitempty"`
+}
+
+// AutonomousStateResponse is returned when autonomous mode starts, pauses or is blocked.
+type AutonomousStateResponse struct {
+	OK              bool     `json:"ok"`
+	Status          string   `json:"status"`
+	Mode            string   `json:"mode,omitempty"`
+	ReadinessPassed *bool    `json:"readiness_passed,omitempty"`
+	FailedChecks    []string `json:"failed_checks,omitempty"`
+	Message         string   `json:"message"`
+	// Readiness is the weighted score behind the gate, with the top
+	// blockers and what to do about each.
+	Readiness *services.ReadinessReport `json:"readiness,omitempty"`
+	// Profile is the operating profile selected by this request.
+	Profile string `json:"profile,omitempty"`
 }
 
 type connectExchangeRequest struct {
@@ -223,7 +283,7 @@ type walletRecordInput struct {
 }
 
 func (h *TelegramInternalHandler) BeginAutonomous(c *gin.Context) {
-	var req autonomousStateRequest
+	var req AutonomousStateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 		return
@@ -235,25 +295,49 @@ func (h *TelegramInternalHandler) BeginAutonomous(c *gin.Context) {
 		return
 	}
 
+	profile := strings.TrimSpace(req.Profile)
+	if profile != "" {
+		if h.profiles == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "operating profiles not available"})
+			return
+		}
+		if _, err := h.profiles.Resolve(c.Request.Context(), chatID, profile); err != nil {
+			writeProfileError(c, err)
+			return
+		}
+	}
+
+	if h.questEngine != nil && h.questEngine.HoldReason() != "" {
+		c.JSON(http.StatusOK, AutonomousStateResponse{
+			OK:      false,
+			Status:  "held",
+			Mode:    "autonomous",
+			Message: "Autonomous trading is held after an unclean shutdown. Review the restart summary, then send /resume.",
+		})
+		return
+	}
+
 	if err := h.ensureOperatorSchema(c.Request.Context()); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to initialize operator state"})
 		return
 	}
 
-	failedChecks, err := h.collectReadinessFailures(c.Request.Context(), chatID)
+	readiness, failedChecks, err := h.evaluateReadiness(c.Request.Context(), chatID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to evaluate readiness"})
 		return
 	}
 
-	if len(failedChecks) > 0 {
-		c.JSON(http.StatusOK, gin.H{
-			"ok":               false,
-			"status":           "blocked",
-			"mode":             "autonomous",
-			"readiness_passed": false,
-			"failed_checks":    failedChecks,
-			"message":          "Readiness gate blocked autonomous mode",
+	if !readiness.Go {
+		passed := false
+		c.JSON(http.StatusOK, AutonomousStateResponse{
+			OK:              false,
+			Status:          "blocked",
+			Mode:            "autonomous",
+			ReadinessPassed: &passed,
+			FailedChecks:    failedChecks,
+			Message:         "Readiness gate blocked autonomous mode",
+			Readiness:       readiness,
 		})
 		return
 	}
@@ -273,6 +357,16 @@ func (h *TelegramInternalHandler) BeginAutonomous(c *gin.Context) {
 		return
 	}
 
+	// Select the profile before quests start so the first cycle uses it
+	if profile != "" {
+		selected, err := h.profiles.Select(c.Request.Context(), chatID, profile)
+		if err != nil {
+			writeProfileError(c, err)
+			return
+		}
+		profile = selected.Name
+	}
+
 	// Start quest engine for this user
 	if h.questEngine != nil {
 		_, err := h.questEngine.BeginAutonomous(chatID)
@@ -282,17 +376,81 @@ func (h *TelegramInternalHandler) BeginAutonomous(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"ok":               true,
-		"status":           "active",
-		"mode":             "autonomous",
-		"readiness_passed": true,
-		"message":          "Autonomous mode started",
+	passed := true
+	c.JSON(http.StatusOK, AutonomousStateResponse{
+		OK:              true,
+		Status:          "active",
+		Mode:            "autonomous",
+		ReadinessPassed: &passed,
+		Message:         "Autonomous mode started",
+		Readiness:       readiness,
+		Profile:         profile,
+	})
+}
+
+// ResumeAutonomous releases the hold placed on autonomous trading after an
+// unclean shutdown, once the readiness checks pass again.
+func (h *TelegramInternalHandler) ResumeAutonomous(c *gin.Context) {
+	var req AutonomousStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	chatID := strings.TrimSpace(req.ChatID)
+	if chatID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "chat_id is required"})
+		return
+	}
+
+	if h.questEngine == nil || h.questEngine.HoldReason() == "" {
+		c.JSON(http.StatusOK, AutonomousStateResponse{
+			OK:      true,
+			Status:  "not_held",
+			Message: "Autonomous trading is not held. Use /begin to start autonomous mode.",
+		})
+		return
+	}
+
+	if err := h.ensureOperatorSchema(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to initialize operator state"})
+		return
+	}
+
+	readiness, failedChecks, err := h.evaluateReadiness(c.Request.Context(), chatID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to evaluate readiness"})
+		return
+	}
+
+	if !readiness.Go {
+		passed := false
+		c.JSON(http.StatusOK, AutonomousStateResponse{
+			OK:              false,
+			Status:          "held",
+			Mode:            "autonomous",
+			ReadinessPassed: &passed,
+			FailedChecks:    failedChecks,
+			Message:         "Readiness gate kept autonomous trading held",
+			Readiness:       readiness,
+		})
+		return
+	}
+
+	h.questEngine.Release()
+	passed := true
+	c.JSON(http.StatusOK, AutonomousStateResponse{
+		OK:              true,
+		Status:          "active",
+		Mode:            "autonomous",
+		ReadinessPassed: &passed,
+		Message:         "Autonomous trading resumed",
+		Readiness:       readiness,
 	})
 }
 
 func (h *TelegramInternalHandler) PauseAutonomous(c *gin.Context) {
-	var req autonomousStateRequest
+	var req AutonomousStateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 		return
@@ -324,10 +482,10 @@ func (h *TelegramInternalHandler) PauseAutonomous(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"ok":      true,
-		"status":  "paused",
-		"message": "Autonomous mode paused",
+	c.JSON(http.StatusOK, AutonomousStateResponse{
+		OK:      true,
+		Status:  "paused",
+		Message: "Autonomous mode paused",
 	})
 }
 
@@ -684,6 +842,117 @@ func (h *TelegramInternalHandler) GetDoctor(c *gin.Context) {
 		})
 	}
 
+	if h.loadShedding != nil {
+		shedding := h.loadShedding.LoadShedding()
+		details := gin.H{
+			"cpu_percent":    fmt.Sprintf("%.1f", shedding.CPUPercent),
+			"memory_percent": fmt.Sprintf("%.1f", shedding.MemoryPercent),
+		}
+		if shedding.Level == services.LoadSheddingNone {
+			checks = append(checks, gin.H{
+				"name":    "load-shedding",
+				"status":  "healthy",
+				"details": details,
+			})
+		} else {
+			if overall != "critical" {
+				overall = "warning"
+			}
+			checks = append(checks, gin.H{
+				"name":    "load-shedding",
+				"status":  "warning",
+				"message": fmt.Sprintf("%s load shedding: %s", shedding.Level, strings.Join(shedding.Actions, "; ")),
+				"details": details,
+			})
+		}
+	}
+
+	var staleSymbols []services.SymbolFreshness
+	if h.freshness != nil {
+		var err error
+		staleSymbols, err = h.freshness.StaleSymbols(c.Request.Context())
+		switch {
+		case err != nil:
+			if overall != "critical" {
+				overall = "warning"
+			}
+			checks = append(checks, gin.H{
+				"name":    "data-freshness",
+				"status":  "warning",
+				"message": "unable to determine data freshness",
+			})
+		case len(staleSymbols) > 0:
+			if overall != "critical" {
+				overall = "warning"
+			}
+			checks = append(checks, gin.H{
+				"name":    "data-freshness",
+				"status":  "warning",
+				"message": fmt.Sprintf("%d pair(s) breach their freshness SLA and are skipped by signals", len(staleSymbols)),
+				"details": gin.H{"count": fmt.Sprintf("%d", len(staleSymbols))},
+			})
+		default:
+			checks = append(checks, gin.H{
+				"name":   "data-freshness",
+				"status": "healthy",
+			})
+		}
+	}
+
+	var stateDiff *services.StateDiff
+	if h.state != nil {
+		var err error
+		stateDiff, err = h.state.Diff(c.Request.Context(), time.Now().UTC(), nil)
+		switch {
+		case errors.Is(err, services.ErrStateFingerprintNotFound):
+			checks = append(checks, gin.H{
+				"name":    "state",
+				"status":  "healthy",
+				"message": "no decision recorded yet",
+			})
+		case err != nil:
+			if overall != "critical" {
+				overall = "warning"
+			}
+			checks = append(checks, gin.H{
+				"name":    "state",
+				"status":  "warning",
+				"message": "unable to compare state with the last decision",
+			})
+		case stateDiff.Changed:
+			checks = append(checks, gin.H{
+				"name":    "state",
+				"status":  "healthy",
+				"message": fmt.Sprintf("%d change(s) since the last decision at %s", len(stateDiff.Changes), stateDiff.From.At.UTC().Format(time.RFC3339)),
+				"details": gin.H{"changes": fmt.Sprintf("%d", len(stateDiff.Changes))},
+			})
+		default:
+			checks = append(checks, gin.H{
+				"name":    "state",
+				"status":  "healthy",
+				"message": "unchanged since the last decision",
+			})
+		}
+	}
+
+	readiness, _ := h.scoreReadiness(c.Request.Context(), chatID, readinessFailures(polymarketCount, exchangeCount))
+	readinessCheck := gin.H{
+		"name":    "readiness",
+		"status":  "healthy",
+		"message": readiness.Explanation,
+		"details": gin.H{
+			"score":     fmt.Sprintf("%d", readiness.Score),
+			"min_score": fmt.Sprintf("%d", readiness.MinScore),
+		},
+	}
+	if !readiness.Go || len(readiness.Blockers) > 0 {
+		if overall != "critical" {
+			overall = "warning"
+		}
+		readinessCheck["status"] = "warning"
+	}
+	checks = append(checks, readinessCheck)
+
 	summary := "All checks healthy"
 	switch overall {
 	case "warning":
@@ -692,12 +961,23 @@ func (h *TelegramInternalHandler) GetDoctor(c *gin.Context) {
 		summary = "Critical checks failed"
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"overall_status": overall,
 		"summary":        summary,
 		"checked_at":     time.Now().UTC().Format(time.RFC3339),
 		"checks":         checks,
-	})
+		"readiness":      readiness,
+	}
+	if h.freshness != nil {
+		if staleSymbols == nil {
+			staleSymbols = []services.SymbolFreshness{}
+		}
+		response["stale_symbols"] = staleSymbols
+	}
+	if stateDiff != nil {
+		response["state_diff"] = stateDiff
+	}
+	c.JSON(http.StatusOK, response)
 }
 
 func (h *TelegramInternalHandler) ensureOperatorSchema(ctx context.Context) error {
@@ -734,8 +1014,6 @@ func (h *TelegramInternalHandler) ensureOperatorSchema(ctx context.Context) erro
 }
 
 func (h *TelegramInternalHandler) collectReadinessFailures(ctx context.Context, chatID string) ([]string, error) {
-	failedChecks := make([]string, 0, 2)
-
 	pmWalletCount, err := h.countConnectedWallets(ctx, chatID, "provider = 'polymarket' AND status = 'connected'")
 	if err != nil {
 		pmWalletCount = 0
@@ -750,16 +1028,50 @@ func (h *TelegramInternalHandler) collectReadinessFailures(ctx context.Context,
 		exchangeCount = 1
 	}
 
-	totalWallets := pmWalletCount + exchangeCount
-	if totalWallets < 1 {
+	return readinessFailures(pmWalletCount, exchangeCount), nil
+}
+
+// readinessRemedies tells the operator how to fix each failed wallet check.
+var readinessRemedies = map[string]string{
+	"wallet minimum":   "connect a wallet with /connect_polymarket or /add_wallet",
+	"exchange minimum": "connect an exchange with /connect_exchange",
+}
+
+func readinessFailures(pmWalletCount, exchangeCount int) []string {
+	failedChecks := make([]string, 0, 2)
+	if pmWalletCount+exchangeCount < 1 {
 		failedChecks = append(failedChecks, "wallet minimum")
 	}
-
 	if exchangeCount < 1 {
 		failedChecks = append(failedChecks, "exchange minimum")
 	}
+	return failedChecks
+}
 
-	return failedChecks, nil
+// scoreReadiness weighs the failed wallet checks together with market,
+// risk and AI budget readiness. The returned failed checks add the names
+// of the other blocking components to the wallet checks.
+func (h *TelegramInternalHandler) scoreReadiness(ctx context.Context, chatID string, failedChecks []string) (*services.ReadinessReport, []string) {
+	issues := make([]services.ReadinessIssue, 0, len(failedChecks))
+	for _, check := range failedChecks {
+		issues = append(issues, services.ReadinessIssue{Problem: check + " not met", Remedy: readinessRemedies[check]})
+	}
+	report := h.readiness.Evaluate(ctx, chatID, issues)
+	for _, component := range report.Components {
+		if component.Status == services.ReadinessStatusBlocking && component.Name != services.ReadinessConfigValidity {
+			failedChecks = append(failedChecks, component.Name)
+		}
+	}
+	return report, failedChecks
+}
+
+func (h *TelegramInternalHandler) evaluateReadiness(ctx context.Context, chatID string) (*services.ReadinessReport, []string, error) {
+	failedChecks, err := h.collectReadinessFailures(ctx, chatID)
+	if err != nil {
+		return nil, nil, err
+	}
+	report, failedChecks := h.scoreReadiness(ctx, chatID, failedChecks)
+	return report, failedChecks, nil
 }
 
 func (h *TelegramInternalHandler) countConnectedWallets(ctx context.Context, chatID, filter string) (int, error) {
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -12,9 +13,11 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
 	"github.com/pashagolub/pgxmock/v4"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/irfndi/neuratrade/internal/services"
 )
 
 // TestTelegramInternalHandler_GetNotificationPreferences_Success tests success case
@@ -598,6 +601,286 @@ func TestTelegramInternalHandler_GetDoctor_Healthy(t *testing.T) {
 	assert.Equal(t, "healthy", response["overall_status"])
 	checks, ok := response["checks"].([]interface{})
 	assert.True(t, ok)
-	assert.Len(t, checks, 4)
+	assert.Len(t, checks, 5)
+	assert.Equal(t, "readiness", checks[4].(map[string]interface{})["name"])
+	assert.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+func TestTelegramInternalHandler_AutonomousHeldAfterUncleanShutdown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := services.NewQuestEngine(nil)
+	engine.Hold("unclean shutdown")
+	handler := NewTelegramInternalHandler(nil, nil, engine)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/telegram/internal/autonomous/begin", bytes.NewBufferString(`{"chat_id":"777"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	handler.BeginAutonomous(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp AutonomousStateResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.False(t, resp.OK)
+	assert.Equal(t, "held", resp.Status)
+	assert.Contains(t, resp.Message, "/resume")
+	assert.Equal(t, "unclean shutdown", engine.HoldReason())
+}
+
+func TestTelegramInternalHandler_ResumeAutonomous_NotHeld(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewTelegramInternalHandler(nil, nil, services.NewQuestEngine(nil))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/telegram/internal/autonomous/resume", bytes.NewBufferString(`{"chat_id":"777"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	handler.ResumeAutonomous(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp AutonomousStateResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "not_held", resp.Status)
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/telegram/internal/autonomous/resume", bytes.NewBufferString(`{}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	handler.ResumeAutonomous(c)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+type stubLoadShedding struct{ status services.LoadSheddingStatus }
+
+func (s stubLoadShedding) LoadShedding() services.LoadSheddingStatus { return s.status }
+
+func TestTelegramInternalHandler_GetDoctor_ReportsLoadShedding(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockDB, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	handler := NewTelegramInternalHandler(database.NewMockDBPool(mockDB), nil, nil)
+	handler.SetLoadShedding(stubLoadShedding{status: services.LoadSheddingStatus{
+		Enabled:    true,
+		Level:      services.LoadSheddingElevated,
+		CPUPercent: 82.5,
+		Actions:    []string{"scanning 50% of symbols", "cycle intervals x2"},
+	}})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/telegram/internal/doctor?chat_id=777", nil)
+
+	mockDB.ExpectExec("CREATE TABLE IF NOT EXISTS telegram_operator_wallets").WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+	mockDB.ExpectExec("CREATE TABLE IF NOT EXISTS telegram_operator_state").WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+	mockDB.ExpectQuery("SELECT 1").WillReturnRows(pgxmock.NewRows([]string{"one"}).AddRow(1))
+	mockDB.ExpectQuery(`SELECT COUNT\(\*\) FROM telegram_operator_wallets`).
+		WithArgs("777").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(1))
+	mockDB.ExpectQuery(`SELECT COUNT\(\*\) FROM telegram_operator_wallets`).
+		WithArgs("777").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(1))
+	mockDB.ExpectQuery(`SELECT COALESCE\(\(SELECT autonomous_enabled`).
+		WithArgs("777").
+		WillReturnRows(pgxmock.NewRows([]string{"autonomous_enabled"}).AddRow(true))
+
+	handler.GetDoctor(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"overall_status":"warning"`)
+	assert.Contains(t, w.Body.String(), "elevated load shedding: scanning 50% of symbols; cycle intervals x2")
+	assert.Contains(t, w.Body.String(), `"cpu_percent":"82.5"`)
+	assert.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+type stubStaleSymbols []services.SymbolFreshness
+
+func (s stubStaleSymbols) StaleSymbols(context.Context) ([]services.SymbolFreshness, error) {
+	return s, nil
+}
+
+func TestTelegramInternalHandler_GetDoctor_ReportsStaleSymbols(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockDB, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	handler := NewTelegramInternalHandler(database.NewMockDBPool(mockDB), nil, nil)
+	handler.SetFreshness(stubStaleSymbols{{
+		Exchange:         "binance",
+		Symbol:           "ETH/USDT",
+		TickerSLASeconds: 900,
+		Stale:            true,
+		Reason:           "ticker 20m0s old (SLA 15m0s)",
+	}})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/telegram/internal/doctor?chat_id=777", nil)
+
+	mockDB.ExpectExec("CREATE TABLE IF NOT EXISTS telegram_operator_wallets").WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+	mockDB.ExpectExec("CREATE TABLE IF NOT EXISTS telegram_operator_state").WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+	mockDB.ExpectQuery("SELECT 1").WillReturnRows(pgxmock.NewRows([]string{"one"}).AddRow(1))
+	mockDB.ExpectQuery(`SELECT COUNT\(\*\) FROM telegram_operator_wallets`).
+		WithArgs("777").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(1))
+	mockDB.ExpectQuery(`SELECT COUNT\(\*\) FROM telegram_operator_wallets`).
+		WithArgs("777").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(1))
+	mockDB.ExpectQuery(`SELECT COALESCE\(\(SELECT autonomous_enabled`).
+		WithArgs("777").
+		WillReturnRows(pgxmock.NewRows([]string{"autonomous_enabled"}).AddRow(true))
+
+	handler.GetDoctor(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		OverallStatus string                     `json:"overall_status"`
+		StaleSymbols  []services.SymbolFreshness `json:"stale_symbols"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "warning", response.OverallStatus)
+	assert.Len(t, response.StaleSymbols, 1)
+	assert.Equal(t, "ETH/USDT", response.StaleSymbols[0].Symbol)
+	assert.Contains(t, w.Body.String(), "1 pair(s) breach their freshness SLA")
+	assert.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+func TestTelegramInternalHandler_GetDoctor_ReportsStateChanges(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockDB, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	handler := NewTelegramInternalHandler(database.NewMockDBPool(mockDB), nil, nil)
+	differ := &stubStateDiffer{}
+	handler.SetStateDiff(differ)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/telegram/internal/doctor?chat_id=777", nil)
+
+	mockDB.ExpectExec("CREATE TABLE IF NOT EXISTS telegram_operator_wallets").WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+	mockDB.ExpectExec("CREATE TABLE IF NOT EXISTS telegram_operator_state").WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+	mockDB.ExpectQuery("SELECT 1").WillReturnRows(pgxmock.NewRows([]string{"one"}).AddRow(1))
+	mockDB.ExpectQuery(`SELECT COUNT\(\*\) FROM telegram_operator_wallets`).
+		WithArgs("777").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(1))
+	mockDB.ExpectQuery(`SELECT COUNT\(\*\) FROM telegram_operator_wallets`).
+		WithArgs("777").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(1))
+	mockDB.ExpectQuery(`SELECT COALESCE\(\(SELECT autonomous_enabled`).
+		WithArgs("777").
+		WillReturnRows(pgxmock.NewRows([]string{"autonomous_enabled"}).AddRow(true))
+
+	handler.GetDoctor(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		StateDiff *services.StateDiff `json:"state_diff"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Nil(t, differ.to, "the live state is compared with the last decision")
+	assert.NotNil(t, response.StateDiff)
+	assert.Equal(t, "v2", response.StateDiff.To.PromptVersion)
+	assert.Contains(t, w.Body.String(), "1 change(s) since the last decision")
+	assert.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+type haltedDailyLoss struct{}
+
+func (haltedDailyLoss) Evaluate(_ context.Context, chatID string) (*services.DailyLossState, error) {
+	return &services.DailyLossState{ChatID: chatID, Limit: decimal.NewFromInt(50), PnL: decimal.NewFromInt(-60), Halted: true}, nil
+}
+
+func TestTelegramInternalHandler_BeginAutonomous_ReadinessScore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("HOME", t.TempDir())
+	mockDB, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	scorer := services.NewReadinessScorer(services.ReadinessConfig{})
+	scorer.SetDailyLoss(haltedDailyLoss{})
+	handler := NewTelegramInternalHandler(database.NewMockDBPool(mockDB), nil, nil)
+	handler.SetReadinessScorer(scorer)
+
+	mockDB.ExpectExec("CREATE TABLE IF NOT EXISTS telegram_operator_wallets").WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+	mockDB.ExpectExec("CREATE TABLE IF NOT EXISTS telegram_operator_state").WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+	mockDB.ExpectQuery(`SELECT COUNT\(\*\) FROM telegram_operator_wallets`).
+		WithArgs("777").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(1))
+	mockDB.ExpectQuery(`SELECT COUNT\(\*\) FROM telegram_operator_wallets`).
+		WithArgs("777").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(0))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/telegram/internal/autonomous/begin", bytes.NewBufferString(`{"chat_id":"777"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	handler.BeginAutonomous(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp AutonomousStateResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "blocked", resp.Status)
+	assert.Equal(t, []string{"exchange minimum", services.ReadinessRiskBudget}, resp.FailedChecks)
+	if assert.NotNil(t, resp.Readiness) {
+		assert.False(t, resp.Readiness.Go)
+		assert.Equal(t, 0, resp.Readiness.Score)
+		assert.Contains(t, resp.Readiness.Explanation, "Fix: connect an exchange with /connect_exchange.")
+		assert.Contains(t, resp.Readiness.Explanation, "the daily loss cap of 50.00 USDT was hit")
+	}
+	assert.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+func TestTelegramInternalHandler_BeginAutonomous_SelectsProfile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("HOME", t.TempDir())
+	mockDB, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	defer mockDB.Close()
+
+	profiles := newTestOperatingProfileService(t)
+	handler := NewTelegramInternalHandler(database.NewMockDBPool(mockDB), nil, nil)
+	handler.SetOperatingProfiles(profiles)
+
+	begin := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/telegram/internal/autonomous/begin", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.BeginAutonomous(c)
+		return w
+	}
+
+	// Unknown profiles are rejected before any state changes
+	w := begin(`{"chat_id":"777","profile":"reckless"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mockDB.ExpectExec("CREATE TABLE IF NOT EXISTS telegram_operator_wallets").WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+	mockDB.ExpectExec("CREATE TABLE IF NOT EXISTS telegram_operator_state").WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+	mockDB.ExpectQuery(`SELECT COUNT\(\*\) FROM telegram_operator_wallets`).
+		WithArgs("777").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(1))
+	mockDB.ExpectQuery(`SELECT COUNT\(\*\) FROM telegram_operator_wallets`).
+		WithArgs("777").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(1))
+	mockDB.ExpectExec("INSERT INTO telegram_operator_state").
+		WithArgs("777", pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	w = begin(`{"chat_id":"777","profile":"Conservative"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp AutonomousStateResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "active", resp.Status)
+	assert.Equal(t, services.ProfileConservative, resp.Profile)
+
+	active, ok, err := profiles.ActiveProfile(t.Context(), "777")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, services.ProfileConservative, active.Name)
 	assert.NoError(t, mockDB.ExpectationsWereMet())
 }
@@ -349,6 +349,14 @@ func (m *MockCCXTService) FetchAllFundingRates(ctx context.Context, exchange str
 	return args.Get(0).([]ccxt.FundingRate), args.Error(1)
 }
 
+func (m *MockCCXTService) FetchDerivatives(ctx context.Context, exchange string, query ccxt.DerivativesQuery) (*ccxt.DerivativesResponse, error) {
+	args := m.Called(ctx, exchange, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ccxt.DerivativesResponse), args.Error(1)
+}
+
 func (m *MockCCXTService) CalculateArbitrageOpportunities(ctx context.Context, exchanges []string, symbols []string, minProfitPercent decimal.Decimal) ([]models.ArbitrageOpportunityResponse, error) {
 	args := m.Called(ctx, exchanges, symbols, minProfitPercent)
 	if args.Get(0) == nil {
@@ -419,6 +427,46 @@ func (m *MockCacheAnalyticsService) RecordCacheMiss(category string) {
 	m.Called(category)
 }
 
+func (m *MockCCXTClient) GetDerivatives(ctx context.Context, exchange string, query ccxt.DerivativesQuery) (*ccxt.DerivativesResponse, error) {
+	args := m.Called(ctx, exchange, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ccxt.DerivativesResponse), args.Error(1)
+}
+
+func (m *MockCCXTClient) GetLeverage(ctx context.Context, exchange, symbol string) (*ccxt.MarginSettingsResponse, error) {
+	args := m.Called(ctx, exchange, symbol)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ccxt.MarginSettingsResponse), args.Error(1)
+}
+
+func (m *MockCCXTClient) SetLeverage(ctx context.Context, exchange string, req ccxt.SetLeverageRequest) (*ccxt.MarginSettingsResponse, error) {
+	args := m.Called(ctx, exchange, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ccxt.MarginSettingsResponse), args.Error(1)
+}
+
+func (m *MockCCXTClient) GetPositions(ctx context.Context, exchange string, symbols []string) (*ccxt.PositionsResponse, error) {
+	args := m.Called(ctx, exchange, symbols)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ccxt.PositionsResponse), args.Error(1)
+}
+
+func (m *MockCCXTClient) GetPositioning(ctx context.Context, exchange string, symbols []string, period string) (*ccxt.PositioningResponse, error) {
+	args := m.Called(ctx, exchange, symbols, period)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ccxt.PositioningResponse), args.Error(1)
+}
+
 func (m *MockCCXTClient) FetchBalance(ctx context.Context, exchange string) (*ccxt.BalanceResponse, error) {
 	args := m.Called(ctx, exchange)
 	if args.Get(0) == nil {
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// TradeFlowHandler serves trade-tape imbalance and large-print indicators.
+type TradeFlowHandler struct {
+	tradeFlow services.TradeFlowReader
+}
+
+// NewTradeFlowHandler creates a new trade flow handler.
+//
+// Parameters:
+//
+//	tradeFlow: The trade-tape analyzer (may be nil when disabled).
+//
+// Returns:
+//
+//	*TradeFlowHandler: The initialized handler.
+func NewTradeFlowHandler(tradeFlow services.TradeFlowReader) *TradeFlowHandler {
+	return &TradeFlowHandler{tradeFlow: tradeFlow}
+}
+
+// GetTradeFlow returns the trade flow indicator of a symbol.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *TradeFlowHandler) GetTradeFlow(c *gin.Context) {
+	if h.tradeFlow == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "trade flow not available"})
+		return
+	}
+	exchange := strings.ToLower(c.Query("exchange"))
+	symbol := c.Query("symbol")
+	if exchange == "" || symbol == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "exchange and symbol are required"})
+		return
+	}
+	indicator, err := h.tradeFlow.TradeFlow(c.Request.Context(), exchange, symbol)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	if indicator == nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "error": "no recent trades for " + symbol + " on " + exchange})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": indicator})
+}
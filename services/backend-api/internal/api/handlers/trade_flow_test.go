@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTradeFlowHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	analyzer := services.NewTradeFlowAnalyzer(nil, services.TradeFlowConfig{})
+	analyzer.Ingest("binance", "BTC/USDT", []ccxt.Trade{
+		{ID: "1", Side: "buy", Price: decimal.NewFromInt(100), Amount: decimal.NewFromInt(3), Timestamp: time.Now()},
+		{ID: "2", Side: "sell", Price: decimal.NewFromInt(100), Amount: decimal.NewFromInt(1), Timestamp: time.Now()},
+	})
+	handler := NewTradeFlowHandler(analyzer)
+
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, url, nil)
+		handler.GetTradeFlow(c)
+		return w
+	}
+
+	w := get("/api/v1/market/trade-flow?exchange=binance&symbol=BTC/USDT")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"imbalance":0.5`)
+
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/market/trade-flow?exchange=binance&symbol=ETH/USDT").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/api/v1/market/trade-flow?symbol=BTC/USDT").Code)
+
+	w = performTradingModeRequest(NewTradeFlowHandler(nil).GetTradeFlow, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -3,14 +3,18 @@ package handlers
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/services"
 	"github.com/jackc/pgx/v5"
 	"github.com/shopspring/decimal"
 )
@@ -29,9 +33,26 @@ type TradingHandler struct {
 	db       DBQuerier
 	mu       sync.Mutex
 	sequence int64
+	guard    ProtectedActionGuard
+	events   services.EventEmitter
+	margins  PositionMarginSource
+	entries  services.PreTradeHook
+	chain    services.AuditChainAppender
 	// In-memory caches removed - all data persisted to database
 }
 
+// PositionMarginSource reports the margin state of an exchange account's open
+// derivatives positions.
+type PositionMarginSource interface {
+	Positions(ctx context.Context, exchange string) ([]ccxt.PositionInfo, error)
+}
+
+// ProtectedActionGuard gates destructive actions behind a second confirmation in live mode.
+type ProtectedActionGuard interface {
+	IsLive(ctx context.Context) bool
+	RequestAction(ctx context.Context, action services.ProtectedAction, operator string) (*services.ActionConfirmation, error)
+}
+
 type PlaceOrderRequest struct {
 	Exchange string          `json:"exchange" binding:"required"`
 	Symbol   string          `json:"symbol" binding:"required"`
@@ -143,18 +164,110 @@ func (h *TradingHandler) PlaceOrder(c *gin.Context) {
 		return
 	}
 
+	order, position, err := h.openPosition(c.Request.Context(), req.Exchange, req.Symbol, side, orderType, req.Amount, req.Price, "")
+	if errors.Is(err, services.ErrTradeVetoed) {
+		c.JSON(http.StatusConflict, gin.H{
+			"status": "error",
+			"error":  err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status": "error",
+			"error":  "failed to persist trading records",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status": "success",
+		"data": gin.H{
+			"order":    order,
+			"position": position,
+		},
+	})
+}
+
+// PlaceExternalOrder opens a market position on behalf of an external signal.
+// It implements services.ExternalOrderPlacer.
+//
+// Parameters:
+//
+//	ctx: Request context.
+//	order: The order to place.
+//
+// Returns:
+//
+//	string: The order ID.
+//	error: Error if validation or persistence fails.
+func (h *TradingHandler) PlaceExternalOrder(ctx context.Context, order services.ExternalOrder) (string, error) {
+	side := strings.ToUpper(strings.TrimSpace(order.Side))
+	if side != "BUY" && side != "SELL" {
+		return "", fmt.Errorf("side must be BUY or SELL")
+	}
+	if order.Amount.LessThanOrEqual(decimal.Zero) {
+		return "", fmt.Errorf("amount must be greater than zero")
+	}
+
+	record, _, err := h.openPosition(ctx, order.Exchange, order.Symbol, side, "MARKET", order.Amount, order.Price, order.Strategy)
+	if err != nil {
+		return "", err
+	}
+	return record.OrderID, nil
+}
+
+// OpenPositions lists the open positions. It implements
+// services.OpenPositionSource.
+func (h *TradingHandler) OpenPositions(ctx context.Context) ([]services.OpenPosition, error) {
+	records, err := h.listPositionsPersistent(ctx, "OPEN")
+	if err != nil {
+		return nil, err
+	}
+	positions := make([]services.OpenPosition, 0, len(records))
+	for _, record := range records {
+		positions = append(positions, services.OpenPosition{
+			Exchange:   record.Exchange,
+			Symbol:     record.Symbol,
+			Side:       record.Side,
+			Size:       record.Size,
+			EntryPrice: record.EntryPrice,
+		})
+	}
+	return positions, nil
+}
+
+// openPosition persists an order with its position and emits trade.executed.
+func (h *TradingHandler) openPosition(ctx context.Context, exchange, symbol, side, orderType string, amount, price decimal.Decimal, strategy string) (OrderRecord, PositionRecord, error) {
+	if h.entries != nil {
+		decision, err := h.entries.PreTrade(ctx, services.HookOrder{
+			Exchange:  exchange,
+			Symbol:    symbol,
+			Side:      side,
+			OrderType: orderType,
+			Amount:    amount,
+			Price:     &price,
+		})
+		if err != nil {
+			return OrderRecord{}, PositionRecord{}, fmt.Errorf("%w: %v", services.ErrTradeVetoed, err)
+		}
+		if decision.Veto {
+			return OrderRecord{}, PositionRecord{}, fmt.Errorf("%w: %s", services.ErrTradeVetoed, decision.Reason)
+		}
+	}
+
 	now := time.Now().UTC()
 	orderID, positionID := h.generateIDs(now)
 
 	order := OrderRecord{
 		OrderID:    orderID,
 		PositionID: positionID,
-		Exchange:   req.Exchange,
-		Symbol:     req.Symbol,
+		Exchange:   exchange,
+		Symbol:     symbol,
 		Side:       side,
 		Type:       orderType,
-		Amount:     req.Amount,
-		Price:      req.Price,
+		Amount:     amount,
+		Price:      price,
 		Status:     "OPEN",
 		CreatedAt:  now,
 		UpdatedAt:  now,
@@ -163,31 +276,33 @@ func (h *TradingHandler) PlaceOrder(c *gin.Context) {
 	position := PositionRecord{
 		PositionID: positionID,
 		OrderID:    orderID,
-		Exchange:   req.Exchange,
-		Symbol:     req.Symbol,
+		Exchange:   exchange,
+		Symbol:     symbol,
 		Side:       side,
-		Size:       req.Amount,
-		EntryPrice: req.Price,
+		Size:       amount,
+		EntryPrice: price,
 		Status:     "OPEN",
 		OpenedAt:   now,
 		UpdatedAt:  now,
 	}
 
-	if err := h.insertTradingRecords(c.Request.Context(), order, position); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status": "error",
-			"error":  "failed to persist trading records",
-		})
-		return
+	if err := h.insertTradingRecords(ctx, order, position); err != nil {
+		return OrderRecord{}, PositionRecord{}, err
 	}
+	h.chainRecords(ctx, order.OrderID, position.PositionID)
 
-	c.JSON(http.StatusCreated, gin.H{
-		"status": "success",
-		"data": gin.H{
+	if h.events != nil {
+		data := gin.H{
 			"order":    order,
 			"position": position,
-		},
-	})
+		}
+		if strategy != "" {
+			data["strategy"] = strategy
+		}
+		h.events.Emit(ctx, services.WebhookEventTradeExecuted, data)
+	}
+
+	return order, position, nil
 }
 
 func (h *TradingHandler) CancelOrder(c *gin.Context) {
@@ -256,7 +371,54 @@ func (h *TradingHandler) Liquidate(c *gin.Context) {
 	})
 }
 
+// SetActionGuard enables the two-man rule for liquidate-all on live accounts.
+func (h *TradingHandler) SetActionGuard(guard ProtectedActionGuard) {
+	h.guard = guard
+}
+
+// SetEntryGuard checks every new position, from the API and from external
+// signals, before it is opened; a veto rejects the order.
+func (h *TradingHandler) SetEntryGuard(guard services.PreTradeHook) {
+	h.entries = guard
+}
+
+// SetAuditChain appends every order and position, and each change to their
+// status, to the tamper-evident audit chain.
+func (h *TradingHandler) SetAuditChain(chain services.AuditChainAppender) {
+	h.chain = chain
+}
+
+// SetEventEmitter publishes executed trades, for example to outbound webhooks.
+func (h *TradingHandler) SetEventEmitter(events services.EventEmitter) {
+	h.events = events
+}
+
+// SetMarginSource adds leverage, margin mode and margin ratio from the
+// exchanges to position snapshots.
+func (h *TradingHandler) SetMarginSource(margins PositionMarginSource) {
+	h.margins = margins
+}
+
+// LiquidateAllPositions closes every open position. It is the action run once
+// a pending liquidate-all confirmation is approved.
+func (h *TradingHandler) LiquidateAllPositions(ctx context.Context) ([]PositionRecord, error) {
+	return h.liquidateAllPersistent(ctx)
+}
+
 func (h *TradingHandler) LiquidateAll(c *gin.Context) {
+	if h.guard != nil && h.guard.IsLive(c.Request.Context()) {
+		confirmation, err := h.guard.RequestAction(c.Request.Context(), services.ProtectedActionLiquidateAll, c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": "failed to request confirmation"})
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{
+			"status": "pending_confirmation",
+			"data":   confirmation,
+		})
+		return
+	}
+
 	positions, err := h.liquidateAllPersistent(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": "failed to liquidate positions"})
@@ -272,8 +434,22 @@ func (h *TradingHandler) LiquidateAll(c *gin.Context) {
 	})
 }
 
+// positionPageOptions defines pagination for the positions list, newest first by default.
+var positionPageOptions = PageOptions{
+	DefaultLimit: 50,
+	MaxLimit:     200,
+	SortFields:   []string{"opened_at", "updated_at", "symbol"},
+	DefaultDesc:  true,
+	Filters:      []string{"status", "symbol", "exchange", "side"},
+}
+
 func (h *TradingHandler) ListPositions(c *gin.Context) {
-	statusFilter := strings.ToUpper(strings.TrimSpace(c.Query("status")))
+	page, err := ParsePageRequest(c, positionPageOptions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	statusFilter := strings.ToUpper(page.Filter("status"))
 
 	positions, err := h.listPositionsPersistent(c.Request.Context(), statusFilter)
 	if err != nil {
@@ -281,15 +457,49 @@ func (h *TradingHandler) ListPositions(c *gin.Context) {
 		return
 	}
 
+	filtered := positions[:0]
+	for _, p := range positions {
+		if matchesPositionFilters(p, page) {
+			filtered = append(filtered, p)
+		}
+	}
+
+	items, info := PaginateSlice(filtered, page, positionSortKey, func(p PositionRecord) string { return p.PositionID })
+	writePageHeaders(c, info)
 	c.JSON(http.StatusOK, gin.H{
 		"status": "success",
 		"data": gin.H{
-			"count":     len(positions),
-			"positions": positions,
+			"count":      len(items),
+			"positions":  items,
+			"pagination": info,
 		},
 	})
 }
 
+func matchesPositionFilters(p PositionRecord, page PageRequest) bool {
+	if symbol := page.Filter("symbol"); symbol != "" && !strings.EqualFold(p.Symbol, symbol) {
+		return false
+	}
+	if exchange := page.Filter("exchange"); exchange != "" && !strings.EqualFold(p.Exchange, exchange) {
+		return false
+	}
+	if side := page.Filter("side"); side != "" && !strings.EqualFold(p.Side, side) {
+		return false
+	}
+	return true
+}
+
+func positionSortKey(p PositionRecord, field string) string {
+	switch field {
+	case "updated_at":
+		return pageTimeKey(p.UpdatedAt)
+	case "symbol":
+		return p.Symbol
+	default:
+		return pageTimeKey(p.OpenedAt)
+	}
+}
+
 func (h *TradingHandler) GetPosition(c *gin.Context) {
 	positionID := strings.TrimSpace(c.Param("position_id"))
 	if positionID == "" {
@@ -324,6 +534,21 @@ type PositionSnapshot struct {
 	OpenPositions   int              `json:"open_positions"`
 	ClosedPositions int              `json:"closed_positions"`
 	Positions       []PositionRecord `json:"positions"`
+	// Margins is the exchange-reported margin state of open derivatives
+	// positions, when a margin source is configured.
+	Margins []PositionMargin `json:"margins,omitempty"`
+}
+
+// PositionMargin is the leverage and margin state of an open exchange position.
+type PositionMargin struct {
+	Exchange         string  `json:"exchange"`
+	Symbol           string  `json:"symbol"`
+	Side             string  `json:"side,omitempty"`
+	Leverage         float64 `json:"leverage,omitempty"`
+	MarginMode       string  `json:"margin_mode,omitempty"`
+	MarginRatio      float64 `json:"margin_ratio"`
+	LiquidationPrice float64 `json:"liquidation_price,omitempty"`
+	MarkPrice        float64 `json:"mark_price,omitempty"`
 }
 
 // GetPositionSnapshot returns a comprehensive snapshot of all positions at a point in time.
@@ -360,6 +585,7 @@ func (h *TradingHandler) GetPositionSnapshot(c *gin.Context) {
 		OpenPositions:   openCount,
 		ClosedPositions: closedCount,
 		Positions:       allPositions,
+		Margins:         h.positionMargins(c.Request.Context(), allPositions),
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -368,6 +594,40 @@ func (h *TradingHandler) GetPositionSnapshot(c *gin.Context) {
 	})
 }
 
+// positionMargins fetches the margin state of every exchange with open
+// positions. Exchanges that fail are left out of the snapshot.
+func (h *TradingHandler) positionMargins(ctx context.Context, positions []PositionRecord) []PositionMargin {
+	if h.margins == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var margins []PositionMargin
+	for _, p := range positions {
+		if p.Status != "OPEN" || seen[p.Exchange] {
+			continue
+		}
+		seen[p.Exchange] = true
+		infos, err := h.margins.Positions(ctx, p.Exchange)
+		if err != nil {
+			log.Printf("Failed to fetch margin state for %s: %v", p.Exchange, err)
+			continue
+		}
+		for _, info := range infos {
+			margins = append(margins, PositionMargin{
+				Exchange:         p.Exchange,
+				Symbol:           info.Symbol,
+				Side:             info.Side,
+				Leverage:         info.Leverage,
+				MarginMode:       info.MarginMode,
+				MarginRatio:      info.MarginRatio,
+				LiquidationPrice: info.LiquidationPrice,
+				MarkPrice:        info.MarkPrice,
+			})
+		}
+	}
+	return margins
+}
+
 func (h *TradingHandler) generateIDs(now time.Time) (string, string) {
 	h.mu.Lock()
 	h.sequence++
@@ -449,28 +709,8 @@ func (h *TradingHandler) insertTradingRecords(ctx context.Context, order OrderRe
 }
 
 func (h *TradingHandler) cancelOrderPersistent(ctx context.Context, orderID string) (OrderRecord, error) {
-	var order OrderRecord
-	err := h.db.QueryRow(ctx, `
-		SELECT order_id, position_id, exchange, symbol, side, type, amount, price, status, created_at, updated_at
-		FROM trading_orders
-		WHERE order_id = $1
-	`, orderID).Scan(
-		&order.OrderID,
-		&order.PositionID,
-		&order.Exchange,
-		&order.Symbol,
-		&order.Side,
-		&order.Type,
-		&order.Amount,
-		&order.Price,
-		&order.Status,
-		&order.CreatedAt,
-		&order.UpdatedAt,
-	)
+	order, err := h.getOrderPersistent(ctx, orderID)
 	if err != nil {
-		if isNoRowsError(err) {
-			return OrderRecord{}, errTradingOrderNotFound
-		}
 		return OrderRecord{}, err
 	}
 
@@ -495,6 +735,8 @@ func (h *TradingHandler) cancelOrderPersistent(ctx context.Context, orderID stri
 		return OrderRecord{}, err
 	}
 
+	h.chainRecords(ctx, orderID, order.PositionID)
+
 	order.Status = "CANCELED"
 	order.UpdatedAt = now
 	return order, nil
@@ -552,6 +794,8 @@ func (h *TradingHandler) liquidatePersistent(ctx context.Context, positionID, sy
 		return PositionRecord{}, err
 	}
 
+	h.chainRecords(ctx, position.OrderID, position.PositionID)
+
 	position.Status = "LIQUIDATED"
 	position.UpdatedAt = now
 	return position, nil
@@ -610,6 +854,8 @@ func (h *TradingHandler) liquidateAllPersistent(ctx context.Context) ([]Position
 			return nil, err
 		}
 
+		h.chainRecords(ctx, positions[i].OrderID, positions[i].PositionID)
+
 		positions[i].Status = "LIQUIDATED"
 		positions[i].UpdatedAt = now
 	}
@@ -689,6 +935,90 @@ func (h *TradingHandler) getPositionPersistent(ctx context.Context, positionID s
 	return p, nil
 }
 
+func (h *TradingHandler) getOrderPersistent(ctx context.Context, orderID string) (OrderRecord, error) {
+	var order OrderRecord
+	err := h.db.QueryRow(ctx, `
+		SELECT order_id, position_id, exchange, symbol, side, type, amount, price, status, created_at, updated_at
+		FROM trading_orders
+		WHERE order_id = $1
+	`, orderID).Scan(
+		&order.OrderID,
+		&order.PositionID,
+		&order.Exchange,
+		&order.Symbol,
+		&order.Side,
+		&order.Type,
+		&order.Amount,
+		&order.Price,
+		&order.Status,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+	)
+	if err != nil {
+		if isNoRowsError(err) {
+			return OrderRecord{}, errTradingOrderNotFound
+		}
+		return OrderRecord{}, err
+	}
+
+	return order, nil
+}
+
+// chainRecords appends the stored form of an order and its position to the
+// audit chain. They are read back so the chained payload matches what
+// verification reads. Failures are logged: the change is stored either way
+// and verification reports it.
+func (h *TradingHandler) chainRecords(ctx context.Context, orderID, positionID string) {
+	if h.chain == nil {
+		return
+	}
+	for _, record := range []struct{ kind, id string }{
+		{services.AuditKindTradingOrder, orderID},
+		{services.AuditKindTradingPosition, positionID},
+	} {
+		payload, err := h.ChainPayload(ctx, record.kind, record.id)
+		if err == nil {
+			_, err = h.chain.Append(ctx, record.kind, record.id, payload)
+		}
+		if err != nil {
+			log.Printf("Failed to append %s %s to the audit chain: %v", record.kind, record.id, err)
+		}
+	}
+}
+
+// ChainPayload returns the current form of a chained order or position. It
+// implements services.AuditRecordSource.
+//
+// Parameters:
+//
+//	ctx: Context for the lookup.
+//	kind: services.AuditKindTradingOrder or services.AuditKindTradingPosition.
+//	refID: The order or position ID.
+//
+// Returns:
+//
+//	[]byte: The record as JSON.
+//	error: services.ErrAuditRecordNotFound if the record no longer exists.
+func (h *TradingHandler) ChainPayload(ctx context.Context, kind, refID string) ([]byte, error) {
+	var record interface{}
+	var err error
+	switch kind {
+	case services.AuditKindTradingOrder:
+		record, err = h.getOrderPersistent(ctx, refID)
+	case services.AuditKindTradingPosition:
+		record, err = h.getPositionPersistent(ctx, refID)
+	default:
+		return nil, fmt.Errorf("unsupported audit record kind %q", kind)
+	}
+	if errors.Is(err, errTradingOrderNotFound) || errors.Is(err, errTradingPositionNotFound) {
+		return nil, fmt.Errorf("%w: %s", services.ErrAuditRecordNotFound, refID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(record)
+}
+
 func isNoRowsError(err error) bool {
 	return errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows)
 }
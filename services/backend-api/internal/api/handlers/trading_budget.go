@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/shopspring/decimal"
+)
+
+// TradingBudgetManager defines the per-chat trading budget operations.
+type TradingBudgetManager interface {
+	Set(ctx context.Context, chatID string, budget decimal.Decimal) (*services.TradingBudget, error)
+	Clear(ctx context.Context, chatID string) error
+	Status(ctx context.Context, chatID string) (*services.TradingBudgetStatus, error)
+}
+
+// TradingBudgetHandler manages how much of the account each chat trades autonomously.
+type TradingBudgetHandler struct {
+	budgets TradingBudgetManager
+}
+
+// UpdateTradingBudgetRequest changes a chat's trading budget.
+type UpdateTradingBudgetRequest struct {
+	ChatID string `json:"chat_id" binding:"required"`
+	// Action is set or clear.
+	Action string `json:"action" binding:"required"`
+	// Budget is the capital to trade for set, e.g. "500".
+	Budget string `json:"budget"`
+}
+
+// NewTradingBudgetHandler creates a new trading budget handler.
+//
+// Parameters:
+//
+//	budgets: The budget service (may be nil when Redis or balances are unavailable).
+//
+// Returns:
+//
+//	*TradingBudgetHandler: The initialized handler.
+func NewTradingBudgetHandler(budgets TradingBudgetManager) *TradingBudgetHandler {
+	return &TradingBudgetHandler{budgets: budgets}
+}
+
+func (h *TradingBudgetHandler) available(c *gin.Context) bool {
+	if h.budgets == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "trading budget service not available"})
+		return false
+	}
+	return true
+}
+
+func (h *TradingBudgetHandler) respondBudget(c *gin.Context, chatID string) {
+	status, err := h.budgets.Status(c.Request.Context(), chatID)
+	if err != nil && !errors.Is(err, services.ErrTradingBudgetNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{
+		"budget":   status,
+		"enforced": status != nil,
+	}})
+}
+
+// GetTradingBudget returns a chat's trading budget valued against the
+// account; budget is null when the whole account may be traded.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *TradingBudgetHandler) GetTradingBudget(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	chatID := c.Query("chat_id")
+	if chatID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "chat_id is required"})
+		return
+	}
+	h.respondBudget(c, chatID)
+}
+
+// UpdateTradingBudget sets or clears a chat's trading budget.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *TradingBudgetHandler) UpdateTradingBudget(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req UpdateTradingBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "Invalid request body"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	var err error
+	switch req.Action {
+	case "set":
+		budget, parseErr := decimal.NewFromString(req.Budget)
+		if parseErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "budget must be a number"})
+			return
+		}
+		_, err = h.budgets.Set(ctx, req.ChatID, budget)
+	case "clear":
+		err = h.budgets.Clear(ctx, req.ChatID)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "action must be set or clear"})
+		return
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrTradingBudgetInvalid) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	h.respondBudget(c, req.ChatID)
+}
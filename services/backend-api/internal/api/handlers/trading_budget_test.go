@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type staticAccountEquity decimal.Decimal
+
+func (s staticAccountEquity) AccountEquity(context.Context) (decimal.Decimal, error) {
+	return decimal.Decimal(s), nil
+}
+
+func TestTradingBudgetHandler_UpdateAndGet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	handler := NewTradingBudgetHandler(services.NewTradingBudgetService(client, staticAccountEquity(decimal.NewFromInt(2000))))
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/telegram/internal/trading-budget?chat_id=42", nil)
+	handler.GetTradingBudget(c)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"enforced":false`)
+
+	w := performTradingModeRequest(handler.UpdateTradingBudget, `{"chat_id":"42","action":"set","budget":"500"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"enforced":true`)
+	assert.Contains(t, w.Body.String(), `"reserve":"1500"`)
+	assert.Contains(t, w.Body.String(), `"remaining":"500"`)
+
+	w = performTradingModeRequest(handler.UpdateTradingBudget, `{"chat_id":"42","action":"set","budget":"5000"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(handler.UpdateTradingBudget, `{"chat_id":"42","action":"set","budget":"lots"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(handler.UpdateTradingBudget, `{"chat_id":"42","action":"clear"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"budget":null`)
+}
+
+func TestTradingBudgetHandler_Unavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := performTradingModeRequest(NewTradingBudgetHandler(nil).UpdateTradingBudget, `{"chat_id":"42","action":"clear"}`, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
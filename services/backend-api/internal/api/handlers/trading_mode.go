@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/middleware"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// TradingModeInterface defines the execution mode operations exposed to operators.
+type TradingModeInterface interface {
+	GetState(ctx context.Context) (*services.TradingModeState, error)
+	SwitchMode(ctx context.Context, mode services.ExecutionMode, operator string) (*services.ActionConfirmation, error)
+	EngageKillSwitch(ctx context.Context, operator, reason string) error
+	RequestAction(ctx context.Context, action services.ProtectedAction, operator string) (*services.ActionConfirmation, error)
+	Confirm(ctx context.Context, id, operator, code string) (*services.ActionConfirmation, error)
+	Cancel(ctx context.Context, id, operator string) (*services.ActionConfirmation, error)
+	GetConfirmation(ctx context.Context, id string) (*services.ActionConfirmation, error)
+	ScheduleGoLive(ctx context.Context, activateAt time.Time, operator string) (*services.ActionConfirmation, error)
+	GetGoLiveSchedule(ctx context.Context) (*services.GoLiveSchedule, error)
+	CancelGoLive(ctx context.Context, id, operator string) (*services.GoLiveSchedule, error)
+}
+
+// TradingModeHandler handles execution mode, kill switch and confirmation endpoints.
+type TradingModeHandler struct {
+	modes TradingModeInterface
+}
+
+// NewTradingModeHandler creates a new trading mode handler.
+//
+// Parameters:
+//
+//	modes: The trading mode service (may be nil when Redis is unavailable).
+//
+// Returns:
+//
+//	*TradingModeHandler: The initialized handler.
+func NewTradingModeHandler(modes TradingModeInterface) *TradingModeHandler {
+	return &TradingModeHandler{modes: modes}
+}
+
+type tradingModeOperatorRequest struct {
+	// Operator is not accepted from clients; it is the authenticated
+	// admin credential, so one key cannot act as two operators.
+	Operator string `json:"operator"`
+	Mode     string `json:"mode"`
+	Reason   string `json:"reason"`
+	Code     string `json:"code"`
+	// ActivateAt is when a scheduled go-live switches to live mode.
+	ActivateAt time.Time `json:"activate_at"`
+}
+
+func (h *TradingModeHandler) available(c *gin.Context) bool {
+	if h.modes == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "trading mode service not available"})
+		return false
+	}
+	return true
+}
+
+// bindOperator reads the request body and sets the operator to the one
+// authenticated by the admin middleware.
+func (h *TradingModeHandler) bindOperator(c *gin.Context) (*tradingModeOperatorRequest, bool) {
+	var req tradingModeOperatorRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "invalid request body"})
+		return nil, false
+	}
+	if req.Operator != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "operator is taken from the admin credential and must not be sent"})
+		return nil, false
+	}
+	req.Operator = middleware.AdminOperator(c)
+	if req.Operator == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"status": "error", "error": "an authenticated operator is required"})
+		return nil, false
+	}
+	return &req, true
+}
+
+// respondConfirmation writes 202 for pending confirmations and 200 otherwise.
+func respondConfirmation(c *gin.Context, confirmation *services.ActionConfirmation) {
+	if confirmation != nil && confirmation.Status == services.ConfirmationStatusPending {
+		c.JSON(http.StatusAccepted, gin.H{"status": "pending_confirmation", "data": confirmation})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": confirmation})
+}
+
+// GetState returns the current execution mode and kill-switch state.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *TradingModeHandler) GetState(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	state, err := h.modes.GetState(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": state})
+}
+
+// SwitchMode changes the execution mode. Switching to live returns a pending confirmation.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *TradingModeHandler) SwitchMode(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	req, ok := h.bindOperator(c)
+	if !ok {
+		return
+	}
+	confirmation, err := h.modes.SwitchMode(c.Request.Context(), services.ExecutionMode(req.Mode), req.Operator)
+	if err != nil {
+		writeConfirmationError(c, err)
+		return
+	}
+	respondConfirmation(c, confirmation)
+}
+
+// EngageKillSwitch halts trading immediately.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *TradingModeHandler) EngageKillSwitch(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	req, ok := h.bindOperator(c)
+	if !ok {
+		return
+	}
+	if err := h.modes.EngageKillSwitch(c.Request.Context(), req.Operator, req.Reason); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Kill switch engaged"})
+}
+
+// ReleaseKillSwitch requests kill-switch release, which needs confirmation in live mode.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *TradingModeHandler) ReleaseKillSwitch(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	req, ok := h.bindOperator(c)
+	if !ok {
+		return
+	}
+	confirmation, err := h.modes.RequestAction(c.Request.Context(), services.ProtectedActionKillSwitchRelease, req.Operator)
+	if err != nil {
+		writeConfirmationError(c, err)
+		return
+	}
+	respondConfirmation(c, confirmation)
+}
+
+// GetConfirmation returns a confirmation by ID.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *TradingModeHandler) GetConfirmation(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	confirmation, err := h.modes.GetConfirmation(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeConfirmationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": confirmation})
+}
+
+// Confirm completes a pending confirmation with a delayed code or a second
+// operator, who must authenticate with a different credential.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *TradingModeHandler) Confirm(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	req, ok := h.bindOperator(c)
+	if !ok {
+		return
+	}
+	confirmation, err := h.modes.Confirm(c.Request.Context(), c.Param("id"), req.Operator, req.Code)
+	if err != nil {
+		writeConfirmationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": confirmation})
+}
+
+// Cancel aborts a pending confirmation.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *TradingModeHandler) Cancel(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	req, ok := h.bindOperator(c)
+	if !ok {
+		return
+	}
+	confirmation, err := h.modes.Cancel(c.Request.Context(), c.Param("id"), req.Operator)
+	if err != nil {
+		writeConfirmationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": confirmation})
+}
+
+// ScheduleGoLive requests a switch to live mode at a future time. The
+// returned confirmation must be completed before the go-live is scheduled.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *TradingModeHandler) ScheduleGoLive(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	req, ok := h.bindOperator(c)
+	if !ok {
+		return
+	}
+	confirmation, err := h.modes.ScheduleGoLive(c.Request.Context(), req.ActivateAt, req.Operator)
+	if err != nil {
+		writeConfirmationError(c, err)
+		return
+	}
+	respondConfirmation(c, confirmation)
+}
+
+// GetGoLiveSchedule returns the latest go-live schedule.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *TradingModeHandler) GetGoLiveSchedule(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	schedule, err := h.modes.GetGoLiveSchedule(c.Request.Context())
+	if err != nil {
+		writeConfirmationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": schedule})
+}
+
+// CancelGoLive cancels the scheduled go-live without confirmation.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *TradingModeHandler) CancelGoLive(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	req, ok := h.bindOperator(c)
+	if !ok {
+		return
+	}
+	schedule, err := h.modes.CancelGoLive(c.Request.Context(), "", req.Operator)
+	if err != nil {
+		writeConfirmationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": schedule})
+}
+
+func writeConfirmationError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrConfirmationNotFound), errors.Is(err, services.ErrGoLiveNotScheduled):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidExecutionMode), errors.Is(err, services.ErrInvalidGoLiveTime):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrConfirmationInvalidCode), errors.Is(err, services.ErrOperatorNotBound):
+		status = http.StatusForbidden
+	case errors.Is(err, services.ErrConfirmationTooEarly), errors.Is(err, services.ErrConfirmationNotPending),
+		errors.Is(err, services.ErrLiveTradingBlocked), errors.Is(err, services.ErrGoLiveAlreadyScheduled):
+		status = http.StatusConflict
+	case errors.Is(err, services.ErrConfirmationExpired):
+		status = http.StatusGone
+	}
+	c.JSON(status, gin.H{"status": "error", "error": err.Error()})
+}
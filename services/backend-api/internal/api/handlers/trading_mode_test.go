@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/middleware"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubTradingModes struct {
+	confirmErr error
+	operator   string
+}
+
+func (s *stubTradingModes) GetState(ctx context.Context) (*services.TradingModeState, error) {
+	return &services.TradingModeState{Mode: services.ExecutionModePaper}, nil
+}
+
+func (s *stubTradingModes) SwitchMode(ctx context.Context, mode services.ExecutionMode, operator string) (*services.ActionConfirmation, error) {
+	switch mode {
+	case services.ExecutionModeLive:
+		return &services.ActionConfirmation{ID: "c1", Status: services.ConfirmationStatusPending}, nil
+	case services.ExecutionModePaper:
+		return nil, nil
+	default:
+		return nil, services.ErrInvalidExecutionMode
+	}
+}
+
+func (s *stubTradingModes) EngageKillSwitch(ctx context.Context, operator, reason string) error {
+	return nil
+}
+
+func (s *stubTradingModes) RequestAction(ctx context.Context, action services.ProtectedAction, operator string) (*services.ActionConfirmation, error) {
+	return &services.ActionConfirmation{ID: "c2", Action: action, Status: services.ConfirmationStatusPending}, nil
+}
+
+func (s *stubTradingModes) Confirm(ctx context.Context, id, operator, code string) (*services.ActionConfirmation, error) {
+	s.operator = operator
+	if s.confirmErr != nil {
+		return nil, s.confirmErr
+	}
+	return &services.ActionConfirmation{ID: id, Status: services.ConfirmationStatusExecuted}, nil
+}
+
+func (s *stubTradingModes) Cancel(ctx context.Context, id, operator string) (*services.ActionConfirmation, error) {
+	return &services.ActionConfirmation{ID: id, Status: services.ConfirmationStatusCancelled}, nil
+}
+
+func (s *stubTradingModes) GetConfirmation(ctx context.Context, id string) (*services.ActionConfirmation, error) {
+	return nil, services.ErrConfirmationNotFound
+}
+
+func (s *stubTradingModes) ScheduleGoLive(ctx context.Context, activateAt time.Time, operator string) (*services.ActionConfirmation, error) {
+	if activateAt.IsZero() {
+		return nil, services.ErrInvalidGoLiveTime
+	}
+	return &services.ActionConfirmation{ID: "c3", Action: services.ProtectedActionSwitchToLive, Status: services.ConfirmationStatusPending, ActivateAt: &activateAt}, nil
+}
+
+func (s *stubTradingModes) GetGoLiveSchedule(ctx context.Context) (*services.GoLiveSchedule, error) {
+	return nil, services.ErrGoLiveNotScheduled
+}
+
+func (s *stubTradingModes) CancelGoLive(ctx context.Context, id, operator string) (*services.GoLiveSchedule, error) {
+	return &services.GoLiveSchedule{ID: "c3", Status: services.GoLiveStatusCancelled, CancelledBy: operator}, nil
+}
+
+func performTradingModeRequest(handlerFunc gin.HandlerFunc, body string, params gin.Params) *httptest.ResponseRecorder {
+	return performTradingModeRequestAs("op-1", handlerFunc, body, params)
+}
+
+func performTradingModeRequestAs(operator string, handlerFunc gin.HandlerFunc, body string, params gin.Params) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/admin/trading-mode", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = params
+	if operator != "" {
+		c.Set(middleware.AdminOperatorContextKey, operator)
+	}
+	handlerFunc(c)
+	return w
+}
+
+func TestTradingModeHandler_SwitchMode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewTradingModeHandler(&stubTradingModes{})
+
+	w := performTradingModeRequest(handler.SwitchMode, `{"mode":"live"}`, nil)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Contains(t, w.Body.String(), "pending_confirmation")
+
+	w = performTradingModeRequest(handler.SwitchMode, `{"mode":"paper"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = performTradingModeRequest(handler.SwitchMode, `{"mode":"yolo"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTradingModeHandler_OperatorFromCredential(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	modes := &stubTradingModes{}
+	handler := NewTradingModeHandler(modes)
+	params := gin.Params{{Key: "id", Value: "c1"}}
+
+	w := performTradingModeRequestAs("op-2", handler.Confirm, `{"code":"123456"}`, params)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "op-2", modes.operator)
+
+	// A client cannot claim to be another operator
+	w = performTradingModeRequestAs("op-1", handler.Confirm, `{"operator":"op-2"}`, params)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequestAs("", handler.EngageKillSwitch, "", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = performTradingModeRequestAs("op-1", handler.EngageKillSwitch, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code, "an empty body is accepted")
+}
+
+func TestTradingModeHandler_ConfirmErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	params := gin.Params{{Key: "id", Value: "c1"}}
+
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{nil, http.StatusOK},
+		{services.ErrConfirmationInvalidCode, http.StatusForbidden},
+		{services.ErrOperatorNotBound, http.StatusForbidden},
+		{services.ErrConfirmationTooEarly, http.StatusConflict},
+		{services.ErrConfirmationExpired, http.StatusGone},
+		{services.ErrConfirmationNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		handler := NewTradingModeHandler(&stubTradingModes{confirmErr: tt.err})
+		w := performTradingModeRequest(handler.Confirm, `{"code":"123456"}`, params)
+		assert.Equal(t, tt.status, w.Code, "error %v", tt.err)
+	}
+}
+
+func TestTradingModeHandler_GoLive(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewTradingModeHandler(&stubTradingModes{})
+
+	w := performTradingModeRequest(handler.ScheduleGoLive, `{"activate_at":"2026-03-02T14:30:00Z"}`, nil)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Contains(t, w.Body.String(), `"activate_at":"2026-03-02T14:30:00Z"`)
+
+	w = performTradingModeRequest(handler.ScheduleGoLive, `{}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(handler.GetGoLiveSchedule, "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = performTradingModeRequestAs("op-2", handler.CancelGoLive, `{}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"cancelled_by":"op-2"`)
+}
+
+func TestTradingModeHandler_Unavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewTradingModeHandler(nil)
+
+	w := performTradingModeRequest(handler.GetState, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+type stubActionGuard struct {
+	live bool
+}
+
+func (s *stubActionGuard) IsLive(ctx context.Context) bool { return s.live }
+
+func (s *stubActionGuard) RequestAction(ctx context.Context, action services.ProtectedAction, operator string) (*services.ActionConfirmation, error) {
+	return &services.ActionConfirmation{ID: "c3", Action: action, Status: services.ConfirmationStatusPending, RequestedBy: operator}, nil
+}
+
+func TestTradingHandler_LiquidateAllRequiresConfirmationInLiveMode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := &TradingHandler{}
+	handler.SetActionGuard(&stubActionGuard{live: true})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/trading/liquidate_all", nil)
+	c.Set("user_id", "user-1")
+	handler.LiquidateAll(c)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Contains(t, w.Body.String(), "liquidate_all")
+	assert.Contains(t, w.Body.String(), "user-1")
+}
@@ -11,6 +11,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/jackc/pgx/v5"
 	"github.com/pashagolub/pgxmock/v4"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
@@ -192,6 +194,104 @@ func TestTradingHandlerPlaceOrderValidation(t *testing.T) {
 	require.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+type vetoEntries struct{ orders []services.HookOrder }
+
+func (v *vetoEntries) PreTrade(_ context.Context, order services.HookOrder) (services.PreTradeDecision, error) {
+	v.orders = append(v.orders, order)
+	return services.PreTradeDecision{Veto: true, Reason: "daily loss cap reached"}, nil
+}
+
+func TestTradingHandlerEntryGuard(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h, mock := setupTradingHandlerWithMock(t)
+	defer closeMock(t, mock)
+	guard := &vetoEntries{}
+	h.SetEntryGuard(guard)
+
+	r := gin.New()
+	r.POST("/trading/place_order", h.PlaceOrder)
+
+	// A vetoed order is rejected before anything is persisted
+	body := `{"exchange":"binance","symbol":"BTC/USDT","side":"buy","amount":"1"}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/trading/place_order", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "daily loss cap reached")
+
+	_, err := h.PlaceExternalOrder(t.Context(), services.ExternalOrder{Exchange: "binance", Symbol: "ETH/USDT", Side: "sell", Amount: decimal.NewFromInt(2)})
+	assert.ErrorIs(t, err, services.ErrTradeVetoed)
+	require.Len(t, guard.orders, 2)
+	assert.Equal(t, "BUY", guard.orders[0].Side)
+	assert.Equal(t, "ETH/USDT", guard.orders[1].Symbol)
+}
+
+type recordingAuditChain struct {
+	kinds    []string
+	refs     []string
+	payloads []string
+}
+
+func (r *recordingAuditChain) Append(_ context.Context, kind, refID string, payload []byte) (*services.AuditChainEntry, error) {
+	r.kinds = append(r.kinds, kind)
+	r.refs = append(r.refs, refID)
+	r.payloads = append(r.payloads, string(payload))
+	return &services.AuditChainEntry{Kind: kind, RefID: refID, Payload: string(payload)}, nil
+}
+
+func TestTradingHandlerChainsOrdersAndPositions(t *testing.T) {
+	h, mock := setupTradingHandlerWithMock(t)
+	defer closeMock(t, mock)
+	chain := &recordingAuditChain{}
+	h.SetAuditChain(chain)
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	orderRows := func(status string) *pgxmock.Rows {
+		return pgxmock.NewRows([]string{"order_id", "position_id", "exchange", "symbol", "side", "type", "amount", "price", "status", "created_at", "updated_at"}).
+			AddRow("ord-1", "pos-1", "binance", "BTC/USDT", "BUY", "MARKET", decimal.NewFromInt(1), decimal.NewFromInt(100), status, now, now)
+	}
+	positionRows := func(status string) *pgxmock.Rows {
+		return pgxmock.NewRows([]string{"position_id", "order_id", "exchange", "symbol", "side", "size", "entry_price", "status", "opened_at", "updated_at"}).
+			AddRow("pos-1", "ord-1", "binance", "BTC/USDT", "BUY", decimal.NewFromInt(1), decimal.NewFromInt(100), status, now, now)
+	}
+
+	// Opening chains the stored order and position
+	mock.ExpectExec("INSERT INTO trading_orders").WithArgs(
+		pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+		pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+	).WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectExec("INSERT INTO trading_positions").WithArgs(
+		pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+		pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+	).WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectQuery("SELECT order_id, position_id").WithArgs(pgxmock.AnyArg()).WillReturnRows(orderRows("OPEN"))
+	mock.ExpectQuery("SELECT position_id, order_id").WithArgs(pgxmock.AnyArg()).WillReturnRows(positionRows("OPEN"))
+	_, err := h.PlaceExternalOrder(t.Context(), services.ExternalOrder{Exchange: "binance", Symbol: "BTC/USDT", Side: "buy", Amount: decimal.NewFromInt(1)})
+	require.NoError(t, err)
+	require.Equal(t, []string{services.AuditKindTradingOrder, services.AuditKindTradingPosition}, chain.kinds)
+
+	// Liquidating chains the new status of both
+	mock.ExpectQuery("SELECT position_id, order_id").WithArgs("pos-1").WillReturnRows(positionRows("OPEN"))
+	mock.ExpectExec("UPDATE trading_positions").WithArgs("pos-1", pgxmock.AnyArg()).WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec("UPDATE trading_orders").WithArgs("ord-1", pgxmock.AnyArg()).WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectQuery("SELECT order_id, position_id").WithArgs("ord-1").WillReturnRows(orderRows("CLOSED"))
+	mock.ExpectQuery("SELECT position_id, order_id").WithArgs("pos-1").WillReturnRows(positionRows("LIQUIDATED"))
+	_, err = h.liquidatePersistent(t.Context(), "pos-1", "")
+	require.NoError(t, err)
+	require.Len(t, chain.kinds, 4)
+	assert.Equal(t, []string{"ord-1", "pos-1"}, chain.refs[2:])
+	assert.Contains(t, chain.payloads[3], `"status":"LIQUIDATED"`)
+
+	// Verification reads the same form, and reports deleted records
+	mock.ExpectQuery("SELECT position_id, order_id").WithArgs("pos-1").WillReturnRows(positionRows("LIQUIDATED"))
+	payload, err := h.ChainPayload(t.Context(), services.AuditKindTradingPosition, "pos-1")
+	require.NoError(t, err)
+	assert.Equal(t, chain.payloads[3], string(payload))
+	mock.ExpectQuery("SELECT order_id, position_id").WithArgs("ord-2").WillReturnError(pgx.ErrNoRows)
+	_, err = h.ChainPayload(t.Context(), services.AuditKindTradingOrder, "ord-2")
+	assert.ErrorIs(t, err, services.ErrAuditRecordNotFound)
+}
+
 func TestTradingHandlerCancelOrder(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	h, mock := setupTradingHandlerWithMock(t)
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// TradingViewSecretHeader may carry the shared secret for senders that can set
+// headers; TradingView itself sends it in the alert body.
+const TradingViewSecretHeader = "X-Webhook-Secret"
+
+// ExternalSignalIngestor validates and ingests external alerts.
+type ExternalSignalIngestor interface {
+	VerifySecret(provided string) error
+	IngestTradingView(ctx context.Context, alert services.TradingViewAlert) (*services.ExternalSignalResult, error)
+}
+
+// TradingViewHandler receives TradingView webhook alerts.
+type TradingViewHandler struct {
+	signals ExternalSignalIngestor
+}
+
+// NewTradingViewHandler creates a new TradingView webhook handler.
+//
+// Parameters:
+//
+//	signals: The external signal service (may be nil).
+//
+// Returns:
+//
+//	*TradingViewHandler: The initialized handler.
+func NewTradingViewHandler(signals ExternalSignalIngestor) *TradingViewHandler {
+	return &TradingViewHandler{signals: signals}
+}
+
+// ReceiveAlert validates the shared secret, converts the alert into a signal and
+// reports whether it was executed under the external-signal strategy.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *TradingViewHandler) ReceiveAlert(c *gin.Context) {
+	if h.signals == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": services.ErrExternalSignalDisabled.Error()})
+		return
+	}
+
+	var alert services.TradingViewAlert
+	if err := c.ShouldBindJSON(&alert); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "invalid alert payload"})
+		return
+	}
+
+	secret := c.GetHeader(TradingViewSecretHeader)
+	if secret == "" {
+		secret = alert.Secret
+	}
+	if secret == "" {
+		secret = alert.Passphrase
+	}
+	if err := h.signals.VerifySecret(secret); err != nil {
+		if errors.Is(err, services.ErrExternalSignalDisabled) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+
+	result, err := h.signals.IngestTradingView(c.Request.Context(), alert)
+	if err != nil {
+		if errors.Is(err, services.ErrExternalSignalInvalid) {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": result})
+}
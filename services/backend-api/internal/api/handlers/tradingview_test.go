@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubExternalSignals struct {
+	alerts []services.TradingViewAlert
+}
+
+func (s *stubExternalSignals) VerifySecret(provided string) error {
+	if provided != "tv-secret" {
+		return services.ErrExternalSignalUnauthorized
+	}
+	return nil
+}
+
+func (s *stubExternalSignals) IngestTradingView(ctx context.Context, alert services.TradingViewAlert) (*services.ExternalSignalResult, error) {
+	if alert.Ticker == "" {
+		return nil, services.ErrExternalSignalInvalid
+	}
+	s.alerts = append(s.alerts, alert)
+	return &services.ExternalSignalResult{Executed: true, OrderID: "ord-1", Strategy: services.ExternalSignalStrategy}, nil
+}
+
+func TestTradingViewHandler_ReceiveAlert(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	signals := &stubExternalSignals{}
+	handler := NewTradingViewHandler(signals)
+
+	w := performTradingModeRequest(handler.ReceiveAlert, `{"secret":"tv-secret","ticker":"BTCUSDT","action":"buy","quantity":"0.1"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "external-signal")
+	assert.Len(t, signals.alerts, 1)
+
+	w = performTradingModeRequest(handler.ReceiveAlert, `{"passphrase":"tv-secret","ticker":"BTCUSDT","action":"sell"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = performTradingModeRequest(handler.ReceiveAlert, `{"secret":"nope","ticker":"BTCUSDT","action":"buy"}`, nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = performTradingModeRequest(handler.ReceiveAlert, `{"secret":"tv-secret","action":"buy"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(handler.ReceiveAlert, `not json`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Len(t, signals.alerts, 2)
+}
+
+func TestTradingViewHandler_Unavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewTradingViewHandler(nil)
+
+	w := performTradingModeRequest(handler.ReceiveAlert, `{}`, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
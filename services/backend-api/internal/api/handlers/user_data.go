@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// UserDataEraser removes a user's personal data.
+type UserDataEraser interface {
+	Erase(ctx context.Context, userID string, dryRun bool) (*services.UserDataReport, error)
+}
+
+// UserDataHandler serves data deletion requests.
+type UserDataHandler struct {
+	eraser UserDataEraser
+}
+
+// NewUserDataHandler creates a new user data handler.
+//
+// Parameters:
+//
+//	eraser: The user data service (may be nil without a database).
+//
+// Returns:
+//
+//	*UserDataHandler: The initialized handler.
+func NewUserDataHandler(eraser UserDataEraser) *UserDataHandler {
+	return &UserDataHandler{eraser: eraser}
+}
+
+// EraseUserData removes or anonymizes a user's Telegram binding,
+// notification logs, operator notes and personal identifiers, keeping
+// anonymized trading statistics. With dry_run=true it only reports what
+// would be removed.
+//
+// Parameters:
+//
+//	c: Gin context with the user ID in the "id" path parameter.
+func (h *UserDataHandler) EraseUserData(c *gin.Context) {
+	if h.eraser == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "user data erasure not available"})
+		return
+	}
+	userID := strings.TrimSpace(c.Param("id"))
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "user id is required"})
+		return
+	}
+	dryRun := false
+	if raw := c.Query("dry_run"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "dry_run must be true or false"})
+			return
+		}
+		dryRun = parsed
+	}
+
+	report, err := h.eraser.Erase(c.Request.Context(), userID, dryRun)
+	if errors.Is(err, services.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "error": "user not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": report})
+}
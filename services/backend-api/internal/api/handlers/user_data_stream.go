@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// UserDataStreamStatusProvider reports exchange user-data stream state.
+type UserDataStreamStatusProvider interface {
+	Status() []services.UserDataStreamStatus
+}
+
+// UserDataStreamHandler exposes the state of exchange user-data streams.
+type UserDataStreamHandler struct {
+	streams UserDataStreamStatusProvider
+}
+
+// NewUserDataStreamHandler creates a new user-data stream handler.
+//
+// Parameters:
+//
+//	streams: The stream client (may be nil when streaming is disabled).
+//
+// Returns:
+//
+//	*UserDataStreamHandler: The initialized handler.
+func NewUserDataStreamHandler(streams UserDataStreamStatusProvider) *UserDataStreamHandler {
+	return &UserDataStreamHandler{streams: streams}
+}
+
+// GetStreams returns whether each exchange's orders are streamed or polled,
+// with its latest streamed balance.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *UserDataStreamHandler) GetStreams(c *gin.Context) {
+	if h.streams == nil {
+		c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"enabled": false, "streams": []services.UserDataStreamStatus{}}})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"enabled": true, "streams": h.streams.Status()}})
+}
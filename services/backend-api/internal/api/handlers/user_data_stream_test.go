@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubUserDataStreams struct{}
+
+func (stubUserDataStreams) Status() []services.UserDataStreamStatus {
+	return []services.UserDataStreamStatus{{Exchange: "binance", Connected: true}, {Exchange: "kraken", Unsupported: true}}
+}
+
+func TestUserDataStreamHandler(t *testing.T) {
+	w := performTradingModeRequest(NewUserDataStreamHandler(stubUserDataStreams{}).GetStreams, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"enabled":true`)
+	assert.Contains(t, w.Body.String(), `"exchange":"kraken","connected":false,"unsupported":true`)
+
+	w = performTradingModeRequest(NewUserDataStreamHandler(nil).GetStreams, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"enabled":false`)
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubUserDataEraser struct {
+	dryRun bool
+	err    error
+}
+
+func (s *stubUserDataEraser) Erase(_ context.Context, userID string, dryRun bool) (*services.UserDataReport, error) {
+	s.dryRun = dryRun
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &services.UserDataReport{UserID: userID, DryRun: dryRun, Removed: 4}, nil
+}
+
+func performEraseUserData(handler *UserDataHandler, query string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodDelete, "/api/v1/users/u-1/data"+query, nil)
+	c.Params = gin.Params{{Key: "id", Value: "u-1"}}
+	handler.EraseUserData(c)
+	return w
+}
+
+func TestUserDataHandler_EraseUserData(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	eraser := &stubUserDataEraser{}
+	handler := NewUserDataHandler(eraser)
+
+	w := performEraseUserData(handler, "?dry_run=true")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, eraser.dryRun)
+	assert.Contains(t, w.Body.String(), `"dry_run":true`)
+
+	w = performEraseUserData(handler, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, eraser.dryRun)
+
+	assert.Equal(t, http.StatusBadRequest, performEraseUserData(handler, "?dry_run=maybe").Code)
+
+	eraser.err = services.ErrUserNotFound
+	assert.Equal(t, http.StatusNotFound, performEraseUserData(handler, "").Code)
+	eraser.err = errors.New("database down")
+	assert.Equal(t, http.StatusInternalServerError, performEraseUserData(handler, "").Code)
+	assert.Equal(t, http.StatusServiceUnavailable, performEraseUserData(NewUserDataHandler(nil), "").Code)
+}
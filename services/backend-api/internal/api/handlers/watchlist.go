@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// WatchlistManager defines the watchlist and symbol universe operations.
+type WatchlistManager interface {
+	Get(ctx context.Context, chatID string) (*services.Watchlist, error)
+	AddSymbols(ctx context.Context, chatID string, symbols []string) (*services.Watchlist, error)
+	RemoveSymbols(ctx context.Context, chatID string, symbols []string) (*services.Watchlist, error)
+	ExcludeSymbols(ctx context.Context, chatID string, symbols []string) (*services.Watchlist, error)
+	IncludeSymbols(ctx context.Context, chatID string, symbols []string) (*services.Watchlist, error)
+	Clear(ctx context.Context, chatID string) error
+	ChatSymbols(ctx context.Context, chatID string) ([]string, error)
+	ActiveSymbols(ctx context.Context) ([]string, error)
+	Universe(ctx context.Context) (*services.UniverseSnapshot, error)
+	RefreshUniverse(ctx context.Context) (*services.UniverseSnapshot, error)
+	SetExclusions(ctx context.Context, symbols []string) ([]string, error)
+}
+
+// WatchlistHandler manages per-chat watchlists and the generated symbol universe.
+type WatchlistHandler struct {
+	watchlists WatchlistManager
+}
+
+// UpdateWatchlistRequest changes a chat's watchlist.
+type UpdateWatchlistRequest struct {
+	ChatID string `json:"chat_id" binding:"required"`
+	// Action is one of add, remove, exclude, include or clear.
+	Action  string   `json:"action" binding:"required"`
+	Symbols []string `json:"symbols"`
+}
+
+// SetExclusionsRequest replaces the global exclusion list.
+type SetExclusionsRequest struct {
+	Symbols []string `json:"symbols"`
+}
+
+// NewWatchlistHandler creates a new watchlist handler.
+//
+// Parameters:
+//
+//	watchlists: The watchlist service (may be nil when Redis is unavailable).
+//
+// Returns:
+//
+//	*WatchlistHandler: The initialized handler.
+func NewWatchlistHandler(watchlists WatchlistManager) *WatchlistHandler {
+	return &WatchlistHandler{watchlists: watchlists}
+}
+
+func (h *WatchlistHandler) available(c *gin.Context) bool {
+	if h.watchlists == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "watchlist service not available"})
+		return false
+	}
+	return true
+}
+
+func (h *WatchlistHandler) respondWatchlist(c *gin.Context, chatID string) {
+	ctx := c.Request.Context()
+	watchlist, err := h.watchlists.Get(ctx, chatID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	active, err := h.watchlists.ChatSymbols(ctx, chatID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{
+		"watchlist":      watchlist,
+		"active_symbols": active,
+		"uses_universe":  len(watchlist.Symbols) == 0,
+	}})
+}
+
+// GetWatchlist returns a chat's watchlist and the symbols it currently trades.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *WatchlistHandler) GetWatchlist(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	chatID := c.Query("chat_id")
+	if chatID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "chat_id is required"})
+		return
+	}
+	h.respondWatchlist(c, chatID)
+}
+
+// UpdateWatchlist adds, removes, excludes or includes symbols, or clears a chat's watchlist.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *WatchlistHandler) UpdateWatchlist(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req UpdateWatchlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "Invalid request body"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if req.Action == "clear" {
+		if err := h.watchlists.Clear(ctx, req.ChatID); err != nil {
+			writeWatchlistError(c, err)
+			return
+		}
+		h.respondWatchlist(c, req.ChatID)
+		return
+	}
+
+	updates := map[string]func(context.Context, string, []string) (*services.Watchlist, error){
+		"add":     h.watchlists.AddSymbols,
+		"remove":  h.watchlists.RemoveSymbols,
+		"exclude": h.watchlists.ExcludeSymbols,
+		"include": h.watchlists.IncludeSymbols,
+	}
+	update, ok := updates[req.Action]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "action must be add, remove, exclude, include or clear"})
+		return
+	}
+	if len(req.Symbols) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "symbols are required"})
+		return
+	}
+	if _, err := update(ctx, req.ChatID, req.Symbols); err != nil {
+		writeWatchlistError(c, err)
+		return
+	}
+	h.respondWatchlist(c, req.ChatID)
+}
+
+// GetUniverse returns the generated universe, the global exclusions and the
+// symbols currently processed across all chats.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *WatchlistHandler) GetUniverse(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	ctx := c.Request.Context()
+	universe, err := h.watchlists.Universe(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	active, err := h.watchlists.ActiveSymbols(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{
+		"universe":       universe,
+		"active_symbols": active,
+	}})
+}
+
+// RefreshUniverse regenerates the top-by-volume universe now.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *WatchlistHandler) RefreshUniverse(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	universe, err := h.watchlists.RefreshUniverse(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": universe})
+}
+
+// SetExclusions replaces the global exclusion list.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *WatchlistHandler) SetExclusions(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req SetExclusionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "Invalid request body"})
+		return
+	}
+	exclusions, err := h.watchlists.SetExclusions(c.Request.Context(), req.Symbols)
+	if err != nil {
+		writeWatchlistError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{"excluded": exclusions}})
+}
+
+func writeWatchlistError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, services.ErrWatchlistInvalidSymbol) || errors.Is(err, services.ErrWatchlistFull) {
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{"status": "error", "error": err.Error()})
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestWatchlistHandler(t *testing.T) *WatchlistHandler {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewWatchlistHandler(services.NewWatchlistService(nil, client, services.WatchlistConfig{MaxSymbols: 2}))
+}
+
+func TestWatchlistHandler_UpdateAndGet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := newTestWatchlistHandler(t)
+
+	w := performTradingModeRequest(handler.UpdateWatchlist, `{"chat_id":"42","action":"add","symbols":["btc/usdt","ETH/USDT"]}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"active_symbols":["BTC/USDT","ETH/USDT"]`)
+
+	w = performTradingModeRequest(handler.UpdateWatchlist, `{"chat_id":"42","action":"add","symbols":["SOL/USDT"]}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(handler.UpdateWatchlist, `{"chat_id":"42","action":"exclude","symbols":["ETH/USDT"]}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/telegram/internal/watchlist?chat_id=42", nil)
+	handler.GetWatchlist(c)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"excluded":["ETH/USDT"]`)
+	assert.Contains(t, rec.Body.String(), `"active_symbols":["BTC/USDT"]`)
+
+	w = performTradingModeRequest(handler.UpdateWatchlist, `{"chat_id":"42","action":"clear"}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"uses_universe":true`)
+}
+
+func TestWatchlistHandler_InvalidRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := newTestWatchlistHandler(t)
+
+	w := performTradingModeRequest(handler.UpdateWatchlist, `{"chat_id":"42","action":"rename","symbols":["BTC/USDT"]}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(handler.UpdateWatchlist, `{"chat_id":"42","action":"add"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(handler.UpdateWatchlist, `{"chat_id":"42","action":"add","symbols":["/"]}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(handler.SetExclusions, `{"symbols":["LUNA/USDT"]}`, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "LUNA/USDT")
+
+	unavailable := NewWatchlistHandler(nil)
+	w = performTradingModeRequest(unavailable.GetUniverse, ``, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
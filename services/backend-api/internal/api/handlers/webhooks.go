@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// WebhookManager defines the outbound webhook operations exposed to operators.
+type WebhookManager interface {
+	CreateEndpoint(ctx context.Context, endpoint services.WebhookEndpoint) (*services.WebhookEndpoint, error)
+	ListEndpoints(ctx context.Context) ([]services.WebhookEndpoint, error)
+	DeleteEndpoint(ctx context.Context, id string) error
+	SendTest(ctx context.Context, id string) error
+}
+
+// WebhookHandler manages outbound webhook endpoints.
+type WebhookHandler struct {
+	webhooks WebhookManager
+}
+
+// NewWebhookHandler creates a new webhook handler.
+//
+// Parameters:
+//
+//	webhooks: The webhook service (may be nil when Redis is unavailable).
+//
+// Returns:
+//
+//	*WebhookHandler: The initialized handler.
+func NewWebhookHandler(webhooks WebhookManager) *WebhookHandler {
+	return &WebhookHandler{webhooks: webhooks}
+}
+
+// CreateWebhookRequest is the body for registering an outbound webhook.
+type CreateWebhookRequest struct {
+	// Kind is generic (default), github or linear. GitHub and Linear
+	// endpoints comment on the issue at URL using Secret as the API token.
+	Kind        services.WebhookKind        `json:"kind,omitempty"`
+	URL         string                      `json:"url" binding:"required"`
+	Secret      string                      `json:"secret,omitempty"`
+	Events      []services.WebhookEventType `json:"events,omitempty"`
+	Description string                      `json:"description,omitempty"`
+}
+
+func (h *WebhookHandler) available(c *gin.Context) bool {
+	if h.webhooks == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "error": "webhook service not available"})
+		return false
+	}
+	return true
+}
+
+// CreateWebhook registers an endpoint. The response contains the signing secret,
+// which is not returned again.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "url is required"})
+		return
+	}
+	endpoint, err := h.webhooks.CreateEndpoint(c.Request.Context(), services.WebhookEndpoint{
+		Kind:        req.Kind,
+		URL:         req.URL,
+		Secret:      req.Secret,
+		Events:      req.Events,
+		Description: req.Description,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrWebhookInvalidURL) || errors.Is(err, services.ErrWebhookBadEvent) ||
+			errors.Is(err, services.ErrWebhookBadKind) || errors.Is(err, services.ErrWebhookNoToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"status": "success", "data": endpoint})
+}
+
+// ListWebhooks returns configured endpoints without their secrets.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	endpoints, err := h.webhooks.ListEndpoints(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{
+		"webhooks": endpoints,
+		"events":   services.WebhookEventTypes,
+	}})
+}
+
+// DeleteWebhook removes an endpoint.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	if err := h.webhooks.DeleteEndpoint(c.Request.Context(), c.Param("id")); err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Webhook deleted"})
+}
+
+// TestWebhook sends a webhook.test event and reports whether delivery succeeded.
+//
+// Parameters:
+//
+//	c: Gin context.
+func (h *WebhookHandler) TestWebhook(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+	if err := h.webhooks.SendTest(c.Request.Context(), c.Param("id")); err != nil {
+		if errors.Is(err, services.ErrWebhookNotFound) {
+			writeWebhookError(c, err)
+			return
+		}
+		c.JSON(http.StatusBadGateway, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success", "message": "Test event delivered"})
+}
+
+func writeWebhookError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrWebhookNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+}
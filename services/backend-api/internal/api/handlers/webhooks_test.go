@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubWebhooks struct {
+	sendErr error
+}
+
+func (s *stubWebhooks) CreateEndpoint(ctx context.Context, endpoint services.WebhookEndpoint) (*services.WebhookEndpoint, error) {
+	if endpoint.URL == "not-a-url" {
+		return nil, services.ErrWebhookInvalidURL
+	}
+	if endpoint.Kind == services.WebhookKindGitHub && endpoint.Secret == "" {
+		return nil, services.ErrWebhookNoToken
+	}
+	endpoint.ID = "wh-1"
+	endpoint.Secret = "generated"
+	return &endpoint, nil
+}
+
+func (s *stubWebhooks) ListEndpoints(ctx context.Context) ([]services.WebhookEndpoint, error) {
+	return []services.WebhookEndpoint{{ID: "wh-1", URL: "https://example.com"}}, nil
+}
+
+func (s *stubWebhooks) DeleteEndpoint(ctx context.Context, id string) error {
+	if id != "wh-1" {
+		return services.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (s *stubWebhooks) SendTest(ctx context.Context, id string) error {
+	return s.sendErr
+}
+
+func TestWebhookHandler_CreateWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewWebhookHandler(&stubWebhooks{})
+
+	w := performTradingModeRequest(handler.CreateWebhook, `{"url":"https://example.com","events":["trade.executed"]}`, nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), "generated")
+
+	w = performTradingModeRequest(handler.CreateWebhook, `{"url":"not-a-url"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performTradingModeRequest(handler.CreateWebhook, `{"kind":"github","url":"https://github.com/acme/desk/issues/1"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "API token")
+
+	w = performTradingModeRequest(handler.CreateWebhook, `{"kind":"github","url":"https://github.com/acme/desk/issues/1","secret":"ghp_x"}`, nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"kind":"github"`)
+
+	w = performTradingModeRequest(handler.CreateWebhook, `{}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestWebhookHandler_DeleteAndTest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewWebhookHandler(&stubWebhooks{})
+
+	w := performTradingModeRequest(handler.DeleteWebhook, "", gin.Params{{Key: "id", Value: "wh-1"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = performTradingModeRequest(handler.DeleteWebhook, "", gin.Params{{Key: "id", Value: "wh-2"}})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = performTradingModeRequest(handler.TestWebhook, "", gin.Params{{Key: "id", Value: "wh-1"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	handler = NewWebhookHandler(&stubWebhooks{sendErr: errors.New("connection refused")})
+	w = performTradingModeRequest(handler.TestWebhook, "", gin.Params{{Key: "id", Value: "wh-1"}})
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+}
+
+func TestWebhookHandler_Unavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewWebhookHandler(nil)
+
+	w := performTradingModeRequest(handler.ListWebhooks, "", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
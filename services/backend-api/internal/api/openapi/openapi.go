@@ -0,0 +1,450 @@
+// Package openapi builds an OpenAPI 3 document for the HTTP API from the
+// registered Gin routes and the Go request/response types of documented
+// operations. The document is served at /api/v1/openapi.json and is the
+// source for the CLI's generated API client.
+package openapi
+
+import (
+	"encoding"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Version is the OpenAPI specification version emitted.
+const Version = "3.0.3"
+
+// Document is the root OpenAPI 3 object.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// Info holds API metadata.
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Components holds reusable schemas.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// PathItem holds the operations available on a path.
+type PathItem struct {
+	Get    *OperationObject `json:"get,omitempty"`
+	Post   *OperationObject `json:"post,omitempty"`
+	Put    *OperationObject `json:"put,omitempty"`
+	Patch  *OperationObject `json:"patch,omitempty"`
+	Delete *OperationObject `json:"delete,omitempty"`
+}
+
+// OperationObject describes a single API operation.
+type OperationObject struct {
+	OperationID string               `json:"operationId,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter describes a path or query parameter.
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody describes a JSON request body.
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes a response.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType wraps a schema for a content type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is the subset of JSON Schema used by the generator.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+}
+
+// Param declares a path or query parameter of an operation.
+type Param struct {
+	Name     string
+	In       string
+	Required bool
+}
+
+// Operation documents a route with its request and response types.
+type Operation struct {
+	Method      string
+	Path        string
+	OperationID string
+	Summary     string
+	Tags        []string
+	Params      []Param
+	// Request is a zero value of the JSON body type, or nil.
+	Request any
+	// Response is a zero value of the success body type, or nil.
+	Response any
+	// Envelope wraps Response in the {"status": ..., "data": ...} shape.
+	Envelope bool
+}
+
+// Registry collects documented operations.
+type Registry struct {
+	mu         sync.RWMutex
+	title      string
+	version    string
+	operations map[string]Operation
+}
+
+// NewRegistry creates an empty registry.
+//
+// Parameters:
+//
+//	title: API title.
+//	version: API version.
+//
+// Returns:
+//
+//	*Registry: Initialized registry.
+func NewRegistry(title, version string) *Registry {
+	return &Registry{
+		title:      title,
+		version:    version,
+		operations: make(map[string]Operation),
+	}
+}
+
+// Register documents an operation. Paths use Gin syntax (":id").
+func (r *Registry) Register(op Operation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.operations[operationKey(op.Method, op.Path)] = op
+}
+
+func operationKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+var ginParamPattern = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+// toOpenAPIPath converts Gin path syntax to OpenAPI templating.
+func toOpenAPIPath(path string) string {
+	return ginParamPattern.ReplaceAllString(path, "{$1}")
+}
+
+// Build assembles the document. Every route is listed; routes without a
+// registered operation get a generic JSON response. Registered operations
+// are included even when routes is nil, so the document can be produced
+// without a running router.
+//
+// Parameters:
+//
+//	routes: Routes from gin.Engine.Routes(), or nil.
+//
+// Returns:
+//
+//	*Document: The OpenAPI document.
+func (r *Registry) Build(routes gin.RoutesInfo) *Document {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	doc := &Document{
+		OpenAPI:    Version,
+		Info:       Info{Title: r.title, Version: r.version},
+		Paths:      make(map[string]*PathItem),
+		Components: Components{Schemas: make(map[string]*Schema)},
+	}
+	gen := &schemaGenerator{components: doc.Components.Schemas}
+
+	keys := make([]string, 0, len(r.operations))
+	for key := range r.operations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		op := r.operations[key]
+		addOperation(doc, op.Method, op.Path, buildOperation(gen, op))
+	}
+
+	for _, route := range routes {
+		if _, documented := r.operations[operationKey(route.Method, route.Path)]; documented {
+			continue
+		}
+		addOperation(doc, route.Method, route.Path, &OperationObject{
+			Parameters: pathParameters(route.Path, nil),
+			Responses: map[string]*Response{
+				"200": {Description: "Success", Content: jsonContent(&Schema{Type: "object"})},
+			},
+		})
+	}
+
+	return doc
+}
+
+func addOperation(doc *Document, method, path string, op *OperationObject) {
+	apiPath := toOpenAPIPath(path)
+	item := doc.Paths[apiPath]
+	if item == nil {
+		item = &PathItem{}
+	}
+	switch strings.ToUpper(method) {
+	case "GET":
+		item.Get = op
+	case "POST":
+		item.Post = op
+	case "PUT":
+		item.Put = op
+	case "PATCH":
+		item.Patch = op
+	case "DELETE":
+		item.Delete = op
+	default:
+		// HEAD/OPTIONS mirror other operations and are not documented.
+		return
+	}
+	doc.Paths[apiPath] = item
+}
+
+func buildOperation(gen *schemaGenerator, op Operation) *OperationObject {
+	result := &OperationObject{
+		OperationID: op.OperationID,
+		Summary:     op.Summary,
+		Tags:        op.Tags,
+		Parameters:  pathParameters(op.Path, op.Params),
+		Responses:   map[string]*Response{},
+	}
+	if op.Request != nil {
+		result.RequestBody = &RequestBody{
+			Required: true,
+			Content:  jsonContent(gen.schemaFor(reflect.TypeOf(op.Request))),
+		}
+	}
+	success := &Response{Description: "Success"}
+	if op.Response != nil {
+		schema := gen.schemaFor(reflect.TypeOf(op.Response))
+		if op.Envelope {
+			schema = gen.envelope(reflect.TypeOf(op.Response), schema)
+		}
+		success.Content = jsonContent(schema)
+	}
+	result.Responses["200"] = success
+	return result
+}
+
+// pathParameters lists path params from the template followed by declared params.
+func pathParameters(path string, declared []Param) []Parameter {
+	var params []Parameter
+	seen := map[string]bool{}
+	for _, match := range ginParamPattern.FindAllStringSubmatch(path, -1) {
+		params = append(params, Parameter{Name: match[1], In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		seen[match[1]] = true
+	}
+	for _, p := range declared {
+		if seen[p.Name] {
+			continue
+		}
+		in := p.In
+		if in == "" {
+			in = "query"
+		}
+		params = append(params, Parameter{Name: p.Name, In: in, Required: p.Required, Schema: &Schema{Type: "string"}})
+	}
+	return params
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+type schemaGenerator struct {
+	components map[string]*Schema
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	rawJSONType = reflect.TypeOf([]byte(nil))
+
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// schemaFor returns the schema for a Go type. Named structs are placed in
+// components and referenced.
+func (g *schemaGenerator) schemaFor(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		// decimal.Decimal, uuid.UUID and similar types serialize as strings.
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t == rawJSONType {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := t.Name()
+		if _, exists := g.components[name]; !exists {
+			// Reserve the name first so recursive types terminate.
+			g.components[name] = &Schema{Type: "object"}
+			g.components[name] = g.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	default:
+		return &Schema{}
+	}
+}
+
+// envelope registers "<Name>Envelope" wrapping data in the standard response shape.
+func (g *schemaGenerator) envelope(t reflect.Type, data *Schema) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	wrapper := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"status": {Type: "string"},
+			"data":   data,
+		},
+		Required: []string{"data", "status"},
+	}
+	if t.Name() == "" {
+		return wrapper
+	}
+	name := t.Name() + "Envelope"
+	g.components[name] = wrapper
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	g.addFields(schema, t)
+	sort.Strings(schema.Required)
+	return schema
+}
+
+func (g *schemaGenerator) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitEmpty, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			// Like encoding/json, promote fields of embedded structs even when
+			// the embedded type itself is unexported.
+			embedded := field.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.addFields(schema, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := g.schemaFor(field.Type)
+		if field.Type.Kind() == reflect.Pointer && prop.Ref == "" {
+			prop.Nullable = true
+		}
+		schema.Properties[name] = prop
+		if !omitEmpty && field.Type.Kind() != reflect.Pointer {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+func jsonFieldName(field reflect.StructField) (name string, omitEmpty bool, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" || opt == "omitzero" {
+			omitEmpty = true
+		}
+	}
+	return parts[0], omitEmpty, false
+}
+
+// Handler serves the document for the engine's routes. The document is built
+// on first request, after all routes have been registered.
+//
+// Parameters:
+//
+//	reg: Registry of documented operations.
+//	engine: Engine whose routes are listed.
+//
+// Returns:
+//
+//	gin.HandlerFunc: Handler writing the document as JSON.
+func Handler(reg *Registry, engine *gin.Engine) gin.HandlerFunc {
+	var once sync.Once
+	var doc *Document
+	return func(c *gin.Context) {
+		once.Do(func() {
+			doc = reg.Build(engine.Routes())
+		})
+		c.JSON(http.StatusOK, doc)
+	}
+}
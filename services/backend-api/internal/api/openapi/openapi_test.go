@@ -0,0 +1,90 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testBase struct {
+	ID string `json:"id"`
+}
+
+type testItem struct {
+	testBase
+	Name      string            `json:"name"`
+	Price     decimal.Decimal   `json:"price"`
+	Note      *string           `json:"note,omitempty"`
+	Tags      []string          `json:"tags,omitempty"`
+	Labels    map[string]string `json:"labels"`
+	Parent    *testItem         `json:"parent,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	internal  string
+	Ignored   string `json:"-"`
+}
+
+func TestRegistry_BuildSchemas(t *testing.T) {
+	reg := NewRegistry("Test API", "1.0")
+	reg.Register(Operation{
+		Method:      "POST",
+		Path:        "/items/:id",
+		OperationID: "UpdateItem",
+		Request:     testItem{},
+		Response:    testItem{},
+		Envelope:    true,
+	})
+
+	doc := reg.Build(nil)
+	assert.Equal(t, Version, doc.OpenAPI)
+
+	item := doc.Components.Schemas["testItem"]
+	require.NotNil(t, item)
+	assert.Equal(t, "string", item.Properties["id"].Type, "embedded fields are flattened")
+	assert.Equal(t, "string", item.Properties["price"].Type)
+	assert.True(t, item.Properties["note"].Nullable)
+	assert.Equal(t, "array", item.Properties["tags"].Type)
+	assert.Equal(t, "string", item.Properties["labels"].AdditionalProperties.Type)
+	assert.Equal(t, "#/components/schemas/testItem", item.Properties["parent"].Ref)
+	assert.Equal(t, "date-time", item.Properties["created_at"].Format)
+	assert.NotContains(t, item.Properties, "internal")
+	assert.NotContains(t, item.Properties, "Ignored")
+	assert.Equal(t, []string{"created_at", "id", "labels", "name", "price"}, item.Required)
+
+	op := doc.Paths["/items/{id}"].Post
+	require.NotNil(t, op)
+	assert.Equal(t, "UpdateItem", op.OperationID)
+	assert.Equal(t, []Parameter{{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string"}}}, op.Parameters)
+	assert.Equal(t, "#/components/schemas/testItemEnvelope", op.Responses["200"].Content["application/json"].Schema.Ref)
+	assert.Contains(t, doc.Components.Schemas["testItemEnvelope"].Properties, "data")
+}
+
+func TestHandler_ListsUndocumentedRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	reg := NewRegistry("Test API", "1.0")
+	reg.Register(Operation{Method: "GET", Path: "/documented", OperationID: "GetDocumented"})
+
+	noop := func(c *gin.Context) {}
+	router.GET("/documented", noop)
+	router.GET("/things/:id/*path", noop)
+	router.HEAD("/things/:id/*path", noop)
+	router.GET("/openapi.json", Handler(reg, router))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var doc Document
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, "GetDocumented", doc.Paths["/documented"].Get.OperationID)
+	require.Contains(t, doc.Paths, "/things/{id}/{path}")
+	assert.Len(t, doc.Paths["/things/{id}/{path}"].Get.Parameters, 2)
+	assert.Contains(t, doc.Paths, "/openapi.json")
+}
@@ -0,0 +1,482 @@
+package api
+
+import (
+	"github.com/irfndi/neuratrade/internal/api/handlers"
+	"github.com/irfndi/neuratrade/internal/api/openapi"
+	"github.com/irfndi/neuratrade/internal/chaos"
+	"github.com/irfndi/neuratrade/internal/services"
+)
+
+// NewOpenAPIRegistry returns the registry of documented operations. Routes
+// that are not registered here still appear in the served document with a
+// generic response; operations with an OperationID become methods on the
+// CLI's generated client (cmd/neuratrade-cli/api_client_gen.go).
+//
+// Returns:
+//
+//	*openapi.Registry: Registry with all documented operations.
+func NewOpenAPIRegistry() *openapi.Registry {
+	reg := openapi.NewRegistry("NeuraTrade API", appVersion())
+
+	reg.Register(openapi.Operation{
+		Method:      "GET",
+		Path:        "/health",
+		OperationID: "GetHealth",
+		Summary:     "Service health and dependency status",
+		Tags:        []string{"health"},
+		Response:    handlers.HealthResponse{},
+	})
+
+	reg.Register(openapi.Operation{
+		Method:      "GET",
+		Path:        "/api/v1/compat",
+		OperationID: "GetCompat",
+		Summary:     "Server version and minimum supported client versions",
+		Tags:        []string{"health"},
+		Response:    handlers.CompatResponse{},
+	})
+
+	reg.Register(openapi.Operation{
+		Method:      "GET",
+		Path:        "/api/v1/market/bulk",
+		OperationID: "GetMarketBulk",
+		Summary:     "Selected ticker fields for many symbols in one call",
+		Tags:        []string{"market"},
+		Params: []openapi.Param{
+			{Name: "symbols", In: "query", Required: true},
+			{Name: "exchanges", In: "query"},
+			{Name: "fields", In: "query"},
+		},
+		Response: handlers.BulkMarketResponse{},
+	})
+	reg.Register(openapi.Operation{
+		Method:      "GET",
+		Path:        "/api/v1/arbitrage/carry",
+		OperationID: "GetCarryAnalytics",
+		Summary:     "Historical funding and spot-perp basis statistics with annualized carry per symbol and exchange",
+		Tags:        []string{"arbitrage"},
+		Params: []openapi.Param{
+			{Name: "symbol", In: "query"},
+			{Name: "exchange", In: "query"},
+			{Name: "days", In: "query"},
+			{Name: "limit", In: "query"},
+		},
+		Response: services.CarryReport{},
+		Envelope: true,
+	})
+
+	reg.Register(openapi.Operation{
+		Method:      "POST",
+		Path:        "/api/v1/telegram/internal/autonomous/begin",
+		OperationID: "BeginAutonomous",
+		Summary:     "Start autonomous mode for a chat after readiness checks",
+		Tags:        []string{"autonomous"},
+		Request:     handlers.AutonomousStateRequest{},
+		Response:    handlers.AutonomousStateResponse{},
+	})
+	reg.Register(openapi.Operation{
+		Method:      "POST",
+		Path:        "/api/v1/telegram/internal/autonomous/pause",
+		OperationID: "PauseAutonomous",
+		Summary:     "Pause autonomous mode for a chat",
+		Tags:        []string{"autonomous"},
+		Request:     handlers.AutonomousStateRequest{},
+		Response:    handlers.AutonomousStateResponse{},
+	})
+
+	reg.Register(openapi.Operation{
+		Method:      "GET",
+		Path:        "/api/v1/telegram/internal/equity",
+		OperationID: "GetEquity",
+		Summary:     "Account equity: valued exchange balances, open-position PnL and, with a chat_id, its external wallets",
+		Tags:        []string{"autonomous"},
+		Params:      []openapi.Param{{Name: "chat_id", In: "query"}},
+		Response:    services.EquitySnapshot{},
+		Envelope:    true,
+	})
+
+	reg.Register(openapi.Operation{
+		Method:      "GET",
+		Path:        "/api/v1/telegram/internal/profiles",
+		OperationID: "ListOperatingProfiles",
+		Summary:     "List the built-in and custom operating profiles of a chat and the selected one",
+		Tags:        []string{"autonomous"},
+		Params:      []openapi.Param{{Name: "chat_id", In: "query", Required: true}},
+		Response:    services.ProfileList{},
+		Envelope:    true,
+	})
+	reg.Register(openapi.Operation{
+		Method:      "POST",
+		Path:        "/api/v1/telegram/internal/profiles",
+		OperationID: "SaveOperatingProfile",
+		Summary:     "Create or replace a custom operating profile, starting from a base profile",
+		Tags:        []string{"autonomous"},
+		Request:     handlers.SaveProfileRequest{},
+		Response:    services.OperatingProfile{},
+		Envelope:    true,
+	})
+	reg.Register(openapi.Operation{
+		Method:      "POST",
+		Path:        "/api/v1/telegram/internal/profiles/select",
+		OperationID: "SelectOperatingProfile",
+		Summary:     "Select the operating profile a chat trades with",
+		Tags:        []string{"autonomous"},
+		Request:     handlers.SelectProfileRequest{},
+		Response:    services.OperatingProfile{},
+		Envelope:    true,
+	})
+	reg.Register(openapi.Operation{
+		Method:      "DELETE",
+		Path:        "/api/v1/telegram/internal/profiles/:name",
+		OperationID: "DeleteOperatingProfile",
+		Summary:     "Delete a custom operating profile",
+		Tags:        []string{"autonomous"},
+		Params:      []openapi.Param{{Name: "chat_id", In: "query", Required: true}},
+		Response:    handlers.ProfileChangeResponse{},
+		Envelope:    true,
+	})
+	reg.Register(openapi.Operation{
+		Method:      "GET",
+		Path:        "/api/v1/telegram/internal/strategies",
+		OperationID: "ListStrategyConfigs",
+		Summary:     "List the strategy configurations installed for a chat",
+		Tags:        []string{"strategies"},
+		Params:      []openapi.Param{{Name: "chat_id", In: "query", Required: true}},
+		Response:    handlers.StrategyListResponse{},
+		Envelope:    true,
+	})
+	reg.Register(openapi.Operation{
+		Method:      "POST",
+		Path:        "/api/v1/telegram/internal/strategies",
+		OperationID: "InstallStrategyConfig",
+		Summary:     "Validate a shared YAML or JSON strategy configuration and install it for a chat",
+		Tags:        []string{"strategies"},
+		Request:     handlers.InstallStrategyRequest{},
+		Response:    handlers.InstallStrategyResponse{},
+		Envelope:    true,
+	})
+	reg.Register(openapi.Operation{
+		Method:      "GET",
+		Path:        "/api/v1/telegram/internal/strategies/schema",
+		OperationID: "GetStrategyConfigSchema",
+		Summary:     "Return the JSON Schema of the strategy configuration format",
+		Tags:        []string{"strategies"},
+		Response:    map[string]interface{}{},
+	})
+	reg.Register(openapi.Operation{
+		Method:      "POST",
+		Path:        "/api/v1/telegram/internal/strategies/validate",
+		OperationID: "ValidateStrategyConfig",
+		Summary:     "Check a strategy configuration for errors, secrets and missing skills without installing it",
+		Tags:        []string{"strategies"},
+		Request:     handlers.ValidateStrategyRequest{},
+		Response:    services.StrategyValidation{},
+		Envelope:    true,
+	})
+	reg.Register(openapi.Operation{
+		Method:      "GET",
+		Path:        "/api/v1/telegram/internal/strategies/:name/export",
+		OperationID: "ExportStrategyConfig",
+		Summary:     "Export an installed strategy or operating profile as a shareable configuration",
+		Tags:        []string{"strategies"},
+		Params: []openapi.Param{
+			{Name: "chat_id", In: "query", Required: true},
+			{Name: "format", In: "query"},
+		},
+		Response: services.StrategyExport{},
+		Envelope: true,
+	})
+	reg.Register(openapi.Operation{
+		Method:      "DELETE",
+		Path:        "/api/v1/telegram/internal/strategies/:name",
+		OperationID: "UninstallStrategyConfig",
+		Summary:     "Uninstall a strategy configuration and its operating profile",
+		Tags:        []string{"strategies"},
+		Params:      []openapi.Param{{Name: "chat_id", In: "query", Required: true}},
+		Response:    handlers.StrategyChangeResponse{},
+		Envelope:    true,
+	})
+
+	reg.Register(openapi.Operation{
+		Method:      "GET",
+		Path:        "/api/v1/ai/models",
+		OperationID: "GetAIModels",
+		Summary:     "List active AI models",
+		Tags:        []string{"ai"},
+		Params:      []openapi.Param{{Name: "provider", In: "query"}},
+		Response:    handlers.AIModelsResponse{},
+	})
+
+	reg.Register(openapi.Operation{
+		Method:      "POST",
+		Path:        "/api/v1/auth/login",
+		OperationID: "Login",
+		Summary:     "Exchange an API key or Telegram login code for session tokens",
+		Tags:        []string{"auth"},
+		Request:     handlers.SessionLoginRequest{},
+		Response:    handlers.SessionTokenResponse{},
+		Envelope:    true,
+	})
+	reg.Register(openapi.Operation{
+		Method:   "POST",
+		Path:     "/api/v1/auth/refresh",
+		Summary:  "Rotate a refresh token into a new token pair",
+		Tags:     []string{"auth"},
+		Response: handlers.SessionTokenResponse{},
+		Envelope: true,
+	})
+
+	reg.Register(openapi.Operation{
+		Method:      "GET",
+		Path:        "/api/v1/search",
+		OperationID: "Search",
+		Summary:     "Ranked search across trades, signals, quests and exchange error logs",
+		Tags:        []string{"search"},
+		Params: []openapi.Param{
+			{Name: "q", In: "query", Required: true},
+			{Name: "scope", In: "query"},
+			{Name: "limit", In: "query"},
+		},
+		Response: services.SearchResponse{},
+		Envelope: true,
+	})
+
+	reg.Register(openapi.Operation{
+		Method:      "GET",
+		Path:        "/api/v1/telegram/internal/alerts/custom",
+		OperationID: "ListCustomAlerts",
+		Summary:     "User-defined price and indicator alerts of a chat",
+		Tags:        []string{"alerts"},
+		Params:      []openapi.Param{{Name: "chat_id", In: "query", Required: true}},
+		Response:    handlers.CustomAlertListResponse{},
+		Envelope:    true,
+	})
+
+	reg.Register(openapi.Operation{
+		Method:      "POST",
+		Path:        "/api/v1/telegram/internal/alerts/custom",
+		OperationID: "CreateCustomAlert",
+		Summary:     "Create an alert from a rule such as \"BTC/USDT RSI(14,1h) < 30\"",
+		Tags:        []string{"alerts"},
+		Request:     handlers.CreateCustomAlertRequest{},
+		Response:    services.CustomAlert{},
+		Envelope:    true,
+	})
+
+	reg.Register(openapi.Operation{
+		Method:      "POST",
+		Path:        "/api/v1/telegram/internal/alerts/custom/preview",
+		OperationID: "PreviewCustomAlert",
+		Summary:     "How often a rule would have fired over the last 30 days, without saving it",
+		Tags:        []string{"alerts"},
+		Request:     handlers.PreviewCustomAlertRequest{},
+		Response:    services.AlertPreview{},
+		Envelope:    true,
+	})
+
+	reg.Register(openapi.Operation{
+		Method:      "PUT",
+		Path:        "/api/v1/telegram/internal/alerts/custom/:id",
+		OperationID: "UpdateCustomAlert",
+		Summary:     "Pause or resume a custom alert",
+		Tags:        []string{"alerts"},
+		Request:     handlers.UpdateCustomAlertRequest{},
+		Response:    handlers.CustomAlertChangeResponse{},
+		Envelope:    true,
+	})
+
+	reg.Register(openapi.Operation{
+		Method:      "DELETE",
+		Path:        "/api/v1/telegram/internal/alerts/custom/:id",
+		OperationID: "DeleteCustomAlert",
+		Summary:     "Delete a custom alert",
+		Tags:        []string{"alerts"},
+		Params:      []openapi.Param{{Name: "chat_id", In: "query", Required: true}},
+		Response:    handlers.CustomAlertChangeResponse{},
+		Envelope:    true,
+	})
+
+	reg.Register(openapi.Operation{
+		Method:   "GET",
+		Path:     "/api/v1/admin/trading-mode",
+		Summary:  "Current execution mode and kill-switch state",
+		Tags:     []string{"admin"},
+		Response: services.TradingModeState{},
+		Envelope: true,
+	})
+	reg.Register(openapi.Operation{
+		Method:   "GET",
+		Path:     "/api/v1/admin/trading-mode/go-live",
+		Summary:  "Latest scheduled go-live and whether it is pending, activated or cancelled",
+		Tags:     []string{"admin"},
+		Response: services.GoLiveSchedule{},
+		Envelope: true,
+	})
+
+	reg.Register(openapi.Operation{
+		Method:      "GET",
+		Path:        "/api/v1/setup/state",
+		OperationID: "GetSetupState",
+		Summary:     "First-run setup wizard progress with the next step to complete",
+		Tags:        []string{"setup"},
+		Response:    services.SetupState{},
+		Envelope:    true,
+	})
+	reg.Register(openapi.Operation{
+		Method:      "PUT",
+		Path:        "/api/v1/setup/state/:step",
+		OperationID: "UpdateSetupStep",
+		Summary:     "Mark a setup step done, skipped or pending",
+		Tags:        []string{"setup"},
+		Request:     handlers.UpdateSetupStepRequest{},
+		Response:    services.SetupState{},
+		Envelope:    true,
+	})
+	reg.Register(openapi.Operation{
+		Method:      "DELETE",
+		Path:        "/api/v1/setup/state",
+		OperationID: "ResetSetupState",
+		Summary:     "Forget setup progress so the wizard starts over",
+		Tags:        []string{"setup"},
+		Response:    services.SetupState{},
+		Envelope:    true,
+	})
+	reg.Register(openapi.Operation{
+		Method:      "POST",
+		Path:        "/api/v1/setup/verify",
+		OperationID: "VerifySetup",
+		Summary:     "Run the setup dry-run verification against every dependency",
+		Tags:        []string{"setup"},
+		Response:    services.SetupVerification{},
+		Envelope:    true,
+	})
+
+	reg.Register(openapi.Operation{
+		Method:      "POST",
+		Path:        "/api/v1/admin/self-test",
+		OperationID: "RunSelfTest",
+		Summary:     "Dry-run cycle from market data to a test notification, with pass/fail and timing per stage",
+		Tags:        []string{"admin"},
+		Request:     handlers.RunSelfTestRequest{},
+		Response:    services.SelfTestReport{},
+		Envelope:    true,
+	})
+	reg.Register(openapi.Operation{
+		Method:      "POST",
+		Path:        "/api/v1/admin/stress-test",
+		OperationID: "RunStressTest",
+		Summary:     "Replay recent signals with added latency, partial fills and higher fees and compare PnL with the baseline",
+		Tags:        []string{"admin"},
+		Request:     handlers.RunStressTestRequest{},
+		Response:    services.StressReport{},
+		Envelope:    true,
+	})
+	reg.Register(openapi.Operation{
+		Method:      "POST",
+		Path:        "/api/v1/admin/collect",
+		OperationID: "CollectNow",
+		Summary:     "Fetch and store fresh data for one symbol immediately, optionally with recent candles",
+		Tags:        []string{"admin"},
+		Request:     handlers.CollectNowRequest{},
+		Response:    services.OnDemandCollection{},
+		Envelope:    true,
+	})
+	reg.Register(openapi.Operation{
+		Method:      "GET",
+		Path:        "/api/v1/admin/audit-chain/verify",
+		OperationID: "VerifyAuditChain",
+		Summary:     "Walk the hash-chained decision and trade log and report any retroactive modification",
+		Tags:        []string{"admin"},
+		Response:    services.AuditChainReport{},
+		Envelope:    true,
+	})
+	reg.Register(openapi.Operation{
+		Method:      "POST",
+		Path:        "/api/v1/admin/audit-chain/anchor",
+		OperationID: "AnchorAuditChain",
+		Summary:     "Record a signed checkpoint of the audit chain head",
+		Tags:        []string{"admin"},
+		Response:    services.AuditAnchor{},
+		Envelope:    true,
+	})
+	reg.Register(openapi.Operation{
+		Method:      "GET",
+		Path:        "/api/v1/admin/state/diff",
+		OperationID: "GetStateDiff",
+		Summary:     "Compare the config, strategy parameters, prompt version and model behind decisions at two times, or with the live state",
+		Tags:        []string{"admin"},
+		Params: []openapi.Param{
+			{Name: "from", In: "query", Required: true},
+			{Name: "to", In: "query"},
+		},
+		Response: services.StateDiff{},
+		Envelope: true,
+	})
+	reg.Register(openapi.Operation{
+		Method:      "GET",
+		Path:        "/api/v1/replay/day",
+		OperationID: "ReplayDay",
+		Summary:     "Replay a recorded day of a symbol through the signal and decision pipeline without executing orders, next to the decisions recorded that day",
+		Tags:        []string{"admin"},
+		Params: []openapi.Param{
+			{Name: "exchange", In: "query", Required: true},
+			{Name: "symbol", In: "query", Required: true},
+			{Name: "date", In: "query", Required: true},
+			{Name: "interval", In: "query"},
+			{Name: "at", In: "query"},
+		},
+		Response: services.SessionReplayReport{},
+		Envelope: true,
+	})
+	reg.Register(openapi.Operation{
+		Method:      "DELETE",
+		Path:        "/api/v1/users/:id/data",
+		OperationID: "EraseUserData",
+		Summary:     "Remove or anonymize a user's Telegram binding, notification logs, operator notes and personal identifiers; dry_run=true only reports",
+		Tags:        []string{"admin"},
+		Params:      []openapi.Param{{Name: "dry_run", In: "query"}},
+		Response:    services.UserDataReport{},
+		Envelope:    true,
+	})
+	reg.Register(openapi.Operation{
+		Method:      "GET",
+		Path:        "/api/v1/config/effective",
+		OperationID: "GetEffectiveConfig",
+		Summary:     "Show the configuration of a mode with its dry, paper or live overlay applied; defaults to the active mode",
+		Tags:        []string{"admin"},
+		Params:      []openapi.Param{{Name: "mode", In: "query"}},
+		Response:    services.EffectiveConfig{},
+		Envelope:    true,
+	})
+	reg.Register(openapi.Operation{
+		Method:      "GET",
+		Path:        "/api/v1/admin/chaos",
+		OperationID: "GetChaosFaults",
+		Summary:     "List the injected dependency faults",
+		Tags:        []string{"admin"},
+		Response:    handlers.ChaosStatusResponse{},
+		Envelope:    true,
+	})
+	reg.Register(openapi.Operation{
+		Method:      "POST",
+		Path:        "/api/v1/admin/chaos",
+		OperationID: "InjectChaosFault",
+		Summary:     "Simulate Redis loss, CCXT timeouts, LLM 429s or database slowness for a bounded window",
+		Tags:        []string{"admin"},
+		Request:     handlers.InjectFaultRequest{},
+		Response:    chaos.Fault{},
+		Envelope:    true,
+	})
+	reg.Register(openapi.Operation{
+		Method:      "DELETE",
+		Path:        "/api/v1/admin/chaos",
+		OperationID: "ClearChaosFaults",
+		Summary:     "End the fault on one target, or every fault",
+		Tags:        []string{"admin"},
+		Params:      []openapi.Param{{Name: "target", In: "query"}},
+		Response:    handlers.ChaosStatusResponse{},
+		Envelope:    true,
+	})
+
+	return reg
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOpenAPIRegistry_DocumentsCLIOperations(t *testing.T) {
+	doc := NewOpenAPIRegistry().Build(nil)
+
+	operationIDs := map[string]bool{}
+	for _, item := range doc.Paths {
+		if item.Get != nil && item.Get.OperationID != "" {
+			operationIDs[item.Get.OperationID] = true
+		}
+		if item.Post != nil && item.Post.OperationID != "" {
+			operationIDs[item.Post.OperationID] = true
+		}
+	}
+	for _, id := range []string{"GetHealth", "BeginAutonomous", "PauseAutonomous", "GetAIModels", "Login"} {
+		assert.True(t, operationIDs[id], "operation %s", id)
+	}
+
+	response := doc.Components.Schemas["AutonomousStateResponse"]
+	require.NotNil(t, response)
+	assert.Contains(t, response.Properties, "readiness_passed")
+	assert.Contains(t, doc.Components.Schemas, "SessionTokenResponseEnvelope")
+}
@@ -0,0 +1,1804 @@
+package api
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/middleware"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/irfndi/neuratrade/internal/services/eventbus"
+	"github.com/irfndi/neuratrade/internal/utils"
+	"github.com/shopspring/decimal"
+)
+
+// routeConfig is the environment configuration of the features SetupRoutes
+// wires. loadRouteConfig reads it once at start-up and the setup functions
+// only consume it. A nil section means the feature is disabled.
+type routeConfig struct {
+	appVersion        string
+	minClientVersions map[string]string
+	quota             *middleware.QuotaConfig
+	pprofEnabled      bool
+	profiler          *services.ProfilerConfig
+	chaosEnabled      bool
+	symbolAliases     map[string]map[string]string
+
+	notifications notificationRouteConfig
+	escalation    *escalationRouteConfig
+	sentiment     services.SentimentServiceConfig
+	polymarket    services.OrderExecutionConfig
+	tradingMode   services.TradingModeConfig
+	eventBus      *eventbus.Config
+	auditChain    services.AuditChainConfig
+
+	watchlist            services.WatchlistConfig
+	customAlerts         *services.CustomAlertConfig
+	capitalAllocation    services.CapitalAllocationConfig
+	newListings          *services.NewListingConfig
+	decisionAuditEnabled bool
+	externalSignals      services.ExternalSignalConfig
+	migrationsDir        string
+	dbPool               services.DBPoolMonitorConfig
+	aiDailyBudget        decimal.Decimal
+	aiMonthlyBudget      decimal.Decimal
+	readinessMinScore    int
+	quests               questRouteConfig
+
+	// ccxtServiceURL and adminAPIKey reach the CCXT service for orders and
+	// user-data streams.
+	ccxtServiceURL              string
+	adminAPIKey                 string
+	quarantine                  *services.SymbolQuarantineConfig
+	kpiStore                    *services.KPIStoreConfig
+	opportunityLifecycleEnabled bool
+	loadShedding                *services.LoadSheddingConfig
+	equity                      services.EquityConfig
+	equityWallets               *equityWalletConfig
+	dailyLoss                   *services.DailyLossConfig
+	hooks                       hookRouteConfig
+	marginCheck                 *services.MarginCheckConfig
+	makerFirst                  services.MakerFirstConfig
+	orderDefaults               services.StrategyOrderDefaults
+	algoOrders                  services.AlgoOrderManagerConfig
+	stablecoins                 *services.StablecoinMonitorConfig
+	exchangeOutage              *services.ExchangeOutageConfig
+	lossStreaks                 services.ConsecutiveLossPolicyConfig
+	symbolCooldowns             services.SymbolCooldownConfig
+	positionRegistry            services.PositionRegistryConfig
+	hedging                     services.HedgingConfig
+	margin                      *services.MarginConfig
+	tradeFlow                   services.TradeFlowConfig
+	fundingForecast             services.FundingForecastConfig
+	positionTracker             services.PositionTrackerConfig
+	userDataStreams             services.UserDataStreamConfig
+
+	shareLinkSecret          string
+	shareLinkBaseURL         string
+	reports                  reportRouteConfig
+	promptCanary             *services.PromptCanaryConfig
+	shadowStrategy           *services.ShadowStrategyConfig
+	notificationActionSecret string
+	embedder                 *services.OpenAIEmbedderConfig
+	embeddingPipeline        services.EmbeddingPipelineConfig
+	startupGuard             services.StartupGuardConfig
+	loopWatchdog             *services.LoopWatchdogConfig
+}
+
+// notificationRouteConfig configures notification formatting and delivery.
+type notificationRouteConfig struct {
+	// format is empty to keep the service default.
+	format     services.NotificationFormat
+	quietHours *services.QuietHours
+	// queue is nil when NOTIFICATION_QUEUE_ENABLED is "false".
+	queue           *services.NotificationDeliveryQueueConfig
+	queueWorkerID   string
+	bounceThreshold int
+	changeDetection *services.NotificationChangeConfig
+}
+
+// escalationRouteConfig configures SMS and phone escalation of critical alerts.
+type escalationRouteConfig struct {
+	channel services.TwilioChannelConfig
+	config  services.CriticalEscalationConfig
+}
+
+// questRouteConfig configures the quest engine.
+type questRouteConfig struct {
+	// maxFinished is 0 to keep the engine default.
+	maxFinished int
+	resume      services.QuestResumeConfig
+	loadLegacy  bool
+}
+
+// equityWalletConfig values external wallets by a stablecoin balance on an
+// EVM chain.
+type equityWalletConfig struct {
+	rpcURL   string
+	token    string
+	decimals int32
+}
+
+// hookRouteConfig configures the pre-trade, post-trade and pre-notify hooks.
+type hookRouteConfig struct {
+	config services.HookConfig
+	// webhooks maps each URL, in the order listed, to the extension points it handles.
+	webhooks      []hookWebhookConfig
+	webhookSecret string
+	plugins       []string
+}
+
+// hookWebhookConfig is one hook webhook and the extension points it handles.
+type hookWebhookConfig struct {
+	url    string
+	events []services.HookEvent
+}
+
+// reportRouteConfig configures the scheduled trading reports.
+type reportRouteConfig struct {
+	config   services.TradingReportConfig
+	calendar []services.CalendarEvent
+	// email is nil when reports are not emailed.
+	email *services.EmailChannelConfig
+}
+
+// loadRouteConfig reads the route configuration from the environment.
+// Invalid values are logged and replaced by their defaults.
+//
+// Returns:
+//
+//	routeConfig: The configuration.
+func loadRouteConfig() routeConfig {
+	cfg := routeConfig{
+		appVersion:        appVersion(),
+		minClientVersions: newMinClientVersions(),
+		quota:             newQuotaConfig(),
+		pprofEnabled:      getEnvOrDefault("PPROF_ENABLED", "false") == "true",
+		profiler:          enabledConfig("PROFILER_ENABLED", "false", newProfilerConfig),
+		chaosEnabled:      getEnvOrDefault("CHAOS_ENABLED", "false") == "true",
+		symbolAliases:     newSymbolAliases(),
+
+		notifications: newNotificationRouteConfig(),
+		escalation:    enabledConfig("CRITICAL_ESCALATION_ENABLED", "false", newEscalationRouteConfig),
+		sentiment:     newSentimentConfig(),
+		polymarket:    newPolymarketConfig(),
+		tradingMode:   newTradingModeConfig(),
+		eventBus:      newEventBusConfig(),
+		auditChain:    newAuditChainConfig(),
+
+		watchlist:            newWatchlistConfig(),
+		customAlerts:         enabledConfig("CUSTOM_ALERTS_ENABLED", "true", newCustomAlertConfig),
+		capitalAllocation:    newCapitalAllocationConfig(),
+		newListings:          enabledConfig("NEW_LISTINGS_ENABLED", "true", newListingConfig),
+		decisionAuditEnabled: getEnvOrDefault("DECISION_AUDIT_ENABLED", "true") == "true",
+		externalSignals:      newExternalSignalConfig(),
+		migrationsDir:        newMigrationsDir(),
+		dbPool:               newDBPoolMonitorConfig(),
+		readinessMinScore:    newReadinessMinScore(),
+		quests:               newQuestRouteConfig(),
+
+		ccxtServiceURL:              getEnvOrDefault("CCXT_SERVICE_URL", "http://localhost:3001"),
+		adminAPIKey:                 os.Getenv("ADMIN_API_KEY"),
+		quarantine:                  enabledConfig("QUARANTINE_ENABLED", "true", newSymbolQuarantineConfig),
+		kpiStore:                    enabledConfig("KPI_STORE_ENABLED", "true", newKPIStoreConfig),
+		opportunityLifecycleEnabled: getEnvOrDefault("ARBITRAGE_LIFECYCLE_ENABLED", "true") == "true",
+		loadShedding:                enabledConfig("LOAD_SHEDDING_ENABLED", "true", newLoadSheddingConfig),
+		equity:                      newEquityConfig(),
+		equityWallets:               newEquityWalletConfig(),
+		dailyLoss:                   enabledConfig("DAILY_LOSS_CAP_ENABLED", "true", newDailyLossConfig),
+		hooks:                       newHookConfig(),
+		marginCheck:                 enabledConfig("MARGIN_CHECK_ENABLED", "true", newMarginCheckConfig),
+		makerFirst:                  newMakerFirstConfig(),
+		orderDefaults:               newStrategyOrderDefaults(),
+		algoOrders:                  newAlgoOrderConfig(),
+		stablecoins:                 enabledConfig("STABLECOIN_MONITOR_ENABLED", "true", newStablecoinMonitorConfig),
+		exchangeOutage:              enabledConfig("EXCHANGE_OUTAGE_ENABLED", "true", newExchangeOutageConfig),
+		lossStreaks:                 newConsecutiveLossConfig(),
+		symbolCooldowns:             newSymbolCooldownConfig(),
+		positionRegistry:            newPositionRegistryConfig(),
+		hedging:                     newHedgingConfig(),
+		margin:                      enabledConfig("MARGIN_MANAGEMENT_ENABLED", "true", newMarginConfig),
+		tradeFlow:                   newTradeFlowConfig(),
+		fundingForecast:             newFundingForecastConfig(),
+		positionTracker:             newPositionTrackerConfig(),
+
+		shareLinkSecret:          os.Getenv("SHARE_LINK_SECRET"),
+		shareLinkBaseURL:         os.Getenv("SHARE_LINK_BASE_URL"),
+		reports:                  newReportRouteConfig(),
+		notificationActionSecret: os.Getenv("NOTIFICATION_ACTION_SECRET"),
+		embedder:                 newEmbedderConfig(),
+		embeddingPipeline:        newEmbeddingPipelineConfig(),
+		startupGuard:             newStartupGuardConfig(),
+		loopWatchdog:             enabledConfig("LOOP_WATCHDOG_ENABLED", "true", newLoopWatchdogConfig),
+	}
+	cfg.aiDailyBudget, cfg.aiMonthlyBudget = newAIBudgets()
+	cfg.userDataStreams = newUserDataStreamConfig(cfg.ccxtServiceURL, cfg.adminAPIKey)
+	if promptCanary, ok := newPromptCanaryConfig(); ok {
+		cfg.promptCanary = &promptCanary
+	}
+	if shadowStrategy, ok := newShadowStrategyConfig(); ok {
+		cfg.shadowStrategy = &shadowStrategy
+	}
+	if cfg.adminAPIKey == "" {
+		log.Printf("WARNING: ADMIN_API_KEY is not set; CCXT order executor requests will be unauthenticated")
+	}
+	return cfg
+}
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// enabledConfig builds a feature's configuration when its *_ENABLED
+// environment variable is "true".
+//
+// Parameters:
+//
+//	key: The *_ENABLED environment variable.
+//	defaultValue: Its value when unset.
+//	build: Reads the feature's configuration.
+//
+// Returns:
+//
+//	*T: The configuration, or nil when the feature is disabled.
+func enabledConfig[T any](key, defaultValue string, build func() T) *T {
+	if getEnvOrDefault(key, defaultValue) != "true" {
+		return nil
+	}
+	config := build()
+	return &config
+}
+
+// newQuotaConfig builds the per-client read/write quotas from
+// API_RATE_LIMIT_* environment variables.
+//
+// Returns:
+//
+//	*middleware.QuotaConfig: The quotas, or nil when API_RATE_LIMIT_ENABLED is "false".
+func newQuotaConfig() *middleware.QuotaConfig {
+	if getEnvOrDefault("API_RATE_LIMIT_ENABLED", "true") == "false" {
+		return nil
+	}
+
+	config := middleware.DefaultQuotaConfig()
+	parseQuota := func(name string, quota *middleware.Quota) {
+		rateKey, burstKey := "API_RATE_LIMIT_"+name+"_RPS", "API_RATE_LIMIT_"+name+"_BURST"
+		if raw := os.Getenv(rateKey); raw != "" {
+			if rate, err := strconv.ParseFloat(raw, 64); err == nil && rate > 0 {
+				quota.Rate = rate
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', using default %g", rateKey, raw, quota.Rate)
+			}
+		}
+		if raw := os.Getenv(burstKey); raw != "" {
+			if burst, err := strconv.Atoi(raw); err == nil && burst > 0 {
+				quota.Burst = burst
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', using default %d", burstKey, raw, quota.Burst)
+			}
+		}
+	}
+	parseQuota("READ", &config.Read)
+	parseQuota("WRITE", &config.Write)
+	return &config
+}
+
+// newNotificationRouteConfig builds the notification format, quiet hours,
+// delivery queue, bounce tracking and change detection settings from
+// NOTIFICATION_* and NOTIFY_* environment variables.
+func newNotificationRouteConfig() notificationRouteConfig {
+	var config notificationRouteConfig
+	if format, err := services.ParseNotificationFormat(os.Getenv("NOTIFICATION_FORMAT")); err != nil {
+		log.Printf("WARNING: Invalid NOTIFICATION_FORMAT value '%s', using default", os.Getenv("NOTIFICATION_FORMAT"))
+	} else {
+		config.format = format
+	}
+	if spec := os.Getenv("NOTIFICATION_QUIET_HOURS"); spec != "" {
+		if quietHours, err := services.ParseQuietHours(spec, os.Getenv("NOTIFICATION_QUIET_HOURS_TZ")); err != nil {
+			log.Printf("WARNING: Invalid NOTIFICATION_QUIET_HOURS value '%s', quiet hours disabled: %v", spec, err)
+		} else {
+			config.quietHours = quietHours
+		}
+	}
+	if getEnvOrDefault("NOTIFICATION_QUEUE_ENABLED", "true") != "false" {
+		queue := services.DefaultNotificationDeliveryQueueConfig()
+		if workers, err := strconv.Atoi(os.Getenv("NOTIFICATION_QUEUE_WORKERS")); err == nil && workers > 0 {
+			queue.Workers = workers
+		}
+		if attempts, err := strconv.Atoi(os.Getenv("NOTIFICATION_QUEUE_MAX_ATTEMPTS")); err == nil && attempts > 0 {
+			queue.MaxAttempts = attempts
+		}
+		config.queue = &queue
+		// A stable worker ID lets a restarted instance reclaim its own
+		// in-flight deliveries straight away.
+		workerID, _ := os.Hostname()
+		config.queueWorkerID = getEnvOrDefault("NOTIFICATION_QUEUE_WORKER_ID", workerID)
+	}
+	config.bounceThreshold, _ = strconv.Atoi(os.Getenv("NOTIFICATION_BOUNCE_THRESHOLD"))
+	// Only alert a chat again about a symbol once its best spread or signal
+	// has changed materially.
+	config.changeDetection = enabledConfig("NOTIFY_CHANGE_DETECTION_ENABLED", "true", newNotificationChangeConfig)
+	return config
+}
+
+// newEscalationRouteConfig builds critical alert escalation and its Twilio
+// channel from ESCALATION_* and TWILIO_* environment variables.
+func newEscalationRouteConfig() escalationRouteConfig {
+	return escalationRouteConfig{
+		channel: newTwilioChannelConfig(),
+		config:  newCriticalEscalationConfig(),
+	}
+}
+
+// newSentimentConfig builds the news and Reddit sentiment sources from
+// REDDIT_* and CRYPTOPANIC_TOKEN environment variables.
+func newSentimentConfig() services.SentimentServiceConfig {
+	config := services.DefaultSentimentServiceConfig()
+	config.RedditClientID = os.Getenv("REDDIT_CLIENT_ID")
+	config.RedditClientSecret = os.Getenv("REDDIT_CLIENT_SECRET")
+	config.CryptoPanicToken = os.Getenv("CRYPTOPANIC_TOKEN")
+	return config
+}
+
+// newPolymarketConfig builds the Polymarket CLOB order execution settings
+// from POLYMARKET_* environment variables.
+func newPolymarketConfig() services.OrderExecutionConfig {
+	return services.OrderExecutionConfig{
+		BaseURL:    getEnvOrDefault("POLYMARKET_CLOB_URL", "https://clob.polymarket.com"),
+		APIKey:     os.Getenv("POLYMARKET_API_KEY"),
+		APISecret:  os.Getenv("POLYMARKET_API_SECRET"),
+		WalletAddr: os.Getenv("POLYMARKET_WALLET_ADDRESS"),
+	}
+}
+
+// newTradingModeConfig builds the paper/live mode, operators and go-live
+// notifications from TRADING_* environment variables.
+func newTradingModeConfig() services.TradingModeConfig {
+	config := services.TradingModeConfig{
+		DefaultMode: services.ExecutionMode(getEnvOrDefault("TRADING_MODE", "paper")),
+	}
+	if delay, err := time.ParseDuration(os.Getenv("TRADING_CONFIRMATION_DELAY")); err == nil {
+		config.ConfirmationDelay = delay
+	}
+	for _, operator := range strings.Split(os.Getenv("TRADING_OPERATORS"), ",") {
+		if operator = strings.TrimSpace(operator); operator != "" {
+			config.Operators = append(config.Operators, operator)
+		}
+	}
+	for _, raw := range strings.Split(os.Getenv("TRADING_GO_LIVE_NOTIFY_CHAT_IDS"), ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		if chatID, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			config.NotifyChatIDs = append(config.NotifyChatIDs, chatID)
+		} else {
+			log.Printf("WARNING: Invalid TRADING_GO_LIVE_NOTIFY_CHAT_IDS entry '%s', ignoring", raw)
+		}
+	}
+	return config
+}
+
+// newEventBusConfig builds the internal event bus settings from EVENT_BUS_*
+// environment variables.
+//
+// Returns:
+//
+//	*eventbus.Config: The configuration, or nil when EVENT_BUS_DRIVER is "none".
+func newEventBusConfig() *eventbus.Config {
+	driver := getEnvOrDefault("EVENT_BUS_DRIVER", "none")
+	if driver == "none" {
+		return nil
+	}
+	return &eventbus.Config{Driver: driver, URL: os.Getenv("EVENT_BUS_NATS_URL")}
+}
+
+// newAuditChainConfig builds the audit chain anchoring settings from
+// AUDIT_CHAIN_* environment variables.
+func newAuditChainConfig() services.AuditChainConfig {
+	anchorInterval, err := time.ParseDuration(getEnvOrDefault("AUDIT_CHAIN_ANCHOR_INTERVAL", "1h"))
+	if err != nil {
+		log.Printf("WARNING: invalid AUDIT_CHAIN_ANCHOR_INTERVAL, using default: %v", err)
+	}
+	return services.AuditChainConfig{
+		SigningKey:     os.Getenv("AUDIT_CHAIN_SIGNING_KEY"),
+		AnchorInterval: anchorInterval,
+	}
+}
+
+// newWatchlistConfig builds the symbol universe settings from WATCHLIST_*
+// environment variables.
+func newWatchlistConfig() services.WatchlistConfig {
+	universeSize, err := strconv.Atoi(getEnvOrDefault("WATCHLIST_UNIVERSE_SIZE", "50"))
+	if err != nil {
+		log.Printf("WARNING: invalid WATCHLIST_UNIVERSE_SIZE, using default: %v", err)
+	}
+	refreshInterval, err := time.ParseDuration(getEnvOrDefault("WATCHLIST_REFRESH_INTERVAL", "24h"))
+	if err != nil {
+		log.Printf("WARNING: invalid WATCHLIST_REFRESH_INTERVAL, using default: %v", err)
+	}
+	return services.WatchlistConfig{
+		UniverseSize:    universeSize,
+		RefreshInterval: refreshInterval,
+	}
+}
+
+// newCustomAlertConfig builds the custom alert limits from CUSTOM_ALERTS_*
+// environment variables.
+func newCustomAlertConfig() services.CustomAlertConfig {
+	maxAlerts, err := strconv.Atoi(getEnvOrDefault("CUSTOM_ALERTS_MAX_PER_USER", "20"))
+	if err != nil {
+		log.Printf("WARNING: Invalid CUSTOM_ALERTS_MAX_PER_USER value '%s', using default", os.Getenv("CUSTOM_ALERTS_MAX_PER_USER"))
+	}
+	return services.CustomAlertConfig{
+		DefaultExchange:  getEnvOrDefault("CUSTOM_ALERTS_DEFAULT_EXCHANGE", "binance"),
+		MaxAlertsPerUser: maxAlerts,
+	}
+}
+
+// newCapitalAllocationConfig builds the strategy budget rebalancing settings
+// from CAPITAL_ALLOCATION_* environment variables.
+func newCapitalAllocationConfig() services.CapitalAllocationConfig {
+	rebalanceInterval, err := time.ParseDuration(getEnvOrDefault("CAPITAL_ALLOCATION_REBALANCE_INTERVAL", "24h"))
+	if err != nil {
+		log.Printf("WARNING: invalid CAPITAL_ALLOCATION_REBALANCE_INTERVAL, using default: %v", err)
+	}
+	maxShift, err := strconv.ParseFloat(getEnvOrDefault("CAPITAL_ALLOCATION_MAX_SHIFT", "0.5"), 64)
+	if err != nil {
+		log.Printf("WARNING: invalid CAPITAL_ALLOCATION_MAX_SHIFT, using default: %v", err)
+	}
+	return services.CapitalAllocationConfig{
+		RebalanceInterval: rebalanceInterval,
+		MaxShift:          maxShift,
+	}
+}
+
+// newSymbolAliases reads the per-exchange symbol aliases from SYMBOL_ALIASES.
+func newSymbolAliases() map[string]map[string]string {
+	raw := os.Getenv("SYMBOL_ALIASES")
+	if raw == "" {
+		return nil
+	}
+	aliases, err := services.ParseSymbolAliases(raw)
+	if err != nil {
+		log.Printf("WARNING: Invalid SYMBOL_ALIASES value: %v", err)
+		return nil
+	}
+	return aliases
+}
+
+// newMigrationsDir returns MIGRATIONS_DIR, or the migrations of the
+// DATABASE_DRIVER in use.
+func newMigrationsDir() string {
+	if dir := os.Getenv("MIGRATIONS_DIR"); dir != "" {
+		return dir
+	}
+	if getEnvOrDefault("DATABASE_DRIVER", "sqlite") == "sqlite" {
+		return "database/sqlite_migrations"
+	}
+	return "database/migrations"
+}
+
+// newDBPoolMonitorConfig builds the connection pool wait threshold from
+// DATABASE_POOL_WAIT_THRESHOLD.
+func newDBPoolMonitorConfig() services.DBPoolMonitorConfig {
+	waitThreshold, err := time.ParseDuration(getEnvOrDefault("DATABASE_POOL_WAIT_THRESHOLD", "100ms"))
+	if err != nil {
+		log.Printf("WARNING: invalid DATABASE_POOL_WAIT_THRESHOLD, using default: %v", err)
+	}
+	return services.DBPoolMonitorConfig{WaitThreshold: waitThreshold}
+}
+
+// newAIBudgets reads the daily and monthly AI budgets from AI_DAILY_BUDGET
+// and AI_MONTHLY_BUDGET, with the defaults from migration 054.
+func newAIBudgets() (daily, monthly decimal.Decimal) {
+	dailyBudgetStr := getEnvOrDefault("AI_DAILY_BUDGET", "10.00")
+	monthlyBudgetStr := getEnvOrDefault("AI_MONTHLY_BUDGET", "200.00")
+
+	daily, err := decimal.NewFromString(dailyBudgetStr)
+	if err != nil {
+		log.Printf("WARNING: Invalid AI_DAILY_BUDGET value '%s', using default 10.00", dailyBudgetStr)
+		daily = decimal.NewFromFloat(10.00)
+	}
+
+	monthly, err = decimal.NewFromString(monthlyBudgetStr)
+	if err != nil {
+		log.Printf("WARNING: Invalid AI_MONTHLY_BUDGET value '%s', using default 200.00", monthlyBudgetStr)
+		monthly = decimal.NewFromFloat(200.00)
+	}
+	return daily, monthly
+}
+
+// newReadinessMinScore reads the live-trading readiness threshold from
+// READINESS_MIN_SCORE; 0 keeps the scorer default.
+func newReadinessMinScore() int {
+	raw := os.Getenv("READINESS_MIN_SCORE")
+	if raw == "" {
+		return 0
+	}
+	if value, err := strconv.Atoi(raw); err == nil && value > 0 && value <= 100 {
+		return value
+	}
+	log.Printf("WARNING: Invalid READINESS_MIN_SCORE value '%s', using default", raw)
+	return 0
+}
+
+// newQuestRouteConfig builds the quest engine retention and resume settings
+// from QUEST_* and NEURATRADE_LOAD_LEGACY_ACTIVE_QUESTS environment variables.
+func newQuestRouteConfig() questRouteConfig {
+	var config questRouteConfig
+	if raw := os.Getenv("QUEST_MAX_FINISHED"); raw != "" {
+		if value, err := strconv.Atoi(raw); err == nil && value > 0 {
+			config.maxFinished = value
+		} else {
+			log.Printf("WARNING: Invalid QUEST_MAX_FINISHED value '%s', using default", raw)
+		}
+	}
+	if raw := os.Getenv("QUEST_START_JITTER"); raw != "" {
+		if value, err := time.ParseDuration(raw); err == nil {
+			if value == 0 {
+				value = -1 // 0 disables the jitter; SetResumeConfig reads 0 as the default
+			}
+			config.resume.StartJitter = value
+		} else {
+			log.Printf("WARNING: Invalid QUEST_START_JITTER value '%s', using default", raw)
+		}
+	}
+	if raw := os.Getenv("QUEST_MAX_BACKFILL_RUNS"); raw != "" {
+		if value, err := strconv.Atoi(raw); err == nil && value > 0 {
+			config.resume.MaxBackfillRuns = value
+		} else {
+			log.Printf("WARNING: Invalid QUEST_MAX_BACKFILL_RUNS value '%s', using default", raw)
+		}
+	}
+	// Legacy quest preload is opt-in only.
+	// In scalping-first mode we avoid restoring old active rows without metadata/chat ownership.
+	config.loadLegacy = os.Getenv("NEURATRADE_LOAD_LEGACY_ACTIVE_QUESTS") == "1" ||
+		os.Getenv("NEURATRADE_LOAD_LEGACY_ACTIVE_QUESTS") == "true"
+	return config
+}
+
+// newDailyLossConfig builds the daily loss cap from DAILY_LOSS_* environment
+// variables.
+func newDailyLossConfig() services.DailyLossConfig {
+	config := services.DailyLossConfig{
+		FlattenOnBreach: getEnvOrDefault("DAILY_LOSS_FLATTEN_ON_BREACH", "false") == "true",
+	}
+	if raw := os.Getenv("DAILY_LOSS_CAP_PCT"); raw != "" {
+		if value, err := strconv.ParseFloat(raw, 64); err == nil {
+			config.MaxLossPct = value
+		} else {
+			log.Printf("WARNING: Invalid DAILY_LOSS_CAP_PCT value '%s', using default", raw)
+		}
+	}
+	if raw := os.Getenv("DAILY_LOSS_CAP_USDT"); raw != "" {
+		if value, err := decimal.NewFromString(raw); err == nil {
+			config.MaxLoss = value
+		} else {
+			log.Printf("WARNING: Invalid DAILY_LOSS_CAP_USDT value '%s', ignoring", raw)
+		}
+	}
+	if raw := os.Getenv("DAILY_LOSS_CHECK_INTERVAL"); raw != "" {
+		if value, err := time.ParseDuration(raw); err == nil {
+			config.CheckInterval = value
+		} else {
+			log.Printf("WARNING: Invalid DAILY_LOSS_CHECK_INTERVAL value '%s', using default", raw)
+		}
+	}
+	return config
+}
+
+// newConsecutiveLossConfig builds the consecutive-loss rule from
+// CONSECUTIVE_LOSS_* environment variables.
+func newConsecutiveLossConfig() services.ConsecutiveLossPolicyConfig {
+	config := services.ConsecutiveLossPolicyConfig{
+		Action: services.LossStreakAction(getEnvOrDefault("CONSECUTIVE_LOSS_ACTION", "reduce")),
+	}
+	if raw := os.Getenv("CONSECUTIVE_LOSS_LIMIT"); raw != "" {
+		if value, err := strconv.Atoi(raw); err == nil {
+			config.MaxLosses = value
+		} else {
+			log.Printf("WARNING: Invalid CONSECUTIVE_LOSS_LIMIT value '%s', using default", raw)
+		}
+	}
+	if raw := os.Getenv("CONSECUTIVE_LOSS_SIZE_REDUCTION"); raw != "" {
+		if value, err := strconv.ParseFloat(raw, 64); err == nil {
+			config.SizeReduction = value
+		} else {
+			log.Printf("WARNING: Invalid CONSECUTIVE_LOSS_SIZE_REDUCTION value '%s', using default", raw)
+		}
+	}
+	if raw := os.Getenv("CONSECUTIVE_LOSS_COOLDOWN"); raw != "" {
+		if value, err := time.ParseDuration(raw); err == nil {
+			config.Cooldown = value
+		} else {
+			log.Printf("WARNING: Invalid CONSECUTIVE_LOSS_COOLDOWN value '%s', using default", raw)
+		}
+	}
+	return config
+}
+
+// newSymbolCooldownConfig builds the re-entry cooldowns from SYMBOL_COOLDOWN_*
+// environment variables.
+func newSymbolCooldownConfig() services.SymbolCooldownConfig {
+	var config services.SymbolCooldownConfig
+	if raw := os.Getenv("SYMBOL_COOLDOWN_AFTER_LOSS"); raw != "" {
+		if value, err := time.ParseDuration(raw); err == nil {
+			config.AfterLoss = value
+		} else {
+			log.Printf("WARNING: Invalid SYMBOL_COOLDOWN_AFTER_LOSS value '%s', using default", raw)
+		}
+	}
+	if raw := os.Getenv("SYMBOL_COOLDOWN_AFTER_EXIT"); raw != "" {
+		if value, err := time.ParseDuration(raw); err == nil {
+			config.AfterExit = value
+		} else {
+			log.Printf("WARNING: Invalid SYMBOL_COOLDOWN_AFTER_EXIT value '%s', using default", raw)
+		}
+	}
+	return config
+}
+
+// newLoopWatchdogConfig builds the dead man's switch settings from
+// LOOP_WATCHDOG_* environment variables.
+func newLoopWatchdogConfig() services.LoopWatchdogConfig {
+	config := services.LoopWatchdogConfig{
+		FlattenOnStall: getEnvOrDefault("LOOP_WATCHDOG_FLATTEN", "false") == "true",
+	}
+	if raw := os.Getenv("LOOP_WATCHDOG_MISSED_BEATS"); raw != "" {
+		if value, err := strconv.Atoi(raw); err == nil && value > 0 {
+			config.MissedBeats = value
+		} else {
+			log.Printf("WARNING: Invalid LOOP_WATCHDOG_MISSED_BEATS value '%s', using default", raw)
+		}
+	}
+	if raw := os.Getenv("LOOP_WATCHDOG_GRACE"); raw != "" {
+		if value, err := time.ParseDuration(raw); err == nil {
+			config.Grace = value
+		} else {
+			log.Printf("WARNING: Invalid LOOP_WATCHDOG_GRACE value '%s', using default", raw)
+		}
+	}
+	return config
+}
+
+// newReportRouteConfig builds the scheduled report settings, calendar and
+// email delivery from REPORT_* and SMTP_* environment variables.
+func newReportRouteConfig() reportRouteConfig {
+	config := reportRouteConfig{
+		config: newTradingReportConfig(),
+		email:  newReportEmailConfig(),
+	}
+	if raw := os.Getenv("REPORT_CALENDAR_EVENTS"); raw != "" {
+		if events, err := services.ParseCalendarEvents(raw); err == nil {
+			config.calendar = events
+		} else {
+			log.Printf("WARNING: Invalid REPORT_CALENDAR_EVENTS value: %v", err)
+		}
+	}
+	return config
+}
+
+// newExternalSignalConfig builds the TradingView ingestion and external-signal
+// strategy configuration from TRADINGVIEW_* and EXTERNAL_SIGNAL_* environment variables.
+//
+// Returns:
+//
+//	services.ExternalSignalConfig: The configuration; ingestion is disabled without a secret.
+func newExternalSignalConfig() services.ExternalSignalConfig {
+	config := services.ExternalSignalConfig{
+		Secret:          os.Getenv("TRADINGVIEW_WEBHOOK_SECRET"),
+		DefaultExchange: os.Getenv("TRADINGVIEW_DEFAULT_EXCHANGE"),
+		AutoExecute:     getEnvOrDefault("EXTERNAL_SIGNAL_AUTO_EXECUTE", "false") == "true",
+	}
+	if raw := os.Getenv("EXTERNAL_SIGNAL_MIN_CONFIDENCE"); raw != "" {
+		if value, err := strconv.ParseFloat(raw, 64); err == nil {
+			config.MinConfidence = value
+		} else {
+			log.Printf("WARNING: Invalid EXTERNAL_SIGNAL_MIN_CONFIDENCE value '%s', ignoring", raw)
+		}
+	}
+	for key, target := range map[string]*decimal.Decimal{
+		"EXTERNAL_SIGNAL_MAX_ORDER_AMOUNT":   &config.MaxOrderAmount,
+		"EXTERNAL_SIGNAL_MAX_ORDER_NOTIONAL": &config.MaxOrderNotional,
+	} {
+		if raw := os.Getenv(key); raw != "" {
+			if value, err := decimal.NewFromString(raw); err == nil {
+				*target = value
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', ignoring", key, raw)
+			}
+		}
+	}
+	config.MaxDailyOrders = 10
+	if raw := os.Getenv("EXTERNAL_SIGNAL_MAX_DAILY_ORDERS"); raw != "" {
+		if value, err := strconv.Atoi(raw); err == nil && value >= 0 {
+			config.MaxDailyOrders = value
+		} else {
+			log.Printf("WARNING: Invalid EXTERNAL_SIGNAL_MAX_DAILY_ORDERS value '%s', using default 10", raw)
+		}
+	}
+	for _, symbol := range strings.Split(os.Getenv("EXTERNAL_SIGNAL_ALLOWED_SYMBOLS"), ",") {
+		if symbol = strings.TrimSpace(symbol); symbol != "" {
+			config.AllowedSymbols = append(config.AllowedSymbols, symbol)
+		}
+	}
+	return config
+}
+
+// newListingConfig builds the new-listing detector configuration from
+// NEW_LISTINGS_* environment variables.
+//
+// Returns:
+//
+//	services.NewListingConfig: The configuration; auto-add is off unless enabled.
+func newListingConfig() services.NewListingConfig {
+	config := services.NewListingConfig{
+		AutoAdd: getEnvOrDefault("NEW_LISTINGS_AUTO_ADD", "false") == "true",
+	}
+	for _, exchange := range strings.Split(getEnvOrDefault("NEW_LISTINGS_EXCHANGES", "binance"), ",") {
+		if exchange = strings.TrimSpace(exchange); exchange != "" {
+			config.Exchanges = append(config.Exchanges, strings.ToLower(exchange))
+		}
+	}
+	for key, target := range map[string]*time.Duration{
+		"NEW_LISTINGS_SCAN_INTERVAL":    &config.Interval,
+		"NEW_LISTINGS_PROBATION_PERIOD": &config.ProbationPeriod,
+	} {
+		if raw := os.Getenv(key); raw != "" {
+			if value, err := time.ParseDuration(raw); err == nil {
+				*target = value
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', using default", key, raw)
+			}
+		}
+	}
+	for key, target := range map[string]*float64{
+		"NEW_LISTINGS_MAX_CAPITAL_PCT": &config.MaxCapitalPct,
+		"NEW_LISTINGS_MIN_CONFIDENCE":  &config.MinConfidence,
+	} {
+		if raw := os.Getenv(key); raw != "" {
+			if value, err := strconv.ParseFloat(raw, 64); err == nil {
+				*target = value
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', using default", key, raw)
+			}
+		}
+	}
+	for _, raw := range strings.Split(os.Getenv("NEW_LISTINGS_NOTIFY_CHAT_IDS"), ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		if chatID, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			config.NotifyChatIDs = append(config.NotifyChatIDs, chatID)
+		} else {
+			log.Printf("WARNING: Invalid NEW_LISTINGS_NOTIFY_CHAT_IDS entry '%s', ignoring", raw)
+		}
+	}
+	return config
+}
+
+// newStablecoinMonitorConfig builds the depeg monitor configuration from
+// STABLECOIN_* environment variables.
+//
+// Returns:
+//
+//	services.StablecoinMonitorConfig: The configuration; defensive actions are off unless enabled.
+func newStablecoinMonitorConfig() services.StablecoinMonitorConfig {
+	config := services.StablecoinMonitorConfig{
+		PauseStrategies: getEnvOrDefault("STABLECOIN_DEPEG_PAUSE_STRATEGIES", "true") == "true",
+		ConvertBalances: getEnvOrDefault("STABLECOIN_DEPEG_CONVERT_BALANCES", "false") == "true",
+		ConvertExchange: os.Getenv("STABLECOIN_DEPEG_CONVERT_EXCHANGE"),
+	}
+	if raw := os.Getenv("STABLECOIN_DEPEG_THRESHOLD"); raw != "" {
+		if value, err := strconv.ParseFloat(raw, 64); err == nil && value > 0 {
+			config.Threshold = value
+		} else {
+			log.Printf("WARNING: Invalid STABLECOIN_DEPEG_THRESHOLD value '%s', using default", raw)
+		}
+	}
+	if raw := os.Getenv("STABLECOIN_CHECK_INTERVAL"); raw != "" {
+		if value, err := time.ParseDuration(raw); err == nil {
+			config.Interval = value
+		} else {
+			log.Printf("WARNING: Invalid STABLECOIN_CHECK_INTERVAL value '%s', using default", raw)
+		}
+	}
+	// STABLECOIN_PROXIES entries look like "USDT=kraken:USDT/USD"; a "~" before
+	// the symbol marks an inverted market such as "~USD/USDT".
+	for _, entry := range strings.Split(os.Getenv("STABLECOIN_PROXIES"), ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		stable, market, ok := strings.Cut(entry, "=")
+		exchange, symbol, ok2 := strings.Cut(market, ":")
+		if !ok || !ok2 || stable == "" || exchange == "" || symbol == "" {
+			log.Printf("WARNING: Invalid STABLECOIN_PROXIES entry '%s', ignoring", entry)
+			continue
+		}
+		proxy := services.StablecoinProxy{Stablecoin: strings.ToUpper(stable), Exchange: exchange}
+		proxy.Symbol, proxy.Inverse = strings.CutPrefix(symbol, "~")
+		config.Proxies = append(config.Proxies, proxy)
+	}
+	return config
+}
+
+// newExchangeOutageConfig builds the outage detector configuration from
+// EXCHANGE_OUTAGE_* environment variables.
+//
+// Returns:
+//
+//	services.ExchangeOutageConfig: The configuration; unset values use defaults.
+func newExchangeOutageConfig() services.ExchangeOutageConfig {
+	var config services.ExchangeOutageConfig
+	for key, target := range map[string]*time.Duration{
+		"EXCHANGE_OUTAGE_CHECK_INTERVAL": &config.Interval,
+		"EXCHANGE_OUTAGE_STALE_AFTER":    &config.StaleAfter,
+		"EXCHANGE_OUTAGE_SPREAD_WINDOW":  &config.SpreadWindow,
+		"EXCHANGE_OUTAGE_STABLE_PERIOD":  &config.StablePeriod,
+	} {
+		if raw := os.Getenv(key); raw != "" {
+			if value, err := time.ParseDuration(raw); err == nil {
+				*target = value
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', using default", key, raw)
+			}
+		}
+	}
+	for key, target := range map[string]*float64{
+		"EXCHANGE_OUTAGE_MAX_ERROR_RATE": &config.MaxErrorRate,
+		"EXCHANGE_OUTAGE_MAX_SPREAD_PCT": &config.MaxSpreadPct,
+	} {
+		if raw := os.Getenv(key); raw != "" {
+			if value, err := strconv.ParseFloat(raw, 64); err == nil {
+				*target = value
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', using default", key, raw)
+			}
+		}
+	}
+	if raw := os.Getenv("EXCHANGE_OUTAGE_MIN_REQUESTS"); raw != "" {
+		if value, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			config.MinRequests = value
+		} else {
+			log.Printf("WARNING: Invalid EXCHANGE_OUTAGE_MIN_REQUESTS value '%s', using default", raw)
+		}
+	}
+	for _, raw := range strings.Split(os.Getenv("EXCHANGE_OUTAGE_NOTIFY_CHAT_IDS"), ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		if chatID, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			config.NotifyChatIDs = append(config.NotifyChatIDs, chatID)
+		} else {
+			log.Printf("WARNING: Invalid EXCHANGE_OUTAGE_NOTIFY_CHAT_IDS entry '%s', ignoring", raw)
+		}
+	}
+	return config
+}
+
+// newSymbolQuarantineConfig builds the symbol quarantine configuration from
+// QUARANTINE_* environment variables.
+//
+// Returns:
+//
+//	services.SymbolQuarantineConfig: The configuration; unset values use defaults.
+func newSymbolQuarantineConfig() services.SymbolQuarantineConfig {
+	var config services.SymbolQuarantineConfig
+	for key, target := range map[string]*time.Duration{
+		"QUARANTINE_WINDOW": &config.Window,
+		"QUARANTINE_TTL":    &config.TTL,
+	} {
+		if raw := os.Getenv(key); raw != "" {
+			if value, err := time.ParseDuration(raw); err == nil {
+				*target = value
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', using default", key, raw)
+			}
+		}
+	}
+	for key, target := range map[string]*int{
+		"QUARANTINE_MAX_ORDER_REJECTIONS": &config.MaxOrderRejections,
+		"QUARANTINE_MAX_STALE_DATA":       &config.MaxStaleData,
+		"QUARANTINE_MAX_ANOMALIES":        &config.MaxAnomalies,
+	} {
+		if raw := os.Getenv(key); raw != "" {
+			if value, err := strconv.Atoi(raw); err == nil {
+				*target = value
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', using default", key, raw)
+			}
+		}
+	}
+	for _, raw := range strings.Split(os.Getenv("QUARANTINE_NOTIFY_CHAT_IDS"), ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		if chatID, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			config.NotifyChatIDs = append(config.NotifyChatIDs, chatID)
+		} else {
+			log.Printf("WARNING: Invalid QUARANTINE_NOTIFY_CHAT_IDS entry '%s', ignoring", raw)
+		}
+	}
+	return config
+}
+
+// newHedgingConfig builds the hedging advisor configuration from HEDGE_*
+// environment variables.
+//
+// Returns:
+//
+//	services.HedgingConfig: The configuration; unset values use defaults.
+func newHedgingConfig() services.HedgingConfig {
+	var config services.HedgingConfig
+	for key, target := range map[string]*float64{
+		"HEDGE_MIN_CORRELATION": &config.MinCorrelation,
+		"HEDGE_RATIO":           &config.HedgeRatio,
+	} {
+		if raw := os.Getenv(key); raw != "" {
+			if value, err := strconv.ParseFloat(raw, 64); err == nil {
+				*target = value
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', using default", key, raw)
+			}
+		}
+	}
+	raw := getEnvOrDefault("HEDGE_MIN_NOTIONAL", "100")
+	if value, err := decimal.NewFromString(raw); err == nil {
+		config.MinNotional = value
+	} else {
+		config.MinNotional = decimal.NewFromInt(100)
+		log.Printf("WARNING: Invalid HEDGE_MIN_NOTIONAL value '%s', using default", raw)
+	}
+	for key, target := range map[string]*time.Duration{
+		"HEDGE_SUGGESTION_TTL": &config.SuggestionTTL,
+		"HEDGE_SCAN_INTERVAL":  &config.ScanInterval,
+	} {
+		if raw := os.Getenv(key); raw != "" {
+			if value, err := time.ParseDuration(raw); err == nil {
+				*target = value
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', using default", key, raw)
+			}
+		}
+	}
+	for _, raw := range strings.Split(os.Getenv("HEDGE_NOTIFY_CHAT_IDS"), ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		if chatID, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			config.NotifyChatIDs = append(config.NotifyChatIDs, chatID)
+		} else {
+			log.Printf("WARNING: Invalid HEDGE_NOTIFY_CHAT_IDS entry '%s', ignoring", raw)
+		}
+	}
+	return config
+}
+
+// newMarginConfig builds leverage enforcement and margin monitoring settings
+// from MARGIN_* environment variables.
+func newMarginConfig() services.MarginConfig {
+	config := services.MarginConfig{MarginMode: os.Getenv("MARGIN_MODE")}
+	for key, target := range map[string]*float64{
+		"MARGIN_RATIO_WARN":     &config.MarginRatioWarn,
+		"MARGIN_RATIO_CRITICAL": &config.MarginRatioCritical,
+	} {
+		if raw := os.Getenv(key); raw != "" {
+			if value, err := strconv.ParseFloat(raw, 64); err == nil {
+				*target = value
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', using default", key, raw)
+			}
+		}
+	}
+	for key, target := range map[string]*time.Duration{
+		"MARGIN_VERIFY_TTL":    &config.VerifyTTL,
+		"MARGIN_SCAN_INTERVAL": &config.ScanInterval,
+	} {
+		if raw := os.Getenv(key); raw != "" {
+			if value, err := time.ParseDuration(raw); err == nil {
+				*target = value
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', using default", key, raw)
+			}
+		}
+	}
+	for _, exchange := range strings.Split(os.Getenv("MARGIN_MONITOR_EXCHANGES"), ",") {
+		if exchange = strings.ToLower(strings.TrimSpace(exchange)); exchange != "" {
+			config.Exchanges = append(config.Exchanges, exchange)
+		}
+	}
+	return config
+}
+
+// newFundingForecastConfig builds funding forecast settings from
+// FUNDING_FORECAST_* environment variables.
+func newFundingForecastConfig() services.FundingForecastConfig {
+	var config services.FundingForecastConfig
+	for key, target := range map[string]*float64{
+		"FUNDING_FORECAST_ALPHA":          &config.Alpha,
+		"FUNDING_FORECAST_PREMIUM_WEIGHT": &config.PremiumWeight,
+	} {
+		if raw := os.Getenv(key); raw != "" {
+			if value, err := strconv.ParseFloat(raw, 64); err == nil {
+				*target = value
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', using default", key, raw)
+			}
+		}
+	}
+	if raw := os.Getenv("FUNDING_FORECAST_INTERVAL"); raw != "" {
+		if value, err := time.ParseDuration(raw); err == nil {
+			config.ScanInterval = value
+		} else {
+			log.Printf("WARNING: Invalid FUNDING_FORECAST_INTERVAL value '%s', using default", raw)
+		}
+	}
+	for _, exchange := range strings.Split(os.Getenv("FUNDING_FORECAST_EXCHANGES"), ",") {
+		if exchange = strings.ToLower(strings.TrimSpace(exchange)); exchange != "" {
+			config.Exchanges = append(config.Exchanges, exchange)
+		}
+	}
+	for _, symbol := range strings.Split(os.Getenv("FUNDING_FORECAST_SYMBOLS"), ",") {
+		if symbol = strings.TrimSpace(symbol); symbol != "" {
+			config.Symbols = append(config.Symbols, symbol)
+		}
+	}
+	return config
+}
+
+// newKPIStoreConfig reads the KPI store's bucket size, flush interval and
+// retention from KPI_BUCKET, KPI_FLUSH_INTERVAL and KPI_RETENTION.
+func newKPIStoreConfig() services.KPIStoreConfig {
+	config := services.KPIStoreConfig{}
+	for name, target := range map[string]*time.Duration{
+		"KPI_BUCKET":         &config.Bucket,
+		"KPI_FLUSH_INTERVAL": &config.FlushInterval,
+		"KPI_RETENTION":      &config.Retention,
+	} {
+		raw := os.Getenv(name)
+		if raw == "" {
+			continue
+		}
+		if value, err := time.ParseDuration(raw); err == nil && value > 0 {
+			*target = value
+		} else {
+			log.Printf("WARNING: Invalid %s value '%s', using default", name, raw)
+		}
+	}
+	return config
+}
+
+// newProfilerConfig builds the continuous profiler triggers and destination
+// from PROFILER_* environment variables.
+func newProfilerConfig() services.ProfilerConfig {
+	config := services.ProfilerConfig{
+		Destination: os.Getenv("PROFILER_DESTINATION"),
+		UploadToken: os.Getenv("PROFILER_UPLOAD_TOKEN"),
+	}
+	for name, target := range map[string]*time.Duration{
+		"PROFILER_LATENCY_THRESHOLD": &config.LatencyThreshold,
+		"PROFILER_CHECK_INTERVAL":    &config.CheckInterval,
+		"PROFILER_CPU_DURATION":      &config.CPUDuration,
+		"PROFILER_COOLDOWN":          &config.Cooldown,
+	} {
+		raw := os.Getenv(name)
+		if raw == "" {
+			continue
+		}
+		if value, err := time.ParseDuration(raw); err == nil && value > 0 {
+			*target = value
+		} else {
+			log.Printf("WARNING: Invalid %s value '%s', using default", name, raw)
+		}
+	}
+	if raw := os.Getenv("PROFILER_HEAP_THRESHOLD_MB"); raw != "" {
+		if value, err := strconv.ParseUint(raw, 10, 64); err == nil && value > 0 {
+			config.HeapThresholdMB = value
+		} else {
+			log.Printf("WARNING: Invalid PROFILER_HEAP_THRESHOLD_MB value '%s', using default", raw)
+		}
+	}
+	return config
+}
+
+// newNotificationChangeConfig builds the alert change thresholds from
+// NOTIFY_CHANGE_* environment variables.
+func newNotificationChangeConfig() services.NotificationChangeConfig {
+	config := services.NotificationChangeConfig{}
+	for name, target := range map[string]*float64{
+		"NOTIFY_CHANGE_SPREAD_DELTA":     &config.SpreadDelta,
+		"NOTIFY_CHANGE_CONFIDENCE_DELTA": &config.ConfidenceDelta,
+	} {
+		raw := os.Getenv(name)
+		if raw == "" {
+			continue
+		}
+		if value, err := strconv.ParseFloat(raw, 64); err == nil && value > 0 {
+			*target = value
+		} else {
+			log.Printf("WARNING: Invalid %s value '%s', using default", name, raw)
+		}
+	}
+	if raw := os.Getenv("NOTIFY_CHANGE_RENOTIFY_AFTER"); raw != "" {
+		if value, err := time.ParseDuration(raw); err == nil && value > 0 {
+			config.RenotifyAfter = value
+		} else {
+			log.Printf("WARNING: Invalid NOTIFY_CHANGE_RENOTIFY_AFTER value '%s', using default", raw)
+		}
+	}
+	if raw := os.Getenv("NOTIFY_CHANGE_MAX_ENTRIES"); raw != "" {
+		if value, err := strconv.Atoi(raw); err == nil && value > 0 {
+			config.MaxEntries = value
+		} else {
+			log.Printf("WARNING: Invalid NOTIFY_CHANGE_MAX_ENTRIES value '%s', using default", raw)
+		}
+	}
+	return config
+}
+
+// newLoadSheddingConfig builds the load shedding thresholds and actions from
+// LOAD_SHEDDING_* environment variables.
+func newLoadSheddingConfig() services.LoadSheddingConfig {
+	config := services.LoadSheddingConfig{}
+	for name, target := range map[string]*float64{
+		"LOAD_SHEDDING_CPU_ELEVATED":    &config.CPUElevated,
+		"LOAD_SHEDDING_CPU_CRITICAL":    &config.CPUCritical,
+		"LOAD_SHEDDING_MEMORY_ELEVATED": &config.MemoryElevated,
+		"LOAD_SHEDDING_MEMORY_CRITICAL": &config.MemoryCritical,
+		"LOAD_SHEDDING_SYMBOL_FRACTION": &config.SymbolFraction,
+		"LOAD_SHEDDING_INTERVAL_FACTOR": &config.IntervalFactor,
+	} {
+		raw := os.Getenv(name)
+		if raw == "" {
+			continue
+		}
+		if value, err := strconv.ParseFloat(raw, 64); err == nil && value > 0 {
+			*target = value
+		} else {
+			log.Printf("WARNING: Invalid %s value '%s', using default", name, raw)
+		}
+	}
+	if raw := os.Getenv("LOAD_SHEDDING_SAMPLE_INTERVAL"); raw != "" {
+		if value, err := time.ParseDuration(raw); err == nil && value > 0 {
+			config.SampleInterval = value
+		} else {
+			log.Printf("WARNING: Invalid LOAD_SHEDDING_SAMPLE_INTERVAL value '%s', using default", raw)
+		}
+	}
+	if raw, ok := os.LookupEnv("LOAD_SHEDDING_NON_CRITICAL_QUESTS"); ok {
+		config.NonCriticalQuests = []string{}
+		for _, id := range strings.Split(raw, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				config.NonCriticalQuests = append(config.NonCriticalQuests, id)
+			}
+		}
+	}
+	return config
+}
+
+// newStartupGuardConfig reads the unclean shutdown policy from
+// UNCLEAN_SHUTDOWN_POLICY: "hold" (default) keeps autonomous trading paused
+// until /resume, "resume" only informs the operator.
+func newStartupGuardConfig() services.StartupGuardConfig {
+	policy := strings.ToLower(getEnvOrDefault("UNCLEAN_SHUTDOWN_POLICY", "hold"))
+	if policy != "hold" && policy != "resume" {
+		log.Printf("WARNING: Invalid UNCLEAN_SHUTDOWN_POLICY value '%s', using default", policy)
+		policy = "hold"
+	}
+	return services.StartupGuardConfig{RequireResume: policy == "hold"}
+}
+
+// newPositionRegistryConfig builds the cross-strategy overlap rule from
+// POSITION_OVERLAP_* environment variables.
+func newPositionRegistryConfig() services.PositionRegistryConfig {
+	config := services.PositionRegistryConfig{
+		Mode: services.PositionOverlapMode(strings.ToLower(getEnvOrDefault("POSITION_OVERLAP_MODE", "block"))),
+	}
+	for _, symbol := range strings.Split(os.Getenv("POSITION_OVERLAP_ALLOW_SYMBOLS"), ",") {
+		if symbol = strings.TrimSpace(symbol); symbol != "" {
+			config.AllowSymbols = append(config.AllowSymbols, symbol)
+		}
+	}
+	if raw := os.Getenv("POSITION_OVERLAP_STALE_AFTER"); raw != "" {
+		if value, err := time.ParseDuration(raw); err == nil {
+			config.StaleAfter = value
+		} else {
+			log.Printf("WARNING: Invalid POSITION_OVERLAP_STALE_AFTER value '%s', using default", raw)
+		}
+	}
+	return config
+}
+
+// newStrategyOrderDefaults reads each strategy's order flags from
+// ORDER_TIME_IN_FORCE_<STRATEGY>, ORDER_POST_ONLY_<STRATEGY> and
+// ORDER_MAKER_FIRST_<STRATEGY>.
+func newStrategyOrderDefaults() services.StrategyOrderDefaults {
+	defaults := services.StrategyOrderDefaults{}
+	for _, strategy := range []string{services.StrategyScalping, services.StrategyArbitrage, services.StrategyFundingArbitrage} {
+		suffix := strings.ToUpper(strategy)
+		options := services.OrderOptions{
+			TimeInForce: strings.ToUpper(strings.TrimSpace(os.Getenv("ORDER_TIME_IN_FORCE_" + suffix))),
+		}
+		if raw := os.Getenv("ORDER_POST_ONLY_" + suffix); raw != "" {
+			if value, err := strconv.ParseBool(raw); err == nil {
+				options.PostOnly = value
+			} else {
+				log.Printf("WARNING: Invalid ORDER_POST_ONLY_%s value '%s', using default", suffix, raw)
+			}
+		}
+		if raw := os.Getenv("ORDER_MAKER_FIRST_" + suffix); raw != "" {
+			if value, err := strconv.ParseBool(raw); err == nil {
+				options.MakerFirst = value
+			} else {
+				log.Printf("WARNING: Invalid ORDER_MAKER_FIRST_%s value '%s', using default", suffix, raw)
+			}
+		}
+		if err := options.Validate("limit"); err != nil {
+			log.Printf("WARNING: Invalid order defaults for %s, ignoring them: %v", strategy, err)
+			continue
+		}
+		if !options.IsZero() {
+			defaults[strategy] = options
+		}
+	}
+	return defaults
+}
+
+// newMakerFirstConfig reads maker-first timeouts and fee estimates from
+// MAKER_FIRST_* environment variables.
+func newMakerFirstConfig() services.MakerFirstConfig {
+	var config services.MakerFirstConfig
+	for env, target := range map[string]*time.Duration{
+		"MAKER_FIRST_TIMEOUT":       &config.Timeout,
+		"MAKER_FIRST_POLL_INTERVAL": &config.PollInterval,
+	} {
+		if raw := os.Getenv(env); raw != "" {
+			if value, err := time.ParseDuration(raw); err == nil {
+				*target = value
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', using default", env, raw)
+			}
+		}
+	}
+	for env, target := range map[string]*float64{
+		"MAKER_FIRST_INSIDE_SPREAD": &config.InsideSpread,
+		"MAKER_FEE_PCT":             &config.MakerFeePct,
+		"TAKER_FEE_PCT":             &config.TakerFeePct,
+	} {
+		if raw := os.Getenv(env); raw != "" {
+			if value, err := strconv.ParseFloat(raw, 64); err == nil {
+				*target = value
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', using default", env, raw)
+			}
+		}
+	}
+	return config
+}
+
+// newUserDataStreamConfig reads the exchanges whose order and balance updates
+// are streamed from USER_DATA_STREAM_* environment variables.
+func newUserDataStreamConfig(serviceURL, apiKey string) services.UserDataStreamConfig {
+	config := services.UserDataStreamConfig{ServiceURL: serviceURL, APIKey: apiKey}
+	for _, exchange := range strings.Split(os.Getenv("USER_DATA_STREAM_EXCHANGES"), ",") {
+		if exchange = strings.ToLower(strings.TrimSpace(exchange)); exchange != "" {
+			config.Exchanges = append(config.Exchanges, exchange)
+		}
+	}
+	for env, target := range map[string]*time.Duration{
+		"USER_DATA_STREAM_RECONNECT_DELAY":     &config.ReconnectDelay,
+		"USER_DATA_STREAM_MAX_RECONNECT_DELAY": &config.MaxReconnectDelay,
+	} {
+		if raw := os.Getenv(env); raw != "" {
+			if value, err := time.ParseDuration(raw); err == nil {
+				*target = value
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', using default", env, raw)
+			}
+		}
+	}
+	return config
+}
+
+// newAlgoOrderConfig reads TWAP and iceberg defaults from ALGO_* environment
+// variables.
+func newAlgoOrderConfig() services.AlgoOrderManagerConfig {
+	var config services.AlgoOrderManagerConfig
+	if raw := os.Getenv("ALGO_TWAP_SLICES"); raw != "" {
+		if value, err := strconv.Atoi(raw); err == nil {
+			config.DefaultSlices = value
+		} else {
+			log.Printf("WARNING: Invalid ALGO_TWAP_SLICES value '%s', using default", raw)
+		}
+	}
+	for env, target := range map[string]*time.Duration{
+		"ALGO_TWAP_DURATION":   &config.DefaultDuration,
+		"ALGO_ICEBERG_TIMEOUT": &config.IcebergTimeout,
+	} {
+		if raw := os.Getenv(env); raw != "" {
+			if value, err := time.ParseDuration(raw); err == nil {
+				*target = value
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', using default", env, raw)
+			}
+		}
+	}
+	if raw := os.Getenv("ALGO_MAX_ADVERSE_MOVE_PCT"); raw != "" {
+		if value, err := strconv.ParseFloat(raw, 64); err == nil {
+			config.DefaultMaxAdverseMovePct = value
+		} else {
+			log.Printf("WARNING: Invalid ALGO_MAX_ADVERSE_MOVE_PCT value '%s', using default", raw)
+		}
+	}
+	return config
+}
+
+// newTradingReportConfig builds scheduled report settings from REPORT_*
+// environment variables.
+func newTradingReportConfig() services.TradingReportConfig {
+	var config services.TradingReportConfig
+	for _, recipient := range strings.Split(os.Getenv("REPORT_EMAIL_RECIPIENTS"), ",") {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			config.EmailRecipients = append(config.EmailRecipients, recipient)
+		}
+	}
+	if raw := os.Getenv("REPORT_CALENDAR_LOOKAHEAD"); raw != "" {
+		if value, err := time.ParseDuration(raw); err == nil {
+			config.CalendarLookahead = value
+		} else {
+			log.Printf("WARNING: Invalid REPORT_CALENDAR_LOOKAHEAD value '%s', using default", raw)
+		}
+	}
+	return config
+}
+
+// newReportEmailConfig builds the SMTP channel reports are emailed through
+// from SMTP_* environment variables; it is nil without SMTP_HOST.
+func newReportEmailConfig() *services.EmailChannelConfig {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return nil
+	}
+	port, err := strconv.Atoi(getEnvOrDefault("SMTP_PORT", "587"))
+	if err != nil {
+		log.Printf("WARNING: Invalid SMTP_PORT value '%s', using 587", os.Getenv("SMTP_PORT"))
+		port = 587
+	}
+	return &services.EmailChannelConfig{
+		SMTPHost:    host,
+		SMTPPort:    port,
+		Username:    os.Getenv("SMTP_USERNAME"),
+		Password:    os.Getenv("SMTP_PASSWORD"),
+		FromAddress: os.Getenv("SMTP_FROM_ADDRESS"),
+		FromName:    getEnvOrDefault("SMTP_FROM_NAME", "NeuraTrade"),
+		Enabled:     true,
+	}
+}
+
+// newTradeFlowConfig builds trade-tape analyzer settings from TRADE_FLOW_*
+// environment variables.
+func newTradeFlowConfig() services.TradeFlowConfig {
+	var config services.TradeFlowConfig
+	for key, target := range map[string]*time.Duration{
+		"TRADE_FLOW_WINDOW":           &config.Window,
+		"TRADE_FLOW_REFRESH_INTERVAL": &config.RefreshInterval,
+	} {
+		if raw := os.Getenv(key); raw != "" {
+			if value, err := time.ParseDuration(raw); err == nil {
+				*target = value
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', using default", key, raw)
+			}
+		}
+	}
+	for key, target := range map[string]*float64{
+		"TRADE_FLOW_LARGE_PRINT_MULTIPLE":     &config.LargePrintMultiple,
+		"TRADE_FLOW_MIN_LARGE_PRINT_NOTIONAL": &config.MinLargePrintNotional,
+	} {
+		if raw := os.Getenv(key); raw != "" {
+			if value, err := strconv.ParseFloat(raw, 64); err == nil {
+				*target = value
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', using default", key, raw)
+			}
+		}
+	}
+	return config
+}
+
+// newCriticalEscalationConfig builds the escalation of unacknowledged
+// critical alerts from CRITICAL_ESCALATION_* and KILL_SWITCH_NOTIFY_CHAT_IDS.
+func newCriticalEscalationConfig() services.CriticalEscalationConfig {
+	var config services.CriticalEscalationConfig
+	if raw := os.Getenv("CRITICAL_ESCALATION_ACK_TIMEOUT"); raw != "" {
+		if value, err := time.ParseDuration(raw); err == nil && value > 0 {
+			config.AckTimeout = value
+		} else {
+			log.Printf("WARNING: Invalid CRITICAL_ESCALATION_ACK_TIMEOUT value '%s', using default", raw)
+		}
+	}
+	for _, raw := range strings.Split(os.Getenv("KILL_SWITCH_NOTIFY_CHAT_IDS"), ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		if chatID, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			config.KillSwitchChatIDs = append(config.KillSwitchChatIDs, chatID)
+		} else {
+			log.Printf("WARNING: Invalid KILL_SWITCH_NOTIFY_CHAT_IDS entry '%s', ignoring", raw)
+		}
+	}
+	return config
+}
+
+// newTwilioChannelConfig builds the SMS/voice channel critical alerts
+// escalate to from TWILIO_* and ESCALATION_* environment variables.
+func newTwilioChannelConfig() services.TwilioChannelConfig {
+	var phones []string
+	for _, raw := range strings.Split(os.Getenv("ESCALATION_PHONE_NUMBERS"), ",") {
+		if raw = strings.TrimSpace(raw); raw != "" {
+			phones = append(phones, raw)
+		}
+	}
+	config := services.TwilioChannelConfig{
+		AccountSID: os.Getenv("TWILIO_ACCOUNT_SID"),
+		AuthToken:  os.Getenv("TWILIO_AUTH_TOKEN"),
+		FromNumber: os.Getenv("TWILIO_FROM_NUMBER"),
+		ToNumbers:  phones,
+		Voice:      getEnvOrDefault("ESCALATION_VOICE_CALL", "false") == "true",
+		Enabled:    true,
+	}
+	if config.AccountSID == "" || config.AuthToken == "" || config.FromNumber == "" || len(phones) == 0 {
+		log.Printf("WARNING: Critical alert escalation needs TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN, TWILIO_FROM_NUMBER and ESCALATION_PHONE_NUMBERS; escalations will fail")
+	}
+	return config
+}
+
+// newPositionTrackerConfig builds position tracking settings, including the
+// liquidation alert buffers, from LIQUIDATION_* environment variables.
+func newPositionTrackerConfig() services.PositionTrackerConfig {
+	config := services.DefaultPositionTrackerConfig()
+	if raw := os.Getenv("LIQUIDATION_ALERT_BUFFERS"); raw != "" {
+		var buffers []float64
+		for _, entry := range strings.Split(raw, ",") {
+			if entry = strings.TrimSpace(entry); entry == "" {
+				continue
+			}
+			if value, err := strconv.ParseFloat(entry, 64); err == nil && value > 0 && value < 1 {
+				buffers = append(buffers, value)
+			} else {
+				log.Printf("WARNING: Invalid LIQUIDATION_ALERT_BUFFERS entry '%s', ignoring", entry)
+			}
+		}
+		if len(buffers) > 0 {
+			config.LiquidationBuffers = buffers
+		}
+	}
+	if raw := os.Getenv("LIQUIDATION_NOTIFY_CHAT_ID"); raw != "" {
+		if chatID, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			config.NotifyChatID = chatID
+		} else {
+			log.Printf("WARNING: Invalid LIQUIDATION_NOTIFY_CHAT_ID value '%s', ignoring", raw)
+		}
+	}
+	return config
+}
+
+// newShadowStrategyConfig builds the shadow scalping variant from
+// SHADOW_STRATEGY_* environment variables.
+//
+// Returns:
+//
+//	services.ShadowStrategyConfig: The variant configuration.
+//	bool: Whether shadow mode is enabled.
+func newShadowStrategyConfig() (services.ShadowStrategyConfig, bool) {
+	config := services.ShadowStrategyConfig{
+		Name:        getEnvOrDefault("SHADOW_STRATEGY_NAME", "shadow"),
+		PromptNotes: os.Getenv("SHADOW_STRATEGY_PROMPT_NOTES"),
+	}
+	if getEnvOrDefault("SHADOW_STRATEGY_ENABLED", "false") != "true" {
+		return config, false
+	}
+	for key, target := range map[string]*float64{
+		"SHADOW_STRATEGY_MIN_CONFIDENCE":  &config.MinConfidence,
+		"SHADOW_STRATEGY_MAX_CAPITAL_PCT": &config.MaxCapitalPct,
+	} {
+		if raw := os.Getenv(key); raw != "" {
+			if value, err := strconv.ParseFloat(raw, 64); err == nil {
+				*target = value
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', using the live value", key, raw)
+			}
+		}
+	}
+	for key, target := range map[string]*decimal.Decimal{
+		"SHADOW_STRATEGY_INITIAL_CAPITAL":    &config.InitialCapital,
+		"SHADOW_STRATEGY_COMMISSION_PERCENT": &config.CommissionPercent,
+		"SHADOW_STRATEGY_SLIPPAGE_PERCENT":   &config.SlippagePercent,
+	} {
+		if raw := os.Getenv(key); raw != "" {
+			if value, err := decimal.NewFromString(raw); err == nil {
+				*target = value
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', using default", key, raw)
+			}
+		}
+	}
+	return config, true
+}
+
+// newPromptCanaryConfig builds the prompt/model canary rollout from
+// PROMPT_CANARY_* environment variables.
+//
+// Returns:
+//
+//	services.PromptCanaryConfig: The rollout configuration.
+//	bool: Whether a canary is configured.
+func newPromptCanaryConfig() (services.PromptCanaryConfig, bool) {
+	config := services.PromptCanaryConfig{
+		Control: services.PromptVersion{
+			Name:  getEnvOrDefault("PROMPT_CANARY_CONTROL_NAME", "control"),
+			Model: os.Getenv("PROMPT_CANARY_CONTROL_MODEL"),
+		},
+		Canary: services.PromptVersion{
+			Name:        getEnvOrDefault("PROMPT_CANARY_NAME", "canary"),
+			Model:       os.Getenv("PROMPT_CANARY_MODEL"),
+			PromptNotes: os.Getenv("PROMPT_CANARY_PROMPT_NOTES"),
+		},
+		Exchange: os.Getenv("PROMPT_CANARY_EXCHANGE"),
+	}
+	if getEnvOrDefault("PROMPT_CANARY_ENABLED", "false") != "true" {
+		return config, false
+	}
+	if config.Canary.Model == "" && config.Canary.PromptNotes == "" {
+		log.Printf("WARNING: PROMPT_CANARY_ENABLED is set without PROMPT_CANARY_MODEL or PROMPT_CANARY_PROMPT_NOTES, canary disabled")
+		return config, false
+	}
+	for key, target := range map[string]*float64{
+		"PROMPT_CANARY_FRACTION":                 &config.Fraction,
+		"PROMPT_CANARY_MAX_UNDERPERFORMANCE_PCT": &config.MaxUnderperformancePct,
+	} {
+		if raw := os.Getenv(key); raw != "" {
+			if value, err := strconv.ParseFloat(raw, 64); err == nil {
+				*target = value
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', using default", key, raw)
+			}
+		}
+	}
+	for key, target := range map[string]*time.Duration{
+		"PROMPT_CANARY_HORIZON":        &config.Horizon,
+		"PROMPT_CANARY_CHECK_INTERVAL": &config.CheckInterval,
+	} {
+		if raw := os.Getenv(key); raw != "" {
+			if value, err := time.ParseDuration(raw); err == nil {
+				*target = value
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', using default", key, raw)
+			}
+		}
+	}
+	if raw := os.Getenv("PROMPT_CANARY_MIN_SAMPLES"); raw != "" {
+		if value, err := strconv.Atoi(raw); err == nil {
+			config.MinSamples = value
+		} else {
+			log.Printf("WARNING: Invalid PROMPT_CANARY_MIN_SAMPLES value '%s', using default", raw)
+		}
+	}
+	return config, true
+}
+
+// newHookConfig reads the pre-trade, post-trade and pre-notify hooks from
+// HOOKS_* environment variables: comma-separated webhook URLs per extension
+// point and Go plugin paths.
+func newHookConfig() hookRouteConfig {
+	config := hookRouteConfig{
+		config:        services.HookConfig{FailOpen: getEnvOrDefault("HOOKS_FAIL_OPEN", "false") == "true"},
+		webhookSecret: os.Getenv("HOOKS_WEBHOOK_SECRET"),
+	}
+	if raw := os.Getenv("HOOKS_TIMEOUT"); raw != "" {
+		if timeout, err := time.ParseDuration(raw); err == nil && timeout > 0 {
+			config.config.Timeout = timeout
+		} else {
+			log.Printf("WARNING: Invalid HOOKS_TIMEOUT value '%s', using default", raw)
+		}
+	}
+
+	// A URL listed for several extension points is one hook handling each
+	seen := make(map[string]int)
+	for _, source := range []struct {
+		key   string
+		event services.HookEvent
+	}{
+		{"HOOKS_PRE_TRADE_URLS", services.HookPreTrade},
+		{"HOOKS_POST_TRADE_URLS", services.HookPostTrade},
+		{"HOOKS_PRE_NOTIFY_URLS", services.HookPreNotify},
+	} {
+		for _, raw := range strings.Split(os.Getenv(source.key), ",") {
+			if hookURL := strings.TrimSpace(raw); hookURL != "" {
+				index, ok := seen[hookURL]
+				if !ok {
+					index = len(config.webhooks)
+					seen[hookURL] = index
+					config.webhooks = append(config.webhooks, hookWebhookConfig{url: hookURL})
+				}
+				config.webhooks[index].events = append(config.webhooks[index].events, source.event)
+			}
+		}
+	}
+
+	for _, raw := range strings.Split(os.Getenv("HOOKS_PLUGINS"), ",") {
+		if path := strings.TrimSpace(raw); path != "" {
+			config.plugins = append(config.plugins, path)
+		}
+	}
+	return config
+}
+
+// newMarginCheckConfig builds the pre-trade margin check configuration from
+// MARGIN_CHECK_* environment variables.
+//
+// Returns:
+//
+//	services.MarginCheckConfig: The configuration; unset floors only reject
+//	orders the free balance cannot cover.
+func newMarginCheckConfig() services.MarginCheckConfig {
+	var config services.MarginCheckConfig
+	for key, target := range map[string]*decimal.Decimal{
+		"MARGIN_CHECK_MIN_FREE":       &config.MinFreeMargin,
+		"MARGIN_CHECK_MIN_FREE_RATIO": &config.MinFreeMarginRatio,
+		"MARGIN_CHECK_LEVERAGE":       &config.Leverage,
+		"MARGIN_CHECK_FEE_RATE":       &config.FeeRate,
+	} {
+		if raw := os.Getenv(key); raw != "" {
+			if value, err := decimal.NewFromString(raw); err == nil && !value.IsNegative() {
+				*target = value
+			} else {
+				log.Printf("WARNING: Invalid %s value '%s', using default", key, raw)
+			}
+		}
+	}
+	return config
+}
+
+// newEquityConfig builds the account valuation configuration from EQUITY_*
+// environment variables.
+//
+// Returns:
+//
+//	services.EquityConfig: The configuration; binance valued in USDT by default.
+func newEquityConfig() services.EquityConfig {
+	config := services.EquityConfig{Currency: getEnvOrDefault("EQUITY_CURRENCY", "USDT")}
+	for _, raw := range strings.Split(getEnvOrDefault("EQUITY_EXCHANGES", "binance"), ",") {
+		if exchange := strings.ToLower(strings.TrimSpace(raw)); exchange != "" {
+			config.Exchanges = append(config.Exchanges, exchange)
+		}
+	}
+	if raw := os.Getenv("EQUITY_CACHE_TTL"); raw != "" {
+		if value, err := time.ParseDuration(raw); err == nil {
+			config.CacheTTL = value
+		} else {
+			log.Printf("WARNING: Invalid EQUITY_CACHE_TTL value '%s', using default", raw)
+		}
+	}
+	return config
+}
+
+// newEquityWalletConfig reads the external wallet valuation from
+// EQUITY_WALLET_* environment variables: the stablecoin balance on the EVM
+// chain behind EQUITY_WALLET_RPC_URL, by default USDC on Polygon.
+//
+// Returns:
+//
+//	*equityWalletConfig: The configuration, or nil without EQUITY_WALLET_RPC_URL.
+func newEquityWalletConfig() *equityWalletConfig {
+	rpcURL := os.Getenv("EQUITY_WALLET_RPC_URL")
+	if rpcURL == "" {
+		return nil
+	}
+	config := &equityWalletConfig{
+		rpcURL:   rpcURL,
+		token:    getEnvOrDefault("EQUITY_WALLET_TOKEN", "0x2791Bca1f2de4661ED88A30C99A7a9449Aa84174"),
+		decimals: 6,
+	}
+	if raw := os.Getenv("EQUITY_WALLET_TOKEN_DECIMALS"); raw != "" {
+		if value, err := strconv.Atoi(raw); err == nil && value >= 0 && value <= 36 {
+			config.decimals = int32(value)
+		} else {
+			log.Printf("WARNING: Invalid EQUITY_WALLET_TOKEN_DECIMALS value '%s', using default", raw)
+		}
+	}
+	return config
+}
+
+// appVersion returns the backend version from APP_VERSION, or "dev".
+func appVersion() string {
+	return getEnvOrDefault("APP_VERSION", "dev")
+}
+
+// newEmbedderConfig reads the embedding provider from EMBEDDING_*
+// environment variables. The local hashing embedder is the default and needs
+// no API key.
+//
+// Returns:
+//
+//	*services.OpenAIEmbedderConfig: The OpenAI-compatible endpoint, or nil for the hashing embedder.
+func newEmbedderConfig() *services.OpenAIEmbedderConfig {
+	provider := getEnvOrDefault("EMBEDDING_PROVIDER", services.EmbeddingProviderHashing)
+	switch provider {
+	case services.EmbeddingProviderOpenAI:
+		apiKey := os.Getenv("EMBEDDING_API_KEY")
+		if apiKey == "" {
+			log.Printf("WARNING: EMBEDDING_PROVIDER is openai but EMBEDDING_API_KEY is not set, using hashing")
+			break
+		}
+		return &services.OpenAIEmbedderConfig{
+			BaseURL:    os.Getenv("EMBEDDING_BASE_URL"),
+			APIKey:     apiKey,
+			Model:      os.Getenv("EMBEDDING_MODEL"),
+			Dimensions: services.DefaultDecisionEmbeddingDims,
+		}
+	case services.EmbeddingProviderHashing:
+	default:
+		log.Printf("WARNING: Invalid EMBEDDING_PROVIDER value '%s', using hashing", provider)
+	}
+	return nil
+}
+
+// newEmbeddingPipelineConfig builds the embedding batch, flush and cost
+// settings from EMBEDDING_* environment variables.
+func newEmbeddingPipelineConfig() services.EmbeddingPipelineConfig {
+	config := services.DefaultEmbeddingPipelineConfig()
+	if raw := os.Getenv("EMBEDDING_BATCH_SIZE"); raw != "" {
+		if value, err := strconv.Atoi(raw); err == nil && value > 0 {
+			config.BatchSize = value
+		} else {
+			log.Printf("WARNING: Invalid EMBEDDING_BATCH_SIZE value '%s', using default", raw)
+		}
+	}
+	if raw := os.Getenv("EMBEDDING_FLUSH_INTERVAL"); raw != "" {
+		if value, err := time.ParseDuration(raw); err == nil && value > 0 {
+			config.FlushInterval = value
+		} else {
+			log.Printf("WARNING: Invalid EMBEDDING_FLUSH_INTERVAL value '%s', using default", raw)
+		}
+	}
+	if raw := os.Getenv("EMBEDDING_COST_PER_1K_TOKENS"); raw != "" {
+		if value, err := decimal.NewFromString(raw); err == nil && !value.IsNegative() {
+			config.CostPer1KTokens = value
+		} else {
+			log.Printf("WARNING: Invalid EMBEDDING_COST_PER_1K_TOKENS value '%s', ignoring", raw)
+		}
+	}
+	return config
+}
+
+// newMinClientVersions builds the minimum supported client versions from
+// COMPAT_MIN_CLI_VERSION and COMPAT_MIN_TELEGRAM_VERSION. An empty value
+// disables the check for that client.
+//
+// Returns:
+//
+//	map[string]string: Minimum version by client name.
+func newMinClientVersions() map[string]string {
+	minVersions := make(map[string]string)
+	for key, client := range map[string]string{
+		"COMPAT_MIN_CLI_VERSION":      middleware.ClientCLI,
+		"COMPAT_MIN_TELEGRAM_VERSION": middleware.ClientTelegram,
+	} {
+		raw := getEnvOrDefault(key, "1.0.0")
+		if raw == "none" {
+			continue
+		}
+		if _, err := utils.ParseVersion(raw); err != nil {
+			log.Printf("WARNING: Invalid %s value '%s', compatibility check disabled for %s", key, raw, client)
+			continue
+		}
+		minVersions[client] = raw
+	}
+	return minVersions
+}
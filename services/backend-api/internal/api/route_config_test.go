@@ -0,0 +1,69 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadRouteConfig_Defaults tests the features enabled and disabled by
+// default when no environment variable is set
+func TestLoadRouteConfig_Defaults(t *testing.T) {
+	t.Setenv("ADMIN_API_KEY", "test-admin-key-that-is-at-least-32-chars")
+
+	cfg := loadRouteConfig()
+
+	assert.NotNil(t, cfg.quota)
+	assert.NotNil(t, cfg.dailyLoss)
+	assert.NotNil(t, cfg.loopWatchdog)
+	assert.NotNil(t, cfg.newListings)
+	assert.Nil(t, cfg.eventBus)
+	assert.Nil(t, cfg.escalation)
+	assert.Nil(t, cfg.profiler)
+	assert.False(t, cfg.chaosEnabled)
+	assert.Equal(t, services.ExecutionModePaper, cfg.tradingMode.DefaultMode)
+	assert.True(t, cfg.aiDailyBudget.Equal(decimal.NewFromFloat(10)))
+	assert.True(t, cfg.aiMonthlyBudget.Equal(decimal.NewFromFloat(200)))
+}
+
+// TestLoadRouteConfig_InvalidValues tests that malformed values fall back to
+// their defaults rather than failing startup
+func TestLoadRouteConfig_InvalidValues(t *testing.T) {
+	t.Setenv("AI_DAILY_BUDGET", "ten")
+	t.Setenv("DAILY_LOSS_CAP_PCT", "lots")
+	t.Setenv("QUEST_MAX_FINISHED", "-1")
+	t.Setenv("LOOP_WATCHDOG_GRACE", "soon")
+
+	cfg := loadRouteConfig()
+
+	assert.True(t, cfg.aiDailyBudget.Equal(decimal.NewFromFloat(10)))
+	require.NotNil(t, cfg.dailyLoss)
+	assert.Zero(t, cfg.dailyLoss.MaxLossPct)
+	assert.Zero(t, cfg.quests.maxFinished)
+	require.NotNil(t, cfg.loopWatchdog)
+	assert.Zero(t, cfg.loopWatchdog.Grace)
+}
+
+// TestLoadRouteConfig_Overrides tests that feature switches and values are
+// read from the environment
+func TestLoadRouteConfig_Overrides(t *testing.T) {
+	t.Setenv("API_RATE_LIMIT_ENABLED", "false")
+	t.Setenv("DAILY_LOSS_CAP_ENABLED", "false")
+	t.Setenv("EVENT_BUS_DRIVER", "nats")
+	t.Setenv("EVENT_BUS_NATS_URL", "nats://localhost:4222")
+	t.Setenv("QUEST_START_JITTER", "0s")
+	t.Setenv("TRADING_GO_LIVE_NOTIFY_CHAT_IDS", "42, nope, 7")
+
+	cfg := loadRouteConfig()
+
+	assert.Nil(t, cfg.quota)
+	assert.Nil(t, cfg.dailyLoss)
+	require.NotNil(t, cfg.eventBus)
+	assert.Equal(t, "nats://localhost:4222", cfg.eventBus.URL)
+	assert.Equal(t, time.Duration(-1), cfg.quests.resume.StartJitter)
+	assert.Equal(t, []int64{42, 7}, cfg.tradingMode.NotifyChatIDs)
+}
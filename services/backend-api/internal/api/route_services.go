@@ -0,0 +1,1777 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/irfndi/neuratrade/internal/ai"
+	"github.com/irfndi/neuratrade/internal/ai/llm"
+	"github.com/irfndi/neuratrade/internal/api/handlers"
+	"github.com/irfndi/neuratrade/internal/cache"
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/chaos"
+	"github.com/irfndi/neuratrade/internal/config"
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/irfndi/neuratrade/internal/logging"
+	zaplogrus "github.com/irfndi/neuratrade/internal/logging/zaplogrus"
+	"github.com/irfndi/neuratrade/internal/middleware"
+	"github.com/irfndi/neuratrade/internal/services"
+	"github.com/irfndi/neuratrade/internal/services/eventbus"
+	"github.com/irfndi/neuratrade/internal/services/jobqueue"
+	"github.com/irfndi/neuratrade/internal/skill"
+	"github.com/irfndi/neuratrade/pkg/interfaces"
+	redisv9 "github.com/redis/go-redis/v9"
+)
+
+// routeServices holds the dependencies SetupRoutes receives and the services
+// it builds. Each setup method wires one feature against the services set up
+// before it, so SetupRoutes calls them in dependency order; stop shuts the
+// background services down.
+type routeServices struct {
+	cfg      routeConfig
+	handlers routeHandlers
+
+	db               routeDB
+	redis            *database.RedisClient
+	ccxt             ccxt.CCXTService
+	collector        *services.CollectorService
+	cleanup          *services.CleanupService
+	cacheAnalytics   *services.CacheAnalyticsService
+	signalAggregator *services.SignalAggregator
+	analytics        *services.AnalyticsService
+	telegramConfig   *config.TelegramConfig
+	aiConfig         *config.AIConfig
+	featuresConfig   *config.FeaturesConfig
+	auth             *middleware.AuthMiddleware
+	admin            *middleware.AdminMiddleware
+	walletValidator  *services.WalletValidator
+	drain            *services.ShutdownDrain
+	configService    *services.ConfigService
+
+	notifications        *services.NotificationService
+	notificationQueue    *services.NotificationDeliveryQueue
+	sentiment            *services.SentimentService
+	tradingModes         *services.TradingModeService
+	eventBus             eventbus.Bus
+	auditChain           *services.AuditChainService
+	eventEmitters        services.MultiEmitter
+	riskEventLog         *services.RiskEventLog
+	criticalEscalation   *services.CriticalEscalationService
+	watchlist            *services.WatchlistService
+	allocation           *services.CapitalAllocationService
+	profiles             *services.OperatingProfileService
+	listingDetector      *services.ListingDetector
+	decisionAudit        *services.DecisionAuditService
+	stateFingerprints    *services.StateFingerprintService
+	externalSignals      *services.ExternalSignalService
+	dbPoolMonitor        *services.DBPoolMonitor
+	questStore           services.QuestStore
+	questEngine          *services.QuestEngine
+	integrated           *services.IntegratedQuestHandlers
+	ccxtOrders           *services.CCXTOrderExecutor
+	orderExecutor        services.ScalpingOrderExecutor
+	kpiStore             *services.KPIStore
+	opportunityLifecycle *services.OpportunityLifecycleService
+	profiler             *services.Profiler
+	loadShedding         *services.ResourceManager
+	equity               *services.EquityService
+	tradingBudget        *services.TradingBudgetService
+	dailyLoss            *services.DailyLossCircuit
+	marginCheck          *services.MarginCheck
+	makerFirst           *services.MakerFirstExecutor
+	algoOrders           *services.AlgoOrderManager
+	stablecoins          *services.StablecoinMonitor
+	outageDetector       *services.ExchangeOutageDetector
+	symbolCooldowns      *services.SymbolCooldownTracker
+	positionRegistry     *services.PositionRegistry
+	intentLog            *services.IntentLog
+	hedging              *services.HedgingAdvisor
+	marginManager        *services.MarginManager
+	fundingForecaster    *services.FundingForecaster
+	positionTracker      *services.PositionTracker
+	exchangeFlattener    *services.ExchangeFlattener
+	userDataStream       *services.UserDataStream
+	pnlReporter          *services.PnLReporter
+	promptCanary         *services.PromptCanary
+	embeddingPipeline    *services.EmbeddingPipeline
+	startupGuard         *services.StartupGuard
+	uncleanShutdown      *services.UncleanShutdown
+	reconciled           []services.DecisionIntent
+	loopWatchdog         *services.TradingLoopWatchdog
+}
+
+// redisClient returns the Redis client, or nil when Redis is not configured.
+func (s *routeServices) redisClient() *redisv9.Client {
+	if s.redis == nil {
+		return nil
+	}
+	return s.redis.Client
+}
+
+// setupHealth builds the health handler, which reports drain progress.
+func (s *routeServices) setupHealth() {
+	s.handlers.health = handlers.NewHealthHandler(s.db, s.redis, s.ccxt.GetServiceURL(), s.cacheAnalytics)
+	if s.drain != nil {
+		s.handlers.health.SetDrainReporter(s.drain)
+	}
+}
+
+// setupNotifications builds the notification service with its format, quiet
+// hours, bounce tracking and change detection, and the durable delivery
+// queue: sends go through a Redis-backed job queue so that crashes do not
+// lose in-flight messages.
+func (s *routeServices) setupNotifications() {
+	cfg := s.cfg.notifications
+	if s.telegramConfig != nil {
+		s.notifications = services.NewNotificationService(s.db, s.redis, s.telegramConfig.ServiceURL, s.telegramConfig.GrpcAddress, s.telegramConfig.AdminAPIKey)
+	} else {
+		log.Printf("[TELEGRAM] WARNING: telegramConfig is nil, notification service will run with default settings")
+		s.notifications = services.NewNotificationService(s.db, s.redis, "http://telegram-service:3002", "telegram-service:50052", "")
+	}
+	if cfg.format != "" {
+		s.notifications.SetMessageFormat(cfg.format)
+	}
+	if cfg.quietHours != nil {
+		s.notifications.SetQuietHours(cfg.quietHours)
+	}
+
+	if client := s.redisClient(); client != nil {
+		if cfg.queue != nil {
+			s.notificationQueue = s.notifications.EnableDeliveryQueue(
+				jobqueue.New(client, jobqueue.Config{
+					Namespace: cfg.queue.Namespace,
+					WorkerID:  cfg.queueWorkerID,
+				}),
+				*cfg.queue,
+			)
+			if err := s.notificationQueue.Start(context.Background()); err != nil {
+				log.Printf("Failed to start notification delivery queue: %v", err)
+			}
+		}
+		s.notifications.SetDeliveryTracker(services.NewNotificationDeliveryTracker(client, cfg.bounceThreshold))
+	}
+	if cfg.changeDetection != nil {
+		s.notifications.SetChangeDetector(services.NewNotificationChangeDetector(*cfg.changeDetection))
+	}
+	s.handlers.notificationQueue = handlers.NewNotificationQueueHandler(s.notifications)
+}
+
+// setupCoreHandlers builds the market data, arbitrage, analysis, sentiment,
+// alert, exchange, cache, AI model and wallet handlers.
+func (s *routeServices) setupCoreHandlers() {
+	s.handlers.market = handlers.NewMarketHandler(s.db, s.ccxt, s.collector, s.redis, s.cacheAnalytics)
+	s.handlers.arbitrage = handlers.NewArbitrageHandler(s.db, s.ccxt, s.notifications, s.redis.Client)
+	s.handlers.circuitBreaker = handlers.NewCircuitBreakerHandler(s.collector)
+	s.handlers.analysis = handlers.NewAnalysisHandler(s.db, s.ccxt, s.analytics)
+
+	s.sentiment = services.NewSentimentService(s.cfg.sentiment, s.db)
+	s.handlers.sentiment = handlers.NewSentimentHandler(s.sentiment)
+
+	s.handlers.alert = handlers.NewAlertHandler(s.db)
+	s.handlers.cleanup = handlers.NewCleanupHandler(s.cleanup)
+	s.handlers.exchange = handlers.NewExchangeHandler(s.ccxt, s.collector, s.redis.Client)
+	s.handlers.cache = handlers.NewCacheHandler(s.cacheAnalytics)
+	s.handlers.webSocket = handlers.NewWebSocketHandler(s.redis)
+
+	aiRegistry := ai.NewRegistry(
+		ai.WithRedis(s.redis.Client),
+	)
+	s.handlers.ai = handlers.NewAIHandler(aiRegistry, s.db)
+	s.handlers.wallet = handlers.NewWalletHandler(s.walletValidator)
+}
+
+// setupTradingAPI builds the trading API over Polymarket CLOB order execution.
+func (s *routeServices) setupTradingAPI() {
+	orderExecutionService := services.NewOrderExecutionService(s.cfg.polymarket)
+	s.handlers.trading = handlers.NewTradingHandler(s.db, orderExecutionService)
+}
+
+// setupTradingModes builds the paper/live execution mode with a two-man rule
+// for destructive live actions.
+func (s *routeServices) setupTradingModes() {
+	var tradingModes handlers.TradingModeInterface
+	if client := s.redisClient(); client != nil {
+		s.tradingModes = services.NewTradingModeService(client, s.cfg.tradingMode)
+		s.tradingModes.RegisterActionHandler(services.ProtectedActionLiquidateAll, s.liquidateLedger)
+		s.handlers.trading.SetActionGuard(s.tradingModes)
+		s.tradingModes.SetGoLiveNotifier(s.notifications)
+		if err := s.tradingModes.Start(context.Background()); err != nil {
+			log.Printf("WARNING: failed to start the go-live scheduler: %v", err)
+		}
+		tradingModes = s.tradingModes
+	}
+	s.handlers.tradingMode = handlers.NewTradingModeHandler(tradingModes)
+}
+
+// liquidateLedger closes every position in the trading API ledger.
+func (s *routeServices) liquidateLedger(ctx context.Context) error {
+	_, err := s.handlers.trading.LiquidateAllPositions(ctx)
+	return err
+}
+
+// setupEventBus connects the internal event bus for opportunities, decisions
+// and fills. The streams and nats drivers deliver at least once; pubsub is
+// the fire-and-forget fallback.
+func (s *routeServices) setupEventBus() {
+	cfg := s.cfg.eventBus
+	if cfg == nil {
+		return
+	}
+	client := s.redisClient()
+	if cfg.Driver != eventbus.DriverNATS && client == nil {
+		log.Printf("WARNING: EVENT_BUS_DRIVER=%s requires Redis, event bus disabled", cfg.Driver)
+		return
+	}
+	bus, err := eventbus.New(client, *cfg)
+	if err != nil {
+		log.Printf("WARNING: Event bus disabled: %v", err)
+		return
+	}
+	s.eventBus = bus
+}
+
+// setupAuditChain starts the tamper-evident log: orders, positions,
+// decisions, outcomes and trade, risk and mode events are hash-chained and
+// the head is anchored periodically with a signature.
+func (s *routeServices) setupAuditChain() {
+	var auditChainVerifier handlers.AuditChainVerifier
+	if s.db != nil {
+		if s.cfg.auditChain.SigningKey == "" {
+			log.Printf("WARNING: AUDIT_CHAIN_SIGNING_KEY not set, audit chain anchors are unsigned")
+		}
+		s.auditChain = services.NewAuditChainService(s.db, s.cfg.auditChain)
+		s.auditChain.AddRecordSource(s.handlers.trading, services.AuditKindTradingOrder, services.AuditKindTradingPosition)
+		s.handlers.trading.SetAuditChain(s.auditChain)
+		if err := s.auditChain.Start(context.Background()); err != nil {
+			log.Printf("WARNING: failed to start audit chain anchoring: %v", err)
+		}
+		auditChainVerifier = s.auditChain
+	}
+	s.handlers.auditChain = handlers.NewAuditChainHandler(auditChainVerifier)
+}
+
+// setupEventEmitters builds the risk and trade event sinks: outbound webhooks
+// for third-party automation (n8n, Zapier, custom services), the risk event
+// log kept for the scheduled reports, the event bus, the audit chain and
+// critical alert escalation.
+func (s *routeServices) setupEventEmitters() {
+	var webhookManager handlers.WebhookManager
+	if client := s.redisClient(); client != nil {
+		webhookService := services.NewWebhookService(client, services.WebhookConfig{})
+		webhookManager = webhookService
+		s.riskEventLog = services.NewRiskEventLog(client, 0)
+		s.eventEmitters = append(s.eventEmitters, webhookService, s.riskEventLog)
+	}
+	if s.eventBus != nil {
+		s.eventEmitters = append(s.eventEmitters, services.NewEventBusEmitter(s.eventBus))
+	}
+	if s.auditChain != nil {
+		s.eventEmitters = append(s.eventEmitters, s.auditChain)
+	}
+	s.setupCriticalEscalation()
+	if len(s.eventEmitters) > 0 {
+		s.handlers.trading.SetEventEmitter(s.eventEmitters)
+		if s.tradingModes != nil {
+			s.tradingModes.SetEventEmitter(s.eventEmitters)
+		}
+	}
+	s.handlers.webhook = handlers.NewWebhookHandler(webhookManager)
+}
+
+// setupCriticalEscalation escalates critical risk alerts that nobody
+// acknowledges on Telegram by SMS or phone call; kill-switch trips are
+// alerted through it as well.
+func (s *routeServices) setupCriticalEscalation() {
+	var criticalEscalations handlers.CriticalEscalationInterface
+	if cfg := s.cfg.escalation; cfg != nil {
+		s.criticalEscalation = services.NewCriticalEscalationService(services.NewTwilioChannel(cfg.channel), cfg.config)
+		s.criticalEscalation.SetRiskNotifier(s.notifications)
+		s.notifications.SetEscalation(s.criticalEscalation)
+		s.eventEmitters = append(s.eventEmitters, s.criticalEscalation)
+		if err := s.criticalEscalation.Start(context.Background()); err != nil {
+			log.Printf("WARNING: failed to start critical alert escalation: %v", err)
+		}
+		criticalEscalations = s.criticalEscalation
+	}
+	s.handlers.criticalEscalation = handlers.NewCriticalEscalationHandler(criticalEscalations)
+}
+
+// setupWatchlist builds the symbol universe: per-chat watchlists plus a daily
+// "top N by volume" list.
+func (s *routeServices) setupWatchlist() {
+	var watchlistManager handlers.WatchlistManager
+	if client := s.redisClient(); client != nil {
+		s.watchlist = services.NewWatchlistService(s.db, client, s.cfg.watchlist)
+		if err := s.watchlist.Start(context.Background()); err != nil {
+			log.Printf("WARNING: failed to start watchlist service: %v", err)
+		}
+		watchlistManager = s.watchlist
+	}
+	s.handlers.watchlist = handlers.NewWatchlistHandler(watchlistManager)
+}
+
+// setupCustomAlerts builds user-defined price and indicator alerts, managed
+// from Telegram and the CLI; the server evaluates them with the signal
+// processor cycle, and previews replay rules against exchange candles.
+func (s *routeServices) setupCustomAlerts() {
+	var customAlertManager handlers.CustomAlertManager
+	if s.db != nil && s.cfg.customAlerts != nil {
+		customAlertManager = services.NewCustomAlertService(s.db, s.ccxt, *s.cfg.customAlerts)
+	}
+	s.handlers.customAlert = handlers.NewCustomAlertHandler(customAlertManager)
+}
+
+// setupCapitalAllocation builds per-chat strategy budgets enforced at order
+// sizing, optionally rebalanced towards the best performing strategies.
+func (s *routeServices) setupCapitalAllocation() {
+	var allocationManager handlers.CapitalAllocationManager
+	if client := s.redisClient(); client != nil {
+		s.allocation = services.NewCapitalAllocationService(client, s.cfg.capitalAllocation)
+		if err := s.allocation.Start(context.Background()); err != nil {
+			log.Printf("WARNING: failed to start capital allocation service: %v", err)
+		}
+		allocationManager = s.allocation
+	}
+	s.handlers.allocation = handlers.NewCapitalAllocationHandler(allocationManager)
+}
+
+// setupOperatingProfiles builds per-chat bundles of risk limits, position
+// sizing, scan frequency and AI usage selected at /begin, and shareable
+// strategy configurations: validated, secret-free documents whose risk caps
+// install as custom operating profiles.
+func (s *routeServices) setupOperatingProfiles() {
+	var profileManager handlers.OperatingProfileManager
+	var strategyManager handlers.StrategyConfigManager
+	if client := s.redisClient(); client != nil {
+		s.profiles = services.NewOperatingProfileService(client)
+		if err := s.profiles.Load(context.Background()); err != nil {
+			log.Printf("WARNING: failed to load operating profiles: %v", err)
+		}
+		profileManager = s.profiles
+
+		strategyService := services.NewStrategyConfigService(client, s.profiles)
+		strategySkills := skill.NewRegistry(filepath.Join(filepath.Dir(""), "skills"))
+		if err := strategySkills.LoadTree(); err != nil {
+			log.Printf("WARNING: failed to load skills for strategy configurations: %v", err)
+		} else {
+			strategyService.SetSkillCatalog(strategySkills)
+		}
+		strategyManager = strategyService
+	}
+	s.handlers.profile = handlers.NewOperatingProfileHandler(profileManager)
+	s.handlers.strategy = handlers.NewStrategyConfigHandler(strategyManager)
+}
+
+// setupWizard builds the first-run setup wizard: step progress for
+// neuratrade setup, with steps completed elsewhere detected and a dry-run
+// verification.
+func (s *routeServices) setupWizard() {
+	var setupManager handlers.SetupManager
+	if client := s.redisClient(); client != nil {
+		setupConfig := services.SetupConfig{}
+		if s.aiConfig != nil {
+			setupConfig.AIProvider = s.aiConfig.Provider
+			setupConfig.AIKeyConfigured = s.aiConfig.APIKey != ""
+		}
+		setupService := services.NewSetupService(client, s.db, setupConfig)
+		if s.profiles != nil {
+			setupService.SetCheck(services.SetupStepRiskLimits, s.checkRiskLimits)
+		}
+		if s.db != nil {
+			setupService.AddProbe("database", s.db.HealthCheck)
+		}
+		setupService.AddProbe("redis", s.pingRedis)
+		if s.ccxt != nil {
+			setupService.AddProbe("ccxt", s.checkCCXT)
+		}
+		setupManager = setupService
+	}
+	s.handlers.setup = handlers.NewSetupHandler(setupManager)
+}
+
+// checkRiskLimits completes the risk limits setup step once a chat has
+// selected an operating profile.
+func (s *routeServices) checkRiskLimits(context.Context) (bool, string, error) {
+	count := s.profiles.SelectedCount()
+	return count > 0, fmt.Sprintf("%d chat(s) with an operating profile", count), nil
+}
+
+// pingRedis probes Redis for the setup verification.
+func (s *routeServices) pingRedis(ctx context.Context) error {
+	return s.redis.Client.Ping(ctx).Err()
+}
+
+// checkCCXT probes the CCXT service for the setup verification.
+func (s *routeServices) checkCCXT(ctx context.Context) error {
+	if !s.ccxt.IsHealthy(ctx) {
+		return fmt.Errorf("ccxt service at %s is not healthy", s.ccxt.GetServiceURL())
+	}
+	return nil
+}
+
+// setupOperatorTools builds the deep doctor (one dry-run cycle from market
+// data to a test notification), the stress test (the strategy's recent
+// signals executed with added latency, partial fills and higher fees), on-
+// demand collection of one symbol, the effective configuration per mode and,
+// for staging only, fault injection into Redis, CCXT, LLM and database calls.
+func (s *routeServices) setupOperatorTools() {
+	s.handlers.selfTest = handlers.NewSelfTestHandler(services.NewSelfTestService(s.ccxt, s.notifications, services.DefaultSelfTestConfig()))
+	s.handlers.stressTest = handlers.NewStressTestHandler(services.NewStressTestService(s.ccxt, services.DefaultStressTestConfig()))
+
+	var onDemandCollector handlers.OnDemandCollector
+	if s.collector != nil {
+		onDemandCollector = s.collector
+	}
+	s.handlers.collection = handlers.NewCollectionHandler(onDemandCollector)
+
+	// The effective configuration follows the paper/live switch
+	var configInspector handlers.ConfigInspector
+	if s.configService != nil {
+		if s.tradingModes != nil {
+			s.configService.SetModeProvider(s.tradingModes)
+		}
+		configInspector = s.configService
+	}
+	s.handlers.config = handlers.NewConfigHandler(configInspector)
+
+	var faultInjector handlers.FaultInjector
+	if s.cfg.chaosEnabled {
+		faultInjector = chaos.Default()
+		log.Printf("WARNING: Chaos fault injection API enabled")
+	}
+	s.handlers.chaos = handlers.NewChaosHandler(faultInjector)
+}
+
+// setupUserData builds data deletion requests: personal data is removed or
+// anonymized while anonymized trading statistics are kept.
+func (s *routeServices) setupUserData() {
+	var userDataEraser handlers.UserDataEraser
+	if s.db != nil {
+		userDataService := services.NewUserDataService(s.db, s.redisClient())
+		if s.tradingModes != nil {
+			userDataService.SetOperatorRegistry(s.tradingModes)
+		}
+		userDataEraser = userDataService
+	}
+	s.handlers.userData = handlers.NewUserDataHandler(userDataEraser)
+}
+
+// setupNewListings reports new listings to operators and trades them under
+// conservative limits for a probation period, optionally via the
+// "new_listings" watchlist.
+func (s *routeServices) setupNewListings() {
+	var listingScanner handlers.ListingScanner
+	if client := s.redisClient(); client != nil && s.cfg.newListings != nil {
+		s.listingDetector = services.NewListingDetector(s.ccxt, client, *s.cfg.newListings)
+		if s.watchlist != nil {
+			s.listingDetector.SetWatchlist(s.watchlist)
+		}
+		s.listingDetector.SetMessenger(s.notifications)
+		if len(s.eventEmitters) > 0 {
+			s.listingDetector.SetEventEmitter(s.eventEmitters)
+		}
+		if err := s.listingDetector.Start(context.Background()); err != nil {
+			log.Printf("WARNING: failed to start new listing detector: %v", err)
+		}
+		listingScanner = s.listingDetector
+	}
+	s.handlers.newListings = handlers.NewNewListingsHandler(listingScanner)
+}
+
+// setupDecisionAudit builds the decision audit trail: every scalping and
+// external-signal decision is stored with its context for replay from
+// Telegram deep links. State fingerprints record the hashes of the effective
+// config and strategy parameters each decision was made under, so behavior
+// changes after a deploy can be traced to what changed. Decisions and
+// outcomes join the audit chain.
+func (s *routeServices) setupDecisionAudit() {
+	var decisionAudits handlers.DecisionAuditProvider
+	var stateDiffer handlers.StateDiffer
+	if s.db != nil && s.cfg.decisionAuditEnabled {
+		s.decisionAudit = services.NewDecisionAuditService(s.db, s.ccxt)
+		decisionAudits = s.decisionAudit
+
+		var configState services.StateSource
+		if s.configService != nil {
+			configState = s.effectiveConfigState
+		}
+		s.stateFingerprints = services.NewStateFingerprintService(s.db, configState)
+		s.decisionAudit.SetStateStamper(s.stateFingerprints)
+		stateDiffer = s.stateFingerprints
+
+		if s.auditChain != nil {
+			s.auditChain.AddRecordSource(s.decisionAudit, services.AuditKindDecision, services.AuditKindDecisionOutcome)
+			s.decisionAudit.SetAuditChain(s.auditChain)
+		}
+	}
+	s.handlers.decisionAudit = handlers.NewDecisionAuditHandler(decisionAudits)
+	s.handlers.stateDiff = handlers.NewStateDiffHandler(stateDiffer)
+}
+
+// effectiveConfigState is the active mode and its effective settings, for
+// state fingerprints.
+func (s *routeServices) effectiveConfigState(ctx context.Context) (map[string]interface{}, error) {
+	effective, err := s.configService.Effective(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	settings := map[string]interface{}{"active_mode": effective.Mode}
+	for key, value := range effective.Settings {
+		settings[key] = value
+	}
+	return settings, nil
+}
+
+// setupSessionReplay builds session replay: a recorded day re-fed through the
+// technical signals and trader agent, without an order executor, next to its
+// audited decisions.
+func (s *routeServices) setupSessionReplay() {
+	var sessionReplayer handlers.SessionReplayer
+	if s.db != nil && s.signalAggregator != nil {
+		sessionReplayer = services.NewSessionReplayService(s.db, s.signalAggregator, services.DefaultSessionReplayConfig())
+	}
+	s.handlers.sessionReplay = handlers.NewSessionReplayHandler(sessionReplayer)
+}
+
+// setupExternalSignals scores TradingView alerts with the signal processor;
+// they may be executed under the external-signal strategy, which has its own
+// risk caps.
+func (s *routeServices) setupExternalSignals() {
+	externalSignalProcessor := services.NewSignalProcessor(s.db, logging.NewStandardLogger("info", "production"), s.signalAggregator, nil, nil, s.notifications, s.collector, nil)
+	if s.watchlist != nil {
+		externalSignalProcessor.SetSymbolUniverse(s.watchlist)
+	}
+	s.externalSignals = services.NewExternalSignalService(s.cfg.externalSignals, externalSignalProcessor, s.handlers.trading)
+	if s.tradingModes != nil {
+		s.externalSignals.SetKillSwitch(s.tradingModes)
+	}
+	s.externalSignals.SetEventBus(s.eventBus)
+	if s.decisionAudit != nil {
+		s.externalSignals.SetDecisionRecorder(s.decisionAudit)
+	}
+	var externalSignals handlers.ExternalSignalIngestor
+	if s.externalSignals.Enabled() {
+		externalSignals = s.externalSignals
+	}
+	s.handlers.tradingView = handlers.NewTradingViewHandler(externalSignals)
+}
+
+// setupSignalIngest lets signals from external strategy engines compete in
+// the aggregator, weighted by each source's track record.
+func (s *routeServices) setupSignalIngest() {
+	var signalIngestor handlers.SignalIngestor
+	if s.signalAggregator != nil {
+		signalIngestService := services.NewSignalIngestService(s.signalAggregator, s.redisClient())
+		signalIngestService.SetEventBus(s.eventBus)
+		signalIngestor = signalIngestService
+	}
+	s.handlers.signalIngest = handlers.NewSignalIngestHandler(signalIngestor)
+}
+
+// setupBackups builds encrypted state snapshots for disaster recovery and
+// SQLite/Postgres migration.
+func (s *routeServices) setupBackups() {
+	var backupModes services.TradingModeBackup
+	if s.tradingModes != nil {
+		backupModes = s.tradingModes
+	}
+	var backupManager handlers.BackupManager
+	if s.db != nil {
+		backupManager = services.NewBackupService(s.db, backupModes)
+	}
+	s.handlers.backup = handlers.NewBackupHandler(backupManager)
+}
+
+// setupMigrations builds migration status and control. Live trading is
+// refused while migrations are pending so orders never run against a schema
+// the code does not expect.
+func (s *routeServices) setupMigrations() {
+	var migrationManager handlers.MigrationManager
+	if s.db != nil {
+		migrationService := services.NewMigrationService(s.db, s.cfg.migrationsDir)
+		if s.tradingModes != nil {
+			s.tradingModes.AddLiveTradingCheck(migrationService.CheckNoPending)
+		}
+		migrationManager = migrationService
+	}
+	s.handlers.migration = handlers.NewMigrationHandler(migrationManager)
+}
+
+// setupDBPoolMonitor reports connection pool statistics, with a risk event
+// when waits exceed the threshold.
+func (s *routeServices) setupDBPoolMonitor() {
+	var dbPoolMetrics handlers.DBPoolMetrics
+	if pools, ok := s.db.(database.PoolStatsProvider); ok {
+		s.dbPoolMonitor = services.NewDBPoolMonitor(pools, s.cfg.dbPool)
+		if len(s.eventEmitters) > 0 {
+			s.dbPoolMonitor.SetEventEmitter(s.eventEmitters)
+		}
+		if err := s.dbPoolMonitor.Start(context.Background()); err != nil {
+			log.Printf("WARNING: failed to start database pool monitor: %v", err)
+		}
+		dbPoolMetrics = s.dbPoolMonitor
+	}
+	s.handlers.dbPool = handlers.NewDBPoolHandler(dbPoolMetrics)
+}
+
+// setupAIBudget builds the AI spending budget handler.
+func (s *routeServices) setupAIBudget() {
+	s.handlers.budget = handlers.NewBudgetHandler(
+		database.NewAIUsageRepository(s.db),
+		s.cfg.aiDailyBudget,
+		s.cfg.aiMonthlyBudget,
+	)
+}
+
+// setupQuestEngine builds the quest engine and, when enabled, restores the
+// legacy active quests from the database.
+func (s *routeServices) setupQuestEngine() {
+	cfg := s.cfg.quests
+	s.questStore = services.NewInMemoryQuestStore()
+	s.questEngine = services.NewQuestEngineWithNotification(s.questStore, nil, s.notifications)
+	if len(s.eventEmitters) > 0 {
+		s.questEngine.SetEventEmitter(s.eventEmitters)
+	}
+	if cfg.maxFinished > 0 {
+		s.questEngine.SetMaxFinishedQuests(cfg.maxFinished)
+	}
+	s.questEngine.SetResumeConfig(cfg.resume)
+	cache.RegisterBoundedStats(s.questEngine)
+
+	log.Printf("DEBUG: db is nil: %v", s.db == nil)
+	if s.db != nil && cfg.loadLegacy {
+		s.loadLegacyQuests()
+	} else if s.db != nil {
+		log.Println("Skipping legacy active quest preload (set NEURATRADE_LOAD_LEGACY_ACTIVE_QUESTS=1 to enable)")
+	}
+}
+
+// loadLegacyQuests copies the active quests in the database into the quest store.
+func (s *routeServices) loadLegacyQuests() {
+	log.Println("Loading legacy active quests from database into memory...")
+	rows, err := s.db.Query(context.Background(), "SELECT id, type, cadence, status, target_value, checkpoint, created_at FROM quests WHERE status = 'active'")
+	if err != nil {
+		log.Printf("Failed to load quests from database: %v", err)
+		return
+	}
+	defer rows.Close()
+	loadedCount := 0
+	for rows.Next() {
+		var id, questType, cadence, status string
+		var targetValue float64
+		var checkpoint []byte
+		var createdAt time.Time
+		if err := rows.Scan(&id, &questType, &cadence, &status, &targetValue, &checkpoint, &createdAt); err != nil {
+			log.Printf("Failed to scan quest row: %v", err)
+			continue
+		}
+		quest := &services.Quest{
+			ID:          id,
+			Type:        services.QuestType(questType),
+			Cadence:     services.QuestCadence(cadence),
+			Status:      services.QuestStatus(status),
+			TargetCount: int(targetValue),
+			CreatedAt:   createdAt,
+			UpdatedAt:   time.Now(),
+		}
+		if len(checkpoint) > 0 {
+			var cp map[string]interface{}
+			if err := json.Unmarshal(checkpoint, &cp); err == nil {
+				quest.Checkpoint = cp
+			}
+		}
+		if err := s.questStore.SaveQuest(context.Background(), quest); err != nil {
+			log.Printf("Failed to save quest %s: %v", id, err)
+		}
+		log.Printf("Loaded quest from DB: %s (type: %s, status: %s)", id, questType, status)
+		loadedCount++
+	}
+	log.Printf("Loaded %d quests from database", loadedCount)
+}
+
+// setupIntegratedHandlers builds the quest handlers that run scalping,
+// arbitrage and monitoring quests, and the futures arbitrage handler they use.
+func (s *routeServices) setupIntegratedHandlers() {
+	if s.db != nil {
+		s.handlers.futuresArbitrage = handlers.NewFuturesArbitrageHandlerWithQuerier(s.db)
+		log.Printf("Futures arbitrage handler initialized successfully")
+	} else {
+		log.Printf("Database not available for futures arbitrage handler initialization")
+	}
+
+	// Create autonomous monitoring for tracking quest execution
+	autonomousMonitoring := services.NewAutonomousMonitorManager(s.notifications)
+
+	// Create integrated quest handlers with actual implementations
+	s.integrated = services.NewIntegratedQuestHandlers(
+		nil,                         // TA service - TODO: Initialize when ready
+		s.ccxt,                      // CCXT service
+		s.handlers.arbitrage,        // Arbitrage service
+		s.handlers.futuresArbitrage, // Futures arbitrage
+		s.notifications,             // Notification service
+		autonomousMonitoring,        // Monitoring service
+	)
+}
+
+// setupOrderExecutor builds the CCXT order executor used for scalping
+// execution. A graceful shutdown waits for orders already being submitted.
+func (s *routeServices) setupOrderExecutor() {
+	log.Printf("CCXT Order Executor configured with URL: %s", s.cfg.ccxtServiceURL)
+	s.ccxtOrders = services.NewCCXTOrderExecutor(services.CCXTOrderExecutorConfig{
+		ServiceURL: s.cfg.ccxtServiceURL,
+		APIKey:     s.cfg.adminAPIKey,
+		Timeout:    30 * time.Second,
+	})
+	s.ccxtOrders.SetShutdownDrain(s.drain)
+}
+
+// setupSymbolQuarantine blacklists an exchange/symbol pair for a while after
+// repeated order rejections, stale tickers or anomaly filter trips.
+func (s *routeServices) setupSymbolQuarantine() {
+	var symbolQuarantineManager handlers.SymbolQuarantineManager
+	if s.collector != nil && s.collector.BlacklistCache() != nil && s.cfg.quarantine != nil {
+		symbolQuarantine := services.NewSymbolQuarantine(s.collector.BlacklistCache(), *s.cfg.quarantine)
+		symbolQuarantine.SetMessenger(s.notifications)
+		if len(s.eventEmitters) > 0 {
+			symbolQuarantine.SetEventEmitter(s.eventEmitters)
+		}
+		s.collector.SetQuarantine(symbolQuarantine)
+		s.ccxtOrders.SetQuarantine(symbolQuarantine)
+		symbolQuarantineManager = symbolQuarantine
+	}
+	s.handlers.symbolQuarantine = handlers.NewSymbolQuarantineHandler(symbolQuarantineManager)
+}
+
+// setupKPIStore keeps internal KPI history: cycle durations, opportunities
+// found, orders placed and notification latency, for capacity planning.
+func (s *routeServices) setupKPIStore() {
+	var kpiSource handlers.KPISource
+	if s.db != nil && s.cfg.kpiStore != nil {
+		s.kpiStore = services.NewKPIStore(s.db, *s.cfg.kpiStore)
+		kpiSource = s.kpiStore
+		s.questEngine.SetKPIRecorder(s.kpiStore)
+		s.ccxtOrders.SetKPIRecorder(s.kpiStore)
+		s.notifications.SetKPIRecorder(s.kpiStore)
+		if s.signalAggregator != nil {
+			s.signalAggregator.SetKPIRecorder(s.kpiStore)
+		}
+		s.kpiStore.Start()
+	}
+	s.handlers.kpi = handlers.NewKPIHandler(kpiSource)
+}
+
+// setupOpportunityLifecycle tracks opportunity lifecycles: the arbitrage scan
+// opens and expires them, alerts and executions from notifications advance
+// them.
+func (s *routeServices) setupOpportunityLifecycle() {
+	var opportunityHistory handlers.OpportunityHistorySource
+	if s.db != nil && s.cfg.opportunityLifecycleEnabled {
+		s.opportunityLifecycle = services.NewOpportunityLifecycleService(s.db, services.OpportunityLifecycleConfig{})
+		opportunityHistory = s.opportunityLifecycle
+		s.notifications.SetLifecycleService(s.opportunityLifecycle)
+	}
+	s.handlers.opportunityLifecycle = handlers.NewOpportunityLifecycleHandler(opportunityHistory)
+}
+
+// setupProfiler captures CPU/heap profiles automatically when request
+// latency or heap usage crosses a threshold.
+func (s *routeServices) setupProfiler() {
+	var profileCaptures handlers.ProfileCaptureSource
+	if s.cfg.profiler != nil {
+		s.profiler = services.NewProfiler(*s.cfg.profiler)
+		profileCaptures = s.profiler
+		s.profiler.Start()
+	}
+	s.handlers.profiler = handlers.NewProfilerHandler(profileCaptures)
+}
+
+// setupLoadShedding sheds scan symbols, cycle length and non-critical quests
+// under CPU/memory pressure, as sampled by the collector's resource manager.
+func (s *routeServices) setupLoadShedding() {
+	if s.collector != nil && s.collector.ResourceManager() != nil && s.cfg.loadShedding != nil {
+		s.loadShedding = s.collector.ResourceManager()
+		s.loadShedding.EnableLoadShedding(*s.cfg.loadShedding)
+		s.questEngine.SetLoadShedder(s.loadShedding)
+		s.handlers.health.SetLoadShedding(s.loadShedding)
+	}
+	if s.profiles != nil {
+		s.questEngine.SetScanIntervals(s.profiles)
+	}
+}
+
+// setupEquity builds the account valuation shared by the portfolio and equity
+// endpoints, PnL reports, the daily loss circuit and fund growth goals.
+func (s *routeServices) setupEquity() {
+	balances, ok := s.ccxt.(services.MarginBalanceFetcher)
+	if !ok {
+		return
+	}
+	s.equity = services.NewEquityService(balances, s.ccxt, s.cfg.equity)
+	if wallets := s.cfg.equityWallets; wallets != nil {
+		s.equity.SetExternalWallets(services.NewOperatorWalletSource(s.db), services.NewERC20WalletValuer(wallets.rpcURL, wallets.token, wallets.decimals))
+	}
+	s.integrated.SetEquitySource(s.equity)
+}
+
+// setupTradingBudget builds per-chat virtual sub-accounts of the exchange
+// equity enforced at order sizing; the rest of the account is an untouchable
+// reserve left out of trading equity.
+func (s *routeServices) setupTradingBudget() {
+	var tradingBudgetManager handlers.TradingBudgetManager
+	if client := s.redisClient(); s.equity != nil && client != nil {
+		s.tradingBudget = services.NewTradingBudgetService(client, s.equity)
+		s.equity.SetReserveSource(s.tradingBudget)
+		s.integrated.SetTradingBudget(s.tradingBudget)
+		tradingBudgetManager = s.tradingBudget
+	}
+	s.handlers.tradingBudget = handlers.NewTradingBudgetHandler(tradingBudgetManager)
+}
+
+// setupDailyLossCircuit builds the daily loss cap: it halts new entries for a
+// chat whose realized plus unrealized loss today reaches the cap, resuming at
+// the UTC day rollover. Orders from every other path are checked by its
+// pre-trade hook. It starts once its flattener is set.
+func (s *routeServices) setupDailyLossCircuit() {
+	var dailyLossProvider handlers.DailyLossProvider
+	if client := s.redisClient(); client != nil && s.cfg.dailyLoss != nil {
+		var equity services.EquitySource
+		if s.equity != nil {
+			equity = s.equity
+		}
+		s.dailyLoss = services.NewDailyLossCircuit(client, equity, *s.cfg.dailyLoss)
+		s.dailyLoss.SetMessenger(s.notifications)
+		if len(s.eventEmitters) > 0 {
+			s.dailyLoss.SetEventEmitter(s.eventEmitters)
+		}
+		if s.profiles != nil {
+			s.dailyLoss.SetLimitSource(s.profiles)
+		}
+		s.integrated.SetDailyLossGuard(s.dailyLoss)
+		s.handlers.trading.SetEntryGuard(s.dailyLoss)
+		dailyLossProvider = s.dailyLoss
+	}
+	s.handlers.dailyLoss = handlers.NewDailyLossHandler(dailyLossProvider)
+}
+
+// setupTradeHooks runs orders and notifications through the pre-trade,
+// post-trade and pre-notify hooks from webhooks or Go plugins, followed by
+// the built-in daily loss and margin checks.
+func (s *routeServices) setupTradeHooks() {
+	s.orderExecutor = s.ccxtOrders
+	hooks := newHookRegistry(s.cfg.hooks)
+	if s.dailyLoss != nil {
+		if hooks == nil {
+			hooks = services.NewHookRegistry(services.HookConfig{})
+		}
+		if err := hooks.Register("daily_loss", s.dailyLoss); err != nil {
+			log.Printf("WARNING: failed to register daily loss check: %v", err)
+		}
+	}
+	if balances, ok := s.ccxt.(services.MarginBalanceFetcher); ok && s.cfg.marginCheck != nil {
+		s.marginCheck = services.NewMarginCheck(balances, s.ccxt, *s.cfg.marginCheck)
+		if len(s.eventEmitters) > 0 {
+			s.marginCheck.SetEventEmitter(s.eventEmitters)
+		}
+		if hooks == nil {
+			hooks = services.NewHookRegistry(services.HookConfig{})
+		}
+		if err := hooks.Register("margin_check", s.marginCheck); err != nil {
+			log.Printf("WARNING: failed to register margin check: %v", err)
+		}
+	}
+	if hooks != nil {
+		hookedExec := services.NewHookedOrderExecutor(s.ccxtOrders, hooks)
+		if len(s.eventEmitters) > 0 {
+			hookedExec.SetEventEmitter(s.eventEmitters)
+		}
+		s.orderExecutor = hookedExec
+		s.notifications.SetHooks(hooks)
+		log.Printf("Trade and notification hooks enabled (%d registered)", hooks.Len())
+	}
+}
+
+// newHookRegistry builds the configured hook webhooks and Go plugins.
+//
+// Parameters:
+//
+//	cfg: The hooks read by newHookConfig.
+//
+// Returns:
+//
+//	*services.HookRegistry: The registry, or nil when no hook is configured.
+func newHookRegistry(cfg hookRouteConfig) *services.HookRegistry {
+	registry := services.NewHookRegistry(cfg.config)
+	for _, webhook := range cfg.webhooks {
+		hook, err := services.NewWebhookHook(webhook.url, cfg.webhookSecret, webhook.events, cfg.config.Timeout)
+		if err != nil {
+			log.Printf("WARNING: Invalid hook webhook '%s': %v", webhook.url, err)
+			continue
+		}
+		if err := registry.Register(webhook.url, hook); err != nil {
+			log.Printf("WARNING: failed to register hook webhook '%s': %v", webhook.url, err)
+		}
+	}
+	for _, path := range cfg.plugins {
+		if err := registry.LoadPlugin(path); err != nil {
+			log.Printf("WARNING: %v", err)
+		}
+	}
+
+	if registry.Len() == 0 {
+		return nil
+	}
+	return registry
+}
+
+// setupMakerFirst routes orders of strategies with ORDER_MAKER_FIRST_<STRATEGY>
+// through maker-first execution; other orders pass straight through. The
+// resulting executor places scalping orders.
+func (s *routeServices) setupMakerFirst() {
+	var executionQuality handlers.ExecutionQualityProvider
+	if inner, ok := s.orderExecutor.(services.MakerFirstOrderExecutor); ok {
+		s.makerFirst = services.NewMakerFirstExecutor(inner, s.ccxt, s.cfg.makerFirst)
+		s.orderExecutor = s.makerFirst
+		executionQuality = s.makerFirst
+	}
+	s.handlers.executionQuality = handlers.NewExecutionQualityHandler(executionQuality)
+	s.integrated.SetOrderExecutor(s.orderExecutor)
+	s.integrated.SetOrderDefaults(s.cfg.orderDefaults)
+}
+
+// setupAlgoOrders builds TWAP and iceberg execution for orders too large for
+// one market order. Child orders go straight to the exchange, so algo orders
+// are only offered where the kill switch and live mode can be checked.
+func (s *routeServices) setupAlgoOrders() {
+	var algoOrders handlers.AlgoOrderManager
+	if algoExecutor, ok := s.orderExecutor.(services.AlgoOrderExecutor); ok && s.tradingModes != nil {
+		s.algoOrders = services.NewAlgoOrderManager(algoExecutor, s.ccxt, s.cfg.algoOrders)
+		s.algoOrders.SetTradingGuard(s.tradingModes)
+		algoOrders = s.algoOrders
+	}
+	s.handlers.algoOrder = handlers.NewAlgoOrderHandler(algoOrders)
+}
+
+// setupStablecoinMonitor watches stablecoin pegs: a depeg raises critical risk
+// events and, when enabled, pauses strategies on the affected stablecoin and
+// converts its balance.
+func (s *routeServices) setupStablecoinMonitor() {
+	var stablecoinStatus handlers.StablecoinStatusProvider
+	if client := s.redisClient(); client != nil && s.cfg.stablecoins != nil {
+		s.stablecoins = services.NewStablecoinMonitor(s.ccxt, client, *s.cfg.stablecoins)
+		if balances, ok := s.ccxt.(services.StablecoinBalanceFetcher); ok {
+			s.stablecoins.SetConverter(balances, s.orderExecutor)
+		}
+		if len(s.eventEmitters) > 0 {
+			s.stablecoins.SetEventEmitter(s.eventEmitters)
+		}
+		if err := s.stablecoins.Start(context.Background()); err != nil {
+			log.Printf("WARNING: failed to start stablecoin monitor: %v", err)
+		}
+		s.integrated.SetStablecoinGuard(s.stablecoins)
+		s.externalSignals.SetStablecoinGuard(s.stablecoins)
+		stablecoinStatus = s.stablecoins
+	}
+	s.handlers.stablecoin = handlers.NewStablecoinHandler(stablecoinStatus)
+}
+
+// setupExchangeOutage pauses strategies on an exchange with rising errors,
+// wide spreads or stale tickers until it has been stable again.
+func (s *routeServices) setupExchangeOutage() {
+	var exchangeHealth handlers.ExchangeHealthProvider
+	if client := s.redisClient(); client != nil && s.collector != nil && s.cfg.exchangeOutage != nil {
+		s.outageDetector = services.NewExchangeOutageDetector(s.collector, s.db, client, *s.cfg.exchangeOutage)
+		s.outageDetector.SetMessenger(s.notifications)
+		if len(s.eventEmitters) > 0 {
+			s.outageDetector.SetEventEmitter(s.eventEmitters)
+		}
+		if err := s.outageDetector.Start(context.Background()); err != nil {
+			log.Printf("WARNING: failed to start exchange outage detector: %v", err)
+		}
+		s.integrated.SetExchangeGuard(s.outageDetector)
+		s.externalSignals.SetExchangeGuard(s.outageDetector)
+		exchangeHealth = s.outageDetector
+	}
+	s.handlers.exchangeHealth = handlers.NewExchangeHealthHandler(exchangeHealth)
+}
+
+// setupLossStreaks applies the consecutive-loss rule: a strategy that loses N
+// trades in a row is reduced in size or paused for a cool-down window.
+func (s *routeServices) setupLossStreaks() {
+	var lossStreakPolicy handlers.LossStreakManager
+	if client := s.redisClient(); client != nil {
+		policy := services.NewConsecutiveLossPolicy(client, s.cfg.lossStreaks)
+		if len(s.eventEmitters) > 0 {
+			policy.SetEventEmitter(s.eventEmitters)
+		}
+		s.integrated.SetStrategyThrottle(policy)
+		lossStreakPolicy = policy
+	}
+	s.handlers.lossStreak = handlers.NewLossStreakHandler(lossStreakPolicy)
+}
+
+// setupSymbolCooldowns blocks re-entry on a symbol for a while after a
+// stop-out or exit, so scalping cannot revenge-trade the same market.
+func (s *routeServices) setupSymbolCooldowns() {
+	var symbolCooldownManager handlers.SymbolCooldownManager
+	if client := s.redisClient(); client != nil {
+		s.symbolCooldowns = services.NewSymbolCooldownTracker(client, s.cfg.symbolCooldowns)
+		if len(s.eventEmitters) > 0 {
+			s.symbolCooldowns.SetEventEmitter(s.eventEmitters)
+		}
+		s.integrated.SetSymbolCooldownGuard(s.symbolCooldowns)
+		symbolCooldownManager = s.symbolCooldowns
+	}
+	s.handlers.symbolCooldown = handlers.NewSymbolCooldownHandler(symbolCooldownManager)
+}
+
+// setupPositionRegistry records which strategy holds each symbol so two
+// strategies do not stack exposure on it.
+func (s *routeServices) setupPositionRegistry() {
+	if client := s.redisClient(); client != nil {
+		s.positionRegistry = services.NewPositionRegistry(client, s.cfg.positionRegistry)
+		s.integrated.SetPositionGuard(s.positionRegistry)
+	}
+}
+
+// setupIntentLog writes each decision's orders before they are placed, so
+// plans interrupted by a restart are settled before quests resume.
+func (s *routeServices) setupIntentLog() {
+	if client := s.redisClient(); client != nil {
+		s.intentLog = services.NewIntentLog(client)
+		s.integrated.SetIntentLog(s.intentLog)
+	}
+}
+
+// setupHedging suggests perpetual hedges against clusters of correlated open
+// positions; a hedge is only opened once confirmed from Telegram.
+func (s *routeServices) setupHedging() {
+	var hedgeAdvisor handlers.HedgeAdvisor
+	if client := s.redisClient(); client != nil && s.analytics != nil {
+		s.hedging = services.NewHedgingAdvisor(s.handlers.trading, s.analytics, client, s.cfg.hedging)
+		s.hedging.SetOrderPlacer(s.handlers.trading)
+		if s.tradingModes != nil {
+			s.hedging.SetKillSwitch(s.tradingModes)
+		}
+		s.hedging.SetNotifier(s.notifications)
+		if err := s.hedging.Start(context.Background()); err != nil {
+			log.Printf("WARNING: failed to start hedging advisor: %v", err)
+		}
+		hedgeAdvisor = s.hedging
+	}
+	s.handlers.hedge = handlers.NewHedgeHandler(hedgeAdvisor)
+}
+
+// setupCharts builds charts for /chart, annotated with open positions and
+// active signals.
+func (s *routeServices) setupCharts() {
+	var chartSignals handlers.ChartSignalSource
+	if s.signalAggregator != nil {
+		chartSignals = s.signalAggregator
+	}
+	s.handlers.chart = handlers.NewChartHandler(s.handlers.analysis, s.handlers.trading, chartSignals)
+}
+
+// setupMarginManager manages per-symbol leverage and margin mode, verified
+// before scalping entries, with risk events for positions nearing
+// liquidation.
+func (s *routeServices) setupMarginManager() {
+	var marginProvider handlers.MarginProvider
+	if client, ok := s.ccxt.(services.MarginClient); ok && s.cfg.margin != nil {
+		s.marginManager = services.NewMarginManager(client, *s.cfg.margin)
+		if len(s.eventEmitters) > 0 {
+			s.marginManager.SetEventEmitter(s.eventEmitters)
+		}
+		if len(s.marginManager.Config().Exchanges) > 0 {
+			if err := s.marginManager.Start(context.Background()); err != nil {
+				log.Printf("WARNING: failed to start margin monitor: %v", err)
+			}
+		}
+		s.integrated.SetLeverageGuard(s.marginManager)
+		s.handlers.trading.SetMarginSource(s.marginManager)
+		marginProvider = s.marginManager
+	}
+	s.handlers.margin = handlers.NewMarginHandler(marginProvider)
+}
+
+// setupMarketContext feeds technical signals and scalping prompts with open
+// interest and long/short ratios stored by the collector, trade-tape flow
+// (buy/sell imbalance and large prints of recent trades), the market regime
+// and recent headlines. Stored news is only queried in Postgres mode.
+func (s *routeServices) setupMarketContext() {
+	positioningStore := services.NewPositioningStore(s.db, 0)
+	s.integrated.SetPositioningSource(positioningStore)
+	if s.signalAggregator != nil {
+		s.signalAggregator.SetPositioningSource(positioningStore)
+	}
+
+	tradeFlowAnalyzer := services.NewTradeFlowAnalyzer(s.ccxt, s.cfg.tradeFlow)
+	s.integrated.SetTradeFlowSource(tradeFlowAnalyzer)
+	s.handlers.tradeFlow = handlers.NewTradeFlowHandler(tradeFlowAnalyzer)
+
+	if s.analytics != nil {
+		s.integrated.SetRegimeSource(s.analytics)
+	}
+	if _, ok := s.db.(*database.SQLiteDB); !ok && s.db != nil {
+		s.integrated.SetNewsSource(s.sentiment)
+	}
+}
+
+// setupFundingForecast predicts next-interval funding of tracked perps and
+// publishes it for the futures arbitrage calculator to pre-position on.
+func (s *routeServices) setupFundingForecast() {
+	var fundingForecastProvider handlers.FundingForecastProvider
+	if s.ccxt != nil && len(s.cfg.fundingForecast.Exchanges) > 0 {
+		s.fundingForecaster = services.NewFundingForecaster(s.ccxt, s.redisClient(), s.cfg.fundingForecast)
+		if err := s.fundingForecaster.Start(context.Background()); err != nil {
+			log.Printf("WARNING: failed to start funding forecaster: %v", err)
+		}
+		fundingForecastProvider = s.fundingForecaster
+	}
+	s.handlers.fundingForecast = handlers.NewFundingForecastHandler(fundingForecastProvider)
+}
+
+// setupCarryAnalytics reports funding and spot-perp basis statistics from the
+// funding rate history, which only exists in Postgres mode.
+func (s *routeServices) setupCarryAnalytics() {
+	var carryReporter handlers.CarryReporter
+	if _, ok := s.db.(*database.SQLiteDB); !ok && s.db != nil {
+		carryReporter = services.NewCarryAnalyticsService(s.db)
+	}
+	s.handlers.carryAnalytics = handlers.NewCarryAnalyticsHandler(carryReporter)
+}
+
+// setupPositionTracker marks tracked positions to market and alerts as they
+// approach their estimated liquidation price.
+func (s *routeServices) setupPositionTracker() {
+	trackerLogger := zaplogrus.New()
+	trackerLogger.SetFormatter(&zaplogrus.JSONFormatter{})
+	s.positionTracker = services.NewPositionTracker(s.cfg.positionTracker, s.ccxt, s.redisClient(), trackerLogger)
+	s.positionTracker.SetRiskNotifier(s.notifications)
+	if s.marginCheck != nil {
+		s.marginCheck.SetPositionSource(s.positionTracker)
+	}
+	if s.dailyLoss != nil {
+		s.dailyLoss.SetPositionSource(s.positionTracker)
+	}
+	if s.equity != nil {
+		s.equity.SetPositionSource(s.positionTracker)
+	}
+	if s.tradingBudget != nil {
+		s.tradingBudget.SetPositionSource(s.positionTracker)
+	}
+	if len(s.eventEmitters) > 0 {
+		s.positionTracker.SetEventEmitter(s.eventEmitters)
+	}
+	s.positionTracker.SetOnCloseCallback(s.onPositionClosed)
+	s.positionTracker.Start()
+	s.integrated.SetOpenPositionSource(s.positionTracker)
+}
+
+// onPositionClosed books a closed position towards the daily loss cap and
+// releases it in the position registry and symbol cooldowns.
+func (s *routeServices) onPositionClosed(ctx context.Context, position interfaces.Position) error {
+	if s.dailyLoss != nil {
+		if err := s.dailyLoss.RecordAccountRealized(ctx, position.UnrealizedPL); err != nil {
+			log.Printf("WARNING: Failed to record the %s result towards the daily loss cap: %v", position.Symbol, err)
+		}
+	}
+	if s.positionRegistry != nil {
+		if err := s.positionRegistry.OnPositionClosed(ctx, position); err != nil {
+			log.Printf("WARNING: Failed to release %s in the position registry: %v", position.Symbol, err)
+		}
+	}
+	if s.symbolCooldowns != nil {
+		return s.symbolCooldowns.OnPositionClosed(ctx, position)
+	}
+	return nil
+}
+
+// setupFlattening lets the daily loss circuit flatten positions and starts it.
+func (s *routeServices) setupFlattening() {
+	s.exchangeFlattener = services.NewExchangeFlattener(s.positionTracker, s.orderExecutor)
+	if s.dailyLoss != nil {
+		s.dailyLoss.SetFlattener(s.flattenPositions)
+		if err := s.dailyLoss.Start(context.Background()); err != nil {
+			log.Printf("WARNING: failed to start daily loss circuit: %v", err)
+		}
+	}
+}
+
+// flattenPositions closes every tracked position on the exchange through the
+// hooked executor, whose daily loss check lets closing orders through, and
+// marks the trading API ledger liquidated. All chats share one exchange
+// account, so the chat is not used to pick positions.
+func (s *routeServices) flattenPositions(ctx context.Context, chatID string) error {
+	err := s.exchangeFlattener.Flatten(ctx, chatID)
+	if _, ledgerErr := s.handlers.trading.LiquidateAllPositions(ctx); ledgerErr != nil {
+		err = errors.Join(err, fmt.Errorf("failed to liquidate ledger positions: %w", ledgerErr))
+	}
+	return err
+}
+
+// setupUserDataStreams pushes fills from exchange user-data streams to
+// resting maker, iceberg and position updates as they happen; unstreamed
+// exchanges keep polling.
+func (s *routeServices) setupUserDataStreams() {
+	var userDataStreams handlers.UserDataStreamStatusProvider
+	if len(s.cfg.userDataStreams.Exchanges) > 0 {
+		s.userDataStream = services.NewUserDataStream(s.cfg.userDataStreams)
+		s.userDataStream.OnOrderUpdate(s.applyOrderUpdate)
+		if s.makerFirst != nil {
+			s.makerFirst.SetOrderUpdates(s.userDataStream)
+		}
+		if s.algoOrders != nil {
+			s.algoOrders.SetOrderUpdates(s.userDataStream)
+		}
+		s.userDataStream.Start()
+		userDataStreams = s.userDataStream
+	}
+	s.handlers.userDataStream = handlers.NewUserDataStreamHandler(userDataStreams)
+}
+
+// applyOrderUpdate applies a streamed fill to the tracked positions.
+func (s *routeServices) applyOrderUpdate(ctx context.Context, update services.OrderUpdate) {
+	if err := s.positionTracker.OnOrderUpdate(ctx, update); err != nil {
+		log.Printf("WARNING: Failed to apply streamed fill for order %s: %v", update.OrderID, err)
+	}
+}
+
+// setupPnL builds the PnL report shared by /pnl and the daily report quest,
+// public performance pages behind expiring, signed share links, and global
+// search across trades, signals, quests and exchange error logs.
+func (s *routeServices) setupPnL() {
+	var pnlEquity services.EquitySource
+	if s.equity != nil {
+		pnlEquity = s.equity
+	}
+	s.pnlReporter = services.NewPnLReporter(services.NewTradeOutcomeSource(s.db), s.positionTracker, pnlEquity)
+	s.handlers.pnl = handlers.NewPnLHandler(s.pnlReporter)
+
+	var shareLinks handlers.ShareLinkManager
+	if s.cfg.shareLinkSecret != "" {
+		shareLinks = services.NewShareLinkService(s.cfg.shareLinkSecret, s.redisClient(), s.pnlReporter)
+	}
+	s.handlers.share = handlers.NewShareHandler(shareLinks, s.cfg.shareLinkBaseURL)
+
+	s.handlers.search = handlers.NewSearchHandler(services.NewSearchService(s.db))
+}
+
+// setupReports schedules daily/weekly reports: PnL, strategy attribution,
+// risk events and upcoming calendar events, sent to Telegram and optionally
+// by email.
+func (s *routeServices) setupReports() {
+	cfg := s.cfg.reports
+	var reportRisk services.RiskEventSource
+	if s.riskEventLog != nil {
+		reportRisk = s.riskEventLog
+	}
+	var reportCalendar services.CalendarSource
+	if cfg.calendar != nil {
+		reportCalendar = services.NewConfiguredCalendar(cfg.calendar)
+	}
+	reportGenerator := services.NewTradingReportGenerator(s.pnlReporter, reportRisk, reportCalendar, cfg.config)
+	reportGenerator.SetMessenger(s.notifications)
+	if cfg.email != nil {
+		reportGenerator.SetMailer(services.NewEmailChannel(*cfg.email))
+	}
+	s.integrated.SetReportGenerator(reportGenerator)
+}
+
+// setupPromptCanary routes a fraction of scalping cycles to a new prompt or
+// model version and rolls it back when it trails the control.
+func (s *routeServices) setupPromptCanary() {
+	var promptCanaryProvider handlers.PromptCanaryProvider
+	if client := s.redisClient(); s.cfg.promptCanary != nil && client != nil {
+		s.promptCanary = services.NewPromptCanary(s.ccxt, client, *s.cfg.promptCanary)
+		if len(s.eventEmitters) > 0 {
+			s.promptCanary.SetEventEmitter(s.eventEmitters)
+		}
+		if err := s.promptCanary.Start(context.Background()); err != nil {
+			log.Printf("WARNING: failed to start prompt canary: %v", err)
+		}
+		s.integrated.SetPromptRouter(s.promptCanary)
+		promptCanaryProvider = s.promptCanary
+	}
+	s.handlers.promptCanary = handlers.NewPromptCanaryHandler(promptCanaryProvider)
+}
+
+// connectScalpingSources hands the scalping quest handlers the decision
+// audit, symbol universe, capital allocation, operating profiles and listing
+// risk set up before them, and fingerprints their strategy parameters.
+func (s *routeServices) connectScalpingSources() {
+	if s.decisionAudit != nil {
+		s.integrated.SetDecisionRecorder(s.decisionAudit)
+	}
+	if s.stateFingerprints != nil {
+		s.stateFingerprints.AddStrategySource("ai_scalping", s.integrated.StrategyParameters)
+	}
+	if s.watchlist != nil {
+		s.integrated.SetSymbolUniverse(s.watchlist)
+	}
+	if s.allocation != nil {
+		s.integrated.SetCapitalAllocator(s.allocation)
+	}
+	if s.profiles != nil {
+		s.integrated.SetOperatingProfiles(s.profiles)
+	}
+	if s.listingDetector != nil {
+		s.integrated.SetListingRiskProvider(s.listingDetector)
+	}
+}
+
+// setupNotificationActions adds inline action buttons to opportunity alerts
+// (execute / snooze / details). Live execution requires an explicit
+// confirmation step.
+func (s *routeServices) setupNotificationActions() {
+	var notificationActions handlers.NotificationActionInterface
+	if client := s.redisClient(); client != nil {
+		// A dedicated secret: callback signing must not depend on, or be
+		// rotated with, the admin credential
+		actionSecret := s.cfg.notificationActionSecret
+		if actionSecret == "" {
+			actionSecret = uuid.NewString()
+			log.Printf("WARNING: NOTIFICATION_ACTION_SECRET is not set; notification buttons will not survive restarts")
+		}
+		actionService := services.NewNotificationActionService(client, services.NotificationActionConfig{
+			Secret:      actionSecret,
+			LiveTrading: s.cfg.tradingMode.DefaultMode == services.ExecutionModeLive,
+		}, s.orderExecutor)
+		if s.tradingModes != nil {
+			actionService.SetModeProvider(s.tradingModes)
+			actionService.SetGoLiveCanceller(s.tradingModes)
+		}
+		if s.opportunityLifecycle != nil {
+			actionService.SetLifecycleService(s.opportunityLifecycle)
+		}
+		if s.criticalEscalation != nil {
+			actionService.SetAcknowledger(s.criticalEscalation)
+		}
+		s.notifications.SetActionService(actionService)
+		notificationActions = actionService
+	}
+	s.handlers.notificationAction = handlers.NewNotificationActionHandler(notificationActions)
+}
+
+// setupSessionAuth builds session auth for the web dashboard: scoped access
+// tokens, single-use refresh tokens and a Redis revocation list.
+func (s *routeServices) setupSessionAuth() {
+	var sessionStore handlers.SessionStoreInterface
+	if client := s.redisClient(); client != nil {
+		store := services.NewSessionStore(client)
+		s.auth.SetRevocationChecker(store)
+		sessionStore = store
+	}
+	s.handlers.auth = handlers.NewAuthHandler(s.auth, sessionStore, s.admin, s.handlers.user, s.notifications)
+}
+
+// setupTradeMemory gives AI scalping the memory of past trades.
+func (s *routeServices) setupTradeMemory() {
+	var sqlDB *sql.DB
+	switch concreteDB := s.db.(type) {
+	case *database.SQLiteDB:
+		sqlDB = concreteDB.DB
+	case *database.PostgresDB:
+		sqlDB = concreteDB.SQL
+	default:
+		log.Printf("Warning: Unknown database type, AI learning disabled")
+	}
+
+	if sqlDB != nil {
+		tradeMemory, err := services.NewTradeMemory(sqlDB)
+		if err != nil {
+			log.Printf("Warning: Failed to create trade memory: %v", err)
+		} else {
+			s.integrated.SetTradeMemory(tradeMemory)
+			log.Printf("Trade memory initialized for AI learning")
+		}
+	}
+}
+
+// setupEmbeddings embeds news, decisions and operator notes in the background
+// into a vector store: SQLite, on a sqlite-vec index when
+// SQLITE_VEC_EXTENSION_PATH loads the extension, or Postgres with pgvector
+// (migrations 073 and 074).
+func (s *routeServices) setupEmbeddings() {
+	var embeddingProvider handlers.EmbeddingPipelineProvider
+	if s.db != nil {
+		var vectorStore services.VectorStore
+		var vectorErr error
+		if sqliteDB, ok := s.db.(*database.SQLiteDB); ok {
+			vectorStore, vectorErr = services.NewSQLiteVectorStore(sqliteDB.DB, services.DefaultDecisionEmbeddingDims)
+		} else {
+			vectorStore, vectorErr = services.NewPgVectorStore(context.Background(), s.db, services.DefaultDecisionEmbeddingDims)
+		}
+		embedder := newEmbeddingProvider(s.cfg.embedder)
+		var decisionMemory *services.DecisionMemory
+		if vectorErr == nil {
+			decisionMemory, vectorErr = services.NewDecisionMemory(vectorStore, embedder)
+		}
+		if vectorErr == nil {
+			s.embeddingPipeline, vectorErr = services.NewEmbeddingPipeline(embedder, vectorStore, database.NewAIUsageRepository(s.db), s.cfg.embeddingPipeline)
+		}
+		if vectorErr == nil {
+			vectorErr = s.embeddingPipeline.Start(context.Background())
+		}
+		if vectorErr != nil {
+			log.Printf("Warning: Embedding pipeline and similar decision lookups disabled: %v", vectorErr)
+			s.embeddingPipeline = nil
+		} else {
+			if s.decisionAudit != nil {
+				s.decisionAudit.SetVectorIndex(s.embeddingPipeline)
+				s.integrated.SetSimilarDecisionSource(decisionMemory)
+			}
+			s.sentiment.SetEmbeddingQueue(s.embeddingPipeline)
+			embeddingProvider = s.embeddingPipeline
+		}
+	}
+	s.handlers.embedding = handlers.NewEmbeddingHandler(embeddingProvider)
+}
+
+// newEmbeddingProvider builds the embedder read by newEmbedderConfig.
+//
+// Parameters:
+//
+//	cfg: The OpenAI-compatible endpoint, or nil for the local hashing embedder.
+//
+// Returns:
+//
+//	services.EmbeddingProvider: The embedder.
+func newEmbeddingProvider(cfg *services.OpenAIEmbedderConfig) services.EmbeddingProvider {
+	if cfg != nil {
+		return services.NewOpenAIEmbedder(*cfg)
+	}
+	return services.NewHashingEmbedder(services.DefaultDecisionEmbeddingDims)
+}
+
+// setupAIScalping gives the scalping quests an LLM and the skill registry
+// when an AI API key is configured, with the optional shadow-mode variant
+// compared with live scalping on modeled fills.
+func (s *routeServices) setupAIScalping() {
+	var aiAPIKey, aiBaseURL, aiProvider string
+	if s.aiConfig != nil && s.aiConfig.APIKey != "" {
+		aiAPIKey = s.aiConfig.APIKey
+		aiBaseURL = s.aiConfig.BaseURL
+		if aiBaseURL == "" {
+			aiBaseURL = "https://api.minimax.chat/v1"
+		}
+		aiProvider = s.aiConfig.Provider
+		if aiProvider == "" {
+			aiProvider = "minimax"
+		}
+	}
+
+	if aiAPIKey != "" {
+		log.Printf("Initializing AI Scalping with provider: %s (base_url: %s)", aiProvider, aiBaseURL)
+
+		llmConfig := llm.ClientConfig{
+			APIKey:      aiAPIKey,
+			BaseURL:     aiBaseURL,
+			HTTPTimeout: 120,
+		}
+
+		var llmClient llm.Client
+		switch aiProvider {
+		case "openai":
+			llmClient = llm.NewOpenAIClient(llmConfig)
+		case "anthropic":
+			llmClient = llm.NewAnthropicClient(llmConfig)
+		case "mlx":
+			llmClient = llm.NewMLXClient(llmConfig)
+		default:
+			llmClient = llm.NewOpenAIClient(llmConfig)
+		}
+
+		skillRegistry := skill.NewRegistry(filepath.Join(filepath.Dir(""), "skills"))
+		if err := skillRegistry.LoadAll(); err != nil {
+			log.Printf("Warning: Failed to load skills: %v", err)
+		}
+		if s.cfg.shadowStrategy != nil {
+			s.integrated.SetShadowStrategy(*s.cfg.shadowStrategy)
+		}
+		s.integrated.SetAIScalping(llmClient, skillRegistry)
+		log.Printf("AI Scalping service initialized successfully")
+	} else {
+		log.Printf("AI API key not configured in ~/.neuratrade/config.json, AI scalping disabled")
+	}
+
+	var shadowReporter handlers.ShadowStrategyReporter
+	if runner := s.integrated.ShadowStrategy(); runner != nil {
+		shadowReporter = runner
+	}
+	s.handlers.shadowStrategy = handlers.NewShadowStrategyHandler(shadowReporter)
+}
+
+// setupStartupGuard is the start-up safety gate: a dirty flag left by a run
+// that did not shut down gracefully holds autonomous trading until the
+// operator sends /resume. Decisions interrupted by the last shutdown are
+// then reconciled against the exchange.
+func (s *routeServices) setupStartupGuard() {
+	if client := s.redisClient(); client != nil {
+		s.startupGuard = services.NewStartupGuard(client, s.cfg.startupGuard)
+		s.startupGuard.SetMessenger(s.notifications)
+		unclean, err := s.startupGuard.Open(context.Background())
+		if err != nil {
+			log.Printf("WARNING: Failed to check for an unclean shutdown: %v", err)
+		}
+		s.uncleanShutdown = unclean
+	}
+	if s.uncleanShutdown != nil && s.startupGuard.Config().RequireResume {
+		s.questEngine.Hold("unclean shutdown; waiting for /resume")
+	}
+
+	if s.intentLog != nil {
+		reconcileCtx, cancelReconcile := context.WithTimeout(context.Background(), 60*time.Second)
+		intents, err := s.intentLog.Reconcile(reconcileCtx, s.ccxtOrders)
+		cancelReconcile()
+		if err != nil {
+			log.Printf("WARNING: Failed to reconcile interrupted decisions: %v", err)
+		} else if len(intents) > 0 {
+			log.Printf("Reconciled %d decision(s) interrupted by the last shutdown", len(intents))
+		}
+		s.reconciled = intents
+	}
+}
+
+// startQuests starts the quest engine scheduler. On shutdown it stops
+// scheduling, lets running quests finish and checkpoints them.
+func (s *routeServices) startQuests() {
+	s.questEngine.Start()
+	s.drain.OnDrain("quests", s.questEngine.Drain)
+}
+
+// setupLoopWatchdog is the dead man's switch: it alerts when the scheduler or
+// a quest stops refreshing its heartbeat.
+func (s *routeServices) setupLoopWatchdog() {
+	if s.cfg.loopWatchdog == nil {
+		return
+	}
+	s.loopWatchdog = services.NewTradingLoopWatchdog(s.questEngine, *s.cfg.loopWatchdog)
+	s.loopWatchdog.SetFlattener(s.flattenPositions)
+	s.loopWatchdog.SetMessenger(s.notifications)
+	if len(s.eventEmitters) > 0 {
+		s.loopWatchdog.SetEventEmitter(s.eventEmitters)
+	}
+	if err := s.loopWatchdog.Start(context.Background()); err != nil {
+		log.Printf("WARNING: failed to start trading loop watchdog: %v", err)
+	}
+}
+
+// restoreAutonomousChats restores autonomous scalping for operator chats that
+// were enabled via Telegram /begin, and tells them about an unclean shutdown.
+func (s *routeServices) restoreAutonomousChats() {
+	var autonomousChats []string
+	if s.db != nil {
+		autonomousChats = s.loadAutonomousChats()
+	}
+	if s.uncleanShutdown != nil {
+		notifyCtx, cancelNotify := context.WithTimeout(context.Background(), 30*time.Second)
+		s.startupGuard.Notify(notifyCtx, autonomousChats, s.uncleanShutdown, s.reconciled)
+		cancelNotify()
+	}
+}
+
+// loadAutonomousChats begins autonomous mode for the latest chat enabled in
+// telegram_operator_state and returns the chats restored.
+func (s *routeServices) loadAutonomousChats() []string {
+	rows, err := s.db.Query(
+		context.Background(),
+		"SELECT chat_id FROM telegram_operator_state WHERE autonomous_enabled = TRUE ORDER BY updated_at DESC LIMIT 1",
+	)
+	if err != nil {
+		log.Printf("Failed to restore autonomous-enabled chats: %v", err)
+		return nil
+	}
+	defer rows.Close()
+	var autonomousChats []string
+	for rows.Next() {
+		var chatID string
+		if err := rows.Scan(&chatID); err != nil {
+			log.Printf("Failed to scan autonomous chat row: %v", err)
+			continue
+		}
+		chatID = strings.TrimSpace(chatID)
+		if chatID == "" {
+			continue
+		}
+		if _, err := s.questEngine.BeginAutonomous(chatID); err != nil {
+			log.Printf("Failed to restore autonomous mode for chat %s: %v", chatID, err)
+			continue
+		}
+		autonomousChats = append(autonomousChats, chatID)
+	}
+	log.Printf("Restored autonomous scalping for %d chat(s) from telegram_operator_state (latest enabled chat only)", len(autonomousChats))
+	return autonomousChats
+}
+
+// setupArbitrageBridge keeps the arbitrage execution bridge disabled in
+// scalping-first mode. It is only enabled when AI arbitrage mode is
+// explicitly turned on.
+func (s *routeServices) setupArbitrageBridge() {
+	if s.featuresConfig == nil || !s.featuresConfig.EnableAIArbitrage {
+		log.Printf("Arbitrage execution bridge disabled in scalping-first mode")
+		return
+	}
+	arbitrageBridge := services.NewArbitrageExecutionBridge(s.db, s.questEngine, s.signalAggregator, nil)
+	go func() {
+		if err := arbitrageBridge.Start(context.Background()); err != nil {
+			log.Printf("Arbitrage execution bridge error: %v", err)
+		}
+	}()
+	log.Printf("Arbitrage execution bridge enabled (features.enable_ai_arbitrage=true)")
+}
+
+// setupTelegramHandlers builds the handlers behind the Telegram bot: quests,
+// portfolio and liquidation, and the internal operator routes with their
+// readiness score.
+func (s *routeServices) setupTelegramHandlers() {
+	s.handlers.autonomous = handlers.NewAutonomousHandler(s.questEngine)
+	s.handlers.autonomous.SetPositionSource(s.positionTracker)
+	if s.equity != nil {
+		s.handlers.autonomous.SetEquitySource(s.equity)
+	}
+	if s.dailyLoss != nil {
+		s.handlers.autonomous.SetDailyLossCircuit(s.dailyLoss)
+	}
+
+	telegramInternal := handlers.NewTelegramInternalHandler(s.db, s.handlers.user, s.questEngine)
+	if s.loadShedding != nil {
+		telegramInternal.SetLoadShedding(s.loadShedding)
+	}
+	if s.collector != nil {
+		telegramInternal.SetFreshness(s.collector.Freshness())
+	}
+	if s.stateFingerprints != nil {
+		telegramInternal.SetStateDiff(s.stateFingerprints)
+	}
+	readinessScorer := services.NewReadinessScorer(services.ReadinessConfig{
+		DailyAIBudget:   s.cfg.aiDailyBudget,
+		MonthlyAIBudget: s.cfg.aiMonthlyBudget,
+		MinScore:        s.cfg.readinessMinScore,
+	})
+	readinessScorer.SetAICosts(database.NewAIUsageRepository(s.db))
+	if s.outageDetector != nil {
+		readinessScorer.SetExchangeHealth(s.outageDetector)
+	}
+	if s.dailyLoss != nil {
+		readinessScorer.SetDailyLoss(s.dailyLoss)
+	}
+	telegramInternal.SetReadinessScorer(readinessScorer)
+	if s.profiles != nil {
+		telegramInternal.SetOperatingProfiles(s.profiles)
+	}
+	s.handlers.telegramInternal = telegramInternal
+}
+
+// newAPIQuotaLimiter builds the per-client read/write quota limiter. The
+// limiter runs before route auth, so it verifies session tokens and admin
+// keys itself to give each credential its own bucket.
+//
+// Parameters:
+//
+//	config: The quotas read by newQuotaConfig; nil disables the limiter.
+//	redis: Redis client for shared buckets (may be nil for in-memory buckets).
+//	auth: Verifies session tokens.
+//	admin: Verifies admin API keys.
+//
+// Returns:
+//
+//	*middleware.QuotaLimiter: The limiter, or nil when disabled.
+func newAPIQuotaLimiter(config *middleware.QuotaConfig, redis *database.RedisClient, auth *middleware.AuthMiddleware, admin *middleware.AdminMiddleware) *middleware.QuotaLimiter {
+	if config == nil {
+		return nil
+	}
+	quotas := *config
+	quotas.KeyFunc = middleware.CredentialQuotaKey(auth, admin)
+
+	if redis != nil && redis.Client != nil {
+		return middleware.NewQuotaLimiter(quotas, redis.Client, nil)
+	}
+	return middleware.NewQuotaLimiter(quotas, nil, nil)
+}
+
+// stop shuts down the background services.
+func (s *routeServices) stop() {
+	if s.handlers.webSocket != nil {
+		s.handlers.webSocket.Stop()
+	}
+	if s.embeddingPipeline != nil {
+		s.embeddingPipeline.Stop()
+	}
+	if s.notificationQueue != nil {
+		s.notificationQueue.Stop()
+	}
+	if s.dbPoolMonitor != nil {
+		s.dbPoolMonitor.Stop()
+	}
+	if s.listingDetector != nil {
+		s.listingDetector.Stop()
+	}
+	if s.stablecoins != nil {
+		s.stablecoins.Stop()
+	}
+	if s.userDataStream != nil {
+		s.userDataStream.Stop()
+	}
+	if s.algoOrders != nil {
+		s.algoOrders.Stop()
+	}
+	if s.outageDetector != nil {
+		s.outageDetector.Stop()
+	}
+	if s.promptCanary != nil {
+		s.promptCanary.Stop()
+	}
+	if s.watchlist != nil {
+		s.watchlist.Stop()
+	}
+	if s.dailyLoss != nil {
+		s.dailyLoss.Stop()
+	}
+	if s.loopWatchdog != nil {
+		s.loopWatchdog.Stop()
+	}
+	if s.criticalEscalation != nil {
+		s.criticalEscalation.Stop()
+	}
+	if s.tradingModes != nil {
+		s.tradingModes.Stop()
+	}
+	if s.auditChain != nil {
+		s.auditChain.Stop()
+	}
+	if s.hedging != nil {
+		s.hedging.Stop()
+	}
+	if s.marginManager != nil {
+		s.marginManager.Stop()
+	}
+	if s.fundingForecaster != nil {
+		s.fundingForecaster.Stop()
+	}
+	s.positionTracker.Stop()
+	if s.allocation != nil {
+		s.allocation.Stop()
+	}
+	if s.kpiStore != nil {
+		s.kpiStore.Stop()
+	}
+	if s.profiler != nil {
+		s.profiler.Stop()
+	}
+	if s.startupGuard != nil {
+		if err := s.startupGuard.Close(context.Background()); err != nil {
+			log.Printf("WARNING: %v", err)
+		}
+	}
+	if s.eventBus != nil {
+		_ = s.eventBus.Close()
+	}
+}
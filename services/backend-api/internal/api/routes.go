@@ -2,25 +2,18 @@ package api
 
 import (
 	"context"
-	"database/sql"
-	"encoding/json"
 	"log"
-	"os"
-	"path/filepath"
-	"strings"
+	"net/http/pprof"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/irfndi/neuratrade/internal/ai"
-	"github.com/irfndi/neuratrade/internal/ai/llm"
 	"github.com/irfndi/neuratrade/internal/api/handlers"
+	"github.com/irfndi/neuratrade/internal/api/openapi"
 	"github.com/irfndi/neuratrade/internal/ccxt"
 	"github.com/irfndi/neuratrade/internal/config"
 	"github.com/irfndi/neuratrade/internal/database"
 	"github.com/irfndi/neuratrade/internal/middleware"
 	"github.com/irfndi/neuratrade/internal/services"
-	"github.com/irfndi/neuratrade/internal/skill"
-	"github.com/shopspring/decimal"
 )
 
 // HealthResponse represents the response structure for health check endpoints.
@@ -49,11 +42,20 @@ type routeDB interface {
 	HealthCheck(ctx context.Context) error
 }
 
-func getEnvOrDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// registerPprofRoutes mounts the net/http/pprof endpoints under /debug/pprof
+// behind admin authentication.
+func registerPprofRoutes(router *gin.Engine, adminMiddleware *middleware.AdminMiddleware) {
+	debug := router.Group("/debug/pprof", adminMiddleware.RequireAdminAuth())
+	{
+		debug.GET("/", gin.WrapF(pprof.Index))
+		debug.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+		debug.GET("/profile", gin.WrapF(pprof.Profile))
+		debug.GET("/symbol", gin.WrapF(pprof.Symbol))
+		debug.POST("/symbol", gin.WrapF(pprof.Symbol))
+		debug.GET("/trace", gin.WrapF(pprof.Trace))
+		// Index serves the named profiles: heap, goroutine, allocs, block, mutex, threadcreate
+		debug.GET("/:name", gin.WrapF(pprof.Index))
 	}
-	return defaultValue
 }
 
 // SetupRoutes configures all the HTTP routes for the application.
@@ -73,544 +75,808 @@ func getEnvOrDefault(key, defaultValue string) string {
 //	aiConfig: Configuration for AI-driven trading.
 //	featuresConfig: Feature flags for enabling/disabling features.
 //	authMiddleware: Middleware for handling authentication.
+//	drain: Graceful shutdown coordinator; readiness, order submissions and the quest engine report to it (may be nil).
+//	configService: Resolves the dry, paper and live config overlays (may be nil).
 //
 // Returns a cleanup function that should be called on shutdown.
-func SetupRoutes(router *gin.Engine, db routeDB, redis *database.RedisClient, ccxtService ccxt.CCXTService, collectorService *services.CollectorService, cleanupService *services.CleanupService, cacheAnalyticsService *services.CacheAnalyticsService, signalAggregator *services.SignalAggregator, analyticsService *services.AnalyticsService, telegramConfig *config.TelegramConfig, aiConfig *config.AIConfig, featuresConfig *config.FeaturesConfig, authMiddleware *middleware.AuthMiddleware, walletValidator *services.WalletValidator) func() {
+func SetupRoutes(router *gin.Engine, db routeDB, redis *database.RedisClient, ccxtService ccxt.CCXTService, collectorService *services.CollectorService, cleanupService *services.CleanupService, cacheAnalyticsService *services.CacheAnalyticsService, signalAggregator *services.SignalAggregator, analyticsService *services.AnalyticsService, telegramConfig *config.TelegramConfig, aiConfig *config.AIConfig, featuresConfig *config.FeaturesConfig, authMiddleware *middleware.AuthMiddleware, walletValidator *services.WalletValidator, drain *services.ShutdownDrain, configService *services.ConfigService) func() {
+	cfg := loadRouteConfig()
+
 	// Initialize admin middleware
 	adminMiddleware := middleware.NewAdminMiddleware()
+	adminMiddleware.SetSessionVerifier(authMiddleware)
+
+	s := &routeServices{
+		cfg:              cfg,
+		db:               db,
+		redis:            redis,
+		ccxt:             ccxtService,
+		collector:        collectorService,
+		cleanup:          cleanupService,
+		cacheAnalytics:   cacheAnalyticsService,
+		signalAggregator: signalAggregator,
+		analytics:        analyticsService,
+		telegramConfig:   telegramConfig,
+		aiConfig:         aiConfig,
+		featuresConfig:   featuresConfig,
+		auth:             authMiddleware,
+		admin:            adminMiddleware,
+		walletValidator:  walletValidator,
+		drain:            drain,
+		configService:    configService,
+	}
+
+	// Advertise the server version and flag outdated CLI and Telegram clients
+	router.Use(middleware.ClientVersionMiddleware(cfg.appVersion, cfg.minClientVersions))
+	s.handlers.compat = handlers.NewCompatHandler(cfg.appVersion, cfg.minClientVersions)
+
+	s.setupHealth()
+	registerHealthRoutes(router, &s.handlers)
 
-	// Initialize health handler
-	healthHandler := handlers.NewHealthHandler(db, redis, ccxtService.GetServiceURL(), cacheAnalyticsService)
+	// Initialize user handler early for internal routes
+	s.handlers.user = handlers.NewUserHandler(db, redis.Client, authMiddleware)
+
+	s.setupNotifications()
+	s.setupCoreHandlers()
+	s.setupTradingAPI()
+	s.setupTradingModes()
+	s.setupEventBus()
+	s.setupAuditChain()
+	s.setupEventEmitters()
+	s.setupWatchlist()
+	s.setupCustomAlerts()
+	s.setupCapitalAllocation()
+	s.setupOperatingProfiles()
+	s.setupWizard()
+	s.setupOperatorTools()
+	s.setupUserData()
+	s.setupNewListings()
+	s.setupDecisionAudit()
+	s.setupSessionReplay()
+	s.setupExternalSignals()
+	s.setupSignalIngest()
+	s.setupBackups()
+	s.setupMigrations()
+	s.setupDBPoolMonitor()
+	s.setupAIBudget()
+	s.setupQuestEngine()
+	s.setupIntegratedHandlers()
+	s.setupOrderExecutor()
+	s.setupSymbolQuarantine()
+	s.setupKPIStore()
+	s.setupOpportunityLifecycle()
+
+	// Profiling: /debug/pprof on demand, and CPU/heap profiles captured
+	// automatically when request latency or heap usage crosses a threshold
+	if cfg.pprofEnabled {
+		registerPprofRoutes(router, adminMiddleware)
+	}
+	s.setupProfiler()
+	s.setupLoadShedding()
+
+	s.setupEquity()
+	s.setupTradingBudget()
+	s.setupDailyLossCircuit()
+	s.setupTradeHooks()
+	s.setupMakerFirst()
+	s.setupAlgoOrders()
+	s.setupStablecoinMonitor()
+	s.setupExchangeOutage()
+	s.setupLossStreaks()
+	s.setupSymbolCooldowns()
+	s.setupPositionRegistry()
+	s.setupIntentLog()
+	s.setupHedging()
+	s.setupCharts()
+	s.setupMarginManager()
+	s.setupMarketContext()
+	s.setupFundingForecast()
+	s.setupCarryAnalytics()
+	s.setupPositionTracker()
+	s.setupFlattening()
+	s.setupUserDataStreams()
+	s.setupPnL()
+	s.setupReports()
+	s.setupPromptCanary()
+	s.connectScalpingSources()
+	s.setupNotificationActions()
+	s.setupSessionAuth()
+	s.setupTradeMemory()
+	s.setupEmbeddings()
+	s.setupAIScalping()
+	s.setupStartupGuard()
+	s.startQuests()
+	s.setupLoopWatchdog()
+	s.restoreAutonomousChats()
+	s.setupArbitrageBridge()
+
+	// Register integrated handlers for production-ready quest execution
+	s.questEngine.RegisterIntegratedHandlers(s.integrated)
+	s.setupTelegramHandlers()
+
+	// Per-client quotas protect the backend from runaway Telegram service or CLI loops.
+	quotaLimiter := newAPIQuotaLimiter(cfg.quota, redis, authMiddleware, adminMiddleware)
+
+	registerInternalRoutes(router, &s.handlers, quotaLimiter)
+
+	// Symbols as typed (BTCUSDT, btc-usdt, XBT/USD) are resolved to the
+	// exchange's unified symbol before the market, trading and bot handlers.
+	// Analytics and reporting routes use the analytics pool (or read replica)
+	// when one is configured; trading reads and writes stay on the primary.
+	mw := routeMiddleware{
+		auth:              authMiddleware,
+		admin:             adminMiddleware,
+		symbolResolution:  middleware.SymbolResolution(services.NewSymbolResolver(ccxtService, cfg.symbolAliases)),
+		analyticsWorkload: middleware.WorkloadMiddleware(database.WorkloadAnalytics),
+	}
+	registerV1Routes(router, &s.handlers, mw, s.profiler, quotaLimiter)
+
+	// Return cleanup function for WebSocket handler and other resources
+	return s.stop
+}
+
+// routeHandlers holds the HTTP handlers SetupRoutes builds for the route groups.
+type routeHandlers struct {
+	compat               *handlers.CompatHandler
+	health               *handlers.HealthHandler
+	user                 *handlers.UserHandler
+	notificationQueue    *handlers.NotificationQueueHandler
+	market               *handlers.MarketHandler
+	arbitrage            *handlers.ArbitrageHandler
+	circuitBreaker       *handlers.CircuitBreakerHandler
+	analysis             *handlers.AnalysisHandler
+	sentiment            *handlers.SentimentHandler
+	alert                *handlers.AlertHandler
+	cleanup              *handlers.CleanupHandler
+	exchange             *handlers.ExchangeHandler
+	cache                *handlers.CacheHandler
+	webSocket            *handlers.WebSocketHandler
+	ai                   *handlers.AIHandler
+	wallet               *handlers.WalletHandler
+	trading              *handlers.TradingHandler
+	tradingMode          *handlers.TradingModeHandler
+	auditChain           *handlers.AuditChainHandler
+	criticalEscalation   *handlers.CriticalEscalationHandler
+	webhook              *handlers.WebhookHandler
+	watchlist            *handlers.WatchlistHandler
+	customAlert          *handlers.CustomAlertHandler
+	allocation           *handlers.CapitalAllocationHandler
+	profile              *handlers.OperatingProfileHandler
+	strategy             *handlers.StrategyConfigHandler
+	setup                *handlers.SetupHandler
+	selfTest             *handlers.SelfTestHandler
+	stressTest           *handlers.StressTestHandler
+	collection           *handlers.CollectionHandler
+	config               *handlers.ConfigHandler
+	userData             *handlers.UserDataHandler
+	chaos                *handlers.ChaosHandler
+	newListings          *handlers.NewListingsHandler
+	decisionAudit        *handlers.DecisionAuditHandler
+	stateDiff            *handlers.StateDiffHandler
+	sessionReplay        *handlers.SessionReplayHandler
+	tradingView          *handlers.TradingViewHandler
+	signalIngest         *handlers.SignalIngestHandler
+	backup               *handlers.BackupHandler
+	migration            *handlers.MigrationHandler
+	dbPool               *handlers.DBPoolHandler
+	budget               *handlers.BudgetHandler
+	futuresArbitrage     *handlers.FuturesArbitrageHandler
+	symbolQuarantine     *handlers.SymbolQuarantineHandler
+	kpi                  *handlers.KPIHandler
+	opportunityLifecycle *handlers.OpportunityLifecycleHandler
+	profiler             *handlers.ProfilerHandler
+	tradingBudget        *handlers.TradingBudgetHandler
+	dailyLoss            *handlers.DailyLossHandler
+	executionQuality     *handlers.ExecutionQualityHandler
+	algoOrder            *handlers.AlgoOrderHandler
+	stablecoin           *handlers.StablecoinHandler
+	exchangeHealth       *handlers.ExchangeHealthHandler
+	lossStreak           *handlers.LossStreakHandler
+	symbolCooldown       *handlers.SymbolCooldownHandler
+	hedge                *handlers.HedgeHandler
+	chart                *handlers.ChartHandler
+	margin               *handlers.MarginHandler
+	tradeFlow            *handlers.TradeFlowHandler
+	fundingForecast      *handlers.FundingForecastHandler
+	carryAnalytics       *handlers.CarryAnalyticsHandler
+	userDataStream       *handlers.UserDataStreamHandler
+	pnl                  *handlers.PnLHandler
+	share                *handlers.ShareHandler
+	search               *handlers.SearchHandler
+	promptCanary         *handlers.PromptCanaryHandler
+	notificationAction   *handlers.NotificationActionHandler
+	auth                 *handlers.AuthHandler
+	embedding            *handlers.EmbeddingHandler
+	shadowStrategy       *handlers.ShadowStrategyHandler
+	autonomous           *handlers.AutonomousHandler
+	telegramInternal     *handlers.TelegramInternalHandler
+}
+
+// routeMiddleware holds the middleware shared across the v1 route groups.
+type routeMiddleware struct {
+	auth              *middleware.AuthMiddleware
+	admin             *middleware.AdminMiddleware
+	symbolResolution  gin.HandlerFunc
+	analyticsWorkload gin.HandlerFunc
+}
 
-	// Health check endpoints with telemetry
+// registerHealthRoutes mounts the health check endpoints with telemetry.
+func registerHealthRoutes(router *gin.Engine, h *routeHandlers) {
 	healthGroup := router.Group("/")
 	healthGroup.Use(middleware.HealthCheckTelemetryMiddleware())
 	{
-		healthGroup.GET("/health", gin.WrapF(healthHandler.HealthCheck))
-		healthGroup.HEAD("/health", gin.WrapF(healthHandler.HealthCheck))
-		healthGroup.GET("/ready", gin.WrapF(healthHandler.ReadinessCheck))
-		healthGroup.GET("/live", gin.WrapF(healthHandler.LivenessCheck))
+		healthGroup.GET("/health", gin.WrapF(h.health.HealthCheck))
+		healthGroup.HEAD("/health", gin.WrapF(h.health.HealthCheck))
+		healthGroup.GET("/ready", gin.WrapF(h.health.ReadinessCheck))
+		healthGroup.GET("/live", gin.WrapF(h.health.LivenessCheck))
 	}
+}
 
-	// Initialize user handler early for internal routes
-	userHandler := handlers.NewUserHandler(db, redis.Client, authMiddleware)
-
-	// Initialize notification service with Redis caching
-	var notificationService *services.NotificationService
-	if telegramConfig != nil {
-		notificationService = services.NewNotificationService(db, redis, telegramConfig.ServiceURL, telegramConfig.GrpcAddress, telegramConfig.AdminAPIKey)
-	} else {
-		log.Printf("[TELEGRAM] WARNING: telegramConfig is nil, notification service will run with default settings")
-		notificationService = services.NewNotificationService(db, redis, "http://telegram-service:3002", "telegram-service:50052", "")
-	}
-
-	// Initialize handlers
-	marketHandler := handlers.NewMarketHandler(db, ccxtService, collectorService, redis, cacheAnalyticsService)
-	arbitrageHandler := handlers.NewArbitrageHandler(db, ccxtService, notificationService, redis.Client)
-	circuitBreakerHandler := handlers.NewCircuitBreakerHandler(collectorService)
-
-	analysisHandler := handlers.NewAnalysisHandler(db, ccxtService, analyticsService)
-
-	// Sentiment handler - initialize with config from environment
-	sentimentConfig := services.DefaultSentimentServiceConfig()
-	sentimentConfig.RedditClientID = os.Getenv("REDDIT_CLIENT_ID")
-	sentimentConfig.RedditClientSecret = os.Getenv("REDDIT_CLIENT_SECRET")
-	sentimentConfig.CryptoPanicToken = os.Getenv("CRYPTOPANIC_TOKEN")
-	sentimentService := services.NewSentimentService(sentimentConfig, db)
-	sentimentHandler := handlers.NewSentimentHandler(sentimentService)
-
-	// userHandler and telegramInternalHandler already initialized above for internal routes
-	alertHandler := handlers.NewAlertHandler(db)
-	cleanupHandler := handlers.NewCleanupHandler(cleanupService)
-	exchangeHandler := handlers.NewExchangeHandler(ccxtService, collectorService, redis.Client)
-	cacheHandler := handlers.NewCacheHandler(cacheAnalyticsService)
-	webSocketHandler := handlers.NewWebSocketHandler(redis)
-
-	// AI handler - uses registry from ai package
-	aiRegistry := ai.NewRegistry(
-		ai.WithRedis(redis.Client),
-	)
-	aiHandler := handlers.NewAIHandler(aiRegistry, db)
-
-	// Initialize order execution service (Polymarket CLOB)
-	orderExecConfig := services.OrderExecutionConfig{
-		BaseURL:    getEnvOrDefault("POLYMARKET_CLOB_URL", "https://clob.polymarket.com"),
-		APIKey:     os.Getenv("POLYMARKET_API_KEY"),
-		APISecret:  os.Getenv("POLYMARKET_API_SECRET"),
-		WalletAddr: os.Getenv("POLYMARKET_WALLET_ADDRESS"),
-	}
-	orderExecutionService := services.NewOrderExecutionService(orderExecConfig)
-	tradingHandler := handlers.NewTradingHandler(db, orderExecutionService)
-
-	// Budget handler - configurable via environment variables with defaults from migration 054
-	dailyBudgetStr := getEnvOrDefault("AI_DAILY_BUDGET", "10.00")
-	monthlyBudgetStr := getEnvOrDefault("AI_MONTHLY_BUDGET", "200.00")
-
-	dailyBudget, err := decimal.NewFromString(dailyBudgetStr)
-	if err != nil {
-		log.Printf("WARNING: Invalid AI_DAILY_BUDGET value '%s', using default 10.00", dailyBudgetStr)
-		dailyBudget = decimal.NewFromFloat(10.00)
-	}
-
-	monthlyBudget, err := decimal.NewFromString(monthlyBudgetStr)
-	if err != nil {
-		log.Printf("WARNING: Invalid AI_MONTHLY_BUDGET value '%s', using default 200.00", monthlyBudgetStr)
-		monthlyBudget = decimal.NewFromFloat(200.00)
-	}
-
-	budgetHandler := handlers.NewBudgetHandler(
-		database.NewAIUsageRepository(db),
-		dailyBudget,
-		monthlyBudget,
-	)
-
-	questStore := services.NewInMemoryQuestStore()
-	questEngine := services.NewQuestEngineWithNotification(questStore, nil, notificationService)
-
-	// Legacy quest preload is opt-in only.
-	// In scalping-first mode we avoid restoring old active rows without metadata/chat ownership.
-	loadLegacyQuests := os.Getenv("NEURATRADE_LOAD_LEGACY_ACTIVE_QUESTS") == "1" ||
-		os.Getenv("NEURATRADE_LOAD_LEGACY_ACTIVE_QUESTS") == "true"
-	log.Printf("DEBUG: db is nil: %v", db == nil)
-	if db != nil && loadLegacyQuests {
-		log.Println("Loading legacy active quests from database into memory...")
-		rows, err := db.Query(context.Background(), "SELECT id, type, cadence, status, target_value, checkpoint, created_at FROM quests WHERE status = 'active'")
-		if err != nil {
-			log.Printf("Failed to load quests from database: %v", err)
-		} else {
-			defer rows.Close()
-			loadedCount := 0
-			for rows.Next() {
-				var id, questType, cadence, status string
-				var targetValue float64
-				var checkpoint []byte
-				var createdAt time.Time
-				if err := rows.Scan(&id, &questType, &cadence, &status, &targetValue, &checkpoint, &createdAt); err != nil {
-					log.Printf("Failed to scan quest row: %v", err)
-					continue
-				}
-				quest := &services.Quest{
-					ID:          id,
-					Type:        services.QuestType(questType),
-					Cadence:     services.QuestCadence(cadence),
-					Status:      services.QuestStatus(status),
-					TargetCount: int(targetValue),
-					CreatedAt:   createdAt,
-					UpdatedAt:   time.Now(),
-				}
-				if len(checkpoint) > 0 {
-					var cp map[string]interface{}
-					if err := json.Unmarshal(checkpoint, &cp); err == nil {
-						quest.Checkpoint = cp
-					}
-				}
-				if err := questStore.SaveQuest(context.Background(), quest); err != nil {
-					log.Printf("Failed to save quest %s: %v", id, err)
-				}
-				log.Printf("Loaded quest from DB: %s (type: %s, status: %s)", id, questType, status)
-				loadedCount++
-			}
-			log.Printf("Loaded %d quests from database", loadedCount)
+// registerInternalRoutes mounts the service-to-service routes (no auth,
+// network-isolated via Docker).
+func registerInternalRoutes(router *gin.Engine, h *routeHandlers, quotaLimiter *middleware.QuotaLimiter) {
+	internal := router.Group("/internal")
+	if quotaLimiter != nil {
+		internal.Use(quotaLimiter.Middleware())
+	}
+	{
+		internalTelegram := internal.Group("/telegram")
+		{
+			internalTelegram.GET("/users/:id", h.telegramInternal.GetUserByChatID)
+			internalTelegram.GET("/notifications/:userId", h.telegramInternal.GetNotificationPreferences)
+			internalTelegram.POST("/notifications/:userId", h.telegramInternal.SetNotificationPreferences)
+			internalTelegram.POST("/autonomous/begin", h.telegramInternal.BeginAutonomous)
+			internalTelegram.POST("/autonomous/pause", h.telegramInternal.PauseAutonomous)
+			internalTelegram.POST("/autonomous/resume", h.telegramInternal.ResumeAutonomous)
+			internalTelegram.POST("/wallets/connect_exchange", h.telegramInternal.ConnectExchange)
+			internalTelegram.POST("/wallets/connect_polymarket", h.telegramInternal.ConnectPolymarket)
+			internalTelegram.POST("/wallets", h.telegramInternal.AddWallet)
+			internalTelegram.POST("/wallets/remove", h.telegramInternal.RemoveWallet)
+			internalTelegram.GET("/wallets", h.telegramInternal.GetWallets)
+			internalTelegram.GET("/doctor", h.telegramInternal.GetDoctor)
+			internalTelegram.POST("/callbacks", h.notificationAction.HandleCallback)
 		}
-	} else if db != nil {
-		log.Println("Skipping legacy active quest preload (set NEURATRADE_LOAD_LEGACY_ACTIVE_QUESTS=1 to enable)")
 	}
+}
 
-	// Initialize futures arbitrage handler first (needed for quest handlers)
-	var futuresArbitrageHandler *handlers.FuturesArbitrageHandler
-	if db != nil {
-		futuresArbitrageHandler = handlers.NewFuturesArbitrageHandlerWithQuerier(db)
-		log.Printf("Futures arbitrage handler initialized successfully")
-	} else {
-		log.Printf("Database not available for futures arbitrage handler initialization")
-	}
-
-	// Create autonomous monitoring for tracking quest execution
-	autonomousMonitoring := services.NewAutonomousMonitorManager(notificationService)
-
-	// Create integrated quest handlers with actual implementations
-	integratedHandlers := services.NewIntegratedQuestHandlers(
-		nil,                     // TA service - TODO: Initialize when ready
-		ccxtService,             // CCXT service
-		arbitrageHandler,        // Arbitrage service
-		futuresArbitrageHandler, // Futures arbitrage
-		notificationService,     // Notification service
-		autonomousMonitoring,    // Monitoring service
-	)
-
-	// Wire order executor to integrated handlers for scalping execution
-	adminAPIKey := os.Getenv("ADMIN_API_KEY")
-	if adminAPIKey == "" {
-		log.Printf("WARNING: ADMIN_API_KEY is not set; CCXT order executor requests will be unauthenticated")
-	}
-	ccxtServiceURL := os.Getenv("CCXT_SERVICE_URL")
-	if ccxtServiceURL == "" {
-		ccxtServiceURL = "http://localhost:3001"
-	}
-	log.Printf("CCXT Order Executor configured with URL: %s", ccxtServiceURL)
-	ccxtOrderExec := services.NewCCXTOrderExecutor(services.CCXTOrderExecutorConfig{
-		ServiceURL: ccxtServiceURL,
-		APIKey:     adminAPIKey,
-		Timeout:    30 * time.Second,
-	})
-	integratedHandlers.SetOrderExecutor(ccxtOrderExec)
-
-	var sqlDB *sql.DB
-	switch concreteDB := db.(type) {
-	case *database.SQLiteDB:
-		sqlDB = concreteDB.DB
-	case *database.PostgresDB:
-		sqlDB = concreteDB.SQL
-	default:
-		log.Printf("Warning: Unknown database type, AI learning disabled")
-	}
-
-	if sqlDB != nil {
-		tradeMemory, err := services.NewTradeMemory(sqlDB)
-		if err != nil {
-			log.Printf("Warning: Failed to create trade memory: %v", err)
-		} else {
-			integratedHandlers.SetTradeMemory(tradeMemory)
-			log.Printf("Trade memory initialized for AI learning")
-		}
+// registerV1Routes mounts the /api/v1 routes with telemetry, latency
+// profiling and per-client quotas.
+func registerV1Routes(router *gin.Engine, h *routeHandlers, mw routeMiddleware, profiler *services.Profiler, quotaLimiter *middleware.QuotaLimiter) {
+	v1 := router.Group("/api/v1")
+	v1.Use(middleware.TelemetryMiddleware())
+	if profiler != nil && profiler.Config().LatencyThreshold > 0 {
+		v1.Use(middleware.LatencyMiddleware(profiler))
+	}
+	if quotaLimiter != nil {
+		v1.Use(quotaLimiter.Middleware())
 	}
 
-	var aiAPIKey, aiBaseURL, aiProvider string
-	if aiConfig != nil && aiConfig.APIKey != "" {
-		aiAPIKey = aiConfig.APIKey
-		aiBaseURL = aiConfig.BaseURL
-		if aiBaseURL == "" {
-			aiBaseURL = "https://api.minimax.chat/v1"
-		}
-		aiProvider = aiConfig.Provider
-		if aiProvider == "" {
-			aiProvider = "minimax"
-		}
+	// OpenAPI document; the CLI client is generated from it.
+	v1.GET("/openapi.json", openapi.Handler(NewOpenAPIRegistry(), router))
+
+	registerGeneralRoutes(v1, h, mw)
+	registerMarketRoutes(v1, h, mw)
+	registerTelegramRoutes(v1, h, mw)
+	registerAccountRoutes(v1, h, mw)
+	registerTradingRoutes(v1, h, mw)
+	registerExchangeRoutes(v1, h, mw)
+	registerAdminRoutes(v1, h, mw)
+}
+
+// registerGeneralRoutes mounts client compatibility, inbound alerts, shared
+// performance, search, the setup wizard and embedding routes.
+func registerGeneralRoutes(v1 *gin.RouterGroup, h *routeHandlers, mw routeMiddleware) {
+	// Minimum supported client versions for the X-Client-Version handshake
+	v1.GET("/compat", h.compat.GetCompat)
+
+	// Inbound alerts authenticated by a shared secret rather than user auth
+	v1.POST("/webhooks/tradingview", h.tradingView.ReceiveAlert)
+
+	// Read-only performance behind a signed share link
+	v1.GET("/share/:token", h.share.GetSharedPerformance)
+	v1.GET("/share/:token/page", h.share.GetSharedPage)
+
+	// Operator-only: results include trade PnL and exchange errors
+	v1.GET("/search", mw.admin.RequireAdminAuth(), h.search.Search)
+
+	// First-run setup wizard; admin-only as steps reveal configuration
+	setup := v1.Group("/setup")
+	setup.Use(mw.admin.RequireAdminAuth())
+	{
+		setup.GET("/state", h.setup.GetState)
+		setup.DELETE("/state", h.setup.ResetState)
+		setup.PUT("/state/:step", h.setup.UpdateStep)
+		setup.POST("/verify", h.setup.Verify)
 	}
 
-	if aiAPIKey != "" {
-		log.Printf("Initializing AI Scalping with provider: %s (base_url: %s)", aiProvider, aiBaseURL)
+	// Embedding pipeline: operator notes and progress (admin)
+	embeddings := v1.Group("/embeddings", mw.admin.RequireAdminAuth())
+	{
+		embeddings.POST("/notes", h.embedding.AddNote)
+		embeddings.GET("/stats", h.embedding.GetStats)
+	}
+}
 
-		llmConfig := llm.ClientConfig{
-			APIKey:      aiAPIKey,
-			BaseURL:     aiBaseURL,
-			HTTPTimeout: 120,
-		}
+// registerMarketRoutes mounts the market data, arbitrage, opportunity,
+// futures arbitrage, technical analysis and sentiment routes.
+func registerMarketRoutes(v1 *gin.RouterGroup, h *routeHandlers, mw routeMiddleware) {
+	// Market data routes
+	market := v1.Group("/market")
+	market.Use(mw.symbolResolution)
+	{
+		market.GET("/prices", h.market.GetMarketPrices)
+		market.GET("/ticker/:exchange/:symbol", h.market.GetTicker)
+		market.GET("/tickers/:exchange", h.market.GetBulkTickers)
+		market.GET("/bulk", h.market.GetBulkMarketData)
+		market.GET("/orderbook/:exchange/:symbol", h.market.GetOrderBook)
+		market.GET("/orderbook/:exchange/:symbol/metrics", h.market.GetOrderBookMetrics)
+		market.GET("/derivatives/:exchange", h.market.GetDerivatives)
+		market.GET("/workers/status", h.market.GetWorkerStatus)
+		market.GET("/quarantine", h.market.GetQuarantinedTicks)
+		market.GET("/trade-flow", h.tradeFlow.GetTradeFlow)
+		market.GET("/ws", h.webSocket.HandleWebSocket)
+		market.GET("/ws/stats", func(c *gin.Context) {
+			c.JSON(200, h.webSocket.GetStats())
+		})
+	}
 
-		var llmClient llm.Client
-		switch aiProvider {
-		case "openai":
-			llmClient = llm.NewOpenAIClient(llmConfig)
-		case "anthropic":
-			llmClient = llm.NewAnthropicClient(llmConfig)
-		case "mlx":
-			llmClient = llm.NewMLXClient(llmConfig)
-		default:
-			llmClient = llm.NewOpenAIClient(llmConfig)
-		}
+	// Arbitrage routes
+	arbitrage := v1.Group("/arbitrage")
+	{
+		arbitrage.GET("/opportunities", h.arbitrage.GetArbitrageOpportunities)
+		arbitrage.GET("/history", mw.analyticsWorkload, h.arbitrage.GetArbitrageHistory)
+		arbitrage.GET("/stats", mw.analyticsWorkload, h.arbitrage.GetArbitrageStats)
+		// Funding rate arbitrage
+		arbitrage.GET("/funding", h.arbitrage.GetFundingRateArbitrage)
+		arbitrage.GET("/funding-rates/:exchange", h.arbitrage.GetFundingRates)
+		arbitrage.GET("/funding-forecasts", h.fundingForecast.GetForecasts)
+		arbitrage.GET("/carry", mw.analyticsWorkload, h.carryAnalytics.GetCarry)
+	}
+
+	// Opportunity lifecycle history: detected, notified, executed and
+	// expired edges with durations and capture rates
+	opportunities := v1.Group("/opportunities")
+	{
+		opportunities.GET("/history", mw.analyticsWorkload, h.opportunityLifecycle.GetHistory)
+	}
 
-		skillRegistry := skill.NewRegistry(filepath.Join(filepath.Dir(""), "skills"))
-		if err := skillRegistry.LoadAll(); err != nil {
-			log.Printf("Warning: Failed to load skills: %v", err)
+	// Futures arbitrage routes (only if handler initialized successfully)
+	if h.futuresArbitrage != nil {
+		futuresArbitrage := v1.Group("/futures-arbitrage")
+		{
+			futuresArbitrage.GET("/opportunities", h.futuresArbitrage.GetFuturesArbitrageOpportunities)
+			futuresArbitrage.POST("/calculate", h.futuresArbitrage.CalculateFuturesArbitrage)
+			futuresArbitrage.GET("/strategy/:id", h.futuresArbitrage.GetFuturesArbitrageStrategy)
+			futuresArbitrage.GET("/market-summary", h.futuresArbitrage.GetFuturesMarketSummary)
+			futuresArbitrage.POST("/position-sizing", h.futuresArbitrage.GetPositionSizingRecommendation)
 		}
-		integratedHandlers.SetAIScalping(llmClient, skillRegistry)
-		log.Printf("AI Scalping service initialized successfully")
+		log.Printf("Futures arbitrage routes registered successfully")
 	} else {
-		log.Printf("AI API key not configured in ~/.neuratrade/config.json, AI scalping disabled")
-	}
-
-	questEngine.Start() // Start the quest engine scheduler
-
-	// Restore autonomous scalping for operator chats that were enabled via Telegram /begin.
-	if db != nil {
-		rows, err := db.Query(
-			context.Background(),
-			"SELECT chat_id FROM telegram_operator_state WHERE autonomous_enabled = TRUE ORDER BY updated_at DESC LIMIT 1",
-		)
-		if err != nil {
-			log.Printf("Failed to restore autonomous-enabled chats: %v", err)
-		} else {
-			defer rows.Close()
-			restored := 0
-			for rows.Next() {
-				var chatID string
-				if err := rows.Scan(&chatID); err != nil {
-					log.Printf("Failed to scan autonomous chat row: %v", err)
-					continue
-				}
-				chatID = strings.TrimSpace(chatID)
-				if chatID == "" {
-					continue
-				}
-				if _, err := questEngine.BeginAutonomous(chatID); err != nil {
-					log.Printf("Failed to restore autonomous mode for chat %s: %v", chatID, err)
-					continue
-				}
-				restored++
-			}
-			log.Printf("Restored autonomous scalping for %d chat(s) from telegram_operator_state (latest enabled chat only)", restored)
+		log.Printf("Skipping futures arbitrage routes due to handler initialization failure")
+	}
+
+	// Technical analysis routes
+	analysis := v1.Group("/analysis")
+	analysis.Use(mw.analyticsWorkload, mw.symbolResolution)
+	{
+		analysis.GET("/indicators", h.analysis.GetTechnicalIndicators)
+		analysis.GET("/signals", h.analysis.GetTradingSignals)
+		analysis.GET("/correlation", h.analysis.GetCorrelationMatrix)
+		analysis.GET("/regime", h.analysis.GetMarketRegime)
+		analysis.GET("/forecast", h.analysis.GetForecast)
+	}
+
+	// Sentiment routes - news and reddit sentiment analysis
+	sentiment := v1.Group("/sentiment")
+	{
+		sentiment.GET("/:symbol", h.sentiment.GetSentiment)
+		sentiment.POST("/refresh", h.sentiment.RefreshSentiment)
+		sentiment.GET("/sources", h.sentiment.GetSentimentSources)
+	}
+}
+
+// registerTelegramRoutes mounts the Telegram bot routes. Both new
+// (/internal/telegram/*) and legacy (/api/v1/telegram/internal/*) paths work.
+func registerTelegramRoutes(v1 *gin.RouterGroup, h *routeHandlers, mw routeMiddleware) {
+	telegram := v1.Group("/telegram")
+	{
+		// Legacy paths kept for backward compatibility with older telegram-service versions.
+		// Resume re-arms live trading, so it is served on /internal/telegram only.
+		telegram.GET("/internal/users/:id", h.telegramInternal.GetUserByChatID)
+		telegram.GET("/internal/notifications/:userId", h.telegramInternal.GetNotificationPreferences)
+		telegram.POST("/internal/notifications/:userId", h.telegramInternal.SetNotificationPreferences)
+		telegram.POST("/internal/autonomous/begin", h.telegramInternal.BeginAutonomous)
+		telegram.POST("/internal/autonomous/pause", h.telegramInternal.PauseAutonomous)
+		telegram.POST("/internal/wallets/connect_exchange", h.telegramInternal.ConnectExchange)
+		telegram.POST("/internal/wallets/connect_polymarket", h.telegramInternal.ConnectPolymarket)
+		telegram.POST("/internal/wallets", h.telegramInternal.AddWallet)
+		telegram.POST("/internal/wallets/remove", h.telegramInternal.RemoveWallet)
+		telegram.GET("/internal/wallets", h.telegramInternal.GetWallets)
+		telegram.GET("/internal/doctor", h.telegramInternal.GetDoctor)
+		telegram.POST("/internal/callbacks", h.notificationAction.HandleCallback)
+
+		telegramInternal := telegram.Group("/internal")
+		telegramInternal.Use(mw.admin.RequireAdminAuth(), mw.symbolResolution)
+		{
+			telegramInternal.GET("/quests", h.autonomous.GetQuests)
+			telegramInternal.POST("/quests/fund-growth", h.autonomous.CreateFundGrowthGoal)
+			telegramInternal.PUT("/quests/:id/catch-up", h.autonomous.SetQuestCatchUp)
+			telegramInternal.GET("/portfolio", h.autonomous.GetPortfolio)
+			telegramInternal.GET("/equity", h.autonomous.GetEquity)
+			telegramInternal.GET("/logs", h.autonomous.GetLogs)
+			telegramInternal.GET("/performance/summary", mw.analyticsWorkload, h.autonomous.GetPerformanceSummary)
+			telegramInternal.GET("/performance", mw.analyticsWorkload, h.autonomous.GetPerformanceBreakdown)
+			telegramInternal.POST("/liquidate", h.autonomous.Liquidate)
+			telegramInternal.POST("/liquidate/all", h.autonomous.LiquidateAll)
+			telegramInternal.GET("/watchlist", h.watchlist.GetWatchlist)
+			telegramInternal.POST("/watchlist", h.watchlist.UpdateWatchlist)
+			telegramInternal.GET("/allocation", h.allocation.GetAllocation)
+			telegramInternal.POST("/allocation", h.allocation.UpdateAllocation)
+			telegramInternal.GET("/trading-budget", h.tradingBudget.GetTradingBudget)
+			telegramInternal.POST("/trading-budget", h.tradingBudget.UpdateTradingBudget)
+			telegramInternal.GET("/profiles", h.profile.ListProfiles)
+			telegramInternal.POST("/profiles", h.profile.SaveProfile)
+			telegramInternal.POST("/profiles/select", h.profile.SelectProfile)
+			telegramInternal.DELETE("/profiles/:name", h.profile.DeleteProfile)
+			telegramInternal.GET("/strategies", h.strategy.ListStrategies)
+			telegramInternal.POST("/strategies", h.strategy.InstallStrategy)
+			telegramInternal.GET("/strategies/schema", h.strategy.Schema)
+			telegramInternal.POST("/strategies/validate", h.strategy.ValidateStrategy)
+			telegramInternal.GET("/strategies/:name/export", h.strategy.ExportStrategy)
+			telegramInternal.DELETE("/strategies/:name", h.strategy.UninstallStrategy)
+			telegramInternal.GET("/risk/daily-loss", h.dailyLoss.GetStatus)
+			telegramInternal.POST("/risk/daily-loss", h.dailyLoss.RecordRealized)
+			telegramInternal.GET("/risk/loss-streaks", h.lossStreak.GetLossStreaks)
+			telegramInternal.POST("/risk/loss-streaks", h.lossStreak.UpdateLossStreaks)
+			telegramInternal.GET("/risk/cooldowns", h.symbolCooldown.GetCooldowns)
+			telegramInternal.POST("/risk/cooldowns", h.symbolCooldown.UpdateCooldowns)
+			telegramInternal.GET("/hedge", h.hedge.GetSuggestions)
+			telegramInternal.POST("/hedge", h.hedge.ConfirmSuggestion)
+			telegramInternal.GET("/chart", h.chart.GetChart)
+			telegramInternal.GET("/pnl", h.pnl.GetReport)
+			telegramInternal.POST("/share-links", h.share.CreateLink)
+			telegramInternal.DELETE("/share-links/:id", h.share.RevokeLink)
+			telegramInternal.GET("/alerts/custom", h.customAlert.ListAlerts)
+			telegramInternal.POST("/alerts/custom", h.customAlert.CreateAlert)
+			telegramInternal.POST("/alerts/custom/preview", h.customAlert.PreviewAlert)
+			telegramInternal.PUT("/alerts/custom/:id", h.customAlert.UpdateAlert)
+			telegramInternal.DELETE("/alerts/custom/:id", h.customAlert.DeleteAlert)
 		}
 	}
+}
 
-	// Scalping-first mode: keep arbitrage execution bridge disabled by default.
-	// It can be re-enabled only when AI arbitrage mode is explicitly turned on.
-	if featuresConfig != nil && featuresConfig.EnableAIArbitrage {
-		arbitrageBridge := services.NewArbitrageExecutionBridge(db, questEngine, signalAggregator, nil)
-		go func() {
-			if err := arbitrageBridge.Start(context.Background()); err != nil {
-				log.Printf("Arbitrage execution bridge error: %v", err)
-			}
-		}()
-		log.Printf("Arbitrage execution bridge enabled (features.enable_ai_arbitrage=true)")
-	} else {
-		log.Printf("Arbitrage execution bridge disabled in scalping-first mode")
+// registerAccountRoutes mounts session auth, user management, alerts, data
+// management and risk routes.
+func registerAccountRoutes(v1 *gin.RouterGroup, h *routeHandlers, mw routeMiddleware) {
+	// Session auth
+	auth := v1.Group("/auth")
+	{
+		auth.POST("/login", h.auth.Login)
+		auth.POST("/otp", h.auth.RequestLoginCode)
+		auth.POST("/refresh", h.auth.Refresh)
+		auth.POST("/logout", mw.auth.RequireAuth(), h.auth.Logout)
 	}
 
-	// Register integrated handlers for production-ready quest execution
-	questEngine.RegisterIntegratedHandlers(integratedHandlers)
+	// User management
+	users := v1.Group("/users")
+	{
+		users.POST("/register", h.user.RegisterUser)
+		users.POST("/login", h.user.LoginUser)
+		users.GET("/profile", mw.auth.RequireAuth(), h.user.GetUserProfile)
+		users.GET("/notifications/delivery", mw.auth.RequireAuth(), h.notificationQueue.GetUserDeliveryStats)
+		users.DELETE("/:id/data", mw.admin.RequireAdminAuth(), h.userData.EraseUserData)
+	}
 
-	autonomousHandler := handlers.NewAutonomousHandler(questEngine)
-	telegramInternalHandler := handlers.NewTelegramInternalHandler(db, userHandler, questEngine)
+	// Alerts management
+	alerts := v1.Group("/alerts")
+	alerts.Use(mw.auth.RequireAuth())
+	{
+		alerts.GET("/", h.alert.GetUserAlerts)
+		alerts.POST("/", h.alert.CreateAlert)
+		alerts.PUT("/:id", h.alert.UpdateAlert)
+		alerts.DELETE("/:id", h.alert.DeleteAlert)
+	}
 
-	// Internal service-to-service routes (no auth, network-isolated via Docker)
-	internal := router.Group("/internal")
+	// Data management
+	data := v1.Group("/data")
 	{
-		internalTelegram := internal.Group("/telegram")
+		data.GET("/stats", mw.analyticsWorkload, h.cleanup.GetDataStats)
+		data.POST("/cleanup", h.cleanup.TriggerCleanup)
+	}
+
+	// Risk management
+	risk := v1.Group("/risk")
+	{
+		risk.GET("/metrics", gin.WrapF(h.health.GetRiskMetrics))
+	}
+
+	adminRisk := v1.Group("/admin/risk")
+	adminRisk.Use(mw.admin.RequireAdminAuth())
+	{
+		adminRisk.POST("/validate_wallet", h.wallet.ValidateWallet)
+	}
+}
+
+// registerTradingRoutes mounts the trading, decision replay, signal, budget
+// and AI model routes.
+func registerTradingRoutes(v1 *gin.RouterGroup, h *routeHandlers, mw routeMiddleware) {
+	trading := v1.Group("/trading")
+	trading.Use(mw.auth.RequireAuth(), mw.auth.RequireScope(middleware.ScopeTrade), mw.symbolResolution)
+	{
+		trading.POST("/place_order", h.trading.PlaceOrder)
+		trading.POST("/cancel_order", h.trading.CancelOrder)
+		trading.POST("/liquidate", h.trading.Liquidate)
+		trading.POST("/liquidate_all", h.trading.LiquidateAll)
+		trading.GET("/positions", h.trading.ListPositions)
+		trading.GET("/positions/snapshot", h.trading.GetPositionSnapshot)
+		trading.GET("/positions/:position_id", h.trading.GetPosition)
+		trading.GET("/execution_quality", h.executionQuality.GetExecutionQuality)
+		trading.GET("/streams", h.userDataStream.GetStreams)
+	}
+
+	// Leverage, margin and algo orders act on the shared exchange
+	// account, so only operator sessions may use them
+	operatorTrading := v1.Group("/trading")
+	operatorTrading.Use(mw.auth.RequireAuth(), mw.auth.RequireScope(middleware.ScopeAdmin), mw.symbolResolution)
+	{
+		operatorTrading.GET("/leverage", h.margin.GetLeverage)
+		operatorTrading.POST("/leverage", h.margin.SetLeverage)
+		operatorTrading.GET("/margin/positions", h.margin.ListPositions)
+		operatorTrading.POST("/algo_orders", h.algoOrder.SubmitAlgoOrder)
+		operatorTrading.GET("/algo_orders", h.algoOrder.ListAlgoOrders)
+		operatorTrading.GET("/algo_orders/:id", h.algoOrder.GetAlgoOrder)
+		operatorTrading.POST("/algo_orders/:id/cancel", h.algoOrder.CancelAlgoOrder)
+	}
+
+	// Decision replay: prompts and balances are redacted, but the full
+	// context is still operator-only
+	decisions := v1.Group("/decisions")
+	decisions.Use(mw.admin.RequireAdminAuth())
+	{
+		decisions.GET("/:id", h.decisionAudit.GetDecision)
+	}
+
+	replay := v1.Group("/replay")
+	replay.Use(mw.admin.RequireAdminAuth())
+	{
+		replay.GET("/day", h.sessionReplay.ReplayDay)
+	}
+
+	signals := v1.Group("/signals")
+	signals.Use(mw.auth.RequireAuth())
+	{
+		signals.POST("/ingest", h.signalIngest.IngestSignal)
+		signals.GET("/sources", h.signalIngest.ListSignalSources)
+	}
+
+	// Outcomes move a source's reliability score and with it how much
+	// weight its signals get, so only operators may record them
+	signalOutcomes := v1.Group("/signals")
+	signalOutcomes.Use(mw.admin.RequireAdminAuth())
+	{
+		signalOutcomes.POST("/:id/outcome", h.signalIngest.RecordSignalOutcome)
+	}
+
+	budget := v1.Group("/budget")
+	budget.Use(mw.auth.RequireAuth())
+	{
+		budget.GET("/status", h.budget.GetBudgetStatus)
+		budget.GET("/check", h.budget.CheckBudget)
+	}
+
+	// AI model routes
+	ai := v1.Group("/ai")
+	{
+		ai.GET("/models", h.ai.GetModels)
+		ai.POST("/route", h.ai.RouteModel)
+		aiAuth := ai.Group("")
+		aiAuth.Use(mw.auth.RequireAuth())
 		{
-			internalTelegram.GET("/users/:id", telegramInternalHandler.GetUserByChatID)
-			internalTelegram.GET("/notifications/:userId", telegramInternalHandler.GetNotificationPreferences)
-			internalTelegram.POST("/notifications/:userId", telegramInternalHandler.SetNotificationPreferences)
-			internalTelegram.POST("/autonomous/begin", telegramInternalHandler.BeginAutonomous)
-			internalTelegram.POST("/autonomous/pause", telegramInternalHandler.PauseAutonomous)
-			internalTelegram.POST("/wallets/connect_exchange", telegramInternalHandler.ConnectExchange)
-			internalTelegram.POST("/wallets/connect_polymarket", telegramInternalHandler.ConnectPolymarket)
-			internalTelegram.POST("/wallets", telegramInternalHandler.AddWallet)
-			internalTelegram.POST("/wallets/remove", telegramInternalHandler.RemoveWallet)
-			internalTelegram.GET("/wallets", telegramInternalHandler.GetWallets)
-			internalTelegram.GET("/doctor", telegramInternalHandler.GetDoctor)
+			aiAuth.POST("/select/:userId", h.ai.SelectModel)
+			aiAuth.GET("/status/:userId", h.ai.GetModelStatus)
 		}
 	}
+}
 
-	// Initialize wallet handler
-	walletHandler := handlers.NewWalletHandler(walletValidator)
-
-	// API v1 routes with telemetry
-	v1 := router.Group("/api/v1")
-	v1.Use(middleware.TelemetryMiddleware())
+// registerExchangeRoutes mounts exchange management, cache monitoring,
+// database pool metrics and mode-scoped configuration routes.
+func registerExchangeRoutes(v1 *gin.RouterGroup, h *routeHandlers, mw routeMiddleware) {
+	// Exchange management
+	exchanges := v1.Group("/exchanges")
 	{
-		// Market data routes
-		market := v1.Group("/market")
+		// Public endpoints (no admin auth required)
+		exchanges.GET("/config", h.exchange.GetExchangeConfig)
+		exchanges.GET("/supported", h.exchange.GetSupportedExchanges)
+		exchanges.GET("/workers/status", h.exchange.GetWorkerStatus)
+
+		// Admin-only endpoints (require admin authentication)
+		adminExchanges := exchanges.Group("/")
+		adminExchanges.Use(mw.admin.RequireAdminAuth())
 		{
-			market.GET("/prices", marketHandler.GetMarketPrices)
-			market.GET("/ticker/:exchange/:symbol", marketHandler.GetTicker)
-			market.GET("/tickers/:exchange", marketHandler.GetBulkTickers)
-			market.GET("/orderbook/:exchange/:symbol", marketHandler.GetOrderBook)
-			market.GET("/orderbook/:exchange/:symbol/metrics", marketHandler.GetOrderBookMetrics)
-			market.GET("/workers/status", marketHandler.GetWorkerStatus)
-			market.GET("/ws", webSocketHandler.HandleWebSocket)
-			market.GET("/ws/stats", func(c *gin.Context) {
-				c.JSON(200, webSocketHandler.GetStats())
-			})
+			adminExchanges.POST("/refresh", h.exchange.RefreshExchanges)
+			adminExchanges.POST("/add/:exchange", h.exchange.AddExchange)
+			adminExchanges.POST("/blacklist/:exchange", h.exchange.AddExchangeToBlacklist)
+			adminExchanges.DELETE("/blacklist/:exchange", h.exchange.RemoveExchangeFromBlacklist)
+			adminExchanges.POST("/workers/:exchange/restart", h.exchange.RestartWorker)
 		}
+	}
 
-		// Arbitrage routes
-		arbitrage := v1.Group("/arbitrage")
+	// Cache monitoring and analytics
+	cache := v1.Group("/cache")
+	{
+		cache.GET("/stats", h.cache.GetCacheStats)
+		cache.GET("/stats/:category", h.cache.GetCacheStatsByCategory)
+		cache.GET("/metrics", h.cache.GetCacheMetrics)
+		cache.GET("/bounded", h.cache.GetBoundedCaches)
+		cache.POST("/stats/reset", h.cache.ResetCacheStats)
+		cache.POST("/hit", h.cache.RecordCacheHit)
+		cache.POST("/miss", h.cache.RecordCacheMiss)
+	}
+
+	// Database connection pool metrics
+	databaseGroup := v1.Group("/database")
+	{
+		databaseGroup.GET("/metrics", h.dbPool.GetPoolMetrics)
+	}
+
+	// Mode-scoped configuration (require admin authentication)
+	configGroup := v1.Group("/config")
+	configGroup.Use(mw.admin.RequireAdminAuth())
+	{
+		configGroup.GET("/effective", h.config.GetEffective)
+	}
+}
+
+// registerAdminRoutes mounts the operator endpoints (require admin
+// authentication).
+func registerAdminRoutes(v1 *gin.RouterGroup, h *routeHandlers, mw routeMiddleware) {
+	admin := v1.Group("/admin")
+	admin.Use(mw.admin.RequireAdminAuth())
+	{
+		// Circuit breaker management
+		circuitBreakers := admin.Group("/circuit-breakers")
 		{
-			arbitrage.GET("/opportunities", arbitrageHandler.GetArbitrageOpportunities)
-			arbitrage.GET("/history", arbitrageHandler.GetArbitrageHistory)
-			arbitrage.GET("/stats", arbitrageHandler.GetArbitrageStats)
-			// Funding rate arbitrage
-			arbitrage.GET("/funding", arbitrageHandler.GetFundingRateArbitrage)
-			arbitrage.GET("/funding-rates/:exchange", arbitrageHandler.GetFundingRates)
+			circuitBreakers.GET("", h.circuitBreaker.GetCircuitBreakerStats)
+			circuitBreakers.POST("/:name/reset", h.circuitBreaker.ResetCircuitBreaker)
+			circuitBreakers.POST("/reset-all", h.circuitBreaker.ResetAllCircuitBreakers)
 		}
 
-		// Futures arbitrage routes (only if handler initialized successfully)
-		if futuresArbitrageHandler != nil {
-			futuresArbitrage := v1.Group("/futures-arbitrage")
-			{
-				futuresArbitrage.GET("/opportunities", futuresArbitrageHandler.GetFuturesArbitrageOpportunities)
-				futuresArbitrage.POST("/calculate", futuresArbitrageHandler.CalculateFuturesArbitrage)
-				futuresArbitrage.GET("/strategy/:id", futuresArbitrageHandler.GetFuturesArbitrageStrategy)
-				futuresArbitrage.GET("/market-summary", futuresArbitrageHandler.GetFuturesMarketSummary)
-				futuresArbitrage.POST("/position-sizing", futuresArbitrageHandler.GetPositionSizingRecommendation)
-			}
-			log.Printf("Futures arbitrage routes registered successfully")
-		} else {
-			log.Printf("Skipping futures arbitrage routes due to handler initialization failure")
+		// End-to-end dry-run self-test behind neuratrade doctor --deep
+		admin.POST("/self-test", h.selfTest.RunSelfTest)
+		admin.POST("/stress-test", h.stressTest.RunStressTest)
+		admin.POST("/collect", h.collection.CollectNow)
+
+		// Verification of the hash-chained decision and trade log
+		auditChainGroup := admin.Group("/audit-chain")
+		{
+			auditChainGroup.GET("/verify", h.auditChain.Verify)
+			auditChainGroup.POST("/anchor", h.auditChain.Anchor)
 		}
 
-		// Technical analysis routes
-		analysis := v1.Group("/analysis")
+		// Config, strategy, prompt and model changes between decisions
+		stateGroup := admin.Group("/state")
 		{
-			analysis.GET("/indicators", analysisHandler.GetTechnicalIndicators)
-			analysis.GET("/signals", analysisHandler.GetTradingSignals)
-			analysis.GET("/correlation", analysisHandler.GetCorrelationMatrix)
-			analysis.GET("/regime", analysisHandler.GetMarketRegime)
-			analysis.GET("/forecast", analysisHandler.GetForecast)
+			stateGroup.GET("/diff", h.stateDiff.GetDiff)
 		}
 
-		// Sentiment routes - news and reddit sentiment analysis
-		sentiment := v1.Group("/sentiment")
+		// Dependency fault injection for degradation drills
+		chaosGroup := admin.Group("/chaos")
 		{
-			sentiment.GET("/:symbol", sentimentHandler.GetSentiment)
-			sentiment.POST("/refresh", sentimentHandler.RefreshSentiment)
-			sentiment.GET("/sources", sentimentHandler.GetSentimentSources)
+			chaosGroup.GET("", h.chaos.GetFaults)
+			chaosGroup.POST("", h.chaos.InjectFault)
+			chaosGroup.DELETE("", h.chaos.ClearFaults)
 		}
 
-		// Telegram internal routes - backward compatible (no auth for internal network)
-		// Both new (/internal/telegram/*) and legacy (/api/v1/telegram/internal/*) paths work
-		telegram := v1.Group("/telegram")
+		// Execution mode, kill switch and two-man-rule confirmations
+		tradingMode := admin.Group("/trading-mode")
 		{
-			// Legacy paths kept for backward compatibility with older telegram-service versions
-			telegram.GET("/internal/users/:id", telegramInternalHandler.GetUserByChatID)
-			telegram.GET("/internal/notifications/:userId", telegramInternalHandler.GetNotificationPreferences)
-			telegram.POST("/internal/notifications/:userId", telegramInternalHandler.SetNotificationPreferences)
-			telegram.POST("/internal/autonomous/begin", telegramInternalHandler.BeginAutonomous)
-			telegram.POST("/internal/autonomous/pause", telegramInternalHandler.PauseAutonomous)
-			telegram.POST("/internal/wallets/connect_exchange", telegramInternalHandler.ConnectExchange)
-			telegram.POST("/internal/wallets/connect_polymarket", telegramInternalHandler.ConnectPolymarket)
-			telegram.POST("/internal/wallets", telegramInternalHandler.AddWallet)
-			telegram.POST("/internal/wallets/remove", telegramInternalHandler.RemoveWallet)
-			telegram.GET("/internal/wallets", telegramInternalHandler.GetWallets)
-			telegram.GET("/internal/doctor", telegramInternalHandler.GetDoctor)
-
-			telegramInternal := telegram.Group("/internal")
-			telegramInternal.Use(adminMiddleware.RequireAdminAuth())
-			{
-				telegramInternal.GET("/quests", autonomousHandler.GetQuests)
-				telegramInternal.GET("/portfolio", autonomousHandler.GetPortfolio)
-				telegramInternal.GET("/logs", autonomousHandler.GetLogs)
-				telegramInternal.GET("/performance/summary", autonomousHandler.GetPerformanceSummary)
-				telegramInternal.GET("/performance", autonomousHandler.GetPerformanceBreakdown)
-				telegramInternal.POST("/liquidate", autonomousHandler.Liquidate)
-				telegramInternal.POST("/liquidate/all", autonomousHandler.LiquidateAll)
-			}
+			tradingMode.GET("", h.tradingMode.GetState)
+			tradingMode.POST("/mode", h.tradingMode.SwitchMode)
+			tradingMode.POST("/kill-switch", h.tradingMode.EngageKillSwitch)
+			tradingMode.POST("/kill-switch/release", h.tradingMode.ReleaseKillSwitch)
+			tradingMode.GET("/confirmations/:id", h.tradingMode.GetConfirmation)
+			tradingMode.POST("/confirmations/:id/confirm", h.tradingMode.Confirm)
+			tradingMode.POST("/confirmations/:id/cancel", h.tradingMode.Cancel)
+			tradingMode.GET("/go-live", h.tradingMode.GetGoLiveSchedule)
+			tradingMode.POST("/go-live", h.tradingMode.ScheduleGoLive)
+			tradingMode.POST("/go-live/cancel", h.tradingMode.CancelGoLive)
 		}
 
-		// User management
-		users := v1.Group("/users")
+		// Critical alerts awaiting acknowledgment before SMS/phone escalation
+		escalations := admin.Group("/escalations")
 		{
-			users.POST("/register", userHandler.RegisterUser)
-			users.POST("/login", userHandler.LoginUser)
-			users.GET("/profile", authMiddleware.RequireAuth(), userHandler.GetUserProfile)
+			escalations.GET("", h.criticalEscalation.ListAlerts)
+			escalations.POST("/:id/ack", h.criticalEscalation.Acknowledge)
 		}
 
-		// Alerts management
-		alerts := v1.Group("/alerts")
-		alerts.Use(authMiddleware.RequireAuth())
+		// Outbound webhooks
+		webhooks := admin.Group("/webhooks")
 		{
-			alerts.GET("/", alertHandler.GetUserAlerts)
-			alerts.POST("/", alertHandler.CreateAlert)
-			alerts.PUT("/:id", alertHandler.UpdateAlert)
-			alerts.DELETE("/:id", alertHandler.DeleteAlert)
+			webhooks.GET("", h.webhook.ListWebhooks)
+			webhooks.POST("", h.webhook.CreateWebhook)
+			webhooks.DELETE("/:id", h.webhook.DeleteWebhook)
+			webhooks.POST("/:id/test", h.webhook.TestWebhook)
 		}
 
-		// Data management
-		data := v1.Group("/data")
+		// State snapshots for disaster recovery
+		backup := admin.Group("/backup")
 		{
-			data.GET("/stats", cleanupHandler.GetDataStats)
-			data.POST("/cleanup", cleanupHandler.TriggerCleanup)
+			backup.POST("", h.backup.CreateBackup)
+			backup.POST("/restore", h.backup.RestoreBackup)
 		}
 
-		// Risk management
-		risk := v1.Group("/risk")
+		// Database migrations
+		migrations := admin.Group("/migrations")
 		{
-			risk.GET("/metrics", gin.WrapF(healthHandler.GetRiskMetrics))
+			migrations.GET("", h.migration.GetMigrations)
+			migrations.POST("/migrate", h.migration.RunMigrations)
+			migrations.POST("/rollback", h.migration.RollbackMigration)
 		}
 
-		adminRisk := v1.Group("/admin/risk")
-		adminRisk.Use(adminMiddleware.RequireAdminAuth())
+		// Generated symbol universe and global exclusions
+		universe := admin.Group("/universe")
 		{
-			adminRisk.POST("/validate_wallet", walletHandler.ValidateWallet)
+			universe.GET("", h.watchlist.GetUniverse)
+			universe.POST("/refresh", h.watchlist.RefreshUniverse)
+			universe.PUT("/exclusions", h.watchlist.SetExclusions)
 		}
 
-		trading := v1.Group("/trading")
-		trading.Use(authMiddleware.RequireAuth())
+		// Stablecoin peg status
+		stablecoins := admin.Group("/stablecoins")
 		{
-			trading.POST("/place_order", tradingHandler.PlaceOrder)
-			trading.POST("/cancel_order", tradingHandler.CancelOrder)
-			trading.POST("/liquidate", tradingHandler.Liquidate)
-			trading.POST("/liquidate_all", tradingHandler.LiquidateAll)
-			trading.GET("/positions", tradingHandler.ListPositions)
-			trading.GET("/positions/snapshot", tradingHandler.GetPositionSnapshot)
-			trading.GET("/positions/:position_id", tradingHandler.GetPosition)
+			stablecoins.GET("", h.stablecoin.GetStatus)
+			stablecoins.POST("/check", h.stablecoin.CheckNow)
 		}
 
-		budget := v1.Group("/budget")
-		budget.Use(authMiddleware.RequireAuth())
+		// Shadow-mode strategy evaluation
+		shadowStrategy := admin.Group("/shadow-strategy")
 		{
-			budget.GET("/status", budgetHandler.GetBudgetStatus)
-			budget.GET("/check", budgetHandler.CheckBudget)
+			shadowStrategy.GET("", h.shadowStrategy.GetReport)
+			shadowStrategy.GET("/comparisons", h.shadowStrategy.GetComparisons)
+			shadowStrategy.POST("/reset", h.shadowStrategy.Reset)
 		}
 
-		// AI model routes
-		ai := v1.Group("/ai")
+		// Prompt/model canary rollout
+		promptCanaryGroup := admin.Group("/prompt-canary")
 		{
-			ai.GET("/models", aiHandler.GetModels)
-			ai.POST("/route", aiHandler.RouteModel)
-			aiAuth := ai.Group("")
-			aiAuth.Use(authMiddleware.RequireAuth())
-			{
-				aiAuth.POST("/select/:userId", aiHandler.SelectModel)
-				aiAuth.GET("/status/:userId", aiHandler.GetModelStatus)
-			}
+			promptCanaryGroup.GET("", h.promptCanary.GetStatus)
+			promptCanaryGroup.POST("/evaluate", h.promptCanary.EvaluateNow)
+			promptCanaryGroup.POST("/rollback", h.promptCanary.Rollback)
 		}
 
-		// Exchange management
-		exchanges := v1.Group("/exchanges")
+		// Exchange health and outage pauses
+		exchangeHealthGroup := admin.Group("/exchange-health")
 		{
-			// Public endpoints (no admin auth required)
-			exchanges.GET("/config", exchangeHandler.GetExchangeConfig)
-			exchanges.GET("/supported", exchangeHandler.GetSupportedExchanges)
-			exchanges.GET("/workers/status", exchangeHandler.GetWorkerStatus)
-
-			// Admin-only endpoints (require admin authentication)
-			adminExchanges := exchanges.Group("/")
-			adminExchanges.Use(adminMiddleware.RequireAdminAuth())
-			{
-				adminExchanges.POST("/refresh", exchangeHandler.RefreshExchanges)
-				adminExchanges.POST("/add/:exchange", exchangeHandler.AddExchange)
-				adminExchanges.POST("/blacklist/:exchange", exchangeHandler.AddExchangeToBlacklist)
-				adminExchanges.DELETE("/blacklist/:exchange", exchangeHandler.RemoveExchangeFromBlacklist)
-				adminExchanges.POST("/workers/:exchange/restart", exchangeHandler.RestartWorker)
-			}
+			exchangeHealthGroup.GET("", h.exchangeHealth.GetHealth)
+			exchangeHealthGroup.POST("/check", h.exchangeHealth.CheckNow)
 		}
 
-		// Cache monitoring and analytics
-		cache := v1.Group("/cache")
+		// Quarantined exchange/symbol pairs and manual overrides
+		quarantine := admin.Group("/quarantine")
 		{
-			cache.GET("/stats", cacheHandler.GetCacheStats)
-			cache.GET("/stats/:category", cacheHandler.GetCacheStatsByCategory)
-			cache.GET("/metrics", cacheHandler.GetCacheMetrics)
-			cache.POST("/stats/reset", cacheHandler.ResetCacheStats)
-			cache.POST("/hit", cacheHandler.RecordCacheHit)
-			cache.POST("/miss", cacheHandler.RecordCacheMiss)
+			quarantine.GET("", h.symbolQuarantine.GetQuarantine)
+			quarantine.POST("", h.symbolQuarantine.UpdateQuarantine)
 		}
 
-		// Admin endpoints (require admin authentication)
-		admin := v1.Group("/admin")
-		admin.Use(adminMiddleware.RequireAdminAuth())
+		// Newly listed symbols under probation
+		listings := admin.Group("/listings")
 		{
-			// Circuit breaker management
-			circuitBreakers := admin.Group("/circuit-breakers")
-			{
-				circuitBreakers.GET("", circuitBreakerHandler.GetCircuitBreakerStats)
-				circuitBreakers.POST("/:name/reset", circuitBreakerHandler.ResetCircuitBreaker)
-				circuitBreakers.POST("/reset-all", circuitBreakerHandler.ResetAllCircuitBreakers)
-			}
+			listings.GET("", h.newListings.GetListings)
+			listings.POST("/scan", h.newListings.ScanListings)
 		}
-	}
 
-	// Return cleanup function for WebSocket handler and other resources
-	return func() {
-		if webSocketHandler != nil {
-			webSocketHandler.Stop()
+		// Internal KPI history
+		admin.GET("/kpis", h.kpi.GetKPIs)
+
+		// Profiles captured on latency and memory threshold breaches
+		admin.GET("/profiles", h.profiler.GetCaptures)
+
+		// Notification delivery queue and dead letters
+		notifications := admin.Group("/notifications")
+		{
+			notifications.GET("/queue", h.notificationQueue.GetQueueStats)
+			notifications.POST("/dead-letters/replay", h.notificationQueue.ReplayDeadLetters)
+			notifications.GET("/delivery/:userId", h.notificationQueue.GetUserDeliveryStats)
 		}
 	}
 }
-
-// Placeholder handlers - to be implemented
-
-// Arbitrage handlers are now implemented in handlers/arbitrage.go
-// Technical analysis handlers are now implemented in handlers/analysis.go
-// Alert handlers are now implemented in handlers/alert.go
@@ -3,7 +3,10 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -340,7 +343,7 @@ func TestSetupRoutes_PanicHandling(t *testing.T) {
 	assert.NotNil(t, router)
 
 	assert.Panics(t, func() {
-		SetupRoutes(router, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		SetupRoutes(router, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	}, "SetupRoutes should panic with nil dependencies")
 }
 
@@ -383,7 +386,7 @@ func TestSetupRoutes_RouteRegistration(t *testing.T) {
 	mockAuthMiddleware := middleware.NewAuthMiddleware("test-secret-key-must-be-32-chars-min!")
 
 	assert.NotPanics(t, func() {
-		SetupRoutes(router, mockDB, mockRedis, mockCCXT, nil, nil, nil, nil, nil, mockTelegramConfig, nil, nil, mockAuthMiddleware, nil)
+		SetupRoutes(router, mockDB, mockRedis, mockCCXT, nil, nil, nil, nil, nil, mockTelegramConfig, nil, nil, mockAuthMiddleware, nil, nil, nil)
 	}, "SetupRoutes should handle minimal dependencies gracefully")
 
 	// Verify routes were registered
@@ -439,7 +442,7 @@ func TestSetupRoutes_RouteGroups(t *testing.T) {
 		}),
 	}
 	mockAuthMiddleware := middleware.NewAuthMiddleware("test-secret-key-must-be-32-chars-min!")
-	SetupRoutes(router, mockDB, mockRedis, mockCCXT, nil, nil, nil, nil, nil, mockTelegramConfig, nil, nil, mockAuthMiddleware, nil)
+	SetupRoutes(router, mockDB, mockRedis, mockCCXT, nil, nil, nil, nil, nil, mockTelegramConfig, nil, nil, mockAuthMiddleware, nil, nil, nil)
 
 	// Get all routes
 	routes := router.Routes()
@@ -503,7 +506,7 @@ func TestSetupRoutes_HttpMethods(t *testing.T) {
 		}),
 	}
 	mockAuthMiddleware := middleware.NewAuthMiddleware("test-secret-key-must-be-32-chars-min!")
-	SetupRoutes(router, mockDB, mockRedis, mockCCXT, nil, nil, nil, nil, nil, mockTelegramConfig, nil, nil, mockAuthMiddleware, nil)
+	SetupRoutes(router, mockDB, mockRedis, mockCCXT, nil, nil, nil, nil, nil, mockTelegramConfig, nil, nil, mockAuthMiddleware, nil, nil, nil)
 
 	// Get all routes
 	routes := router.Routes()
@@ -570,7 +573,7 @@ func TestSetupRoutes_Middleware(t *testing.T) {
 		}),
 	}
 	mockAuthMiddleware := middleware.NewAuthMiddleware("test-secret-key-must-be-32-chars-min!")
-	SetupRoutes(router, mockDB, mockRedis, mockCCXT, nil, nil, nil, nil, nil, mockTelegramConfig, nil, nil, mockAuthMiddleware, nil)
+	SetupRoutes(router, mockDB, mockRedis, mockCCXT, nil, nil, nil, nil, nil, mockTelegramConfig, nil, nil, mockAuthMiddleware, nil, nil, nil)
 
 	// Test that router has middleware configured
 	// Gin router should have middleware registered
@@ -625,7 +628,7 @@ func TestSetupRoutes_MissingAdminKey(t *testing.T) {
 			}),
 		}
 		mockAuthMiddleware := middleware.NewAuthMiddleware("test-secret-key-must-be-32-chars-min!")
-		SetupRoutes(router, mockDB, mockRedis, mockCCXT, nil, nil, nil, nil, nil, mockTelegramConfig, nil, nil, mockAuthMiddleware, nil)
+		SetupRoutes(router, mockDB, mockRedis, mockCCXT, nil, nil, nil, nil, nil, mockTelegramConfig, nil, nil, mockAuthMiddleware, nil, nil, nil)
 	}, "SetupRoutes should handle missing admin key gracefully")
 }
 
@@ -668,10 +671,133 @@ func TestSetupRoutes_MissingTelegramConfig(t *testing.T) {
 			}),
 		}
 		mockAuthMiddleware := middleware.NewAuthMiddleware("test-secret-key-must-be-32-chars-min!")
-		SetupRoutes(router, mockDB, mockRedis, mockCCXT, nil, nil, nil, nil, nil, mockTelegramConfig, nil, nil, mockAuthMiddleware, nil)
+		SetupRoutes(router, mockDB, mockRedis, mockCCXT, nil, nil, nil, nil, nil, mockTelegramConfig, nil, nil, mockAuthMiddleware, nil, nil, nil)
 	}, "SetupRoutes should not panic when telegram config is missing")
 
 	// Verify routes were still registered
 	routes := router.Routes()
 	assert.Greater(t, len(routes), 0, "Routes should be registered even without telegram config")
 }
+
+// TestRegisterPprofRoutes tests that profiling endpoints require admin authentication
+func TestRegisterPprofRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	oldAdminKey, adminKeyExists := os.LookupEnv("ADMIN_API_KEY")
+	defer restoreEnv(t, "ADMIN_API_KEY", oldAdminKey, adminKeyExists)
+	mustSetEnv(t, "ADMIN_API_KEY", "test-admin-key-that-is-at-least-32-chars")
+
+	router := gin.New()
+	registerPprofRoutes(router, middleware.NewAdminMiddleware())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/pprof/heap?debug=1", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	for _, path := range []string{"/debug/pprof/", "/debug/pprof/heap?debug=1", "/debug/pprof/cmdline"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-API-Key", "test-admin-key-that-is-at-least-32-chars")
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, path)
+	}
+}
+
+// setupTestRouter registers every route against mock dependencies and
+// returns the router with the auth middleware that signs its sessions.
+func setupTestRouter(t *testing.T) (*gin.Engine, *middleware.AuthMiddleware) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	t.Setenv("ADMIN_API_KEY", "test-admin-key-that-is-at-least-32-chars")
+	t.Setenv("TELEGRAM_BOT_TOKEN", "test-token")
+	t.Setenv("TELEGRAM_CHAT_ID", "test-chat-id")
+
+	mockCCXT := &testmocks.MockCCXTService{}
+	mockCCXT.On("GetServiceURL").Return("test-url")
+	mockRedis := &database.RedisClient{Client: redis.NewClient(&redis.Options{Addr: "localhost:6379"})}
+	authMiddleware := middleware.NewAuthMiddleware("test-secret-key-must-be-32-chars-min!")
+
+	router := gin.New()
+	SetupRoutes(router, setupMockDB(t), mockRedis, mockCCXT, nil, nil, nil, nil, nil, &config.TelegramConfig{BotToken: "test-token"}, nil, nil, authMiddleware, nil, nil, nil)
+	return router, authMiddleware
+}
+
+// TestSetupRoutes_AlgoOrdersRequireAdmin tests that a user login token,
+// which carries read and trade scopes, cannot drive algo orders
+func TestSetupRoutes_AlgoOrdersRequireAdmin(t *testing.T) {
+	router, authMiddleware := setupTestRouter(t)
+	userToken, err := authMiddleware.GenerateToken("user-1", "user@example.com", time.Hour)
+	require.NoError(t, err)
+
+	for _, path := range []string{"/api/v1/trading/algo_orders", "/api/v1/trading/algo_orders/algo-1/cancel"} {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{}`))
+		req.Header.Set("Authorization", "Bearer "+userToken)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code, path)
+	}
+}
+
+// TestSetupRoutes_MarginRequiresAdmin tests that leverage and margin routes,
+// which act on the shared exchange account, reject user login tokens
+func TestSetupRoutes_MarginRequiresAdmin(t *testing.T) {
+	router, authMiddleware := setupTestRouter(t)
+	userToken, err := authMiddleware.GenerateToken("user-1", "user@example.com", time.Hour)
+	require.NoError(t, err)
+
+	for _, tc := range []struct{ method, path string }{
+		{http.MethodGet, "/api/v1/trading/leverage?exchange=binance&symbol=BTC/USDT"},
+		{http.MethodPost, "/api/v1/trading/leverage"},
+		{http.MethodGet, "/api/v1/trading/margin/positions"},
+	} {
+		req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(`{"exchange":"binance","symbol":"BTC/USDT","leverage":20}`))
+		req.Header.Set("Authorization", "Bearer "+userToken)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code, tc.path)
+	}
+}
+
+// TestSetupRoutes_ResumeIsInternalOnly tests that resuming autonomous
+// trading is not reachable on the public legacy telegram path
+func TestSetupRoutes_ResumeIsInternalOnly(t *testing.T) {
+	router, _ := setupTestRouter(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/telegram/internal/autonomous/resume", strings.NewReader(`{"chat_id":"777"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	found := false
+	for _, route := range router.Routes() {
+		if route.Method == http.MethodPost && route.Path == "/internal/telegram/autonomous/resume" {
+			found = true
+		}
+	}
+	assert.True(t, found, "resume should stay on the internal group")
+}
+
+// TestSetupRoutes_SignalOutcomeRequiresAdmin tests that a user session
+// cannot record signal outcomes while the admin key can
+func TestSetupRoutes_SignalOutcomeRequiresAdmin(t *testing.T) {
+	router, authMiddleware := setupTestRouter(t)
+	userToken, err := authMiddleware.GenerateToken("user-1", "user@example.com", time.Hour)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/signals/sig-1/outcome", strings.NewReader(`{"profitable":true}`))
+	req.Header.Set("Authorization", "Bearer "+userToken)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/signals/sig-1/outcome", strings.NewReader(`{"profitable":true}`))
+	req.Header.Set("X-API-Key", "test-admin-key-that-is-at-least-32-chars")
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.NotEqual(t, http.StatusUnauthorized, w.Code)
+	assert.NotEqual(t, http.StatusForbidden, w.Code)
+}
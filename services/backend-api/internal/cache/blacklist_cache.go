@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
 	"time"
 
@@ -37,6 +38,8 @@ type BlacklistCacheStats struct {
 	Misses int64 `json:"misses"`
 	// Adds is the total number of entries added to the cache.
 	Adds int64 `json:"adds"`
+	// Evictions is the number of entries dropped to stay within capacity.
+	Evictions int64 `json:"evictions"`
 	// LastCleanup is the timestamp of the last cache cleanup operation.
 	LastCleanup time.Time `json:"last_cleanup"`
 }
@@ -63,6 +66,41 @@ type BlacklistCache interface {
 	GetBlacklistedSymbols() ([]BlacklistCacheEntry, error)
 }
 
+// PairKey is the blacklist key of a symbol on one exchange. Entries keyed by a
+// bare exchange name blacklist the whole exchange.
+//
+// Parameters:
+//
+//	exchange: The exchange name.
+//	symbol: The trading pair, e.g. "BTC/USDT".
+//
+// Returns:
+//
+//	string: The "exchange:symbol" key.
+func PairKey(exchange, symbol string) string {
+	return exchange + ":" + symbol
+}
+
+// SplitPairKey splits a key built by PairKey. Symbols may contain colons
+// themselves (e.g. "BTC/USDT:USDT"), so only the first one separates them.
+//
+// Parameters:
+//
+//	key: A blacklist key.
+//
+// Returns:
+//
+//	string: The exchange name.
+//	string: The symbol.
+//	bool: False if the key is not an exchange/symbol pair.
+func SplitPairKey(key string) (string, string, bool) {
+	exchange, symbol, ok := strings.Cut(key, ":")
+	if !ok || exchange == "" || symbol == "" {
+		return "", "", false
+	}
+	return exchange, symbol, true
+}
+
 // BlacklistRepository interface defines the contract for database operations.
 // This allows for dependency injection and testing with mock implementations.
 type BlacklistRepository interface {
@@ -478,23 +516,46 @@ func (rbc *RedisBlacklistCache) CleanupExpired() int {
 	return expiredCount
 }
 
+// DefaultBlacklistMaxEntries caps the in-memory blacklist cache when no limit
+// is configured.
+const DefaultBlacklistMaxEntries = 10000
+
 // InMemoryBlacklistCache provides a fallback in-memory implementation of BlacklistCache.
-// It uses a Go map to store blacklist entries and is thread-safe.
+// Entries live in a bounded LRU cache, so a long-running deployment cannot grow
+// it without limit; the least recently checked entry is evicted when full.
 type InMemoryBlacklistCache struct {
-	cache map[string]*BlacklistCacheEntry
-	mu    sync.RWMutex
-	stats BlacklistCacheStats
+	entries *Bounded[string, *BlacklistCacheEntry]
+	mu      sync.RWMutex
+	stats   BlacklistCacheStats
 }
 
-// NewInMemoryBlacklistCache creates a new in-memory blacklist cache.
+// NewInMemoryBlacklistCache creates a new in-memory blacklist cache holding
+// up to DefaultBlacklistMaxEntries entries.
 //
 // Returns:
 //
 //	*InMemoryBlacklistCache: A pointer to the initialized in-memory cache.
 func NewInMemoryBlacklistCache() *InMemoryBlacklistCache {
+	return NewInMemoryBlacklistCacheWithLimit(DefaultBlacklistMaxEntries)
+}
+
+// NewInMemoryBlacklistCacheWithLimit creates a new in-memory blacklist cache
+// holding up to maxEntries entries.
+//
+// Parameters:
+//
+//	maxEntries: Capacity; zero or less uses DefaultBlacklistMaxEntries.
+//
+// Returns:
+//
+//	*InMemoryBlacklistCache: A pointer to the initialized in-memory cache.
+func NewInMemoryBlacklistCacheWithLimit(maxEntries int) *InMemoryBlacklistCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultBlacklistMaxEntries
+	}
 	return &InMemoryBlacklistCache{
-		cache: make(map[string]*BlacklistCacheEntry),
-		stats: BlacklistCacheStats{},
+		entries: NewBounded[string, *BlacklistCacheEntry](BoundedConfig{Name: "blacklist", MaxEntries: maxEntries}),
+		stats:   BlacklistCacheStats{},
 	}
 }
 
@@ -509,39 +570,16 @@ func NewInMemoryBlacklistCache() *InMemoryBlacklistCache {
 //	bool: True if blacklisted.
 //	string: The reason for blacklisting.
 func (ibc *InMemoryBlacklistCache) IsBlacklisted(symbol string) (bool, string) {
-	// First, try with read lock for the common case
-	ibc.mu.RLock()
-	entry, exists := ibc.cache[symbol]
-	if !exists {
-		ibc.mu.RUnlock()
-		// Need write lock to update stats
-		ibc.mu.Lock()
-		ibc.stats.Misses++
-		ibc.mu.Unlock()
-		return false, ""
-	}
+	// Expired entries are dropped by the bounded cache on lookup
+	entry, exists := ibc.entries.Get(symbol)
 
-	// Check if entry has expired
-	if entry.ExpiresAt != nil && time.Now().After(*entry.ExpiresAt) {
-		ibc.mu.RUnlock()
-		// Need write lock to remove expired entry and update stats
-		ibc.mu.Lock()
-		// Double-check the entry still exists and is expired
-		if entry, exists := ibc.cache[symbol]; exists && entry.ExpiresAt != nil && time.Now().After(*entry.ExpiresAt) {
-			delete(ibc.cache, symbol)
-			ibc.stats.ExpiredEntries++
-			ibc.stats.TotalEntries--
-		}
+	ibc.mu.Lock()
+	defer ibc.mu.Unlock()
+	if !exists {
 		ibc.stats.Misses++
-		ibc.mu.Unlock()
 		return false, ""
 	}
-
-	ibc.mu.RUnlock()
-	// Need write lock to update stats
-	ibc.mu.Lock()
 	ibc.stats.Hits++
-	ibc.mu.Unlock()
 	return true, entry.Reason
 }
 
@@ -553,9 +591,6 @@ func (ibc *InMemoryBlacklistCache) IsBlacklisted(symbol string) (bool, string) {
 //	reason: The reason.
 //	ttl: The time-to-live.
 func (ibc *InMemoryBlacklistCache) Add(symbol, reason string, ttl time.Duration) {
-	ibc.mu.Lock()
-	defer ibc.mu.Unlock()
-
 	entry := &BlacklistCacheEntry{
 		Symbol:    symbol,
 		Reason:    reason,
@@ -569,9 +604,10 @@ func (ibc *InMemoryBlacklistCache) Add(symbol, reason string, ttl time.Duration)
 	}
 	// If ttl <= 0, ExpiresAt remains nil (no expiration)
 
-	ibc.cache[symbol] = entry
+	ibc.entries.SetWithTTL(symbol, entry, ttl)
+	ibc.mu.Lock()
 	ibc.stats.Adds++
-	ibc.stats.TotalEntries++
+	ibc.mu.Unlock()
 	log.Printf("Blacklisted symbol %s for %s (TTL: %v)", symbol, reason, ttl)
 }
 
@@ -581,24 +617,15 @@ func (ibc *InMemoryBlacklistCache) Add(symbol, reason string, ttl time.Duration)
 //
 //	symbol: The symbol to remove.
 func (ibc *InMemoryBlacklistCache) Remove(symbol string) {
-	ibc.mu.Lock()
-	defer ibc.mu.Unlock()
-
-	if _, exists := ibc.cache[symbol]; exists {
-		delete(ibc.cache, symbol)
-		ibc.stats.TotalEntries--
+	if ibc.entries.Delete(symbol) {
 		log.Printf("Removed symbol %s from blacklist", symbol)
 	}
 }
 
 // Clear removes all blacklisted symbols (in-memory implementation).
 func (ibc *InMemoryBlacklistCache) Clear() {
-	ibc.mu.Lock()
-	defer ibc.mu.Unlock()
-
-	count := len(ibc.cache)
-	ibc.cache = make(map[string]*BlacklistCacheEntry)
-	ibc.stats.TotalEntries = 0
+	count := ibc.entries.Len()
+	ibc.entries.Clear()
 	log.Printf("Cleared %d blacklisted symbols", count)
 }
 
@@ -608,16 +635,21 @@ func (ibc *InMemoryBlacklistCache) Clear() {
 //
 //	BlacklistCacheStats: The statistics.
 func (ibc *InMemoryBlacklistCache) GetStats() BlacklistCacheStats {
+	bounded := ibc.entries.BoundedStats()
 	ibc.mu.RLock()
 	defer ibc.mu.RUnlock()
-	return ibc.stats
+	stats := ibc.stats
+	stats.TotalEntries = int64(bounded.Entries)
+	stats.ExpiredEntries = bounded.Expirations
+	stats.Evictions = bounded.Evictions
+	return stats
 }
 
 // LogStats logs current cache statistics (in-memory implementation).
 func (ibc *InMemoryBlacklistCache) LogStats() {
 	stats := ibc.GetStats()
-	log.Printf("Blacklist Cache Stats - Total: %d, Hits: %d, Misses: %d, Adds: %d, Expired: %d",
-		stats.TotalEntries, stats.Hits, stats.Misses, stats.Adds, stats.ExpiredEntries)
+	log.Printf("Blacklist Cache Stats - Total: %d, Hits: %d, Misses: %d, Adds: %d, Expired: %d, Evicted: %d",
+		stats.TotalEntries, stats.Hits, stats.Misses, stats.Adds, stats.ExpiredEntries, stats.Evictions)
 }
 
 // Close closes the cache (in-memory implementation).
@@ -650,17 +682,12 @@ func (ibc *InMemoryBlacklistCache) LoadFromDatabase(ctx context.Context) error {
 //	[]BlacklistCacheEntry: List of entries.
 //	error: Always nil.
 func (ibc *InMemoryBlacklistCache) GetBlacklistedSymbols() ([]BlacklistCacheEntry, error) {
-	ibc.mu.RLock()
-	defer ibc.mu.RUnlock()
-
+	// Range skips expired entries
 	var entries []BlacklistCacheEntry
-	for _, entry := range ibc.cache {
-		// Check if entry has expired
-		if entry.ExpiresAt != nil && time.Now().After(*entry.ExpiresAt) {
-			continue // Skip expired entries
-		}
+	ibc.entries.Range(func(_ string, entry *BlacklistCacheEntry) bool {
 		entries = append(entries, *entry)
-	}
+		return true
+	})
 
 	return entries, nil
 }
@@ -978,6 +978,24 @@ func TestInMemoryBlacklistCache_LogStats(t *testing.T) {
 	assert.Equal(t, int64(2), stats.Adds)
 }
 
+// TestInMemoryBlacklistCache_EvictsOverLimit tests that the in-memory cache stays within its limit
+func TestInMemoryBlacklistCache_EvictsOverLimit(t *testing.T) {
+	cache := NewInMemoryBlacklistCacheWithLimit(2)
+
+	cache.Add("binance:BTC/USDT", "test reason", time.Hour)
+	cache.Add("binance:ETH/USDT", "test reason", time.Hour)
+	cache.Add("binance:SOL/USDT", "test reason", time.Hour)
+
+	isBlacklisted, _ := cache.IsBlacklisted("binance:BTC/USDT")
+	assert.False(t, isBlacklisted)
+	isBlacklisted, _ = cache.IsBlacklisted("binance:SOL/USDT")
+	assert.True(t, isBlacklisted)
+
+	stats := cache.GetStats()
+	assert.Equal(t, int64(2), stats.TotalEntries)
+	assert.Equal(t, int64(1), stats.Evictions)
+}
+
 // TestInMemoryBlacklistCache_Close tests closing the in-memory cache
 func TestInMemoryBlacklistCache_Close(t *testing.T) {
 	cache := NewInMemoryBlacklistCache()
@@ -1026,3 +1044,21 @@ func TestBlacklistRepositoryInterface(t *testing.T) {
 	assert.True(t, entry.IsActive)
 	assert.Nil(t, entry.ExpiresAt)
 }
+
+func TestPairKey(t *testing.T) {
+	key := PairKey("binance", "BTC/USDT")
+	assert.Equal(t, "binance:BTC/USDT", key)
+
+	exchange, symbol, ok := SplitPairKey(key)
+	assert.True(t, ok)
+	assert.Equal(t, "binance", exchange)
+	assert.Equal(t, "BTC/USDT", symbol)
+
+	exchange, symbol, ok = SplitPairKey("bybit:BTC/USDT:USDT")
+	assert.True(t, ok)
+	assert.Equal(t, "bybit", exchange)
+	assert.Equal(t, "BTC/USDT:USDT", symbol, "derivative symbols keep their settlement suffix")
+
+	_, _, ok = SplitPairKey("binance")
+	assert.False(t, ok)
+}
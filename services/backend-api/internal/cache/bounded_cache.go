@@ -0,0 +1,266 @@
+package cache
+
+import (
+	"container/list"
+	"sort"
+	"sync"
+	"time"
+)
+
+// BoundedConfig configures a memory-bounded cache.
+type BoundedConfig struct {
+	// Name identifies the cache in eviction metrics. Caches without a name
+	// are not registered.
+	Name string
+	// MaxEntries caps the number of entries; the least recently used entry
+	// is evicted to make room. Zero or less means 10000.
+	MaxEntries int
+	// TTL is the default time-to-live of an entry. Zero means entries only
+	// leave through eviction or removal.
+	TTL time.Duration
+}
+
+// BoundedStats holds the size and eviction counters of a bounded cache.
+type BoundedStats struct {
+	// Name identifies the cache.
+	Name string `json:"name"`
+	// Entries is the current number of entries.
+	Entries int `json:"entries"`
+	// MaxEntries is the configured capacity.
+	MaxEntries int `json:"max_entries"`
+	// Hits is the number of lookups that found a live entry.
+	Hits int64 `json:"hits"`
+	// Misses is the number of lookups that found nothing or an expired entry.
+	Misses int64 `json:"misses"`
+	// Evictions is the number of entries dropped to stay within capacity.
+	Evictions int64 `json:"evictions"`
+	// Expirations is the number of entries dropped after their TTL.
+	Expirations int64 `json:"expirations"`
+}
+
+// BoundedStatsSource reports the counters of a bounded structure.
+type BoundedStatsSource interface {
+	// BoundedStats returns the current size and eviction counters.
+	BoundedStats() BoundedStats
+}
+
+type boundedEntry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// Bounded is a thread-safe LRU cache with optional per-entry TTL and a hard
+// cap on its size.
+type Bounded[K comparable, V any] struct {
+	config  BoundedConfig
+	mu      sync.Mutex
+	order   *list.List // front is most recently used
+	entries map[K]*list.Element
+	stats   BoundedStats
+	now     func() time.Time
+}
+
+// NewBounded creates a memory-bounded cache and registers it for eviction
+// metrics when it has a name.
+//
+// Parameters:
+//
+//	config: Name, capacity and default TTL.
+//
+// Returns:
+//
+//	*Bounded[K, V]: Initialized cache.
+func NewBounded[K comparable, V any](config BoundedConfig) *Bounded[K, V] {
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = 10000
+	}
+	b := &Bounded[K, V]{
+		config:  config,
+		order:   list.New(),
+		entries: make(map[K]*list.Element),
+		stats:   BoundedStats{Name: config.Name, MaxEntries: config.MaxEntries},
+		now:     time.Now,
+	}
+	if config.Name != "" {
+		RegisterBoundedStats(b)
+	}
+	return b
+}
+
+// Get returns the live entry for key and marks it recently used.
+func (b *Bounded[K, V]) Get(key K) (V, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var zero V
+	element, ok := b.entries[key]
+	if !ok {
+		b.stats.Misses++
+		return zero, false
+	}
+	entry := element.Value.(*boundedEntry[K, V])
+	if b.expired(entry) {
+		b.remove(element)
+		b.stats.Expirations++
+		b.stats.Misses++
+		return zero, false
+	}
+	b.order.MoveToFront(element)
+	b.stats.Hits++
+	return entry.value, true
+}
+
+// Set stores value under key with the default TTL.
+func (b *Bounded[K, V]) Set(key K, value V) {
+	b.SetWithTTL(key, value, b.config.TTL)
+}
+
+// SetWithTTL stores value under key for ttl; zero or less never expires.
+// When the cache is full, expired entries go first, then the least
+// recently used one.
+func (b *Bounded[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = b.now().Add(ttl)
+	}
+	if element, ok := b.entries[key]; ok {
+		entry := element.Value.(*boundedEntry[K, V])
+		entry.value = value
+		entry.expiresAt = expiresAt
+		b.order.MoveToFront(element)
+		return
+	}
+
+	if b.order.Len() >= b.config.MaxEntries {
+		b.purgeExpired()
+	}
+	for b.order.Len() >= b.config.MaxEntries {
+		b.remove(b.order.Back())
+		b.stats.Evictions++
+	}
+	b.entries[key] = b.order.PushFront(&boundedEntry[K, V]{key: key, value: value, expiresAt: expiresAt})
+}
+
+// Delete removes key and reports whether it was present.
+func (b *Bounded[K, V]) Delete(key K) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	element, ok := b.entries[key]
+	if ok {
+		b.remove(element)
+	}
+	return ok
+}
+
+// Clear removes all entries without counting them as evictions.
+func (b *Bounded[K, V]) Clear() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.order.Init()
+	b.entries = make(map[K]*list.Element)
+}
+
+// Len returns the number of entries, including expired ones not yet purged.
+func (b *Bounded[K, V]) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.order.Len()
+}
+
+// PurgeExpired drops expired entries and returns how many were dropped.
+func (b *Bounded[K, V]) PurgeExpired() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.purgeExpired()
+}
+
+// Range calls fn for each live entry, most recently used first, until fn
+// returns false. It does not change the recency order.
+func (b *Bounded[K, V]) Range(fn func(key K, value V) bool) {
+	b.mu.Lock()
+	entries := make([]*boundedEntry[K, V], 0, b.order.Len())
+	for element := b.order.Front(); element != nil; element = element.Next() {
+		entry := element.Value.(*boundedEntry[K, V])
+		if !b.expired(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, entry := range entries {
+		if !fn(entry.key, entry.value) {
+			return
+		}
+	}
+}
+
+// BoundedStats returns the current size and eviction counters.
+func (b *Bounded[K, V]) BoundedStats() BoundedStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	stats := b.stats
+	stats.Entries = b.order.Len()
+	return stats
+}
+
+func (b *Bounded[K, V]) expired(entry *boundedEntry[K, V]) bool {
+	return !entry.expiresAt.IsZero() && b.now().After(entry.expiresAt)
+}
+
+func (b *Bounded[K, V]) remove(element *list.Element) {
+	entry := b.order.Remove(element).(*boundedEntry[K, V])
+	delete(b.entries, entry.key)
+}
+
+func (b *Bounded[K, V]) purgeExpired() int {
+	purged := 0
+	for element := b.order.Back(); element != nil; {
+		previous := element.Prev()
+		if b.expired(element.Value.(*boundedEntry[K, V])) {
+			b.remove(element)
+			b.stats.Expirations++
+			purged++
+		}
+		element = previous
+	}
+	return purged
+}
+
+var (
+	boundedRegistryMu sync.RWMutex
+	boundedRegistry   = map[string]BoundedStatsSource{}
+)
+
+// RegisterBoundedStats adds a source to the eviction metrics, replacing any
+// source registered under the same name.
+func RegisterBoundedStats(source BoundedStatsSource) {
+	name := source.BoundedStats().Name
+	if name == "" {
+		return
+	}
+	boundedRegistryMu.Lock()
+	defer boundedRegistryMu.Unlock()
+	boundedRegistry[name] = source
+}
+
+// AllBoundedStats returns the counters of every registered bounded
+// structure, ordered by name.
+func AllBoundedStats() []BoundedStats {
+	boundedRegistryMu.RLock()
+	sources := make([]BoundedStatsSource, 0, len(boundedRegistry))
+	for _, source := range boundedRegistry {
+		sources = append(sources, source)
+	}
+	boundedRegistryMu.RUnlock()
+
+	stats := make([]BoundedStats, 0, len(sources))
+	for _, source := range sources {
+		stats = append(stats, source.BoundedStats())
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBounded_EvictsLeastRecentlyUsed(t *testing.T) {
+	b := NewBounded[string, int](BoundedConfig{MaxEntries: 2})
+	b.Set("a", 1)
+	b.Set("b", 2)
+
+	// Touch "a" so "b" becomes the least recently used entry
+	_, ok := b.Get("a")
+	require.True(t, ok)
+	b.Set("c", 3)
+
+	_, ok = b.Get("b")
+	assert.False(t, ok)
+	value, ok := b.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+	assert.Equal(t, 2, b.Len())
+
+	stats := b.BoundedStats()
+	assert.Equal(t, int64(1), stats.Evictions)
+	assert.Equal(t, int64(2), stats.Hits)
+	assert.Equal(t, int64(1), stats.Misses)
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, 2, stats.MaxEntries)
+}
+
+func TestBounded_ExpiresEntries(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	b := NewBounded[string, int](BoundedConfig{MaxEntries: 2, TTL: time.Minute})
+	b.now = func() time.Time { return now }
+
+	b.Set("a", 1)
+	b.SetWithTTL("b", 2, 0)
+	now = now.Add(2 * time.Minute)
+
+	_, ok := b.Get("a")
+	assert.False(t, ok, "entry past its TTL should be gone")
+	_, ok = b.Get("b")
+	assert.True(t, ok, "entry without TTL should not expire")
+
+	// A full cache drops expired entries before evicting live ones
+	b.Set("c", 3)
+	b.SetWithTTL("d", 4, time.Second)
+	now = now.Add(2 * time.Second)
+	b.Set("e", 5)
+
+	stats := b.BoundedStats()
+	assert.Equal(t, int64(2), stats.Expirations)
+	assert.Equal(t, int64(1), stats.Evictions)
+	assert.Equal(t, 0, b.PurgeExpired())
+
+	var keys []string
+	b.Range(func(key string, _ int) bool {
+		keys = append(keys, key)
+		return true
+	})
+	assert.Equal(t, []string{"e", "c"}, keys)
+}
+
+func TestBounded_DeleteAndClear(t *testing.T) {
+	b := NewBounded[string, int](BoundedConfig{})
+	assert.Equal(t, 10000, b.BoundedStats().MaxEntries)
+
+	b.Set("a", 1)
+	b.Set("b", 2)
+	assert.True(t, b.Delete("a"))
+	assert.False(t, b.Delete("a"))
+	b.Clear()
+	assert.Equal(t, 0, b.Len())
+	assert.Equal(t, int64(0), b.BoundedStats().Evictions)
+}
+
+func TestAllBoundedStats_ListsNamedCaches(t *testing.T) {
+	NewBounded[string, int](BoundedConfig{Name: "test.zeta"})
+	named := NewBounded[string, int](BoundedConfig{Name: "test.alpha", MaxEntries: 1})
+	named.Set("a", 1)
+	named.Set("b", 2)
+
+	var names []string
+	var alpha BoundedStats
+	for _, stats := range AllBoundedStats() {
+		names = append(names, stats.Name)
+		if stats.Name == "test.alpha" {
+			alpha = stats
+		}
+	}
+	assert.Subset(t, names, []string{"test.alpha", "test.zeta"})
+	assert.IsIncreasing(t, names)
+	assert.Equal(t, int64(1), alpha.Evictions)
+}
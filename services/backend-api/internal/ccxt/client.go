@@ -18,6 +18,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
+	"github.com/irfndi/neuratrade/internal/chaos"
 	"github.com/irfndi/neuratrade/internal/config"
 	pb "github.com/irfndi/neuratrade/pkg/pb/ccxt"
 	"github.com/shopspring/decimal"
@@ -150,12 +151,24 @@ func NewClient(cfg *config.CCXTConfig) *Client {
 		adminAPIKey: cfg.AdminAPIKey,
 	}
 
-	if cfg.GrpcAddress != "" {
+	fixtureMode, err := ParseFixtureMode(cfg.FixtureMode)
+	if err != nil {
+		log.Printf("WARNING: %v, fixtures disabled", err)
+	}
+	if cfg.Synthetic.Enabled {
+		client.HTTPClient.Transport = NewSyntheticMarket(cfg.Synthetic)
+		log.Printf("CCXT client using synthetic market data for %v", cfg.Synthetic.Exchanges)
+	} else if fixtureMode != FixtureModeOff {
+		// gRPC calls cannot be recorded, so fixtures use the HTTP API only.
+		client.HTTPClient.Transport = NewFixtureTransport(cfg.FixtureDir, fixtureMode, nil)
+		log.Printf("CCXT client using fixtures in %s mode from %s", fixtureMode, cfg.FixtureDir)
+	} else if cfg.GrpcAddress != "" {
 		// Use insecure credentials for internal communication with connection timeout
 		// Use non-blocking dial to avoid startup delays when gRPC service is unavailable
 		conn, err := grpc.NewClient(
 			cfg.GrpcAddress,
 			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithUnaryInterceptor(chaos.UnaryClientInterceptor(chaos.Default(), chaos.TargetCCXT)),
 		)
 		if err != nil {
 			log.Printf("Failed to create CCXT gRPC client at %s: %v (HTTP fallback available)", cfg.GrpcAddress, err)
@@ -167,6 +180,9 @@ func NewClient(cfg *config.CCXTConfig) *Client {
 		}
 	}
 
+	// Stall requests while a ccxt chaos fault is injected
+	client.HTTPClient.Transport = chaos.NewTransport(chaos.Default(), chaos.TargetCCXT, client.HTTPClient.Transport)
+
 	log.Printf("DEBUG: CCXT Client initialized with BaseURL: %s, gRPC: %v", client.BaseURL(), client.grpcEnabled)
 	return client
 }
@@ -515,6 +531,81 @@ func (c *Client) GetAllFundingRates(ctx context.Context, exchange string) ([]Fun
 	return response.FundingRates, nil
 }
 
+// GetDerivatives retrieves the options and dated futures listed on an exchange,
+// with mark prices and implied volatility where the exchange reports them.
+func (c *Client) GetDerivatives(ctx context.Context, exchange string, query DerivativesQuery) (*DerivativesResponse, error) {
+	params := url.Values{}
+	if len(query.Types) > 0 {
+		params.Set("type", strings.Join(query.Types, ","))
+	}
+	if query.Underlying != "" {
+		params.Set("underlying", query.Underlying)
+	}
+	if query.Limit > 0 {
+		params.Set("limit", strconv.Itoa(query.Limit))
+	}
+	path := fmt.Sprintf("/api/derivatives/%s", exchange)
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+
+	var response DerivativesResponse
+	if err := c.makeRequest(ctx, "GET", path, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetLeverage retrieves the leverage and margin mode configured for a symbol.
+func (c *Client) GetLeverage(ctx context.Context, exchange, symbol string) (*MarginSettingsResponse, error) {
+	path := fmt.Sprintf("/api/leverage/%s?symbol=%s", exchange, url.QueryEscape(symbol))
+	var response MarginSettingsResponse
+	if err := c.makeRequest(ctx, "GET", path, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// SetLeverage applies leverage and optionally a margin mode to a symbol and
+// returns the settings read back from the exchange.
+func (c *Client) SetLeverage(ctx context.Context, exchange string, req SetLeverageRequest) (*MarginSettingsResponse, error) {
+	path := fmt.Sprintf("/api/leverage/%s", exchange)
+	var response MarginSettingsResponse
+	if err := c.makeRequest(ctx, "POST", path, req, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetPositions retrieves the open derivatives positions of the account,
+// optionally restricted to symbols.
+func (c *Client) GetPositions(ctx context.Context, exchange string, symbols []string) (*PositionsResponse, error) {
+	path := fmt.Sprintf("/api/positions/%s", exchange)
+	if len(symbols) > 0 {
+		path += "?symbols=" + url.QueryEscape(strings.Join(symbols, ","))
+	}
+	var response PositionsResponse
+	if err := c.makeRequest(ctx, "GET", path, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetPositioning retrieves open interest and long/short account ratios of
+// perpetual swaps; period is the ratio aggregation period, e.g. "5m".
+func (c *Client) GetPositioning(ctx context.Context, exchange string, symbols []string, period string) (*PositioningResponse, error) {
+	query := url.Values{}
+	query.Set("symbols", strings.Join(symbols, ","))
+	if period != "" {
+		query.Set("period", period)
+	}
+	var response PositioningResponse
+	if err := c.makeRequest(ctx, "GET", fmt.Sprintf("/api/positioning/%s?%s", exchange, query.Encode()), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
 // Exchange Management Methods
 
 // GetExchangeConfig retrieves the current exchange configuration.
@@ -643,7 +734,8 @@ func (c *Client) makeRequest(ctx context.Context, method, path string, body inte
 	req.Header.Set("User-Agent", "NeuraTrade/1.0")
 
 	// Add API key for admin endpoints
-	if (strings.Contains(path, "/admin/") || strings.Contains(path, "/balance/") || strings.Contains(path, "/order")) && c.adminAPIKey != "" {
+	if (strings.Contains(path, "/admin/") || strings.Contains(path, "/balance/") || strings.Contains(path, "/order") ||
+		strings.Contains(path, "/leverage/") || strings.Contains(path, "/positions/")) && c.adminAPIKey != "" {
 		req.Header.Set("X-API-Key", c.adminAPIKey)
 	}
 
@@ -676,6 +768,13 @@ func (c *Client) makeRequest(ctx context.Context, method, path string, body inte
 		// Extract operation from path (e.g., "funding-rate", "ticker", "orderbook")
 		operation := c.extractOperationFromPath(path)
 
+		// Known exchange errors (margin, bans, nonces...) carry a remediation hint
+		if resp.StatusCode != http.StatusNotFound && resp.StatusCode != http.StatusNotImplemented {
+			if exchangeErr := MapExchangeError(exchange, errorMsg); exchangeErr != nil {
+				return exchangeErr
+			}
+		}
+
 		// Return typed errors based on HTTP status code
 		switch resp.StatusCode {
 		case http.StatusNotFound:
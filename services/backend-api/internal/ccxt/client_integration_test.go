@@ -565,6 +565,93 @@ func TestClient_GetAllFundingRates(t *testing.T) {
 	assert.Equal(t, "BTC/USDT", resp[0].Symbol)
 }
 
+func TestClient_GetDerivatives(t *testing.T) {
+	server := newTestServerOrSkip(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/derivatives/deribit", r.URL.Path)
+		assert.Equal(t, "option,future", r.URL.Query().Get("type"))
+		assert.Equal(t, "BTC", r.URL.Query().Get("underlying"))
+		assert.Equal(t, "50", r.URL.Query().Get("limit"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"exchange":"deribit","count":1,"instruments":[{"symbol":"BTC/USD:BTC-261225-100000-C","type":"option","base":"BTC","quote":"USD","expiry":1798185600000,"strike":100000,"optionType":"call","markPrice":0.05,"markIV":0.52,"delta":0.31}]}`))
+	}))
+	defer server.Close()
+
+	client := ccxt.NewClient(&config.CCXTConfig{ServiceURL: server.URL, Timeout: 30})
+	resp, err := client.GetDerivatives(context.Background(), "deribit", ccxt.DerivativesQuery{
+		Types:      []string{"option", "future"},
+		Underlying: "BTC",
+		Limit:      50,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Instruments, 1)
+	instrument := resp.Instruments[0]
+	assert.Equal(t, "option", instrument.Type)
+	assert.Equal(t, 0.52, instrument.MarkIV)
+	assert.Equal(t, 0.31, instrument.Delta)
+	assert.Equal(t, int64(1798185600000), instrument.Expiry.Time().UnixMilli())
+}
+
+func TestClient_LeverageAndPositions(t *testing.T) {
+	server := newTestServerOrSkip(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "admin-key", r.Header.Get("X-API-Key"))
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/leverage/binance":
+			assert.Equal(t, "BTC/USDT:USDT", r.URL.Query().Get("symbol"))
+			_, _ = w.Write([]byte(`{"exchange":"binance","settings":{"symbol":"BTC/USDT:USDT","marginMode":"cross","longLeverage":10,"shortLeverage":10}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/leverage/binance":
+			var req ccxt.SetLeverageRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, 3.0, req.Leverage)
+			assert.Equal(t, "isolated", req.MarginMode)
+			_, _ = w.Write([]byte(`{"exchange":"binance","settings":{"symbol":"BTC/USDT:USDT","marginMode":"isolated","longLeverage":3,"shortLeverage":3}}`))
+		case r.URL.Path == "/api/positions/binance":
+			assert.Equal(t, "BTC/USDT:USDT", r.URL.Query().Get("symbols"))
+			_, _ = w.Write([]byte(`{"exchange":"binance","positions":[{"symbol":"BTC/USDT:USDT","side":"long","leverage":3,"marginMode":"isolated","marginRatio":0.05}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := ccxt.NewClient(&config.CCXTConfig{ServiceURL: server.URL, Timeout: 30, AdminAPIKey: "admin-key"})
+	ctx := context.Background()
+
+	current, err := client.GetLeverage(ctx, "binance", "BTC/USDT:USDT")
+	require.NoError(t, err)
+	assert.Equal(t, 10.0, current.Settings.LongLeverage)
+	assert.Equal(t, "cross", current.Settings.MarginMode)
+
+	updated, err := client.SetLeverage(ctx, "binance", ccxt.SetLeverageRequest{Symbol: "BTC/USDT:USDT", Leverage: 3, MarginMode: "isolated"})
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, updated.Settings.ShortLeverage)
+
+	positions, err := client.GetPositions(ctx, "binance", []string{"BTC/USDT:USDT"})
+	require.NoError(t, err)
+	require.Len(t, positions.Positions, 1)
+	assert.Equal(t, 0.05, positions.Positions[0].MarginRatio)
+}
+
+func TestClient_GetPositioning(t *testing.T) {
+	server := newTestServerOrSkip(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/positioning/binance", r.URL.Path)
+		assert.Equal(t, "BTC/USDT:USDT,ETH/USDT:USDT", r.URL.Query().Get("symbols"))
+		assert.Equal(t, "1h", r.URL.Query().Get("period"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"exchange":"binance","period":"1h","positioning":[{"symbol":"BTC/USDT:USDT","openInterestValue":4000000000,"longShortRatio":1.5,"longAccount":0.6,"shortAccount":0.4,"timestamp":1700000000000}],"count":1}`))
+	}))
+	defer server.Close()
+
+	client := ccxt.NewClient(&config.CCXTConfig{ServiceURL: server.URL, Timeout: 30})
+	resp, err := client.GetPositioning(context.Background(), "binance", []string{"BTC/USDT:USDT", "ETH/USDT:USDT"}, "1h")
+	require.NoError(t, err)
+	require.Len(t, resp.Positioning, 1)
+	assert.Equal(t, 1.5, resp.Positioning[0].LongShortRatio)
+	assert.Equal(t, int64(1700000000), resp.Positioning[0].Timestamp.Time().Unix())
+}
+
 func TestClient_GetExchangeConfig(t *testing.T) {
 	expectedConfig := ccxt.ExchangeConfigResponse{
 		ActiveExchanges:    []string{"binance", "coinbase"},
@@ -0,0 +1,223 @@
+package ccxt
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ExchangeErrorKind classifies an exchange error by what the operator has to do about it.
+type ExchangeErrorKind string
+
+const (
+	// ExchangeErrorInsufficientMargin means the margin account cannot carry the order.
+	ExchangeErrorInsufficientMargin ExchangeErrorKind = "insufficient_margin"
+	// ExchangeErrorInsufficientBalance means the spot wallet cannot pay for the order.
+	ExchangeErrorInsufficientBalance ExchangeErrorKind = "insufficient_balance"
+	// ExchangeErrorRateLimited means requests are being throttled.
+	ExchangeErrorRateLimited ExchangeErrorKind = "rate_limited"
+	// ExchangeErrorIPBanned means the exchange has banned the host's IP address.
+	ExchangeErrorIPBanned ExchangeErrorKind = "ip_banned"
+	// ExchangeErrorInvalidNonce means the request timestamp or nonce was rejected.
+	ExchangeErrorInvalidNonce ExchangeErrorKind = "invalid_nonce"
+	// ExchangeErrorAuthentication means the API key, signature or permissions were rejected.
+	ExchangeErrorAuthentication ExchangeErrorKind = "authentication"
+	// ExchangeErrorInvalidOrder means the order violates the market's trading rules.
+	ExchangeErrorInvalidOrder ExchangeErrorKind = "invalid_order"
+	// ExchangeErrorBadSymbol means the symbol is not tradable on the exchange.
+	ExchangeErrorBadSymbol ExchangeErrorKind = "bad_symbol"
+)
+
+// Label returns the kind in words, e.g. "insufficient margin".
+func (k ExchangeErrorKind) Label() string {
+	return strings.ReplaceAll(string(k), "_", " ")
+}
+
+// exchangeErrorHints are the default remediation hints per kind.
+var exchangeErrorHints = map[ExchangeErrorKind]string{
+	ExchangeErrorInsufficientMargin:  "add margin to the derivatives wallet or reduce the position size or leverage",
+	ExchangeErrorInsufficientBalance: "top up the quote currency or lower the trade size",
+	ExchangeErrorRateLimited:         "slow down: raise the collection interval or reduce concurrent requests to this exchange",
+	ExchangeErrorIPBanned:            "stop sending requests until the ban expires, then lower the request rate",
+	ExchangeErrorInvalidNonce:        "sync the host clock with NTP; the exchange rejects requests whose timestamp drifts",
+	ExchangeErrorAuthentication:      "check the API key and secret, the key's IP whitelist and that trading permission is enabled",
+	ExchangeErrorInvalidOrder:        "adjust the amount and price to the market's lot size, tick size and minimum notional",
+	ExchangeErrorBadSymbol:           "the symbol is not tradable here; remove it from the watchlist or check the market list",
+}
+
+// exchangeErrorRule maps exchange error codes or message fragments to a kind.
+// Rules are evaluated in order; the first match wins.
+type exchangeErrorRule struct {
+	// exchange restricts the rule to one exchange; empty matches any.
+	exchange string
+	codes    []string
+	// patterns are lower-case message fragments.
+	patterns []string
+	kind     ExchangeErrorKind
+	// hint overrides the kind's default hint.
+	hint string
+}
+
+var exchangeErrorRules = []exchangeErrorRule{
+	// Binance
+	{exchange: "binance", codes: []string{"-2019"}, kind: ExchangeErrorInsufficientMargin},
+	{exchange: "binance", patterns: []string{"insufficient balance"}, kind: ExchangeErrorInsufficientBalance},
+	{exchange: "binance", patterns: []string{"ip banned", "banned until"}, kind: ExchangeErrorIPBanned,
+		hint: "Binance banned the IP for ignoring 429s; wait for the time in the message and lower the request weight"},
+	{exchange: "binance", codes: []string{"-1003", "-1015"}, kind: ExchangeErrorRateLimited},
+	{exchange: "binance", codes: []string{"-1021"}, kind: ExchangeErrorInvalidNonce,
+		hint: "sync the host clock with NTP; Binance rejects requests outside recvWindow (5s by default)"},
+	{exchange: "binance", codes: []string{"-1022", "-2014", "-2015"}, kind: ExchangeErrorAuthentication},
+	{exchange: "binance", codes: []string{"-1013", "-1111", "-1100", "-4164", "-2010"}, kind: ExchangeErrorInvalidOrder},
+	{exchange: "binance", codes: []string{"-1121"}, kind: ExchangeErrorBadSymbol},
+
+	// Bybit
+	{exchange: "bybit", codes: []string{"110004", "110007", "110012"}, kind: ExchangeErrorInsufficientBalance},
+	{exchange: "bybit", codes: []string{"110044", "110045"}, kind: ExchangeErrorInsufficientMargin},
+	{exchange: "bybit", codes: []string{"10006", "10018"}, kind: ExchangeErrorRateLimited},
+	{exchange: "bybit", codes: []string{"10002"}, kind: ExchangeErrorInvalidNonce},
+	{exchange: "bybit", codes: []string{"10010"}, kind: ExchangeErrorAuthentication,
+		hint: "add this host's IP address to the Bybit API key whitelist"},
+	{exchange: "bybit", codes: []string{"10003", "10004", "10005"}, kind: ExchangeErrorAuthentication},
+	{exchange: "bybit", codes: []string{"10001", "110017"}, kind: ExchangeErrorInvalidOrder},
+
+	// OKX
+	{exchange: "okx", codes: []string{"51008"}, patterns: []string{"insufficient margin"}, kind: ExchangeErrorInsufficientMargin},
+	{exchange: "okx", codes: []string{"51008", "51131"}, kind: ExchangeErrorInsufficientBalance},
+	{exchange: "okx", codes: []string{"50011", "50061"}, kind: ExchangeErrorRateLimited},
+	{exchange: "okx", codes: []string{"50102", "50112"}, kind: ExchangeErrorInvalidNonce},
+	{exchange: "okx", codes: []string{"50105", "50111", "50113", "50110"}, kind: ExchangeErrorAuthentication},
+	{exchange: "okx", codes: []string{"51020", "51121", "51201"}, kind: ExchangeErrorInvalidOrder},
+	{exchange: "okx", codes: []string{"51001"}, kind: ExchangeErrorBadSymbol},
+
+	// Kraken reports errors as "ECategory:Message" strings
+	{exchange: "kraken", patterns: []string{"eorder:insufficient funds"}, kind: ExchangeErrorInsufficientBalance},
+	{exchange: "kraken", patterns: []string{"eorder:insufficient margin"}, kind: ExchangeErrorInsufficientMargin},
+	{exchange: "kraken", patterns: []string{"eapi:invalid nonce"}, kind: ExchangeErrorInvalidNonce,
+		hint: "use a dedicated API key per process and raise its nonce window; Kraken nonces must always increase"},
+	{exchange: "kraken", patterns: []string{"egeneral:temporary lockout"}, kind: ExchangeErrorIPBanned},
+	{exchange: "kraken", patterns: []string{"rate limit exceeded"}, kind: ExchangeErrorRateLimited},
+	{exchange: "kraken", patterns: []string{"eapi:invalid key", "eapi:invalid signature", "egeneral:permission denied"}, kind: ExchangeErrorAuthentication},
+	{exchange: "kraken", patterns: []string{"eorder:order minimum not met", "egeneral:invalid arguments"}, kind: ExchangeErrorInvalidOrder},
+	{exchange: "kraken", patterns: []string{"equery:unknown asset pair"}, kind: ExchangeErrorBadSymbol},
+
+	// Wording shared by many exchanges
+	{patterns: []string{"insufficient margin", "margin is insufficient"}, kind: ExchangeErrorInsufficientMargin},
+	{patterns: []string{"insufficient balance", "insufficient funds", "not enough balance"}, kind: ExchangeErrorInsufficientBalance},
+	{patterns: []string{"ip banned", "ip has been banned", "ip is banned"}, kind: ExchangeErrorIPBanned},
+	{patterns: []string{"too many requests", "rate limit"}, kind: ExchangeErrorRateLimited},
+	{patterns: []string{"invalid nonce", "nonce is too small", "recvwindow", "timestamp for this request"}, kind: ExchangeErrorInvalidNonce},
+	{patterns: []string{"invalid api", "api key", "api-key", "invalid signature", "permission denied"}, kind: ExchangeErrorAuthentication},
+	{patterns: []string{"min notional", "min_notional", "lot_size", "minimum amount", "precision"}, kind: ExchangeErrorInvalidOrder},
+}
+
+// exchangeErrorCodePattern extracts the codes from JSON error payloads such as
+// {"code":-2019,"msg":"..."} (Binance), {"retCode":10006,...} (Bybit) or
+// {"code":"1","data":[{"sCode":"51008",...}]} (OKX).
+var exchangeErrorCodePattern = regexp.MustCompile(`"(?:code|retCode|sCode)"\s*:\s*"?(-?\d+)"?`)
+
+// ExchangeError is an exchange error mapped to a known kind with a
+// remediation hint for operators.
+type ExchangeError struct {
+	Exchange string
+	Kind     ExchangeErrorKind
+	// Code is the exchange's own error code, if the message carried one.
+	Code    string
+	Message string
+	Hint    string
+}
+
+func (e *ExchangeError) Error() string {
+	code := ""
+	if e.Code != "" {
+		code = " " + e.Code
+	}
+	return fmt.Sprintf("%s %s%s: %s (hint: %s)", e.Exchange, e.Kind.Label(), code, e.Message, e.Hint)
+}
+
+// MapExchangeError translates a raw exchange error message into an
+// ExchangeError.
+//
+// Parameters:
+//
+//	exchange: The exchange that returned the error.
+//	message: The error message as relayed by the CCXT service.
+//
+// Returns:
+//
+//	*ExchangeError: The mapped error, or nil if the message is not recognised.
+func MapExchangeError(exchange, message string) *ExchangeError {
+	if message == "" {
+		return nil
+	}
+	exchange = strings.ToLower(exchange)
+	lower := strings.ToLower(message)
+	var codes []string
+	for _, match := range exchangeErrorCodePattern.FindAllStringSubmatch(message, -1) {
+		codes = append(codes, match[1])
+	}
+
+	for _, rule := range exchangeErrorRules {
+		if rule.exchange != "" && rule.exchange != exchange {
+			continue
+		}
+		code, ok := rule.matches(codes, lower)
+		if !ok {
+			continue
+		}
+		hint := rule.hint
+		if hint == "" {
+			hint = exchangeErrorHints[rule.kind]
+		}
+		return &ExchangeError{Exchange: exchange, Kind: rule.kind, Code: code, Message: message, Hint: hint}
+	}
+	return nil
+}
+
+// matches reports whether the rule applies, and the code it matched on. Rules
+// without codes report the message's first code.
+func (r exchangeErrorRule) matches(codes []string, lowerMessage string) (string, bool) {
+	code := ""
+	if len(codes) > 0 {
+		code = codes[0]
+	}
+	if len(r.codes) > 0 {
+		code = ""
+		for _, c := range codes {
+			if slices.Contains(r.codes, c) {
+				code = c
+				break
+			}
+		}
+		if code == "" {
+			return "", false
+		}
+	}
+	if len(r.patterns) == 0 {
+		return code, true
+	}
+	for _, pattern := range r.patterns {
+		if strings.Contains(lowerMessage, pattern) {
+			return code, true
+		}
+	}
+	return "", false
+}
+
+// AsExchangeError returns the mapped exchange error wrapped in err, if any.
+// Uses errors.As to correctly handle wrapped errors.
+func AsExchangeError(err error) (*ExchangeError, bool) {
+	var exchangeErr *ExchangeError
+	if err == nil || !errors.As(err, &exchangeErr) {
+		return nil, false
+	}
+	return exchangeErr, true
+}
+
+// IsExchangeErrorKind returns true if err wraps an exchange error of the given kind.
+func IsExchangeErrorKind(err error, kind ExchangeErrorKind) bool {
+	exchangeErr, ok := AsExchangeError(err)
+	return ok && exchangeErr.Kind == kind
+}
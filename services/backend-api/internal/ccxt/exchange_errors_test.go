@@ -0,0 +1,72 @@
+package ccxt_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapExchangeError(t *testing.T) {
+	tests := []struct {
+		name     string
+		exchange string
+		message  string
+		kind     ccxt.ExchangeErrorKind
+		code     string
+	}{
+		{"binance margin", "binance", `binance {"code":-2019,"msg":"Margin is insufficient."}`, ccxt.ExchangeErrorInsufficientMargin, "-2019"},
+		{"binance balance", "Binance", `binance {"code":-2010,"msg":"Account has insufficient balance for requested action."}`, ccxt.ExchangeErrorInsufficientBalance, "-2010"},
+		{"binance filter", "binance", `binance {"code":-1013,"msg":"Filter failure: LOT_SIZE"}`, ccxt.ExchangeErrorInvalidOrder, "-1013"},
+		{"binance ip ban", "binance", `binance {"code":-1003,"msg":"Way too many requests; IP(1.2.3.4) banned until 1700000000000."}`, ccxt.ExchangeErrorIPBanned, "-1003"},
+		{"binance clock", "binance", `binance {"code":-1021,"msg":"Timestamp for this request is outside of the recvWindow."}`, ccxt.ExchangeErrorInvalidNonce, "-1021"},
+		{"okx nested code", "okx", `okx {"code":"1","data":[{"sCode":"51008","sMsg":"Order failed. Insufficient USDT balance in account."}]}`, ccxt.ExchangeErrorInsufficientBalance, "51008"},
+		{"okx margin", "okx", `okx {"code":"1","data":[{"sCode":"51008","sMsg":"Order failed. Insufficient margin."}]}`, ccxt.ExchangeErrorInsufficientMargin, "51008"},
+		{"bybit whitelist", "bybit", `bybit {"retCode":10010,"retMsg":"Unmatched IP, please check your API key's bound IP addresses."}`, ccxt.ExchangeErrorAuthentication, "10010"},
+		{"kraken nonce", "kraken", `kraken {"error":["EAPI:Invalid nonce"]}`, ccxt.ExchangeErrorInvalidNonce, ""},
+		{"generic funds", "coinbase", "coinbase Insufficient funds", ccxt.ExchangeErrorInsufficientBalance, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapped := ccxt.MapExchangeError(tt.exchange, tt.message)
+			require.NotNil(t, mapped)
+			assert.Equal(t, tt.kind, mapped.Kind)
+			assert.Equal(t, tt.code, mapped.Code)
+			assert.NotEmpty(t, mapped.Hint)
+		})
+	}
+
+	assert.Nil(t, ccxt.MapExchangeError("binance", "connection reset by peer"))
+	assert.Nil(t, ccxt.MapExchangeError("binance", ""))
+	assert.Contains(t, ccxt.MapExchangeError("bybit", `{"retCode":10010}`).Hint, "whitelist", "rules can override the kind's hint")
+}
+
+func TestExchangeError_Wrapped(t *testing.T) {
+	mapped := ccxt.MapExchangeError("binance", `binance {"code":-2019,"msg":"Margin is insufficient."}`)
+	assert.Equal(t, `binance insufficient margin -2019: binance {"code":-2019,"msg":"Margin is insufficient."} (hint: add margin to the derivatives wallet or reduce the position size or leverage)`, mapped.Error())
+
+	err := fmt.Errorf("failed to place buy order: %w", mapped)
+	assert.True(t, ccxt.IsExchangeErrorKind(err, ccxt.ExchangeErrorInsufficientMargin))
+	assert.False(t, ccxt.IsExchangeErrorKind(err, ccxt.ExchangeErrorRateLimited))
+	_, ok := ccxt.AsExchangeError(fmt.Errorf("plain"))
+	assert.False(t, ok)
+}
+
+func TestClient_MapsExchangeErrors(t *testing.T) {
+	server := newTestServerOrSkip(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"error":"binance {\"code\":-1003,\"msg\":\"Too many requests.\"}"}`))
+	}))
+	defer server.Close()
+
+	client := ccxt.NewClient(&config.CCXTConfig{ServiceURL: server.URL, Timeout: 30})
+	_, err := client.GetTicker(context.Background(), "binance", "BTC/USDT")
+	require.Error(t, err)
+	assert.True(t, ccxt.IsExchangeErrorKind(err, ccxt.ExchangeErrorRateLimited))
+}
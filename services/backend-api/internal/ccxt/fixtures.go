@@ -0,0 +1,247 @@
+package ccxt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FixtureMode selects how a FixtureTransport treats CCXT service requests.
+type FixtureMode string
+
+const (
+	// FixtureModeOff sends requests to the CCXT service unchanged.
+	FixtureModeOff FixtureMode = ""
+	// FixtureModeRecord sends requests to the CCXT service and saves every
+	// response as a fixture file.
+	FixtureModeRecord FixtureMode = "record"
+	// FixtureModeReplay answers requests from fixture files without any
+	// network access.
+	FixtureModeReplay FixtureMode = "replay"
+)
+
+// ErrFixtureNotFound is returned in replay mode when no fixture matches a request.
+var ErrFixtureNotFound = errors.New("ccxt fixture not found")
+
+var fixtureNameSanitizer = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// Fixture is a recorded CCXT service response. Requests are matched on
+// method, path, query and a hash of the body; the body itself and request
+// headers are not stored, so credentials never end up in fixture files.
+type Fixture struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	BodySHA256  string `json:"body_sha256,omitempty"`
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type,omitempty"`
+	// Response holds JSON responses; other bodies are kept in ResponseText.
+	Response     json.RawMessage `json:"response,omitempty"`
+	ResponseText string          `json:"response_text,omitempty"`
+	RecordedAt   time.Time       `json:"recorded_at"`
+}
+
+// ParseFixtureMode validates a configured fixture mode.
+//
+// Parameters:
+//
+//	raw: The configured mode ("", "off", "record" or "replay").
+//
+// Returns:
+//
+//	FixtureMode: The parsed mode.
+//	error: Error if the mode is unknown.
+func ParseFixtureMode(raw string) (FixtureMode, error) {
+	switch mode := FixtureMode(strings.ToLower(strings.TrimSpace(raw))); mode {
+	case FixtureModeOff, "off":
+		return FixtureModeOff, nil
+	case FixtureModeRecord, FixtureModeReplay:
+		return mode, nil
+	default:
+		return FixtureModeOff, fmt.Errorf("unknown ccxt fixture mode %q", raw)
+	}
+}
+
+// FixtureTransport is an http.RoundTripper that records CCXT service
+// responses to fixture files or replays them, so tests and the offline demo
+// run without exchange connectivity.
+type FixtureTransport struct {
+	dir  string
+	mode FixtureMode
+	next http.RoundTripper
+	now  func() time.Time
+	mu   sync.Mutex
+}
+
+// NewFixtureTransport creates a record/replay transport.
+//
+// Parameters:
+//
+//	dir: Directory holding the fixture files.
+//	mode: FixtureModeRecord or FixtureModeReplay.
+//	next: Transport used to reach the CCXT service when recording (nil uses http.DefaultTransport).
+//
+// Returns:
+//
+//	*FixtureTransport: Initialized transport.
+func NewFixtureTransport(dir string, mode FixtureMode, next http.RoundTripper) *FixtureTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &FixtureTransport{dir: dir, mode: mode, next: next, now: time.Now}
+}
+
+// RoundTrip records or replays a request depending on the mode.
+func (t *FixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	path := FixtureFile(req.Method, req.URL.RequestURI(), body)
+
+	switch t.mode {
+	case FixtureModeReplay:
+		return t.replay(req, filepath.Join(t.dir, path))
+	case FixtureModeRecord:
+		return t.record(req, body, filepath.Join(t.dir, path))
+	default:
+		return t.next.RoundTrip(req)
+	}
+}
+
+func (t *FixtureTransport) replay(req *http.Request, path string) (*http.Response, error) {
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s %s (%s)", ErrFixtureNotFound, req.Method, req.URL.RequestURI(), filepath.Base(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ccxt fixture: %w", err)
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(raw, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to parse ccxt fixture %s: %w", filepath.Base(path), err)
+	}
+	return fixture.response(req), nil
+}
+
+func (t *FixtureTransport) record(req *http.Request, body []byte, path string) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ccxt response: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	fixture := Fixture{
+		Method:      req.Method,
+		Path:        req.URL.RequestURI(),
+		BodySHA256:  bodyHash(body),
+		StatusCode:  resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		RecordedAt:  t.now().UTC(),
+	}
+	if json.Valid(respBody) {
+		fixture.Response = respBody
+	} else {
+		fixture.ResponseText = string(respBody)
+	}
+	if err := t.write(path, fixture); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (t *FixtureTransport) write(path string, fixture Fixture) error {
+	raw, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode ccxt fixture: %w", err)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create ccxt fixture directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(raw, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write ccxt fixture: %w", err)
+	}
+	return nil
+}
+
+func (f Fixture) response(req *http.Request) *http.Response {
+	body := []byte(f.Response)
+	if len(body) == 0 {
+		body = []byte(f.ResponseText)
+	}
+	header := make(http.Header)
+	if f.ContentType != "" {
+		header.Set("Content-Type", f.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.StatusCode, http.StatusText(f.StatusCode)),
+		StatusCode:    f.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// FixtureFile returns the fixture file name for a request. The name is
+// readable from the method and path, with a hash that separates requests
+// differing only in query or body.
+//
+// Parameters:
+//
+//	method: HTTP method.
+//	requestURI: Path and query of the request.
+//	body: Request body (may be nil).
+//
+// Returns:
+//
+//	string: File name relative to the fixture directory.
+func FixtureFile(method, requestURI string, body []byte) string {
+	sum := sha256.Sum256([]byte(method + " " + requestURI + "\n" + bodyHash(body)))
+	name := strings.Trim(fixtureNameSanitizer.ReplaceAllString(strings.ToLower(requestURI), "_"), "_")
+	if len(name) > 80 {
+		name = name[:80]
+	}
+	return fmt.Sprintf("%s_%s_%s.json", strings.ToLower(method), name, hex.EncodeToString(sum[:4]))
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func bodyHash(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,194 @@
+package ccxt_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const bundledFixtureDir = "../../testdata/ccxt-fixtures"
+
+func fixtureClient(dir string, mode ccxt.FixtureMode, serviceURL string) *ccxt.Client {
+	return ccxt.NewClient(&config.CCXTConfig{
+		ServiceURL:  serviceURL,
+		Timeout:     5,
+		AdminAPIKey: "super-secret-admin-key",
+		FixtureMode: string(mode),
+		FixtureDir:  dir,
+	})
+}
+
+func TestParseFixtureMode(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    ccxt.FixtureMode
+		wantErr bool
+	}{
+		{raw: "", want: ccxt.FixtureModeOff},
+		{raw: "off", want: ccxt.FixtureModeOff},
+		{raw: " Record ", want: ccxt.FixtureModeRecord},
+		{raw: "replay", want: ccxt.FixtureModeReplay},
+		{raw: "playback", want: ccxt.FixtureModeOff, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := ccxt.ParseFixtureMode(tt.raw)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestFixtureTransport_RecordThenReplay(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/ticker/binance/BTCUSDT":
+			_ = json.NewEncoder(w).Encode(ccxt.TickerResponse{Exchange: "binance", Symbol: "BTC/USDT", Timestamp: "2026-01-05T12:00:00Z"})
+		case "/api/tickers":
+			var req ccxt.TickersRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			tickers := make([]ccxt.TickerData, 0, len(req.Symbols))
+			for _, symbol := range req.Symbols {
+				tickers = append(tickers, ccxt.TickerData{Exchange: "binance", Ticker: ccxt.Ticker{Symbol: symbol}})
+			}
+			_ = json.NewEncoder(w).Encode(ccxt.TickersResponse{Tickers: tickers})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	dir := t.TempDir()
+	ctx := context.Background()
+	recorder := fixtureClient(dir, ccxt.FixtureModeRecord, server.URL)
+	_, err := recorder.GetTicker(ctx, "binance", "BTC/USDT")
+	require.NoError(t, err)
+	_, err = recorder.GetTickers(ctx, &ccxt.TickersRequest{Symbols: []string{"BTC/USDT"}})
+	require.NoError(t, err)
+	_, err = recorder.GetTickers(ctx, &ccxt.TickersRequest{Symbols: []string{"ETH/USDT", "SOL/USDT"}})
+	require.NoError(t, err)
+	server.Close()
+	assert.Equal(t, 3, calls)
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 3, "requests differing only in body get their own fixture")
+
+	replayer := fixtureClient(dir, ccxt.FixtureModeReplay, server.URL)
+	ticker, err := replayer.GetTicker(ctx, "binance", "BTC/USDT")
+	require.NoError(t, err)
+	assert.Equal(t, "BTC/USDT", ticker.Symbol)
+
+	tickers, err := replayer.GetTickers(ctx, &ccxt.TickersRequest{Symbols: []string{"ETH/USDT", "SOL/USDT"}})
+	require.NoError(t, err)
+	require.Len(t, tickers.Tickers, 2)
+	assert.Equal(t, "SOL/USDT", tickers.Tickers[1].Ticker.Symbol)
+	assert.Equal(t, 3, calls, "replay must not reach the service")
+}
+
+func TestFixtureTransport_ReplayMiss(t *testing.T) {
+	client := fixtureClient(t.TempDir(), ccxt.FixtureModeReplay, "http://127.0.0.1:1")
+
+	_, err := client.GetTicker(context.Background(), "kraken", "BTC/USD")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ccxt.ErrFixtureNotFound)
+}
+
+func TestFixtureTransport_DoesNotStoreCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "super-secret-admin-key", r.Header.Get("X-API-Key"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"success":true,"message":"blacklisted"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	_, err := fixtureClient(dir, ccxt.FixtureModeRecord, server.URL).AddExchangeToBlacklist(context.Background(), "ftx")
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	raw, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "super-secret-admin-key")
+
+	var fixture ccxt.Fixture
+	require.NoError(t, json.Unmarshal(raw, &fixture))
+	assert.Equal(t, http.MethodPost, fixture.Method)
+	assert.Equal(t, http.StatusOK, fixture.StatusCode)
+	assert.JSONEq(t, `{"success":true,"message":"blacklisted"}`, string(fixture.Response))
+}
+
+func TestFixtureTransport_ReplaysNonJSONAndErrors(t *testing.T) {
+	dir := t.TempDir()
+	name := ccxt.FixtureFile(http.MethodGet, "/api/markets/offline", nil)
+	raw, err := json.Marshal(ccxt.Fixture{
+		Method:       http.MethodGet,
+		Path:         "/api/markets/offline",
+		StatusCode:   http.StatusServiceUnavailable,
+		ContentType:  "text/plain",
+		ResponseText: "exchange offline",
+		RecordedAt:   time.Now(),
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), raw, 0o600))
+
+	_, err = fixtureClient(dir, ccxt.FixtureModeReplay, "http://127.0.0.1:1").GetMarkets(context.Background(), "offline")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ccxt.ErrFixtureNotFound)
+	assert.Contains(t, err.Error(), "exchange offline")
+}
+
+func TestFixtureFile(t *testing.T) {
+	a := ccxt.FixtureFile(http.MethodGet, "/api/ohlcv/binance/BTCUSDT?timeframe=1h&limit=5", nil)
+	b := ccxt.FixtureFile(http.MethodGet, "/api/ohlcv/binance/BTCUSDT?timeframe=1m&limit=5", nil)
+
+	assert.True(t, strings.HasPrefix(a, "get_api_ohlcv_binance_btcusdt_"))
+	assert.True(t, strings.HasSuffix(a, ".json"))
+	assert.NotEqual(t, a, b)
+	assert.Equal(t, a, ccxt.FixtureFile(http.MethodGet, "/api/ohlcv/binance/BTCUSDT?timeframe=1h&limit=5", nil))
+	assert.NotEqual(t,
+		ccxt.FixtureFile(http.MethodPost, "/api/tickers", []byte(`{"symbols":["BTC/USDT"]}`)),
+		ccxt.FixtureFile(http.MethodPost, "/api/tickers", []byte(`{"symbols":["ETH/USDT"]}`)))
+	assert.LessOrEqual(t, len(ccxt.FixtureFile(http.MethodGet, "/"+strings.Repeat("x", 300), nil)), 100)
+}
+
+func TestNewClient_ReplaysBundledFixtures(t *testing.T) {
+	client := fixtureClient(bundledFixtureDir, ccxt.FixtureModeReplay, "http://127.0.0.1:1")
+	ctx := context.Background()
+
+	health, err := client.HealthCheck(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", health.Status)
+
+	exchanges, err := client.GetExchanges(ctx)
+	require.NoError(t, err)
+	assert.NotEmpty(t, exchanges.Exchanges)
+
+	ticker, err := client.GetTicker(ctx, "binance", "BTC/USDT")
+	require.NoError(t, err)
+	assert.True(t, ticker.Ticker.Last.IsPositive())
+
+	orderBook, err := client.GetOrderBook(ctx, "binance", "BTC/USDT", 20)
+	require.NoError(t, err)
+	assert.NotEmpty(t, orderBook.OrderBook.Bids)
+	assert.NotEmpty(t, orderBook.OrderBook.Asks)
+
+	ohlcv, err := client.GetOHLCV(ctx, "binance", "BTC/USDT", "1h", 5)
+	require.NoError(t, err)
+	assert.Len(t, ohlcv.OHLCV, 5)
+}
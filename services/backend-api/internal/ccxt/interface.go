@@ -168,6 +168,22 @@ type CCXTClient interface {
 	// GetAllFundingRates gets all funding rates.
 	GetAllFundingRates(ctx context.Context, exchange string) ([]FundingRate, error)
 
+	// Derivatives operations
+
+	// GetDerivatives gets options and dated futures with mark and IV data.
+	GetDerivatives(ctx context.Context, exchange string, query DerivativesQuery) (*DerivativesResponse, error)
+
+	// Margin operations
+
+	// GetLeverage gets the leverage and margin mode of a symbol.
+	GetLeverage(ctx context.Context, exchange, symbol string) (*MarginSettingsResponse, error)
+	// SetLeverage sets the leverage and margin mode of a symbol.
+	SetLeverage(ctx context.Context, exchange string, req SetLeverageRequest) (*MarginSettingsResponse, error)
+	// GetPositions gets open derivatives positions with their margin state.
+	GetPositions(ctx context.Context, exchange string, symbols []string) (*PositionsResponse, error)
+	// GetPositioning gets open interest and long/short account ratios of perps.
+	GetPositioning(ctx context.Context, exchange string, symbols []string, period string) (*PositioningResponse, error)
+
 	// Balance operations
 	FetchBalance(ctx context.Context, exchange string) (*BalanceResponse, error)
 
@@ -269,6 +269,181 @@ type FundingRateResponse struct {
 	Timestamp string `json:"timestamp"`
 }
 
+// DerivativesQuery selects the expiring instruments to list.
+type DerivativesQuery struct {
+	// Types is option, future or both; empty lists both.
+	Types []string
+	// Underlying restricts instruments to one base asset, e.g. BTC.
+	Underlying string
+	// Limit caps the number of instruments; zero uses the service default.
+	Limit int
+}
+
+// DerivativeInstrument is an option or dated future with its latest mark data.
+type DerivativeInstrument struct {
+	// Symbol is the unified instrument symbol, e.g. BTC/USD:BTC-250328-60000-C.
+	Symbol string `json:"symbol"`
+	// Type is option or future.
+	Type string `json:"type"`
+	// Base is the underlying asset.
+	Base string `json:"base"`
+	// Quote is the quote currency.
+	Quote string `json:"quote"`
+	// Settle is the settlement currency.
+	Settle string `json:"settle,omitempty"`
+	// Expiry is the expiration time.
+	Expiry UnixTimestamp `json:"expiry"`
+	// Strike is the option strike price.
+	Strike float64 `json:"strike,omitempty"`
+	// OptionType is call or put.
+	OptionType string `json:"optionType,omitempty"`
+	// ContractSize is the amount of the underlying per contract.
+	ContractSize float64 `json:"contractSize,omitempty"`
+	// MarkPrice is the exchange mark price.
+	MarkPrice float64 `json:"markPrice,omitempty"`
+	// IndexPrice is the index price.
+	IndexPrice float64 `json:"indexPrice,omitempty"`
+	// UnderlyingPrice is the price of the option's underlying.
+	UnderlyingPrice float64 `json:"underlyingPrice,omitempty"`
+	// Bid is the best bid.
+	Bid float64 `json:"bid,omitempty"`
+	// Ask is the best ask.
+	Ask float64 `json:"ask,omitempty"`
+	// Last is the last traded price.
+	Last float64 `json:"last,omitempty"`
+	// MarkIV is the mark implied volatility as a fraction.
+	MarkIV float64 `json:"markIV,omitempty"`
+	// BidIV is the bid implied volatility as a fraction.
+	BidIV float64 `json:"bidIV,omitempty"`
+	// AskIV is the ask implied volatility as a fraction.
+	AskIV float64 `json:"askIV,omitempty"`
+	// Delta is the option delta.
+	Delta float64 `json:"delta,omitempty"`
+	// Gamma is the option gamma.
+	Gamma float64 `json:"gamma,omitempty"`
+	// Theta is the option theta.
+	Theta float64 `json:"theta,omitempty"`
+	// Vega is the option vega.
+	Vega float64 `json:"vega,omitempty"`
+}
+
+// DerivativesResponse represents the response from the derivatives endpoint.
+type DerivativesResponse struct {
+	// Exchange is the exchange name.
+	Exchange string `json:"exchange"`
+	// Instruments are the options and dated futures, soonest expiry first.
+	Instruments []DerivativeInstrument `json:"instruments"`
+	// Count is the number of instruments.
+	Count int `json:"count"`
+	// Timestamp is the response timestamp.
+	Timestamp string `json:"timestamp"`
+}
+
+// MarginSettings is the leverage and margin mode configured for a symbol.
+type MarginSettings struct {
+	// Symbol is the unified derivatives symbol, e.g. BTC/USDT:USDT.
+	Symbol string `json:"symbol"`
+	// MarginMode is isolated or cross; empty if the exchange does not report it.
+	MarginMode string `json:"marginMode,omitempty"`
+	// LongLeverage is the leverage applied to long positions.
+	LongLeverage float64 `json:"longLeverage,omitempty"`
+	// ShortLeverage is the leverage applied to short positions.
+	ShortLeverage float64 `json:"shortLeverage,omitempty"`
+}
+
+// SetLeverageRequest sets the leverage and optionally the margin mode of a symbol.
+type SetLeverageRequest struct {
+	// Symbol is the unified derivatives symbol.
+	Symbol string `json:"symbol"`
+	// Leverage is the leverage to apply to both sides.
+	Leverage float64 `json:"leverage"`
+	// MarginMode is isolated or cross; empty keeps the current mode.
+	MarginMode string `json:"marginMode,omitempty"`
+}
+
+// MarginSettingsResponse represents the response from the leverage endpoints.
+type MarginSettingsResponse struct {
+	// Exchange is the exchange name.
+	Exchange string `json:"exchange"`
+	// Settings are the margin settings of the symbol.
+	Settings MarginSettings `json:"settings"`
+	// Timestamp is the response timestamp.
+	Timestamp string `json:"timestamp"`
+}
+
+// PositionInfo is an open derivatives position with its margin state.
+type PositionInfo struct {
+	// Symbol is the unified derivatives symbol.
+	Symbol string `json:"symbol"`
+	// Side is long or short.
+	Side string `json:"side,omitempty"`
+	// Contracts is the position size in contracts.
+	Contracts float64 `json:"contracts,omitempty"`
+	// Notional is the position value in the settle currency.
+	Notional float64 `json:"notional,omitempty"`
+	// EntryPrice is the average entry price.
+	EntryPrice float64 `json:"entryPrice,omitempty"`
+	// MarkPrice is the current mark price.
+	MarkPrice float64 `json:"markPrice,omitempty"`
+	// LiquidationPrice is the estimated liquidation price.
+	LiquidationPrice float64 `json:"liquidationPrice,omitempty"`
+	// UnrealizedPnl is the unrealized profit or loss.
+	UnrealizedPnl float64 `json:"unrealizedPnl,omitempty"`
+	// Leverage is the applied leverage.
+	Leverage float64 `json:"leverage,omitempty"`
+	// MarginMode is isolated or cross.
+	MarginMode string `json:"marginMode,omitempty"`
+	// MaintenanceMargin is the margin required to keep the position open.
+	MaintenanceMargin float64 `json:"maintenanceMargin,omitempty"`
+	// Collateral is the margin backing the position.
+	Collateral float64 `json:"collateral,omitempty"`
+	// MarginRatio is maintenance margin over collateral; liquidation is near 1.
+	MarginRatio float64 `json:"marginRatio,omitempty"`
+}
+
+// PositionsResponse represents the response from the positions endpoint.
+type PositionsResponse struct {
+	// Exchange is the exchange name.
+	Exchange string `json:"exchange"`
+	// Positions are the open positions of the account.
+	Positions []PositionInfo `json:"positions"`
+	// Timestamp is the response timestamp.
+	Timestamp string `json:"timestamp"`
+}
+
+// PositioningInfo is the open interest and long/short account ratio of a
+// perpetual swap. Values the exchange does not expose are zero.
+type PositioningInfo struct {
+	// Symbol is the perpetual swap symbol.
+	Symbol string `json:"symbol"`
+	// OpenInterestAmount is open interest in contracts or base currency.
+	OpenInterestAmount float64 `json:"openInterestAmount"`
+	// OpenInterestValue is open interest in quote currency.
+	OpenInterestValue float64 `json:"openInterestValue"`
+	// LongShortRatio is long accounts divided by short accounts.
+	LongShortRatio float64 `json:"longShortRatio"`
+	// LongAccount is the share of accounts that are net long.
+	LongAccount float64 `json:"longAccount"`
+	// ShortAccount is the share of accounts that are net short.
+	ShortAccount float64 `json:"shortAccount"`
+	// Timestamp is the data timestamp.
+	Timestamp UnixTimestamp `json:"timestamp"`
+}
+
+// PositioningResponse represents the response from the positioning endpoint.
+type PositioningResponse struct {
+	// Exchange is the exchange name.
+	Exchange string `json:"exchange"`
+	// Period is the long/short ratio aggregation period.
+	Period string `json:"period"`
+	// Positioning holds one entry per symbol with data.
+	Positioning []PositioningInfo `json:"positioning"`
+	// Count is the number of entries.
+	Count int `json:"count"`
+	// Timestamp is the response timestamp.
+	Timestamp string `json:"timestamp"`
+}
+
 // FundingArbitrageOpportunity represents a funding rate arbitrage opportunity.
 type FundingArbitrageOpportunity struct {
 	// Symbol is the trading pair.
@@ -0,0 +1,559 @@
+package ccxt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/config"
+	"github.com/shopspring/decimal"
+)
+
+const secondsPerYear = 365 * 24 * 60 * 60
+
+// syntheticBasePrices are starting prices for well-known assets; other assets
+// start at a price derived from their name.
+var syntheticBasePrices = map[string]float64{
+	"BTC":  65000,
+	"ETH":  3200,
+	"SOL":  150,
+	"BNB":  580,
+	"XRP":  0.6,
+	"ADA":  0.45,
+	"DOGE": 0.15,
+}
+
+// SyntheticMarket is an http.RoundTripper that answers CCXT service requests
+// with generated market data. Each symbol follows geometric Brownian motion,
+// every exchange quotes it with a small fixed basis, and arbitrage episodes
+// temporarily push one exchange's price away from the others.
+type SyntheticMarket struct {
+	cfg       config.SyntheticMarketConfig
+	exchanges map[string]bool
+	symbols   map[string]string
+	rng       *rand.Rand
+	now       func() time.Time
+	mu        sync.Mutex
+	prices    map[string]*syntheticPrice
+	episodes  map[string]syntheticEpisode
+}
+
+type syntheticPrice struct {
+	price   float64
+	open    float64
+	high    float64
+	low     float64
+	volume  float64
+	updated time.Time
+}
+
+// syntheticEpisode is an injected arbitrage spread on one exchange.
+type syntheticEpisode struct {
+	exchange  string
+	offsetPct float64
+	until     time.Time
+}
+
+// NewSyntheticMarket creates a synthetic market. Zero config values fall back
+// to the defaults of the ccxt.synthetic config section.
+//
+// Parameters:
+//
+//	cfg: Synthetic market configuration.
+//
+// Returns:
+//
+//	*SyntheticMarket: Initialized market.
+func NewSyntheticMarket(cfg config.SyntheticMarketConfig) *SyntheticMarket {
+	cfg = withSyntheticDefaults(cfg)
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	m := &SyntheticMarket{
+		cfg:       cfg,
+		exchanges: make(map[string]bool, len(cfg.Exchanges)),
+		symbols:   make(map[string]string, len(cfg.Symbols)),
+		// #nosec G404 -- synthetic market data is not security sensitive
+		rng:      rand.New(rand.NewSource(seed)),
+		now:      time.Now,
+		prices:   make(map[string]*syntheticPrice),
+		episodes: make(map[string]syntheticEpisode),
+	}
+	for _, exchange := range cfg.Exchanges {
+		m.exchanges[strings.ToLower(strings.TrimSpace(exchange))] = true
+	}
+	for _, symbol := range cfg.Symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		m.symbols[normalizeSyntheticSymbol(symbol)] = symbol
+	}
+	return m
+}
+
+func withSyntheticDefaults(cfg config.SyntheticMarketConfig) config.SyntheticMarketConfig {
+	if len(cfg.Exchanges) == 0 {
+		cfg.Exchanges = []string{"binance", "bybit", "okx"}
+	}
+	if len(cfg.Symbols) == 0 {
+		cfg.Symbols = []string{"BTC/USDT", "ETH/USDT", "SOL/USDT", "BNB/USDT"}
+	}
+	if cfg.Volatility <= 0 {
+		cfg.Volatility = 0.8
+	}
+	if cfg.TimeScale <= 0 {
+		cfg.TimeScale = 1
+	}
+	if cfg.SpreadBps <= 0 {
+		cfg.SpreadBps = 2
+	}
+	if cfg.ArbitrageDurationSeconds <= 0 {
+		cfg.ArbitrageDurationSeconds = 120
+	}
+	return cfg
+}
+
+// RoundTrip answers a CCXT service request from the synthetic market.
+func (m *SyntheticMarket) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	status, payload := m.route(req, body)
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode synthetic response: %w", err)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(raw)),
+		ContentLength: int64(len(raw)),
+		Request:       req,
+	}, nil
+}
+
+func (m *SyntheticMarket) route(req *http.Request, body []byte) (int, interface{}) {
+	segments := strings.Split(strings.Trim(req.URL.EscapedPath(), "/"), "/")
+	for i, segment := range segments {
+		if unescaped, err := url.PathUnescape(segment); err == nil {
+			segments[i] = unescaped
+		}
+	}
+	query := req.URL.Query()
+	now := m.now().UTC()
+	timestamp := now.Format(time.RFC3339)
+
+	if len(segments) == 1 && segments[0] == "health" {
+		return http.StatusOK, HealthResponse{Status: "ok", Timestamp: timestamp, Service: "synthetic-market", Version: "1.0.0"}
+	}
+	if len(segments) < 2 || segments[0] != "api" {
+		return syntheticNotFound(req)
+	}
+
+	switch segments[1] {
+	case "exchanges":
+		exchanges := make([]ExchangeInfo, 0, len(m.cfg.Exchanges))
+		for _, exchange := range m.exchangeIDs() {
+			exchanges = append(exchanges, ExchangeInfo{ID: exchange, Name: exchange, Countries: []string{}, URLs: map[string]interface{}{}})
+		}
+		return http.StatusOK, ExchangesResponse{Exchanges: exchanges}
+	case "tickers":
+		var request TickersRequest
+		if len(body) > 0 {
+			if err := json.Unmarshal(body, &request); err != nil {
+				return http.StatusBadRequest, map[string]string{"error": "invalid tickers request"}
+			}
+		}
+		exchanges := request.Exchanges
+		if len(exchanges) == 0 {
+			exchanges = m.exchangeIDs()
+		}
+		tickers := make([]TickerData, 0, len(exchanges)*len(request.Symbols))
+		for _, exchange := range exchanges {
+			for _, raw := range request.Symbols {
+				if symbol, ok := m.lookup(exchange, raw); ok {
+					tickers = append(tickers, TickerData{Exchange: strings.ToLower(exchange), Ticker: m.ticker(strings.ToLower(exchange), symbol, now)})
+				}
+			}
+		}
+		return http.StatusOK, TickersResponse{Tickers: tickers, Timestamp: timestamp}
+	case "admin":
+		if len(segments) == 4 && segments[2] == "exchanges" && segments[3] == "config" {
+			return http.StatusOK, ExchangeConfigResponse{ActiveExchanges: m.exchangeIDs(), AvailableExchanges: m.exchangeIDs(), Timestamp: timestamp}
+		}
+		return syntheticNotFound(req)
+	}
+
+	if len(segments) < 3 {
+		return syntheticNotFound(req)
+	}
+	exchange := strings.ToLower(segments[2])
+	if !m.exchanges[exchange] {
+		return http.StatusNotFound, map[string]string{"error": fmt.Sprintf("exchange %s is not simulated", exchange)}
+	}
+
+	switch segments[1] {
+	case "markets":
+		symbols := m.symbolList()
+		return http.StatusOK, MarketsResponse{Exchange: exchange, Symbols: symbols, Count: len(symbols), Timestamp: timestamp}
+	case "balance":
+		return http.StatusOK, BalanceResponse{
+			Exchange:  exchange,
+			Timestamp: now,
+			Total:     map[string]float64{"USDT": 10000},
+			Free:      map[string]float64{"USDT": 10000},
+			Used:      map[string]float64{"USDT": 0},
+		}
+	case "funding-rates":
+		symbols := m.symbolList()
+		if raw := query.Get("symbols"); raw != "" {
+			symbols = nil
+			for _, requested := range strings.Split(raw, ",") {
+				if symbol, ok := m.lookup(exchange, requested); ok {
+					symbols = append(symbols, symbol)
+				}
+			}
+		}
+		rates := make([]FundingRate, 0, len(symbols))
+		for _, symbol := range symbols {
+			rates = append(rates, m.fundingRate(exchange, symbol, now))
+		}
+		return http.StatusOK, FundingRateResponse{Exchange: exchange, FundingRates: rates, Count: len(rates), Timestamp: timestamp}
+	}
+
+	if len(segments) != 4 {
+		return syntheticNotFound(req)
+	}
+	symbol, ok := m.lookup(exchange, segments[3])
+	if !ok {
+		return http.StatusNotFound, map[string]string{"error": fmt.Sprintf("symbol %s is not simulated", segments[3])}
+	}
+	limit := syntheticLimit(query.Get("limit"), 20)
+
+	switch segments[1] {
+	case "ticker":
+		return http.StatusOK, TickerResponse{Exchange: exchange, Symbol: symbol, Ticker: m.ticker(exchange, symbol, now), Timestamp: timestamp}
+	case "orderbook":
+		return http.StatusOK, OrderBookResponse{Exchange: exchange, Symbol: symbol, OrderBook: m.orderBook(exchange, symbol, limit, now), Timestamp: timestamp}
+	case "trades":
+		return http.StatusOK, TradesResponse{Exchange: exchange, Symbol: symbol, Trades: m.trades(exchange, symbol, limit, now), Timestamp: timestamp}
+	case "ohlcv":
+		timeframe := query.Get("timeframe")
+		if timeframe == "" {
+			timeframe = "1h"
+		}
+		interval, err := parseTimeframe(timeframe)
+		if err != nil {
+			return http.StatusBadRequest, map[string]string{"error": err.Error()}
+		}
+		candles := m.ohlcv(exchange, symbol, interval, syntheticLimit(query.Get("limit"), 100), now)
+		return http.StatusOK, OHLCVResponse{Exchange: exchange, Symbol: symbol, Timeframe: timeframe, OHLCV: candles, Timestamp: timestamp}
+	case "funding-rate":
+		return http.StatusOK, m.fundingRate(exchange, symbol, now)
+	}
+	return syntheticNotFound(req)
+}
+
+// Price returns the current mid price of a symbol on an exchange.
+//
+// Parameters:
+//
+//	exchange: Exchange ID.
+//	symbol: Trading pair (e.g., "BTC/USDT").
+//
+// Returns:
+//
+//	float64: Mid price, or 0 if the pair is not simulated.
+func (m *SyntheticMarket) Price(exchange, symbol string) float64 {
+	symbol, ok := m.lookup(exchange, symbol)
+	if !ok {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.midLocked(strings.ToLower(exchange), symbol, m.now().UTC())
+}
+
+func (m *SyntheticMarket) ticker(exchange, symbol string, now time.Time) Ticker {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	mid := m.midLocked(exchange, symbol, now)
+	state := m.prices[symbol]
+	half := mid * m.cfg.SpreadBps / 20000
+	change := mid - state.open
+	return Ticker{
+		Symbol:     symbol,
+		Last:       syntheticDecimal(mid),
+		Bid:        syntheticDecimal(mid - half),
+		Ask:        syntheticDecimal(mid + half),
+		Volume:     decimal.NewFromFloat(state.volume).Round(4),
+		High:       syntheticDecimal(math.Max(state.high, mid)),
+		Low:        syntheticDecimal(math.Min(state.low, mid)),
+		Open:       syntheticDecimal(state.open),
+		Close:      syntheticDecimal(mid),
+		Change:     syntheticDecimal(change),
+		Percentage: decimal.NewFromFloat(change / state.open * 100).Round(4),
+		Timestamp:  UnixTimestamp(now),
+	}
+}
+
+func (m *SyntheticMarket) orderBook(exchange, symbol string, depth int, now time.Time) OrderBook {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	mid := m.midLocked(exchange, symbol, now)
+	half := mid * m.cfg.SpreadBps / 20000
+	tick := math.Max(half, mid*0.00005)
+	book := OrderBook{
+		Symbol:    symbol,
+		Bids:      make([]OrderBookEntry, 0, depth),
+		Asks:      make([]OrderBookEntry, 0, depth),
+		Timestamp: now,
+		Nonce:     now.UnixMilli(),
+	}
+	baseAmount := 5000 / mid
+	for i := 0; i < depth; i++ {
+		offset := half + float64(i)*tick
+		book.Bids = append(book.Bids, OrderBookEntry{Price: syntheticDecimal(mid - offset), Amount: syntheticAmount(baseAmount * (0.5 + m.rng.Float64()) * (1 + float64(i)*0.1))})
+		book.Asks = append(book.Asks, OrderBookEntry{Price: syntheticDecimal(mid + offset), Amount: syntheticAmount(baseAmount * (0.5 + m.rng.Float64()) * (1 + float64(i)*0.1))})
+	}
+	return book
+}
+
+func (m *SyntheticMarket) trades(exchange, symbol string, count int, now time.Time) []Trade {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	mid := m.midLocked(exchange, symbol, now)
+	trades := make([]Trade, 0, count)
+	for i := count - 1; i >= 0; i-- {
+		side := "buy"
+		if m.rng.Intn(2) == 0 {
+			side = "sell"
+		}
+		price := mid * (1 + (m.rng.Float64()-0.5)*m.cfg.SpreadBps/10000)
+		amount := 2000 / mid * (0.1 + m.rng.Float64())
+		trades = append(trades, Trade{
+			ID:        fmt.Sprintf("syn-%d-%d", now.UnixMilli(), i),
+			Timestamp: now.Add(-time.Duration(i) * time.Second),
+			Symbol:    symbol,
+			Side:      side,
+			Amount:    syntheticAmount(amount),
+			Price:     syntheticDecimal(price),
+			Cost:      syntheticDecimal(price * amount),
+		})
+	}
+	return trades
+}
+
+// ohlcv walks the price process backwards from the current price, so the
+// last candle closes at the live quote.
+func (m *SyntheticMarket) ohlcv(exchange, symbol string, interval time.Duration, count int, now time.Time) []OHLCV {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	price := m.midLocked(exchange, symbol, now)
+	dt := interval.Seconds() * m.cfg.TimeScale / secondsPerYear
+	sigma := m.cfg.Volatility * math.Sqrt(dt)
+	start := now.Truncate(interval)
+
+	candles := make([]OHLCV, count)
+	for i := count - 1; i >= 0; i-- {
+		closePrice := price
+		openPrice := closePrice / math.Exp((m.cfg.Drift-m.cfg.Volatility*m.cfg.Volatility/2)*dt+sigma*m.rng.NormFloat64())
+		high := math.Max(openPrice, closePrice) * (1 + math.Abs(m.rng.NormFloat64())*sigma/2)
+		low := math.Min(openPrice, closePrice) * (1 - math.Abs(m.rng.NormFloat64())*sigma/2)
+		candles[i] = OHLCV{
+			Timestamp: start.Add(-time.Duration(count-1-i) * interval),
+			Open:      syntheticDecimal(openPrice),
+			High:      syntheticDecimal(high),
+			Low:       syntheticDecimal(low),
+			Close:     syntheticDecimal(closePrice),
+			Volume:    syntheticAmount(m.prices[symbol].volume * interval.Hours() / 24 * (0.5 + m.rng.Float64())),
+		}
+		price = openPrice
+	}
+	return candles
+}
+
+// fundingRate quotes a small positive rate with a fixed per-exchange skew;
+// an exchange in an arbitrage episode also diverges on funding.
+func (m *SyntheticMarket) fundingRate(exchange, symbol string, now time.Time) FundingRate {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	mid := m.midLocked(exchange, symbol, now)
+	rate := 0.0001 + syntheticHashUnit(exchange+"|funding|"+symbol)*0.0002
+	if episode, ok := m.episodes[symbol]; ok && episode.exchange == exchange {
+		rate += episode.offsetPct / 100 * 0.05
+	}
+	next := now.Truncate(8 * time.Hour).Add(8 * time.Hour)
+	return FundingRate{
+		Symbol:           symbol,
+		FundingRate:      math.Round(rate*1e8) / 1e8,
+		FundingTimestamp: UnixTimestamp(next.Add(-8 * time.Hour)),
+		NextFundingTime:  UnixTimestamp(next),
+		MarkPrice:        mid,
+		IndexPrice:       m.prices[symbol].price,
+		Timestamp:        UnixTimestamp(now),
+	}
+}
+
+// midLocked advances the symbol's price to now and returns the exchange's
+// quote of it. m.mu must be held.
+func (m *SyntheticMarket) midLocked(exchange, symbol string, now time.Time) float64 {
+	price := m.advanceLocked(symbol, now)
+	mid := price * (1 + syntheticHashUnit(exchange+"|"+symbol)*m.cfg.SpreadBps/10000)
+	if episode, ok := m.episodes[symbol]; ok && episode.exchange == exchange {
+		mid *= 1 + episode.offsetPct/100
+	}
+	return mid
+}
+
+// advanceLocked moves a symbol's reference price along geometric Brownian
+// motion and may start an arbitrage episode. m.mu must be held.
+func (m *SyntheticMarket) advanceLocked(symbol string, now time.Time) float64 {
+	state, ok := m.prices[symbol]
+	if !ok {
+		price := syntheticBasePrice(symbol)
+		state = &syntheticPrice{
+			price:   price,
+			open:    price,
+			high:    price * 1.01,
+			low:     price * 0.99,
+			volume:  5e7 / price * (0.8 + 0.4*m.rng.Float64()),
+			updated: now,
+		}
+		m.prices[symbol] = state
+		return state.price
+	}
+
+	elapsed := now.Sub(state.updated).Seconds()
+	if elapsed <= 0 {
+		return state.price
+	}
+	dt := elapsed * m.cfg.TimeScale / secondsPerYear
+	sigma := m.cfg.Volatility
+	state.price *= math.Exp((m.cfg.Drift-sigma*sigma/2)*dt + sigma*math.Sqrt(dt)*m.rng.NormFloat64())
+	state.high = math.Max(state.high, state.price)
+	state.low = math.Min(state.low, state.price)
+	state.updated = now
+
+	if episode, ok := m.episodes[symbol]; ok && !now.Before(episode.until) {
+		delete(m.episodes, symbol)
+	}
+	if _, active := m.episodes[symbol]; !active && len(m.exchanges) > 1 && m.rng.Float64() < m.cfg.ArbitrageChance {
+		exchanges := m.exchangeIDs()
+		offset := m.cfg.ArbitrageSpreadPct
+		if m.rng.Intn(2) == 0 {
+			offset = -offset
+		}
+		m.episodes[symbol] = syntheticEpisode{
+			exchange:  exchanges[m.rng.Intn(len(exchanges))],
+			offsetPct: offset,
+			until:     now.Add(time.Duration(m.cfg.ArbitrageDurationSeconds) * time.Second),
+		}
+	}
+	return state.price
+}
+
+func (m *SyntheticMarket) lookup(exchange, raw string) (string, bool) {
+	if !m.exchanges[strings.ToLower(exchange)] {
+		return "", false
+	}
+	symbol, ok := m.symbols[normalizeSyntheticSymbol(raw)]
+	return symbol, ok
+}
+
+func (m *SyntheticMarket) exchangeIDs() []string {
+	ids := make([]string, 0, len(m.exchanges))
+	for id := range m.exchanges {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (m *SyntheticMarket) symbolList() []string {
+	symbols := make([]string, 0, len(m.symbols))
+	for _, symbol := range m.symbols {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+func syntheticNotFound(req *http.Request) (int, interface{}) {
+	return http.StatusNotFound, map[string]string{"error": fmt.Sprintf("synthetic market does not serve %s %s", req.Method, req.URL.Path)}
+}
+
+func normalizeSyntheticSymbol(symbol string) string {
+	return strings.NewReplacer("/", "", "-", "", "_", "").Replace(strings.ToUpper(strings.TrimSpace(symbol)))
+}
+
+func syntheticBasePrice(symbol string) float64 {
+	base := strings.SplitN(symbol, "/", 2)[0]
+	if price, ok := syntheticBasePrices[base]; ok {
+		return price
+	}
+	return 1 + (syntheticHashUnit(base)+0.5)*99
+}
+
+// syntheticHashUnit maps a key to a stable value in [-0.5, 0.5).
+func syntheticHashUnit(key string) float64 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return float64(h.Sum32())/float64(math.MaxUint32+1) - 0.5
+}
+
+// syntheticDecimal rounds a price to a precision that suits its magnitude.
+func syntheticDecimal(v float64) decimal.Decimal {
+	places := int32(2)
+	if abs := math.Abs(v); abs > 0 && abs < 100 {
+		places = int32(4 - math.Floor(math.Log10(abs)))
+	}
+	return decimal.NewFromFloat(v).Round(places)
+}
+
+func syntheticAmount(v float64) decimal.Decimal {
+	return decimal.NewFromFloat(v).Round(6)
+}
+
+func syntheticLimit(raw string, fallback int) int {
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit <= 0 {
+		return fallback
+	}
+	if limit > 1000 {
+		return 1000
+	}
+	return limit
+}
+
+// parseTimeframe converts a CCXT timeframe such as "5m" or "4h" to a duration.
+func parseTimeframe(timeframe string) (time.Duration, error) {
+	if len(timeframe) < 2 {
+		return 0, fmt.Errorf("invalid timeframe %q", timeframe)
+	}
+	n, err := strconv.Atoi(timeframe[:len(timeframe)-1])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid timeframe %q", timeframe)
+	}
+	units := map[byte]time.Duration{'m': time.Minute, 'h': time.Hour, 'd': 24 * time.Hour, 'w': 7 * 24 * time.Hour}
+	unit, ok := units[timeframe[len(timeframe)-1]]
+	if !ok {
+		return 0, fmt.Errorf("invalid timeframe %q", timeframe)
+	}
+	return time.Duration(n) * unit, nil
+}
@@ -0,0 +1,160 @@
+package ccxt
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSyntheticMarket(cfg config.SyntheticMarketConfig) (*SyntheticMarket, *time.Time) {
+	cfg.Seed = 42
+	m := NewSyntheticMarket(cfg)
+	now := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	return m, &now
+}
+
+func TestSyntheticMarket_PricesFollowTrend(t *testing.T) {
+	m, now := newTestSyntheticMarket(config.SyntheticMarketConfig{
+		Symbols:    []string{"BTC/USDT"},
+		Volatility: 0.0001,
+		Drift:      0.5,
+	})
+
+	start := m.Price("binance", "BTC/USDT")
+	require.InDelta(t, 65000, start, 65000*0.001)
+	for i := 0; i < 365; i++ {
+		*now = now.Add(24 * time.Hour)
+		require.Positive(t, m.Price("binance", "BTC/USDT"))
+	}
+
+	// A year at 50% drift and negligible volatility grows by about e^0.5.
+	assert.InDelta(t, math.Exp(0.5), m.Price("binance", "BTC/USDT")/start, 0.01)
+}
+
+func TestSyntheticMarket_VolatilityMovesPrices(t *testing.T) {
+	m, now := newTestSyntheticMarket(config.SyntheticMarketConfig{Symbols: []string{"ETH/USDT"}, Volatility: 0.8, TimeScale: 60})
+
+	previous := m.Price("binance", "ETH/USDT")
+	moved := 0
+	for i := 0; i < 50; i++ {
+		*now = now.Add(time.Minute)
+		price := m.Price("binance", "ETH/USDT")
+		if price != previous {
+			moved++
+		}
+		previous = price
+	}
+	assert.Equal(t, 50, moved)
+	assert.Zero(t, m.Price("binance", "DOGE/USDT"), "unconfigured symbols are not quoted")
+	assert.Zero(t, m.Price("kraken", "ETH/USDT"), "unconfigured exchanges are not quoted")
+}
+
+func TestSyntheticMarket_InjectsArbitrageSpread(t *testing.T) {
+	m, now := newTestSyntheticMarket(config.SyntheticMarketConfig{
+		Exchanges:                []string{"binance", "bybit"},
+		Symbols:                  []string{"BTC/USDT"},
+		Volatility:               0.0001,
+		SpreadBps:                1,
+		ArbitrageSpreadPct:       1,
+		ArbitrageChance:          1,
+		ArbitrageDurationSeconds: 60,
+	})
+	gap := func() float64 {
+		a, b := m.Price("binance", "BTC/USDT"), m.Price("bybit", "BTC/USDT")
+		return math.Abs(a-b) / math.Min(a, b) * 100
+	}
+
+	m.Price("binance", "BTC/USDT")
+	assert.Less(t, gap(), 0.02, "exchanges only differ by their basis before an episode")
+
+	*now = now.Add(time.Second)
+	assert.Greater(t, gap(), 0.95)
+
+	m.cfg.ArbitrageChance = 0
+	*now = now.Add(2 * time.Minute)
+	assert.Less(t, gap(), 0.02, "the spread closes when the episode ends")
+}
+
+func TestSyntheticMarket_ServesClientRequests(t *testing.T) {
+	client := NewClient(&config.CCXTConfig{
+		ServiceURL:  "http://127.0.0.1:1",
+		GrpcAddress: "127.0.0.1:1",
+		Synthetic:   config.SyntheticMarketConfig{Enabled: true, Seed: 7},
+	})
+	require.False(t, client.IsGRPCEnabled())
+	ctx := context.Background()
+
+	health, err := client.HealthCheck(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", health.Status)
+
+	exchanges, err := client.GetExchanges(ctx)
+	require.NoError(t, err)
+	assert.Len(t, exchanges.Exchanges, 3)
+
+	markets, err := client.GetMarkets(ctx, "bybit")
+	require.NoError(t, err)
+	assert.Contains(t, markets.Symbols, "BTC/USDT")
+
+	// OKX symbols are sent URL-escaped.
+	ticker, err := client.GetTicker(ctx, "okx", "ETH/USDT")
+	require.NoError(t, err)
+	assert.Equal(t, "ETH/USDT", ticker.Symbol)
+	assert.True(t, ticker.Ticker.Bid.LessThan(ticker.Ticker.Ask))
+
+	tickers, err := client.GetTickers(ctx, &TickersRequest{Symbols: []string{"BTC/USDT", "SOL/USDT"}})
+	require.NoError(t, err)
+	assert.Len(t, tickers.Tickers, 6)
+
+	book, err := client.GetOrderBook(ctx, "binance", "BTC/USDT", 10)
+	require.NoError(t, err)
+	require.Len(t, book.OrderBook.Bids, 10)
+	require.Len(t, book.OrderBook.Asks, 10)
+	assert.True(t, book.OrderBook.Bids[0].Price.GreaterThan(book.OrderBook.Bids[9].Price))
+	assert.True(t, book.OrderBook.Bids[0].Price.LessThan(book.OrderBook.Asks[0].Price))
+
+	candles, err := client.GetOHLCV(ctx, "binance", "BTC/USDT", "5m", 30)
+	require.NoError(t, err)
+	require.Len(t, candles.OHLCV, 30)
+	for i, candle := range candles.OHLCV {
+		assert.True(t, candle.High.GreaterThanOrEqual(candle.Open) && candle.High.GreaterThanOrEqual(candle.Close))
+		assert.True(t, candle.Low.LessThanOrEqual(candle.Open) && candle.Low.LessThanOrEqual(candle.Close))
+		if i > 0 {
+			assert.Equal(t, 5*time.Minute, candle.Timestamp.Sub(candles.OHLCV[i-1].Timestamp))
+		}
+	}
+
+	rates, err := client.GetAllFundingRates(ctx, "bybit")
+	require.NoError(t, err)
+	assert.Len(t, rates, 4)
+	rates, err = client.GetFundingRates(ctx, "bybit", []string{"BTC/USDT"})
+	require.NoError(t, err)
+	require.Len(t, rates, 1)
+	assert.Positive(t, rates[0].FundingRate)
+
+	balance, err := client.FetchBalance(ctx, "binance")
+	require.NoError(t, err)
+	assert.Equal(t, 10000.0, balance.Free["USDT"])
+
+	_, err = client.GetTicker(ctx, "kraken", "BTC/USDT")
+	assert.Error(t, err)
+}
+
+func TestParseTimeframe(t *testing.T) {
+	tests := map[string]time.Duration{"1m": time.Minute, "15m": 15 * time.Minute, "4h": 4 * time.Hour, "1d": 24 * time.Hour, "1w": 7 * 24 * time.Hour}
+	for timeframe, want := range tests {
+		got, err := parseTimeframe(timeframe)
+		require.NoError(t, err, timeframe)
+		assert.Equal(t, want, got, timeframe)
+	}
+	for _, invalid := range []string{"", "m", "0h", "5y"} {
+		_, err := parseTimeframe(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
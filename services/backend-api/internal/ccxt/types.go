@@ -708,3 +708,108 @@ func (s *Service) AddExchange(ctx context.Context, exchange string) (*ExchangeMa
 func (s *Service) FetchBalance(ctx context.Context, exchange string) (*BalanceResponse, error) {
 	return s.client.FetchBalance(ctx, exchange)
 }
+
+// FetchDerivatives retrieves an exchange's options and dated futures.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	exchange: Exchange identifier, e.g. deribit or okx.
+//	query: Instrument types, underlying and limit.
+//
+// Returns:
+//
+//	*DerivativesResponse: Instruments with mark and IV data.
+//	error: Error if retrieval fails.
+func (s *Service) FetchDerivatives(ctx context.Context, exchange string, query DerivativesQuery) (*DerivativesResponse, error) {
+	resp, err := s.client.GetDerivatives(ctx, exchange, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch derivatives for %s: %w", exchange, err)
+	}
+	return resp, nil
+}
+
+// FetchMarginSettings retrieves the leverage and margin mode of a symbol.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	exchange: Exchange identifier.
+//	symbol: Unified derivatives symbol, e.g. BTC/USDT:USDT.
+//
+// Returns:
+//
+//	*MarginSettings: Leverage and margin mode.
+//	error: Error if retrieval fails.
+func (s *Service) FetchMarginSettings(ctx context.Context, exchange, symbol string) (*MarginSettings, error) {
+	resp, err := s.client.GetLeverage(ctx, exchange, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch leverage for %s on %s: %w", symbol, exchange, err)
+	}
+	return &resp.Settings, nil
+}
+
+// UpdateMarginSettings applies leverage and optionally a margin mode to a symbol.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	exchange: Exchange identifier.
+//	req: Symbol, leverage and margin mode.
+//
+// Returns:
+//
+//	*MarginSettings: Settings read back from the exchange.
+//	error: Error if the update fails.
+func (s *Service) UpdateMarginSettings(ctx context.Context, exchange string, req SetLeverageRequest) (*MarginSettings, error) {
+	resp, err := s.client.SetLeverage(ctx, exchange, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set leverage for %s on %s: %w", req.Symbol, exchange, err)
+	}
+	return &resp.Settings, nil
+}
+
+// FetchPositions retrieves open derivatives positions with their margin state.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	exchange: Exchange identifier.
+//	symbols: Optional symbols to restrict to.
+//
+// Returns:
+//
+//	[]PositionInfo: Open positions.
+//	error: Error if retrieval fails.
+func (s *Service) FetchPositions(ctx context.Context, exchange string, symbols []string) ([]PositionInfo, error) {
+	resp, err := s.client.GetPositions(ctx, exchange, symbols)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch positions for %s: %w", exchange, err)
+	}
+	return resp.Positions, nil
+}
+
+// FetchPositioning retrieves open interest and long/short account ratios of
+// perpetual swaps.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	exchange: Exchange identifier.
+//	symbols: Perpetual swap symbols.
+//	period: Long/short ratio aggregation period, e.g. "5m".
+//
+// Returns:
+//
+//	[]PositioningInfo: Entries of the symbols the exchange has data for.
+//	error: Error if retrieval fails.
+func (s *Service) FetchPositioning(ctx context.Context, exchange string, symbols []string, period string) ([]PositioningInfo, error) {
+	if len(symbols) == 0 {
+		return nil, nil
+	}
+	resp, err := s.client.GetPositioning(ctx, exchange, symbols, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch positioning for %s: %w", exchange, err)
+	}
+	return resp.Positioning, nil
+}
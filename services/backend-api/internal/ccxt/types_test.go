@@ -163,6 +163,26 @@ func (m *MockClient) Close() error {
 	return nil
 }
 
+func (m *MockClient) GetDerivatives(ctx context.Context, exchange string, query DerivativesQuery) (*DerivativesResponse, error) {
+	return &DerivativesResponse{Exchange: exchange, Instruments: []DerivativeInstrument{}}, nil
+}
+
+func (m *MockClient) GetLeverage(ctx context.Context, exchange, symbol string) (*MarginSettingsResponse, error) {
+	return &MarginSettingsResponse{Exchange: exchange, Settings: MarginSettings{Symbol: symbol}}, nil
+}
+
+func (m *MockClient) SetLeverage(ctx context.Context, exchange string, req SetLeverageRequest) (*MarginSettingsResponse, error) {
+	return &MarginSettingsResponse{Exchange: exchange, Settings: MarginSettings{Symbol: req.Symbol, MarginMode: req.MarginMode, LongLeverage: req.Leverage, ShortLeverage: req.Leverage}}, nil
+}
+
+func (m *MockClient) GetPositions(ctx context.Context, exchange string, symbols []string) (*PositionsResponse, error) {
+	return &PositionsResponse{Exchange: exchange, Positions: []PositionInfo{}}, nil
+}
+
+func (m *MockClient) GetPositioning(ctx context.Context, exchange string, symbols []string, period string) (*PositioningResponse, error) {
+	return &PositioningResponse{Exchange: exchange, Period: period, Positioning: []PositioningInfo{}}, nil
+}
+
 func (m *MockClient) FetchBalance(ctx context.Context, exchange string) (*BalanceResponse, error) {
 	return &BalanceResponse{Exchange: exchange, Total: map[string]float64{"USDT": 1000.0}}, nil
 }
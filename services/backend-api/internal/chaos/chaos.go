@@ -0,0 +1,390 @@
+// Package chaos injects dependency failures for a bounded window so that
+// degradation handling and circuit breakers can be exercised in staging.
+//
+// The Redis, PostgreSQL, CCXT and LLM clients consult the process-wide
+// Default injector through hooks installed when they are created; with no
+// fault injected every hook passes calls through unchanged.
+package chaos
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+)
+
+// Target is a dependency a fault can be injected into.
+type Target string
+
+const (
+	// TargetRedis fails every Redis command and dial as if Redis were lost.
+	TargetRedis Target = "redis"
+	// TargetCCXT stalls CCXT service calls until they time out.
+	TargetCCXT Target = "ccxt"
+	// TargetLLM answers LLM provider calls with 429 Too Many Requests.
+	TargetLLM Target = "llm"
+	// TargetDatabase delays every PostgreSQL query.
+	TargetDatabase Target = "database"
+)
+
+const (
+	// DefaultDuration is how long a fault lasts when none is given.
+	DefaultDuration = 5 * time.Minute
+	// DefaultMaxDuration bounds the window of a single fault.
+	DefaultMaxDuration = 30 * time.Minute
+)
+
+// defaultLatency is the stall added by timeout and slowness faults when none is given.
+var defaultLatency = map[Target]time.Duration{
+	TargetCCXT:     30 * time.Second,
+	TargetDatabase: 2 * time.Second,
+}
+
+// kinds describes the failure each target simulates.
+var kinds = map[Target]string{
+	TargetRedis:    "unavailable",
+	TargetCCXT:     "timeout",
+	TargetLLM:      "rate_limited",
+	TargetDatabase: "slow",
+}
+
+var (
+	// ErrInjected marks errors produced by an injected fault.
+	ErrInjected = errors.New("chaos fault injected")
+	// ErrInvalidFault is returned for an unknown target or an out-of-range window.
+	ErrInvalidFault = errors.New("invalid chaos fault")
+)
+
+// Targets returns the dependencies faults can be injected into.
+func Targets() []Target {
+	return []Target{TargetRedis, TargetCCXT, TargetLLM, TargetDatabase}
+}
+
+// Fault is an injected failure and its window.
+type Fault struct {
+	Target Target `json:"target"`
+	// Kind is the simulated failure: unavailable, timeout, rate_limited or slow.
+	Kind string `json:"kind"`
+	// LatencyMs is the stall before a timeout or the delay added to a query.
+	LatencyMs int64     `json:"latency_ms,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// Injected counts the calls the fault has affected so far.
+	Injected int64 `json:"injected"`
+}
+
+// Injector holds the active faults.
+type Injector struct {
+	mu          sync.Mutex
+	faults      map[Target]*Fault
+	maxDuration time.Duration
+	now         func() time.Time
+}
+
+var defaultInjector = NewInjector(DefaultMaxDuration)
+
+// Default returns the process-wide injector the dependency clients consult.
+func Default() *Injector {
+	return defaultInjector
+}
+
+// NewInjector creates an injector with no active faults.
+//
+// Parameters:
+//
+//	maxDuration: Longest window a fault may be injected for (0 uses DefaultMaxDuration).
+//
+// Returns:
+//
+//	*Injector: Initialized injector.
+func NewInjector(maxDuration time.Duration) *Injector {
+	if maxDuration <= 0 {
+		maxDuration = DefaultMaxDuration
+	}
+	return &Injector{
+		faults:      make(map[Target]*Fault),
+		maxDuration: maxDuration,
+		now:         time.Now,
+	}
+}
+
+// Inject starts a fault, replacing any active fault on the same target.
+//
+// Parameters:
+//
+//	target: Dependency to fail.
+//	duration: Window length (0 uses DefaultDuration).
+//	latency: Stall for ccxt and delay for database faults (0 uses the target default).
+//
+// Returns:
+//
+//	Fault: The injected fault.
+//	error: ErrInvalidFault for an unknown target or an out-of-range window.
+func (i *Injector) Inject(target Target, duration, latency time.Duration) (Fault, error) {
+	kind, ok := kinds[target]
+	if !ok {
+		return Fault{}, fmt.Errorf("%w: unknown target %q", ErrInvalidFault, target)
+	}
+	if duration == 0 {
+		duration = DefaultDuration
+	}
+	if duration < 0 || duration > i.maxDuration {
+		return Fault{}, fmt.Errorf("%w: duration must be between 0 and %s", ErrInvalidFault, i.maxDuration)
+	}
+	if latency < 0 {
+		return Fault{}, fmt.Errorf("%w: latency must not be negative", ErrInvalidFault)
+	}
+	if latency == 0 {
+		latency = defaultLatency[target]
+	}
+
+	now := i.now().UTC()
+	fault := &Fault{
+		Target:    target,
+		Kind:      kind,
+		LatencyMs: latency.Milliseconds(),
+		StartedAt: now,
+		ExpiresAt: now.Add(duration),
+	}
+	i.mu.Lock()
+	i.faults[target] = fault
+	i.mu.Unlock()
+
+	telemetry.Logger().Warn("Chaos fault injected", "target", target, "kind", kind, "latency_ms", fault.LatencyMs, "expires_at", fault.ExpiresAt)
+	return *fault, nil
+}
+
+// Clear ends the fault on a target.
+//
+// Returns:
+//
+//	bool: Whether a fault was active.
+func (i *Injector) Clear(target Target) bool {
+	i.mu.Lock()
+	fault, ok := i.faults[target]
+	delete(i.faults, target)
+	i.mu.Unlock()
+	if ok {
+		telemetry.Logger().Info("Chaos fault cleared", "target", target, "injected", fault.Injected)
+	}
+	return ok
+}
+
+// ClearAll ends every fault and returns how many were active.
+func (i *Injector) ClearAll() int {
+	cleared := 0
+	for _, target := range Targets() {
+		if i.Clear(target) {
+			cleared++
+		}
+	}
+	return cleared
+}
+
+// Faults returns the active faults ordered by target.
+func (i *Injector) Faults() []Fault {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	now := i.now()
+	faults := make([]Fault, 0, len(i.faults))
+	for target, fault := range i.faults {
+		if !now.Before(fault.ExpiresAt) {
+			i.expire(target)
+			continue
+		}
+		faults = append(faults, *fault)
+	}
+	sort.Slice(faults, func(a, b int) bool { return faults[a].Target < faults[b].Target })
+	return faults
+}
+
+// hit returns the active fault on a target and counts the affected call.
+func (i *Injector) hit(target Target) (Fault, bool) {
+	if i == nil {
+		return Fault{}, false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	fault, ok := i.faults[target]
+	if !ok {
+		return Fault{}, false
+	}
+	if !i.now().Before(fault.ExpiresAt) {
+		i.expire(target)
+		return Fault{}, false
+	}
+	fault.Injected++
+	return *fault, true
+}
+
+// expire drops an elapsed fault; the caller holds the lock.
+func (i *Injector) expire(target Target) {
+	fault := i.faults[target]
+	delete(i.faults, target)
+	telemetry.Logger().Info("Chaos fault expired", "target", target, "injected", fault.Injected)
+}
+
+// Err returns an injected error while the target is failing.
+func (i *Injector) Err(target Target) error {
+	fault, ok := i.hit(target)
+	if !ok {
+		return nil
+	}
+	return fmt.Errorf("%w: %s %s", ErrInjected, target, fault.Kind)
+}
+
+// Delay sleeps for the fault latency while the target is slow, returning
+// early when ctx is done.
+func (i *Injector) Delay(ctx context.Context, target Target) {
+	fault, ok := i.hit(target)
+	if !ok || fault.LatencyMs <= 0 {
+		return
+	}
+	timer := time.NewTimer(time.Duration(fault.LatencyMs) * time.Millisecond)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
+// stall blocks like an unresponsive dependency until ctx is done or the
+// fault latency elapses, then fails with a timeout.
+func (i *Injector) stall(ctx context.Context, fault Fault) error {
+	timer := time.NewTimer(time.Duration(fault.LatencyMs) * time.Millisecond)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %s %s: %w", ErrInjected, fault.Target, fault.Kind, ctx.Err())
+	case <-timer.C:
+		return fmt.Errorf("%w: %s %s: %w", ErrInjected, fault.Target, fault.Kind, context.DeadlineExceeded)
+	}
+}
+
+// RedisHook fails Redis commands while a redis fault is active.
+type RedisHook struct {
+	injector *Injector
+}
+
+// NewRedisHook creates a Redis hook backed by an injector.
+func NewRedisHook(injector *Injector) *RedisHook {
+	return &RedisHook{injector: injector}
+}
+
+// DialHook implements redis.Hook.
+func (h *RedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if err := h.injector.Err(TargetRedis); err != nil {
+			return nil, err
+		}
+		return next(ctx, network, addr)
+	}
+}
+
+// ProcessHook implements redis.Hook.
+func (h *RedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if err := h.injector.Err(TargetRedis); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+		return next(ctx, cmd)
+	}
+}
+
+// ProcessPipelineHook implements redis.Hook.
+func (h *RedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if err := h.injector.Err(TargetRedis); err != nil {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+		return next(ctx, cmds)
+	}
+}
+
+// Transport is an http.RoundTripper that fails requests to a dependency
+// while a fault is active on it: ccxt requests stall until they time out
+// and llm requests are answered with 429 Too Many Requests.
+type Transport struct {
+	injector *Injector
+	target   Target
+	next     http.RoundTripper
+}
+
+// NewTransport wraps a transport with fault injection.
+//
+// Parameters:
+//
+//	injector: Source of active faults.
+//	target: Dependency the requests go to.
+//	next: Transport used when no fault is active (nil uses http.DefaultTransport).
+//
+// Returns:
+//
+//	*Transport: Initialized transport.
+func NewTransport(injector *Injector, target Target, next http.RoundTripper) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Transport{injector: injector, target: target, next: next}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fault, ok := t.injector.hit(t.target)
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	if t.target == TargetLLM {
+		return rateLimitedResponse(req, fault, t.injector.now()), nil
+	}
+	return nil, t.injector.stall(req.Context(), fault)
+}
+
+func rateLimitedResponse(req *http.Request, fault Fault, now time.Time) *http.Response {
+	body := []byte(`{"error":{"type":"rate_limit_error","message":"chaos fault injected: rate limited"}}`)
+	retryAfter := int(math.Ceil(fault.ExpiresAt.Sub(now).Seconds()))
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	header.Set("Retry-After", strconv.Itoa(retryAfter))
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", http.StatusTooManyRequests, http.StatusText(http.StatusTooManyRequests)),
+		StatusCode:    http.StatusTooManyRequests,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// UnaryClientInterceptor stalls gRPC calls to a dependency until they time
+// out while a fault is active on it.
+func UnaryClientInterceptor(injector *Injector, target Target) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if fault, ok := injector.hit(target); ok {
+			return injector.stall(ctx, fault)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
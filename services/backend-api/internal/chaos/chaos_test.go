@@ -0,0 +1,142 @@
+package chaos
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestInjector_WindowAndValidation(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	injector := NewInjector(10 * time.Minute)
+	injector.now = func() time.Time { return now }
+
+	_, err := injector.Inject("kafka", time.Minute, 0)
+	assert.ErrorIs(t, err, ErrInvalidFault)
+	_, err = injector.Inject(TargetRedis, time.Hour, 0)
+	assert.ErrorIs(t, err, ErrInvalidFault)
+	_, err = injector.Inject(TargetDatabase, time.Minute, -time.Second)
+	assert.ErrorIs(t, err, ErrInvalidFault)
+
+	fault, err := injector.Inject(TargetCCXT, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "timeout", fault.Kind)
+	assert.Equal(t, int64(30000), fault.LatencyMs)
+	assert.Equal(t, now.Add(DefaultDuration), fault.ExpiresAt)
+
+	_, err = injector.Inject(TargetRedis, time.Minute, 0)
+	require.NoError(t, err)
+	assert.ErrorIs(t, injector.Err(TargetRedis), ErrInjected)
+	assert.NoError(t, injector.Err(TargetLLM))
+
+	faults := injector.Faults()
+	require.Len(t, faults, 2)
+	assert.Equal(t, TargetCCXT, faults[0].Target)
+	assert.Equal(t, int64(1), faults[1].Injected)
+
+	// The redis window ends after a minute; the ccxt one is still open.
+	now = now.Add(2 * time.Minute)
+	assert.NoError(t, injector.Err(TargetRedis))
+	assert.Len(t, injector.Faults(), 1)
+
+	assert.Equal(t, 1, injector.ClearAll())
+	assert.Empty(t, injector.Faults())
+}
+
+func TestRedisHook(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	injector := NewInjector(0)
+	client.AddHook(NewRedisHook(injector))
+	ctx := context.Background()
+
+	require.NoError(t, client.Set(ctx, "key", "value", 0).Err())
+
+	_, err := injector.Inject(TargetRedis, time.Minute, 0)
+	require.NoError(t, err)
+	assert.ErrorIs(t, client.Get(ctx, "key").Err(), ErrInjected)
+	_, err = client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Get(ctx, "key")
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrInjected)
+
+	injector.Clear(TargetRedis)
+	value, err := client.Get(ctx, "key").Result()
+	require.NoError(t, err)
+	assert.Equal(t, "value", value)
+}
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	injector := NewInjector(0)
+
+	llm := &http.Client{Transport: NewTransport(injector, TargetLLM, nil)}
+	resp, err := llm.Get(server.URL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_, err = injector.Inject(TargetLLM, time.Minute, 0)
+	require.NoError(t, err)
+	resp, err = llm.Get(server.URL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "60", resp.Header.Get("Retry-After"))
+
+	ccxt := &http.Client{Transport: NewTransport(injector, TargetCCXT, nil), Timeout: time.Second}
+	_, err = injector.Inject(TargetCCXT, time.Minute, 20*time.Millisecond)
+	require.NoError(t, err)
+	started := time.Now()
+	_, err = ccxt.Get(server.URL)
+	assert.ErrorIs(t, err, ErrInjected)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(started), time.Second)
+}
+
+func TestDelayAndInterceptor(t *testing.T) {
+	injector := NewInjector(0)
+	ctx := context.Background()
+
+	_, err := injector.Inject(TargetDatabase, time.Minute, 20*time.Millisecond)
+	require.NoError(t, err)
+	started := time.Now()
+	injector.Delay(ctx, TargetDatabase)
+	assert.GreaterOrEqual(t, time.Since(started), 20*time.Millisecond)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	started = time.Now()
+	_, err = injector.Inject(TargetDatabase, time.Minute, time.Minute)
+	require.NoError(t, err)
+	injector.Delay(cancelled, TargetDatabase)
+	assert.Less(t, time.Since(started), time.Second)
+
+	invoked := false
+	invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		invoked = true
+		return nil
+	}
+	intercept := UnaryClientInterceptor(injector, TargetCCXT)
+	require.NoError(t, intercept(ctx, "/ccxt/FetchTicker", nil, nil, nil, invoker))
+	assert.True(t, invoked)
+
+	invoked = false
+	_, err = injector.Inject(TargetCCXT, time.Minute, 10*time.Millisecond)
+	require.NoError(t, err)
+	err = intercept(ctx, "/ccxt/FetchTicker", nil, nil, nil, invoker)
+	assert.ErrorIs(t, err, ErrInjected)
+	assert.False(t, invoked)
+}
@@ -38,6 +38,8 @@ type Config struct {
 	MarketData MarketDataConfig `mapstructure:"market_data"`
 	// Arbitrage holds configuration for arbitrage detection logic.
 	Arbitrage ArbitrageConfig `mapstructure:"arbitrage"`
+	// CustomAlerts holds configuration for user-defined price and indicator alerts.
+	CustomAlerts CustomAlertsConfig `mapstructure:"custom_alerts"`
 	// Blacklist holds configuration for the symbol blacklist mechanism.
 	Blacklist BlacklistConfig `mapstructure:"blacklist"`
 	// Auth holds configuration for authentication.
@@ -55,6 +57,12 @@ type Config struct {
 	AI AIConfig `mapstructure:"ai"`
 	// Features holds feature flags.
 	Features FeaturesConfig `mapstructure:"features"`
+	// Modes holds per-mode overlays (dry, paper, live) of the settings above,
+	// applied by ForMode.
+	Modes map[string]map[string]interface{} `mapstructure:"modes"`
+
+	// settings are the loaded settings the mode overlays are merged over.
+	settings map[string]interface{}
 }
 
 // ServerConfig defines the HTTP server settings.
@@ -63,6 +71,15 @@ type ServerConfig struct {
 	Port int `mapstructure:"port"`
 	// AllowedOrigins is a list of CORS allowed origins.
 	AllowedOrigins []string `mapstructure:"allowed_origins"`
+	// DrainBudgetSeconds bounds a graceful shutdown, from SIGTERM to exit.
+	DrainBudgetSeconds int `mapstructure:"drain_budget_seconds"`
+	// ReadinessDelaySeconds is how long the server keeps serving after
+	// readiness turns false, so load balancers stop routing to it first.
+	ReadinessDelaySeconds int `mapstructure:"readiness_delay_seconds"`
+	// TrustedProxies lists the proxy IPs or CIDRs whose X-Forwarded-For
+	// header is believed. Empty trusts none, so client IPs come from the
+	// connection itself.
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 // DatabaseConfig defines the PostgreSQL database connection settings.
@@ -82,6 +99,12 @@ type DatabaseConfig struct {
 	SSLMode string `mapstructure:"sslmode"`
 	// DatabaseURL is a connection string that can override individual fields.
 	DatabaseURL string `mapstructure:"database_url"`
+	// ReplicaURL is an optional PostgreSQL read replica for analytics and reporting reads.
+	ReplicaURL string `mapstructure:"replica_url"`
+	// CollectorMaxConns gives the market data collector its own pool of this size (0 shares the API pool).
+	CollectorMaxConns int `mapstructure:"collector_max_conns"`
+	// AnalyticsMaxConns sizes the analytics pool, on the replica when configured (0 shares the API pool).
+	AnalyticsMaxConns int `mapstructure:"analytics_max_conns"`
 	// MaxOpenConns is the maximum number of open connections.
 	MaxOpenConns int `mapstructure:"max_open_conns"`
 	// MaxIdleConns is the maximum number of idle connections.
@@ -142,6 +165,42 @@ type CCXTConfig struct {
 	Timeout int `mapstructure:"timeout"`
 	// AdminAPIKey is the API key for authenticating with admin endpoints.
 	AdminAPIKey string `mapstructure:"admin_api_key"`
+	// FixtureMode records CCXT service responses to fixture files ("record")
+	// or serves them from fixtures without network access ("replay").
+	FixtureMode string `mapstructure:"fixture_mode"`
+	// FixtureDir is the directory holding the CCXT fixture files.
+	FixtureDir string `mapstructure:"fixture_dir"`
+	// Synthetic replaces the CCXT service with generated market data.
+	Synthetic SyntheticMarketConfig `mapstructure:"synthetic"`
+}
+
+// SyntheticMarketConfig defines the generated market used to run the stack
+// without exchange connectivity. Prices follow geometric Brownian motion and
+// occasionally diverge between exchanges to create arbitrage spreads.
+type SyntheticMarketConfig struct {
+	// Enabled serves all CCXT requests from the generator.
+	Enabled bool `mapstructure:"enabled"`
+	// Exchanges are the simulated exchanges.
+	Exchanges []string `mapstructure:"exchanges"`
+	// Symbols are the simulated trading pairs.
+	Symbols []string `mapstructure:"symbols"`
+	// Volatility is the annualized volatility of prices (0.8 = 80%).
+	Volatility float64 `mapstructure:"volatility"`
+	// Drift is the annualized trend of prices (0.2 = +20% a year).
+	Drift float64 `mapstructure:"drift"`
+	// TimeScale is how many simulated seconds pass per real second.
+	TimeScale float64 `mapstructure:"time_scale"`
+	// SpreadBps is the bid/ask spread in basis points.
+	SpreadBps float64 `mapstructure:"spread_bps"`
+	// ArbitrageSpreadPct is the price gap injected on one exchange during an
+	// arbitrage episode.
+	ArbitrageSpreadPct float64 `mapstructure:"arbitrage_spread_pct"`
+	// ArbitrageChance is the probability that a price update starts an episode.
+	ArbitrageChance float64 `mapstructure:"arbitrage_chance"`
+	// ArbitrageDurationSeconds is how long an episode lasts.
+	ArbitrageDurationSeconds int `mapstructure:"arbitrage_duration_seconds"`
+	// Seed makes the generated data reproducible (0 seeds from the clock).
+	Seed int64 `mapstructure:"seed"`
 }
 
 // TelegramConfig defines settings for the Telegram notification bot.
@@ -248,6 +307,42 @@ type MarketDataConfig struct {
 	Timeout string `mapstructure:"timeout"`
 	// Exchanges is a list of exchange names to collect data from.
 	Exchanges []string `mapstructure:"exchanges"`
+	// WriteBatchSize is the number of buffered rows per exchange that triggers a database write.
+	WriteBatchSize int `mapstructure:"write_batch_size"`
+	// WriteFlushInterval is the time string for the longest a buffered row waits before it is written.
+	WriteFlushInterval string `mapstructure:"write_flush_interval"`
+	// WriteBufferSize is the maximum number of buffered rows per exchange before the oldest are dropped.
+	WriteBufferSize int `mapstructure:"write_buffer_size"`
+	// AnomalyWindowSize is the number of recent prices per market that incoming ticks are compared with.
+	AnomalyWindowSize int `mapstructure:"anomaly_window_size"`
+	// AnomalyMaxDeviation is the modified z-score (median absolute deviations from the median) above which a tick is quarantined.
+	AnomalyMaxDeviation float64 `mapstructure:"anomaly_max_deviation"`
+	// AnomalyMinJumpPct is the smallest move from the median, in percent, that can be quarantined.
+	AnomalyMinJumpPct float64 `mapstructure:"anomaly_min_jump_pct"`
+	// AnomalyMaxJumpPct is the largest move from the last price, in percent, allowed before enough history exists.
+	AnomalyMaxJumpPct float64 `mapstructure:"anomaly_max_jump_pct"`
+	// AnomalyMaxSpreadPct is the widest bid/ask spread, in percent of the mid price, before a tick is quarantined.
+	AnomalyMaxSpreadPct float64 `mapstructure:"anomaly_max_spread_pct"`
+	// AnomalyConfirmTicks is the number of consecutive outliers accepted as a genuine price move.
+	AnomalyConfirmTicks int `mapstructure:"anomaly_confirm_ticks"`
+	// PositioningInterval is the time string for how often open interest and long/short ratios are collected.
+	PositioningInterval string `mapstructure:"positioning_interval"`
+	// PositioningPeriod is the exchange period the long/short account ratio is aggregated over.
+	PositioningPeriod string `mapstructure:"positioning_period"`
+	// PositioningMaxSymbols is the maximum number of perps per exchange positioning is collected for.
+	PositioningMaxSymbols int `mapstructure:"positioning_max_symbols"`
+	// PartitionEnabled splits collection between backend replicas through Redis leases.
+	PartitionEnabled bool `mapstructure:"partition_enabled"`
+	// PartitionShards is the number of symbol partitions per exchange.
+	PartitionShards int `mapstructure:"partition_shards"`
+	// PartitionLeaseTTL is the time string after which the partitions of a silent replica move to the others.
+	PartitionLeaseTTL string `mapstructure:"partition_lease_ttl"`
+	// FreshnessTickerSLA is the time string for the oldest a pair's last ticker may be before signals skip it.
+	FreshnessTickerSLA string `mapstructure:"freshness_ticker_sla"`
+	// FreshnessCandleSLA is the time string for the oldest a pair's last fetched candle may be.
+	FreshnessCandleSLA string `mapstructure:"freshness_candle_sla"`
+	// FreshnessSLAOverrides sets per-symbol ticker SLAs, e.g. "BTC/USDT=5m,binance:ETH/USDT=2m".
+	FreshnessSLAOverrides string `mapstructure:"freshness_sla_overrides"`
 }
 
 // ArbitrageConfig defines settings for arbitrage detection.
@@ -268,6 +363,24 @@ type ArbitrageConfig struct {
 	MaxAgeMinutes int `mapstructure:"max_age_minutes"`
 	// BatchSize is the processing batch size.
 	BatchSize int `mapstructure:"batch_size"`
+	// LifecycleEnabled persists opportunities with their detected, notified,
+	// executed and expired states.
+	LifecycleEnabled bool `mapstructure:"lifecycle_enabled"`
+	// LifecycleRetentionHours is how long closed opportunity lifecycles are kept.
+	LifecycleRetentionHours int `mapstructure:"lifecycle_retention_hours"`
+}
+
+// CustomAlertsConfig defines settings for user-defined price and indicator alerts.
+type CustomAlertsConfig struct {
+	// Enabled evaluates the alerts users create from Telegram, the API and the CLI.
+	Enabled bool `mapstructure:"enabled"`
+	// DefaultExchange prices rules that do not name an exchange.
+	DefaultExchange string `mapstructure:"default_exchange"`
+	// MaxPerUser caps the alerts one user can create.
+	MaxPerUser int `mapstructure:"max_per_user"`
+	// Interval is the string duration between evaluations when the signal
+	// processor, which otherwise evaluates them each cycle, is disabled.
+	Interval string `mapstructure:"interval"`
 }
 
 // BlacklistConfig defines settings for the symbol blacklist.
@@ -282,6 +395,8 @@ type BlacklistConfig struct {
 	UseRedis bool `mapstructure:"use_redis"`
 	// RetryAfterClear controls whether to immediately retry symbols after they expire.
 	RetryAfterClear bool `mapstructure:"retry_after_clear"`
+	// MaxEntries caps the in-memory blacklist used when Redis is unavailable.
+	MaxEntries int `mapstructure:"max_entries"`
 }
 
 // AuthConfig defines authentication settings.
@@ -396,6 +511,7 @@ func Load() (*Config, error) {
 
 	// Bind standard DATABASE_URL
 	_ = viper.BindEnv("database.database_url", "DATABASE_URL")
+	_ = viper.BindEnv("database.replica_url", "DATABASE_REPLICA_URL")
 	_ = viper.BindEnv("database.driver", "DATABASE_DRIVER")
 	_ = viper.BindEnv("database.sqlite_path", "SQLITE_PATH")
 	_ = viper.BindEnv("database.sqlite_vector_extension_path", "SQLITE_VEC_EXTENSION_PATH")
@@ -412,12 +528,31 @@ func Load() (*Config, error) {
 	_ = viper.BindEnv("ccxt.service_url", "CCXT_SERVICE_URL")
 	_ = viper.BindEnv("ccxt.grpc_address", "CCXT_GRPC_ADDRESS")
 	_ = viper.BindEnv("ccxt.admin_api_key", "ADMIN_API_KEY")
+	_ = viper.BindEnv("ccxt.fixture_mode", "CCXT_FIXTURE_MODE")
+	_ = viper.BindEnv("ccxt.fixture_dir", "CCXT_FIXTURE_DIR")
+	_ = viper.BindEnv("ccxt.synthetic.enabled", "CCXT_SYNTHETIC_ENABLED")
+	_ = viper.BindEnv("ccxt.synthetic.exchanges", "CCXT_SYNTHETIC_EXCHANGES")
+	_ = viper.BindEnv("ccxt.synthetic.symbols", "CCXT_SYNTHETIC_SYMBOLS")
+	_ = viper.BindEnv("ccxt.synthetic.volatility", "CCXT_SYNTHETIC_VOLATILITY")
+	_ = viper.BindEnv("ccxt.synthetic.drift", "CCXT_SYNTHETIC_DRIFT")
+	_ = viper.BindEnv("ccxt.synthetic.time_scale", "CCXT_SYNTHETIC_TIME_SCALE")
+	_ = viper.BindEnv("ccxt.synthetic.spread_bps", "CCXT_SYNTHETIC_SPREAD_BPS")
+	_ = viper.BindEnv("ccxt.synthetic.arbitrage_spread_pct", "CCXT_SYNTHETIC_ARBITRAGE_SPREAD_PCT")
+	_ = viper.BindEnv("ccxt.synthetic.arbitrage_chance", "CCXT_SYNTHETIC_ARBITRAGE_CHANCE")
+	_ = viper.BindEnv("ccxt.synthetic.arbitrage_duration_seconds", "CCXT_SYNTHETIC_ARBITRAGE_DURATION_SECONDS")
+	_ = viper.BindEnv("ccxt.synthetic.seed", "CCXT_SYNTHETIC_SEED")
 
 	// Bind Telegram service environment variables
 	_ = viper.BindEnv("telegram.service_url", "TELEGRAM_SERVICE_URL")
 	_ = viper.BindEnv("telegram.grpc_address", "TELEGRAM_GRPC_ADDRESS")
 	_ = viper.BindEnv("backfill.enabled", "BACKFILL_ENABLED")
 	_ = viper.BindEnv("arbitrage.enabled", "ARBITRAGE_ENABLED")
+	_ = viper.BindEnv("arbitrage.lifecycle_enabled", "ARBITRAGE_LIFECYCLE_ENABLED")
+	_ = viper.BindEnv("arbitrage.lifecycle_retention_hours", "ARBITRAGE_LIFECYCLE_RETENTION_HOURS")
+	_ = viper.BindEnv("custom_alerts.enabled", "CUSTOM_ALERTS_ENABLED")
+	_ = viper.BindEnv("custom_alerts.default_exchange", "CUSTOM_ALERTS_DEFAULT_EXCHANGE")
+	_ = viper.BindEnv("custom_alerts.max_per_user", "CUSTOM_ALERTS_MAX_PER_USER")
+	_ = viper.BindEnv("custom_alerts.interval", "CUSTOM_ALERTS_INTERVAL")
 	_ = viper.BindEnv("features.enable_ai_arbitrage", "ENABLE_AI_ARBITRAGE")
 	_ = viper.BindEnv("features.enable_ai_signals", "ENABLE_AI_SIGNALS")
 
@@ -434,6 +569,8 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	config.settings = settingsSnapshot()
+
 	// Sanitize Sentry DSN (remove surrounding spaces)
 	if config.Sentry.DSN != "" {
 		config.Sentry.DSN = strings.TrimSpace(config.Sentry.DSN)
@@ -465,8 +602,15 @@ func setDefaults() {
 	viper.SetDefault("server.port", 8080)
 	viper.SetDefault("server.allowed_origins", []string{"http://localhost:3000"})
 
+	viper.SetDefault("server.drain_budget_seconds", 30)
+	viper.SetDefault("server.readiness_delay_seconds", 5)
+	viper.SetDefault("server.trusted_proxies", []string{})
+
 	// Bind PORT env to server.port
 	_ = viper.BindEnv("server.port", "PORT")
+	_ = viper.BindEnv("server.drain_budget_seconds", "SHUTDOWN_DRAIN_BUDGET_SECONDS")
+	_ = viper.BindEnv("server.readiness_delay_seconds", "SHUTDOWN_READINESS_DELAY_SECONDS")
+	_ = viper.BindEnv("server.trusted_proxies", "TRUSTED_PROXIES")
 
 	// Set database defaults - SQLite by default
 	viper.SetDefault("database.driver", "sqlite")
@@ -478,6 +622,9 @@ func setDefaults() {
 	viper.SetDefault("database.dbname", "neuratrade")
 	viper.SetDefault("database.sslmode", "disable")
 	viper.SetDefault("database.database_url", "")
+	viper.SetDefault("database.replica_url", "")
+	viper.SetDefault("database.collector_max_conns", 0)
+	viper.SetDefault("database.analytics_max_conns", 0)
 	viper.SetDefault("database.max_open_conns", 25)
 	viper.SetDefault("database.max_idle_conns", 5)
 	viper.SetDefault("database.conn_max_lifetime", "300s")
@@ -520,6 +667,17 @@ func setDefaults() {
 		viper.SetDefault("ccxt.grpc_address", "127.0.0.1:50051")
 	}
 	viper.SetDefault("ccxt.timeout", 30)
+	viper.SetDefault("ccxt.fixture_dir", "testdata/ccxt-fixtures")
+	viper.SetDefault("ccxt.synthetic.enabled", false)
+	viper.SetDefault("ccxt.synthetic.exchanges", []string{"binance", "bybit", "okx"})
+	viper.SetDefault("ccxt.synthetic.symbols", []string{"BTC/USDT", "ETH/USDT", "SOL/USDT", "BNB/USDT"})
+	viper.SetDefault("ccxt.synthetic.volatility", 0.8)
+	viper.SetDefault("ccxt.synthetic.drift", 0.0)
+	viper.SetDefault("ccxt.synthetic.time_scale", 60.0)
+	viper.SetDefault("ccxt.synthetic.spread_bps", 2.0)
+	viper.SetDefault("ccxt.synthetic.arbitrage_spread_pct", 0.8)
+	viper.SetDefault("ccxt.synthetic.arbitrage_chance", 0.02)
+	viper.SetDefault("ccxt.synthetic.arbitrage_duration_seconds", 120)
 
 	// Telegram - Use Docker service names when running in Docker/Coolify
 	// Note: These defaults can be overridden by explicit env vars (TELEGRAM_SERVICE_URL, TELEGRAM_GRPC_ADDRESS)
@@ -575,6 +733,24 @@ func setDefaults() {
 	viper.SetDefault("market_data.max_retries", 3)
 	viper.SetDefault("market_data.timeout", "15s")
 	viper.SetDefault("market_data.exchanges", []string{"binance", "coinbase", "kraken", "bitfinex", "huobi"})
+	viper.SetDefault("market_data.write_batch_size", 500)
+	viper.SetDefault("market_data.write_flush_interval", "1s")
+	viper.SetDefault("market_data.write_buffer_size", 10000)
+	viper.SetDefault("market_data.anomaly_window_size", 20)
+	viper.SetDefault("market_data.anomaly_max_deviation", 5.0)
+	viper.SetDefault("market_data.anomaly_min_jump_pct", 2.0)
+	viper.SetDefault("market_data.anomaly_max_jump_pct", 20.0)
+	viper.SetDefault("market_data.anomaly_max_spread_pct", 5.0)
+	viper.SetDefault("market_data.anomaly_confirm_ticks", 3)
+	viper.SetDefault("market_data.positioning_interval", "15m")
+	viper.SetDefault("market_data.positioning_period", "5m")
+	viper.SetDefault("market_data.positioning_max_symbols", 50)
+	viper.SetDefault("market_data.partition_enabled", false)
+	viper.SetDefault("market_data.partition_shards", 4)
+	viper.SetDefault("market_data.partition_lease_ttl", "15s")
+	viper.SetDefault("market_data.freshness_ticker_sla", "15m")
+	viper.SetDefault("market_data.freshness_candle_sla", "1h")
+	viper.SetDefault("market_data.freshness_sla_overrides", "")
 
 	// Arbitrage
 	viper.SetDefault("arbitrage.enabled", true)
@@ -582,16 +758,25 @@ func setDefaults() {
 	viper.SetDefault("arbitrage.min_profit_threshold", 0.5)
 	viper.SetDefault("arbitrage.max_age_minutes", 30)
 	viper.SetDefault("arbitrage.batch_size", 100)
+	viper.SetDefault("arbitrage.lifecycle_enabled", true)
+	viper.SetDefault("arbitrage.lifecycle_retention_hours", 720)
 	viper.SetDefault("arbitrage.max_trade_amount", 1000.0)
 	viper.SetDefault("arbitrage.check_interval", "2m")
 	viper.SetDefault("arbitrage.enabled_pairs", []string{"BTC/USDT", "ETH/USDT", "BNB/USDT", "ADA/USDT"})
 
+	// Custom alerts
+	viper.SetDefault("custom_alerts.enabled", true)
+	viper.SetDefault("custom_alerts.default_exchange", "binance")
+	viper.SetDefault("custom_alerts.max_per_user", 20)
+	viper.SetDefault("custom_alerts.interval", "1m")
+
 	// Blacklist
 	viper.SetDefault("blacklist.ttl", "24h")
 	viper.SetDefault("blacklist.short_ttl", "1h")
 	viper.SetDefault("blacklist.long_ttl", "72h")
 	viper.SetDefault("blacklist.use_redis", true)
 	viper.SetDefault("blacklist.retry_after_clear", true)
+	viper.SetDefault("blacklist.max_entries", 10000)
 
 	// Auth
 	viper.SetDefault("auth.jwt_secret", "")
@@ -673,6 +858,10 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("database.sqlite_path is required when database.driver=sqlite")
 	}
 
+	if err := validateModes(config); err != nil {
+		return err
+	}
+
 	// Validate JWT secret for production environments
 	if config.Environment == "production" || config.Environment == "staging" {
 		if config.Auth.JWTSecret == "" {
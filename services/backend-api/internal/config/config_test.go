@@ -327,6 +327,32 @@ func TestLoad_SQLiteDriverRejectsWhitespacePath(t *testing.T) {
 	assert.ErrorContains(t, err, "database.sqlite_path is required")
 }
 
+func TestLoad_DatabaseReplicaAndWorkloadPools(t *testing.T) {
+	os.Clearenv()
+	t.Setenv("DATABASE_DRIVER", "postgres")
+	t.Setenv("DATABASE_REPLICA_URL", "postgres://reader@replica:5432/neuratrade")
+	t.Setenv("DATABASE_COLLECTOR_MAX_CONNS", "5")
+	t.Setenv("DATABASE_ANALYTICS_MAX_CONNS", "3")
+
+	config, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://reader@replica:5432/neuratrade", config.Database.ReplicaURL)
+	assert.Equal(t, 5, config.Database.CollectorMaxConns)
+	assert.Equal(t, 3, config.Database.AnalyticsMaxConns)
+}
+
+func TestLoad_TrustedProxies(t *testing.T) {
+	os.Clearenv()
+	config, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, config.Server.TrustedProxies, "no proxy is trusted by default")
+
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8,172.16.0.1")
+	config, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.0/8", "172.16.0.1"}, config.Server.TrustedProxies)
+}
+
 func TestLoad_UserHomeDirConfig(t *testing.T) {
 	os.Clearenv()
 	t.Setenv("DATABASE_DRIVER", "postgres")
@@ -410,3 +436,58 @@ func TestLoad_NeuratradeConfigEnvTakesPrecedence(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "env-host", config.Database.Host)
 }
+
+func TestLoad_ModeOverlays(t *testing.T) {
+	os.Clearenv()
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	require.NoError(t, os.MkdirAll(tempDir+"/.neuratrade", 0755))
+	configContent := `{
+		"arbitrage": {"max_trade_amount": 1000, "enabled_pairs": ["BTC/USDT", "ETH/USDT"]},
+		"modes": {
+			"live": {"arbitrage": {"max_trade_amount": 250, "enabled_pairs": ["BTC/USDT"]}},
+			"dry": {"arbitrage": {"max_trade_amount": 50000}, "ai": {"daily_budget": 0}}
+		}
+	}`
+	require.NoError(t, os.WriteFile(tempDir+"/.neuratrade/config.json", []byte(configContent), 0644))
+	t.Setenv("ARBITRAGE_MIN_PROFIT_THRESHOLD", "0.8")
+
+	config, err := Load()
+	require.NoError(t, err)
+
+	live, err := config.ForMode(ModeLive)
+	require.NoError(t, err)
+	assert.Equal(t, 250.0, live.Arbitrage.MaxTradeAmount)
+	assert.Equal(t, []string{"BTC/USDT"}, live.Arbitrage.EnabledPairs)
+	assert.Equal(t, 0.8, live.Arbitrage.MinProfitThreshold, "settings outside the overlay keep their loaded value")
+
+	dry, err := config.ForMode(ModeDry)
+	require.NoError(t, err)
+	assert.Equal(t, 50000.0, dry.Arbitrage.MaxTradeAmount)
+	assert.Equal(t, []string{"BTC/USDT", "ETH/USDT"}, dry.Arbitrage.EnabledPairs)
+	assert.Equal(t, 0.0, dry.AI.DailyBudget)
+
+	paper, err := config.ForMode(ModePaper)
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0, paper.Arbitrage.MaxTradeAmount)
+	assert.Equal(t, 1000.0, config.Arbitrage.MaxTradeAmount, "resolving a mode leaves the loaded config alone")
+
+	settings, overridden, err := config.ModeSettings(ModeDry)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ai.daily_budget", "arbitrage.max_trade_amount"}, overridden)
+	assert.NotContains(t, settings, "modes")
+
+	_, err = config.ForMode("yolo")
+	assert.ErrorIs(t, err, ErrUnknownMode)
+}
+
+func TestLoad_RejectsInvalidModeOverlays(t *testing.T) {
+	os.Clearenv()
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	require.NoError(t, os.MkdirAll(tempDir+"/.neuratrade", 0755))
+	require.NoError(t, os.WriteFile(tempDir+"/.neuratrade/config.json", []byte(`{"modes": {"staging": {"log_level": "debug"}}}`), 0644))
+
+	_, err := Load()
+	assert.ErrorIs(t, err, ErrUnknownMode)
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Modes the configuration can be overlaid for.
+const (
+	// ModeDry simulates market data and never trades.
+	ModeDry = "dry"
+	// ModePaper trades against real market data without placing orders.
+	ModePaper = "paper"
+	// ModeLive places real orders.
+	ModeLive = "live"
+)
+
+// ErrUnknownMode is returned for a mode other than dry, paper or live.
+var ErrUnknownMode = errors.New("unknown mode: must be dry, paper or live")
+
+// modesKey is the config section holding the per-mode overlays, e.g.
+//
+//	modes:
+//	  live:
+//	    arbitrage:
+//	      max_trade_amount: 250
+const modesKey = "modes"
+
+// ValidMode reports whether mode is dry, paper or live.
+func ValidMode(mode string) bool {
+	return mode == ModeDry || mode == ModePaper || mode == ModeLive
+}
+
+// ForMode returns the configuration with the overlay of a mode applied. The
+// overlay takes precedence over the config file and environment variables,
+// so it can tighten caps that are set globally.
+//
+// Parameters:
+//
+//	mode: dry, paper or live.
+//
+// Returns:
+//
+//	*Config: The effective configuration; the receiver is not modified.
+//	error: ErrUnknownMode or an overlay that does not fit the configuration.
+func (c *Config) ForMode(mode string) (*Config, error) {
+	v, _, err := c.resolveMode(mode)
+	if err != nil {
+		return nil, err
+	}
+	var resolved Config
+	if err := v.Unmarshal(&resolved); err != nil {
+		return nil, fmt.Errorf("invalid %s overlay: %w", mode, err)
+	}
+	// Load derives these after decoding; overlays do not set them
+	resolved.Sentry.DSN = strings.TrimSpace(resolved.Sentry.DSN)
+	if resolved.Auth.JWTSecret == "" {
+		resolved.Auth.JWTSecret = c.Auth.JWTSecret
+	}
+	resolved.Modes = c.Modes
+	resolved.settings = c.settings
+	return &resolved, nil
+}
+
+// ModeSettings returns the effective settings of a mode as nested maps keyed
+// like the config file, with the dotted keys the overlay changed.
+//
+// Parameters:
+//
+//	mode: dry, paper or live.
+//
+// Returns:
+//
+//	map[string]interface{}: Effective settings, without the overlays themselves.
+//	[]string: Sorted dotted keys set by the mode's overlay.
+//	error: ErrUnknownMode.
+func (c *Config) ModeSettings(mode string) (map[string]interface{}, []string, error) {
+	v, overridden, err := c.resolveMode(mode)
+	if err != nil {
+		return nil, nil, err
+	}
+	return v.AllSettings(), overridden, nil
+}
+
+// resolveMode merges a mode's overlay over the loaded settings.
+func (c *Config) resolveMode(mode string) (*viper.Viper, []string, error) {
+	if !ValidMode(mode) {
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnknownMode, mode)
+	}
+	// viper keeps the nested maps it merges, so each mode gets its own copy
+	v := viper.New()
+	if err := v.MergeConfigMap(deepCopySettings(c.settings)); err != nil {
+		return nil, nil, fmt.Errorf("failed to load settings: %w", err)
+	}
+	overlay := c.Modes[mode]
+	if len(overlay) == 0 {
+		return v, []string{}, nil
+	}
+	if err := v.MergeConfigMap(deepCopySettings(overlay)); err != nil {
+		return nil, nil, fmt.Errorf("invalid %s overlay: %w", mode, err)
+	}
+	overridden := make([]string, 0)
+	collectOverlayKeys("", overlay, &overridden)
+	sort.Strings(overridden)
+	return v, overridden, nil
+}
+
+// collectOverlayKeys flattens an overlay into dotted keys.
+func collectOverlayKeys(prefix string, overlay map[string]interface{}, keys *[]string) {
+	for key, value := range overlay {
+		key = strings.ToLower(key)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			collectOverlayKeys(key, nested, keys)
+			continue
+		}
+		*keys = append(*keys, key)
+	}
+}
+
+// validateModes rejects overlays for unknown modes and overlays that do not
+// decode into the configuration.
+func validateModes(config *Config) error {
+	for mode := range config.Modes {
+		if _, err := config.ForMode(mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// settingsSnapshot copies the loaded settings without the overlays.
+func settingsSnapshot() map[string]interface{} {
+	settings := viper.AllSettings()
+	delete(settings, modesKey)
+	return deepCopySettings(settings)
+}
+
+func deepCopySettings(settings map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(settings))
+	for key, value := range settings {
+		if nested, ok := value.(map[string]interface{}); ok {
+			copied[key] = deepCopySettings(nested)
+			continue
+		}
+		if rv := reflect.ValueOf(value); rv.Kind() == reflect.Slice {
+			slice := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+			reflect.Copy(slice, rv)
+			copied[key] = slice.Interface()
+			continue
+		}
+		copied[key] = value
+	}
+	return copied
+}
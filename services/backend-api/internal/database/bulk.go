@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrBulkCopyUnsupported is returned by CopyRows when the connection has no COPY support.
+var ErrBulkCopyUnsupported = errors.New("bulk copy is not supported by this connection")
+
+// BulkCopier is implemented by connections that can load rows with COPY.
+type BulkCopier interface {
+	CopyRows(ctx context.Context, table string, columns []string, rows [][]any) (int64, error)
+}
+
+// CopyRows loads rows into table with the PostgreSQL COPY protocol.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	table: Target table.
+//	columns: Target columns, in the order of each row's values.
+//	rows: Row values.
+//
+// Returns:
+//
+//	int64: Number of rows copied.
+//	error: Error if the copy fails.
+func (db *PostgresDB) CopyRows(ctx context.Context, table string, columns []string, rows [][]any) (int64, error) {
+	if db.Pool == nil {
+		return 0, fmt.Errorf("postgres pool is not initialized")
+	}
+	return db.Pool.CopyFrom(ctx, pgx.Identifier{table}, columns, pgx.CopyFromRows(rows))
+}
+
+// CopyRows copies on the pool that would run the workload's writes.
+func (r *QueryRouter) CopyRows(ctx context.Context, table string, columns []string, rows [][]any) (int64, error) {
+	copier, ok := r.writer(ctx).(BulkCopier)
+	if !ok {
+		return 0, ErrBulkCopyUnsupported
+	}
+	return copier.CopyRows(ctx, table, columns, rows)
+}
+
+func (w workloadDB) CopyRows(ctx context.Context, table string, columns []string, rows [][]any) (int64, error) {
+	copier, ok := w.db.(BulkCopier)
+	if !ok {
+		return 0, ErrBulkCopyUnsupported
+	}
+	return copier.CopyRows(WithWorkload(ctx, w.workload), table, columns, rows)
+}
@@ -64,6 +64,9 @@ func NewDatabaseConnectionWithContext(ctx context.Context, cfg *config.DatabaseC
 
 	switch driver {
 	case "sqlite":
+		if cfg.ReplicaURL != "" || cfg.CollectorMaxConns > 0 || cfg.AnalyticsMaxConns > 0 {
+			zaplogrus.Warnf("Read replica and per-workload pools are ignored with the SQLite driver")
+		}
 		path := cfg.SQLitePath
 		if path == "" {
 			path = "neuratrade.db"
@@ -73,13 +76,59 @@ func NewDatabaseConnectionWithContext(ctx context.Context, cfg *config.DatabaseC
 
 	case "postgres", "postgresql":
 		zaplogrus.Infof("Connecting to PostgreSQL database: %s@%s:%d/%s", cfg.User, cfg.Host, cfg.Port, cfg.DBName)
-		return NewPostgresConnectionWithContext(ctx, cfg)
+		primary, err := NewPostgresConnectionWithContext(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.ReplicaURL == "" && cfg.CollectorMaxConns <= 0 && cfg.AnalyticsMaxConns <= 0 {
+			return primary, nil
+		}
+
+		router := NewQueryRouter(primary)
+		if cfg.CollectorMaxConns > 0 {
+			router.SetPool(WorkloadCollector, connectWorkloadPool(ctx, cfg, WorkloadCollector, "", cfg.CollectorMaxConns))
+		}
+		if cfg.ReplicaURL != "" {
+			router.SetReplica(WorkloadAnalytics, connectWorkloadPool(ctx, cfg, WorkloadAnalytics, cfg.ReplicaURL, cfg.AnalyticsMaxConns))
+		} else if cfg.AnalyticsMaxConns > 0 {
+			router.SetPool(WorkloadAnalytics, connectWorkloadPool(ctx, cfg, WorkloadAnalytics, "", cfg.AnalyticsMaxConns))
+		}
+		return router, nil
 
 	default:
 		return nil, fmt.Errorf("unsupported database driver: %s (supported: sqlite, postgres)", driver)
 	}
 }
 
+// connectWorkloadPool opens a dedicated pool for a workload class, on the
+// database at url or on the primary when url is empty. A pool that cannot be
+// opened is logged and skipped so the workload falls back to the primary
+// instead of blocking startup.
+func connectWorkloadPool(ctx context.Context, cfg *config.DatabaseConfig, workload Workload, url string, maxConns int) Database {
+	poolCfg := *cfg
+	if url != "" {
+		poolCfg.Host = ""
+		poolCfg.DatabaseURL = url
+	}
+	if maxConns > 0 {
+		poolCfg.MaxOpenConns = maxConns
+		if poolCfg.MaxIdleConns > maxConns {
+			poolCfg.MaxIdleConns = maxConns
+		}
+	}
+	if poolCfg.ApplicationName != "" {
+		poolCfg.ApplicationName += "-" + string(workload)
+	}
+
+	zaplogrus.Infof("Connecting PostgreSQL pool for %s workload", workload)
+	pool, err := NewPostgresConnectionWithContext(ctx, &poolCfg)
+	if err != nil {
+		zaplogrus.Warnf("Pool for %s workload unavailable, routing its queries to the primary: %v", workload, err)
+		return nil
+	}
+	return pool
+}
+
 // DetectDBType detects the database type from the driver string.
 func DetectDBType(driver string) DBType {
 	driver = strings.ToLower(strings.TrimSpace(driver))
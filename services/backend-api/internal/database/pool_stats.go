@@ -0,0 +1,43 @@
+package database
+
+import "time"
+
+// PoolStats is a point-in-time snapshot of a connection pool.
+// The acquire counters and durations are cumulative since the pool was created.
+type PoolStats struct {
+	Name                 string        `json:"name"`
+	MaxConns             int32         `json:"max_conns"`
+	TotalConns           int32         `json:"total_conns"`
+	InUseConns           int32         `json:"in_use_conns"`
+	IdleConns            int32         `json:"idle_conns"`
+	AcquireCount         int64         `json:"acquire_count"`
+	EmptyAcquireCount    int64         `json:"empty_acquire_count"`
+	CanceledAcquireCount int64         `json:"canceled_acquire_count"`
+	AcquireDuration      time.Duration `json:"acquire_duration_ns"`
+	EmptyAcquireWaitTime time.Duration `json:"empty_acquire_wait_ns"`
+}
+
+// PoolStatsProvider is implemented by connections backed by a pgx pool.
+type PoolStatsProvider interface {
+	PoolStats() []PoolStats
+}
+
+// PoolStats returns the statistics of the pgx pool.
+func (db *PostgresDB) PoolStats() []PoolStats {
+	if db == nil || db.Pool == nil {
+		return nil
+	}
+	stat := db.Pool.Stat()
+	return []PoolStats{{
+		Name:                 "primary",
+		MaxConns:             stat.MaxConns(),
+		TotalConns:           stat.TotalConns(),
+		InUseConns:           stat.AcquiredConns(),
+		IdleConns:            stat.IdleConns(),
+		AcquireCount:         stat.AcquireCount(),
+		EmptyAcquireCount:    stat.EmptyAcquireCount(),
+		CanceledAcquireCount: stat.CanceledAcquireCount(),
+		AcquireDuration:      stat.AcquireDuration(),
+		EmptyAcquireWaitTime: stat.EmptyAcquireWaitTime(),
+	}}
+}
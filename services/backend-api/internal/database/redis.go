@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/irfndi/neuratrade/internal/chaos"
 	"github.com/irfndi/neuratrade/internal/config"
 	zaplogrus "github.com/irfndi/neuratrade/internal/logging/zaplogrus"
 	"github.com/redis/go-redis/v9"
@@ -91,6 +92,8 @@ func NewRedisConnectionWithRetry(cfg config.RedisConfig, errorRecoveryManager Er
 
 	// Add Sentry hook for error tracking
 	rdb.AddHook(&RedisSentryHook{})
+	// Fail commands while a redis chaos fault is injected
+	rdb.AddHook(chaos.NewRedisHook(chaos.Default()))
 
 	// Test the connection with retry logic if available
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
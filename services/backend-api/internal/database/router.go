@@ -0,0 +1,233 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+)
+
+// Workload classifies database traffic so that each class can get its own
+// connection pool and cannot starve the others.
+type Workload string
+
+const (
+	// WorkloadAPI is request handling and trading; it always uses the primary pool.
+	WorkloadAPI Workload = "api"
+	// WorkloadCollector is background market data collection.
+	WorkloadCollector Workload = "collector"
+	// WorkloadAnalytics is analytics, export and reporting reads that tolerate
+	// replication lag and may be served by a read replica.
+	WorkloadAnalytics Workload = "analytics"
+)
+
+type workloadKey struct{}
+
+// WithWorkload tags a context with the workload class of the queries issued under it.
+//
+// Parameters:
+//
+//	ctx: Parent context.
+//	workload: Workload class.
+//
+// Returns:
+//
+//	context.Context: Tagged context.
+func WithWorkload(ctx context.Context, workload Workload) context.Context {
+	return context.WithValue(ctx, workloadKey{}, workload)
+}
+
+// WorkloadFromContext returns the workload class of ctx, WorkloadAPI by default.
+func WorkloadFromContext(ctx context.Context) Workload {
+	if workload, ok := ctx.Value(workloadKey{}).(Workload); ok {
+		return workload
+	}
+	return WorkloadAPI
+}
+
+type routedPool struct {
+	db       Database
+	readOnly bool
+}
+
+// QueryRouter sends queries to a per-workload pool when one is configured and
+// to the primary otherwise. Read-only pools (replicas) only serve reads;
+// their workload's writes and transactions still go to the primary.
+type QueryRouter struct {
+	primary Database
+	pools   map[Workload]routedPool
+}
+
+// Ensure QueryRouter implements Database interface.
+var _ Database = (*QueryRouter)(nil)
+
+// NewQueryRouter creates a router over the primary connection.
+//
+// Parameters:
+//
+//	primary: Connection used by the API workload and any workload without its own pool.
+//
+// Returns:
+//
+//	*QueryRouter: The initialized router.
+func NewQueryRouter(primary Database) *QueryRouter {
+	return &QueryRouter{primary: primary, pools: make(map[Workload]routedPool)}
+}
+
+// SetPool gives a workload a dedicated read-write pool on the primary database.
+func (r *QueryRouter) SetPool(workload Workload, db Database) {
+	if db != nil && workload != WorkloadAPI {
+		r.pools[workload] = routedPool{db: db}
+	}
+}
+
+// SetReplica sends a workload's reads to a read replica.
+func (r *QueryRouter) SetReplica(workload Workload, db Database) {
+	if db != nil && workload != WorkloadAPI {
+		r.pools[workload] = routedPool{db: db, readOnly: true}
+	}
+}
+
+// Primary returns the primary connection.
+func (r *QueryRouter) Primary() Database {
+	return r.primary
+}
+
+// Pool returns the dedicated pool of a workload, or nil if it shares the primary.
+func (r *QueryRouter) Pool(workload Workload) Database {
+	return r.pools[workload].db
+}
+
+func (r *QueryRouter) reader(ctx context.Context) Database {
+	if pool, ok := r.pools[WorkloadFromContext(ctx)]; ok {
+		return pool.db
+	}
+	return r.primary
+}
+
+func (r *QueryRouter) writer(ctx context.Context) Database {
+	if pool, ok := r.pools[WorkloadFromContext(ctx)]; ok && !pool.readOnly {
+		return pool.db
+	}
+	return r.primary
+}
+
+// Query runs a query on the workload's pool.
+func (r *QueryRouter) Query(ctx context.Context, query string, args ...any) (Rows, error) {
+	return r.reader(ctx).Query(ctx, query, args...)
+}
+
+// QueryRow runs a single-row query on the workload's pool.
+func (r *QueryRouter) QueryRow(ctx context.Context, query string, args ...any) Row {
+	return r.reader(ctx).QueryRow(ctx, query, args...)
+}
+
+// Exec runs a statement on the workload's pool, or the primary for replicas.
+func (r *QueryRouter) Exec(ctx context.Context, query string, args ...any) (Result, error) {
+	return r.writer(ctx).Exec(ctx, query, args...)
+}
+
+// Begin starts a transaction on the workload's pool, or the primary for replicas.
+func (r *QueryRouter) Begin(ctx context.Context) (Tx, error) {
+	return r.writer(ctx).Begin(ctx)
+}
+
+// BeginTx starts a transaction on the workload's pool, or the primary for replicas.
+func (r *QueryRouter) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	return r.writer(ctx).BeginTx(ctx)
+}
+
+// IsReady reports whether the primary is ready.
+func (r *QueryRouter) IsReady() bool {
+	return r.primary != nil && r.primary.IsReady()
+}
+
+// HealthCheck checks the primary only, so a lagging or unavailable replica
+// does not take trading down. Use PoolHealthCheck for the other pools.
+func (r *QueryRouter) HealthCheck(ctx context.Context) error {
+	return r.primary.HealthCheck(ctx)
+}
+
+// PoolHealthCheck checks the dedicated pool of a workload, if it has one.
+func (r *QueryRouter) PoolHealthCheck(ctx context.Context, workload Workload) error {
+	pool, ok := r.pools[workload]
+	if !ok {
+		return nil
+	}
+	return pool.db.HealthCheck(ctx)
+}
+
+// PoolStats returns the statistics of the primary, labelled as the API pool,
+// followed by each dedicated pool labelled with its workload.
+func (r *QueryRouter) PoolStats() []PoolStats {
+	stats := labelPoolStats(r.primary, string(WorkloadAPI))
+
+	workloads := make([]string, 0, len(r.pools))
+	for workload := range r.pools {
+		workloads = append(workloads, string(workload))
+	}
+	sort.Strings(workloads)
+	for _, workload := range workloads {
+		stats = append(stats, labelPoolStats(r.pools[Workload(workload)].db, workload)...)
+	}
+	return stats
+}
+
+func labelPoolStats(db Database, name string) []PoolStats {
+	provider, ok := db.(PoolStatsProvider)
+	if !ok {
+		return nil
+	}
+	stats := provider.PoolStats()
+	for i := range stats {
+		stats[i].Name = name
+	}
+	return stats
+}
+
+// Close closes the dedicated pools and the primary.
+func (r *QueryRouter) Close() error {
+	var errs []error
+	for _, pool := range r.pools {
+		errs = append(errs, pool.db.Close())
+	}
+	errs = append(errs, r.primary.Close())
+	return errors.Join(errs...)
+}
+
+// workloadDB tags every query with a fixed workload class.
+type workloadDB struct {
+	db       DBPool
+	workload Workload
+}
+
+// ForWorkload returns a DBPool that tags every query with workload, for
+// services such as the collector whose queries all belong to one class.
+//
+// Parameters:
+//
+//	db: Underlying connection, usually a QueryRouter.
+//	workload: Workload class.
+//
+// Returns:
+//
+//	DBPool: Tagging wrapper.
+func ForWorkload(db DBPool, workload Workload) DBPool {
+	return workloadDB{db: db, workload: workload}
+}
+
+func (w workloadDB) Query(ctx context.Context, query string, args ...any) (Rows, error) {
+	return w.db.Query(WithWorkload(ctx, w.workload), query, args...)
+}
+
+func (w workloadDB) QueryRow(ctx context.Context, query string, args ...any) Row {
+	return w.db.QueryRow(WithWorkload(ctx, w.workload), query, args...)
+}
+
+func (w workloadDB) Exec(ctx context.Context, query string, args ...any) (Result, error) {
+	return w.db.Exec(WithWorkload(ctx, w.workload), query, args...)
+}
+
+func (w workloadDB) Begin(ctx context.Context) (Tx, error) {
+	return w.db.Begin(WithWorkload(ctx, w.workload))
+}
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingDatabase struct {
+	calls    []string
+	closed   bool
+	healthy  error
+	closeErr error
+}
+
+func (d *recordingDatabase) Query(ctx context.Context, query string, args ...any) (Rows, error) {
+	d.calls = append(d.calls, "query")
+	return nil, nil
+}
+
+func (d *recordingDatabase) QueryRow(ctx context.Context, query string, args ...any) Row {
+	d.calls = append(d.calls, "query_row")
+	return nil
+}
+
+func (d *recordingDatabase) Exec(ctx context.Context, query string, args ...any) (Result, error) {
+	d.calls = append(d.calls, "exec")
+	return nil, nil
+}
+
+func (d *recordingDatabase) Begin(ctx context.Context) (Tx, error) {
+	d.calls = append(d.calls, "begin")
+	return nil, nil
+}
+
+func (d *recordingDatabase) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	d.calls = append(d.calls, "begin_tx")
+	return nil, nil
+}
+
+func (d *recordingDatabase) Close() error {
+	d.closed = true
+	return d.closeErr
+}
+
+func (d *recordingDatabase) IsReady() bool { return true }
+
+func (d *recordingDatabase) HealthCheck(ctx context.Context) error { return d.healthy }
+
+func TestQueryRouter_RoutesByWorkload(t *testing.T) {
+	primary := &recordingDatabase{}
+	replica := &recordingDatabase{}
+	collector := &recordingDatabase{}
+	router := NewQueryRouter(primary)
+	router.SetReplica(WorkloadAnalytics, replica)
+	router.SetPool(WorkloadCollector, collector)
+
+	ctx := context.Background()
+	analytics := WithWorkload(ctx, WorkloadAnalytics)
+	assert.Equal(t, WorkloadAPI, WorkloadFromContext(ctx))
+	assert.Equal(t, WorkloadAnalytics, WorkloadFromContext(analytics))
+
+	_, _ = router.Query(ctx, "SELECT 1")
+	_ = router.QueryRow(ctx, "SELECT 1")
+	_, _ = router.Query(analytics, "SELECT 1")
+	_ = router.QueryRow(analytics, "SELECT 1")
+	_, _ = router.Exec(analytics, "UPDATE t SET x = 1")
+	_, _ = router.Begin(analytics)
+	_, _ = router.BeginTx(analytics)
+
+	assert.Equal(t, []string{"query", "query_row", "exec", "begin", "begin_tx"}, primary.calls)
+	assert.Equal(t, []string{"query", "query_row"}, replica.calls, "replicas only serve reads")
+
+	collectorDB := ForWorkload(router, WorkloadCollector)
+	_, _ = collectorDB.Query(ctx, "SELECT 1")
+	_, _ = collectorDB.Exec(ctx, "INSERT INTO market_data VALUES (1)")
+	_, _ = collectorDB.Begin(ctx)
+	assert.Equal(t, []string{"query", "exec", "begin"}, collector.calls, "dedicated pools serve reads and writes")
+	assert.Same(t, collector, router.Pool(WorkloadCollector))
+}
+
+func TestQueryRouter_WithoutPools(t *testing.T) {
+	primary := &recordingDatabase{}
+	router := NewQueryRouter(primary)
+	router.SetReplica(WorkloadAnalytics, nil)
+	router.SetPool(WorkloadAPI, &recordingDatabase{})
+
+	_, _ = router.Query(WithWorkload(context.Background(), WorkloadAnalytics), "SELECT 1")
+	_, _ = router.Query(context.Background(), "SELECT 1")
+	assert.Equal(t, []string{"query", "query"}, primary.calls)
+	assert.Nil(t, router.Pool(WorkloadAnalytics))
+	assert.NoError(t, router.PoolHealthCheck(context.Background(), WorkloadAnalytics))
+	assert.NoError(t, router.Close())
+	assert.True(t, primary.closed)
+}
+
+func TestQueryRouter_HealthAndClose(t *testing.T) {
+	primary := &recordingDatabase{}
+	replica := &recordingDatabase{healthy: errors.New("replica down"), closeErr: errors.New("close failed")}
+	router := NewQueryRouter(primary)
+	router.SetReplica(WorkloadAnalytics, replica)
+
+	assert.NoError(t, router.HealthCheck(context.Background()), "replica health does not affect the primary check")
+	assert.EqualError(t, router.PoolHealthCheck(context.Background(), WorkloadAnalytics), "replica down")
+	assert.True(t, router.IsReady())
+
+	assert.EqualError(t, router.Close(), "close failed")
+	assert.True(t, primary.closed)
+	assert.True(t, replica.closed)
+}
+
+type statsDatabase struct {
+	recordingDatabase
+	stats PoolStats
+}
+
+func (d *statsDatabase) PoolStats() []PoolStats { return []PoolStats{d.stats} }
+
+func TestQueryRouter_PoolStats(t *testing.T) {
+	router := NewQueryRouter(&statsDatabase{stats: PoolStats{Name: "primary", MaxConns: 25}})
+	router.SetPool(WorkloadCollector, &statsDatabase{stats: PoolStats{Name: "primary", MaxConns: 5}})
+	router.SetReplica(WorkloadAnalytics, &recordingDatabase{})
+
+	stats := router.PoolStats()
+	assert.Equal(t, []PoolStats{
+		{Name: "api", MaxConns: 25},
+		{Name: "collector", MaxConns: 5},
+	}, stats, "pools without statistics are skipped")
+}
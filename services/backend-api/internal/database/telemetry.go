@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/getsentry/sentry-go"
+	"github.com/irfndi/neuratrade/internal/chaos"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -199,6 +200,9 @@ func (t *PostgresSentryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Co
 	// Add breadcrumb
 	addPostgresBreadcrumb(ctx, operation, table, "start")
 
+	// Slow the query down while a database chaos fault is injected
+	chaos.Default().Delay(ctx, chaos.TargetDatabase)
+
 	return ctx
 }
 
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/subtle"
 	"encoding/hex"
@@ -15,9 +16,23 @@ import (
 // Package middleware provides HTTP middleware components for authentication,
 // authorization, telemetry, and other cross-cutting concerns.
 
+// AdminOperatorContextKey holds the operator authenticated by RequireAdminAuth.
+const AdminOperatorContextKey = "admin_operator"
+
+// SharedAdminOperator is the operator name of the shared ADMIN_API_KEY.
+const SharedAdminOperator = "admin"
+
+// AdminSessionVerifier validates session tokens presented to admin routes.
+type AdminSessionVerifier interface {
+	VerifySession(ctx context.Context, tokenString string) (*JWTClaims, error)
+}
+
 // AdminMiddleware provides admin authentication middleware.
 type AdminMiddleware struct {
 	apiKey string
+	// operatorKeys maps per-operator API keys to operator names.
+	operatorKeys map[string]string
+	sessions     AdminSessionVerifier
 }
 
 // generateSecureKey generates a cryptographically secure random key.
@@ -87,12 +102,82 @@ func NewAdminMiddleware() *AdminMiddleware {
 	}
 
 	return &AdminMiddleware{
-		apiKey: apiKey,
+		apiKey:       apiKey,
+		operatorKeys: parseOperatorKeys(os.Getenv("ADMIN_OPERATOR_KEYS")),
 	}
 }
 
+// parseOperatorKeys reads "name:key" pairs separated by commas. Every
+// operator gets their own credential so that actions needing a second
+// operator cannot be approved with the key that requested them.
+func parseOperatorKeys(raw string) map[string]string {
+	keys := make(map[string]string)
+	for _, entry := range strings.Split(raw, ",") {
+		name, key, found := strings.Cut(strings.TrimSpace(entry), ":")
+		name, key = strings.TrimSpace(name), strings.TrimSpace(key)
+		if !found || name == "" || key == "" {
+			if entry = strings.TrimSpace(entry); entry != "" {
+				log.Printf("WARNING: Invalid ADMIN_OPERATOR_KEYS entry, expected name:key")
+			}
+			continue
+		}
+		if len(key) < 32 {
+			if isProductionEnvironment() {
+				log.Fatalf("ADMIN_OPERATOR_KEYS key for %s must be at least 32 characters long in production", name)
+			}
+			log.Printf("WARNING: ADMIN_OPERATOR_KEYS key for %s is shorter than 32 characters", name)
+		}
+		keys[key] = name
+	}
+	return keys
+}
+
+// authenticate returns the operator a key belongs to. Every candidate is
+// compared in constant time.
+func (am *AdminMiddleware) authenticate(key string) (string, bool) {
+	if key == "" {
+		return "", false
+	}
+	operator := ""
+	if subtle.ConstantTimeCompare([]byte(key), []byte(am.apiKey)) == 1 {
+		operator = SharedAdminOperator
+	}
+	for candidate, name := range am.operatorKeys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(candidate)) == 1 {
+			operator = name
+		}
+	}
+	return operator, operator != ""
+}
+
+// SetSessionVerifier lets admin routes accept admin-scope session tokens
+// as well as API keys.
+func (am *AdminMiddleware) SetSessionVerifier(verifier AdminSessionVerifier) {
+	am.sessions = verifier
+}
+
+// authenticateSession returns the operator behind an admin-scope session.
+func (am *AdminMiddleware) authenticateSession(ctx context.Context, token string) (*JWTClaims, string, bool) {
+	if am.sessions == nil {
+		return nil, "", false
+	}
+	claims, err := am.sessions.VerifySession(ctx, token)
+	if err != nil || !claims.HasScope(ScopeAdmin) || claims.UserID == "" {
+		return nil, "", false
+	}
+	return claims, strings.TrimPrefix(claims.UserID, OperatorSessionPrefix), true
+}
+
+// AdminOperator returns the operator authenticated for the request, or ""
+// when the request did not pass RequireAdminAuth.
+func AdminOperator(c *gin.Context) string {
+	return c.GetString(AdminOperatorContextKey)
+}
+
 // RequireAdminAuth middleware validates admin API keys.
-// It checks Authorization and X-API-Key headers.
+// It checks Authorization and X-API-Key headers against the shared key and
+// the per-operator keys, accepts admin-scope session tokens in the
+// Authorization header, and records the operator on the context.
 // Uses constant-time comparison to prevent timing attacks.
 //
 // Returns:
@@ -107,8 +192,14 @@ func (am *AdminMiddleware) RequireAdminAuth() gin.HandlerFunc {
 		if authHeader != "" {
 			tokenParts := strings.Split(authHeader, " ")
 			if len(tokenParts) == 2 && tokenParts[0] == "Bearer" {
-				// Use constant-time comparison to prevent timing attacks
-				if subtle.ConstantTimeCompare([]byte(tokenParts[1]), []byte(am.apiKey)) == 1 {
+				if operator, ok := am.authenticate(tokenParts[1]); ok {
+					c.Set(AdminOperatorContextKey, operator)
+					c.Next()
+					return
+				}
+				if claims, operator, ok := am.authenticateSession(c.Request.Context(), tokenParts[1]); ok {
+					c.Set(AdminOperatorContextKey, operator)
+					c.Set("token_claims", claims)
 					c.Next()
 					return
 				}
@@ -119,8 +210,8 @@ func (am *AdminMiddleware) RequireAdminAuth() gin.HandlerFunc {
 
 		// Check for API key in X-API-Key header
 		apiKeyHeader := c.GetHeader("X-API-Key")
-		// Use constant-time comparison to prevent timing attacks
-		if subtle.ConstantTimeCompare([]byte(apiKeyHeader), []byte(am.apiKey)) == 1 {
+		if operator, ok := am.authenticate(apiKeyHeader); ok {
+			c.Set(AdminOperatorContextKey, operator)
 			c.Next()
 			return
 		}
@@ -155,5 +246,20 @@ func (am *AdminMiddleware) RequireAdminAuth() gin.HandlerFunc {
 //
 //	bool: True if valid.
 func (am *AdminMiddleware) ValidateAdminKey(key string) bool {
-	return key == am.apiKey
+	_, ok := am.authenticate(key)
+	return ok
+}
+
+// AdminKeyOperator returns the operator an admin API key belongs to.
+//
+// Parameters:
+//
+//	key: API key to check.
+//
+// Returns:
+//
+//	string: The operator name.
+//	bool: True if the key is valid.
+func (am *AdminMiddleware) AdminKeyOperator(key string) (string, bool) {
+	return am.authenticate(key)
 }
@@ -4,6 +4,7 @@
 package middleware
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -12,8 +13,31 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// Token types distinguish short-lived access tokens from refresh tokens.
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// OperatorSessionPrefix prefixes the user ID of sessions opened with an
+// admin key; the rest is the operator the key belongs to.
+const OperatorSessionPrefix = "operator:"
+
+// Scopes carried in session tokens. ScopeAdmin implies every other scope.
+const (
+	ScopeRead  = "read"
+	ScopeTrade = "trade"
+	ScopeAdmin = "admin"
+)
+
+// TokenRevocationChecker reports whether a token ID has been revoked.
+type TokenRevocationChecker interface {
+	IsTokenRevoked(ctx context.Context, tokenID string) bool
+}
+
 // JWTClaims represents the JWT token claims.
 // Contains user identification and authentication information.
 type JWTClaims struct {
@@ -21,13 +45,28 @@ type JWTClaims struct {
 	UserID string `json:"user_id"`
 	// Email is the user email.
 	Email string `json:"email"`
+	// Scopes limits what the token may access. Legacy tokens carry no scopes.
+	Scopes []string `json:"scopes,omitempty"`
+	// TokenType is "access" or "refresh". Legacy tokens leave it empty.
+	TokenType string `json:"token_type,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// HasScope reports whether the claims grant a scope.
+func (c *JWTClaims) HasScope(scope string) bool {
+	for _, s := range c.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
 // AuthMiddleware provides JWT authentication middleware.
 // Validates Bearer tokens in the Authorization header.
 type AuthMiddleware struct {
-	secretKey []byte
+	secretKey  []byte
+	revocation TokenRevocationChecker
 }
 
 // NewAuthMiddleware creates a new JWT authentication middleware.
@@ -113,9 +152,21 @@ func (am *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 
 		// Check if token is valid
 		if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
+			if claims.TokenType == TokenTypeRefresh {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh tokens cannot be used for API access"})
+				c.Abort()
+				return
+			}
+			if am.isRevoked(c.Request.Context(), claims) {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Token revoked"})
+				c.Abort()
+				return
+			}
+
 			// Set user context
 			c.Set("user_id", claims.UserID)
 			c.Set("user_email", claims.Email)
+			c.Set("token_claims", claims)
 			c.Next()
 		} else {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
@@ -125,6 +176,64 @@ func (am *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 	}
 }
 
+// SetRevocationChecker enables rejection of revoked session tokens.
+func (am *AuthMiddleware) SetRevocationChecker(checker TokenRevocationChecker) {
+	am.revocation = checker
+}
+
+func (am *AuthMiddleware) isRevoked(ctx context.Context, claims *JWTClaims) bool {
+	return am.revocation != nil && claims.ID != "" && am.revocation.IsTokenRevoked(ctx, claims.ID)
+}
+
+// RequireScope rejects requests whose token lacks the given scope.
+// It must run after RequireAuth.
+//
+// Parameters:
+//
+//	scope: Required scope.
+//
+// Returns:
+//
+//	gin.HandlerFunc: Gin handler.
+func (am *AuthMiddleware) RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get("token_claims")
+		claims, ok := value.(*JWTClaims)
+		if !exists || !ok || !claims.HasScope(scope) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient scope", "required_scope": scope})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// VerifySession validates an access token the way RequireAuth does:
+// refresh tokens and revoked tokens are rejected.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	tokenString: Token string to validate.
+//
+// Returns:
+//
+//	*JWTClaims: Token claims.
+//	error: Error if the token is not a valid access token.
+func (am *AuthMiddleware) VerifySession(ctx context.Context, tokenString string) (*JWTClaims, error) {
+	claims, err := am.ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType == TokenTypeRefresh {
+		return nil, fmt.Errorf("refresh tokens cannot be used for API access")
+	}
+	if am.isRevoked(ctx, claims) {
+		return nil, fmt.Errorf("token revoked")
+	}
+	return claims, nil
+}
+
 // OptionalAuth middleware validates JWT tokens but doesn't require them.
 // If a valid token is present, user context is set.
 //
@@ -156,7 +265,7 @@ func (am *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 		})
 
 		if err == nil && token.Valid {
-			if claims, ok := token.Claims.(*JWTClaims); ok {
+			if claims, ok := token.Claims.(*JWTClaims); ok && claims.TokenType != TokenTypeRefresh && !am.isRevoked(c.Request.Context(), claims) {
 				if claims.ExpiresAt == nil || claims.ExpiresAt.After(time.Now()) {
 					c.Set("user_id", claims.UserID)
 					c.Set("user_email", claims.Email)
@@ -168,7 +277,8 @@ func (am *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 	}
 }
 
-// GenerateToken creates a new JWT token for a user.
+// GenerateToken creates a new JWT token for a user with the read and trade
+// scopes.
 //
 // Parameters:
 //
@@ -184,6 +294,7 @@ func (am *AuthMiddleware) GenerateToken(userID, email string, duration time.Dura
 	claims := &JWTClaims{
 		UserID: userID,
 		Email:  email,
+		Scopes: []string{ScopeRead, ScopeTrade},
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -195,6 +306,44 @@ func (am *AuthMiddleware) GenerateToken(userID, email string, duration time.Dura
 	return token.SignedString(am.secretKey)
 }
 
+// GenerateScopedToken creates a session token with scopes and a unique token ID
+// so it can be revoked individually.
+//
+// Parameters:
+//
+//	userID: User identifier.
+//	email: User email.
+//	tokenType: TokenTypeAccess or TokenTypeRefresh.
+//	scopes: Granted scopes.
+//	duration: Token validity duration.
+//
+// Returns:
+//
+//	string: Signed token string.
+//	*JWTClaims: The claims that were signed.
+//	error: Error if generation fails.
+func (am *AuthMiddleware) GenerateScopedToken(userID, email, tokenType string, scopes []string, duration time.Duration) (string, *JWTClaims, error) {
+	now := time.Now()
+	claims := &JWTClaims{
+		UserID:    userID,
+		Email:     email,
+		Scopes:    scopes,
+		TokenType: tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(am.secretKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, claims, nil
+}
+
 // ValidateToken validates a JWT token and returns claims.
 //
 // Parameters:
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
@@ -445,3 +446,50 @@ func TestJWTClaims(t *testing.T) {
 		assert.NotNil(t, claims.IssuedAt)
 	})
 }
+
+type stubRevocationChecker struct {
+	revoked map[string]bool
+}
+
+func (s *stubRevocationChecker) IsTokenRevoked(ctx context.Context, tokenID string) bool {
+	return s.revoked[tokenID]
+}
+
+func TestAuthMiddleware_ScopedTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	am := NewAuthMiddleware(generateTestSecret())
+	revocations := &stubRevocationChecker{revoked: map[string]bool{}}
+	am.SetRevocationChecker(revocations)
+
+	router := gin.New()
+	router.GET("/read", am.RequireAuth(), am.RequireScope(ScopeRead), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.GET("/admin", am.RequireAuth(), am.RequireScope(ScopeAdmin), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	call := func(path, token string) int {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	access, claims, err := am.GenerateScopedToken("user123", "", TokenTypeAccess, []string{ScopeRead}, time.Hour)
+	require.NoError(t, err)
+	assert.NotEmpty(t, claims.ID)
+	assert.Equal(t, http.StatusOK, call("/read", access))
+	assert.Equal(t, http.StatusForbidden, call("/admin", access))
+
+	admin, _, err := am.GenerateScopedToken("operator", "", TokenTypeAccess, []string{ScopeAdmin}, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, call("/read", admin), "admin implies every scope")
+
+	refresh, _, err := am.GenerateScopedToken("user123", "", TokenTypeRefresh, []string{ScopeRead}, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, call("/read", refresh))
+
+	revocations.revoked[claims.ID] = true
+	assert.Equal(t, http.StatusUnauthorized, call("/read", access))
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/utils"
+)
+
+const (
+	// ClientVersionHeader carries the caller's "<name>/<version>", for example
+	// "neuratrade-cli/1.4.0".
+	ClientVersionHeader = "X-Client-Version"
+	// ServerVersionHeader is set on every response with the backend version.
+	ServerVersionHeader = "X-Server-Version"
+	// ClientCompatHeader is set to "outdated; min=<version>" when the caller
+	// is older than the minimum version supported for its client name.
+	ClientCompatHeader = "X-Client-Compat"
+)
+
+// Client names the first-party clients declare in X-Client-Version.
+const (
+	ClientCLI      = "neuratrade-cli"
+	ClientTelegram = "telegram-service"
+)
+
+// ClientCompat is the result of checking a client's declared version against
+// the minimum the backend supports.
+type ClientCompat struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	MinVersion string `json:"min_version,omitempty"`
+	Compatible bool   `json:"compatible"`
+}
+
+// ParseClientVersion splits an X-Client-Version value into name and version.
+//
+// Parameters:
+//   - header: Header value in "<name>/<version>" form.
+//
+// Returns:
+//   - string: Client name.
+//   - string: Client version.
+//   - bool: False if the header is empty or malformed.
+func ParseClientVersion(header string) (string, string, bool) {
+	name, version, found := strings.Cut(strings.TrimSpace(header), "/")
+	name, version = strings.TrimSpace(name), strings.TrimSpace(version)
+	if !found || name == "" || version == "" {
+		return "", "", false
+	}
+	return name, version, true
+}
+
+// CheckClientVersion reports whether a declared client version is supported.
+// Clients without a configured minimum and versions that are not releases
+// (such as "dev" builds) are treated as compatible.
+//
+// Parameters:
+//   - header: X-Client-Version value.
+//   - minVersions: Minimum supported version by client name.
+//
+// Returns:
+//   - ClientCompat: Check result.
+//   - bool: False if the header is empty or malformed.
+func CheckClientVersion(header string, minVersions map[string]string) (ClientCompat, bool) {
+	name, version, ok := ParseClientVersion(header)
+	if !ok {
+		return ClientCompat{}, false
+	}
+	result := ClientCompat{Name: name, Version: version, MinVersion: minVersions[name], Compatible: true}
+	if result.MinVersion == "" {
+		return result, true
+	}
+	if cmp, err := utils.CompareVersions(version, result.MinVersion); err == nil && cmp < 0 {
+		result.Compatible = false
+	}
+	return result, true
+}
+
+// ClientVersionMiddleware advertises the server version on every response and
+// flags requests from clients older than their minimum supported version, so
+// clients can warn or refuse destructive commands instead of drifting silently.
+//
+// Parameters:
+//   - serverVersion: Backend version reported in X-Server-Version.
+//   - minVersions: Minimum supported version by client name.
+//
+// Returns:
+//   - gin.HandlerFunc: Gin middleware handler.
+func ClientVersionMiddleware(serverVersion string, minVersions map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header(ServerVersionHeader, serverVersion)
+		if compat, ok := CheckClientVersion(c.GetHeader(ClientVersionHeader), minVersions); ok && !compat.Compatible {
+			c.Header(ClientCompatHeader, "outdated; min="+compat.MinVersion)
+		}
+		c.Next()
+	}
+}
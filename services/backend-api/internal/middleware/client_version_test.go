@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseClientVersion(t *testing.T) {
+	name, version, ok := ParseClientVersion("neuratrade-cli/1.4.0")
+	assert.True(t, ok)
+	assert.Equal(t, ClientCLI, name)
+	assert.Equal(t, "1.4.0", version)
+
+	for _, header := range []string{"", "neuratrade-cli", "/1.0.0", "neuratrade-cli/"} {
+		_, _, ok := ParseClientVersion(header)
+		assert.False(t, ok, header)
+	}
+}
+
+func TestCheckClientVersion(t *testing.T) {
+	minVersions := map[string]string{ClientCLI: "1.2.0"}
+
+	compat, ok := CheckClientVersion("neuratrade-cli/1.1.9", minVersions)
+	assert.True(t, ok)
+	assert.False(t, compat.Compatible)
+	assert.Equal(t, "1.2.0", compat.MinVersion)
+
+	compat, _ = CheckClientVersion("neuratrade-cli/v1.2.0", minVersions)
+	assert.True(t, compat.Compatible)
+
+	// Development builds and clients without a minimum are not blocked
+	compat, _ = CheckClientVersion("neuratrade-cli/dev", minVersions)
+	assert.True(t, compat.Compatible)
+	compat, _ = CheckClientVersion("telegram-service/0.1.0", minVersions)
+	assert.True(t, compat.Compatible)
+
+	_, ok = CheckClientVersion("", minVersions)
+	assert.False(t, ok)
+}
+
+func TestClientVersionMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ClientVersionMiddleware("2.0.0", map[string]string{ClientCLI: "1.2.0"}))
+	router.GET("/status", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/status", nil)
+	req.Header.Set(ClientVersionHeader, "neuratrade-cli/1.0.0")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2.0.0", w.Header().Get(ServerVersionHeader))
+	assert.Equal(t, "outdated; min=1.2.0", w.Header().Get(ClientCompatHeader))
+
+	req = httptest.NewRequest(http.MethodGet, "/status", nil)
+	req.Header.Set(ClientVersionHeader, "neuratrade-cli/1.3.0")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Empty(t, w.Header().Get(ClientCompatHeader))
+}
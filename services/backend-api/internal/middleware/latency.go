@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LatencyObserver receives the latency of each handled request.
+type LatencyObserver interface {
+	ObserveLatency(latency time.Duration)
+}
+
+// LatencyMiddleware reports how long each request took to an observer, such
+// as the continuous profiler.
+//
+// Parameters:
+//   - observer: Receiver of request latencies.
+//
+// Returns:
+//   - gin.HandlerFunc: Gin middleware handler.
+func LatencyMiddleware(observer LatencyObserver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		observer.ObserveLatency(time.Since(start))
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingLatencyObserver struct {
+	latencies []time.Duration
+}
+
+func (o *recordingLatencyObserver) ObserveLatency(latency time.Duration) {
+	o.latencies = append(o.latencies, latency)
+}
+
+func TestLatencyMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	observer := &recordingLatencyObserver{}
+	router := gin.New()
+	router.Use(LatencyMiddleware(observer))
+	router.GET("/slow", func(c *gin.Context) {
+		time.Sleep(20 * time.Millisecond)
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	if assert.Len(t, observer.latencies, 1) {
+		assert.GreaterOrEqual(t, observer.latencies[0], 20*time.Millisecond)
+	}
+}
@@ -0,0 +1,328 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// QuotaClass identifies a group of endpoints that share a quota.
+type QuotaClass string
+
+const (
+	// QuotaRead applies to safe methods (GET, HEAD, OPTIONS).
+	QuotaRead QuotaClass = "read"
+	// QuotaWrite applies to every other method.
+	QuotaWrite QuotaClass = "write"
+)
+
+// Quota is a token bucket: Burst requests may be made at once and the bucket
+// refills at Rate requests per second.
+type Quota struct {
+	Rate  float64
+	Burst int
+}
+
+// QuotaConfig defines per-client quotas for read and write endpoints.
+type QuotaConfig struct {
+	// Read is the quota for safe methods.
+	Read Quota
+	// Write is the quota for state-changing methods.
+	Write Quota
+	// KeyFunc extracts the client identity (defaults to ClientQuotaKey).
+	KeyFunc func(*gin.Context) string
+	// SkipFunc bypasses limiting for certain requests.
+	SkipFunc func(*gin.Context) bool
+}
+
+// DefaultQuotaConfig returns quotas suitable for the Telegram service and CLI.
+// Reads allow 20 requests/second with bursts of 120; writes allow 2 requests/second
+// with bursts of 20.
+//
+// Returns:
+//   - QuotaConfig: Default configuration.
+func DefaultQuotaConfig() QuotaConfig {
+	return QuotaConfig{
+		Read:    Quota{Rate: 20, Burst: 120},
+		Write:   Quota{Rate: 2, Burst: 20},
+		KeyFunc: ClientQuotaKey,
+		SkipFunc: func(c *gin.Context) bool {
+			return c.Request.URL.Path == "/health"
+		},
+	}
+}
+
+// ClientQuotaKey identifies a client by the user an upstream auth middleware
+// authenticated, and by IP otherwise. API keys and bearer tokens are not
+// used unverified: a fresh random credential would get a fresh bucket.
+//
+// Parameters:
+//   - c: Gin context.
+//
+// Returns:
+//   - string: The client key.
+func ClientQuotaKey(c *gin.Context) string {
+	if userID := c.GetString("user_id"); userID != "" {
+		return "user:" + userID
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// CredentialQuotaKey returns a key function for limiters that run before
+// auth. It verifies the session token or admin API key on the request
+// itself, so each user session and each operator key gets its own bucket.
+// Requests with a missing or invalid credential fall back to ClientQuotaKey.
+//
+// Parameters:
+//   - sessions: Verifies bearer session tokens (optional).
+//   - keys: Verifies admin API keys (optional).
+//
+// Returns:
+//   - func(*gin.Context) string: The key function.
+func CredentialQuotaKey(sessions AdminSessionVerifier, keys *AdminMiddleware) func(*gin.Context) string {
+	return func(c *gin.Context) string {
+		if token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); found && token != "" {
+			if sessions != nil {
+				if claims, err := sessions.VerifySession(c.Request.Context(), token); err == nil && claims.UserID != "" {
+					return "user:" + claims.UserID
+				}
+			}
+			if keys != nil {
+				if operator, ok := keys.AdminKeyOperator(token); ok {
+					return "key:" + operator
+				}
+			}
+		}
+		if keys != nil {
+			if operator, ok := keys.AdminKeyOperator(c.GetHeader("X-API-Key")); ok {
+				return "key:" + operator
+			}
+		}
+		return ClientQuotaKey(c)
+	}
+}
+
+// QuotaLimiter enforces token bucket quotas per client and quota class.
+// Buckets live in Redis so limits hold across instances; an in-memory bucket
+// is used when Redis is not configured or a Redis call fails.
+type QuotaLimiter struct {
+	config QuotaConfig
+	redis  *redis.Client
+	logger *zap.Logger
+
+	mu      sync.Mutex
+	buckets map[string]*quotaBucket
+}
+
+type quotaBucket struct {
+	quota      Quota
+	tokens     float64
+	lastUpdate time.Time
+}
+
+// quotaDecision is the outcome of taking a token from a bucket.
+type quotaDecision struct {
+	allowed bool
+	tokens  float64
+}
+
+// quotaScript refills the bucket for the elapsed time, takes one token if
+// available and keeps the key until the bucket would be full again.
+// Tokens are returned as a string because Lua numbers are truncated to integers.
+var quotaScript = redis.NewScript(`
+	local key = KEYS[1]
+	local rate = tonumber(ARGV[1])
+	local burst = tonumber(ARGV[2])
+	local now = tonumber(ARGV[3])
+
+	local state = redis.call("HMGET", key, "tokens", "ts")
+	local tokens = tonumber(state[1])
+	local ts = tonumber(state[2])
+	if tokens == nil or ts == nil then
+		tokens = burst
+		ts = now
+	end
+
+	tokens = math.min(burst, tokens + math.max(0, now - ts) / 1000 * rate)
+	local allowed = 0
+	if tokens >= 1 then
+		tokens = tokens - 1
+		allowed = 1
+	end
+
+	redis.call("HSET", key, "tokens", tostring(tokens), "ts", now)
+	redis.call("PEXPIRE", key, math.ceil((burst - tokens) / rate * 1000) + 1000)
+	return {allowed, tostring(tokens)}
+`)
+
+// NewQuotaLimiter creates a new quota limiter.
+//
+// Parameters:
+//   - config: Quota configuration.
+//   - redisClient: Redis client for distributed buckets (optional).
+//   - logger: Logger for limiter events.
+//
+// Returns:
+//   - *QuotaLimiter: Initialized limiter instance.
+func NewQuotaLimiter(config QuotaConfig, redisClient *redis.Client, logger *zap.Logger) *QuotaLimiter {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = ClientQuotaKey
+	}
+
+	return &QuotaLimiter{
+		config:  config,
+		redis:   redisClient,
+		logger:  logger,
+		buckets: make(map[string]*quotaBucket),
+	}
+}
+
+// Middleware returns gin middleware that enforces the read or write quota
+// depending on the request method and sets X-RateLimit-* headers.
+func (ql *QuotaLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ql.config.SkipFunc != nil && ql.config.SkipFunc(c) {
+			c.Next()
+			return
+		}
+
+		class := QuotaClassForMethod(c.Request.Method)
+		quota := ql.quotaFor(class)
+		if quota.Rate <= 0 || quota.Burst <= 0 {
+			c.Next()
+			return
+		}
+
+		now := time.Now()
+		decision := ql.take(c.Request.Context(), string(class)+":"+ql.config.KeyFunc(c), quota, now)
+
+		// The bucket is full again after the missing tokens have been refilled.
+		resetIn := time.Duration((float64(quota.Burst) - decision.tokens) / quota.Rate * float64(time.Second))
+		c.Header(RateLimitHeader, strconv.Itoa(quota.Burst))
+		c.Header(RateLimitRemainingHeader, strconv.Itoa(int(math.Floor(decision.tokens))))
+		c.Header(RateLimitResetHeader, strconv.FormatInt(now.Add(resetIn).Unix(), 10))
+		c.Header(RateLimitPolicyHeader, fmt.Sprintf("%s;burst=%d;rate=%g", class, quota.Burst, quota.Rate))
+
+		if !decision.allowed {
+			retryAfter := int(math.Ceil((1 - decision.tokens) / quota.Rate))
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":       "Rate limit exceeded",
+				"quota":       class,
+				"retry_after": retryAfter,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// QuotaClassForMethod maps an HTTP method to its quota class.
+//
+// Parameters:
+//   - method: HTTP method.
+//
+// Returns:
+//   - QuotaClass: QuotaRead for safe methods, QuotaWrite otherwise.
+func QuotaClassForMethod(method string) QuotaClass {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return QuotaRead
+	default:
+		return QuotaWrite
+	}
+}
+
+func (ql *QuotaLimiter) quotaFor(class QuotaClass) Quota {
+	if class == QuotaRead {
+		return ql.config.Read
+	}
+	return ql.config.Write
+}
+
+func (ql *QuotaLimiter) take(ctx context.Context, key string, quota Quota, now time.Time) quotaDecision {
+	if ql.redis != nil {
+		decision, err := ql.takeRedis(ctx, key, quota, now)
+		if err == nil {
+			return decision
+		}
+		ql.logger.Warn("Quota check against Redis failed, using local bucket",
+			zap.Error(err),
+			zap.String("key", key),
+		)
+	}
+	return ql.takeLocal(key, quota, now)
+}
+
+func (ql *QuotaLimiter) takeRedis(ctx context.Context, key string, quota Quota, now time.Time) (quotaDecision, error) {
+	result, err := quotaScript.Run(ctx, ql.redis, []string{"quota:" + key}, quota.Rate, quota.Burst, now.UnixMilli()).Result()
+	if err != nil {
+		return quotaDecision{}, err
+	}
+
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 2 {
+		return quotaDecision{}, fmt.Errorf("unexpected Redis response format")
+	}
+	allowed, ok := values[0].(int64)
+	if !ok {
+		return quotaDecision{}, fmt.Errorf("unexpected type for allowed value")
+	}
+	raw, ok := values[1].(string)
+	if !ok {
+		return quotaDecision{}, fmt.Errorf("unexpected type for tokens value")
+	}
+	tokens, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return quotaDecision{}, fmt.Errorf("invalid tokens value: %w", err)
+	}
+
+	return quotaDecision{allowed: allowed == 1, tokens: tokens}, nil
+}
+
+func (ql *QuotaLimiter) takeLocal(key string, quota Quota, now time.Time) quotaDecision {
+	ql.mu.Lock()
+	defer ql.mu.Unlock()
+
+	// Drop buckets that have refilled completely; they are equivalent to new ones.
+	if len(ql.buckets) > 1000 {
+		for k, b := range ql.buckets {
+			if now.Sub(b.lastUpdate).Seconds()*b.quota.Rate >= float64(b.quota.Burst) {
+				delete(ql.buckets, k)
+			}
+		}
+	}
+
+	bucket, exists := ql.buckets[key]
+	if !exists {
+		bucket = &quotaBucket{quota: quota, tokens: float64(quota.Burst), lastUpdate: now}
+		ql.buckets[key] = bucket
+	}
+
+	if elapsed := now.Sub(bucket.lastUpdate).Seconds(); elapsed > 0 {
+		bucket.tokens = math.Min(float64(quota.Burst), bucket.tokens+elapsed*quota.Rate)
+		bucket.lastUpdate = now
+	}
+
+	if bucket.tokens < 1 {
+		return quotaDecision{allowed: false, tokens: bucket.tokens}
+	}
+	bucket.tokens--
+	return quotaDecision{allowed: true, tokens: bucket.tokens}
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxSymbolBodyBytes bounds the JSON bodies inspected for a symbol.
+const maxSymbolBodyBytes = 1 << 20
+
+// SymbolResolver turns a symbol as typed into an exchange's unified symbol.
+type SymbolResolver interface {
+	Resolve(ctx context.Context, exchange, symbol string) (string, error)
+}
+
+// SymbolResolution resolves the "symbol" path parameter, query value and
+// top-level JSON body field before the handler runs, so handlers only see
+// unified symbols. The exchange is taken from the same places. Symbols the
+// exchange does not list are rejected with 400 and the closest matches.
+//
+// Parameters:
+//   - resolver: Symbol resolver.
+//
+// Returns:
+//   - gin.HandlerFunc: The middleware.
+func SymbolResolution(resolver SymbolResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// The query is read from the URL: c.Query caches it before the rewrite
+		query := c.Request.URL.Query()
+		exchange := c.Param("exchange")
+		if exchange == "" {
+			exchange = query.Get("exchange")
+		}
+
+		for i, param := range c.Params {
+			if param.Key != "symbol" {
+				continue
+			}
+			resolved, ok := resolveSymbol(c, resolver, exchange, param.Value)
+			if !ok {
+				return
+			}
+			c.Params[i].Value = resolved
+		}
+
+		if query.Get("symbol") != "" {
+			resolved, ok := resolveSymbol(c, resolver, exchange, query.Get("symbol"))
+			if !ok {
+				return
+			}
+			query.Set("symbol", resolved)
+			c.Request.URL.RawQuery = query.Encode()
+		}
+
+		if !strings.HasPrefix(c.ContentType(), "application/json") || c.Request.Body == nil {
+			c.Next()
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxSymbolBodyBytes))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "error": "Invalid request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(body, &fields) != nil {
+			// Malformed bodies are left for the handler to reject
+			c.Next()
+			return
+		}
+		var symbol, bodyExchange string
+		if json.Unmarshal(fields["symbol"], &symbol) != nil || symbol == "" {
+			c.Next()
+			return
+		}
+		if json.Unmarshal(fields["exchange"], &bodyExchange) == nil && bodyExchange != "" {
+			exchange = bodyExchange
+		}
+		resolved, ok := resolveSymbol(c, resolver, exchange, symbol)
+		if !ok {
+			return
+		}
+		if resolved != symbol {
+			fields["symbol"], _ = json.Marshal(resolved)
+			if rewritten, err := json.Marshal(fields); err == nil {
+				c.Request.Body = io.NopCloser(bytes.NewReader(rewritten))
+				c.Request.ContentLength = int64(len(rewritten))
+			}
+		}
+		c.Next()
+	}
+}
+
+func resolveSymbol(c *gin.Context, resolver SymbolResolver, exchange, symbol string) (string, bool) {
+	resolved, err := resolver.Resolve(c.Request.Context(), strings.ToLower(exchange), symbol)
+	if err != nil {
+		response := gin.H{"status": "error", "error": err.Error()}
+		var suggestible interface{ SymbolSuggestions() []string }
+		if errors.As(err, &suggestible) {
+			response["suggestions"] = suggestible.SymbolSuggestions()
+		}
+		c.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return "", false
+	}
+	if resolved == "" {
+		return symbol, true
+	}
+	return resolved, true
+}
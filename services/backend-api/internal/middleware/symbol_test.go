@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type suggestingError struct{ suggestions []string }
+
+func (e *suggestingError) Error() string               { return "unknown symbol" }
+func (e *suggestingError) SymbolSuggestions() []string { return e.suggestions }
+
+// upperResolver uppercases symbols and knows nothing listed on "empty".
+type upperResolver struct{ exchanges []string }
+
+func (r *upperResolver) Resolve(_ context.Context, exchange, symbol string) (string, error) {
+	r.exchanges = append(r.exchanges, exchange)
+	if exchange == "empty" {
+		return "", &suggestingError{suggestions: []string{"BTC/USDT"}}
+	}
+	if symbol == "fail" {
+		return "", errors.New("boom")
+	}
+	return strings.ToUpper(strings.ReplaceAll(symbol, "-", "/")), nil
+}
+
+func TestSymbolResolution(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	resolver := &upperResolver{}
+	router := gin.New()
+	router.Use(SymbolResolution(resolver))
+	echo := func(c *gin.Context) {
+		var body map[string]interface{}
+		_ = c.ShouldBindJSON(&body)
+		c.JSON(http.StatusOK, gin.H{"param": c.Param("symbol"), "query": c.Query("symbol"), "body": body["symbol"]})
+	}
+	router.GET("/ticker/:exchange/:symbol", echo)
+	router.GET("/chart", echo)
+	router.POST("/orders", echo)
+	serve := func(method, url, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, url, bytes.NewBufferString(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(http.MethodGet, "/ticker/Kraken/xbt-usd", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"param":"XBT/USD"`)
+	assert.Equal(t, "kraken", resolver.exchanges[0])
+
+	w = serve(http.MethodGet, "/chart?symbol=eth-usdt&timeframe=1h", "")
+	assert.Contains(t, w.Body.String(), `"query":"ETH/USDT"`)
+
+	w = serve(http.MethodPost, "/orders", `{"exchange":"binance","symbol":"sol-usdt","amount":1}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"body":"SOL/USDT"`)
+
+	w = serve(http.MethodGet, "/chart?symbol=btcusdx&exchange=empty", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"suggestions":["BTC/USDT"]`)
+
+	// Bodies without a symbol pass through untouched
+	w = serve(http.MethodPost, "/orders", `not json`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = serve(http.MethodPost, "/orders", `{"symbol":"fail"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
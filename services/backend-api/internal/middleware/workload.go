@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/database"
+)
+
+// WorkloadMiddleware tags the database queries of a request with a workload
+// class so they use that class's pool. Tag analytics, export and reporting
+// routes with database.WorkloadAnalytics, which may be served by a read replica.
+//
+// Parameters:
+//   - workload: Workload class of the routes.
+//
+// Returns:
+//   - gin.HandlerFunc: Gin middleware handler.
+func WorkloadMiddleware(workload database.Workload) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(database.WithWorkload(c.Request.Context(), workload))
+		c.Next()
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkloadMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"workload": database.WorkloadFromContext(c.Request.Context())})
+	}
+	router.GET("/reports", WorkloadMiddleware(database.WorkloadAnalytics), handler)
+	router.GET("/trading", handler)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/reports", nil))
+	assert.JSONEq(t, `{"workload":"analytics"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/trading", nil))
+	assert.JSONEq(t, `{"workload":"api"}`, w.Body.String())
+}
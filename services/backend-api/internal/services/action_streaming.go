@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"sort"
 	"sync"
 	"time"
 
@@ -277,12 +278,21 @@ func (as *ActionStreamer) formatActionMessage(action StreamingAction) string {
 	// Add type-specific data
 	if len(action.Data) > 0 {
 		lines = append(lines, "", "**Details:**")
-		for key, value := range action.Data {
-			lines = append(lines, fmt.Sprintf("• %s: %v", key, value))
+		keys := make([]string, 0, len(action.Data))
+		for key := range action.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			lines = append(lines, fmt.Sprintf("• %s: %v", key, action.Data[key]))
 		}
 	}
 
-	return fmt.Sprintf("```\n%s\n```", joinLines(lines))
+	formatter := NewMessageFormatter(NotificationFormatMarkdown)
+	if as.notificationSvc != nil {
+		formatter = as.notificationSvc.formatter()
+	}
+	return formatter.Pre(joinLines(lines))
 }
 
 // GetHistory returns recent action history
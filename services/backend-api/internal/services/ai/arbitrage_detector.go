@@ -6,6 +6,8 @@ import (
 	"log"
 	"sync"
 	"time"
+
+	"github.com/irfndi/neuratrade/internal/cache"
 )
 
 // AIArbitrageDetector replaces rule-based arbitrage detection with AI
@@ -15,8 +17,7 @@ type AIArbitrageDetector struct {
 
 	// State
 	exchanges     []string
-	opportunities []*ArbitrageOpportunity
-	mu            sync.RWMutex
+	opportunities *cache.Bounded[string, *ArbitrageOpportunity]
 	logger        *log.Logger
 
 	// Control
@@ -33,6 +34,8 @@ type ArbitrageConfig struct {
 	Exchanges     []string      `json:"exchanges"`
 	MinConfidence float64       `json:"min_confidence"`
 	MaxHoldTime   time.Duration `json:"max_hold_time"`
+	// MaxOpportunities caps the opportunities kept in memory
+	MaxOpportunities int `json:"max_opportunities"`
 }
 
 // DefaultArbitrageConfig returns default config
@@ -44,6 +47,8 @@ func DefaultArbitrageConfig() ArbitrageConfig {
 		Exchanges:     []string{"binance", "bybit", "okx"},
 		MinConfidence: 0.80,
 		MaxHoldTime:   5 * time.Minute,
+
+		MaxOpportunities: 500,
 	}
 }
 
@@ -69,12 +74,16 @@ func NewAIArbitrageDetector(
 	config ArbitrageConfig,
 ) *AIArbitrageDetector {
 	ctx, cancel := context.WithCancel(context.Background())
+	opportunities := cache.NewBounded[string, *ArbitrageOpportunity](cache.BoundedConfig{
+		Name:       "ai_arbitrage.opportunities",
+		MaxEntries: config.MaxOpportunities,
+	})
 
 	return &AIArbitrageDetector{
 		brain:         brain,
 		config:        config,
 		exchanges:     config.Exchanges,
-		opportunities: make([]*ArbitrageOpportunity, 0),
+		opportunities: opportunities,
 		logger:        log.Default(),
 		ctx:           ctx,
 		cancel:        cancel,
@@ -138,9 +147,9 @@ func (ad *AIArbitrageDetector) detectOpportunities() error {
 		}
 
 		if len(opps) > 0 {
-			ad.mu.Lock()
-			ad.opportunities = append(ad.opportunities, opps...)
-			ad.mu.Unlock()
+			for _, opp := range opps {
+				ad.opportunities.SetWithTTL(opp.ID, opp, time.Until(opp.ExpiresAt))
+			}
 
 			for _, opp := range opps {
 				ad.logger.Printf("[AI Arbitrage] %s: Buy %s @ %.2f, Sell %s @ %.2f, Profit: %.2f%% (confidence: %.2f)",
@@ -239,44 +248,30 @@ func (ad *AIArbitrageDetector) getPricesFromExchanges(symbol string) (map[string
 
 // cleanExpiredOpportunities removes old opportunities
 func (ad *AIArbitrageDetector) cleanExpiredOpportunities() {
-	ad.mu.Lock()
-	defer ad.mu.Unlock()
-
-	var active []*ArbitrageOpportunity
-	now := time.Now()
-
-	for _, opp := range ad.opportunities {
-		if now.Before(opp.ExpiresAt) {
-			active = append(active, opp)
-		}
-	}
-
-	ad.opportunities = active
+	ad.opportunities.PurgeExpired()
 }
 
 // GetActiveOpportunities returns current opportunities
 func (ad *AIArbitrageDetector) GetActiveOpportunities() []*ArbitrageOpportunity {
-	ad.mu.RLock()
-	defer ad.mu.RUnlock()
-
-	result := make([]*ArbitrageOpportunity, len(ad.opportunities))
-	copy(result, ad.opportunities)
+	result := make([]*ArbitrageOpportunity, 0, ad.opportunities.Len())
+	ad.opportunities.Range(func(_ string, opp *ArbitrageOpportunity) bool {
+		result = append(result, opp)
+		return true
+	})
 	return result
 }
 
 // GetBestOpportunity returns best opportunity for symbol
 func (ad *AIArbitrageDetector) GetBestOpportunity(symbol string) *ArbitrageOpportunity {
-	ad.mu.RLock()
-	defer ad.mu.RUnlock()
-
 	var best *ArbitrageOpportunity
-	for _, opp := range ad.opportunities {
+	ad.opportunities.Range(func(_ string, opp *ArbitrageOpportunity) bool {
 		if opp.Symbol == symbol && time.Now().Before(opp.ExpiresAt) {
 			if best == nil || opp.SpreadPercent > best.SpreadPercent {
 				best = opp
 			}
 		}
-	}
+		return true
+	})
 
 	return best
 }
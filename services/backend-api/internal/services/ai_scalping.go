@@ -2,7 +2,10 @@ package services
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math"
@@ -26,6 +29,8 @@ type AIScalpingConfig struct {
 	AutoExecute       bool
 	MaxPairsToAnalyze int
 	MaxCandidatePairs int
+	// PromptNotes are extra instructions appended to the system prompt.
+	PromptNotes string
 }
 
 func DefaultAIScalpingConfig() AIScalpingConfig {
@@ -50,6 +55,13 @@ type AITradingDecision struct {
 	Reasoning   string           `json:"reasoning"`
 	StopLoss    *decimal.Decimal `json:"stop_loss,omitempty"`
 	TakeProfit  *decimal.Decimal `json:"take_profit,omitempty"`
+	// PromptVersion names the prompt/model version that made the decision
+	// while a canary rollout is running.
+	PromptVersion string `json:"prompt_version,omitempty"`
+	// DecisionID identifies the decision's audit record.
+	DecisionID string `json:"decision_id,omitempty"`
+	// ContextTrace records which context the prompt included.
+	ContextTrace *ContextTrace `json:"context_trace,omitempty"`
 }
 
 type TradingPortfolio struct {
@@ -57,6 +69,18 @@ type TradingPortfolio struct {
 	TotalValue    float64 `json:"total_value"`
 	OpenPositions int     `json:"open_positions"`
 	UnrealizedPnL float64 `json:"unrealized_pnl"`
+	// Limits override the service's thresholds for this cycle.
+	Limits ScalpingLimits `json:"-"`
+}
+
+// ScalpingLimits override the configured thresholds of one trading cycle,
+// for example from the chat's operating profile. Zero values keep the
+// configured ones.
+type ScalpingLimits struct {
+	MinConfidence float64
+	MaxCapitalPct float64
+	// MaxPairs is how many ranked pairs are sent to the AI.
+	MaxPairs int
 }
 
 type AIScalpingService struct {
@@ -66,6 +90,23 @@ type AIScalpingService struct {
 	ccxtService   ccxt.CCXTService
 	orderExecutor ScalpingOrderExecutor
 	tradeMemory   *TradeMemory
+	listingRisk   ListingRiskProvider
+	stableGuard   StablecoinGuard
+	cooldowns     SymbolCooldownGuard
+	positions     StrategyPositionGuard
+	orderOptions  OrderOptions
+	exchangeGuard ExchangeGuard
+	promptRouter  PromptRouter
+	decisions     DecisionRecorder
+	similar       SimilarDecisionFinder
+	leverage      LeverageGuard
+	positioning   PositioningReader
+	tradeFlow     TradeFlowReader
+	regime        RegimeDetector
+	news          RecentNewsReader
+	openPositions OpenPositionReader
+	context       *ContextBuilder
+	intents       IntentRecorder
 }
 
 func NewAIScalpingService(
@@ -86,34 +127,185 @@ func NewAIScalpingService(
 	}
 }
 
+// StrategyParameters returns the configured parameters of the strategy for
+// state fingerprints.
+func (s *AIScalpingService) StrategyParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"exchange":             s.config.Exchange,
+		"leverage":             s.config.Leverage,
+		"max_capital_pct":      s.config.MaxCapitalPct,
+		"min_confidence":       s.config.MinConfidence,
+		"max_iterations":       s.config.MaxIterations,
+		"timeout":              s.config.Timeout.String(),
+		"auto_execute":         s.config.AutoExecute,
+		"max_pairs_to_analyze": s.config.MaxPairsToAnalyze,
+		"max_candidate_pairs":  s.config.MaxCandidatePairs,
+		"prompt_notes":         s.config.PromptNotes,
+		"order_options":        s.orderOptions,
+	}
+}
+
+// SetListingRiskProvider applies conservative limits to recently listed symbols.
+func (s *AIScalpingService) SetListingRiskProvider(provider ListingRiskProvider) {
+	s.listingRisk = provider
+}
+
+// SetStablecoinGuard skips symbols that trade against a depegged stablecoin.
+func (s *AIScalpingService) SetStablecoinGuard(guard StablecoinGuard) {
+	s.stableGuard = guard
+}
+
+// SetSymbolCooldownGuard skips symbols that are cooling down after a recent exit.
+func (s *AIScalpingService) SetSymbolCooldownGuard(guard SymbolCooldownGuard) {
+	s.cooldowns = guard
+}
+
+// SetPositionGuard stops scalping from stacking exposure on symbols other strategies hold.
+func (s *AIScalpingService) SetPositionGuard(guard StrategyPositionGuard) {
+	s.positions = guard
+}
+
+// SetOrderOptions sets the time-in-force and post-only flags of scalping entries.
+func (s *AIScalpingService) SetOrderOptions(options OrderOptions) {
+	s.orderOptions = options
+}
+
+// SetIntentLog records each entry's order before it is placed so a restart
+// mid-order can be reconciled.
+func (s *AIScalpingService) SetIntentLog(intents IntentRecorder) {
+	s.intents = intents
+}
+
+// SetExchangeGuard holds while the configured exchange is paused for an outage.
+func (s *AIScalpingService) SetExchangeGuard(guard ExchangeGuard) {
+	s.exchangeGuard = guard
+}
+
+// SetLeverageGuard verifies the configured leverage is applied on the
+// exchange before each entry.
+func (s *AIScalpingService) SetLeverageGuard(guard LeverageGuard) {
+	s.leverage = guard
+}
+
+// SetPositioningSource adds open interest and long/short ratios of each
+// symbol's perp to the market signals.
+func (s *AIScalpingService) SetPositioningSource(positioning PositioningReader) {
+	s.positioning = positioning
+}
+
+// SetTradeFlowSource adds trade-tape imbalance and large prints of each
+// symbol to the market signals.
+func (s *AIScalpingService) SetTradeFlowSource(tradeFlow TradeFlowReader) {
+	s.tradeFlow = tradeFlow
+}
+
+// SetPromptRouter picks the prompt/model version of each cycle and reports
+// the resulting decisions back, for canary rollouts.
+func (s *AIScalpingService) SetPromptRouter(router PromptRouter) {
+	s.promptRouter = router
+}
+
+// SetDecisionRecorder stores the prompt, model output and order of every
+// decision for later replay.
+func (s *AIScalpingService) SetDecisionRecorder(recorder DecisionRecorder) {
+	s.decisions = recorder
+}
+
+// SetSimilarDecisionSource prepends what happened after past decisions made
+// in similar situations to the prompt.
+func (s *AIScalpingService) SetSimilarDecisionSource(similar SimilarDecisionFinder) {
+	s.similar = similar
+}
+
+// SetRegimeSource adds the top symbol's trend and volatility regime to the
+// prompt.
+func (s *AIScalpingService) SetRegimeSource(regime RegimeDetector) {
+	s.regime = regime
+}
+
+// SetNewsSource adds the latest headlines about the top symbol to the prompt.
+func (s *AIScalpingService) SetNewsSource(news RecentNewsReader) {
+	s.news = news
+}
+
+// SetOpenPositionSource lists open positions in the prompt's portfolio.
+func (s *AIScalpingService) SetOpenPositionSource(positions OpenPositionReader) {
+	s.openPositions = positions
+}
+
+// SetContextBuilder replaces the default prompt context and its budgets.
+func (s *AIScalpingService) SetContextBuilder(builder *ContextBuilder) {
+	s.context = builder
+}
+
 func (s *AIScalpingService) ExecuteTradingCycle(ctx context.Context, portfolio TradingPortfolio) (*AITradingDecision, error) {
+	return s.ExecuteTradingCycleForSymbols(ctx, portfolio, nil)
+}
+
+// ExecuteTradingCycleForSymbols runs a trading cycle that only considers the
+// given symbols; an empty list considers every discovered pair.
+func (s *AIScalpingService) ExecuteTradingCycleForSymbols(ctx context.Context, portfolio TradingPortfolio, symbols []string) (*AITradingDecision, error) {
 	log.Printf("[AI-SCALPING] Starting trading cycle for portfolio: %.2f USDT", portfolio.USDTBalance)
+	if s.exchangeGuard != nil && s.exchangeGuard.ExchangePaused(ctx, s.config.Exchange) {
+		log.Printf("[AI-SCALPING] %s is paused after degraded health, holding", s.config.Exchange)
+		return &AITradingDecision{Action: "hold", Reasoning: s.config.Exchange + " is paused while its health is degraded"}, nil
+	}
 	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
 	defer cancel()
 
-	signals, err := s.gatherMarketSignals(ctx)
+	signals, err := s.gatherMarketSignals(ctx, symbols, portfolio.Limits)
 	if err != nil {
 		log.Printf("[AI-SCALPING] Failed to gather signals: %v", err)
 		return nil, fmt.Errorf("failed to gather market signals: %w", err)
 	}
 	log.Printf("[AI-SCALPING] Gathered %d market signals", len(signals))
 
-	decision, err := s.getAIDecision(ctx, signals, portfolio)
+	signals = s.withoutDepeggedStablecoins(ctx, signals)
+	if len(signals) == 0 {
+		return &AITradingDecision{Action: "hold", Reasoning: "no tradable pairs: every candidate uses a depegged stablecoin"}, nil
+	}
+	signals = s.withoutCoolingDownSymbols(ctx, signals)
+	if len(signals) == 0 {
+		return &AITradingDecision{Action: "hold", Reasoning: "every candidate symbol is cooling down after a recent exit"}, nil
+	}
+
+	var version PromptVersion
+	if s.promptRouter != nil {
+		version = s.promptRouter.Assign(ctx)
+	}
+	var audit *DecisionAudit
+	if s.decisions != nil {
+		audit = &DecisionAudit{Source: DecisionSourceAIScalping, Exchange: s.config.Exchange}
+		audit.MarketSnapshot, _ = json.Marshal(signals)
+	}
+	decision, err := s.getAIDecision(ctx, signals, portfolio, version, audit)
 	if err != nil {
 		log.Printf("[AI-SCALPING] Failed to get AI decision: %v", err)
 		return nil, fmt.Errorf("failed to get AI decision: %w", err)
 	}
+	if audit != nil {
+		defer s.recordDecisionAudit(ctx, audit, decision, signals)
+	}
 
 	decision.Action = strings.ToLower(strings.TrimSpace(decision.Action))
 	decision.Symbol = normalizeSymbolForComparison(decision.Symbol)
+	decision.PromptVersion = version.Name
 
 	log.Printf("[AI-SCALPING] AI decision: %s %s (confidence: %.2f)", decision.Action, decision.Symbol, decision.Confidence)
 
 	if err := s.validateDecision(decision, signals); err != nil {
+		audit.skipOrder(err.Error())
 		return nil, fmt.Errorf("invalid AI decision: %w", err)
 	}
 
-	effectiveMinConfidence, effectiveMaxCapital := s.dynamicRiskThresholds()
+	effectiveMinConfidence, effectiveMaxCapital := s.dynamicRiskThresholds(portfolio.Limits)
+	if s.listingRisk != nil && decision.Action != "hold" {
+		if limits, ok := s.listingRisk.ListingRiskLimits(ctx, decision.Symbol); ok {
+			effectiveMinConfidence = math.Max(effectiveMinConfidence, limits.MinConfidence)
+			effectiveMaxCapital = math.Min(effectiveMaxCapital, limits.MaxCapitalPct)
+			log.Printf("[AI-SCALPING] %s is a new listing, applying conservative limits", decision.Symbol)
+		}
+	}
 	log.Printf(
 		"[AI-SCALPING] Dynamic thresholds: min_confidence=%.2f max_capital_pct=%.2f",
 		effectiveMinConfidence,
@@ -122,18 +314,28 @@ func (s *AIScalpingService) ExecuteTradingCycle(ctx context.Context, portfolio T
 
 	if decision.Action == "hold" {
 		log.Printf("[AI-SCALPING] AI decided to hold: %s", decision.Reasoning)
+		s.recordPromptDecision(ctx, decision, signals)
 		return decision, nil
 	}
 
 	if decision.Confidence < effectiveMinConfidence {
 		log.Printf("[AI-SCALPING] Confidence %.2f below minimum %.2f, skipping", decision.Confidence, effectiveMinConfidence)
+		// A skipped trade is measured as a hold.
+		skipped := *decision
+		skipped.Action = "hold"
+		s.recordPromptDecision(ctx, &skipped, signals)
+		audit.skipOrder(fmt.Sprintf("confidence %.2f below minimum %.2f", decision.Confidence, effectiveMinConfidence))
 		return decision, fmt.Errorf("confidence below threshold")
 	}
+	s.recordPromptDecision(ctx, decision, signals)
 
 	if s.config.AutoExecute && s.orderExecutor != nil {
-		if err := s.executeDecision(ctx, decision, portfolio, effectiveMaxCapital); err != nil {
+		if err := s.executeDecision(ctx, decision, portfolio, effectiveMaxCapital, signalPrice(signals, decision.Symbol), audit); err != nil {
+			audit.skipOrder(err.Error())
 			return decision, fmt.Errorf("execution failed: %w", err)
 		}
+	} else {
+		audit.skipOrder("auto execution disabled")
 	}
 
 	return decision, nil
@@ -148,14 +350,27 @@ type aiMarketSignal struct {
 	BidAskSpread       float64 `json:"spread_pct"`
 	OrderBookImbalance float64 `json:"ob_imbalance"`
 	PriceChange24h     float64 `json:"price_change_24h_pct"`
+	// Derivatives positioning of the symbol's perp, when collected
+	OpenInterestValue     float64 `json:"open_interest_value,omitempty"`
+	OpenInterestChangePct float64 `json:"oi_change_pct,omitempty"`
+	LongShortRatio        float64 `json:"long_short_ratio,omitempty"`
+	// Trade-tape flow over the analyzer window, when available
+	TradeImbalance  float64 `json:"trade_imbalance,omitempty"`
+	LargeBuyPrints  int     `json:"large_buys,omitempty"`
+	LargeSellPrints int     `json:"large_sells,omitempty"`
 }
 
-func (s *AIScalpingService) discoverTradingPairs(ctx context.Context) ([]string, error) {
+func (s *AIScalpingService) discoverTradingPairs(ctx context.Context, universe []string, maxPairs int) ([]string, error) {
 	markets, err := s.ccxtService.FetchMarkets(ctx, s.config.Exchange)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch markets: %w", err)
 	}
 
+	allowed := make(map[string]struct{}, len(universe))
+	for _, symbol := range universe {
+		allowed[normalizeSymbolForComparison(symbol)] = struct{}{}
+	}
+
 	var candidates []string
 	seen := make(map[string]struct{})
 	for _, symbol := range markets.Symbols {
@@ -170,11 +385,17 @@ func (s *AIScalpingService) discoverTradingPairs(ctx context.Context) ([]string,
 		if _, ok := seen[comparison]; ok {
 			continue
 		}
+		if _, ok := allowed[comparison]; len(allowed) > 0 && !ok {
+			continue
+		}
 		seen[comparison] = struct{}{}
 		candidates = append(candidates, symbol)
 	}
 
 	if len(candidates) == 0 {
+		if len(allowed) > 0 {
+			return nil, fmt.Errorf("no USDT pairs of the active universe discovered")
+		}
 		return nil, fmt.Errorf("no USDT pairs discovered")
 	}
 
@@ -191,7 +412,7 @@ func (s *AIScalpingService) discoverTradingPairs(ctx context.Context) ([]string,
 	// Dynamically rank discovered symbols by liquidity + spread + intraday movement.
 	scored, err := s.ccxtService.FetchMarketData(ctx, []string{s.config.Exchange}, candidates)
 	if err != nil || len(scored) == 0 {
-		limit := s.config.MaxPairsToAnalyze
+		limit := maxPairs
 		if limit > len(candidates) {
 			limit = len(candidates)
 		}
@@ -230,7 +451,7 @@ func (s *AIScalpingService) discoverTradingPairs(ctx context.Context) ([]string,
 		return pairs[i].score > pairs[j].score
 	})
 
-	limit := s.config.MaxPairsToAnalyze
+	limit := maxPairs
 	if limit > len(pairs) {
 		limit = len(pairs)
 	}
@@ -243,10 +464,14 @@ func (s *AIScalpingService) discoverTradingPairs(ctx context.Context) ([]string,
 	return selected, nil
 }
 
-func (s *AIScalpingService) gatherMarketSignals(ctx context.Context) ([]aiMarketSignal, error) {
+func (s *AIScalpingService) gatherMarketSignals(ctx context.Context, universe []string, limits ScalpingLimits) ([]aiMarketSignal, error) {
 	var signals []aiMarketSignal
 
-	pairs, err := s.discoverTradingPairs(ctx)
+	maxPairs := s.config.MaxPairsToAnalyze
+	if limits.MaxPairs > 0 {
+		maxPairs = limits.MaxPairs
+	}
+	pairs, err := s.discoverTradingPairs(ctx, universe, maxPairs)
 	if err != nil {
 		log.Printf("[AI-SCALPING] Failed dynamic pair discovery: %v", err)
 		return nil, fmt.Errorf("dynamic pair discovery unavailable: %w", err)
@@ -297,6 +522,26 @@ func (s *AIScalpingService) gatherMarketSignals(ctx context.Context) ([]aiMarket
 			}
 		}
 
+		if s.positioning != nil {
+			if snapshot, err := s.positioning.LatestPositioning(ctx, s.config.Exchange, symbol); err != nil {
+				log.Printf("[AI-SCALPING] Failed to load positioning for %s: %v", symbol, err)
+			} else if snapshot != nil {
+				signal.OpenInterestValue = snapshot.OpenInterestValue
+				signal.OpenInterestChangePct = snapshot.OpenInterestChangePct
+				signal.LongShortRatio = snapshot.LongShortRatio
+			}
+		}
+
+		if s.tradeFlow != nil {
+			if flow, err := s.tradeFlow.TradeFlow(ctx, s.config.Exchange, symbol); err != nil {
+				log.Printf("[AI-SCALPING] Failed to load trade flow for %s: %v", symbol, err)
+			} else if flow != nil {
+				signal.TradeImbalance = flow.Imbalance
+				signal.LargeBuyPrints = flow.LargeBuyCount
+				signal.LargeSellPrints = flow.LargeSellCount
+			}
+		}
+
 		signals = append(signals, signal)
 	}
 
@@ -307,13 +552,57 @@ func (s *AIScalpingService) gatherMarketSignals(ctx context.Context) ([]aiMarket
 	return signals, nil
 }
 
-func (s *AIScalpingService) getAIDecision(ctx context.Context, signals []aiMarketSignal, portfolio TradingPortfolio) (*AITradingDecision, error) {
-	systemPrompt := s.buildSystemPrompt()
-	userPrompt := s.buildUserPrompt(ctx, signals, portfolio)
+// recordPromptDecision reports a decision to the prompt router with the
+// signal price of its symbol.
+func (s *AIScalpingService) recordPromptDecision(ctx context.Context, decision *AITradingDecision, signals []aiMarketSignal) {
+	if s.promptRouter == nil || decision.PromptVersion == "" {
+		return
+	}
+	var price float64
+	for _, signal := range signals {
+		if normalizeSymbolForComparison(signal.Symbol) == decision.Symbol {
+			price = signal.Price
+			break
+		}
+	}
+	s.promptRouter.RecordDecision(ctx, decision.PromptVersion, decision, price)
+}
+
+// recordDecisionAudit stores a decision with the context collected during
+// the cycle and links the decision to its audit record.
+func (s *AIScalpingService) recordDecisionAudit(ctx context.Context, audit *DecisionAudit, decision *AITradingDecision, signals []aiMarketSignal) {
+	audit.Symbol = decision.Symbol
+	audit.Action = decision.Action
+	audit.Confidence = decision.Confidence
+	audit.Reasoning = decision.Reasoning
+	for _, signal := range signals {
+		if normalizeSymbolForComparison(signal.Symbol) == decision.Symbol {
+			audit.Price = signal.Price
+			break
+		}
+	}
+	audit.Decision, _ = json.Marshal(decision)
+	if err := s.decisions.Record(ctx, audit); err != nil {
+		log.Printf("[AI-SCALPING] Failed to record decision audit: %v", err)
+		return
+	}
+	decision.DecisionID = audit.ID
+}
+
+func (s *AIScalpingService) getAIDecision(ctx context.Context, signals []aiMarketSignal, portfolio TradingPortfolio, version PromptVersion, audit *DecisionAudit) (*AITradingDecision, error) {
+	systemPrompt := s.buildSystemPrompt(version)
+	userPrompt, trace := s.buildUserPrompt(ctx, signals, portfolio)
+	log.Printf("[AI-SCALPING] Prompt context: %s", trace)
+	if audit != nil {
+		audit.Prompt = systemPrompt + "\n\n" + userPrompt
+		audit.Model = version.Model
+		audit.PromptVersion = promptVersionID(version, systemPrompt)
+	}
 
 	log.Printf("[AI-SCALPING] Calling LLM with %d signals", len(signals))
 
 	req := &llm.CompletionRequest{
+		Model: version.Model,
 		Messages: []llm.Message{
 			{Role: llm.RoleSystem, Content: systemPrompt},
 			{Role: llm.RoleUser, Content: userPrompt},
@@ -330,17 +619,36 @@ func (s *AIScalpingService) getAIDecision(ctx context.Context, signals []aiMarke
 	}
 
 	log.Printf("[AI-SCALPING] LLM response received (latency: %dms)", resp.LatencyMs)
+	if audit != nil {
+		audit.RawOutput = resp.Message.Content
+		if resp.Model != "" {
+			audit.Model = resp.Model
+		}
+	}
 
 	var decision AITradingDecision
 	if err := json.Unmarshal([]byte(resp.Message.Content), &decision); err != nil {
 		log.Printf("[AI-SCALPING] Failed to parse AI response: %s", resp.Message.Content)
 		return nil, fmt.Errorf("failed to parse AI decision: %w", err)
 	}
+	decision.ContextTrace = &trace
 
 	return &decision, nil
 }
 
-func (s *AIScalpingService) buildSystemPrompt() string {
+// promptVersionID names the system prompt of a decision: the canary version
+// name, if any, and a short hash of the prompt, which changes with the
+// template, skill content and prompt notes.
+func promptVersionID(version PromptVersion, systemPrompt string) string {
+	sum := sha256.Sum256([]byte(systemPrompt))
+	hash := hex.EncodeToString(sum[:6])
+	if version.Name == "" {
+		return hash
+	}
+	return version.Name + "@" + hash
+}
+
+func (s *AIScalpingService) buildSystemPrompt(version PromptVersion) string {
 	skillContent := ""
 	if s.skillRegistry != nil {
 		if sk, found := s.skillRegistry.Get("scalping"); found {
@@ -380,38 +688,86 @@ Return JSON only:
 - ob_imbalance < -0.2: Strong sell pressure (more asks)
 - spread < 0.1%%: Good liquidity for execution
 - price_change_24h > 5%%: Strong momentum (consider direction)
-`, s.config.MinConfidence, s.config.MaxCapitalPct, s.config.Leverage, skillContent)
+- long_short_ratio > 2 or < 0.5: Crowded longs or shorts, squeeze risk against the crowd
+- oi_change_pct rising with price: New positions confirm the move; falling: positions closing
+- trade_imbalance > 0.3: Aggressive buyers dominate the tape; < -0.3: aggressive sellers
+- large_buys / large_sells: Recent block prints; size following them often continues the move
+`, s.config.MinConfidence, s.config.MaxCapitalPct, s.config.Leverage, skillContent) + s.promptNotes(version)
+}
+
+// promptNotes returns the additional instructions of the prompt version,
+// falling back to the configured notes.
+func (s *AIScalpingService) promptNotes(version PromptVersion) string {
+	notes := version.PromptNotes
+	if strings.TrimSpace(notes) == "" {
+		notes = s.config.PromptNotes
+	}
+	if strings.TrimSpace(notes) == "" {
+		return ""
+	}
+	return "\n## Additional Instructions\n" + notes + "\n"
 }
 
-func (s *AIScalpingService) buildUserPrompt(ctx context.Context, signals []aiMarketSignal, portfolio TradingPortfolio) string {
+func (s *AIScalpingService) buildUserPrompt(ctx context.Context, signals []aiMarketSignal, portfolio TradingPortfolio) (string, ContextTrace) {
 	signalsJSON, _ := json.MarshalIndent(signals, "", "  ")
+	minConfidence, maxCapitalPct := s.dynamicRiskThresholds(portfolio.Limits)
+	input := ContextInput{
+		Exchange:      s.config.Exchange,
+		Signals:       signalsJSON,
+		Portfolio:     portfolio,
+		MinConfidence: minConfidence,
+		MaxCapitalPct: maxCapitalPct,
+		Leverage:      s.config.Leverage,
+	}
+	if len(signals) > 0 {
+		input.Symbol = signals[0].Symbol
+		input.TopSignal, _ = json.Marshal(signals[0])
+	}
+
+	body, trace := s.contextBuilder().Build(ctx, input)
+	return "Analyze these market signals and make a trading decision.\n\n" + body +
+		"\n\nBased on the signals and past trading history, what is your trading decision? Learn from past mistakes. Return only valid JSON.", trace
+}
 
-	var memoryContext string
+// contextBuilder returns the configured prompt context, or the default one
+// over the sources that are set.
+func (s *AIScalpingService) contextBuilder() *ContextBuilder {
+	if s.context != nil {
+		return s.context
+	}
+	builder := NewContextBuilder(DefaultScalpingContextTokens)
+	if s.similar != nil {
+		builder.Add(NewSimilarDecisionsContextSource(s.similar), 800)
+	}
+	builder.Add(NewPositionsContextSource(s.openPositions), 400)
+	builder.Add(NewRiskBudgetContextSource(), 150)
+	if s.regime != nil {
+		builder.Add(NewRegimeContextSource(s.regime), 150)
+	}
+	builder.Add(NewIndicatorsContextSource(), 2500)
+	if s.news != nil {
+		builder.Add(NewNewsContextSource(s.news), 500)
+	}
 	if s.tradeMemory != nil {
-		topSymbol := ""
-		if len(signals) > 0 {
-			topSymbol = signals[0].Symbol
-		}
-		currentContext := string(signalsJSON)
-		if mem, err := s.tradeMemory.BuildMemoryContext(ctx, topSymbol, currentContext); err == nil {
-			memoryContext = "\n" + mem
-		}
+		builder.Add(NewTradeMemoryContextSource(s.tradeMemory), 1200)
 	}
+	return builder
+}
 
-	return fmt.Sprintf(`Analyze these market signals and make a trading decision.
-
-## Portfolio
-- USDT Balance: %.2f
-- Total Value: %.2f
-- Open Positions: %d
-
-## Market Signals
-%s%s
-
-Based on the signals and past trading history, what is your trading decision? Learn from past mistakes. Return only valid JSON.`, portfolio.USDTBalance, portfolio.TotalValue, portfolio.OpenPositions, string(signalsJSON), memoryContext)
+// signalPrice returns the symbol's last price from the cycle's signals, or 0.
+func signalPrice(signals []aiMarketSignal, symbol string) float64 {
+	for _, sig := range signals {
+		if sig.Symbol == symbol {
+			return sig.Price
+		}
+	}
+	return 0
 }
 
-func (s *AIScalpingService) executeDecision(ctx context.Context, decision *AITradingDecision, portfolio TradingPortfolio, maxCapitalPct float64) error {
+// executeDecision places the decision's order. With order flags set and a
+// known price the entry is a limit order at that price, sized in the base
+// asset; otherwise it is a market order for the USDT amount.
+func (s *AIScalpingService) executeDecision(ctx context.Context, decision *AITradingDecision, portfolio TradingPortfolio, maxCapitalPct, price float64, audit *DecisionAudit) error {
 	if s.orderExecutor == nil {
 		return fmt.Errorf("no order executor configured")
 	}
@@ -431,17 +787,115 @@ func (s *AIScalpingService) executeDecision(ctx context.Context, decision *AITra
 		return fmt.Errorf("computed order amount is non-positive")
 	}
 
+	if s.positions != nil {
+		if err := s.positions.CheckOverlap(ctx, StrategyScalping, s.config.Exchange, decision.Symbol, decision.Action); err != nil {
+			return fmt.Errorf("position check failed: %w", err)
+		}
+	}
+
+	if s.leverage != nil && s.config.Leverage > 0 {
+		if err := s.leverage.EnsureLeverage(ctx, s.config.Exchange, decision.Symbol, float64(s.config.Leverage)); err != nil {
+			audit.skipOrder("leverage not applied: " + err.Error())
+			return fmt.Errorf("leverage check failed: %w", err)
+		}
+	}
+
 	log.Printf("[AI-SCALPING] Executing: %s %s (%s USDT)", decision.Action, decision.Symbol, amount.String())
 
-	orderID, err := s.orderExecutor.PlaceOrder(ctx, s.config.Exchange, decision.Symbol, decision.Action, "market", amount, nil)
+	orderType, orderAmount, limitPrice := "market", amount, (*decimal.Decimal)(nil)
+	if !s.orderOptions.IsZero() && price > 0 {
+		entry := decimal.NewFromFloat(price)
+		orderType, orderAmount, limitPrice = "limit", amount.Div(entry), &entry
+	}
+	options := s.orderOptions
+	var intent *DecisionIntent
+	if s.intents != nil {
+		intent = &DecisionIntent{
+			Strategy: StrategyScalping,
+			Orders: []IntentOrder{{
+				Exchange: s.config.Exchange, Symbol: decision.Symbol, Side: decision.Action,
+				OrderType: orderType, Amount: orderAmount, Price: limitPrice,
+			}},
+		}
+		if audit != nil {
+			intent.DecisionID = audit.ID
+		}
+		if err := s.intents.Begin(ctx, intent); err != nil {
+			return fmt.Errorf("intent log unavailable: %w", err)
+		}
+		options.ClientOrderID = intent.Orders[0].ClientOrderID
+	}
+	orderID, err := placeOrderWithOptions(ctx, s.orderExecutor, s.config.Exchange, decision.Symbol, decision.Action, orderType, orderAmount, limitPrice, options)
+	if intent != nil {
+		if recErr := s.intents.RecordOrder(ctx, intent, 0, orderID, err); recErr != nil {
+			log.Printf("[AI-SCALPING] Failed to record order intent: %v", recErr)
+		}
+		if finErr := s.intents.Finish(ctx, intent); finErr != nil {
+			log.Printf("[AI-SCALPING] Failed to finish order intent: %v", finErr)
+		}
+	}
+	if audit != nil {
+		audit.Order = &DecisionAuditOrder{Status: DecisionOrderPlaced, OrderID: orderID, Side: decision.Action, Amount: amount}
+		if err != nil {
+			audit.Order.Status = DecisionOrderFailed
+			audit.Order.Error = err.Error()
+		}
+	}
+	if errors.Is(err, ErrPostOnlyRejected) {
+		// The price moved through the entry; the next cycle decides again
+		log.Printf("[AI-SCALPING] Post-only %s %s not placed: it would have taken liquidity", decision.Action, decision.Symbol)
+		if audit != nil {
+			audit.Order.Status = DecisionOrderSkipped
+		}
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("order failed: %w", err)
 	}
 
 	log.Printf("[AI-SCALPING] Order placed: %s", orderID)
+	if s.positions != nil {
+		if err := s.positions.RecordFill(ctx, StrategyScalping, s.config.Exchange, decision.Symbol, decision.Action, amount); err != nil {
+			log.Printf("[AI-SCALPING] Failed to record %s position: %v", decision.Symbol, err)
+		}
+	}
 	return nil
 }
 
+// withoutDepeggedStablecoins drops signals for symbols whose strategies are
+// paused because they trade against a depegged stablecoin.
+func (s *AIScalpingService) withoutDepeggedStablecoins(ctx context.Context, signals []aiMarketSignal) []aiMarketSignal {
+	if s.stableGuard == nil {
+		return signals
+	}
+	filtered := signals[:0:0]
+	for _, sig := range signals {
+		if stable, depegged := s.stableGuard.DepeggedStablecoin(ctx, sig.Symbol); depegged {
+			log.Printf("[AI-SCALPING] Skipping %s: %s is depegged", sig.Symbol, stable)
+			continue
+		}
+		filtered = append(filtered, sig)
+	}
+	return filtered
+}
+
+// withoutCoolingDownSymbols drops signals for symbols that may not be
+// re-entered yet after a recent exit.
+func (s *AIScalpingService) withoutCoolingDownSymbols(ctx context.Context, signals []aiMarketSignal) []aiMarketSignal {
+	if s.cooldowns == nil {
+		return signals
+	}
+	filtered := signals[:0:0]
+	for _, sig := range signals {
+		if cooldown, cooling := s.cooldowns.SymbolCooldown(ctx, s.config.Exchange, sig.Symbol); cooling {
+			log.Printf("[AI-SCALPING] Skipping %s: cooling down after %s until %s", sig.Symbol, cooldown.Reason, cooldown.Until.Format(time.RFC3339))
+			continue
+		}
+		filtered = append(filtered, sig)
+	}
+	return filtered
+}
+
 func sumDecimalOrderVolume(orders []ccxt.OrderBookEntry, limit int) float64 {
 	var total float64
 	for i := 0; i < limit && i < len(orders); i++ {
@@ -519,9 +973,15 @@ func normalizeSymbolForComparison(symbol string) string {
 	return normalized
 }
 
-func (s *AIScalpingService) dynamicRiskThresholds() (minConfidence float64, maxCapitalPct float64) {
+func (s *AIScalpingService) dynamicRiskThresholds(limits ScalpingLimits) (minConfidence float64, maxCapitalPct float64) {
 	minConfidence = s.config.MinConfidence
 	maxCapitalPct = s.config.MaxCapitalPct
+	if limits.MinConfidence > 0 {
+		minConfidence = limits.MinConfidence
+	}
+	if limits.MaxCapitalPct > 0 {
+		maxCapitalPct = limits.MaxCapitalPct
+	}
 
 	adjusted := GetScalpingPerformance().GetAdjustedParameters()
 	if adjusted.MaxCapitalPercent > 0 && adjusted.MaxCapitalPercent < maxCapitalPct {
@@ -0,0 +1,719 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// AlertIndicator is a value an alert rule watches.
+type AlertIndicator string
+
+const (
+	// AlertIndicatorPrice is the last traded price.
+	AlertIndicatorPrice AlertIndicator = "price"
+	// AlertIndicatorRSI is the relative strength index of the closes.
+	AlertIndicatorRSI AlertIndicator = "rsi"
+	// AlertIndicatorSMA is the simple moving average of the closes.
+	AlertIndicatorSMA AlertIndicator = "sma"
+	// AlertIndicatorEMA is the exponential moving average of the closes.
+	AlertIndicatorEMA AlertIndicator = "ema"
+	// AlertIndicatorFunding is the latest perpetual funding rate, in percent.
+	AlertIndicatorFunding AlertIndicator = "funding"
+	// AlertIndicatorSpread is how far another exchange's price is above the
+	// rule's exchange, in percent.
+	AlertIndicatorSpread AlertIndicator = "spread"
+	// AlertIndicatorValue is a constant.
+	AlertIndicatorValue AlertIndicator = "value"
+)
+
+// AlertOperator compares the two sides of a condition.
+type AlertOperator string
+
+const (
+	AlertOperatorBelow        AlertOperator = "<"
+	AlertOperatorBelowOrEqual AlertOperator = "<="
+	AlertOperatorAbove        AlertOperator = ">"
+	AlertOperatorAboveOrEqual AlertOperator = ">="
+	// AlertOperatorCrosses holds when the left side crosses the right either way.
+	AlertOperatorCrosses      AlertOperator = "crosses"
+	AlertOperatorCrossesAbove AlertOperator = "crosses above"
+	AlertOperatorCrossesBelow AlertOperator = "crosses below"
+)
+
+const (
+	alertLogicAnd = "and"
+	alertLogicOr  = "or"
+	// maxAlertComparisons bounds how many comparisons one rule may combine.
+	maxAlertComparisons = 8
+)
+
+// alertIndicatorDefaults are the period and timeframe used when a rule
+// leaves them out.
+var alertIndicatorDefaults = map[AlertIndicator]struct {
+	period    int
+	timeframe string
+}{
+	AlertIndicatorPrice:  {timeframe: "1m"},
+	AlertIndicatorRSI:    {period: 14, timeframe: "1h"},
+	AlertIndicatorSMA:    {period: 20, timeframe: "1h"},
+	AlertIndicatorEMA:    {period: 20, timeframe: "1h"},
+	AlertIndicatorSpread: {timeframe: "1m"},
+}
+
+var alertTimeframes = map[string]time.Duration{
+	"1m": time.Minute, "5m": 5 * time.Minute, "15m": 15 * time.Minute, "30m": 30 * time.Minute,
+	"1h": time.Hour, "4h": 4 * time.Hour, "1d": 24 * time.Hour,
+}
+
+// alertRulePrefixes are phrasings stripped before a rule is parsed.
+var alertRulePrefixes = []string{"notify me when", "notify me if", "alert me when", "alert me if", "when", "if"}
+
+// AlertOperand is one side of a comparison: an indicator or a constant.
+type AlertOperand struct {
+	Indicator AlertIndicator `json:"indicator"`
+	// Period is the lookback of RSI, SMA and EMA.
+	Period int `json:"period,omitempty"`
+	// Timeframe is the candle size; empty for funding and constants.
+	Timeframe string `json:"timeframe,omitempty"`
+	// Exchange is the other exchange of a spread.
+	Exchange string `json:"exchange,omitempty"`
+	// Value is the number of a constant.
+	Value float64 `json:"value,omitempty"`
+}
+
+// String returns the operand as written in canonical rules.
+func (o AlertOperand) String() string {
+	switch o.Indicator {
+	case AlertIndicatorValue:
+		return strconv.FormatFloat(o.Value, 'f', -1, 64)
+	case AlertIndicatorPrice, AlertIndicatorFunding:
+		return string(o.Indicator)
+	case AlertIndicatorSpread:
+		return fmt.Sprintf("spread(%s)", o.Exchange)
+	default:
+		return fmt.Sprintf("%s(%d,%s)", strings.ToUpper(string(o.Indicator)), o.Period, o.Timeframe)
+	}
+}
+
+func (o AlertOperand) constant() bool {
+	return o.Indicator == AlertIndicatorValue
+}
+
+// percent reports whether the operand is expressed in percent.
+func (o AlertOperand) percent() bool {
+	return o.Indicator == AlertIndicatorFunding || o.Indicator == AlertIndicatorSpread
+}
+
+// unit groups operands that can be compared with each other.
+func (o AlertOperand) unit() string {
+	switch o.Indicator {
+	case AlertIndicatorPrice, AlertIndicatorSMA, AlertIndicatorEMA:
+		return "price"
+	case AlertIndicatorRSI:
+		return "index"
+	default:
+		return "percent"
+	}
+}
+
+// warmup is how many candles the operand needs for one value.
+func (o AlertOperand) warmup() int {
+	switch o.Indicator {
+	case AlertIndicatorRSI, AlertIndicatorEMA:
+		// Wilder and exponential smoothing settle after a few periods
+		return min(o.Period*4+1, 500)
+	case AlertIndicatorSMA:
+		return o.Period + 1
+	default:
+		return 2
+	}
+}
+
+// AlertCondition is a comparison, or a group of conditions joined by AND
+// or OR.
+type AlertCondition struct {
+	// Logic is "and" or "or" for a group and empty for a comparison.
+	Logic    string           `json:"logic,omitempty"`
+	Terms    []AlertCondition `json:"terms,omitempty"`
+	Left     *AlertOperand    `json:"left,omitempty"`
+	Operator AlertOperator    `json:"operator,omitempty"`
+	Right    *AlertOperand    `json:"right,omitempty"`
+}
+
+// String returns the condition in canonical form. AND binds tighter than
+// OR, so only OR groups inside AND groups are parenthesized.
+func (c AlertCondition) String() string {
+	if c.Logic == "" {
+		return fmt.Sprintf("%s %s %s", c.Left, c.Operator, c.Right)
+	}
+	terms := make([]string, len(c.Terms))
+	for i, term := range c.Terms {
+		terms[i] = term.String()
+		if c.Logic == alertLogicAnd && term.Logic == alertLogicOr {
+			terms[i] = "(" + terms[i] + ")"
+		}
+	}
+	return strings.Join(terms, " "+strings.ToUpper(c.Logic)+" ")
+}
+
+// comparisons returns the condition's comparisons in order.
+func (c AlertCondition) comparisons() []AlertCondition {
+	if c.Logic == "" {
+		return []AlertCondition{c}
+	}
+	var all []AlertCondition
+	for _, term := range c.Terms {
+		all = append(all, term.comparisons()...)
+	}
+	return all
+}
+
+// evaluate reports whether the condition holds for the current operand
+// values. Crossings compare against the previous values and do not hold
+// without them.
+func (c AlertCondition) evaluate(current, previous map[string]float64) bool {
+	switch c.Logic {
+	case alertLogicAnd:
+		for _, term := range c.Terms {
+			if !term.evaluate(current, previous) {
+				return false
+			}
+		}
+		return true
+	case alertLogicOr:
+		for _, term := range c.Terms {
+			if term.evaluate(current, previous) {
+				return true
+			}
+		}
+		return false
+	}
+
+	left, okLeft := operandValue(*c.Left, current)
+	right, okRight := operandValue(*c.Right, current)
+	if !okLeft || !okRight {
+		return false
+	}
+	diff := left - right
+	switch c.Operator {
+	case AlertOperatorBelow:
+		return diff < 0
+	case AlertOperatorBelowOrEqual:
+		return diff <= 0
+	case AlertOperatorAbove:
+		return diff > 0
+	case AlertOperatorAboveOrEqual:
+		return diff >= 0
+	}
+
+	previousLeft, okLeft := operandValue(*c.Left, previous)
+	previousRight, okRight := operandValue(*c.Right, previous)
+	if !okLeft || !okRight {
+		return false
+	}
+	previousDiff := previousLeft - previousRight
+	up := previousDiff < 0 && diff >= 0
+	down := previousDiff > 0 && diff <= 0
+	switch c.Operator {
+	case AlertOperatorCrossesAbove:
+		return up
+	case AlertOperatorCrossesBelow:
+		return down
+	default:
+		return up || down
+	}
+}
+
+func operandValue(operand AlertOperand, values map[string]float64) (float64, bool) {
+	if operand.constant() {
+		return operand.Value, true
+	}
+	value, ok := values[operand.String()]
+	return value, ok
+}
+
+// AlertRule is a parsed user-defined alert, such as
+// "BTC/USDT RSI(14,1h) < 30" or
+// "ETH/USDT on bybit price crosses above SMA(200,1d) AND funding < 0".
+type AlertRule struct {
+	Symbol string `json:"symbol"`
+	// Exchange is empty for the configured default exchange.
+	Exchange  string         `json:"exchange,omitempty"`
+	Condition AlertCondition `json:"condition"`
+}
+
+// String returns the rule in canonical form, which ParseAlertRule accepts.
+func (r AlertRule) String() string {
+	var b strings.Builder
+	b.WriteString(r.Symbol)
+	if r.Exchange != "" {
+		b.WriteString(" on " + r.Exchange)
+	}
+	b.WriteString(" " + r.Condition.String())
+	return b.String()
+}
+
+// operands returns the rule's distinct indicators in order of appearance.
+func (r AlertRule) operands() []AlertOperand {
+	seen := make(map[string]bool)
+	var operands []AlertOperand
+	for _, comparison := range r.Condition.comparisons() {
+		for _, operand := range []AlertOperand{*comparison.Left, *comparison.Right} {
+			if operand.constant() || seen[operand.String()] {
+				continue
+			}
+			seen[operand.String()] = true
+			operands = append(operands, operand)
+		}
+	}
+	return operands
+}
+
+// step evaluates the rule for new values and reports whether it fires. A
+// rule fires when its condition becomes true; armed is false while the
+// condition stays true after firing.
+func (r AlertRule) step(current, previous map[string]float64, armed bool) (fires, rearmed bool) {
+	met := r.Condition.evaluate(current, previous)
+	return met && armed, !met
+}
+
+// ParseAlertRule parses an alert rule written as
+// "SYMBOL [on EXCHANGE] CONDITION". A condition compares two operands with
+// <, <=, >, >=, crosses, crosses above or crosses below, and conditions are
+// combined with AND, OR and parentheses. Operands are numbers, price,
+// RSI/SMA/EMA[(PERIOD[,TIMEFRAME])], funding (the funding rate in percent)
+// and spread(EXCHANGE) (the other exchange's premium in percent). A leading
+// "notify me when" or similar phrasing is ignored.
+//
+// Parameters:
+//
+//	text: The rule as typed by the user.
+//
+// Returns:
+//
+//	AlertRule: The parsed rule with defaults applied.
+//	error: ErrInvalidAlertRule describing what is wrong.
+func ParseAlertRule(text string) (AlertRule, error) {
+	expression := strings.Join(strings.Fields(text), " ")
+	lower := strings.ToLower(expression)
+	for _, prefix := range alertRulePrefixes {
+		if strings.HasPrefix(lower, prefix+" ") {
+			expression = expression[len(prefix)+1:]
+			break
+		}
+	}
+	expression = strings.Trim(expression, " \"'")
+
+	tokens, err := lexAlertRule(expression)
+	if err != nil {
+		return AlertRule{}, err
+	}
+	if len(tokens) < 2 || tokens[0].kind != alertTokenWord {
+		return AlertRule{}, fmt.Errorf("%w: expected e.g. \"BTC/USDT RSI(14,1h) < 30\" or \"BTC/USDT price crosses 70000\"", ErrInvalidAlertRule)
+	}
+
+	symbol := NormalizeSymbol(tokens[0].text)
+	if !isValidSymbolFormat(symbol) || len(symbol) > 32 {
+		return AlertRule{}, fmt.Errorf("%w: invalid symbol %q", ErrInvalidAlertRule, tokens[0].text)
+	}
+	rule := AlertRule{Symbol: symbol}
+	p := &alertParser{tokens: tokens, pos: 1}
+	if p.keyword("on") {
+		exchange := p.next()
+		if exchange.kind != alertTokenWord || !validAlertExchange(exchange.text) {
+			return AlertRule{}, fmt.Errorf("%w: expected an exchange after \"on\"", ErrInvalidAlertRule)
+		}
+		rule.Exchange = strings.ToLower(exchange.text)
+	}
+
+	condition, err := p.parseOr()
+	if err != nil {
+		return AlertRule{}, err
+	}
+	if p.pos < len(p.tokens) {
+		return AlertRule{}, fmt.Errorf("%w: unexpected %q", ErrInvalidAlertRule, p.tokens[p.pos].text)
+	}
+	for _, comparison := range condition.comparisons() {
+		for _, operand := range []*AlertOperand{comparison.Left, comparison.Right} {
+			if operand.Indicator == AlertIndicatorSpread && operand.Exchange == rule.Exchange {
+				return AlertRule{}, fmt.Errorf("%w: spread needs an exchange other than %s", ErrInvalidAlertRule, rule.Exchange)
+			}
+		}
+	}
+	rule.Condition = condition
+	return rule, nil
+}
+
+func validAlertExchange(name string) bool {
+	if name == "" || len(name) > 32 {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+type alertTokenKind int
+
+const (
+	alertTokenWord alertTokenKind = iota
+	alertTokenNumber
+	alertTokenOperator
+	alertTokenOpen
+	alertTokenClose
+	alertTokenComma
+)
+
+type alertToken struct {
+	kind   alertTokenKind
+	text   string
+	number float64
+}
+
+func isAlertWordRune(r byte) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.IndexByte("/_-:.", r) >= 0
+}
+
+func isAlertDigit(r byte) bool {
+	return r >= '0' && r <= '9'
+}
+
+// lexAlertRule splits a rule into tokens. Outside parentheses a comma
+// followed by three digits is a thousands separator, so "70,000" is one
+// number while "RSI(14,1h)" keeps its argument separator.
+func lexAlertRule(text string) ([]alertToken, error) {
+	var tokens []alertToken
+	depth := 0
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == ' ':
+			i++
+		case c == '(':
+			depth++
+			tokens = append(tokens, alertToken{kind: alertTokenOpen, text: "("})
+			i++
+		case c == ')':
+			depth--
+			tokens = append(tokens, alertToken{kind: alertTokenClose, text: ")"})
+			i++
+		case c == ',':
+			tokens = append(tokens, alertToken{kind: alertTokenComma, text: ","})
+			i++
+		case c == '<' || c == '>':
+			op := string(c)
+			if i+1 < len(text) && text[i+1] == '=' {
+				op += "="
+			}
+			tokens = append(tokens, alertToken{kind: alertTokenOperator, text: op})
+			i += len(op)
+		case strings.HasPrefix(text[i:], "&&"):
+			tokens = append(tokens, alertToken{kind: alertTokenWord, text: alertLogicAnd})
+			i += 2
+		case strings.HasPrefix(text[i:], "||"):
+			tokens = append(tokens, alertToken{kind: alertTokenWord, text: alertLogicOr})
+			i += 2
+		case isAlertDigit(c) || (c == '-' || c == '.') && i+1 < len(text) && (isAlertDigit(text[i+1]) || text[i+1] == '.'):
+			start := i
+			i++
+			for i < len(text) {
+				if isAlertDigit(text[i]) || text[i] == '.' || text[i] == '_' {
+					i++
+				} else if text[i] == ',' && depth == 0 && i+4 <= len(text) &&
+					isAlertDigit(text[i+1]) && isAlertDigit(text[i+2]) && isAlertDigit(text[i+3]) &&
+					(i+4 == len(text) || !isAlertWordRune(text[i+4])) {
+					i += 4
+				} else {
+					break
+				}
+			}
+			if i < len(text) && isAlertWordRune(text[i]) && c != '-' {
+				// Words such as "1h" or "1INCH/USDT" start with digits
+				for i < len(text) && isAlertWordRune(text[i]) {
+					i++
+				}
+				tokens = append(tokens, alertToken{kind: alertTokenWord, text: text[start:i]})
+				continue
+			}
+			raw := strings.NewReplacer(",", "", "_", "").Replace(text[start:i])
+			number, err := strconv.ParseFloat(raw, 64)
+			if err != nil || math.IsInf(number, 0) {
+				return nil, fmt.Errorf("%w: invalid number %q", ErrInvalidAlertRule, text[start:i])
+			}
+			tokens = append(tokens, alertToken{kind: alertTokenNumber, text: text[start:i], number: number})
+		case isAlertWordRune(c):
+			start := i
+			for i < len(text) && isAlertWordRune(text[i]) {
+				i++
+			}
+			tokens = append(tokens, alertToken{kind: alertTokenWord, text: text[start:i]})
+		default:
+			return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidAlertRule, string(c))
+		}
+	}
+	return tokens, nil
+}
+
+// alertParser is a recursive descent parser over the tokens of a rule.
+type alertParser struct {
+	tokens      []alertToken
+	pos         int
+	comparisons int
+}
+
+func (p *alertParser) peek() (alertToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return alertToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *alertParser) next() alertToken {
+	token, _ := p.peek()
+	p.pos++
+	return token
+}
+
+// keyword consumes the next token if it is the given word.
+func (p *alertParser) keyword(word string) bool {
+	token, ok := p.peek()
+	if ok && token.kind == alertTokenWord && strings.EqualFold(token.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *alertParser) parseOr() (AlertCondition, error) {
+	return p.parseGroup(alertLogicOr, p.parseAnd)
+}
+
+func (p *alertParser) parseAnd() (AlertCondition, error) {
+	return p.parseGroup(alertLogicAnd, p.parsePrimary)
+}
+
+// parseGroup parses terms joined by logic, flattening nested groups of the
+// same logic.
+func (p *alertParser) parseGroup(logic string, term func() (AlertCondition, error)) (AlertCondition, error) {
+	first, err := term()
+	if err != nil {
+		return AlertCondition{}, err
+	}
+	group := AlertCondition{Logic: logic}
+	add := func(c AlertCondition) {
+		if c.Logic == logic {
+			group.Terms = append(group.Terms, c.Terms...)
+		} else {
+			group.Terms = append(group.Terms, c)
+		}
+	}
+	add(first)
+	for p.keyword(logic) {
+		next, err := term()
+		if err != nil {
+			return AlertCondition{}, err
+		}
+		add(next)
+	}
+	if len(group.Terms) == 1 {
+		return group.Terms[0], nil
+	}
+	return group, nil
+}
+
+func (p *alertParser) parsePrimary() (AlertCondition, error) {
+	if token, ok := p.peek(); ok && token.kind == alertTokenOpen {
+		p.pos++
+		condition, err := p.parseOr()
+		if err != nil {
+			return AlertCondition{}, err
+		}
+		if p.next().kind != alertTokenClose {
+			return AlertCondition{}, fmt.Errorf("%w: missing \")\"", ErrInvalidAlertRule)
+		}
+		return condition, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *alertParser) parseComparison() (AlertCondition, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return AlertCondition{}, err
+	}
+	operator, err := p.parseOperator()
+	if err != nil {
+		return AlertCondition{}, err
+	}
+	right, err := p.parseOperand()
+	if err != nil {
+		return AlertCondition{}, err
+	}
+
+	p.comparisons++
+	if p.comparisons > maxAlertComparisons {
+		return AlertCondition{}, fmt.Errorf("%w: at most %d comparisons per rule", ErrInvalidAlertRule, maxAlertComparisons)
+	}
+	if left.constant() && right.constant() {
+		return AlertCondition{}, fmt.Errorf("%w: compare an indicator with a number or another indicator", ErrInvalidAlertRule)
+	}
+	if !left.constant() && !right.constant() && left.unit() != right.unit() {
+		return AlertCondition{}, fmt.Errorf("%w: cannot compare %s with %s", ErrInvalidAlertRule, left, right)
+	}
+	for _, pair := range [][2]AlertOperand{{left, right}, {right, left}} {
+		indicator, value := pair[0], pair[1]
+		if !value.constant() || indicator.constant() || indicator.percent() {
+			continue
+		}
+		if value.Value <= 0 {
+			return AlertCondition{}, fmt.Errorf("%w: invalid value %s for %s", ErrInvalidAlertRule, value, indicator)
+		}
+		if indicator.Indicator == AlertIndicatorRSI && value.Value >= 100 {
+			return AlertCondition{}, fmt.Errorf("%w: RSI ranges from 0 to 100", ErrInvalidAlertRule)
+		}
+	}
+	return AlertCondition{Left: &left, Operator: operator, Right: &right}, nil
+}
+
+func (p *alertParser) parseOperator() (AlertOperator, error) {
+	token, ok := p.peek()
+	if !ok {
+		return "", fmt.Errorf("%w: missing comparison, e.g. \"< 30\" or \"crosses 70000\"", ErrInvalidAlertRule)
+	}
+	if token.kind == alertTokenOperator {
+		p.pos++
+		return AlertOperator(token.text), nil
+	}
+	if p.keyword("crosses") {
+		switch {
+		case p.keyword("above"):
+			return AlertOperatorCrossesAbove, nil
+		case p.keyword("below"):
+			return AlertOperatorCrossesBelow, nil
+		}
+		return AlertOperatorCrosses, nil
+	}
+	return "", fmt.Errorf("%w: expected a comparison instead of %q", ErrInvalidAlertRule, token.text)
+}
+
+func (p *alertParser) parseOperand() (AlertOperand, error) {
+	token, ok := p.peek()
+	if !ok {
+		return AlertOperand{}, fmt.Errorf("%w: rule ends early", ErrInvalidAlertRule)
+	}
+	p.pos++
+	if token.kind == alertTokenNumber {
+		return AlertOperand{Indicator: AlertIndicatorValue, Value: token.number}, nil
+	}
+	if token.kind != alertTokenWord {
+		return AlertOperand{}, fmt.Errorf("%w: unexpected %q", ErrInvalidAlertRule, token.text)
+	}
+
+	indicator := AlertIndicator(strings.ToLower(token.text))
+	operand := AlertOperand{Indicator: indicator}
+	defaults := alertIndicatorDefaults[indicator]
+	operand.Period, operand.Timeframe = defaults.period, defaults.timeframe
+
+	args, err := p.parseArguments()
+	if err != nil {
+		return AlertOperand{}, err
+	}
+	switch indicator {
+	case AlertIndicatorPrice, AlertIndicatorFunding:
+		if len(args) > 0 {
+			return AlertOperand{}, fmt.Errorf("%w: %s takes no period", ErrInvalidAlertRule, indicator)
+		}
+	case AlertIndicatorSpread:
+		if len(args) != 1 || args[0].kind != alertTokenWord || !validAlertExchange(args[0].text) {
+			return AlertOperand{}, fmt.Errorf("%w: spread needs the other exchange, e.g. spread(okx)", ErrInvalidAlertRule)
+		}
+		operand.Exchange = strings.ToLower(args[0].text)
+	case AlertIndicatorRSI, AlertIndicatorSMA, AlertIndicatorEMA:
+		if len(args) > 2 {
+			return AlertOperand{}, fmt.Errorf("%w: %s takes a period and a timeframe", ErrInvalidAlertRule, indicator)
+		}
+		for i, arg := range args {
+			if arg.kind == alertTokenNumber && i == 0 {
+				period := int(arg.number)
+				if float64(period) != arg.number || period < 2 || period > 200 {
+					return AlertOperand{}, fmt.Errorf("%w: period must be between 2 and 200", ErrInvalidAlertRule)
+				}
+				operand.Period = period
+				continue
+			}
+			operand.Timeframe = strings.ToLower(arg.text)
+			if _, ok := alertTimeframes[operand.Timeframe]; !ok || arg.kind != alertTokenWord {
+				return AlertOperand{}, fmt.Errorf("%w: timeframe must be one of 1m, 5m, 15m, 30m, 1h, 4h or 1d", ErrInvalidAlertRule)
+			}
+		}
+	default:
+		return AlertOperand{}, fmt.Errorf("%w: unknown indicator %q; use price, RSI, SMA, EMA, funding or spread", ErrInvalidAlertRule, token.text)
+	}
+	return operand, nil
+}
+
+// parseArguments parses an optional parenthesized, comma separated list.
+func (p *alertParser) parseArguments() ([]alertToken, error) {
+	if token, ok := p.peek(); !ok || token.kind != alertTokenOpen {
+		return nil, nil
+	}
+	p.pos++
+	var args []alertToken
+	for {
+		token := p.next()
+		switch {
+		case token.kind == alertTokenClose && len(args) == 0:
+			return args, nil
+		case token.kind != alertTokenWord && token.kind != alertTokenNumber:
+			return nil, fmt.Errorf("%w: malformed indicator arguments", ErrInvalidAlertRule)
+		}
+		args = append(args, token)
+		switch p.next().kind {
+		case alertTokenClose:
+			return args, nil
+		case alertTokenComma:
+		default:
+			return nil, fmt.Errorf("%w: malformed indicator arguments", ErrInvalidAlertRule)
+		}
+	}
+}
+
+// alertSeries is an indicator over time, oldest first. Each value is known
+// from its time on.
+type alertSeries struct {
+	times  []time.Time
+	values []float64
+}
+
+func (s *alertSeries) add(at time.Time, value float64) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return
+	}
+	s.times = append(s.times, at)
+	s.values = append(s.values, value)
+}
+
+// last returns the latest value.
+func (s alertSeries) last() (float64, bool) {
+	if len(s.values) == 0 {
+		return 0, false
+	}
+	return s.values[len(s.values)-1], true
+}
+
+// at returns the value known at t.
+func (s alertSeries) at(t time.Time) (float64, bool) {
+	i := sort.Search(len(s.times), func(i int) bool { return s.times[i].After(t) })
+	if i == 0 {
+		return 0, false
+	}
+	return s.values[i-1], true
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAlertRule(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"notify me when BTC/USDT RSI(14,1h) < 30", "BTC/USDT RSI(14,1h) < 30"},
+		{"btcusdt price crosses 70,000", "BTC/USDT price crosses 70000"},
+		{"if ETH/USDT on OKX ema(50, 4h)  crosses  below 3000.5", "ETH/USDT on okx EMA(50,4h) crosses below 3000.5"},
+		{"SOL/USDT SMA >= 150", "SOL/USDT SMA(20,1h) >= 150"},
+		{"1INCH/USDT rsi(4h) > 70", "1INCH/USDT RSI(14,4h) > 70"},
+		{"BTC/USDT price > SMA(200,1d) and RSI < 70", "BTC/USDT price > SMA(200,1d) AND RSI(14,1h) < 70"},
+		{"BTC/USDT funding < -0.01 || spread(okx) > 0.5", "BTC/USDT funding < -0.01 OR spread(okx) > 0.5"},
+		{
+			"BTC/USDT (price crosses above EMA(50,1h) or RSI < 30) and (funding < 0 and spread(bybit) >= 0.2)",
+			"BTC/USDT (price crosses above EMA(50,1h) OR RSI(14,1h) < 30) AND funding < 0 AND spread(bybit) >= 0.2",
+		},
+		{"BTC/USDT price < 60000 or price > 80000 and RSI > 70", "BTC/USDT price < 60000 OR price > 80000 AND RSI(14,1h) > 70"},
+	}
+	for _, tt := range tests {
+		got, err := ParseAlertRule(tt.text)
+		require.NoError(t, err, tt.text)
+		assert.Equal(t, tt.want, got.String(), tt.text)
+
+		// The canonical form parses back to the same rule
+		again, err := ParseAlertRule(got.String())
+		require.NoError(t, err, got.String())
+		assert.Equal(t, got, again)
+	}
+
+	for _, text := range []string{
+		"",
+		"BTC/USDT RSI(14,1h)",
+		"BTC/USDT MACD < 1",
+		"BTC/USDT price(14) > 1",
+		"BTC/USDT RSI(1,1h) < 30",
+		"BTC/USDT RSI(14,2w) < 30",
+		"BTC/USDT RSI < 120",
+		"BTC/USDT price > 0",
+		"BTC/USDT 1 < 2",
+		"BTC/USDT price > RSI",
+		"BTC/USDT (price > 1",
+		"BTC/USDT price > 1 AND",
+		"BTC/USDT price > 1 RSI < 30",
+		"BTC/USDT spread > 1",
+		"BTC/USDT on okx spread(okx) > 1",
+		"BTC/USDT price > 1 and price > 2 and price > 3 and price > 4 and price > 5 and price > 6 and price > 7 and price > 8 and price > 9",
+	} {
+		_, err := ParseAlertRule(text)
+		assert.ErrorIs(t, err, ErrInvalidAlertRule, text)
+	}
+}
+
+func TestAlertCondition_Evaluate(t *testing.T) {
+	rule, err := ParseAlertRule("BTC/USDT price crosses above SMA(20,1h) AND (RSI < 40 OR funding < 0)")
+	require.NoError(t, err)
+	values := func(price, sma, rsi, funding float64) map[string]float64 {
+		return map[string]float64{"price": price, "SMA(20,1h)": sma, "RSI(14,1h)": rsi, "funding": funding}
+	}
+
+	below := values(99, 100, 35, 0.01)
+	assert.False(t, rule.Condition.evaluate(below, nil))
+	assert.False(t, rule.Condition.evaluate(values(101, 100, 35, 0.01), nil), "a crossing needs the previous values")
+	assert.True(t, rule.Condition.evaluate(values(101, 100, 35, 0.01), below))
+	assert.True(t, rule.Condition.evaluate(values(101, 100, 45, -0.01), below))
+	assert.False(t, rule.Condition.evaluate(values(101, 100, 45, 0.01), below))
+	assert.False(t, rule.Condition.evaluate(values(102, 100, 35, 0.01), values(101, 100, 35, 0.01)), "already above")
+	assert.False(t, rule.Condition.evaluate(map[string]float64{"price": 101}, below), "missing operands do not hold")
+}
+
+func TestAlertRule_Step(t *testing.T) {
+	rule, err := ParseAlertRule("BTC/USDT price > 100 OR RSI < 30")
+	require.NoError(t, err)
+	armed := true
+	var fired []bool
+	for _, v := range [][2]float64{{99, 50}, {101, 50}, {101, 25}, {99, 50}, {99, 25}} {
+		var fires bool
+		fires, armed = rule.step(map[string]float64{"price": v[0], "RSI(14,1h)": v[1]}, nil, armed)
+		fired = append(fired, fires)
+	}
+	assert.Equal(t, []bool{false, true, false, false, true}, fired, "fires when the condition becomes true")
+}
+
+func TestAlertSeries_At(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	var series alertSeries
+	series.add(start, 1)
+	series.add(start.Add(time.Hour), 2)
+
+	_, ok := series.at(start.Add(-time.Minute))
+	assert.False(t, ok)
+	value, _ := series.at(start.Add(30 * time.Minute))
+	assert.Equal(t, 1.0, value)
+	value, _ = series.at(start.Add(time.Hour))
+	assert.Equal(t, 2.0, value)
+}
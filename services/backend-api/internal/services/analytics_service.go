@@ -19,6 +19,7 @@ type AnalyticsQuerier interface {
 }
 
 // AnalyticsService provides correlation, regime detection, and forecasting utilities.
+// Its history reads are tagged as the analytics workload and may be served by a read replica.
 type AnalyticsService struct {
 	db     AnalyticsQuerier
 	config config.AnalyticsConfig
@@ -314,7 +315,7 @@ func (s *AnalyticsService) getPriceSeries(ctx context.Context, exchange string,
 		LIMIT $3
 	`
 
-	rows, err := s.db.Query(ctx, query, symbol, exchange, limit)
+	rows, err := s.db.Query(database.WithWorkload(ctx, database.WorkloadAnalytics), query, symbol, exchange, limit)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -353,7 +354,7 @@ func (s *AnalyticsService) getFundingRateSeries(ctx context.Context, exchange st
 		LIMIT $3
 	`
 
-	rows, err := s.db.Query(ctx, query, symbol, exchange, limit)
+	rows, err := s.db.Query(database.WithWorkload(ctx, database.WorkloadAnalytics), query, symbol, exchange, limit)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/irfndi/neuratrade/internal/models"
+)
+
+// minArbitrageScanChunk is the fewest symbols one scan worker takes; smaller
+// universes are scanned on the calling goroutine.
+const minArbitrageScanChunk = 256
+
+// arbitrageScanPrefilter is the float profit percentage below which a symbol
+// is dropped without the exact decimal check. It sits under the 0.1%
+// threshold so float rounding never drops a real opportunity.
+const arbitrageScanPrefilter = 0.099
+
+var (
+	spotMinProfitPercentage = decimal.NewFromFloat(0.1)
+	decimalHundred          = decimal.NewFromInt(100)
+)
+
+// pow10Float holds the powers of ten exactly representable as float64.
+var pow10Float = [...]float64{1e0, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10, 1e11, 1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18, 1e19, 1e20, 1e21, 1e22}
+
+// arbitrageScan holds the columnar buffers of one spot arbitrage scan. The
+// prices of all symbols are laid out back to back so finding the cheapest
+// and dearest exchange per symbol is a tight loop over a float64 slice, and
+// the buffers are kept between scans so a cycle allocates only for the
+// opportunities it returns.
+type arbitrageScan struct {
+	// prices holds the last price of every usable quote, grouped by symbol.
+	prices []float64
+	// quotes holds the market data behind each price.
+	quotes []*models.MarketData
+	// offsets delimits symbol i as prices[offsets[i]:offsets[i+1]].
+	offsets []int
+	// lowest and highest hold, per symbol, the index of the cheapest and
+	// dearest quote, or -1 when the symbol has no usable quotes.
+	lowest  []int
+	highest []int
+}
+
+// load lays out the market data column-wise, skipping quotes without a
+// trading pair and symbols quoted on fewer than two exchanges.
+func (s *arbitrageScan) load(marketData map[string][]models.MarketData) {
+	s.prices = s.prices[:0]
+	s.quotes = s.quotes[:0]
+	s.offsets = append(s.offsets[:0], 0)
+	for _, exchangeData := range marketData {
+		if len(exchangeData) < 2 {
+			continue
+		}
+		for i := range exchangeData {
+			if exchangeData[i].TradingPair == nil {
+				continue
+			}
+			s.prices = append(s.prices, decimalToFloat64(exchangeData[i].LastPrice))
+			s.quotes = append(s.quotes, &exchangeData[i])
+		}
+		s.offsets = append(s.offsets, len(s.prices))
+	}
+
+	symbols := len(s.offsets) - 1
+	if cap(s.lowest) < symbols {
+		s.lowest = make([]int, symbols)
+		s.highest = make([]int, symbols)
+	}
+	s.lowest = s.lowest[:symbols]
+	s.highest = s.highest[:symbols]
+}
+
+// compare finds the cheapest and dearest quote of each symbol, splitting the
+// symbols into chunks compared in parallel.
+func (s *arbitrageScan) compare(ctx context.Context, workers int) error {
+	symbols := len(s.lowest)
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	chunk := (symbols + workers - 1) / workers
+	if chunk < minArbitrageScanChunk {
+		chunk = minArbitrageScanChunk
+	}
+	if symbols <= chunk {
+		s.compareRange(0, symbols)
+		return ctx.Err()
+	}
+
+	var wg sync.WaitGroup
+	for start := 0; start < symbols; start += chunk {
+		if err := ctx.Err(); err != nil {
+			wg.Wait()
+			return err
+		}
+		end := min(start+chunk, symbols)
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			s.compareRange(start, end)
+		}(start, end)
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// compareRange scans symbols [start, end). Ties keep the first quote, as
+// the exchanges are listed in the market data.
+func (s *arbitrageScan) compareRange(start, end int) {
+	for symbol := start; symbol < end; symbol++ {
+		from, to := s.offsets[symbol], s.offsets[symbol+1]
+		if from == to {
+			s.lowest[symbol], s.highest[symbol] = -1, -1
+			continue
+		}
+		prices := s.prices[from:to]
+		low, high := 0, 0
+		lowPrice, highPrice := prices[0], prices[0]
+		for i, price := range prices[1:] {
+			if price < lowPrice {
+				low, lowPrice = i+1, price
+			}
+			if price > highPrice {
+				high, highPrice = i+1, price
+			}
+		}
+		s.lowest[symbol], s.highest[symbol] = from+low, from+high
+	}
+}
+
+// candidate reports whether symbol may clear the profit threshold.
+func (s *arbitrageScan) candidate(symbol int) bool {
+	low, high := s.lowest[symbol], s.highest[symbol]
+	if low < 0 || s.prices[low] <= 0 {
+		return false
+	}
+	return (s.prices[high]-s.prices[low])/s.prices[low]*100 >= arbitrageScanPrefilter
+}
+
+// release drops the references to the scanned market data so it can be
+// collected while the buffers wait for the next scan.
+func (s *arbitrageScan) release() {
+	clear(s.quotes)
+}
+
+// decimalToFloat64 converts a price without allocating for the usual case of
+// a coefficient and exponent float64 holds exactly, and falls back to the
+// exact rational conversion otherwise.
+func decimalToFloat64(d decimal.Decimal) float64 {
+	exp := d.Exponent()
+	if d.NumDigits() <= 15 && exp >= -22 && exp <= 22 {
+		coefficient := float64(d.CoefficientInt64())
+		if exp < 0 {
+			return coefficient / pow10Float[-exp]
+		}
+		return coefficient * pow10Float[exp]
+	}
+	return d.InexactFloat64()
+}
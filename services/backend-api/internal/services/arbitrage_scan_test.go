@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfndi/neuratrade/internal/models"
+)
+
+// arbitrageUniverse builds market data for symbols quoted on exchanges; every
+// tenth symbol has a 1% spread between its first and last exchange.
+func arbitrageUniverse(symbols, exchanges int) map[string][]models.MarketData {
+	marketData := make(map[string][]models.MarketData, symbols)
+	for s := 0; s < symbols; s++ {
+		symbol := fmt.Sprintf("SYM%d/USDT", s)
+		pair := &models.TradingPair{ID: s + 1, Symbol: symbol}
+		quotes := make([]models.MarketData, exchanges)
+		for e := 0; e < exchanges; e++ {
+			price := decimal.NewFromInt(int64(1000 + s)).Add(decimal.New(int64(e), -2))
+			if s%10 == 0 && e == exchanges-1 {
+				price = decimal.NewFromInt(int64(1000 + s)).Mul(decimal.RequireFromString("1.01"))
+			}
+			quotes[e] = models.MarketData{
+				LastPrice:   price,
+				Exchange:    &models.Exchange{ID: e + 1, Name: fmt.Sprintf("exchange-%d", e)},
+				TradingPair: pair,
+			}
+		}
+		marketData[symbol] = quotes
+	}
+	return marketData
+}
+
+func opportunitiesByPair(opportunities []models.ArbitrageOpportunity) map[int]models.ArbitrageOpportunity {
+	byPair := make(map[int]models.ArbitrageOpportunity, len(opportunities))
+	for _, opportunity := range opportunities {
+		byPair[opportunity.TradingPairID] = opportunity
+	}
+	return byPair
+}
+
+func TestSpotArbitrageCalculator_ParallelMatchesSequential(t *testing.T) {
+	marketData := arbitrageUniverse(3000, 6)
+
+	sequential := &SpotArbitrageCalculator{Workers: 1}
+	parallel := &SpotArbitrageCalculator{Workers: 8}
+	expected, err := sequential.CalculateArbitrageOpportunities(context.Background(), marketData)
+	require.NoError(t, err)
+	actual, err := parallel.CalculateArbitrageOpportunities(context.Background(), marketData)
+	require.NoError(t, err)
+
+	require.Len(t, expected, 300)
+	require.Len(t, actual, 300)
+	byPair := opportunitiesByPair(actual)
+	for _, want := range expected {
+		got, ok := byPair[want.TradingPairID]
+		require.True(t, ok, "missing opportunity for pair %d", want.TradingPairID)
+		assert.Equal(t, want.BuyExchangeID, got.BuyExchangeID)
+		assert.Equal(t, want.SellExchangeID, got.SellExchangeID)
+		assert.True(t, want.ProfitPercentage.Equal(got.ProfitPercentage))
+	}
+	assert.Equal(t, 1, expected[0].BuyExchangeID)
+	assert.Equal(t, 6, expected[0].SellExchangeID)
+}
+
+func TestSpotArbitrageCalculator_ReusesBuffersAcrossScans(t *testing.T) {
+	calculator := &SpotArbitrageCalculator{Workers: 4}
+	_, err := calculator.CalculateArbitrageOpportunities(context.Background(), arbitrageUniverse(2000, 4))
+	require.NoError(t, err)
+	capacity := cap(calculator.scan.prices)
+
+	// A smaller universe must not see results left over from the larger one
+	opportunities, err := calculator.CalculateArbitrageOpportunities(context.Background(), arbitrageUniverse(20, 4))
+	require.NoError(t, err)
+	assert.Len(t, opportunities, 2)
+	assert.Equal(t, capacity, cap(calculator.scan.prices))
+	for _, quote := range calculator.scan.quotes[:cap(calculator.scan.quotes)] {
+		assert.Nil(t, quote, "scanned market data should be released")
+	}
+}
+
+func TestSpotArbitrageCalculator_CanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	opportunities, err := NewSpotArbitrageCalculator().CalculateArbitrageOpportunities(ctx, arbitrageUniverse(10, 2))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, opportunities)
+}
+
+func TestDecimalToFloat64(t *testing.T) {
+	for _, value := range []string{"0", "50000", "50000.5", "0.00001234", "-12.75", "123456789012345678901234567890", "1e30", "0.000000000000000000000000001"} {
+		d := decimal.RequireFromString(value)
+		assert.InDelta(t, d.InexactFloat64(), decimalToFloat64(d), 1e-9*max(1, d.Abs().InexactFloat64()), value)
+	}
+}
+
+// BenchmarkSpotArbitrageCalculator benchmarks a full-universe scan of 5000
+// symbols quoted on 8 exchanges.
+func BenchmarkSpotArbitrageCalculator(b *testing.B) {
+	marketData := arbitrageUniverse(5000, 8)
+	calculator := NewSpotArbitrageCalculator()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := calculator.CalculateArbitrageOpportunities(ctx, marketData); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
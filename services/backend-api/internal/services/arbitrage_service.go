@@ -29,7 +29,14 @@ type ArbitrageCalculator interface {
 
 // SpotArbitrageCalculator implements arbitrage calculations for spot markets.
 // Compares prices across exchanges to identify profitable trades.
-type SpotArbitrageCalculator struct{}
+type SpotArbitrageCalculator struct {
+	// Workers caps the goroutines comparing symbols in parallel; zero or
+	// less uses GOMAXPROCS.
+	Workers int
+
+	mu   sync.Mutex
+	scan arbitrageScan
+}
 
 // NewSpotArbitrageCalculator creates a new spot arbitrage calculator.
 //
@@ -40,7 +47,9 @@ func NewSpotArbitrageCalculator() *SpotArbitrageCalculator {
 }
 
 // CalculateArbitrageOpportunities calculates arbitrage opportunities from market data.
-// It compares prices across exchanges to find profitable trades.
+// It compares prices across exchanges to find profitable trades. Prices are
+// laid out in reused columnar buffers and symbols are compared in parallel
+// chunks; the profit of each candidate is then checked exactly in decimal.
 //
 // Parameters:
 //
@@ -52,59 +61,43 @@ func NewSpotArbitrageCalculator() *SpotArbitrageCalculator {
 //	[]models.ArbitrageOpportunity: List of opportunities.
 //	error: Error if calculation fails.
 func (calc *SpotArbitrageCalculator) CalculateArbitrageOpportunities(ctx context.Context, marketData map[string][]models.MarketData) ([]models.ArbitrageOpportunity, error) {
-	var opportunities []models.ArbitrageOpportunity
-
-	// For each symbol, find arbitrage opportunities between exchanges
-	for _, exchangeData := range marketData {
-		if len(exchangeData) < 2 {
-			continue
-		}
+	calc.mu.Lock()
+	defer calc.mu.Unlock()
 
-		// Find the lowest and highest prices for this symbol
-		var lowestPrice, highestPrice decimal.Decimal
-		var lowestExchange, highestExchange *models.Exchange
-		var lowestPair *models.TradingPair
-
-		for _, data := range exchangeData {
-			// Skip data with nil trading pair
-			if data.TradingPair == nil {
-				continue
-			}
-
-			if lowestExchange == nil || data.LastPrice.LessThan(lowestPrice) {
-				lowestPrice = data.LastPrice
-				lowestExchange = data.Exchange
-				lowestPair = data.TradingPair
-			}
+	scan := &calc.scan
+	scan.load(marketData)
+	defer scan.release()
+	if err := scan.compare(ctx, calc.Workers); err != nil {
+		return nil, err
+	}
 
-			if highestExchange == nil || data.LastPrice.GreaterThan(highestPrice) {
-				highestPrice = data.LastPrice
-				highestExchange = data.Exchange
-			}
+	var opportunities []models.ArbitrageOpportunity
+	now := time.Now()
+	for symbol := range scan.lowest {
+		if !scan.candidate(symbol) {
+			continue
 		}
+		lowest, highest := scan.quotes[scan.lowest[symbol]], scan.quotes[scan.highest[symbol]]
 
 		// Calculate profit percentage
-		if !lowestPrice.IsZero() {
-			profitPercentage := highestPrice.Sub(lowestPrice).Div(lowestPrice).Mul(decimal.NewFromInt(100))
-
-			// Only consider opportunities with meaningful profit
-			if profitPercentage.GreaterThan(decimal.NewFromFloat(0.1)) {
-				opportunity := models.ArbitrageOpportunity{
-					ID:               uuid.New().String(),
-					BuyExchangeID:    lowestExchange.ID,
-					SellExchangeID:   highestExchange.ID,
-					TradingPairID:    lowestPair.ID,
-					BuyPrice:         lowestPrice,
-					SellPrice:        highestPrice,
-					ProfitPercentage: profitPercentage,
-					DetectedAt:       time.Now(),
-					ExpiresAt:        time.Now().Add(5 * time.Minute),
-					BuyExchange:      lowestExchange,
-					SellExchange:     highestExchange,
-					TradingPair:      lowestPair,
-				}
-				opportunities = append(opportunities, opportunity)
-			}
+		profitPercentage := highest.LastPrice.Sub(lowest.LastPrice).Div(lowest.LastPrice).Mul(decimalHundred)
+
+		// Only consider opportunities with meaningful profit
+		if profitPercentage.GreaterThan(spotMinProfitPercentage) {
+			opportunities = append(opportunities, models.ArbitrageOpportunity{
+				ID:               uuid.New().String(),
+				BuyExchangeID:    lowest.Exchange.ID,
+				SellExchangeID:   highest.Exchange.ID,
+				TradingPairID:    lowest.TradingPair.ID,
+				BuyPrice:         lowest.LastPrice,
+				SellPrice:        highest.LastPrice,
+				ProfitPercentage: profitPercentage,
+				DetectedAt:       now,
+				ExpiresAt:        now.Add(5 * time.Minute),
+				BuyExchange:      lowest.Exchange,
+				SellExchange:     highest.Exchange,
+				TradingPair:      lowest.TradingPair,
+			})
 		}
 	}
 
@@ -140,6 +133,7 @@ type ArbitrageService struct {
 	lastCalculation    time.Time
 	opportunitiesFound int
 	multiLegCalculator *MultiLegArbitrageCalculator
+	lifecycle          *OpportunityLifecycleService
 }
 
 type readOnlyDBPoolAdapter struct {
@@ -233,6 +227,12 @@ func NewArbitrageService(db any, cfg *config.Config, calculator ArbitrageCalcula
 	}
 }
 
+// SetLifecycleService persists each cycle's opportunities with their
+// lifecycle so detected edges can be compared with what was executed.
+func (s *ArbitrageService) SetLifecycleService(lifecycle *OpportunityLifecycleService) {
+	s.lifecycle = lifecycle
+}
+
 // Start begins the periodic arbitrage calculation.
 //
 // Returns:
@@ -449,6 +449,13 @@ func (s *ArbitrageService) calculateAndStoreOpportunities() error {
 	}
 	observability.FinishSpan(storeSpan, nil)
 
+	// Step 6: Open, update and expire opportunity lifecycles
+	if s.lifecycle != nil {
+		if err := s.lifecycle.ObserveCycle(s.ctx, validOpportunities); err != nil {
+			s.logger.WithError(err).Warn("Failed to record opportunity lifecycles")
+		}
+	}
+
 	// Update status
 	s.mu.Lock()
 	s.lastCalculation = time.Now()
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	auditChainColumns  = []string{"seq", "kind", "ref_id", "payload", "prev_hash", "hash", "created_at"}
+	auditAnchorColumns = []string{"seq", "hash", "signature", "created_at"}
+)
+
+type stubAuditRecords map[string]string
+
+func (s stubAuditRecords) ChainPayload(_ context.Context, kind, refID string) ([]byte, error) {
+	payload, ok := s[kind+":"+refID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrAuditRecordNotFound, refID)
+	}
+	return []byte(payload), nil
+}
+
+// buildAuditChain links n decision entries the way Append does.
+func buildAuditChain(n int, at time.Time) []AuditChainEntry {
+	entries := make([]AuditChainEntry, 0, n)
+	prev := auditChainGenesisHash
+	for i := 1; i <= n; i++ {
+		entry := AuditChainEntry{
+			Seq:      int64(i),
+			Kind:     AuditKindDecision,
+			RefID:    fmt.Sprintf("dec_%d", i),
+			Payload:  fmt.Sprintf(`{"id":"dec_%d"}`, i),
+			PrevHash: prev,
+			At:       at.Add(time.Duration(i) * time.Second),
+		}
+		entry.Hash = auditEntryHash(&entry)
+		prev = entry.Hash
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func auditChainRows(entries []AuditChainEntry) *pgxmock.Rows {
+	rows := pgxmock.NewRows(auditChainColumns)
+	for _, e := range entries {
+		rows.AddRow(e.Seq, e.Kind, e.RefID, e.Payload, e.PrevHash, e.Hash, e.At)
+	}
+	return rows
+}
+
+func TestAuditChainService_AppendLinksEntries(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 123456789, time.UTC)
+	svc := NewAuditChainService(database.NewMockDBPool(mockPool), AuditChainConfig{})
+	svc.now = func() time.Time { return now }
+
+	mockPool.ExpectQuery("SELECT seq, hash FROM audit_chain").WillReturnError(pgx.ErrNoRows)
+	mockPool.ExpectExec("INSERT INTO audit_chain").
+		WithArgs(int64(1), AuditKindDecision, "dec_1", `{"id":"dec_1"}`, auditChainGenesisHash, pgxmock.AnyArg(), now.Truncate(time.Microsecond)).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	first, err := svc.Append(t.Context(), AuditKindDecision, "dec_1", []byte(`{"id":"dec_1"}`))
+	require.NoError(t, err)
+	assert.Equal(t, auditEntryHash(first), first.Hash)
+
+	// Another replica took seq 2, so the entry is relinked to its head
+	mockPool.ExpectQuery("SELECT seq, hash FROM audit_chain").
+		WillReturnRows(pgxmock.NewRows([]string{"seq", "hash"}).AddRow(int64(1), first.Hash))
+	mockPool.ExpectExec("INSERT INTO audit_chain").
+		WithArgs(int64(2), AuditKindDecisionOutcome, "dec_1", `{}`, first.Hash, pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("INSERT", 0))
+	mockPool.ExpectQuery("SELECT seq, hash FROM audit_chain").
+		WillReturnRows(pgxmock.NewRows([]string{"seq", "hash"}).AddRow(int64(2), "other"))
+	mockPool.ExpectExec("INSERT INTO audit_chain").
+		WithArgs(int64(3), AuditKindDecisionOutcome, "dec_1", `{}`, "other", pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	third, err := svc.Append(t.Context(), AuditKindDecisionOutcome, "dec_1", []byte(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), third.Seq)
+	assert.Equal(t, "other", third.PrevHash)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestAuditChainService_EmitChainsAuditEvents(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	svc := NewAuditChainService(database.NewMockDBPool(mockPool), AuditChainConfig{})
+
+	// Only trade, risk and mode events are chained
+	svc.Emit(t.Context(), WebhookEventQuestCompleted, map[string]string{"quest_id": "q-1"})
+
+	mockPool.ExpectQuery("SELECT seq, hash FROM audit_chain").WillReturnError(pgx.ErrNoRows)
+	mockPool.ExpectExec("INSERT INTO audit_chain").
+		WithArgs(int64(1), AuditKindEvent, pgxmock.AnyArg(), pgxmock.AnyArg(), auditChainGenesisHash, pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	svc.Emit(t.Context(), WebhookEventRisk, map[string]string{"type": "kill_switch"})
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestAuditChainService_AnchorSignsHead(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	svc := NewAuditChainService(database.NewMockDBPool(mockPool), AuditChainConfig{SigningKey: "key"})
+
+	mockPool.ExpectQuery("SELECT seq, hash FROM audit_chain").
+		WillReturnRows(pgxmock.NewRows([]string{"seq", "hash"}).AddRow(int64(7), "head"))
+	mockPool.ExpectQuery("FROM audit_chain_anchors").WillReturnError(pgx.ErrNoRows)
+	mockPool.ExpectExec("INSERT INTO audit_chain_anchors").
+		WithArgs(int64(7), "head", svc.sign(7, "head"), pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	anchor, err := svc.Anchor(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), anchor.Seq)
+	assert.Len(t, anchor.Signature, 64)
+
+	// Nothing appended since: the last anchor is returned
+	mockPool.ExpectQuery("SELECT seq, hash FROM audit_chain").
+		WillReturnRows(pgxmock.NewRows([]string{"seq", "hash"}).AddRow(int64(7), "head"))
+	mockPool.ExpectQuery("FROM audit_chain_anchors").
+		WillReturnRows(pgxmock.NewRows(auditAnchorColumns).AddRow(int64(7), "head", anchor.Signature, anchor.CreatedAt))
+	again, err := svc.Anchor(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, anchor.Signature, again.Signature)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestAuditChainService_Verify(t *testing.T) {
+	at := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	records := stubAuditRecords{
+		"decision:dec_1": `{"id":"dec_1"}`,
+		"decision:dec_2": `{"id":"dec_2"}`,
+		"decision:dec_3": `{"id":"dec_3"}`,
+	}
+
+	tests := []struct {
+		name     string
+		tamper   func(entries []AuditChainEntry, anchors []AuditAnchor, records stubAuditRecords) ([]AuditChainEntry, []AuditAnchor)
+		problems []string
+	}{
+		{
+			name: "intact",
+			tamper: func(entries []AuditChainEntry, anchors []AuditAnchor, _ stubAuditRecords) ([]AuditChainEntry, []AuditAnchor) {
+				return entries, anchors
+			},
+		},
+		{
+			name: "edited entry",
+			tamper: func(entries []AuditChainEntry, anchors []AuditAnchor, _ stubAuditRecords) ([]AuditChainEntry, []AuditAnchor) {
+				entries[0].Payload = `{"id":"dec_1","price":1}`
+				return entries, anchors
+			},
+			problems: []string{AuditIssueHashMismatch, AuditIssueRecordModified},
+		},
+		{
+			name: "rehashed entry",
+			tamper: func(entries []AuditChainEntry, anchors []AuditAnchor, records stubAuditRecords) ([]AuditChainEntry, []AuditAnchor) {
+				entries[1].Payload = `{"id":"dec_2","price":1}`
+				entries[1].Hash = auditEntryHash(&entries[1])
+				records["decision:dec_2"] = entries[1].Payload
+				return entries, anchors
+			},
+			problems: []string{AuditIssueBrokenLink},
+		},
+		{
+			name: "rewritten chain",
+			tamper: func(entries []AuditChainEntry, anchors []AuditAnchor, records stubAuditRecords) ([]AuditChainEntry, []AuditAnchor) {
+				entries[1].Payload = `{"id":"dec_2","price":1}`
+				entries[1].Hash = auditEntryHash(&entries[1])
+				entries[2].PrevHash = entries[1].Hash
+				entries[2].Hash = auditEntryHash(&entries[2])
+				records["decision:dec_2"] = entries[1].Payload
+				return entries, anchors
+			},
+			problems: []string{AuditIssueAnchorMismatch},
+		},
+		{
+			name: "forged anchor",
+			tamper: func(entries []AuditChainEntry, anchors []AuditAnchor, _ stubAuditRecords) ([]AuditChainEntry, []AuditAnchor) {
+				anchors[0].Signature = "forged"
+				return entries, anchors
+			},
+			problems: []string{AuditIssueAnchorSignature},
+		},
+		{
+			name: "deleted entry",
+			tamper: func(entries []AuditChainEntry, anchors []AuditAnchor, _ stubAuditRecords) ([]AuditChainEntry, []AuditAnchor) {
+				return []AuditChainEntry{entries[0], entries[2]}, anchors
+			},
+			problems: []string{AuditIssueSequenceGap, AuditIssueBrokenLink},
+		},
+		{
+			name: "truncated chain",
+			tamper: func(entries []AuditChainEntry, anchors []AuditAnchor, _ stubAuditRecords) ([]AuditChainEntry, []AuditAnchor) {
+				return entries[:2], anchors
+			},
+			problems: []string{AuditIssueChainTruncated},
+		},
+		{
+			name: "edited record",
+			tamper: func(entries []AuditChainEntry, anchors []AuditAnchor, records stubAuditRecords) ([]AuditChainEntry, []AuditAnchor) {
+				records["decision:dec_3"] = `{"id":"dec_3","price":1}`
+				delete(records, "decision:dec_1")
+				return entries, anchors
+			},
+			problems: []string{AuditIssueRecordMissing, AuditIssueRecordModified},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPool, err := pgxmock.NewPool()
+			require.NoError(t, err)
+			defer mockPool.Close()
+			svc := NewAuditChainService(database.NewMockDBPool(mockPool), AuditChainConfig{SigningKey: "key"})
+			current := stubAuditRecords{}
+			for k, v := range records {
+				current[k] = v
+			}
+			svc.AddRecordSource(current, AuditKindDecision)
+
+			entries := buildAuditChain(3, at)
+			anchors := []AuditAnchor{{Seq: 3, Hash: entries[2].Hash, Signature: svc.sign(3, entries[2].Hash), CreatedAt: at}}
+			entries, anchors = tt.tamper(entries, anchors, current)
+
+			anchorRows := pgxmock.NewRows(auditAnchorColumns)
+			for _, a := range anchors {
+				anchorRows.AddRow(a.Seq, a.Hash, a.Signature, a.CreatedAt)
+			}
+			mockPool.ExpectQuery("FROM audit_chain_anchors").WillReturnRows(anchorRows)
+			mockPool.ExpectQuery("FROM audit_chain").WithArgs(int64(0), auditChainPageSize).WillReturnRows(auditChainRows(entries))
+			mockPool.ExpectQuery("FROM audit_chain_anchors").
+				WillReturnRows(pgxmock.NewRows(auditAnchorColumns).AddRow(anchors[0].Seq, anchors[0].Hash, anchors[0].Signature, at))
+
+			report, err := svc.Verify(t.Context())
+			require.NoError(t, err)
+			problems := make([]string, 0, len(report.Issues))
+			for _, issue := range report.Issues {
+				problems = append(problems, issue.Problem)
+			}
+			assert.ElementsMatch(t, tt.problems, problems)
+			assert.Equal(t, len(tt.problems) == 0, report.Valid)
+			assert.Equal(t, len(report.Issues), report.IssueCount)
+			assert.True(t, report.Signed)
+			assert.NoError(t, mockPool.ExpectationsWereMet())
+		})
+	}
+}
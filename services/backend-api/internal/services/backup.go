@@ -0,0 +1,440 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/crypto"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// BackupFormat identifies NeuraTrade backup archives.
+	BackupFormat = "neuratrade-backup"
+	// BackupVersion is the snapshot schema version written by this build.
+	BackupVersion = 1
+
+	backupKDF = "argon2id"
+	// maxBackupSnapshotSize bounds the decompressed snapshot on restore.
+	maxBackupSnapshotSize = 256 << 20
+)
+
+var (
+	ErrBackupPassphraseRequired = errors.New("backup passphrase is required")
+	ErrBackupInvalid            = errors.New("backup archive is invalid")
+	ErrBackupDecrypt            = errors.New("backup could not be decrypted: wrong passphrase or corrupted archive")
+	ErrBackupUnsupported        = errors.New("backup version is not supported")
+)
+
+// BackupArchive is the encrypted file format. Ciphertext holds the gzipped
+// JSON snapshot encrypted with AES-256-GCM under a key derived from the
+// passphrase and Salt.
+type BackupArchive struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	KDF        string    `json:"kdf"`
+	Salt       string    `json:"salt"`
+	CreatedAt  time.Time `json:"created_at"`
+	Ciphertext string    `json:"ciphertext"`
+}
+
+// BackupSnapshot is the application state needed to recover on a new host.
+// It only uses portable values so it can be restored into SQLite or Postgres.
+type BackupSnapshot struct {
+	Version          int                `json:"version"`
+	CreatedAt        time.Time          `json:"created_at"`
+	Quests           []*Quest           `json:"quests"`
+	AutonomousStates []*AutonomousState `json:"autonomous_states"`
+	RiskLimits       BackupRiskLimits   `json:"risk_limits"`
+	UserBindings     []UserBinding      `json:"user_bindings"`
+	OpenPositions    []BackupPosition   `json:"open_positions"`
+}
+
+// BackupRiskLimits groups the risk controls: the execution mode and kill
+// switch, bound operators and the system configuration values.
+type BackupRiskLimits struct {
+	TradingMode *TradingModeState `json:"trading_mode,omitempty"`
+	Operators   []string          `json:"operators,omitempty"`
+	Settings    []SystemSetting   `json:"settings"`
+}
+
+// SystemSetting is a row of system_config.
+type SystemSetting struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+}
+
+// UserBinding links a user account to its Telegram chat.
+type UserBinding struct {
+	UserID           string `json:"user_id"`
+	Email            string `json:"email"`
+	PasswordHash     string `json:"password_hash"`
+	TelegramChatID   string `json:"telegram_chat_id,omitempty"`
+	SubscriptionTier string `json:"subscription_tier,omitempty"`
+}
+
+// BackupPosition is an open position. The exchange holds the actual balance;
+// this preserves the metadata needed to manage it after recovery.
+type BackupPosition struct {
+	PositionID string          `json:"position_id"`
+	OrderID    string          `json:"order_id"`
+	Exchange   string          `json:"exchange"`
+	Symbol     string          `json:"symbol"`
+	Side       string          `json:"side"`
+	Size       decimal.Decimal `json:"size"`
+	EntryPrice decimal.Decimal `json:"entry_price"`
+	Status     string          `json:"status"`
+	OpenedAt   time.Time       `json:"opened_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+// BackupRestoreSummary counts what a restore wrote.
+type BackupRestoreSummary struct {
+	Quests           int  `json:"quests"`
+	AutonomousStates int  `json:"autonomous_states"`
+	Settings         int  `json:"settings"`
+	Operators        int  `json:"operators"`
+	TradingMode      bool `json:"trading_mode"`
+	UserBindings     int  `json:"user_bindings"`
+	OpenPositions    int  `json:"open_positions"`
+}
+
+// TradingModeBackup is the part of the trading mode service used by backups.
+type TradingModeBackup interface {
+	GetState(ctx context.Context) (*TradingModeState, error)
+	RestoreState(ctx context.Context, backup TradingModeState, operator string) error
+	ListOperators(ctx context.Context) ([]string, error)
+	BindOperator(ctx context.Context, operator string) error
+}
+
+// BackupService exports and restores application state.
+type BackupService struct {
+	db     DBPool
+	quests *DBQuestStore
+	modes  TradingModeBackup
+	now    func() time.Time
+}
+
+// NewBackupService creates a new backup service.
+//
+// Parameters:
+//   - db: Database holding quests, users, settings and positions.
+//   - modes: Trading mode service (optional).
+//
+// Returns:
+//   - *BackupService: The initialized service.
+func NewBackupService(db DBPool, modes TradingModeBackup) *BackupService {
+	return &BackupService{
+		db:     db,
+		quests: NewDBQuestStore(db),
+		modes:  modes,
+		now:    time.Now,
+	}
+}
+
+// Snapshot collects the current application state.
+//
+// Parameters:
+//   - ctx: Request context.
+//
+// Returns:
+//   - *BackupSnapshot: The state.
+//   - error: Error if any section cannot be read.
+func (s *BackupService) Snapshot(ctx context.Context) (*BackupSnapshot, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+
+	snapshot := &BackupSnapshot{Version: BackupVersion, CreatedAt: s.now().UTC()}
+	var err error
+	if snapshot.Quests, err = s.quests.ListQuests(ctx, "", ""); err != nil {
+		return nil, err
+	}
+	if snapshot.AutonomousStates, err = s.quests.ListAutonomousStates(ctx); err != nil {
+		return nil, err
+	}
+	if snapshot.RiskLimits.Settings, err = s.listSettings(ctx); err != nil {
+		return nil, err
+	}
+	if s.modes != nil {
+		if snapshot.RiskLimits.TradingMode, err = s.modes.GetState(ctx); err != nil {
+			return nil, err
+		}
+		if snapshot.RiskLimits.Operators, err = s.modes.ListOperators(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if snapshot.UserBindings, err = s.listUserBindings(ctx); err != nil {
+		return nil, err
+	}
+	if snapshot.OpenPositions, err = s.listOpenPositions(ctx); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// Restore writes a snapshot into the current database. Existing rows with the
+// same keys are updated, so restoring twice is safe.
+//
+// Parameters:
+//   - ctx: Request context.
+//   - snapshot: The state to restore.
+//   - operator: Who performed the restore, recorded on the trading mode.
+//
+// Returns:
+//   - *BackupRestoreSummary: What was written.
+//   - error: Error if a section fails; earlier sections stay restored.
+func (s *BackupService) Restore(ctx context.Context, snapshot *BackupSnapshot, operator string) (*BackupRestoreSummary, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	if snapshot == nil || snapshot.Version < 1 {
+		return nil, ErrBackupInvalid
+	}
+	if snapshot.Version > BackupVersion {
+		return nil, fmt.Errorf("%w: %d", ErrBackupUnsupported, snapshot.Version)
+	}
+
+	summary := &BackupRestoreSummary{}
+	for _, setting := range snapshot.RiskLimits.Settings {
+		if _, err := s.db.Exec(ctx, `
+			INSERT INTO system_config (config_key, config_value, description)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (config_key) DO UPDATE SET
+				config_value = EXCLUDED.config_value,
+				description = EXCLUDED.description
+		`, setting.Key, setting.Value, setting.Description); err != nil {
+			return summary, fmt.Errorf("failed to restore setting %s: %w", setting.Key, err)
+		}
+		summary.Settings++
+	}
+
+	if s.modes != nil {
+		for _, op := range snapshot.RiskLimits.Operators {
+			if err := s.modes.BindOperator(ctx, op); err != nil {
+				return summary, fmt.Errorf("failed to restore operator %s: %w", op, err)
+			}
+			summary.Operators++
+		}
+		if snapshot.RiskLimits.TradingMode != nil {
+			if err := s.modes.RestoreState(ctx, *snapshot.RiskLimits.TradingMode, operator); err != nil {
+				return summary, err
+			}
+			summary.TradingMode = true
+		}
+	}
+
+	for _, binding := range snapshot.UserBindings {
+		if _, err := s.db.Exec(ctx, `
+			INSERT INTO users (id, email, password_hash, telegram_chat_id, subscription_tier)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (email) DO UPDATE SET
+				telegram_chat_id = EXCLUDED.telegram_chat_id,
+				subscription_tier = EXCLUDED.subscription_tier
+		`, binding.UserID, binding.Email, binding.PasswordHash, nullableString(binding.TelegramChatID), binding.SubscriptionTier); err != nil {
+			return summary, fmt.Errorf("failed to restore user %s: %w", binding.Email, err)
+		}
+		summary.UserBindings++
+	}
+
+	for _, quest := range snapshot.Quests {
+		if err := s.quests.SaveQuest(ctx, quest); err != nil {
+			return summary, fmt.Errorf("failed to restore quest %s: %w", quest.ID, err)
+		}
+		summary.Quests++
+	}
+	for _, state := range snapshot.AutonomousStates {
+		if err := s.quests.SaveAutonomousState(ctx, state); err != nil {
+			return summary, fmt.Errorf("failed to restore autonomous state %s: %w", state.ChatID, err)
+		}
+		summary.AutonomousStates++
+	}
+
+	for _, position := range snapshot.OpenPositions {
+		if _, err := s.db.Exec(ctx, `
+			INSERT INTO trading_positions (
+				position_id, order_id, exchange, symbol, side, size, entry_price, status, opened_at, updated_at
+			) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+			ON CONFLICT (position_id) DO UPDATE SET
+				size = EXCLUDED.size,
+				status = EXCLUDED.status,
+				updated_at = EXCLUDED.updated_at
+		`, position.PositionID, position.OrderID, position.Exchange, position.Symbol, position.Side,
+			position.Size, position.EntryPrice, position.Status, position.OpenedAt, position.UpdatedAt); err != nil {
+			return summary, fmt.Errorf("failed to restore position %s: %w", position.PositionID, err)
+		}
+		summary.OpenPositions++
+	}
+
+	return summary, nil
+}
+
+func (s *BackupService) listSettings(ctx context.Context) ([]SystemSetting, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT config_key, COALESCE(config_value, ''), COALESCE(description, '')
+		FROM system_config ORDER BY config_key
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list settings: %w", err)
+	}
+	defer rows.Close()
+
+	settings := make([]SystemSetting, 0)
+	for rows.Next() {
+		var setting SystemSetting
+		if err := rows.Scan(&setting.Key, &setting.Value, &setting.Description); err != nil {
+			return nil, fmt.Errorf("failed to scan setting: %w", err)
+		}
+		settings = append(settings, setting)
+	}
+	return settings, rows.Err()
+}
+
+func (s *BackupService) listUserBindings(ctx context.Context) ([]UserBinding, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT CAST(id AS TEXT), email, password_hash, COALESCE(telegram_chat_id, ''), COALESCE(subscription_tier, 'free')
+		FROM users ORDER BY email
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	bindings := make([]UserBinding, 0)
+	for rows.Next() {
+		var binding UserBinding
+		if err := rows.Scan(&binding.UserID, &binding.Email, &binding.PasswordHash, &binding.TelegramChatID, &binding.SubscriptionTier); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		bindings = append(bindings, binding)
+	}
+	return bindings, rows.Err()
+}
+
+func (s *BackupService) listOpenPositions(ctx context.Context) ([]BackupPosition, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT position_id, order_id, exchange, symbol, side, size, entry_price, status, opened_at, updated_at
+		FROM trading_positions WHERE status = 'OPEN' ORDER BY opened_at
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open positions: %w", err)
+	}
+	defer rows.Close()
+
+	positions := make([]BackupPosition, 0)
+	for rows.Next() {
+		var p BackupPosition
+		if err := rows.Scan(&p.PositionID, &p.OrderID, &p.Exchange, &p.Symbol, &p.Side, &p.Size, &p.EntryPrice, &p.Status, &p.OpenedAt, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan position: %w", err)
+		}
+		positions = append(positions, p)
+	}
+	return positions, rows.Err()
+}
+
+func nullableString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// EncryptBackup compresses and encrypts a snapshot into an archive file.
+//
+// Parameters:
+//   - snapshot: The state to archive.
+//   - passphrase: Secret the archive key is derived from.
+//
+// Returns:
+//   - []byte: The archive file contents.
+//   - error: ErrBackupPassphraseRequired or an encoding error.
+func EncryptBackup(snapshot *BackupSnapshot, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, ErrBackupPassphraseRequired
+	}
+
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	if err := json.NewEncoder(zw).Encode(snapshot); err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress snapshot: %w", err)
+	}
+
+	encryptor, salt, err := crypto.NewEncryptorFromPassphrase(passphrase, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer encryptor.Close()
+	ciphertext, err := encryptor.Encrypt(compressed.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt snapshot: %w", err)
+	}
+
+	return json.MarshalIndent(BackupArchive{
+		Format:     BackupFormat,
+		Version:    BackupVersion,
+		KDF:        backupKDF,
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+		CreatedAt:  snapshot.CreatedAt,
+		Ciphertext: ciphertext,
+	}, "", "  ")
+}
+
+// DecryptBackup opens an archive produced by EncryptBackup.
+//
+// Parameters:
+//   - data: The archive file contents.
+//   - passphrase: Secret the archive was created with.
+//
+// Returns:
+//   - *BackupSnapshot: The archived state.
+//   - error: ErrBackupInvalid, ErrBackupUnsupported or ErrBackupDecrypt.
+func DecryptBackup(data []byte, passphrase string) (*BackupSnapshot, error) {
+	if passphrase == "" {
+		return nil, ErrBackupPassphraseRequired
+	}
+
+	var archive BackupArchive
+	if err := json.Unmarshal(data, &archive); err != nil || archive.Format != BackupFormat {
+		return nil, ErrBackupInvalid
+	}
+	if archive.Version > BackupVersion || archive.KDF != backupKDF {
+		return nil, fmt.Errorf("%w: version %d, kdf %q", ErrBackupUnsupported, archive.Version, archive.KDF)
+	}
+	salt, err := base64.StdEncoding.DecodeString(archive.Salt)
+	if err != nil {
+		return nil, ErrBackupInvalid
+	}
+
+	encryptor, _, err := crypto.NewEncryptorFromPassphrase(passphrase, salt)
+	if err != nil {
+		return nil, ErrBackupInvalid
+	}
+	defer encryptor.Close()
+	compressed, err := encryptor.Decrypt(archive.Ciphertext)
+	if err != nil {
+		return nil, ErrBackupDecrypt
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, ErrBackupInvalid
+	}
+	defer func() { _ = zr.Close() }()
+
+	var snapshot BackupSnapshot
+	if err := json.NewDecoder(io.LimitReader(zr, maxBackupSnapshotSize)).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBackupInvalid, err)
+	}
+	return &snapshot, nil
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptBackup_RoundTrip(t *testing.T) {
+	snapshot := &BackupSnapshot{
+		Version:   BackupVersion,
+		CreatedAt: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+		Quests:    []*Quest{{ID: "q-1", Name: "Scalp", Status: QuestStatusActive}},
+		UserBindings: []UserBinding{
+			{UserID: "u-1", Email: "ops@example.com", PasswordHash: "hash", TelegramChatID: "42"},
+		},
+		OpenPositions: []BackupPosition{{PositionID: "pos-1", Size: decimal.NewFromFloat(0.5)}},
+	}
+
+	archive, err := EncryptBackup(snapshot, "correct horse")
+	require.NoError(t, err)
+	assert.NotContains(t, string(archive), "ops@example.com", "contents are encrypted")
+
+	var envelope BackupArchive
+	require.NoError(t, json.Unmarshal(archive, &envelope))
+	assert.Equal(t, BackupFormat, envelope.Format)
+	assert.Equal(t, "argon2id", envelope.KDF)
+
+	restored, err := DecryptBackup(archive, "correct horse")
+	require.NoError(t, err)
+	assert.Equal(t, "q-1", restored.Quests[0].ID)
+	assert.Equal(t, "42", restored.UserBindings[0].TelegramChatID)
+	assert.True(t, restored.OpenPositions[0].Size.Equal(decimal.NewFromFloat(0.5)))
+
+	_, err = DecryptBackup(archive, "wrong")
+	assert.ErrorIs(t, err, ErrBackupDecrypt)
+	_, err = DecryptBackup([]byte(`{"format":"other"}`), "correct horse")
+	assert.ErrorIs(t, err, ErrBackupInvalid)
+	_, err = EncryptBackup(snapshot, "")
+	assert.ErrorIs(t, err, ErrBackupPassphraseRequired)
+}
+
+func TestBackupService_Snapshot(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+
+	modes, _ := newTestTradingModeService(t, TradingModeConfig{})
+	require.NoError(t, modes.BindOperator(t.Context(), "op-1"))
+	require.NoError(t, modes.EngageKillSwitch(t.Context(), "op-1", "drawdown"))
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	mockPool.ExpectQuery("FROM quests").WillReturnRows(pgxmock.NewRows([]string{
+		"id", "name", "description", "type", "cadence", "cron_expr", "status", "prompt",
+		"target_count", "current_count", "checkpoint", "created_at", "updated_at",
+		"last_executed_at", "completed_at", "last_error", "metadata",
+	}).AddRow("q-1", "Scalp", "", "routine", "micro", nil, "active", "",
+		0, 3, []byte(`{"step":1}`), now, now, nil, nil, nil, []byte(`{"chat_id":"42"}`)))
+	mockPool.ExpectQuery("FROM autonomous_state").WillReturnRows(pgxmock.NewRows([]string{
+		"chat_id", "is_active", "started_at", "paused_at", "active_quests",
+	}).AddRow("42", true, now, nil, []byte(`["q-1"]`)))
+	mockPool.ExpectQuery("FROM system_config").WillReturnRows(pgxmock.NewRows([]string{
+		"config_key", "config_value", "description",
+	}).AddRow("funding_rate_max_risk", "3.0", "Maximum risk score"))
+	mockPool.ExpectQuery("FROM users").WillReturnRows(pgxmock.NewRows([]string{
+		"id", "email", "password_hash", "telegram_chat_id", "subscription_tier",
+	}).AddRow("u-1", "ops@example.com", "hash", "42", "free"))
+	mockPool.ExpectQuery("FROM trading_positions WHERE status = 'OPEN'").WillReturnRows(pgxmock.NewRows([]string{
+		"position_id", "order_id", "exchange", "symbol", "side", "size", "entry_price", "status", "opened_at", "updated_at",
+	}).AddRow("pos-1", "ord-1", "binance", "BTC/USDT", "BUY", decimal.NewFromInt(1), decimal.NewFromInt(50000), "OPEN", now, now))
+
+	svc := NewBackupService(database.NewMockDBPool(mockPool), modes)
+	snapshot, err := svc.Snapshot(t.Context())
+	require.NoError(t, err)
+	require.NoError(t, mockPool.ExpectationsWereMet())
+
+	assert.Equal(t, BackupVersion, snapshot.Version)
+	require.Len(t, snapshot.Quests, 1)
+	assert.Equal(t, "42", snapshot.Quests[0].Metadata["chat_id"])
+	assert.Equal(t, []string{"q-1"}, snapshot.AutonomousStates[0].ActiveQuests)
+	assert.True(t, snapshot.RiskLimits.TradingMode.KillSwitchEngaged)
+	assert.Equal(t, []string{"op-1"}, snapshot.RiskLimits.Operators)
+	assert.Equal(t, "3.0", snapshot.RiskLimits.Settings[0].Value)
+	assert.Equal(t, "ops@example.com", snapshot.UserBindings[0].Email)
+	assert.Equal(t, "pos-1", snapshot.OpenPositions[0].PositionID)
+}
+
+func TestBackupService_Restore(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+
+	modes, _ := newTestTradingModeService(t, TradingModeConfig{DefaultMode: ExecutionModeLive})
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	snapshot := &BackupSnapshot{
+		Version: BackupVersion,
+		RiskLimits: BackupRiskLimits{
+			TradingMode: &TradingModeState{Mode: ExecutionModePaper, KillSwitchEngaged: true, KillSwitchReason: "host lost"},
+			Operators:   []string{"op-1"},
+			Settings:    []SystemSetting{{Key: "funding_rate_max_risk", Value: "3.0"}},
+		},
+		UserBindings:     []UserBinding{{UserID: "u-1", Email: "ops@example.com", PasswordHash: "hash", SubscriptionTier: "free"}},
+		Quests:           []*Quest{{ID: "q-1", Name: "Scalp", Status: QuestStatusActive, CreatedAt: now, UpdatedAt: now}},
+		AutonomousStates: []*AutonomousState{{ChatID: "42", IsActive: true, ActiveQuests: []string{"q-1"}}},
+		OpenPositions:    []BackupPosition{{PositionID: "pos-1", OrderID: "ord-1", Status: "OPEN", OpenedAt: now, UpdatedAt: now}},
+	}
+
+	mockPool.ExpectExec("INSERT INTO system_config").
+		WithArgs("funding_rate_max_risk", "3.0", "").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mockPool.ExpectExec("INSERT INTO users").
+		WithArgs("u-1", "ops@example.com", "hash", nil, "free").
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mockPool.ExpectExec("INSERT INTO quests").WithArgs(
+		pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+		pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+		pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+	).WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mockPool.ExpectExec("INSERT INTO autonomous_state").WithArgs(
+		"42", true, pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+	).WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mockPool.ExpectExec("INSERT INTO trading_positions").WithArgs(
+		"pos-1", "ord-1", "", "", "", pgxmock.AnyArg(), pgxmock.AnyArg(), "OPEN", now, now,
+	).WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	svc := NewBackupService(database.NewMockDBPool(mockPool), modes)
+	summary, err := svc.Restore(t.Context(), snapshot, "op-2")
+	require.NoError(t, err)
+	require.NoError(t, mockPool.ExpectationsWereMet())
+	assert.Equal(t, &BackupRestoreSummary{
+		Quests: 1, AutonomousStates: 1, Settings: 1, Operators: 1, TradingMode: true, UserBindings: 1, OpenPositions: 1,
+	}, summary)
+
+	state, err := modes.GetState(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, ExecutionModePaper, state.Mode)
+	assert.True(t, state.KillSwitchEngaged)
+	assert.Equal(t, "op-2", state.UpdatedBy)
+	operators, err := modes.ListOperators(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"op-1"}, operators)
+
+	_, err = svc.Restore(t.Context(), &BackupSnapshot{Version: BackupVersion + 1}, "op-2")
+	assert.ErrorIs(t, err, ErrBackupUnsupported)
+}
+
+func TestTradingModeService_RestoreStateNeverLoosens(t *testing.T) {
+	svc, _ := newTestTradingModeService(t, TradingModeConfig{})
+	require.NoError(t, svc.EngageKillSwitch(t.Context(), "op-1", "manual"))
+
+	require.NoError(t, svc.RestoreState(t.Context(), TradingModeState{Mode: ExecutionModeLive}, "restore"))
+
+	state, err := svc.GetState(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, ExecutionModePaper, state.Mode, "live mode needs confirmation")
+	assert.True(t, state.KillSwitchEngaged, "a released kill switch is not restored")
+	assert.Equal(t, "manual", state.KillSwitchReason)
+}
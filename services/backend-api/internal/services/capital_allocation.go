@@ -0,0 +1,473 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+)
+
+// Strategies that capital can be allocated to. The reserve is never traded.
+const (
+	StrategyScalping         = "scalping"
+	StrategyArbitrage        = "arbitrage"
+	StrategyFundingArbitrage = "funding_arbitrage"
+	StrategyReserve          = "reserve"
+)
+
+const (
+	capitalAllocationKey = "allocation:chats"
+
+	defaultRebalanceInterval      = 24 * time.Hour
+	defaultRebalanceCheckInterval = 15 * time.Minute
+	defaultRebalanceMaxShift      = 0.5
+
+	// allocationTolerance absorbs rounding when fractions are summed.
+	allocationTolerance = 1e-6
+)
+
+var (
+	ErrAllocationInvalid         = errors.New("invalid capital allocation")
+	ErrAllocationUnknownStrategy = errors.New("unknown strategy")
+	ErrAllocationNotFound        = errors.New("capital allocation not found")
+)
+
+// allocationStrategies lists the strategies accepted in an allocation, in display order.
+var allocationStrategies = []string{StrategyScalping, StrategyArbitrage, StrategyFundingArbitrage, StrategyReserve}
+
+// CapitalAllocator limits the share of a chat's capital each strategy sizes
+// its orders with.
+type CapitalAllocator interface {
+	// StrategyFraction returns the share of the chat's capital the strategy
+	// may use. limited is false when the chat has no allocation, in which case
+	// the strategy may use all of it.
+	StrategyFraction(ctx context.Context, chatID, strategy string) (fraction float64, limited bool, err error)
+}
+
+// StrategyResults accumulates a strategy's realized results since the last rebalance.
+type StrategyResults struct {
+	PnL    decimal.Decimal `json:"pnl"`
+	Trades int             `json:"trades"`
+	Wins   int             `json:"wins"`
+}
+
+// CapitalAllocation is the capital split of one chat.
+type CapitalAllocation struct {
+	ChatID string `json:"chat_id"`
+	// Targets are the fractions chosen by the user; they sum to 1 with the reserve.
+	Targets map[string]float64 `json:"targets"`
+	// Weights are the fractions enforced at order sizing time. They equal the
+	// targets unless performance weighting moved them at the last rebalance.
+	Weights map[string]float64 `json:"weights"`
+	// PerformanceWeighted shifts weights towards the strategies that earned
+	// the most since the previous rebalance.
+	PerformanceWeighted bool `json:"performance_weighted"`
+	// Results are collected per strategy between rebalances.
+	Results          map[string]StrategyResults `json:"results"`
+	UpdatedAt        time.Time                  `json:"updated_at"`
+	LastRebalancedAt time.Time                  `json:"last_rebalanced_at"`
+}
+
+// CapitalAllocationConfig configures scheduled rebalancing.
+type CapitalAllocationConfig struct {
+	// RebalanceInterval is how often performance-weighted chats are rebalanced.
+	RebalanceInterval time.Duration
+	// CheckInterval is how often the scheduler looks for chats that are due.
+	CheckInterval time.Duration
+	// MaxShift bounds a rebalance: a strategy's weight stays within
+	// target*(1-MaxShift) and target*(1+MaxShift) before normalization.
+	MaxShift float64
+}
+
+// CapitalAllocationService stores per-chat capital allocations in Redis,
+// answers sizing queries and rebalances performance-weighted allocations on
+// a schedule.
+type CapitalAllocationService struct {
+	redis  *redis.Client
+	config CapitalAllocationConfig
+	logger *slog.Logger
+	now    func() time.Time
+	// mu serializes allocation read-modify-write cycles.
+	mu     sync.Mutex
+	runMu  sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Ensure CapitalAllocationService implements CapitalAllocator.
+var _ CapitalAllocator = (*CapitalAllocationService)(nil)
+
+// NewCapitalAllocationService creates a capital allocation service.
+//
+// Parameters:
+//
+//	client: Redis client used to persist allocations.
+//	config: Rebalancing configuration; zero values use defaults.
+//
+// Returns:
+//
+//	*CapitalAllocationService: Initialized service (scheduler not started).
+func NewCapitalAllocationService(client *redis.Client, config CapitalAllocationConfig) *CapitalAllocationService {
+	if config.RebalanceInterval <= 0 {
+		config.RebalanceInterval = defaultRebalanceInterval
+	}
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = defaultRebalanceCheckInterval
+	}
+	if config.MaxShift <= 0 || config.MaxShift >= 1 {
+		config.MaxShift = defaultRebalanceMaxShift
+	}
+	return &CapitalAllocationService{
+		redis:  client,
+		config: config,
+		logger: telemetry.Logger(),
+		now:    time.Now,
+	}
+}
+
+// Start rebalances performance-weighted allocations that are due, checking
+// once per check interval until Stop is called.
+func (s *CapitalAllocationService) Start(ctx context.Context) error {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+	if s.cancel != nil {
+		return fmt.Errorf("capital allocation service already running")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.config.CheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.RebalanceDue(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the rebalancing scheduler.
+func (s *CapitalAllocationService) Stop() {
+	s.runMu.Lock()
+	cancel := s.cancel
+	s.cancel = nil
+	s.runMu.Unlock()
+	if cancel != nil {
+		cancel()
+		s.wg.Wait()
+	}
+}
+
+// Get returns a chat's allocation.
+//
+// Returns:
+//
+//	*CapitalAllocation: The allocation.
+//	error: ErrAllocationNotFound when the chat has none, or a persistence error.
+func (s *CapitalAllocationService) Get(ctx context.Context, chatID string) (*CapitalAllocation, error) {
+	raw, err := s.redis.HGet(ctx, capitalAllocationKey, chatID).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrAllocationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load capital allocation: %w", err)
+	}
+	var allocation CapitalAllocation
+	if err := json.Unmarshal([]byte(raw), &allocation); err != nil {
+		return nil, fmt.Errorf("failed to decode capital allocation: %w", err)
+	}
+	return &allocation, nil
+}
+
+// Set replaces a chat's target allocation. Fractions must be between 0 and 1
+// and sum to at most 1; the remainder is added to the reserve. Weights are
+// reset to the new targets and collected results are kept.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	chatID: Telegram chat ID.
+//	targets: Fraction per strategy, e.g. {"scalping": 0.6, "funding_arbitrage": 0.3}.
+//	performanceWeighted: Whether scheduled rebalancing adjusts weights by performance.
+//
+// Returns:
+//
+//	*CapitalAllocation: The stored allocation.
+//	error: ErrAllocationInvalid, ErrAllocationUnknownStrategy or a persistence error.
+func (s *CapitalAllocationService) Set(ctx context.Context, chatID string, targets map[string]float64, performanceWeighted bool) (*CapitalAllocation, error) {
+	normalized, err := normalizeAllocationTargets(targets)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	allocation, err := s.Get(ctx, chatID)
+	if errors.Is(err, ErrAllocationNotFound) {
+		allocation = &CapitalAllocation{ChatID: chatID, Results: map[string]StrategyResults{}}
+	} else if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	allocation.Targets = normalized
+	allocation.Weights = copyAllocationFractions(normalized)
+	allocation.PerformanceWeighted = performanceWeighted
+	allocation.UpdatedAt = now
+	if allocation.LastRebalancedAt.IsZero() {
+		allocation.LastRebalancedAt = now
+	}
+	if err := s.save(ctx, allocation); err != nil {
+		return nil, err
+	}
+	return allocation, nil
+}
+
+// Clear deletes a chat's allocation so every strategy may use all capital again.
+func (s *CapitalAllocationService) Clear(ctx context.Context, chatID string) error {
+	if err := s.redis.HDel(ctx, capitalAllocationKey, chatID).Err(); err != nil {
+		return fmt.Errorf("failed to clear capital allocation: %w", err)
+	}
+	return nil
+}
+
+// StrategyFraction implements CapitalAllocator. The reserve always gets 0.
+// A strategy missing from the allocation gets 0 as well, so enabling an
+// allocation never lets an unlisted strategy trade.
+func (s *CapitalAllocationService) StrategyFraction(ctx context.Context, chatID, strategy string) (float64, bool, error) {
+	allocation, err := s.Get(ctx, chatID)
+	if errors.Is(err, ErrAllocationNotFound) {
+		return 1, false, nil
+	}
+	if err != nil {
+		return 0, true, err
+	}
+	if strategy == StrategyReserve {
+		return 0, true, nil
+	}
+	return allocation.Weights[strategy], true, nil
+}
+
+// RecordResult adds a realized trade result to a strategy's performance since
+// the last rebalance. Chats without an allocation are ignored.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	chatID: Telegram chat ID.
+//	strategy: Strategy that closed the trade.
+//	pnl: Realized profit or loss in the quote currency.
+//
+// Returns:
+//
+//	error: ErrAllocationUnknownStrategy or a persistence error.
+func (s *CapitalAllocationService) RecordResult(ctx context.Context, chatID, strategy string, pnl decimal.Decimal) error {
+	if !isAllocationStrategy(strategy) || strategy == StrategyReserve {
+		return fmt.Errorf("%w: %q", ErrAllocationUnknownStrategy, strategy)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	allocation, err := s.Get(ctx, chatID)
+	if errors.Is(err, ErrAllocationNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if allocation.Results == nil {
+		allocation.Results = map[string]StrategyResults{}
+	}
+	results := allocation.Results[strategy]
+	results.PnL = results.PnL.Add(pnl)
+	results.Trades++
+	if pnl.IsPositive() {
+		results.Wins++
+	}
+	allocation.Results[strategy] = results
+	return s.save(ctx, allocation)
+}
+
+// Rebalance recomputes a chat's weights now and starts a new results period.
+// Performance-weighted allocations move towards the strategies with the
+// largest share of the period's profit; others return to their targets.
+//
+// Returns:
+//
+//	*CapitalAllocation: The rebalanced allocation.
+//	error: ErrAllocationNotFound or a persistence error.
+func (s *CapitalAllocationService) Rebalance(ctx context.Context, chatID string) (*CapitalAllocation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	allocation, err := s.Get(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	s.rebalance(allocation)
+	if err := s.save(ctx, allocation); err != nil {
+		return nil, err
+	}
+	return allocation, nil
+}
+
+// RebalanceDue rebalances every performance-weighted allocation whose last
+// rebalance is at least one rebalance interval old.
+//
+// Returns:
+//
+//	int: Number of allocations rebalanced.
+func (s *CapitalAllocationService) RebalanceDue(ctx context.Context) int {
+	values, err := s.redis.HGetAll(ctx, capitalAllocationKey).Result()
+	if err != nil {
+		s.logger.Warn("Failed to load capital allocations", "error", err)
+		return 0
+	}
+
+	chatIDs := make([]string, 0, len(values))
+	for chatID := range values {
+		chatIDs = append(chatIDs, chatID)
+	}
+	sort.Strings(chatIDs)
+
+	rebalanced := 0
+	now := s.now()
+	for _, chatID := range chatIDs {
+		var allocation CapitalAllocation
+		if err := json.Unmarshal([]byte(values[chatID]), &allocation); err != nil {
+			s.logger.Warn("Skipping unreadable capital allocation", "chat_id", chatID, "error", err)
+			continue
+		}
+		if !allocation.PerformanceWeighted || now.Sub(allocation.LastRebalancedAt) < s.config.RebalanceInterval {
+			continue
+		}
+		updated, err := s.Rebalance(ctx, chatID)
+		if err != nil {
+			s.logger.Warn("Failed to rebalance capital allocation", "chat_id", chatID, "error", err)
+			continue
+		}
+		rebalanced++
+		s.logger.Info("Capital allocation rebalanced", "chat_id", chatID, "weights", updated.Weights)
+	}
+	return rebalanced
+}
+
+// rebalance sets allocation weights from the targets and the results collected
+// since the previous rebalance, then resets the results.
+//
+// Each traded strategy's share of the period's absolute PnL, in [-1, 1],
+// scales its target by up to ±MaxShift. The traded strategies are then
+// normalized back to their combined target, so the reserve never changes and
+// a losing strategy keeps at least target*(1-MaxShift) before normalization.
+func (s *CapitalAllocationService) rebalance(allocation *CapitalAllocation) {
+	weights := copyAllocationFractions(allocation.Targets)
+
+	if allocation.PerformanceWeighted {
+		totalAbs := decimal.Zero
+		for strategy, results := range allocation.Results {
+			if weights[strategy] > 0 {
+				totalAbs = totalAbs.Add(results.PnL.Abs())
+			}
+		}
+		if totalAbs.IsPositive() {
+			targetSum, adjustedSum := 0.0, 0.0
+			for strategy, target := range allocation.Targets {
+				if strategy == StrategyReserve || target <= 0 {
+					continue
+				}
+				share := allocation.Results[strategy].PnL.Div(totalAbs).InexactFloat64()
+				weights[strategy] = target * (1 + s.config.MaxShift*share)
+				targetSum += target
+				adjustedSum += weights[strategy]
+			}
+			for strategy := range weights {
+				if strategy != StrategyReserve && adjustedSum > 0 {
+					weights[strategy] = weights[strategy] * targetSum / adjustedSum
+				}
+			}
+		}
+	}
+
+	now := s.now().UTC()
+	allocation.Weights = weights
+	allocation.Results = map[string]StrategyResults{}
+	allocation.LastRebalancedAt = now
+	allocation.UpdatedAt = now
+}
+
+func (s *CapitalAllocationService) save(ctx context.Context, allocation *CapitalAllocation) error {
+	raw, err := json.Marshal(allocation)
+	if err != nil {
+		return err
+	}
+	if err := s.redis.HSet(ctx, capitalAllocationKey, allocation.ChatID, raw).Err(); err != nil {
+		return fmt.Errorf("failed to save capital allocation: %w", err)
+	}
+	return nil
+}
+
+// AllocationStrategies returns the strategies an allocation may list.
+func AllocationStrategies() []string {
+	return append([]string{}, allocationStrategies...)
+}
+
+func isAllocationStrategy(strategy string) bool {
+	for _, known := range allocationStrategies {
+		if strategy == known {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeAllocationTargets validates targets and adds any unallocated
+// remainder to the reserve.
+func normalizeAllocationTargets(targets map[string]float64) (map[string]float64, error) {
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("%w: at least one strategy is required", ErrAllocationInvalid)
+	}
+	normalized := make(map[string]float64, len(targets)+1)
+	sum := 0.0
+	for strategy, fraction := range targets {
+		strategy = strings.ToLower(strings.TrimSpace(strategy))
+		if !isAllocationStrategy(strategy) {
+			return nil, fmt.Errorf("%w: %q (use %s)", ErrAllocationUnknownStrategy, strategy, strings.Join(allocationStrategies, ", "))
+		}
+		if math.IsNaN(fraction) || fraction < 0 || fraction > 1 {
+			return nil, fmt.Errorf("%w: %s must be between 0 and 1", ErrAllocationInvalid, strategy)
+		}
+		normalized[strategy] += fraction
+		sum += fraction
+	}
+	if sum > 1+allocationTolerance {
+		return nil, fmt.Errorf("%w: fractions sum to %.4f, more than 1", ErrAllocationInvalid, sum)
+	}
+	if remainder := 1 - sum; remainder > allocationTolerance {
+		normalized[StrategyReserve] += remainder
+	}
+	return normalized, nil
+}
+
+func copyAllocationFractions(fractions map[string]float64) map[string]float64 {
+	copied := make(map[string]float64, len(fractions))
+	for strategy, fraction := range fractions {
+		copied[strategy] = fraction
+	}
+	return copied
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCapitalAllocationService(t *testing.T, config CapitalAllocationConfig) *CapitalAllocationService {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewCapitalAllocationService(client, config)
+}
+
+func TestCapitalAllocationService_SetAndFraction(t *testing.T) {
+	svc := newTestCapitalAllocationService(t, CapitalAllocationConfig{})
+	ctx := t.Context()
+
+	// Without an allocation every strategy may use all capital
+	fraction, limited, err := svc.StrategyFraction(ctx, "42", StrategyScalping)
+	require.NoError(t, err)
+	assert.False(t, limited)
+	assert.Equal(t, 1.0, fraction)
+
+	allocation, err := svc.Set(ctx, "42", map[string]float64{" Scalping": 0.6, "funding_arbitrage": 0.3}, false)
+	require.NoError(t, err)
+	assert.Equal(t, 0.6, allocation.Weights[StrategyScalping])
+	assert.InDelta(t, 0.1, allocation.Targets[StrategyReserve], 1e-9, "the remainder goes to the reserve")
+
+	fraction, limited, err = svc.StrategyFraction(ctx, "42", StrategyScalping)
+	require.NoError(t, err)
+	assert.True(t, limited)
+	assert.Equal(t, 0.6, fraction)
+
+	// Unlisted strategies and the reserve get nothing
+	fraction, _, err = svc.StrategyFraction(ctx, "42", StrategyArbitrage)
+	require.NoError(t, err)
+	assert.Zero(t, fraction)
+	fraction, _, err = svc.StrategyFraction(ctx, "42", StrategyReserve)
+	require.NoError(t, err)
+	assert.Zero(t, fraction)
+
+	_, err = svc.Set(ctx, "42", map[string]float64{StrategyScalping: 0.8, StrategyArbitrage: 0.3}, false)
+	assert.ErrorIs(t, err, ErrAllocationInvalid)
+	_, err = svc.Set(ctx, "42", map[string]float64{"grid": 0.5}, false)
+	assert.ErrorIs(t, err, ErrAllocationUnknownStrategy)
+	_, err = svc.Set(ctx, "42", map[string]float64{StrategyScalping: -0.1}, false)
+	assert.ErrorIs(t, err, ErrAllocationInvalid)
+
+	require.NoError(t, svc.Clear(ctx, "42"))
+	_, err = svc.Get(ctx, "42")
+	assert.ErrorIs(t, err, ErrAllocationNotFound)
+}
+
+func TestCapitalAllocationService_PerformanceRebalance(t *testing.T) {
+	svc := newTestCapitalAllocationService(t, CapitalAllocationConfig{MaxShift: 0.5, RebalanceInterval: time.Hour})
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := t.Context()
+
+	_, err := svc.Set(ctx, "42", map[string]float64{StrategyScalping: 0.6, StrategyFundingArbitrage: 0.3, StrategyReserve: 0.1}, true)
+	require.NoError(t, err)
+	require.NoError(t, svc.RecordResult(ctx, "42", StrategyScalping, decimal.NewFromInt(-20)))
+	require.NoError(t, svc.RecordResult(ctx, "42", StrategyFundingArbitrage, decimal.NewFromInt(60)))
+	assert.ErrorIs(t, svc.RecordResult(ctx, "42", StrategyReserve, decimal.NewFromInt(1)), ErrAllocationUnknownStrategy)
+	// Results for chats without an allocation are ignored
+	require.NoError(t, svc.RecordResult(ctx, "7", StrategyScalping, decimal.NewFromInt(5)))
+
+	// Not due yet
+	assert.Zero(t, svc.RebalanceDue(ctx))
+
+	now = now.Add(time.Hour)
+	assert.Equal(t, 1, svc.RebalanceDue(ctx))
+
+	allocation, err := svc.Get(ctx, "42")
+	require.NoError(t, err)
+	// Shares of |PnL|: scalping -0.25, funding +0.75, scaled by ±50%:
+	// 0.6*0.875=0.525 and 0.3*1.375=0.4125, normalized back to 0.9
+	assert.InDelta(t, 0.5040, allocation.Weights[StrategyScalping], 1e-3)
+	assert.InDelta(t, 0.3960, allocation.Weights[StrategyFundingArbitrage], 1e-3)
+	assert.InDelta(t, 0.1, allocation.Weights[StrategyReserve], 1e-9)
+	assert.Empty(t, allocation.Results)
+	assert.Equal(t, now, allocation.LastRebalancedAt)
+	// Targets are unchanged so the next period starts from the user's split
+	assert.Equal(t, 0.6, allocation.Targets[StrategyScalping])
+
+	// A period without results returns to the targets
+	allocation, err = svc.Rebalance(ctx, "42")
+	require.NoError(t, err)
+	assert.Equal(t, allocation.Targets, allocation.Weights)
+}
+
+type recordingOrderExecutor struct {
+	amounts []decimal.Decimal
+}
+
+func (e *recordingOrderExecutor) PlaceOrder(_ context.Context, _, _, _, _ string, amount decimal.Decimal, _ *decimal.Decimal) (string, error) {
+	e.amounts = append(e.amounts, amount)
+	return "order", nil
+}
+
+func (e *recordingOrderExecutor) GetOpenOrders(context.Context, string, string) ([]map[string]interface{}, error) {
+	return nil, nil
+}
+
+type totalBalanceFetcher struct {
+	total map[string]float64
+}
+
+func (f *totalBalanceFetcher) FetchBalance(_ context.Context, exchange string) (*ccxt.BalanceResponse, error) {
+	return &ccxt.BalanceResponse{Exchange: exchange, Total: f.total}, nil
+}
+
+func TestIntegratedQuestHandlers_ArbitrageAllocation(t *testing.T) {
+	svc := newTestCapitalAllocationService(t, CapitalAllocationConfig{})
+	ctx := t.Context()
+	newQuest := func() *Quest {
+		return &Quest{
+			Name:     "arbitrage",
+			Metadata: map[string]string{"chat_id": "42"},
+			Checkpoint: map[string]interface{}{
+				"symbol":        "ETH/USDT",
+				"buy_exchange":  "binance",
+				"sell_exchange": "okx",
+				"buy_price":     "100",
+				"sell_price":    "101",
+				"profit_pct":    "1",
+			},
+		}
+	}
+
+	executor := &recordingOrderExecutor{}
+	handlers := NewIntegratedQuestHandlers(nil, &totalBalanceFetcher{total: map[string]float64{"USDT": 2000}}, nil, nil, nil, nil)
+	handlers.SetOrderExecutor(executor)
+	handlers.SetCapitalAllocator(svc)
+
+	// 25% of 2000 USDT at 100 caps both legs at 5 instead of the default 10
+	_, err := svc.Set(ctx, "42", map[string]float64{StrategyArbitrage: 0.25, StrategyScalping: 0.75}, false)
+	require.NoError(t, err)
+	require.NoError(t, handlers.handleArbitrageExecution(ctx, newQuest()))
+	require.Len(t, executor.amounts, 2)
+	assert.True(t, executor.amounts[0].Equal(decimal.NewFromInt(5)), executor.amounts[0].String())
+
+	// A strategy without a share places no orders
+	_, err = svc.Set(ctx, "42", map[string]float64{StrategyScalping: 1}, false)
+	require.NoError(t, err)
+	quest := newQuest()
+	require.NoError(t, handlers.handleArbitrageExecution(ctx, quest))
+	assert.Len(t, executor.amounts, 2)
+	assert.Equal(t, "allocation_zero_hold", quest.Checkpoint["status"])
+}
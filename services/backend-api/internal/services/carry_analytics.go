@@ -0,0 +1,296 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	defaultCarryDays            = 30
+	maxCarryDays                = 365
+	defaultCarryLimit           = 20
+	maxCarryLimit               = 100
+	defaultFundingIntervalHours = 8.0
+	hoursPerYear                = 365 * 24
+)
+
+// ErrCarryInvalidQuery is returned for carry queries outside the supported
+// window or limit.
+var ErrCarryInvalidQuery = errors.New("invalid carry query")
+
+// CarryQuery selects the perpetuals and window carry statistics cover.
+type CarryQuery struct {
+	// Symbol limits the report to one market, e.g. BTC/USDT; empty covers all.
+	Symbol string
+	// Exchange limits the report to one exchange; empty covers all.
+	Exchange string
+	// Days is the lookback window; zero uses 30 days.
+	Days int
+	// Limit caps the number of markets, best carry first; zero uses 20.
+	Limit int
+}
+
+// CarryDistribution summarises a series in percent.
+type CarryDistribution struct {
+	Latest float64 `json:"latest"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	P10    float64 `json:"p10"`
+	P90    float64 `json:"p90"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+}
+
+// CarryStats are the funding and basis statistics of one perpetual.
+type CarryStats struct {
+	Exchange string    `json:"exchange"`
+	Symbol   string    `json:"symbol"`
+	Samples  int       `json:"samples"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	// IntervalHours is the funding interval, estimated from the spacing of
+	// the stored funding times.
+	IntervalHours float64 `json:"interval_hours"`
+	// Funding is the funding rate per interval, in percent.
+	Funding CarryDistribution `json:"funding"`
+	// PositivePct is the share of intervals in which longs paid shorts.
+	PositivePct float64 `json:"positive_pct"`
+	// AnnualizedCarryPct is the mean funding compounded simply over a year:
+	// what a short perp hedged with spot would have earned.
+	AnnualizedCarryPct float64 `json:"annualized_carry_pct"`
+	// Basis is how far the perp mark price was above its spot index, in
+	// percent; nil when no index prices are stored.
+	Basis *CarryDistribution `json:"basis,omitempty"`
+}
+
+// CarryReport lists carry statistics of the selected perpetuals, best
+// annualized carry first.
+type CarryReport struct {
+	Days        int          `json:"days"`
+	From        time.Time    `json:"from"`
+	GeneratedAt time.Time    `json:"generated_at"`
+	Markets     []CarryStats `json:"markets"`
+}
+
+// CarryAnalyticsService aggregates the funding rate history into funding and
+// spot-perp basis statistics for evaluating carry strategies.
+type CarryAnalyticsService struct {
+	db  DBPool
+	now func() time.Time
+}
+
+// NewCarryAnalyticsService creates a carry analytics service.
+//
+// Parameters:
+//
+//	db: Database pool holding the funding_rate_history table.
+//
+// Returns:
+//
+//	*CarryAnalyticsService: Initialized service.
+func NewCarryAnalyticsService(db DBPool) *CarryAnalyticsService {
+	return &CarryAnalyticsService{db: db, now: time.Now}
+}
+
+type carrySeries struct {
+	exchange string
+	symbol   string
+	times    []time.Time
+	funding  []float64
+	basis    []float64
+}
+
+// Report computes carry statistics over the query window.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	query: Market filters, window and limit.
+//
+// Returns:
+//
+//	*CarryReport: Statistics per exchange and symbol.
+//	error: ErrCarryInvalidQuery for an unsupported window or limit, or a
+//	query error.
+func (s *CarryAnalyticsService) Report(ctx context.Context, query CarryQuery) (*CarryReport, error) {
+	days := query.Days
+	if days == 0 {
+		days = defaultCarryDays
+	}
+	if days < 0 || days > maxCarryDays {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", ErrCarryInvalidQuery, maxCarryDays)
+	}
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultCarryLimit
+	}
+	if limit < 0 || limit > maxCarryLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrCarryInvalidQuery, maxCarryLimit)
+	}
+	if isNilDBPool(s.db) {
+		return nil, fmt.Errorf("database pool is not available")
+	}
+
+	now := s.now().UTC()
+	from := now.AddDate(0, 0, -days)
+	series, err := s.loadSeries(ctx, query, from)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CarryReport{Days: days, From: from, GeneratedAt: now, Markets: []CarryStats{}}
+	for _, market := range series {
+		report.Markets = append(report.Markets, market.stats())
+	}
+	sort.Slice(report.Markets, func(i, j int) bool {
+		a, b := report.Markets[i], report.Markets[j]
+		if a.AnnualizedCarryPct != b.AnnualizedCarryPct {
+			return a.AnnualizedCarryPct > b.AnnualizedCarryPct
+		}
+		if a.Symbol != b.Symbol {
+			return a.Symbol < b.Symbol
+		}
+		return a.Exchange < b.Exchange
+	})
+	if len(report.Markets) > limit {
+		report.Markets = report.Markets[:limit]
+	}
+	return report, nil
+}
+
+// loadSeries reads the funding history since a time, grouped by exchange
+// and symbol. Perpetuals are stored under their settlement symbol, e.g.
+// BTC/USDT:USDT, so both forms are matched and reported without the suffix.
+func (s *CarryAnalyticsService) loadSeries(ctx context.Context, query CarryQuery, from time.Time) ([]*carrySeries, error) {
+	statement := `
+		SELECT exchange, symbol, funding_time, funding_rate, mark_price, index_price
+		FROM funding_rate_history
+		WHERE funding_time > $1`
+	args := []interface{}{from}
+	if exchange := strings.ToLower(strings.TrimSpace(query.Exchange)); exchange != "" {
+		args = append(args, exchange)
+		statement += fmt.Sprintf(" AND exchange = $%d", len(args))
+	}
+	if symbol := normalizeSymbolForComparison(query.Symbol); symbol != "" {
+		settlement := symbol
+		if _, quote, ok := strings.Cut(symbol, "/"); ok {
+			settlement = symbol + ":" + quote
+		}
+		args = append(args, symbol, settlement)
+		statement += fmt.Sprintf(" AND symbol IN ($%d, $%d)", len(args)-1, len(args))
+	}
+	statement += " ORDER BY funding_time ASC"
+
+	rows, err := s.db.Query(ctx, statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query funding history: %w", err)
+	}
+	defer rows.Close()
+
+	byMarket := make(map[string]*carrySeries)
+	var ordered []*carrySeries
+	for rows.Next() {
+		var exchange, symbol string
+		var at time.Time
+		var rate decimal.Decimal
+		var mark, index decimal.NullDecimal
+		if err := rows.Scan(&exchange, &symbol, &at, &rate, &mark, &index); err != nil {
+			return nil, fmt.Errorf("failed to scan funding history: %w", err)
+		}
+		symbol = normalizeSymbolForComparison(symbol)
+		key := exchange + "|" + symbol
+		market, ok := byMarket[key]
+		if !ok {
+			market = &carrySeries{exchange: exchange, symbol: symbol}
+			byMarket[key] = market
+			ordered = append(ordered, market)
+		}
+		market.times = append(market.times, at.UTC())
+		market.funding = append(market.funding, rate.InexactFloat64()*100)
+		if mark.Valid && index.Valid && index.Decimal.IsPositive() {
+			markPrice, indexPrice := mark.Decimal.InexactFloat64(), index.Decimal.InexactFloat64()
+			market.basis = append(market.basis, (markPrice-indexPrice)/indexPrice*100)
+		}
+	}
+	return ordered, rows.Err()
+}
+
+func (m *carrySeries) stats() CarryStats {
+	stats := CarryStats{
+		Exchange:      m.exchange,
+		Symbol:        m.symbol,
+		Samples:       len(m.funding),
+		From:          m.times[0],
+		To:            m.times[len(m.times)-1],
+		IntervalHours: fundingIntervalHours(m.times),
+		Funding:       distributionOf(m.funding),
+	}
+	positive := 0
+	for _, rate := range m.funding {
+		if rate > 0 {
+			positive++
+		}
+	}
+	stats.PositivePct = float64(positive) / float64(len(m.funding)) * 100
+	stats.AnnualizedCarryPct = stats.Funding.Mean * hoursPerYear / stats.IntervalHours
+	if len(m.basis) > 0 {
+		basis := distributionOf(m.basis)
+		stats.Basis = &basis
+	}
+	return stats
+}
+
+// fundingIntervalHours estimates the funding interval as the median spacing
+// of funding times, between one and 24 hours. Fewer than two samples assume
+// the common eight-hour interval.
+func fundingIntervalHours(times []time.Time) float64 {
+	var gaps []float64
+	for i := 1; i < len(times); i++ {
+		if gap := times[i].Sub(times[i-1]).Hours(); gap > 0 {
+			gaps = append(gaps, gap)
+		}
+	}
+	if len(gaps) == 0 {
+		return defaultFundingIntervalHours
+	}
+	hours := math.Round(medianOf(gaps)*100) / 100
+	return math.Min(math.Max(hours, 1), 24)
+}
+
+// distributionOf summarises a chronological series; the latest value is the
+// last one.
+func distributionOf(values []float64) CarryDistribution {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	return CarryDistribution{
+		Latest: values[len(values)-1],
+		Mean:   sum / float64(len(values)),
+		Median: percentileOf(sorted, 50),
+		P10:    percentileOf(sorted, 10),
+		P90:    percentileOf(sorted, 90),
+		Min:    sorted[0],
+		Max:    sorted[len(sorted)-1],
+	}
+}
+
+// percentileOf interpolates the p-th percentile of sorted values.
+func percentileOf(sorted []float64, p float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var carryColumns = []string{"exchange", "symbol", "funding_time", "funding_rate", "mark_price", "index_price"}
+
+func TestCarryAnalyticsService_Report(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	service := NewCarryAnalyticsService(database.NewMockDBPool(mockPool))
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	rows := pgxmock.NewRows(carryColumns)
+	// BTC funds every 8 hours at 0.01%, 0.02%, 0.03% and -0.02%
+	for i, rate := range []float64{0.0001, 0.0002, 0.0003, -0.0002} {
+		rows.AddRow("binance", "BTC/USDT:USDT", now.Add(time.Duration(i-4)*8*time.Hour), rate, 101.0, 100.0)
+	}
+	// ETH funds hourly at 0.001% without index prices
+	for i := 0; i < 3; i++ {
+		rows.AddRow("bybit", "ETH/USDT:USDT", now.Add(time.Duration(i-3)*time.Hour), 0.00001, nil, nil)
+	}
+	mockPool.ExpectQuery("FROM funding_rate_history").
+		WithArgs(now.AddDate(0, 0, -30)).
+		WillReturnRows(rows)
+
+	report, err := service.Report(t.Context(), CarryQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, 30, report.Days)
+	require.Len(t, report.Markets, 2)
+
+	btc := report.Markets[0]
+	assert.Equal(t, "BTC/USDT", btc.Symbol)
+	assert.Equal(t, 4, btc.Samples)
+	assert.InDelta(t, 8.0, btc.IntervalHours, 1e-9)
+	assert.InDelta(t, 0.01, btc.Funding.Mean, 1e-9)
+	assert.InDelta(t, 0.015, btc.Funding.Median, 1e-9)
+	assert.InDelta(t, -0.02, btc.Funding.Latest, 1e-9)
+	assert.InDelta(t, 75.0, btc.PositivePct, 1e-9)
+	// 0.01% over 1095 eight-hour intervals a year
+	assert.InDelta(t, 10.95, btc.AnnualizedCarryPct, 1e-9)
+	require.NotNil(t, btc.Basis)
+	assert.InDelta(t, 1.0, btc.Basis.Mean, 1e-9)
+
+	eth := report.Markets[1]
+	assert.Equal(t, "bybit", eth.Exchange)
+	assert.InDelta(t, 1.0, eth.IntervalHours, 1e-9)
+	assert.InDelta(t, 0.001*8760, eth.AnnualizedCarryPct, 1e-9)
+	assert.Nil(t, eth.Basis)
+	require.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestCarryAnalyticsService_ReportFilters(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	service := NewCarryAnalyticsService(database.NewMockDBPool(mockPool))
+
+	mockPool.ExpectQuery("AND exchange = \\$2 AND symbol IN \\(\\$3, \\$4\\)").
+		WithArgs(pgxmock.AnyArg(), "okx", "BTC/USDT", "BTC/USDT:USDT").
+		WillReturnRows(pgxmock.NewRows(carryColumns))
+
+	report, err := service.Report(t.Context(), CarryQuery{Symbol: "btc-usdt", Exchange: "OKX", Days: 7})
+	require.NoError(t, err)
+	assert.Equal(t, 7, report.Days)
+	assert.Empty(t, report.Markets)
+
+	_, err = service.Report(t.Context(), CarryQuery{Days: 400})
+	assert.True(t, errors.Is(err, ErrCarryInvalidQuery))
+	_, err = service.Report(t.Context(), CarryQuery{Limit: -1})
+	assert.True(t, errors.Is(err, ErrCarryInvalidQuery))
+	require.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestPercentileOf(t *testing.T) {
+	sorted := []float64{1, 2, 3, 4, 5}
+	assert.InDelta(t, 3.0, percentileOf(sorted, 50), 1e-9)
+	assert.InDelta(t, 1.4, percentileOf(sorted, 10), 1e-9)
+	assert.InDelta(t, 4.6, percentileOf(sorted, 90), 1e-9)
+	assert.InDelta(t, 7.0, percentileOf([]float64{7}, 90), 1e-9)
+}
@@ -4,11 +4,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"time"
 
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/telemetry"
 	"github.com/shopspring/decimal"
 )
 
@@ -29,8 +34,16 @@ type CCXTOrderExecutor struct {
 	serviceURL string
 	apiKey     string
 	httpClient *http.Client
+	kpis       KPIRecorder
+	quarantine *SymbolQuarantine
+	drain      *ShutdownDrain
+	logger     *slog.Logger
 }
 
+// ErrOrderRejected is returned when the exchange refused an order as invalid,
+// as opposed to the CCXT service or exchange being unavailable.
+var ErrOrderRejected = errors.New("order placement failed")
+
 func NewCCXTOrderExecutor(cfg CCXTOrderExecutorConfig) *CCXTOrderExecutor {
 	return &CCXTOrderExecutor{
 		serviceURL: cfg.ServiceURL,
@@ -38,10 +51,57 @@ func NewCCXTOrderExecutor(cfg CCXTOrderExecutorConfig) *CCXTOrderExecutor {
 		httpClient: &http.Client{
 			Timeout: cfg.Timeout,
 		},
+		logger: telemetry.Logger(),
 	}
 }
 
 func (e *CCXTOrderExecutor) PlaceOrder(ctx context.Context, exchange, symbol, side, orderType string, amount decimal.Decimal, price *decimal.Decimal) (string, error) {
+	return e.PlaceOrderWithOptions(ctx, exchange, symbol, side, orderType, amount, price, OrderOptions{})
+}
+
+// PlaceOrderWithOptions places an order with a time-in-force and post-only
+// flag. A post-only order that would have taken liquidity returns
+// ErrPostOnlyRejected.
+func (e *CCXTOrderExecutor) PlaceOrderWithOptions(ctx context.Context, exchange, symbol, side, orderType string, amount decimal.Decimal, price *decimal.Decimal, options OrderOptions) (string, error) {
+	defer e.drain.Track()()
+	orderID, err := e.placeOrder(ctx, exchange, symbol, side, orderType, amount, price, options)
+	if e.kpis != nil {
+		if err != nil {
+			e.kpis.RecordKPI(KPIOrdersFailed, 1)
+		} else {
+			e.kpis.RecordKPI(KPIOrdersPlaced, 1)
+		}
+	}
+	if exchangeErr, ok := ccxt.AsExchangeError(err); ok {
+		e.logger.Warn("Exchange rejected order", "exchange", exchange, "symbol", symbol,
+			"kind", exchangeErr.Kind, "code", exchangeErr.Code, "message", exchangeErr.Message, "hint", exchangeErr.Hint)
+	}
+	if errors.Is(err, ErrOrderRejected) {
+		e.quarantine.RecordStrike(ctx, QuarantineTriggerOrderRejection, exchange, symbol, err.Error())
+	}
+	return orderID, err
+}
+
+// SetKPIRecorder counts placed and failed orders.
+func (e *CCXTOrderExecutor) SetKPIRecorder(kpis KPIRecorder) {
+	e.kpis = kpis
+}
+
+// SetQuarantine quarantines pairs whose orders are repeatedly rejected.
+// Orders are never blocked by it, so positions can still be closed.
+func (e *CCXTOrderExecutor) SetQuarantine(quarantine *SymbolQuarantine) {
+	e.quarantine = quarantine
+}
+
+// SetShutdownDrain keeps a graceful shutdown waiting for in-flight orders.
+func (e *CCXTOrderExecutor) SetShutdownDrain(drain *ShutdownDrain) {
+	e.drain = drain
+}
+
+func (e *CCXTOrderExecutor) placeOrder(ctx context.Context, exchange, symbol, side, orderType string, amount decimal.Decimal, price *decimal.Decimal, options OrderOptions) (string, error) {
+	if err := options.Validate(orderType); err != nil {
+		return "", err
+	}
 	reqBody := map[string]interface{}{
 		"exchange": exchange,
 		"symbol":   symbol,
@@ -53,6 +113,15 @@ func (e *CCXTOrderExecutor) PlaceOrder(ctx context.Context, exchange, symbol, si
 	if price != nil {
 		reqBody["price"] = price.InexactFloat64()
 	}
+	if options.TimeInForce != "" {
+		reqBody["timeInForce"] = options.TimeInForce
+	}
+	if options.PostOnly {
+		reqBody["postOnly"] = true
+	}
+	if options.ClientOrderID != "" {
+		reqBody["params"] = map[string]interface{}{"clientOrderId": options.ClientOrderID}
+	}
 
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
@@ -75,6 +144,31 @@ func (e *CCXTOrderExecutor) PlaceOrder(ctx context.Context, exchange, symbol, si
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		var failure struct {
+			Error string `json:"error"`
+			Code  string `json:"code"`
+		}
+		message := string(body)
+		if err := json.Unmarshal(body, &failure); err == nil {
+			message = failure.Error
+		}
+		if resp.StatusCode == http.StatusConflict && failure.Code == "post_only_rejected" {
+			return "", ErrPostOnlyRejected
+		}
+		if exchangeErr := ccxt.MapExchangeError(exchange, message); exchangeErr != nil {
+			if exchangeErr.Kind == ccxt.ExchangeErrorInvalidOrder || exchangeErr.Kind == ccxt.ExchangeErrorBadSymbol {
+				return "", fmt.Errorf("%w: %w", ErrOrderRejected, exchangeErr)
+			}
+			return "", fmt.Errorf("order placement failed: %w", exchangeErr)
+		}
+	}
+	switch resp.StatusCode {
+	case http.StatusBadRequest, http.StatusConflict, http.StatusUnprocessableEntity:
+		// Auth, rate limit and server errors say nothing about the pair
+		return "", fmt.Errorf("%w with status: %d", ErrOrderRejected, resp.StatusCode)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return "", fmt.Errorf("order placement failed with status: %d", resp.StatusCode)
 	}
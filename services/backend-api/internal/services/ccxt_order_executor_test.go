@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/irfndi/neuratrade/internal/ccxt"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -213,3 +214,28 @@ func TestCCXTOrderExecutor_GetOrderTrades(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, trades, 2)
 }
+
+func TestCCXTOrderExecutor_MapsExchangeErrors(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": body})
+	}))
+	defer server.Close()
+	executor := NewCCXTOrderExecutor(CCXTOrderExecutorConfig{ServiceURL: server.URL, Timeout: 5 * time.Second})
+
+	body = `binance {"code":-2019,"msg":"Margin is insufficient."}`
+	_, err := executor.PlaceOrder(context.Background(), "binance", "BTC/USDT", "buy", "market", decimal.NewFromFloat(0.5), nil)
+	assert.True(t, ccxt.IsExchangeErrorKind(err, ccxt.ExchangeErrorInsufficientMargin))
+	assert.NotErrorIs(t, err, ErrOrderRejected, "margin says nothing about the pair")
+
+	body = `binance {"code":-1013,"msg":"Filter failure: MIN_NOTIONAL"}`
+	_, err = executor.PlaceOrder(context.Background(), "binance", "BTC/USDT", "buy", "market", decimal.NewFromFloat(0.5), nil)
+	assert.True(t, ccxt.IsExchangeErrorKind(err, ccxt.ExchangeErrorInvalidOrder))
+	assert.ErrorIs(t, err, ErrOrderRejected)
+
+	body = "socket hang up"
+	_, err = executor.PlaceOrder(context.Background(), "binance", "BTC/USDT", "buy", "market", decimal.NewFromFloat(0.5), nil)
+	assert.EqualError(t, err, "order placement failed with status: 500")
+}
@@ -0,0 +1,415 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultChartWidth  = 960
+	defaultChartHeight = 540
+	chartAxisWidth     = 84
+	chartPadding       = 12
+	// The volume pane takes this share of the plot height.
+	chartVolumeShare = 0.18
+)
+
+// Chart colors.
+var (
+	ChartBackground = color.RGBA{R: 19, G: 23, B: 34, A: 255}
+	ChartGrid       = color.RGBA{R: 42, G: 46, B: 57, A: 255}
+	ChartText       = color.RGBA{R: 178, G: 181, B: 190, A: 255}
+	ChartUp         = color.RGBA{R: 38, G: 166, B: 154, A: 255}
+	ChartDown       = color.RGBA{R: 239, G: 83, B: 80, A: 255}
+	ChartBlue       = color.RGBA{R: 41, G: 98, B: 255, A: 255}
+	ChartOrange     = color.RGBA{R: 255, G: 152, B: 0, A: 255}
+	ChartPurple     = color.RGBA{R: 156, G: 39, B: 176, A: 255}
+	ChartYellow     = color.RGBA{R: 255, G: 235, B: 59, A: 255}
+)
+
+// ChartCandle is one candle of a rendered chart.
+type ChartCandle struct {
+	Time   time.Time
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	Volume float64
+}
+
+// ChartSeries is an indicator line drawn over the candles; values align with
+// the candles and NaN leaves a gap.
+type ChartSeries struct {
+	Name   string
+	Color  color.RGBA
+	Values []float64
+}
+
+// ChartLevel is a horizontal price line, such as a position entry.
+type ChartLevel struct {
+	Label string
+	Price float64
+	Color color.RGBA
+}
+
+// ChartMarker marks a signal at a candle: buys below the low, sells above
+// the high.
+type ChartMarker struct {
+	Time time.Time
+	Side string
+}
+
+// ChartSpec describes a candle chart to render.
+type ChartSpec struct {
+	Width   int
+	Height  int
+	Candles []ChartCandle
+	Series  []ChartSeries
+	Levels  []ChartLevel
+	Markers []ChartMarker
+}
+
+// chartCanvas maps prices and candle indexes to pixels.
+type chartCanvas struct {
+	img                *image.RGBA
+	plot               image.Rectangle
+	priceBottom        int
+	volumeTop          int
+	minPrice, maxPrice float64
+	maxVolume          float64
+	step               float64
+}
+
+// RenderChart draws candles with volume, indicator lines, price levels and
+// signal markers as a PNG.
+//
+// Parameters:
+//
+//	spec: Chart contents; zero sizes use 960x540.
+//
+// Returns:
+//
+//	[]byte: PNG image.
+//	error: Error if there are no candles or encoding fails.
+func RenderChart(spec ChartSpec) ([]byte, error) {
+	if len(spec.Candles) == 0 {
+		return nil, fmt.Errorf("no candles to chart")
+	}
+	if spec.Width <= 0 {
+		spec.Width = defaultChartWidth
+	}
+	if spec.Height <= 0 {
+		spec.Height = defaultChartHeight
+	}
+
+	canvas := newChartCanvas(spec)
+	canvas.drawGrid()
+	canvas.drawVolume(spec.Candles)
+	for _, level := range spec.Levels {
+		canvas.drawLevel(level)
+	}
+	canvas.drawCandles(spec.Candles)
+	for _, series := range spec.Series {
+		canvas.drawSeries(series)
+	}
+	canvas.drawMarkers(spec.Candles, spec.Markers)
+	canvas.drawLastPrice(spec.Candles[len(spec.Candles)-1])
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, canvas.img); err != nil {
+		return nil, fmt.Errorf("failed to encode chart: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func newChartCanvas(spec ChartSpec) *chartCanvas {
+	img := image.NewRGBA(image.Rect(0, 0, spec.Width, spec.Height))
+	fillRect(img, img.Bounds(), ChartBackground)
+
+	plot := image.Rect(chartPadding, chartPadding, spec.Width-chartAxisWidth, spec.Height-chartPadding)
+	volumeHeight := int(float64(plot.Dy()) * chartVolumeShare)
+	canvas := &chartCanvas{
+		img:         img,
+		plot:        plot,
+		priceBottom: plot.Max.Y - volumeHeight - chartPadding,
+		volumeTop:   plot.Max.Y - volumeHeight,
+		minPrice:    math.Inf(1),
+		maxPrice:    math.Inf(-1),
+		step:        float64(plot.Dx()) / float64(len(spec.Candles)),
+	}
+
+	include := func(price float64) {
+		if price > 0 && !math.IsNaN(price) && !math.IsInf(price, 0) {
+			canvas.minPrice = math.Min(canvas.minPrice, price)
+			canvas.maxPrice = math.Max(canvas.maxPrice, price)
+		}
+	}
+	for _, candle := range spec.Candles {
+		include(candle.Low)
+		include(candle.High)
+		canvas.maxVolume = math.Max(canvas.maxVolume, candle.Volume)
+	}
+	for _, series := range spec.Series {
+		for _, value := range series.Values {
+			include(value)
+		}
+	}
+	for _, level := range spec.Levels {
+		include(level.Price)
+	}
+	if canvas.maxPrice <= canvas.minPrice {
+		canvas.minPrice, canvas.maxPrice = canvas.minPrice*0.99, canvas.maxPrice*1.01
+	}
+	// Leave room for markers above the high and below the low
+	margin := (canvas.maxPrice - canvas.minPrice) * 0.05
+	canvas.minPrice -= margin
+	canvas.maxPrice += margin
+	return canvas
+}
+
+func (c *chartCanvas) y(price float64) int {
+	ratio := (price - c.minPrice) / (c.maxPrice - c.minPrice)
+	return c.priceBottom - int(ratio*float64(c.priceBottom-c.plot.Min.Y))
+}
+
+func (c *chartCanvas) x(index int) int {
+	return c.plot.Min.X + int((float64(index)+0.5)*c.step)
+}
+
+func (c *chartCanvas) drawGrid() {
+	const lines = 5
+	for i := 0; i <= lines; i++ {
+		price := c.minPrice + (c.maxPrice-c.minPrice)*float64(i)/lines
+		y := c.y(price)
+		drawHLine(c.img, c.plot.Min.X, c.plot.Max.X, y, ChartGrid, 0)
+		drawText(c.img, c.plot.Max.X+6, y-5, formatChartPrice(price), ChartText)
+	}
+	drawHLine(c.img, c.plot.Min.X, c.plot.Max.X, c.volumeTop-chartPadding/2, ChartGrid, 0)
+}
+
+func (c *chartCanvas) drawVolume(candles []ChartCandle) {
+	if c.maxVolume <= 0 {
+		return
+	}
+	width := max(1, int(c.step*0.7))
+	for i, candle := range candles {
+		height := int(candle.Volume / c.maxVolume * float64(c.plot.Max.Y-c.volumeTop))
+		clr := dim(ChartUp)
+		if candle.Close < candle.Open {
+			clr = dim(ChartDown)
+		}
+		x := c.x(i) - width/2
+		fillRect(c.img, image.Rect(x, c.plot.Max.Y-height, x+width, c.plot.Max.Y), clr)
+	}
+}
+
+func (c *chartCanvas) drawCandles(candles []ChartCandle) {
+	width := max(1, int(c.step*0.7))
+	for i, candle := range candles {
+		clr := ChartUp
+		if candle.Close < candle.Open {
+			clr = ChartDown
+		}
+		x := c.x(i)
+		drawVLine(c.img, x, c.y(candle.High), c.y(candle.Low), clr)
+		top, bottom := c.y(math.Max(candle.Open, candle.Close)), c.y(math.Min(candle.Open, candle.Close))
+		fillRect(c.img, image.Rect(x-width/2, top, x-width/2+width, bottom+1), clr)
+	}
+}
+
+func (c *chartCanvas) drawSeries(series ChartSeries) {
+	prevX, prevY, havePrev := 0, 0, false
+	for i, value := range series.Values {
+		if math.IsNaN(value) || value <= 0 {
+			havePrev = false
+			continue
+		}
+		x, y := c.x(i), c.y(value)
+		if havePrev {
+			drawLine(c.img, prevX, prevY, x, y, series.Color)
+		}
+		prevX, prevY, havePrev = x, y, true
+	}
+}
+
+func (c *chartCanvas) drawLevel(level ChartLevel) {
+	y := c.y(level.Price)
+	drawHLine(c.img, c.plot.Min.X, c.plot.Max.X, y, level.Color, 6)
+	fillRect(c.img, image.Rect(c.plot.Max.X+2, y-7, c.plot.Max.X+chartAxisWidth-4, y+8), level.Color)
+	drawText(c.img, c.plot.Max.X+6, y-5, formatChartPrice(level.Price), ChartBackground)
+}
+
+func (c *chartCanvas) drawLastPrice(last ChartCandle) {
+	clr := ChartUp
+	if last.Close < last.Open {
+		clr = ChartDown
+	}
+	c.drawLevel(ChartLevel{Price: last.Close, Color: clr})
+}
+
+func (c *chartCanvas) drawMarkers(candles []ChartCandle, markers []ChartMarker) {
+	size := max(9, int(c.step))
+	for _, marker := range markers {
+		i := nearestCandle(candles, marker.Time)
+		if i < 0 {
+			continue
+		}
+		x := c.x(i)
+		if marker.Side == "sell" {
+			drawTriangle(c.img, x, c.y(candles[i].High)-4, size, false, ChartDown)
+		} else {
+			drawTriangle(c.img, x, c.y(candles[i].Low)+4, size, true, ChartUp)
+		}
+	}
+}
+
+// nearestCandle returns the index of the candle containing t, or -1 when t is
+// outside the charted range.
+func nearestCandle(candles []ChartCandle, t time.Time) int {
+	if len(candles) == 0 || t.Before(candles[0].Time) {
+		return -1
+	}
+	for i := len(candles) - 1; i >= 0; i-- {
+		if !t.Before(candles[i].Time) {
+			return i
+		}
+	}
+	return -1
+}
+
+func formatChartPrice(price float64) string {
+	decimals := 2
+	switch {
+	case price < 1:
+		decimals = 5
+	case price < 100:
+		decimals = 3
+	case price >= 10000:
+		decimals = 1
+	}
+	return strconv.FormatFloat(price, 'f', decimals, 64)
+}
+
+func dim(clr color.RGBA) color.RGBA {
+	return color.RGBA{R: clr.R / 2, G: clr.G / 2, B: clr.B / 2, A: 255}
+}
+
+func fillRect(img *image.RGBA, rect image.Rectangle, clr color.RGBA) {
+	rect = rect.Canon().Intersect(img.Bounds())
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			img.SetRGBA(x, y, clr)
+		}
+	}
+}
+
+// drawHLine draws a horizontal line, dashed when dash is positive.
+func drawHLine(img *image.RGBA, x0, x1, y int, clr color.RGBA, dash int) {
+	for x := x0; x < x1; x++ {
+		if dash > 0 && (x/dash)%2 == 1 {
+			continue
+		}
+		img.SetRGBA(x, y, clr)
+	}
+}
+
+func drawVLine(img *image.RGBA, x, y0, y1 int, clr color.RGBA) {
+	if y0 > y1 {
+		y0, y1 = y1, y0
+	}
+	for y := y0; y <= y1; y++ {
+		img.SetRGBA(x, y, clr)
+	}
+}
+
+// drawLine draws a line with Bresenham's algorithm.
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, clr color.RGBA) {
+	dx, dy := absInt(x1-x0), -absInt(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+	for {
+		img.SetRGBA(x0, y0, clr)
+		img.SetRGBA(x0, y0+1, clr)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		if e2 := 2 * err; e2 >= dy {
+			err += dy
+			x0 += sx
+		} else {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// drawTriangle draws a filled triangle with its tip at (x, y), pointing up
+// or down.
+func drawTriangle(img *image.RGBA, x, y, size int, up bool, clr color.RGBA) {
+	for row := 0; row < size; row++ {
+		half := row / 2
+		rowY := y + row
+		if !up {
+			rowY = y - row
+		}
+		for col := x - half; col <= x+half; col++ {
+			img.SetRGBA(col, rowY, clr)
+		}
+	}
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// chartGlyphs is a 3x5 bitmap font for price labels; each row is three bits,
+// most significant bit on the left.
+var chartGlyphs = map[rune][5]uint8{
+	'0': {7, 5, 5, 5, 7},
+	'1': {2, 6, 2, 2, 7},
+	'2': {7, 1, 7, 4, 7},
+	'3': {7, 1, 7, 1, 7},
+	'4': {5, 5, 7, 1, 1},
+	'5': {7, 4, 7, 1, 7},
+	'6': {7, 4, 7, 5, 7},
+	'7': {7, 1, 1, 1, 1},
+	'8': {7, 5, 7, 5, 7},
+	'9': {7, 5, 7, 1, 7},
+	'.': {0, 0, 0, 0, 2},
+	'-': {0, 0, 7, 0, 0},
+}
+
+// drawText draws text in the bitmap font at twice its size.
+func drawText(img *image.RGBA, x, y int, text string, clr color.RGBA) {
+	const scale = 2
+	for _, r := range text {
+		glyph, ok := chartGlyphs[r]
+		if !ok {
+			x += 4 * scale
+			continue
+		}
+		for row, bits := range glyph {
+			for col := 0; col < 3; col++ {
+				if bits&(4>>col) != 0 {
+					fillRect(img, image.Rect(x+col*scale, y+row*scale, x+(col+1)*scale, y+(row+1)*scale), clr)
+				}
+			}
+		}
+		x += 4 * scale
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"bytes"
+	"image/png"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderChart(t *testing.T) {
+	start := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	var candles []ChartCandle
+	line := make([]float64, 60)
+	for i := 0; i < 60; i++ {
+		price := 100 + 10*math.Sin(float64(i)/8)
+		candles = append(candles, ChartCandle{
+			Time: start.Add(time.Duration(i) * time.Hour), Open: price - 1, High: price + 2,
+			Low: price - 3, Close: price + float64(i%3-1), Volume: float64(10 + i%7),
+		})
+		line[i] = math.NaN()
+		if i >= 10 {
+			line[i] = price
+		}
+	}
+
+	image, err := RenderChart(ChartSpec{
+		Width:   640,
+		Height:  360,
+		Candles: candles,
+		Series:  []ChartSeries{{Name: "sma", Color: ChartBlue, Values: line}},
+		Levels:  []ChartLevel{{Label: "long", Price: 95, Color: ChartUp}},
+		Markers: []ChartMarker{{Time: start.Add(20 * time.Hour), Side: "buy"}, {Time: start.Add(-time.Hour), Side: "sell"}},
+	})
+	require.NoError(t, err)
+	if path := os.Getenv("CHART_RENDER_OUTPUT"); path != "" {
+		require.NoError(t, os.WriteFile(path, image, 0o600))
+	}
+	decoded, err := png.Decode(bytes.NewReader(image))
+	require.NoError(t, err)
+	assert.Equal(t, 640, decoded.Bounds().Dx())
+	assert.Equal(t, 360, decoded.Bounds().Dy())
+
+	_, err = RenderChart(ChartSpec{})
+	assert.Error(t, err)
+}
+
+func TestNearestCandle(t *testing.T) {
+	start := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	candles := []ChartCandle{{Time: start}, {Time: start.Add(time.Hour)}}
+	assert.Equal(t, -1, nearestCandle(candles, start.Add(-time.Minute)))
+	assert.Equal(t, 0, nearestCandle(candles, start.Add(30*time.Minute)))
+	assert.Equal(t, 1, nearestCandle(candles, start.Add(5*time.Hour)))
+}
@@ -390,6 +390,12 @@ type CollectorService struct {
 	lastFundingCollection   map[string]time.Time
 	symbolRefreshMu         sync.RWMutex
 	fundingCollectionMu     sync.RWMutex
+	// Derivatives positioning (open interest and long/short ratios)
+	positioningClient         PositioningClient
+	positioningStore          *PositioningStore
+	perpSymbols               map[string][]string // perps listed with funding rates, per exchange
+	lastPositioningCollection map[string]time.Time
+	positioningMu             sync.RWMutex
 	// Anti-manipulation filters
 	lastPrice   sync.Map // map[string]priceCacheEntry
 	volumeStats sync.Map // map[string]volumeStatsEntry
@@ -397,6 +403,7 @@ type CollectorService struct {
 	tickerInterval        time.Duration
 	symbolRefreshInterval time.Duration
 	fundingRateInterval   time.Duration
+	positioningInterval   time.Duration
 	// Readiness state
 	isInitialized    bool
 	isReady          bool
@@ -411,6 +418,16 @@ type CollectorService struct {
 	performanceMonitor    *PerformanceMonitor
 	// Resource optimization
 	resourceOptimizer *ResourceOptimizer
+	// Batched market data writes
+	marketDataWriter *MarketDataWriter
+	// Bad tick quarantine
+	tickFilter *TickAnomalyFilter
+	// Exchange/symbol quarantine after repeated stale or anomalous data
+	quarantine *SymbolQuarantine
+	// Work split with the other replicas; nil collects everything
+	partitioner *CollectorPartitioner
+	// Last successful ticker and candle per pair, checked against SLAs
+	freshness *SymbolFreshnessTracker
 	// Logging
 	logger logging.Logger
 }
@@ -495,10 +512,37 @@ func NewCollectorService(db DBPool, ccxtService ccxt.CCXTService, cfg *config.Co
 		DelayBetweenBatches:   cfg.Backfill.DelayBetweenBatches,
 	}
 
+	// Initialize market data write buffering
+	writeFlushInterval := time.Duration(0)
+	if cfg.MarketData.WriteFlushInterval != "" {
+		if duration, err := time.ParseDuration(cfg.MarketData.WriteFlushInterval); err == nil {
+			writeFlushInterval = duration
+		}
+	}
+	marketDataWriter := NewMarketDataWriter(db, MarketDataWriterConfig{
+		BatchSize:     cfg.MarketData.WriteBatchSize,
+		FlushInterval: writeFlushInterval,
+		BufferSize:    cfg.MarketData.WriteBufferSize,
+	})
+	tickFilter := NewTickAnomalyFilter(TickAnomalyConfig{
+		WindowSize:   cfg.MarketData.AnomalyWindowSize,
+		MaxDeviation: cfg.MarketData.AnomalyMaxDeviation,
+		MinJumpPct:   cfg.MarketData.AnomalyMinJumpPct,
+		MaxJumpPct:   cfg.MarketData.AnomalyMaxJumpPct,
+		MaxSpreadPct: cfg.MarketData.AnomalyMaxSpreadPct,
+		ConfirmTicks: cfg.MarketData.AnomalyConfirmTicks,
+	})
+
 	// Initialize separate intervals for different operations
 	tickerInterval := time.Duration(intervalSeconds) * time.Second // 5 minutes (from config)
 	symbolRefreshInterval := 1 * time.Hour                         // 1 hour for symbol refresh
 	fundingRateInterval := 15 * time.Minute                        // 15 minutes for funding rates
+	positioningInterval := 15 * time.Minute
+	if duration, err := time.ParseDuration(cfg.MarketData.PositioningInterval); err == nil && duration > 0 {
+		positioningInterval = duration
+	}
+	// Positioning is optional: only the concrete CCXT service fetches it
+	positioningClient, _ := ccxtService.(PositioningClient)
 
 	// Initialize logger with config-provided log level
 	logLevel := cfg.Telemetry.LogLevel
@@ -510,6 +554,16 @@ func NewCollectorService(db DBPool, ccxtService ccxt.CCXTService, cfg *config.Co
 	}
 	logger := logging.NewStandardLogger(logLevel, cfg.Environment)
 
+	// Freshness SLAs; unparseable values fall back to the defaults
+	freshnessConfig := SymbolFreshnessConfig{}
+	freshnessConfig.TickerSLA, _ = time.ParseDuration(cfg.MarketData.FreshnessTickerSLA)
+	freshnessConfig.CandleSLA, _ = time.ParseDuration(cfg.MarketData.FreshnessCandleSLA)
+	overrides, err := ParseFreshnessOverrides(cfg.MarketData.FreshnessSLAOverrides)
+	if err != nil {
+		logger.WithError(err).Warn("Ignoring invalid freshness SLA overrides")
+	}
+	freshnessConfig.Overrides = overrides
+
 	// Initialize error recovery components
 	logrusLogger := zaplogrus.New()
 	circuitBreakerManager := NewCircuitBreakerManager(logrusLogger)
@@ -582,10 +636,16 @@ func NewCollectorService(db DBPool, ccxtService ccxt.CCXTService, cfg *config.Co
 		redisClient:             redisClient,
 		lastSymbolRefresh:       make(map[string]time.Time),
 		lastFundingCollection:   make(map[string]time.Time),
+		// Initialize derivatives positioning collection
+		positioningClient:         positioningClient,
+		positioningStore:          NewPositioningStore(db, 0),
+		perpSymbols:               make(map[string][]string),
+		lastPositioningCollection: make(map[string]time.Time),
 		// Set separate intervals
 		tickerInterval:        tickerInterval,
 		symbolRefreshInterval: symbolRefreshInterval,
 		fundingRateInterval:   fundingRateInterval,
+		positioningInterval:   positioningInterval,
 		// Initialize data readiness signaling
 		dataReadyChan: make(chan struct{}),
 		// Initialize error recovery components
@@ -596,6 +656,10 @@ func NewCollectorService(db DBPool, ccxtService ccxt.CCXTService, cfg *config.Co
 		performanceMonitor:    performanceMonitor,
 		// Initialize resource optimization
 		resourceOptimizer: resourceOptimizer,
+		// Initialize batched market data writes
+		marketDataWriter: marketDataWriter,
+		tickFilter:       tickFilter,
+		freshness:        NewSymbolFreshnessTracker(redisClient, freshnessConfig),
 		// Initialize logging
 		logger: logger,
 	}
@@ -626,6 +690,10 @@ func (c *CollectorService) Start() error {
 	c.isInitialized = true
 	c.readinessMu.Unlock()
 
+	if err := c.marketDataWriter.Start(c.ctx); err != nil {
+		return fmt.Errorf("failed to start market data writer: %w", err)
+	}
+
 	// Start symbol collection and worker creation asynchronously
 	go c.initializeWorkersAsync()
 
@@ -796,7 +864,7 @@ func (c *CollectorService) initializeWorkersAsync() {
 		log.Printf("Triggering immediate initial data collection for %d workers", len(c.workers))
 		for _, worker := range c.workers {
 			// Fetch only first 10 symbols to quickly unblock dependent services
-			initialSymbols := worker.Symbols
+			initialSymbols := c.partitioner.Filter(worker.Exchange, worker.Symbols)
 			if len(initialSymbols) > 10 {
 				initialSymbols = initialSymbols[:10]
 			}
@@ -819,6 +887,11 @@ func (c *CollectorService) Stop() {
 	c.logger.Info("Stopping market data collector service...")
 	c.cancel()
 	c.wg.Wait()
+
+	// Write what the workers buffered before they stopped
+	flushCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	c.marketDataWriter.Stop(flushCtx)
 	c.logger.Info("Market data collector service stopped")
 }
 
@@ -871,6 +944,8 @@ func (c *CollectorService) WaitForFirstData(timeout time.Duration) error {
 
 	select {
 	case <-c.dataReadyChan:
+		// Write the buffered rows so dependent services can read them right away
+		c.marketDataWriter.Flush(c.ctx)
 		c.logger.Info("First market data collected successfully")
 		return nil
 	case <-time.After(timeout):
@@ -1000,6 +1075,7 @@ func (c *CollectorService) createWorker(exchangeID string, multiExchangeSymbols
 	}
 
 	c.workers[exchangeID] = worker
+	c.partitioner.AddExchange(c.ctx, exchangeID)
 
 	// Start worker goroutine
 	c.wg.Add(1)
@@ -1035,9 +1111,11 @@ func (c *CollectorService) runWorker(worker *Worker) {
 		}
 	}()
 
-	// Use ticker interval for main ticker data collection
-	ticker := time.NewTicker(c.tickerInterval)
-	defer ticker.Stop()
+	// Use ticker interval for main ticker data collection, lengthened while
+	// load is being shed
+	activeInterval := c.shedTickerInterval()
+	ticker := time.NewTicker(activeInterval)
+	defer func() { ticker.Stop() }()
 
 	// Add cache statistics logging every 10 minutes
 	cacheStatsTicker := time.NewTicker(10 * time.Minute)
@@ -1134,12 +1212,13 @@ func (c *CollectorService) runWorker(worker *Worker) {
 
 					// Increase interval temporarily to reduce load
 					ticker.Stop()
-					ticker = time.NewTicker(c.tickerInterval * 2) // Double the interval
+					activeInterval = c.shedTickerInterval() * 2 // Double the interval
+					ticker = time.NewTicker(activeInterval)
 					intervalIncreased = true
 					degradationStartTime = time.Now()
 					c.logger.WithFields(map[string]interface{}{
 						"exchange":     worker.Exchange,
-						"new_interval": c.tickerInterval * 2,
+						"new_interval": activeInterval,
 					}).Info("Temporarily increased collection interval")
 				}
 
@@ -1168,20 +1247,30 @@ func (c *CollectorService) runWorker(worker *Worker) {
 				// Restore normal interval if it was increased due to failures
 				if intervalIncreased {
 					ticker.Stop()
-					ticker = time.NewTicker(c.tickerInterval)
+					activeInterval = c.shedTickerInterval()
+					ticker = time.NewTicker(activeInterval)
 					intervalIncreased = false
 					degradationStartTime = time.Time{}
 					c.logger.WithFields(map[string]interface{}{
 						"exchange": worker.Exchange,
-						"interval": c.tickerInterval,
+						"interval": activeInterval,
 					}).Info("Restored normal collection interval")
+				} else if want := c.shedTickerInterval(); want != activeInterval {
+					// Follow load shedding level changes
+					activeInterval = want
+					ticker.Reset(activeInterval)
+					c.logger.WithFields(map[string]interface{}{
+						"exchange": worker.Exchange,
+						"interval": activeInterval,
+					}).Info("Collection interval adjusted for load shedding")
 				}
 			}
 
 			// Check if we've been degraded for too long (> 5 minutes) and force restore
 			if intervalIncreased && !degradationStartTime.IsZero() && time.Since(degradationStartTime) > 5*time.Minute {
 				ticker.Stop()
-				ticker = time.NewTicker(c.tickerInterval)
+				activeInterval = c.shedTickerInterval()
+				ticker = time.NewTicker(activeInterval)
 				intervalIncreased = false
 				degradationStartTime = time.Time{}
 				c.logger.WithFields(map[string]interface{}{
@@ -1195,7 +1284,9 @@ func (c *CollectorService) runWorker(worker *Worker) {
 			lastFundingCollection, exists := c.lastFundingCollection[worker.Exchange]
 			c.fundingCollectionMu.RUnlock()
 
-			if !exists || time.Since(lastFundingCollection) >= c.fundingRateInterval {
+			// Funding rates and positioning are collected exchange-wide by one replica
+			ownsExchange := c.partitioner.OwnsExchange(worker.Exchange)
+			if ownsExchange && (!exists || time.Since(lastFundingCollection) >= c.fundingRateInterval) {
 				c.logger.WithFields(map[string]interface{}{
 					"exchange": worker.Exchange,
 					"interval": c.fundingRateInterval,
@@ -1224,10 +1315,53 @@ func (c *CollectorService) runWorker(worker *Worker) {
 					c.fundingCollectionMu.Unlock()
 				}
 			}
+
+			// Open interest and long/short ratios follow the funding rates,
+			// which list the perps of the exchange
+			c.positioningMu.RLock()
+			lastPositioningCollection, exists := c.lastPositioningCollection[worker.Exchange]
+			c.positioningMu.RUnlock()
+
+			if c.positioningClient != nil && ownsExchange && (!exists || time.Since(lastPositioningCollection) >= c.positioningInterval) {
+				if err := c.collectPositioning(worker); err != nil {
+					c.logger.WithFields(map[string]interface{}{
+						"exchange": worker.Exchange,
+					}).WithError(err).Warn("Failed to collect derivatives positioning")
+				} else {
+					c.positioningMu.Lock()
+					c.lastPositioningCollection[worker.Exchange] = time.Now()
+					c.positioningMu.Unlock()
+				}
+			}
 		}
 	}
 }
 
+// ResourceManager returns the collector's resource manager, which also
+// drives load shedding.
+func (c *CollectorService) ResourceManager() *ResourceManager {
+	return c.resourceManager
+}
+
+// scanSymbols returns the worker symbols this replica collects this cycle,
+// trimmed while load is being shed.
+func (c *CollectorService) scanSymbols(worker *Worker) []string {
+	symbols := c.partitioner.Filter(worker.Exchange, worker.Symbols)
+	if c.resourceManager == nil {
+		return symbols
+	}
+	return c.resourceManager.ShedSymbols(symbols)
+}
+
+// shedTickerInterval returns the ticker interval lengthened for the active
+// load shedding level.
+func (c *CollectorService) shedTickerInterval() time.Duration {
+	if c.resourceManager == nil {
+		return c.tickerInterval
+	}
+	return c.resourceManager.ShedInterval(c.tickerInterval)
+}
+
 // collectTickerDataOnly collects only ticker data for worker symbols (no funding rates)
 func (c *CollectorService) collectTickerDataOnly(worker *Worker) error {
 	// Track performance metrics
@@ -1286,9 +1420,10 @@ func (c *CollectorService) collectTickerDataBulk(worker *Worker) error {
 	}).Info("Collecting ticker data (bulk)")
 
 	// Filter out blacklisted symbols before making the bulk request
-	validSymbols := make([]string, 0, len(worker.Symbols))
-	for _, symbol := range worker.Symbols {
-		symbolKey := fmt.Sprintf("%s:%s", worker.Exchange, symbol)
+	symbols := c.scanSymbols(worker)
+	validSymbols := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		symbolKey := cache.PairKey(worker.Exchange, symbol)
 		if isBlacklisted, reason := c.blacklistCache.IsBlacklisted(symbolKey); !isBlacklisted {
 			validSymbols = append(validSymbols, symbol)
 		} else {
@@ -1352,8 +1487,11 @@ func (c *CollectorService) collectTickerDataBulk(worker *Worker) error {
 		return fmt.Errorf("failed to fetch bulk ticker data with circuit breaker: %w", err)
 	}
 
+	// Bad ticks are quarantined before they reach the database or the cache
+	marketData = c.withoutAnomalousTicks(marketData)
+
 	// Channels to track async save results
-	successChan := make(chan bool, len(marketData))
+	successChan := make(chan models.MarketPrice, len(marketData))
 	errorChan := make(chan error, len(marketData))
 	successCount := 0
 
@@ -1397,15 +1535,17 @@ func (c *CollectorService) collectTickerDataBulk(worker *Worker) error {
 					}
 					c.readinessMu.Unlock()
 				}
-				successChan <- true
+				successChan <- t
 			}
 		}(i, ticker)
 	}
 
 	// Wait for all goroutines to complete
+	saved := make([]models.MarketPrice, 0, len(marketData))
 	for i := 0; i < len(marketData); i++ {
 		select {
-		case <-successChan:
+		case ticker := <-successChan:
+			saved = append(saved, ticker)
 			successCount++
 		case <-errorChan:
 			// Error already logged, continue processing
@@ -1416,6 +1556,7 @@ func (c *CollectorService) collectTickerDataBulk(worker *Worker) error {
 
 	// Cache bulk results for fast API responses (best-effort)
 	c.cacheBulkTickerData(worker.Exchange, marketData)
+	c.freshness.RecordTickers(c.ctx, worker.Exchange, saved)
 
 	c.logger.WithFields(map[string]interface{}{
 		"exchange":      worker.Exchange,
@@ -1428,9 +1569,10 @@ func (c *CollectorService) collectTickerDataBulk(worker *Worker) error {
 // collectTickerDataSequential collects ticker data sequentially (fallback method)
 func (c *CollectorService) collectTickerDataSequential(worker *Worker) error {
 	// Filter out blacklisted symbols before sequential processing
-	validSymbols := make([]string, 0, len(worker.Symbols))
-	for _, symbol := range worker.Symbols {
-		symbolKey := fmt.Sprintf("%s:%s", worker.Exchange, symbol)
+	symbols := c.scanSymbols(worker)
+	validSymbols := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		symbolKey := cache.PairKey(worker.Exchange, symbol)
 		if isBlacklisted, reason := c.blacklistCache.IsBlacklisted(symbolKey); !isBlacklisted {
 			validSymbols = append(validSymbols, symbol)
 		} else {
@@ -1587,7 +1729,14 @@ func (c *CollectorService) saveBulkTickerData(ticker models.MarketPrice) error {
 
 	// Check if symbol should be blacklisted based on data quality
 	if shouldBlacklist, reason := c.shouldBlacklistTicker(ticker); shouldBlacklist {
-		symbolKey := fmt.Sprintf("%s:%s", ticker.ExchangeName, ticker.Symbol)
+		// Stale tickers are often transient; with a quarantine they only
+		// blacklist the pair once they repeat
+		if reason == QuarantineTriggerStaleData && c.quarantine != nil {
+			c.quarantine.RecordStrike(c.ctx, QuarantineTriggerStaleData, ticker.ExchangeName, ticker.Symbol,
+				fmt.Sprintf("last update %s", ticker.Timestamp.UTC().Format(time.RFC3339)))
+			return nil
+		}
+		symbolKey := cache.PairKey(ticker.ExchangeName, ticker.Symbol)
 		ttl, _ := time.ParseDuration(c.config.Blacklist.TTL)
 		c.blacklistCache.Add(symbolKey, reason, ttl)
 		c.logger.WithFields(map[string]interface{}{
@@ -1623,19 +1772,35 @@ func (c *CollectorService) saveBulkTickerData(ticker models.MarketPrice) error {
 	// does not provide these values. To get actual bid/ask volumes, the order book would need
 	// to be fetched separately, which would significantly increase API calls and rate limits.
 	// These fields are reserved for future implementation when order book data is integrated.
-	_, err = c.db.Exec(c.ctx,
-		`INSERT INTO market_data (
-			exchange_id, trading_pair_id,
-			bid, bid_volume, ask, ask_volume,
-			last_price, volume_24h,
-			timestamp, created_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		exchangeID, tradingPairID,
-		ticker.Bid, ticker.BidVolume, ticker.Ask, ticker.AskVolume,
-		ticker.Price, ticker.Volume,
-		ticker.Timestamp, time.Now())
-	if err != nil {
-		return fmt.Errorf("failed to save market data: %w", err)
+	// Rows are batched by the market data writer; it only refuses them when not running.
+	queued := c.marketDataWriter.Enqueue(MarketDataRow{
+		Exchange:      ticker.ExchangeName,
+		ExchangeID:    exchangeID,
+		TradingPairID: tradingPairID,
+		Bid:           decimal.NewNullDecimal(ticker.Bid),
+		BidVolume:     decimal.NewNullDecimal(ticker.BidVolume),
+		Ask:           decimal.NewNullDecimal(ticker.Ask),
+		AskVolume:     decimal.NewNullDecimal(ticker.AskVolume),
+		LastPrice:     ticker.Price,
+		Volume24h:     ticker.Volume,
+		Timestamp:     ticker.Timestamp,
+		CreatedAt:     time.Now(),
+	})
+	if !queued {
+		_, err = c.db.Exec(c.ctx,
+			`INSERT INTO market_data (
+				exchange_id, trading_pair_id,
+				bid, bid_volume, ask, ask_volume,
+				last_price, volume_24h,
+				timestamp, created_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			exchangeID, tradingPairID,
+			ticker.Bid, ticker.BidVolume, ticker.Ask, ticker.AskVolume,
+			ticker.Price, ticker.Volume,
+			ticker.Timestamp, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to save market data: %w", err)
+		}
 	}
 
 	// Signal first data collected (only once) - allows dependent services to start
@@ -1654,6 +1819,53 @@ func (c *CollectorService) saveBulkTickerData(ticker models.MarketPrice) error {
 	return nil
 }
 
+// withoutAnomalousTicks drops ticks the anomaly filter quarantines
+func (c *CollectorService) withoutAnomalousTicks(marketData []models.MarketPrice) []models.MarketPrice {
+	if c.tickFilter == nil {
+		return marketData
+	}
+	accepted := marketData[:0]
+	for _, ticker := range marketData {
+		if reason := c.tickFilter.Check(ticker); reason != "" {
+			c.quarantine.RecordStrike(c.ctx, QuarantineTriggerAnomaly, ticker.ExchangeName, ticker.Symbol, reason)
+			continue
+		}
+		accepted = append(accepted, ticker)
+	}
+	return accepted
+}
+
+// SetQuarantine enables automatic quarantine of pairs whose data is
+// repeatedly stale or anomalous.
+func (c *CollectorService) SetQuarantine(quarantine *SymbolQuarantine) {
+	c.quarantine = quarantine
+}
+
+// SetPartitioner splits collection with the other backend replicas: this
+// replica only collects the exchange/symbol partitions it holds.
+func (c *CollectorService) SetPartitioner(partitioner *CollectorPartitioner) {
+	c.partitioner = partitioner
+}
+
+// Freshness returns the tracker of the last successful ticker and candle
+// per pair, which reports the pairs breaching their freshness SLA.
+func (c *CollectorService) Freshness() *SymbolFreshnessTracker {
+	return c.freshness
+}
+
+// BlacklistCache returns the blacklist cache shared by collection and scanning.
+func (c *CollectorService) BlacklistCache() cache.BlacklistCache {
+	return c.blacklistCache
+}
+
+// GetQuarantinedTicks returns the ticks rejected as anomalous, newest first.
+func (c *CollectorService) GetQuarantinedTicks() []QuarantinedTick {
+	if c.tickFilter == nil {
+		return []QuarantinedTick{}
+	}
+	return c.tickFilter.Quarantined()
+}
+
 // shouldBlacklistTicker determines if a ticker should be blacklisted based on data quality
 func (c *CollectorService) shouldBlacklistTicker(ticker models.MarketPrice) (bool, string) {
 	// Check for zero or negative price
@@ -1692,7 +1904,7 @@ func (c *CollectorService) collectTickerDataDirect(exchange, symbol string) erro
 	}
 
 	// Check if symbol is blacklisted before making API call
-	symbolKey := fmt.Sprintf("%s:%s", exchange, symbol)
+	symbolKey := cache.PairKey(exchange, symbol)
 	if isBlacklisted, reason := c.blacklistCache.IsBlacklisted(symbolKey); isBlacklisted {
 		c.logger.WithFields(map[string]interface{}{
 			"symbol": symbolKey,
@@ -1741,7 +1953,7 @@ func (c *CollectorService) collectTickerDataDirect(exchange, symbol string) erro
 	if cbErr != nil {
 		// Check if the error indicates a symbol that should be blacklisted
 		if shouldBlacklist, reason := isBlacklistableError(cbErr); shouldBlacklist {
-			symbolKey := fmt.Sprintf("%s:%s", exchange, symbol)
+			symbolKey := cache.PairKey(exchange, symbol)
 			ttl, _ := time.ParseDuration(c.config.Blacklist.TTL)
 			c.blacklistCache.Add(symbolKey, reason, ttl)
 			c.logger.WithFields(map[string]interface{}{
@@ -1754,6 +1966,14 @@ func (c *CollectorService) collectTickerDataDirect(exchange, symbol string) erro
 		return fmt.Errorf("failed to fetch ticker data with circuit breaker: %w", cbErr)
 	}
 
+	// Bad ticks are quarantined instead of being stored
+	if c.tickFilter != nil {
+		if reason := c.tickFilter.Check(*ticker); reason != "" {
+			c.quarantine.RecordStrike(c.ctx, QuarantineTriggerAnomaly, exchange, symbol, reason)
+			return nil
+		}
+	}
+
 	// Ensure exchange exists and get its ID
 	exchangeID, err := c.getOrCreateExchange(exchange)
 	if err != nil {
@@ -1776,13 +1996,25 @@ func (c *CollectorService) collectTickerDataDirect(exchange, symbol string) erro
 	}
 
 	// Save market data to database with proper column mapping
-	_, err = c.db.Exec(c.ctx,
-		`INSERT INTO market_data (exchange_id, trading_pair_id, last_price, volume_24h, timestamp, created_at)
-		 VALUES (?, ?, ?, ?, ?, ?)`,
-		exchangeID, tradingPairID, ticker.Price, ticker.Volume, ticker.Timestamp, time.Now())
-	if err != nil {
-		return fmt.Errorf("failed to save market data: %w", err)
+	queued := c.marketDataWriter.Enqueue(MarketDataRow{
+		Exchange:      exchange,
+		ExchangeID:    exchangeID,
+		TradingPairID: tradingPairID,
+		LastPrice:     ticker.Price,
+		Volume24h:     ticker.Volume,
+		Timestamp:     ticker.Timestamp,
+		CreatedAt:     time.Now(),
+	})
+	if !queued {
+		_, err = c.db.Exec(c.ctx,
+			`INSERT INTO market_data (exchange_id, trading_pair_id, last_price, volume_24h, timestamp, created_at)
+			 VALUES (?, ?, ?, ?, ?, ?)`,
+			exchangeID, tradingPairID, ticker.Price, ticker.Volume, ticker.Timestamp, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to save market data: %w", err)
+		}
 	}
+	c.freshness.RecordTickers(c.ctx, exchange, []models.MarketPrice{*ticker})
 
 	return nil
 }
@@ -1859,6 +2091,17 @@ func (c *CollectorService) collectFundingRatesBulk(worker *Worker) error {
 		return nil
 	}
 
+	perps := make([]string, 0, len(fundingRates))
+	for _, rate := range fundingRates {
+		perps = append(perps, rate.Symbol)
+	}
+	c.positioningMu.Lock()
+	if c.perpSymbols == nil {
+		c.perpSymbols = make(map[string][]string)
+	}
+	c.perpSymbols[worker.Exchange] = perps
+	c.positioningMu.Unlock()
+
 	// Process funding rates concurrently using worker pool
 	// Get dynamic concurrency limit from resource optimizer
 	optimalConcurrency := c.resourceOptimizer.GetOptimalConcurrency()
@@ -1915,6 +2158,77 @@ func (c *CollectorService) collectFundingRatesBulk(worker *Worker) error {
 	return nil
 }
 
+// collectPositioning collects open interest and long/short account ratios of
+// the perps of the worker's symbols and stores them as a time series.
+func (c *CollectorService) collectPositioning(worker *Worker) error {
+	symbols := c.positioningSymbols(worker)
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(c.ctx, 30*time.Second)
+	defer cancel()
+
+	var positioning []ccxt.PositioningInfo
+	err := c.getExchangeCCXTCircuitBreaker(worker.Exchange).Execute(ctx, func(ctx context.Context) error {
+		var fetchErr error
+		positioning, fetchErr = c.positioningClient.FetchPositioning(ctx, worker.Exchange, symbols, c.config.MarketData.PositioningPeriod)
+		return fetchErr
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch positioning for %s: %w", worker.Exchange, err)
+	}
+
+	snapshots := make([]PositioningSnapshot, 0, len(positioning))
+	for _, info := range positioning {
+		snapshots = append(snapshots, NewPositioningSnapshot(worker.Exchange, info))
+	}
+	if err := c.positioningStore.Save(ctx, snapshots); err != nil {
+		return err
+	}
+
+	c.logger.WithFields(map[string]interface{}{
+		"exchange": worker.Exchange,
+		"count":    len(snapshots),
+	}).Info("Collected derivatives positioning")
+	return nil
+}
+
+// positioningSymbols returns the perps of the worker's spot symbols, capped at
+// the configured maximum.
+func (c *CollectorService) positioningSymbols(worker *Worker) []string {
+	c.positioningMu.RLock()
+	perps := c.perpSymbols[worker.Exchange]
+	c.positioningMu.RUnlock()
+	if len(perps) == 0 {
+		return nil
+	}
+
+	perpBySymbol := make(map[string]string, len(perps))
+	for _, perp := range perps {
+		perpBySymbol[normalizeSymbolForComparison(perp)] = perp
+	}
+	maxSymbols := c.config.MarketData.PositioningMaxSymbols
+	if maxSymbols <= 0 {
+		maxSymbols = 50
+	}
+
+	var symbols []string
+	seen := make(map[string]bool)
+	for _, symbol := range worker.Symbols {
+		perp, ok := perpBySymbol[normalizeSymbolForComparison(symbol)]
+		if !ok || seen[perp] {
+			continue
+		}
+		seen[perp] = true
+		symbols = append(symbols, perp)
+		if len(symbols) >= maxSymbols {
+			break
+		}
+	}
+	return symbols
+}
+
 // storeFundingRate stores funding rate data in the database
 func (c *CollectorService) storeFundingRate(exchange string, rate ccxt.FundingRate) error {
 	// Ensure exchange exists and get its ID
@@ -2723,7 +3037,7 @@ func (c *CollectorService) validateMarketData(ticker *models.MarketPrice, exchan
 
 // checkPriceOutlier checks if price moved more than 50% in 1 minute (potential manipulation)
 func (c *CollectorService) checkPriceOutlier(ticker *models.MarketPrice, exchange, symbol string) error {
-	key := fmt.Sprintf("%s:%s", exchange, symbol)
+	key := cache.PairKey(exchange, symbol)
 
 	var prevPrice decimal.Decimal
 	var prevTime time.Time
@@ -3376,7 +3690,7 @@ func (c *CollectorService) processBackfillJob(job BackfillJob, workerID int, ctx
 	}
 
 	// Check if symbol is blacklisted
-	symbolKey := fmt.Sprintf("%s:%s", job.ExchangeID, job.Symbol)
+	symbolKey := cache.PairKey(job.ExchangeID, job.Symbol)
 	if isBlacklisted, reason := c.blacklistCache.IsBlacklisted(symbolKey); isBlacklisted {
 		c.logger.WithFields(map[string]interface{}{
 			"worker_id": workerID,
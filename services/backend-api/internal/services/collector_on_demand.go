@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/cache"
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/models"
+)
+
+// ErrInvalidCollectionRequest is returned for an on-demand collection
+// without an exchange or with a malformed symbol or candle count.
+var ErrInvalidCollectionRequest = errors.New("invalid collection request")
+
+const (
+	defaultOnDemandCandles = 100
+	maxOnDemandCandles     = 1000
+)
+
+// OnDemandCollectionRequest selects the market collected on demand.
+type OnDemandCollectionRequest struct {
+	Exchange string `json:"exchange"`
+	Symbol   string `json:"symbol"`
+	// Timeframe also fetches recent candles when set, e.g. "1m".
+	Timeframe string `json:"timeframe,omitempty"`
+	// Candles is how many recent candles are fetched (default 100).
+	Candles int `json:"candles,omitempty"`
+}
+
+// OnDemandCollection reports what an on-demand collection fetched.
+type OnDemandCollection struct {
+	Exchange string              `json:"exchange"`
+	Symbol   string              `json:"symbol"`
+	Ticker   *models.MarketPrice `json:"ticker"`
+	// Stored is true when the ticker was written to the database and cache.
+	Stored bool `json:"stored"`
+	// Rejected explains why a fetched ticker was not stored.
+	Rejected  string       `json:"rejected,omitempty"`
+	Timeframe string       `json:"timeframe,omitempty"`
+	Candles   []ccxt.OHLCV `json:"candles,omitempty"`
+	// CandleError is set when the ticker was fetched but the candles were not.
+	CandleError string    `json:"candle_error,omitempty"`
+	FetchedAt   time.Time `json:"fetched_at"`
+	DurationMs  int64     `json:"duration_ms"`
+}
+
+// CollectNow fetches a symbol immediately, outside the worker schedule. It
+// ignores replica partitioning and load shedding and writes the ticker
+// straight to the database and cache instead of the write buffer, so the
+// data is visible as soon as the call returns.
+//
+// Parameters:
+//
+//	ctx: Request context.
+//	req: Exchange, symbol and optional candle timeframe.
+//
+// Returns:
+//
+//	*OnDemandCollection: The fetched ticker, candles and whether the ticker was stored.
+//	error: ErrInvalidCollectionRequest or the ticker fetch error.
+func (c *CollectorService) CollectNow(ctx context.Context, req OnDemandCollectionRequest) (*OnDemandCollection, error) {
+	if req.Exchange == "" {
+		return nil, fmt.Errorf("%w: exchange is required", ErrInvalidCollectionRequest)
+	}
+	if !isValidSymbolFormat(req.Symbol) {
+		return nil, fmt.Errorf("%w: malformed symbol %q", ErrInvalidCollectionRequest, req.Symbol)
+	}
+	if req.Candles < 0 || req.Candles > maxOnDemandCandles {
+		return nil, fmt.Errorf("%w: candles must be between 1 and %d", ErrInvalidCollectionRequest, maxOnDemandCandles)
+	}
+	if req.Candles == 0 {
+		req.Candles = defaultOnDemandCandles
+	}
+
+	started := time.Now()
+	result := &OnDemandCollection{Exchange: req.Exchange, Symbol: req.Symbol, Timeframe: req.Timeframe}
+
+	err := c.getExchangeCCXTCircuitBreaker(req.Exchange).Execute(ctx, func(ctx context.Context) error {
+		resp, fetchErr := c.ccxtService.FetchSingleTicker(ctx, req.Exchange, req.Symbol)
+		if fetchErr != nil {
+			return fetchErr
+		}
+		result.Ticker = c.convertMarketPriceInterfaceToModel(resp)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s %s: %w", req.Exchange, req.Symbol, err)
+	}
+	if result.Ticker == nil {
+		return nil, fmt.Errorf("failed to fetch %s %s: empty ticker", req.Exchange, req.Symbol)
+	}
+	result.Rejected = c.storeOnDemandTicker(ctx, req.Exchange, req.Symbol, *result.Ticker)
+	result.Stored = result.Rejected == ""
+
+	if req.Timeframe != "" {
+		candles, candleErr := c.ccxtService.FetchOHLCV(ctx, req.Exchange, req.Symbol, req.Timeframe, req.Candles)
+		switch {
+		case candleErr != nil:
+			result.CandleError = candleErr.Error()
+		case candles != nil:
+			result.Candles = candles.OHLCV
+			if n := len(candles.OHLCV); n > 0 {
+				c.freshness.RecordCandle(ctx, req.Exchange, req.Symbol, candles.OHLCV[n-1].Timestamp)
+			}
+		}
+	}
+
+	result.FetchedAt = started
+	result.DurationMs = time.Since(started).Milliseconds()
+	c.logger.WithFields(map[string]interface{}{
+		"exchange": req.Exchange,
+		"symbol":   req.Symbol,
+		"stored":   result.Stored,
+		"rejected": result.Rejected,
+		"candles":  len(result.Candles),
+	}).Info("On-demand collection completed")
+	return result, nil
+}
+
+// storeOnDemandTicker writes an on-demand ticker through the same filters as
+// the scheduled collection and returns why it was rejected, if it was.
+func (c *CollectorService) storeOnDemandTicker(ctx context.Context, exchange, symbol string, ticker models.MarketPrice) string {
+	if isBlacklisted, reason := c.blacklistCache.IsBlacklisted(cache.PairKey(exchange, symbol)); isBlacklisted {
+		return "blacklisted: " + reason
+	}
+	if c.tickFilter != nil {
+		if reason := c.tickFilter.Check(ticker); reason != "" {
+			return "anomalous tick: " + reason
+		}
+	}
+	if err := c.validateMarketData(&ticker, exchange, symbol); err != nil {
+		return "invalid market data: " + err.Error()
+	}
+
+	exchangeID, err := c.getOrCreateExchange(exchange)
+	if err != nil {
+		return "store failed: " + err.Error()
+	}
+	tradingPairID, err := c.getOrCreateTradingPair(exchangeID, symbol)
+	if err != nil {
+		return "store failed: " + err.Error()
+	}
+	if _, err := c.db.Exec(ctx,
+		`INSERT INTO market_data (exchange_id, trading_pair_id, last_price, volume_24h, timestamp, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?)`,
+		exchangeID, tradingPairID, ticker.Price, ticker.Volume, ticker.Timestamp, time.Now()); err != nil {
+		return "store failed: " + err.Error()
+	}
+
+	c.freshness.RecordTickers(ctx, exchange, []models.MarketPrice{ticker})
+	if c.redisClient != nil {
+		if tickerJSON, err := json.Marshal(ticker); err == nil {
+			key := fmt.Sprintf("ticker:%s:%s", exchange, symbol)
+			if err := c.redisClient.Set(ctx, key, string(tickerJSON), 10*time.Second).Err(); err != nil {
+				c.logger.WithFields(map[string]interface{}{"key": key}).WithError(err).Warn("Failed to cache on-demand ticker")
+			}
+		}
+	}
+	return ""
+}
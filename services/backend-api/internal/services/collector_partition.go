@@ -0,0 +1,400 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/services/distributedlock"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// collectorMembersKey is the sorted set of live collector replicas, scored
+	// by their last heartbeat in unix milliseconds.
+	collectorMembersKey = "collector:partition:members"
+	// collectorLeasePrefix prefixes the lease key of each partition.
+	collectorLeasePrefix = "collector:partition:lease:"
+)
+
+// CollectorPartitionConfig configures how collection work is split between
+// backend replicas.
+type CollectorPartitionConfig struct {
+	// ReplicaID identifies this replica; it defaults to the hostname and pid.
+	ReplicaID string
+	// Shards is the number of symbol partitions per exchange.
+	Shards int
+	// HeartbeatInterval is how often membership and leases are renewed.
+	HeartbeatInterval time.Duration
+	// LeaseTTL is how long a replica keeps its membership and partitions
+	// without a heartbeat, i.e. how quickly the work of a dead replica moves.
+	LeaseTTL time.Duration
+}
+
+// DefaultCollectorPartitionConfig returns 4 shards per exchange with a 15
+// second lease renewed every 5 seconds.
+func DefaultCollectorPartitionConfig() CollectorPartitionConfig {
+	return CollectorPartitionConfig{
+		Shards:            4,
+		HeartbeatInterval: 5 * time.Second,
+		LeaseTTL:          15 * time.Second,
+	}
+}
+
+// CollectorPartitionStatus describes the partitions this replica collects.
+type CollectorPartitionStatus struct {
+	ReplicaID string   `json:"replica_id"`
+	Members   []string `json:"members"`
+	Shards    int      `json:"shards"`
+	Owned     []string `json:"owned"`
+}
+
+// CollectorPartitioner splits market data collection between backend
+// replicas. Every exchange is cut into shards by symbol hash; each
+// exchange/shard partition is assigned to one live replica by rendezvous
+// hashing and held through a Redis lease. When a replica stops
+// heartbeating its membership and leases expire and the survivors take
+// over its partitions on their next heartbeat.
+type CollectorPartitioner struct {
+	redis  *redis.Client
+	locker *distributedlock.Locker
+	config CollectorPartitionConfig
+	logger *slog.Logger
+	now    func() time.Time
+
+	// rebalanceMu serializes rebalances; mu guards the state they publish.
+	rebalanceMu sync.Mutex
+	mu          sync.RWMutex
+	exchanges   map[string]bool
+	members     []string
+	leases      map[string]*distributedlock.Lock
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewCollectorPartitioner creates a collector partitioner.
+//
+// Parameters:
+//
+//	redisClient: Redis client shared by all replicas.
+//	config: Partition configuration (zero values use the defaults).
+//
+// Returns:
+//
+//	*CollectorPartitioner: Initialized partitioner.
+func NewCollectorPartitioner(redisClient *redis.Client, config CollectorPartitionConfig) *CollectorPartitioner {
+	defaults := DefaultCollectorPartitionConfig()
+	if config.ReplicaID == "" {
+		hostname, err := os.Hostname()
+		if err != nil || hostname == "" {
+			hostname = "collector"
+		}
+		config.ReplicaID = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+	if config.Shards <= 0 {
+		config.Shards = defaults.Shards
+	}
+	if config.LeaseTTL <= 0 {
+		config.LeaseTTL = defaults.LeaseTTL
+	}
+	if config.HeartbeatInterval <= 0 || config.HeartbeatInterval >= config.LeaseTTL {
+		config.HeartbeatInterval = config.LeaseTTL / 3
+	}
+	return &CollectorPartitioner{
+		redis:     redisClient,
+		locker:    distributedlock.NewLocker(redisClient),
+		config:    config,
+		logger:    telemetry.Logger(),
+		now:       time.Now,
+		exchanges: make(map[string]bool),
+		leases:    make(map[string]*distributedlock.Lock),
+	}
+}
+
+// Config returns the partition configuration.
+func (p *CollectorPartitioner) Config() CollectorPartitionConfig {
+	return p.config
+}
+
+// Start joins the replica set, claims the partitions assigned to this
+// replica and keeps heartbeating until ctx is done or Stop is called.
+func (p *CollectorPartitioner) Start(ctx context.Context) {
+	if p == nil {
+		return
+	}
+	if err := p.rebalance(ctx); err != nil {
+		p.logger.Warn("Collector partition rebalance failed", "replica", p.config.ReplicaID, "error", err)
+	}
+
+	loopCtx, cancel := context.WithCancel(ctx)
+	p.mu.Lock()
+	p.cancel = cancel
+	p.done = make(chan struct{})
+	done := p.done
+	p.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(p.config.HeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-loopCtx.Done():
+				return
+			case <-ticker.C:
+				if err := p.rebalance(loopCtx); err != nil && loopCtx.Err() == nil {
+					p.logger.Warn("Collector partition rebalance failed", "replica", p.config.ReplicaID, "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops heartbeating, releases the leases held by this replica and
+// leaves the replica set, so the survivors take over without waiting for
+// the leases to expire.
+func (p *CollectorPartitioner) Stop() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	cancel, done := p.cancel, p.done
+	p.cancel, p.done = nil, nil
+	p.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+
+	p.rebalanceMu.Lock()
+	defer p.rebalanceMu.Unlock()
+	ctx, cancelRelease := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelRelease()
+
+	p.mu.Lock()
+	leases := p.leases
+	p.leases = make(map[string]*distributedlock.Lock)
+	p.mu.Unlock()
+	for partition, lock := range leases {
+		if err := p.locker.Unlock(ctx, lock); err != nil {
+			p.logger.Warn("Failed to release collector partition", "partition", partition, "error", err)
+		}
+	}
+	if err := p.redis.ZRem(ctx, collectorMembersKey, p.config.ReplicaID).Err(); err != nil {
+		p.logger.Warn("Failed to leave collector replica set", "replica", p.config.ReplicaID, "error", err)
+	}
+	p.logger.Info("Collector partitions released", "replica", p.config.ReplicaID, "released", len(leases))
+}
+
+// AddExchange adds an exchange to the partitioned work and claims its
+// partitions right away, so a new worker does not wait for the next
+// heartbeat.
+func (p *CollectorPartitioner) AddExchange(ctx context.Context, exchange string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	known := p.exchanges[exchange]
+	p.exchanges[exchange] = true
+	p.mu.Unlock()
+	if known {
+		return
+	}
+	if err := p.rebalance(ctx); err != nil {
+		p.logger.Warn("Collector partition rebalance failed", "replica", p.config.ReplicaID, "exchange", exchange, "error", err)
+	}
+}
+
+// Owns reports whether this replica collects the symbol on the exchange.
+// Without a partitioner every symbol is collected.
+func (p *CollectorPartitioner) Owns(exchange, symbol string) bool {
+	if p == nil {
+		return true
+	}
+	return p.holds(p.partition(exchange, p.shard(symbol)))
+}
+
+// OwnsExchange reports whether this replica runs the exchange-wide
+// collection (funding rates and positioning). It belongs to the owner of
+// the first shard.
+func (p *CollectorPartitioner) OwnsExchange(exchange string) bool {
+	if p == nil {
+		return true
+	}
+	return p.holds(p.partition(exchange, 0))
+}
+
+// Filter returns the symbols of the exchange this replica collects.
+func (p *CollectorPartitioner) Filter(exchange string, symbols []string) []string {
+	if p == nil {
+		return symbols
+	}
+	var owned []string
+	for _, symbol := range symbols {
+		if p.Owns(exchange, symbol) {
+			owned = append(owned, symbol)
+		}
+	}
+	return owned
+}
+
+// Status returns the live replicas and the partitions this replica holds.
+func (p *CollectorPartitioner) Status() CollectorPartitionStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	owned := make([]string, 0, len(p.leases))
+	for partition := range p.leases {
+		owned = append(owned, partition)
+	}
+	sort.Strings(owned)
+	return CollectorPartitionStatus{
+		ReplicaID: p.config.ReplicaID,
+		Members:   append([]string(nil), p.members...),
+		Shards:    p.config.Shards,
+		Owned:     owned,
+	}
+}
+
+// holds reports whether this replica holds the lease of the partition.
+func (p *CollectorPartitioner) holds(partition string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	_, ok := p.leases[partition]
+	return ok
+}
+
+// rebalance heartbeats, reads the live replicas and claims, renews or
+// releases each partition so this replica holds exactly those assigned to
+// it. When Redis cannot be reached the current partitions are kept:
+// collecting a pair twice is cheaper than not collecting it.
+func (p *CollectorPartitioner) rebalance(ctx context.Context) error {
+	p.rebalanceMu.Lock()
+	defer p.rebalanceMu.Unlock()
+
+	now := p.now()
+	expired := now.Add(-p.config.LeaseTTL).UnixMilli()
+	var membersCmd *redis.StringSliceCmd
+	_, err := p.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, collectorMembersKey, redis.Z{Score: float64(now.UnixMilli()), Member: p.config.ReplicaID})
+		pipe.ZRemRangeByScore(ctx, collectorMembersKey, "-inf", "("+strconv.FormatInt(expired, 10))
+		membersCmd = pipe.ZRange(ctx, collectorMembersKey, 0, -1)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("collector heartbeat: %w", err)
+	}
+	members := membersCmd.Val()
+	sort.Strings(members)
+
+	p.mu.RLock()
+	exchanges := make([]string, 0, len(p.exchanges))
+	for exchange := range p.exchanges {
+		exchanges = append(exchanges, exchange)
+	}
+	leases := make(map[string]*distributedlock.Lock, len(p.leases))
+	for partition, lock := range p.leases {
+		leases[partition] = lock
+	}
+	p.mu.RUnlock()
+	sort.Strings(exchanges)
+
+	lockOpts := distributedlock.DefaultLockOptions()
+	lockOpts.TTL = p.config.LeaseTTL
+	var acquired, released []string
+	for _, exchange := range exchanges {
+		for shard := 0; shard < p.config.Shards; shard++ {
+			partition := p.partition(exchange, shard)
+			lock, held := leases[partition]
+			assigned := rendezvousOwner(members, partition) == p.config.ReplicaID
+			if assigned && held {
+				if err := p.locker.Extend(ctx, lock, p.config.LeaseTTL); err == nil {
+					continue
+				}
+				// Expired, e.g. after a long pause; claim it again below unless
+				// another replica took it over
+				_ = p.locker.Unlock(ctx, lock)
+				delete(leases, partition)
+				held = false
+			}
+			switch {
+			case assigned:
+				// Fails until the previous owner releases or its lease expires
+				if lock, err := p.locker.TryLock(ctx, collectorLeasePrefix+partition, lockOpts); err == nil {
+					leases[partition] = lock
+					acquired = append(acquired, partition)
+				} else if _, wasHeld := p.leases[partition]; wasHeld {
+					released = append(released, partition)
+				}
+			case held:
+				if err := p.locker.Unlock(ctx, lock); err != nil {
+					p.logger.Warn("Failed to release collector partition", "partition", partition, "error", err)
+				}
+				delete(leases, partition)
+				released = append(released, partition)
+			}
+		}
+	}
+
+	p.mu.Lock()
+	p.members = members
+	p.leases = leases
+	p.mu.Unlock()
+	if len(acquired) > 0 || len(released) > 0 {
+		p.logger.Info("Collector partitions rebalanced",
+			"replica", p.config.ReplicaID,
+			"members", len(members),
+			"acquired", acquired,
+			"released", released,
+			"owned", len(leases))
+	}
+	return nil
+}
+
+// partition returns the partition name of an exchange shard.
+func (p *CollectorPartitioner) partition(exchange string, shard int) string {
+	return exchange + "/" + strconv.Itoa(shard)
+}
+
+// shard returns the shard of a symbol.
+func (p *CollectorPartitioner) shard(symbol string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(symbol))
+	return int(h.Sum32() % uint32(p.config.Shards))
+}
+
+// rendezvousOwner picks the member with the highest hash for the partition,
+// so a membership change only moves the partitions of the member that
+// joined or left.
+func rendezvousOwner(members []string, partition string) string {
+	var owner string
+	var best uint64
+	for _, member := range members {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(member))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(partition))
+		if score := mix64(h.Sum64()); owner == "" || score > best {
+			owner, best = member, score
+		}
+	}
+	return owner
+}
+
+// mix64 spreads the bits of an FNV hash (the splitmix64 finalizer); FNV
+// alone barely separates names that differ in one character.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPartitioner(t *testing.T, mr *miniredis.Miniredis, replicaID string, now *time.Time) *CollectorPartitioner {
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	partitioner := NewCollectorPartitioner(client, CollectorPartitionConfig{ReplicaID: replicaID, Shards: 8, LeaseTTL: 15 * time.Second})
+	partitioner.now = func() time.Time { return *now }
+	return partitioner
+}
+
+func assertPartitioned(t *testing.T, symbols []string, partitioners ...*CollectorPartitioner) {
+	t.Helper()
+	owners := make(map[string]int)
+	exchangeOwners := 0
+	for _, partitioner := range partitioners {
+		for _, symbol := range partitioner.Filter("binance", symbols) {
+			owners[symbol]++
+		}
+		if partitioner.OwnsExchange("binance") {
+			exchangeOwners++
+		}
+	}
+	assert.Len(t, owners, len(symbols), "every symbol is collected")
+	for symbol, count := range owners {
+		assert.Equal(t, 1, count, "%s is collected by exactly one replica", symbol)
+	}
+	assert.Equal(t, 1, exchangeOwners)
+}
+
+func TestCollectorPartitioner_SplitAndRebalance(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	symbols := make([]string, 40)
+	for i := range symbols {
+		symbols[i] = fmt.Sprintf("COIN%d/USDT", i)
+	}
+
+	a := newTestPartitioner(t, mr, "replica-a", &now)
+	a.AddExchange(ctx, "binance")
+	assert.Equal(t, symbols, a.Filter("binance", symbols), "a lone replica collects everything")
+	assert.Len(t, a.Status().Owned, 8)
+
+	// A second replica gets its partitions once the first one releases them
+	b := newTestPartitioner(t, mr, "replica-b", &now)
+	b.AddExchange(ctx, "binance")
+	require.NoError(t, a.rebalance(ctx))
+	require.NoError(t, b.rebalance(ctx))
+	assert.Equal(t, []string{"replica-a", "replica-b"}, a.Status().Members)
+	assertPartitioned(t, symbols, a, b)
+	assert.NotEmpty(t, b.Status().Owned)
+	assert.Less(t, len(a.Status().Owned), 8)
+
+	// b dies without releasing: its membership and leases expire
+	now = now.Add(20 * time.Second)
+	mr.FastForward(20 * time.Second)
+	require.NoError(t, a.rebalance(ctx))
+	assert.Equal(t, []string{"replica-a"}, a.Status().Members)
+	assert.Equal(t, symbols, a.Filter("binance", symbols))
+	assert.True(t, a.OwnsExchange("binance"))
+}
+
+func TestCollectorPartitioner_StopHandsOver(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	symbols := []string{"BTC/USDT", "ETH/USDT", "SOL/USDT", "XRP/USDT", "ADA/USDT", "DOGE/USDT"}
+
+	a := newTestPartitioner(t, mr, "replica-a", &now)
+	b := newTestPartitioner(t, mr, "replica-b", &now)
+	a.Start(ctx)
+	b.Start(ctx)
+	a.AddExchange(ctx, "binance")
+	b.AddExchange(ctx, "binance")
+	require.NoError(t, a.rebalance(ctx))
+	require.NoError(t, b.rebalance(ctx))
+	assertPartitioned(t, symbols, a, b)
+
+	// A clean stop hands the partitions over without waiting for the lease TTL
+	a.Stop()
+	assert.Empty(t, a.Status().Owned)
+	require.NoError(t, b.rebalance(ctx))
+	assert.Equal(t, []string{"replica-b"}, b.Status().Members)
+	assert.Equal(t, symbols, b.Filter("binance", symbols))
+	b.Stop()
+
+	var none *CollectorPartitioner
+	assert.Equal(t, symbols, none.Filter("binance", symbols))
+	assert.True(t, none.OwnsExchange("binance"))
+}
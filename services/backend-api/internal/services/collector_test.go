@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"os"
@@ -23,6 +24,7 @@ import (
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMain(m *testing.M) {
@@ -65,6 +67,27 @@ func TestMain(m *testing.M) {
 	os.Exit(code)
 }
 
+func TestCollectorService_withoutAnomalousTicks(t *testing.T) {
+	collector := NewCollectorService(nil, &testmocks.MockCCXTService{}, &config.Config{}, nil, cache.NewInMemoryBlacklistCache())
+	defer collector.Stop()
+
+	var ticks []models.MarketPrice
+	for _, price := range []float64{100, 100.5, 99.5, 100.2, 99.8, 250, 100.1} {
+		ticks = append(ticks, models.MarketPrice{
+			ExchangeName: "binance",
+			Symbol:       "BTC/USDT",
+			Price:        decimal.NewFromFloat(price),
+			Timestamp:    time.Now(),
+		})
+	}
+
+	accepted := collector.withoutAnomalousTicks(ticks)
+	assert.Len(t, accepted, 6)
+	quarantined := collector.GetQuarantinedTicks()
+	require.Len(t, quarantined, 1)
+	assert.True(t, quarantined[0].Price.Equal(decimal.NewFromInt(250)))
+}
+
 func TestNewCollectorService(t *testing.T) {
 	mockCCXT := &testmocks.MockCCXTService{}
 	config := &config.Config{}
@@ -1783,3 +1806,41 @@ func TestCollectorService_CheckVolumeAnomaly(t *testing.T) {
 		})
 	}
 }
+
+func TestCollectorService_CollectNow(t *testing.T) {
+	mockCCXT := &testmocks.MockCCXTService{}
+	blacklistCache := cache.NewInMemoryBlacklistCache()
+	collector := NewCollectorService(nil, mockCCXT, &config.Config{}, nil, blacklistCache)
+	defer collector.Stop()
+	ctx := context.Background()
+
+	_, err := collector.CollectNow(ctx, OnDemandCollectionRequest{Symbol: "BTC/USDT"})
+	assert.ErrorIs(t, err, ErrInvalidCollectionRequest)
+	_, err = collector.CollectNow(ctx, OnDemandCollectionRequest{Exchange: "binance", Symbol: "BTC/"})
+	assert.ErrorIs(t, err, ErrInvalidCollectionRequest)
+	_, err = collector.CollectNow(ctx, OnDemandCollectionRequest{Exchange: "binance", Symbol: "BTC/USDT", Candles: 5000})
+	assert.ErrorIs(t, err, ErrInvalidCollectionRequest)
+
+	// A blacklisted pair is still fetched for inspection but not stored
+	blacklistCache.Add("binance:ETH/USDT", "invalid_data", time.Hour)
+	mockCCXT.On("FetchSingleTicker", mock.Anything, "binance", "ETH/USDT").Return(&models.MarketPrice{
+		ExchangeName: "binance",
+		Symbol:       "ETH/USDT",
+		Price:        decimal.NewFromFloat(3000),
+		Timestamp:    time.Now(),
+	}, nil)
+	mockCCXT.On("FetchOHLCV", mock.Anything, "binance", "ETH/USDT", "1m", 100).Return((*ccxt.OHLCVResponse)(nil), errors.New("timeframe not supported"))
+
+	result, err := collector.CollectNow(ctx, OnDemandCollectionRequest{Exchange: "binance", Symbol: "ETH/USDT", Timeframe: "1m"})
+	require.NoError(t, err)
+	require.NotNil(t, result.Ticker)
+	assert.True(t, result.Ticker.Price.Equal(decimal.NewFromInt(3000)))
+	assert.False(t, result.Stored)
+	assert.Equal(t, "blacklisted: invalid_data", result.Rejected)
+	assert.Equal(t, "timeframe not supported", result.CandleError)
+	mockCCXT.AssertExpectations(t)
+
+	mockCCXT.On("FetchSingleTicker", mock.Anything, "binance", "SOL/USDT").Return(nil, errors.New("exchange unavailable"))
+	_, err = collector.CollectNow(ctx, OnDemandCollectionRequest{Exchange: "binance", Symbol: "SOL/USDT"})
+	assert.ErrorContains(t, err, "exchange unavailable")
+}
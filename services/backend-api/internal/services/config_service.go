@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/irfndi/neuratrade/internal/config"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+)
+
+// redactedConfigValue replaces secrets in the effective configuration.
+const redactedConfigValue = "[redacted]"
+
+// configSecretSuffixes end the keys whose values are never exposed.
+var configSecretSuffixes = []string{"password", "secret", "api_key", "encryption_key", "token", "dsn", "database_url", "replica_url"}
+
+// ExecutionModeSource reports the account-wide execution mode.
+// TradingModeService satisfies this interface.
+type ExecutionModeSource interface {
+	GetState(ctx context.Context) (*TradingModeState, error)
+}
+
+// EffectiveConfig is the configuration of one mode with its overlay applied.
+type EffectiveConfig struct {
+	Mode string `json:"mode"`
+	// Active is true when Mode is the mode the backend is running in.
+	Active bool `json:"active"`
+	// Overrides lists the dotted keys the mode's overlay sets.
+	Overrides []string `json:"overrides"`
+	// Settings are keyed like the config file; secrets are redacted.
+	Settings map[string]interface{} `json:"settings"`
+}
+
+// ConfigService resolves the per-mode configuration overlays at runtime.
+type ConfigService struct {
+	base   *config.Config
+	modes  ExecutionModeSource
+	logger *slog.Logger
+
+	mu       sync.Mutex
+	resolved map[string]*config.Config
+}
+
+// NewConfigService creates a config service.
+//
+// Parameters:
+//
+//	base: The loaded configuration with its mode overlays.
+//
+// Returns:
+//
+//	*ConfigService: Initialized service.
+func NewConfigService(base *config.Config) *ConfigService {
+	return &ConfigService{
+		base:     base,
+		logger:   telemetry.Logger(),
+		resolved: make(map[string]*config.Config),
+	}
+}
+
+// SetModeProvider makes the active mode follow the paper/live execution mode.
+func (s *ConfigService) SetModeProvider(modes ExecutionModeSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.modes = modes
+}
+
+// ActiveMode returns the mode the backend is running in: dry when market data
+// is synthetic or replayed from fixtures, otherwise the execution mode, which
+// is paper until a mode provider is set.
+func (s *ConfigService) ActiveMode(ctx context.Context) string {
+	if s.base.CCXT.Synthetic.Enabled || s.base.CCXT.FixtureMode != "" {
+		return config.ModeDry
+	}
+	s.mu.Lock()
+	modes := s.modes
+	s.mu.Unlock()
+	if modes == nil {
+		return config.ModePaper
+	}
+	state, err := modes.GetState(ctx)
+	if err != nil {
+		// Paper settings could loosen the caps of a live account, so an
+		// unknown mode resolves to live
+		s.logger.Warn("Failed to load execution mode, resolving live configuration", "error", err)
+		return config.ModeLive
+	}
+	if state.Mode == ExecutionModeLive {
+		return config.ModeLive
+	}
+	return config.ModePaper
+}
+
+// Current returns the configuration of the active mode. Overlays are
+// validated at load, so this falls back to the base configuration only if
+// one cannot be resolved.
+func (s *ConfigService) Current(ctx context.Context) *config.Config {
+	mode := s.ActiveMode(ctx)
+	resolved, err := s.ForMode(mode)
+	if err != nil {
+		s.logger.Error("Failed to resolve mode configuration", "mode", mode, "error", err)
+		return s.base
+	}
+	return resolved
+}
+
+// ForMode returns the configuration of a mode, resolved once and cached.
+//
+// Parameters:
+//
+//	mode: dry, paper or live.
+//
+// Returns:
+//
+//	*config.Config: The effective configuration.
+//	error: config.ErrUnknownMode or an overlay error.
+func (s *ConfigService) ForMode(mode string) (*config.Config, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if resolved, ok := s.resolved[mode]; ok {
+		return resolved, nil
+	}
+	resolved, err := s.base.ForMode(mode)
+	if err != nil {
+		return nil, err
+	}
+	s.resolved[mode] = resolved
+	return resolved, nil
+}
+
+// Effective returns the inspectable configuration of a mode.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	mode: dry, paper or live; empty for the active mode.
+//
+// Returns:
+//
+//	*EffectiveConfig: Settings with secrets redacted and the overlay's keys.
+//	error: config.ErrUnknownMode.
+func (s *ConfigService) Effective(ctx context.Context, mode string) (*EffectiveConfig, error) {
+	active := s.ActiveMode(ctx)
+	if mode == "" {
+		mode = active
+	}
+	settings, overrides, err := s.base.ModeSettings(mode)
+	if err != nil {
+		return nil, err
+	}
+	redactConfigSettings(settings)
+	return &EffectiveConfig{
+		Mode:      mode,
+		Active:    mode == active,
+		Overrides: overrides,
+		Settings:  settings,
+	}, nil
+}
+
+// redactConfigSettings replaces non-empty secret values in place.
+func redactConfigSettings(settings map[string]interface{}) {
+	for key, value := range settings {
+		if nested, ok := value.(map[string]interface{}); ok {
+			redactConfigSettings(nested)
+			continue
+		}
+		if !isConfigSecret(key) {
+			continue
+		}
+		if str, ok := value.(string); ok && str == "" {
+			continue
+		}
+		settings[key] = redactedConfigValue
+	}
+}
+
+func isConfigSecret(key string) bool {
+	key = strings.ToLower(key)
+	for _, suffix := range configSecretSuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/irfndi/neuratrade/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubExecutionModes struct {
+	mode ExecutionMode
+	err  error
+}
+
+func (s *stubExecutionModes) GetState(ctx context.Context) (*TradingModeState, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &TradingModeState{Mode: s.mode}, nil
+}
+
+func loadModeConfig(t *testing.T, content string) *config.Config {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".neuratrade"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".neuratrade", "config.json"), []byte(content), 0644))
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	return cfg
+}
+
+func TestConfigService_FollowsExecutionMode(t *testing.T) {
+	cfg := loadModeConfig(t, `{
+		"arbitrage": {"max_trade_amount": 1000},
+		"telegram": {"bot_token": "123:abc"},
+		"modes": {"live": {"arbitrage": {"max_trade_amount": 250}}}
+	}`)
+	svc := NewConfigService(cfg)
+
+	assert.Equal(t, config.ModePaper, svc.ActiveMode(t.Context()), "paper until a mode provider is set")
+	assert.Equal(t, 1000.0, svc.Current(t.Context()).Arbitrage.MaxTradeAmount)
+
+	modes := &stubExecutionModes{mode: ExecutionModeLive}
+	svc.SetModeProvider(modes)
+	assert.Equal(t, 250.0, svc.Current(t.Context()).Arbitrage.MaxTradeAmount)
+
+	modes.err = errors.New("redis down")
+	assert.Equal(t, config.ModeLive, svc.ActiveMode(t.Context()), "an unknown mode resolves to the live overlay")
+
+	modes.err = nil
+	modes.mode = ExecutionModePaper
+	assert.Equal(t, 1000.0, svc.Current(t.Context()).Arbitrage.MaxTradeAmount)
+}
+
+func TestConfigService_Effective(t *testing.T) {
+	cfg := loadModeConfig(t, `{
+		"arbitrage": {"max_trade_amount": 1000},
+		"telegram": {"bot_token": "123:abc"},
+		"ai": {"max_tokens": 4096},
+		"modes": {"live": {"arbitrage": {"max_trade_amount": 250}}}
+	}`)
+	svc := NewConfigService(cfg)
+	svc.SetModeProvider(&stubExecutionModes{mode: ExecutionModePaper})
+
+	effective, err := svc.Effective(t.Context(), config.ModeLive)
+	require.NoError(t, err)
+	assert.Equal(t, config.ModeLive, effective.Mode)
+	assert.False(t, effective.Active)
+	assert.Equal(t, []string{"arbitrage.max_trade_amount"}, effective.Overrides)
+	arbitrage := effective.Settings["arbitrage"].(map[string]interface{})
+	assert.EqualValues(t, 250, arbitrage["max_trade_amount"])
+	telegram := effective.Settings["telegram"].(map[string]interface{})
+	assert.Equal(t, redactedConfigValue, telegram["bot_token"])
+	ai := effective.Settings["ai"].(map[string]interface{})
+	assert.EqualValues(t, 4096, ai["max_tokens"], "only keys ending in a secret name are redacted")
+
+	effective, err = svc.Effective(t.Context(), "")
+	require.NoError(t, err)
+	assert.Equal(t, config.ModePaper, effective.Mode)
+	assert.True(t, effective.Active)
+	assert.Empty(t, effective.Overrides)
+
+	_, err = svc.Effective(t.Context(), "staging")
+	assert.ErrorIs(t, err, config.ErrUnknownMode)
+}
@@ -0,0 +1,315 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	lossStreakStateKey = "risk:loss_streak:chats"
+
+	defaultLossStreakMaxLosses     = 3
+	defaultLossStreakSizeReduction = 0.5
+	defaultLossStreakCooldown      = time.Hour
+	lossStreakTriggerHistory       = 10
+)
+
+// LossStreakAction is how a strategy is throttled after a losing streak.
+type LossStreakAction string
+
+const (
+	// LossStreakReduce cuts the strategy's position size for the cool-down window.
+	LossStreakReduce LossStreakAction = "reduce"
+	// LossStreakPause stops the strategy from opening positions for the cool-down window.
+	LossStreakPause LossStreakAction = "pause"
+)
+
+// StrategyThrottle reports how a chat's strategy is throttled after losses.
+type StrategyThrottle interface {
+	StrategyThrottle(ctx context.Context, chatID, strategy string) (multiplier float64, paused bool)
+}
+
+// ConsecutiveLossPolicyConfig configures the consecutive-loss rule.
+type ConsecutiveLossPolicyConfig struct {
+	// MaxLosses is the number of consecutive losing trades that triggers the rule.
+	MaxLosses int
+	// Action is reduce or pause.
+	Action LossStreakAction
+	// SizeReduction is the share of the position size removed by reduce,
+	// e.g. 0.5 halves it. Repeated triggers compound.
+	SizeReduction float64
+	// Cooldown is how long the throttle lasts after the latest trigger.
+	Cooldown time.Duration
+}
+
+// LossStreakTrigger records one time the rule fired.
+type LossStreakTrigger struct {
+	Strategy   string           `json:"strategy"`
+	Losses     int              `json:"losses"`
+	Action     LossStreakAction `json:"action"`
+	Multiplier float64          `json:"multiplier"`
+	Until      time.Time        `json:"until"`
+	At         time.Time        `json:"at"`
+}
+
+// StrategyLossStreak is one strategy's losing streak and active throttle.
+type StrategyLossStreak struct {
+	ConsecutiveLosses int              `json:"consecutive_losses"`
+	Action            LossStreakAction `json:"action,omitempty"`
+	// Multiplier scales position sizes while the throttle is active.
+	Multiplier     float64    `json:"multiplier"`
+	ThrottledUntil *time.Time `json:"throttled_until,omitempty"`
+}
+
+// LossStreakState is a chat's losing streaks per strategy.
+type LossStreakState struct {
+	ChatID     string                         `json:"chat_id"`
+	Strategies map[string]*StrategyLossStreak `json:"strategies"`
+	// Triggers are the most recent times the rule fired, newest last.
+	Triggers  []LossStreakTrigger `json:"triggers"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// ConsecutiveLossPolicy is the risk rule that throttles a strategy after N
+// consecutive losing trades, either by reducing its position size or by
+// pausing it for a cool-down window. Each trigger is emitted as a risk event.
+type ConsecutiveLossPolicy struct {
+	redis  *redis.Client
+	config ConsecutiveLossPolicyConfig
+	events EventEmitter
+	logger *slog.Logger
+	now    func() time.Time
+	// mu serializes read-modify-write updates of chat state.
+	mu sync.Mutex
+}
+
+// Ensure ConsecutiveLossPolicy implements StrategyThrottle.
+var _ StrategyThrottle = (*ConsecutiveLossPolicy)(nil)
+
+// NewConsecutiveLossPolicy creates the consecutive-loss throttling rule.
+//
+// Parameters:
+//
+//	client: Redis client used to persist streaks and throttles.
+//	config: Rule configuration; zero values use defaults.
+//
+// Returns:
+//
+//	*ConsecutiveLossPolicy: Initialized policy.
+func NewConsecutiveLossPolicy(client *redis.Client, config ConsecutiveLossPolicyConfig) *ConsecutiveLossPolicy {
+	if config.MaxLosses <= 0 {
+		config.MaxLosses = defaultLossStreakMaxLosses
+	}
+	if config.Action != LossStreakPause {
+		config.Action = LossStreakReduce
+	}
+	if config.SizeReduction <= 0 || config.SizeReduction >= 1 {
+		config.SizeReduction = defaultLossStreakSizeReduction
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = defaultLossStreakCooldown
+	}
+	return &ConsecutiveLossPolicy{
+		redis:  client,
+		config: config,
+		logger: telemetry.Logger(),
+		now:    time.Now,
+	}
+}
+
+// Config returns the rule configuration.
+func (p *ConsecutiveLossPolicy) Config() ConsecutiveLossPolicyConfig {
+	return p.config
+}
+
+// SetEventEmitter publishes triggers as risk events.
+func (p *ConsecutiveLossPolicy) SetEventEmitter(events EventEmitter) {
+	p.events = events
+}
+
+// RecordTrade adds a closed trade's result to the strategy's streak. A win
+// resets the streak; the Nth consecutive loss triggers the throttle.
+//
+// Parameters:
+//
+//	ctx: Context for the update.
+//	chatID: The chat that traded.
+//	strategy: The strategy that opened the trade.
+//	pnl: The trade's realized profit or loss.
+//
+// Returns:
+//
+//	*LossStreakState: The chat's updated state.
+//	error: Error if the state could not be read or saved.
+func (p *ConsecutiveLossPolicy) RecordTrade(ctx context.Context, chatID, strategy string, pnl decimal.Decimal) (*LossStreakState, error) {
+	strategy = strings.ToLower(strings.TrimSpace(strategy))
+	if chatID == "" || strategy == "" {
+		return nil, fmt.Errorf("chat_id and strategy are required")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	state, err := p.load(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	now := p.now().UTC()
+	streak := state.streak(strategy)
+	p.expire(streak, now)
+
+	var trigger *LossStreakTrigger
+	switch {
+	case pnl.IsNegative():
+		streak.ConsecutiveLosses++
+		if streak.ConsecutiveLosses >= p.config.MaxLosses {
+			trigger = p.trigger(strategy, streak, now)
+			state.Triggers = append(state.Triggers, *trigger)
+			if len(state.Triggers) > lossStreakTriggerHistory {
+				state.Triggers = state.Triggers[len(state.Triggers)-lossStreakTriggerHistory:]
+			}
+		}
+	case pnl.IsPositive():
+		streak.ConsecutiveLosses = 0
+	}
+	state.UpdatedAt = now
+
+	if err := p.save(ctx, state); err != nil {
+		return nil, err
+	}
+	if trigger != nil {
+		p.emit(ctx, chatID, *trigger)
+	}
+	return state, nil
+}
+
+// trigger throttles the strategy and restarts its streak count.
+func (p *ConsecutiveLossPolicy) trigger(strategy string, streak *StrategyLossStreak, now time.Time) *LossStreakTrigger {
+	losses := streak.ConsecutiveLosses
+	until := now.Add(p.config.Cooldown)
+	streak.ConsecutiveLosses = 0
+	streak.Action = p.config.Action
+	streak.ThrottledUntil = &until
+	if p.config.Action == LossStreakPause {
+		streak.Multiplier = 0
+	} else {
+		streak.Multiplier *= 1 - p.config.SizeReduction
+	}
+	return &LossStreakTrigger{
+		Strategy:   strategy,
+		Losses:     losses,
+		Action:     streak.Action,
+		Multiplier: streak.Multiplier,
+		Until:      until,
+		At:         now,
+	}
+}
+
+// expire lifts a throttle whose cool-down window has passed.
+func (p *ConsecutiveLossPolicy) expire(streak *StrategyLossStreak, now time.Time) {
+	if streak.ThrottledUntil != nil && !now.Before(*streak.ThrottledUntil) {
+		streak.ThrottledUntil = nil
+		streak.Action = ""
+		streak.Multiplier = 1
+	}
+}
+
+// StrategyThrottle returns the position size multiplier of the chat's
+// strategy, and whether the strategy is paused. Errors are logged and leave
+// the strategy unthrottled.
+func (p *ConsecutiveLossPolicy) StrategyThrottle(ctx context.Context, chatID, strategy string) (float64, bool) {
+	state, err := p.Status(ctx, chatID)
+	if err != nil {
+		p.logger.Warn("Failed to load loss streaks", "chat_id", chatID, "error", err)
+		return 1, false
+	}
+	streak, ok := state.Strategies[strategy]
+	if !ok || streak.ThrottledUntil == nil {
+		return 1, false
+	}
+	return streak.Multiplier, streak.Action == LossStreakPause
+}
+
+// Status returns the chat's streaks with expired throttles lifted.
+func (p *ConsecutiveLossPolicy) Status(ctx context.Context, chatID string) (*LossStreakState, error) {
+	state, err := p.load(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	now := p.now().UTC()
+	for _, streak := range state.Strategies {
+		p.expire(streak, now)
+	}
+	return state, nil
+}
+
+// Reset clears the chat's streaks and lifts its throttles.
+func (p *ConsecutiveLossPolicy) Reset(ctx context.Context, chatID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.redis.HDel(ctx, lossStreakStateKey, chatID).Err()
+}
+
+func (p *ConsecutiveLossPolicy) emit(ctx context.Context, chatID string, trigger LossStreakTrigger) {
+	p.logger.Warn("Consecutive loss limit reached, throttling strategy",
+		"chat_id", chatID, "strategy", trigger.Strategy, "losses", trigger.Losses, "action", trigger.Action)
+	if p.events == nil {
+		return
+	}
+	p.events.Emit(ctx, WebhookEventRisk, map[string]interface{}{
+		"type":       "consecutive_losses",
+		"chat_id":    chatID,
+		"strategy":   trigger.Strategy,
+		"losses":     trigger.Losses,
+		"action":     string(trigger.Action),
+		"multiplier": trigger.Multiplier,
+		"until":      trigger.Until,
+	})
+}
+
+func (s *LossStreakState) streak(strategy string) *StrategyLossStreak {
+	streak, ok := s.Strategies[strategy]
+	if !ok {
+		streak = &StrategyLossStreak{Multiplier: 1}
+		s.Strategies[strategy] = streak
+	}
+	return streak
+}
+
+func (p *ConsecutiveLossPolicy) load(ctx context.Context, chatID string) (*LossStreakState, error) {
+	state := &LossStreakState{ChatID: chatID, Strategies: map[string]*StrategyLossStreak{}, Triggers: []LossStreakTrigger{}}
+	raw, err := p.redis.HGet(ctx, lossStreakStateKey, chatID).Result()
+	if err == redis.Nil {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load loss streaks: %w", err)
+	}
+	if err := json.Unmarshal([]byte(raw), state); err != nil {
+		return nil, fmt.Errorf("failed to decode loss streaks: %w", err)
+	}
+	if state.Strategies == nil {
+		state.Strategies = map[string]*StrategyLossStreak{}
+	}
+	return state, nil
+}
+
+func (p *ConsecutiveLossPolicy) save(ctx context.Context, state *LossStreakState) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if err := p.redis.HSet(ctx, lossStreakStateKey, state.ChatID, raw).Err(); err != nil {
+		return fmt.Errorf("failed to save loss streaks: %w", err)
+	}
+	return nil
+}
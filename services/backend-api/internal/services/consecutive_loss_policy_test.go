@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConsecutiveLossPolicy(t *testing.T, config ConsecutiveLossPolicyConfig) (*ConsecutiveLossPolicy, *time.Time) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	policy := NewConsecutiveLossPolicy(client, config)
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	policy.now = func() time.Time { return now }
+	return policy, &now
+}
+
+func TestConsecutiveLossPolicy_Reduce(t *testing.T) {
+	policy, now := newTestConsecutiveLossPolicy(t, ConsecutiveLossPolicyConfig{MaxLosses: 2, SizeReduction: 0.4, Cooldown: time.Hour})
+	events := &hookEventCapture{}
+	policy.SetEventEmitter(events)
+	ctx := t.Context()
+	loss := decimal.NewFromInt(-5)
+
+	_, err := policy.RecordTrade(ctx, "42", StrategyScalping, loss)
+	require.NoError(t, err)
+	// A win in between resets the streak
+	_, err = policy.RecordTrade(ctx, "42", StrategyScalping, decimal.NewFromInt(3))
+	require.NoError(t, err)
+	_, err = policy.RecordTrade(ctx, "42", StrategyScalping, loss)
+	require.NoError(t, err)
+	multiplier, paused := policy.StrategyThrottle(ctx, "42", StrategyScalping)
+	assert.Equal(t, 1.0, multiplier)
+	assert.False(t, paused)
+
+	state, err := policy.RecordTrade(ctx, "42", StrategyScalping, loss)
+	require.NoError(t, err)
+	require.Len(t, state.Triggers, 1)
+	assert.Equal(t, 2, state.Triggers[0].Losses)
+	assert.Equal(t, LossStreakReduce, state.Triggers[0].Action)
+	require.Len(t, events.events, 1)
+	assert.Equal(t, WebhookEventRisk, events.events[0].event)
+	assert.Equal(t, "consecutive_losses", events.events[0].data.(map[string]interface{})["type"])
+
+	multiplier, paused = policy.StrategyThrottle(ctx, "42", StrategyScalping)
+	assert.InDelta(t, 0.6, multiplier, 1e-9)
+	assert.False(t, paused)
+	// Other strategies and chats are unaffected
+	multiplier, _ = policy.StrategyThrottle(ctx, "42", StrategyArbitrage)
+	assert.Equal(t, 1.0, multiplier)
+	multiplier, _ = policy.StrategyThrottle(ctx, "7", StrategyScalping)
+	assert.Equal(t, 1.0, multiplier)
+
+	// Another streak during the cool-down compounds the reduction
+	_, err = policy.RecordTrade(ctx, "42", StrategyScalping, loss)
+	require.NoError(t, err)
+	_, err = policy.RecordTrade(ctx, "42", StrategyScalping, loss)
+	require.NoError(t, err)
+	multiplier, _ = policy.StrategyThrottle(ctx, "42", StrategyScalping)
+	assert.InDelta(t, 0.36, multiplier, 1e-9)
+
+	*now = now.Add(time.Hour)
+	multiplier, _ = policy.StrategyThrottle(ctx, "42", StrategyScalping)
+	assert.Equal(t, 1.0, multiplier)
+	state, err = policy.Status(ctx, "42")
+	require.NoError(t, err)
+	assert.Nil(t, state.Strategies[StrategyScalping].ThrottledUntil)
+	assert.Len(t, state.Triggers, 2)
+}
+
+func TestConsecutiveLossPolicy_PauseAndReset(t *testing.T) {
+	policy, now := newTestConsecutiveLossPolicy(t, ConsecutiveLossPolicyConfig{Action: LossStreakPause, Cooldown: 30 * time.Minute})
+	ctx := t.Context()
+
+	for i := 0; i < 3; i++ {
+		_, err := policy.RecordTrade(ctx, "42", "Arbitrage", decimal.NewFromInt(-1))
+		require.NoError(t, err)
+	}
+	multiplier, paused := policy.StrategyThrottle(ctx, "42", StrategyArbitrage)
+	assert.True(t, paused)
+	assert.Zero(t, multiplier)
+
+	*now = now.Add(10 * time.Minute)
+	_, paused = policy.StrategyThrottle(ctx, "42", StrategyArbitrage)
+	assert.True(t, paused)
+
+	require.NoError(t, policy.Reset(ctx, "42"))
+	_, paused = policy.StrategyThrottle(ctx, "42", StrategyArbitrage)
+	assert.False(t, paused)
+
+	_, err := policy.RecordTrade(ctx, "42", "", decimal.NewFromInt(-1))
+	assert.Error(t, err)
+}
+
+type lossStreakThrottle struct {
+	multiplier float64
+	paused     bool
+}
+
+func (l lossStreakThrottle) StrategyThrottle(context.Context, string, string) (float64, bool) {
+	return l.multiplier, l.paused
+}
+
+func TestIntegratedQuestHandlers_LossStreakThrottle(t *testing.T) {
+	executor := &recordingOrderExecutor{}
+	handlers := NewIntegratedQuestHandlers(nil, &totalBalanceFetcher{total: map[string]float64{"USDT": 2000}}, nil, nil, nil, nil)
+	handlers.SetOrderExecutor(executor)
+	newQuest := func() *Quest {
+		return &Quest{
+			Name:     "arbitrage",
+			Metadata: map[string]string{"chat_id": "42"},
+			Checkpoint: map[string]interface{}{
+				"symbol":        "ETH/USDT",
+				"buy_exchange":  "binance",
+				"sell_exchange": "okx",
+				"buy_price":     "100",
+				"sell_price":    "101",
+				"profit_pct":    "1",
+			},
+		}
+	}
+
+	// Half size after a losing streak: the default 10 becomes 5
+	handlers.SetStrategyThrottle(lossStreakThrottle{multiplier: 0.5})
+	quest := newQuest()
+	require.NoError(t, handlers.handleArbitrageExecution(t.Context(), quest))
+	require.Len(t, executor.amounts, 2)
+	assert.True(t, executor.amounts[0].Equal(decimal.NewFromInt(5)), executor.amounts[0].String())
+	assert.Equal(t, 0.5, quest.Checkpoint["loss_streak_multiplier"])
+
+	handlers.SetStrategyThrottle(lossStreakThrottle{paused: true})
+	quest = newQuest()
+	require.NoError(t, handlers.handleArbitrageExecution(t.Context(), quest))
+	assert.Len(t, executor.amounts, 2)
+	assert.Equal(t, "loss_streak_pause", quest.Checkpoint["status"])
+}
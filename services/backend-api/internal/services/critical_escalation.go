@@ -0,0 +1,303 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/telemetry"
+)
+
+const (
+	defaultEscalationAckTimeout    = 5 * time.Minute
+	defaultEscalationCheckInterval = 30 * time.Second
+	defaultEscalationRetention     = 24 * time.Hour
+)
+
+// ErrCriticalAlertNotFound is returned when acknowledging an unknown alert.
+var ErrCriticalAlertNotFound = errors.New("critical alert not found")
+
+// CriticalEscalationConfig configures escalation of unacknowledged critical alerts.
+type CriticalEscalationConfig struct {
+	// AckTimeout is how long a critical Telegram alert may go unacknowledged
+	// before it is escalated.
+	AckTimeout time.Duration
+	// CheckInterval is how often pending alerts are checked.
+	CheckInterval time.Duration
+	// Retention is how long alerts are kept for the API after they were sent.
+	Retention time.Duration
+	// KillSwitchChatIDs are alerted when the kill switch is engaged.
+	KillSwitchChatIDs []int64
+}
+
+// CriticalAlert is a critical notification awaiting acknowledgment.
+type CriticalAlert struct {
+	ID             string     `json:"id"`
+	ChatID         int64      `json:"chat_id"`
+	EventType      string     `json:"event_type"`
+	Message        string     `json:"message"`
+	SentAt         time.Time  `json:"sent_at"`
+	Deadline       time.Time  `json:"deadline"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy string     `json:"acknowledged_by,omitempty"`
+	EscalatedAt    *time.Time `json:"escalated_at,omitempty"`
+	// EscalationError is set when the escalation channel failed.
+	EscalationError string `json:"escalation_error,omitempty"`
+}
+
+// CriticalEscalationService escalates critical risk alerts, such as
+// liquidation proximity or a kill-switch trip, to a phone channel when
+// nobody acknowledges the Telegram message in time. Alerts are acknowledged
+// with the button on the message or through the API. Pending alerts are kept
+// in memory, so a restart forgets them.
+type CriticalEscalationService struct {
+	channel  NotificationChannel
+	config   CriticalEscalationConfig
+	notifier RiskEventNotifier
+	logger   *slog.Logger
+	now      func() time.Time
+
+	mu     sync.Mutex
+	alerts map[string]*CriticalAlert
+
+	runMu  sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewCriticalEscalationService creates an escalation service.
+//
+// Parameters:
+//
+//	channel: Channel unacknowledged alerts escalate to, normally Twilio.
+//	config: Escalation configuration; zero values use defaults.
+//
+// Returns:
+//
+//	*CriticalEscalationService: Initialized service (checks not started).
+func NewCriticalEscalationService(channel NotificationChannel, config CriticalEscalationConfig) *CriticalEscalationService {
+	if config.AckTimeout <= 0 {
+		config.AckTimeout = defaultEscalationAckTimeout
+	}
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = defaultEscalationCheckInterval
+	}
+	if config.Retention <= 0 {
+		config.Retention = defaultEscalationRetention
+	}
+	return &CriticalEscalationService{
+		channel: channel,
+		config:  config,
+		logger:  telemetry.Logger(),
+		now:     time.Now,
+		alerts:  make(map[string]*CriticalAlert),
+	}
+}
+
+// SetRiskNotifier sets how kill-switch trips are sent to KillSwitchChatIDs.
+func (s *CriticalEscalationService) SetRiskNotifier(notifier RiskEventNotifier) {
+	s.notifier = notifier
+}
+
+// Start checks pending alerts once per interval until Stop is called.
+func (s *CriticalEscalationService) Start(ctx context.Context) error {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+	if s.cancel != nil {
+		return fmt.Errorf("critical escalation already running")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.config.CheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.Check(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the checks and waits for the running check to finish.
+func (s *CriticalEscalationService) Stop() {
+	s.runMu.Lock()
+	cancel := s.cancel
+	s.cancel = nil
+	s.runMu.Unlock()
+	if cancel != nil {
+		cancel()
+		s.wg.Wait()
+	}
+}
+
+// Track starts the acknowledgment timer for a critical alert sent to a chat.
+//
+// Parameters:
+//
+//	chatID: Chat the alert was sent to.
+//	eventType: Risk event type, e.g. liquidation_proximity.
+//	message: Plain-text summary used for the escalation.
+//
+// Returns:
+//
+//	*CriticalAlert: The tracked alert.
+func (s *CriticalEscalationService) Track(chatID int64, eventType, message string) *CriticalAlert {
+	idBytes := make([]byte, notificationActionIDBytes)
+	_, _ = rand.Read(idBytes)
+	now := s.now()
+	alert := &CriticalAlert{
+		ID:        hex.EncodeToString(idBytes),
+		ChatID:    chatID,
+		EventType: eventType,
+		Message:   message,
+		SentAt:    now,
+		Deadline:  now.Add(s.config.AckTimeout),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.alerts[alert.ID] = alert
+	copied := *alert
+	return &copied
+}
+
+// Acknowledge stops the escalation of an alert. Acknowledging twice keeps
+// the first acknowledgment.
+//
+// Parameters:
+//
+//	id: Alert ID.
+//	by: Who acknowledged, e.g. "telegram:<chat>" or an operator name.
+//
+// Returns:
+//
+//	*CriticalAlert: The acknowledged alert.
+//	error: ErrCriticalAlertNotFound for an unknown or expired alert.
+func (s *CriticalEscalationService) Acknowledge(id, by string) (*CriticalAlert, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	alert, ok := s.alerts[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrCriticalAlertNotFound, id)
+	}
+	if alert.AcknowledgedAt == nil {
+		now := s.now()
+		alert.AcknowledgedAt = &now
+		alert.AcknowledgedBy = by
+		s.logger.Info("Critical alert acknowledged", "alert_id", id, "event_type", alert.EventType, "by", by,
+			"after", now.Sub(alert.SentAt).Round(time.Second).String())
+	}
+	copied := *alert
+	return &copied, nil
+}
+
+// List returns the tracked alerts, newest first.
+func (s *CriticalEscalationService) List() []CriticalAlert {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	alerts := make([]CriticalAlert, 0, len(s.alerts))
+	for _, alert := range s.alerts {
+		alerts = append(alerts, *alert)
+	}
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].SentAt.After(alerts[j].SentAt) })
+	return alerts
+}
+
+// Check escalates alerts that passed their deadline unacknowledged, once
+// each, and forgets alerts older than the retention.
+//
+// Parameters:
+//
+//	ctx: Context for the escalation channel.
+//
+// Returns:
+//
+//	int: Number of alerts escalated.
+func (s *CriticalEscalationService) Check(ctx context.Context) int {
+	now := s.now()
+	var due []*CriticalAlert
+	s.mu.Lock()
+	for id, alert := range s.alerts {
+		if now.Sub(alert.SentAt) > s.config.Retention {
+			delete(s.alerts, id)
+			continue
+		}
+		if alert.AcknowledgedAt == nil && alert.EscalatedAt == nil && !now.Before(alert.Deadline) {
+			escalatedAt := now
+			alert.EscalatedAt = &escalatedAt
+			due = append(due, alert)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, alert := range due {
+		err := s.escalate(ctx, alert)
+		if err != nil {
+			s.logger.Error("Failed to escalate critical alert", "alert_id", alert.ID, "event_type", alert.EventType, "error", err)
+			s.mu.Lock()
+			alert.EscalationError = err.Error()
+			s.mu.Unlock()
+			continue
+		}
+		s.logger.Warn("Escalated unacknowledged critical alert", "alert_id", alert.ID, "event_type", alert.EventType, "chat_id", alert.ChatID)
+	}
+	return len(due)
+}
+
+func (s *CriticalEscalationService) escalate(ctx context.Context, alert *CriticalAlert) error {
+	if s.channel == nil || !s.channel.IsEnabled() {
+		return fmt.Errorf("escalation channel not configured")
+	}
+	return s.channel.Send(ctx, &Notification{
+		ID:       "escalation-" + alert.ID,
+		Type:     NotificationTypeEmergency,
+		Priority: PriorityNotificationEmergency,
+		Title:    fmt.Sprintf("NeuraTrade critical alert unacknowledged for %s", s.config.AckTimeout),
+		Message:  fmt.Sprintf("%s: %s", alert.EventType, alert.Message),
+		Metadata: map[string]string{
+			"alert_id":   alert.ID,
+			"event_type": alert.EventType,
+		},
+		Timestamp: s.now(),
+	})
+}
+
+// Emit sends kill-switch trips to KillSwitchChatIDs as critical risk events,
+// which starts their escalation timers. Other events are ignored.
+func (s *CriticalEscalationService) Emit(ctx context.Context, event WebhookEventType, payload interface{}) {
+	data, ok := payload.(map[string]interface{})
+	if !ok || event != WebhookEventRisk || data["type"] != "kill_switch_engaged" || s.notifier == nil {
+		return
+	}
+	reason, _ := data["reason"].(string)
+	operator, _ := data["operator"].(string)
+	message := "The kill switch was engaged; trading is halted."
+	if reason != "" {
+		message = fmt.Sprintf("The kill switch was engaged (%s); trading is halted.", reason)
+	}
+	for _, chatID := range s.config.KillSwitchChatIDs {
+		err := s.notifier.NotifyRiskEvent(ctx, chatID, RiskEventNotification{
+			EventType: "kill_switch_engaged",
+			Severity:  "critical",
+			Message:   message,
+			Details:   map[string]string{"operator": operator},
+		})
+		if err != nil {
+			s.logger.Warn("Failed to send kill switch alert", "chat_id", chatID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingChannel struct {
+	mu   sync.Mutex
+	sent []*Notification
+	err  error
+}
+
+func (c *recordingChannel) Send(_ context.Context, notification *Notification) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, notification)
+	return c.err
+}
+
+func (c *recordingChannel) Name() string    { return "recording" }
+func (c *recordingChannel) IsEnabled() bool { return true }
+func (c *recordingChannel) Priorities() []NotificationPriority {
+	return []NotificationPriority{PriorityNotificationEmergency}
+}
+
+type recordingRiskNotifier struct {
+	chats  []int64
+	events []RiskEventNotification
+}
+
+func (n *recordingRiskNotifier) NotifyRiskEvent(_ context.Context, chatID int64, event RiskEventNotification) error {
+	n.chats = append(n.chats, chatID)
+	n.events = append(n.events, event)
+	return nil
+}
+
+func TestCriticalEscalationService_EscalatesUnacknowledged(t *testing.T) {
+	channel := &recordingChannel{}
+	escalation := NewCriticalEscalationService(channel, CriticalEscalationConfig{AckTimeout: 5 * time.Minute})
+	now := time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC)
+	escalation.now = func() time.Time { return now }
+
+	missed := escalation.Track(7, "liquidation_proximity", "long BTC/USDT is 4.0% from its liquidation price")
+	acked := escalation.Track(7, "drawdown_halt", "trading halted")
+	_, err := escalation.Acknowledge(acked.ID, "telegram:7")
+	require.NoError(t, err)
+
+	now = now.Add(4 * time.Minute)
+	assert.Equal(t, 0, escalation.Check(t.Context()), "still within the acknowledgment window")
+
+	now = now.Add(time.Minute)
+	assert.Equal(t, 1, escalation.Check(t.Context()))
+	require.Len(t, channel.sent, 1)
+	assert.Equal(t, PriorityNotificationEmergency, channel.sent[0].Priority)
+	assert.Contains(t, channel.sent[0].Message, "liquidation_proximity: long BTC/USDT")
+	assert.Equal(t, missed.ID, channel.sent[0].Metadata["alert_id"])
+
+	assert.Equal(t, 0, escalation.Check(t.Context()), "an alert escalates once")
+
+	// A late acknowledgment is still recorded
+	late, err := escalation.Acknowledge(missed.ID, "op-1")
+	require.NoError(t, err)
+	assert.NotNil(t, late.EscalatedAt)
+	assert.Equal(t, "op-1", late.AcknowledgedBy)
+	again, err := escalation.Acknowledge(acked.ID, "op-2")
+	require.NoError(t, err)
+	assert.Equal(t, "telegram:7", again.AcknowledgedBy, "the first acknowledgment wins")
+
+	_, err = escalation.Acknowledge("missing", "op-1")
+	assert.ErrorIs(t, err, ErrCriticalAlertNotFound)
+
+	now = now.Add(25 * time.Hour)
+	escalation.Check(t.Context())
+	assert.Empty(t, escalation.List(), "old alerts are forgotten")
+}
+
+func TestCriticalEscalationService_RecordsChannelFailure(t *testing.T) {
+	channel := &recordingChannel{err: fmt.Errorf("twilio returned status 401")}
+	escalation := NewCriticalEscalationService(channel, CriticalEscalationConfig{AckTimeout: time.Minute})
+	now := time.Now()
+	escalation.now = func() time.Time { return now }
+	escalation.Track(7, "kill_switch_engaged", "halted")
+
+	now = now.Add(time.Minute)
+	assert.Equal(t, 1, escalation.Check(t.Context()))
+	alerts := escalation.List()
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "twilio returned status 401", alerts[0].EscalationError)
+}
+
+func TestCriticalEscalationService_KillSwitchEvent(t *testing.T) {
+	notifier := &recordingRiskNotifier{}
+	escalation := NewCriticalEscalationService(&recordingChannel{}, CriticalEscalationConfig{KillSwitchChatIDs: []int64{7, 8}})
+	escalation.SetRiskNotifier(notifier)
+
+	escalation.Emit(t.Context(), WebhookEventRisk, map[string]interface{}{"type": "kill_switch_released"})
+	escalation.Emit(t.Context(), WebhookEventModeChanged, map[string]interface{}{"type": "kill_switch_engaged"})
+	assert.Empty(t, notifier.chats)
+
+	escalation.Emit(t.Context(), WebhookEventRisk, map[string]interface{}{"type": "kill_switch_engaged", "reason": "drawdown", "operator": "op-1"})
+	assert.Equal(t, []int64{7, 8}, notifier.chats)
+	assert.Equal(t, "critical", notifier.events[0].Severity)
+	assert.Contains(t, notifier.events[0].Message, "(drawdown)")
+}
+
+func TestNotificationService_CriticalRiskEventIsAcknowledgeable(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		_, _ = w.Write([]byte(`{"ok":true,"messageId":"1"}`))
+	}))
+	defer server.Close()
+
+	channel := &recordingChannel{}
+	escalation := NewCriticalEscalationService(channel, CriticalEscalationConfig{AckTimeout: time.Minute})
+	actions := newTestActionService(t, false, nil)
+	actions.SetAcknowledger(escalation)
+	ns := NewNotificationService(nil, nil, server.URL, "", "")
+	ns.SetActionService(actions)
+	ns.SetEscalation(escalation)
+
+	require.NoError(t, ns.NotifyRiskEvent(t.Context(), 7, RiskEventNotification{EventType: "drawdown", Severity: "high", Message: "warning"}))
+	require.NoError(t, ns.NotifyRiskEvent(t.Context(), 7, RiskEventNotification{EventType: "liquidation_proximity", Severity: "critical", Message: "close to liquidation"}))
+	require.Len(t, bodies, 2)
+	assert.NotContains(t, bodies[0], "replyMarkup", "only critical events are escalated")
+	alerts := escalation.List()
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "close to liquidation", alerts[0].Message)
+
+	markup := bodies[1]["replyMarkup"].(map[string]interface{})
+	button := markup["inline_keyboard"].([]interface{})[0].([]interface{})[0].(map[string]interface{})
+	data := button["callback_data"].(string)
+
+	_, err := actions.HandleCallback(t.Context(), 8, data)
+	assert.ErrorIs(t, err, ErrInvalidActionSignature, "the button only works in the chat it was sent to")
+	response, err := actions.HandleCallback(t.Context(), 7, data)
+	require.NoError(t, err)
+	assert.Equal(t, "Acknowledged", response.Toast)
+
+	escalation.now = func() time.Time { return time.Now().Add(time.Hour) }
+	assert.Equal(t, 0, escalation.Check(t.Context()))
+	assert.Empty(t, channel.sent)
+}
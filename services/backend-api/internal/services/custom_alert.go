@@ -0,0 +1,696 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/talib"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+)
+
+var (
+	ErrInvalidAlertRule    = errors.New("invalid alert rule")
+	ErrCustomAlertNotFound = errors.New("alert not found")
+	ErrCustomAlertLimit    = errors.New("too many alerts")
+	ErrCustomAlertNoUser   = errors.New("no user is bound to this chat")
+)
+
+// CustomAlertConfig configures user-defined alerts.
+type CustomAlertConfig struct {
+	// DefaultExchange is used by rules that do not name an exchange.
+	DefaultExchange string
+	// MaxAlertsPerUser caps the alerts one user can create.
+	MaxAlertsPerUser int
+	// Interval is the evaluation period of the standalone loop started with
+	// Start; alerts evaluated by the signal processor follow its cycle.
+	Interval time.Duration
+}
+
+// CustomAlert is a user-defined alert as stored.
+type CustomAlert struct {
+	ID              string     `json:"id"`
+	Rule            AlertRule  `json:"rule"`
+	Expression      string     `json:"expression"`
+	IsActive        bool       `json:"is_active"`
+	CreatedAt       time.Time  `json:"created_at"`
+	LastTriggeredAt *time.Time `json:"last_triggered_at,omitempty"`
+	LastValue       *float64   `json:"last_value,omitempty"`
+}
+
+// customAlertConditions is the user_alerts.conditions document of a custom
+// alert. Symbol and exchange are kept alongside the rule so the generic alert
+// API shows them.
+type customAlertConditions struct {
+	Rule            string     `json:"rule"`
+	Symbol          string     `json:"symbol"`
+	Exchange        string     `json:"exchange,omitempty"`
+	LastTriggeredAt *time.Time `json:"last_triggered_at,omitempty"`
+	LastValue       *float64   `json:"last_value,omitempty"`
+}
+
+// customAlertState is the evaluator's memory of an alert between cycles.
+type customAlertState struct {
+	// values are the rule's operands at the last evaluation, for crossings.
+	values map[string]float64
+	// armed is false while the rule's condition stays true after firing.
+	armed bool
+}
+
+// alertMaxCandles caps one candle fetch, as most exchanges do.
+const alertMaxCandles = 1000
+
+// OHLCVSource fetches candles.
+type OHLCVSource interface {
+	FetchOHLCV(ctx context.Context, exchange, symbol, timeframe string, limit int) (*ccxt.OHLCVResponse, error)
+}
+
+// CustomAlertService manages user-defined price and indicator alerts and
+// evaluates them. Rules are stored in user_alerts with alert_type "custom";
+// the signal processor calls EvaluateAlerts once per cycle.
+type CustomAlertService struct {
+	db        DBPool
+	candles   OHLCVSource
+	messenger DirectMessenger
+	config    CustomAlertConfig
+	logger    *slog.Logger
+	now       func() time.Time
+	mu        sync.Mutex
+	state     map[string]customAlertState
+	runMu     sync.Mutex
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// NewCustomAlertService creates a custom alert service.
+//
+// Parameters:
+//
+//	db: Database pool holding the users and user_alerts tables.
+//	candles: Candle source for evaluation and previews (may be nil when only managing alerts).
+//	config: Defaults; the zero value evaluates on binance every minute and allows 20 alerts per user.
+//
+// Returns:
+//
+//	*CustomAlertService: Initialized service.
+func NewCustomAlertService(db DBPool, candles OHLCVSource, config CustomAlertConfig) *CustomAlertService {
+	if config.DefaultExchange == "" {
+		config.DefaultExchange = "binance"
+	}
+	if config.MaxAlertsPerUser <= 0 {
+		config.MaxAlertsPerUser = 20
+	}
+	if config.Interval <= 0 {
+		config.Interval = time.Minute
+	}
+	return &CustomAlertService{
+		db:      db,
+		candles: candles,
+		config:  config,
+		logger:  telemetry.Logger(),
+		now:     time.Now,
+		state:   make(map[string]customAlertState),
+	}
+}
+
+// SetMessenger sets where triggered alerts are sent.
+func (s *CustomAlertService) SetMessenger(messenger DirectMessenger) {
+	s.messenger = messenger
+}
+
+// Start evaluates the alerts on a loop, for deployments where the signal
+// processor does not run.
+//
+// Parameters:
+//
+//	ctx: Parent context of the loop.
+//
+// Returns:
+//
+//	error: Error if the loop is already running.
+func (s *CustomAlertService) Start(ctx context.Context) error {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+	if s.cancel != nil {
+		return fmt.Errorf("custom alert service already running")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.EvaluateAlerts(ctx); err != nil {
+					s.logger.Warn("Failed to evaluate custom alerts", "error", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the evaluation loop.
+func (s *CustomAlertService) Stop() {
+	s.runMu.Lock()
+	cancel := s.cancel
+	s.cancel = nil
+	s.runMu.Unlock()
+	if cancel != nil {
+		cancel()
+		s.wg.Wait()
+	}
+}
+
+func (s *CustomAlertService) userIDForChat(ctx context.Context, chatID string) (string, error) {
+	var userID string
+	err := s.db.QueryRow(ctx, `SELECT id FROM users WHERE telegram_chat_id = $1`, chatID).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrCustomAlertNoUser
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up user: %w", err)
+	}
+	return userID, nil
+}
+
+// List returns a chat's custom alerts, newest first.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	chatID: Telegram chat ID of the user.
+//
+// Returns:
+//
+//	[]CustomAlert: The alerts.
+//	error: ErrCustomAlertNoUser or a query error.
+func (s *CustomAlertService) List(ctx context.Context, chatID string) ([]CustomAlert, error) {
+	userID, err := s.userIDForChat(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.Query(ctx, `
+		SELECT id, conditions, is_active, created_at
+		FROM user_alerts
+		WHERE user_id = $1 AND alert_type = 'custom'
+		ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+	defer rows.Close()
+
+	alerts := []CustomAlert{}
+	for rows.Next() {
+		var alert CustomAlert
+		var raw []byte
+		if err := rows.Scan(&alert.ID, &raw, &alert.IsActive, &alert.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan alert: %w", err)
+		}
+		if err := decodeCustomAlert(&alert, raw); err != nil {
+			s.logger.Warn("Skipping unreadable custom alert", "alert_id", alert.ID, "error", err)
+			continue
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, rows.Err()
+}
+
+// Create parses a rule and stores it as an active alert for a chat.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	chatID: Telegram chat ID of the user.
+//	expression: The rule, e.g. "BTC/USDT RSI(14,1h) < 30".
+//
+// Returns:
+//
+//	*CustomAlert: The stored alert.
+//	error: ErrInvalidAlertRule, ErrCustomAlertLimit, ErrCustomAlertNoUser or a persistence error.
+func (s *CustomAlertService) Create(ctx context.Context, chatID, expression string) (*CustomAlert, error) {
+	rule, err := ParseAlertRule(expression)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := s.userIDForChat(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int
+	if err := s.db.QueryRow(ctx, `SELECT COUNT(*) FROM user_alerts WHERE user_id = $1 AND alert_type = 'custom'`, userID).Scan(&count); err != nil {
+		return nil, fmt.Errorf("failed to count alerts: %w", err)
+	}
+	if count >= s.config.MaxAlertsPerUser {
+		return nil, fmt.Errorf("%w: at most %d custom alerts per user", ErrCustomAlertLimit, s.config.MaxAlertsPerUser)
+	}
+
+	conditions, err := json.Marshal(customAlertConditions{Rule: rule.String(), Symbol: rule.Symbol, Exchange: rule.Exchange})
+	if err != nil {
+		return nil, err
+	}
+	alert := &CustomAlert{
+		ID:         uuid.NewString(),
+		Rule:       rule,
+		Expression: rule.String(),
+		IsActive:   true,
+		CreatedAt:  s.now().UTC(),
+	}
+	if _, err := s.db.Exec(ctx, `
+		INSERT INTO user_alerts (id, user_id, alert_type, conditions, is_active, created_at)
+		VALUES ($1, $2, 'custom', $3, true, $4)`,
+		alert.ID, userID, conditions, alert.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to store alert: %w", err)
+	}
+	return alert, nil
+}
+
+// SetActive pauses or resumes one of a chat's alerts.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	chatID: Telegram chat ID of the user.
+//	alertID: The alert to change.
+//	active: Whether the alert is evaluated.
+//
+// Returns:
+//
+//	error: ErrCustomAlertNotFound, ErrCustomAlertNoUser or a persistence error.
+func (s *CustomAlertService) SetActive(ctx context.Context, chatID, alertID string, active bool) error {
+	userID, err := s.userIDForChat(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	tag, err := s.db.Exec(ctx, `
+		UPDATE user_alerts SET is_active = $1
+		WHERE id = $2 AND user_id = $3 AND alert_type = 'custom'`, active, alertID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update alert: %w", err)
+	}
+	if affected, err := tag.RowsAffected(); err == nil && affected == 0 {
+		return ErrCustomAlertNotFound
+	}
+	s.forget(alertID)
+	return nil
+}
+
+// Delete removes one of a chat's alerts.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	chatID: Telegram chat ID of the user.
+//	alertID: The alert to remove.
+//
+// Returns:
+//
+//	error: ErrCustomAlertNotFound, ErrCustomAlertNoUser or a persistence error.
+func (s *CustomAlertService) Delete(ctx context.Context, chatID, alertID string) error {
+	userID, err := s.userIDForChat(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	tag, err := s.db.Exec(ctx, `
+		DELETE FROM user_alerts
+		WHERE id = $1 AND user_id = $2 AND alert_type = 'custom'`, alertID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete alert: %w", err)
+	}
+	if affected, err := tag.RowsAffected(); err == nil && affected == 0 {
+		return ErrCustomAlertNotFound
+	}
+	s.forget(alertID)
+	return nil
+}
+
+func (s *CustomAlertService) forget(alertID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.state, alertID)
+}
+
+// evaluatedAlert is an active alert loaded for evaluation.
+type evaluatedAlert struct {
+	CustomAlert
+	chatID int64
+}
+
+// EvaluateAlerts checks every active custom alert against fresh market data
+// and notifies the owners of the alerts that fire. A rule fires when its
+// condition becomes true and re-arms once it no longer is; crossings hold
+// when the two sides cross between consecutive evaluations.
+//
+// Parameters:
+//
+//	ctx: Context for the queries and candle fetches.
+//
+// Returns:
+//
+//	int: Number of alerts that fired.
+//	error: Error loading the alerts; failed fetches are logged and skipped.
+func (s *CustomAlertService) EvaluateAlerts(ctx context.Context) (int, error) {
+	if s.candles == nil {
+		return 0, nil
+	}
+	alerts, err := s.activeAlerts(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	rules := make([]AlertRule, len(alerts))
+	for i, alert := range alerts {
+		rules[i] = alert.Rule
+	}
+	data := s.loadMarketData(ctx, rules, "", 0, s.now().UTC())
+
+	fired := 0
+	for _, alert := range alerts {
+		values, ok := s.currentValues(alert.Rule, data)
+		if !ok {
+			continue
+		}
+		if !s.observe(alert.CustomAlert, values) {
+			continue
+		}
+		if err := s.trigger(ctx, alert, values); err != nil {
+			s.logger.Warn("Failed to deliver custom alert", "alert_id", alert.ID, "error", err)
+			continue
+		}
+		fired++
+	}
+	return fired, nil
+}
+
+func (s *CustomAlertService) exchangeFor(rule AlertRule) string {
+	if rule.Exchange != "" {
+		return rule.Exchange
+	}
+	return s.config.DefaultExchange
+}
+
+func (s *CustomAlertService) activeAlerts(ctx context.Context) ([]evaluatedAlert, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT a.id, a.conditions, a.created_at, u.telegram_chat_id
+		FROM user_alerts a
+		JOIN users u ON u.id = a.user_id
+		WHERE a.alert_type = 'custom' AND a.is_active = true AND u.telegram_chat_id IS NOT NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load custom alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []evaluatedAlert
+	for rows.Next() {
+		var alert evaluatedAlert
+		var raw []byte
+		var chatID string
+		if err := rows.Scan(&alert.ID, &raw, &alert.CreatedAt, &chatID); err != nil {
+			return nil, fmt.Errorf("failed to scan custom alert: %w", err)
+		}
+		alert.IsActive = true
+		if err := decodeCustomAlert(&alert.CustomAlert, raw); err != nil {
+			s.logger.Warn("Skipping unreadable custom alert", "alert_id", alert.ID, "error", err)
+			continue
+		}
+		if alert.chatID, err = strconv.ParseInt(chatID, 10, 64); err != nil {
+			continue
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, rows.Err()
+}
+
+// alertMarketKey identifies a candle series, or with an empty timeframe a
+// funding rate series.
+type alertMarketKey struct {
+	exchange, symbol, timeframe string
+}
+
+// alertMarketData is the market data read by the rules of one evaluation.
+type alertMarketData struct {
+	candles map[alertMarketKey][]ccxt.OHLCV
+	funding map[alertMarketKey]alertSeries
+}
+
+// alertOperandTimeframe is the candle size an operand is computed from; at
+// a resolution, finer operands are computed from candles of the resolution.
+func alertOperandTimeframe(operand AlertOperand, resolution string) string {
+	if resolution != "" && operand.Timeframe != "" && alertTimeframes[operand.Timeframe] < alertTimeframes[resolution] {
+		return resolution
+	}
+	return operand.Timeframe
+}
+
+// loadMarketData fetches what the rules need, once per series. span is
+// how much history to cover beyond the indicators' warmup, up to to.
+// Failed fetches are logged and leave the series out.
+func (s *CustomAlertService) loadMarketData(ctx context.Context, rules []AlertRule, resolution string, span time.Duration, to time.Time) alertMarketData {
+	limits := make(map[alertMarketKey]int)
+	funding := make(map[alertMarketKey]bool)
+	for _, rule := range rules {
+		exchange := s.exchangeFor(rule)
+		for _, operand := range rule.operands() {
+			if operand.Indicator == AlertIndicatorFunding {
+				funding[alertMarketKey{exchange: exchange, symbol: rule.Symbol}] = true
+				continue
+			}
+			timeframe := alertOperandTimeframe(operand, resolution)
+			limit := min(operand.warmup()+int(span/alertTimeframes[timeframe]), alertMaxCandles)
+			keys := []alertMarketKey{{exchange, rule.Symbol, timeframe}}
+			if operand.Indicator == AlertIndicatorSpread {
+				keys = append(keys, alertMarketKey{operand.Exchange, rule.Symbol, timeframe})
+			}
+			for _, key := range keys {
+				limits[key] = max(limits[key], limit)
+			}
+		}
+	}
+
+	data := alertMarketData{
+		candles: make(map[alertMarketKey][]ccxt.OHLCV, len(limits)),
+		funding: make(map[alertMarketKey]alertSeries, len(funding)),
+	}
+	for key, limit := range limits {
+		response, err := s.candles.FetchOHLCV(ctx, key.exchange, key.symbol, key.timeframe, limit)
+		if err != nil {
+			s.logger.Warn("Failed to fetch candles for custom alerts",
+				"exchange", key.exchange, "symbol", key.symbol, "timeframe", key.timeframe, "error", err)
+			continue
+		}
+		data.candles[key] = response.OHLCV
+	}
+	for key := range funding {
+		// Funding settles every few hours; a day back always holds the latest rate
+		series, err := s.fundingSeries(ctx, key.exchange, key.symbol, to.Add(-span-24*time.Hour))
+		if err != nil {
+			s.logger.Warn("Failed to load funding rates for custom alerts",
+				"exchange", key.exchange, "symbol", key.symbol, "error", err)
+			continue
+		}
+		data.funding[key] = series
+	}
+	return data
+}
+
+// fundingSeries loads funding rates in percent. Perpetuals are stored under
+// their settlement symbol, e.g. BTC/USDT:USDT, so both forms are matched.
+func (s *CustomAlertService) fundingSeries(ctx context.Context, exchange, symbol string, since time.Time) (alertSeries, error) {
+	settlement := symbol
+	if _, quote, ok := strings.Cut(symbol, "/"); ok {
+		settlement = symbol + ":" + quote
+	}
+	rows, err := s.db.Query(ctx, `
+		SELECT funding_time, funding_rate
+		FROM funding_rate_history
+		WHERE exchange = $1 AND symbol IN ($2, $3) AND funding_time > $4
+		ORDER BY funding_time ASC`, exchange, symbol, settlement, since)
+	if err != nil {
+		return alertSeries{}, err
+	}
+	defer rows.Close()
+
+	var series alertSeries
+	for rows.Next() {
+		var at time.Time
+		var rate decimal.Decimal
+		if err := rows.Scan(&at, &rate); err != nil {
+			return alertSeries{}, err
+		}
+		series.add(at, rate.InexactFloat64()*100)
+	}
+	return series, rows.Err()
+}
+
+// operandSeries computes an operand over the loaded market data. Candle
+// values are known once their candle closes.
+func (s *CustomAlertService) operandSeries(rule AlertRule, operand AlertOperand, data alertMarketData, resolution string) (alertSeries, bool) {
+	exchange := s.exchangeFor(rule)
+	if operand.Indicator == AlertIndicatorFunding {
+		series, ok := data.funding[alertMarketKey{exchange: exchange, symbol: rule.Symbol}]
+		return series, ok
+	}
+
+	timeframe := alertOperandTimeframe(operand, resolution)
+	candles, ok := data.candles[alertMarketKey{exchange, rule.Symbol, timeframe}]
+	if !ok {
+		return alertSeries{}, false
+	}
+	duration := alertTimeframes[timeframe]
+	var series alertSeries
+
+	if operand.Indicator == AlertIndicatorSpread {
+		other, ok := data.candles[alertMarketKey{operand.Exchange, rule.Symbol, timeframe}]
+		if !ok {
+			return alertSeries{}, false
+		}
+		otherCloses := make(map[time.Time]float64, len(other))
+		for _, candle := range other {
+			otherCloses[candle.Timestamp] = candle.Close.InexactFloat64()
+		}
+		for _, candle := range candles {
+			base := candle.Close.InexactFloat64()
+			if price, ok := otherCloses[candle.Timestamp]; ok && base > 0 {
+				series.add(candle.Timestamp.Add(duration), (price-base)/base*100)
+			}
+		}
+		return series, true
+	}
+
+	closes := make([]float64, len(candles))
+	for i, candle := range candles {
+		closes[i] = candle.Close.InexactFloat64()
+	}
+	var values []float64
+	switch operand.Indicator {
+	case AlertIndicatorPrice:
+		values = closes
+	case AlertIndicatorRSI:
+		values = talib.Rsi(closes, operand.Period)
+	case AlertIndicatorSMA:
+		values = talib.Sma(closes, operand.Period)
+	case AlertIndicatorEMA:
+		values = talib.Ema(closes, operand.Period)
+	}
+	// Indicators start once their period is filled and are left out until
+	// the smoothing has settled
+	offset := len(closes) - len(values)
+	settled := min(operand.warmup(), len(closes)) - 1
+	for i, value := range values {
+		if i+offset >= settled {
+			series.add(candles[i+offset].Timestamp.Add(duration), value)
+		}
+	}
+	return series, true
+}
+
+// currentValues returns the latest value of every operand of a rule.
+func (s *CustomAlertService) currentValues(rule AlertRule, data alertMarketData) (map[string]float64, bool) {
+	values := make(map[string]float64)
+	for _, operand := range rule.operands() {
+		series, ok := s.operandSeries(rule, operand, data, "")
+		if !ok {
+			return nil, false
+		}
+		value, ok := series.last()
+		if !ok {
+			return nil, false
+		}
+		values[operand.String()] = value
+	}
+	return values, true
+}
+
+// observe records new operand values for an alert and reports whether it
+// fires.
+func (s *CustomAlertService) observe(alert CustomAlert, values map[string]float64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, seen := s.state[alert.ID]
+
+	armed := previous.armed
+	if !seen {
+		// After a restart, an alert that already fired stays quiet until its
+		// condition clears
+		armed = alert.LastTriggeredAt == nil
+	}
+	fires, armed := alert.Rule.step(values, previous.values, armed)
+	s.state[alert.ID] = customAlertState{values: values, armed: armed}
+	return fires
+}
+
+func (s *CustomAlertService) trigger(ctx context.Context, alert evaluatedAlert, values map[string]float64) error {
+	now := s.now().UTC()
+	// The first indicator of the rule is kept as its headline value
+	value := values[alert.Rule.operands()[0].String()]
+	conditions, err := json.Marshal(customAlertConditions{
+		Rule: alert.Expression, Symbol: alert.Rule.Symbol, Exchange: alert.Rule.Exchange,
+		LastTriggeredAt: &now, LastValue: &value,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := s.db.Exec(ctx, `UPDATE user_alerts SET conditions = $1 WHERE id = $2`, conditions, alert.ID); err != nil {
+		s.logger.Warn("Failed to record custom alert trigger", "alert_id", alert.ID, "error", err)
+	}
+	if s.messenger == nil {
+		return nil
+	}
+	return s.messenger.SendDirectMessage(ctx, alert.chatID, formatCustomAlertMessage(alert.Rule, s.exchangeFor(alert.Rule), values))
+}
+
+func formatCustomAlertMessage(rule AlertRule, exchange string, values map[string]float64) string {
+	var readings []string
+	for _, operand := range rule.operands() {
+		reading := strconv.FormatFloat(roundAlertValue(values[operand.String()]), 'f', -1, 64)
+		if operand.percent() {
+			reading += "%"
+		}
+		readings = append(readings, operand.String()+" "+reading)
+	}
+	return fmt.Sprintf("🔔 Alert: %s\n\nNow on %s: %s.", rule.String(), exchange, strings.Join(readings, ", "))
+}
+
+// roundAlertValue keeps six significant digits for display.
+func roundAlertValue(value float64) float64 {
+	if value == 0 {
+		return 0
+	}
+	scale := math.Pow(10, 5-math.Floor(math.Log10(math.Abs(value))))
+	return math.Round(value*scale) / scale
+}
+
+func decodeCustomAlert(alert *CustomAlert, raw []byte) error {
+	var conditions customAlertConditions
+	if err := json.Unmarshal(raw, &conditions); err != nil {
+		return err
+	}
+	rule, err := ParseAlertRule(conditions.Rule)
+	if err != nil {
+		return err
+	}
+	alert.Rule = rule
+	alert.Expression = rule.String()
+	alert.LastTriggeredAt = conditions.LastTriggeredAt
+	alert.LastValue = conditions.LastValue
+	return nil
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrAlertPreviewNoData is returned when there is no market data to
+// preview a rule against.
+var ErrAlertPreviewNoData = errors.New("not enough market data to preview the alert")
+
+const (
+	// alertPreviewResolution is the step of a preview. Operands on finer
+	// timeframes are computed from candles of this size.
+	alertPreviewResolution = "1h"
+	alertPreviewWindow     = 30 * 24 * time.Hour
+	alertPreviewRecent     = 10
+)
+
+// AlertPreview is how often a rule would have fired over recent history.
+type AlertPreview struct {
+	Expression string `json:"expression"`
+	Exchange   string `json:"exchange"`
+	// Resolution is the candle size the rule was stepped through.
+	Resolution string    `json:"resolution"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	// Evaluations is the number of steps with data for every operand.
+	Evaluations    int     `json:"evaluations"`
+	Triggers       int     `json:"triggers"`
+	TriggersPerDay float64 `json:"triggers_per_day"`
+	// RecentTriggers are the times of the last triggers, oldest first.
+	RecentTriggers []time.Time `json:"recent_triggers"`
+	// Notes explain where the preview differs from live evaluation.
+	Notes []string `json:"notes,omitempty"`
+}
+
+// Preview evaluates a rule against the last 30 days of market data without
+// saving it, stepping hourly with the same firing logic as live alerts.
+//
+// Parameters:
+//
+//	ctx: Context for the candle fetches and funding queries.
+//	expression: The rule, e.g. "BTC/USDT RSI(14,4h) < 30 AND funding < 0".
+//
+// Returns:
+//
+//	*AlertPreview: How often the rule would have fired.
+//	error: ErrInvalidAlertRule, or ErrAlertPreviewNoData when an operand has no history.
+func (s *CustomAlertService) Preview(ctx context.Context, expression string) (*AlertPreview, error) {
+	rule, err := ParseAlertRule(expression)
+	if err != nil {
+		return nil, err
+	}
+	if s.candles == nil {
+		return nil, fmt.Errorf("%w: no candle source configured", ErrAlertPreviewNoData)
+	}
+
+	exchange := s.exchangeFor(rule)
+	resolution := alertTimeframes[alertPreviewResolution]
+	to := s.now().UTC().Truncate(resolution)
+	from := to.Add(-alertPreviewWindow)
+	data := s.loadMarketData(ctx, []AlertRule{rule}, alertPreviewResolution, alertPreviewWindow, to)
+
+	preview := &AlertPreview{
+		Expression:     rule.String(),
+		Exchange:       exchange,
+		Resolution:     alertPreviewResolution,
+		To:             to,
+		RecentTriggers: []time.Time{},
+	}
+	series := make(map[string]alertSeries)
+	for _, operand := range rule.operands() {
+		values, ok := s.operandSeries(rule, operand, data, alertPreviewResolution)
+		if !ok || len(values.values) == 0 {
+			return nil, fmt.Errorf("%w: no %s data for %s on %s", ErrAlertPreviewNoData, operand, rule.Symbol, exchange)
+		}
+		series[operand.String()] = values
+		if timeframe := alertOperandTimeframe(operand, alertPreviewResolution); timeframe != operand.Timeframe {
+			preview.Notes = append(preview.Notes, fmt.Sprintf("%s is evaluated on %s candles, so shorter moves are not seen", operand, timeframe))
+		}
+	}
+
+	var previous map[string]float64
+	armed := true
+	for at := from.Add(resolution); !at.After(to); at = at.Add(resolution) {
+		current := make(map[string]float64, len(series))
+		for key, values := range series {
+			if value, ok := values.at(at); ok {
+				current[key] = value
+			}
+		}
+		if len(current) < len(series) {
+			continue
+		}
+		if preview.Evaluations == 0 {
+			preview.From = at
+		}
+		preview.Evaluations++
+
+		var fires bool
+		fires, armed = rule.step(current, previous, armed)
+		previous = current
+		if fires {
+			preview.Triggers++
+			preview.RecentTriggers = append(preview.RecentTriggers, at)
+			if len(preview.RecentTriggers) > alertPreviewRecent {
+				preview.RecentTriggers = preview.RecentTriggers[1:]
+			}
+		}
+	}
+	if preview.Evaluations == 0 {
+		return nil, fmt.Errorf("%w: %s has no overlapping history on %s", ErrAlertPreviewNoData, rule.Symbol, exchange)
+	}
+
+	days := float64(preview.Evaluations) * resolution.Hours() / 24
+	preview.TriggersPerDay = math.Round(float64(preview.Triggers)/days*100) / 100
+	if covered := to.Sub(preview.From); covered < alertPreviewWindow-24*time.Hour {
+		preview.Notes = append(preview.Notes, fmt.Sprintf("market data covers only the last %.1f days", covered.Hours()/24))
+	}
+	return preview, nil
+}
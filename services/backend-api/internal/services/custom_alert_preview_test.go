@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/database"
+)
+
+// squareWaveCandles returns hourly candles ending at end that alternate
+// between 90 and 110 every 24 hours, rising on every 48th candle from the 24th.
+type squareWaveCandles struct {
+	end time.Time
+}
+
+func (f squareWaveCandles) FetchOHLCV(_ context.Context, exchange, symbol, timeframe string, limit int) (*ccxt.OHLCVResponse, error) {
+	if exchange != "binance" || timeframe != "1h" {
+		return nil, fmt.Errorf("no %s candles on %s", timeframe, exchange)
+	}
+	prices := make([]float64, limit)
+	for i := range prices {
+		prices[i] = 90
+		if i%48 >= 24 {
+			prices[i] = 110
+		}
+	}
+	return &ccxt.OHLCVResponse{OHLCV: hourlyCandles(f.end.Add(-time.Duration(limit)*time.Hour), prices...)}, nil
+}
+
+func TestCustomAlertService_Preview(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+
+	to := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	from := to.Add(-30 * 24 * time.Hour)
+	service := NewCustomAlertService(database.NewMockDBPool(mockPool), squareWaveCandles{end: to}, CustomAlertConfig{})
+	service.now = func() time.Time { return to.Add(30 * time.Minute) }
+
+	// Funding is negative for the first 15 days
+	mockPool.ExpectQuery("SELECT funding_time, funding_rate").
+		WithArgs("binance", "BTC/USDT", "BTC/USDT:USDT", to.Add(-31*24*time.Hour)).
+		WillReturnRows(pgxmock.NewRows([]string{"funding_time", "funding_rate"}).
+			AddRow(from, decimal.NewFromFloat(-0.0001)).
+			AddRow(from.Add(15*24*time.Hour), decimal.NewFromFloat(0.0001)))
+
+	preview, err := service.Preview(t.Context(), "BTC/USDT price crosses above 100 and funding < 0")
+	require.NoError(t, err)
+	assert.Equal(t, "BTC/USDT price crosses above 100 AND funding < 0", preview.Expression)
+	assert.Equal(t, 720, preview.Evaluations)
+	assert.Equal(t, from.Add(time.Hour), preview.From)
+	assert.Equal(t, 8, preview.Triggers, "one rise every two days while funding is negative")
+	assert.Equal(t, 0.27, preview.TriggersPerDay)
+	require.Len(t, preview.RecentTriggers, 8)
+	assert.Equal(t, from.Add(359*time.Hour), preview.RecentTriggers[7])
+	assert.Equal(t, []string{"price is evaluated on 1h candles, so shorter moves are not seen"}, preview.Notes)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+
+	_, err = service.Preview(t.Context(), "BTC/USDT on okx price > 1")
+	assert.ErrorIs(t, err, ErrAlertPreviewNoData)
+
+	_, err = service.Preview(t.Context(), "BTC/USDT price >")
+	assert.ErrorIs(t, err, ErrInvalidAlertRule)
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/database"
+)
+
+type fakeCandles struct {
+	closes map[string][]float64
+	calls  int
+}
+
+func (f *fakeCandles) FetchOHLCV(_ context.Context, exchange, symbol, timeframe string, limit int) (*ccxt.OHLCVResponse, error) {
+	f.calls++
+	response := &ccxt.OHLCVResponse{Exchange: exchange, Symbol: symbol, Timeframe: timeframe}
+	for _, price := range f.closes[exchange+":"+symbol+":"+timeframe] {
+		response.OHLCV = append(response.OHLCV, ccxt.OHLCV{Close: decimal.NewFromFloat(price)})
+	}
+	return response, nil
+}
+
+func customAlertRows(alerts ...[]interface{}) *pgxmock.Rows {
+	rows := pgxmock.NewRows([]string{"id", "conditions", "created_at", "telegram_chat_id"})
+	for _, alert := range alerts {
+		rows.AddRow(alert...)
+	}
+	return rows
+}
+
+func customAlertRow(id, rule, chatID string) []interface{} {
+	raw, _ := json.Marshal(customAlertConditions{Rule: rule})
+	return []interface{}{id, raw, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), chatID}
+}
+
+func TestCustomAlertService_EvaluateAlerts(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+
+	candles := &fakeCandles{closes: map[string][]float64{
+		"binance:BTC/USDT:1m": {69000, 69500},
+	}}
+	messenger := &recordingMessenger{}
+	service := NewCustomAlertService(database.NewMockDBPool(mockPool), candles, CustomAlertConfig{})
+	service.SetMessenger(messenger)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	rows := func() *pgxmock.Rows {
+		return customAlertRows(
+			customAlertRow("cross", "BTC/USDT price crosses above 70000", "7"),
+			customAlertRow("above", "BTC/USDT price > 69900", "8"),
+		)
+	}
+
+	// First cycle seeds the crossing and the threshold is not met yet; both
+	// rules share one fetch
+	mockPool.ExpectQuery("SELECT a.id, a.conditions").WillReturnRows(rows())
+	fired, err := service.EvaluateAlerts(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 0, fired)
+	assert.Equal(t, 1, candles.calls)
+
+	// Price moves through both thresholds
+	candles.closes["binance:BTC/USDT:1m"] = []float64{69500, 70100}
+	mockPool.ExpectQuery("SELECT a.id, a.conditions").WillReturnRows(rows())
+	mockPool.ExpectExec("UPDATE user_alerts SET conditions").
+		WithArgs(pgxmock.AnyArg(), "cross").WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mockPool.ExpectExec("UPDATE user_alerts SET conditions").
+		WithArgs(pgxmock.AnyArg(), "above").WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	fired, err = service.EvaluateAlerts(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 2, fired)
+	require.Equal(t, []int64{7, 8}, messenger.chats)
+	assert.Contains(t, messenger.texts[0], "BTC/USDT price crosses above 70000")
+	assert.Contains(t, messenger.texts[0], "Now on binance: price 70100.")
+
+	// Still above: neither alert repeats
+	candles.closes["binance:BTC/USDT:1m"] = []float64{70100, 70200}
+	mockPool.ExpectQuery("SELECT a.id, a.conditions").WillReturnRows(rows())
+	fired, err = service.EvaluateAlerts(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 0, fired)
+
+	// Below again re-arms the threshold alert, which fires on the next rise
+	for _, price := range []float64{69800, 69950} {
+		candles.closes["binance:BTC/USDT:1m"] = []float64{price}
+		mockPool.ExpectQuery("SELECT a.id, a.conditions").WillReturnRows(rows())
+		if price == 69950 {
+			mockPool.ExpectExec("UPDATE user_alerts SET conditions").
+				WithArgs(pgxmock.AnyArg(), "above").WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		}
+		_, err = service.EvaluateAlerts(t.Context())
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []int64{7, 8, 8}, messenger.chats, "the crossing needs to pass 70000 again")
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestCustomAlertService_TriggeredAlertStaysQuietAfterRestart(t *testing.T) {
+	triggered := time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC)
+	rule, err := ParseAlertRule("BTC/USDT RSI < 30")
+	require.NoError(t, err)
+	alert := CustomAlert{ID: "a", Rule: rule, LastTriggeredAt: &triggered}
+	rsi := func(value float64) map[string]float64 { return map[string]float64{"RSI(14,1h)": value} }
+
+	service := NewCustomAlertService(nil, nil, CustomAlertConfig{})
+	assert.False(t, service.observe(alert, rsi(25)), "already fired before the restart")
+	assert.False(t, service.observe(alert, rsi(35)))
+	assert.True(t, service.observe(alert, rsi(28)))
+}
+
+func hourlyCandles(start time.Time, prices ...float64) []ccxt.OHLCV {
+	candles := make([]ccxt.OHLCV, len(prices))
+	for i, price := range prices {
+		candles[i] = ccxt.OHLCV{Timestamp: start.Add(time.Duration(i) * time.Hour), Close: decimal.NewFromFloat(price)}
+	}
+	return candles
+}
+
+func TestCustomAlertService_OperandSeries(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	decline := make([]float64, 60)
+	for i := range decline {
+		decline[i] = float64(100 - i)
+	}
+	data := alertMarketData{candles: map[alertMarketKey][]ccxt.OHLCV{
+		{"binance", "BTC/USDT", "1h"}: hourlyCandles(start, decline...),
+		{"okx", "BTC/USDT", "1h"}:     hourlyCandles(start, decline[:59]...),
+	}}
+	service := NewCustomAlertService(nil, nil, CustomAlertConfig{})
+	rule, err := ParseAlertRule("BTC/USDT RSI(14,1h) < 30 AND SMA(5,1h) > 40 AND spread(okx) >= 0")
+	require.NoError(t, err)
+	operands := rule.operands()
+
+	rsi, ok := service.operandSeries(rule, operands[0], data, "")
+	require.True(t, ok)
+	value, _ := rsi.last()
+	assert.Less(t, value, 1.0)
+	_, ok = rsi.at(start.Add(50 * time.Hour))
+	assert.False(t, ok, "RSI is left out until its smoothing settles")
+
+	sma, _ := service.operandSeries(rule, operands[1], data, "")
+	value, _ = sma.last()
+	assert.InDelta(t, 43, value, 1e-9)
+	value, ok = sma.at(start.Add(10 * time.Hour))
+	require.True(t, ok, "value of the candle that closed at 10:00")
+	assert.InDelta(t, 93, value, 1e-9)
+
+	// Identical prices wherever both exchanges have an hourly candle
+	spread, ok := service.operandSeries(rule, operands[2], data, "1h")
+	require.True(t, ok)
+	value, _ = spread.last()
+	assert.Zero(t, value)
+	assert.Len(t, spread.values, 59)
+}
+
+func TestCustomAlertService_Create(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	service := NewCustomAlertService(database.NewMockDBPool(mockPool), nil, CustomAlertConfig{MaxAlertsPerUser: 1})
+
+	_, err = service.Create(t.Context(), "42", "BTC/USDT MACD < 1")
+	assert.ErrorIs(t, err, ErrInvalidAlertRule)
+
+	mockPool.ExpectQuery("SELECT id FROM users").WithArgs("42").
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("user-1"))
+	mockPool.ExpectQuery("SELECT COUNT").WithArgs("user-1").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(0))
+	mockPool.ExpectExec("INSERT INTO user_alerts").
+		WithArgs(pgxmock.AnyArg(), "user-1", pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	alert, err := service.Create(t.Context(), "42", "when btc/usdt rsi < 30")
+	require.NoError(t, err)
+	assert.Equal(t, "BTC/USDT RSI(14,1h) < 30", alert.Expression)
+
+	mockPool.ExpectQuery("SELECT id FROM users").WithArgs("42").
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow("user-1"))
+	mockPool.ExpectQuery("SELECT COUNT").WithArgs("user-1").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(1))
+	_, err = service.Create(t.Context(), "42", "BTC/USDT price > 1")
+	assert.ErrorIs(t, err, ErrCustomAlertLimit)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
@@ -0,0 +1,559 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/irfndi/neuratrade/pkg/interfaces"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	dailyLossStateKey = "risk:daily_pnl"
+	dailyLossDayFmt   = "2006-01-02"
+	// dailyLossOpeningKeyBase prefixes the per-day hash of each chat's
+	// opening equity. It is written once per day, so a restart or a rewritten
+	// state cannot move the baseline.
+	dailyLossOpeningKeyBase = "risk:daily_opening_equity"
+	dailyLossOpeningTTL     = 48 * time.Hour
+
+	defaultDailyLossMaxPct        = 0.02
+	defaultDailyLossCheckInterval = time.Minute
+)
+
+// DailyLossGuard reports whether a chat's new entries are halted because its
+// daily loss cap was breached.
+type DailyLossGuard interface {
+	EntriesHalted(ctx context.Context, chatID string) bool
+}
+
+// EquitySource returns a chat's current account equity, marked to market.
+type EquitySource interface {
+	Equity(ctx context.Context, chatID string) (decimal.Decimal, error)
+}
+
+// DailyLossLimitSource overrides the percentage loss cap per chat, for
+// example from its operating profile.
+type DailyLossLimitSource interface {
+	// DailyLossPct returns the chat's cap as a fraction of the day's opening
+	// equity; ok is false when the configured cap applies.
+	DailyLossPct(ctx context.Context, chatID string) (pct float64, ok bool)
+}
+
+// PositionFlattener closes a chat's open positions after its daily loss cap
+// was breached.
+type PositionFlattener func(ctx context.Context, chatID string) error
+
+// DailyLossConfig configures the daily loss circuit.
+type DailyLossConfig struct {
+	// MaxLossPct is the daily loss, as a fraction of the equity at the UTC day
+	// open, that halts new entries.
+	MaxLossPct float64
+	// MaxLoss is an absolute daily loss cap in USDT; zero disables it. When
+	// both caps apply the smaller one wins.
+	MaxLoss decimal.Decimal
+	// FlattenOnBreach closes open positions when the cap is breached.
+	FlattenOnBreach bool
+	// CheckInterval is how often tracked chats are re-evaluated, which also
+	// bounds how late entries resume after the UTC day rolls over.
+	CheckInterval time.Duration
+}
+
+// DailyLossState is a chat's profit and loss for one UTC day.
+type DailyLossState struct {
+	ChatID string `json:"chat_id"`
+	// Day is the UTC date the state covers.
+	Day           string          `json:"day"`
+	OpeningEquity decimal.Decimal `json:"opening_equity"`
+	Equity        decimal.Decimal `json:"equity"`
+	RealizedPnL   decimal.Decimal `json:"realized_pnl"`
+	UnrealizedPnL decimal.Decimal `json:"unrealized_pnl"`
+	// PnL is realized plus unrealized profit since the day opened.
+	PnL decimal.Decimal `json:"pnl"`
+	// Limit is the loss that halts entries; zero when no cap applies.
+	Limit     decimal.Decimal `json:"limit"`
+	Halted    bool            `json:"halted"`
+	HaltedAt  *time.Time      `json:"halted_at,omitempty"`
+	Flattened bool            `json:"flattened"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// DailyLossCircuit tracks each chat's realized and unrealized PnL per UTC day.
+// Once the loss reaches the cap new entries are halted, and optionally open
+// positions are flattened, until the next UTC day.
+type DailyLossCircuit struct {
+	redis     *redis.Client
+	config    DailyLossConfig
+	equity    EquitySource
+	limits    DailyLossLimitSource
+	positions MarginPositionSource
+	flatten   PositionFlattener
+	events    EventEmitter
+	messenger DirectMessenger
+	logger    *slog.Logger
+	now       func() time.Time
+	// mu serializes state updates so realized PnL is not lost between checks.
+	mu     sync.Mutex
+	runMu  sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Ensure DailyLossCircuit implements DailyLossGuard and PreTradeHook.
+var (
+	_ DailyLossGuard = (*DailyLossCircuit)(nil)
+	_ PreTradeHook   = (*DailyLossCircuit)(nil)
+)
+
+// NewDailyLossCircuit creates a daily loss circuit.
+//
+// Parameters:
+//
+//	client: Redis client used to persist per-chat daily state.
+//	equity: Source of current equity for unrealized PnL (may be nil to track realized PnL only).
+//	config: Circuit configuration; zero values use defaults.
+//
+// Returns:
+//
+//	*DailyLossCircuit: Initialized circuit (checks not started).
+func NewDailyLossCircuit(client *redis.Client, equity EquitySource, config DailyLossConfig) *DailyLossCircuit {
+	if config.MaxLossPct <= 0 {
+		config.MaxLossPct = defaultDailyLossMaxPct
+	}
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = defaultDailyLossCheckInterval
+	}
+	return &DailyLossCircuit{
+		redis:  client,
+		config: config,
+		equity: equity,
+		logger: telemetry.Logger(),
+		now:    time.Now,
+	}
+}
+
+// Config returns the circuit configuration.
+func (d *DailyLossCircuit) Config() DailyLossConfig {
+	return d.config
+}
+
+// SetFlattener sets how open positions are closed when FlattenOnBreach is enabled.
+func (d *DailyLossCircuit) SetFlattener(flatten PositionFlattener) {
+	d.flatten = flatten
+}
+
+// SetLimitSource sets where per-chat percentage caps come from.
+func (d *DailyLossCircuit) SetLimitSource(limits DailyLossLimitSource) {
+	d.limits = limits
+}
+
+// SetPositionSource lets orders that close an open position through the
+// pre-trade hook while entries are halted.
+func (d *DailyLossCircuit) SetPositionSource(positions MarginPositionSource) {
+	d.positions = positions
+}
+
+// SetEventEmitter publishes halt and resume risk events.
+func (d *DailyLossCircuit) SetEventEmitter(events EventEmitter) {
+	d.events = events
+}
+
+// SetMessenger sets the Telegram sender used for halt and resume notifications.
+func (d *DailyLossCircuit) SetMessenger(messenger DirectMessenger) {
+	d.messenger = messenger
+}
+
+// Start re-evaluates every tracked chat now and then once per interval until
+// Stop is called.
+func (d *DailyLossCircuit) Start(ctx context.Context) error {
+	d.runMu.Lock()
+	defer d.runMu.Unlock()
+	if d.cancel != nil {
+		return fmt.Errorf("daily loss circuit already running")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	d.cancel = cancel
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		// Check right away so a restart records the day's baseline without
+		// waiting a full interval.
+		d.CheckAll(ctx)
+		ticker := time.NewTicker(d.config.CheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				d.CheckAll(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the checks and waits for the running check to finish.
+func (d *DailyLossCircuit) Stop() {
+	d.runMu.Lock()
+	cancel := d.cancel
+	d.cancel = nil
+	d.runMu.Unlock()
+	if cancel != nil {
+		cancel()
+		d.wg.Wait()
+	}
+}
+
+// CheckAll re-evaluates every tracked chat and returns how many are halted.
+func (d *DailyLossCircuit) CheckAll(ctx context.Context) int {
+	chatIDs, err := d.redis.HKeys(ctx, dailyLossStateKey).Result()
+	if err != nil {
+		d.logger.Warn("Failed to list daily loss states", "error", err)
+		return 0
+	}
+	halted := 0
+	for _, chatID := range chatIDs {
+		state, err := d.Evaluate(ctx, chatID)
+		if err != nil {
+			d.logger.Warn("Failed to evaluate daily loss", "chat_id", chatID, "error", err)
+			continue
+		}
+		if state.Halted {
+			halted++
+		}
+	}
+	return halted
+}
+
+// Evaluate refreshes a chat's daily PnL, halting entries when the cap is
+// breached and resuming them once the UTC day has rolled over. The chat is
+// tracked from its first evaluation on.
+//
+// Parameters:
+//
+//	ctx: Context for the evaluation.
+//	chatID: The chat to evaluate.
+//
+// Returns:
+//
+//	*DailyLossState: The chat's state for the current UTC day.
+//	error: Error if the state could not be read or saved.
+func (d *DailyLossCircuit) Evaluate(ctx context.Context, chatID string) (*DailyLossState, error) {
+	return d.update(ctx, chatID, decimal.Zero)
+}
+
+// RecordRealized adds a closed trade's profit or loss to the chat's day and
+// re-evaluates the cap.
+func (d *DailyLossCircuit) RecordRealized(ctx context.Context, chatID string, pnl decimal.Decimal) (*DailyLossState, error) {
+	return d.update(ctx, chatID, pnl)
+}
+
+// RecordAccountRealized adds a closed position's profit or loss to every
+// tracked chat's day. All chats trade one exchange account, so a closed
+// position counts towards each of them.
+func (d *DailyLossCircuit) RecordAccountRealized(ctx context.Context, pnl decimal.Decimal) error {
+	chatIDs, err := d.redis.HKeys(ctx, dailyLossStateKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list daily loss states: %w", err)
+	}
+	var errs []error
+	for _, chatID := range chatIDs {
+		if _, err := d.RecordRealized(ctx, chatID, pnl); err != nil {
+			errs = append(errs, fmt.Errorf("chat %s: %w", chatID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// EntriesHalted reports whether the chat's new entries are halted. Errors
+// are logged and halt new entries, since the loss cannot be checked.
+func (d *DailyLossCircuit) EntriesHalted(ctx context.Context, chatID string) bool {
+	state, err := d.Evaluate(ctx, chatID)
+	if err != nil {
+		d.logger.Warn("Failed to evaluate daily loss, halting new entries", "chat_id", chatID, "error", err)
+		return true
+	}
+	return state.Halted
+}
+
+// AccountHalted reports whether any chat's entries are halted today. All
+// chats trade one exchange account, so one breached cap halts entries on
+// it. Errors are logged and halt new entries.
+func (d *DailyLossCircuit) AccountHalted(ctx context.Context) bool {
+	states, err := d.redis.HGetAll(ctx, dailyLossStateKey).Result()
+	if err != nil {
+		d.logger.Warn("Failed to read daily loss states, halting new entries", "error", err)
+		return true
+	}
+	today := d.now().UTC().Format(dailyLossDayFmt)
+	for chatID, raw := range states {
+		var state DailyLossState
+		if err := json.Unmarshal([]byte(raw), &state); err != nil {
+			d.logger.Warn("Failed to decode daily loss state, halting new entries", "chat_id", chatID, "error", err)
+			return true
+		}
+		if state.Day == today && state.Halted {
+			return true
+		}
+	}
+	return false
+}
+
+// PreTrade vetoes orders while the account's entries are halted. Orders that
+// close an open position pass, so exits and stop losses keep working.
+func (d *DailyLossCircuit) PreTrade(ctx context.Context, order HookOrder) (PreTradeDecision, error) {
+	if !d.AccountHalted(ctx) {
+		return PreTradeDecision{}, nil
+	}
+	var open []interfaces.Position
+	if d.positions != nil {
+		open = d.positions.GetOpenPositions()
+	}
+	if closesOpenPosition(open, order) {
+		return PreTradeDecision{}, nil
+	}
+	return PreTradeDecision{Veto: true, Reason: "daily loss cap reached, new entries are halted until 00:00 UTC"}, nil
+}
+
+// Status returns the chat's stored state without re-evaluating it; nil when
+// the chat is not tracked yet.
+func (d *DailyLossCircuit) Status(ctx context.Context, chatID string) (*DailyLossState, error) {
+	return d.load(ctx, chatID)
+}
+
+// dailyLossTransition is the outcome of one state update.
+type dailyLossTransition struct {
+	state    *DailyLossState
+	previous *DailyLossState
+	resumed  bool
+	breached bool
+}
+
+func (d *DailyLossCircuit) update(ctx context.Context, chatID string, realized decimal.Decimal) (*DailyLossState, error) {
+	transition, err := d.record(ctx, chatID, realized)
+	if err != nil {
+		return nil, err
+	}
+	state := transition.state
+
+	// Closing positions records their realized PnL through this circuit, so
+	// the flattener runs after the state lock is released.
+	if transition.breached && d.config.FlattenOnBreach && d.flatten != nil {
+		if err := d.flatten(ctx, chatID); err != nil {
+			d.logger.Error("Failed to flatten positions after daily loss breach", "chat_id", chatID, "error", err)
+		} else if flattened, err := d.markFlattened(ctx, chatID, state.Day); err != nil {
+			d.logger.Warn("Failed to record flattened positions", "chat_id", chatID, "error", err)
+			state.Flattened = true
+		} else {
+			state = flattened
+		}
+	}
+
+	if transition.resumed {
+		d.notify(ctx, transition.previous, false)
+	}
+	if transition.breached {
+		d.notify(ctx, state, true)
+	}
+	return state, nil
+}
+
+// record applies realized PnL and the current equity to the chat's day and
+// saves the state.
+func (d *DailyLossCircuit) record(ctx context.Context, chatID string, realized decimal.Decimal) (dailyLossTransition, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	previous, err := d.load(ctx, chatID)
+	if err != nil {
+		return dailyLossTransition{}, err
+	}
+
+	now := d.now().UTC()
+	today := now.Format(dailyLossDayFmt)
+	state := previous
+	resumed := false
+	if state == nil || state.Day != today {
+		resumed = state != nil && state.Halted
+		state = &DailyLossState{ChatID: chatID, Day: today}
+	}
+	state.RealizedPnL = state.RealizedPnL.Add(realized)
+
+	observedOpening := decimal.Zero
+	if d.equity != nil {
+		equity, err := d.equity.Equity(ctx, chatID)
+		if err != nil {
+			// Keep the last known equity; the next check retries.
+			d.logger.Warn("Failed to read equity for daily loss", "chat_id", chatID, "error", err)
+		} else {
+			state.Equity = equity
+			observedOpening = equity.Sub(state.RealizedPnL)
+		}
+	}
+	if !state.OpeningEquity.IsPositive() {
+		opening, err := d.openingEquity(ctx, chatID, today, observedOpening)
+		if err != nil {
+			return dailyLossTransition{}, err
+		}
+		state.OpeningEquity = opening
+	}
+	if state.OpeningEquity.IsPositive() && state.Equity.IsPositive() {
+		state.PnL = state.Equity.Sub(state.OpeningEquity)
+		state.UnrealizedPnL = state.PnL.Sub(state.RealizedPnL)
+	} else {
+		state.PnL = state.RealizedPnL
+		state.UnrealizedPnL = decimal.Zero
+	}
+	state.Limit = d.limit(ctx, chatID, state.OpeningEquity)
+	state.UpdatedAt = now
+
+	breached := !state.Halted && state.Limit.IsPositive() && state.PnL.Neg().GreaterThanOrEqual(state.Limit)
+	if breached {
+		state.Halted = true
+		state.HaltedAt = &now
+	}
+
+	if err := d.save(ctx, state); err != nil {
+		return dailyLossTransition{}, err
+	}
+	return dailyLossTransition{state: state, previous: previous, resumed: resumed, breached: breached}, nil
+}
+
+// openingEquity returns the chat's equity at the open of day. The first
+// positive observation of the day is stored and every later call returns it.
+func (d *DailyLossCircuit) openingEquity(ctx context.Context, chatID, day string, observed decimal.Decimal) (decimal.Decimal, error) {
+	key := fmt.Sprintf("%s:%s", dailyLossOpeningKeyBase, day)
+	pipe := d.redis.TxPipeline()
+	if observed.IsPositive() {
+		pipe.HSetNX(ctx, key, chatID, observed.String())
+		pipe.Expire(ctx, key, dailyLossOpeningTTL)
+	}
+	stored := pipe.HGet(ctx, key, chatID)
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return decimal.Zero, fmt.Errorf("failed to load opening equity: %w", err)
+	}
+	raw, err := stored.Result()
+	if err == redis.Nil {
+		return decimal.Zero, nil
+	}
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to load opening equity: %w", err)
+	}
+	opening, err := decimal.NewFromString(raw)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to decode opening equity: %w", err)
+	}
+	return opening, nil
+}
+
+// markFlattened records that the chat's positions were closed on day.
+func (d *DailyLossCircuit) markFlattened(ctx context.Context, chatID, day string) (*DailyLossState, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	state, err := d.load(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil || state.Day != day {
+		return nil, fmt.Errorf("daily loss state for %s changed while flattening", day)
+	}
+	state.Flattened = true
+	if err := d.save(ctx, state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// limit returns the loss that breaches the cap for a day that opened at the
+// given equity.
+func (d *DailyLossCircuit) limit(ctx context.Context, chatID string, opening decimal.Decimal) decimal.Decimal {
+	pct := d.config.MaxLossPct
+	if d.limits != nil {
+		if chatPct, ok := d.limits.DailyLossPct(ctx, chatID); ok && chatPct > 0 {
+			pct = chatPct
+		}
+	}
+	limit := decimal.Zero
+	if opening.IsPositive() {
+		limit = opening.Mul(decimal.NewFromFloat(pct))
+	}
+	if d.config.MaxLoss.IsPositive() && (limit.IsZero() || d.config.MaxLoss.LessThan(limit)) {
+		limit = d.config.MaxLoss
+	}
+	return limit
+}
+
+func (d *DailyLossCircuit) notify(ctx context.Context, state *DailyLossState, halted bool) {
+	data := map[string]interface{}{
+		"chat_id": state.ChatID,
+		"day":     state.Day,
+		"pnl":     state.PnL.StringFixed(2),
+		"limit":   state.Limit.StringFixed(2),
+	}
+	var text string
+	if halted {
+		d.logger.Warn("Daily loss cap breached, halting new entries", "chat_id", state.ChatID, "pnl", state.PnL.String(), "limit", state.Limit.String())
+		data["type"] = "daily_loss_halt"
+		data["flattened"] = state.Flattened
+		text = fmt.Sprintf("🛑 Daily loss cap reached: %s USDT today (cap %s USDT).\nNew entries are halted until 00:00 UTC.",
+			state.PnL.StringFixed(2), state.Limit.StringFixed(2))
+		if state.Flattened {
+			text += "\nOpen positions were closed."
+		}
+	} else {
+		d.logger.Info("UTC day rolled over, resuming entries", "chat_id", state.ChatID, "day", state.Day)
+		data["type"] = "daily_loss_resumed"
+		text = fmt.Sprintf("▶️ New UTC day: entries resumed after yesterday's daily loss halt (%s USDT).", state.PnL.StringFixed(2))
+	}
+
+	if d.events != nil {
+		d.events.Emit(ctx, WebhookEventRisk, data)
+	}
+	if d.messenger == nil {
+		return
+	}
+	chatID, err := strconv.ParseInt(state.ChatID, 10, 64)
+	if err != nil {
+		return
+	}
+	if err := d.messenger.SendDirectMessage(ctx, chatID, text); err != nil {
+		d.logger.Warn("Failed to send daily loss notification", "chat_id", chatID, "error", err)
+	}
+}
+
+func (d *DailyLossCircuit) load(ctx context.Context, chatID string) (*DailyLossState, error) {
+	raw, err := d.redis.HGet(ctx, dailyLossStateKey, chatID).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load daily loss state: %w", err)
+	}
+	var state DailyLossState
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		return nil, fmt.Errorf("failed to decode daily loss state: %w", err)
+	}
+	return &state, nil
+}
+
+func (d *DailyLossCircuit) save(ctx context.Context, state *DailyLossState) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if err := d.redis.HSet(ctx, dailyLossStateKey, state.ChatID, raw).Err(); err != nil {
+		return fmt.Errorf("failed to save daily loss state: %w", err)
+	}
+	return nil
+}
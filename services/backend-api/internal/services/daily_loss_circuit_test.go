@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/irfndi/neuratrade/pkg/interfaces"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEquitySource struct {
+	equity decimal.Decimal
+	err    error
+}
+
+func (f *fakeEquitySource) Equity(context.Context, string) (decimal.Decimal, error) {
+	return f.equity, f.err
+}
+
+func newTestDailyLossCircuit(t *testing.T, equity EquitySource, config DailyLossConfig) (*DailyLossCircuit, *recordingMessenger, *time.Time) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	circuit := NewDailyLossCircuit(client, equity, config)
+	messenger := &recordingMessenger{}
+	circuit.SetMessenger(messenger)
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	circuit.now = func() time.Time { return now }
+	return circuit, messenger, &now
+}
+
+func TestDailyLossCircuit_HaltsAndResumesAtRollover(t *testing.T) {
+	equity := &fakeEquitySource{equity: decimal.NewFromInt(1000)}
+	circuit, messenger, now := newTestDailyLossCircuit(t, equity, DailyLossConfig{})
+	flattened := 0
+	circuit.SetFlattener(func(context.Context, string) error {
+		flattened++
+		return nil
+	})
+	ctx := t.Context()
+
+	state, err := circuit.Evaluate(ctx, "42")
+	require.NoError(t, err)
+	assert.True(t, state.OpeningEquity.Equal(decimal.NewFromInt(1000)))
+	assert.True(t, state.Limit.Equal(decimal.NewFromInt(20)), "the default cap is 2% of the opening equity")
+
+	// A realized loss of 5 and an unrealized loss of 10 stay under the cap
+	equity.equity = decimal.NewFromInt(985)
+	state, err = circuit.RecordRealized(ctx, "42", decimal.NewFromInt(-5))
+	require.NoError(t, err)
+	assert.True(t, state.PnL.Equal(decimal.NewFromInt(-15)), state.PnL.String())
+	assert.True(t, state.UnrealizedPnL.Equal(decimal.NewFromInt(-10)), state.UnrealizedPnL.String())
+	assert.False(t, circuit.EntriesHalted(ctx, "42"))
+
+	equity.equity = decimal.NewFromInt(979)
+	assert.Equal(t, 1, circuit.CheckAll(ctx))
+	assert.True(t, circuit.EntriesHalted(ctx, "42"))
+	assert.Zero(t, flattened, "flattening is opt-in")
+	require.Len(t, messenger.texts, 1)
+	assert.Equal(t, int64(42), messenger.chats[0])
+	assert.Contains(t, messenger.texts[0], "Daily loss cap reached")
+
+	// Recovering during the day keeps the halt
+	equity.equity = decimal.NewFromInt(1000)
+	assert.True(t, circuit.EntriesHalted(ctx, "42"))
+	assert.Len(t, messenger.texts, 1)
+
+	*now = now.Add(15 * time.Hour)
+	assert.Zero(t, circuit.CheckAll(ctx))
+	state, err = circuit.Status(ctx, "42")
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-03", state.Day)
+	assert.True(t, state.OpeningEquity.Equal(decimal.NewFromInt(1000)))
+	assert.True(t, state.RealizedPnL.IsZero())
+	require.Len(t, messenger.texts, 2)
+	assert.Contains(t, messenger.texts[1], "entries resumed")
+}
+
+func TestDailyLossCircuit_AbsoluteCapAndFlatten(t *testing.T) {
+	equity := &fakeEquitySource{equity: decimal.NewFromInt(10000)}
+	circuit, _, _ := newTestDailyLossCircuit(t, equity, DailyLossConfig{
+		MaxLossPct:      0.05,
+		MaxLoss:         decimal.NewFromInt(50),
+		FlattenOnBreach: true,
+	})
+	var flattenedChat string
+	circuit.SetFlattener(func(_ context.Context, chatID string) error {
+		flattenedChat = chatID
+		return nil
+	})
+	ctx := t.Context()
+
+	_, err := circuit.Evaluate(ctx, "7")
+	require.NoError(t, err)
+	equity.equity = decimal.NewFromInt(9950)
+	state, err := circuit.Evaluate(ctx, "7")
+	require.NoError(t, err)
+	assert.True(t, state.Limit.Equal(decimal.NewFromInt(50)), "the lower absolute cap wins")
+	assert.True(t, state.Halted)
+	assert.True(t, state.Flattened)
+	assert.Equal(t, "7", flattenedChat)
+}
+
+func TestDailyLossCircuit_RealizedOnly(t *testing.T) {
+	circuit, _, _ := newTestDailyLossCircuit(t, nil, DailyLossConfig{MaxLoss: decimal.NewFromInt(30)})
+	ctx := t.Context()
+
+	state, err := circuit.RecordRealized(ctx, "42", decimal.NewFromInt(-20))
+	require.NoError(t, err)
+	assert.False(t, state.Halted)
+	state, err = circuit.RecordRealized(ctx, "42", decimal.NewFromInt(-10))
+	require.NoError(t, err)
+	assert.True(t, state.Halted)
+	assert.True(t, state.PnL.Equal(decimal.NewFromInt(-30)))
+	assert.True(t, state.Limit.Equal(decimal.NewFromInt(30)))
+
+	// Without equity or an absolute cap nothing can be enforced
+	circuit, _, _ = newTestDailyLossCircuit(t, &fakeEquitySource{err: errors.New("exchange down")}, DailyLossConfig{})
+	state, err = circuit.RecordRealized(ctx, "42", decimal.NewFromInt(-500))
+	require.NoError(t, err)
+	assert.False(t, state.Halted)
+	assert.True(t, state.Limit.IsZero())
+}
+
+func TestDailyLossCircuit_PreTradeHaltsAccountEntries(t *testing.T) {
+	circuit, _, now := newTestDailyLossCircuit(t, nil, DailyLossConfig{MaxLoss: decimal.NewFromInt(30)})
+	circuit.SetPositionSource(marginTestPositions{
+		{Exchange: "binance", Symbol: "BTC/USDT", Side: "long", Size: decimal.NewFromInt(1), Status: interfaces.PositionStatusOpen},
+	})
+	ctx := t.Context()
+	entry := HookOrder{Exchange: "binance", Symbol: "ETH/USDT", Side: "buy", Amount: decimal.NewFromInt(1)}
+	exit := HookOrder{Exchange: "binance", Symbol: "BTC/USDT", Side: "sell", Amount: decimal.NewFromInt(1)}
+
+	decision, err := circuit.PreTrade(ctx, entry)
+	require.NoError(t, err)
+	assert.False(t, decision.Veto)
+
+	// Closed positions count towards every tracked chat
+	_, err = circuit.Evaluate(ctx, "7")
+	require.NoError(t, err)
+	_, err = circuit.Evaluate(ctx, "42")
+	require.NoError(t, err)
+	require.NoError(t, circuit.RecordAccountRealized(ctx, decimal.NewFromInt(-30)))
+	assert.True(t, circuit.EntriesHalted(ctx, "7"))
+	assert.True(t, circuit.EntriesHalted(ctx, "42"))
+
+	decision, err = circuit.PreTrade(ctx, entry)
+	require.NoError(t, err)
+	assert.True(t, decision.Veto)
+	decision, err = circuit.PreTrade(ctx, exit)
+	require.NoError(t, err)
+	assert.False(t, decision.Veto, "closing a position is not an entry")
+
+	// Entries resume with the new UTC day
+	*now = now.Add(24 * time.Hour)
+	assert.False(t, circuit.AccountHalted(ctx))
+
+	// Without its state the circuit cannot vouch for the loss and fails closed
+	require.NoError(t, circuit.redis.Close())
+	assert.True(t, circuit.EntriesHalted(ctx, "42"))
+	assert.True(t, circuit.AccountHalted(ctx))
+	decision, err = circuit.PreTrade(ctx, entry)
+	require.NoError(t, err)
+	assert.True(t, decision.Veto)
+}
+
+type haltedGuard map[string]bool
+
+func (g haltedGuard) EntriesHalted(_ context.Context, chatID string) bool {
+	return g[chatID]
+}
+
+func TestIntegratedQuestHandlers_DailyLossHalt(t *testing.T) {
+	executor := &recordingOrderExecutor{}
+	handlers := NewIntegratedQuestHandlers(nil, &totalBalanceFetcher{total: map[string]float64{"USDT": 2000}}, nil, nil, nil, nil)
+	handlers.SetOrderExecutor(executor)
+	handlers.SetDailyLossGuard(haltedGuard{"42": true})
+
+	quest := &Quest{
+		Name:     "arbitrage",
+		Metadata: map[string]string{"chat_id": "42"},
+		Checkpoint: map[string]interface{}{
+			"symbol":        "ETH/USDT",
+			"buy_exchange":  "binance",
+			"sell_exchange": "okx",
+			"buy_price":     "100",
+			"sell_price":    "101",
+			"profit_pct":    "1",
+		},
+	}
+	require.NoError(t, handlers.handleArbitrageExecution(t.Context(), quest))
+	assert.Empty(t, executor.amounts)
+	assert.Equal(t, "daily_loss_halt", quest.Checkpoint["status"])
+
+	scalping := &Quest{Metadata: map[string]string{"chat_id": "42"}}
+	require.NoError(t, handlers.handleScalpingExecution(t.Context(), scalping))
+	assert.Equal(t, "daily_loss_halt", scalping.Checkpoint["status"])
+
+	// Other chats keep trading
+	quest.Metadata["chat_id"] = "7"
+	require.NoError(t, handlers.handleArbitrageExecution(t.Context(), quest))
+	assert.Len(t, executor.amounts, 2)
+}
+
+func TestDailyLossCircuit_FlattensWithoutHoldingStateLock(t *testing.T) {
+	equity := &fakeEquitySource{equity: decimal.NewFromInt(1000)}
+	circuit, _, _ := newTestDailyLossCircuit(t, equity, DailyLossConfig{FlattenOnBreach: true})
+	// Closing a position records its realized loss, which updates the state again
+	circuit.SetFlattener(func(ctx context.Context, _ string) error {
+		return circuit.RecordAccountRealized(ctx, decimal.NewFromInt(-25))
+	})
+	ctx := t.Context()
+
+	_, err := circuit.Evaluate(ctx, "42")
+	require.NoError(t, err)
+	equity.equity = decimal.NewFromInt(975)
+
+	done := make(chan *DailyLossState, 1)
+	go func() {
+		state, err := circuit.Evaluate(ctx, "42")
+		assert.NoError(t, err)
+		done <- state
+	}()
+	select {
+	case state := <-done:
+		require.NotNil(t, state)
+		assert.True(t, state.Halted)
+		assert.True(t, state.Flattened)
+		assert.True(t, state.RealizedPnL.Equal(decimal.NewFromInt(-25)), state.RealizedPnL.String())
+	case <-time.After(5 * time.Second):
+		t.Fatal("flattening deadlocked on the state lock")
+	}
+}
+
+func TestDailyLossCircuit_OpeningEquitySurvivesRestart(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	now := time.Date(2026, 3, 2, 0, 1, 0, 0, time.UTC)
+	newCircuit := func(equity EquitySource) *DailyLossCircuit {
+		circuit := NewDailyLossCircuit(client, equity, DailyLossConfig{})
+		circuit.now = func() time.Time { return now }
+		return circuit
+	}
+	ctx := t.Context()
+
+	state, err := newCircuit(&fakeEquitySource{equity: decimal.NewFromInt(1000)}).Evaluate(ctx, "42")
+	require.NoError(t, err)
+	assert.True(t, state.OpeningEquity.Equal(decimal.NewFromInt(1000)))
+
+	// The process restarts mid-day after losing money and its stored state
+	// is rewritten; the day keeps its original baseline
+	now = now.Add(6 * time.Hour)
+	require.NoError(t, client.HDel(ctx, dailyLossStateKey, "42").Err())
+	state, err = newCircuit(&fakeEquitySource{equity: decimal.NewFromInt(970)}).Evaluate(ctx, "42")
+	require.NoError(t, err)
+	assert.True(t, state.OpeningEquity.Equal(decimal.NewFromInt(1000)), state.OpeningEquity.String())
+	assert.True(t, state.PnL.Equal(decimal.NewFromInt(-30)), state.PnL.String())
+	assert.True(t, state.Halted)
+
+	// The next UTC day takes a new baseline
+	now = now.Add(24 * time.Hour)
+	state, err = newCircuit(&fakeEquitySource{equity: decimal.NewFromInt(970)}).Evaluate(ctx, "42")
+	require.NoError(t, err)
+	assert.True(t, state.OpeningEquity.Equal(decimal.NewFromInt(970)))
+	assert.False(t, state.Halted)
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+)
+
+const (
+	defaultPoolWaitThreshold  = 100 * time.Millisecond
+	defaultPoolSampleInterval = 15 * time.Second
+)
+
+// DBPoolMonitorConfig configures pool saturation detection.
+type DBPoolMonitorConfig struct {
+	// WaitThreshold is the average connection acquire time over one sample
+	// interval above which a pool is reported as saturated.
+	WaitThreshold time.Duration
+	// Interval is how often pool statistics are sampled.
+	Interval time.Duration
+}
+
+// DBPoolSample is the latest sample of one pool.
+type DBPoolSample struct {
+	database.PoolStats
+	// AvgWaitMs is the average acquire time over the last sample interval.
+	AvgWaitMs float64 `json:"avg_wait_ms"`
+	// Utilization is the share of MaxConns in use.
+	Utilization float64   `json:"utilization"`
+	Saturated   bool      `json:"saturated"`
+	SampledAt   time.Time `json:"sampled_at"`
+}
+
+// DBPoolMonitor samples connection pool statistics and raises a risk event
+// when a pool's average wait time crosses the threshold, and again once it
+// recovers.
+type DBPoolMonitor struct {
+	pools   database.PoolStatsProvider
+	config  DBPoolMonitorConfig
+	events  EventEmitter
+	logger  *slog.Logger
+	now     func() time.Time
+	mu      sync.Mutex
+	last    map[string]database.PoolStats
+	samples []DBPoolSample
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewDBPoolMonitor creates a pool monitor.
+//
+// Parameters:
+//
+//	pools: Source of pool statistics, such as a database.QueryRouter.
+//	config: Monitor configuration.
+//
+// Returns:
+//
+//	*DBPoolMonitor: Initialized monitor (sampling not started).
+func NewDBPoolMonitor(pools database.PoolStatsProvider, config DBPoolMonitorConfig) *DBPoolMonitor {
+	if config.WaitThreshold <= 0 {
+		config.WaitThreshold = defaultPoolWaitThreshold
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaultPoolSampleInterval
+	}
+	return &DBPoolMonitor{
+		pools:  pools,
+		config: config,
+		logger: telemetry.Logger(),
+		now:    time.Now,
+		last:   make(map[string]database.PoolStats),
+	}
+}
+
+// SetEventEmitter publishes saturation and recovery events, for example to
+// outbound webhooks and the event bus.
+func (m *DBPoolMonitor) SetEventEmitter(events EventEmitter) {
+	m.events = events
+}
+
+// WaitThreshold returns the configured saturation threshold.
+func (m *DBPoolMonitor) WaitThreshold() time.Duration {
+	return m.config.WaitThreshold
+}
+
+// Start samples the pools every interval until Stop is called.
+func (m *DBPoolMonitor) Start(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cancel != nil {
+		return fmt.Errorf("database pool monitor already running")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	m.cancel = cancel
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(m.config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.Sample(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops sampling and waits for the sampler to exit.
+func (m *DBPoolMonitor) Stop() {
+	m.mu.Lock()
+	cancel := m.cancel
+	m.cancel = nil
+	m.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		m.wg.Wait()
+	}
+}
+
+// Sample reads the current pool statistics, updates the latest samples and
+// emits an event for every pool whose saturation state changed.
+//
+// Parameters:
+//
+//	ctx: Context for emitted events.
+//
+// Returns:
+//
+//	[]DBPoolSample: The new samples.
+func (m *DBPoolMonitor) Sample(ctx context.Context) []DBPoolSample {
+	stats := m.pools.PoolStats()
+	now := m.now().UTC()
+
+	m.mu.Lock()
+	previous := make(map[string]bool, len(m.samples))
+	for _, sample := range m.samples {
+		previous[sample.Name] = sample.Saturated
+	}
+
+	samples := make([]DBPoolSample, 0, len(stats))
+	for _, stat := range stats {
+		sample := DBPoolSample{PoolStats: stat, SampledAt: now}
+		if stat.MaxConns > 0 {
+			sample.Utilization = float64(stat.InUseConns) / float64(stat.MaxConns)
+		}
+		last, seen := m.last[stat.Name]
+		if acquires := stat.AcquireCount - last.AcquireCount; seen && acquires > 0 {
+			avgWait := (stat.AcquireDuration - last.AcquireDuration) / time.Duration(acquires)
+			sample.AvgWaitMs = float64(avgWait) / float64(time.Millisecond)
+			sample.Saturated = avgWait > m.config.WaitThreshold
+		} else {
+			// No new acquires: keep the previous state rather than flapping.
+			sample.Saturated = previous[stat.Name]
+		}
+		m.last[stat.Name] = stat
+		samples = append(samples, sample)
+	}
+	m.samples = samples
+	m.mu.Unlock()
+
+	for _, sample := range samples {
+		if sample.Saturated != previous[sample.Name] {
+			m.emit(ctx, sample)
+		}
+	}
+	return samples
+}
+
+// Samples returns the latest sample of each pool.
+func (m *DBPoolMonitor) Samples() []DBPoolSample {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]DBPoolSample(nil), m.samples...)
+}
+
+func (m *DBPoolMonitor) emit(ctx context.Context, sample DBPoolSample) {
+	riskType := "db_pool_recovered"
+	if sample.Saturated {
+		riskType = "db_pool_saturated"
+		m.logger.Warn("Database pool saturated",
+			"pool", sample.Name,
+			"avg_wait_ms", sample.AvgWaitMs,
+			"in_use", sample.InUseConns,
+			"max_conns", sample.MaxConns)
+	} else {
+		m.logger.Info("Database pool recovered", "pool", sample.Name)
+	}
+
+	if m.events != nil {
+		m.events.Emit(ctx, WebhookEventRisk, map[string]interface{}{
+			"type":              riskType,
+			"pool":              sample.Name,
+			"avg_wait_ms":       sample.AvgWaitMs,
+			"wait_threshold_ms": float64(m.config.WaitThreshold) / float64(time.Millisecond),
+			"in_use_conns":      sample.InUseConns,
+			"max_conns":         sample.MaxConns,
+		})
+	}
+}
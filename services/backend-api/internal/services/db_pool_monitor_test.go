@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePoolStats struct {
+	stats []database.PoolStats
+}
+
+func (f *fakePoolStats) PoolStats() []database.PoolStats {
+	return append([]database.PoolStats(nil), f.stats...)
+}
+
+type poolEventRecorder struct {
+	payloads []map[string]interface{}
+}
+
+func (r *poolEventRecorder) Emit(_ context.Context, event WebhookEventType, data interface{}) {
+	if event == WebhookEventRisk {
+		r.payloads = append(r.payloads, data.(map[string]interface{}))
+	}
+}
+
+func TestDBPoolMonitor_SaturationAndRecovery(t *testing.T) {
+	pools := &fakePoolStats{stats: []database.PoolStats{
+		{Name: "api", MaxConns: 10, InUseConns: 2, AcquireCount: 100, AcquireDuration: 100 * time.Millisecond},
+		{Name: "collector", MaxConns: 4, InUseConns: 1, AcquireCount: 10, AcquireDuration: 10 * time.Millisecond},
+	}}
+	events := &poolEventRecorder{}
+	monitor := NewDBPoolMonitor(pools, DBPoolMonitorConfig{WaitThreshold: 50 * time.Millisecond})
+	monitor.SetEventEmitter(events)
+
+	// The first sample has no baseline, so nothing is saturated yet.
+	samples := monitor.Sample(t.Context())
+	require.Len(t, samples, 2)
+	assert.False(t, samples[0].Saturated)
+	assert.InDelta(t, 0.2, samples[0].Utilization, 0.0001)
+	assert.Empty(t, events.payloads)
+
+	// 10 new API acquires waited 2s in total: 200ms on average.
+	pools.stats[0].AcquireCount, pools.stats[0].AcquireDuration = 110, 2100*time.Millisecond
+	pools.stats[0].InUseConns = 10
+	pools.stats[1].AcquireCount, pools.stats[1].AcquireDuration = 20, 20*time.Millisecond
+	samples = monitor.Sample(t.Context())
+	assert.True(t, samples[0].Saturated)
+	assert.InDelta(t, 200, samples[0].AvgWaitMs, 0.0001)
+	assert.False(t, samples[1].Saturated)
+	require.Len(t, events.payloads, 1)
+	assert.Equal(t, "db_pool_saturated", events.payloads[0]["type"])
+	assert.Equal(t, "api", events.payloads[0]["pool"])
+
+	// Without new acquires the state holds and no event is repeated.
+	samples = monitor.Sample(t.Context())
+	assert.True(t, samples[0].Saturated)
+	assert.Len(t, events.payloads, 1)
+
+	pools.stats[0].AcquireCount, pools.stats[0].AcquireDuration = 120, 2110*time.Millisecond
+	monitor.Sample(t.Context())
+	require.Len(t, events.payloads, 2)
+	assert.Equal(t, "db_pool_recovered", events.payloads[1]["type"])
+	assert.False(t, monitor.Samples()[0].Saturated)
+}
+
+func TestDBPoolMonitor_StartStop(t *testing.T) {
+	pools := &fakePoolStats{stats: []database.PoolStats{{Name: "api", MaxConns: 10}}}
+	monitor := NewDBPoolMonitor(pools, DBPoolMonitorConfig{Interval: 5 * time.Millisecond})
+	assert.Equal(t, defaultPoolWaitThreshold, monitor.WaitThreshold())
+
+	require.NoError(t, monitor.Start(t.Context()))
+	assert.Error(t, monitor.Start(t.Context()))
+	assert.Eventually(t, func() bool { return len(monitor.Samples()) == 1 }, time.Second, 5*time.Millisecond)
+	monitor.Stop()
+	monitor.Stop()
+}
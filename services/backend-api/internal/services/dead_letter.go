@@ -29,6 +29,7 @@ type DeadLetterEntry struct {
 	ChatID         string           `json:"chat_id"`
 	MessageType    string           `json:"message_type"`
 	MessageContent string           `json:"message_content"`
+	Priority       string           `json:"priority"`
 	ErrorCode      string           `json:"error_code"`
 	ErrorMessage   string           `json:"error_message"`
 	Attempts       int              `json:"attempts"`
@@ -72,6 +73,7 @@ func NewDeadLetterService(db *database.PostgresDB) *DeadLetterService {
 //	chatID: The Telegram chat ID.
 //	messageType: Type of message (e.g., "arbitrage_alert", "technical_signal").
 //	messageContent: The message content that failed to send.
+//	priority: Notification priority, kept so a replay is delivered with the same urgency.
 //	errorCode: Error code from the sending attempt.
 //	errorMessage: Error message from the sending attempt.
 //
@@ -81,9 +83,12 @@ func NewDeadLetterService(db *database.PostgresDB) *DeadLetterService {
 //	error: Error if the operation fails.
 func (dls *DeadLetterService) AddToDeadLetter(
 	ctx context.Context,
-	userID, chatID, messageType, messageContent, errorCode, errorMessage string,
+	userID, chatID, messageType, messageContent string,
+	priority NotificationPriority,
+	errorCode, errorMessage string,
 ) (string, error) {
 	id := uuid.New().String()
+	priority = ParseNotificationPriority(string(priority))
 
 	// Calculate next retry time based on error type
 	var nextRetryAt *time.Time
@@ -95,12 +100,12 @@ func (dls *DeadLetterService) AddToDeadLetter(
 
 	query := `
 		INSERT INTO notification_dead_letters
-		(id, user_id, chat_id, message_type, message_content, error_code, error_message, attempts, status, next_retry_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, 1, 'pending', $8)
+		(id, user_id, chat_id, message_type, message_content, priority, error_code, error_message, attempts, status, next_retry_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, 1, 'pending', $9)
 	`
 
 	_, err := dls.db.Pool.Exec(ctx, query,
-		id, userID, chatID, messageType, messageContent, errorCode, errorMessage, nextRetryAt,
+		id, userID, chatID, messageType, messageContent, string(priority), errorCode, errorMessage, nextRetryAt,
 	)
 	if err != nil {
 		return "", fmt.Errorf("failed to insert dead letter entry: %w", err)
@@ -109,6 +114,7 @@ func (dls *DeadLetterService) AddToDeadLetter(
 	dls.logger.Info("Added message to dead letter queue",
 		"id", id,
 		"user_id", userID,
+		"priority", priority,
 		"error_code", errorCode,
 		"next_retry_at", nextRetryAt,
 	)
@@ -200,7 +206,7 @@ func (dls *DeadLetterService) UpdateDeadLetter(
 	return nil
 }
 
-// GetPendingMessages retrieves messages ready for retry
+// GetPendingMessages retrieves messages ready for retry, critical ones first
 //
 // Parameters:
 //
@@ -213,13 +219,13 @@ func (dls *DeadLetterService) UpdateDeadLetter(
 //	error: Error if the operation fails.
 func (dls *DeadLetterService) GetPendingMessages(ctx context.Context, limit int) ([]DeadLetterEntry, error) {
 	query := `
-		SELECT id, user_id, chat_id, message_type, message_content,
+		SELECT id, user_id, chat_id, message_type, message_content, priority,
 		       COALESCE(error_code, ''), COALESCE(error_message, ''),
 		       attempts, status, created_at, last_attempt_at, next_retry_at
 		FROM notification_dead_letters
 		WHERE status IN ('pending', 'retrying')
 		  AND (next_retry_at IS NULL OR next_retry_at <= NOW())
-		ORDER BY created_at ASC
+		ORDER BY (priority = 'critical') DESC, created_at ASC
 		LIMIT $1
 	`
 
@@ -234,7 +240,60 @@ func (dls *DeadLetterService) GetPendingMessages(ctx context.Context, limit int)
 		var entry DeadLetterEntry
 		err := rows.Scan(
 			&entry.ID, &entry.UserID, &entry.ChatID, &entry.MessageType,
-			&entry.MessageContent, &entry.ErrorCode, &entry.ErrorMessage,
+			&entry.MessageContent, &entry.Priority, &entry.ErrorCode, &entry.ErrorMessage,
+			&entry.Attempts, &entry.Status, &entry.CreatedAt,
+			&entry.LastAttemptAt, &entry.NextRetryAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan dead letter entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// GetReplayCandidates retrieves entries eligible for a manual replay,
+// ignoring their scheduled retry time, critical ones first.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	includeFailed: Whether to include entries that exhausted their retries.
+//	limit: Maximum number of entries to retrieve.
+//
+// Returns:
+//
+//	[]DeadLetterEntry: List of replayable entries.
+//	error: Error if the operation fails.
+func (dls *DeadLetterService) GetReplayCandidates(ctx context.Context, includeFailed bool, limit int) ([]DeadLetterEntry, error) {
+	statuses := []string{string(DeadLetterStatusPending)}
+	if includeFailed {
+		statuses = append(statuses, string(DeadLetterStatusFailed))
+	}
+
+	query := `
+		SELECT id, user_id, chat_id, message_type, message_content, priority,
+		       COALESCE(error_code, ''), COALESCE(error_message, ''),
+		       attempts, status, created_at, last_attempt_at, next_retry_at
+		FROM notification_dead_letters
+		WHERE status = ANY($1)
+		ORDER BY (priority = 'critical') DESC, created_at ASC
+		LIMIT $2
+	`
+
+	rows, err := dls.db.Pool.Query(ctx, query, statuses, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query replay candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []DeadLetterEntry
+	for rows.Next() {
+		var entry DeadLetterEntry
+		err := rows.Scan(
+			&entry.ID, &entry.UserID, &entry.ChatID, &entry.MessageType,
+			&entry.MessageContent, &entry.Priority, &entry.ErrorCode, &entry.ErrorMessage,
 			&entry.Attempts, &entry.Status, &entry.CreatedAt,
 			&entry.LastAttemptAt, &entry.NextRetryAt,
 		)
@@ -408,7 +467,7 @@ func (dls *DeadLetterService) CleanupOldEntries(ctx context.Context, olderThan t
 //	error: Error if the operation fails.
 func (dls *DeadLetterService) GetUserDeadLetters(ctx context.Context, userID string, limit int) ([]DeadLetterEntry, error) {
 	query := `
-		SELECT id, user_id, chat_id, message_type, message_content,
+		SELECT id, user_id, chat_id, message_type, message_content, priority,
 		       COALESCE(error_code, ''), COALESCE(error_message, ''),
 		       attempts, status, created_at, last_attempt_at, next_retry_at
 		FROM notification_dead_letters
@@ -428,7 +487,7 @@ func (dls *DeadLetterService) GetUserDeadLetters(ctx context.Context, userID str
 		var entry DeadLetterEntry
 		err := rows.Scan(
 			&entry.ID, &entry.UserID, &entry.ChatID, &entry.MessageType,
-			&entry.MessageContent, &entry.ErrorCode, &entry.ErrorMessage,
+			&entry.MessageContent, &entry.Priority, &entry.ErrorCode, &entry.ErrorMessage,
 			&entry.Attempts, &entry.Status, &entry.CreatedAt,
 			&entry.LastAttemptAt, &entry.NextRetryAt,
 		)
@@ -454,7 +513,7 @@ func (dls *DeadLetterService) GetUserDeadLetters(ctx context.Context, userID str
 //	error: Error if the operation fails.
 func (dls *DeadLetterService) ExportForAnalysis(ctx context.Context, limit int) ([]byte, error) {
 	query := `
-		SELECT id, user_id, chat_id, message_type, message_content,
+		SELECT id, user_id, chat_id, message_type, message_content, priority,
 		       COALESCE(error_code, ''), COALESCE(error_message, ''),
 		       attempts, status, created_at, last_attempt_at, next_retry_at
 		FROM notification_dead_letters
@@ -473,7 +532,7 @@ func (dls *DeadLetterService) ExportForAnalysis(ctx context.Context, limit int)
 		var entry DeadLetterEntry
 		err := rows.Scan(
 			&entry.ID, &entry.UserID, &entry.ChatID, &entry.MessageType,
-			&entry.MessageContent, &entry.ErrorCode, &entry.ErrorMessage,
+			&entry.MessageContent, &entry.Priority, &entry.ErrorCode, &entry.ErrorMessage,
 			&entry.Attempts, &entry.Status, &entry.CreatedAt,
 			&entry.LastAttemptAt, &entry.NextRetryAt,
 		)
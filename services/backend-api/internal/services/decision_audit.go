@@ -0,0 +1,457 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/irfndi/neuratrade/internal/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+// Decision sources recorded in the audit trail.
+const (
+	DecisionSourceAIScalping     = "ai_scalping"
+	DecisionSourceExternalSignal = "external_signal"
+)
+
+// Order statuses recorded with a decision.
+const (
+	DecisionOrderPlaced  = "placed"
+	DecisionOrderFailed  = "failed"
+	DecisionOrderSkipped = "skipped"
+)
+
+// ErrDecisionNotFound is returned when no decision has the requested ID.
+var ErrDecisionNotFound = errors.New("decision not found")
+
+var (
+	promptBalancePattern = regexp.MustCompile(`(?i)((?:USDT Balance|Total Value|Unrealized PnL):\s*)-?[\d.,]+`)
+	promptBearerPattern  = regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9._~+/=-]+`)
+	promptAPIKeyPattern  = regexp.MustCompile(`\b(?:sk|pk|rk)-[A-Za-z0-9_-]{8,}`)
+)
+
+// DecisionRecorder stores the full context of trading decisions. It is
+// implemented by DecisionAuditService.
+type DecisionRecorder interface {
+	Record(ctx context.Context, audit *DecisionAudit) error
+}
+
+// DecisionAuditOrder is the order that resulted from a decision.
+type DecisionAuditOrder struct {
+	Status  string          `json:"status"`
+	OrderID string          `json:"order_id,omitempty"`
+	Side    string          `json:"side,omitempty"`
+	Amount  decimal.Decimal `json:"amount"`
+	Price   decimal.Decimal `json:"price"`
+	// Error is why the order failed or was skipped.
+	Error string `json:"error,omitempty"`
+}
+
+// DecisionOutcome is how a decision played out. A recorded outcome is
+// realized; without one, buys and sells are marked to the current price when
+// the decision is read.
+type DecisionOutcome struct {
+	Status     string    `json:"status"`
+	EntryPrice float64   `json:"entry_price"`
+	ExitPrice  float64   `json:"exit_price"`
+	ReturnPct  float64   `json:"return_pct"`
+	PnL        *float64  `json:"pnl,omitempty"`
+	Note       string    `json:"note,omitempty"`
+	MeasuredAt time.Time `json:"measured_at"`
+}
+
+// Decision outcome statuses.
+const (
+	DecisionOutcomeRealized     = "realized"
+	DecisionOutcomeMarkToMarket = "mark_to_market"
+)
+
+// DecisionAudit is the full context of one AI or deterministic decision.
+type DecisionAudit struct {
+	ID         string  `json:"id"`
+	Source     string  `json:"source"`
+	Exchange   string  `json:"exchange"`
+	Symbol     string  `json:"symbol"`
+	Action     string  `json:"action"`
+	Confidence float64 `json:"confidence"`
+	Reasoning  string  `json:"reasoning"`
+	// Price is the symbol's market price when the decision was made.
+	Price float64 `json:"price"`
+	// MarketSnapshot is the market data the decision was based on.
+	MarketSnapshot json.RawMessage `json:"market_snapshot,omitempty"`
+	// Prompt is the redacted prompt of AI decisions; balances and credentials
+	// are removed before it is stored.
+	Prompt    string `json:"prompt,omitempty"`
+	Model     string `json:"model,omitempty"`
+	RawOutput string `json:"raw_output,omitempty"`
+	// ConfigHash, StrategyHash and PromptVersion fingerprint the state the
+	// decision was made under, with Model.
+	ConfigHash    string `json:"config_hash,omitempty"`
+	StrategyHash  string `json:"strategy_hash,omitempty"`
+	PromptVersion string `json:"prompt_version,omitempty"`
+	// Decision is the decision as the strategy produced it.
+	Decision  json.RawMessage     `json:"decision,omitempty"`
+	Order     *DecisionAuditOrder `json:"order,omitempty"`
+	Outcome   *DecisionOutcome    `json:"outcome,omitempty"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// DecisionAuditService persists decisions for replay and post-mortems.
+type DecisionAuditService struct {
+	db     DBPool
+	prices TickerFetcher
+	index  DecisionIndexer
+	chain  AuditChainAppender
+	state  StateStamper
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// Ensure DecisionAuditService implements DecisionRecorder and AuditRecordSource.
+var (
+	_ DecisionRecorder  = (*DecisionAuditService)(nil)
+	_ AuditRecordSource = (*DecisionAuditService)(nil)
+)
+
+// NewDecisionAuditService creates a decision audit service.
+//
+// Parameters:
+//
+//	db: Database pool holding the decision_audits table.
+//	prices: Ticker source used to mark open decisions to market (optional).
+//
+// Returns:
+//
+//	*DecisionAuditService: Initialized service.
+func NewDecisionAuditService(db DBPool, prices TickerFetcher) *DecisionAuditService {
+	return &DecisionAuditService{
+		db:     db,
+		prices: prices,
+		logger: telemetry.Logger(),
+		now:    time.Now,
+	}
+}
+
+// SetVectorIndex embeds every recorded decision and its realized outcome for
+// similarity search.
+func (s *DecisionAuditService) SetVectorIndex(index DecisionIndexer) {
+	s.index = index
+}
+
+// SetAuditChain appends every recorded decision and realized outcome to the
+// tamper-evident audit chain.
+func (s *DecisionAuditService) SetAuditChain(chain AuditChainAppender) {
+	s.chain = chain
+}
+
+// SetStateStamper fingerprints the config and strategy parameters every
+// decision is recorded under.
+func (s *DecisionAuditService) SetStateStamper(state StateStamper) {
+	s.state = state
+}
+
+// Record stores a decision, assigning its ID and redacting its prompt.
+//
+// Parameters:
+//
+//	ctx: Context for the insert.
+//	audit: The decision; ID, CreatedAt and UpdatedAt are set on success.
+//
+// Returns:
+//
+//	error: Error if the decision could not be stored.
+func (s *DecisionAuditService) Record(ctx context.Context, audit *DecisionAudit) error {
+	id, err := generateID()
+	if err != nil {
+		return err
+	}
+	// Microseconds, as stored, so the chained payload matches the row
+	now := s.now().UTC().Truncate(time.Microsecond)
+	audit.ID = "dec_" + id
+	audit.CreatedAt = now
+	audit.UpdatedAt = now
+	audit.Prompt = redactPrompt(audit.Prompt)
+	if s.state != nil {
+		if err := s.state.Stamp(ctx, audit); err != nil {
+			s.logger.Warn("Failed to fingerprint decision state", "decision_id", audit.ID, "error", err)
+		}
+	}
+
+	var order, outcome []byte
+	if audit.Order != nil {
+		if order, err = json.Marshal(audit.Order); err != nil {
+			return fmt.Errorf("marshal order: %w", err)
+		}
+	}
+	if audit.Outcome != nil {
+		if outcome, err = json.Marshal(audit.Outcome); err != nil {
+			return fmt.Errorf("marshal outcome: %w", err)
+		}
+	}
+
+	_, err = s.db.Exec(ctx, `
+		INSERT INTO decision_audits (
+			id, source, exchange, symbol, action, confidence, reasoning, price,
+			market_snapshot, prompt, model, raw_output, decision, order_details, outcome,
+			config_hash, strategy_hash, prompt_version, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)`,
+		audit.ID, audit.Source, audit.Exchange, audit.Symbol, audit.Action, audit.Confidence,
+		audit.Reasoning, audit.Price, nullableJSON(audit.MarketSnapshot), audit.Prompt, audit.Model,
+		audit.RawOutput, nullableJSON(audit.Decision), order, outcome,
+		audit.ConfigHash, audit.StrategyHash, audit.PromptVersion, audit.CreatedAt, audit.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record decision: %w", err)
+	}
+	if payload, err := decisionChainPayload(audit); err != nil {
+		s.logger.Error("Failed to encode decision for the audit chain", "decision_id", audit.ID, "error", err)
+	} else {
+		s.appendToChain(ctx, AuditKindDecision, audit.ID, payload)
+	}
+	s.indexDecision(ctx, audit)
+	return nil
+}
+
+// RecordOutcome attaches the realized outcome of a decision.
+//
+// Parameters:
+//
+//	ctx: Context for the update.
+//	id: The decision ID.
+//	outcome: The outcome; its status is set to realized.
+//
+// Returns:
+//
+//	error: ErrDecisionNotFound if no decision has the ID.
+func (s *DecisionAuditService) RecordOutcome(ctx context.Context, id string, outcome DecisionOutcome) error {
+	outcome.Status = DecisionOutcomeRealized
+	if outcome.MeasuredAt.IsZero() {
+		outcome.MeasuredAt = s.now().UTC()
+	}
+	raw, err := json.Marshal(outcome)
+	if err != nil {
+		return fmt.Errorf("marshal outcome: %w", err)
+	}
+	result, err := s.db.Exec(ctx,
+		`UPDATE decision_audits SET outcome = $1, updated_at = $2 WHERE id = $3`,
+		raw, s.now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to record decision outcome: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrDecisionNotFound
+	}
+	s.appendToChain(ctx, AuditKindDecisionOutcome, id, raw)
+	if s.index != nil {
+		if audit, err := s.Get(ctx, id); err == nil {
+			s.indexDecision(ctx, audit)
+		}
+	}
+	return nil
+}
+
+// appendToChain links a stored record to the audit chain. Failures are
+// logged: the record is stored either way and verification reports it.
+func (s *DecisionAuditService) appendToChain(ctx context.Context, kind, id string, payload []byte) {
+	if s.chain == nil {
+		return
+	}
+	if _, err := s.chain.Append(ctx, kind, id, payload); err != nil {
+		s.logger.Error("Failed to append to audit chain", "kind", kind, "decision_id", id, "error", err)
+	}
+}
+
+// ChainPayload returns the current form of a chained decision or outcome,
+// encoded as it was when chained.
+//
+// Parameters:
+//
+//	ctx: Context for the lookup.
+//	kind: AuditKindDecision or AuditKindDecisionOutcome.
+//	refID: The decision ID.
+//
+// Returns:
+//
+//	[]byte: The payload; nil for a decision without a realized outcome.
+//	error: ErrAuditRecordNotFound if no decision has the ID.
+func (s *DecisionAuditService) ChainPayload(ctx context.Context, kind, refID string) ([]byte, error) {
+	audit, err := s.load(ctx, refID)
+	if errors.Is(err, ErrDecisionNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrAuditRecordNotFound, refID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case AuditKindDecision:
+		return decisionChainPayload(audit)
+	case AuditKindDecisionOutcome:
+		if audit.Outcome == nil {
+			return nil, nil
+		}
+		return json.Marshal(audit.Outcome)
+	}
+	return nil, fmt.Errorf("unsupported audit record kind %q", kind)
+}
+
+// indexDecision adds a decision to the vector index. Failures are logged:
+// the audit record is stored either way.
+func (s *DecisionAuditService) indexDecision(ctx context.Context, audit *DecisionAudit) {
+	if s.index == nil {
+		return
+	}
+	if err := s.index.IndexDecision(ctx, audit); err != nil {
+		s.logger.Warn("Failed to index decision", "decision_id", audit.ID, "error", err)
+	}
+}
+
+// Get returns a decision with its order and outcome. Buys and sells without
+// a realized outcome are marked to the current price.
+//
+// Parameters:
+//
+//	ctx: Context for the lookup.
+//	id: The decision ID.
+//
+// Returns:
+//
+//	*DecisionAudit: The decision.
+//	error: ErrDecisionNotFound if no decision has the ID.
+func (s *DecisionAuditService) Get(ctx context.Context, id string) (*DecisionAudit, error) {
+	audit, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if audit.Outcome == nil {
+		audit.Outcome = s.markToMarket(ctx, audit)
+	}
+	return audit, nil
+}
+
+// load reads a decision as stored.
+func (s *DecisionAuditService) load(ctx context.Context, id string) (*DecisionAudit, error) {
+	var audit DecisionAudit
+	var snapshot, decision, order, outcome []byte
+	err := s.db.QueryRow(ctx, `
+		SELECT id, source, exchange, symbol, action, confidence, reasoning, price,
+			market_snapshot, prompt, model, raw_output, decision, order_details, outcome,
+			config_hash, strategy_hash, prompt_version, created_at, updated_at
+		FROM decision_audits
+		WHERE id = $1`, id).Scan(
+		&audit.ID, &audit.Source, &audit.Exchange, &audit.Symbol, &audit.Action,
+		&audit.Confidence, &audit.Reasoning, &audit.Price, &snapshot, &audit.Prompt,
+		&audit.Model, &audit.RawOutput, &decision, &order, &outcome,
+		&audit.ConfigHash, &audit.StrategyHash, &audit.PromptVersion,
+		&audit.CreatedAt, &audit.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDecisionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get decision: %w", err)
+	}
+	audit.MarketSnapshot = json.RawMessage(snapshot)
+	audit.Decision = json.RawMessage(decision)
+	if len(order) > 0 {
+		if err := json.Unmarshal(order, &audit.Order); err != nil {
+			return nil, fmt.Errorf("decode order: %w", err)
+		}
+	}
+	if len(outcome) > 0 {
+		if err := json.Unmarshal(outcome, &audit.Outcome); err != nil {
+			return nil, fmt.Errorf("decode outcome: %w", err)
+		}
+	}
+	return &audit, nil
+}
+
+// markToMarket measures an unrealized buy or sell against the current price.
+func (s *DecisionAuditService) markToMarket(ctx context.Context, audit *DecisionAudit) *DecisionOutcome {
+	action := strings.ToLower(audit.Action)
+	if s.prices == nil || audit.Price <= 0 || (action != "buy" && action != "sell") {
+		return nil
+	}
+	ticker, err := s.prices.FetchSingleTicker(ctx, audit.Exchange, audit.Symbol)
+	if err != nil || ticker.GetPrice() <= 0 {
+		s.logger.Debug("Cannot mark decision to market", "decision_id", audit.ID, "error", err)
+		return nil
+	}
+	current := ticker.GetPrice()
+	returnPct := (current - audit.Price) / audit.Price * 100
+	if action == "sell" {
+		returnPct = -returnPct
+	}
+	return &DecisionOutcome{
+		Status:     DecisionOutcomeMarkToMarket,
+		EntryPrice: audit.Price,
+		ExitPrice:  current,
+		ReturnPct:  returnPct,
+		MeasuredAt: s.now().UTC(),
+	}
+}
+
+// skipOrder records why a decision did not lead to an order, unless an order
+// was already attempted. It is a no-op on a nil audit.
+func (a *DecisionAudit) skipOrder(reason string) {
+	if a == nil || a.Order != nil {
+		return
+	}
+	a.Order = &DecisionAuditOrder{Status: DecisionOrderSkipped, Error: reason}
+}
+
+// redactPrompt removes account balances and credential-like values from a
+// prompt before it is stored.
+func redactPrompt(prompt string) string {
+	if prompt == "" {
+		return ""
+	}
+	prompt = promptBalancePattern.ReplaceAllString(prompt, "${1}[redacted]")
+	prompt = promptBearerPattern.ReplaceAllString(prompt, "${1}[redacted]")
+	prompt = promptAPIKeyPattern.ReplaceAllString(prompt, "[redacted]")
+	return utils.MaskJSON(prompt, nil)
+}
+
+// decisionChainPayload encodes the immutable part of a decision for the
+// audit chain: the outcome is chained separately and the JSONB columns are
+// normalized, since the database does not keep their key order.
+func decisionChainPayload(audit *DecisionAudit) ([]byte, error) {
+	chained := *audit
+	chained.Outcome = nil
+	chained.UpdatedAt = time.Time{}
+	var err error
+	if chained.MarketSnapshot, err = normalizeJSON(audit.MarketSnapshot); err != nil {
+		return nil, fmt.Errorf("normalize market snapshot: %w", err)
+	}
+	if chained.Decision, err = normalizeJSON(audit.Decision); err != nil {
+		return nil, fmt.Errorf("normalize decision: %w", err)
+	}
+	return json.Marshal(chained)
+}
+
+// normalizeJSON re-encodes JSON with sorted keys.
+func normalizeJSON(raw json.RawMessage) (json.RawMessage, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+func nullableJSON(raw json.RawMessage) []byte {
+	if len(raw) == 0 {
+		return nil
+	}
+	return raw
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingDecisions struct {
+	audits []*DecisionAudit
+	err    error
+}
+
+func (r *recordingDecisions) Record(_ context.Context, audit *DecisionAudit) error {
+	if r.err != nil {
+		return r.err
+	}
+	audit.ID = "dec_test"
+	r.audits = append(r.audits, audit)
+	return nil
+}
+
+var decisionAuditColumns = []string{
+	"id", "source", "exchange", "symbol", "action", "confidence", "reasoning", "price",
+	"market_snapshot", "prompt", "model", "raw_output", "decision", "order_details", "outcome",
+	"config_hash", "strategy_hash", "prompt_version", "created_at", "updated_at",
+}
+
+func TestDecisionAuditService_RecordAndGet(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	prices := &fakeTickerFetcher{prices: map[string]float64{"binance:BTC/USDT": 99}}
+	svc := NewDecisionAuditService(database.NewMockDBPool(mockPool), prices)
+	svc.now = func() time.Time { return now }
+
+	mockPool.ExpectExec("INSERT INTO decision_audits").
+		WithArgs(pgxmock.AnyArg(), DecisionSourceAIScalping, "binance", "BTC/USDT", "sell", 0.8,
+			"momentum fading", 100.0, pgxmock.AnyArg(), pgxmock.AnyArg(), "gpt-4o", `{"action":"sell"}`,
+			pgxmock.AnyArg(), pgxmock.AnyArg(), []byte(nil), "", "", "", now, now).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	audit := &DecisionAudit{
+		Source:     DecisionSourceAIScalping,
+		Exchange:   "binance",
+		Symbol:     "BTC/USDT",
+		Action:     "sell",
+		Confidence: 0.8,
+		Reasoning:  "momentum fading",
+		Price:      100,
+		Prompt:     "## Portfolio\n- USDT Balance: 1234.56\n- Total Value: 2000.00\nAuthorization: Bearer abc.def",
+		Model:      "gpt-4o",
+		RawOutput:  `{"action":"sell"}`,
+		Order:      &DecisionAuditOrder{Status: DecisionOrderPlaced, OrderID: "ord-1", Side: "sell", Amount: decimal.NewFromInt(50)},
+	}
+	require.NoError(t, svc.Record(t.Context(), audit))
+	assert.Regexp(t, `^dec_[0-9a-f]{16}$`, audit.ID)
+	assert.Equal(t, "## Portfolio\n- USDT Balance: [redacted]\n- Total Value: [redacted]\nAuthorization: Bearer [redacted]", audit.Prompt)
+
+	mockPool.ExpectQuery("SELECT id, source").
+		WithArgs(audit.ID).
+		WillReturnRows(pgxmock.NewRows(decisionAuditColumns).AddRow(
+			audit.ID, audit.Source, audit.Exchange, audit.Symbol, audit.Action, audit.Confidence,
+			audit.Reasoning, audit.Price, []byte(`[{"symbol":"BTC/USDT"}]`), audit.Prompt, audit.Model,
+			audit.RawOutput, []byte(`{"action":"sell"}`), []byte(`{"status":"placed","order_id":"ord-1"}`), []byte(nil),
+			"", "", "", now, now))
+	stored, err := svc.Get(t.Context(), audit.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "ord-1", stored.Order.OrderID)
+	assert.JSONEq(t, `[{"symbol":"BTC/USDT"}]`, string(stored.MarketSnapshot))
+	require.NotNil(t, stored.Outcome)
+	assert.Equal(t, DecisionOutcomeMarkToMarket, stored.Outcome.Status)
+	assert.InDelta(t, 1.0, stored.Outcome.ReturnPct, 1e-9, "a sell gains when the price falls")
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestDecisionAuditService_NotFound(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	svc := NewDecisionAuditService(database.NewMockDBPool(mockPool), nil)
+
+	mockPool.ExpectQuery("SELECT id, source").
+		WithArgs("dec_missing").
+		WillReturnRows(pgxmock.NewRows(decisionAuditColumns))
+	_, err = svc.Get(t.Context(), "dec_missing")
+	assert.ErrorIs(t, err, ErrDecisionNotFound)
+
+	mockPool.ExpectExec("UPDATE decision_audits SET outcome").
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), "dec_missing").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	err = svc.RecordOutcome(t.Context(), "dec_missing", DecisionOutcome{ExitPrice: 101})
+	assert.ErrorIs(t, err, ErrDecisionNotFound)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestRedactPrompt(t *testing.T) {
+	assert.Equal(t, "", redactPrompt(""))
+	assert.Equal(t, "key [redacted] used", redactPrompt("key sk-abcdefghijkl used"))
+	assert.Equal(t, `{"api_key": "se****et"}`, redactPrompt(`{"api_key": "se1234et"}`))
+}
+
+func TestExternalSignalService_RecordsDecisions(t *testing.T) {
+	decisions := &recordingDecisions{}
+	placer := &recordingPlacer{}
+	svc := NewExternalSignalService(ExternalSignalConfig{Secret: "s", AutoExecute: true}, nil, placer)
+	svc.SetDecisionRecorder(decisions)
+	alert := TradingViewAlert{Secret: "s", Passphrase: "p", Ticker: "BTCUSDT", Action: "buy", Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(100), Strategy: "breakout"}
+
+	result, err := svc.IngestTradingView(t.Context(), alert)
+	require.NoError(t, err)
+	assert.Equal(t, "dec_test", result.DecisionID)
+	require.Len(t, decisions.audits, 1)
+	audit := decisions.audits[0]
+	assert.Equal(t, DecisionSourceExternalSignal, audit.Source)
+	assert.Equal(t, "TradingView alert buy BTCUSDT from strategy breakout", audit.Reasoning)
+	assert.Equal(t, DecisionOrderPlaced, audit.Order.Status)
+	assert.Equal(t, "ord-1", audit.Order.OrderID)
+	assert.NotContains(t, string(audit.MarketSnapshot), `"secret":"s"`)
+	assert.NotContains(t, string(audit.MarketSnapshot), `"passphrase":"p"`)
+
+	placer.err = errors.New("exchange rejected")
+	result, err = svc.IngestTradingView(t.Context(), alert)
+	require.NoError(t, err)
+	assert.False(t, result.Executed)
+	assert.Equal(t, DecisionOrderFailed, decisions.audits[1].Order.Status)
+	assert.Equal(t, "exchange rejected", decisions.audits[1].Order.Error)
+
+	// A failing audit store does not block ingestion.
+	decisions.err = errors.New("db down")
+	result, err = svc.IngestTradingView(t.Context(), TradingViewAlert{Ticker: "BTCUSDT", Action: "close"})
+	require.NoError(t, err)
+	assert.Empty(t, result.DecisionID)
+}
+
+type recordingAuditChain struct {
+	kinds    []string
+	payloads [][]byte
+}
+
+func (r *recordingAuditChain) Append(_ context.Context, kind, refID string, payload []byte) (*AuditChainEntry, error) {
+	r.kinds = append(r.kinds, kind)
+	r.payloads = append(r.payloads, payload)
+	return &AuditChainEntry{Kind: kind, RefID: refID, Payload: string(payload)}, nil
+}
+
+func TestDecisionAuditService_ChainPayloadMatchesStoredRow(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 123456789, time.UTC)
+	svc := NewDecisionAuditService(database.NewMockDBPool(mockPool), nil)
+	svc.now = func() time.Time { return now }
+	chain := &recordingAuditChain{}
+	svc.SetAuditChain(chain)
+
+	mockPool.ExpectExec("INSERT INTO decision_audits").
+		WithArgs(pgxmock.AnyArg(), DecisionSourceExternalSignal, "binance", "BTC/USDT", "buy", 0.0, "", 100.0,
+			pgxmock.AnyArg(), "", "", "", []byte(nil), pgxmock.AnyArg(), []byte(nil), "", "", "",
+			now.Truncate(time.Microsecond), now.Truncate(time.Microsecond)).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	audit := &DecisionAudit{
+		Source:         DecisionSourceExternalSignal,
+		Exchange:       "binance",
+		Symbol:         "BTC/USDT",
+		Action:         "buy",
+		Price:          100,
+		MarketSnapshot: []byte(`{"symbol": "BTC/USDT", "bid": 99.5}`),
+		Order:          &DecisionAuditOrder{Status: DecisionOrderPlaced, OrderID: "ord-1", Amount: decimal.RequireFromString("0.50")},
+	}
+	require.NoError(t, svc.Record(t.Context(), audit))
+
+	mockPool.ExpectExec("UPDATE decision_audits SET outcome").
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	require.NoError(t, svc.RecordOutcome(t.Context(), audit.ID, DecisionOutcome{EntryPrice: 100, ExitPrice: 101, ReturnPct: 1}))
+	require.Equal(t, []string{AuditKindDecision, AuditKindDecisionOutcome}, chain.kinds)
+
+	// JSONB reorders keys and the row comes back with microsecond timestamps
+	storedRow := func() *pgxmock.Rows {
+		return pgxmock.NewRows(decisionAuditColumns).AddRow(
+			audit.ID, audit.Source, audit.Exchange, audit.Symbol, audit.Action, 0.0,
+			"", 100.0, []byte(`{"bid": 99.5, "symbol": "BTC/USDT"}`), "", "",
+			"", []byte(nil), []byte(`{"amount": "0.5", "status": "placed", "order_id": "ord-1"}`), chain.payloads[1],
+			"", "", "", now.Truncate(time.Microsecond), now)
+	}
+	mockPool.ExpectQuery("SELECT id, source").WithArgs(audit.ID).WillReturnRows(storedRow())
+	payload, err := svc.ChainPayload(t.Context(), AuditKindDecision, audit.ID)
+	require.NoError(t, err)
+	assert.Equal(t, string(chain.payloads[0]), string(payload))
+
+	mockPool.ExpectQuery("SELECT id, source").WithArgs(audit.ID).WillReturnRows(storedRow())
+	payload, err = svc.ChainPayload(t.Context(), AuditKindDecisionOutcome, audit.ID)
+	require.NoError(t, err)
+	assert.Equal(t, string(chain.payloads[1]), string(payload))
+
+	mockPool.ExpectQuery("SELECT id, source").WithArgs("dec_missing").WillReturnRows(pgxmock.NewRows(decisionAuditColumns))
+	_, err = svc.ChainPayload(t.Context(), AuditKindDecision, "dec_missing")
+	assert.ErrorIs(t, err, ErrAuditRecordNotFound)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
@@ -0,0 +1,556 @@
+package services
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultDecisionEmbeddingDims is the vector size of the hashing embedder.
+const DefaultDecisionEmbeddingDims = 256
+
+const (
+	defaultSimilarDecisions = 5
+	maxSimilarDecisions     = 20
+	// defaultMinDecisionSimilarity drops decisions that share less than about
+	// half of their features, such as only the symbol and exchange.
+	defaultMinDecisionSimilarity = 0.5
+	// decisionVectorOverfetch widens the KNN search so that decisions
+	// filtered out afterwards (no outcome, excluded) still leave enough.
+	decisionVectorOverfetch = 4
+)
+
+// decisionPriceLevelFields are absolute price levels; they are replaced by
+// the price's position in the 24h range so that situations compare across
+// price levels.
+var decisionPriceLevelFields = map[string]bool{"price": true, "high_24h": true, "low_24h": true}
+
+// DecisionEmbedder turns the text rendering of a decision context into a
+// vector. Vectors must have Dimensions() entries.
+type DecisionEmbedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+	Dimensions() int
+}
+
+// HashingEmbedder embeds whitespace-separated feature tokens by feature
+// hashing. It needs no model, so identical situations always map to the same
+// vector and similar ones share most of their components.
+type HashingEmbedder struct {
+	dims int
+}
+
+// NewHashingEmbedder creates a feature hashing embedder.
+//
+// Parameters:
+//
+//	dims: Vector size; zero or less uses DefaultDecisionEmbeddingDims.
+//
+// Returns:
+//
+//	*HashingEmbedder: Initialized embedder.
+func NewHashingEmbedder(dims int) *HashingEmbedder {
+	if dims <= 0 {
+		dims = DefaultDecisionEmbeddingDims
+	}
+	return &HashingEmbedder{dims: dims}
+}
+
+// Dimensions returns the vector size.
+func (e *HashingEmbedder) Dimensions() int {
+	return e.dims
+}
+
+// Embed returns the L2-normalized hashed token vector of text.
+func (e *HashingEmbedder) Embed(_ context.Context, text string) ([]float32, error) {
+	vector := make([]float32, e.dims)
+	for _, token := range strings.Fields(strings.ToLower(text)) {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(token))
+		sum := h.Sum64()
+		// The top bit picks the sign so colliding tokens tend to cancel out
+		sign := float32(1)
+		if sum>>63 == 1 {
+			sign = -1
+		}
+		vector[sum%uint64(e.dims)] += sign
+	}
+	normalizeVector(vector)
+	return vector, nil
+}
+
+// DecisionContext is the market situation a decision was, or is about to be,
+// made in.
+type DecisionContext struct {
+	Exchange string
+	Symbol   string
+	// MarketSnapshot is the market data of the situation: an object, or an
+	// array of per-symbol objects from which the Symbol entry is used.
+	MarketSnapshot json.RawMessage
+	// ExcludeID skips a decision, usually the one being made.
+	ExcludeID string
+}
+
+// SimilarDecision is a past decision made in a similar situation, with how it
+// played out.
+type SimilarDecision struct {
+	DecisionID string    `json:"decision_id"`
+	Exchange   string    `json:"exchange"`
+	Symbol     string    `json:"symbol"`
+	Action     string    `json:"action"`
+	Confidence float64   `json:"confidence"`
+	Reasoning  string    `json:"reasoning"`
+	ReturnPct  float64   `json:"return_pct"`
+	PnL        *float64  `json:"pnl,omitempty"`
+	Note       string    `json:"note,omitempty"`
+	DecidedAt  time.Time `json:"decided_at"`
+	Similarity float64   `json:"similarity"`
+}
+
+// SimilarDecisionFinder finds past decisions made in situations similar to
+// the current one. It is implemented by DecisionMemory.
+type SimilarDecisionFinder interface {
+	FindSimilarDecisions(ctx context.Context, query DecisionContext, limit int) ([]SimilarDecision, error)
+}
+
+// DecisionIndexer keeps the vector index of decisions up to date. It is
+// implemented by DecisionMemory.
+type DecisionIndexer interface {
+	IndexDecision(ctx context.Context, audit *DecisionAudit) error
+}
+
+// DecisionVectorRecord is a decision as stored in a VectorStore.
+type DecisionVectorRecord struct {
+	// SimilarDecision holds the decision and its outcome; Similarity is unused.
+	SimilarDecision
+	// OutcomeStatus is empty until the outcome is realized.
+	OutcomeStatus string
+	Embedding     []float32
+}
+
+// Kinds of embedded content.
+const (
+	EmbeddingKindDecision = "decision"
+	EmbeddingKindNews     = "news"
+	EmbeddingKindNote     = "note"
+)
+
+// VectorDocument is an embedded text other than a decision, such as a news
+// item or an operator note.
+type VectorDocument struct {
+	Kind string
+	// SourceID identifies the document within its kind, such as a news URL.
+	SourceID string
+	Content  string
+	// UserID is the user an operator note is about, if any.
+	UserID    string
+	CreatedAt time.Time
+	Embedding []float32
+}
+
+// SimilarDocument is a stored document similar to a query.
+type SimilarDocument struct {
+	Kind       string    `json:"kind"`
+	SourceID   string    `json:"source_id"`
+	Content    string    `json:"content"`
+	CreatedAt  time.Time `json:"created_at"`
+	Similarity float64   `json:"similarity"`
+}
+
+// VectorStore persists embedded decisions and documents and finds their
+// nearest neighbours. It is implemented by SQLiteVectorStore and
+// PgVectorStore.
+type VectorStore interface {
+	// UpsertDecision stores a decision, replacing an earlier entry of it.
+	UpsertDecision(ctx context.Context, record DecisionVectorRecord) error
+	// NearestDecisions returns up to k decisions with a realized outcome,
+	// nearest to vector first, with Similarity set to the cosine similarity.
+	NearestDecisions(ctx context.Context, vector []float32, k int) ([]SimilarDecision, error)
+	// UpsertDocument stores a document, replacing an earlier entry with the
+	// same kind and source ID.
+	UpsertDocument(ctx context.Context, document VectorDocument) error
+	// NearestDocuments returns up to k documents of a kind, nearest first.
+	NearestDocuments(ctx context.Context, kind string, vector []float32, k int) ([]SimilarDocument, error)
+	// Dimensions is the vector size the store holds.
+	Dimensions() int
+}
+
+// DecisionMemory embeds decision contexts with their outcomes into a vector
+// store and finds the decisions made in situations similar to a new one.
+type DecisionMemory struct {
+	store         VectorStore
+	embedder      DecisionEmbedder
+	minSimilarity float64
+	now           func() time.Time
+}
+
+// Ensure DecisionMemory implements the finder and indexer.
+var (
+	_ SimilarDecisionFinder = (*DecisionMemory)(nil)
+	_ DecisionIndexer       = (*DecisionMemory)(nil)
+)
+
+// NewDecisionMemory creates a decision memory.
+//
+// Parameters:
+//
+//	store: Vector store of the configured database driver.
+//	embedder: Embedder of decision contexts; nil uses a HashingEmbedder of the store's size.
+//
+// Returns:
+//
+//	*DecisionMemory: Initialized memory.
+//	error: Error if the embedder and store sizes differ.
+func NewDecisionMemory(store VectorStore, embedder DecisionEmbedder) (*DecisionMemory, error) {
+	if embedder == nil {
+		embedder = NewHashingEmbedder(store.Dimensions())
+	}
+	if embedder.Dimensions() != store.Dimensions() {
+		return nil, fmt.Errorf("embedder has %d dimensions, vector store has %d", embedder.Dimensions(), store.Dimensions())
+	}
+	return &DecisionMemory{
+		store:         store,
+		embedder:      embedder,
+		minSimilarity: defaultMinDecisionSimilarity,
+		now:           time.Now,
+	}, nil
+}
+
+// IndexDecision stores the embedded context of a decision with its realized
+// outcome, replacing an earlier entry of the decision.
+//
+// Parameters:
+//
+//	ctx: Context for the writes.
+//	audit: The decision; mark-to-market outcomes are not stored.
+//
+// Returns:
+//
+//	error: Error if the decision could not be stored.
+func (m *DecisionMemory) IndexDecision(ctx context.Context, audit *DecisionAudit) error {
+	if audit == nil || audit.ID == "" {
+		return nil
+	}
+	vector, err := m.embed(ctx, DecisionContext{
+		Exchange:       audit.Exchange,
+		Symbol:         audit.Symbol,
+		MarketSnapshot: audit.MarketSnapshot,
+	})
+	if err != nil {
+		return err
+	}
+
+	return m.store.UpsertDecision(ctx, newDecisionVectorRecord(audit, vector, m.now()))
+}
+
+// FindSimilarDecisions returns past decisions with a realized outcome whose
+// situation is most similar to query, most similar first.
+//
+// Parameters:
+//
+//	ctx: Context for the search.
+//	query: The current situation.
+//	limit: Maximum number of decisions; zero or less uses 5, capped at 20.
+//
+// Returns:
+//
+//	[]SimilarDecision: The similar decisions, at least minimally similar.
+//	error: Error if the search failed.
+func (m *DecisionMemory) FindSimilarDecisions(ctx context.Context, query DecisionContext, limit int) ([]SimilarDecision, error) {
+	if limit <= 0 {
+		limit = defaultSimilarDecisions
+	}
+	limit = min(limit, maxSimilarDecisions)
+	vector, err := m.embed(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	similar, err := m.store.NearestDecisions(ctx, vector, limit*decisionVectorOverfetch)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := similar[:0]
+	for _, decision := range similar {
+		decision.Similarity = roundSimilarity(decision.Similarity)
+		if decision.DecisionID != query.ExcludeID && decision.Similarity >= m.minSimilarity {
+			filtered = append(filtered, decision)
+		}
+	}
+	sortSimilarDecisions(filtered)
+	if len(filtered) > limit {
+		filtered = filtered[:limit]
+	}
+	return filtered, nil
+}
+
+// FindSimilarDocuments returns the stored documents of a kind most similar
+// to text, for retrieval-augmented prompts.
+//
+// Parameters:
+//
+//	ctx: Context for the search.
+//	kind: Document kind, such as EmbeddingKindNews.
+//	text: The query text.
+//	limit: Maximum number of documents; zero or less uses 5, capped at 20.
+//
+// Returns:
+//
+//	[]SimilarDocument: The documents, at least minimally similar.
+//	error: Error if the search failed.
+func (m *DecisionMemory) FindSimilarDocuments(ctx context.Context, kind, text string, limit int) ([]SimilarDocument, error) {
+	if limit <= 0 {
+		limit = defaultSimilarDecisions
+	}
+	limit = min(limit, maxSimilarDecisions)
+	vector, err := m.embedder.Embed(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	documents, err := m.store.NearestDocuments(ctx, kind, vector, limit)
+	if err != nil {
+		return nil, err
+	}
+	filtered := documents[:0]
+	for _, document := range documents {
+		document.Similarity = roundSimilarity(document.Similarity)
+		if document.Similarity >= m.minSimilarity {
+			filtered = append(filtered, document)
+		}
+	}
+	return filtered, nil
+}
+
+func (m *DecisionMemory) embed(ctx context.Context, decisionCtx DecisionContext) ([]float32, error) {
+	vector, err := m.embedder.Embed(ctx, decisionContextText(decisionCtx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed decision context: %w", err)
+	}
+	if len(vector) != m.embedder.Dimensions() {
+		return nil, fmt.Errorf("embedder returned %d dimensions, expected %d", len(vector), m.embedder.Dimensions())
+	}
+	return vector, nil
+}
+
+// newDecisionVectorRecord builds the stored form of a decision. Only realized
+// outcomes are kept; decisions without a time are dated now.
+func newDecisionVectorRecord(audit *DecisionAudit, vector []float32, now time.Time) DecisionVectorRecord {
+	record := DecisionVectorRecord{
+		SimilarDecision: SimilarDecision{
+			DecisionID: audit.ID,
+			Exchange:   audit.Exchange,
+			Symbol:     audit.Symbol,
+			Action:     audit.Action,
+			Confidence: audit.Confidence,
+			Reasoning:  audit.Reasoning,
+			DecidedAt:  audit.CreatedAt,
+		},
+		Embedding: vector,
+	}
+	if record.DecidedAt.IsZero() {
+		record.DecidedAt = now.UTC()
+	}
+	if outcome := audit.Outcome; outcome != nil && outcome.Status == DecisionOutcomeRealized {
+		record.OutcomeStatus = outcome.Status
+		record.ReturnPct, record.PnL, record.Note = outcome.ReturnPct, outcome.PnL, outcome.Note
+	}
+	return record
+}
+
+// sortSimilarDecisions orders decisions most similar first, newest first
+// among equally similar ones.
+func sortSimilarDecisions(decisions []SimilarDecision) {
+	sort.SliceStable(decisions, func(i, j int) bool {
+		if decisions[i].Similarity != decisions[j].Similarity {
+			return decisions[i].Similarity > decisions[j].Similarity
+		}
+		return decisions[i].DecidedAt.After(decisions[j].DecidedAt)
+	})
+}
+
+// FormatSimilarDecisions renders similar decisions as a prompt section, or
+// an empty string when there are none.
+//
+// Parameters:
+//
+//	similar: The similar decisions, most similar first.
+//
+// Returns:
+//
+//	string: The prompt section.
+func FormatSimilarDecisions(similar []SimilarDecision) string {
+	if len(similar) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("## What Happened in Similar Past Situations\n")
+	for _, decision := range similar {
+		fmt.Fprintf(&b, "- %s: %s %s (confidence %.2f, similarity %.2f) → %+.2f%%",
+			decision.DecidedAt.UTC().Format("2006-01-02 15:04"), decision.Action, decision.Symbol,
+			decision.Confidence, decision.Similarity, decision.ReturnPct)
+		if decision.Note != "" {
+			fmt.Fprintf(&b, " (%s)", decision.Note)
+		}
+		b.WriteString("\n")
+		if decision.Reasoning != "" {
+			fmt.Fprintf(&b, "  Reasoning then: %s\n", truncate(decision.Reasoning, 160))
+		}
+	}
+	return b.String()
+}
+
+// decisionContextText renders a situation as feature tokens: the base asset,
+// the exchange, and the sign and log-scale magnitude of each numeric market
+// field.
+func decisionContextText(decisionCtx DecisionContext) string {
+	symbol := normalizeSymbolForComparison(decisionCtx.Symbol)
+	base, _, _ := strings.Cut(symbol, "/")
+	tokens := []string{"base:" + base}
+	if decisionCtx.Exchange != "" {
+		tokens = append(tokens, "exchange:"+strings.ToLower(decisionCtx.Exchange))
+	}
+
+	fields := snapshotFields(decisionCtx.MarketSnapshot, symbol)
+	price, high, low := numericField(fields["price"]), numericField(fields["high_24h"]), numericField(fields["low_24h"])
+	if price > 0 && high > low {
+		tokens = append(tokens, "range_pos:"+strconv.Itoa(int(math.Round((price-low)/(high-low)*10))))
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if decisionPriceLevelFields[key] || key == "symbol" {
+			continue
+		}
+		switch value := fields[key].(type) {
+		case float64:
+			tokens = append(tokens, featureTokens(key, value)...)
+		case string:
+			if value != "" && len(value) <= 32 {
+				tokens = append(tokens, key+":"+strings.ReplaceAll(value, " ", "_"))
+			}
+		case bool:
+			tokens = append(tokens, key+":"+strconv.FormatBool(value))
+		}
+	}
+	return strings.Join(tokens, " ")
+}
+
+// featureTokens encodes a number by its sign and log2 magnitude bucket.
+func featureTokens(key string, value float64) []string {
+	if value == 0 || math.IsNaN(value) || math.IsInf(value, 0) {
+		return []string{key + ":zero"}
+	}
+	sign := "pos"
+	if value < 0 {
+		sign = "neg"
+	}
+	bucket := int(math.Round(math.Log2(1+math.Abs(value)) * 2))
+	return []string{key + ":" + sign, key + ":" + sign + strconv.Itoa(bucket)}
+}
+
+// snapshotFields returns the fields of a snapshot object, or of the entry of
+// a snapshot array whose symbol matches. Nested objects are flattened one
+// level deep as "parent.child".
+func snapshotFields(snapshot json.RawMessage, symbol string) map[string]any {
+	if len(snapshot) == 0 {
+		return nil
+	}
+	var decoded any
+	if json.Unmarshal(snapshot, &decoded) != nil {
+		return nil
+	}
+	var object map[string]any
+	switch value := decoded.(type) {
+	case map[string]any:
+		object = value
+	case []any:
+		for _, entry := range value {
+			candidate, ok := entry.(map[string]any)
+			if !ok {
+				continue
+			}
+			if entrySymbol, _ := candidate["symbol"].(string); normalizeSymbolForComparison(entrySymbol) == symbol {
+				object = candidate
+				break
+			}
+		}
+	}
+
+	fields := make(map[string]any, len(object))
+	for key, value := range object {
+		if nested, ok := value.(map[string]any); ok {
+			for childKey, childValue := range nested {
+				fields[key+"."+childKey] = childValue
+			}
+			continue
+		}
+		fields[key] = value
+	}
+	return fields
+}
+
+func numericField(value any) float64 {
+	number, _ := value.(float64)
+	return number
+}
+
+func normalizeVector(vector []float32) {
+	var sum float64
+	for _, v := range vector {
+		sum += float64(v) * float64(v)
+	}
+	if sum == 0 {
+		return
+	}
+	norm := float32(math.Sqrt(sum))
+	for i := range vector {
+		vector[i] /= norm
+	}
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+func roundSimilarity(similarity float64) float64 {
+	return math.Round(similarity*1000) / 1000
+}
+
+// encodeVector serializes a vector as little-endian float32, the blob format
+// sqlite-vec accepts.
+func encodeVector(vector []float32) []byte {
+	raw := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(raw[4*i:], math.Float32bits(v))
+	}
+	return raw
+}
+
+func decodeVector(raw []byte) []float32 {
+	vector := make([]float32, len(raw)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(raw[4*i:]))
+	}
+	return vector
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrPgVectorUnavailable is returned when the pgvector decision_vectors
+// table does not exist, usually because the extension is not installed and
+// migration 073 skipped it.
+var ErrPgVectorUnavailable = errors.New("pgvector decision_vectors table not found")
+
+// PgVectorStore stores decision and document vectors in Postgres with
+// pgvector. Nearest neighbours come from the HNSW cosine indexes created by
+// migrations 073 and 074.
+type PgVectorStore struct {
+	db   DBPool
+	dims int
+}
+
+// Ensure PgVectorStore implements VectorStore.
+var _ VectorStore = (*PgVectorStore)(nil)
+
+// NewPgVectorStore creates the pgvector store after checking that the
+// decision_vectors table exists with the expected vector size.
+//
+// Parameters:
+//
+//	ctx: Context for the schema check.
+//	db: Postgres pool.
+//	dims: Vector size; zero or less uses DefaultDecisionEmbeddingDims.
+//
+// Returns:
+//
+//	*PgVectorStore: Initialized store.
+//	error: ErrPgVectorUnavailable without the table, or a size mismatch.
+func NewPgVectorStore(ctx context.Context, db DBPool, dims int) (*PgVectorStore, error) {
+	if dims <= 0 {
+		dims = DefaultDecisionEmbeddingDims
+	}
+	// The type modifier of a vector column is its dimension count
+	var columnDims int
+	err := db.QueryRow(ctx, `
+		SELECT atttypmod FROM pg_attribute
+		WHERE attrelid = to_regclass('decision_vectors') AND attname = 'embedding'`).Scan(&columnDims)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrPgVectorUnavailable
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check decision_vectors table: %w", err)
+	}
+	if columnDims != dims {
+		return nil, fmt.Errorf("decision_vectors.embedding has %d dimensions, expected %d", columnDims, dims)
+	}
+	return &PgVectorStore{db: db, dims: dims}, nil
+}
+
+// Dimensions returns the vector size.
+func (s *PgVectorStore) Dimensions() int {
+	return s.dims
+}
+
+// UpsertDecision stores a decision, replacing an earlier entry of it.
+func (s *PgVectorStore) UpsertDecision(ctx context.Context, record DecisionVectorRecord) error {
+	_, err := s.db.Exec(ctx, `
+		INSERT INTO decision_vectors (
+			decision_id, exchange, symbol, action, confidence, reasoning,
+			outcome_status, return_pct, pnl, outcome_note, embedding, decided_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11::vector, $12, $13)
+		ON CONFLICT (decision_id) DO UPDATE SET
+			action = EXCLUDED.action, confidence = EXCLUDED.confidence, reasoning = EXCLUDED.reasoning,
+			outcome_status = EXCLUDED.outcome_status, return_pct = EXCLUDED.return_pct, pnl = EXCLUDED.pnl,
+			outcome_note = EXCLUDED.outcome_note, embedding = EXCLUDED.embedding, updated_at = EXCLUDED.updated_at`,
+		record.DecisionID, record.Exchange, record.Symbol, record.Action, record.Confidence, record.Reasoning,
+		record.OutcomeStatus, record.ReturnPct, record.PnL, record.Note, pgvectorLiteral(record.Embedding),
+		record.DecidedAt, time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to index decision: %w", err)
+	}
+	return nil
+}
+
+// NearestDecisions returns up to k decisions with a realized outcome,
+// nearest to vector first by cosine distance.
+func (s *PgVectorStore) NearestDecisions(ctx context.Context, vector []float32, k int) ([]SimilarDecision, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT decision_id, exchange, symbol, action, confidence, reasoning,
+			return_pct, pnl, outcome_note, decided_at, 1 - (embedding <=> $1::vector)
+		FROM decision_vectors
+		WHERE outcome_status <> ''
+		ORDER BY embedding <=> $1::vector
+		LIMIT $2`, pgvectorLiteral(vector), k)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search decision vectors: %w", err)
+	}
+	defer rows.Close()
+
+	var similar []SimilarDecision
+	for rows.Next() {
+		var decision SimilarDecision
+		if err := rows.Scan(&decision.DecisionID, &decision.Exchange, &decision.Symbol, &decision.Action,
+			&decision.Confidence, &decision.Reasoning, &decision.ReturnPct, &decision.PnL, &decision.Note,
+			&decision.DecidedAt, &decision.Similarity); err != nil {
+			return nil, fmt.Errorf("failed to scan similar decision: %w", err)
+		}
+		similar = append(similar, decision)
+	}
+	return similar, rows.Err()
+}
+
+// pgvectorLiteral formats a vector as pgvector's text input, "[1,2,3]".
+func pgvectorLiteral(vector []float32) string {
+	parts := make([]string, len(vector))
+	for i, v := range vector {
+		parts[i] = strconv.FormatFloat(float64(v), 'g', -1, 32)
+	}
+	return "[" + strings.Join(parts, ",") + "]"
+}
+
+// UpsertDocument stores a document, replacing an earlier entry with the same
+// kind and source ID.
+func (s *PgVectorStore) UpsertDocument(ctx context.Context, document VectorDocument) error {
+	_, err := s.db.Exec(ctx, `
+		INSERT INTO embedded_documents (kind, source_id, content, embedding, user_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4::vector, NULLIF($5, ''), $6, $7)
+		ON CONFLICT (kind, source_id) DO UPDATE SET
+			content = EXCLUDED.content, embedding = EXCLUDED.embedding, user_id = EXCLUDED.user_id,
+			updated_at = EXCLUDED.updated_at`,
+		document.Kind, document.SourceID, document.Content, pgvectorLiteral(document.Embedding),
+		document.UserID, document.CreatedAt, time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to index document: %w", err)
+	}
+	return nil
+}
+
+// NearestDocuments returns up to k documents of a kind, nearest first by
+// cosine distance.
+func (s *PgVectorStore) NearestDocuments(ctx context.Context, kind string, vector []float32, k int) ([]SimilarDocument, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT kind, source_id, content, created_at, 1 - (embedding <=> $1::vector)
+		FROM embedded_documents
+		WHERE kind = $2
+		ORDER BY embedding <=> $1::vector
+		LIMIT $3`, pgvectorLiteral(vector), kind, k)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search documents: %w", err)
+	}
+	defer rows.Close()
+
+	var documents []SimilarDocument
+	for rows.Next() {
+		var document SimilarDocument
+		if err := rows.Scan(&document.Kind, &document.SourceID, &document.Content, &document.CreatedAt, &document.Similarity); err != nil {
+			return nil, fmt.Errorf("failed to scan similar document: %w", err)
+		}
+		documents = append(documents, document)
+	}
+	return documents, rows.Err()
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPgVectorStore(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	db := database.NewMockDBPool(mockPool)
+
+	mockPool.ExpectQuery("SELECT atttypmod FROM pg_attribute").
+		WillReturnRows(pgxmock.NewRows([]string{"atttypmod"}))
+	_, err = NewPgVectorStore(t.Context(), db, 0)
+	assert.ErrorIs(t, err, ErrPgVectorUnavailable)
+
+	mockPool.ExpectQuery("SELECT atttypmod FROM pg_attribute").
+		WillReturnRows(pgxmock.NewRows([]string{"atttypmod"}).AddRow(128))
+	_, err = NewPgVectorStore(t.Context(), db, 0)
+	assert.EqualError(t, err, "decision_vectors.embedding has 128 dimensions, expected 256")
+
+	mockPool.ExpectQuery("SELECT atttypmod FROM pg_attribute").
+		WillReturnRows(pgxmock.NewRows([]string{"atttypmod"}).AddRow(256))
+	store, err := NewPgVectorStore(t.Context(), db, 0)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultDecisionEmbeddingDims, store.Dimensions())
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestPgVectorStore_UpsertAndNearest(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	store := &PgVectorStore{db: database.NewMockDBPool(mockPool), dims: 3}
+	decidedAt := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	pnl := 12.5
+
+	mockPool.ExpectExec("INSERT INTO decision_vectors").
+		WithArgs("dec_a", "binance", "BTC/USDT", "buy", 0.8, "bids stacking", DecisionOutcomeRealized,
+			2.5, &pnl, "closed", "[0.6,0,-0.8]", decidedAt, pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	require.NoError(t, store.UpsertDecision(t.Context(), DecisionVectorRecord{
+		SimilarDecision: SimilarDecision{
+			DecisionID: "dec_a", Exchange: "binance", Symbol: "BTC/USDT", Action: "buy", Confidence: 0.8,
+			Reasoning: "bids stacking", ReturnPct: 2.5, PnL: &pnl, Note: "closed", DecidedAt: decidedAt,
+		},
+		OutcomeStatus: DecisionOutcomeRealized,
+		Embedding:     []float32{0.6, 0, -0.8},
+	}))
+
+	mockPool.ExpectQuery("ORDER BY embedding <=> \\$1::vector").
+		WithArgs("[0.6,0,-0.8]", 20).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"decision_id", "exchange", "symbol", "action", "confidence", "reasoning",
+			"return_pct", "pnl", "outcome_note", "decided_at", "similarity",
+		}).AddRow("dec_a", "binance", "BTC/USDT", "buy", 0.8, "bids stacking", 2.5, &pnl, "closed", decidedAt, 0.97))
+	similar, err := store.NearestDecisions(t.Context(), []float32{0.6, 0, -0.8}, 20)
+	require.NoError(t, err)
+	require.Len(t, similar, 1)
+	assert.Equal(t, "dec_a", similar[0].DecisionID)
+	assert.InDelta(t, 0.97, similar[0].Similarity, 1e-9)
+	require.NotNil(t, similar[0].PnL)
+	assert.InDelta(t, 12.5, *similar[0].PnL, 1e-9)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
@@ -0,0 +1,315 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/telemetry"
+)
+
+// maxDecisionVectorScan bounds the brute-force search without sqlite-vec.
+const maxDecisionVectorScan = 5000
+
+// SQLiteVectorStore stores decision and document vectors in SQLite. When the sqlite-vec
+// extension is loaded the nearest neighbours come from a vec0 index;
+// otherwise the latest vectors are compared in Go.
+type SQLiteVectorStore struct {
+	db     *sql.DB
+	dims   int
+	vec    bool
+	logger *slog.Logger
+}
+
+// Ensure SQLiteVectorStore implements VectorStore.
+var _ VectorStore = (*SQLiteVectorStore)(nil)
+
+// NewSQLiteVectorStore creates the SQLite vector store and its tables.
+//
+// Parameters:
+//
+//	db: SQLite connection, with the sqlite-vec extension loaded when available.
+//	dims: Vector size; zero or less uses DefaultDecisionEmbeddingDims.
+//
+// Returns:
+//
+//	*SQLiteVectorStore: Initialized store.
+//	error: Error if the tables could not be created.
+func NewSQLiteVectorStore(db *sql.DB, dims int) (*SQLiteVectorStore, error) {
+	if dims <= 0 {
+		dims = DefaultDecisionEmbeddingDims
+	}
+	s := &SQLiteVectorStore{db: db, dims: dims, logger: telemetry.Logger()}
+	if err := s.initTables(); err != nil {
+		return nil, fmt.Errorf("failed to init decision vector tables: %w", err)
+	}
+	return s, nil
+}
+
+// Dimensions returns the vector size.
+func (s *SQLiteVectorStore) Dimensions() int {
+	return s.dims
+}
+
+func (s *SQLiteVectorStore) initTables() error {
+	_, err := s.db.Exec(`
+	CREATE TABLE IF NOT EXISTS decision_vectors (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		decision_id TEXT NOT NULL UNIQUE,
+		exchange TEXT NOT NULL DEFAULT '',
+		symbol TEXT NOT NULL,
+		action TEXT NOT NULL,
+		confidence REAL NOT NULL DEFAULT 0,
+		reasoning TEXT NOT NULL DEFAULT '',
+		outcome_status TEXT NOT NULL DEFAULT '',
+		return_pct REAL NOT NULL DEFAULT 0,
+		pnl REAL,
+		outcome_note TEXT NOT NULL DEFAULT '',
+		embedding BLOB NOT NULL,
+		decided_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL
+	)`)
+	if err != nil {
+		return err
+	}
+	_, _ = s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_decision_vectors_outcome ON decision_vectors(outcome_status, decided_at)`)
+
+	_, err = s.db.Exec(`
+	CREATE TABLE IF NOT EXISTS embedded_documents (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		kind TEXT NOT NULL,
+		source_id TEXT NOT NULL,
+		content TEXT NOT NULL,
+		embedding BLOB NOT NULL,
+		user_id TEXT,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		UNIQUE (kind, source_id)
+	)`)
+	if err != nil {
+		return err
+	}
+	// Tables created before notes carried their user; fails once it exists
+	_, _ = s.db.Exec(`ALTER TABLE embedded_documents ADD COLUMN user_id TEXT`)
+	_, _ = s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_embedded_documents_kind ON embedded_documents(kind, created_at)`)
+
+	var version string
+	if s.db.QueryRow(`SELECT vec_version()`).Scan(&version) != nil {
+		s.logger.Info("sqlite-vec not loaded, similar decisions are searched without an index")
+		return nil
+	}
+	_, err = s.db.Exec(fmt.Sprintf(
+		`CREATE VIRTUAL TABLE IF NOT EXISTS decision_vectors_vec USING vec0(embedding float[%d])`, s.dims))
+	if err != nil {
+		return fmt.Errorf("create vec0 table: %w", err)
+	}
+	_, err = s.db.Exec(fmt.Sprintf(
+		`CREATE VIRTUAL TABLE IF NOT EXISTS embedded_documents_vec USING vec0(embedding float[%d])`, s.dims))
+	if err != nil {
+		return fmt.Errorf("create vec0 table: %w", err)
+	}
+	s.vec = true
+	s.logger.Info("Similar decisions are indexed with sqlite-vec", "version", version)
+	return nil
+}
+
+// UpsertDecision stores a decision, replacing an earlier entry of it.
+func (s *SQLiteVectorStore) UpsertDecision(ctx context.Context, record DecisionVectorRecord) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO decision_vectors (
+			decision_id, exchange, symbol, action, confidence, reasoning,
+			outcome_status, return_pct, pnl, outcome_note, embedding, decided_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(decision_id) DO UPDATE SET
+			action = excluded.action, confidence = excluded.confidence, reasoning = excluded.reasoning,
+			outcome_status = excluded.outcome_status, return_pct = excluded.return_pct, pnl = excluded.pnl,
+			outcome_note = excluded.outcome_note, embedding = excluded.embedding, updated_at = excluded.updated_at`,
+		record.DecisionID, record.Exchange, record.Symbol, record.Action, record.Confidence, record.Reasoning,
+		record.OutcomeStatus, record.ReturnPct, record.PnL, record.Note, encodeVector(record.Embedding),
+		record.DecidedAt, time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to index decision: %w", err)
+	}
+	if !s.vec {
+		return nil
+	}
+
+	var rowID int64
+	if err := s.db.QueryRowContext(ctx, `SELECT id FROM decision_vectors WHERE decision_id = ?`, record.DecisionID).Scan(&rowID); err != nil {
+		return fmt.Errorf("failed to look up indexed decision: %w", err)
+	}
+	// vec0 tables do not support upserts
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM decision_vectors_vec WHERE rowid = ?`, rowID); err != nil {
+		return fmt.Errorf("failed to replace decision vector: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `INSERT INTO decision_vectors_vec (rowid, embedding) VALUES (?, ?)`, rowID, encodeVector(record.Embedding)); err != nil {
+		return fmt.Errorf("failed to store decision vector: %w", err)
+	}
+	return nil
+}
+
+// NearestDecisions returns up to k decisions with a realized outcome,
+// nearest to vector first.
+func (s *SQLiteVectorStore) NearestDecisions(ctx context.Context, vector []float32, k int) ([]SimilarDecision, error) {
+	if s.vec {
+		return s.nearestIndexed(ctx, vector, k)
+	}
+	similar, err := s.nearestScanned(ctx, vector)
+	if err != nil {
+		return nil, err
+	}
+	sortSimilarDecisions(similar)
+	if len(similar) > k {
+		similar = similar[:k]
+	}
+	return similar, nil
+}
+
+// nearestIndexed runs a KNN query on the vec0 index. Vectors are normalized,
+// so the L2 distance d maps to cosine similarity as 1 - d²/2.
+func (s *SQLiteVectorStore) nearestIndexed(ctx context.Context, vector []float32, k int) ([]SimilarDecision, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT d.decision_id, d.exchange, d.symbol, d.action, d.confidence, d.reasoning,
+			d.return_pct, d.pnl, d.outcome_note, d.decided_at, v.distance
+		FROM (SELECT rowid, distance FROM decision_vectors_vec WHERE embedding MATCH ? AND k = ?) v
+		JOIN decision_vectors d ON d.id = v.rowid
+		WHERE d.outcome_status != ''
+		ORDER BY v.distance`, encodeVector(vector), k)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search decision vectors: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var similar []SimilarDecision
+	for rows.Next() {
+		var decision SimilarDecision
+		var pnl sql.NullFloat64
+		var distance float64
+		if err := rows.Scan(&decision.DecisionID, &decision.Exchange, &decision.Symbol, &decision.Action,
+			&decision.Confidence, &decision.Reasoning, &decision.ReturnPct, &pnl, &decision.Note,
+			&decision.DecidedAt, &distance); err != nil {
+			return nil, fmt.Errorf("failed to scan similar decision: %w", err)
+		}
+		if pnl.Valid {
+			decision.PnL = &pnl.Float64
+		}
+		decision.Similarity = 1 - distance*distance/2
+		similar = append(similar, decision)
+	}
+	return similar, rows.Err()
+}
+
+// nearestScanned compares vector with the latest decisions that have an
+// outcome.
+func (s *SQLiteVectorStore) nearestScanned(ctx context.Context, vector []float32) ([]SimilarDecision, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT decision_id, exchange, symbol, action, confidence, reasoning,
+			return_pct, pnl, outcome_note, decided_at, embedding
+		FROM decision_vectors
+		WHERE outcome_status != ''
+		ORDER BY decided_at DESC
+		LIMIT ?`, maxDecisionVectorScan)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search decision vectors: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var similar []SimilarDecision
+	for rows.Next() {
+		var decision SimilarDecision
+		var pnl sql.NullFloat64
+		var embedding []byte
+		if err := rows.Scan(&decision.DecisionID, &decision.Exchange, &decision.Symbol, &decision.Action,
+			&decision.Confidence, &decision.Reasoning, &decision.ReturnPct, &pnl, &decision.Note,
+			&decision.DecidedAt, &embedding); err != nil {
+			return nil, fmt.Errorf("failed to scan similar decision: %w", err)
+		}
+		if pnl.Valid {
+			decision.PnL = &pnl.Float64
+		}
+		decision.Similarity = cosineSimilarity(vector, decodeVector(embedding))
+		similar = append(similar, decision)
+	}
+	return similar, rows.Err()
+}
+
+// UpsertDocument stores a document, replacing an earlier entry with the same
+// kind and source ID.
+func (s *SQLiteVectorStore) UpsertDocument(ctx context.Context, document VectorDocument) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO embedded_documents (kind, source_id, content, embedding, user_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, NULLIF(?, ''), ?, ?)
+		ON CONFLICT(kind, source_id) DO UPDATE SET
+			content = excluded.content, embedding = excluded.embedding, user_id = excluded.user_id,
+			updated_at = excluded.updated_at`,
+		document.Kind, document.SourceID, document.Content, encodeVector(document.Embedding),
+		document.UserID, document.CreatedAt, time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to index document: %w", err)
+	}
+	if !s.vec {
+		return nil
+	}
+
+	var rowID int64
+	if err := s.db.QueryRowContext(ctx, `SELECT id FROM embedded_documents WHERE kind = ? AND source_id = ?`,
+		document.Kind, document.SourceID).Scan(&rowID); err != nil {
+		return fmt.Errorf("failed to look up indexed document: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM embedded_documents_vec WHERE rowid = ?`, rowID); err != nil {
+		return fmt.Errorf("failed to replace document vector: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `INSERT INTO embedded_documents_vec (rowid, embedding) VALUES (?, ?)`, rowID, encodeVector(document.Embedding)); err != nil {
+		return fmt.Errorf("failed to store document vector: %w", err)
+	}
+	return nil
+}
+
+// NearestDocuments returns up to k documents of a kind, nearest first.
+func (s *SQLiteVectorStore) NearestDocuments(ctx context.Context, kind string, vector []float32, k int) ([]SimilarDocument, error) {
+	var rows *sql.Rows
+	var err error
+	if s.vec {
+		// The KNN search covers every kind, so it is widened before filtering
+		rows, err = s.db.QueryContext(ctx, `
+			SELECT d.kind, d.source_id, d.content, d.created_at, d.embedding
+			FROM (SELECT rowid FROM embedded_documents_vec WHERE embedding MATCH ? AND k = ?) v
+			JOIN embedded_documents d ON d.id = v.rowid
+			WHERE d.kind = ?`, encodeVector(vector), k*decisionVectorOverfetch, kind)
+	} else {
+		rows, err = s.db.QueryContext(ctx, `
+			SELECT kind, source_id, content, created_at, embedding
+			FROM embedded_documents
+			WHERE kind = ?
+			ORDER BY created_at DESC
+			LIMIT ?`, kind, maxDecisionVectorScan)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to search documents: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var documents []SimilarDocument
+	for rows.Next() {
+		var document SimilarDocument
+		var embedding []byte
+		if err := rows.Scan(&document.Kind, &document.SourceID, &document.Content, &document.CreatedAt, &embedding); err != nil {
+			return nil, fmt.Errorf("failed to scan similar document: %w", err)
+		}
+		document.Similarity = cosineSimilarity(vector, decodeVector(embedding))
+		documents = append(documents, document)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(documents, func(i, j int) bool { return documents[i].Similarity > documents[j].Similarity })
+	if len(documents) > k {
+		documents = documents[:k]
+	}
+	return documents, nil
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func indexedDecision(id, symbol, action string, snapshot string, returnPct float64, at time.Time) *DecisionAudit {
+	audit := &DecisionAudit{
+		ID:             id,
+		Exchange:       "binance",
+		Symbol:         symbol,
+		Action:         action,
+		Confidence:     0.8,
+		Reasoning:      action + " on order book pressure",
+		MarketSnapshot: json.RawMessage(snapshot),
+		CreatedAt:      at,
+	}
+	if returnPct != 0 {
+		audit.Outcome = &DecisionOutcome{Status: DecisionOutcomeRealized, ReturnPct: returnPct, Note: "closed"}
+	}
+	return audit
+}
+
+func TestHashingEmbedder_Embed(t *testing.T) {
+	embedder := NewHashingEmbedder(0)
+	assert.Equal(t, DefaultDecisionEmbeddingDims, embedder.Dimensions())
+
+	a, err := embedder.Embed(t.Context(), "base:btc ob_imbalance:pos ob_imbalance:pos1")
+	require.NoError(t, err)
+	b, err := embedder.Embed(t.Context(), "BASE:BTC ob_imbalance:pos ob_imbalance:pos1")
+	require.NoError(t, err)
+	assert.Equal(t, a, b, "embedding is deterministic and case-insensitive")
+	assert.InDelta(t, 1.0, cosineSimilarity(a, a), 1e-6)
+	assert.InDelta(t, 1.0, cosineSimilarity(a, decodeVector(encodeVector(a))), 1e-6)
+
+	empty, err := embedder.Embed(t.Context(), "")
+	require.NoError(t, err)
+	assert.Zero(t, cosineSimilarity(a, empty))
+}
+
+func TestDecisionContextText(t *testing.T) {
+	text := decisionContextText(DecisionContext{
+		Exchange: "Binance",
+		Symbol:   "eth/usdt:usdt",
+		MarketSnapshot: json.RawMessage(`[
+			{"symbol":"BTC/USDT","ob_imbalance":-0.5},
+			{"symbol":"ETH/USDT","price":105,"high_24h":110,"low_24h":100,"ob_imbalance":0.3,"spread_pct":0}
+		]`),
+	})
+
+	assert.Equal(t, "base:ETH exchange:binance range_pos:5 ob_imbalance:pos ob_imbalance:pos1 spread_pct:zero", text)
+	assert.NotContains(t, text, "price:", "absolute price levels are not features")
+}
+
+func TestDecisionMemory_FindSimilarDecisions(t *testing.T) {
+	db := setupTestDB(t)
+	store, err := NewSQLiteVectorStore(db, 0)
+	require.NoError(t, err)
+	memory, err := NewDecisionMemory(store, nil)
+	require.NoError(t, err)
+	now := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	bullish := `{"symbol":"BTC/USDT","ob_imbalance":0.35,"price_change_24h_pct":4.2,"trade_imbalance":0.4}`
+	bearish := `{"symbol":"SOL/USDT","ob_imbalance":-0.6,"price_change_24h_pct":-9,"long_short_ratio":3.1}`
+
+	for _, audit := range []*DecisionAudit{
+		indexedDecision("dec_a", "BTC/USDT", "buy", bullish, 2.5, now.Add(-2*time.Hour)),
+		indexedDecision("dec_b", "SOL/USDT", "sell", bearish, -1.2, now.Add(-time.Hour)),
+		indexedDecision("dec_open", "BTC/USDT", "buy", bullish, 0, now),
+		indexedDecision("dec_self", "BTC/USDT", "buy", bullish, 1, now),
+	} {
+		require.NoError(t, memory.IndexDecision(t.Context(), audit))
+	}
+
+	similar, err := memory.FindSimilarDecisions(t.Context(), DecisionContext{
+		Exchange:       "binance",
+		Symbol:         "BTC/USDT",
+		MarketSnapshot: json.RawMessage(bullish),
+		ExcludeID:      "dec_self",
+	}, 0)
+	require.NoError(t, err)
+	require.Len(t, similar, 1, "open, excluded and dissimilar decisions are left out")
+	assert.Equal(t, "dec_a", similar[0].DecisionID)
+	assert.Equal(t, "buy", similar[0].Action)
+	assert.InDelta(t, 2.5, similar[0].ReturnPct, 1e-9)
+	assert.InDelta(t, 1.0, similar[0].Similarity, 1e-3)
+	assert.True(t, similar[0].DecidedAt.Equal(now.Add(-2*time.Hour)))
+
+	// Recording the outcome of the open decision re-indexes it
+	open := indexedDecision("dec_open", "BTC/USDT", "buy", bullish, -0.8, now)
+	require.NoError(t, memory.IndexDecision(t.Context(), open))
+	similar, err = memory.FindSimilarDecisions(t.Context(), DecisionContext{
+		Exchange: "binance", Symbol: "BTC/USDT", MarketSnapshot: json.RawMessage(bullish), ExcludeID: "dec_self",
+	}, 5)
+	require.NoError(t, err)
+	require.Len(t, similar, 2)
+	assert.Equal(t, "dec_open", similar[0].DecisionID, "equally similar decisions are newest first")
+
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM decision_vectors`).Scan(&count))
+	assert.Equal(t, 4, count)
+}
+
+func TestNewDecisionMemory_DimensionMismatch(t *testing.T) {
+	store, err := NewSQLiteVectorStore(setupTestDB(t), 128)
+	require.NoError(t, err)
+
+	_, err = NewDecisionMemory(store, NewHashingEmbedder(256))
+	assert.EqualError(t, err, "embedder has 256 dimensions, vector store has 128")
+
+	memory, err := NewDecisionMemory(store, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 128, memory.embedder.Dimensions(), "the default embedder matches the store")
+}
+
+func TestFormatSimilarDecisions(t *testing.T) {
+	assert.Empty(t, FormatSimilarDecisions(nil))
+
+	text := FormatSimilarDecisions([]SimilarDecision{{
+		Symbol:     "BTC/USDT",
+		Action:     "buy",
+		Confidence: 0.8,
+		Reasoning:  "bids stacking",
+		ReturnPct:  -1.25,
+		Note:       "stopped out",
+		DecidedAt:  time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC),
+		Similarity: 0.912,
+	}})
+	assert.True(t, strings.HasPrefix(text, "## What Happened in Similar Past Situations\n"))
+	assert.Contains(t, text, "- 2026-09-01 12:00: buy BTC/USDT (confidence 0.80, similarity 0.91) → -1.25% (stopped out)\n")
+	assert.Contains(t, text, "  Reasoning then: bids stacking\n")
+}
+
+type recordingIndexer struct {
+	audits []*DecisionAudit
+}
+
+func (r *recordingIndexer) IndexDecision(_ context.Context, audit *DecisionAudit) error {
+	r.audits = append(r.audits, audit)
+	return nil
+}
+
+func TestDecisionAuditService_IndexesDecisions(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc := NewDecisionAuditService(database.NewMockDBPool(mockPool), nil)
+	svc.now = func() time.Time { return now }
+	index := &recordingIndexer{}
+	svc.SetVectorIndex(index)
+
+	insertArgs := make([]any, len(decisionAuditColumns))
+	for i := range insertArgs {
+		insertArgs[i] = pgxmock.AnyArg()
+	}
+	mockPool.ExpectExec("INSERT INTO decision_audits").WithArgs(insertArgs...).WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	audit := &DecisionAudit{Source: DecisionSourceAIScalping, Exchange: "binance", Symbol: "BTC/USDT", Action: "buy"}
+	require.NoError(t, svc.Record(t.Context(), audit))
+	require.Len(t, index.audits, 1)
+	assert.Same(t, audit, index.audits[0])
+
+	mockPool.ExpectExec("UPDATE decision_audits SET outcome").
+		WithArgs(pgxmock.AnyArg(), now, pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mockPool.ExpectQuery("SELECT id, source").
+		WithArgs(audit.ID).
+		WillReturnRows(pgxmock.NewRows(decisionAuditColumns).AddRow(
+			audit.ID, audit.Source, audit.Exchange, audit.Symbol, audit.Action, 0.0, "", 100.0,
+			[]byte(nil), "", "", "", []byte(nil), []byte(nil),
+			[]byte(`{"status":"realized","return_pct":3}`), "", "", "", now, now))
+	require.NoError(t, svc.RecordOutcome(t.Context(), audit.ID, DecisionOutcome{ReturnPct: 3}))
+	require.Len(t, index.audits, 2)
+	require.NotNil(t, index.audits[1].Outcome)
+	assert.Equal(t, DecisionOutcomeRealized, index.audits[1].Outcome.Status)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
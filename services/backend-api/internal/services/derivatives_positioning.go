@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/jackc/pgx/v5"
+)
+
+const defaultPositioningLookback = time.Hour
+
+// PositioningClient fetches open interest and long/short account ratios of
+// perpetual swaps. It is implemented by ccxt.Service.
+type PositioningClient interface {
+	FetchPositioning(ctx context.Context, exchange string, symbols []string, period string) ([]ccxt.PositioningInfo, error)
+}
+
+// PositioningReader provides the latest derivatives positioning of a symbol.
+type PositioningReader interface {
+	LatestPositioning(ctx context.Context, exchange, symbol string) (*PositioningSnapshot, error)
+}
+
+// PositioningSnapshot is one sample of a perp's open interest and long/short
+// account ratio. Symbols are stored without their settlement suffix, so the
+// perp of "BTC/USDT" is found as "BTC/USDT".
+type PositioningSnapshot struct {
+	Exchange string `json:"exchange"`
+	Symbol   string `json:"symbol"`
+	// OpenInterest is in contracts or base currency, OpenInterestValue in
+	// quote currency.
+	OpenInterest      float64 `json:"open_interest"`
+	OpenInterestValue float64 `json:"open_interest_value"`
+	// LongShortRatio is long accounts divided by short accounts.
+	LongShortRatio float64   `json:"long_short_ratio"`
+	LongAccount    float64   `json:"long_account"`
+	ShortAccount   float64   `json:"short_account"`
+	Timestamp      time.Time `json:"timestamp"`
+	// OpenInterestChangePct is the change of open interest over the store
+	// lookback, in percent; zero without an earlier sample.
+	OpenInterestChangePct float64 `json:"open_interest_change_pct"`
+}
+
+// NewPositioningSnapshot converts an exchange entry to a snapshot.
+//
+// Parameters:
+//
+//	exchange: Exchange identifier.
+//	info: Positioning entry from the CCXT service.
+//
+// Returns:
+//
+//	PositioningSnapshot: The snapshot, timestamped now when the exchange
+//	reports no time.
+func NewPositioningSnapshot(exchange string, info ccxt.PositioningInfo) PositioningSnapshot {
+	timestamp := info.Timestamp.Time()
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	return PositioningSnapshot{
+		Exchange:          strings.ToLower(exchange),
+		Symbol:            normalizeSymbolForComparison(info.Symbol),
+		OpenInterest:      info.OpenInterestAmount,
+		OpenInterestValue: info.OpenInterestValue,
+		LongShortRatio:    info.LongShortRatio,
+		LongAccount:       info.LongAccount,
+		ShortAccount:      info.ShortAccount,
+		Timestamp:         timestamp.UTC(),
+	}
+}
+
+// PositioningStore persists positioning time series in the
+// derivatives_positioning table.
+type PositioningStore struct {
+	db       DBPool
+	lookback time.Duration
+}
+
+// Ensure PositioningStore implements PositioningReader.
+var _ PositioningReader = (*PositioningStore)(nil)
+
+// NewPositioningStore creates the positioning store.
+//
+// Parameters:
+//
+//	db: Database pool holding the derivatives_positioning table.
+//	lookback: Window open interest changes are measured over; zero uses one hour.
+//
+// Returns:
+//
+//	*PositioningStore: Initialized store.
+func NewPositioningStore(db DBPool, lookback time.Duration) *PositioningStore {
+	if lookback <= 0 {
+		lookback = defaultPositioningLookback
+	}
+	return &PositioningStore{db: db, lookback: lookback}
+}
+
+// Save stores snapshots; a sample already stored for the same time is kept.
+func (s *PositioningStore) Save(ctx context.Context, snapshots []PositioningSnapshot) error {
+	if isNilDBPool(s.db) {
+		return fmt.Errorf("database pool is not available")
+	}
+	for _, snapshot := range snapshots {
+		_, err := s.db.Exec(ctx, `
+			INSERT INTO derivatives_positioning (
+				exchange, symbol, open_interest, open_interest_value,
+				long_short_ratio, long_account, short_account, timestamp
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (exchange, symbol, timestamp) DO NOTHING`,
+			snapshot.Exchange, snapshot.Symbol, snapshot.OpenInterest, snapshot.OpenInterestValue,
+			snapshot.LongShortRatio, snapshot.LongAccount, snapshot.ShortAccount, snapshot.Timestamp)
+		if err != nil {
+			return fmt.Errorf("failed to store positioning for %s on %s: %w", snapshot.Symbol, snapshot.Exchange, err)
+		}
+	}
+	return nil
+}
+
+// LatestPositioning returns the newest snapshot of a symbol with its open
+// interest change over the lookback.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	exchange: Exchange identifier.
+//	symbol: Symbol, with or without settlement suffix.
+//
+// Returns:
+//
+//	*PositioningSnapshot: The snapshot, or nil when none is stored.
+//	error: Error if the query fails.
+func (s *PositioningStore) LatestPositioning(ctx context.Context, exchange, symbol string) (*PositioningSnapshot, error) {
+	history, err := s.History(ctx, exchange, symbol, time.Now().UTC().Add(-s.lookback), 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, nil
+	}
+	latest := history[len(history)-1]
+	earliest := history[0]
+	if len(history) > 1 {
+		if earliest.OpenInterestValue > 0 && latest.OpenInterestValue > 0 {
+			latest.OpenInterestChangePct = (latest.OpenInterestValue/earliest.OpenInterestValue - 1) * 100
+		} else if earliest.OpenInterest > 0 {
+			latest.OpenInterestChangePct = (latest.OpenInterest/earliest.OpenInterest - 1) * 100
+		}
+	}
+	return &latest, nil
+}
+
+// History returns the snapshots of a symbol since a time, oldest first.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	exchange: Exchange identifier.
+//	symbol: Symbol, with or without settlement suffix.
+//	since: Earliest sample time.
+//	limit: Maximum number of newest samples; zero returns all.
+//
+// Returns:
+//
+//	[]PositioningSnapshot: Samples, oldest first.
+//	error: Error if the query fails.
+func (s *PositioningStore) History(ctx context.Context, exchange, symbol string, since time.Time, limit int) ([]PositioningSnapshot, error) {
+	if isNilDBPool(s.db) {
+		return nil, fmt.Errorf("database pool is not available")
+	}
+	query := `
+		SELECT exchange, symbol, open_interest, open_interest_value,
+			long_short_ratio, long_account, short_account, timestamp
+		FROM derivatives_positioning
+		WHERE exchange = $1 AND symbol = $2 AND timestamp >= $3
+		ORDER BY timestamp DESC`
+	args := []interface{}{strings.ToLower(exchange), normalizeSymbolForComparison(symbol), since}
+	if limit > 0 {
+		query += " LIMIT $4"
+		args = append(args, limit)
+	}
+	rows, err := s.db.Query(ctx, query, args...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query positioning: %w", err)
+	}
+	defer rows.Close()
+
+	var history []PositioningSnapshot
+	for rows.Next() {
+		var snapshot PositioningSnapshot
+		if err := rows.Scan(&snapshot.Exchange, &snapshot.Symbol, &snapshot.OpenInterest, &snapshot.OpenInterestValue,
+			&snapshot.LongShortRatio, &snapshot.LongAccount, &snapshot.ShortAccount, &snapshot.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan positioning: %w", err)
+		}
+		history = append(history, snapshot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// Newest first from the query; callers get chronological order
+	for i, j := 0, len(history)-1; i < j; i, j = i+1, j-1 {
+		history[i], history[j] = history[j], history[i]
+	}
+	return history, nil
+}
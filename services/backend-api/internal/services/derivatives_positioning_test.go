@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/cache"
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/config"
+	"github.com/irfndi/neuratrade/internal/database"
+	zaplogrus "github.com/irfndi/neuratrade/internal/logging/zaplogrus"
+	"github.com/irfndi/neuratrade/test/testmocks"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var positioningColumns = []string{
+	"exchange", "symbol", "open_interest", "open_interest_value",
+	"long_short_ratio", "long_account", "short_account", "timestamp",
+}
+
+type fakePositioningClient struct {
+	symbols []string
+	info    []ccxt.PositioningInfo
+}
+
+func (f *fakePositioningClient) FetchPositioning(_ context.Context, _ string, symbols []string, _ string) ([]ccxt.PositioningInfo, error) {
+	f.symbols = symbols
+	return f.info, nil
+}
+
+type staticPositioning map[string]*PositioningSnapshot
+
+func (s staticPositioning) LatestPositioning(_ context.Context, exchange, symbol string) (*PositioningSnapshot, error) {
+	return s[exchange+"|"+normalizeSymbolForComparison(symbol)], nil
+}
+
+func TestPositioningStore_LatestPositioningMeasuresOpenInterestChange(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	store := NewPositioningStore(database.NewMockDBPool(mockPool), 0)
+	now := time.Now().UTC()
+
+	mockPool.ExpectQuery("SELECT exchange, symbol, open_interest").
+		WithArgs("binance", "BTC/USDT", pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows(positioningColumns).
+			AddRow("binance", "BTC/USDT", 1100.0, 110000000.0, 2.4, 0.7, 0.3, now).
+			AddRow("binance", "BTC/USDT", 1000.0, 100000000.0, 1.5, 0.6, 0.4, now.Add(-time.Hour)))
+
+	snapshot, err := store.LatestPositioning(t.Context(), "Binance", "BTC/USDT:USDT")
+	require.NoError(t, err)
+	require.NotNil(t, snapshot)
+	assert.InDelta(t, 2.4, snapshot.LongShortRatio, 1e-9)
+	assert.InDelta(t, 10.0, snapshot.OpenInterestChangePct, 1e-9)
+
+	mockPool.ExpectQuery("SELECT exchange, symbol, open_interest").
+		WithArgs("binance", "ETH/USDT", pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows(positioningColumns))
+	snapshot, err = store.LatestPositioning(t.Context(), "binance", "ETH/USDT")
+	require.NoError(t, err)
+	assert.Nil(t, snapshot)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestCollectorService_CollectPositioningStoresPerpsOfWorkerSymbols(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	collector := NewCollectorService(database.NewMockDBPool(mockPool), &testmocks.MockCCXTService{}, &config.Config{}, nil, cache.NewInMemoryBlacklistCache())
+	stamp := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	client := &fakePositioningClient{info: []ccxt.PositioningInfo{{
+		Symbol: "BTC/USDT:USDT", OpenInterestAmount: 1000, OpenInterestValue: 1e8,
+		LongShortRatio: 1.5, LongAccount: 0.6, ShortAccount: 0.4, Timestamp: ccxt.UnixTimestamp(stamp),
+	}}}
+	collector.positioningClient = client
+	worker := &Worker{Exchange: "binance", Symbols: []string{"BTC/USDT", "XRP/USDT"}}
+
+	// Without listed perps nothing is fetched
+	require.NoError(t, collector.collectPositioning(worker))
+	assert.Nil(t, client.symbols)
+
+	collector.perpSymbols["binance"] = []string{"BTC/USDT:USDT", "ETH/USDT:USDT"}
+	mockPool.ExpectExec("INSERT INTO derivatives_positioning").
+		WithArgs("binance", "BTC/USDT", 1000.0, 1e8, 1.5, 0.6, 0.4, stamp).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	require.NoError(t, collector.collectPositioning(worker))
+	assert.Equal(t, []string{"BTC/USDT:USDT"}, client.symbols)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestSignalAggregator_PositioningAdjustsCrowdedSignals(t *testing.T) {
+	sa := NewSignalAggregator(nil, nil, zaplogrus.New())
+	sa.SetPositioningSource(staticPositioning{
+		"binance|BTC/USDT": {LongShortRatio: 2.5, OpenInterestValue: 1e8, OpenInterestChangePct: 4},
+	})
+	buy := &AggregatedSignal{Action: "buy", Confidence: decimal.NewFromFloat(0.82), Metadata: map[string]interface{}{}}
+	sell := &AggregatedSignal{Action: "sell", Confidence: decimal.NewFromFloat(0.6), Metadata: map[string]interface{}{}}
+
+	sa.applyPositioning(t.Context(), "BTC/USDT", "binance", []*AggregatedSignal{buy, sell})
+	assert.Equal(t, "0.77", buy.Confidence.String())
+	assert.Equal(t, SignalStrengthMedium, buy.Strength)
+	assert.Equal(t, "0.65", sell.Confidence.String())
+	assert.Equal(t, "long", sell.Metadata["crowded_side"])
+	assert.Equal(t, 2.5, buy.Metadata["long_short_ratio"])
+
+	// Symbols without positioning are left alone
+	other := &AggregatedSignal{Action: "buy", Confidence: decimal.NewFromFloat(0.7)}
+	sa.applyPositioning(t.Context(), "ETH/USDT", "binance", []*AggregatedSignal{other})
+	assert.Equal(t, "0.7", other.Confidence.String())
+	assert.Nil(t, other.Metadata)
+}
@@ -0,0 +1,444 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/models"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/shopspring/decimal"
+)
+
+// embeddingRequestType is the ai_usage request type of embedding batches.
+const embeddingRequestType = "embedding"
+
+// ErrEmbeddingQueueFull is returned when a job is dropped because the
+// pipeline is behind.
+var ErrEmbeddingQueueFull = errors.New("embedding queue is full")
+
+// EmbeddingJob is a text waiting to be embedded.
+type EmbeddingJob struct {
+	// Kind is EmbeddingKindNews or EmbeddingKindNote; decisions are queued
+	// through IndexDecision.
+	Kind     string
+	SourceID string
+	Text     string
+	// UserID is the user an operator note is about, if any.
+	UserID    string
+	CreatedAt time.Time
+
+	decision *DecisionAudit
+}
+
+// EmbeddingQueue accepts texts for background embedding. It is implemented
+// by EmbeddingPipeline.
+type EmbeddingQueue interface {
+	Enqueue(job EmbeddingJob) bool
+}
+
+// EmbeddingUsageRecorder stores the tokens and cost of embedding batches. It
+// is implemented by database.AIUsageRepository.
+type EmbeddingUsageRecorder interface {
+	Create(ctx context.Context, usage *models.AIUsageCreate) (*models.AIUsage, error)
+}
+
+// EmbeddingPipelineConfig configures the embedding pipeline.
+type EmbeddingPipelineConfig struct {
+	// BatchSize is the number of texts sent per provider request.
+	BatchSize int
+	// FlushInterval is the longest a queued text waits for a full batch.
+	FlushInterval time.Duration
+	// QueueSize bounds the jobs waiting; further jobs are dropped.
+	QueueSize int
+	// MaxAttempts is how often a batch is sent before it is given up.
+	MaxAttempts int
+	// RetryBackoff is the wait before the first retry, doubled for each one.
+	RetryBackoff time.Duration
+	// CostPer1KTokens is the provider's price in USD per 1000 input tokens.
+	CostPer1KTokens decimal.Decimal
+}
+
+// DefaultEmbeddingPipelineConfig returns the default pipeline configuration.
+func DefaultEmbeddingPipelineConfig() EmbeddingPipelineConfig {
+	return EmbeddingPipelineConfig{
+		BatchSize:     32,
+		FlushInterval: 5 * time.Second,
+		QueueSize:     1000,
+		MaxAttempts:   3,
+		RetryBackoff:  time.Second,
+	}
+}
+
+// EmbeddingPipelineStats are the pipeline counters since start.
+type EmbeddingPipelineStats struct {
+	Provider    string          `json:"provider"`
+	Model       string          `json:"model"`
+	Queued      int             `json:"queued"`
+	Embedded    int64           `json:"embedded"`
+	Failed      int64           `json:"failed"`
+	Dropped     int64           `json:"dropped"`
+	Batches     int64           `json:"batches"`
+	InputTokens int64           `json:"input_tokens"`
+	CostUSD     decimal.Decimal `json:"cost_usd"`
+	LastError   string          `json:"last_error,omitempty"`
+	LastBatchAt *time.Time      `json:"last_batch_at,omitempty"`
+}
+
+// EmbeddingPipeline embeds news items, AI decisions and operator notes in
+// the background, in batches with retries, and stores the vectors for
+// similarity search. Tokens and cost of each batch are recorded as AI usage.
+type EmbeddingPipeline struct {
+	provider EmbeddingProvider
+	store    VectorStore
+	usage    EmbeddingUsageRecorder
+	config   EmbeddingPipelineConfig
+	queue    chan EmbeddingJob
+	logger   *slog.Logger
+	now      func() time.Time
+	sleep    func(ctx context.Context, d time.Duration) error
+
+	mu    sync.Mutex
+	stats EmbeddingPipelineStats
+
+	runMu  sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Ensure EmbeddingPipeline implements the queue and decision indexer.
+var (
+	_ EmbeddingQueue  = (*EmbeddingPipeline)(nil)
+	_ DecisionIndexer = (*EmbeddingPipeline)(nil)
+)
+
+// NewEmbeddingPipeline creates an embedding pipeline.
+//
+// Parameters:
+//
+//	provider: Embedding provider.
+//	store: Vector store the embeddings are written to.
+//	usage: Recorder of batch tokens and cost (optional).
+//	config: Pipeline configuration; zero values use the defaults.
+//
+// Returns:
+//
+//	*EmbeddingPipeline: Initialized pipeline; call Start to run it.
+//	error: Error if the provider and store sizes differ.
+func NewEmbeddingPipeline(provider EmbeddingProvider, store VectorStore, usage EmbeddingUsageRecorder, config EmbeddingPipelineConfig) (*EmbeddingPipeline, error) {
+	if provider.Dimensions() != store.Dimensions() {
+		return nil, fmt.Errorf("embedding provider has %d dimensions, vector store has %d", provider.Dimensions(), store.Dimensions())
+	}
+	defaults := DefaultEmbeddingPipelineConfig()
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = defaults.FlushInterval
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaults.QueueSize
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaults.MaxAttempts
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = defaults.RetryBackoff
+	}
+	return &EmbeddingPipeline{
+		provider: provider,
+		store:    store,
+		usage:    usage,
+		config:   config,
+		queue:    make(chan EmbeddingJob, config.QueueSize),
+		logger:   telemetry.Logger(),
+		now:      time.Now,
+		sleep:    sleepContext,
+		stats:    EmbeddingPipelineStats{Provider: provider.Name(), Model: provider.Model()},
+	}, nil
+}
+
+// Enqueue queues a text for embedding without blocking.
+//
+// Parameters:
+//
+//	job: The text; jobs without text are ignored.
+//
+// Returns:
+//
+//	bool: False if the job was ignored or dropped on a full queue.
+func (p *EmbeddingPipeline) Enqueue(job EmbeddingJob) bool {
+	job.Text = strings.TrimSpace(job.Text)
+	if job.Text == "" || (job.decision == nil && (job.Kind == "" || job.SourceID == "")) {
+		return false
+	}
+	if job.CreatedAt.IsZero() {
+		job.CreatedAt = p.now().UTC()
+	}
+	select {
+	case p.queue <- job:
+		return true
+	default:
+		p.mu.Lock()
+		p.stats.Dropped++
+		p.mu.Unlock()
+		return false
+	}
+}
+
+// IndexDecision queues a decision and its realized outcome for embedding.
+//
+// Parameters:
+//
+//	ctx: Unused; the decision is embedded in the background.
+//	audit: The decision.
+//
+// Returns:
+//
+//	error: ErrEmbeddingQueueFull if the decision was dropped.
+func (p *EmbeddingPipeline) IndexDecision(_ context.Context, audit *DecisionAudit) error {
+	if audit == nil || audit.ID == "" {
+		return nil
+	}
+	// The audit is copied: callers keep modifying theirs after recording
+	decision := *audit
+	job := EmbeddingJob{
+		Kind:      EmbeddingKindDecision,
+		SourceID:  audit.ID,
+		Text:      decisionContextText(DecisionContext{Exchange: audit.Exchange, Symbol: audit.Symbol, MarketSnapshot: audit.MarketSnapshot}),
+		CreatedAt: audit.CreatedAt,
+		decision:  &decision,
+	}
+	if !p.Enqueue(job) {
+		return ErrEmbeddingQueueFull
+	}
+	return nil
+}
+
+// Stats returns the pipeline counters.
+func (p *EmbeddingPipeline) Stats() EmbeddingPipelineStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := p.stats
+	stats.Queued = len(p.queue)
+	return stats
+}
+
+// Start runs the pipeline until Stop is called.
+//
+// Parameters:
+//
+//	ctx: Context bounding the pipeline's lifetime.
+//
+// Returns:
+//
+//	error: Error if the pipeline is already running.
+func (p *EmbeddingPipeline) Start(ctx context.Context) error {
+	p.runMu.Lock()
+	defer p.runMu.Unlock()
+	if p.cancel != nil {
+		return fmt.Errorf("embedding pipeline already running")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	p.cancel = cancel
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(p.config.FlushInterval)
+		defer ticker.Stop()
+		// Stop does not abort a batch in flight, or its texts would be lost
+		flushCtx := context.WithoutCancel(ctx)
+		batch := make([]EmbeddingJob, 0, p.config.BatchSize)
+		for {
+			select {
+			case <-ctx.Done():
+				p.drain(flushCtx, batch)
+				return
+			case job := <-p.queue:
+				batch = append(batch, job)
+				if len(batch) >= p.config.BatchSize {
+					p.flush(flushCtx, batch)
+					batch = batch[:0]
+				}
+			case <-ticker.C:
+				if len(batch) > 0 {
+					p.flush(flushCtx, batch)
+					batch = batch[:0]
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the pipeline after embedding the texts still queued.
+func (p *EmbeddingPipeline) Stop() {
+	p.runMu.Lock()
+	cancel := p.cancel
+	p.cancel = nil
+	p.runMu.Unlock()
+	if cancel != nil {
+		cancel()
+		p.wg.Wait()
+	}
+}
+
+// drain embeds the current batch and the texts still queued, within a
+// shutdown deadline.
+func (p *EmbeddingPipeline) drain(ctx context.Context, batch []EmbeddingJob) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	for {
+		select {
+		case job := <-p.queue:
+			batch = append(batch, job)
+			if len(batch) < p.config.BatchSize {
+				continue
+			}
+		default:
+		}
+		if len(batch) == 0 || ctx.Err() != nil {
+			return
+		}
+		p.flush(ctx, batch)
+		batch = batch[:0]
+	}
+}
+
+// flush embeds a batch and stores its vectors.
+func (p *EmbeddingPipeline) flush(ctx context.Context, jobs []EmbeddingJob) {
+	texts := make([]string, len(jobs))
+	for i, job := range jobs {
+		texts[i] = job.Text
+	}
+
+	started := p.now()
+	batch, err := p.embedWithRetry(ctx, texts)
+	latency := int(p.now().Sub(started).Milliseconds())
+	if err != nil {
+		p.logger.Warn("Embedding batch failed", "texts", len(jobs), "error", err)
+		p.recordUsage(ctx, 0, latency, err)
+		p.mu.Lock()
+		p.stats.Failed += int64(len(jobs))
+		p.stats.LastError = err.Error()
+		p.mu.Unlock()
+		return
+	}
+	cost := p.recordUsage(ctx, batch.InputTokens, latency, nil)
+
+	var stored, failed int64
+	var lastErr error
+	for i, job := range jobs {
+		if err := p.storeVector(ctx, job, batch.Vectors[i]); err != nil {
+			failed++
+			lastErr = err
+			continue
+		}
+		stored++
+	}
+	if lastErr != nil {
+		p.logger.Warn("Failed to store embeddings", "failed", failed, "error", lastErr)
+	}
+
+	now := p.now().UTC()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stats.Batches++
+	p.stats.Embedded += stored
+	p.stats.Failed += failed
+	p.stats.InputTokens += int64(batch.InputTokens)
+	p.stats.CostUSD = p.stats.CostUSD.Add(cost)
+	p.stats.LastBatchAt = &now
+	if lastErr != nil {
+		p.stats.LastError = lastErr.Error()
+	}
+}
+
+// embedWithRetry sends a batch until it succeeds, fails permanently or runs
+// out of attempts, doubling the wait between attempts.
+func (p *EmbeddingPipeline) embedWithRetry(ctx context.Context, texts []string) (*EmbeddingBatch, error) {
+	backoff := p.config.RetryBackoff
+	var err error
+	for attempt := 1; attempt <= p.config.MaxAttempts; attempt++ {
+		var batch *EmbeddingBatch
+		if batch, err = p.provider.EmbedBatch(ctx, texts); err == nil {
+			if len(batch.Vectors) != len(texts) {
+				return nil, fmt.Errorf("provider returned %d vectors for %d texts", len(batch.Vectors), len(texts))
+			}
+			return batch, nil
+		}
+		var providerErr *EmbeddingProviderError
+		if errors.As(err, &providerErr) && !providerErr.Retryable() {
+			return nil, err
+		}
+		if attempt == p.config.MaxAttempts {
+			break
+		}
+		p.logger.Debug("Retrying embedding batch", "attempt", attempt, "error", err)
+		if sleepErr := p.sleep(ctx, backoff); sleepErr != nil {
+			return nil, err
+		}
+		backoff *= 2
+	}
+	return nil, fmt.Errorf("after %d attempts: %w", p.config.MaxAttempts, err)
+}
+
+func (p *EmbeddingPipeline) storeVector(ctx context.Context, job EmbeddingJob, vector []float32) error {
+	if job.decision != nil {
+		return p.store.UpsertDecision(ctx, newDecisionVectorRecord(job.decision, vector, p.now()))
+	}
+	return p.store.UpsertDocument(ctx, VectorDocument{
+		Kind:      job.Kind,
+		SourceID:  job.SourceID,
+		Content:   job.Text,
+		UserID:    job.UserID,
+		CreatedAt: job.CreatedAt,
+		Embedding: vector,
+	})
+}
+
+// recordUsage stores the tokens and cost of a batch and returns the cost.
+// Local providers bill no tokens and are not recorded.
+func (p *EmbeddingPipeline) recordUsage(ctx context.Context, tokens, latencyMs int, batchErr error) decimal.Decimal {
+	cost := p.config.CostPer1KTokens.Mul(decimal.NewFromInt(int64(tokens))).Div(decimal.NewFromInt(1000))
+	if p.usage == nil || p.provider.Name() == EmbeddingProviderHashing {
+		return cost
+	}
+	usage := &models.AIUsageCreate{
+		Provider:     p.provider.Name(),
+		Model:        p.provider.Model(),
+		RequestType:  embeddingRequestType,
+		InputTokens:  tokens,
+		InputCostUSD: cost,
+		LatencyMs:    &latencyMs,
+		Status:       models.AIUsageStatusSuccess,
+		Metadata:     json.RawMessage("{}"),
+	}
+	if batchErr != nil {
+		message := batchErr.Error()
+		usage.Status = models.AIUsageStatusError
+		usage.ErrorMessage = &message
+	}
+	if _, err := p.usage.Create(ctx, usage); err != nil {
+		p.logger.Warn("Failed to record embedding usage", "error", err)
+	}
+	return cost
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
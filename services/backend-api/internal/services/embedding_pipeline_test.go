@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedEmbeddings embeds like the hashing embedder but fails with the
+// queued errors first and bills tokensPerText for each text.
+type scriptedEmbeddings struct {
+	*HashingEmbedder
+	errs          []error
+	batches       [][]string
+	tokensPerText int
+}
+
+func (s *scriptedEmbeddings) EmbedBatch(ctx context.Context, texts []string) (*EmbeddingBatch, error) {
+	s.batches = append(s.batches, texts)
+	if len(s.errs) > 0 {
+		err := s.errs[0]
+		s.errs = s.errs[1:]
+		return nil, err
+	}
+	batch, err := s.HashingEmbedder.EmbedBatch(ctx, texts)
+	batch.InputTokens = s.tokensPerText * len(texts)
+	return batch, err
+}
+
+func (s *scriptedEmbeddings) Name() string  { return EmbeddingProviderOpenAI }
+func (s *scriptedEmbeddings) Model() string { return "test-embedding" }
+
+type recordingUsage struct {
+	usages []*models.AIUsageCreate
+}
+
+func (r *recordingUsage) Create(_ context.Context, usage *models.AIUsageCreate) (*models.AIUsage, error) {
+	r.usages = append(r.usages, usage)
+	return &models.AIUsage{}, nil
+}
+
+func newTestEmbeddingPipeline(t *testing.T, config EmbeddingPipelineConfig) (*EmbeddingPipeline, *scriptedEmbeddings, *recordingUsage, *DecisionMemory) {
+	t.Helper()
+	store, err := NewSQLiteVectorStore(setupTestDB(t), 0)
+	require.NoError(t, err)
+	provider := &scriptedEmbeddings{HashingEmbedder: NewHashingEmbedder(0), tokensPerText: 10}
+	usage := &recordingUsage{}
+	pipeline, err := NewEmbeddingPipeline(provider, store, usage, config)
+	require.NoError(t, err)
+	pipeline.sleep = func(context.Context, time.Duration) error { return nil }
+	memory, err := NewDecisionMemory(store, provider)
+	require.NoError(t, err)
+	return pipeline, provider, usage, memory
+}
+
+func TestEmbeddingPipeline_EmbedsInBatches(t *testing.T) {
+	pipeline, provider, usage, memory := newTestEmbeddingPipeline(t, EmbeddingPipelineConfig{
+		BatchSize:       2,
+		FlushInterval:   time.Hour,
+		CostPer1KTokens: decimal.RequireFromString("0.02"),
+	})
+	published := time.Date(2026, 9, 1, 8, 0, 0, 0, time.UTC)
+
+	assert.True(t, pipeline.Enqueue(EmbeddingJob{Kind: EmbeddingKindNews, SourceID: "https://news.example/etf", Text: "Bitcoin ETF inflows hit a record (BTC)", CreatedAt: published}))
+	assert.True(t, pipeline.Enqueue(EmbeddingJob{Kind: EmbeddingKindNote, SourceID: "note-1", Text: "Avoid SOL longs during the unlock week"}))
+	audit := indexedDecision("dec_a", "BTC/USDT", "buy", `{"symbol":"BTC/USDT","ob_imbalance":0.35}`, 2.5, published)
+	require.NoError(t, pipeline.IndexDecision(t.Context(), audit))
+	assert.False(t, pipeline.Enqueue(EmbeddingJob{Kind: EmbeddingKindNote, SourceID: "note-2", Text: "  "}), "empty texts are ignored")
+	assert.Equal(t, 3, pipeline.Stats().Queued)
+
+	require.NoError(t, pipeline.Start(t.Context()))
+	assert.Error(t, pipeline.Start(t.Context()))
+	// Stop embeds what is still queued without waiting for the flush interval
+	pipeline.Stop()
+
+	require.Len(t, provider.batches, 2)
+	assert.Len(t, provider.batches[0], 2)
+	assert.Len(t, provider.batches[1], 1)
+
+	news, err := memory.FindSimilarDocuments(t.Context(), EmbeddingKindNews, "bitcoin etf inflows record", 0)
+	require.NoError(t, err)
+	require.Len(t, news, 1)
+	assert.Equal(t, "https://news.example/etf", news[0].SourceID)
+	assert.True(t, news[0].CreatedAt.Equal(published))
+	notes, err := memory.FindSimilarDocuments(t.Context(), EmbeddingKindNote, "sol unlock week longs", 0)
+	require.NoError(t, err)
+	require.Len(t, notes, 1)
+	assert.Equal(t, "Avoid SOL longs during the unlock week", notes[0].Content)
+
+	similar, err := memory.FindSimilarDecisions(t.Context(), DecisionContext{Exchange: "binance", Symbol: "BTC/USDT", MarketSnapshot: audit.MarketSnapshot}, 0)
+	require.NoError(t, err)
+	require.Len(t, similar, 1)
+	assert.Equal(t, "dec_a", similar[0].DecisionID)
+
+	require.Len(t, usage.usages, 2)
+	assert.Equal(t, "embedding", usage.usages[0].RequestType)
+	assert.Equal(t, "test-embedding", usage.usages[0].Model)
+	assert.Equal(t, 20, usage.usages[0].InputTokens)
+	assert.Equal(t, "0.0004", usage.usages[0].InputCostUSD.String())
+	assert.Equal(t, models.AIUsageStatusSuccess, usage.usages[0].Status)
+
+	stats := pipeline.Stats()
+	assert.Equal(t, int64(3), stats.Embedded)
+	assert.Equal(t, int64(2), stats.Batches)
+	assert.Equal(t, int64(30), stats.InputTokens)
+	assert.Equal(t, "0.0006", stats.CostUSD.String())
+	assert.NotNil(t, stats.LastBatchAt)
+}
+
+func TestEmbeddingPipeline_Retries(t *testing.T) {
+	pipeline, provider, usage, _ := newTestEmbeddingPipeline(t, EmbeddingPipelineConfig{MaxAttempts: 3, RetryBackoff: time.Second})
+	var waits []time.Duration
+	pipeline.sleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	jobs := []EmbeddingJob{{Kind: EmbeddingKindNote, SourceID: "note-1", Text: "funding flips negative before squeezes"}}
+
+	provider.errs = []error{&EmbeddingProviderError{Message: "connection reset"}, &EmbeddingProviderError{StatusCode: 429, Message: "rate limited"}}
+	pipeline.flush(t.Context(), jobs)
+	assert.Len(t, provider.batches, 3)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, waits)
+	assert.Equal(t, int64(1), pipeline.Stats().Embedded)
+
+	// Client errors are not retried
+	provider.batches, waits = nil, nil
+	provider.errs = []error{&EmbeddingProviderError{StatusCode: 400, Message: "input too long"}}
+	pipeline.flush(t.Context(), jobs)
+	assert.Len(t, provider.batches, 1)
+	assert.Empty(t, waits)
+
+	// Attempts run out on persistent server errors
+	provider.batches = nil
+	provider.errs = []error{errors.New("boom"), errors.New("boom"), errors.New("boom")}
+	pipeline.flush(t.Context(), jobs)
+	assert.Len(t, provider.batches, 3)
+
+	stats := pipeline.Stats()
+	assert.Equal(t, int64(2), stats.Failed)
+	assert.Equal(t, "after 3 attempts: boom", stats.LastError)
+	require.Len(t, usage.usages, 3)
+	assert.Equal(t, models.AIUsageStatusError, usage.usages[1].Status)
+	require.NotNil(t, usage.usages[1].ErrorMessage)
+	assert.Contains(t, *usage.usages[1].ErrorMessage, "input too long")
+}
+
+func TestEmbeddingPipeline_DropsWhenFull(t *testing.T) {
+	pipeline, _, _, _ := newTestEmbeddingPipeline(t, EmbeddingPipelineConfig{QueueSize: 1})
+
+	assert.True(t, pipeline.Enqueue(EmbeddingJob{Kind: EmbeddingKindNote, SourceID: "note-1", Text: "first"}))
+	assert.False(t, pipeline.Enqueue(EmbeddingJob{Kind: EmbeddingKindNote, SourceID: "note-2", Text: "second"}))
+	assert.ErrorIs(t, pipeline.IndexDecision(t.Context(), indexedDecision("dec_a", "BTC/USDT", "buy", `{}`, 0, time.Now())), ErrEmbeddingQueueFull)
+	assert.Equal(t, int64(2), pipeline.Stats().Dropped)
+}
+
+func TestNewEmbeddingPipeline_DimensionMismatch(t *testing.T) {
+	store, err := NewSQLiteVectorStore(setupTestDB(t), 128)
+	require.NoError(t, err)
+
+	_, err = NewEmbeddingPipeline(NewHashingEmbedder(256), store, nil, EmbeddingPipelineConfig{})
+	assert.EqualError(t, err, "embedding provider has 256 dimensions, vector store has 128")
+}
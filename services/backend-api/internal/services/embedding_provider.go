@@ -0,0 +1,222 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Embedding providers selectable with EMBEDDING_PROVIDER.
+const (
+	EmbeddingProviderHashing = "hashing"
+	EmbeddingProviderOpenAI  = "openai"
+)
+
+// DefaultOpenAIEmbeddingModel supports the dimensions parameter, so its
+// vectors fit the 256-dimension stores.
+const DefaultOpenAIEmbeddingModel = "text-embedding-3-small"
+
+// EmbeddingBatch is the result of embedding several texts in one request.
+type EmbeddingBatch struct {
+	// Vectors are in the order of the texts.
+	Vectors     [][]float32
+	InputTokens int
+}
+
+// EmbeddingProvider embeds texts in batches. Providers are also
+// DecisionEmbedders so that queries are embedded like the stored vectors.
+type EmbeddingProvider interface {
+	DecisionEmbedder
+	EmbedBatch(ctx context.Context, texts []string) (*EmbeddingBatch, error)
+	Name() string
+	Model() string
+}
+
+// EmbeddingProviderError is a failed embedding request.
+type EmbeddingProviderError struct {
+	// StatusCode is the HTTP status; zero when no response was received.
+	StatusCode int
+	Message    string
+}
+
+func (e *EmbeddingProviderError) Error() string {
+	if e.StatusCode == 0 {
+		return "embedding request failed: " + e.Message
+	}
+	return fmt.Sprintf("embedding request failed with status %d: %s", e.StatusCode, e.Message)
+}
+
+// Retryable reports whether the request may succeed when sent again: on
+// network errors, rate limits and server errors.
+func (e *EmbeddingProviderError) Retryable() bool {
+	return e.StatusCode == 0 || e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+// Ensure HashingEmbedder implements EmbeddingProvider.
+var _ EmbeddingProvider = (*HashingEmbedder)(nil)
+
+// EmbedBatch embeds each text locally; no tokens are billed.
+func (e *HashingEmbedder) EmbedBatch(ctx context.Context, texts []string) (*EmbeddingBatch, error) {
+	batch := &EmbeddingBatch{Vectors: make([][]float32, len(texts))}
+	for i, text := range texts {
+		batch.Vectors[i], _ = e.Embed(ctx, text)
+	}
+	return batch, nil
+}
+
+// Name returns the provider name.
+func (e *HashingEmbedder) Name() string {
+	return EmbeddingProviderHashing
+}
+
+// Model returns the model name, which includes the vector size.
+func (e *HashingEmbedder) Model() string {
+	return "feature-hash-" + strconv.Itoa(e.dims)
+}
+
+// OpenAIEmbedderConfig configures an OpenAI-compatible embeddings endpoint.
+type OpenAIEmbedderConfig struct {
+	BaseURL string
+	APIKey  string
+	Model   string
+	// Dimensions is requested from the model and checked on every vector.
+	Dimensions int
+	Timeout    time.Duration
+}
+
+// OpenAIEmbedder embeds texts with an OpenAI-compatible /embeddings endpoint.
+type OpenAIEmbedder struct {
+	config OpenAIEmbedderConfig
+	client *http.Client
+}
+
+// Ensure OpenAIEmbedder implements EmbeddingProvider.
+var _ EmbeddingProvider = (*OpenAIEmbedder)(nil)
+
+// NewOpenAIEmbedder creates an OpenAI-compatible embedder.
+//
+// Parameters:
+//
+//	config: Endpoint configuration; empty values use the OpenAI API,
+//	text-embedding-3-small, 256 dimensions and a 30s timeout.
+//
+// Returns:
+//
+//	*OpenAIEmbedder: Initialized embedder.
+func NewOpenAIEmbedder(config OpenAIEmbedderConfig) *OpenAIEmbedder {
+	if config.BaseURL == "" {
+		config.BaseURL = "https://api.openai.com/v1"
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	if config.Model == "" {
+		config.Model = DefaultOpenAIEmbeddingModel
+	}
+	if config.Dimensions <= 0 {
+		config.Dimensions = DefaultDecisionEmbeddingDims
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	return &OpenAIEmbedder{config: config, client: &http.Client{Timeout: config.Timeout}}
+}
+
+// Name returns the provider name.
+func (e *OpenAIEmbedder) Name() string {
+	return EmbeddingProviderOpenAI
+}
+
+// Model returns the embedding model.
+func (e *OpenAIEmbedder) Model() string {
+	return e.config.Model
+}
+
+// Dimensions returns the vector size.
+func (e *OpenAIEmbedder) Dimensions() int {
+	return e.config.Dimensions
+}
+
+// Embed embeds a single text.
+func (e *OpenAIEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	batch, err := e.EmbedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return batch.Vectors[0], nil
+}
+
+// EmbedBatch embeds texts in one request. Vectors are normalized so that
+// stores may compare them by L2 distance.
+func (e *OpenAIEmbedder) EmbedBatch(ctx context.Context, texts []string) (*EmbeddingBatch, error) {
+	body, err := json.Marshal(map[string]any{
+		"model":      e.config.Model,
+		"input":      texts,
+		"dimensions": e.config.Dimensions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal embedding request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.config.BaseURL+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create embedding request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.config.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.config.APIKey)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, &EmbeddingProviderError{Message: err.Error()}
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 64<<20))
+	if err != nil {
+		return nil, &EmbeddingProviderError{Message: err.Error()}
+	}
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		message := strings.TrimSpace(string(respBody))
+		if json.Unmarshal(respBody, &failure) == nil && failure.Error.Message != "" {
+			message = failure.Error.Message
+		}
+		return nil, &EmbeddingProviderError{StatusCode: resp.StatusCode, Message: truncate(message, 200)}
+	}
+
+	var decoded struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+		Usage struct {
+			PromptTokens int `json:"prompt_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.Unmarshal(respBody, &decoded); err != nil {
+		return nil, fmt.Errorf("decode embedding response: %w", err)
+	}
+	if len(decoded.Data) != len(texts) {
+		return nil, fmt.Errorf("embedding response has %d vectors for %d texts", len(decoded.Data), len(texts))
+	}
+	sort.Slice(decoded.Data, func(i, j int) bool { return decoded.Data[i].Index < decoded.Data[j].Index })
+
+	batch := &EmbeddingBatch{Vectors: make([][]float32, len(texts)), InputTokens: decoded.Usage.PromptTokens}
+	for i, item := range decoded.Data {
+		if len(item.Embedding) != e.config.Dimensions {
+			return nil, fmt.Errorf("embedding model returned %d dimensions, expected %d", len(item.Embedding), e.config.Dimensions)
+		}
+		normalizeVector(item.Embedding)
+		batch.Vectors[i] = item.Embedding
+	}
+	return batch, nil
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAIEmbedder_EmbedBatch(t *testing.T) {
+	var request struct {
+		Model      string   `json:"model"`
+		Input      []string `json:"input"`
+		Dimensions int      `json:"dimensions"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/embeddings", r.URL.Path)
+		assert.Equal(t, "Bearer sk-test", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		// Vectors may come back out of order
+		_, _ = w.Write([]byte(`{"data":[{"index":1,"embedding":[0,2]},{"index":0,"embedding":[3,4]}],"usage":{"prompt_tokens":7}}`))
+	}))
+	defer server.Close()
+
+	embedder := NewOpenAIEmbedder(OpenAIEmbedderConfig{BaseURL: server.URL + "/v1/", APIKey: "sk-test", Dimensions: 2})
+	batch, err := embedder.EmbedBatch(t.Context(), []string{"first", "second"})
+	require.NoError(t, err)
+
+	assert.Equal(t, DefaultOpenAIEmbeddingModel, request.Model)
+	assert.Equal(t, []string{"first", "second"}, request.Input)
+	assert.Equal(t, 2, request.Dimensions)
+	assert.Equal(t, 7, batch.InputTokens)
+	require.Len(t, batch.Vectors, 2)
+	assert.InDeltaSlice(t, []float32{0.6, 0.8}, batch.Vectors[0], 1e-6, "vectors are ordered and normalized")
+	assert.InDeltaSlice(t, []float32{0, 1}, batch.Vectors[1], 1e-6)
+
+	_, err = NewOpenAIEmbedder(OpenAIEmbedderConfig{BaseURL: server.URL + "/v1", APIKey: "sk-test", Dimensions: 3}).Embed(t.Context(), "first")
+	assert.Error(t, err, "vectors of the wrong size are rejected")
+}
+
+func TestOpenAIEmbedder_Errors(t *testing.T) {
+	status := http.StatusTooManyRequests
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"error":{"message":"slow down"}}`))
+	}))
+	defer server.Close()
+	embedder := NewOpenAIEmbedder(OpenAIEmbedderConfig{BaseURL: server.URL})
+
+	_, err := embedder.EmbedBatch(t.Context(), []string{"text"})
+	var providerErr *EmbeddingProviderError
+	require.True(t, errors.As(err, &providerErr))
+	assert.Equal(t, "embedding request failed with status 429: slow down", err.Error())
+	assert.True(t, providerErr.Retryable())
+
+	status = http.StatusBadRequest
+	_, err = embedder.EmbedBatch(t.Context(), []string{"text"})
+	require.True(t, errors.As(err, &providerErr))
+	assert.False(t, providerErr.Retryable())
+}
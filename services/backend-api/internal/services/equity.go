@@ -0,0 +1,407 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	defaultEquityCurrency = "USDT"
+	defaultEquityTimeout  = 5 * time.Second
+	defaultEquityCacheTTL = 15 * time.Second
+)
+
+// equityParCurrencies are valued one-to-one with the equity currency.
+var equityParCurrencies = map[string]bool{
+	"USDT": true, "USDC": true, "USD": true, "FDUSD": true, "BUSD": true, "DAI": true, "TUSD": true,
+}
+
+// ExternalWallet is a wallet connected outside the exchange accounts.
+type ExternalWallet struct {
+	WalletID string `json:"wallet_id"`
+	Provider string `json:"provider"`
+	Address  string `json:"address"`
+}
+
+// ExternalWalletSource lists a chat's connected external wallets.
+type ExternalWalletSource interface {
+	ExternalWallets(ctx context.Context, chatID string) ([]ExternalWallet, error)
+}
+
+// WalletValuer values an external wallet in the equity currency.
+type WalletValuer interface {
+	ValueWallet(ctx context.Context, address string) (decimal.Decimal, error)
+}
+
+// EquityConfig configures account valuation.
+type EquityConfig struct {
+	// Exchanges are the exchange accounts whose balances count; defaults to binance.
+	Exchanges []string
+	// Currency is the valuation currency and the quote of the tickers that
+	// price other assets; defaults to USDT.
+	Currency string
+	// Timeout bounds each balance, ticker and wallet request.
+	Timeout time.Duration
+	// CacheTTL is how long exchange valuations are reused across callers.
+	CacheTTL time.Duration
+}
+
+// AssetValuation is one exchange asset valued in the equity currency.
+type AssetValuation struct {
+	Asset     string          `json:"asset"`
+	Total     decimal.Decimal `json:"total"`
+	Free      decimal.Decimal `json:"free"`
+	Price     decimal.Decimal `json:"price"`
+	Value     decimal.Decimal `json:"value"`
+	FreeValue decimal.Decimal `json:"free_value"`
+}
+
+// ExchangeEquity is the valued balance of one exchange account.
+type ExchangeEquity struct {
+	Exchange  string           `json:"exchange"`
+	Total     decimal.Decimal  `json:"total"`
+	Available decimal.Decimal  `json:"available"`
+	Assets    []AssetValuation `json:"assets"`
+	Error     string           `json:"error,omitempty"`
+}
+
+// ExternalWalletEquity is the value of one external wallet.
+type ExternalWalletEquity struct {
+	WalletID string          `json:"wallet_id"`
+	Provider string          `json:"provider"`
+	Value    decimal.Decimal `json:"value"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// EquitySnapshot is a chat's account equity. TotalEquity is the exchange
+// balances plus the unrealized PnL of open derivative positions plus the
+// external wallets; spot positions are already valued through the assets
+// they hold. When the chat trades a budget, Reserve is the part of the
+// account kept out of trading and TradingEquity and AvailableBalance exclude it.
+type EquitySnapshot struct {
+	Currency         string                 `json:"currency"`
+	TotalEquity      decimal.Decimal        `json:"total_equity"`
+	TradingEquity    decimal.Decimal        `json:"trading_equity"`
+	AvailableBalance decimal.Decimal        `json:"available_balance"`
+	ExchangeBalance  decimal.Decimal        `json:"exchange_balance"`
+	UnrealizedPnL    decimal.Decimal        `json:"unrealized_pnl"`
+	ExternalBalance  decimal.Decimal        `json:"external_balance"`
+	Reserve          decimal.Decimal        `json:"reserve"`
+	Exposure         decimal.Decimal        `json:"exposure"`
+	Exchanges        []ExchangeEquity       `json:"exchanges"`
+	Wallets          []ExternalWalletEquity `json:"wallets"`
+	Warnings         []string               `json:"warnings,omitempty"`
+	UpdatedAt        time.Time              `json:"updated_at"`
+}
+
+// EquityService values the trading account in one place so the portfolio,
+// balance and report endpoints, the daily loss circuit and fund growth goals
+// all see the same numbers.
+type EquityService struct {
+	balances  MarginBalanceFetcher
+	tickers   TickerFetcher
+	positions MarkedPositionSource
+	wallets   ExternalWalletSource
+	valuer    WalletValuer
+	reserves  TradingReserveSource
+	config    EquityConfig
+	logger    *slog.Logger
+	now       func() time.Time
+
+	mu        sync.Mutex
+	cached    []ExchangeEquity
+	cachedAt  time.Time
+	cacheFill sync.Mutex
+}
+
+var (
+	_ EquitySource        = (*EquityService)(nil)
+	_ AccountEquitySource = (*EquityService)(nil)
+)
+
+// NewEquityService creates the equity service.
+//
+// Parameters:
+//
+//	balances: Exchange balance source.
+//	tickers: Prices non-stablecoin assets (may be nil to count stablecoins only).
+//	config: Valuation configuration; zero values use defaults.
+//
+// Returns:
+//
+//	*EquityService: Initialized service.
+func NewEquityService(balances MarginBalanceFetcher, tickers TickerFetcher, config EquityConfig) *EquityService {
+	if len(config.Exchanges) == 0 {
+		config.Exchanges = []string{"binance"}
+	}
+	if config.Currency == "" {
+		config.Currency = defaultEquityCurrency
+	}
+	config.Currency = strings.ToUpper(config.Currency)
+	if config.Timeout <= 0 {
+		config.Timeout = defaultEquityTimeout
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = defaultEquityCacheTTL
+	}
+	return &EquityService{
+		balances: balances,
+		tickers:  tickers,
+		config:   config,
+		logger:   telemetry.Logger().With("component", "equity"),
+		now:      time.Now,
+	}
+}
+
+// SetPositionSource adds open-position PnL and exposure to the equity.
+func (s *EquityService) SetPositionSource(positions MarkedPositionSource) {
+	s.positions = positions
+}
+
+// SetExternalWallets adds the chat's external wallets to the equity.
+func (s *EquityService) SetExternalWallets(wallets ExternalWalletSource, valuer WalletValuer) {
+	s.wallets = wallets
+	s.valuer = valuer
+}
+
+// SetReserveSource leaves each chat's reserve out of its trading equity.
+func (s *EquityService) SetReserveSource(reserves TradingReserveSource) {
+	s.reserves = reserves
+}
+
+// Equity returns the chat's trading equity: exchange balances plus
+// unrealized derivative PnL, less the reserve when the chat trades a budget.
+// External wallets are left out because they are not traded, so loss caps,
+// growth goals and reports track the traded capital only.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	chatID: Chat; all chats share the exchange accounts but may reserve
+//	different parts of them.
+//
+// Returns:
+//
+//	decimal.Decimal: Trading equity.
+//	error: Error if an exchange balance or the reserve is unavailable.
+func (s *EquityService) Equity(ctx context.Context, chatID string) (decimal.Decimal, error) {
+	equity, err := s.AccountEquity(ctx)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if s.reserves == nil || chatID == "" {
+		return equity, nil
+	}
+	reserve, _, err := s.reserves.Reserve(ctx, chatID)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("reserve unavailable: %w", err)
+	}
+	return equity.Sub(reserve), nil
+}
+
+// AccountEquity returns the exchange balances plus unrealized derivative
+// PnL, before any chat's reserve.
+//
+// Returns:
+//
+//	decimal.Decimal: Account equity.
+//	error: Error if an exchange balance is unavailable.
+func (s *EquityService) AccountEquity(ctx context.Context) (decimal.Decimal, error) {
+	exchanges := s.exchangeEquity(ctx)
+	total := decimal.Zero
+	for _, exchange := range exchanges {
+		if exchange.Error != "" {
+			return decimal.Zero, fmt.Errorf("%s balance unavailable: %s", exchange.Exchange, exchange.Error)
+		}
+		total = total.Add(exchange.Total)
+	}
+	pnl, _ := s.positionTotals()
+	return total.Add(pnl), nil
+}
+
+// Snapshot values a chat's exchange accounts, open positions and external
+// wallets. Parts that cannot be valued are reported in Warnings and left out
+// of the totals.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	chatID: Chat whose external wallets and reserve are included; empty skips them.
+//
+// Returns:
+//
+//	*EquitySnapshot: The valuation.
+func (s *EquityService) Snapshot(ctx context.Context, chatID string) *EquitySnapshot {
+	snapshot := &EquitySnapshot{
+		Currency:  s.config.Currency,
+		Exchanges: s.exchangeEquity(ctx),
+		Wallets:   []ExternalWalletEquity{},
+		UpdatedAt: s.now().UTC(),
+	}
+	for _, exchange := range snapshot.Exchanges {
+		if exchange.Error != "" {
+			snapshot.Warnings = append(snapshot.Warnings, fmt.Sprintf("%s balance unavailable: %s", exchange.Exchange, exchange.Error))
+			continue
+		}
+		snapshot.ExchangeBalance = snapshot.ExchangeBalance.Add(exchange.Total)
+		snapshot.AvailableBalance = snapshot.AvailableBalance.Add(exchange.Available)
+	}
+	snapshot.UnrealizedPnL, snapshot.Exposure = s.positionTotals()
+
+	if chatID != "" && s.wallets != nil && s.valuer != nil {
+		wallets, err := s.wallets.ExternalWallets(ctx, chatID)
+		if err != nil {
+			snapshot.Warnings = append(snapshot.Warnings, fmt.Sprintf("external wallets unavailable: %v", err))
+		}
+		for _, wallet := range wallets {
+			entry := ExternalWalletEquity{WalletID: wallet.WalletID, Provider: wallet.Provider}
+			walletCtx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+			value, err := s.valuer.ValueWallet(walletCtx, wallet.Address)
+			cancel()
+			if err != nil {
+				entry.Error = err.Error()
+				snapshot.Warnings = append(snapshot.Warnings, fmt.Sprintf("wallet %s unavailable: %v", wallet.WalletID, err))
+			} else {
+				entry.Value = value
+				snapshot.ExternalBalance = snapshot.ExternalBalance.Add(value)
+			}
+			snapshot.Wallets = append(snapshot.Wallets, entry)
+		}
+	}
+
+	for _, exchange := range snapshot.Exchanges {
+		for _, asset := range exchange.Assets {
+			if asset.Total.IsPositive() && !asset.Price.IsPositive() {
+				snapshot.Warnings = append(snapshot.Warnings, fmt.Sprintf("%s %s has no %s price", exchange.Exchange, asset.Asset, s.config.Currency))
+			}
+		}
+	}
+	snapshot.TradingEquity = snapshot.ExchangeBalance.Add(snapshot.UnrealizedPnL)
+	if chatID != "" && s.reserves != nil {
+		reserve, _, err := s.reserves.Reserve(ctx, chatID)
+		if err != nil {
+			snapshot.Warnings = append(snapshot.Warnings, fmt.Sprintf("reserve unavailable: %v", err))
+		} else {
+			snapshot.Reserve = reserve
+			snapshot.TradingEquity = snapshot.TradingEquity.Sub(reserve)
+			snapshot.AvailableBalance = decimal.Max(snapshot.AvailableBalance.Sub(reserve), decimal.Zero)
+		}
+	}
+	snapshot.TotalEquity = snapshot.ExchangeBalance.Add(snapshot.UnrealizedPnL).Add(snapshot.ExternalBalance)
+	return snapshot
+}
+
+// positionTotals returns the unrealized PnL of open derivative positions and
+// the notional of all open positions.
+func (s *EquityService) positionTotals() (decimal.Decimal, decimal.Decimal) {
+	pnl, exposure := decimal.Zero, decimal.Zero
+	if s.positions == nil {
+		return pnl, exposure
+	}
+	for _, position := range s.positions.GetOpenPositions() {
+		exposure = exposure.Add(position.Size.Mul(position.CurrentPrice).Abs())
+		if strings.Contains(position.Symbol, ":") {
+			pnl = pnl.Add(position.UnrealizedPL)
+		}
+	}
+	return pnl, exposure
+}
+
+// exchangeEquity values every configured exchange, reusing a recent
+// valuation so callers polling together hit the exchange once.
+func (s *EquityService) exchangeEquity(ctx context.Context) []ExchangeEquity {
+	s.cacheFill.Lock()
+	defer s.cacheFill.Unlock()
+
+	s.mu.Lock()
+	if s.cached != nil && s.now().Sub(s.cachedAt) < s.config.CacheTTL {
+		cached := s.cached
+		s.mu.Unlock()
+		return cached
+	}
+	s.mu.Unlock()
+
+	exchanges := make([]ExchangeEquity, 0, len(s.config.Exchanges))
+	complete := true
+	for _, name := range s.config.Exchanges {
+		exchange := s.valueExchange(ctx, name)
+		if exchange.Error != "" {
+			complete = false
+		}
+		exchanges = append(exchanges, exchange)
+	}
+	// Failures are retried on the next call rather than cached
+	if complete {
+		s.mu.Lock()
+		s.cached = exchanges
+		s.cachedAt = s.now()
+		s.mu.Unlock()
+	}
+	return exchanges
+}
+
+func (s *EquityService) valueExchange(ctx context.Context, name string) ExchangeEquity {
+	exchange := ExchangeEquity{Exchange: name, Assets: []AssetValuation{}}
+	balanceCtx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	balance, err := s.balances.FetchBalance(balanceCtx, name)
+	cancel()
+	if err != nil {
+		exchange.Error = err.Error()
+		return exchange
+	}
+	if balance == nil || balance.Total == nil {
+		exchange.Error = "balance response has no totals"
+		return exchange
+	}
+
+	for asset, total := range balance.Total {
+		if total <= 0 {
+			continue
+		}
+		valuation := AssetValuation{
+			Asset: strings.ToUpper(asset),
+			Total: decimal.NewFromFloat(total),
+			Free:  decimal.NewFromFloat(balance.Free[asset]),
+		}
+		valuation.Price = s.assetPrice(ctx, name, valuation.Asset)
+		valuation.Value = valuation.Total.Mul(valuation.Price)
+		valuation.FreeValue = valuation.Free.Mul(valuation.Price)
+		exchange.Total = exchange.Total.Add(valuation.Value)
+		exchange.Available = exchange.Available.Add(valuation.FreeValue)
+		exchange.Assets = append(exchange.Assets, valuation)
+	}
+	sort.Slice(exchange.Assets, func(i, j int) bool {
+		if !exchange.Assets[i].Value.Equal(exchange.Assets[j].Value) {
+			return exchange.Assets[i].Value.GreaterThan(exchange.Assets[j].Value)
+		}
+		return exchange.Assets[i].Asset < exchange.Assets[j].Asset
+	})
+	return exchange
+}
+
+// assetPrice returns the asset's price in the equity currency, or zero when
+// it has no market against it.
+func (s *EquityService) assetPrice(ctx context.Context, exchange, asset string) decimal.Decimal {
+	if asset == s.config.Currency || equityParCurrencies[asset] && equityParCurrencies[s.config.Currency] {
+		return decimal.NewFromInt(1)
+	}
+	if s.tickers == nil {
+		return decimal.Zero
+	}
+	tickerCtx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+	ticker, err := s.tickers.FetchSingleTicker(tickerCtx, exchange, asset+"/"+s.config.Currency)
+	if err != nil || ticker == nil || ticker.GetPrice() <= 0 {
+		s.logger.Debug("Asset left out of equity", "exchange", exchange, "asset", asset, "error", err)
+		return decimal.Zero
+	}
+	return decimal.NewFromFloat(ticker.GetPrice())
+}
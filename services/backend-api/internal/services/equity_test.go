@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingBalances struct {
+	marginTestBalances
+	calls int
+}
+
+func (b *countingBalances) FetchBalance(ctx context.Context, exchange string) (*ccxt.BalanceResponse, error) {
+	b.calls++
+	return b.marginTestBalances.FetchBalance(ctx, exchange)
+}
+
+type equityTestWallets []ExternalWallet
+
+func (w equityTestWallets) ExternalWallets(context.Context, string) ([]ExternalWallet, error) {
+	return w, nil
+}
+
+type equityTestValuer map[string]float64
+
+func (v equityTestValuer) ValueWallet(_ context.Context, address string) (decimal.Decimal, error) {
+	value, ok := v[address]
+	if !ok {
+		return decimal.Zero, errors.New("unknown address")
+	}
+	return decimal.NewFromFloat(value), nil
+}
+
+func TestEquityService_ValuesBalancesAndDerivativePnL(t *testing.T) {
+	balances := &countingBalances{marginTestBalances: marginTestBalances{
+		free:  map[string]float64{"USDT": 800, "BTC": 0.01, "USDC": 50},
+		total: map[string]float64{"USDT": 1000, "BTC": 0.02, "USDC": 50, "DOGE": 100},
+	}}
+	tickers := &fakeTickerFetcher{prices: map[string]float64{"binance:BTC/USDT": 50000}}
+	service := NewEquityService(balances, tickers, EquityConfig{})
+	service.SetPositionSource(marginTestPositions{
+		{Symbol: "ETH/USDT:USDT", Size: decimal.NewFromFloat(1), CurrentPrice: decimal.NewFromInt(2000), UnrealizedPL: decimal.NewFromInt(-150)},
+		{Symbol: "BTC/USDT", Size: decimal.NewFromFloat(0.02), CurrentPrice: decimal.NewFromInt(50000), UnrealizedPL: decimal.NewFromInt(40)},
+	})
+
+	snapshot := service.Snapshot(t.Context(), "")
+	assert.Equal(t, "2050", snapshot.ExchangeBalance.String())
+	assert.Equal(t, "1350", snapshot.AvailableBalance.String())
+	assert.Equal(t, "-150", snapshot.UnrealizedPnL.String(), "spot PnL is already in the asset balances")
+	assert.Equal(t, "3000", snapshot.Exposure.String())
+	assert.Equal(t, "1900", snapshot.TotalEquity.String())
+	assert.Equal(t, "BTC", snapshot.Exchanges[0].Assets[0].Asset)
+	assert.Contains(t, snapshot.Warnings, "binance DOGE has no USDT price")
+
+	equity, err := service.Equity(t.Context(), "42")
+	require.NoError(t, err)
+	assert.True(t, equity.Equal(snapshot.TotalEquity), "trading equity matches the snapshot without wallets")
+	assert.Equal(t, 1, balances.calls, "valuations are cached across callers")
+}
+
+func TestEquityService_ExchangeFailure(t *testing.T) {
+	balances := &countingBalances{marginTestBalances: marginTestBalances{err: errors.New("timeout")}}
+	service := NewEquityService(balances, nil, EquityConfig{})
+
+	_, err := service.Equity(t.Context(), "")
+	require.Error(t, err, "a missing balance must not read as a loss")
+
+	snapshot := service.Snapshot(t.Context(), "")
+	assert.True(t, snapshot.TotalEquity.IsZero())
+	assert.Equal(t, []string{"binance balance unavailable: timeout"}, snapshot.Warnings)
+	assert.Equal(t, 2, balances.calls, "failed valuations are not cached")
+
+	balances.err = nil
+	balances.total = map[string]float64{"USDT": 10}
+	equity, err := service.Equity(t.Context(), "")
+	require.NoError(t, err)
+	assert.Equal(t, "10", equity.String())
+}
+
+func TestEquityService_CacheExpires(t *testing.T) {
+	balances := &countingBalances{marginTestBalances: marginTestBalances{total: map[string]float64{"USDT": 10}}}
+	service := NewEquityService(balances, nil, EquityConfig{CacheTTL: time.Minute})
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	service.Snapshot(t.Context(), "")
+	now = now.Add(30 * time.Second)
+	service.Snapshot(t.Context(), "")
+	assert.Equal(t, 1, balances.calls)
+
+	now = now.Add(time.Minute)
+	service.Snapshot(t.Context(), "")
+	assert.Equal(t, 2, balances.calls)
+}
+
+func TestEquityService_ExternalWallets(t *testing.T) {
+	balances := &marginTestBalances{total: map[string]float64{"USDT": 100}}
+	service := NewEquityService(balances, nil, EquityConfig{})
+	service.SetExternalWallets(
+		equityTestWallets{{WalletID: "w1", Provider: "polymarket", Address: "0xabc"}, {WalletID: "w2", Provider: "metamask", Address: "0xdef"}},
+		equityTestValuer{"0xabc": 25.5},
+	)
+
+	snapshot := service.Snapshot(t.Context(), "42")
+	assert.Equal(t, "25.5", snapshot.ExternalBalance.String())
+	assert.Equal(t, "125.5", snapshot.TotalEquity.String())
+	require.Len(t, snapshot.Wallets, 2)
+	assert.Equal(t, "unknown address", snapshot.Wallets[1].Error)
+	assert.Contains(t, snapshot.Warnings, "wallet w2 unavailable: unknown address")
+
+	assert.Empty(t, service.Snapshot(t.Context(), "").Wallets, "wallets need a chat")
+
+	equity, err := service.Equity(t.Context(), "42")
+	require.NoError(t, err)
+	assert.Equal(t, "100", equity.String(), "external wallets are not trading equity")
+}
+
+func TestERC20WalletValuer_ValueWallet(t *testing.T) {
+	var request struct {
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		// 12.5 USDC with 6 decimals
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x0000000000000000000000000000000000000000000000000000000000bebc20"}`))
+	}))
+	defer server.Close()
+
+	valuer := NewERC20WalletValuer(server.URL, "0xTOKEN", 6)
+	value, err := valuer.ValueWallet(t.Context(), "0x1111111111111111111111111111111111111111")
+	require.NoError(t, err)
+	assert.Equal(t, "12.5", value.String())
+	assert.Equal(t, "eth_call", request.Method)
+	var call map[string]string
+	require.NoError(t, json.Unmarshal(request.Params[0], &call))
+	assert.Equal(t, "0xtoken", call["to"])
+	assert.Equal(t, "0x70a08231"+"000000000000000000000000"+"1111111111111111111111111111111111111111", call["data"])
+
+	_, err = valuer.ValueWallet(t.Context(), "not-an-address")
+	assert.Error(t, err)
+}
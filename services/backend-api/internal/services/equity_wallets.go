@@ -0,0 +1,149 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// erc20BalanceOfSelector is the ABI selector of balanceOf(address).
+const erc20BalanceOfSelector = "0x70a08231"
+
+// OperatorWalletSource lists the external and Polymarket wallets chats
+// connected through Telegram.
+type OperatorWalletSource struct {
+	db DBPool
+}
+
+// NewOperatorWalletSource creates a wallet source over telegram_operator_wallets.
+//
+// Parameters:
+//
+//	db: Database pool.
+//
+// Returns:
+//
+//	*OperatorWalletSource: The initialized source.
+func NewOperatorWalletSource(db DBPool) *OperatorWalletSource {
+	return &OperatorWalletSource{db: db}
+}
+
+// ExternalWallets returns the chat's connected wallets that are not
+// exchange accounts.
+func (s *OperatorWalletSource) ExternalWallets(ctx context.Context, chatID string) ([]ExternalWallet, error) {
+	if isNilDBPool(s.db) {
+		return nil, fmt.Errorf("database pool is not available")
+	}
+	rows, err := s.db.Query(ctx, `
+		SELECT wallet_id, provider, wallet_address
+		FROM telegram_operator_wallets
+		WHERE chat_id = $1 AND wallet_type <> 'exchange' AND status = 'connected'
+		ORDER BY created_at ASC`, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query wallets: %w", err)
+	}
+	defer rows.Close()
+
+	var wallets []ExternalWallet
+	for rows.Next() {
+		var wallet ExternalWallet
+		if err := rows.Scan(&wallet.WalletID, &wallet.Provider, &wallet.Address); err != nil {
+			return nil, fmt.Errorf("failed to scan wallet: %w", err)
+		}
+		wallets = append(wallets, wallet)
+	}
+	return wallets, rows.Err()
+}
+
+// ERC20WalletValuer values wallets by their balance of one USD stablecoin
+// token, such as USDC on Polygon for Polymarket wallets, read with an
+// eth_call to an EVM JSON-RPC endpoint.
+type ERC20WalletValuer struct {
+	rpcURL   string
+	token    string
+	decimals int32
+	client   *http.Client
+}
+
+// NewERC20WalletValuer creates an ERC-20 stablecoin wallet valuer.
+//
+// Parameters:
+//
+//	rpcURL: EVM JSON-RPC endpoint.
+//	token: Token contract address.
+//	decimals: Token decimals, e.g. 6 for USDC.
+//
+// Returns:
+//
+//	*ERC20WalletValuer: The initialized valuer.
+func NewERC20WalletValuer(rpcURL, token string, decimals int32) *ERC20WalletValuer {
+	return &ERC20WalletValuer{
+		rpcURL:   rpcURL,
+		token:    strings.ToLower(token),
+		decimals: decimals,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// ValueWallet returns the wallet's token balance.
+func (v *ERC20WalletValuer) ValueWallet(ctx context.Context, address string) (decimal.Decimal, error) {
+	address = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(address)), "0x")
+	if len(address) != 40 {
+		return decimal.Zero, fmt.Errorf("not an EVM address")
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "eth_call",
+		"params": []interface{}{
+			map[string]string{"to": v.token, "data": erc20BalanceOfSelector + strings.Repeat("0", 24) + address},
+			"latest",
+		},
+	})
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", v.rpcURL, bytes.NewReader(body))
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return decimal.Zero, fmt.Errorf("rpc returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Result string `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return decimal.Zero, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if result.Error != nil {
+		return decimal.Zero, fmt.Errorf("rpc error: %s", result.Error.Message)
+	}
+	raw := strings.TrimPrefix(result.Result, "0x")
+	if raw == "" {
+		return decimal.Zero, nil
+	}
+	balance, ok := new(big.Int).SetString(raw, 16)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("invalid balance %q", result.Result)
+	}
+	return decimal.NewFromBigInt(balance, -v.decimals), nil
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+
+	"github.com/irfndi/neuratrade/internal/services/eventbus"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+)
+
+// eventBusSubjects maps domain events onto event bus subjects.
+var eventBusSubjects = map[WebhookEventType]string{
+	WebhookEventTradeExecuted:   eventbus.SubjectFill,
+	WebhookEventRisk:            eventbus.SubjectRisk,
+	WebhookEventModeChanged:     eventbus.SubjectMode,
+	WebhookEventQuestCompleted:  eventbus.SubjectQuest,
+	WebhookEventListingDetected: eventbus.SubjectListing,
+}
+
+// EventBusEmitter publishes domain events to the internal event bus.
+type EventBusEmitter struct {
+	publisher eventbus.Publisher
+}
+
+// NewEventBusEmitter creates an emitter backed by an event bus publisher.
+//
+// Parameters:
+//   - publisher: The event bus.
+//
+// Returns:
+//   - *EventBusEmitter: The emitter.
+func NewEventBusEmitter(publisher eventbus.Publisher) *EventBusEmitter {
+	return &EventBusEmitter{
+		publisher: publisher,
+	}
+}
+
+// Emit publishes an event on its subject. Events without a subject are
+// ignored and publish failures are logged.
+func (e *EventBusEmitter) Emit(ctx context.Context, event WebhookEventType, data interface{}) {
+	subject, ok := eventBusSubjects[event]
+	if !ok {
+		return
+	}
+	publishEvent(ctx, e.publisher, subject, data)
+}
+
+// publishEvent publishes to the bus when one is configured. Failures are
+// logged so a bus outage never fails the operation that produced the event.
+func publishEvent(ctx context.Context, publisher eventbus.Publisher, subject string, data interface{}) {
+	if publisher == nil {
+		return
+	}
+	if err := publisher.Publish(context.WithoutCancel(ctx), subject, data); err != nil {
+		telemetry.Logger().Warn("Event bus publish failed", "subject", subject, "error", err)
+	}
+}
+
+// MultiEmitter fans events out to several emitters.
+type MultiEmitter []EventEmitter
+
+// Emit forwards the event to every emitter.
+func (m MultiEmitter) Emit(ctx context.Context, event WebhookEventType, data interface{}) {
+	for _, emitter := range m {
+		emitter.Emit(ctx, event, data)
+	}
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/irfndi/neuratrade/internal/services/eventbus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingPublisher struct {
+	subjects []string
+	data     []interface{}
+	err      error
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, subject string, data interface{}) error {
+	p.subjects = append(p.subjects, subject)
+	p.data = append(p.data, data)
+	return p.err
+}
+
+func TestEventBusEmitter_MapsEventsToSubjects(t *testing.T) {
+	publisher := &recordingPublisher{}
+	emitter := NewEventBusEmitter(publisher)
+
+	emitter.Emit(t.Context(), WebhookEventTradeExecuted, map[string]string{"order_id": "ord-1"})
+	emitter.Emit(t.Context(), WebhookEventRisk, nil)
+	emitter.Emit(t.Context(), WebhookEventModeChanged, nil)
+	emitter.Emit(t.Context(), WebhookEventQuestCompleted, nil)
+	emitter.Emit(t.Context(), WebhookEventListingDetected, nil)
+	emitter.Emit(t.Context(), WebhookEventTest, nil)
+
+	assert.Equal(t, []string{eventbus.SubjectFill, eventbus.SubjectRisk, eventbus.SubjectMode, eventbus.SubjectQuest, eventbus.SubjectListing}, publisher.subjects)
+
+	// Publish failures are logged, not propagated.
+	publisher.err = errors.New("redis down")
+	emitter.Emit(t.Context(), WebhookEventRisk, nil)
+	assert.Len(t, publisher.subjects, 6)
+}
+
+func TestMultiEmitter_FansOut(t *testing.T) {
+	first, second := &recordingEmitter{}, &recordingEmitter{}
+	MultiEmitter{first, second}.Emit(t.Context(), WebhookEventRisk, nil)
+
+	assert.Equal(t, []WebhookEventType{WebhookEventRisk}, first.events)
+	assert.Equal(t, []WebhookEventType{WebhookEventRisk}, second.events)
+}
+
+func TestSignalIngestService_PublishesOpportunities(t *testing.T) {
+	svc, _ := newTestSignalIngestService(t)
+	publisher := &recordingPublisher{}
+	svc.SetEventBus(publisher)
+
+	_, err := svc.Ingest(t.Context(), InboundSignal{Source: "quant-a", Symbol: "BTC/USDT", Side: "buy", Confidence: 0.8})
+	require.NoError(t, err)
+	_, err = svc.Ingest(t.Context(), InboundSignal{Source: "quant-a", Symbol: "BTC/USDT", Side: "buy", Confidence: 0.8})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{eventbus.SubjectOpportunity}, publisher.subjects, "duplicates are not published")
+	signal, ok := publisher.data[0].(*AggregatedSignal)
+	require.True(t, ok)
+	assert.Equal(t, "BTC/USDT", signal.Symbol)
+}
@@ -0,0 +1,230 @@
+// Package eventbus provides a subject-based event bus for communication
+// between services.
+//
+// Subjects are dot-separated tokens in the NATS style, e.g. events.fill.binance.
+// Subscription patterns may use "*" to match exactly one token and ">" as the
+// last token to match one or more remaining tokens.
+//
+// Two Redis-backed drivers are provided: "streams" (the default) delivers each
+// event at least once to every subscriber group using Redis Streams consumer
+// groups; "pubsub" uses Redis pub/sub and is fire-and-forget. The "nats"
+// driver delivers at least once using NATS JetStream durable consumers, for
+// deployments that run services on a NATS cluster instead of Redis.
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// SubjectOpportunity carries detected trading opportunities.
+	SubjectOpportunity = "events.opportunity"
+	// SubjectDecision carries trading decisions made by strategies or operators.
+	SubjectDecision = "events.decision"
+	// SubjectFill carries executed orders.
+	SubjectFill = "events.fill"
+	// SubjectRisk carries risk events such as kill switch changes.
+	SubjectRisk = "events.risk"
+	// SubjectMode carries execution mode changes.
+	SubjectMode = "events.mode"
+	// SubjectQuest carries completed quests.
+	SubjectQuest = "events.quest"
+	// SubjectListing carries symbols newly listed on an exchange.
+	SubjectListing = "events.listing"
+)
+
+const (
+	// DriverStreams delivers events at least once using Redis Streams.
+	DriverStreams = "streams"
+	// DriverPubSub delivers events at most once using Redis pub/sub.
+	DriverPubSub = "pubsub"
+	// DriverNATS delivers events at least once using NATS JetStream.
+	DriverNATS = "nats"
+)
+
+var (
+	// ErrInvalidSubject is returned for empty subjects, empty tokens or
+	// wildcards in a published subject.
+	ErrInvalidSubject = errors.New("invalid subject")
+	// ErrClosed is returned when publishing or subscribing on a closed bus.
+	ErrClosed = errors.New("event bus closed")
+)
+
+// Message is an event delivered to a handler.
+type Message struct {
+	ID        string          `json:"id"`
+	Subject   string          `json:"subject"`
+	Data      json.RawMessage `json:"data"`
+	Timestamp time.Time       `json:"timestamp"`
+	// Attempt is 1 on first delivery and increases on each retry.
+	Attempt int `json:"attempt"`
+}
+
+// Decode unmarshals the message data into v.
+func (m Message) Decode(v interface{}) error {
+	return json.Unmarshal(m.Data, v)
+}
+
+// Handler processes a message. Returning an error asks the bus to redeliver
+// the message when the driver supports it.
+type Handler func(ctx context.Context, msg Message) error
+
+// Subscription is an active subscription.
+type Subscription interface {
+	Unsubscribe() error
+}
+
+// Publisher publishes events.
+type Publisher interface {
+	Publish(ctx context.Context, subject string, data interface{}) error
+}
+
+// Bus publishes events and delivers them to subscribers.
+type Bus interface {
+	Publisher
+	// Subscribe delivers messages whose subject matches pattern. Subscribers
+	// sharing a group divide the messages between them; every group receives
+	// every message.
+	Subscribe(ctx context.Context, pattern, group string, handler Handler) (Subscription, error)
+	Close() error
+}
+
+// Config configures the event bus.
+type Config struct {
+	// Driver selects the implementation (DriverStreams, DriverPubSub or
+	// DriverNATS).
+	Driver string
+	// URL is the NATS server for DriverNATS (default nats://127.0.0.1:4222).
+	URL string
+	// Prefix namespaces Redis keys and channels, or names the JetStream
+	// streams (default "eventbus").
+	Prefix string
+	// MaxLen approximately caps the stream length (default 100000).
+	MaxLen int64
+	// Block is how long a stream read waits for new messages (default 1s).
+	Block time.Duration
+	// RedeliverAfter is how long a failed or unacknowledged message waits
+	// before it is delivered again (default 30s).
+	RedeliverAfter time.Duration
+	// MaxDeliveries is how many times a message is attempted before it is
+	// moved to the dead-letter stream (default 5).
+	MaxDeliveries int
+}
+
+func (c Config) withDefaults() Config {
+	if c.Driver == "" {
+		c.Driver = DriverStreams
+	}
+	if c.URL == "" {
+		c.URL = nats.DefaultURL
+	}
+	if c.Prefix == "" {
+		c.Prefix = "eventbus"
+	}
+	if c.MaxLen <= 0 {
+		c.MaxLen = 100000
+	}
+	if c.Block <= 0 {
+		c.Block = time.Second
+	}
+	if c.RedeliverAfter <= 0 {
+		c.RedeliverAfter = 30 * time.Second
+	}
+	if c.MaxDeliveries <= 0 {
+		c.MaxDeliveries = 5
+	}
+	return c
+}
+
+// New creates an event bus using the configured driver. The NATS driver
+// connects to cfg.URL and does not use the Redis client.
+//
+// Parameters:
+//   - client: Redis client (may be nil for DriverNATS).
+//   - cfg: Bus configuration.
+//
+// Returns:
+//   - Bus: The event bus.
+//   - error: Error if the driver is unknown, the client is missing or NATS is unreachable.
+func New(client *redis.Client, cfg Config) (Bus, error) {
+	cfg = cfg.withDefaults()
+	if cfg.Driver == DriverNATS {
+		return ConnectNATS(cfg)
+	}
+	if client == nil {
+		return nil, errors.New("event bus requires a redis client")
+	}
+	switch cfg.Driver {
+	case DriverStreams:
+		return NewStreamBus(client, cfg), nil
+	case DriverPubSub:
+		return NewPubSubBus(client, cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown event bus driver %q", cfg.Driver)
+	}
+}
+
+// ValidateSubject checks that a subject can be published to.
+//
+// Parameters:
+//   - subject: The subject.
+//
+// Returns:
+//   - error: ErrInvalidSubject if it is empty, has empty tokens or wildcards.
+func ValidateSubject(subject string) error {
+	if subject == "" {
+		return ErrInvalidSubject
+	}
+	for _, token := range strings.Split(subject, ".") {
+		if token == "" || token == "*" || token == ">" {
+			return fmt.Errorf("%w: %q", ErrInvalidSubject, subject)
+		}
+	}
+	return nil
+}
+
+// MatchSubject reports whether subject matches pattern.
+//
+// Parameters:
+//   - pattern: Pattern with optional "*" and trailing ">" wildcards.
+//   - subject: The subject.
+//
+// Returns:
+//   - bool: True when the subject matches.
+func MatchSubject(pattern, subject string) bool {
+	patternTokens := strings.Split(pattern, ".")
+	subjectTokens := strings.Split(subject, ".")
+	for i, token := range patternTokens {
+		if token == ">" {
+			return i == len(patternTokens)-1 && len(subjectTokens) > i
+		}
+		if i >= len(subjectTokens) {
+			return false
+		}
+		if token != "*" && token != subjectTokens[i] {
+			return false
+		}
+	}
+	return len(patternTokens) == len(subjectTokens)
+}
+
+// encodeData marshals published data unless it is already JSON.
+func encodeData(data interface{}) ([]byte, error) {
+	switch v := data.(type) {
+	case json.RawMessage:
+		return v, nil
+	case []byte:
+		if json.Valid(v) {
+			return v, nil
+		}
+	}
+	return json.Marshal(data)
+}
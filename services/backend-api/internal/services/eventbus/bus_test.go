@@ -0,0 +1,223 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return s, client
+}
+
+func testConfig(driver string) Config {
+	return Config{Driver: driver, Block: 20 * time.Millisecond, RedeliverAfter: 30 * time.Millisecond, MaxDeliveries: 3}
+}
+
+type collector struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func (c *collector) handle(_ context.Context, msg Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.messages = append(c.messages, msg)
+	return nil
+}
+
+func (c *collector) subjects() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	subjects := make([]string, 0, len(c.messages))
+	for _, msg := range c.messages {
+		subjects = append(subjects, msg.Subject)
+	}
+	return subjects
+}
+
+func TestMatchSubject(t *testing.T) {
+	tests := []struct {
+		pattern, subject string
+		want             bool
+	}{
+		{"events.fill", "events.fill", true},
+		{"events.fill", "events.fill.binance", false},
+		{"events.*", "events.fill", true},
+		{"events.*", "events.fill.binance", false},
+		{"events.*.binance", "events.fill.binance", true},
+		{"events.>", "events.fill", true},
+		{"events.>", "events.fill.binance", true},
+		{"events.>", "events", false},
+		{">", "events", true},
+		{"events.>.x", "events.fill.x", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, MatchSubject(tt.pattern, tt.subject), "%s ~ %s", tt.pattern, tt.subject)
+	}
+}
+
+func TestValidateSubject(t *testing.T) {
+	assert.NoError(t, ValidateSubject("events.fill.binance"))
+	for _, subject := range []string{"", "events..fill", "events.*", "events.>", ".events"} {
+		assert.ErrorIs(t, ValidateSubject(subject), ErrInvalidSubject, subject)
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, client := newTestClient(t)
+
+	bus, err := New(client, Config{})
+	require.NoError(t, err)
+	assert.IsType(t, &StreamBus{}, bus)
+
+	bus, err = New(client, Config{Driver: DriverPubSub})
+	require.NoError(t, err)
+	assert.IsType(t, &PubSubBus{}, bus)
+
+	_, err = New(client, Config{Driver: "carrier-pigeon"})
+	assert.Error(t, err)
+	_, err = New(nil, Config{})
+	assert.Error(t, err)
+
+	// NATS does not need Redis
+	server := newTestNATSServer(t)
+	bus, err = New(nil, Config{Driver: DriverNATS, URL: server.ClientURL()})
+	require.NoError(t, err)
+	assert.IsType(t, &NATSBus{}, bus)
+	require.NoError(t, bus.Close())
+	assert.True(t, bus.(*NATSBus).conn.IsClosed(), "a bus that connected closes its connection")
+}
+
+func TestBus_DeliversMatchingSubjects(t *testing.T) {
+	for _, driver := range []string{DriverStreams, DriverPubSub, DriverNATS} {
+		t.Run(driver, func(t *testing.T) {
+			var bus Bus
+			var err error
+			if driver == DriverNATS {
+				bus, err = NewNATSBus(newTestNATS(t), testConfig(driver))
+			} else {
+				_, client := newTestClient(t)
+				bus, err = New(client, testConfig(driver))
+			}
+			require.NoError(t, err)
+			defer func() { _ = bus.Close() }()
+
+			fills := &collector{}
+			all := &collector{}
+			_, err = bus.Subscribe(t.Context(), SubjectFill+".>", "fills", fills.handle)
+			require.NoError(t, err)
+			_, err = bus.Subscribe(t.Context(), "events.>", "audit", all.handle)
+			require.NoError(t, err)
+
+			require.NoError(t, bus.Publish(t.Context(), SubjectFill+".binance", map[string]string{"order_id": "ord-1"}))
+			require.NoError(t, bus.Publish(t.Context(), SubjectDecision, map[string]string{"action": "buy"}))
+			assert.ErrorIs(t, bus.Publish(t.Context(), "events.*", nil), ErrInvalidSubject)
+
+			require.Eventually(t, func() bool { return len(all.subjects()) == 2 }, 2*time.Second, 10*time.Millisecond)
+			assert.ElementsMatch(t, []string{"events.fill.binance", "events.decision"}, all.subjects())
+			assert.Equal(t, []string{"events.fill.binance"}, fills.subjects())
+
+			var payload map[string]string
+			require.NoError(t, fills.messages[0].Decode(&payload))
+			assert.Equal(t, "ord-1", payload["order_id"])
+			assert.NotEmpty(t, fills.messages[0].ID)
+			assert.False(t, fills.messages[0].Timestamp.IsZero())
+		})
+	}
+}
+
+func TestStreamBus_RedeliversFailedMessages(t *testing.T) {
+	_, client := newTestClient(t)
+	bus := NewStreamBus(client, testConfig(DriverStreams))
+	defer func() { _ = bus.Close() }()
+
+	var attempts []int
+	var mu sync.Mutex
+	_, err := bus.Subscribe(t.Context(), SubjectFill, "executor", func(_ context.Context, msg Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts = append(attempts, msg.Attempt)
+		if len(attempts) < 2 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, bus.Publish(t.Context(), SubjectFill, "fill"))
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(attempts) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	assert.Equal(t, []int{1, 2}, attempts)
+	mu.Unlock()
+
+	require.Eventually(t, func() bool {
+		pending, err := client.XPending(t.Context(), bus.stream, "executor:"+SubjectFill).Result()
+		return err == nil && pending.Count == 0
+	}, time.Second, 10*time.Millisecond, "the retried message is acknowledged")
+}
+
+func TestStreamBus_DeadLettersAfterMaxDeliveries(t *testing.T) {
+	_, client := newTestClient(t)
+	bus := NewStreamBus(client, testConfig(DriverStreams))
+	defer func() { _ = bus.Close() }()
+
+	var calls atomic.Int32
+	_, err := bus.Subscribe(t.Context(), SubjectRisk, "risk", func(context.Context, Message) error {
+		calls.Add(1)
+		panic("boom")
+	})
+	require.NoError(t, err)
+	require.NoError(t, bus.Publish(t.Context(), SubjectRisk, "risk"))
+
+	require.Eventually(t, func() bool {
+		n, err := client.XLen(t.Context(), bus.deadLetter).Result()
+		return err == nil && n == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(3), calls.Load())
+
+	entries, err := client.XRange(t.Context(), bus.deadLetter, "-", "+").Result()
+	require.NoError(t, err)
+	assert.Equal(t, "events.risk", entries[0].Values["subject"])
+	assert.Contains(t, entries[0].Values["error"], "panicked")
+}
+
+func TestStreamBus_GroupsShareWorkAndSurviveRestarts(t *testing.T) {
+	_, client := newTestClient(t)
+	cfg := testConfig(DriverStreams)
+
+	first := NewStreamBus(client, cfg)
+	received := &collector{}
+	sub, err := first.Subscribe(t.Context(), SubjectOpportunity, "executor", received.handle)
+	require.NoError(t, err)
+	require.NoError(t, sub.Unsubscribe())
+
+	// Published while no consumer in the group is running.
+	publisher := NewStreamBus(client, cfg)
+	require.NoError(t, publisher.Publish(t.Context(), SubjectOpportunity, "opp-1"))
+
+	second := NewStreamBus(client, cfg)
+	defer func() { _ = second.Close() }()
+	_, err = second.Subscribe(t.Context(), SubjectOpportunity, "executor", received.handle)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return len(received.subjects()) == 1 }, 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, second.Close())
+	assert.ErrorIs(t, second.Publish(t.Context(), SubjectOpportunity, "opp-2"), ErrClosed)
+}
@@ -0,0 +1,294 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// natsSetupTimeout bounds connecting and creating the streams.
+const natsSetupTimeout = 10 * time.Second
+
+// natsNameReplacer maps subjects and groups to valid stream and consumer
+// names, which may not contain dots, wildcards or path separators.
+var natsNameReplacer = strings.NewReplacer(".", "_", "*", "star", ">", "gt", " ", "_", "/", "_", "\\", "_")
+
+// NATSBus delivers events at least once using a NATS JetStream stream. Each
+// subscriber group is a durable consumer on the stream, so events published
+// while a group is offline are delivered once it is back, and an
+// unacknowledged message is redelivered after RedeliverAfter. Messages that
+// fail MaxDeliveries times are moved to a dead-letter stream.
+type NATSBus struct {
+	conn       *nats.Conn
+	owned      bool
+	js         jetstream.JetStream
+	cfg        Config
+	stream     string
+	subject    string
+	deadLetter string
+
+	mu     sync.Mutex
+	closed bool
+	subs   map[*natsSubscription]struct{}
+}
+
+type natsSubscription struct {
+	bus     *NATSBus
+	group   string
+	handler Handler
+	consume jetstream.ConsumeContext
+	ctx     context.Context
+	cancel  context.CancelFunc
+	once    sync.Once
+}
+
+// natsDeadLetter is a message that failed every delivery.
+type natsDeadLetter struct {
+	Message
+	Group string `json:"group"`
+	Error string `json:"error"`
+}
+
+// ConnectNATS connects to cfg.URL and creates a NATS event bus that closes
+// the connection when it is closed.
+//
+// Parameters:
+//   - cfg: Bus configuration.
+//
+// Returns:
+//   - *NATSBus: The event bus.
+//   - error: Error if the server is unreachable or JetStream is disabled.
+func ConnectNATS(cfg Config) (*NATSBus, error) {
+	cfg = cfg.withDefaults()
+	conn, err := nats.Connect(cfg.URL, nats.Name("neuratrade-eventbus"), nats.Timeout(natsSetupTimeout))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	bus, err := NewNATSBus(conn, cfg)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	bus.owned = true
+	return bus, nil
+}
+
+// NewNATSBus creates a NATS JetStream event bus on an existing connection,
+// creating the event and dead-letter streams when they do not exist.
+//
+// Parameters:
+//   - conn: NATS connection; the caller keeps ownership.
+//   - cfg: Bus configuration.
+//
+// Returns:
+//   - *NATSBus: The event bus.
+//   - error: Error if JetStream is unavailable or the streams cannot be created.
+func NewNATSBus(conn *nats.Conn, cfg Config) (*NATSBus, error) {
+	if conn == nil {
+		return nil, errors.New("event bus requires a NATS connection")
+	}
+	cfg = cfg.withDefaults()
+	js, err := jetstream.New(conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open JetStream: %w", err)
+	}
+
+	name := natsNameReplacer.Replace(cfg.Prefix)
+	b := &NATSBus{
+		conn:       conn,
+		js:         js,
+		cfg:        cfg,
+		stream:     name,
+		subject:    name,
+		deadLetter: name + "-deadletter",
+		subs:       make(map[*natsSubscription]struct{}),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), natsSetupTimeout)
+	defer cancel()
+	for _, stream := range []string{b.stream, b.deadLetter} {
+		if _, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+			Name:     stream,
+			Subjects: []string{stream + ".>"},
+			MaxMsgs:  cfg.MaxLen,
+			Discard:  jetstream.DiscardOld,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to create stream %s: %w", stream, err)
+		}
+	}
+	return b, nil
+}
+
+// Publish stores an event in the stream. The event ID doubles as the
+// JetStream message ID, so a retried publish is not stored twice.
+func (b *NATSBus) Publish(ctx context.Context, subject string, data interface{}) error {
+	if err := ValidateSubject(subject); err != nil {
+		return err
+	}
+	if b.isClosed() {
+		return ErrClosed
+	}
+	payload, err := encodeData(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	msg := Message{
+		ID:        uuid.NewString(),
+		Subject:   subject,
+		Data:      payload,
+		Timestamp: time.Now().UTC(),
+	}
+	envelope, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if _, err := b.js.Publish(ctx, b.subject+"."+subject, envelope, jetstream.WithMsgID(msg.ID)); err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+	return nil
+}
+
+// Subscribe starts consuming matching events as part of group. The durable
+// consumer is keyed by group and pattern and starts at new messages the
+// first time it is created. NATS matches the pattern itself.
+func (b *NATSBus) Subscribe(ctx context.Context, pattern, group string, handler Handler) (Subscription, error) {
+	if pattern == "" || group == "" || handler == nil {
+		return nil, errors.New("pattern, group and handler are required")
+	}
+	if b.isClosed() {
+		return nil, ErrClosed
+	}
+
+	groupName := natsNameReplacer.Replace(group + ":" + pattern)
+	consumer, err := b.js.CreateOrUpdateConsumer(ctx, b.stream, jetstream.ConsumerConfig{
+		Durable:       groupName,
+		FilterSubject: b.subject + "." + pattern,
+		DeliverPolicy: jetstream.DeliverNewPolicy,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       b.cfg.RedeliverAfter,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consumer: %w", err)
+	}
+
+	subCtx, cancel := context.WithCancel(context.Background())
+	sub := &natsSubscription{bus: b, group: groupName, handler: handler, ctx: subCtx, cancel: cancel}
+	sub.consume, err = consumer.Consume(sub.handle)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to consume: %w", err)
+	}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+	return sub, nil
+}
+
+// Close stops all subscriptions and closes the connection when the bus
+// opened it.
+func (b *NATSBus) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	subs := make([]*natsSubscription, 0, len(b.subs))
+	for sub := range b.subs {
+		subs = append(subs, sub)
+	}
+	b.mu.Unlock()
+
+	for _, sub := range subs {
+		_ = sub.Unsubscribe()
+	}
+	if b.owned {
+		b.conn.Close()
+	}
+	return nil
+}
+
+func (b *NATSBus) isClosed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closed
+}
+
+// Unsubscribe stops consuming. Unacknowledged messages stay with the durable
+// consumer and are redelivered to the next subscriber in the group.
+func (s *natsSubscription) Unsubscribe() error {
+	s.once.Do(func() {
+		s.cancel()
+		s.consume.Stop()
+		<-s.consume.Closed()
+
+		s.bus.mu.Lock()
+		delete(s.bus.subs, s)
+		s.bus.mu.Unlock()
+	})
+	return nil
+}
+
+func (s *natsSubscription) handle(raw jetstream.Msg) {
+	var msg Message
+	if err := json.Unmarshal(raw.Data(), &msg); err != nil {
+		s.deadLetter(raw, msg, fmt.Sprintf("undecodable event: %v", err))
+		return
+	}
+	msg.Attempt = 1
+	if meta, err := raw.Metadata(); err == nil {
+		msg.Attempt = int(meta.NumDelivered)
+	}
+	if msg.Attempt > s.bus.cfg.MaxDeliveries {
+		s.deadLetter(raw, msg, "max deliveries exceeded")
+		return
+	}
+
+	if err := s.invoke(msg); err != nil {
+		if msg.Attempt >= s.bus.cfg.MaxDeliveries {
+			s.deadLetter(raw, msg, err.Error())
+			return
+		}
+		_ = raw.NakWithDelay(s.bus.cfg.RedeliverAfter)
+		return
+	}
+	_ = raw.Ack()
+}
+
+func (s *natsSubscription) invoke(msg Message) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+	return s.handler(s.ctx, msg)
+}
+
+// deadLetter stores the message in the dead-letter stream and acknowledges
+// it. It stays with the consumer when the dead-letter write fails.
+func (s *natsSubscription) deadLetter(raw jetstream.Msg, msg Message, reason string) {
+	if msg.Subject == "" {
+		msg.Subject = strings.TrimPrefix(raw.Subject(), s.bus.subject+".")
+	}
+	if msg.Data == nil && json.Valid(raw.Data()) {
+		msg.Data = raw.Data()
+	}
+	entry, err := json.Marshal(natsDeadLetter{Message: msg, Group: s.group, Error: reason})
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), natsSetupTimeout)
+	defer cancel()
+	if _, err := s.bus.js.Publish(ctx, s.bus.deadLetter+"."+msg.Subject, entry); err == nil {
+		_ = raw.Ack()
+	}
+}
@@ -0,0 +1,126 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestNATSServer(t *testing.T) *server.Server {
+	s, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, JetStream: true, StoreDir: t.TempDir(), NoLog: true, NoSigs: true})
+	require.NoError(t, err)
+	go s.Start()
+	require.True(t, s.ReadyForConnections(5*time.Second), "nats server did not start")
+	t.Cleanup(s.Shutdown)
+	return s
+}
+
+func newTestNATS(t *testing.T) *nats.Conn {
+	conn, err := nats.Connect(newTestNATSServer(t).ClientURL())
+	require.NoError(t, err)
+	t.Cleanup(conn.Close)
+	return conn
+}
+
+func TestNATSBus_RedeliversFailedMessages(t *testing.T) {
+	bus, err := NewNATSBus(newTestNATS(t), testConfig(DriverNATS))
+	require.NoError(t, err)
+	defer func() { _ = bus.Close() }()
+
+	var attempts []int
+	var mu sync.Mutex
+	_, err = bus.Subscribe(t.Context(), SubjectFill, "executor", func(_ context.Context, msg Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts = append(attempts, msg.Attempt)
+		if len(attempts) < 2 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, bus.Publish(t.Context(), SubjectFill, "fill"))
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(attempts) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	assert.Equal(t, []int{1, 2}, attempts)
+	mu.Unlock()
+
+	consumer, err := bus.js.Consumer(t.Context(), bus.stream, natsNameReplacer.Replace("executor:"+SubjectFill))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		info, err := consumer.Info(t.Context())
+		return err == nil && info.NumAckPending == 0 && info.NumPending == 0
+	}, time.Second, 10*time.Millisecond, "the retried message is acknowledged")
+}
+
+func TestNATSBus_DeadLettersAfterMaxDeliveries(t *testing.T) {
+	bus, err := NewNATSBus(newTestNATS(t), testConfig(DriverNATS))
+	require.NoError(t, err)
+	defer func() { _ = bus.Close() }()
+
+	var calls atomic.Int32
+	_, err = bus.Subscribe(t.Context(), SubjectRisk, "risk", func(context.Context, Message) error {
+		calls.Add(1)
+		panic("boom")
+	})
+	require.NoError(t, err)
+	require.NoError(t, bus.Publish(t.Context(), SubjectRisk, "risk"))
+
+	deadLetters, err := bus.js.Stream(t.Context(), bus.deadLetter)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		info, err := deadLetters.Info(t.Context())
+		return err == nil && info.State.Msgs == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(3), calls.Load())
+
+	raw, err := deadLetters.GetLastMsgForSubject(t.Context(), bus.deadLetter+".>")
+	require.NoError(t, err)
+	var entry natsDeadLetter
+	require.NoError(t, json.Unmarshal(raw.Data, &entry))
+	assert.Equal(t, "events.risk", entry.Subject)
+	assert.Contains(t, entry.Error, "panicked")
+}
+
+func TestNATSBus_GroupsShareWorkAndSurviveRestarts(t *testing.T) {
+	conn := newTestNATS(t)
+	cfg := testConfig(DriverNATS)
+
+	first, err := NewNATSBus(conn, cfg)
+	require.NoError(t, err)
+	received := &collector{}
+	sub, err := first.Subscribe(t.Context(), SubjectOpportunity, "executor", received.handle)
+	require.NoError(t, err)
+	require.NoError(t, sub.Unsubscribe())
+
+	// Published while no consumer in the group is running.
+	publisher, err := NewNATSBus(conn, cfg)
+	require.NoError(t, err)
+	require.NoError(t, publisher.Publish(t.Context(), SubjectOpportunity, "opp-1"))
+
+	second, err := NewNATSBus(conn, cfg)
+	require.NoError(t, err)
+	defer func() { _ = second.Close() }()
+	_, err = second.Subscribe(t.Context(), SubjectOpportunity, "executor", received.handle)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return len(received.subjects()) == 1 }, 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, second.Close())
+	assert.ErrorIs(t, second.Publish(t.Context(), SubjectOpportunity, "opp-2"), ErrClosed)
+	assert.False(t, conn.IsClosed(), "a bus on a shared connection leaves it open")
+}
@@ -0,0 +1,155 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// PubSubBus is the Redis pub/sub fallback. Delivery is at most once: events
+// published while no subscriber is connected are lost, handler errors are not
+// retried and groups are not load balanced.
+type PubSubBus struct {
+	client *redis.Client
+	prefix string
+
+	mu     sync.Mutex
+	closed bool
+	subs   map[*pubSubSubscription]struct{}
+}
+
+type pubSubSubscription struct {
+	bus    *PubSubBus
+	pubsub *redis.PubSub
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewPubSubBus creates a Redis pub/sub event bus.
+//
+// Parameters:
+//   - client: Redis client.
+//   - cfg: Bus configuration; only Prefix is used.
+//
+// Returns:
+//   - *PubSubBus: The event bus.
+func NewPubSubBus(client *redis.Client, cfg Config) *PubSubBus {
+	cfg = cfg.withDefaults()
+	return &PubSubBus{
+		client: client,
+		prefix: cfg.Prefix + ":",
+		subs:   make(map[*pubSubSubscription]struct{}),
+	}
+}
+
+// Publish sends an event to current subscribers.
+func (b *PubSubBus) Publish(ctx context.Context, subject string, data interface{}) error {
+	if err := ValidateSubject(subject); err != nil {
+		return err
+	}
+	if b.isClosed() {
+		return ErrClosed
+	}
+	payload, err := encodeData(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	envelope, err := json.Marshal(Message{
+		ID:        uuid.NewString(),
+		Subject:   subject,
+		Data:      payload,
+		Timestamp: time.Now().UTC(),
+		Attempt:   1,
+	})
+	if err != nil {
+		return err
+	}
+	return b.client.Publish(ctx, b.prefix+subject, envelope).Err()
+}
+
+// Subscribe delivers matching events to handler. The group is ignored.
+func (b *PubSubBus) Subscribe(ctx context.Context, pattern, group string, handler Handler) (Subscription, error) {
+	if pattern == "" || handler == nil {
+		return nil, errors.New("pattern and handler are required")
+	}
+	if b.isClosed() {
+		return nil, ErrClosed
+	}
+
+	// Redis globs match across dots, so the pattern is re-checked per message.
+	glob := b.prefix + strings.ReplaceAll(pattern, ">", "*")
+	pubsub := b.client.PSubscribe(ctx, glob)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	sub := &pubSubSubscription{bus: b, pubsub: pubsub, done: make(chan struct{})}
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		defer close(sub.done)
+		for raw := range pubsub.Channel() {
+			var msg Message
+			if err := json.Unmarshal([]byte(raw.Payload), &msg); err != nil {
+				continue
+			}
+			if !MatchSubject(pattern, msg.Subject) {
+				continue
+			}
+			func() {
+				defer func() { _ = recover() }()
+				_ = handler(context.Background(), msg)
+			}()
+		}
+	}()
+	return sub, nil
+}
+
+// Close stops all subscriptions.
+func (b *PubSubBus) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	subs := make([]*pubSubSubscription, 0, len(b.subs))
+	for sub := range b.subs {
+		subs = append(subs, sub)
+	}
+	b.mu.Unlock()
+
+	for _, sub := range subs {
+		_ = sub.Unsubscribe()
+	}
+	return nil
+}
+
+func (b *PubSubBus) isClosed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closed
+}
+
+// Unsubscribe stops delivery.
+func (s *pubSubSubscription) Unsubscribe() error {
+	var err error
+	s.once.Do(func() {
+		err = s.pubsub.Close()
+		<-s.done
+		s.bus.mu.Lock()
+		delete(s.bus.subs, s)
+		s.bus.mu.Unlock()
+	})
+	return err
+}
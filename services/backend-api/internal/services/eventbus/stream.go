@@ -0,0 +1,290 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// StreamBus delivers events at least once using a Redis stream. Each
+// subscriber group is a consumer group on the stream, so a crashed consumer's
+// unacknowledged messages are claimed and redelivered after RedeliverAfter.
+// Messages that fail MaxDeliveries times are moved to a dead-letter stream.
+type StreamBus struct {
+	client     *redis.Client
+	cfg        Config
+	stream     string
+	deadLetter string
+	consumer   string
+
+	mu     sync.Mutex
+	closed bool
+	subs   map[*streamSubscription]struct{}
+}
+
+type streamSubscription struct {
+	bus     *StreamBus
+	pattern string
+	group   string
+	handler Handler
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// NewStreamBus creates a Redis Streams event bus.
+//
+// Parameters:
+//   - client: Redis client.
+//   - cfg: Bus configuration.
+//
+// Returns:
+//   - *StreamBus: The event bus.
+func NewStreamBus(client *redis.Client, cfg Config) *StreamBus {
+	cfg = cfg.withDefaults()
+	return &StreamBus{
+		client:     client,
+		cfg:        cfg,
+		stream:     cfg.Prefix + ":stream",
+		deadLetter: cfg.Prefix + ":deadletter",
+		consumer:   uuid.NewString(),
+		subs:       make(map[*streamSubscription]struct{}),
+	}
+}
+
+// Publish appends an event to the stream.
+func (b *StreamBus) Publish(ctx context.Context, subject string, data interface{}) error {
+	if err := ValidateSubject(subject); err != nil {
+		return err
+	}
+	if b.isClosed() {
+		return ErrClosed
+	}
+	payload, err := encodeData(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	return b.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: b.stream,
+		MaxLen: b.cfg.MaxLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"id":      uuid.NewString(),
+			"subject": subject,
+			"data":    string(payload),
+			"ts":      time.Now().UTC().UnixMilli(),
+		},
+	}).Err()
+}
+
+// Subscribe starts consuming matching events as part of group. The consumer
+// group is keyed by group and pattern and starts at new messages the first
+// time it is created.
+func (b *StreamBus) Subscribe(ctx context.Context, pattern, group string, handler Handler) (Subscription, error) {
+	if pattern == "" || group == "" || handler == nil {
+		return nil, errors.New("pattern, group and handler are required")
+	}
+	if b.isClosed() {
+		return nil, ErrClosed
+	}
+
+	groupName := group + ":" + pattern
+	err := b.client.XGroupCreateMkStream(ctx, b.stream, groupName, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil, fmt.Errorf("failed to create consumer group: %w", err)
+	}
+
+	subCtx, cancel := context.WithCancel(context.Background())
+	sub := &streamSubscription{
+		bus:     b,
+		pattern: pattern,
+		group:   groupName,
+		handler: handler,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	go sub.run(subCtx)
+	return sub, nil
+}
+
+// Close stops all subscriptions.
+func (b *StreamBus) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	subs := make([]*streamSubscription, 0, len(b.subs))
+	for sub := range b.subs {
+		subs = append(subs, sub)
+	}
+	b.mu.Unlock()
+
+	for _, sub := range subs {
+		_ = sub.Unsubscribe()
+	}
+	return nil
+}
+
+func (b *StreamBus) isClosed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closed
+}
+
+// Unsubscribe stops consuming. Unacknowledged messages stay pending in the
+// group and are redelivered to the next consumer.
+func (s *streamSubscription) Unsubscribe() error {
+	s.cancel()
+	<-s.done
+
+	s.bus.mu.Lock()
+	delete(s.bus.subs, s)
+	s.bus.mu.Unlock()
+	return nil
+}
+
+func (s *streamSubscription) run(ctx context.Context) {
+	defer close(s.done)
+	for ctx.Err() == nil {
+		s.claimStale(ctx)
+
+		streams, err := s.bus.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    s.group,
+			Consumer: s.bus.consumer,
+			Streams:  []string{s.bus.stream, ">"},
+			Count:    10,
+			Block:    s.bus.cfg.Block,
+		}).Result()
+		if err != nil {
+			if !errors.Is(err, redis.Nil) && ctx.Err() == nil {
+				s.sleep(ctx, s.bus.cfg.Block)
+			}
+			continue
+		}
+		for _, stream := range streams {
+			for _, message := range stream.Messages {
+				s.handle(ctx, message, 1)
+			}
+		}
+	}
+}
+
+// claimStale takes over messages that failed or whose consumer stopped
+// without acknowledging them.
+func (s *streamSubscription) claimStale(ctx context.Context) {
+	messages, _, err := s.bus.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   s.bus.stream,
+		Group:    s.group,
+		Consumer: s.bus.consumer,
+		MinIdle:  s.bus.cfg.RedeliverAfter,
+		Start:    "0-0",
+		Count:    10,
+	}).Result()
+	if err != nil {
+		return
+	}
+	for _, message := range messages {
+		attempt := 2
+		pending, err := s.bus.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+			Stream: s.bus.stream,
+			Group:  s.group,
+			Start:  message.ID,
+			End:    message.ID,
+			Count:  1,
+		}).Result()
+		if err == nil && len(pending) == 1 {
+			attempt = int(pending[0].RetryCount)
+		}
+		s.handle(ctx, message, attempt)
+	}
+}
+
+func (s *streamSubscription) handle(ctx context.Context, raw redis.XMessage, attempt int) {
+	msg := decodeStreamMessage(raw, attempt)
+	if !MatchSubject(s.pattern, msg.Subject) {
+		s.ack(ctx, raw.ID)
+		return
+	}
+	if attempt > s.bus.cfg.MaxDeliveries {
+		s.deadLetter(ctx, raw, "max deliveries exceeded")
+		return
+	}
+
+	if err := s.invoke(ctx, msg); err != nil {
+		if attempt >= s.bus.cfg.MaxDeliveries {
+			s.deadLetter(ctx, raw, err.Error())
+		}
+		// Left pending; claimStale redelivers it after RedeliverAfter.
+		return
+	}
+	s.ack(ctx, raw.ID)
+}
+
+func (s *streamSubscription) invoke(ctx context.Context, msg Message) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+	return s.handler(ctx, msg)
+}
+
+func (s *streamSubscription) ack(ctx context.Context, id string) {
+	_ = s.bus.client.XAck(context.WithoutCancel(ctx), s.bus.stream, s.group, id).Err()
+}
+
+func (s *streamSubscription) deadLetter(ctx context.Context, raw redis.XMessage, reason string) {
+	values := make(map[string]interface{}, len(raw.Values)+3)
+	for k, v := range raw.Values {
+		values[k] = v
+	}
+	values["group"] = s.group
+	values["stream_id"] = raw.ID
+	values["error"] = reason
+
+	ctx = context.WithoutCancel(ctx)
+	if err := s.bus.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: s.bus.deadLetter,
+		MaxLen: s.bus.cfg.MaxLen,
+		Approx: true,
+		Values: values,
+	}).Err(); err == nil {
+		s.ack(ctx, raw.ID)
+	}
+}
+
+func (s *streamSubscription) sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
+
+func decodeStreamMessage(raw redis.XMessage, attempt int) Message {
+	msg := Message{Attempt: attempt}
+	msg.ID, _ = raw.Values["id"].(string)
+	msg.Subject, _ = raw.Values["subject"].(string)
+	if data, ok := raw.Values["data"].(string); ok {
+		msg.Data = []byte(data)
+	}
+	if ts, ok := raw.Values["ts"].(string); ok {
+		if ms, err := strconv.ParseInt(ts, 10, 64); err == nil {
+			msg.Timestamp = time.UnixMilli(ms).UTC()
+		}
+	}
+	return msg
+}
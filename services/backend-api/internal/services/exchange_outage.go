@@ -0,0 +1,419 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	exchangeHealthKey = "exchange:health"
+
+	defaultOutageCheckInterval = 30 * time.Second
+	defaultOutageMaxErrorRate  = 0.5
+	defaultOutageMinRequests   = 5
+	defaultOutageStaleAfter    = 5 * time.Minute
+	defaultOutageMaxSpreadPct  = 1.0
+	defaultOutageSpreadWindow  = 5 * time.Minute
+	defaultOutageStablePeriod  = 10 * time.Minute
+)
+
+// ExchangeActivitySource reports collector activity per exchange. It is
+// implemented by CollectorService.
+type ExchangeActivitySource interface {
+	GetCircuitBreakerStats() map[string]CircuitBreakerStats
+	GetWorkerStatus() map[string]*Worker
+}
+
+// ExchangeGuard reports whether strategies targeting an exchange are paused.
+type ExchangeGuard interface {
+	ExchangePaused(ctx context.Context, exchange string) bool
+}
+
+// ExchangeHealth is the latest health assessment of one exchange.
+type ExchangeHealth struct {
+	Exchange string `json:"exchange"`
+	// Degraded is true when the last check found at least one problem.
+	Degraded bool     `json:"degraded"`
+	Reasons  []string `json:"reasons,omitempty"`
+	// Paused is true while strategies targeting the exchange are paused.
+	Paused    bool       `json:"paused"`
+	PausedAt  *time.Time `json:"paused_at,omitempty"`
+	ErrorRate float64    `json:"error_rate"`
+	SpreadPct float64    `json:"spread_pct"`
+	// LastTickerAt is the time of the collector's last successful update.
+	LastTickerAt   time.Time  `json:"last_ticker_at"`
+	LastDegradedAt *time.Time `json:"last_degraded_at,omitempty"`
+	CheckedAt      time.Time  `json:"checked_at"`
+}
+
+// ExchangeOutageConfig configures degradation detection and resumption.
+type ExchangeOutageConfig struct {
+	// Interval is how often exchange health is checked.
+	Interval time.Duration
+	// MaxErrorRate is the share of failed CCXT calls since the previous check
+	// above which an exchange is degraded.
+	MaxErrorRate float64
+	// MinRequests is the number of calls needed before the error rate counts.
+	MinRequests int64
+	// StaleAfter is how old the last ticker update may be.
+	StaleAfter time.Duration
+	// MaxSpreadPct is the average bid/ask spread, in percent of the mid
+	// price, above which an exchange is degraded.
+	MaxSpreadPct float64
+	// SpreadWindow is how much recent market data the spread is averaged over.
+	SpreadWindow time.Duration
+	// StablePeriod is how long an exchange must stay healthy before its
+	// strategies resume.
+	StablePeriod time.Duration
+	// NotifyChatIDs are the Telegram chats told about pauses and resumptions.
+	NotifyChatIDs []int64
+}
+
+// ExchangeOutageDetector watches error rates, spreads and ticker freshness of
+// every collected exchange. A degraded exchange is paused until it has been
+// healthy for the stable period.
+type ExchangeOutageDetector struct {
+	activity  ExchangeActivitySource
+	db        DBPool
+	redis     *redis.Client
+	config    ExchangeOutageConfig
+	events    EventEmitter
+	messenger DirectMessenger
+	logger    *slog.Logger
+	now       func() time.Time
+	// mu guards lastStats, the circuit breaker counters at the previous check.
+	mu        sync.Mutex
+	lastStats map[string]CircuitBreakerStats
+	runMu     sync.Mutex
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// Ensure ExchangeOutageDetector implements ExchangeGuard.
+var _ ExchangeGuard = (*ExchangeOutageDetector)(nil)
+
+// NewExchangeOutageDetector creates an exchange outage detector.
+//
+// Parameters:
+//
+//	activity: Collector activity, such as the CollectorService.
+//	db: Database used to measure recent spreads (may be nil to skip spreads).
+//	client: Redis client used to persist exchange health.
+//	config: Detection configuration; zero values use defaults.
+//
+// Returns:
+//
+//	*ExchangeOutageDetector: Initialized detector (checks not started).
+func NewExchangeOutageDetector(activity ExchangeActivitySource, db DBPool, client *redis.Client, config ExchangeOutageConfig) *ExchangeOutageDetector {
+	if config.Interval <= 0 {
+		config.Interval = defaultOutageCheckInterval
+	}
+	if config.MaxErrorRate <= 0 {
+		config.MaxErrorRate = defaultOutageMaxErrorRate
+	}
+	if config.MinRequests <= 0 {
+		config.MinRequests = defaultOutageMinRequests
+	}
+	if config.StaleAfter <= 0 {
+		config.StaleAfter = defaultOutageStaleAfter
+	}
+	if config.MaxSpreadPct <= 0 {
+		config.MaxSpreadPct = defaultOutageMaxSpreadPct
+	}
+	if config.SpreadWindow <= 0 {
+		config.SpreadWindow = defaultOutageSpreadWindow
+	}
+	if config.StablePeriod <= 0 {
+		config.StablePeriod = defaultOutageStablePeriod
+	}
+	return &ExchangeOutageDetector{
+		activity:  activity,
+		db:        db,
+		redis:     client,
+		config:    config,
+		logger:    telemetry.Logger(),
+		now:       time.Now,
+		lastStats: make(map[string]CircuitBreakerStats),
+	}
+}
+
+// SetEventEmitter publishes pause and resume risk events.
+func (d *ExchangeOutageDetector) SetEventEmitter(events EventEmitter) {
+	d.events = events
+}
+
+// SetMessenger sets the Telegram sender used for pause and resume notifications.
+func (d *ExchangeOutageDetector) SetMessenger(messenger DirectMessenger) {
+	d.messenger = messenger
+}
+
+// Start checks exchange health once per interval until Stop is called.
+func (d *ExchangeOutageDetector) Start(ctx context.Context) error {
+	d.runMu.Lock()
+	defer d.runMu.Unlock()
+	if d.cancel != nil {
+		return fmt.Errorf("exchange outage detector already running")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	d.cancel = cancel
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		ticker := time.NewTicker(d.config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := d.Check(ctx); err != nil {
+					d.logger.Warn("Exchange health check failed", "error", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the checks and waits for the running check to finish.
+func (d *ExchangeOutageDetector) Stop() {
+	d.runMu.Lock()
+	cancel := d.cancel
+	d.cancel = nil
+	d.runMu.Unlock()
+	if cancel != nil {
+		cancel()
+		d.wg.Wait()
+	}
+}
+
+// Check assesses every collected exchange, pausing degraded ones and
+// resuming those that have been healthy for the stable period.
+//
+// Parameters:
+//
+//	ctx: Context for the check.
+//
+// Returns:
+//
+//	[]ExchangeHealth: The health of every exchange, sorted by name.
+//	error: Error if the health could not be read or saved.
+func (d *ExchangeOutageDetector) Check(ctx context.Context) ([]ExchangeHealth, error) {
+	previous, err := d.loadHealth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workers := d.activity.GetWorkerStatus()
+	errorRates, requests := d.errorRates()
+	spreads := d.spreads(ctx)
+
+	exchanges := make(map[string]struct{}, len(workers))
+	for exchange := range workers {
+		exchanges[exchange] = struct{}{}
+	}
+	for exchange := range errorRates {
+		exchanges[exchange] = struct{}{}
+	}
+
+	now := d.now().UTC()
+	for exchange := range exchanges {
+		health := previous[exchange]
+		health.Exchange = exchange
+		health.CheckedAt = now
+		health.ErrorRate = errorRates[exchange]
+		health.SpreadPct = spreads[exchange]
+		health.Reasons = nil
+
+		if requests[exchange] >= d.config.MinRequests && health.ErrorRate > d.config.MaxErrorRate {
+			health.Reasons = append(health.Reasons, fmt.Sprintf("error rate %.0f%%", health.ErrorRate*100))
+		}
+		if health.SpreadPct > d.config.MaxSpreadPct {
+			health.Reasons = append(health.Reasons, fmt.Sprintf("average spread %.2f%%", health.SpreadPct))
+		}
+		if worker, ok := workers[exchange]; ok {
+			health.LastTickerAt = worker.LastUpdate
+			switch {
+			case !worker.IsRunning:
+				health.Reasons = append(health.Reasons, "collector worker stopped")
+			case !worker.LastUpdate.IsZero() && now.Sub(worker.LastUpdate) > d.config.StaleAfter:
+				health.Reasons = append(health.Reasons, fmt.Sprintf("tickers stale for %s", now.Sub(worker.LastUpdate).Round(time.Second)))
+			}
+		}
+		health.Degraded = len(health.Reasons) > 0
+
+		switch {
+		case health.Degraded:
+			health.LastDegradedAt = &now
+			if !health.Paused {
+				health.Paused = true
+				health.PausedAt = &now
+				d.notify(ctx, health, true)
+			}
+		case health.Paused && (health.LastDegradedAt == nil || now.Sub(*health.LastDegradedAt) >= d.config.StablePeriod):
+			health.Paused = false
+			health.PausedAt = nil
+			d.notify(ctx, health, false)
+		}
+
+		raw, err := json.Marshal(health)
+		if err != nil {
+			return nil, err
+		}
+		if err := d.redis.HSet(ctx, exchangeHealthKey, exchange, raw).Err(); err != nil {
+			return nil, fmt.Errorf("failed to save exchange health: %w", err)
+		}
+		previous[exchange] = health
+	}
+	return sortedExchangeHealth(previous), nil
+}
+
+// errorRates returns each exchange's CCXT failure share and call count since
+// the previous check.
+func (d *ExchangeOutageDetector) errorRates() (map[string]float64, map[string]int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	rates := make(map[string]float64)
+	requests := make(map[string]int64)
+	for name, stats := range d.activity.GetCircuitBreakerStats() {
+		exchange, ok := strings.CutPrefix(name, "ccxt:")
+		if !ok {
+			continue
+		}
+		last, seen := d.lastStats[name]
+		d.lastStats[name] = stats
+		total := stats.TotalRequests - last.TotalRequests
+		failed := stats.FailedRequests - last.FailedRequests
+		if !seen || total <= 0 || failed < 0 {
+			rates[exchange] = 0
+			continue
+		}
+		rates[exchange] = float64(failed) / float64(total)
+		requests[exchange] = total
+	}
+	return rates, requests
+}
+
+// spreads returns each exchange's average bid/ask spread over the spread
+// window, in percent of the mid price.
+func (d *ExchangeOutageDetector) spreads(ctx context.Context) map[string]float64 {
+	spreads := make(map[string]float64)
+	if d.db == nil {
+		return spreads
+	}
+	rows, err := d.db.Query(ctx, `
+		SELECT e.ccxt_id, AVG((md.ask - md.bid) / ((md.ask + md.bid) / 2)) * 100
+		FROM market_data md
+		JOIN exchanges e ON e.id = md.exchange_id
+		WHERE md.timestamp >= $1 AND md.bid > 0 AND md.ask > 0
+		GROUP BY e.ccxt_id`, d.now().Add(-d.config.SpreadWindow))
+	if err != nil {
+		d.logger.Warn("Failed to measure exchange spreads", "error", err)
+		return spreads
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var exchange string
+		var spread float64
+		if err := rows.Scan(&exchange, &spread); err != nil {
+			d.logger.Warn("Failed to read exchange spread", "error", err)
+			return spreads
+		}
+		spreads[exchange] = spread
+	}
+	return spreads
+}
+
+func (d *ExchangeOutageDetector) notify(ctx context.Context, health ExchangeHealth, paused bool) {
+	data := map[string]interface{}{
+		"exchange":   health.Exchange,
+		"error_rate": health.ErrorRate,
+		"spread_pct": health.SpreadPct,
+	}
+	var text string
+	if paused {
+		d.logger.Warn("Exchange degraded, pausing strategies", "exchange", health.Exchange, "reasons", health.Reasons)
+		data["type"] = "exchange_paused"
+		data["reasons"] = health.Reasons
+		text = fmt.Sprintf("⏸ Strategies on %s paused: %s.\nThey resume after %s without problems.",
+			health.Exchange, strings.Join(health.Reasons, ", "), d.config.StablePeriod)
+	} else {
+		d.logger.Info("Exchange stable again, resuming strategies", "exchange", health.Exchange)
+		data["type"] = "exchange_resumed"
+		text = fmt.Sprintf("▶️ Strategies on %s resumed after %s without problems.", health.Exchange, d.config.StablePeriod)
+	}
+
+	if d.events != nil {
+		d.events.Emit(ctx, WebhookEventRisk, data)
+	}
+	if d.messenger == nil {
+		return
+	}
+	for _, chatID := range d.config.NotifyChatIDs {
+		if err := d.messenger.SendDirectMessage(ctx, chatID, text); err != nil {
+			d.logger.Warn("Failed to send exchange pause notification", "chat_id", chatID, "error", err)
+		}
+	}
+}
+
+// Health returns the latest health of every exchange, sorted by name.
+func (d *ExchangeOutageDetector) Health(ctx context.Context) ([]ExchangeHealth, error) {
+	health, err := d.loadHealth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sortedExchangeHealth(health), nil
+}
+
+// ExchangePaused reports whether strategies targeting the exchange are paused.
+func (d *ExchangeOutageDetector) ExchangePaused(ctx context.Context, exchange string) bool {
+	raw, err := d.redis.HGet(ctx, exchangeHealthKey, strings.ToLower(exchange)).Result()
+	if err != nil {
+		if err != redis.Nil {
+			d.logger.Warn("Failed to load exchange health", "exchange", exchange, "error", err)
+		}
+		return false
+	}
+	var health ExchangeHealth
+	if err := json.Unmarshal([]byte(raw), &health); err != nil {
+		return false
+	}
+	return health.Paused
+}
+
+func (d *ExchangeOutageDetector) loadHealth(ctx context.Context) (map[string]ExchangeHealth, error) {
+	raw, err := d.redis.HGetAll(ctx, exchangeHealthKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load exchange health: %w", err)
+	}
+	health := make(map[string]ExchangeHealth, len(raw))
+	for exchange, value := range raw {
+		var h ExchangeHealth
+		if err := json.Unmarshal([]byte(value), &h); err != nil {
+			d.logger.Warn("Skipping corrupt exchange health", "exchange", exchange, "error", err)
+			continue
+		}
+		health[exchange] = h
+	}
+	return health, nil
+}
+
+func sortedExchangeHealth(health map[string]ExchangeHealth) []ExchangeHealth {
+	result := make([]ExchangeHealth, 0, len(health))
+	for _, h := range health {
+		result = append(result, h)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Exchange < result[j].Exchange })
+	return result
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeExchangeActivity struct {
+	mu      sync.Mutex
+	stats   map[string]CircuitBreakerStats
+	workers map[string]*Worker
+}
+
+func (f *fakeExchangeActivity) GetCircuitBreakerStats() map[string]CircuitBreakerStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stats := make(map[string]CircuitBreakerStats, len(f.stats))
+	for name, s := range f.stats {
+		stats[name] = s
+	}
+	return stats
+}
+
+func (f *fakeExchangeActivity) GetWorkerStatus() map[string]*Worker {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.workers
+}
+
+func (f *fakeExchangeActivity) record(exchange string, total, failed int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s := f.stats["ccxt:"+exchange]
+	s.TotalRequests += total
+	s.FailedRequests += failed
+	f.stats["ccxt:"+exchange] = s
+}
+
+func newTestOutageDetector(t *testing.T, activity ExchangeActivitySource, db DBPool, config ExchangeOutageConfig) *ExchangeOutageDetector {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewExchangeOutageDetector(activity, db, client, config)
+}
+
+func TestExchangeOutageDetector_PausesAndResumes(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	activity := &fakeExchangeActivity{
+		stats: map[string]CircuitBreakerStats{"ccxt:binance": {}, "ccxt:kraken": {}},
+		workers: map[string]*Worker{
+			"binance": {Exchange: "binance", IsRunning: true, LastUpdate: now},
+			"kraken":  {Exchange: "kraken", IsRunning: true, LastUpdate: now},
+		},
+	}
+	detector := newTestOutageDetector(t, activity, nil, ExchangeOutageConfig{
+		StablePeriod:  10 * time.Minute,
+		NotifyChatIDs: []int64{7},
+	})
+	detector.now = func() time.Time { return now }
+	emitter := &recordingEmitter{}
+	messenger := &recordingMessenger{}
+	detector.SetEventEmitter(emitter)
+	detector.SetMessenger(messenger)
+	ctx := t.Context()
+
+	// The first check only records the breaker counters.
+	activity.record("binance", 10, 9)
+	health, err := detector.Check(ctx)
+	require.NoError(t, err)
+	require.Len(t, health, 2)
+	assert.False(t, health[0].Paused)
+
+	activity.record("binance", 10, 8)
+	activity.record("kraken", 10, 0)
+	health, err = detector.Check(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "binance", health[0].Exchange)
+	assert.True(t, health[0].Paused)
+	assert.InDelta(t, 0.8, health[0].ErrorRate, 1e-9)
+	assert.Equal(t, []string{"error rate 80%"}, health[0].Reasons)
+	assert.False(t, health[1].Paused)
+	assert.True(t, detector.ExchangePaused(ctx, "Binance"))
+	assert.False(t, detector.ExchangePaused(ctx, "kraken"))
+	assert.Equal(t, []WebhookEventType{WebhookEventRisk}, emitter.events)
+	require.Len(t, messenger.texts, 1)
+	assert.Contains(t, messenger.texts[0], "binance paused")
+
+	// Healthy again, but not for the whole stable period yet.
+	now = now.Add(5 * time.Minute)
+	activity.workers["binance"].LastUpdate = now
+	activity.record("binance", 10, 0)
+	health, err = detector.Check(ctx)
+	require.NoError(t, err)
+	assert.True(t, health[0].Paused)
+	assert.False(t, health[0].Degraded)
+
+	now = now.Add(6 * time.Minute)
+	activity.workers["binance"].LastUpdate = now
+	activity.workers["kraken"].LastUpdate = now
+	activity.record("binance", 10, 0)
+	health, err = detector.Check(ctx)
+	require.NoError(t, err)
+	assert.False(t, health[0].Paused)
+	assert.False(t, detector.ExchangePaused(ctx, "binance"))
+	assert.Len(t, emitter.events, 2)
+	require.Len(t, messenger.texts, 2)
+	assert.Contains(t, messenger.texts[1], "binance resumed")
+}
+
+func TestExchangeOutageDetector_StaleTickersAndSpreads(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	activity := &fakeExchangeActivity{
+		stats: map[string]CircuitBreakerStats{},
+		workers: map[string]*Worker{
+			"binance": {Exchange: "binance", IsRunning: true, LastUpdate: now.Add(-10 * time.Minute)},
+			"kraken":  {Exchange: "kraken", IsRunning: true, LastUpdate: now},
+			"okx":     {Exchange: "okx", IsRunning: true, LastUpdate: now},
+		},
+	}
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	mockPool.ExpectQuery("SELECT e.ccxt_id, AVG").
+		WithArgs(pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"ccxt_id", "spread"}).
+			AddRow("kraken", 2.5).
+			AddRow("okx", 0.1))
+	detector := newTestOutageDetector(t, activity, database.NewMockDBPool(mockPool), ExchangeOutageConfig{})
+	detector.now = func() time.Time { return now }
+
+	health, err := detector.Check(t.Context())
+	require.NoError(t, err)
+	require.Len(t, health, 3)
+	assert.Equal(t, []string{"tickers stale for 10m0s"}, health[0].Reasons)
+	assert.True(t, health[0].Paused)
+	assert.Equal(t, []string{"average spread 2.50%"}, health[1].Reasons)
+	assert.True(t, health[1].Paused)
+	assert.False(t, health[2].Paused)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+
+	stored, err := detector.Health(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, health, stored)
+}
+
+func TestExchangeOutageDetector_StartStop(t *testing.T) {
+	activity := &fakeExchangeActivity{stats: map[string]CircuitBreakerStats{}, workers: map[string]*Worker{}}
+	detector := newTestOutageDetector(t, activity, nil, ExchangeOutageConfig{})
+
+	require.NoError(t, detector.Start(context.Background()))
+	assert.Error(t, detector.Start(context.Background()))
+	detector.Stop()
+	detector.Stop()
+}
@@ -0,0 +1,601 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/shopspring/decimal"
+)
+
+// Execution algorithms for parent orders too large for a single market order.
+const (
+	// ExecutionAlgoTWAP splits the order into equal market slices spread
+	// evenly over the schedule.
+	ExecutionAlgoTWAP = "twap"
+	// ExecutionAlgoIceberg rests one visible limit slice at a time and places
+	// the next once it fills, hiding the rest of the quantity.
+	ExecutionAlgoIceberg = "iceberg"
+)
+
+// AlgoOrderStatus is the lifecycle state of a parent order.
+type AlgoOrderStatus string
+
+const (
+	AlgoOrderRunning   AlgoOrderStatus = "running"
+	AlgoOrderCompleted AlgoOrderStatus = "completed"
+	AlgoOrderCancelled AlgoOrderStatus = "cancelled"
+	AlgoOrderFailed    AlgoOrderStatus = "failed"
+)
+
+var (
+	// ErrAlgoOrderNotFound is returned for unknown parent order IDs.
+	ErrAlgoOrderNotFound = errors.New("algo order not found")
+	// ErrAlgoTradingHalted is returned while the kill switch is engaged.
+	ErrAlgoTradingHalted = errors.New("trading is halted by the kill switch")
+	// ErrAlgoNotLive is returned outside live mode: child orders go straight
+	// to the exchange.
+	ErrAlgoNotLive = errors.New("algo orders place real orders and need live mode")
+
+	errAlgoAdverseMove = errors.New("price moved against the order")
+	errAlgoExpired     = errors.New("iceberg did not fill before its deadline")
+)
+
+// AlgoOrderExecutor places, inspects and cancels the child orders.
+type AlgoOrderExecutor interface {
+	PlaceOrder(ctx context.Context, exchange, symbol, side, orderType string, amount decimal.Decimal, price *decimal.Decimal) (string, error)
+	GetOrder(ctx context.Context, exchange, orderID string) (map[string]interface{}, error)
+	CancelOrder(ctx context.Context, exchange, orderID string) error
+}
+
+// AlgoTradingGuard reports whether child orders may reach the exchange.
+type AlgoTradingGuard interface {
+	KillSwitchEngaged(ctx context.Context) bool
+	IsLive(ctx context.Context) bool
+}
+
+// AlgoPriceSource provides the prices used for adverse move checks.
+type AlgoPriceSource interface {
+	FetchSingleTicker(ctx context.Context, exchange, symbol string) (ccxt.MarketPriceInterface, error)
+}
+
+// AlgoOrderManagerConfig configures the execution algorithms.
+type AlgoOrderManagerConfig struct {
+	// DefaultSlices is the number of TWAP slices when a request sets none.
+	DefaultSlices int
+	// DefaultDuration is the TWAP schedule when a request sets none.
+	DefaultDuration time.Duration
+	// IcebergTimeout is how long an iceberg may rest when a request sets no duration.
+	IcebergTimeout time.Duration
+	// PollInterval is how often a resting iceberg slice is checked.
+	PollInterval time.Duration
+	// DefaultMaxAdverseMovePct cancels an order once the price moves this
+	// many percent against it; requests may override it.
+	DefaultMaxAdverseMovePct float64
+}
+
+// AlgoOrderRequest is a parent order to execute with an algorithm.
+type AlgoOrderRequest struct {
+	Exchange string          `json:"exchange"`
+	Symbol   string          `json:"symbol"`
+	Side     string          `json:"side"`
+	Algo     string          `json:"algo"`
+	Amount   decimal.Decimal `json:"amount"`
+	// Slices is the number of TWAP child orders.
+	Slices int `json:"slices,omitempty"`
+	// Duration is the TWAP schedule, or how long an iceberg may rest.
+	Duration time.Duration `json:"-"`
+	// VisibleAmount is the size of each iceberg slice.
+	VisibleAmount decimal.Decimal `json:"visible_amount,omitempty"`
+	// LimitPrice prices the iceberg slices.
+	LimitPrice *decimal.Decimal `json:"limit_price,omitempty"`
+	// MaxAdverseMovePct overrides the configured adverse move limit; a
+	// negative value disables the check.
+	MaxAdverseMovePct float64 `json:"max_adverse_move_pct,omitempty"`
+}
+
+// AlgoSlice is one child order of a parent order.
+type AlgoSlice struct {
+	OrderID string           `json:"order_id,omitempty"`
+	Amount  decimal.Decimal  `json:"amount"`
+	Price   *decimal.Decimal `json:"price,omitempty"`
+	// Status is placed, filled, cancelled or failed.
+	Status string    `json:"status"`
+	Error  string    `json:"error,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// AlgoOrder is a parent order and its progress.
+type AlgoOrder struct {
+	ID string `json:"id"`
+	AlgoOrderRequest
+	Schedule    string          `json:"schedule"`
+	Status      AlgoOrderStatus `json:"status"`
+	Filled      decimal.Decimal `json:"filled"`
+	StartPrice  decimal.Decimal `json:"start_price"`
+	ChildOrders []AlgoSlice     `json:"child_orders"`
+	Reason      string          `json:"reason,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty"`
+}
+
+// AlgoOrderManager executes parent orders as TWAP or iceberg child orders and
+// cancels what is left when the price moves too far against them. Parent
+// orders are held in memory; child orders already placed stay on the
+// exchange if the process restarts.
+type AlgoOrderManager struct {
+	executor AlgoOrderExecutor
+	prices   AlgoPriceSource
+	updates  OrderUpdateWaiter
+	guard    AlgoTradingGuard
+	config   AlgoOrderManagerConfig
+	logger   *slog.Logger
+	now      func() time.Time
+	sleep    func(ctx context.Context, d time.Duration) error
+
+	mu      sync.Mutex
+	orders  map[string]*AlgoOrder
+	cancels map[string]context.CancelFunc
+	done    map[string]chan struct{}
+	wg      sync.WaitGroup
+	ctx     context.Context
+	stop    context.CancelFunc
+}
+
+// NewAlgoOrderManager creates the execution algorithm manager.
+//
+// Parameters:
+//
+//	executor: Places and manages the child orders.
+//	prices: Price source for adverse move checks.
+//	config: Algorithm defaults; zero values use defaults.
+//
+// Returns:
+//
+//	*AlgoOrderManager: Initialized manager.
+func NewAlgoOrderManager(executor AlgoOrderExecutor, prices AlgoPriceSource, config AlgoOrderManagerConfig) *AlgoOrderManager {
+	if config.DefaultSlices <= 0 {
+		config.DefaultSlices = 10
+	}
+	if config.DefaultDuration <= 0 {
+		config.DefaultDuration = 10 * time.Minute
+	}
+	if config.IcebergTimeout <= 0 {
+		config.IcebergTimeout = time.Hour
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = 2 * time.Second
+	}
+	if config.DefaultMaxAdverseMovePct == 0 {
+		config.DefaultMaxAdverseMovePct = 1
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	return &AlgoOrderManager{
+		executor: executor,
+		prices:   prices,
+		config:   config,
+		logger:   telemetry.Logger(),
+		now:      time.Now,
+		sleep:    sleepContext,
+		orders:   make(map[string]*AlgoOrder),
+		cancels:  make(map[string]context.CancelFunc),
+		done:     make(map[string]chan struct{}),
+		ctx:      ctx,
+		stop:     stop,
+	}
+}
+
+// SetOrderUpdates wakes resting iceberg slices on streamed order updates
+// instead of waiting out the poll interval.
+func (m *AlgoOrderManager) SetOrderUpdates(updates OrderUpdateWaiter) {
+	m.updates = updates
+}
+
+// SetTradingGuard refuses new parent orders and stops running ones while the
+// kill switch is engaged or the account is not in live mode.
+func (m *AlgoOrderManager) SetTradingGuard(guard AlgoTradingGuard) {
+	m.guard = guard
+}
+
+// tradingAllowed is checked on submit and before every child order.
+func (m *AlgoOrderManager) tradingAllowed(ctx context.Context) error {
+	if m.guard == nil {
+		return nil
+	}
+	if m.guard.KillSwitchEngaged(ctx) {
+		return ErrAlgoTradingHalted
+	}
+	if !m.guard.IsLive(ctx) {
+		return ErrAlgoNotLive
+	}
+	return nil
+}
+
+// Submit validates a parent order and starts executing it in the background.
+//
+// Parameters:
+//
+//	ctx: Context for the trading checks.
+//	req: The parent order.
+//
+// Returns:
+//
+//	*AlgoOrder: The running order.
+//	error: Error if the request is invalid or trading is not allowed.
+func (m *AlgoOrderManager) Submit(ctx context.Context, req AlgoOrderRequest) (*AlgoOrder, error) {
+	req.Algo = strings.ToLower(strings.TrimSpace(req.Algo))
+	req.Side = strings.ToLower(strings.TrimSpace(req.Side))
+	if req.Exchange == "" || req.Symbol == "" {
+		return nil, fmt.Errorf("exchange and symbol are required")
+	}
+	if req.Side != "buy" && req.Side != "sell" {
+		return nil, fmt.Errorf("side must be buy or sell")
+	}
+	if !req.Amount.IsPositive() {
+		return nil, fmt.Errorf("amount must be greater than zero")
+	}
+	switch req.Algo {
+	case ExecutionAlgoTWAP:
+		if req.Slices <= 0 {
+			req.Slices = m.config.DefaultSlices
+		}
+		if req.Duration <= 0 {
+			req.Duration = m.config.DefaultDuration
+		}
+	case ExecutionAlgoIceberg:
+		if req.LimitPrice == nil || !req.LimitPrice.IsPositive() {
+			return nil, fmt.Errorf("iceberg orders need a limit price")
+		}
+		if !req.VisibleAmount.IsPositive() || req.VisibleAmount.GreaterThanOrEqual(req.Amount) {
+			return nil, fmt.Errorf("visible amount must be positive and smaller than the amount")
+		}
+		if req.Duration <= 0 {
+			req.Duration = m.config.IcebergTimeout
+		}
+	default:
+		return nil, fmt.Errorf("algo must be %s or %s", ExecutionAlgoTWAP, ExecutionAlgoIceberg)
+	}
+	if req.MaxAdverseMovePct == 0 {
+		req.MaxAdverseMovePct = m.config.DefaultMaxAdverseMovePct
+	}
+	if err := m.tradingAllowed(ctx); err != nil {
+		return nil, err
+	}
+
+	now := m.now().UTC()
+	order := &AlgoOrder{
+		ID:               uuid.NewString(),
+		AlgoOrderRequest: req,
+		Schedule:         req.Duration.String(),
+		Status:           AlgoOrderRunning,
+		ChildOrders:      []AlgoSlice{},
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ctx.Err() != nil {
+		return nil, fmt.Errorf("algo order manager is stopped")
+	}
+	runCtx, cancel := context.WithCancel(m.ctx)
+	m.orders[order.ID] = order
+	m.cancels[order.ID] = cancel
+	done := make(chan struct{})
+	m.done[order.ID] = done
+	m.wg.Add(1)
+	go func() {
+		defer close(done)
+		m.run(runCtx, order)
+	}()
+
+	m.logger.Info("Algo order started", "id", order.ID, "algo", req.Algo, "symbol", req.Symbol, "side", req.Side, "amount", req.Amount.String())
+	return order.copy(), nil
+}
+
+// Get returns a parent order.
+func (m *AlgoOrderManager) Get(id string) (*AlgoOrder, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	order, ok := m.orders[id]
+	if !ok {
+		return nil, ErrAlgoOrderNotFound
+	}
+	return order.copy(), nil
+}
+
+// List returns every parent order, newest first.
+func (m *AlgoOrderManager) List() []AlgoOrder {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	orders := make([]AlgoOrder, 0, len(m.orders))
+	for _, order := range m.orders {
+		orders = append(orders, *order.copy())
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].CreatedAt.After(orders[j].CreatedAt) })
+	return orders
+}
+
+// Cancel stops a running parent order and cancels its resting child order.
+// It returns once the order has stopped.
+func (m *AlgoOrderManager) Cancel(id string) (*AlgoOrder, error) {
+	m.mu.Lock()
+	_, ok := m.orders[id]
+	cancel := m.cancels[id]
+	done := m.done[id]
+	m.mu.Unlock()
+	if !ok {
+		return nil, ErrAlgoOrderNotFound
+	}
+	if cancel != nil {
+		cancel()
+	}
+	<-done
+	return m.Get(id)
+}
+
+// Stop cancels every running parent order and waits for them to stop.
+func (m *AlgoOrderManager) Stop() {
+	m.stop()
+	m.wg.Wait()
+}
+
+func (m *AlgoOrderManager) run(ctx context.Context, order *AlgoOrder) {
+	defer m.wg.Done()
+
+	start, err := m.price(ctx, order.Exchange, order.Symbol)
+	switch {
+	case err == nil:
+		m.update(order, func(o *AlgoOrder) { o.StartPrice = start })
+	case order.LimitPrice != nil:
+		start = *order.LimitPrice
+		m.update(order, func(o *AlgoOrder) { o.StartPrice = start })
+	case order.MaxAdverseMovePct > 0:
+		m.finish(order, AlgoOrderFailed, "start price unavailable: "+err.Error())
+		return
+	}
+
+	if order.Algo == ExecutionAlgoIceberg {
+		err = m.runIceberg(ctx, order, start)
+	} else {
+		err = m.runTWAP(ctx, order, start)
+	}
+	switch {
+	case err == nil:
+		m.finish(order, AlgoOrderCompleted, "")
+	case errors.Is(err, errAlgoAdverseMove), errors.Is(err, errAlgoExpired),
+		errors.Is(err, ErrAlgoTradingHalted), errors.Is(err, ErrAlgoNotLive):
+		m.finish(order, AlgoOrderCancelled, err.Error())
+	case ctx.Err() != nil:
+		m.finish(order, AlgoOrderCancelled, "cancelled")
+	default:
+		m.finish(order, AlgoOrderFailed, err.Error())
+	}
+}
+
+// runTWAP places equal market slices at even intervals. The last slice takes
+// the rounding remainder.
+func (m *AlgoOrderManager) runTWAP(ctx context.Context, order *AlgoOrder, start decimal.Decimal) error {
+	interval := order.Duration / time.Duration(order.Slices)
+	sliceAmount := order.Amount.DivRound(decimal.NewFromInt(int64(order.Slices)), 8)
+	remaining := order.Amount
+	for i := 0; i < order.Slices; i++ {
+		if i > 0 {
+			if err := m.sleep(ctx, interval); err != nil {
+				return err
+			}
+		}
+		if err := m.tradingAllowed(ctx); err != nil {
+			return err
+		}
+		if err := m.checkAdverse(ctx, order, start); err != nil {
+			return err
+		}
+		amount := sliceAmount
+		if i == order.Slices-1 || amount.GreaterThan(remaining) {
+			amount = remaining
+		}
+		orderID, err := m.executor.PlaceOrder(ctx, order.Exchange, order.Symbol, order.Side, "market", amount, nil)
+		slice := AlgoSlice{OrderID: orderID, Amount: amount, Status: "filled", At: m.now().UTC()}
+		if err != nil {
+			slice.Status, slice.Error = "failed", err.Error()
+		}
+		m.update(order, func(o *AlgoOrder) {
+			o.ChildOrders = append(o.ChildOrders, slice)
+			if err == nil {
+				o.Filled = o.Filled.Add(amount)
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("slice %d failed: %w", i+1, err)
+		}
+		remaining = remaining.Sub(amount)
+	}
+	return nil
+}
+
+// runIceberg rests one visible limit slice at a time until the whole amount
+// fills, the price moves against the order or the deadline passes.
+func (m *AlgoOrderManager) runIceberg(ctx context.Context, order *AlgoOrder, start decimal.Decimal) error {
+	deadline := m.now().Add(order.Duration)
+	remaining := order.Amount
+	for remaining.IsPositive() {
+		if err := m.tradingAllowed(ctx); err != nil {
+			return err
+		}
+		if err := m.checkAdverse(ctx, order, start); err != nil {
+			return err
+		}
+		amount := decimal.Min(order.VisibleAmount, remaining)
+		orderID, err := m.executor.PlaceOrder(ctx, order.Exchange, order.Symbol, order.Side, "limit", amount, order.LimitPrice)
+		slice := AlgoSlice{OrderID: orderID, Amount: amount, Price: order.LimitPrice, Status: "placed", At: m.now().UTC()}
+		if err != nil {
+			slice.Status, slice.Error = "failed", err.Error()
+		}
+		index := m.appendSlice(order, slice)
+		if err != nil {
+			return fmt.Errorf("slice %d failed: %w", index+1, err)
+		}
+
+		for {
+			if err := waitOrderUpdate(ctx, m.updates, m.sleep, order.Exchange, orderID, m.config.PollInterval); err != nil {
+				m.cancelSlice(order, index)
+				return err
+			}
+			status, filled, err := m.childStatus(ctx, order.Exchange, orderID)
+			if err != nil {
+				m.logger.Warn("Failed to check iceberg slice", "id", order.ID, "order_id", orderID, "error", err)
+			} else if status == "closed" {
+				m.settleSlice(order, index, "filled", amount)
+				break
+			} else if status == "canceled" || status == "expired" || status == "rejected" {
+				m.settleSlice(order, index, "cancelled", filled)
+				return fmt.Errorf("slice %s was %s on the exchange", orderID, status)
+			}
+			if err := m.tradingAllowed(ctx); err != nil {
+				m.cancelSlice(order, index)
+				return err
+			}
+			if err := m.checkAdverse(ctx, order, start); err != nil {
+				m.cancelSlice(order, index)
+				return err
+			}
+			if !m.now().Before(deadline) {
+				m.cancelSlice(order, index)
+				return errAlgoExpired
+			}
+		}
+		remaining = remaining.Sub(amount)
+	}
+	return nil
+}
+
+// checkAdverse fails once the price has moved more than the order's limit
+// against it since the start. Price lookups that fail do not cancel.
+func (m *AlgoOrderManager) checkAdverse(ctx context.Context, order *AlgoOrder, start decimal.Decimal) error {
+	if order.MaxAdverseMovePct <= 0 || !start.IsPositive() {
+		return nil
+	}
+	price, err := m.price(ctx, order.Exchange, order.Symbol)
+	if err != nil {
+		m.logger.Warn("Failed to fetch price for adverse move check", "id", order.ID, "error", err)
+		return nil
+	}
+	move := price.Sub(start).Div(start).Mul(decimal.NewFromInt(100))
+	if order.Side == "sell" {
+		move = move.Neg()
+	}
+	if move.InexactFloat64() > order.MaxAdverseMovePct {
+		m.logger.Warn("Algo order price moved against it, cancelling the rest",
+			"id", order.ID, "symbol", order.Symbol, "move_pct", move.StringFixed(2))
+		return fmt.Errorf("%w: %s%% since start", errAlgoAdverseMove, move.StringFixed(2))
+	}
+	return nil
+}
+
+func (m *AlgoOrderManager) price(ctx context.Context, exchange, symbol string) (decimal.Decimal, error) {
+	if m.prices == nil {
+		return decimal.Zero, fmt.Errorf("no price source configured")
+	}
+	ticker, err := m.prices.FetchSingleTicker(ctx, exchange, symbol)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if ticker == nil || ticker.GetPrice() <= 0 {
+		return decimal.Zero, fmt.Errorf("no price for %s on %s", symbol, exchange)
+	}
+	return decimal.NewFromFloat(ticker.GetPrice()), nil
+}
+
+// childStatus returns a child order's CCXT status and filled amount.
+func (m *AlgoOrderManager) childStatus(ctx context.Context, exchange, orderID string) (string, decimal.Decimal, error) {
+	result, err := m.executor.GetOrder(ctx, exchange, orderID)
+	if err != nil {
+		return "", decimal.Zero, err
+	}
+	order := result
+	if nested, ok := result["order"].(map[string]interface{}); ok {
+		order = nested
+	}
+	status, _ := order["status"].(string)
+	filled := decimal.Zero
+	if value, ok := order["filled"].(float64); ok {
+		filled = decimal.NewFromFloat(value)
+	}
+	return status, filled, nil
+}
+
+// cancelSlice cancels a resting child order, keeping what it filled.
+func (m *AlgoOrderManager) cancelSlice(order *AlgoOrder, index int) {
+	// The parent's context may already be cancelled
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(m.ctx), 10*time.Second)
+	defer cancel()
+	m.mu.Lock()
+	orderID := order.ChildOrders[index].OrderID
+	m.mu.Unlock()
+
+	if err := m.executor.CancelOrder(ctx, order.Exchange, orderID); err != nil {
+		m.logger.Warn("Failed to cancel iceberg slice", "id", order.ID, "order_id", orderID, "error", err)
+	}
+	_, filled, err := m.childStatus(ctx, order.Exchange, orderID)
+	if err != nil {
+		filled = decimal.Zero
+	}
+	m.settleSlice(order, index, "cancelled", filled)
+}
+
+func (m *AlgoOrderManager) appendSlice(order *AlgoOrder, slice AlgoSlice) int {
+	var index int
+	m.update(order, func(o *AlgoOrder) {
+		o.ChildOrders = append(o.ChildOrders, slice)
+		index = len(o.ChildOrders) - 1
+	})
+	return index
+}
+
+func (m *AlgoOrderManager) settleSlice(order *AlgoOrder, index int, status string, filled decimal.Decimal) {
+	m.update(order, func(o *AlgoOrder) {
+		o.ChildOrders[index].Status = status
+		o.Filled = o.Filled.Add(filled)
+	})
+}
+
+func (m *AlgoOrderManager) finish(order *AlgoOrder, status AlgoOrderStatus, reason string) {
+	var filled decimal.Decimal
+	m.update(order, func(o *AlgoOrder) {
+		now := m.now().UTC()
+		o.Status = status
+		o.Reason = reason
+		o.CompletedAt = &now
+		filled = o.Filled
+	})
+	m.mu.Lock()
+	if cancel := m.cancels[order.ID]; cancel != nil {
+		cancel()
+		delete(m.cancels, order.ID)
+	}
+	m.mu.Unlock()
+	m.logger.Info("Algo order finished", "id", order.ID, "status", status, "filled", filled.String(), "reason", reason)
+}
+
+func (m *AlgoOrderManager) update(order *AlgoOrder, fn func(o *AlgoOrder)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	fn(order)
+	order.UpdatedAt = m.now().UTC()
+}
+
+func (o *AlgoOrder) copy() *AlgoOrder {
+	clone := *o
+	clone.ChildOrders = append([]AlgoSlice(nil), o.ChildOrders...)
+	return &clone
+}
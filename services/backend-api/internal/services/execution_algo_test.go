@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// algoTestExecutor fills or rests child orders and records cancellations.
+type algoTestExecutor struct {
+	mu        sync.Mutex
+	amounts   []decimal.Decimal
+	types     []string
+	status    string
+	filled    float64
+	cancelled []string
+}
+
+func (e *algoTestExecutor) PlaceOrder(ctx context.Context, exchange, symbol, side, orderType string, amount decimal.Decimal, price *decimal.Decimal) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.amounts = append(e.amounts, amount)
+	e.types = append(e.types, orderType)
+	return fmt.Sprintf("child-%d", len(e.amounts)), nil
+}
+
+func (e *algoTestExecutor) GetOrder(ctx context.Context, exchange, orderID string) (map[string]interface{}, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return map[string]interface{}{"order": map[string]interface{}{"id": orderID, "status": e.status, "filled": e.filled}}, nil
+}
+
+func (e *algoTestExecutor) CancelOrder(ctx context.Context, exchange, orderID string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cancelled = append(e.cancelled, orderID)
+	return nil
+}
+
+// algoTestPrices returns the given prices in turn, repeating the last one.
+type algoTestPrices struct {
+	mu     sync.Mutex
+	prices []float64
+}
+
+func (p *algoTestPrices) FetchSingleTicker(ctx context.Context, exchange, symbol string) (ccxt.MarketPriceInterface, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	price := p.prices[0]
+	if len(p.prices) > 1 {
+		p.prices = p.prices[1:]
+	}
+	return &models.MarketPrice{ExchangeName: exchange, Symbol: symbol, Price: decimal.NewFromFloat(price)}, nil
+}
+
+func newTestAlgoOrderManager(executor *algoTestExecutor, prices ...float64) (*AlgoOrderManager, *[]time.Duration) {
+	manager := NewAlgoOrderManager(executor, &algoTestPrices{prices: prices}, AlgoOrderManagerConfig{})
+	var mu sync.Mutex
+	sleeps := []time.Duration{}
+	manager.sleep = func(ctx context.Context, d time.Duration) error {
+		mu.Lock()
+		sleeps = append(sleeps, d)
+		mu.Unlock()
+		return ctx.Err()
+	}
+	return manager, &sleeps
+}
+
+func waitAlgoOrder(t *testing.T, manager *AlgoOrderManager, id string) *AlgoOrder {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		order, err := manager.Get(id)
+		return err == nil && order.Status != AlgoOrderRunning
+	}, 2*time.Second, 5*time.Millisecond)
+	order, err := manager.Get(id)
+	require.NoError(t, err)
+	return order
+}
+
+func TestAlgoOrderManager_TWAP(t *testing.T) {
+	executor := &algoTestExecutor{}
+	manager, sleeps := newTestAlgoOrderManager(executor, 100)
+	defer manager.Stop()
+
+	submitted, err := manager.Submit(t.Context(), AlgoOrderRequest{Exchange: "binance", Symbol: "BTC/USDT", Side: "BUY", Algo: "TWAP", Amount: decimal.NewFromInt(10), Slices: 3, Duration: 3 * time.Minute})
+	require.NoError(t, err)
+	order := waitAlgoOrder(t, manager, submitted.ID)
+
+	assert.Equal(t, AlgoOrderCompleted, order.Status)
+	assert.Equal(t, "10", order.Filled.String())
+	assert.Equal(t, "100", order.StartPrice.String())
+	require.Len(t, order.ChildOrders, 3)
+	assert.Equal(t, []string{"3.33333333", "3.33333333", "3.33333334"},
+		[]string{executor.amounts[0].String(), executor.amounts[1].String(), executor.amounts[2].String()}, "the last slice takes the remainder")
+	assert.Equal(t, []string{"market", "market", "market"}, executor.types)
+	assert.Equal(t, []time.Duration{time.Minute, time.Minute}, *sleeps)
+	assert.NotNil(t, order.CompletedAt)
+}
+
+func TestAlgoOrderManager_TWAPAdverseMove(t *testing.T) {
+	executor := &algoTestExecutor{}
+	// Start, first slice check, then a 2% rise against the buy
+	manager, _ := newTestAlgoOrderManager(executor, 100, 100.5, 102)
+	defer manager.Stop()
+
+	submitted, err := manager.Submit(t.Context(), AlgoOrderRequest{Exchange: "binance", Symbol: "BTC/USDT", Side: "buy", Algo: ExecutionAlgoTWAP, Amount: decimal.NewFromInt(4), Slices: 4})
+	require.NoError(t, err)
+	order := waitAlgoOrder(t, manager, submitted.ID)
+
+	assert.Equal(t, AlgoOrderCancelled, order.Status)
+	assert.Contains(t, order.Reason, "price moved against the order")
+	assert.Len(t, order.ChildOrders, 1)
+	assert.Equal(t, "1", order.Filled.String())
+}
+
+func TestAlgoOrderManager_Iceberg(t *testing.T) {
+	executor := &algoTestExecutor{status: "closed"}
+	manager, _ := newTestAlgoOrderManager(executor, 2500)
+	defer manager.Stop()
+	limit := decimal.NewFromInt(2500)
+
+	submitted, err := manager.Submit(t.Context(), AlgoOrderRequest{Exchange: "binance", Symbol: "ETH/USDT", Side: "sell", Algo: ExecutionAlgoIceberg, Amount: decimal.NewFromInt(5), VisibleAmount: decimal.NewFromInt(2), LimitPrice: &limit})
+	require.NoError(t, err)
+	order := waitAlgoOrder(t, manager, submitted.ID)
+
+	assert.Equal(t, AlgoOrderCompleted, order.Status)
+	assert.Equal(t, "5", order.Filled.String())
+	require.Len(t, order.ChildOrders, 3)
+	assert.Equal(t, "1", order.ChildOrders[2].Amount.String())
+	assert.Equal(t, "filled", order.ChildOrders[0].Status)
+	assert.Equal(t, []string{"limit", "limit", "limit"}, executor.types)
+}
+
+func TestAlgoOrderManager_IcebergAdverseMoveCancelsRestingSlice(t *testing.T) {
+	executor := &algoTestExecutor{status: "open", filled: 0.5}
+	// The price drops 3% against the sell while the first slice rests
+	manager, _ := newTestAlgoOrderManager(executor, 2500, 2500, 2425)
+	defer manager.Stop()
+	limit := decimal.NewFromInt(2500)
+
+	submitted, err := manager.Submit(t.Context(), AlgoOrderRequest{Exchange: "binance", Symbol: "ETH/USDT", Side: "sell", Algo: ExecutionAlgoIceberg, Amount: decimal.NewFromInt(5), VisibleAmount: decimal.NewFromInt(2), LimitPrice: &limit})
+	require.NoError(t, err)
+	order := waitAlgoOrder(t, manager, submitted.ID)
+
+	assert.Equal(t, AlgoOrderCancelled, order.Status)
+	assert.Equal(t, []string{"child-1"}, executor.cancelled)
+	require.Len(t, order.ChildOrders, 1)
+	assert.Equal(t, "cancelled", order.ChildOrders[0].Status)
+	assert.Equal(t, "0.5", order.Filled.String(), "the partial fill is kept")
+}
+
+func TestAlgoOrderManager_Cancel(t *testing.T) {
+	executor := &algoTestExecutor{status: "open"}
+	manager := NewAlgoOrderManager(executor, &algoTestPrices{prices: []float64{2500}}, AlgoOrderManagerConfig{PollInterval: time.Hour})
+	defer manager.Stop()
+	limit := decimal.NewFromInt(2500)
+
+	submitted, err := manager.Submit(t.Context(), AlgoOrderRequest{Exchange: "binance", Symbol: "ETH/USDT", Side: "buy", Algo: ExecutionAlgoIceberg, Amount: decimal.NewFromInt(5), VisibleAmount: decimal.NewFromInt(1), LimitPrice: &limit})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		order, _ := manager.Get(submitted.ID)
+		return len(order.ChildOrders) == 1
+	}, 2*time.Second, 5*time.Millisecond)
+
+	order, err := manager.Cancel(submitted.ID)
+	require.NoError(t, err)
+	assert.Equal(t, AlgoOrderCancelled, order.Status)
+	assert.Equal(t, "cancelled", order.Reason)
+	assert.Equal(t, []string{"child-1"}, executor.cancelled)
+	assert.Len(t, manager.List(), 1)
+
+	_, err = manager.Cancel("missing")
+	assert.ErrorIs(t, err, ErrAlgoOrderNotFound)
+}
+
+func TestAlgoOrderManager_SubmitValidation(t *testing.T) {
+	manager := NewAlgoOrderManager(&algoTestExecutor{}, nil, AlgoOrderManagerConfig{})
+	defer manager.Stop()
+	limit := decimal.NewFromInt(100)
+
+	_, err := manager.Submit(t.Context(), AlgoOrderRequest{Exchange: "binance", Symbol: "BTC/USDT", Side: "buy", Algo: "vwap", Amount: decimal.NewFromInt(1)})
+	assert.EqualError(t, err, "algo must be twap or iceberg")
+	_, err = manager.Submit(t.Context(), AlgoOrderRequest{Exchange: "binance", Symbol: "BTC/USDT", Side: "hold", Algo: ExecutionAlgoTWAP, Amount: decimal.NewFromInt(1)})
+	assert.EqualError(t, err, "side must be buy or sell")
+	_, err = manager.Submit(t.Context(), AlgoOrderRequest{Exchange: "binance", Symbol: "BTC/USDT", Side: "buy", Algo: ExecutionAlgoIceberg, Amount: decimal.NewFromInt(1)})
+	assert.EqualError(t, err, "iceberg orders need a limit price")
+	_, err = manager.Submit(t.Context(), AlgoOrderRequest{Exchange: "binance", Symbol: "BTC/USDT", Side: "buy", Algo: ExecutionAlgoIceberg, Amount: decimal.NewFromInt(1), VisibleAmount: decimal.NewFromInt(1), LimitPrice: &limit})
+	assert.EqualError(t, err, "visible amount must be positive and smaller than the amount")
+
+	// Without a price source a TWAP cannot track adverse moves
+	submitted, err := manager.Submit(t.Context(), AlgoOrderRequest{Exchange: "binance", Symbol: "BTC/USDT", Side: "buy", Algo: ExecutionAlgoTWAP, Amount: decimal.NewFromInt(1)})
+	require.NoError(t, err)
+	order := waitAlgoOrder(t, manager, submitted.ID)
+	assert.Equal(t, AlgoOrderFailed, order.Status)
+	assert.Contains(t, order.Reason, "start price unavailable")
+}
+
+// algoTestGuard engages the kill switch once the given number of checks pass.
+type algoTestGuard struct {
+	mu     sync.Mutex
+	live   bool
+	checks int
+	haltAt int
+}
+
+func (g *algoTestGuard) KillSwitchEngaged(ctx context.Context) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.checks++
+	return g.haltAt > 0 && g.checks >= g.haltAt
+}
+
+func (g *algoTestGuard) IsLive(ctx context.Context) bool { return g.live }
+
+func TestAlgoOrderManager_TradingGuard(t *testing.T) {
+	executor := &algoTestExecutor{}
+	manager, _ := newTestAlgoOrderManager(executor, 100)
+	defer manager.Stop()
+	request := AlgoOrderRequest{Exchange: "binance", Symbol: "BTC/USDT", Side: "buy", Algo: ExecutionAlgoTWAP, Amount: decimal.NewFromInt(4), Slices: 4}
+
+	manager.SetTradingGuard(&algoTestGuard{})
+	_, err := manager.Submit(t.Context(), request)
+	assert.ErrorIs(t, err, ErrAlgoNotLive)
+
+	manager.SetTradingGuard(&algoTestGuard{live: true, haltAt: 1})
+	_, err = manager.Submit(t.Context(), request)
+	assert.ErrorIs(t, err, ErrAlgoTradingHalted)
+	assert.Empty(t, executor.amounts)
+
+	// The kill switch engages after the submit and two slices
+	manager.SetTradingGuard(&algoTestGuard{live: true, haltAt: 4})
+	submitted, err := manager.Submit(t.Context(), request)
+	require.NoError(t, err)
+	order := waitAlgoOrder(t, manager, submitted.ID)
+	assert.Equal(t, AlgoOrderCancelled, order.Status)
+	assert.Equal(t, ErrAlgoTradingHalted.Error(), order.Reason)
+	assert.Len(t, order.ChildOrders, 2)
+	assert.Equal(t, "2", order.Filled.String())
+}
@@ -0,0 +1,453 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/irfndi/neuratrade/pkg/indicators"
+	"github.com/shopspring/decimal"
+)
+
+// ErrStressTestInvalidQuery is returned for a malformed stress-test request.
+var ErrStressTestInvalidQuery = errors.New("invalid stress-test query")
+
+// StressScenario perturbs simulated execution. Zero fields leave that part
+// of execution as in the baseline.
+type StressScenario struct {
+	Name string `json:"name"`
+	// LatencyMs delays every fill after its signal; the fill takes the price
+	// the market moved to in the meantime.
+	LatencyMs int64 `json:"latency_ms,omitempty"`
+	// FillRatio is the fraction of every entry order that fills, between 0 and 1.
+	FillRatio float64 `json:"fill_ratio,omitempty"`
+	// FeeMultiplier scales the baseline fee rate.
+	FeeMultiplier float64 `json:"fee_multiplier,omitempty"`
+}
+
+// DefaultStressScenarios returns the scenarios run when a query names none.
+func DefaultStressScenarios() []StressScenario {
+	return []StressScenario{
+		{Name: "latency", LatencyMs: 30000},
+		{Name: "partial_fills", FillRatio: 0.5},
+		{Name: "high_fees", FeeMultiplier: 3},
+		{Name: "combined", LatencyMs: 30000, FillRatio: 0.5, FeeMultiplier: 3},
+	}
+}
+
+// StressTestConfig holds the defaults of a stress-test run.
+type StressTestConfig struct {
+	Exchange  string
+	Symbol    string
+	Timeframe string
+	// Candles is how many candles are replayed.
+	Candles int
+	// Window is how many candles the indicators see at each step; at least 50.
+	Window int
+	// HoldCandles is how long a position is held before it is closed.
+	HoldCandles int
+	// OrderNotional is the quote amount of every entry.
+	OrderNotional decimal.Decimal
+	// FeeRate is the baseline fee on the filled notional.
+	FeeRate decimal.Decimal
+	// Slippage is the baseline slippage applied to every fill.
+	Slippage decimal.Decimal
+}
+
+// DefaultStressTestConfig returns the stress-test defaults.
+func DefaultStressTestConfig() StressTestConfig {
+	return StressTestConfig{
+		Exchange:      "binance",
+		Symbol:        "BTC/USDT",
+		Timeframe:     "5m",
+		Candles:       500,
+		Window:        100,
+		HoldCandles:   12,
+		OrderNotional: decimal.NewFromInt(100),
+		FeeRate:       decimal.NewFromFloat(0.001),
+		Slippage:      decimal.NewFromFloat(0.0005),
+	}
+}
+
+// StressTestQuery selects the market and scenarios of a run. Empty fields
+// use the configured defaults.
+type StressTestQuery struct {
+	Exchange  string
+	Symbol    string
+	Timeframe string
+	Candles   int
+	Scenarios []StressScenario
+}
+
+// StressRun is the simulated result of one execution setting.
+type StressRun struct {
+	Trades      int             `json:"trades"`
+	Wins        int             `json:"wins"`
+	WinRate     float64         `json:"win_rate"`
+	GrossPnL    decimal.Decimal `json:"gross_pnl"`
+	Fees        decimal.Decimal `json:"fees"`
+	NetPnL      decimal.Decimal `json:"net_pnl"`
+	MaxDrawdown decimal.Decimal `json:"max_drawdown"`
+}
+
+// StressScenarioResult compares a stressed run with the baseline.
+type StressScenarioResult struct {
+	Scenario StressScenario `json:"scenario"`
+	StressRun
+	// PnLChange is the stressed net PnL less the baseline net PnL.
+	PnLChange decimal.Decimal `json:"pnl_change"`
+	// Retained is the share of the baseline net PnL the scenario kept; it is
+	// omitted when the baseline did not make money.
+	Retained *float64 `json:"retained,omitempty"`
+}
+
+// StressReport is the robustness report of a stress-test run.
+type StressReport struct {
+	Exchange  string                 `json:"exchange"`
+	Symbol    string                 `json:"symbol"`
+	Timeframe string                 `json:"timeframe"`
+	Candles   int                    `json:"candles"`
+	Signals   int                    `json:"signals"`
+	Baseline  StressRun              `json:"baseline"`
+	Scenarios []StressScenarioResult `json:"scenarios"`
+	// Robust is true when every scenario still made money.
+	Robust      bool      `json:"robust"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// stressSignal is an entry the strategy took at a candle.
+type stressSignal struct {
+	index int
+	side  PaperOrderSide
+}
+
+// StressTestService replays recent candles through the indicator strategy
+// once, then executes its signals in the paper execution simulator under a
+// baseline and under each stress scenario, so the scenarios differ only in
+// execution.
+type StressTestService struct {
+	candles OHLCVSource
+	stack   *indicators.MultiIndicatorStack
+	config  StressTestConfig
+	now     func() time.Time
+	// signal decides the entry side at the last candle of data; ok is false
+	// for a hold.
+	signal func(ctx context.Context, data *indicators.OHLCVData) (side PaperOrderSide, ok bool, err error)
+}
+
+// NewStressTestService creates a new stress-test service.
+//
+// Parameters:
+//
+//	candles: Market data source.
+//	config: Stress-test defaults; zero fields use DefaultStressTestConfig.
+//
+// Returns:
+//
+//	*StressTestService: The initialized service.
+func NewStressTestService(candles OHLCVSource, config StressTestConfig) *StressTestService {
+	defaults := DefaultStressTestConfig()
+	if config.Exchange == "" {
+		config.Exchange = defaults.Exchange
+	}
+	if config.Symbol == "" {
+		config.Symbol = defaults.Symbol
+	}
+	if config.Timeframe == "" {
+		config.Timeframe = defaults.Timeframe
+	}
+	if config.Candles <= 0 {
+		config.Candles = defaults.Candles
+	}
+	if config.Window < 50 {
+		config.Window = defaults.Window
+	}
+	if config.HoldCandles <= 0 {
+		config.HoldCandles = defaults.HoldCandles
+	}
+	if !config.OrderNotional.IsPositive() {
+		config.OrderNotional = defaults.OrderNotional
+	}
+	if !config.FeeRate.IsPositive() {
+		config.FeeRate = defaults.FeeRate
+	}
+	if !config.Slippage.IsPositive() {
+		config.Slippage = defaults.Slippage
+	}
+
+	s := &StressTestService{
+		candles: candles,
+		stack:   indicators.NewMultiIndicatorStack(indicators.NewDefaultProvider(), nil, nil),
+		config:  config,
+		now:     time.Now,
+	}
+	s.signal = s.indicatorSignal
+	return s
+}
+
+// Run replays the market and reports baseline and stressed PnL.
+//
+// Parameters:
+//
+//	ctx: Context for cancellation.
+//	query: Market, candle count and scenarios.
+//
+// Returns:
+//
+//	*StressReport: Baseline and per-scenario results.
+//	error: ErrStressTestInvalidQuery for a malformed query, or a market data error.
+func (s *StressTestService) Run(ctx context.Context, query StressTestQuery) (*StressReport, error) {
+	query, err := s.normalizeQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	if s.candles == nil {
+		return nil, errors.New("market data service not configured")
+	}
+
+	response, err := s.candles.FetchOHLCV(ctx, query.Exchange, query.Symbol, query.Timeframe, query.Candles)
+	if err != nil {
+		return nil, fmt.Errorf("fetch candles: %w", err)
+	}
+	if response == nil || len(response.OHLCV) < s.config.Window+s.config.HoldCandles+2 {
+		got := 0
+		if response != nil {
+			got = len(response.OHLCV)
+		}
+		return nil, fmt.Errorf("got %d candles, need at least %d", got, s.config.Window+s.config.HoldCandles+2)
+	}
+	data := &indicators.OHLCVData{Symbol: query.Symbol, Exchange: query.Exchange, Timeframe: query.Timeframe}
+	for _, candle := range response.OHLCV {
+		data.Timestamps = append(data.Timestamps, candle.Timestamp)
+		data.Open = append(data.Open, candle.Open)
+		data.High = append(data.High, candle.High)
+		data.Low = append(data.Low, candle.Low)
+		data.Close = append(data.Close, candle.Close)
+		data.Volume = append(data.Volume, candle.Volume)
+	}
+
+	signals, err := s.replaySignals(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &StressReport{
+		Exchange:    query.Exchange,
+		Symbol:      query.Symbol,
+		Timeframe:   query.Timeframe,
+		Candles:     data.Length(),
+		Signals:     len(signals),
+		Robust:      true,
+		GeneratedAt: s.now().UTC(),
+	}
+	report.Baseline, err = s.execute(ctx, data, signals, StressScenario{Name: "baseline"})
+	if err != nil {
+		return nil, err
+	}
+	for _, scenario := range query.Scenarios {
+		run, err := s.execute(ctx, data, signals, scenario)
+		if err != nil {
+			return nil, err
+		}
+		result := StressScenarioResult{
+			Scenario:  scenario,
+			StressRun: run,
+			PnLChange: run.NetPnL.Sub(report.Baseline.NetPnL),
+		}
+		if report.Baseline.NetPnL.IsPositive() {
+			retained := run.NetPnL.Div(report.Baseline.NetPnL).InexactFloat64()
+			result.Retained = &retained
+		}
+		if !run.NetPnL.IsPositive() {
+			report.Robust = false
+		}
+		report.Scenarios = append(report.Scenarios, result)
+	}
+	return report, nil
+}
+
+func (s *StressTestService) normalizeQuery(query StressTestQuery) (StressTestQuery, error) {
+	query.Exchange = strings.ToLower(strings.TrimSpace(query.Exchange))
+	if query.Exchange == "" {
+		query.Exchange = s.config.Exchange
+	}
+	query.Symbol = strings.ToUpper(strings.TrimSpace(query.Symbol))
+	if query.Symbol == "" {
+		query.Symbol = s.config.Symbol
+	}
+	if !strings.Contains(query.Symbol, "/") {
+		return query, fmt.Errorf("%w: symbol must look like BTC/USDT", ErrStressTestInvalidQuery)
+	}
+	query.Timeframe = strings.TrimSpace(query.Timeframe)
+	if query.Timeframe == "" {
+		query.Timeframe = s.config.Timeframe
+	}
+	if query.Candles <= 0 {
+		query.Candles = s.config.Candles
+	}
+	if len(query.Scenarios) == 0 {
+		query.Scenarios = DefaultStressScenarios()
+	}
+	for i, scenario := range query.Scenarios {
+		if scenario.LatencyMs < 0 || scenario.FillRatio < 0 || scenario.FillRatio > 1 || scenario.FeeMultiplier < 0 {
+			return query, fmt.Errorf("%w: scenario %d needs latency_ms >= 0, fill_ratio in [0, 1] and fee_multiplier >= 0", ErrStressTestInvalidQuery, i+1)
+		}
+		if strings.TrimSpace(scenario.Name) == "" {
+			query.Scenarios[i].Name = fmt.Sprintf("scenario_%d", i+1)
+		}
+	}
+	return query, nil
+}
+
+// replaySignals walks the candles and records every entry the strategy
+// takes while flat; a position is held for HoldCandles.
+func (s *StressTestService) replaySignals(ctx context.Context, data *indicators.OHLCVData) ([]stressSignal, error) {
+	var signals []stressSignal
+	last := data.Length() - 1
+	for i := s.config.Window - 1; i+s.config.HoldCandles < last; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		side, ok, err := s.signal(ctx, windowOHLCV(data, i+1-s.config.Window, i+1))
+		if err != nil {
+			return nil, fmt.Errorf("signal at candle %d: %w", i, err)
+		}
+		if !ok {
+			continue
+		}
+		signals = append(signals, stressSignal{index: i, side: side})
+		i += s.config.HoldCandles
+	}
+	return signals, nil
+}
+
+// execute fills every signal's entry and exit in the paper simulator under
+// one scenario. Exits close what the entry filled.
+func (s *StressTestService) execute(ctx context.Context, data *indicators.OHLCVData, signals []stressSignal, scenario StressScenario) (StressRun, error) {
+	feeRate := s.config.FeeRate
+	if scenario.FeeMultiplier > 0 {
+		feeRate = feeRate.Mul(decimal.NewFromFloat(scenario.FeeMultiplier))
+	}
+	execution := PaperExecutionConfig{SlippagePercentage: s.config.Slippage, FeeRate: feeRate}
+	exits := NewPaperExecutionSimulator(execution)
+	if scenario.FillRatio > 0 {
+		execution.FillRatio = decimal.NewFromFloat(scenario.FillRatio)
+	}
+	entries := NewPaperExecutionSimulator(execution)
+	latency := time.Duration(scenario.LatencyMs) * time.Millisecond
+
+	run := StressRun{}
+	equity, peak := decimal.Zero, decimal.Zero
+	for _, signal := range signals {
+		entry, err := s.fill(ctx, entries, data, signal.index, signal.side, s.config.OrderNotional.Div(data.Close[signal.index]), latency)
+		if err != nil {
+			return run, err
+		}
+		if !entry.FilledSize.IsPositive() {
+			continue
+		}
+		exitSide := PaperOrderSideSell
+		if signal.side == PaperOrderSideSell {
+			exitSide = PaperOrderSideBuy
+		}
+		exit, err := s.fill(ctx, exits, data, signal.index+s.config.HoldCandles, exitSide, entry.FilledSize, latency)
+		if err != nil {
+			return run, err
+		}
+
+		gross := exit.AvgFillPrice.Sub(entry.AvgFillPrice).Mul(entry.FilledSize)
+		if signal.side == PaperOrderSideSell {
+			gross = gross.Neg()
+		}
+		fees := entry.Fee.Add(exit.Fee)
+		net := gross.Sub(fees)
+
+		run.Trades++
+		if net.IsPositive() {
+			run.Wins++
+		}
+		run.GrossPnL = run.GrossPnL.Add(gross)
+		run.Fees = run.Fees.Add(fees)
+		run.NetPnL = run.NetPnL.Add(net)
+		equity = equity.Add(net)
+		peak = decimal.Max(peak, equity)
+		run.MaxDrawdown = decimal.Max(run.MaxDrawdown, peak.Sub(equity))
+	}
+	if run.Trades > 0 {
+		run.WinRate = float64(run.Wins) / float64(run.Trades)
+	}
+	return run, nil
+}
+
+// fill simulates a market order placed at candle index and filled after latency.
+func (s *StressTestService) fill(ctx context.Context, simulator *PaperExecutionSimulator, data *indicators.OHLCVData, index int, side PaperOrderSide, size decimal.Decimal, latency time.Duration) (*PaperOrder, error) {
+	order, err := simulator.CreateOrder(PaperOrderRequest{
+		UserID:   "stress-test",
+		Exchange: data.Exchange,
+		Symbol:   data.Symbol,
+		Type:     PaperOrderTypeMarket,
+		Side:     side,
+		Size:     size,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create paper order: %w", err)
+	}
+	order, err = simulator.SimulateFill(ctx, order, priceAfter(data, index, latency))
+	if err != nil {
+		return nil, fmt.Errorf("simulate fill: %w", err)
+	}
+	return order, nil
+}
+
+// indicatorSignal takes the indicator stack's overall signal as the entry.
+func (s *StressTestService) indicatorSignal(ctx context.Context, data *indicators.OHLCVData) (PaperOrderSide, bool, error) {
+	analysis, err := s.stack.Analyze(ctx, data)
+	if err != nil {
+		return "", false, err
+	}
+	switch analysis.OverallSignal {
+	case indicators.SignalBuy:
+		return PaperOrderSideBuy, true, nil
+	case indicators.SignalSell:
+		return PaperOrderSideSell, true, nil
+	default:
+		return "", false, nil
+	}
+}
+
+// priceAfter returns the price latency after the close of candle index,
+// interpolated between closes; it stops at the last candle.
+func priceAfter(data *indicators.OHLCVData, index int, latency time.Duration) decimal.Decimal {
+	last := data.Length() - 1
+	if latency <= 0 || index >= last {
+		return data.Close[index]
+	}
+	interval := data.Timestamps[index+1].Sub(data.Timestamps[index])
+	if interval <= 0 {
+		return data.Close[index]
+	}
+	steps := int(latency / interval)
+	if index+steps >= last {
+		return data.Close[last]
+	}
+	from, to := data.Close[index+steps], data.Close[index+steps+1]
+	fraction := decimal.NewFromFloat(float64(latency%interval) / float64(interval))
+	return from.Add(to.Sub(from).Mul(fraction))
+}
+
+// windowOHLCV returns candles [from, to) of data.
+func windowOHLCV(data *indicators.OHLCVData, from, to int) *indicators.OHLCVData {
+	return &indicators.OHLCVData{
+		Symbol:     data.Symbol,
+		Exchange:   data.Exchange,
+		Timeframe:  data.Timeframe,
+		Timestamps: data.Timestamps[from:to],
+		Open:       data.Open[from:to],
+		High:       data.High[from:to],
+		Low:        data.Low[from:to],
+		Close:      data.Close[from:to],
+		Volume:     data.Volume[from:to],
+	}
+}
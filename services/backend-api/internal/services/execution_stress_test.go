@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/pkg/indicators"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rampCandles returns 5m candles whose close rises by 1 every candle.
+type rampCandles struct{ count int }
+
+func (f rampCandles) FetchOHLCV(_ context.Context, exchange, symbol, timeframe string, limit int) (*ccxt.OHLCVResponse, error) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	response := &ccxt.OHLCVResponse{Exchange: exchange, Symbol: symbol, Timeframe: timeframe}
+	for i := 0; i < f.count && i < limit; i++ {
+		price := decimal.NewFromInt(int64(1000 + i))
+		response.OHLCV = append(response.OHLCV, ccxt.OHLCV{
+			Timestamp: start.Add(time.Duration(i) * 5 * time.Minute),
+			Open:      price, High: price, Low: price, Close: price,
+			Volume: decimal.NewFromInt(1),
+		})
+	}
+	return response, nil
+}
+
+func newTestStressTestService(count int) *StressTestService {
+	s := NewStressTestService(rampCandles{count: count}, StressTestConfig{
+		Candles:     count,
+		Window:      50,
+		HoldCandles: 10,
+		FeeRate:     decimal.NewFromFloat(0.001),
+		Slippage:    decimal.NewFromFloat(0.0001),
+	})
+	s.signal = func(context.Context, *indicators.OHLCVData) (PaperOrderSide, bool, error) {
+		return PaperOrderSideBuy, true, nil
+	}
+	return s
+}
+
+func TestStressTestService_ComparesScenariosWithBaseline(t *testing.T) {
+	s := newTestStressTestService(200)
+
+	report, err := s.Run(t.Context(), StressTestQuery{Scenarios: []StressScenario{
+		{Name: "partial_fills", FillRatio: 0.5},
+		{Name: "high_fees", FeeMultiplier: 3},
+		{Name: "ruinous_fees", FeeMultiplier: 10},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, 200, report.Candles)
+	// Entries every 11 candles from candle 49 while an exit candle remains
+	assert.Equal(t, 13, report.Signals)
+	assert.Equal(t, 13, report.Baseline.Trades)
+	assert.True(t, report.Baseline.NetPnL.IsPositive())
+	assert.True(t, report.Baseline.Fees.IsPositive())
+
+	require.Len(t, report.Scenarios, 3)
+	partial := report.Scenarios[0]
+	assert.InDelta(t, report.Baseline.NetPnL.InexactFloat64()/2, partial.NetPnL.InexactFloat64(), 1e-9, "half fills halve the PnL")
+	require.NotNil(t, partial.Retained)
+	assert.InDelta(t, 0.5, *partial.Retained, 1e-9)
+
+	fees := report.Scenarios[1]
+	assert.True(t, fees.GrossPnL.Equal(report.Baseline.GrossPnL))
+	assert.InDelta(t, 3*report.Baseline.Fees.InexactFloat64(), fees.Fees.InexactFloat64(), 1e-9)
+	assert.True(t, fees.PnLChange.IsNegative())
+
+	assert.True(t, report.Scenarios[2].NetPnL.IsNegative())
+	assert.False(t, report.Robust, "a losing scenario makes the strategy fragile")
+}
+
+func TestStressTestService_DefaultScenariosAndValidation(t *testing.T) {
+	s := newTestStressTestService(200)
+
+	report, err := s.Run(t.Context(), StressTestQuery{})
+	require.NoError(t, err)
+	require.Len(t, report.Scenarios, len(DefaultStressScenarios()))
+	assert.Equal(t, "latency", report.Scenarios[0].Scenario.Name)
+
+	_, err = s.Run(t.Context(), StressTestQuery{Symbol: "BTCUSDT"})
+	assert.ErrorIs(t, err, ErrStressTestInvalidQuery)
+	_, err = s.Run(t.Context(), StressTestQuery{Scenarios: []StressScenario{{FillRatio: 1.5}}})
+	assert.ErrorIs(t, err, ErrStressTestInvalidQuery)
+
+	_, err = newTestStressTestService(40).Run(t.Context(), StressTestQuery{})
+	assert.Error(t, err, "too few candles for the indicator window")
+}
+
+func TestPriceAfter_InterpolatesBetweenCloses(t *testing.T) {
+	response, err := rampCandles{count: 5}.FetchOHLCV(t.Context(), "binance", "BTC/USDT", "5m", 5)
+	require.NoError(t, err)
+	data := &indicators.OHLCVData{}
+	for _, candle := range response.OHLCV {
+		data.Timestamps = append(data.Timestamps, candle.Timestamp)
+		data.Close = append(data.Close, candle.Close)
+	}
+
+	assert.Equal(t, "1001", priceAfter(data, 1, 0).String())
+	assert.Equal(t, "1001.5", priceAfter(data, 1, 150*time.Second).String())
+	assert.Equal(t, "1003.2", priceAfter(data, 1, 11*time.Minute).String())
+	assert.Equal(t, "1004", priceAfter(data, 1, time.Hour).String(), "latency past the data fills at the last close")
+}
@@ -0,0 +1,490 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/irfndi/neuratrade/internal/services/eventbus"
+	"github.com/shopspring/decimal"
+)
+
+// ExternalSignalStrategy is the strategy name under which externally sourced
+// signals are executed. Its risk caps are independent of the autonomous strategies.
+const ExternalSignalStrategy = "external-signal"
+
+var (
+	// ErrExternalSignalUnauthorized is returned when the shared secret does not match.
+	ErrExternalSignalUnauthorized = errors.New("invalid webhook secret")
+	// ErrExternalSignalDisabled is returned when no shared secret is configured.
+	ErrExternalSignalDisabled = errors.New("external signal ingestion is not configured")
+	// ErrExternalSignalInvalid is returned when an alert cannot be converted into a signal.
+	ErrExternalSignalInvalid = errors.New("invalid external signal")
+)
+
+// TradingViewAlert is the JSON body of a TradingView webhook alert. TradingView
+// cannot set custom headers, so the shared secret travels in the body.
+type TradingViewAlert struct {
+	Secret     string          `json:"secret"`
+	Passphrase string          `json:"passphrase"`
+	Ticker     string          `json:"ticker"`
+	Exchange   string          `json:"exchange"`
+	Action     string          `json:"action"`
+	Price      decimal.Decimal `json:"price"`
+	Quantity   decimal.Decimal `json:"quantity"`
+	Confidence *float64        `json:"confidence,omitempty"`
+	Interval   string          `json:"interval,omitempty"`
+	Strategy   string          `json:"strategy,omitempty"`
+	Message    string          `json:"message,omitempty"`
+	Time       string          `json:"time,omitempty"`
+}
+
+// ExternalSignalSink receives converted external signals, normally the signal processor.
+type ExternalSignalSink interface {
+	ProcessExternalSignal(ctx context.Context, signal *AggregatedSignal) ProcessingResult
+}
+
+// ExternalOrder is an order placed on behalf of an external signal.
+type ExternalOrder struct {
+	Exchange string          `json:"exchange"`
+	Symbol   string          `json:"symbol"`
+	Side     string          `json:"side"`
+	Amount   decimal.Decimal `json:"amount"`
+	Price    decimal.Decimal `json:"price"`
+	Strategy string          `json:"strategy"`
+	SignalID string          `json:"signal_id"`
+}
+
+// ExternalOrderPlacer opens positions for executed external signals.
+type ExternalOrderPlacer interface {
+	PlaceExternalOrder(ctx context.Context, order ExternalOrder) (string, error)
+}
+
+// KillSwitchChecker reports whether trading has been halted.
+type KillSwitchChecker interface {
+	KillSwitchEngaged(ctx context.Context) bool
+}
+
+// ExternalSignalConfig configures ingestion and the external-signal strategy's risk caps.
+type ExternalSignalConfig struct {
+	// Secret is the shared secret alerts must present. Ingestion is disabled when empty.
+	Secret string
+	// DefaultExchange is used when an alert does not name an exchange.
+	DefaultExchange string
+	// DefaultConfidence is used when an alert does not carry a confidence.
+	DefaultConfidence float64
+	// SignalTTL is how long a converted signal stays valid.
+	SignalTTL time.Duration
+
+	// AutoExecute places orders for buy/sell alerts that pass the risk caps.
+	AutoExecute bool
+	// MinConfidence is the lowest confidence that is executed.
+	MinConfidence float64
+	// MaxOrderAmount caps the quantity of a single order (zero disables the cap).
+	MaxOrderAmount decimal.Decimal
+	// MaxOrderNotional caps quantity * price of a single order (zero disables the cap).
+	MaxOrderNotional decimal.Decimal
+	// MaxDailyOrders caps executed orders per UTC day (zero disables the cap).
+	MaxDailyOrders int
+	// AllowedSymbols restricts execution to these symbols when not empty.
+	AllowedSymbols []string
+}
+
+// ExternalSignalResult reports what happened to an ingested alert.
+type ExternalSignalResult struct {
+	Signal        *AggregatedSignal `json:"signal"`
+	QualityScore  float64           `json:"quality_score"`
+	Processed     bool              `json:"processed"`
+	Executed      bool              `json:"executed"`
+	OrderID       string            `json:"order_id,omitempty"`
+	Strategy      string            `json:"strategy,omitempty"`
+	SkippedReason string            `json:"skipped_reason,omitempty"`
+	DecisionID    string            `json:"decision_id,omitempty"`
+}
+
+// ExternalSignalService validates, converts and optionally executes external alerts.
+type ExternalSignalService struct {
+	config    ExternalSignalConfig
+	sink      ExternalSignalSink
+	placer    ExternalOrderPlacer
+	killCheck KillSwitchChecker
+	stable    StablecoinGuard
+	exchanges ExchangeGuard
+	bus       eventbus.Publisher
+	decisions DecisionRecorder
+	now       func() time.Time
+
+	mu          sync.Mutex
+	dailyDate   string
+	dailyOrders int
+}
+
+// NewExternalSignalService creates a new external signal service.
+//
+// Parameters:
+//   - config: Ingestion and risk cap configuration.
+//   - sink: Receives converted signals (optional).
+//   - placer: Places orders when AutoExecute is set (optional).
+//
+// Returns:
+//   - *ExternalSignalService: The initialized service.
+func NewExternalSignalService(config ExternalSignalConfig, sink ExternalSignalSink, placer ExternalOrderPlacer) *ExternalSignalService {
+	if config.DefaultExchange == "" {
+		config.DefaultExchange = "binance"
+	}
+	if config.DefaultConfidence <= 0 {
+		config.DefaultConfidence = 0.75
+	}
+	if config.SignalTTL <= 0 {
+		config.SignalTTL = time.Hour
+	}
+	return &ExternalSignalService{
+		config: config,
+		sink:   sink,
+		placer: placer,
+		now:    time.Now,
+	}
+}
+
+// SetKillSwitch makes execution respect the trading kill switch.
+func (s *ExternalSignalService) SetKillSwitch(checker KillSwitchChecker) {
+	s.killCheck = checker
+}
+
+// SetStablecoinGuard blocks execution on symbols that trade against a depegged stablecoin.
+func (s *ExternalSignalService) SetStablecoinGuard(guard StablecoinGuard) {
+	s.stable = guard
+}
+
+// SetExchangeGuard blocks execution on exchanges paused for degraded health.
+func (s *ExternalSignalService) SetExchangeGuard(guard ExchangeGuard) {
+	s.exchanges = guard
+}
+
+// SetEventBus publishes executed decisions on eventbus.SubjectDecision.
+func (s *ExternalSignalService) SetEventBus(bus eventbus.Publisher) {
+	s.bus = bus
+}
+
+// SetDecisionRecorder stores every ingested alert with the resulting order for
+// later replay.
+func (s *ExternalSignalService) SetDecisionRecorder(recorder DecisionRecorder) {
+	s.decisions = recorder
+}
+
+// Enabled reports whether a shared secret has been configured.
+func (s *ExternalSignalService) Enabled() bool {
+	return s.config.Secret != ""
+}
+
+// VerifySecret compares a presented secret with the configured one in constant time.
+//
+// Parameters:
+//   - provided: The secret presented by the caller.
+//
+// Returns:
+//   - error: ErrExternalSignalDisabled or ErrExternalSignalUnauthorized on failure.
+func (s *ExternalSignalService) VerifySecret(provided string) error {
+	if !s.Enabled() {
+		return ErrExternalSignalDisabled
+	}
+	if subtle.ConstantTimeCompare([]byte(provided), []byte(s.config.Secret)) != 1 {
+		return ErrExternalSignalUnauthorized
+	}
+	return nil
+}
+
+// IngestTradingView converts a TradingView alert into a signal, feeds it to the
+// sink and, when enabled and within the risk caps, executes it.
+//
+// Parameters:
+//   - ctx: Request context.
+//   - alert: The parsed alert; its secret must already have been verified.
+//
+// Returns:
+//   - *ExternalSignalResult: The outcome.
+//   - error: ErrExternalSignalInvalid if the alert cannot be converted.
+func (s *ExternalSignalService) IngestTradingView(ctx context.Context, alert TradingViewAlert) (*ExternalSignalResult, error) {
+	signal, err := s.ConvertTradingViewAlert(alert)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ExternalSignalResult{
+		Signal:       signal,
+		QualityScore: signal.Confidence.InexactFloat64(),
+		Processed:    true,
+	}
+	if s.sink != nil {
+		processed := s.sink.ProcessExternalSignal(ctx, signal)
+		result.QualityScore = processed.QualityScore
+		result.Processed = processed.Processed
+	}
+
+	if !s.config.AutoExecute || s.placer == nil {
+		result.SkippedReason = "auto execution disabled"
+		s.recordDecision(ctx, alert, result, nil)
+		return result, nil
+	}
+	if reason := s.checkRiskCaps(ctx, signal, alert); reason != "" {
+		result.SkippedReason = reason
+		s.recordDecision(ctx, alert, result, nil)
+		return result, nil
+	}
+
+	orderID, err := s.placer.PlaceExternalOrder(ctx, ExternalOrder{
+		Exchange: signal.Exchanges[0],
+		Symbol:   signal.Symbol,
+		Side:     strings.ToUpper(signal.Action),
+		Amount:   alert.Quantity,
+		Price:    alert.Price,
+		Strategy: ExternalSignalStrategy,
+		SignalID: signal.ID,
+	})
+	if err != nil {
+		s.releaseDailySlot()
+		result.SkippedReason = fmt.Sprintf("order placement failed: %v", err)
+		s.recordDecision(ctx, alert, result, err)
+		return result, nil
+	}
+
+	result.Executed = true
+	result.OrderID = orderID
+	result.Strategy = ExternalSignalStrategy
+	s.recordDecision(ctx, alert, result, nil)
+	publishEvent(ctx, s.bus, eventbus.SubjectDecision, result)
+	return result, nil
+}
+
+// recordDecision stores an ingested alert with the order it led to and links
+// the result to the audit record. Alert credentials are not stored.
+func (s *ExternalSignalService) recordDecision(ctx context.Context, alert TradingViewAlert, result *ExternalSignalResult, orderErr error) {
+	if s.decisions == nil {
+		return
+	}
+	signal := result.Signal
+	snapshot := alert
+	snapshot.Secret, snapshot.Passphrase = "", ""
+	reasoning := alert.Message
+	if reasoning == "" {
+		reasoning = fmt.Sprintf("TradingView alert %s %s", alert.Action, alert.Ticker)
+		if alert.Strategy != "" {
+			reasoning += " from strategy " + alert.Strategy
+		}
+	}
+
+	audit := &DecisionAudit{
+		Source:     DecisionSourceExternalSignal,
+		Symbol:     signal.Symbol,
+		Action:     signal.Action,
+		Confidence: signal.Confidence.InexactFloat64(),
+		Reasoning:  reasoning,
+		Price:      alert.Price.InexactFloat64(),
+		Order: &DecisionAuditOrder{
+			Status:  DecisionOrderSkipped,
+			OrderID: result.OrderID,
+			Side:    strings.ToUpper(signal.Action),
+			Amount:  alert.Quantity,
+			Price:   alert.Price,
+			Error:   result.SkippedReason,
+		},
+	}
+	if len(signal.Exchanges) > 0 {
+		audit.Exchange = signal.Exchanges[0]
+	}
+	switch {
+	case result.Executed:
+		audit.Order.Status = DecisionOrderPlaced
+	case orderErr != nil:
+		audit.Order.Status = DecisionOrderFailed
+		audit.Order.Error = orderErr.Error()
+	}
+	audit.MarketSnapshot, _ = json.Marshal(snapshot)
+	audit.Decision, _ = json.Marshal(signal)
+
+	if err := s.decisions.Record(ctx, audit); err != nil {
+		log.Printf("[EXTERNAL-SIGNAL] Failed to record decision audit: %v", err)
+		return
+	}
+	result.DecisionID = audit.ID
+}
+
+// ConvertTradingViewAlert maps an alert onto an AggregatedSignal.
+//
+// Parameters:
+//   - alert: The alert to convert.
+//
+// Returns:
+//   - *AggregatedSignal: The converted signal.
+//   - error: ErrExternalSignalInvalid when the ticker or action is missing or unknown.
+func (s *ExternalSignalService) ConvertTradingViewAlert(alert TradingViewAlert) (*AggregatedSignal, error) {
+	symbol := NormalizeTradingViewTicker(alert.Ticker)
+	if symbol == "" {
+		return nil, fmt.Errorf("%w: ticker is required", ErrExternalSignalInvalid)
+	}
+
+	action := strings.ToLower(strings.TrimSpace(alert.Action))
+	switch action {
+	case "buy", "long":
+		action = "buy"
+	case "sell", "short":
+		action = "sell"
+	case "hold", "close", "flat":
+		action = "hold"
+	default:
+		return nil, fmt.Errorf("%w: unsupported action %q", ErrExternalSignalInvalid, alert.Action)
+	}
+
+	confidence := s.config.DefaultConfidence
+	if alert.Confidence != nil {
+		confidence = *alert.Confidence
+		if confidence > 1 {
+			confidence /= 100
+		}
+		if confidence < 0 || confidence > 1 {
+			return nil, fmt.Errorf("%w: confidence must be between 0 and 1", ErrExternalSignalInvalid)
+		}
+	}
+
+	exchange := strings.ToLower(strings.TrimSpace(alert.Exchange))
+	if exchange == "" {
+		exchange = s.config.DefaultExchange
+	}
+
+	now := s.now().UTC()
+	strength := SignalStrengthWeak
+	switch {
+	case confidence >= 0.8:
+		strength = SignalStrengthStrong
+	case confidence >= 0.6:
+		strength = SignalStrengthMedium
+	}
+
+	return &AggregatedSignal{
+		ID:              uuid.NewString(),
+		SignalType:      SignalTypeExternal,
+		Symbol:          symbol,
+		Action:          action,
+		Strength:        strength,
+		Confidence:      decimal.NewFromFloat(confidence),
+		ProfitPotential: decimal.Zero,
+		RiskLevel:       decimal.NewFromFloat(1 - confidence),
+		Exchanges:       []string{exchange},
+		Indicators:      []string{"tradingview"},
+		Metadata: map[string]interface{}{
+			"source":   "tradingview",
+			"strategy": alert.Strategy,
+			"interval": alert.Interval,
+			"message":  alert.Message,
+			"price":    alert.Price.String(),
+			"time":     alert.Time,
+		},
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.config.SignalTTL),
+	}, nil
+}
+
+// checkRiskCaps returns a reason when the signal must not be executed. A passing
+// check reserves one of the day's order slots.
+func (s *ExternalSignalService) checkRiskCaps(ctx context.Context, signal *AggregatedSignal, alert TradingViewAlert) string {
+	if signal.Action == "hold" {
+		return "hold signals are not executed"
+	}
+	if s.killCheck != nil && s.killCheck.KillSwitchEngaged(ctx) {
+		return "kill switch engaged"
+	}
+	if s.stable != nil {
+		if stable, depegged := s.stable.DepeggedStablecoin(ctx, signal.Symbol); depegged {
+			return stable + " is depegged"
+		}
+	}
+	if s.exchanges != nil && len(signal.Exchanges) > 0 && s.exchanges.ExchangePaused(ctx, signal.Exchanges[0]) {
+		return signal.Exchanges[0] + " is paused while its health is degraded"
+	}
+	if signal.Confidence.LessThan(decimal.NewFromFloat(s.config.MinConfidence)) {
+		return "confidence below minimum"
+	}
+	if alert.Quantity.LessThanOrEqual(decimal.Zero) {
+		return "quantity is required for execution"
+	}
+	if len(s.config.AllowedSymbols) > 0 && !containsSymbol(s.config.AllowedSymbols, signal.Symbol) {
+		return "symbol not allowed for " + ExternalSignalStrategy
+	}
+	if s.config.MaxOrderAmount.IsPositive() && alert.Quantity.GreaterThan(s.config.MaxOrderAmount) {
+		return "quantity exceeds max order amount"
+	}
+	if s.config.MaxOrderNotional.IsPositive() {
+		if alert.Price.LessThanOrEqual(decimal.Zero) {
+			return "price is required to check max order notional"
+		}
+		if alert.Quantity.Mul(alert.Price).GreaterThan(s.config.MaxOrderNotional) {
+			return "order notional exceeds max order notional"
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	today := s.now().UTC().Format("2006-01-02")
+	if s.dailyDate != today {
+		s.dailyDate = today
+		s.dailyOrders = 0
+	}
+	if s.config.MaxDailyOrders > 0 && s.dailyOrders >= s.config.MaxDailyOrders {
+		return "daily order limit reached"
+	}
+	s.dailyOrders++
+	return ""
+}
+
+func (s *ExternalSignalService) releaseDailySlot() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.dailyOrders > 0 {
+		s.dailyOrders--
+	}
+}
+
+func containsSymbol(symbols []string, symbol string) bool {
+	for _, candidate := range symbols {
+		if NormalizeTradingViewTicker(candidate) == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// tradingViewQuotes are quote currencies recognised when splitting tickers
+// such as BTCUSDT, longest first.
+var tradingViewQuotes = []string{"USDT", "USDC", "BUSD", "USD", "EUR", "BTC", "ETH"}
+
+// NormalizeTradingViewTicker converts TradingView tickers ("BINANCE:BTCUSDT",
+// "BTCUSDT.P", "btc/usdt") to the BASE/QUOTE form used across the platform.
+//
+// Parameters:
+//   - ticker: The TradingView ticker.
+//
+// Returns:
+//   - string: The normalized symbol, or "" when ticker is empty.
+func NormalizeTradingViewTicker(ticker string) string {
+	ticker = strings.ToUpper(strings.TrimSpace(ticker))
+	if i := strings.LastIndex(ticker, ":"); i >= 0 {
+		ticker = ticker[i+1:]
+	}
+	ticker = strings.TrimSuffix(ticker, ".P")
+	if ticker == "" || strings.Contains(ticker, "/") {
+		return ticker
+	}
+	for _, quote := range tradingViewQuotes {
+		if base := strings.TrimSuffix(ticker, quote); base != ticker && base != "" {
+			return base + "/" + quote
+		}
+	}
+	return ticker
+}
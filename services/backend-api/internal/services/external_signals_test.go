@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingSink struct {
+	signals []*AggregatedSignal
+}
+
+func (r *recordingSink) ProcessExternalSignal(_ context.Context, signal *AggregatedSignal) ProcessingResult {
+	r.signals = append(r.signals, signal)
+	return ProcessingResult{Processed: true, QualityScore: 0.9}
+}
+
+type recordingPlacer struct {
+	orders []ExternalOrder
+	err    error
+}
+
+func (r *recordingPlacer) PlaceExternalOrder(_ context.Context, order ExternalOrder) (string, error) {
+	if r.err != nil {
+		return "", r.err
+	}
+	r.orders = append(r.orders, order)
+	return "ord-1", nil
+}
+
+type fixedKillSwitch bool
+
+func (k fixedKillSwitch) KillSwitchEngaged(context.Context) bool { return bool(k) }
+
+type fixedDepeg string
+
+func (d fixedDepeg) DepeggedStablecoin(_ context.Context, symbol string) (string, bool) {
+	return string(d), strings.HasSuffix(symbol, "/"+string(d))
+}
+
+type fixedPausedExchange string
+
+func (e fixedPausedExchange) ExchangePaused(_ context.Context, exchange string) bool {
+	return exchange == string(e)
+}
+
+func TestNormalizeTradingViewTicker(t *testing.T) {
+	tests := map[string]string{
+		"BINANCE:BTCUSDT": "BTC/USDT",
+		"ethusdc":         "ETH/USDC",
+		"SOLUSDT.P":       "SOL/USDT",
+		"BTC/USDT":        "BTC/USDT",
+		"BTCUSD":          "BTC/USD",
+		"AAPL":            "AAPL",
+		"":                "",
+	}
+	for ticker, want := range tests {
+		assert.Equal(t, want, NormalizeTradingViewTicker(ticker), ticker)
+	}
+}
+
+func TestExternalSignalService_VerifySecret(t *testing.T) {
+	assert.ErrorIs(t, NewExternalSignalService(ExternalSignalConfig{}, nil, nil).VerifySecret("x"), ErrExternalSignalDisabled)
+
+	svc := NewExternalSignalService(ExternalSignalConfig{Secret: "tv-secret"}, nil, nil)
+	assert.NoError(t, svc.VerifySecret("tv-secret"))
+	assert.ErrorIs(t, svc.VerifySecret("wrong"), ErrExternalSignalUnauthorized)
+	assert.ErrorIs(t, svc.VerifySecret(""), ErrExternalSignalUnauthorized)
+}
+
+func TestExternalSignalService_ConvertTradingViewAlert(t *testing.T) {
+	svc := NewExternalSignalService(ExternalSignalConfig{}, nil, nil)
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	confidence := 85.0
+	signal, err := svc.ConvertTradingViewAlert(TradingViewAlert{
+		Ticker:     "BINANCE:ETHUSDT",
+		Action:     "long",
+		Price:      decimal.NewFromInt(3000),
+		Confidence: &confidence,
+		Strategy:   "ema-cross",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, SignalTypeExternal, signal.SignalType)
+	assert.Equal(t, "ETH/USDT", signal.Symbol)
+	assert.Equal(t, "buy", signal.Action)
+	assert.Equal(t, SignalStrengthStrong, signal.Strength)
+	assert.True(t, signal.Confidence.Equal(decimal.NewFromFloat(0.85)))
+	assert.Equal(t, []string{"binance"}, signal.Exchanges)
+	assert.Equal(t, "ema-cross", signal.Metadata["strategy"])
+	assert.Equal(t, now.Add(time.Hour), signal.ExpiresAt)
+
+	_, err = svc.ConvertTradingViewAlert(TradingViewAlert{Action: "buy"})
+	assert.ErrorIs(t, err, ErrExternalSignalInvalid)
+	_, err = svc.ConvertTradingViewAlert(TradingViewAlert{Ticker: "BTCUSDT", Action: "moon"})
+	assert.ErrorIs(t, err, ErrExternalSignalInvalid)
+}
+
+func TestExternalSignalService_IngestWithoutExecution(t *testing.T) {
+	sink := &recordingSink{}
+	placer := &recordingPlacer{}
+	svc := NewExternalSignalService(ExternalSignalConfig{Secret: "s"}, sink, placer)
+
+	result, err := svc.IngestTradingView(t.Context(), TradingViewAlert{Ticker: "BTCUSDT", Action: "buy", Quantity: decimal.NewFromInt(1)})
+	require.NoError(t, err)
+	assert.Len(t, sink.signals, 1, "signals are fed to the processor")
+	assert.Equal(t, 0.9, result.QualityScore)
+	assert.False(t, result.Executed)
+	assert.Equal(t, "auto execution disabled", result.SkippedReason)
+	assert.Empty(t, placer.orders)
+}
+
+func TestExternalSignalService_RiskCaps(t *testing.T) {
+	config := ExternalSignalConfig{
+		Secret:           "s",
+		AutoExecute:      true,
+		MinConfidence:    0.6,
+		MaxOrderAmount:   decimal.NewFromInt(2),
+		MaxOrderNotional: decimal.NewFromInt(1000),
+		MaxDailyOrders:   1,
+		AllowedSymbols:   []string{"BTCUSDT"},
+	}
+	low := 0.5
+	tests := []struct {
+		name   string
+		alert  TradingViewAlert
+		reason string
+	}{
+		{"hold", TradingViewAlert{Ticker: "BTCUSDT", Action: "close", Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(100)}, "hold signals are not executed"},
+		{"confidence", TradingViewAlert{Ticker: "BTCUSDT", Action: "buy", Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(100), Confidence: &low}, "confidence below minimum"},
+		{"quantity", TradingViewAlert{Ticker: "BTCUSDT", Action: "buy", Price: decimal.NewFromInt(100)}, "quantity is required for execution"},
+		{"symbol", TradingViewAlert{Ticker: "ETHUSDT", Action: "buy", Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(100)}, "symbol not allowed for external-signal"},
+		{"amount", TradingViewAlert{Ticker: "BTCUSDT", Action: "buy", Quantity: decimal.NewFromInt(3), Price: decimal.NewFromInt(100)}, "quantity exceeds max order amount"},
+		{"notional", TradingViewAlert{Ticker: "BTCUSDT", Action: "buy", Quantity: decimal.NewFromInt(2), Price: decimal.NewFromInt(600)}, "order notional exceeds max order notional"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			placer := &recordingPlacer{}
+			svc := NewExternalSignalService(config, nil, placer)
+			result, err := svc.IngestTradingView(t.Context(), tt.alert)
+			require.NoError(t, err)
+			assert.False(t, result.Executed)
+			assert.Equal(t, tt.reason, result.SkippedReason)
+			assert.Empty(t, placer.orders)
+		})
+	}
+
+	t.Run("executes within caps and enforces the daily limit", func(t *testing.T) {
+		placer := &recordingPlacer{}
+		svc := NewExternalSignalService(config, nil, placer)
+		alert := TradingViewAlert{Ticker: "BTCUSDT", Action: "sell", Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(500)}
+
+		result, err := svc.IngestTradingView(t.Context(), alert)
+		require.NoError(t, err)
+		assert.True(t, result.Executed)
+		assert.Equal(t, "ord-1", result.OrderID)
+		assert.Equal(t, ExternalSignalStrategy, result.Strategy)
+		require.Len(t, placer.orders, 1)
+		assert.Equal(t, "SELL", placer.orders[0].Side)
+		assert.Equal(t, ExternalSignalStrategy, placer.orders[0].Strategy)
+
+		result, err = svc.IngestTradingView(t.Context(), alert)
+		require.NoError(t, err)
+		assert.Equal(t, "daily order limit reached", result.SkippedReason)
+	})
+
+	t.Run("failed placements do not use the daily slot", func(t *testing.T) {
+		placer := &recordingPlacer{err: errors.New("db down")}
+		svc := NewExternalSignalService(config, nil, placer)
+		alert := TradingViewAlert{Ticker: "BTCUSDT", Action: "buy", Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(500)}
+
+		result, err := svc.IngestTradingView(t.Context(), alert)
+		require.NoError(t, err)
+		assert.Contains(t, result.SkippedReason, "order placement failed")
+
+		placer.err = nil
+		result, err = svc.IngestTradingView(t.Context(), alert)
+		require.NoError(t, err)
+		assert.True(t, result.Executed)
+	})
+
+	t.Run("kill switch blocks execution", func(t *testing.T) {
+		placer := &recordingPlacer{}
+		svc := NewExternalSignalService(config, nil, placer)
+		svc.SetKillSwitch(fixedKillSwitch(true))
+		result, err := svc.IngestTradingView(t.Context(), TradingViewAlert{Ticker: "BTCUSDT", Action: "buy", Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(500)})
+		require.NoError(t, err)
+		assert.Equal(t, "kill switch engaged", result.SkippedReason)
+	})
+
+	t.Run("depegged stablecoin blocks execution", func(t *testing.T) {
+		placer := &recordingPlacer{}
+		svc := NewExternalSignalService(config, nil, placer)
+		svc.SetStablecoinGuard(fixedDepeg("USDT"))
+		result, err := svc.IngestTradingView(t.Context(), TradingViewAlert{Ticker: "BTCUSDT", Action: "buy", Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(500)})
+		require.NoError(t, err)
+		assert.Equal(t, "USDT is depegged", result.SkippedReason)
+		assert.Empty(t, placer.orders)
+	})
+
+	t.Run("paused exchange blocks execution", func(t *testing.T) {
+		placer := &recordingPlacer{}
+		svc := NewExternalSignalService(config, nil, placer)
+		svc.SetExchangeGuard(fixedPausedExchange("binance"))
+		result, err := svc.IngestTradingView(t.Context(), TradingViewAlert{Ticker: "BTCUSDT", Action: "buy", Quantity: decimal.NewFromInt(1), Price: decimal.NewFromInt(500)})
+		require.NoError(t, err)
+		assert.Equal(t, "binance is paused while its health is degraded", result.SkippedReason)
+		assert.Empty(t, placer.orders)
+	})
+}
+
+func TestSignalProcessor_ProcessExternalSignal(t *testing.T) {
+	sp := NewSignalProcessor(nil, nil, nil, nil, nil, nil, nil, nil)
+	signal := &AggregatedSignal{ID: "sig-1", SignalType: SignalTypeExternal, Symbol: "BTC/USDT", Confidence: decimal.NewFromFloat(0.8)}
+
+	result := sp.ProcessExternalSignal(t.Context(), signal)
+	assert.True(t, result.Processed)
+	assert.Equal(t, 0.8, result.QualityScore)
+	assert.False(t, result.NotificationSent)
+	assert.Equal(t, int64(1), sp.GetMetrics().TotalSignalsProcessed)
+	assert.Equal(t, int64(1), sp.GetMetrics().SuccessfulSignals)
+}
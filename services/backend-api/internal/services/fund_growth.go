@@ -0,0 +1,348 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/shopspring/decimal"
+)
+
+// FundGrowthDefinitionID is the quest definition of fund growth goals.
+const FundGrowthDefinitionID = "fund_growth"
+
+// Checkpoint keys of a fund_growth quest.
+const (
+	fundGrowthGoalKey       = "goal"
+	fundGrowthMilestonesKey = "milestones_reached"
+	fundGrowthSnapshotsKey  = "equity_snapshots"
+
+	// fundGrowthSnapshotLimit caps the equity history kept in the checkpoint.
+	fundGrowthSnapshotLimit = 48
+)
+
+// FundMilestonePercents are the progress levels that send a FundMilestoneNotification.
+var FundMilestonePercents = []int{25, 50, 75, 100}
+
+var ErrFundGrowthInvalidGoal = errors.New("invalid fund growth goal")
+
+// FundGrowthGoal is the monetary target of a fund_growth quest. It is stored
+// in the quest checkpoint so it survives restarts.
+type FundGrowthGoal struct {
+	// StartValue is the equity the goal is measured from. Zero until the
+	// first equity snapshot when it was not given at creation.
+	StartValue decimal.Decimal `json:"start_value"`
+	// TargetValue is the equity to reach. For percentage goals it is derived
+	// from StartValue once the start is known.
+	TargetValue decimal.Decimal `json:"target_value"`
+	// GrowthPercent is set for percentage goals, e.g. 5 for "grow 5%".
+	GrowthPercent decimal.Decimal `json:"growth_pct"`
+	// Deadline fails the quest when the target is not reached in time.
+	Deadline *time.Time `json:"deadline,omitempty"`
+}
+
+// NewFundGrowthGoal validates a goal given either an absolute target value or
+// a growth percentage, optionally within a period.
+//
+// Parameters:
+//
+//	startValue: Equity to measure from; zero uses the first equity snapshot.
+//	targetValue: Absolute equity target; zero for percentage goals.
+//	growthPercent: Growth in percent, e.g. 5; zero for absolute goals.
+//	period: Time allowed to reach the target; zero for no deadline.
+//	now: Creation time the deadline is counted from.
+//
+// Returns:
+//
+//	FundGrowthGoal: The goal.
+//	error: ErrFundGrowthInvalidGoal when the combination is invalid.
+func NewFundGrowthGoal(startValue, targetValue, growthPercent decimal.Decimal, period time.Duration, now time.Time) (FundGrowthGoal, error) {
+	switch {
+	case targetValue.IsPositive() == growthPercent.IsPositive():
+		return FundGrowthGoal{}, fmt.Errorf("%w: set exactly one of a positive target value or growth percentage", ErrFundGrowthInvalidGoal)
+	case startValue.IsNegative() || targetValue.IsNegative() || growthPercent.IsNegative() || period < 0:
+		return FundGrowthGoal{}, fmt.Errorf("%w: values must not be negative", ErrFundGrowthInvalidGoal)
+	case targetValue.IsPositive() && startValue.IsPositive() && targetValue.LessThanOrEqual(startValue):
+		return FundGrowthGoal{}, fmt.Errorf("%w: target value must exceed the start value", ErrFundGrowthInvalidGoal)
+	}
+
+	goal := FundGrowthGoal{StartValue: startValue, TargetValue: targetValue, GrowthPercent: growthPercent}
+	if period > 0 {
+		deadline := now.Add(period).UTC()
+		goal.Deadline = &deadline
+	}
+	goal.resolveTarget()
+	return goal, nil
+}
+
+// resolveTarget derives the target of a percentage goal from its start value.
+func (g *FundGrowthGoal) resolveTarget() {
+	if g.GrowthPercent.IsPositive() && g.StartValue.IsPositive() {
+		g.TargetValue = g.StartValue.Mul(decimal.NewFromInt(100).Add(g.GrowthPercent)).Div(decimal.NewFromInt(100)).Round(8)
+	}
+}
+
+// Progress returns how much of the growth from the start to the target the
+// equity covers, in percent between 0 and 100.
+func (g FundGrowthGoal) Progress(equity decimal.Decimal) decimal.Decimal {
+	if !g.StartValue.IsPositive() || !g.TargetValue.IsPositive() {
+		return decimal.Zero
+	}
+	span := g.TargetValue.Sub(g.StartValue)
+	if !span.IsPositive() {
+		return decimal.NewFromInt(100)
+	}
+	progress := equity.Sub(g.StartValue).Div(span).Mul(decimal.NewFromInt(100))
+	if progress.IsNegative() {
+		return decimal.Zero
+	}
+	if progress.GreaterThan(decimal.NewFromInt(100)) {
+		return decimal.NewFromInt(100)
+	}
+	return progress
+}
+
+// Describe returns a short human-readable form such as "grow 5% by Mon, 12 Jan".
+func (g FundGrowthGoal) Describe() string {
+	var description string
+	if g.GrowthPercent.IsPositive() {
+		description = fmt.Sprintf("grow %s%%", g.GrowthPercent.String())
+	} else {
+		description = fmt.Sprintf("reach %s USDT", g.TargetValue.StringFixed(2))
+	}
+	if g.Deadline != nil {
+		description += " by " + g.Deadline.Format("Mon, 02 Jan 15:04 UTC")
+	}
+	return description
+}
+
+// ParseGrowthPeriod parses a goal period: day, week or month, a number of
+// days such as "10d", or a Go duration such as "48h".
+func ParseGrowthPeriod(raw string) (time.Duration, error) {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	switch raw {
+	case "":
+		return 0, nil
+	case "day", "daily":
+		return 24 * time.Hour, nil
+	case "week", "weekly":
+		return 7 * 24 * time.Hour, nil
+	case "month", "monthly":
+		return 30 * 24 * time.Hour, nil
+	}
+	if days, ok := strings.CutSuffix(raw, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	}
+	period, err := time.ParseDuration(raw)
+	if err != nil || period <= 0 {
+		return 0, fmt.Errorf("%w: unknown period %q (use day, week, month, 10d or 48h)", ErrFundGrowthInvalidGoal, raw)
+	}
+	return period, nil
+}
+
+// CreateFundGrowthQuest creates an active fund_growth quest for a chat. Its
+// progress is the percentage of the goal reached, so TargetCount is 100.
+//
+// Parameters:
+//
+//	chatID: Telegram chat ID.
+//	goal: The monetary goal, see NewFundGrowthGoal.
+//
+// Returns:
+//
+//	*Quest: The created quest.
+//	error: Error if the definition is missing.
+func (e *QuestEngine) CreateFundGrowthQuest(chatID string, goal FundGrowthGoal) (*Quest, error) {
+	e.mu.Lock()
+	quest, err := e.createQuestInternal(FundGrowthDefinitionID, chatID)
+	if err != nil {
+		e.mu.Unlock()
+		return nil, err
+	}
+	chatIDInt, _ := strconv.ParseInt(chatID, 10, 64)
+	e.chatIDForQuest[quest.ID] = chatIDInt
+	quest.Status = QuestStatusActive
+	quest.Description = "Fund growth goal: " + goal.Describe()
+	setFundGrowthCheckpoint(quest, goal, nil, nil)
+	e.mu.Unlock()
+
+	if e.store != nil {
+		if err := e.store.SaveQuest(context.Background(), quest); err != nil {
+			log.Printf("Failed to persist quest %s: %v", quest.ID, err)
+		}
+	}
+	return quest, nil
+}
+
+// FundGrowthState is the decoded checkpoint of a fund_growth quest.
+type FundGrowthState struct {
+	Goal              FundGrowthGoal `json:"goal"`
+	MilestonesReached []int          `json:"milestones_reached"`
+	Snapshots         []EquityPoint  `json:"equity_snapshots"`
+}
+
+// FundGrowthStateFromQuest decodes a fund_growth quest checkpoint. The
+// checkpoint holds typed values in memory and generic JSON after a reload,
+// so both are decoded through JSON.
+func FundGrowthStateFromQuest(quest *Quest) (*FundGrowthState, error) {
+	if quest == nil || quest.Checkpoint == nil || quest.Checkpoint[fundGrowthGoalKey] == nil {
+		return nil, fmt.Errorf("%w: quest has no goal", ErrFundGrowthInvalidGoal)
+	}
+	raw, err := json.Marshal(map[string]interface{}{
+		fundGrowthGoalKey:       quest.Checkpoint[fundGrowthGoalKey],
+		fundGrowthMilestonesKey: quest.Checkpoint[fundGrowthMilestonesKey],
+		fundGrowthSnapshotsKey:  quest.Checkpoint[fundGrowthSnapshotsKey],
+	})
+	if err != nil {
+		return nil, err
+	}
+	var state FundGrowthState
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode fund growth checkpoint: %w", err)
+	}
+	return &state, nil
+}
+
+func setFundGrowthCheckpoint(quest *Quest, goal FundGrowthGoal, reached []int, snapshots []EquityPoint) {
+	if quest.Checkpoint == nil {
+		quest.Checkpoint = make(map[string]interface{})
+	}
+	if reached == nil {
+		reached = []int{}
+	}
+	if snapshots == nil {
+		snapshots = []EquityPoint{}
+	}
+	quest.Checkpoint[fundGrowthGoalKey] = goal
+	quest.Checkpoint[fundGrowthMilestonesKey] = reached
+	quest.Checkpoint[fundGrowthSnapshotsKey] = snapshots
+	quest.Checkpoint["start_value"] = goal.StartValue.String()
+	quest.Checkpoint["target_value"] = goal.TargetValue.String()
+}
+
+// handleFundGrowth takes an equity snapshot, updates the goal's progress and
+// sends a FundMilestoneNotification for the highest newly reached milestone.
+// The quest completes at 100% and fails once its deadline passes.
+func (h *IntegratedQuestHandlers) handleFundGrowth(ctx context.Context, quest *Quest) error {
+	state, err := FundGrowthStateFromQuest(quest)
+	if err != nil {
+		return err
+	}
+	equity, err := h.fundGrowthEquity(ctx, quest)
+	if err != nil {
+		// A missing snapshot is retried on the next run rather than failing the goal
+		log.Printf("[FUND-GROWTH] Failed to take equity snapshot: %v", err)
+		quest.Checkpoint["status"] = "equity_unavailable"
+		return nil
+	}
+
+	now := time.Now().UTC()
+	goal := state.Goal
+	if !goal.StartValue.IsPositive() {
+		goal.StartValue = equity
+		goal.resolveTarget()
+	}
+
+	snapshots := append(state.Snapshots, EquityPoint{Timestamp: now, Equity: equity})
+	if len(snapshots) > fundGrowthSnapshotLimit {
+		snapshots = snapshots[len(snapshots)-fundGrowthSnapshotLimit:]
+	}
+
+	progress := goal.Progress(equity)
+	percent := int(progress.IntPart())
+	reached := state.MilestonesReached
+	newest := 0
+	for _, milestone := range FundMilestonePercents {
+		if percent >= milestone && !containsInt(reached, milestone) {
+			reached = append(reached, milestone)
+			newest = milestone
+		}
+	}
+
+	setFundGrowthCheckpoint(quest, goal, reached, snapshots)
+	quest.Checkpoint["current_value"] = equity.String()
+	quest.Checkpoint["progress_percent"] = progress.StringFixed(2)
+	quest.Checkpoint["last_snapshot_at"] = now.Format(time.RFC3339)
+	quest.Checkpoint["status"] = "tracking"
+	quest.CurrentCount = percent
+
+	if newest > 0 {
+		h.notifyFundMilestone(ctx, quest, goal, equity, newest)
+	}
+	if percent >= 100 {
+		quest.Checkpoint["status"] = "target_reached"
+		return nil
+	}
+	if goal.Deadline != nil && now.After(*goal.Deadline) {
+		quest.Checkpoint["status"] = "deadline_missed"
+		return fmt.Errorf("fund growth goal %q missed its deadline at %.2f%% progress", goal.Describe(), progress.InexactFloat64())
+	}
+	return nil
+}
+
+func (h *IntegratedQuestHandlers) notifyFundMilestone(ctx context.Context, quest *Quest, goal FundGrowthGoal, equity decimal.Decimal, milestone int) {
+	chatID, err := strconv.ParseInt(quest.Metadata["chat_id"], 10, 64)
+	if h.notificationService == nil || err != nil {
+		return
+	}
+	achievement := fmt.Sprintf("%d%% of the way to %s", milestone, goal.Describe())
+	if milestone == 100 {
+		achievement = "Goal reached: " + goal.Describe()
+	}
+	notification := FundMilestoneNotification{
+		MilestoneType:  fmt.Sprintf("fund_growth_%d", milestone),
+		CurrentValue:   equity.StringFixed(2) + " USDT",
+		TargetValue:    goal.TargetValue.StringFixed(2) + " USDT",
+		PercentReached: milestone,
+		Achievement:    achievement,
+	}
+	if err := h.notificationService.NotifyFundMilestone(ctx, chatID, notification); err != nil {
+		log.Printf("[FUND-GROWTH] Failed to send milestone notification: %v", err)
+	}
+}
+
+// fundGrowthEquity values the account with the equity source, falling back
+// to the USDT balance on the trading exchange.
+func (h *IntegratedQuestHandlers) fundGrowthEquity(ctx context.Context, quest *Quest) (decimal.Decimal, error) {
+	if h.equity != nil {
+		return h.equity.Equity(ctx, quest.Metadata["chat_id"])
+	}
+	return h.fetchUSDTEquity(ctx)
+}
+
+// fetchUSDTEquity returns the total USDT balance on the trading exchange.
+func (h *IntegratedQuestHandlers) fetchUSDTEquity(ctx context.Context) (decimal.Decimal, error) {
+	balanceFetcher, ok := h.ccxtService.(interface {
+		FetchBalance(ctx context.Context, exchange string) (*ccxt.BalanceResponse, error)
+	})
+	if !ok {
+		return decimal.Zero, fmt.Errorf("CCXT service does not implement FetchBalance")
+	}
+	balanceCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	balance, err := balanceFetcher.FetchBalance(balanceCtx, "binance")
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if balance == nil || balance.Total == nil {
+		return decimal.Zero, fmt.Errorf("balance response has no totals")
+	}
+	return decimal.NewFromFloat(balance.Total["USDT"]), nil
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFundGrowthGoal(t *testing.T) {
+	now := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	goal, err := NewFundGrowthGoal(decimal.NewFromInt(1000), decimal.Zero, decimal.NewFromInt(5), 7*24*time.Hour, now)
+	require.NoError(t, err)
+	assert.True(t, goal.TargetValue.Equal(decimal.NewFromInt(1050)), goal.TargetValue.String())
+	require.NotNil(t, goal.Deadline)
+	assert.Equal(t, now.Add(7*24*time.Hour), *goal.Deadline)
+	assert.Contains(t, goal.Describe(), "grow 5%")
+
+	// Percentage goals without a start value wait for the first snapshot
+	goal, err = NewFundGrowthGoal(decimal.Zero, decimal.Zero, decimal.NewFromInt(5), 0, now)
+	require.NoError(t, err)
+	assert.True(t, goal.TargetValue.IsZero())
+	assert.Nil(t, goal.Deadline)
+
+	for name, args := range map[string][3]int64{
+		"none":          {0, 0, 0},
+		"both":          {0, 1500, 5},
+		"below start":   {1000, 900, 0},
+		"negative goal": {0, 0, -5},
+	} {
+		_, err := NewFundGrowthGoal(decimal.NewFromInt(args[0]), decimal.NewFromInt(args[1]), decimal.NewFromInt(args[2]), 0, now)
+		assert.ErrorIs(t, err, ErrFundGrowthInvalidGoal, name)
+	}
+}
+
+func TestFundGrowthGoal_Progress(t *testing.T) {
+	goal := FundGrowthGoal{StartValue: decimal.NewFromInt(1000), TargetValue: decimal.NewFromInt(1200)}
+	assert.True(t, goal.Progress(decimal.NewFromInt(1100)).Equal(decimal.NewFromInt(50)))
+	assert.True(t, goal.Progress(decimal.NewFromInt(900)).IsZero())
+	assert.True(t, goal.Progress(decimal.NewFromInt(1500)).Equal(decimal.NewFromInt(100)))
+	assert.True(t, FundGrowthGoal{TargetValue: decimal.NewFromInt(1200)}.Progress(decimal.NewFromInt(1100)).IsZero())
+}
+
+func TestParseGrowthPeriod(t *testing.T) {
+	for raw, want := range map[string]time.Duration{
+		"":      0,
+		"week":  7 * 24 * time.Hour,
+		"Daily": 24 * time.Hour,
+		"month": 30 * 24 * time.Hour,
+		"10d":   10 * 24 * time.Hour,
+		"48h":   48 * time.Hour,
+	} {
+		got, err := ParseGrowthPeriod(raw)
+		require.NoError(t, err, raw)
+		assert.Equal(t, want, got, raw)
+	}
+	_, err := ParseGrowthPeriod("fortnight")
+	assert.ErrorIs(t, err, ErrFundGrowthInvalidGoal)
+	_, err = ParseGrowthPeriod("-1h")
+	assert.ErrorIs(t, err, ErrFundGrowthInvalidGoal)
+}
+
+type milestoneRecorder struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (r *milestoneRecorder) server(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(req.Body).Decode(&body)
+		r.mu.Lock()
+		r.messages = append(r.messages, body["text"].(string))
+		r.mu.Unlock()
+		_, _ = w.Write([]byte(`{"ok":true,"messageId":"1"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestIntegratedQuestHandlers_FundGrowth(t *testing.T) {
+	recorder := &milestoneRecorder{}
+	notifications := NewNotificationService(nil, nil, recorder.server(t).URL, "", "")
+	balances := &totalBalanceFetcher{total: map[string]float64{"USDT": 1000}}
+	handlers := NewIntegratedQuestHandlers(nil, balances, nil, nil, notifications, nil)
+
+	engine := NewQuestEngine(NewInMemoryQuestStore())
+	goal, err := NewFundGrowthGoal(decimal.Zero, decimal.Zero, decimal.NewFromInt(10), 0, time.Now())
+	require.NoError(t, err)
+	quest, err := engine.CreateFundGrowthQuest("42", goal)
+	require.NoError(t, err)
+	assert.Equal(t, QuestStatusActive, quest.Status)
+	assert.Equal(t, 100, quest.TargetCount)
+
+	// The first snapshot fixes the start; 10% growth targets 1100
+	require.NoError(t, handlers.handleFundGrowth(t.Context(), quest))
+	state, err := FundGrowthStateFromQuest(quest)
+	require.NoError(t, err)
+	assert.True(t, state.Goal.TargetValue.Equal(decimal.NewFromInt(1100)), state.Goal.TargetValue.String())
+	assert.Zero(t, quest.CurrentCount)
+
+	// Jumping past 25% and 50% sends one notification for the highest milestone
+	balances.total["USDT"] = 1060
+	require.NoError(t, handlers.handleFundGrowth(t.Context(), quest))
+	assert.Equal(t, 60, quest.CurrentCount)
+	require.Len(t, recorder.messages, 1)
+	assert.Contains(t, recorder.messages[0], "50%")
+
+	// The checkpoint survives a JSON round trip, as when reloaded from the store
+	raw, err := json.Marshal(quest.Checkpoint)
+	require.NoError(t, err)
+	quest.Checkpoint = map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(raw, &quest.Checkpoint))
+	state, err = FundGrowthStateFromQuest(quest)
+	require.NoError(t, err)
+	assert.Equal(t, []int{25, 50}, state.MilestonesReached)
+	assert.Len(t, state.Snapshots, 2)
+
+	// Same milestone again: no notification
+	require.NoError(t, handlers.handleFundGrowth(t.Context(), quest))
+	assert.Len(t, recorder.messages, 1)
+
+	balances.total["USDT"] = 1100
+	require.NoError(t, handlers.handleFundGrowth(t.Context(), quest))
+	assert.Equal(t, 100, quest.CurrentCount)
+	assert.Equal(t, "target_reached", quest.Checkpoint["status"])
+	require.Len(t, recorder.messages, 2)
+	assert.True(t, strings.Contains(recorder.messages[1], "Goal reached"), recorder.messages[1])
+}
+
+func TestIntegratedQuestHandlers_FundGrowthDeadline(t *testing.T) {
+	balances := &totalBalanceFetcher{total: map[string]float64{"USDT": 1000}}
+	handlers := NewIntegratedQuestHandlers(nil, balances, nil, nil, nil, nil)
+	engine := NewQuestEngine(nil)
+
+	goal, err := NewFundGrowthGoal(decimal.NewFromInt(1000), decimal.NewFromInt(2000), decimal.Zero, time.Hour, time.Now().Add(-2*time.Hour))
+	require.NoError(t, err)
+	quest, err := engine.CreateFundGrowthQuest("42", goal)
+	require.NoError(t, err)
+
+	err = handlers.handleFundGrowth(t.Context(), quest)
+	assert.ErrorContains(t, err, "missed its deadline")
+	assert.Equal(t, "deadline_missed", quest.Checkpoint["status"])
+}
+
+func TestQuestEngine_GoalQuestStaysActiveUntilTarget(t *testing.T) {
+	engine := NewQuestEngine(nil)
+	engine.RegisterHandler(QuestTypeGoal, func(_ context.Context, quest *Quest) error {
+		quest.CurrentCount += 60
+		return nil
+	})
+	goal, err := NewFundGrowthGoal(decimal.NewFromInt(1000), decimal.NewFromInt(1100), decimal.Zero, 0, time.Now())
+	require.NoError(t, err)
+	quest, err := engine.CreateFundGrowthQuest("42", goal)
+	require.NoError(t, err)
+
+	engine.executeQuest(quest)
+	assert.Equal(t, QuestStatusActive, quest.Status)
+	engine.executeQuest(quest)
+	assert.Equal(t, QuestStatusCompleted, quest.Status)
+
+	// Starting autonomous mode does not pause goals
+	quest.Status = QuestStatusActive
+	_, err = engine.BeginAutonomous("42")
+	require.NoError(t, err)
+	assert.Equal(t, QuestStatusActive, quest.Status)
+}
@@ -0,0 +1,345 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/redis/go-redis/v9"
+)
+
+// FundingForecastCacheKey is the Redis key the latest forecasts are published
+// under for services that do not hold the forecaster, such as the futures
+// arbitrage calculator.
+const FundingForecastCacheKey = "funding_forecasts:latest"
+
+const (
+	defaultFundingForecastInterval      = 5 * time.Minute
+	defaultFundingForecastAlpha         = 0.3
+	defaultFundingForecastPremiumWeight = 0.5
+	// Binance-style funding: the premium index plus a clamped interest
+	// component of 0.01% per interval.
+	defaultFundingInterestRate = 0.0001
+	defaultFundingPremiumClamp = 0.0005
+)
+
+// FundingRateSource provides the current funding rates of perpetual swaps.
+type FundingRateSource interface {
+	FetchFundingRates(ctx context.Context, exchange string, symbols []string) ([]ccxt.FundingRate, error)
+	FetchAllFundingRates(ctx context.Context, exchange string) ([]ccxt.FundingRate, error)
+}
+
+// FundingForecastConfig configures the funding rate forecaster.
+type FundingForecastConfig struct {
+	// Exchanges are polled for funding rates.
+	Exchanges []string
+	// Symbols limits the tracked perps; empty tracks every listed perp.
+	Symbols []string
+	// ScanInterval is how often rates are sampled.
+	ScanInterval time.Duration
+	// Alpha is the EWMA smoothing factor of sampled rates, in (0, 1].
+	Alpha float64
+	// PremiumWeight is the weight of the premium-implied rate in the
+	// forecast, in (0, 1]; the remainder goes to the EWMA.
+	PremiumWeight float64
+	// InterestRate and PremiumClamp model the interest component of the
+	// funding formula: rate = premium + clamp(interest - premium, ±clamp).
+	InterestRate float64
+	PremiumClamp float64
+}
+
+// FundingForecast is the predicted funding rate of a perp for its next
+// funding interval.
+type FundingForecast struct {
+	Exchange string `json:"exchange"`
+	Symbol   string `json:"symbol"`
+	// CurrentRate is the exchange's indicative rate for the running interval.
+	CurrentRate float64 `json:"current_rate"`
+	// PremiumIndex is (mark - index) / index at the last sample.
+	PremiumIndex       float64 `json:"premium_index"`
+	PremiumImpliedRate float64 `json:"premium_implied_rate"`
+	EWMARate           float64 `json:"ewma_rate"`
+	PredictedRate      float64 `json:"predicted_rate"`
+	// Flip is set when the predicted rate has the opposite sign of the
+	// current rate.
+	Flip            bool      `json:"flip"`
+	Samples         int       `json:"samples"`
+	NextFundingTime time.Time `json:"next_funding_time,omitempty"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// FundingForecaster predicts next-interval funding for tracked perps from an
+// EWMA of sampled rates blended with the rate implied by the premium index.
+type FundingForecaster struct {
+	source FundingRateSource
+	redis  *redis.Client
+	config FundingForecastConfig
+	logger *slog.Logger
+	now    func() time.Time
+
+	mu        sync.RWMutex
+	forecasts map[string]FundingForecast
+
+	runMu  sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewFundingForecaster creates the funding rate forecaster.
+//
+// Parameters:
+//
+//	source: Funding rate provider, usually the CCXT service.
+//	redisClient: Redis client forecasts are published to (may be nil).
+//	config: Forecaster configuration; zero values use defaults.
+//
+// Returns:
+//
+//	*FundingForecaster: Initialized forecaster.
+func NewFundingForecaster(source FundingRateSource, redisClient *redis.Client, config FundingForecastConfig) *FundingForecaster {
+	if config.ScanInterval <= 0 {
+		config.ScanInterval = defaultFundingForecastInterval
+	}
+	if config.Alpha <= 0 || config.Alpha > 1 {
+		config.Alpha = defaultFundingForecastAlpha
+	}
+	if config.PremiumWeight <= 0 || config.PremiumWeight > 1 {
+		config.PremiumWeight = defaultFundingForecastPremiumWeight
+	}
+	if config.InterestRate == 0 {
+		config.InterestRate = defaultFundingInterestRate
+	}
+	if config.PremiumClamp <= 0 {
+		config.PremiumClamp = defaultFundingPremiumClamp
+	}
+	return &FundingForecaster{
+		source:    source,
+		redis:     redisClient,
+		config:    config,
+		logger:    telemetry.Logger(),
+		now:       time.Now,
+		forecasts: make(map[string]FundingForecast),
+	}
+}
+
+// Config returns the forecaster configuration.
+func (f *FundingForecaster) Config() FundingForecastConfig {
+	return f.config
+}
+
+// Observe folds a funding rate sample into the forecast of its perp.
+//
+// Parameters:
+//
+//	exchange: Exchange identifier.
+//	rate: Funding rate sample with mark and index prices.
+//
+// Returns:
+//
+//	FundingForecast: The updated forecast.
+func (f *FundingForecaster) Observe(exchange string, rate ccxt.FundingRate) FundingForecast {
+	exchange = strings.ToLower(exchange)
+	key := fundingForecastKey(exchange, rate.Symbol)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	forecast, seen := f.forecasts[key]
+	forecast.Exchange = exchange
+	forecast.Symbol = rate.Symbol
+	forecast.CurrentRate = rate.FundingRate
+	if seen {
+		forecast.EWMARate = f.config.Alpha*rate.FundingRate + (1-f.config.Alpha)*forecast.EWMARate
+	} else {
+		forecast.EWMARate = rate.FundingRate
+	}
+	forecast.Samples++
+
+	forecast.PredictedRate = forecast.EWMARate
+	forecast.PremiumIndex, forecast.PremiumImpliedRate = 0, 0
+	if rate.MarkPrice > 0 && rate.IndexPrice > 0 {
+		forecast.PremiumIndex = (rate.MarkPrice - rate.IndexPrice) / rate.IndexPrice
+		forecast.PremiumImpliedRate = f.premiumImpliedRate(forecast.PremiumIndex)
+		forecast.PredictedRate = f.config.PremiumWeight*forecast.PremiumImpliedRate + (1-f.config.PremiumWeight)*forecast.EWMARate
+	}
+	forecast.Flip = forecast.CurrentRate*forecast.PredictedRate < 0
+	forecast.NextFundingTime = rate.NextFundingTime.Time()
+	forecast.UpdatedAt = f.now().UTC()
+
+	f.forecasts[key] = forecast
+	return forecast
+}
+
+// premiumImpliedRate applies the exchange funding formula to a premium index.
+func (f *FundingForecaster) premiumImpliedRate(premium float64) float64 {
+	interest := f.config.InterestRate - premium
+	interest = math.Max(-f.config.PremiumClamp, math.Min(f.config.PremiumClamp, interest))
+	return premium + interest
+}
+
+// Forecast returns the forecast of a perp. Symbols match with or without
+// their settlement suffix, so "BTC/USDT" finds "BTC/USDT:USDT".
+func (f *FundingForecaster) Forecast(exchange, symbol string) (FundingForecast, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	forecast, ok := f.forecasts[fundingForecastKey(exchange, symbol)]
+	return forecast, ok
+}
+
+// Forecasts returns the forecasts of an exchange, or of every exchange when
+// exchange is empty, ordered by exchange and symbol.
+func (f *FundingForecaster) Forecasts(exchange string) []FundingForecast {
+	exchange = strings.ToLower(exchange)
+	f.mu.RLock()
+	forecasts := make([]FundingForecast, 0, len(f.forecasts))
+	for _, forecast := range f.forecasts {
+		if exchange == "" || forecast.Exchange == exchange {
+			forecasts = append(forecasts, forecast)
+		}
+	}
+	f.mu.RUnlock()
+	sort.Slice(forecasts, func(i, j int) bool {
+		if forecasts[i].Exchange != forecasts[j].Exchange {
+			return forecasts[i].Exchange < forecasts[j].Exchange
+		}
+		return forecasts[i].Symbol < forecasts[j].Symbol
+	})
+	return forecasts
+}
+
+// Start samples funding rates every ScanInterval until Stop is called.
+func (f *FundingForecaster) Start(ctx context.Context) error {
+	f.runMu.Lock()
+	defer f.runMu.Unlock()
+	if f.cancel != nil {
+		return fmt.Errorf("funding forecaster already running")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	f.cancel = cancel
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		f.Scan(ctx)
+		ticker := time.NewTicker(f.config.ScanInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				f.Scan(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop ends the sampling loop.
+func (f *FundingForecaster) Stop() {
+	f.runMu.Lock()
+	cancel := f.cancel
+	f.cancel = nil
+	f.runMu.Unlock()
+	if cancel != nil {
+		cancel()
+		f.wg.Wait()
+	}
+}
+
+// Scan samples the funding rates of every configured exchange, updates the
+// forecasts and publishes them to Redis.
+func (f *FundingForecaster) Scan(ctx context.Context) {
+	for _, exchange := range f.config.Exchanges {
+		var rates []ccxt.FundingRate
+		var err error
+		if len(f.config.Symbols) > 0 {
+			rates, err = f.source.FetchFundingRates(ctx, exchange, f.config.Symbols)
+		} else {
+			rates, err = f.source.FetchAllFundingRates(ctx, exchange)
+		}
+		if err != nil {
+			f.logger.Warn("Failed to fetch funding rates for forecast", "exchange", exchange, "error", err)
+			continue
+		}
+		for _, rate := range rates {
+			forecast := f.Observe(exchange, rate)
+			if forecast.Flip {
+				f.logger.Info("Funding rate flip forecast",
+					"exchange", exchange, "symbol", rate.Symbol,
+					"current_rate", forecast.CurrentRate, "predicted_rate", forecast.PredictedRate)
+			}
+		}
+	}
+	f.publish(ctx)
+}
+
+// publish stores the forecasts in Redis; they expire after a few missed scans.
+func (f *FundingForecaster) publish(ctx context.Context) {
+	if f.redis == nil {
+		return
+	}
+	data, err := json.Marshal(f.Forecasts(""))
+	if err != nil {
+		return
+	}
+	if err := f.redis.Set(ctx, FundingForecastCacheKey, data, 3*f.config.ScanInterval).Err(); err != nil {
+		f.logger.Warn("Failed to publish funding forecasts", "error", err)
+	}
+}
+
+// LoadFundingForecasts reads the forecasts published by a FundingForecaster.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	redisClient: Redis client.
+//
+// Returns:
+//
+//	map[string]FundingForecast: Forecasts keyed for LookupFundingForecast;
+//	empty when none are published.
+//	error: Error if Redis fails or the payload is malformed.
+func LoadFundingForecasts(ctx context.Context, redisClient *redis.Client) (map[string]FundingForecast, error) {
+	forecasts := make(map[string]FundingForecast)
+	if redisClient == nil {
+		return forecasts, nil
+	}
+	data, err := redisClient.Get(ctx, FundingForecastCacheKey).Bytes()
+	if err == redis.Nil {
+		return forecasts, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []FundingForecast
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to decode funding forecasts: %w", err)
+	}
+	for _, forecast := range list {
+		forecasts[fundingForecastKey(forecast.Exchange, forecast.Symbol)] = forecast
+	}
+	return forecasts, nil
+}
+
+// LookupFundingForecast finds the forecast of a perp in loaded forecasts.
+func LookupFundingForecast(forecasts map[string]FundingForecast, exchange, symbol string) (FundingForecast, bool) {
+	forecast, ok := forecasts[fundingForecastKey(exchange, symbol)]
+	return forecast, ok
+}
+
+// fundingForecastKey identifies a perp independent of its settlement suffix.
+func fundingForecastKey(exchange, symbol string) string {
+	if i := strings.Index(symbol, ":"); i >= 0 {
+		symbol = symbol[:i]
+	}
+	return strings.ToLower(exchange) + "|" + strings.ToUpper(symbol)
+}
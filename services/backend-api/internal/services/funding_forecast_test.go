@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticFundingRates map[string][]ccxt.FundingRate
+
+func (s staticFundingRates) FetchFundingRates(_ context.Context, exchange string, _ []string) ([]ccxt.FundingRate, error) {
+	return s[exchange], nil
+}
+
+func (s staticFundingRates) FetchAllFundingRates(_ context.Context, exchange string) ([]ccxt.FundingRate, error) {
+	return s[exchange], nil
+}
+
+func TestFundingForecaster_ObserveBlendsEWMAAndPremium(t *testing.T) {
+	forecaster := NewFundingForecaster(nil, nil, FundingForecastConfig{Alpha: 0.5, PremiumWeight: 0.5})
+
+	// Without mark and index prices the forecast is the EWMA alone
+	forecast := forecaster.Observe("Binance", ccxt.FundingRate{Symbol: "BTC/USDT:USDT", FundingRate: 0.0004})
+	assert.Equal(t, "binance", forecast.Exchange)
+	assert.InDelta(t, 0.0004, forecast.PredictedRate, 1e-12)
+
+	// A discount to index pulls the forecast negative: premium -0.2% implies
+	// -0.2% + 0.05% clamp = -0.15%, blended with EWMA 0.0003
+	forecast = forecaster.Observe("binance", ccxt.FundingRate{Symbol: "BTC/USDT:USDT", FundingRate: 0.0002, MarkPrice: 99.8, IndexPrice: 100})
+	assert.Equal(t, 2, forecast.Samples)
+	assert.InDelta(t, 0.0003, forecast.EWMARate, 1e-12)
+	assert.InDelta(t, -0.002, forecast.PremiumIndex, 1e-12)
+	assert.InDelta(t, -0.0015, forecast.PremiumImpliedRate, 1e-12)
+	assert.InDelta(t, -0.0006, forecast.PredictedRate, 1e-12)
+	assert.True(t, forecast.Flip)
+
+	// A small premium keeps the interest rate
+	forecast = forecaster.Observe("binance", ccxt.FundingRate{Symbol: "ETH/USDT:USDT", FundingRate: 0.0001, MarkPrice: 100.001, IndexPrice: 100})
+	assert.InDelta(t, 0.0001, forecast.PremiumImpliedRate, 1e-12)
+	assert.False(t, forecast.Flip)
+
+	got, ok := forecaster.Forecast("binance", "BTC/USDT")
+	require.True(t, ok)
+	assert.InDelta(t, -0.0006, got.PredictedRate, 1e-12)
+	forecasts := forecaster.Forecasts("BINANCE")
+	require.Len(t, forecasts, 2)
+	assert.Equal(t, "BTC/USDT:USDT", forecasts[0].Symbol)
+}
+
+func TestFundingForecaster_ScanPublishesForecasts(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	source := staticFundingRates{"bybit": {{Symbol: "SOL/USDT:USDT", FundingRate: -0.0001, MarkPrice: 100.2, IndexPrice: 100}}}
+	forecaster := NewFundingForecaster(source, client, FundingForecastConfig{Exchanges: []string{"bybit"}})
+
+	forecaster.Scan(t.Context())
+	forecasts, err := LoadFundingForecasts(t.Context(), client)
+	require.NoError(t, err)
+	forecast, ok := LookupFundingForecast(forecasts, "bybit", "SOL/USDT")
+	require.True(t, ok)
+	assert.True(t, forecast.Flip)
+	assert.Greater(t, forecast.PredictedRate, 0.0)
+
+	server.FlushAll()
+	forecasts, err = LoadFundingForecasts(t.Context(), client)
+	require.NoError(t, err)
+	assert.Empty(t, forecasts)
+}
+
+func TestFundingPairRates_PrePositionsOnForecastFlip(t *testing.T) {
+	long := FundingRateData{Exchange: "binance", Symbol: "BTC/USDT", Rate: decimal.NewFromFloat(0.0001)}
+	short := FundingRateData{Exchange: "bybit", Symbol: "BTC/USDT", Rate: decimal.NewFromFloat(0.00015)}
+
+	longFunding, shortFunding, prePositioned := fundingPairRates(long, short, nil)
+	assert.False(t, prePositioned)
+	assert.True(t, longFunding.Equal(long.Rate))
+	assert.True(t, shortFunding.Equal(short.Rate))
+
+	forecasts := map[string]FundingForecast{
+		fundingForecastKey("binance", "BTC/USDT:USDT"): {PredictedRate: -0.0002},
+		fundingForecastKey("bybit", "BTC/USDT:USDT"):   {PredictedRate: 0.0003},
+	}
+	longFunding, shortFunding, prePositioned = fundingPairRates(long, short, forecasts)
+	assert.True(t, prePositioned)
+	assert.Equal(t, "0.0005", shortFunding.Sub(longFunding).String())
+
+	// Rates that already clear the minimum are used as they are
+	short.Rate = decimal.NewFromFloat(0.0005)
+	_, shortFunding, prePositioned = fundingPairRates(long, short, forecasts)
+	assert.False(t, prePositioned)
+	assert.True(t, shortFunding.Equal(short.Rate))
+}
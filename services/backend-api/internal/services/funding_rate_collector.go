@@ -298,6 +298,7 @@ func (c *FundingRateCollector) cleanupOldData(ctx context.Context) error {
 
 // GetFundingRateStats calculates statistical analysis for a symbol's funding rates.
 // Note: Forecasting models (e.g., ARIMA/GARCH) are intentionally not implemented; this uses rolling stats.
+// Next-interval predictions come from FundingForecaster.
 // See docs/architecture/ADVANCED_ANALYTICS.md.
 func (c *FundingRateCollector) GetFundingRateStats(
 	ctx context.Context,
@@ -152,6 +152,46 @@ func (m *MockCCXTClient) GetAllFundingRates(ctx context.Context, exchange string
 	return args.Get(0).([]ccxt.FundingRate), args.Error(1)
 }
 
+func (m *MockCCXTClient) GetDerivatives(ctx context.Context, exchange string, query ccxt.DerivativesQuery) (*ccxt.DerivativesResponse, error) {
+	args := m.Called(ctx, exchange, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ccxt.DerivativesResponse), args.Error(1)
+}
+
+func (m *MockCCXTClient) GetLeverage(ctx context.Context, exchange, symbol string) (*ccxt.MarginSettingsResponse, error) {
+	args := m.Called(ctx, exchange, symbol)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ccxt.MarginSettingsResponse), args.Error(1)
+}
+
+func (m *MockCCXTClient) SetLeverage(ctx context.Context, exchange string, req ccxt.SetLeverageRequest) (*ccxt.MarginSettingsResponse, error) {
+	args := m.Called(ctx, exchange, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ccxt.MarginSettingsResponse), args.Error(1)
+}
+
+func (m *MockCCXTClient) GetPositions(ctx context.Context, exchange string, symbols []string) (*ccxt.PositionsResponse, error) {
+	args := m.Called(ctx, exchange, symbols)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ccxt.PositionsResponse), args.Error(1)
+}
+
+func (m *MockCCXTClient) GetPositioning(ctx context.Context, exchange string, symbols []string, period string) (*ccxt.PositioningResponse, error) {
+	args := m.Called(ctx, exchange, symbols, period)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ccxt.PositioningResponse), args.Error(1)
+}
+
 func (m *MockCCXTClient) FetchBalance(ctx context.Context, exchange string) (*ccxt.BalanceResponse, error) {
 	args := m.Called(ctx, exchange)
 	if args.Get(0) == nil {
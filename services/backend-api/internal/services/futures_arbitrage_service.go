@@ -214,6 +214,12 @@ func (s *FuturesArbitrageService) calculateAndStoreOpportunities(ctx context.Con
 		return nil
 	}
 
+	// Published funding forecasts let positions open ahead of favorable flips
+	forecasts, err := LoadFundingForecasts(ctx, s.redisClient)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to load funding forecasts")
+	}
+
 	opportunitiesCalculated := 0
 	opportunitiesStored := 0
 
@@ -237,23 +243,33 @@ func (s *FuturesArbitrageService) calculateAndStoreOpportunities(ctx context.Con
 					continue // Same exchange
 				}
 
+				longFunding, shortFunding, prePositioned := fundingPairRates(longRate, shortRate, forecasts)
+
 				// Calculate net funding rate (short - long)
-				netFundingRate := shortRate.Rate.Sub(longRate.Rate)
+				netFundingRate := shortFunding.Sub(longFunding)
 
 				// Only consider profitable opportunities (positive net funding rate)
-				if netFundingRate.LessThanOrEqual(decimal.NewFromFloat(0.0001)) {
+				if netFundingRate.LessThanOrEqual(minNetFundingRate) {
 					continue // Minimum 0.01% threshold
 				}
 
 				opportunitiesCalculated++
+				if prePositioned {
+					s.logger.WithFields(map[string]interface{}{
+						"symbol":         symbol,
+						"long_exchange":  longRate.Exchange,
+						"short_exchange": shortRate.Exchange,
+						"net_forecast":   netFundingRate.String(),
+					}).Info("Pre-positioning ahead of forecast funding flip")
+				}
 
 				// Create calculation input
 				input := models.FuturesArbitrageCalculationInput{
 					Symbol:           symbol,
 					LongExchange:     longRate.Exchange,
 					ShortExchange:    shortRate.Exchange,
-					LongFundingRate:  longRate.Rate,
-					ShortFundingRate: shortRate.Rate,
+					LongFundingRate:  longFunding,
+					ShortFundingRate: shortFunding,
 					LongMarkPrice:    longRate.MarkPrice,
 					ShortMarkPrice:   shortRate.MarkPrice,
 					FundingInterval:  8, // Default 8 hours
@@ -303,6 +319,30 @@ func (s *FuturesArbitrageService) calculateAndStoreOpportunities(ctx context.Con
 	return nil
 }
 
+// minNetFundingRate is the smallest net funding an opportunity must earn.
+var minNetFundingRate = decimal.NewFromFloat(0.0001)
+
+// fundingPairRates returns the long and short funding rates an opportunity is
+// priced with. When the current rates do not clear the minimum net funding
+// but both legs' forecasts do, the forecast rates are used so the position
+// is opened ahead of the flip.
+func fundingPairRates(longRate, shortRate FundingRateData, forecasts map[string]FundingForecast) (decimal.Decimal, decimal.Decimal, bool) {
+	if shortRate.Rate.Sub(longRate.Rate).GreaterThan(minNetFundingRate) {
+		return longRate.Rate, shortRate.Rate, false
+	}
+	longForecast, okLong := LookupFundingForecast(forecasts, longRate.Exchange, longRate.Symbol)
+	shortForecast, okShort := LookupFundingForecast(forecasts, shortRate.Exchange, shortRate.Symbol)
+	if !okLong || !okShort {
+		return longRate.Rate, shortRate.Rate, false
+	}
+	longPredicted := decimal.NewFromFloat(longForecast.PredictedRate)
+	shortPredicted := decimal.NewFromFloat(shortForecast.PredictedRate)
+	if shortPredicted.Sub(longPredicted).GreaterThan(minNetFundingRate) {
+		return longPredicted, shortPredicted, true
+	}
+	return longRate.Rate, shortRate.Rate, false
+}
+
 // FundingRateData represents funding rate data for opportunity calculation
 type FundingRateData struct {
 	Exchange  string
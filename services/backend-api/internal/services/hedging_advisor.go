@@ -0,0 +1,563 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/irfndi/neuratrade/internal/models"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	hedgeSuggestionKey = "hedge:suggestion:%s"
+
+	defaultHedgeMinCorrelation = 0.7
+	defaultHedgeRatio          = 0.5
+	defaultHedgeSuggestionTTL  = 30 * time.Minute
+	hedgeStrategy              = "hedge"
+)
+
+var (
+	// ErrHedgeSuggestionNotFound is returned when a suggestion expired or belongs to another chat.
+	ErrHedgeSuggestionNotFound = errors.New("hedge suggestion not found or expired")
+	// ErrHedgeTradingHalted is returned when the kill switch blocks a confirmed hedge.
+	ErrHedgeTradingHalted = errors.New("trading is halted by the kill switch")
+)
+
+// OpenPosition is an open position considered for hedging.
+type OpenPosition struct {
+	Exchange   string          `json:"exchange"`
+	Symbol     string          `json:"symbol"`
+	Side       string          `json:"side"`
+	Size       decimal.Decimal `json:"size"`
+	EntryPrice decimal.Decimal `json:"entry_price"`
+}
+
+// OpenPositionSource lists the account's open positions. It is implemented
+// by the trading handler.
+type OpenPositionSource interface {
+	OpenPositions(ctx context.Context) ([]OpenPosition, error)
+}
+
+// CorrelationSource computes return correlations between symbols. It is
+// implemented by AnalyticsService.
+type CorrelationSource interface {
+	CalculateCorrelationMatrix(ctx context.Context, exchange string, symbols []string, limit int) (*models.CorrelationMatrix, error)
+}
+
+// AIReasoningNotifier sends AI reasoning notifications to Telegram.
+type AIReasoningNotifier interface {
+	NotifyAIReasoning(ctx context.Context, chatID int64, reasoning AIReasoningNotification) error
+}
+
+// HedgingConfig configures hedge suggestions.
+type HedgingConfig struct {
+	// MinCorrelation is the return correlation at which positions form a cluster.
+	MinCorrelation float64
+	// HedgeRatio is the share of a cluster's correlated exposure to offset.
+	HedgeRatio float64
+	// MinNotional is the gross cluster exposure below which no hedge is suggested.
+	MinNotional decimal.Decimal
+	// SuggestionTTL is how long a suggestion can be confirmed.
+	SuggestionTTL time.Duration
+	// ScanInterval is how often positions are analyzed in the background;
+	// zero leaves suggestions to on-demand requests.
+	ScanInterval time.Duration
+	// NotifyChatIDs are the chats sent new suggestions found by background scans.
+	NotifyChatIDs []int64
+}
+
+// HedgeCluster is a group of same-direction positions whose returns move together.
+type HedgeCluster struct {
+	Exchange  string   `json:"exchange"`
+	Symbols   []string `json:"symbols"`
+	Direction string   `json:"direction"`
+	// Exposure is the cluster's gross notional at entry prices.
+	Exposure       decimal.Decimal `json:"exposure"`
+	AvgCorrelation float64         `json:"avg_correlation"`
+}
+
+// HedgeSuggestion is an offsetting perpetual position for one cluster. It is
+// only executed once confirmed.
+type HedgeSuggestion struct {
+	ID        string          `json:"id"`
+	ChatID    string          `json:"chat_id"`
+	Cluster   HedgeCluster    `json:"cluster"`
+	Exchange  string          `json:"exchange"`
+	Symbol    string          `json:"symbol"`
+	Side      string          `json:"side"`
+	Amount    decimal.Decimal `json:"amount"`
+	Notional  decimal.Decimal `json:"notional"`
+	Reason    string          `json:"reason"`
+	CreatedAt time.Time       `json:"created_at"`
+	ExpiresAt time.Time       `json:"expires_at"`
+}
+
+// HedgeReport is the result of analyzing the open positions.
+type HedgeReport struct {
+	Positions   int               `json:"positions"`
+	Suggestions []HedgeSuggestion `json:"suggestions"`
+	// Notes explain exchanges that could not be analyzed.
+	Notes       []string  `json:"notes,omitempty"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// HedgingAdvisor finds clusters of correlated open positions and suggests
+// perpetual hedges against them. Suggestions are advisory: nothing is traded
+// until a chat confirms one.
+type HedgingAdvisor struct {
+	positions    OpenPositionSource
+	correlations CorrelationSource
+	redis        *redis.Client
+	config       HedgingConfig
+	placer       ExternalOrderPlacer
+	killCheck    KillSwitchChecker
+	notifier     AIReasoningNotifier
+	logger       *slog.Logger
+	now          func() time.Time
+	// mu guards lastNotified, each chat's fingerprint of the last scan's suggestions.
+	mu           sync.Mutex
+	lastNotified map[int64]string
+	runMu        sync.Mutex
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+}
+
+// NewHedgingAdvisor creates a hedging advisor.
+//
+// Parameters:
+//
+//	positions: Source of the open positions.
+//	correlations: Source of return correlations, such as the AnalyticsService.
+//	client: Redis client used to keep suggestions until they are confirmed.
+//	config: Advisor configuration; zero values use defaults.
+//
+// Returns:
+//
+//	*HedgingAdvisor: Initialized advisor (background scans not started).
+func NewHedgingAdvisor(positions OpenPositionSource, correlations CorrelationSource, client *redis.Client, config HedgingConfig) *HedgingAdvisor {
+	if config.MinCorrelation <= 0 || config.MinCorrelation > 1 {
+		config.MinCorrelation = defaultHedgeMinCorrelation
+	}
+	if config.HedgeRatio <= 0 || config.HedgeRatio > 1 {
+		config.HedgeRatio = defaultHedgeRatio
+	}
+	if config.SuggestionTTL <= 0 {
+		config.SuggestionTTL = defaultHedgeSuggestionTTL
+	}
+	return &HedgingAdvisor{
+		positions:    positions,
+		correlations: correlations,
+		redis:        client,
+		config:       config,
+		lastNotified: make(map[int64]string),
+		logger:       telemetry.Logger(),
+		now:          time.Now,
+	}
+}
+
+// SetOrderPlacer sets how confirmed hedges are opened.
+func (a *HedgingAdvisor) SetOrderPlacer(placer ExternalOrderPlacer) {
+	a.placer = placer
+}
+
+// SetKillSwitch refuses confirmed hedges while trading is halted.
+func (a *HedgingAdvisor) SetKillSwitch(check KillSwitchChecker) {
+	a.killCheck = check
+}
+
+// SetNotifier sets the Telegram sender for AI reasoning notifications.
+func (a *HedgingAdvisor) SetNotifier(notifier AIReasoningNotifier) {
+	a.notifier = notifier
+}
+
+// Start analyzes positions once per scan interval until Stop is called.
+// It does nothing when no scan interval is configured.
+func (a *HedgingAdvisor) Start(ctx context.Context) error {
+	if a.config.ScanInterval <= 0 {
+		return nil
+	}
+	a.runMu.Lock()
+	defer a.runMu.Unlock()
+	if a.cancel != nil {
+		return fmt.Errorf("hedging advisor already running")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	a.cancel = cancel
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		ticker := time.NewTicker(a.config.ScanInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				a.Scan(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops background scans and waits for the running scan to finish.
+func (a *HedgingAdvisor) Stop() {
+	a.runMu.Lock()
+	cancel := a.cancel
+	a.cancel = nil
+	a.runMu.Unlock()
+	if cancel != nil {
+		cancel()
+		a.wg.Wait()
+	}
+}
+
+// Scan analyzes positions for each notify chat and sends an AI reasoning
+// notification when the suggested hedges changed since the last scan.
+func (a *HedgingAdvisor) Scan(ctx context.Context) {
+	for _, chatID := range a.config.NotifyChatIDs {
+		report, err := a.Suggest(ctx, fmt.Sprintf("%d", chatID))
+		if err != nil {
+			a.logger.Warn("Failed to analyze positions for hedges", "chat_id", chatID, "error", err)
+			continue
+		}
+		fingerprint := hedgeFingerprint(report)
+		a.mu.Lock()
+		changed := fingerprint != a.lastNotified[chatID]
+		a.lastNotified[chatID] = fingerprint
+		a.mu.Unlock()
+		if changed && len(report.Suggestions) > 0 {
+			a.Notify(ctx, chatID, report)
+		}
+	}
+}
+
+// Suggest analyzes the open positions and stores a hedge suggestion for every
+// correlated cluster, ready to be confirmed by the chat.
+//
+// Parameters:
+//
+//	ctx: Context for the analysis.
+//	chatID: The chat that may confirm the suggestions.
+//
+// Returns:
+//
+//	*HedgeReport: The suggestions, empty when no cluster needs a hedge.
+//	error: Error if positions could not be listed or suggestions saved.
+func (a *HedgingAdvisor) Suggest(ctx context.Context, chatID string) (*HedgeReport, error) {
+	positions, err := a.positions.OpenPositions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open positions: %w", err)
+	}
+	now := a.now().UTC()
+	report := &HedgeReport{Positions: len(positions), Suggestions: []HedgeSuggestion{}, GeneratedAt: now}
+
+	exposures := netExposures(positions)
+	exchanges := make([]string, 0, len(exposures))
+	for exchange := range exposures {
+		exchanges = append(exchanges, exchange)
+	}
+	sort.Strings(exchanges)
+
+	for _, exchange := range exchanges {
+		symbols := exposures[exchange]
+		if len(symbols) < 2 {
+			continue
+		}
+		names := make([]string, 0, len(symbols))
+		for symbol := range symbols {
+			names = append(names, symbol)
+		}
+		matrix, err := a.correlations.CalculateCorrelationMatrix(ctx, exchange, names, 0)
+		if err != nil {
+			report.Notes = append(report.Notes, fmt.Sprintf("%s: correlations unavailable (%v)", exchange, err))
+			continue
+		}
+		for _, cluster := range a.clusters(exchange, symbols, matrix) {
+			suggestion, ok := a.suggestion(chatID, cluster, symbols, matrix, now)
+			if !ok {
+				continue
+			}
+			if err := a.save(ctx, suggestion); err != nil {
+				return nil, err
+			}
+			report.Suggestions = append(report.Suggestions, suggestion)
+		}
+	}
+	return report, nil
+}
+
+// clusters groups same-direction symbols whose correlation reaches the
+// minimum; clusters of a single symbol are not correlated exposure.
+func (a *HedgingAdvisor) clusters(exchange string, exposure map[string]hedgeExposure, matrix *models.CorrelationMatrix) []HedgeCluster {
+	n := len(matrix.Symbols)
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			si, sj := matrix.Symbols[i], matrix.Symbols[j]
+			if exposure[si].notional.Sign() != exposure[sj].notional.Sign() {
+				continue
+			}
+			if matrix.Matrix[i][j] >= a.config.MinCorrelation {
+				parent[find(i)] = find(j)
+			}
+		}
+	}
+
+	groups := make(map[int][]int)
+	for i := 0; i < n; i++ {
+		groups[find(i)] = append(groups[find(i)], i)
+	}
+	var clusters []HedgeCluster
+	for _, members := range groups {
+		if len(members) < 2 {
+			continue
+		}
+		cluster := HedgeCluster{Exchange: exchange, Direction: "long"}
+		pairs, sum := 0, 0.0
+		for x, i := range members {
+			symbol := matrix.Symbols[i]
+			cluster.Symbols = append(cluster.Symbols, symbol)
+			cluster.Exposure = cluster.Exposure.Add(exposure[symbol].notional.Abs())
+			if exposure[symbol].notional.IsNegative() {
+				cluster.Direction = "short"
+			}
+			for _, j := range members[x+1:] {
+				sum += matrix.Matrix[i][j]
+				pairs++
+			}
+		}
+		cluster.AvgCorrelation = sum / float64(pairs)
+		clusters = append(clusters, cluster)
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].Exposure.GreaterThan(clusters[j].Exposure) })
+	return clusters
+}
+
+// suggestion sizes a perpetual hedge on the cluster's largest position,
+// weighting each member's exposure by its correlation with that position.
+func (a *HedgingAdvisor) suggestion(chatID string, cluster HedgeCluster, exposure map[string]hedgeExposure, matrix *models.CorrelationMatrix, now time.Time) (HedgeSuggestion, bool) {
+	if cluster.Exposure.LessThan(a.config.MinNotional) {
+		return HedgeSuggestion{}, false
+	}
+	anchor := cluster.Symbols[0]
+	for _, symbol := range cluster.Symbols[1:] {
+		if exposure[symbol].notional.Abs().GreaterThan(exposure[anchor].notional.Abs()) {
+			anchor = symbol
+		}
+	}
+	index := make(map[string]int, len(matrix.Symbols))
+	for i, symbol := range matrix.Symbols {
+		index[symbol] = i
+	}
+
+	correlated := decimal.Zero
+	for _, symbol := range cluster.Symbols {
+		corr := math.Max(0, matrix.Matrix[index[anchor]][index[symbol]])
+		correlated = correlated.Add(exposure[symbol].notional.Abs().Mul(decimal.NewFromFloat(corr)))
+	}
+	notional := correlated.Mul(decimal.NewFromFloat(a.config.HedgeRatio))
+	price := exposure[anchor].price
+	if !price.IsPositive() || !notional.IsPositive() {
+		return HedgeSuggestion{}, false
+	}
+
+	side, verb := "SELL", "Short"
+	if cluster.Direction == "short" {
+		side, verb = "BUY", "Long"
+	}
+	symbol := perpetualSymbol(anchor)
+	return HedgeSuggestion{
+		ID:       uuid.New().String()[:8],
+		ChatID:   chatID,
+		Cluster:  cluster,
+		Exchange: cluster.Exchange,
+		Symbol:   symbol,
+		Side:     side,
+		Amount:   notional.Div(price).Round(6),
+		Notional: notional.Round(2),
+		Reason: fmt.Sprintf("%s %s perp against the %s cluster %s (%s USDT, avg correlation %.2f), offsetting %.0f%% of its correlated exposure",
+			verb, symbol, cluster.Direction, strings.Join(cluster.Symbols, ", "), cluster.Exposure.StringFixed(2),
+			cluster.AvgCorrelation, a.config.HedgeRatio*100),
+		CreatedAt: now,
+		ExpiresAt: now.Add(a.config.SuggestionTTL),
+	}, true
+}
+
+// Confirm opens a suggested hedge on behalf of the chat that received it.
+//
+// Parameters:
+//
+//	ctx: Context for the order.
+//	chatID: The chat confirming the hedge.
+//	id: The suggestion ID.
+//
+// Returns:
+//
+//	*HedgeSuggestion: The executed suggestion.
+//	string: The order ID.
+//	error: ErrHedgeSuggestionNotFound, ErrHedgeTradingHalted or an order error.
+func (a *HedgingAdvisor) Confirm(ctx context.Context, chatID, id string) (*HedgeSuggestion, string, error) {
+	if a.placer == nil {
+		return nil, "", fmt.Errorf("hedge execution is not configured")
+	}
+	key := fmt.Sprintf(hedgeSuggestionKey, id)
+	raw, err := a.redis.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return nil, "", ErrHedgeSuggestionNotFound
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load hedge suggestion: %w", err)
+	}
+	var suggestion HedgeSuggestion
+	if err := json.Unmarshal([]byte(raw), &suggestion); err != nil {
+		return nil, "", fmt.Errorf("failed to decode hedge suggestion: %w", err)
+	}
+	if suggestion.ChatID != chatID {
+		return nil, "", ErrHedgeSuggestionNotFound
+	}
+	if a.killCheck != nil && a.killCheck.KillSwitchEngaged(ctx) {
+		return nil, "", ErrHedgeTradingHalted
+	}
+	// Deleting first keeps a double confirmation from opening two hedges.
+	deleted, err := a.redis.Del(ctx, key).Result()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to claim hedge suggestion: %w", err)
+	}
+	if deleted == 0 {
+		return nil, "", ErrHedgeSuggestionNotFound
+	}
+
+	orderID, err := a.placer.PlaceExternalOrder(ctx, ExternalOrder{
+		Exchange: suggestion.Exchange,
+		Symbol:   suggestion.Symbol,
+		Side:     suggestion.Side,
+		Amount:   suggestion.Amount,
+		Strategy: hedgeStrategy,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	a.logger.Info("Opened confirmed hedge", "chat_id", chatID, "suggestion_id", id, "symbol", suggestion.Symbol, "side", suggestion.Side)
+	return &suggestion, orderID, nil
+}
+
+// Notify presents the report as an AI reasoning notification.
+func (a *HedgingAdvisor) Notify(ctx context.Context, chatID int64, report *HedgeReport) {
+	if a.notifier == nil || len(report.Suggestions) == 0 {
+		return
+	}
+	reasons := make([]string, 0, len(report.Suggestions))
+	confidence := 0.0
+	for _, s := range report.Suggestions {
+		reasons = append(reasons, fmt.Sprintf("%s %s %s (~%s USDT): %s", s.Side, s.Amount.String(), s.Symbol, s.Notional.StringFixed(2), s.Reason))
+		confidence += s.Cluster.AvgCorrelation
+	}
+	err := a.notifier.NotifyAIReasoning(ctx, chatID, AIReasoningNotification{
+		DecisionType: "hedge_suggestion",
+		Summary:      fmt.Sprintf("%d correlated position cluster(s) could be hedged", len(report.Suggestions)),
+		Confidence:   confidence / float64(len(report.Suggestions)),
+		Reasons:      reasons,
+		Action:       fmt.Sprintf("Review with /hedge; nothing is traded until confirmed (valid %s)", a.config.SuggestionTTL),
+	})
+	if err != nil {
+		a.logger.Warn("Failed to send hedge suggestion", "chat_id", chatID, "error", err)
+	}
+}
+
+func (a *HedgingAdvisor) save(ctx context.Context, suggestion HedgeSuggestion) error {
+	raw, err := json.Marshal(suggestion)
+	if err != nil {
+		return err
+	}
+	if err := a.redis.Set(ctx, fmt.Sprintf(hedgeSuggestionKey, suggestion.ID), raw, a.config.SuggestionTTL).Err(); err != nil {
+		return fmt.Errorf("failed to save hedge suggestion: %w", err)
+	}
+	return nil
+}
+
+// hedgeExposure is the net signed notional of one symbol and the price used
+// to convert a hedge notional into an amount.
+type hedgeExposure struct {
+	notional decimal.Decimal
+	price    decimal.Decimal
+}
+
+// netExposures nets positions per exchange and symbol at entry prices; longs
+// are positive and shorts negative.
+func netExposures(positions []OpenPosition) map[string]map[string]hedgeExposure {
+	result := make(map[string]map[string]hedgeExposure)
+	for _, p := range positions {
+		if !p.Size.IsPositive() || !p.EntryPrice.IsPositive() {
+			continue
+		}
+		notional := p.Size.Mul(p.EntryPrice)
+		switch strings.ToUpper(p.Side) {
+		case "SELL", "SHORT":
+			notional = notional.Neg()
+		}
+		exchange := strings.ToLower(p.Exchange)
+		if result[exchange] == nil {
+			result[exchange] = make(map[string]hedgeExposure)
+		}
+		current := result[exchange][p.Symbol]
+		current.notional = current.notional.Add(notional)
+		current.price = p.EntryPrice
+		result[exchange][p.Symbol] = current
+	}
+	for exchange, symbols := range result {
+		for symbol, exposure := range symbols {
+			if exposure.notional.IsZero() {
+				delete(symbols, symbol)
+			}
+		}
+		if len(symbols) == 0 {
+			delete(result, exchange)
+		}
+	}
+	return result
+}
+
+// perpetualSymbol returns the USDT-margined perpetual for a spot symbol.
+func perpetualSymbol(symbol string) string {
+	if strings.Contains(symbol, ":") {
+		return symbol
+	}
+	quote := symbol
+	if i := strings.Index(symbol, "/"); i >= 0 {
+		quote = symbol[i+1:]
+	}
+	return symbol + ":" + quote
+}
+
+func hedgeFingerprint(report *HedgeReport) string {
+	parts := make([]string, 0, len(report.Suggestions))
+	for _, s := range report.Suggestions {
+		parts = append(parts, s.Symbol+":"+s.Side+":"+strings.Join(s.Cluster.Symbols, ","))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "|")
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/irfndi/neuratrade/internal/models"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticPositions []OpenPosition
+
+func (s staticPositions) OpenPositions(context.Context) ([]OpenPosition, error) {
+	return s, nil
+}
+
+type staticCorrelations struct {
+	matrix *models.CorrelationMatrix
+	err    error
+}
+
+func (s staticCorrelations) CalculateCorrelationMatrix(_ context.Context, exchange string, _ []string, _ int) (*models.CorrelationMatrix, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	matrix := *s.matrix
+	matrix.Exchange = exchange
+	return &matrix, nil
+}
+
+type reasoningRecorder struct {
+	notes []AIReasoningNotification
+}
+
+func (r *reasoningRecorder) NotifyAIReasoning(_ context.Context, _ int64, reasoning AIReasoningNotification) error {
+	r.notes = append(r.notes, reasoning)
+	return nil
+}
+
+func position(symbol, side string, size, price int64) OpenPosition {
+	return OpenPosition{Exchange: "binance", Symbol: symbol, Side: side, Size: decimal.NewFromInt(size), EntryPrice: decimal.NewFromInt(price)}
+}
+
+func newTestHedgingAdvisor(t *testing.T, positions []OpenPosition, correlations CorrelationSource, config HedgingConfig) *HedgingAdvisor {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewHedgingAdvisor(staticPositions(positions), correlations, client, config)
+}
+
+// Symbols are sorted, as CalculateCorrelationMatrix returns them.
+var hedgeTestMatrix = &models.CorrelationMatrix{
+	Symbols: []string{"BTC/USDT", "DOGE/USDT", "ETH/USDT", "SOL/USDT"},
+	Matrix: [][]float64{
+		{1, 0.2, 0.9, 0.8},
+		{0.2, 1, 0.1, 0.3},
+		{0.9, 0.1, 1, 0.75},
+		{0.8, 0.3, 0.75, 1},
+	},
+}
+
+func TestHedgingAdvisor_Suggest(t *testing.T) {
+	positions := []OpenPosition{
+		position("BTC/USDT", "BUY", 1, 1000),
+		position("ETH/USDT", "BUY", 5, 100),
+		position("SOL/USDT", "BUY", 10, 10),
+		// Uncorrelated with the cluster
+		position("DOGE/USDT", "BUY", 1000, 1),
+	}
+	advisor := newTestHedgingAdvisor(t, positions, staticCorrelations{matrix: hedgeTestMatrix}, HedgingConfig{MinNotional: decimal.NewFromInt(100)})
+
+	report, err := advisor.Suggest(t.Context(), "42")
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Positions)
+	require.Len(t, report.Suggestions, 1)
+
+	suggestion := report.Suggestions[0]
+	assert.Equal(t, []string{"BTC/USDT", "ETH/USDT", "SOL/USDT"}, suggestion.Cluster.Symbols)
+	assert.Equal(t, "long", suggestion.Cluster.Direction)
+	assert.True(t, suggestion.Cluster.Exposure.Equal(decimal.NewFromInt(1600)))
+	assert.InDelta(t, (0.9+0.8+0.75)/3, suggestion.Cluster.AvgCorrelation, 1e-9)
+	// Hedged on the largest position: (1000*1 + 500*0.9 + 100*0.8) * 0.5 = 765
+	assert.Equal(t, "BTC/USDT:USDT", suggestion.Symbol)
+	assert.Equal(t, "SELL", suggestion.Side)
+	assert.True(t, suggestion.Notional.Equal(decimal.NewFromInt(765)), suggestion.Notional.String())
+	assert.True(t, suggestion.Amount.Equal(decimal.NewFromFloat(0.765)), suggestion.Amount.String())
+	assert.Contains(t, suggestion.Reason, "Short BTC/USDT:USDT perp")
+}
+
+func TestHedgingAdvisor_SuggestSkipsSmallAndMixedClusters(t *testing.T) {
+	// Opposite directions are already hedged against each other
+	positions := []OpenPosition{
+		position("BTC/USDT", "BUY", 1, 1000),
+		position("ETH/USDT", "SELL", 5, 100),
+	}
+	advisor := newTestHedgingAdvisor(t, positions, staticCorrelations{matrix: hedgeTestMatrix}, HedgingConfig{})
+	report, err := advisor.Suggest(t.Context(), "42")
+	require.NoError(t, err)
+	assert.Empty(t, report.Suggestions)
+
+	positions = []OpenPosition{
+		position("BTC/USDT", "SELL", 1, 10),
+		position("ETH/USDT", "SELL", 1, 10),
+	}
+	advisor = newTestHedgingAdvisor(t, positions, staticCorrelations{matrix: hedgeTestMatrix}, HedgingConfig{MinNotional: decimal.NewFromInt(100)})
+	report, err = advisor.Suggest(t.Context(), "42")
+	require.NoError(t, err)
+	assert.Empty(t, report.Suggestions)
+
+	advisor = newTestHedgingAdvisor(t, positions, staticCorrelations{err: errors.New("correlation analysis is disabled")}, HedgingConfig{})
+	report, err = advisor.Suggest(t.Context(), "42")
+	require.NoError(t, err)
+	assert.Empty(t, report.Suggestions)
+	require.Len(t, report.Notes, 1)
+}
+
+func TestHedgingAdvisor_Confirm(t *testing.T) {
+	positions := []OpenPosition{
+		position("BTC/USDT", "SELL", 1, 1000),
+		position("ETH/USDT", "SELL", 5, 100),
+	}
+	advisor := newTestHedgingAdvisor(t, positions, staticCorrelations{matrix: hedgeTestMatrix}, HedgingConfig{})
+	placer := &recordingPlacer{}
+	ctx := t.Context()
+
+	_, _, err := advisor.Confirm(ctx, "42", "missing")
+	assert.Error(t, err)
+
+	advisor.SetOrderPlacer(placer)
+	report, err := advisor.Suggest(ctx, "42")
+	require.NoError(t, err)
+	require.Len(t, report.Suggestions, 1)
+	id := report.Suggestions[0].ID
+	assert.Equal(t, "BUY", report.Suggestions[0].Side)
+
+	// Only the chat that received the suggestion can confirm it
+	_, _, err = advisor.Confirm(ctx, "7", id)
+	assert.ErrorIs(t, err, ErrHedgeSuggestionNotFound)
+
+	advisor.SetKillSwitch(fixedKillSwitch(true))
+	_, _, err = advisor.Confirm(ctx, "42", id)
+	assert.ErrorIs(t, err, ErrHedgeTradingHalted)
+	assert.Empty(t, placer.orders)
+
+	advisor.SetKillSwitch(fixedKillSwitch(false))
+	suggestion, orderID, err := advisor.Confirm(ctx, "42", id)
+	require.NoError(t, err)
+	assert.Equal(t, "ord-1", orderID)
+	assert.Equal(t, id, suggestion.ID)
+	require.Len(t, placer.orders, 1)
+	assert.Equal(t, "BTC/USDT:USDT", placer.orders[0].Symbol)
+	assert.Equal(t, "BUY", placer.orders[0].Side)
+	assert.Equal(t, "hedge", placer.orders[0].Strategy)
+
+	// A suggestion is executed at most once
+	_, _, err = advisor.Confirm(ctx, "42", id)
+	assert.ErrorIs(t, err, ErrHedgeSuggestionNotFound)
+	assert.Len(t, placer.orders, 1)
+}
+
+func TestHedgingAdvisor_ScanNotifiesChanges(t *testing.T) {
+	positions := []OpenPosition{
+		position("BTC/USDT", "BUY", 1, 1000),
+		position("ETH/USDT", "BUY", 5, 100),
+	}
+	advisor := newTestHedgingAdvisor(t, positions, staticCorrelations{matrix: hedgeTestMatrix}, HedgingConfig{ScanInterval: time.Minute, NotifyChatIDs: []int64{42}})
+	notifier := &reasoningRecorder{}
+	advisor.SetNotifier(notifier)
+
+	advisor.Scan(t.Context())
+	require.Len(t, notifier.notes, 1)
+	assert.Equal(t, "hedge_suggestion", notifier.notes[0].DecisionType)
+	assert.InDelta(t, 0.9, notifier.notes[0].Confidence, 1e-9)
+	assert.Contains(t, notifier.notes[0].Action, "/hedge")
+
+	// Unchanged suggestions are not repeated
+	advisor.Scan(t.Context())
+	assert.Len(t, notifier.notes, 1)
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// HookedOrderExecutor runs pre-trade and post-trade hooks around a
+// CCXTOrderExecutor. Order queries and cancellations pass through unchanged.
+type HookedOrderExecutor struct {
+	*CCXTOrderExecutor
+	hooks  *HookRegistry
+	events EventEmitter
+	now    func() time.Time
+}
+
+// NewHookedOrderExecutor wraps an order executor with hooks.
+//
+// Parameters:
+//
+//	executor: Executor that places the orders.
+//	hooks: Hook registry (nil places orders without hooks).
+//
+// Returns:
+//
+//	*HookedOrderExecutor: Initialized executor.
+func NewHookedOrderExecutor(executor *CCXTOrderExecutor, hooks *HookRegistry) *HookedOrderExecutor {
+	return &HookedOrderExecutor{CCXTOrderExecutor: executor, hooks: hooks, now: time.Now}
+}
+
+// SetEventEmitter publishes successful orders, with post-trade hook
+// metadata, as trade.executed events.
+func (e *HookedOrderExecutor) SetEventEmitter(events EventEmitter) {
+	e.events = events
+}
+
+// PlaceOrder runs the pre-trade hooks, places the possibly modified order
+// and passes the result to the post-trade hooks.
+func (e *HookedOrderExecutor) PlaceOrder(ctx context.Context, exchange, symbol, side, orderType string, amount decimal.Decimal, price *decimal.Decimal) (string, error) {
+	return e.PlaceOrderWithOptions(ctx, exchange, symbol, side, orderType, amount, price, OrderOptions{})
+}
+
+// PlaceOrderWithOptions is PlaceOrder with execution flags.
+func (e *HookedOrderExecutor) PlaceOrderWithOptions(ctx context.Context, exchange, symbol, side, orderType string, amount decimal.Decimal, price *decimal.Decimal, options OrderOptions) (string, error) {
+	order, err := e.hooks.RunPreTrade(ctx, HookOrder{
+		Exchange:     exchange,
+		Symbol:       symbol,
+		Side:         side,
+		OrderType:    orderType,
+		Amount:       amount,
+		Price:        price,
+		OrderOptions: options,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	orderID, placeErr := e.CCXTOrderExecutor.PlaceOrderWithOptions(ctx, order.Exchange, order.Symbol, order.Side, order.OrderType, order.Amount, order.Price, order.OrderOptions)
+
+	event := PostTradeEvent{Order: order, OrderID: orderID, ExecutedAt: e.now().UTC()}
+	if placeErr != nil {
+		event.Error = placeErr.Error()
+	}
+	// Post-trade hooks must not delay or cancel with the caller's request
+	event = e.hooks.RunPostTrade(context.WithoutCancel(ctx), event)
+	if placeErr == nil && e.events != nil {
+		e.events.Emit(ctx, WebhookEventTradeExecuted, event)
+	}
+	return orderID, placeErr
+}
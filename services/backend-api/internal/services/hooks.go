@@ -0,0 +1,481 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"plugin"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/shopspring/decimal"
+)
+
+// HookEvent names an extension point hooks can intercept.
+type HookEvent string
+
+const (
+	// HookPreTrade runs before an order is sent and may veto or modify it.
+	HookPreTrade HookEvent = "pre_trade"
+	// HookPostTrade runs after an order attempt and may enrich the trade event.
+	HookPostTrade HookEvent = "post_trade"
+	// HookPreNotify runs before a Telegram message is sent and may rewrite or
+	// suppress it.
+	HookPreNotify HookEvent = "pre_notify"
+)
+
+const (
+	defaultHookTimeout = 2 * time.Second
+
+	// HookEventHeader names the extension point of a hook webhook request.
+	HookEventHeader = "X-NeuraTrade-Hook"
+
+	// hookPluginSymbol is the exported variable a Go plugin must define.
+	hookPluginSymbol = "Hook"
+)
+
+// ErrTradeVetoed is returned when a pre-trade hook blocks an order.
+var ErrTradeVetoed = errors.New("trade vetoed by hook")
+
+// HookOrder is the order passed through trade hooks.
+type HookOrder struct {
+	Exchange  string           `json:"exchange"`
+	Symbol    string           `json:"symbol"`
+	Side      string           `json:"side"`
+	OrderType string           `json:"order_type"`
+	Amount    decimal.Decimal  `json:"amount"`
+	Price     *decimal.Decimal `json:"price,omitempty"`
+	// OrderOptions are the strategy's execution flags. Hooks see them but
+	// cannot change them.
+	OrderOptions
+}
+
+// PreTradeDecision is a pre-trade hook's verdict. A nil Order keeps the
+// order unchanged.
+type PreTradeDecision struct {
+	Veto   bool       `json:"veto"`
+	Reason string     `json:"reason,omitempty"`
+	Order  *HookOrder `json:"order,omitempty"`
+}
+
+// PostTradeEvent describes an order attempt. Hooks add to Metadata, which is
+// published with the trade.executed event.
+type PostTradeEvent struct {
+	Order      HookOrder              `json:"order"`
+	OrderID    string                 `json:"order_id,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	ExecutedAt time.Time              `json:"executed_at"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// HookNotification is a Telegram message passed through pre-notify hooks.
+type HookNotification struct {
+	ChatID   int64                `json:"chat_id"`
+	Text     string               `json:"text"`
+	Priority NotificationPriority `json:"priority,omitempty"`
+}
+
+// PreNotifyDecision is a pre-notify hook's verdict. A nil Text keeps the
+// message unchanged.
+type PreNotifyDecision struct {
+	Suppress bool    `json:"suppress"`
+	Reason   string  `json:"reason,omitempty"`
+	Text     *string `json:"text,omitempty"`
+}
+
+// PreTradeHook may veto or modify an order before it is placed.
+type PreTradeHook interface {
+	PreTrade(ctx context.Context, order HookOrder) (PreTradeDecision, error)
+}
+
+// PostTradeHook receives every order attempt and returns metadata to merge
+// into the trade event.
+type PostTradeHook interface {
+	PostTrade(ctx context.Context, event PostTradeEvent) (map[string]interface{}, error)
+}
+
+// PreNotifyHook may rewrite or suppress a notification before it is sent.
+type PreNotifyHook interface {
+	PreNotify(ctx context.Context, msg HookNotification) (PreNotifyDecision, error)
+}
+
+// HookConfig configures how hook failures are handled.
+type HookConfig struct {
+	// Timeout bounds each hook call.
+	Timeout time.Duration
+	// FailOpen lets orders through when a pre-trade hook fails. By default a
+	// failing pre-trade hook blocks the order; failing post-trade and
+	// pre-notify hooks are always logged and skipped.
+	FailOpen bool
+}
+
+type namedHook struct {
+	name string
+	hook interface{}
+}
+
+// HookRegistry runs registered hooks at each extension point in
+// registration order. Hooks come from Go code (Register), Go plugins
+// (LoadPlugin) or webhooks (NewWebhookHook).
+type HookRegistry struct {
+	config HookConfig
+	logger *slog.Logger
+	mu     sync.RWMutex
+	hooks  []namedHook
+}
+
+// NewHookRegistry creates an empty hook registry.
+//
+// Parameters:
+//
+//	config: Failure handling; zero values use defaults.
+//
+// Returns:
+//
+//	*HookRegistry: Initialized registry.
+func NewHookRegistry(config HookConfig) *HookRegistry {
+	if config.Timeout <= 0 {
+		config.Timeout = defaultHookTimeout
+	}
+	return &HookRegistry{config: config, logger: telemetry.Logger()}
+}
+
+// Register adds a hook. It runs at every extension point whose interface
+// (PreTradeHook, PostTradeHook, PreNotifyHook) it implements.
+//
+// Parameters:
+//
+//	name: Name used in logs and veto reasons.
+//	hook: Hook implementation.
+//
+// Returns:
+//
+//	error: Error if the hook implements none of the hook interfaces.
+func (r *HookRegistry) Register(name string, hook interface{}) error {
+	_, pre := hook.(PreTradeHook)
+	_, post := hook.(PostTradeHook)
+	_, notify := hook.(PreNotifyHook)
+	if !pre && !post && !notify {
+		return fmt.Errorf("hook %s implements no hook interface", name)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = append(r.hooks, namedHook{name: name, hook: hook})
+	return nil
+}
+
+// LoadPlugin opens a Go plugin built with -buildmode=plugin and registers
+// its exported Hook variable. Plugins must be built against the same module
+// versions as the server.
+//
+// Parameters:
+//
+//	path: Path to the plugin .so file.
+//
+// Returns:
+//
+//	error: Error if the plugin cannot be opened or exports no usable hook.
+func (r *HookRegistry) LoadPlugin(path string) error {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open hook plugin %s: %w", path, err)
+	}
+	symbol, err := p.Lookup(hookPluginSymbol)
+	if err != nil {
+		return fmt.Errorf("hook plugin %s: %w", path, err)
+	}
+	return r.Register(filepath.Base(path), symbol)
+}
+
+// Len returns the number of registered hooks.
+func (r *HookRegistry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.hooks)
+}
+
+func (r *HookRegistry) snapshot() []namedHook {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]namedHook(nil), r.hooks...)
+}
+
+// RunPreTrade passes an order through the pre-trade hooks. Each hook sees
+// the order as modified by the hooks before it. A modified order must keep
+// its exchange, symbol and side and may not grow, so hooks cannot bypass the
+// risk checks that sized it.
+//
+// Parameters:
+//
+//	ctx: Context for cancellation.
+//	order: Order about to be placed.
+//
+// Returns:
+//
+//	HookOrder: Order to place.
+//	error: ErrTradeVetoed (wrapped with the reason) if a hook blocked the order.
+func (r *HookRegistry) RunPreTrade(ctx context.Context, order HookOrder) (HookOrder, error) {
+	if r == nil {
+		return order, nil
+	}
+	for _, h := range r.snapshot() {
+		hook, ok := h.hook.(PreTradeHook)
+		if !ok {
+			continue
+		}
+		hookCtx, cancel := context.WithTimeout(ctx, r.config.Timeout)
+		decision, err := hook.PreTrade(hookCtx, order)
+		cancel()
+		if err != nil {
+			if r.config.FailOpen {
+				r.logger.Warn("Pre-trade hook failed, continuing", "hook", h.name, "error", err)
+				continue
+			}
+			return order, fmt.Errorf("%w: %s failed: %v", ErrTradeVetoed, h.name, err)
+		}
+		if decision.Veto {
+			return order, fmt.Errorf("%w: %s: %s", ErrTradeVetoed, h.name, decision.Reason)
+		}
+		if decision.Order != nil {
+			if err := validateModifiedOrder(order, *decision.Order); err != nil {
+				return order, fmt.Errorf("%w: %s returned an invalid order: %v", ErrTradeVetoed, h.name, err)
+			}
+			r.logger.Info("Pre-trade hook modified order", "hook", h.name, "symbol", order.Symbol,
+				"amount", decision.Order.Amount.String(), "reason", decision.Reason)
+			options := order.OrderOptions
+			order = *decision.Order
+			order.OrderOptions = options
+		}
+	}
+	return order, nil
+}
+
+func validateModifiedOrder(original, modified HookOrder) error {
+	if modified.Exchange != original.Exchange || modified.Symbol != original.Symbol || modified.Side != original.Side {
+		return errors.New("exchange, symbol and side cannot change")
+	}
+	if !modified.Amount.IsPositive() {
+		return errors.New("amount must be positive")
+	}
+	if modified.Amount.GreaterThan(original.Amount) {
+		return errors.New("amount cannot increase")
+	}
+	if modified.OrderType == "" {
+		return errors.New("order type is required")
+	}
+	return nil
+}
+
+// RunPostTrade passes an order attempt to the post-trade hooks and returns
+// the event with their metadata merged. Hook failures are logged.
+//
+// Parameters:
+//
+//	ctx: Context for cancellation.
+//	event: Order attempt.
+//
+// Returns:
+//
+//	PostTradeEvent: Event enriched with hook metadata.
+func (r *HookRegistry) RunPostTrade(ctx context.Context, event PostTradeEvent) PostTradeEvent {
+	if r == nil {
+		return event
+	}
+	for _, h := range r.snapshot() {
+		hook, ok := h.hook.(PostTradeHook)
+		if !ok {
+			continue
+		}
+		hookCtx, cancel := context.WithTimeout(ctx, r.config.Timeout)
+		metadata, err := hook.PostTrade(hookCtx, event)
+		cancel()
+		if err != nil {
+			r.logger.Warn("Post-trade hook failed", "hook", h.name, "error", err)
+			continue
+		}
+		if len(metadata) > 0 && event.Metadata == nil {
+			event.Metadata = make(map[string]interface{}, len(metadata))
+		}
+		for key, value := range metadata {
+			event.Metadata[key] = value
+		}
+	}
+	return event
+}
+
+// RunPreNotify passes a message through the pre-notify hooks. Hook failures
+// are logged and the message is sent as it was. Critical messages cannot be
+// suppressed, though hooks may still rewrite them.
+//
+// Parameters:
+//
+//	ctx: Context for cancellation.
+//	msg: Message about to be sent.
+//
+// Returns:
+//
+//	HookNotification: Message to send.
+//	bool: False if a hook suppressed the message.
+func (r *HookRegistry) RunPreNotify(ctx context.Context, msg HookNotification) (HookNotification, bool) {
+	if r == nil {
+		return msg, true
+	}
+	for _, h := range r.snapshot() {
+		hook, ok := h.hook.(PreNotifyHook)
+		if !ok {
+			continue
+		}
+		hookCtx, cancel := context.WithTimeout(ctx, r.config.Timeout)
+		decision, err := hook.PreNotify(hookCtx, msg)
+		cancel()
+		if err != nil {
+			r.logger.Warn("Pre-notify hook failed", "hook", h.name, "error", err)
+			continue
+		}
+		if decision.Suppress {
+			if msg.Priority != PriorityNotificationEmergency {
+				r.logger.Info("Notification suppressed by hook", "hook", h.name, "chat_id", msg.ChatID, "reason", decision.Reason)
+				return msg, false
+			}
+			r.logger.Info("Ignoring hook suppression of a critical notification", "hook", h.name, "chat_id", msg.ChatID, "reason", decision.Reason)
+		}
+		if decision.Text != nil && *decision.Text != "" {
+			msg.Text = *decision.Text
+		}
+	}
+	return msg, true
+}
+
+// WebhookHook forwards hook events to an HTTP endpoint. The endpoint
+// receives {"event": ..., "data": ...} and answers with the decision for
+// the event: PreTradeDecision, PreNotifyDecision or, for post-trade, an
+// object whose "metadata" is merged into the trade event.
+type WebhookHook struct {
+	url        string
+	secret     string
+	events     map[HookEvent]bool
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewWebhookHook creates a webhook-backed hook.
+//
+// Parameters:
+//
+//	rawURL: Absolute http or https URL.
+//	secret: Secret for the X-NeuraTrade-Signature header (may be empty).
+//	events: Extension points the endpoint handles.
+//	timeout: Request timeout (0 uses the default).
+//
+// Returns:
+//
+//	*WebhookHook: Initialized hook.
+//	error: Error if the URL or an event is invalid.
+func NewWebhookHook(rawURL, secret string, events []HookEvent, timeout time.Duration) (*WebhookHook, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, ErrWebhookInvalidURL
+	}
+	if timeout <= 0 {
+		timeout = defaultHookTimeout
+	}
+	subscribed := make(map[HookEvent]bool, len(events))
+	for _, event := range events {
+		switch event {
+		case HookPreTrade, HookPostTrade, HookPreNotify:
+			subscribed[event] = true
+		default:
+			return nil, fmt.Errorf("unknown hook event %q", event)
+		}
+	}
+	return &WebhookHook{
+		url:        rawURL,
+		secret:     secret,
+		events:     subscribed,
+		httpClient: &http.Client{Timeout: timeout},
+		now:        time.Now,
+	}, nil
+}
+
+// Handles reports whether the endpoint is subscribed to an extension point.
+func (w *WebhookHook) Handles(event HookEvent) bool {
+	return w.events[event]
+}
+
+// PreTrade asks the endpoint whether to veto or modify an order.
+func (w *WebhookHook) PreTrade(ctx context.Context, order HookOrder) (PreTradeDecision, error) {
+	var decision PreTradeDecision
+	if !w.Handles(HookPreTrade) {
+		return decision, nil
+	}
+	err := w.call(ctx, HookPreTrade, order, &decision)
+	return decision, err
+}
+
+// PostTrade sends an order attempt and returns the endpoint's metadata.
+func (w *WebhookHook) PostTrade(ctx context.Context, event PostTradeEvent) (map[string]interface{}, error) {
+	if !w.Handles(HookPostTrade) {
+		return nil, nil
+	}
+	var response struct {
+		Metadata map[string]interface{} `json:"metadata"`
+	}
+	err := w.call(ctx, HookPostTrade, event, &response)
+	return response.Metadata, err
+}
+
+// PreNotify asks the endpoint whether to rewrite or suppress a message.
+func (w *WebhookHook) PreNotify(ctx context.Context, msg HookNotification) (PreNotifyDecision, error) {
+	var decision PreNotifyDecision
+	if !w.Handles(HookPreNotify) {
+		return decision, nil
+	}
+	err := w.call(ctx, HookPreNotify, msg, &decision)
+	return decision, err
+}
+
+func (w *WebhookHook) call(ctx context.Context, event HookEvent, data interface{}, out interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{"event": event, "data": data})
+	if err != nil {
+		return fmt.Errorf("failed to encode hook request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create hook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HookEventHeader, string(event))
+	if w.secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(w.secret, w.now().Unix(), body))
+	}
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("hook request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read hook response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("hook returned status %d", resp.StatusCode)
+	}
+	// An empty 2xx response means "no change"
+	if len(bytes.TrimSpace(respBody)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to decode hook response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type funcHook struct {
+	preTrade  func(HookOrder) (PreTradeDecision, error)
+	postTrade func(PostTradeEvent) (map[string]interface{}, error)
+	preNotify func(HookNotification) (PreNotifyDecision, error)
+}
+
+func (h *funcHook) PreTrade(_ context.Context, order HookOrder) (PreTradeDecision, error) {
+	if h.preTrade == nil {
+		return PreTradeDecision{}, nil
+	}
+	return h.preTrade(order)
+}
+
+func (h *funcHook) PostTrade(_ context.Context, event PostTradeEvent) (map[string]interface{}, error) {
+	if h.postTrade == nil {
+		return nil, nil
+	}
+	return h.postTrade(event)
+}
+
+func (h *funcHook) PreNotify(_ context.Context, msg HookNotification) (PreNotifyDecision, error) {
+	if h.preNotify == nil {
+		return PreNotifyDecision{}, nil
+	}
+	return h.preNotify(msg)
+}
+
+type preTradeOnly struct{}
+
+func (preTradeOnly) PreTrade(context.Context, HookOrder) (PreTradeDecision, error) {
+	return PreTradeDecision{Veto: true, Reason: "closed"}, nil
+}
+
+func testHookOrder() HookOrder {
+	return HookOrder{Exchange: "binance", Symbol: "BTC/USDT", Side: "buy", OrderType: "market", Amount: decimal.NewFromFloat(0.5)}
+}
+
+func TestHookRegistry_Register(t *testing.T) {
+	registry := NewHookRegistry(HookConfig{})
+	assert.Error(t, registry.Register("nothing", struct{}{}))
+	require.NoError(t, registry.Register("gate", preTradeOnly{}))
+	assert.Equal(t, 1, registry.Len())
+
+	// A pre-trade-only hook is skipped at the other extension points
+	_, send := registry.RunPreNotify(t.Context(), HookNotification{ChatID: 1, Text: "hi"})
+	assert.True(t, send)
+	_, err := registry.RunPreTrade(t.Context(), testHookOrder())
+	assert.ErrorIs(t, err, ErrTradeVetoed)
+	assert.Contains(t, err.Error(), "closed")
+}
+
+func TestHookRegistry_RunPreTrade(t *testing.T) {
+	halve := &funcHook{preTrade: func(order HookOrder) (PreTradeDecision, error) {
+		order.Amount = order.Amount.Div(decimal.NewFromInt(2))
+		return PreTradeDecision{Order: &order, Reason: "halve size"}, nil
+	}}
+	registry := NewHookRegistry(HookConfig{})
+	require.NoError(t, registry.Register("halve", halve))
+	require.NoError(t, registry.Register("halve-again", halve))
+
+	order, err := registry.RunPreTrade(t.Context(), testHookOrder())
+	require.NoError(t, err)
+	assert.True(t, order.Amount.Equal(decimal.NewFromFloat(0.125)), "each hook sees the previous hook's order")
+
+	// Hooks cannot grow an order or redirect it
+	for name, modify := range map[string]func(*HookOrder){
+		"bigger":   func(o *HookOrder) { o.Amount = decimal.NewFromInt(10) },
+		"redirect": func(o *HookOrder) { o.Symbol = "ETH/USDT" },
+		"flip":     func(o *HookOrder) { o.Side = "sell" },
+		"zero":     func(o *HookOrder) { o.Amount = decimal.Zero },
+	} {
+		modify := modify
+		registry := NewHookRegistry(HookConfig{})
+		require.NoError(t, registry.Register(name, &funcHook{preTrade: func(order HookOrder) (PreTradeDecision, error) {
+			modify(&order)
+			return PreTradeDecision{Order: &order}, nil
+		}}))
+		_, err := registry.RunPreTrade(t.Context(), testHookOrder())
+		assert.ErrorIs(t, err, ErrTradeVetoed, name)
+	}
+
+	var nilRegistry *HookRegistry
+	order, err = nilRegistry.RunPreTrade(t.Context(), testHookOrder())
+	require.NoError(t, err)
+	assert.Equal(t, testHookOrder(), order)
+}
+
+func TestHookRegistry_PreTradeFailurePolicy(t *testing.T) {
+	failing := &funcHook{preTrade: func(HookOrder) (PreTradeDecision, error) {
+		return PreTradeDecision{}, errors.New("hook down")
+	}}
+
+	closed := NewHookRegistry(HookConfig{})
+	require.NoError(t, closed.Register("risk-service", failing))
+	_, err := closed.RunPreTrade(t.Context(), testHookOrder())
+	assert.ErrorIs(t, err, ErrTradeVetoed)
+
+	open := NewHookRegistry(HookConfig{FailOpen: true})
+	require.NoError(t, open.Register("risk-service", failing))
+	_, err = open.RunPreTrade(t.Context(), testHookOrder())
+	assert.NoError(t, err)
+}
+
+func TestHookRegistry_RunPostTradeAndPreNotify(t *testing.T) {
+	registry := NewHookRegistry(HookConfig{})
+	require.NoError(t, registry.Register("tagger", &funcHook{
+		postTrade: func(event PostTradeEvent) (map[string]interface{}, error) {
+			return map[string]interface{}{"strategy": "scalping", "order": event.OrderID}, nil
+		},
+		preNotify: func(msg HookNotification) (PreNotifyDecision, error) {
+			text := "[prod] " + msg.Text
+			return PreNotifyDecision{Text: &text}, nil
+		},
+	}))
+	require.NoError(t, registry.Register("broken", &funcHook{
+		postTrade: func(PostTradeEvent) (map[string]interface{}, error) { return nil, errors.New("boom") },
+		preNotify: func(msg HookNotification) (PreNotifyDecision, error) {
+			return PreNotifyDecision{Suppress: msg.ChatID == 42, Reason: "muted"}, nil
+		},
+	}))
+
+	event := registry.RunPostTrade(t.Context(), PostTradeEvent{Order: testHookOrder(), OrderID: "o-1"})
+	assert.Equal(t, map[string]interface{}{"strategy": "scalping", "order": "o-1"}, event.Metadata)
+
+	msg, send := registry.RunPreNotify(t.Context(), HookNotification{ChatID: 7, Text: "filled"})
+	assert.True(t, send)
+	assert.Equal(t, "[prod] filled", msg.Text)
+
+	_, send = registry.RunPreNotify(t.Context(), HookNotification{ChatID: 42, Text: "filled"})
+	assert.False(t, send)
+}
+
+func TestWebhookHook(t *testing.T) {
+	var mu sync.Mutex
+	var requests []map[string]interface{}
+	var signature, hookEvent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var payload map[string]interface{}
+		_ = json.Unmarshal(body, &payload)
+		mu.Lock()
+		requests = append(requests, payload)
+		signature, hookEvent = r.Header.Get(WebhookSignatureHeader), r.Header.Get(HookEventHeader)
+		mu.Unlock()
+
+		switch HookEvent(payload["event"].(string)) {
+		case HookPreTrade:
+			_, _ = w.Write([]byte(`{"veto":true,"reason":"outside trading hours"}`))
+		case HookPostTrade:
+			_, _ = w.Write([]byte(`{"metadata":{"desk":"alpha"}}`))
+		}
+	}))
+	defer server.Close()
+
+	hook, err := NewWebhookHook(server.URL, "s3cret", []HookEvent{HookPreTrade, HookPostTrade}, 0)
+	require.NoError(t, err)
+
+	decision, err := hook.PreTrade(t.Context(), testHookOrder())
+	require.NoError(t, err)
+	assert.True(t, decision.Veto)
+	assert.Equal(t, "outside trading hours", decision.Reason)
+	assert.Contains(t, signature, "v1=")
+	assert.Equal(t, string(HookPreTrade), hookEvent)
+
+	metadata, err := hook.PostTrade(t.Context(), PostTradeEvent{Order: testHookOrder()})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"desk": "alpha"}, metadata)
+
+	// Unsubscribed extension points make no request
+	notify, err := hook.PreNotify(t.Context(), HookNotification{ChatID: 1, Text: "hi"})
+	require.NoError(t, err)
+	assert.False(t, notify.Suppress)
+	mu.Lock()
+	assert.Len(t, requests, 2)
+	mu.Unlock()
+
+	_, err = NewWebhookHook("ftp://example.com", "", []HookEvent{HookPreTrade}, 0)
+	assert.ErrorIs(t, err, ErrWebhookInvalidURL)
+	_, err = NewWebhookHook(server.URL, "", []HookEvent{"on_login"}, 0)
+	assert.Error(t, err)
+}
+
+type capturedHookEvent struct {
+	event WebhookEventType
+	data  interface{}
+}
+
+type hookEventCapture struct {
+	mu     sync.Mutex
+	events []capturedHookEvent
+}
+
+func (c *hookEventCapture) Emit(_ context.Context, event WebhookEventType, data interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.events = append(c.events, capturedHookEvent{event: event, data: data})
+}
+
+func TestHookedOrderExecutor_PlaceOrder(t *testing.T) {
+	var orders []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		orders = append(orders, body)
+		_, _ = w.Write([]byte(`{"order":{"id":"ord-1"}}`))
+	}))
+	defer server.Close()
+
+	registry := NewHookRegistry(HookConfig{})
+	require.NoError(t, registry.Register("sizer", &funcHook{
+		preTrade: func(order HookOrder) (PreTradeDecision, error) {
+			if order.Symbol == "DOGE/USDT" {
+				return PreTradeDecision{Veto: true, Reason: "not allowed"}, nil
+			}
+			order.Amount = decimal.NewFromFloat(0.1)
+			return PreTradeDecision{Order: &order}, nil
+		},
+		postTrade: func(event PostTradeEvent) (map[string]interface{}, error) {
+			return map[string]interface{}{"tagged": true}, nil
+		},
+	}))
+	executor := NewHookedOrderExecutor(NewCCXTOrderExecutor(CCXTOrderExecutorConfig{ServiceURL: server.URL}), registry)
+	events := &hookEventCapture{}
+	executor.SetEventEmitter(events)
+
+	orderID, err := executor.PlaceOrder(t.Context(), "binance", "BTC/USDT", "buy", "market", decimal.NewFromFloat(0.5), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ord-1", orderID)
+	require.Len(t, orders, 1)
+	assert.InDelta(t, 0.1, orders[0]["amount"], 1e-9, "the modified order is placed")
+
+	require.Len(t, events.events, 1)
+	assert.Equal(t, WebhookEventTradeExecuted, events.events[0].event)
+	event := events.events[0].data.(PostTradeEvent)
+	assert.Equal(t, "ord-1", event.OrderID)
+	assert.Equal(t, true, event.Metadata["tagged"])
+
+	_, err = executor.PlaceOrder(t.Context(), "binance", "DOGE/USDT", "buy", "market", decimal.NewFromInt(100), nil)
+	assert.ErrorIs(t, err, ErrTradeVetoed)
+	assert.Len(t, orders, 1, "vetoed orders never reach the exchange")
+}
+
+func TestNotificationService_PreNotifyHooks(t *testing.T) {
+	var texts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		texts = append(texts, body["text"].(string))
+		_, _ = w.Write([]byte(`{"ok":true,"messageId":"1"}`))
+	}))
+	defer server.Close()
+
+	registry := NewHookRegistry(HookConfig{})
+	require.NoError(t, registry.Register("rewriter", &funcHook{preNotify: func(msg HookNotification) (PreNotifyDecision, error) {
+		if msg.ChatID == 42 {
+			return PreNotifyDecision{Suppress: true}, nil
+		}
+		text := msg.Text + " (via hook)"
+		return PreNotifyDecision{Text: &text}, nil
+	}}))
+	ns := NewNotificationService(nil, nil, server.URL, "", "")
+	ns.SetHooks(registry)
+
+	require.NoError(t, ns.SendDirectMessage(t.Context(), 7, "hello"))
+	require.NoError(t, ns.SendDirectMessage(t.Context(), 42, "muted"))
+	assert.Equal(t, []string{"hello (via hook)"}, texts)
+}
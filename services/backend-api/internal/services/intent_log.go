@@ -0,0 +1,364 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	intentLogKey       = "autonomous:intents"
+	intentLogRecentKey = "autonomous:intents:recent"
+
+	intentLogRecentLimit = 200
+)
+
+// IntentStatus is the lifecycle state of a decision's execution plan.
+type IntentStatus string
+
+const (
+	// IntentExecuting plans are being placed.
+	IntentExecuting IntentStatus = "executing"
+	// IntentCompleted plans placed every order they meant to.
+	IntentCompleted IntentStatus = "completed"
+	// IntentFailed plans stopped on an order error.
+	IntentFailed IntentStatus = "failed"
+	// IntentReconciled plans were interrupted by a restart and settled
+	// against the exchange on startup.
+	IntentReconciled IntentStatus = "reconciled"
+)
+
+// Intent order states.
+const (
+	// IntentOrderPending orders may or may not have reached the exchange.
+	IntentOrderPending = "pending"
+	IntentOrderPlaced  = "placed"
+	IntentOrderFailed  = "failed"
+	// IntentOrderAbandoned orders never reached the exchange and are not
+	// retried after a restart; the strategy decides afresh.
+	IntentOrderAbandoned = "abandoned"
+	// IntentOrderCancelled orders were resting when their plan was found
+	// incomplete on startup and were cancelled so they are not orphaned.
+	IntentOrderCancelled = "cancelled"
+)
+
+// IntentOrder is one planned order of a decision.
+type IntentOrder struct {
+	Exchange  string           `json:"exchange"`
+	Symbol    string           `json:"symbol"`
+	Side      string           `json:"side"`
+	OrderType string           `json:"order_type"`
+	Amount    decimal.Decimal  `json:"amount"`
+	Price     *decimal.Decimal `json:"price,omitempty"`
+	// ClientOrderID is sent with the order so it can be found on the
+	// exchange when the process died before the order was acknowledged.
+	ClientOrderID string `json:"client_order_id"`
+	OrderID       string `json:"order_id,omitempty"`
+	Status        string `json:"status"`
+	Error         string `json:"error,omitempty"`
+}
+
+// DecisionIntent is the durable execution plan of one trading decision.
+type DecisionIntent struct {
+	ID         string        `json:"id"`
+	Strategy   string        `json:"strategy"`
+	QuestID    string        `json:"quest_id,omitempty"`
+	DecisionID string        `json:"decision_id,omitempty"`
+	Status     IntentStatus  `json:"status"`
+	Orders     []IntentOrder `json:"orders"`
+	// Note summarizes what startup reconciliation did.
+	Note      string    `json:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IntentRecorder records decisions' planned and executed orders.
+type IntentRecorder interface {
+	Begin(ctx context.Context, intent *DecisionIntent) error
+	RecordOrder(ctx context.Context, intent *DecisionIntent, leg int, orderID string, err error) error
+	Finish(ctx context.Context, intent *DecisionIntent) error
+}
+
+// IntentOrderReader finds and cancels orders during reconciliation.
+type IntentOrderReader interface {
+	GetOpenOrders(ctx context.Context, exchange, symbol string) ([]map[string]interface{}, error)
+	GetClosedOrders(ctx context.Context, exchange, symbol string, limit int) ([]map[string]interface{}, error)
+	CancelOrder(ctx context.Context, exchange, orderID string) error
+}
+
+// IntentLog is a durable log of decisions' execution plans. Each plan is
+// written before its first order and updated after every order, so a restart
+// mid-cycle can tell which orders reached the exchange. Reconcile settles
+// interrupted plans on startup before the quest engine resumes.
+type IntentLog struct {
+	redis  *redis.Client
+	logger *slog.Logger
+	now    func() time.Time
+	mu     sync.Mutex
+}
+
+// Ensure IntentLog implements IntentRecorder.
+var _ IntentRecorder = (*IntentLog)(nil)
+
+// NewIntentLog creates the decision intent log.
+//
+// Parameters:
+//
+//	client: Redis client used to persist plans.
+//
+// Returns:
+//
+//	*IntentLog: Initialized log.
+func NewIntentLog(client *redis.Client) *IntentLog {
+	return &IntentLog{redis: client, logger: telemetry.Logger(), now: time.Now}
+}
+
+// Begin persists a new plan. Every order is pending and gets a client order
+// ID derived from the plan ID.
+func (l *IntentLog) Begin(ctx context.Context, intent *DecisionIntent) error {
+	now := l.now().UTC()
+	intent.ID = uuid.NewString()
+	intent.Status = IntentExecuting
+	intent.CreatedAt, intent.UpdatedAt = now, now
+	prefix := "nt" + strings.ReplaceAll(intent.ID, "-", "")[:16]
+	for i := range intent.Orders {
+		intent.Orders[i].ClientOrderID = fmt.Sprintf("%s%d", prefix, i)
+		intent.Orders[i].Status = IntentOrderPending
+	}
+	return l.save(ctx, intent)
+}
+
+// RecordOrder records the outcome of placing one of the plan's orders.
+func (l *IntentLog) RecordOrder(ctx context.Context, intent *DecisionIntent, leg int, orderID string, err error) error {
+	if leg < 0 || leg >= len(intent.Orders) {
+		return fmt.Errorf("intent %s has no order %d", intent.ID, leg)
+	}
+	order := &intent.Orders[leg]
+	order.OrderID = orderID
+	order.Status = IntentOrderPlaced
+	if err != nil {
+		order.Status, order.Error = IntentOrderFailed, err.Error()
+	}
+	intent.UpdatedAt = l.now().UTC()
+	return l.save(ctx, intent)
+}
+
+// Finish closes a plan: completed when every order was placed, failed
+// otherwise. Orders never attempted are marked abandoned.
+func (l *IntentLog) Finish(ctx context.Context, intent *DecisionIntent) error {
+	intent.Status = IntentCompleted
+	for i := range intent.Orders {
+		switch intent.Orders[i].Status {
+		case IntentOrderPending:
+			intent.Orders[i].Status = IntentOrderAbandoned
+			intent.Status = IntentFailed
+		case IntentOrderFailed:
+			intent.Status = IntentFailed
+		}
+	}
+	intent.UpdatedAt = l.now().UTC()
+	return l.archive(ctx, intent)
+}
+
+// Active returns plans that have not finished, oldest first.
+func (l *IntentLog) Active(ctx context.Context) ([]DecisionIntent, error) {
+	entries, err := l.redis.HGetAll(ctx, intentLogKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load intents: %w", err)
+	}
+	intents := make([]DecisionIntent, 0, len(entries))
+	for id, raw := range entries {
+		var intent DecisionIntent
+		if err := json.Unmarshal([]byte(raw), &intent); err != nil {
+			l.logger.Warn("Dropping unreadable intent", "id", id, "error", err)
+			continue
+		}
+		intents = append(intents, intent)
+	}
+	sort.Slice(intents, func(i, j int) bool { return intents[i].CreatedAt.Before(intents[j].CreatedAt) })
+	return intents, nil
+}
+
+// Recent returns the most recently finished plans, newest first.
+func (l *IntentLog) Recent(ctx context.Context, limit int) ([]DecisionIntent, error) {
+	if limit <= 0 || limit > intentLogRecentLimit {
+		limit = intentLogRecentLimit
+	}
+	entries, err := l.redis.LRange(ctx, intentLogRecentKey, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recent intents: %w", err)
+	}
+	intents := make([]DecisionIntent, 0, len(entries))
+	for _, raw := range entries {
+		var intent DecisionIntent
+		if err := json.Unmarshal([]byte(raw), &intent); err == nil {
+			intents = append(intents, intent)
+		}
+	}
+	return intents, nil
+}
+
+// Reconcile settles plans interrupted by a restart. Pending orders are looked
+// up on the exchange by client order ID: found ones count as placed, the rest
+// never reached the exchange and are abandoned rather than retried. When a
+// plan is incomplete, its orders still resting on the exchange are cancelled
+// so they are not orphaned. Call it before the quest engine starts.
+//
+// Parameters:
+//
+//	ctx: Context for exchange and Redis calls.
+//	orders: Exchange order reader used for lookups and cancellation.
+//
+// Returns:
+//
+//	[]DecisionIntent: The reconciled plans.
+//	error: Error if the plans could not be loaded.
+func (l *IntentLog) Reconcile(ctx context.Context, orders IntentOrderReader) ([]DecisionIntent, error) {
+	intents, err := l.Active(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range intents {
+		intent := &intents[i]
+		l.reconcileIntent(ctx, orders, intent)
+		if err := l.archive(ctx, intent); err != nil {
+			l.logger.Warn("Failed to archive reconciled intent", "id", intent.ID, "error", err)
+		}
+		l.logger.Warn("Reconciled interrupted decision", "id", intent.ID, "strategy", intent.Strategy, "note", intent.Note)
+	}
+	return intents, nil
+}
+
+func (l *IntentLog) reconcileIntent(ctx context.Context, orders IntentOrderReader, intent *DecisionIntent) {
+	open := map[string][]map[string]interface{}{}
+	openOrders := func(exchange, symbol string) []map[string]interface{} {
+		key := exchange + "|" + symbol
+		if list, ok := open[key]; ok {
+			return list
+		}
+		list, err := orders.GetOpenOrders(ctx, exchange, symbol)
+		if err != nil {
+			l.logger.Warn("Failed to fetch open orders for reconciliation", "exchange", exchange, "symbol", symbol, "error", err)
+		}
+		open[key] = list
+		return list
+	}
+
+	placed, abandoned, unknown := 0, 0, 0
+	for i := range intent.Orders {
+		order := &intent.Orders[i]
+		switch order.Status {
+		case IntentOrderPlaced:
+			placed++
+			continue
+		case IntentOrderFailed, IntentOrderAbandoned:
+			abandoned++
+			continue
+		}
+		found := findClientOrder(openOrders(order.Exchange, order.Symbol), order.ClientOrderID)
+		if found == nil {
+			closed, err := orders.GetClosedOrders(ctx, order.Exchange, order.Symbol, 50)
+			if err != nil {
+				// Without both lists the order's fate is unknown, so it is
+				// left for the operator rather than assumed missing
+				order.Error = "lookup failed: " + err.Error()
+				unknown++
+				continue
+			}
+			found = findClientOrder(closed, order.ClientOrderID)
+		}
+		if found == nil {
+			order.Status = IntentOrderAbandoned
+			abandoned++
+			continue
+		}
+		order.OrderID, _ = found["id"].(string)
+		order.Status = IntentOrderPlaced
+		placed++
+	}
+
+	cancelled := 0
+	if abandoned > 0 || unknown > 0 {
+		for i := range intent.Orders {
+			order := &intent.Orders[i]
+			if order.Status != IntentOrderPlaced || order.OrderID == "" {
+				continue
+			}
+			if !hasOrderID(openOrders(order.Exchange, order.Symbol), order.OrderID) {
+				continue
+			}
+			if err := orders.CancelOrder(ctx, order.Exchange, order.OrderID); err != nil {
+				order.Error = "cancel failed: " + err.Error()
+				continue
+			}
+			order.Status = IntentOrderCancelled
+			cancelled++
+		}
+	}
+
+	intent.Status = IntentReconciled
+	intent.UpdatedAt = l.now().UTC()
+	intent.Note = fmt.Sprintf("%d of %d orders placed, %d abandoned, %d resting orders cancelled", placed, len(intent.Orders), abandoned, cancelled)
+	if unknown > 0 {
+		intent.Note += fmt.Sprintf(", %d unknown (check the exchange)", unknown)
+	}
+}
+
+func findClientOrder(orders []map[string]interface{}, clientOrderID string) map[string]interface{} {
+	for _, order := range orders {
+		if id, _ := order["clientOrderId"].(string); id != "" && id == clientOrderID {
+			return order
+		}
+	}
+	return nil
+}
+
+func hasOrderID(orders []map[string]interface{}, orderID string) bool {
+	for _, order := range orders {
+		if id, _ := order["id"].(string); id == orderID {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *IntentLog) save(ctx context.Context, intent *DecisionIntent) error {
+	raw, err := json.Marshal(intent)
+	if err != nil {
+		return fmt.Errorf("failed to encode intent: %w", err)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.redis.HSet(ctx, intentLogKey, intent.ID, raw).Err(); err != nil {
+		return fmt.Errorf("failed to save intent: %w", err)
+	}
+	return nil
+}
+
+// archive moves a finished plan to the capped recent list.
+func (l *IntentLog) archive(ctx context.Context, intent *DecisionIntent) error {
+	raw, err := json.Marshal(intent)
+	if err != nil {
+		return fmt.Errorf("failed to encode intent: %w", err)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	pipe := l.redis.TxPipeline()
+	pipe.LPush(ctx, intentLogRecentKey, raw)
+	pipe.LTrim(ctx, intentLogRecentKey, 0, intentLogRecentLimit-1)
+	pipe.HDel(ctx, intentLogKey, intent.ID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to archive intent: %w", err)
+	}
+	return nil
+}
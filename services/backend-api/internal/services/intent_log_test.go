@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestIntentLog(t *testing.T) *IntentLog {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	intents := NewIntentLog(client)
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	intents.now = func() time.Time { return now }
+	return intents
+}
+
+func newTestArbitrageIntent() *DecisionIntent {
+	return &DecisionIntent{
+		Strategy: StrategyArbitrage,
+		QuestID:  "quest-1",
+		Orders: []IntentOrder{
+			{Exchange: "binance", Symbol: "BTC/USDT", Side: "buy", OrderType: "limit", Amount: decimal.NewFromInt(1)},
+			{Exchange: "bybit", Symbol: "BTC/USDT", Side: "sell", OrderType: "limit", Amount: decimal.NewFromInt(1)},
+		},
+	}
+}
+
+// intentTestExchange holds open and closed orders and records cancellations.
+type intentTestExchange struct {
+	open      map[string][]map[string]interface{}
+	closed    map[string][]map[string]interface{}
+	closedErr error
+	cancelled []string
+}
+
+func (e *intentTestExchange) GetOpenOrders(_ context.Context, exchange, _ string) ([]map[string]interface{}, error) {
+	return e.open[exchange], nil
+}
+
+func (e *intentTestExchange) GetClosedOrders(_ context.Context, exchange, _ string, _ int) ([]map[string]interface{}, error) {
+	return e.closed[exchange], e.closedErr
+}
+
+func (e *intentTestExchange) CancelOrder(_ context.Context, _, orderID string) error {
+	e.cancelled = append(e.cancelled, orderID)
+	return nil
+}
+
+func TestIntentLog_Lifecycle(t *testing.T) {
+	intents := newTestIntentLog(t)
+	ctx := t.Context()
+	intent := newTestArbitrageIntent()
+
+	require.NoError(t, intents.Begin(ctx, intent))
+	assert.Equal(t, IntentExecuting, intent.Status)
+	assert.NotEqual(t, intent.Orders[0].ClientOrderID, intent.Orders[1].ClientOrderID)
+	assert.LessOrEqual(t, len(intent.Orders[0].ClientOrderID), 32, "client order IDs fit exchange limits")
+
+	active, err := intents.Active(ctx)
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	assert.Equal(t, IntentOrderPending, active[0].Orders[0].Status)
+
+	require.NoError(t, intents.RecordOrder(ctx, intent, 0, "buy-1", nil))
+	require.NoError(t, intents.RecordOrder(ctx, intent, 1, "sell-1", nil))
+	assert.Error(t, intents.RecordOrder(ctx, intent, 2, "", nil))
+	require.NoError(t, intents.Finish(ctx, intent))
+	assert.Equal(t, IntentCompleted, intent.Status)
+
+	active, err = intents.Active(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, active)
+	recent, err := intents.Recent(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, recent, 1)
+	assert.Equal(t, "sell-1", recent[0].Orders[1].OrderID)
+}
+
+func TestIntentLog_FinishMarksUnplacedOrders(t *testing.T) {
+	intents := newTestIntentLog(t)
+	ctx := t.Context()
+	intent := newTestArbitrageIntent()
+	require.NoError(t, intents.Begin(ctx, intent))
+
+	require.NoError(t, intents.RecordOrder(ctx, intent, 0, "", errors.New("insufficient balance")))
+	require.NoError(t, intents.Finish(ctx, intent))
+	assert.Equal(t, IntentFailed, intent.Status)
+	assert.Equal(t, IntentOrderFailed, intent.Orders[0].Status)
+	assert.Equal(t, "insufficient balance", intent.Orders[0].Error)
+	assert.Equal(t, IntentOrderAbandoned, intent.Orders[1].Status)
+}
+
+func TestIntentLog_ReconcileCancelsOrphanedLeg(t *testing.T) {
+	intents := newTestIntentLog(t)
+	ctx := t.Context()
+	intent := newTestArbitrageIntent()
+	require.NoError(t, intents.Begin(ctx, intent))
+	// The process died after the buy reached the exchange but before its
+	// acknowledgement was recorded, and before the sell was sent
+	exchange := &intentTestExchange{open: map[string][]map[string]interface{}{
+		"binance": {{"id": "buy-1", "clientOrderId": intent.Orders[0].ClientOrderID}},
+	}}
+
+	reconciled, err := intents.Reconcile(ctx, exchange)
+	require.NoError(t, err)
+	require.Len(t, reconciled, 1)
+	got := reconciled[0]
+	assert.Equal(t, IntentReconciled, got.Status)
+	assert.Equal(t, "buy-1", got.Orders[0].OrderID)
+	assert.Equal(t, IntentOrderCancelled, got.Orders[0].Status)
+	assert.Equal(t, IntentOrderAbandoned, got.Orders[1].Status)
+	assert.Equal(t, []string{"buy-1"}, exchange.cancelled)
+	assert.Equal(t, "1 of 2 orders placed, 1 abandoned, 1 resting orders cancelled", got.Note)
+
+	active, err := intents.Active(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, active, "reconciled plans are not replayed twice")
+}
+
+func TestIntentLog_ReconcileCompletePlan(t *testing.T) {
+	intents := newTestIntentLog(t)
+	ctx := t.Context()
+	intent := newTestArbitrageIntent()
+	require.NoError(t, intents.Begin(ctx, intent))
+	require.NoError(t, intents.RecordOrder(ctx, intent, 0, "buy-1", nil))
+	exchange := &intentTestExchange{
+		open: map[string][]map[string]interface{}{"binance": {{"id": "buy-1"}}},
+		closed: map[string][]map[string]interface{}{
+			"bybit": {{"id": "sell-1", "clientOrderId": intent.Orders[1].ClientOrderID}},
+		},
+	}
+
+	reconciled, err := intents.Reconcile(ctx, exchange)
+	require.NoError(t, err)
+	require.Len(t, reconciled, 1)
+	assert.Equal(t, "sell-1", reconciled[0].Orders[1].OrderID)
+	assert.Empty(t, exchange.cancelled, "a complete plan keeps its resting orders")
+	assert.Equal(t, "2 of 2 orders placed, 0 abandoned, 0 resting orders cancelled", reconciled[0].Note)
+}
+
+func TestIntentLog_ReconcileUnknownOrder(t *testing.T) {
+	intents := newTestIntentLog(t)
+	ctx := t.Context()
+	intent := newTestArbitrageIntent()
+	intent.Orders = intent.Orders[:1]
+	require.NoError(t, intents.Begin(ctx, intent))
+	exchange := &intentTestExchange{closedErr: errors.New("timeout")}
+
+	reconciled, err := intents.Reconcile(ctx, exchange)
+	require.NoError(t, err)
+	require.Len(t, reconciled, 1)
+	assert.Equal(t, IntentOrderPending, reconciled[0].Orders[0].Status, "an order that could not be looked up is not assumed missing")
+	assert.Contains(t, reconciled[0].Note, "1 unknown")
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,6 +18,9 @@ type Queue struct {
 	namespace  string
 	queues     map[Priority]string
 	deadLetter string
+	processing string
+	heartbeat  string
+	workerTTL  time.Duration
 }
 
 // Priority defines job priority levels.
@@ -39,6 +43,10 @@ type Job struct {
 	ScheduledFor *time.Time             `json:"scheduled_for,omitempty"`
 	Attempts     int                    `json:"attempts"`
 	MaxAttempts  int                    `json:"max_attempts"`
+
+	// raw is the entry as it sits in the processing list, used to
+	// acknowledge the job once it has been handled.
+	raw string
 }
 
 // JobResult represents the outcome of job processing.
@@ -55,6 +63,12 @@ type Handler func(ctx context.Context, job Job) error
 // Config defines queue configuration.
 type Config struct {
 	Namespace string
+	// WorkerID names the processing list holding this consumer's in-flight
+	// jobs. Defaults to a random ID.
+	WorkerID string
+	// WorkerTTL is how long a worker may go without polling before its
+	// in-flight jobs are treated as abandoned. Defaults to one minute.
+	WorkerTTL time.Duration
 }
 
 // New creates a new job queue.
@@ -63,6 +77,14 @@ func New(client *redis.Client, cfg Config) *Queue {
 	if ns == "" {
 		ns = "jobs"
 	}
+	workerID := cfg.WorkerID
+	if workerID == "" {
+		workerID = uuid.NewString()
+	}
+	workerTTL := cfg.WorkerTTL
+	if workerTTL <= 0 {
+		workerTTL = time.Minute
+	}
 
 	return &Queue{
 		client:    client,
@@ -74,6 +96,9 @@ func New(client *redis.Client, cfg Config) *Queue {
 			CRITICAL: fmt.Sprintf("%s:queue:critical", ns),
 		},
 		deadLetter: fmt.Sprintf("%s:deadletter", ns),
+		processing: fmt.Sprintf("%s:processing:%s", ns, workerID),
+		heartbeat:  fmt.Sprintf("%s:worker:%s", ns, workerID),
+		workerTTL:  workerTTL,
 	}
 }
 
@@ -135,12 +160,19 @@ func (q *Queue) EnqueueWithOptions(ctx context.Context, jobType string, payload
 	return &job, nil
 }
 
-// Dequeue retrieves the next job from the queue.
+// Dequeue retrieves the next job from the queue. The job is moved onto this
+// worker's processing list and stays there until it is acknowledged by
+// Complete, Fail, Reschedule or DeadLetter, so a crash in between does not
+// lose it (see RecoverStale).
 func (q *Queue) Dequeue(ctx context.Context) (*Job, error) {
 	if q.client == nil {
 		return nil, fmt.Errorf("redis client is nil")
 	}
 
+	if err := q.client.Set(ctx, q.heartbeat, time.Now().Unix(), q.workerTTL).Err(); err != nil {
+		return nil, fmt.Errorf("failed to record worker heartbeat: %w", err)
+	}
+
 	if err := q.processScheduled(ctx); err != nil {
 		return nil, err
 	}
@@ -148,7 +180,7 @@ func (q *Queue) Dequeue(ctx context.Context) (*Job, error) {
 	priorities := []Priority{CRITICAL, HIGH, NORMAL, LOW}
 	for _, priority := range priorities {
 		queueName := q.queues[priority]
-		result, err := q.client.RPop(ctx, queueName).Result()
+		result, err := q.client.LMove(ctx, queueName, q.processing, "RIGHT", "LEFT").Result()
 		if err == redis.Nil {
 			continue
 		}
@@ -158,10 +190,12 @@ func (q *Queue) Dequeue(ctx context.Context) (*Job, error) {
 
 		var job Job
 		if err := json.Unmarshal([]byte(result), &job); err != nil {
+			_ = q.client.LRem(ctx, q.processing, 1, result).Err()
 			return nil, fmt.Errorf("failed to unmarshal job: %w", err)
 		}
 
 		job.Attempts++
+		job.raw = result
 		return &job, nil
 	}
 
@@ -175,11 +209,25 @@ func (q *Queue) DequeueWithTimeout(ctx context.Context, timeout time.Duration) (
 	return q.Dequeue(ctx)
 }
 
-// Complete marks a job as completed.
+// Complete marks a job as completed, removing it from the processing list.
 func (q *Queue) Complete(ctx context.Context, job *Job) error {
+	if q.client == nil || job == nil || job.raw == "" {
+		return nil
+	}
+	if err := q.client.LRem(ctx, q.processing, 1, job.raw).Err(); err != nil {
+		return fmt.Errorf("failed to acknowledge job: %w", err)
+	}
 	return nil
 }
 
+// ack queues the removal of a dequeued job from the processing list in the
+// same transaction that hands it on.
+func (q *Queue) ack(ctx context.Context, pipe redis.Pipeliner, job *Job) {
+	if job.raw != "" {
+		pipe.LRem(ctx, q.processing, 1, job.raw)
+	}
+}
+
 // Fail marks a job as failed and potentially moves it to dead letter queue.
 func (q *Queue) Fail(ctx context.Context, job *Job, err error) error {
 	if job.Attempts < job.MaxAttempts {
@@ -198,29 +246,94 @@ func (q *Queue) Fail(ctx context.Context, job *Job, err error) error {
 		}
 
 		queueName := q.queues[job.Priority]
-		if pushErr := q.client.RPush(ctx, queueName, data).Err(); pushErr != nil {
+		if _, pushErr := q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.RPush(ctx, queueName, data)
+			q.ack(ctx, pipe, job)
+			return nil
+		}); pushErr != nil {
 			return fmt.Errorf("failed to requeue job: %w", pushErr)
 		}
 
 		return nil
 	}
 
+	return q.DeadLetter(ctx, job, err)
+}
+
+// Reschedule puts a failed job back on the scheduled set so it becomes
+// eligible again after delay. Unlike Fail, the attempt counter is preserved
+// and the caller decides the backoff (e.g. honoring a provider RetryAfter).
+func (q *Queue) Reschedule(ctx context.Context, job *Job, err error, delay time.Duration) error {
+	if q.client == nil {
+		return fmt.Errorf("redis client is nil")
+	}
+
+	retryJob := *job
+	retryJob.Payload = make(map[string]interface{}, len(job.Payload)+1)
+	for k, v := range job.Payload {
+		retryJob.Payload[k] = v
+	}
+	if err != nil {
+		retryJob.Payload["_error"] = err.Error()
+	}
+
+	runAt := time.Now().Add(delay)
+	retryJob.ScheduledFor = &runAt
+
+	data, marshalErr := json.Marshal(retryJob)
+	if marshalErr != nil {
+		return fmt.Errorf("failed to marshal job for reschedule: %w", marshalErr)
+	}
+
+	scheduledQueue := fmt.Sprintf("%s:scheduled", q.namespace)
+	if _, zErr := q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, scheduledQueue, redis.Z{Score: float64(runAt.Unix()), Member: data})
+		q.ack(ctx, pipe, job)
+		return nil
+	}); zErr != nil {
+		return fmt.Errorf("failed to reschedule job: %w", zErr)
+	}
+
+	return nil
+}
+
+// DeadLetter moves a job straight to the dead letter queue regardless of
+// its remaining attempts.
+func (q *Queue) DeadLetter(ctx context.Context, job *Job, err error) error {
+	if q.client == nil {
+		return fmt.Errorf("redis client is nil")
+	}
+
+	errMsg := ""
+	if err != nil {
+		errMsg = err.Error()
+	}
+
 	data, marshalErr := json.Marshal(map[string]interface{}{
 		"job":       job,
-		"error":     err.Error(),
+		"error":     errMsg,
 		"failed_at": time.Now(),
 	})
 	if marshalErr != nil {
 		return fmt.Errorf("failed to marshal dead letter: %w", marshalErr)
 	}
 
-	if pushErr := q.client.LPush(ctx, q.deadLetter, data).Err(); pushErr != nil {
+	if _, pushErr := q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LPush(ctx, q.deadLetter, data)
+		q.ack(ctx, pipe, job)
+		return nil
+	}); pushErr != nil {
 		return fmt.Errorf("failed to add to dead letter: %w", pushErr)
 	}
 
 	return nil
 }
 
+// GetScheduledDepth returns the number of jobs waiting for their scheduled time.
+func (q *Queue) GetScheduledDepth(ctx context.Context) (int64, error) {
+	return q.client.ZCard(ctx, fmt.Sprintf("%s:scheduled", q.namespace)).Result()
+}
+
 // GetQueueDepth returns the number of jobs in each queue.
 func (q *Queue) GetQueueDepth(ctx context.Context) (map[Priority]int64, error) {
 	depths := make(map[Priority]int64)
@@ -299,6 +412,87 @@ func (q *Queue) RetryDeadLetter(ctx context.Context, index int64) error {
 	return nil
 }
 
+// RecoverStale puts jobs that were dequeued but never acknowledged back at
+// the front of their queues. It covers this worker's own processing list and
+// those of workers whose heartbeat has expired. Call it on startup, before
+// this worker dequeues anything.
+//
+// Returns:
+//
+//	int: The number of jobs requeued.
+//	error: Error if Redis could not be read or written.
+func (q *Queue) RecoverStale(ctx context.Context) (int, error) {
+	if q.client == nil {
+		return 0, fmt.Errorf("redis client is nil")
+	}
+
+	prefix := fmt.Sprintf("%s:processing:", q.namespace)
+	var lists []string
+	iter := q.client.Scan(ctx, 0, prefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		lists = append(lists, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return 0, fmt.Errorf("failed to list processing queues: %w", err)
+	}
+
+	recovered := 0
+	for _, list := range lists {
+		if list != q.processing {
+			heartbeat := fmt.Sprintf("%s:worker:%s", q.namespace, strings.TrimPrefix(list, prefix))
+			alive, err := q.client.Exists(ctx, heartbeat).Result()
+			if err != nil {
+				return recovered, fmt.Errorf("failed to check worker heartbeat: %w", err)
+			}
+			if alive > 0 {
+				continue
+			}
+		}
+
+		items, err := q.client.LRange(ctx, list, 0, -1).Result()
+		if err != nil {
+			return recovered, fmt.Errorf("failed to read processing queue: %w", err)
+		}
+		// Newest first, so the oldest job ends up next in line.
+		for _, item := range items {
+			queueName := q.queues[NORMAL]
+			var job Job
+			if err := json.Unmarshal([]byte(item), &job); err == nil {
+				if name, ok := q.queues[job.Priority]; ok {
+					queueName = name
+				}
+			}
+			moved, err := requeueScript.Run(ctx, q.client, []string{list, queueName}, item).Int()
+			if err != nil {
+				return recovered, fmt.Errorf("failed to requeue in-flight job: %w", err)
+			}
+			recovered += moved
+		}
+	}
+
+	return recovered, nil
+}
+
+// requeueScript moves an entry from a processing list back onto its queue
+// only if it is still there, so two recovering workers cannot both requeue it.
+var requeueScript = redis.NewScript(`
+if redis.call('LREM', KEYS[1], 1, ARGV[1]) == 1 then
+	redis.call('RPUSH', KEYS[2], ARGV[1])
+	return 1
+end
+return 0
+`)
+
+// promoteScript moves a due job from the scheduled set onto its queue only if
+// this caller removed it, so two pollers cannot enqueue the same job twice.
+var promoteScript = redis.NewScript(`
+if redis.call('ZREM', KEYS[1], ARGV[1]) == 1 then
+	redis.call('LPUSH', KEYS[2], ARGV[1])
+	return 1
+end
+return 0
+`)
+
 func (q *Queue) processScheduled(ctx context.Context) error {
 	scheduledQueue := fmt.Sprintf("%s:scheduled", q.namespace)
 	now := float64(time.Now().Unix())
@@ -318,11 +512,7 @@ func (q *Queue) processScheduled(ctx context.Context) error {
 		}
 
 		queueName := q.queues[job.Priority]
-		if err := q.client.LPush(ctx, queueName, item).Err(); err != nil {
-			continue
-		}
-
-		if err := q.client.ZRem(ctx, scheduledQueue, item).Err(); err != nil {
+		if err := promoteScript.Run(ctx, q.client, []string{scheduledQueue, queueName}, item).Err(); err != nil {
 			continue
 		}
 	}
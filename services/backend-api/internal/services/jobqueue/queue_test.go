@@ -243,3 +243,144 @@ func TestPriorityFromString(t *testing.T) {
 	assert.Equal(t, LOW, PriorityFromString("low"))
 	assert.Equal(t, NORMAL, PriorityFromString("unknown"))
 }
+
+func TestQueue_Reschedule(t *testing.T) {
+	s := miniredis.RunT(t)
+	defer s.Close()
+
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer func() { _ = client.Close() }()
+
+	queue := New(client, Config{Namespace: "test"})
+	ctx := t.Context()
+
+	job, err := queue.EnqueueWithOptions(ctx, "backoff-job", map[string]interface{}{"k": "v"}, HIGH, EnqueueOptions{MaxAttempts: 5})
+	require.NoError(t, err)
+
+	dequeued, err := queue.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, dequeued)
+
+	require.NoError(t, queue.Reschedule(ctx, dequeued, assert.AnError, time.Hour))
+
+	scheduled, err := queue.GetScheduledDepth(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), scheduled)
+
+	none, err := queue.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, none)
+
+	require.NoError(t, queue.Reschedule(ctx, dequeued, assert.AnError, -time.Second))
+	retried, err := queue.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, retried)
+	assert.Equal(t, job.ID, retried.ID)
+	assert.Equal(t, 2, retried.Attempts)
+	assert.Equal(t, "v", retried.Payload["k"])
+	assert.Equal(t, assert.AnError.Error(), retried.Payload["_error"])
+}
+
+func TestQueue_DeadLetterDirect(t *testing.T) {
+	s := miniredis.RunT(t)
+	defer s.Close()
+
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer func() { _ = client.Close() }()
+
+	queue := New(client, Config{Namespace: "test"})
+	ctx := t.Context()
+
+	_, err := queue.EnqueueWithOptions(ctx, "fatal-job", nil, NORMAL, EnqueueOptions{MaxAttempts: 5})
+	require.NoError(t, err)
+
+	dequeued, err := queue.Dequeue(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, queue.DeadLetter(ctx, dequeued, assert.AnError))
+
+	depth, err := queue.GetDeadLetterDepth(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), depth)
+}
+
+func TestQueue_AcknowledgeAndRecoverStale(t *testing.T) {
+	s := miniredis.RunT(t)
+	defer s.Close()
+
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer func() { _ = client.Close() }()
+
+	ctx := t.Context()
+	crashed := New(client, Config{Namespace: "test", WorkerID: "crashed", WorkerTTL: time.Second})
+	alive := New(client, Config{Namespace: "test", WorkerID: "alive"})
+
+	// An acknowledged job leaves the processing list
+	_, err := crashed.Enqueue(ctx, "done-job", nil, NORMAL)
+	require.NoError(t, err)
+	done, err := crashed.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), client.LLen(ctx, "test:processing:crashed").Val())
+	require.NoError(t, crashed.Complete(ctx, done))
+	assert.Equal(t, int64(0), client.LLen(ctx, "test:processing:crashed").Val())
+
+	// A job dequeued by a worker that dies before acknowledging it is kept
+	first, err := crashed.Enqueue(ctx, "lost-job", nil, HIGH)
+	require.NoError(t, err)
+	second, err := crashed.Enqueue(ctx, "lost-job", nil, HIGH)
+	require.NoError(t, err)
+	_, err = crashed.Dequeue(ctx)
+	require.NoError(t, err)
+	_, err = crashed.Dequeue(ctx)
+	require.NoError(t, err)
+	_, err = alive.Enqueue(ctx, "in-flight", nil, NORMAL)
+	require.NoError(t, err)
+	_, err = alive.Dequeue(ctx)
+	require.NoError(t, err)
+
+	// Nothing is recovered while the worker's heartbeat is alive
+	fresh := New(client, Config{Namespace: "test"})
+	recovered, err := fresh.RecoverStale(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, recovered)
+
+	s.FastForward(2 * time.Second)
+	recovered, err = fresh.RecoverStale(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, recovered)
+	assert.Equal(t, int64(1), client.LLen(ctx, "test:processing:alive").Val(), "a live worker keeps its jobs")
+
+	job, err := fresh.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, job.ID, "the oldest job is redelivered first")
+	job, err = fresh.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, job.ID)
+}
+
+func TestQueue_ScheduledPromotedOnce(t *testing.T) {
+	s := miniredis.RunT(t)
+	defer s.Close()
+
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer func() { _ = client.Close() }()
+
+	ctx := t.Context()
+	queue := New(client, Config{Namespace: "test"})
+	other := New(client, Config{Namespace: "test"})
+
+	past := time.Now().Add(-time.Second)
+	_, err := queue.EnqueueWithOptions(ctx, "scheduled-job", nil, NORMAL, EnqueueOptions{ScheduleFor: &past})
+	require.NoError(t, err)
+	item := client.ZRange(ctx, "test:scheduled", 0, -1).Val()[0]
+
+	require.NoError(t, queue.processScheduled(ctx))
+	// A second poller that read the same due entry must not push it again
+	moved, err := promoteScript.Run(ctx, client, []string{"test:scheduled", other.queues[NORMAL]}, item).Int()
+	require.NoError(t, err)
+	assert.Zero(t, moved)
+
+	depths, err := queue.GetQueueDepth(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), depths[NORMAL])
+}
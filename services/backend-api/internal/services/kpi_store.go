@@ -0,0 +1,327 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/jackc/pgx/v5"
+)
+
+// Internal KPIs recorded into the KPI store.
+const (
+	// KPIQuestCycleMs is the duration of one quest execution in milliseconds.
+	KPIQuestCycleMs = "quest_cycle_ms"
+	// KPIOpportunitiesFound is the number of arbitrage signals per aggregation.
+	KPIOpportunitiesFound = "opportunities_found"
+	// KPIOrdersPlaced counts orders the exchange accepted.
+	KPIOrdersPlaced = "orders_placed"
+	// KPIOrdersFailed counts orders the exchange or the CCXT service rejected.
+	KPIOrdersFailed = "orders_failed"
+	// KPINotificationLatencyMs is the time to hand a Telegram message to the
+	// Telegram service in milliseconds.
+	KPINotificationLatencyMs = "notification_latency_ms"
+)
+
+// KPIRecorder records samples of internal KPIs.
+type KPIRecorder interface {
+	RecordKPI(metric string, value float64)
+}
+
+// KPIStoreConfig configures the KPI time-series store.
+type KPIStoreConfig struct {
+	// Bucket is the resolution samples are aggregated to.
+	Bucket time.Duration
+	// FlushInterval is how often aggregated buckets are written.
+	FlushInterval time.Duration
+	// Retention is how long buckets are kept.
+	Retention time.Duration
+}
+
+// KPIPoint is one aggregated bucket of a KPI.
+type KPIPoint struct {
+	Time  time.Time `json:"time"`
+	Count int64     `json:"count"`
+	Sum   float64   `json:"sum"`
+	Min   float64   `json:"min"`
+	Max   float64   `json:"max"`
+	Avg   float64   `json:"avg"`
+}
+
+type kpiBucketKey struct {
+	metric string
+	start  time.Time
+}
+
+// KPIStore aggregates internal KPI samples in memory and persists them as
+// fixed-size buckets in the kpi_samples table, one row per metric and
+// bucket, for capacity planning.
+type KPIStore struct {
+	db      DBPool
+	config  KPIStoreConfig
+	logger  *slog.Logger
+	now     func() time.Time
+	mu      sync.Mutex
+	pending map[kpiBucketKey]*KPIPoint
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// Ensure KPIStore implements KPIRecorder.
+var _ KPIRecorder = (*KPIStore)(nil)
+
+// NewKPIStore creates the KPI time-series store.
+//
+// Parameters:
+//
+//	db: Database pool holding the kpi_samples table.
+//	config: Bucket size, flush interval and retention; zero values use
+//	one minute, one minute and 30 days.
+//
+// Returns:
+//
+//	*KPIStore: Initialized store.
+func NewKPIStore(db DBPool, config KPIStoreConfig) *KPIStore {
+	if config.Bucket <= 0 {
+		config.Bucket = time.Minute
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = time.Minute
+	}
+	if config.Retention <= 0 {
+		config.Retention = 30 * 24 * time.Hour
+	}
+	return &KPIStore{
+		db:      db,
+		config:  config,
+		logger:  telemetry.Logger(),
+		now:     time.Now,
+		pending: make(map[kpiBucketKey]*KPIPoint),
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// Config returns the store configuration.
+func (s *KPIStore) Config() KPIStoreConfig {
+	return s.config
+}
+
+// RecordKPI adds a sample to the current bucket of a metric.
+func (s *KPIStore) RecordKPI(metric string, value float64) {
+	key := kpiBucketKey{metric: metric, start: s.now().UTC().Truncate(s.config.Bucket)}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mergeKPIPoint(s.pending, key, KPIPoint{Time: key.start, Count: 1, Sum: value, Min: value, Max: value})
+}
+
+// Start flushes buckets and prunes expired ones in the background.
+func (s *KPIStore) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.config.FlushInterval)
+		defer ticker.Stop()
+		lastPrune := time.Time{}
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				if err := s.Flush(ctx); err != nil {
+					s.logger.Warn("Failed to flush KPIs", "error", err)
+				}
+				if s.now().Sub(lastPrune) >= time.Hour {
+					if err := s.Prune(ctx); err != nil {
+						s.logger.Warn("Failed to prune KPIs", "error", err)
+					}
+					lastPrune = s.now()
+				}
+				cancel()
+			}
+		}
+	}()
+}
+
+// Stop ends the background loop and writes what is still pending.
+func (s *KPIStore) Stop() {
+	close(s.stopCh)
+	s.wg.Wait()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.Flush(ctx); err != nil {
+		s.logger.Warn("Failed to flush KPIs on shutdown", "error", err)
+	}
+}
+
+// Flush writes pending buckets, adding them to buckets already stored.
+// Buckets that fail to write are kept for the next flush.
+func (s *KPIStore) Flush(ctx context.Context) error {
+	if isNilDBPool(s.db) {
+		return fmt.Errorf("database pool is not available")
+	}
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[kpiBucketKey]*KPIPoint)
+	s.mu.Unlock()
+
+	var flushErr error
+	for key, point := range pending {
+		_, err := s.db.Exec(ctx, `
+			INSERT INTO kpi_samples (metric, bucket_start, sample_count, value_sum, min_value, max_value)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (metric, bucket_start) DO UPDATE SET
+				sample_count = kpi_samples.sample_count + EXCLUDED.sample_count,
+				value_sum = kpi_samples.value_sum + EXCLUDED.value_sum,
+				min_value = CASE WHEN EXCLUDED.min_value < kpi_samples.min_value THEN EXCLUDED.min_value ELSE kpi_samples.min_value END,
+				max_value = CASE WHEN EXCLUDED.max_value > kpi_samples.max_value THEN EXCLUDED.max_value ELSE kpi_samples.max_value END`,
+			key.metric, key.start, point.Count, point.Sum, point.Min, point.Max)
+		if err != nil {
+			if flushErr == nil {
+				flushErr = fmt.Errorf("failed to store %s: %w", key.metric, err)
+			}
+			s.mu.Lock()
+			mergeKPIPoint(s.pending, key, *point)
+			s.mu.Unlock()
+		}
+	}
+	return flushErr
+}
+
+// Prune deletes buckets older than the retention.
+func (s *KPIStore) Prune(ctx context.Context) error {
+	if isNilDBPool(s.db) {
+		return fmt.Errorf("database pool is not available")
+	}
+	cutoff := s.now().UTC().Add(-s.config.Retention)
+	if _, err := s.db.Exec(ctx, `DELETE FROM kpi_samples WHERE bucket_start < $1`, cutoff); err != nil {
+		return fmt.Errorf("failed to prune KPIs: %w", err)
+	}
+	return nil
+}
+
+// Query returns a metric between two times, rolled up to the step, oldest
+// first. Samples not yet flushed are included.
+//
+// Parameters:
+//
+//	ctx: Context for the query.
+//	metric: KPI name.
+//	from: Start of the range, inclusive.
+//	to: End of the range, exclusive.
+//	step: Resolution of the returned points; values below the bucket size
+//	use the bucket size.
+//
+// Returns:
+//
+//	[]KPIPoint: Points with at least one sample.
+//	error: Error if the query fails.
+func (s *KPIStore) Query(ctx context.Context, metric string, from, to time.Time, step time.Duration) ([]KPIPoint, error) {
+	if isNilDBPool(s.db) {
+		return nil, fmt.Errorf("database pool is not available")
+	}
+	if step < s.config.Bucket {
+		step = s.config.Bucket
+	}
+	rows, err := s.db.Query(ctx, `
+		SELECT bucket_start, sample_count, value_sum, min_value, max_value
+		FROM kpi_samples
+		WHERE metric = $1 AND bucket_start >= $2 AND bucket_start < $3
+		ORDER BY bucket_start`, metric, from.UTC(), to.UTC())
+	if errors.Is(err, pgx.ErrNoRows) {
+		rows = nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to query KPIs: %w", err)
+	}
+
+	rollup := make(map[kpiBucketKey]*KPIPoint)
+	if rows != nil {
+		defer rows.Close()
+		for rows.Next() {
+			var point KPIPoint
+			if err := rows.Scan(&point.Time, &point.Count, &point.Sum, &point.Min, &point.Max); err != nil {
+				return nil, fmt.Errorf("failed to scan KPI: %w", err)
+			}
+			mergeKPIPoint(rollup, kpiBucketKey{metric: metric, start: point.Time.UTC().Truncate(step)}, point)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read KPIs: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	for key, point := range s.pending {
+		if key.metric == metric && !key.start.Before(from) && key.start.Before(to) {
+			mergeKPIPoint(rollup, kpiBucketKey{metric: metric, start: key.start.Truncate(step)}, *point)
+		}
+	}
+	s.mu.Unlock()
+
+	points := make([]KPIPoint, 0, len(rollup))
+	for key, point := range rollup {
+		point.Time = key.start
+		if point.Count > 0 {
+			point.Avg = point.Sum / float64(point.Count)
+		}
+		points = append(points, *point)
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+	return points, nil
+}
+
+// Metrics lists the KPIs with stored or pending samples.
+func (s *KPIStore) Metrics(ctx context.Context) ([]string, error) {
+	if isNilDBPool(s.db) {
+		return nil, fmt.Errorf("database pool is not available")
+	}
+	seen := map[string]bool{}
+	rows, err := s.db.Query(ctx, `SELECT DISTINCT metric FROM kpi_samples`)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to list KPIs: %w", err)
+	}
+	if err == nil {
+		defer rows.Close()
+		for rows.Next() {
+			var metric string
+			if err := rows.Scan(&metric); err != nil {
+				return nil, fmt.Errorf("failed to scan KPI name: %w", err)
+			}
+			seen[metric] = true
+		}
+	}
+	s.mu.Lock()
+	for key := range s.pending {
+		seen[key.metric] = true
+	}
+	s.mu.Unlock()
+
+	metrics := make([]string, 0, len(seen))
+	for metric := range seen {
+		metrics = append(metrics, metric)
+	}
+	sort.Strings(metrics)
+	return metrics, nil
+}
+
+// mergeKPIPoint adds a point's samples to the bucket at key.
+func mergeKPIPoint(buckets map[kpiBucketKey]*KPIPoint, key kpiBucketKey, point KPIPoint) {
+	existing, ok := buckets[key]
+	if !ok {
+		point.Time = key.start
+		buckets[key] = &point
+		return
+	}
+	existing.Count += point.Count
+	existing.Sum += point.Sum
+	if point.Min < existing.Min {
+		existing.Min = point.Min
+	}
+	if point.Max > existing.Max {
+		existing.Max = point.Max
+	}
+}
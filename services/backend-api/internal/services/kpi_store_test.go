@@ -0,0 +1,114 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKPIStore_FlushAggregatesSamplesPerBucket(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	store := NewKPIStore(database.NewMockDBPool(mockPool), KPIStoreConfig{})
+	now := time.Date(2026, 3, 1, 12, 0, 30, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	store.RecordKPI(KPIQuestCycleMs, 120)
+	store.RecordKPI(KPIQuestCycleMs, 80)
+	store.RecordKPI(KPIQuestCycleMs, 200)
+
+	mockPool.ExpectExec("INSERT INTO kpi_samples").
+		WithArgs(KPIQuestCycleMs, now.Truncate(time.Minute), int64(3), 400.0, 80.0, 200.0).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	require.NoError(t, store.Flush(t.Context()))
+
+	// Nothing is pending after a successful flush.
+	require.NoError(t, store.Flush(t.Context()))
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestKPIStore_FlushKeepsFailedBuckets(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	store := NewKPIStore(database.NewMockDBPool(mockPool), KPIStoreConfig{})
+	now := time.Date(2026, 3, 1, 12, 0, 30, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	store.RecordKPI(KPIOrdersPlaced, 1)
+	mockPool.ExpectExec("INSERT INTO kpi_samples").
+		WithArgs(KPIOrdersPlaced, pgxmock.AnyArg(), int64(1), 1.0, 1.0, 1.0).
+		WillReturnError(assert.AnError)
+	require.Error(t, store.Flush(t.Context()))
+
+	store.RecordKPI(KPIOrdersPlaced, 1)
+	mockPool.ExpectExec("INSERT INTO kpi_samples").
+		WithArgs(KPIOrdersPlaced, now.Truncate(time.Minute), int64(2), 2.0, 1.0, 1.0).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	require.NoError(t, store.Flush(t.Context()))
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestKPIStore_QueryRollsUpStoredAndPendingBuckets(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	store := NewKPIStore(database.NewMockDBPool(mockPool), KPIStoreConfig{})
+	now := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	store.RecordKPI(KPINotificationLatencyMs, 90)
+
+	from := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 1, 13, 0, 0, 0, time.UTC)
+	mockPool.ExpectQuery("SELECT bucket_start, sample_count").
+		WithArgs(KPINotificationLatencyMs, from, to).
+		WillReturnRows(pgxmock.NewRows([]string{"bucket_start", "sample_count", "value_sum", "min_value", "max_value"}).
+			AddRow(time.Date(2026, 3, 1, 10, 5, 0, 0, time.UTC), int64(2), 100.0, 40.0, 60.0).
+			AddRow(time.Date(2026, 3, 1, 10, 40, 0, 0, time.UTC), int64(2), 200.0, 20.0, 180.0).
+			AddRow(time.Date(2026, 3, 1, 12, 1, 0, 0, time.UTC), int64(1), 30.0, 30.0, 30.0))
+
+	points, err := store.Query(t.Context(), KPINotificationLatencyMs, from, to, time.Hour)
+	require.NoError(t, err)
+	require.Len(t, points, 2)
+
+	assert.Equal(t, from, points[0].Time)
+	assert.Equal(t, int64(4), points[0].Count)
+	assert.InDelta(t, 75.0, points[0].Avg, 1e-9)
+	assert.InDelta(t, 20.0, points[0].Min, 1e-9)
+	assert.InDelta(t, 180.0, points[0].Max, 1e-9)
+
+	assert.Equal(t, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), points[1].Time)
+	assert.Equal(t, int64(2), points[1].Count)
+	assert.InDelta(t, 60.0, points[1].Avg, 1e-9)
+	assert.InDelta(t, 90.0, points[1].Max, 1e-9)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestKPIStore_MetricsIncludesPending(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	store := NewKPIStore(database.NewMockDBPool(mockPool), KPIStoreConfig{})
+	store.RecordKPI(KPIOpportunitiesFound, 3)
+
+	mockPool.ExpectQuery("SELECT DISTINCT metric FROM kpi_samples").
+		WillReturnRows(pgxmock.NewRows([]string{"metric"}).AddRow(KPIQuestCycleMs).AddRow(KPIOpportunitiesFound))
+
+	metrics, err := store.Metrics(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{KPIOpportunitiesFound, KPIQuestCycleMs}, metrics)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestKPIStore_NilDatabase(t *testing.T) {
+	store := NewKPIStore(nil, KPIStoreConfig{})
+	store.RecordKPI(KPIOrdersFailed, 1)
+	assert.Error(t, store.Flush(t.Context()))
+	_, err := store.Query(t.Context(), KPIOrdersFailed, time.Now().Add(-time.Hour), time.Now(), time.Minute)
+	assert.Error(t, err)
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// MaintenanceTier is one bracket of an exchange's maintenance margin schedule.
+type MaintenanceTier struct {
+	// MaxNotional is the largest position value in the bracket; zero is unbounded.
+	MaxNotional decimal.Decimal
+	// Rate is the maintenance margin rate, e.g. 0.005 for 0.5%.
+	Rate decimal.Decimal
+}
+
+// defaultMaintenanceRate applies to exchanges without a known schedule.
+var defaultMaintenanceRate = decimal.NewFromFloat(0.01)
+
+// DefaultMaintenanceTiers returns the USDT-margined perpetual maintenance
+// margin schedules of the supported exchanges. They follow the majors' tiers;
+// smaller listings usually carry higher rates, so prefer exchange-reported
+// liquidation prices where available.
+//
+// Returns:
+//
+//	map[string][]MaintenanceTier: Tiers per exchange, smallest bracket first.
+func DefaultMaintenanceTiers() map[string][]MaintenanceTier {
+	tier := func(maxNotional int64, rate float64) MaintenanceTier {
+		return MaintenanceTier{MaxNotional: decimal.NewFromInt(maxNotional), Rate: decimal.NewFromFloat(rate)}
+	}
+	return map[string][]MaintenanceTier{
+		"binance": {tier(50_000, 0.004), tier(600_000, 0.005), tier(3_000_000, 0.0065), tier(12_000_000, 0.01), tier(70_000_000, 0.02), tier(0, 0.05)},
+		"bybit":   {tier(2_000_000, 0.005), tier(10_000_000, 0.01), tier(20_000_000, 0.015), tier(0, 0.03)},
+		"okx":     {tier(500_000, 0.004), tier(2_000_000, 0.005), tier(10_000_000, 0.01), tier(0, 0.02)},
+	}
+}
+
+// MaintenanceMarginRate returns the maintenance margin rate for a position
+// of the given notional value on an exchange.
+//
+// Parameters:
+//
+//	tiers: Schedules per exchange, as from DefaultMaintenanceTiers.
+//	exchange: Exchange identifier.
+//	notional: Absolute position value.
+//
+// Returns:
+//
+//	decimal.Decimal: The bracket's maintenance margin rate.
+func MaintenanceMarginRate(tiers map[string][]MaintenanceTier, exchange string, notional decimal.Decimal) decimal.Decimal {
+	schedule := tiers[strings.ToLower(exchange)]
+	for _, t := range schedule {
+		if t.MaxNotional.IsZero() || notional.LessThanOrEqual(t.MaxNotional) {
+			return t.Rate
+		}
+	}
+	if len(schedule) > 0 {
+		return schedule[len(schedule)-1].Rate
+	}
+	return defaultMaintenanceRate
+}
+
+// EstimateLiquidationPrice estimates the isolated-margin liquidation price of
+// a linear position: the price at which the initial margin plus unrealized
+// PnL falls to the maintenance margin.
+//
+// Parameters:
+//
+//	side: BUY/long or SELL/short.
+//	entryPrice: Average entry price.
+//	leverage: Position leverage.
+//	maintenanceRate: Maintenance margin rate at the position's notional.
+//
+// Returns:
+//
+//	decimal.Decimal: Estimated liquidation price; zero when it cannot be
+//	estimated, e.g. for unleveraged or unknown-side positions.
+func EstimateLiquidationPrice(side string, entryPrice, leverage, maintenanceRate decimal.Decimal) decimal.Decimal {
+	if entryPrice.LessThanOrEqual(decimal.Zero) || leverage.LessThanOrEqual(decimal.Zero) {
+		return decimal.Zero
+	}
+	one := decimal.NewFromInt(1)
+	initialRate := one.Div(leverage)
+	switch {
+	case isLongSide(side):
+		// entry - liq = entry/L - mmr*liq
+		divisor := one.Sub(maintenanceRate)
+		if divisor.LessThanOrEqual(decimal.Zero) {
+			return decimal.Zero
+		}
+		price := entryPrice.Mul(one.Sub(initialRate)).Div(divisor)
+		if price.LessThan(decimal.Zero) {
+			return decimal.Zero
+		}
+		return price
+	case isShortSide(side):
+		// liq - entry = entry/L - mmr*liq
+		return entryPrice.Mul(one.Add(initialRate)).Div(one.Add(maintenanceRate))
+	default:
+		return decimal.Zero
+	}
+}
+
+// LiquidationDistance returns how far the mark price may still move against a
+// position before liquidation, as a fraction of the mark price. It is zero or
+// negative once the mark has reached the liquidation price.
+func LiquidationDistance(side string, markPrice, liquidationPrice decimal.Decimal) decimal.Decimal {
+	if markPrice.LessThanOrEqual(decimal.Zero) || liquidationPrice.LessThanOrEqual(decimal.Zero) {
+		return decimal.Zero
+	}
+	if isShortSide(side) {
+		return liquidationPrice.Sub(markPrice).Div(markPrice)
+	}
+	return markPrice.Sub(liquidationPrice).Div(markPrice)
+}
+
+func isLongSide(side string) bool {
+	return strings.EqualFold(side, "BUY") || strings.EqualFold(side, "long")
+}
+
+func isShortSide(side string) bool {
+	return strings.EqualFold(side, "SELL") || strings.EqualFold(side, "short")
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceMarginRate(t *testing.T) {
+	tiers := DefaultMaintenanceTiers()
+	assert.True(t, MaintenanceMarginRate(tiers, "Binance", decimal.NewFromInt(25_000)).Equal(decimal.NewFromFloat(0.004)))
+	assert.True(t, MaintenanceMarginRate(tiers, "binance", decimal.NewFromInt(1_000_000)).Equal(decimal.NewFromFloat(0.0065)))
+	assert.True(t, MaintenanceMarginRate(tiers, "binance", decimal.NewFromInt(500_000_000)).Equal(decimal.NewFromFloat(0.05)))
+	assert.True(t, MaintenanceMarginRate(tiers, "kraken", decimal.NewFromInt(1_000)).Equal(decimal.NewFromFloat(0.01)))
+}
+
+func TestEstimateLiquidationPrice(t *testing.T) {
+	entry := decimal.NewFromInt(50_000)
+	leverage := decimal.NewFromInt(10)
+	rate := decimal.NewFromFloat(0.004)
+
+	// 50000 * (1 - 0.1) / (1 - 0.004)
+	long := EstimateLiquidationPrice("BUY", entry, leverage, rate)
+	assert.InDelta(t, 45180.7229, long.InexactFloat64(), 1e-3)
+	// 50000 * (1 + 0.1) / (1 + 0.004)
+	short := EstimateLiquidationPrice("short", entry, leverage, rate)
+	assert.InDelta(t, 54780.8765, short.InexactFloat64(), 1e-3)
+
+	assert.True(t, EstimateLiquidationPrice("BUY", entry, decimal.Zero, rate).IsZero())
+	assert.True(t, EstimateLiquidationPrice("HOLD", entry, leverage, rate).IsZero())
+
+	assert.InDelta(t, 0.1, LiquidationDistance("BUY", decimal.NewFromInt(50_000), decimal.NewFromInt(45_000)).InexactFloat64(), 1e-9)
+	assert.InDelta(t, 0.1, LiquidationDistance("SELL", decimal.NewFromInt(50_000), decimal.NewFromInt(55_000)).InexactFloat64(), 1e-9)
+	assert.True(t, LiquidationDistance("BUY", decimal.NewFromInt(44_000), decimal.NewFromInt(45_000)).IsNegative())
+}
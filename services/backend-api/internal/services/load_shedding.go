@@ -0,0 +1,357 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	zaplogrus "github.com/irfndi/neuratrade/internal/logging/zaplogrus"
+	"github.com/irfndi/neuratrade/internal/observability"
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+// LoadSheddingLevel is how much work is shed under CPU/memory pressure.
+type LoadSheddingLevel string
+
+const (
+	// LoadSheddingNone runs everything at full capacity.
+	LoadSheddingNone LoadSheddingLevel = "none"
+	// LoadSheddingElevated scans fewer symbols and lengthens cycle intervals.
+	LoadSheddingElevated LoadSheddingLevel = "elevated"
+	// LoadSheddingCritical sheds further and pauses non-critical quests.
+	LoadSheddingCritical LoadSheddingLevel = "critical"
+)
+
+func (l LoadSheddingLevel) rank() int {
+	switch l {
+	case LoadSheddingElevated:
+		return 1
+	case LoadSheddingCritical:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// LoadShedder tells schedulers how much work to shed.
+type LoadShedder interface {
+	// ShedInterval returns the cycle interval to use instead of base.
+	ShedInterval(base time.Duration) time.Duration
+	// AllowQuest reports whether quests of a definition may run.
+	AllowQuest(definitionID string) bool
+}
+
+// Ensure ResourceManager implements LoadShedder.
+var _ LoadShedder = (*ResourceManager)(nil)
+
+// LoadSheddingConfig configures load shedding thresholds and actions.
+type LoadSheddingConfig struct {
+	// CPUElevated and CPUCritical are the CPU usage percentages that enter
+	// the elevated and critical levels.
+	CPUElevated float64
+	CPUCritical float64
+	// MemoryElevated and MemoryCritical are the system memory usage
+	// percentages that enter the elevated and critical levels.
+	MemoryElevated float64
+	MemoryCritical float64
+	// RecoveryMargin is how many percentage points usage must fall below a
+	// threshold before the level is lowered, so shedding does not flap.
+	RecoveryMargin float64
+	// SampleInterval is how often CPU and memory usage are sampled.
+	SampleInterval time.Duration
+	// SymbolFraction is the share of scan symbols kept at the elevated
+	// level; the critical level keeps half of it.
+	SymbolFraction float64
+	// IntervalFactor lengthens cycle intervals at the elevated level; the
+	// critical level doubles it again.
+	IntervalFactor float64
+	// NonCriticalQuests are the quest definitions paused at the critical
+	// level.
+	NonCriticalQuests []string
+}
+
+// DefaultNonCriticalQuests are the quest definitions paused at the critical
+// level by default: scans and reports. Trade execution and portfolio health
+// keep running.
+var DefaultNonCriticalQuests = []string{"market_scan", "funding_rate_scan", "daily_report", "weekly_report"}
+
+// LoadSheddingStatus is the current load shedding state.
+type LoadSheddingStatus struct {
+	// Enabled reports whether load is being monitored.
+	Enabled bool `json:"enabled"`
+	// Level is the active shedding level.
+	Level LoadSheddingLevel `json:"level"`
+	// CPUPercent and MemoryPercent are the last sampled usage.
+	CPUPercent    float64 `json:"cpu_percent"`
+	MemoryPercent float64 `json:"memory_percent"`
+	// SampledAt is when usage was last sampled.
+	SampledAt time.Time `json:"sampled_at,omitempty"`
+	// Since is when the level was entered.
+	Since time.Time `json:"since,omitempty"`
+	// Actions describes what is being shed.
+	Actions []string `json:"actions,omitempty"`
+}
+
+// withDefaults fills zero values.
+func (c LoadSheddingConfig) withDefaults() LoadSheddingConfig {
+	if c.CPUElevated <= 0 {
+		c.CPUElevated = 75
+	}
+	if c.CPUCritical <= 0 {
+		c.CPUCritical = 90
+	}
+	if c.MemoryElevated <= 0 {
+		c.MemoryElevated = 80
+	}
+	if c.MemoryCritical <= 0 {
+		c.MemoryCritical = 92
+	}
+	if c.RecoveryMargin <= 0 {
+		c.RecoveryMargin = 10
+	}
+	if c.SampleInterval <= 0 {
+		c.SampleInterval = 30 * time.Second
+	}
+	if c.SymbolFraction <= 0 || c.SymbolFraction > 1 {
+		c.SymbolFraction = 0.5
+	}
+	if c.IntervalFactor < 1 {
+		c.IntervalFactor = 2
+	}
+	if c.NonCriticalQuests == nil {
+		c.NonCriticalQuests = DefaultNonCriticalQuests
+	}
+	return c
+}
+
+// levelAt returns the level the thresholds put the given usage at.
+func (c LoadSheddingConfig) levelAt(cpuPercent, memoryPercent float64) LoadSheddingLevel {
+	switch {
+	case cpuPercent >= c.CPUCritical || memoryPercent >= c.MemoryCritical:
+		return LoadSheddingCritical
+	case cpuPercent >= c.CPUElevated || memoryPercent >= c.MemoryElevated:
+		return LoadSheddingElevated
+	default:
+		return LoadSheddingNone
+	}
+}
+
+// EnableLoadShedding starts sampling CPU and memory usage and shedding load
+// when it crosses the configured thresholds.
+//
+// Parameters:
+//
+//	config: Thresholds and actions; zero values use the defaults.
+func (rm *ResourceManager) EnableLoadShedding(config LoadSheddingConfig) {
+	config = config.withDefaults()
+	rm.sheddingMu.Lock()
+	if rm.sheddingEnabled {
+		rm.sheddingConfig = config
+		rm.sheddingMu.Unlock()
+		return
+	}
+	rm.sheddingEnabled = true
+	rm.sheddingConfig = config
+	rm.shedding.Enabled = true
+	rm.sheddingMu.Unlock()
+
+	go func() {
+		defer observability.RecoverAndCapture(rm.monitoringCtx, "ResourceManager.loadSheddingLoop")
+		ticker := time.NewTicker(config.SampleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-rm.monitoringCtx.Done():
+				return
+			case <-rm.shutdownChan:
+				return
+			case <-ticker.C:
+				if err := rm.SampleLoad(rm.monitoringCtx); err != nil {
+					rm.logger.WithFields(zaplogrus.Fields{"error": err.Error()}).Warn("Failed to sample system load")
+				}
+			}
+		}
+	}()
+}
+
+// SampleLoad samples CPU and memory usage and updates the shedding level.
+//
+// Parameters:
+//
+//	ctx: Context for sampling.
+//
+// Returns:
+//
+//	error: Error if usage cannot be read.
+func (rm *ResourceManager) SampleLoad(ctx context.Context) error {
+	cpuPercent, memoryPercent, err := rm.sampleLoad(ctx)
+	if err != nil {
+		return err
+	}
+	rm.UpdateLoad(cpuPercent, memoryPercent)
+	return nil
+}
+
+// UpdateLoad applies a usage sample. The level rises as soon as a threshold
+// is crossed and falls only once usage is RecoveryMargin below it.
+//
+// Parameters:
+//
+//	cpuPercent: CPU usage percentage.
+//	memoryPercent: System memory usage percentage.
+//
+// Returns:
+//
+//	LoadSheddingLevel: The level after the sample.
+func (rm *ResourceManager) UpdateLoad(cpuPercent, memoryPercent float64) LoadSheddingLevel {
+	rm.sheddingMu.Lock()
+	defer rm.sheddingMu.Unlock()
+
+	config := rm.sheddingConfig.withDefaults()
+	current := rm.shedding.Level
+	next := config.levelAt(cpuPercent, memoryPercent)
+	if next.rank() < current.rank() {
+		// Hold the current level until usage clears the recovery margin
+		held := config.levelAt(cpuPercent+config.RecoveryMargin, memoryPercent+config.RecoveryMargin)
+		if held.rank() >= current.rank() {
+			next = current
+		} else {
+			next = held
+		}
+	}
+
+	now := time.Now()
+	rm.shedding.CPUPercent = cpuPercent
+	rm.shedding.MemoryPercent = memoryPercent
+	rm.shedding.SampledAt = now
+	if next != current {
+		rm.shedding.Level = next
+		rm.shedding.Since = now
+		rm.shedding.Actions = config.actions(next)
+		fields := zaplogrus.Fields{
+			"from":           current,
+			"to":             next,
+			"cpu_percent":    cpuPercent,
+			"memory_percent": memoryPercent,
+		}
+		if next.rank() > current.rank() {
+			rm.logger.WithFields(fields).Warn("Load shedding level raised")
+		} else {
+			rm.logger.WithFields(fields).Info("Load shedding level lowered")
+		}
+	}
+	return next
+}
+
+// actions describes what the level sheds.
+func (c LoadSheddingConfig) actions(level LoadSheddingLevel) []string {
+	if level == LoadSheddingNone {
+		return nil
+	}
+	actions := []string{
+		fmt.Sprintf("scanning %.0f%% of symbols", c.symbolFraction(level)*100),
+		fmt.Sprintf("cycle intervals x%g", c.intervalFactor(level)),
+	}
+	if level == LoadSheddingCritical && len(c.NonCriticalQuests) > 0 {
+		actions = append(actions, "paused quests: "+strings.Join(c.NonCriticalQuests, ", "))
+	}
+	return actions
+}
+
+func (c LoadSheddingConfig) symbolFraction(level LoadSheddingLevel) float64 {
+	switch level {
+	case LoadSheddingElevated:
+		return c.SymbolFraction
+	case LoadSheddingCritical:
+		return c.SymbolFraction / 2
+	default:
+		return 1
+	}
+}
+
+func (c LoadSheddingConfig) intervalFactor(level LoadSheddingLevel) float64 {
+	switch level {
+	case LoadSheddingElevated:
+		return c.IntervalFactor
+	case LoadSheddingCritical:
+		return c.IntervalFactor * 2
+	default:
+		return 1
+	}
+}
+
+// LoadShedding returns the current load shedding state.
+func (rm *ResourceManager) LoadShedding() LoadSheddingStatus {
+	rm.sheddingMu.RLock()
+	defer rm.sheddingMu.RUnlock()
+	status := rm.shedding
+	status.Actions = append([]string(nil), rm.shedding.Actions...)
+	return status
+}
+
+// LoadSheddingLevel returns the active shedding level.
+func (rm *ResourceManager) LoadSheddingLevel() LoadSheddingLevel {
+	rm.sheddingMu.RLock()
+	defer rm.sheddingMu.RUnlock()
+	return rm.shedding.Level
+}
+
+// ShedSymbols trims a scan list to the share kept at the active level,
+// keeping the first symbols and at least one.
+func (rm *ResourceManager) ShedSymbols(symbols []string) []string {
+	rm.sheddingMu.RLock()
+	fraction := rm.sheddingConfig.withDefaults().symbolFraction(rm.shedding.Level)
+	rm.sheddingMu.RUnlock()
+	if fraction >= 1 || len(symbols) == 0 {
+		return symbols
+	}
+	keep := int(math.Ceil(float64(len(symbols)) * fraction))
+	if keep < 1 {
+		keep = 1
+	}
+	return symbols[:keep]
+}
+
+// ShedInterval returns base lengthened for the active level.
+func (rm *ResourceManager) ShedInterval(base time.Duration) time.Duration {
+	rm.sheddingMu.RLock()
+	factor := rm.sheddingConfig.withDefaults().intervalFactor(rm.shedding.Level)
+	rm.sheddingMu.RUnlock()
+	return time.Duration(float64(base) * factor)
+}
+
+// AllowQuest reports whether quests of a definition may run; non-critical
+// quests are paused at the critical level.
+func (rm *ResourceManager) AllowQuest(definitionID string) bool {
+	rm.sheddingMu.RLock()
+	defer rm.sheddingMu.RUnlock()
+	if rm.shedding.Level != LoadSheddingCritical {
+		return true
+	}
+	for _, id := range rm.sheddingConfig.withDefaults().NonCriticalQuests {
+		if id == definitionID {
+			return false
+		}
+	}
+	return true
+}
+
+// sampleSystemLoad reads CPU and system memory usage.
+func sampleSystemLoad(ctx context.Context) (float64, float64, error) {
+	cpuPercent, err := cpu.PercentWithContext(ctx, time.Second, false)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get CPU usage: %w", err)
+	}
+	memInfo, err := mem.VirtualMemoryWithContext(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get memory usage: %w", err)
+	}
+	usage := 0.0
+	if len(cpuPercent) > 0 {
+		usage = cpuPercent[0]
+	}
+	return usage, memInfo.UsedPercent, nil
+}
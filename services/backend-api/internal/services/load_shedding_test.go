@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	zaplogrus "github.com/irfndi/neuratrade/internal/logging/zaplogrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLoadSheddingManager(t *testing.T) *ResourceManager {
+	t.Helper()
+	rm := NewResourceManager(zaplogrus.New())
+	t.Cleanup(rm.Shutdown)
+	rm.EnableLoadShedding(LoadSheddingConfig{SampleInterval: time.Hour})
+	return rm
+}
+
+func TestResourceManager_UpdateLoadRaisesAndLowersWithHysteresis(t *testing.T) {
+	rm := newLoadSheddingManager(t)
+	assert.Equal(t, LoadSheddingNone, rm.LoadSheddingLevel())
+
+	assert.Equal(t, LoadSheddingElevated, rm.UpdateLoad(80, 40))
+	assert.Equal(t, LoadSheddingCritical, rm.UpdateLoad(50, 95))
+
+	// Below the critical threshold but inside the recovery margin
+	assert.Equal(t, LoadSheddingCritical, rm.UpdateLoad(50, 85))
+	// Clear of critical but still within the elevated margin
+	assert.Equal(t, LoadSheddingElevated, rm.UpdateLoad(70, 60))
+	assert.Equal(t, LoadSheddingNone, rm.UpdateLoad(60, 60))
+
+	status := rm.LoadShedding()
+	assert.True(t, status.Enabled)
+	assert.Equal(t, LoadSheddingNone, status.Level)
+	assert.InDelta(t, 60.0, status.CPUPercent, 1e-9)
+	assert.Empty(t, status.Actions)
+}
+
+func TestResourceManager_ShedsSymbolsIntervalsAndQuests(t *testing.T) {
+	rm := newLoadSheddingManager(t)
+	symbols := []string{"BTC/USDT", "ETH/USDT", "SOL/USDT", "XRP/USDT", "ADA/USDT"}
+
+	assert.Equal(t, symbols, rm.ShedSymbols(symbols))
+	assert.Equal(t, time.Minute, rm.ShedInterval(time.Minute))
+	assert.True(t, rm.AllowQuest("daily_report"))
+
+	rm.UpdateLoad(80, 0)
+	assert.Equal(t, symbols[:3], rm.ShedSymbols(symbols))
+	assert.Equal(t, 2*time.Minute, rm.ShedInterval(time.Minute))
+	assert.True(t, rm.AllowQuest("daily_report"))
+
+	rm.UpdateLoad(95, 0)
+	assert.Equal(t, symbols[:2], rm.ShedSymbols(symbols))
+	assert.Equal(t, []string{"BTC/USDT"}, rm.ShedSymbols(symbols[:1]))
+	assert.Equal(t, 4*time.Minute, rm.ShedInterval(time.Minute))
+	assert.False(t, rm.AllowQuest("daily_report"))
+	assert.True(t, rm.AllowQuest("scalping_execution"))
+	assert.Contains(t, rm.LoadShedding().Actions, "paused quests: market_scan, funding_rate_scan, daily_report, weekly_report")
+}
+
+func TestResourceManager_SampleLoad(t *testing.T) {
+	rm := newLoadSheddingManager(t)
+	rm.sampleLoad = func(context.Context) (float64, float64, error) { return 20, 93, nil }
+	require.NoError(t, rm.SampleLoad(t.Context()))
+	assert.Equal(t, LoadSheddingCritical, rm.LoadSheddingLevel())
+
+	rm.sampleLoad = func(context.Context) (float64, float64, error) { return 0, 0, assert.AnError }
+	assert.Error(t, rm.SampleLoad(t.Context()))
+	assert.Equal(t, LoadSheddingCritical, rm.LoadSheddingLevel())
+}
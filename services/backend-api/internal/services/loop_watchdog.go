@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/telemetry"
+)
+
+// LoopScheduler is the heartbeat of the quest scheduler itself.
+const LoopScheduler = "scheduler"
+
+const (
+	defaultWatchdogCheckInterval = 30 * time.Second
+	defaultWatchdogMissedBeats   = 3
+	// defaultWatchdogGrace covers a quest run in progress, which may take up
+	// to the 5 minute execution timeout before it refreshes its heartbeat.
+	defaultWatchdogGrace = 5 * time.Minute
+)
+
+// LoopHeartbeat is the last sign of life of a trading loop.
+type LoopHeartbeat struct {
+	// Loop identifies the loop: LoopScheduler or "quest:<id>".
+	Loop   string `json:"loop"`
+	Name   string `json:"name"`
+	ChatID string `json:"chat_id,omitempty"`
+	// LastBeat is when the loop last refreshed its heartbeat.
+	LastBeat time.Time `json:"last_beat"`
+	// Interval is how often the loop is expected to beat.
+	Interval time.Duration `json:"interval"`
+}
+
+// HeartbeatSource lists the heartbeats of the loops to watch.
+type HeartbeatSource interface {
+	Heartbeats() []LoopHeartbeat
+}
+
+// LoopWatchdogConfig configures the trading loop watchdog.
+type LoopWatchdogConfig struct {
+	// CheckInterval is how often heartbeats are checked.
+	CheckInterval time.Duration
+	// MissedBeats is how many intervals a loop may miss before it counts as
+	// stalled.
+	MissedBeats int
+	// Grace is added to every deadline to absorb slow runs.
+	Grace time.Duration
+	// FlattenOnStall closes open positions when a loop stalls.
+	FlattenOnStall bool
+}
+
+// LoopStatus is a watched loop and whether it missed its deadline.
+type LoopStatus struct {
+	LoopHeartbeat
+	Deadline time.Time `json:"deadline"`
+	Stalled  bool      `json:"stalled"`
+	// StalledSince is when the stall was first detected.
+	StalledSince *time.Time `json:"stalled_since,omitempty"`
+}
+
+// TradingLoopWatchdog is a dead man's switch for the trading loop: the quest
+// scheduler and every scheduled quest must refresh a heartbeat, and a loop
+// that stays silent for MissedBeats intervals plus the grace period is
+// reported to its chat, and optionally has its positions flattened. The
+// operator is told again once the loop recovers.
+type TradingLoopWatchdog struct {
+	source    HeartbeatSource
+	config    LoopWatchdogConfig
+	flatten   PositionFlattener
+	events    EventEmitter
+	messenger DirectMessenger
+	logger    *slog.Logger
+	now       func() time.Time
+
+	mu sync.Mutex
+	// stalled maps each stalled loop to when the stall was detected
+	stalled map[string]time.Time
+	// last is the result of the latest check
+	last []LoopStatus
+
+	runMu  sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewTradingLoopWatchdog creates a trading loop watchdog.
+//
+// Parameters:
+//
+//	source: Heartbeats to watch, normally the quest engine.
+//	config: Watchdog configuration; zero values use defaults.
+//
+// Returns:
+//
+//	*TradingLoopWatchdog: Initialized watchdog (checks not started).
+func NewTradingLoopWatchdog(source HeartbeatSource, config LoopWatchdogConfig) *TradingLoopWatchdog {
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = defaultWatchdogCheckInterval
+	}
+	if config.MissedBeats <= 0 {
+		config.MissedBeats = defaultWatchdogMissedBeats
+	}
+	if config.Grace <= 0 {
+		config.Grace = defaultWatchdogGrace
+	}
+	return &TradingLoopWatchdog{
+		source:  source,
+		config:  config,
+		logger:  telemetry.Logger(),
+		now:     time.Now,
+		stalled: make(map[string]time.Time),
+	}
+}
+
+// SetFlattener sets how open positions are closed when FlattenOnStall is enabled.
+func (w *TradingLoopWatchdog) SetFlattener(flatten PositionFlattener) {
+	w.flatten = flatten
+}
+
+// SetEventEmitter publishes stall and recovery risk events.
+func (w *TradingLoopWatchdog) SetEventEmitter(events EventEmitter) {
+	w.events = events
+}
+
+// SetMessenger sets the Telegram sender used for stall and recovery alerts.
+func (w *TradingLoopWatchdog) SetMessenger(messenger DirectMessenger) {
+	w.messenger = messenger
+}
+
+// Start checks the heartbeats once per interval until Stop is called.
+func (w *TradingLoopWatchdog) Start(ctx context.Context) error {
+	w.runMu.Lock()
+	defer w.runMu.Unlock()
+	if w.cancel != nil {
+		return fmt.Errorf("loop watchdog already running")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	w.cancel = cancel
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(w.config.CheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.Check(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the checks and waits for the running check to finish.
+func (w *TradingLoopWatchdog) Stop() {
+	w.runMu.Lock()
+	cancel := w.cancel
+	w.cancel = nil
+	w.runMu.Unlock()
+	if cancel != nil {
+		cancel()
+		w.wg.Wait()
+	}
+}
+
+// Check compares every heartbeat with its deadline, alerting on new stalls
+// and on recoveries.
+//
+// Parameters:
+//
+//	ctx: Context for alerts and flattening.
+//
+// Returns:
+//
+//	[]LoopStatus: Every watched loop, stalled ones first.
+func (w *TradingLoopWatchdog) Check(ctx context.Context) []LoopStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.now()
+	beats := w.source.Heartbeats()
+	statuses := make([]LoopStatus, 0, len(beats))
+	seen := make(map[string]bool, len(beats))
+	var newlyStalled []LoopStatus
+	for _, beat := range beats {
+		seen[beat.Loop] = true
+		status := LoopStatus{
+			LoopHeartbeat: beat,
+			Deadline:      beat.LastBeat.Add(beat.Interval*time.Duration(w.config.MissedBeats) + w.config.Grace),
+		}
+		since, wasStalled := w.stalled[beat.Loop]
+		if now.After(status.Deadline) {
+			if !wasStalled {
+				since = now
+				w.stalled[beat.Loop] = since
+			}
+			status.Stalled = true
+			status.StalledSince = &since
+			if !wasStalled {
+				newlyStalled = append(newlyStalled, status)
+			}
+		} else if wasStalled {
+			delete(w.stalled, beat.Loop)
+			w.alertRecovered(ctx, status, beats)
+		}
+		statuses = append(statuses, status)
+	}
+	// Loops that went away, e.g. a paused quest, are no longer stalled
+	for loop := range w.stalled {
+		if !seen[loop] {
+			delete(w.stalled, loop)
+		}
+	}
+
+	if len(newlyStalled) > 0 {
+		flattened := false
+		if w.config.FlattenOnStall && w.flatten != nil {
+			// All chats share one exchange account, so one flatten covers every stall
+			if err := w.flatten(ctx, newlyStalled[0].ChatID); err != nil {
+				w.logger.Error("Failed to flatten positions after a trading loop stall", "error", err)
+			} else {
+				flattened = true
+			}
+		}
+		for _, status := range newlyStalled {
+			w.alertStalled(ctx, status, beats, now, flattened)
+		}
+	}
+
+	sort.SliceStable(statuses, func(i, j int) bool { return statuses[i].Stalled && !statuses[j].Stalled })
+	w.last = statuses
+	return statuses
+}
+
+// Status returns the result of the latest check.
+func (w *TradingLoopWatchdog) Status() []LoopStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]LoopStatus(nil), w.last...)
+}
+
+func (w *TradingLoopWatchdog) alertStalled(ctx context.Context, status LoopStatus, beats []LoopHeartbeat, now time.Time, flattened bool) {
+	silent := now.Sub(status.LastBeat).Round(time.Second)
+	w.logger.Error("Trading loop missed its heartbeat", "loop", status.Loop, "name", status.Name, "chat_id", status.ChatID, "silent_for", silent.String())
+
+	text := fmt.Sprintf("🚨 %s has not run for %s (expected every %s).\nIt may be stalled; check the server and run /doctor.",
+		status.Name, silent, status.Interval)
+	if flattened {
+		text += "\nOpen positions were closed as a precaution."
+	}
+	w.publish(ctx, status, beats, "loop_stalled", text, map[string]interface{}{
+		"silent_for_seconds": int64(silent.Seconds()),
+		"flattened":          flattened,
+	})
+}
+
+func (w *TradingLoopWatchdog) alertRecovered(ctx context.Context, status LoopStatus, beats []LoopHeartbeat) {
+	w.logger.Info("Trading loop heartbeat recovered", "loop", status.Loop, "name", status.Name, "chat_id", status.ChatID)
+	text := fmt.Sprintf("✅ %s is running again.", status.Name)
+	w.publish(ctx, status, beats, "loop_recovered", text, nil)
+}
+
+// publish emits a risk event and messages the loop's chat; a scheduler
+// alert goes to every chat with a watched quest.
+func (w *TradingLoopWatchdog) publish(ctx context.Context, status LoopStatus, beats []LoopHeartbeat, eventType, text string, extra map[string]interface{}) {
+	if w.events != nil {
+		data := map[string]interface{}{
+			"type":      eventType,
+			"loop":      status.Loop,
+			"name":      status.Name,
+			"last_beat": status.LastBeat.UTC().Format(time.RFC3339),
+		}
+		if status.ChatID != "" {
+			data["chat_id"] = status.ChatID
+		}
+		for key, value := range extra {
+			data[key] = value
+		}
+		w.events.Emit(ctx, WebhookEventRisk, data)
+	}
+	if w.messenger == nil {
+		return
+	}
+
+	chats := []string{status.ChatID}
+	if status.Loop == LoopScheduler {
+		chats = nil
+		seen := make(map[string]bool)
+		for _, beat := range beats {
+			if beat.ChatID != "" && !seen[beat.ChatID] {
+				seen[beat.ChatID] = true
+				chats = append(chats, beat.ChatID)
+			}
+		}
+	}
+	for _, chat := range chats {
+		chatID, err := strconv.ParseInt(chat, 10, 64)
+		if err != nil {
+			continue
+		}
+		if err := w.messenger.SendDirectMessage(ctx, chatID, text); err != nil {
+			w.logger.Warn("Failed to send watchdog alert", "chat_id", chatID, "error", err)
+		}
+	}
+}
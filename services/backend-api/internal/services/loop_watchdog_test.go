@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/pkg/interfaces"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeHeartbeatSource struct {
+	beats []LoopHeartbeat
+}
+
+func (f *fakeHeartbeatSource) Heartbeats() []LoopHeartbeat {
+	return f.beats
+}
+
+func TestTradingLoopWatchdog_AlertsOnceAndRecovers(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	now := start
+	source := &fakeHeartbeatSource{beats: []LoopHeartbeat{
+		{Loop: LoopScheduler, Name: "Quest scheduler", LastBeat: start, Interval: time.Minute},
+		{Loop: "quest:q1", Name: "Scalper", ChatID: "42", LastBeat: start, Interval: time.Minute},
+	}}
+	watchdog := NewTradingLoopWatchdog(source, LoopWatchdogConfig{MissedBeats: 2, Grace: time.Minute, FlattenOnStall: true})
+	watchdog.now = func() time.Time { return now }
+	messenger := &recordingMessenger{}
+	watchdog.SetMessenger(messenger)
+	emitter := &recordingEmitter{}
+	watchdog.SetEventEmitter(emitter)
+	flattened := []string{}
+	watchdog.SetFlattener(func(_ context.Context, chatID string) error {
+		flattened = append(flattened, chatID)
+		return nil
+	})
+	ctx := t.Context()
+
+	now = start.Add(3 * time.Minute)
+	statuses := watchdog.Check(ctx)
+	require.Len(t, statuses, 2)
+	assert.False(t, statuses[0].Stalled, "the deadline is two intervals plus the grace period")
+	assert.Empty(t, messenger.texts)
+
+	// The scheduler keeps ticking but the quest stops beating
+	now = start.Add(4 * time.Minute)
+	source.beats[0].LastBeat = now
+	statuses = watchdog.Check(ctx)
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "quest:q1", statuses[0].Loop, "stalled loops come first")
+	assert.True(t, statuses[0].Stalled)
+	assert.Equal(t, []string{"42"}, flattened)
+	assert.Equal(t, []int64{42}, messenger.chats)
+	assert.Contains(t, messenger.texts[0], "Scalper has not run for 4m0s")
+	assert.Contains(t, messenger.texts[0], "Open positions were closed")
+	assert.Equal(t, []WebhookEventType{WebhookEventRisk}, emitter.events)
+
+	// A stall is reported once
+	now = start.Add(10 * time.Minute)
+	source.beats[0].LastBeat = now
+	statuses = watchdog.Check(ctx)
+	assert.True(t, statuses[0].Stalled)
+	assert.Equal(t, start.Add(4*time.Minute), *statuses[0].StalledSince)
+	assert.Len(t, messenger.texts, 1)
+	assert.Len(t, flattened, 1)
+
+	source.beats[1].LastBeat = now
+	statuses = watchdog.Check(ctx)
+	assert.False(t, statuses[0].Stalled || statuses[1].Stalled)
+	require.Len(t, messenger.texts, 2)
+	assert.Contains(t, messenger.texts[1], "Scalper is running again")
+	assert.Len(t, emitter.events, 2)
+	assert.Equal(t, statuses, watchdog.Status())
+}
+
+func TestTradingLoopWatchdog_SchedulerStallAlertsEveryChat(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	source := &fakeHeartbeatSource{beats: []LoopHeartbeat{
+		{Loop: LoopScheduler, Name: "Quest scheduler", LastBeat: start, Interval: time.Minute},
+		{Loop: "quest:q1", Name: "Scalper", ChatID: "42", LastBeat: start, Interval: time.Hour},
+		{Loop: "quest:q2", Name: "Rebalance", ChatID: "42", LastBeat: start, Interval: time.Hour},
+		{Loop: "quest:q3", Name: "Scalper", ChatID: "7", LastBeat: start, Interval: time.Hour},
+	}}
+	watchdog := NewTradingLoopWatchdog(source, LoopWatchdogConfig{})
+	watchdog.now = func() time.Time { return start.Add(9 * time.Minute) }
+	messenger := &recordingMessenger{}
+	watchdog.SetMessenger(messenger)
+	flattened := 0
+	watchdog.SetFlattener(func(context.Context, string) error {
+		flattened++
+		return errors.New("exchange down")
+	})
+
+	statuses := watchdog.Check(t.Context())
+	assert.True(t, statuses[0].Stalled)
+	assert.Equal(t, LoopScheduler, statuses[0].Loop)
+	assert.Equal(t, []int64{42, 7}, messenger.chats)
+	assert.NotContains(t, messenger.texts[0], "Open positions were closed")
+	assert.Zero(t, flattened, "flattening is off by default")
+
+	// A loop that disappears, e.g. a held engine, is no longer tracked as stalled
+	source.beats = nil
+	assert.Empty(t, watchdog.Check(t.Context()))
+	assert.Empty(t, watchdog.stalled)
+}
+
+func TestQuestEngine_Heartbeats(t *testing.T) {
+	engine := NewQuestEngine(NewInMemoryQuestStore())
+	assert.Nil(t, engine.Heartbeats(), "a stopped engine has no heartbeats")
+
+	started := time.Now()
+	engine.running = true
+	engine.startedAt = started
+	engine.quests["micro"] = &Quest{ID: "micro", Name: "Scalper", Cadence: CadenceMicro, Status: QuestStatusActive,
+		Metadata: map[string]string{"chat_id": "42"}}
+	engine.quests["paused"] = &Quest{ID: "paused", Cadence: CadenceMicro, Status: QuestStatusPaused}
+	engine.quests["once"] = &Quest{ID: "once", Cadence: CadenceOnetime, Status: QuestStatusActive}
+
+	beats := engine.Heartbeats()
+	require.Len(t, beats, 2)
+	assert.Equal(t, LoopScheduler, beats[0].Loop)
+	assert.Equal(t, started, beats[0].LastBeat, "the scheduler counts from start-up until its first tick")
+	assert.Equal(t, "quest:micro", beats[1].Loop)
+	assert.Equal(t, "42", beats[1].ChatID)
+	assert.Equal(t, time.Minute, beats[1].Interval)
+	assert.Equal(t, started, beats[1].LastBeat)
+
+	ran := started.Add(time.Minute)
+	engine.questBeats["micro"] = ran
+	assert.Equal(t, ran, engine.Heartbeats()[1].LastBeat)
+
+	engine.Hold("maintenance")
+	assert.Len(t, engine.Heartbeats(), 1, "held quests are not expected to run")
+}
+
+func TestTradingLoopWatchdog_FlattensOnTheExchange(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	source := &fakeHeartbeatSource{beats: []LoopHeartbeat{
+		{Loop: "quest:q1", Name: "Scalper", ChatID: "42", LastBeat: start, Interval: time.Minute},
+	}}
+	watchdog := NewTradingLoopWatchdog(source, LoopWatchdogConfig{MissedBeats: 2, Grace: time.Minute, FlattenOnStall: true})
+	watchdog.now = func() time.Time { return start.Add(4 * time.Minute) }
+	messenger := &recordingMessenger{}
+	watchdog.SetMessenger(messenger)
+	orders := &fakeOrderPlacer{}
+	watchdog.SetFlattener(NewExchangeFlattener(marginTestPositions{
+		{PositionID: "p1", Exchange: "binance", Symbol: "BTC/USDT", Side: "BUY", Size: decimal.NewFromFloat(0.25), Status: interfaces.PositionStatusOpen},
+		{PositionID: "p2", Exchange: "bybit", Symbol: "ETH/USDT", Side: "SELL", Size: decimal.NewFromInt(2), Status: interfaces.PositionStatusOpen},
+	}, orders).Flatten)
+
+	statuses := watchdog.Check(t.Context())
+	require.Len(t, statuses, 1)
+	assert.True(t, statuses[0].Stalled)
+	assert.Equal(t, []string{"binance:sell:0.25", "bybit:buy:2"}, orders.orders, "the executor receives a closing order per position")
+	require.Len(t, messenger.texts, 1)
+	assert.Contains(t, messenger.texts[0], "Open positions were closed")
+}
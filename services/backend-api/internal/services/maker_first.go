@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/shopspring/decimal"
+)
+
+// MakerFirstOrderExecutor is the executor a MakerFirstExecutor wraps.
+type MakerFirstOrderExecutor interface {
+	ScalpingOrderExecutor
+	OrderOptionsExecutor
+	GetOrder(ctx context.Context, exchange, orderID string) (map[string]interface{}, error)
+	CancelOrder(ctx context.Context, exchange, orderID string) error
+}
+
+// MakerFirstConfig configures maker-first execution.
+type MakerFirstConfig struct {
+	// Timeout is how long the maker order may rest before the rest is taken.
+	Timeout time.Duration
+	// PollInterval is how often the resting maker order is checked.
+	PollInterval time.Duration
+	// InsideSpread places the maker price this fraction of the spread inside
+	// the best bid or ask; 0 joins the best price. The order never crosses.
+	InsideSpread float64
+	// MakerFeePct and TakerFeePct estimate the fees saved by maker fills.
+	MakerFeePct float64
+	TakerFeePct float64
+}
+
+// ExecutionQualityStats summarizes maker-first executions.
+type ExecutionQualityStats struct {
+	// Orders is the number of maker-first orders.
+	Orders int `json:"orders"`
+	// MakerFilled orders filled entirely as maker.
+	MakerFilled int `json:"maker_filled"`
+	// PartialMaker orders filled partly as maker before the taker fallback.
+	PartialMaker int `json:"partial_maker"`
+	// TakerFallbacks orders needed a taker order for some or all of the amount.
+	TakerFallbacks int `json:"taker_fallbacks"`
+	// MakerFillRate is the share of the base amount that filled as maker.
+	MakerFillRate float64         `json:"maker_fill_rate"`
+	MakerAmount   decimal.Decimal `json:"maker_amount"`
+	TakerAmount   decimal.Decimal `json:"taker_amount"`
+	// MakerNotional is the quote value filled as maker.
+	MakerNotional decimal.Decimal `json:"maker_notional"`
+	// EstimatedFeeSavings is MakerNotional times the taker-maker fee gap.
+	EstimatedFeeSavings decimal.Decimal `json:"estimated_fee_savings"`
+	MakerFeePct         float64         `json:"maker_fee_pct"`
+	TakerFeePct         float64         `json:"taker_fee_pct"`
+	// AverageMakerWait is how long maker orders rested on average.
+	AverageMakerWait string `json:"average_maker_wait"`
+}
+
+// MakerFirstExecutor places orders flagged MakerFirst as post-only limits at
+// or inside the spread and converts what is unfilled after the timeout into a
+// market order. Other orders pass straight through to the wrapped executor.
+// Maker-first orders block until they complete.
+type MakerFirstExecutor struct {
+	MakerFirstOrderExecutor
+	prices  AlgoPriceSource
+	updates OrderUpdateWaiter
+	config  MakerFirstConfig
+	logger  *slog.Logger
+	now     func() time.Time
+	sleep   func(ctx context.Context, d time.Duration) error
+
+	mu        sync.Mutex
+	stats     ExecutionQualityStats
+	makerWait time.Duration
+}
+
+// NewMakerFirstExecutor wraps an executor with maker-first execution.
+//
+// Parameters:
+//
+//	executor: Places, inspects and cancels the orders.
+//	prices: Ticker source for the best bid and ask.
+//	config: Maker-first settings; zero values use defaults.
+//
+// Returns:
+//
+//	*MakerFirstExecutor: Initialized executor.
+func NewMakerFirstExecutor(executor MakerFirstOrderExecutor, prices AlgoPriceSource, config MakerFirstConfig) *MakerFirstExecutor {
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+	if config.InsideSpread < 0 || config.InsideSpread >= 1 {
+		config.InsideSpread = 0
+	}
+	if config.MakerFeePct == 0 && config.TakerFeePct == 0 {
+		config.MakerFeePct, config.TakerFeePct = 0.02, 0.05
+	}
+	return &MakerFirstExecutor{
+		MakerFirstOrderExecutor: executor,
+		prices:                  prices,
+		config:                  config,
+		logger:                  telemetry.Logger(),
+		now:                     time.Now,
+		sleep:                   sleepContext,
+	}
+}
+
+// SetOrderUpdates wakes resting maker orders on streamed order updates
+// instead of waiting out the poll interval.
+func (e *MakerFirstExecutor) SetOrderUpdates(updates OrderUpdateWaiter) {
+	e.updates = updates
+}
+
+// PlaceOrderWithOptions runs maker-first execution when options.MakerFirst is
+// set and otherwise places the order as given. After a taker fallback it
+// returns the taker order's ID.
+func (e *MakerFirstExecutor) PlaceOrderWithOptions(ctx context.Context, exchange, symbol, side, orderType string, amount decimal.Decimal, price *decimal.Decimal, options OrderOptions) (string, error) {
+	if !options.MakerFirst {
+		return e.MakerFirstOrderExecutor.PlaceOrderWithOptions(ctx, exchange, symbol, side, orderType, amount, price, options)
+	}
+	if !amount.IsPositive() {
+		return "", fmt.Errorf("amount must be greater than zero")
+	}
+
+	makerPrice, err := e.makerPrice(ctx, exchange, symbol, side)
+	if err != nil {
+		e.logger.Warn("No quote for maker-first order, taking liquidity", "symbol", symbol, "error", err)
+		return e.takeRest(ctx, exchange, symbol, side, "", amount, decimal.Zero, decimal.Zero, 0)
+	}
+
+	started := e.now()
+	makerID, err := e.MakerFirstOrderExecutor.PlaceOrderWithOptions(ctx, exchange, symbol, side, "limit", amount, &makerPrice, OrderOptions{PostOnly: true, ClientOrderID: options.ClientOrderID})
+	if errors.Is(err, ErrPostOnlyRejected) {
+		e.logger.Info("Maker-first order would have crossed the spread, taking liquidity", "symbol", symbol, "side", side)
+		return e.takeRest(ctx, exchange, symbol, side, "", amount, decimal.Zero, makerPrice, 0)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	deadline := started.Add(e.config.Timeout)
+	for {
+		if err := waitOrderUpdate(ctx, e.updates, e.sleep, exchange, makerID, e.config.PollInterval); err != nil {
+			filled := e.cancelMaker(exchange, makerID)
+			e.record(amount, filled, decimal.Zero, makerPrice, e.now().Sub(started))
+			return makerID, err
+		}
+		status, filled, err := e.orderStatus(ctx, exchange, makerID)
+		if err != nil {
+			e.logger.Warn("Failed to check maker-first order", "order_id", makerID, "error", err)
+		} else if status == "closed" {
+			e.record(amount, amount, decimal.Zero, makerPrice, e.now().Sub(started))
+			return makerID, nil
+		} else if status == "canceled" || status == "expired" || status == "rejected" {
+			return e.takeRest(ctx, exchange, symbol, side, makerID, amount, filled, makerPrice, e.now().Sub(started))
+		}
+		if !e.now().Before(deadline) {
+			filled := e.cancelMaker(exchange, makerID)
+			return e.takeRest(ctx, exchange, symbol, side, makerID, amount, filled, makerPrice, e.now().Sub(started))
+		}
+	}
+}
+
+// ExecutionQuality returns the maker fill rate and estimated fee savings of
+// maker-first orders since start.
+func (e *MakerFirstExecutor) ExecutionQuality() ExecutionQualityStats {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	stats := e.stats
+	total := stats.MakerAmount.Add(stats.TakerAmount)
+	if total.IsPositive() {
+		stats.MakerFillRate = stats.MakerAmount.Div(total).InexactFloat64()
+	}
+	stats.MakerFeePct, stats.TakerFeePct = e.config.MakerFeePct, e.config.TakerFeePct
+	feeGap := decimal.NewFromFloat(e.config.TakerFeePct - e.config.MakerFeePct).Div(decimal.NewFromInt(100))
+	stats.EstimatedFeeSavings = stats.MakerNotional.Mul(feeGap).Round(8)
+	if waited := stats.MakerFilled + stats.PartialMaker; waited > 0 {
+		stats.AverageMakerWait = (e.makerWait / time.Duration(waited)).Round(time.Millisecond).String()
+	}
+	return stats
+}
+
+// takeRest places a market order for what the maker order left unfilled.
+func (e *MakerFirstExecutor) takeRest(ctx context.Context, exchange, symbol, side, makerID string, amount, makerFilled, makerPrice decimal.Decimal, waited time.Duration) (string, error) {
+	rest := amount.Sub(makerFilled)
+	if !rest.IsPositive() {
+		e.record(amount, amount, decimal.Zero, makerPrice, waited)
+		return makerID, nil
+	}
+	takerID, err := e.MakerFirstOrderExecutor.PlaceOrder(ctx, exchange, symbol, side, "market", rest, nil)
+	if err != nil {
+		e.record(amount, makerFilled, decimal.Zero, makerPrice, waited)
+		return "", fmt.Errorf("taker fallback failed: %w", err)
+	}
+	e.logger.Info("Maker-first order fell back to taker", "symbol", symbol, "side", side,
+		"maker_filled", makerFilled.String(), "taker_amount", rest.String())
+	e.record(amount, makerFilled, rest, makerPrice, waited)
+	return takerID, nil
+}
+
+// makerPrice is the best bid for buys or best ask for sells, moved the
+// configured fraction of the spread inside it.
+func (e *MakerFirstExecutor) makerPrice(ctx context.Context, exchange, symbol, side string) (decimal.Decimal, error) {
+	if e.prices == nil {
+		return decimal.Zero, fmt.Errorf("no price source configured")
+	}
+	ticker, err := e.prices.FetchSingleTicker(ctx, exchange, symbol)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if ticker == nil || ticker.GetBid() <= 0 || ticker.GetAsk() <= ticker.GetBid() {
+		return decimal.Zero, fmt.Errorf("no usable bid/ask for %s on %s", symbol, exchange)
+	}
+	bid, ask := decimal.NewFromFloat(ticker.GetBid()), decimal.NewFromFloat(ticker.GetAsk())
+	step := ask.Sub(bid).Mul(decimal.NewFromFloat(e.config.InsideSpread))
+	if side == "sell" {
+		return ask.Sub(step), nil
+	}
+	return bid.Add(step), nil
+}
+
+// cancelMaker cancels the resting maker order and returns what it filled.
+func (e *MakerFirstExecutor) cancelMaker(exchange, orderID string) decimal.Decimal {
+	// The caller's context may already be cancelled
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := e.MakerFirstOrderExecutor.CancelOrder(ctx, exchange, orderID); err != nil {
+		e.logger.Warn("Failed to cancel maker-first order", "order_id", orderID, "error", err)
+	}
+	_, filled, err := e.orderStatus(ctx, exchange, orderID)
+	if err != nil {
+		return decimal.Zero
+	}
+	return filled
+}
+
+// orderStatus returns an order's CCXT status and filled amount.
+func (e *MakerFirstExecutor) orderStatus(ctx context.Context, exchange, orderID string) (string, decimal.Decimal, error) {
+	result, err := e.MakerFirstOrderExecutor.GetOrder(ctx, exchange, orderID)
+	if err != nil {
+		return "", decimal.Zero, err
+	}
+	order := result
+	if nested, ok := result["order"].(map[string]interface{}); ok {
+		order = nested
+	}
+	status, _ := order["status"].(string)
+	filled := decimal.Zero
+	if value, ok := order["filled"].(float64); ok {
+		filled = decimal.NewFromFloat(value)
+	}
+	return status, filled, nil
+}
+
+func (e *MakerFirstExecutor) record(amount, makerFilled, takerAmount, makerPrice decimal.Decimal, waited time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.stats.Orders++
+	switch {
+	case makerFilled.GreaterThanOrEqual(amount):
+		e.stats.MakerFilled++
+		e.makerWait += waited
+	case makerFilled.IsPositive():
+		e.stats.PartialMaker++
+		e.makerWait += waited
+	}
+	if takerAmount.IsPositive() {
+		e.stats.TakerFallbacks++
+	}
+	e.stats.MakerAmount = e.stats.MakerAmount.Add(makerFilled)
+	e.stats.TakerAmount = e.stats.TakerAmount.Add(takerAmount)
+	e.stats.MakerNotional = e.stats.MakerNotional.Add(makerFilled.Mul(makerPrice))
+}
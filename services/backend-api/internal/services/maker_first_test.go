@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// makerTestExecutor rests limit orders with the given status and fill, and
+// records every order placed.
+type makerTestExecutor struct {
+	types     []string
+	amounts   []decimal.Decimal
+	prices    []*decimal.Decimal
+	options   []OrderOptions
+	status    string
+	filled    float64
+	rejectPO  bool
+	cancelled []string
+}
+
+func (e *makerTestExecutor) PlaceOrder(ctx context.Context, exchange, symbol, side, orderType string, amount decimal.Decimal, price *decimal.Decimal) (string, error) {
+	return e.PlaceOrderWithOptions(ctx, exchange, symbol, side, orderType, amount, price, OrderOptions{})
+}
+
+func (e *makerTestExecutor) PlaceOrderWithOptions(_ context.Context, _, _, _, orderType string, amount decimal.Decimal, price *decimal.Decimal, options OrderOptions) (string, error) {
+	if options.PostOnly && e.rejectPO {
+		return "", ErrPostOnlyRejected
+	}
+	e.types = append(e.types, orderType)
+	e.amounts = append(e.amounts, amount)
+	e.prices = append(e.prices, price)
+	e.options = append(e.options, options)
+	return fmt.Sprintf("order-%d", len(e.types)), nil
+}
+
+func (e *makerTestExecutor) GetOpenOrders(context.Context, string, string) ([]map[string]interface{}, error) {
+	return nil, nil
+}
+
+func (e *makerTestExecutor) GetOrder(_ context.Context, _, orderID string) (map[string]interface{}, error) {
+	return map[string]interface{}{"order": map[string]interface{}{"id": orderID, "status": e.status, "filled": e.filled}}, nil
+}
+
+func (e *makerTestExecutor) CancelOrder(_ context.Context, _, orderID string) error {
+	e.cancelled = append(e.cancelled, orderID)
+	return nil
+}
+
+type makerTestQuotes struct{ bid, ask float64 }
+
+func (q makerTestQuotes) FetchSingleTicker(_ context.Context, exchange, symbol string) (ccxt.MarketPriceInterface, error) {
+	return &models.MarketPrice{ExchangeName: exchange, Symbol: symbol, Bid: decimal.NewFromFloat(q.bid), Ask: decimal.NewFromFloat(q.ask)}, nil
+}
+
+// newTestMakerFirstExecutor advances a fake clock by the poll interval on
+// every sleep.
+func newTestMakerFirstExecutor(executor *makerTestExecutor, config MakerFirstConfig) *MakerFirstExecutor {
+	maker := NewMakerFirstExecutor(executor, makerTestQuotes{bid: 100, ask: 101}, config)
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	maker.now = func() time.Time { return clock }
+	maker.sleep = func(ctx context.Context, d time.Duration) error {
+		clock = clock.Add(d)
+		return ctx.Err()
+	}
+	return maker
+}
+
+func TestMakerFirstExecutor_MakerFill(t *testing.T) {
+	executor := &makerTestExecutor{status: "closed", filled: 2}
+	maker := newTestMakerFirstExecutor(executor, MakerFirstConfig{InsideSpread: 0.5})
+
+	orderID, err := maker.PlaceOrderWithOptions(t.Context(), "binance", "BTC/USDT", "buy", "limit", decimal.NewFromInt(2), nil, OrderOptions{MakerFirst: true})
+	require.NoError(t, err)
+	assert.Equal(t, "order-1", orderID)
+	require.Len(t, executor.types, 1)
+	assert.Equal(t, OrderOptions{PostOnly: true}, executor.options[0])
+	assert.Equal(t, "100.5", executor.prices[0].String(), "half the spread inside the bid")
+
+	stats := maker.ExecutionQuality()
+	assert.Equal(t, 1, stats.MakerFilled)
+	assert.Equal(t, 0, stats.TakerFallbacks)
+	assert.Equal(t, 1.0, stats.MakerFillRate)
+	assert.Equal(t, "201", stats.MakerNotional.String())
+	assert.Equal(t, "0.0603", stats.EstimatedFeeSavings.String(), "201 at a 0.03% fee gap")
+}
+
+func TestMakerFirstExecutor_TakerFallbackAfterTimeout(t *testing.T) {
+	executor := &makerTestExecutor{status: "open", filled: 0.5}
+	maker := newTestMakerFirstExecutor(executor, MakerFirstConfig{Timeout: 5 * time.Second, PollInterval: time.Second})
+
+	orderID, err := maker.PlaceOrderWithOptions(t.Context(), "binance", "BTC/USDT", "sell", "limit", decimal.NewFromInt(2), nil, OrderOptions{MakerFirst: true})
+	require.NoError(t, err)
+	assert.Equal(t, "order-2", orderID, "the taker order's ID")
+	assert.Equal(t, []string{"order-1"}, executor.cancelled)
+	assert.Equal(t, []string{"limit", "market"}, executor.types)
+	assert.Equal(t, "101", executor.prices[0].String(), "sells join the ask")
+	assert.Equal(t, "1.5", executor.amounts[1].String(), "only the unfilled rest is taken")
+
+	stats := maker.ExecutionQuality()
+	assert.Equal(t, 1, stats.PartialMaker)
+	assert.Equal(t, 1, stats.TakerFallbacks)
+	assert.Equal(t, 0.25, stats.MakerFillRate)
+	assert.Equal(t, "5s", stats.AverageMakerWait)
+}
+
+func TestMakerFirstExecutor_PostOnlyRejectedTakes(t *testing.T) {
+	executor := &makerTestExecutor{rejectPO: true}
+	maker := newTestMakerFirstExecutor(executor, MakerFirstConfig{})
+
+	_, err := maker.PlaceOrderWithOptions(t.Context(), "binance", "BTC/USDT", "buy", "limit", decimal.NewFromInt(1), nil, OrderOptions{MakerFirst: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"market"}, executor.types)
+	assert.Equal(t, 0.0, maker.ExecutionQuality().MakerFillRate)
+}
+
+func TestMakerFirstExecutor_PassesOtherOrdersThrough(t *testing.T) {
+	executor := &makerTestExecutor{}
+	maker := newTestMakerFirstExecutor(executor, MakerFirstConfig{})
+	ioc := OrderOptions{TimeInForce: TimeInForceIOC}
+
+	_, err := maker.PlaceOrderWithOptions(t.Context(), "binance", "BTC/USDT", "buy", "limit", decimal.NewFromInt(1), nil, ioc)
+	require.NoError(t, err)
+	assert.Equal(t, []OrderOptions{ioc}, executor.options)
+	assert.Zero(t, maker.ExecutionQuality().Orders)
+}
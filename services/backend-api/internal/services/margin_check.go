@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/irfndi/neuratrade/pkg/interfaces"
+	"github.com/shopspring/decimal"
+)
+
+var defaultMarginCheckFeeRate = decimal.NewFromFloat(0.001)
+
+// MarginBalanceFetcher fetches exchange balances for the pre-trade margin check.
+type MarginBalanceFetcher interface {
+	FetchBalance(ctx context.Context, exchange string) (*ccxt.BalanceResponse, error)
+}
+
+// MarginPositionSource lists open positions so orders that close them are
+// not checked.
+type MarginPositionSource interface {
+	GetOpenPositions() []interfaces.Position
+}
+
+// MarginCheckConfig configures the pre-trade margin check.
+type MarginCheckConfig struct {
+	// MinFreeMargin is the free collateral, in the collateral currency, an
+	// order may not push the account below.
+	MinFreeMargin decimal.Decimal
+	// MinFreeMarginRatio is the same floor as a fraction of the collateral
+	// currency's total balance, e.g. 0.1; the higher floor applies.
+	MinFreeMarginRatio decimal.Decimal
+	// Leverage divides the cost of derivative orders (symbols with a settle
+	// currency, such as BTC/USDT:USDT); zero means unleveraged.
+	Leverage decimal.Decimal
+	// FeeRate is added to the order cost; zero uses 0.1%.
+	FeeRate decimal.Decimal
+}
+
+// MarginImpact is the simulated effect of an order on free collateral.
+type MarginImpact struct {
+	Currency  string          `json:"currency"`
+	Free      decimal.Decimal `json:"free"`
+	Cost      decimal.Decimal `json:"cost"`
+	FreeAfter decimal.Decimal `json:"free_after"`
+	Floor     decimal.Decimal `json:"floor"`
+}
+
+// MarginCheck is a pre-trade hook that simulates an order's margin impact
+// against the exchange balance and vetoes orders that would push free
+// collateral below the configured floor, instead of waiting for the exchange
+// to reject them for insufficient balance. Spot buys and derivative orders
+// consume the quote or settle currency; spot sells only need the base
+// currency they sell. Orders closing a known open position are always let
+// through, and the check fails open when the balance or price is unavailable,
+// leaving the decision to the exchange.
+type MarginCheck struct {
+	balances  MarginBalanceFetcher
+	tickers   TickerFetcher
+	positions MarginPositionSource
+	events    EventEmitter
+	config    MarginCheckConfig
+	logger    *slog.Logger
+}
+
+var _ PreTradeHook = (*MarginCheck)(nil)
+
+// NewMarginCheck creates the pre-trade margin check.
+//
+// Parameters:
+//
+//	balances: Exchange balance source.
+//	tickers: Prices market orders placed without a price (may be nil).
+//	config: Margin floors; zero values disable them.
+//
+// Returns:
+//
+//	*MarginCheck: Initialized hook.
+func NewMarginCheck(balances MarginBalanceFetcher, tickers TickerFetcher, config MarginCheckConfig) *MarginCheck {
+	if !config.FeeRate.IsPositive() {
+		config.FeeRate = defaultMarginCheckFeeRate
+	}
+	if !config.Leverage.IsPositive() {
+		config.Leverage = decimal.NewFromInt(1)
+	}
+	return &MarginCheck{
+		balances: balances,
+		tickers:  tickers,
+		config:   config,
+		logger:   telemetry.Logger().With("component", "margin_check"),
+	}
+}
+
+// SetPositionSource lets orders that close an open position through unchecked.
+func (m *MarginCheck) SetPositionSource(positions MarginPositionSource) {
+	m.positions = positions
+}
+
+// SetEventEmitter publishes rejected orders as margin_check_rejected risk events.
+func (m *MarginCheck) SetEventEmitter(events EventEmitter) {
+	m.events = events
+}
+
+// PreTrade vetoes orders that would leave too little free collateral.
+func (m *MarginCheck) PreTrade(ctx context.Context, order HookOrder) (PreTradeDecision, error) {
+	if m.closesPosition(order) {
+		return PreTradeDecision{}, nil
+	}
+	impact, err := m.Simulate(ctx, order)
+	if err != nil {
+		m.logger.Warn("Margin check skipped", "exchange", order.Exchange, "symbol", order.Symbol, "error", err)
+		return PreTradeDecision{}, nil
+	}
+	if !impact.FreeAfter.LessThan(impact.Floor) {
+		return PreTradeDecision{}, nil
+	}
+
+	reason := fmt.Sprintf("%s %s %s would leave %s %s free, below the %s floor (free %s, cost %s)",
+		order.Side, order.Amount.String(), order.Symbol, impact.FreeAfter.StringFixed(2), impact.Currency,
+		impact.Floor.StringFixed(2), impact.Free.StringFixed(2), impact.Cost.StringFixed(2))
+	m.logger.Warn("Order rejected by pre-trade margin check",
+		"exchange", order.Exchange, "symbol", order.Symbol, "side", order.Side, "amount", order.Amount.String(),
+		"currency", impact.Currency, "free", impact.Free.String(), "cost", impact.Cost.String(),
+		"free_after", impact.FreeAfter.String(), "floor", impact.Floor.String())
+	if m.events != nil {
+		m.events.Emit(ctx, WebhookEventRisk, map[string]interface{}{
+			"type":       "margin_check_rejected",
+			"exchange":   order.Exchange,
+			"symbol":     order.Symbol,
+			"side":       order.Side,
+			"amount":     order.Amount.String(),
+			"currency":   impact.Currency,
+			"free":       impact.Free.String(),
+			"cost":       impact.Cost.String(),
+			"free_after": impact.FreeAfter.String(),
+			"floor":      impact.Floor.String(),
+		})
+	}
+	return PreTradeDecision{Veto: true, Reason: reason}, nil
+}
+
+// Simulate computes the free collateral an order would leave.
+//
+// Parameters:
+//
+//	ctx: Context for the balance and ticker requests.
+//	order: Order about to be placed.
+//
+// Returns:
+//
+//	*MarginImpact: Free balance before and after the order and the floor.
+//	error: Error if the symbol, balance or price is unavailable.
+func (m *MarginCheck) Simulate(ctx context.Context, order HookOrder) (*MarginImpact, error) {
+	base, quote, ok := strings.Cut(order.Symbol, "/")
+	if !ok || base == "" || quote == "" {
+		return nil, fmt.Errorf("cannot parse symbol %q", order.Symbol)
+	}
+	quote, settle, derivative := strings.Cut(quote, ":")
+	side := strings.ToLower(order.Side)
+
+	balance, err := m.balances.FetchBalance(ctx, order.Exchange)
+	if err != nil {
+		return nil, err
+	}
+	if balance == nil {
+		return nil, fmt.Errorf("no balance returned for %s", order.Exchange)
+	}
+
+	// A spot sell spends the base currency and adds to free collateral
+	if !derivative && side == "sell" {
+		free := decimal.NewFromFloat(balance.Free[strings.ToUpper(base)])
+		return &MarginImpact{
+			Currency:  strings.ToUpper(base),
+			Free:      free,
+			Cost:      order.Amount,
+			FreeAfter: free.Sub(order.Amount),
+			Floor:     decimal.Zero,
+		}, nil
+	}
+
+	price, err := m.price(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	currency := strings.ToUpper(quote)
+	cost := order.Amount.Mul(price).Mul(decimal.NewFromInt(1).Add(m.config.FeeRate))
+	if derivative {
+		if settle != "" {
+			currency = strings.ToUpper(settle)
+		}
+		cost = cost.Div(m.config.Leverage)
+	}
+	free := decimal.NewFromFloat(balance.Free[currency])
+	floor := decimal.Max(m.config.MinFreeMargin, decimal.NewFromFloat(balance.Total[currency]).Mul(m.config.MinFreeMarginRatio))
+	return &MarginImpact{
+		Currency:  currency,
+		Free:      free,
+		Cost:      cost,
+		FreeAfter: free.Sub(cost),
+		Floor:     floor,
+	}, nil
+}
+
+func (m *MarginCheck) price(ctx context.Context, order HookOrder) (decimal.Decimal, error) {
+	if order.Price != nil && order.Price.IsPositive() {
+		return *order.Price, nil
+	}
+	if m.tickers == nil {
+		return decimal.Zero, fmt.Errorf("no price for %s order on %s", order.OrderType, order.Symbol)
+	}
+	ticker, err := m.tickers.FetchSingleTicker(ctx, order.Exchange, order.Symbol)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to price %s: %w", order.Symbol, err)
+	}
+	if ticker == nil || ticker.GetPrice() <= 0 {
+		return decimal.Zero, fmt.Errorf("no price for %s", order.Symbol)
+	}
+	return decimal.NewFromFloat(ticker.GetPrice()), nil
+}
+
+// closesPosition reports whether the order closes a tracked open position.
+func (m *MarginCheck) closesPosition(order HookOrder) bool {
+	if m.positions == nil {
+		return false
+	}
+	return closesOpenPosition(m.positions.GetOpenPositions(), order)
+}
+
+// closesOpenPosition reports whether the order is on the opposite side of an
+// open position on the same market and no larger than it.
+func closesOpenPosition(positions []interfaces.Position, order HookOrder) bool {
+	for _, position := range positions {
+		if !strings.EqualFold(position.Exchange, order.Exchange) ||
+			normalizeSymbolForComparison(position.Symbol) != normalizeSymbolForComparison(order.Symbol) {
+			continue
+		}
+		side := strings.ToLower(position.Side)
+		switch side {
+		case "long":
+			side = "buy"
+		case "short":
+			side = "sell"
+		}
+		if side != strings.ToLower(order.Side) && !order.Amount.GreaterThan(position.Size) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/pkg/interfaces"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type marginTestBalances struct {
+	free  map[string]float64
+	total map[string]float64
+	err   error
+}
+
+func (b *marginTestBalances) FetchBalance(_ context.Context, exchange string) (*ccxt.BalanceResponse, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return &ccxt.BalanceResponse{Exchange: exchange, Free: b.free, Total: b.total}, nil
+}
+
+type marginTestPositions []interfaces.Position
+
+func (p marginTestPositions) GetOpenPositions() []interfaces.Position { return p }
+
+func marginTestOrder(symbol, side string, amount, price float64) HookOrder {
+	order := HookOrder{Exchange: "binance", Symbol: symbol, Side: side, OrderType: "limit", Amount: decimal.NewFromFloat(amount)}
+	if price > 0 {
+		p := decimal.NewFromFloat(price)
+		order.Price = &p
+	}
+	return order
+}
+
+func TestMarginCheck_SpotBuyFloor(t *testing.T) {
+	balances := &marginTestBalances{free: map[string]float64{"USDT": 1000}, total: map[string]float64{"USDT": 2000}}
+	check := NewMarginCheck(balances, nil, MarginCheckConfig{
+		MinFreeMargin:      decimal.NewFromInt(100),
+		MinFreeMarginRatio: decimal.NewFromFloat(0.25),
+		FeeRate:            decimal.NewFromFloat(0.001),
+	})
+	events := &hookEventCapture{}
+	check.SetEventEmitter(events)
+
+	// 0.01 BTC at 40000 costs 400.40 and leaves 599.60, above the 500 floor
+	decision, err := check.PreTrade(t.Context(), marginTestOrder("BTC/USDT", "buy", 0.01, 40000))
+	require.NoError(t, err)
+	assert.False(t, decision.Veto)
+
+	impact, err := check.Simulate(t.Context(), marginTestOrder("BTC/USDT", "buy", 0.015, 40000))
+	require.NoError(t, err)
+	assert.Equal(t, "USDT", impact.Currency)
+	assert.Equal(t, "500", impact.Floor.String(), "the ratio floor is higher than the absolute one")
+	assert.Equal(t, "399.4", impact.FreeAfter.String())
+
+	decision, err = check.PreTrade(t.Context(), marginTestOrder("BTC/USDT", "buy", 0.015, 40000))
+	require.NoError(t, err)
+	assert.True(t, decision.Veto)
+	assert.Contains(t, decision.Reason, "would leave 399.40 USDT free, below the 500.00 floor")
+	require.Len(t, events.events, 1)
+	assert.Equal(t, WebhookEventRisk, events.events[0].event)
+	assert.Equal(t, "margin_check_rejected", events.events[0].data.(map[string]interface{})["type"])
+}
+
+func TestMarginCheck_SpotSellNeedsBase(t *testing.T) {
+	balances := &marginTestBalances{free: map[string]float64{"BTC": 0.5, "USDT": 0}}
+	check := NewMarginCheck(balances, nil, MarginCheckConfig{MinFreeMargin: decimal.NewFromInt(100)})
+
+	decision, err := check.PreTrade(t.Context(), marginTestOrder("BTC/USDT", "sell", 0.5, 0))
+	require.NoError(t, err)
+	assert.False(t, decision.Veto, "a sell adds quote currency, so only the base balance matters")
+
+	decision, err = check.PreTrade(t.Context(), marginTestOrder("BTC/USDT", "sell", 0.6, 0))
+	require.NoError(t, err)
+	assert.True(t, decision.Veto)
+}
+
+func TestMarginCheck_DerivativeUsesLeverageAndTicker(t *testing.T) {
+	balances := &marginTestBalances{free: map[string]float64{"USDT": 1000}}
+	tickers := &fakeTickerFetcher{prices: map[string]float64{"binance:BTC/USDT:USDT": 50000}}
+	check := NewMarginCheck(balances, tickers, MarginCheckConfig{Leverage: decimal.NewFromInt(10), FeeRate: decimal.NewFromFloat(0.0005)})
+
+	order := marginTestOrder("BTC/USDT:USDT", "sell", 0.1, 0)
+	order.OrderType = "market"
+	impact, err := check.Simulate(t.Context(), order)
+	require.NoError(t, err)
+	assert.Equal(t, "500.25", impact.Cost.String(), "a short consumes margin too")
+
+	order.Amount = decimal.NewFromFloat(0.3)
+	decision, err := check.PreTrade(t.Context(), order)
+	require.NoError(t, err)
+	assert.True(t, decision.Veto)
+
+	check.SetPositionSource(marginTestPositions{{Exchange: "binance", Symbol: "BTC/USDT:USDT", Side: "long", Size: decimal.NewFromFloat(0.3)}})
+	decision, err = check.PreTrade(t.Context(), order)
+	require.NoError(t, err)
+	assert.False(t, decision.Veto, "closing a position is never blocked")
+}
+
+func TestMarginCheck_FailsOpen(t *testing.T) {
+	check := NewMarginCheck(&marginTestBalances{err: errors.New("exchange unavailable")}, nil, MarginCheckConfig{})
+	decision, err := check.PreTrade(t.Context(), marginTestOrder("BTC/USDT", "buy", 1, 40000))
+	require.NoError(t, err)
+	assert.False(t, decision.Veto)
+
+	check = NewMarginCheck(&marginTestBalances{free: map[string]float64{}}, nil, MarginCheckConfig{})
+	order := marginTestOrder("BTC/USDT", "buy", 1, 0)
+	order.OrderType = "market"
+	decision, err = check.PreTrade(t.Context(), order)
+	require.NoError(t, err)
+	assert.False(t, decision.Veto, "an unpriced order is left to the exchange")
+}
+
+func TestMarginCheck_VetoesThroughHookRegistry(t *testing.T) {
+	registry := NewHookRegistry(HookConfig{})
+	check := NewMarginCheck(&marginTestBalances{free: map[string]float64{"USDT": 10}}, nil, MarginCheckConfig{})
+	require.NoError(t, registry.Register("margin_check", check))
+
+	_, err := registry.RunPreTrade(t.Context(), marginTestOrder("ETH/USDT", "buy", 1, 2000))
+	assert.ErrorIs(t, err, ErrTradeVetoed)
+	assert.Contains(t, err.Error(), "margin_check")
+}
@@ -0,0 +1,393 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+)
+
+const (
+	defaultMarginVerifyTTL          = 10 * time.Minute
+	defaultMarginScanInterval       = time.Minute
+	defaultMarginRatioWarn          = 0.5
+	defaultMarginRatioCritical      = 0.8
+	marginRiskLevelWarning          = "warning"
+	marginRiskLevelCritical         = "critical"
+	marginLeverageComparisonEpsilon = 1e-9
+	marginModeIsolated              = "isolated"
+	marginModeCross                 = "cross"
+)
+
+// ErrLeverageMismatch is returned when the exchange does not report the
+// leverage or margin mode a strategy is configured with.
+var ErrLeverageMismatch = errors.New("leverage not applied on exchange")
+
+// MarginClient reads and changes account leverage, margin mode and
+// positions. It is implemented by ccxt.Service.
+type MarginClient interface {
+	FetchMarginSettings(ctx context.Context, exchange, symbol string) (*ccxt.MarginSettings, error)
+	UpdateMarginSettings(ctx context.Context, exchange string, req ccxt.SetLeverageRequest) (*ccxt.MarginSettings, error)
+	FetchPositions(ctx context.Context, exchange string, symbols []string) ([]ccxt.PositionInfo, error)
+}
+
+// LeverageGuard makes sure a symbol trades at the expected leverage before an
+// entry is placed.
+type LeverageGuard interface {
+	EnsureLeverage(ctx context.Context, exchange, symbol string, leverage float64) error
+}
+
+// MarginConfig configures leverage enforcement and margin monitoring.
+type MarginConfig struct {
+	// MarginMode is enforced together with leverage; empty keeps the
+	// account's current mode.
+	MarginMode string
+	// VerifyTTL is how long a verified symbol is trusted before it is
+	// checked on the exchange again.
+	VerifyTTL time.Duration
+	// Exchanges are scanned for positions nearing liquidation.
+	Exchanges []string
+	// ScanInterval is how often positions are scanned.
+	ScanInterval time.Duration
+	// MarginRatioWarn and MarginRatioCritical are the maintenance margin over
+	// collateral ratios that raise risk events.
+	MarginRatioWarn     float64
+	MarginRatioCritical float64
+}
+
+type verifiedMargin struct {
+	leverage float64
+	mode     string
+	expires  time.Time
+}
+
+// MarginManager applies per-symbol leverage and margin mode, verifies them
+// before strategy entries and raises risk events when a position's margin
+// ratio approaches liquidation.
+type MarginManager struct {
+	client MarginClient
+	config MarginConfig
+	events EventEmitter
+	logger *slog.Logger
+	now    func() time.Time
+
+	mu       sync.Mutex
+	verified map[string]verifiedMargin
+	// alerted is the last risk level raised per position.
+	alerted map[string]string
+
+	runMu  sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Ensure MarginManager implements LeverageGuard.
+var _ LeverageGuard = (*MarginManager)(nil)
+
+// NewMarginManager creates the leverage and margin manager.
+//
+// Parameters:
+//
+//	client: Exchange access for margin settings and positions.
+//	config: Manager configuration; zero values use defaults.
+//
+// Returns:
+//
+//	*MarginManager: Initialized manager.
+func NewMarginManager(client MarginClient, config MarginConfig) *MarginManager {
+	config.MarginMode = strings.ToLower(config.MarginMode)
+	if config.MarginMode != marginModeIsolated && config.MarginMode != marginModeCross {
+		config.MarginMode = ""
+	}
+	if config.VerifyTTL <= 0 {
+		config.VerifyTTL = defaultMarginVerifyTTL
+	}
+	if config.ScanInterval <= 0 {
+		config.ScanInterval = defaultMarginScanInterval
+	}
+	if config.MarginRatioWarn <= 0 {
+		config.MarginRatioWarn = defaultMarginRatioWarn
+	}
+	if config.MarginRatioCritical <= config.MarginRatioWarn {
+		config.MarginRatioCritical = defaultMarginRatioCritical
+		if config.MarginRatioCritical <= config.MarginRatioWarn {
+			config.MarginRatioCritical = config.MarginRatioWarn
+		}
+	}
+	return &MarginManager{
+		client:   client,
+		config:   config,
+		logger:   telemetry.Logger(),
+		now:      time.Now,
+		verified: make(map[string]verifiedMargin),
+		alerted:  make(map[string]string),
+	}
+}
+
+// Config returns the manager configuration.
+func (m *MarginManager) Config() MarginConfig {
+	return m.config
+}
+
+// SetEventEmitter publishes leverage mismatches and high margin ratios as
+// risk events.
+func (m *MarginManager) SetEventEmitter(events EventEmitter) {
+	m.events = events
+}
+
+// Settings returns the leverage and margin mode of a symbol.
+func (m *MarginManager) Settings(ctx context.Context, exchange, symbol string) (*ccxt.MarginSettings, error) {
+	return m.client.FetchMarginSettings(ctx, exchange, symbol)
+}
+
+// Apply sets the leverage and optionally the margin mode of a symbol and
+// verifies the exchange reports them back.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	exchange: Exchange identifier.
+//	symbol: Unified derivatives symbol.
+//	leverage: Leverage for both sides.
+//	marginMode: isolated, cross or empty to keep the current mode.
+//
+// Returns:
+//
+//	*ccxt.MarginSettings: Settings read back from the exchange.
+//	error: ErrLeverageMismatch if they differ from the request.
+func (m *MarginManager) Apply(ctx context.Context, exchange, symbol string, leverage float64, marginMode string) (*ccxt.MarginSettings, error) {
+	marginMode = strings.ToLower(marginMode)
+	if marginMode != "" && marginMode != marginModeIsolated && marginMode != marginModeCross {
+		return nil, fmt.Errorf("margin mode must be %s or %s", marginModeIsolated, marginModeCross)
+	}
+	if leverage < 1 {
+		return nil, fmt.Errorf("leverage must be at least 1")
+	}
+
+	settings, err := m.client.UpdateMarginSettings(ctx, exchange, ccxt.SetLeverageRequest{
+		Symbol:     symbol,
+		Leverage:   leverage,
+		MarginMode: marginMode,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !marginMatches(settings, leverage, marginMode) {
+		return settings, fmt.Errorf("%w: %s on %s reports %s", ErrLeverageMismatch, symbol, exchange, describeMargin(settings))
+	}
+	m.remember(exchange, symbol, leverage, marginMode)
+	return settings, nil
+}
+
+// EnsureLeverage verifies a derivatives symbol trades at the given leverage
+// and the configured margin mode, applying them if it does not. Spot symbols
+// carry no leverage and pass unchecked. A failure is emitted as a risk event
+// and the entry should not be placed.
+func (m *MarginManager) EnsureLeverage(ctx context.Context, exchange, symbol string, leverage float64) error {
+	if !strings.Contains(symbol, ":") || leverage <= 0 {
+		return nil
+	}
+	mode := m.config.MarginMode
+	if m.isVerified(exchange, symbol, leverage, mode) {
+		return nil
+	}
+
+	current, err := m.client.FetchMarginSettings(ctx, exchange, symbol)
+	if err == nil && marginMatches(current, leverage, mode) {
+		m.remember(exchange, symbol, leverage, mode)
+		return nil
+	}
+
+	if _, err = m.Apply(ctx, exchange, symbol, leverage, mode); err != nil {
+		m.logger.Warn("Strategy leverage could not be applied",
+			"exchange", exchange, "symbol", symbol, "leverage", leverage, "margin_mode", mode, "error", err)
+		m.emit(ctx, map[string]interface{}{
+			"type":        "leverage_mismatch",
+			"exchange":    exchange,
+			"symbol":      symbol,
+			"leverage":    leverage,
+			"margin_mode": mode,
+			"error":       err.Error(),
+		})
+		return err
+	}
+	m.logger.Info("Applied strategy leverage", "exchange", exchange, "symbol", symbol, "leverage", leverage, "margin_mode", mode)
+	return nil
+}
+
+// Positions returns the open derivatives positions of an exchange account
+// with their margin ratio.
+func (m *MarginManager) Positions(ctx context.Context, exchange string) ([]ccxt.PositionInfo, error) {
+	return m.client.FetchPositions(ctx, exchange, nil)
+}
+
+// Start scans the configured exchanges on ScanInterval until Stop.
+func (m *MarginManager) Start(ctx context.Context) error {
+	m.runMu.Lock()
+	defer m.runMu.Unlock()
+	if m.cancel != nil {
+		return fmt.Errorf("margin manager already running")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	m.cancel = cancel
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(m.config.ScanInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.Scan(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop ends the scan loop.
+func (m *MarginManager) Stop() {
+	m.runMu.Lock()
+	cancel := m.cancel
+	m.cancel = nil
+	m.runMu.Unlock()
+	if cancel != nil {
+		cancel()
+		m.wg.Wait()
+	}
+}
+
+// Scan checks the positions of every configured exchange and emits a risk
+// event when a margin ratio crosses the warning or critical level. A level is
+// raised once until the ratio falls back below the warning level.
+func (m *MarginManager) Scan(ctx context.Context) {
+	for _, exchange := range m.config.Exchanges {
+		positions, err := m.client.FetchPositions(ctx, exchange, nil)
+		if err != nil {
+			m.logger.Warn("Failed to fetch positions for margin scan", "exchange", exchange, "error", err)
+			continue
+		}
+
+		seen := make(map[string]bool, len(positions))
+		for _, p := range positions {
+			key := exchange + "|" + p.Symbol + "|" + p.Side
+			seen[key] = true
+			level := m.riskLevel(p.MarginRatio)
+			if !m.escalated(key, level) {
+				continue
+			}
+			m.logger.Warn("Position margin ratio is high",
+				"exchange", exchange, "symbol", p.Symbol, "margin_ratio", p.MarginRatio, "level", level)
+			m.emit(ctx, map[string]interface{}{
+				"type":              "margin_ratio_high",
+				"level":             level,
+				"exchange":          exchange,
+				"symbol":            p.Symbol,
+				"side":              p.Side,
+				"margin_ratio":      p.MarginRatio,
+				"leverage":          p.Leverage,
+				"margin_mode":       p.MarginMode,
+				"liquidation_price": p.LiquidationPrice,
+				"mark_price":        p.MarkPrice,
+			})
+		}
+		m.forgetClosed(exchange, seen)
+	}
+}
+
+func (m *MarginManager) riskLevel(ratio float64) string {
+	switch {
+	case ratio >= m.config.MarginRatioCritical:
+		return marginRiskLevelCritical
+	case ratio >= m.config.MarginRatioWarn:
+		return marginRiskLevelWarning
+	default:
+		return ""
+	}
+}
+
+// escalated records the position's level and reports whether it rose.
+func (m *MarginManager) escalated(key, level string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	previous := m.alerted[key]
+	if level == "" {
+		delete(m.alerted, key)
+		return false
+	}
+	m.alerted[key] = level
+	return previous == "" || (previous == marginRiskLevelWarning && level == marginRiskLevelCritical)
+}
+
+func (m *MarginManager) forgetClosed(exchange string, open map[string]bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key := range m.alerted {
+		if strings.HasPrefix(key, exchange+"|") && !open[key] {
+			delete(m.alerted, key)
+		}
+	}
+}
+
+func (m *MarginManager) isVerified(exchange, symbol string, leverage float64, mode string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.verified[exchange+"|"+symbol]
+	return ok && m.now().Before(v.expires) && sameLeverage(v.leverage, leverage) && v.mode == mode
+}
+
+func (m *MarginManager) remember(exchange, symbol string, leverage float64, mode string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.verified[exchange+"|"+symbol] = verifiedMargin{leverage: leverage, mode: mode, expires: m.now().Add(m.config.VerifyTTL)}
+}
+
+func (m *MarginManager) emit(ctx context.Context, data map[string]interface{}) {
+	if m.events == nil {
+		return
+	}
+	m.events.Emit(ctx, WebhookEventRisk, data)
+}
+
+// marginMatches reports whether settings carry the leverage on every side the
+// exchange reports and, if one is requested, the margin mode.
+func marginMatches(settings *ccxt.MarginSettings, leverage float64, mode string) bool {
+	if settings == nil {
+		return false
+	}
+	if settings.LongLeverage == 0 && settings.ShortLeverage == 0 {
+		return false
+	}
+	if settings.LongLeverage != 0 && !sameLeverage(settings.LongLeverage, leverage) {
+		return false
+	}
+	if settings.ShortLeverage != 0 && !sameLeverage(settings.ShortLeverage, leverage) {
+		return false
+	}
+	return mode == "" || strings.EqualFold(settings.MarginMode, mode)
+}
+
+func sameLeverage(a, b float64) bool {
+	diff := a - b
+	return diff < marginLeverageComparisonEpsilon && diff > -marginLeverageComparisonEpsilon
+}
+
+func describeMargin(settings *ccxt.MarginSettings) string {
+	if settings == nil {
+		return "no settings"
+	}
+	mode := settings.MarginMode
+	if mode == "" {
+		mode = "unknown"
+	}
+	return fmt.Sprintf("%gx long, %gx short, %s margin", settings.LongLeverage, settings.ShortLeverage, mode)
+}
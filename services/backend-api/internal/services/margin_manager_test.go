@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMarginClient struct {
+	settings  ccxt.MarginSettings
+	positions []ccxt.PositionInfo
+	// ignoreSet simulates an exchange that accepts but does not apply changes.
+	ignoreSet bool
+	fetches   int
+	updates   []ccxt.SetLeverageRequest
+}
+
+func (f *fakeMarginClient) FetchMarginSettings(_ context.Context, _, symbol string) (*ccxt.MarginSettings, error) {
+	f.fetches++
+	settings := f.settings
+	settings.Symbol = symbol
+	return &settings, nil
+}
+
+func (f *fakeMarginClient) UpdateMarginSettings(_ context.Context, _ string, req ccxt.SetLeverageRequest) (*ccxt.MarginSettings, error) {
+	f.updates = append(f.updates, req)
+	if !f.ignoreSet {
+		f.settings.LongLeverage = req.Leverage
+		f.settings.ShortLeverage = req.Leverage
+		if req.MarginMode != "" {
+			f.settings.MarginMode = req.MarginMode
+		}
+	}
+	settings := f.settings
+	settings.Symbol = req.Symbol
+	return &settings, nil
+}
+
+func (f *fakeMarginClient) FetchPositions(context.Context, string, []string) ([]ccxt.PositionInfo, error) {
+	return f.positions, nil
+}
+
+func TestMarginManager_EnsureLeverage(t *testing.T) {
+	client := &fakeMarginClient{settings: ccxt.MarginSettings{MarginMode: "cross", LongLeverage: 10, ShortLeverage: 10}}
+	manager := NewMarginManager(client, MarginConfig{MarginMode: "isolated"})
+	events := &hookEventCapture{}
+	manager.SetEventEmitter(events)
+	ctx := t.Context()
+
+	// Spot symbols carry no leverage
+	require.NoError(t, manager.EnsureLeverage(ctx, "binance", "BTC/USDT", 5))
+	assert.Zero(t, client.fetches)
+
+	require.NoError(t, manager.EnsureLeverage(ctx, "binance", "BTC/USDT:USDT", 5))
+	require.Len(t, client.updates, 1)
+	assert.Equal(t, ccxt.SetLeverageRequest{Symbol: "BTC/USDT:USDT", Leverage: 5, MarginMode: "isolated"}, client.updates[0])
+	assert.Empty(t, events.events)
+
+	// Verified settings are trusted until the TTL expires
+	require.NoError(t, manager.EnsureLeverage(ctx, "binance", "BTC/USDT:USDT", 5))
+	assert.Equal(t, 1, client.fetches)
+	manager.now = func() time.Time { return time.Now().Add(time.Hour) }
+	require.NoError(t, manager.EnsureLeverage(ctx, "binance", "BTC/USDT:USDT", 5))
+	assert.Equal(t, 2, client.fetches)
+	assert.Len(t, client.updates, 1)
+
+	// A change the exchange did not apply blocks the entry
+	client.ignoreSet = true
+	err := manager.EnsureLeverage(ctx, "binance", "ETH/USDT:USDT", 3)
+	require.ErrorIs(t, err, ErrLeverageMismatch)
+	require.Len(t, events.events, 1)
+	assert.Equal(t, WebhookEventRisk, events.events[0].event)
+	data := events.events[0].data.(map[string]interface{})
+	assert.Equal(t, "leverage_mismatch", data["type"])
+	assert.Equal(t, "ETH/USDT:USDT", data["symbol"])
+}
+
+func TestMarginManager_ScanEmitsMarginRatioOncePerLevel(t *testing.T) {
+	client := &fakeMarginClient{positions: []ccxt.PositionInfo{
+		{Symbol: "BTC/USDT:USDT", Side: "long", MarginRatio: 0.6, Leverage: 5},
+		{Symbol: "ETH/USDT:USDT", Side: "short", MarginRatio: 0.1},
+	}}
+	manager := NewMarginManager(client, MarginConfig{Exchanges: []string{"binance"}})
+	events := &hookEventCapture{}
+	manager.SetEventEmitter(events)
+
+	manager.Scan(t.Context())
+	require.Len(t, events.events, 1)
+	data := events.events[0].data.(map[string]interface{})
+	assert.Equal(t, "margin_ratio_high", data["type"])
+	assert.Equal(t, "warning", data["level"])
+	assert.Equal(t, 0.6, data["margin_ratio"])
+
+	manager.Scan(t.Context())
+	assert.Len(t, events.events, 1)
+
+	client.positions[0].MarginRatio = 0.85
+	manager.Scan(t.Context())
+	require.Len(t, events.events, 2)
+	assert.Equal(t, "critical", events.events[1].data.(map[string]interface{})["level"])
+
+	// Recovering resets the alert
+	client.positions[0].MarginRatio = 0.2
+	manager.Scan(t.Context())
+	client.positions[0].MarginRatio = 0.55
+	manager.Scan(t.Context())
+	assert.Len(t, events.events, 3)
+}
+
+type failingLeverageGuard struct{}
+
+func (failingLeverageGuard) EnsureLeverage(context.Context, string, string, float64) error {
+	return errors.New("exchange rejected leverage")
+}
+
+func TestAIScalpingService_ExecuteDecisionRequiresLeverage(t *testing.T) {
+	executor := &recordingOrderExecutor{}
+	service := NewAIScalpingService(DefaultAIScalpingConfig(), nil, nil, nil, executor, nil)
+	service.SetLeverageGuard(failingLeverageGuard{})
+	decision := &AITradingDecision{Action: "buy", Symbol: "BTC/USDT:USDT", SizePercent: 2}
+	audit := &DecisionAudit{}
+
+	err := service.executeDecision(t.Context(), decision, TradingPortfolio{USDTBalance: 1000}, 0, 0, audit)
+	require.Error(t, err)
+	assert.Empty(t, executor.amounts)
+	require.NotNil(t, audit.Order)
+	assert.Equal(t, DecisionOrderSkipped, audit.Order.Status)
+	assert.Contains(t, audit.Order.Error, "leverage not applied")
+}
@@ -0,0 +1,341 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	defaultMarketDataBatchSize     = 500
+	defaultMarketDataFlushInterval = time.Second
+	defaultMarketDataBufferSize    = 10000
+
+	// maxInsertParams keeps multi-row inserts under SQLite's bound parameter limit.
+	maxInsertParams = 999
+)
+
+var marketDataColumns = []string{
+	"exchange_id", "trading_pair_id",
+	"bid", "bid_volume", "ask", "ask_volume",
+	"last_price", "volume_24h",
+	"timestamp", "created_at",
+}
+
+// MarketDataRow is one market_data row waiting to be written.
+type MarketDataRow struct {
+	// Exchange is the exchange name; rows are buffered per exchange.
+	Exchange      string
+	ExchangeID    int
+	TradingPairID int
+	// Bid, BidVolume, Ask and AskVolume are NULL when the source has no quote.
+	Bid       decimal.NullDecimal
+	BidVolume decimal.NullDecimal
+	Ask       decimal.NullDecimal
+	AskVolume decimal.NullDecimal
+	LastPrice decimal.Decimal
+	Volume24h decimal.Decimal
+	Timestamp time.Time
+	CreatedAt time.Time
+}
+
+func (r MarketDataRow) values() []any {
+	return []any{
+		r.ExchangeID, r.TradingPairID,
+		r.Bid, r.BidVolume, r.Ask, r.AskVolume,
+		r.LastPrice, r.Volume24h,
+		r.Timestamp, r.CreatedAt,
+	}
+}
+
+// MarketDataWriterConfig configures market data write buffering.
+type MarketDataWriterConfig struct {
+	// BatchSize is the number of buffered rows of one exchange that triggers a flush.
+	BatchSize int
+	// FlushInterval is the longest a row waits before it is written.
+	FlushInterval time.Duration
+	// BufferSize is the maximum number of buffered rows per exchange; when it
+	// is reached the oldest rows are dropped.
+	BufferSize int
+}
+
+// MarketDataWriterStats reports the writer's counters since it was created.
+type MarketDataWriterStats struct {
+	Buffered int   `json:"buffered"`
+	Written  int64 `json:"written"`
+	Dropped  int64 `json:"dropped"`
+	Failed   int64 `json:"failed"`
+	Flushes  int64 `json:"flushes"`
+}
+
+// marketDataPartition buffers the rows of one exchange.
+type marketDataPartition struct {
+	exchange string
+	mu       sync.Mutex
+	rows     []MarketDataRow
+	// writeMu serializes flushes so batches of one exchange are written in order.
+	writeMu sync.Mutex
+	full    chan struct{}
+}
+
+// MarketDataWriter batches market data inserts. Each exchange has its own
+// buffer and flusher, so a slow exchange cannot delay the others. Batches are
+// written with COPY when the connection supports it and with multi-row
+// inserts otherwise.
+type MarketDataWriter struct {
+	db         DBPool
+	config     MarketDataWriterConfig
+	logger     *slog.Logger
+	mu         sync.Mutex
+	partitions map[string]*marketDataPartition
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+	written    atomic.Int64
+	dropped    atomic.Int64
+	failed     atomic.Int64
+	flushes    atomic.Int64
+}
+
+// NewMarketDataWriter creates a market data writer.
+//
+// Parameters:
+//
+//	db: Database connection.
+//	config: Buffering configuration; zero values use the defaults.
+//
+// Returns:
+//
+//	*MarketDataWriter: Initialized writer (not started).
+func NewMarketDataWriter(db DBPool, config MarketDataWriterConfig) *MarketDataWriter {
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultMarketDataBatchSize
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = defaultMarketDataFlushInterval
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = defaultMarketDataBufferSize
+	}
+	if config.BufferSize < config.BatchSize {
+		config.BufferSize = config.BatchSize
+	}
+	return &MarketDataWriter{
+		db:         db,
+		config:     config,
+		logger:     telemetry.Logger(),
+		partitions: make(map[string]*marketDataPartition),
+	}
+}
+
+// Start starts flushing buffered rows until Stop is called.
+func (w *MarketDataWriter) Start(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.cancel != nil {
+		return fmt.Errorf("market data writer already running")
+	}
+	w.ctx, w.cancel = context.WithCancel(ctx)
+	for _, partition := range w.partitions {
+		w.startPartitionLocked(partition)
+	}
+	return nil
+}
+
+// Stop stops the flushers and writes the remaining rows.
+//
+// Parameters:
+//
+//	ctx: Context for the final flush.
+func (w *MarketDataWriter) Stop(ctx context.Context) {
+	w.mu.Lock()
+	cancel := w.cancel
+	w.cancel = nil
+	w.mu.Unlock()
+	if cancel == nil {
+		return
+	}
+	cancel()
+	w.wg.Wait()
+	w.Flush(ctx)
+}
+
+// Enqueue buffers a row for the next flush of its exchange. When the buffer
+// is full the oldest row is dropped.
+//
+// Parameters:
+//
+//	row: The row to write.
+//
+// Returns:
+//
+//	bool: False when the writer is not running and the caller must write the row itself.
+func (w *MarketDataWriter) Enqueue(row MarketDataRow) bool {
+	partition := w.partition(row.Exchange)
+	if partition == nil {
+		return false
+	}
+
+	partition.mu.Lock()
+	if over := len(partition.rows) + 1 - w.config.BufferSize; over > 0 {
+		partition.rows = append(partition.rows[:0], partition.rows[over:]...)
+		w.dropped.Add(int64(over))
+		w.logger.Warn("Market data buffer full, dropping oldest rows",
+			"exchange", row.Exchange, "dropped", over)
+	}
+	partition.rows = append(partition.rows, row)
+	size := len(partition.rows)
+	partition.mu.Unlock()
+
+	if size >= w.config.BatchSize {
+		select {
+		case partition.full <- struct{}{}:
+		default:
+		}
+	}
+	return true
+}
+
+// Flush writes every buffered row now.
+//
+// Parameters:
+//
+//	ctx: Context for the writes.
+func (w *MarketDataWriter) Flush(ctx context.Context) {
+	w.mu.Lock()
+	partitions := make([]*marketDataPartition, 0, len(w.partitions))
+	for _, partition := range w.partitions {
+		partitions = append(partitions, partition)
+	}
+	w.mu.Unlock()
+
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].exchange < partitions[j].exchange })
+	for _, partition := range partitions {
+		w.flushPartition(ctx, partition)
+	}
+}
+
+// Stats returns the writer's counters and the number of buffered rows.
+func (w *MarketDataWriter) Stats() MarketDataWriterStats {
+	stats := MarketDataWriterStats{
+		Written: w.written.Load(),
+		Dropped: w.dropped.Load(),
+		Failed:  w.failed.Load(),
+		Flushes: w.flushes.Load(),
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, partition := range w.partitions {
+		partition.mu.Lock()
+		stats.Buffered += len(partition.rows)
+		partition.mu.Unlock()
+	}
+	return stats
+}
+
+// partition returns the exchange's partition, creating it on first use, or
+// nil when the writer is not running.
+func (w *MarketDataWriter) partition(exchange string) *marketDataPartition {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.cancel == nil {
+		return nil
+	}
+	partition, ok := w.partitions[exchange]
+	if !ok {
+		partition = &marketDataPartition{exchange: exchange, full: make(chan struct{}, 1)}
+		w.partitions[exchange] = partition
+		w.startPartitionLocked(partition)
+	}
+	return partition
+}
+
+func (w *MarketDataWriter) startPartitionLocked(partition *marketDataPartition) {
+	ctx := w.ctx
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(w.config.FlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-partition.full:
+			}
+			w.flushPartition(ctx, partition)
+		}
+	}()
+}
+
+func (w *MarketDataWriter) flushPartition(ctx context.Context, partition *marketDataPartition) {
+	partition.writeMu.Lock()
+	defer partition.writeMu.Unlock()
+
+	for {
+		partition.mu.Lock()
+		n := min(len(partition.rows), w.config.BatchSize)
+		batch := append([]MarketDataRow(nil), partition.rows[:n]...)
+		partition.rows = append(partition.rows[:0], partition.rows[n:]...)
+		partition.mu.Unlock()
+		if n == 0 {
+			return
+		}
+
+		w.flushes.Add(1)
+		if err := w.write(ctx, batch); err != nil {
+			w.failed.Add(int64(len(batch)))
+			w.logger.Error("Failed to write market data batch",
+				"exchange", partition.exchange, "rows", len(batch), "error", err)
+			return
+		}
+		w.written.Add(int64(len(batch)))
+	}
+}
+
+func (w *MarketDataWriter) write(ctx context.Context, batch []MarketDataRow) error {
+	rows := make([][]any, len(batch))
+	for i, row := range batch {
+		rows[i] = row.values()
+	}
+
+	if copier, ok := w.db.(database.BulkCopier); ok {
+		_, err := copier.CopyRows(ctx, "market_data", marketDataColumns, rows)
+		if !errors.Is(err, database.ErrBulkCopyUnsupported) {
+			return err
+		}
+	}
+	return w.insert(ctx, rows)
+}
+
+// insert writes rows with multi-row INSERT statements.
+func (w *MarketDataWriter) insert(ctx context.Context, rows [][]any) error {
+	perStatement := maxInsertParams / len(marketDataColumns)
+	placeholder := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(marketDataColumns)), ", ") + ")"
+
+	for start := 0; start < len(rows); start += perStatement {
+		chunk := rows[start:min(start+perStatement, len(rows))]
+		values := make([]string, len(chunk))
+		args := make([]any, 0, len(chunk)*len(marketDataColumns))
+		for i, row := range chunk {
+			values[i] = placeholder
+			args = append(args, row...)
+		}
+		query := "INSERT INTO market_data (" + strings.Join(marketDataColumns, ", ") + ") VALUES " + strings.Join(values, ", ")
+		if _, err := w.db.Exec(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to insert market data: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMarketDataRow(exchange string, pairID int) MarketDataRow {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	return MarketDataRow{
+		Exchange:      exchange,
+		ExchangeID:    1,
+		TradingPairID: pairID,
+		Bid:           decimal.NewNullDecimal(decimal.NewFromInt(99)),
+		LastPrice:     decimal.NewFromInt(100),
+		Volume24h:     decimal.NewFromInt(10),
+		Timestamp:     now,
+		CreatedAt:     now,
+	}
+}
+
+type recordingCopier struct {
+	database.DBPool
+	mu   sync.Mutex
+	rows [][]any
+}
+
+func (r *recordingCopier) CopyRows(_ context.Context, table string, columns []string, rows [][]any) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if table != "market_data" || len(columns) != len(marketDataColumns) {
+		return 0, database.ErrBulkCopyUnsupported
+	}
+	r.rows = append(r.rows, rows...)
+	return int64(len(rows)), nil
+}
+
+func (r *recordingCopier) copied() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.rows)
+}
+
+func anyMarketDataArgs(rows int) []any {
+	args := make([]any, rows*len(marketDataColumns))
+	for i := range args {
+		args[i] = pgxmock.AnyArg()
+	}
+	return args
+}
+
+func TestMarketDataWriter_EnqueueRequiresStart(t *testing.T) {
+	writer := NewMarketDataWriter(&recordingCopier{}, MarketDataWriterConfig{})
+	assert.False(t, writer.Enqueue(testMarketDataRow("binance", 1)))
+
+	require.NoError(t, writer.Start(t.Context()))
+	assert.Error(t, writer.Start(t.Context()))
+	assert.True(t, writer.Enqueue(testMarketDataRow("binance", 1)))
+
+	writer.Stop(t.Context())
+	assert.False(t, writer.Enqueue(testMarketDataRow("binance", 2)))
+	assert.Equal(t, int64(1), writer.Stats().Written)
+}
+
+func TestMarketDataWriter_FlushOnBatchSize(t *testing.T) {
+	copier := &recordingCopier{}
+	writer := NewMarketDataWriter(copier, MarketDataWriterConfig{BatchSize: 3, FlushInterval: time.Hour})
+	require.NoError(t, writer.Start(t.Context()))
+	defer writer.Stop(t.Context())
+
+	for i := 0; i < 3; i++ {
+		require.True(t, writer.Enqueue(testMarketDataRow("binance", i)))
+	}
+	assert.Eventually(t, func() bool { return copier.copied() == 3 }, time.Second, 5*time.Millisecond)
+
+	// Another exchange is buffered separately and waits for its own batch.
+	require.True(t, writer.Enqueue(testMarketDataRow("kraken", 1)))
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 3, copier.copied())
+	assert.Equal(t, 1, writer.Stats().Buffered)
+}
+
+func TestMarketDataWriter_FlushOnInterval(t *testing.T) {
+	copier := &recordingCopier{}
+	writer := NewMarketDataWriter(copier, MarketDataWriterConfig{BatchSize: 100, FlushInterval: 10 * time.Millisecond})
+	require.NoError(t, writer.Start(t.Context()))
+	defer writer.Stop(t.Context())
+
+	require.True(t, writer.Enqueue(testMarketDataRow("binance", 1)))
+	assert.Eventually(t, func() bool { return copier.copied() == 1 }, time.Second, 5*time.Millisecond)
+
+	stats := writer.Stats()
+	assert.Equal(t, int64(1), stats.Written)
+	assert.Equal(t, int64(1), stats.Flushes)
+	assert.Zero(t, stats.Buffered)
+}
+
+func TestMarketDataWriter_DropsOldestWhenFull(t *testing.T) {
+	copier := &recordingCopier{}
+	writer := NewMarketDataWriter(copier, MarketDataWriterConfig{BatchSize: 2, FlushInterval: time.Hour, BufferSize: 3})
+	require.NoError(t, writer.Start(t.Context()))
+
+	// Hold the partition's write lock so nothing is flushed while it fills up.
+	partition := writer.partition("binance")
+	partition.writeMu.Lock()
+	for i := 1; i <= 5; i++ {
+		require.True(t, writer.Enqueue(testMarketDataRow("binance", i)))
+	}
+	stats := writer.Stats()
+	assert.Equal(t, int64(2), stats.Dropped)
+	assert.Equal(t, 3, stats.Buffered)
+	partition.writeMu.Unlock()
+
+	writer.Stop(t.Context())
+	require.Equal(t, 3, copier.copied())
+	assert.Equal(t, 3, copier.rows[0][1])
+	assert.Equal(t, 5, copier.rows[2][1])
+}
+
+func TestMarketDataWriter_MultiRowInsertFallback(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+
+	writer := NewMarketDataWriter(database.NewMockDBPool(mockPool), MarketDataWriterConfig{BatchSize: 150, FlushInterval: time.Hour})
+	require.NoError(t, writer.Start(t.Context()))
+
+	// 149 rows of 10 columns need two statements to stay under 999 parameters.
+	mockPool.ExpectExec(`INSERT INTO market_data \(exchange_id, .*, created_at\) VALUES \(\?, .*\)`).
+		WithArgs(anyMarketDataArgs(99)...).
+		WillReturnResult(pgxmock.NewResult("INSERT", 99))
+	mockPool.ExpectExec("INSERT INTO market_data").
+		WithArgs(anyMarketDataArgs(50)...).
+		WillReturnResult(pgxmock.NewResult("INSERT", 50))
+
+	for i := 0; i < 149; i++ {
+		require.True(t, writer.Enqueue(testMarketDataRow("binance", i)))
+	}
+	writer.Flush(t.Context())
+	writer.Stop(t.Context())
+
+	require.NoError(t, mockPool.ExpectationsWereMet())
+	assert.Equal(t, int64(149), writer.Stats().Written)
+}
+
+func TestMarketDataWriter_FailedBatchIsCounted(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+
+	writer := NewMarketDataWriter(database.NewMockDBPool(mockPool), MarketDataWriterConfig{FlushInterval: time.Hour})
+	require.NoError(t, writer.Start(t.Context()))
+
+	mockPool.ExpectExec("INSERT INTO market_data").WithArgs(anyMarketDataArgs(1)...).WillReturnError(assert.AnError)
+	require.True(t, writer.Enqueue(testMarketDataRow("binance", 1)))
+	writer.Stop(t.Context())
+
+	require.NoError(t, mockPool.ExpectationsWereMet())
+	stats := writer.Stats()
+	assert.Equal(t, int64(1), stats.Failed)
+	assert.Zero(t, stats.Written)
+}
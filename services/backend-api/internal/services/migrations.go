@@ -0,0 +1,401 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MigrationStatus values.
+const (
+	MigrationApplied = "applied"
+	MigrationPending = "pending"
+	// MigrationMissing is an applied migration whose file no longer exists.
+	MigrationMissing = "missing"
+)
+
+var (
+	ErrMigrationNotFound   = errors.New("migration not found")
+	ErrMigrationNotApplied = errors.New("migration is not applied")
+	ErrMigrationNoRollback = errors.New("migration has no rollback script")
+	ErrMigrationsPending   = errors.New("database migrations are pending")
+)
+
+// MigrationInfo describes one migration file and whether it has been applied.
+type MigrationInfo struct {
+	Filename string `json:"filename"`
+	Status   string `json:"status"`
+	// Checksum is the SHA-256 of the file on disk.
+	Checksum string `json:"checksum,omitempty"`
+	// RecordedChecksum is the SHA-256 recorded when the migration was applied
+	// by this service. Migrations applied by the shell scripts have none.
+	RecordedChecksum  string     `json:"recorded_checksum,omitempty"`
+	Modified          bool       `json:"modified"`
+	AppliedAt         *time.Time `json:"applied_at,omitempty"`
+	RollbackAvailable bool       `json:"rollback_available"`
+}
+
+// MigrationReport summarizes the migration state of the database.
+type MigrationReport struct {
+	Directory  string          `json:"directory"`
+	Applied    int             `json:"applied"`
+	Pending    int             `json:"pending"`
+	Modified   int             `json:"modified"`
+	Migrations []MigrationInfo `json:"migrations"`
+}
+
+// MigrationService applies and reports SQL migrations from a directory. It
+// shares the schema_migrations table with database/migrate.sh, and keeps the
+// checksums of migrations it applies in schema_migration_checksums.
+//
+// Rollback scripts are optional and live in <dir>/rollback/<filename>.
+type MigrationService struct {
+	db  DBPool
+	dir string
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// NewMigrationService creates a new migration service.
+//
+// Parameters:
+//   - db: Database to migrate.
+//   - dir: Directory containing the NNN_name.sql migration files.
+//
+// Returns:
+//   - *MigrationService: The initialized service.
+func NewMigrationService(db DBPool, dir string) *MigrationService {
+	return &MigrationService{db: db, dir: dir, now: time.Now}
+}
+
+type migrationFile struct {
+	filename string
+	checksum string
+	content  string
+}
+
+type migrationRecord struct {
+	appliedAt *time.Time
+	checksum  string
+}
+
+// Status lists every migration file and applied record.
+//
+// Parameters:
+//   - ctx: Request context.
+//
+// Returns:
+//   - *MigrationReport: Applied, pending and modified migrations in order.
+//   - error: Error if the directory or database cannot be read.
+func (s *MigrationService) Status(ctx context.Context) (*MigrationReport, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	if err := s.ensureTables(ctx); err != nil {
+		return nil, err
+	}
+
+	files, err := s.readFiles()
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.loadRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &MigrationReport{Directory: s.dir, Migrations: make([]MigrationInfo, 0, len(files))}
+	for _, file := range files {
+		info := MigrationInfo{
+			Filename:          file.filename,
+			Status:            MigrationPending,
+			Checksum:          file.checksum,
+			RollbackAvailable: s.hasRollback(file.filename),
+		}
+		if record, ok := records[file.filename]; ok {
+			info.Status = MigrationApplied
+			info.AppliedAt = record.appliedAt
+			info.RecordedChecksum = record.checksum
+			info.Modified = record.checksum != "" && record.checksum != file.checksum
+			delete(records, file.filename)
+		}
+		report.Migrations = append(report.Migrations, info)
+	}
+	for filename, record := range records {
+		report.Migrations = append(report.Migrations, MigrationInfo{
+			Filename:         filename,
+			Status:           MigrationMissing,
+			RecordedChecksum: record.checksum,
+			AppliedAt:        record.appliedAt,
+		})
+	}
+	sort.SliceStable(report.Migrations, func(i, j int) bool {
+		return report.Migrations[i].Filename < report.Migrations[j].Filename
+	})
+
+	for _, info := range report.Migrations {
+		switch info.Status {
+		case MigrationPending:
+			report.Pending++
+		case MigrationApplied, MigrationMissing:
+			report.Applied++
+		}
+		if info.Modified {
+			report.Modified++
+		}
+	}
+	return report, nil
+}
+
+// CheckNoPending returns ErrMigrationsPending when any migration is pending.
+// It is used to refuse live trading on a schema the code does not expect.
+func (s *MigrationService) CheckNoPending(ctx context.Context) error {
+	report, err := s.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check migrations: %w", err)
+	}
+	if report.Pending > 0 {
+		return fmt.Errorf("%w: %d pending", ErrMigrationsPending, report.Pending)
+	}
+	return nil
+}
+
+// Migrate applies all pending migrations in order and stops at the first failure.
+//
+// Parameters:
+//   - ctx: Request context.
+//
+// Returns:
+//   - []MigrationInfo: The migrations applied by this call.
+//   - error: Error naming the migration that failed.
+func (s *MigrationService) Migrate(ctx context.Context) ([]MigrationInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	if err := s.ensureTables(ctx); err != nil {
+		return nil, err
+	}
+	files, err := s.readFiles()
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.loadRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make([]MigrationInfo, 0)
+	for _, file := range files {
+		if _, ok := records[file.filename]; ok {
+			continue
+		}
+		if _, err := s.db.Exec(ctx, file.content); err != nil {
+			return applied, fmt.Errorf("failed to apply migration %s: %w", file.filename, err)
+		}
+		now := s.now().UTC()
+		if err := s.recordApplied(ctx, file, now); err != nil {
+			return applied, err
+		}
+		applied = append(applied, MigrationInfo{
+			Filename:          file.filename,
+			Status:            MigrationApplied,
+			Checksum:          file.checksum,
+			RecordedChecksum:  file.checksum,
+			AppliedAt:         &now,
+			RollbackAvailable: s.hasRollback(file.filename),
+		})
+	}
+	return applied, nil
+}
+
+// Rollback reverts an applied migration. An empty filename selects the most
+// recently applied one. The rollback script is run when present; without one
+// the record is only removed when force is set, like migrate.sh rollback.
+//
+// Parameters:
+//   - ctx: Request context.
+//   - filename: Migration to roll back, or empty for the latest.
+//   - force: Remove the record even without a rollback script.
+//
+// Returns:
+//   - *MigrationInfo: The migration, now pending.
+//   - error: ErrMigrationNotFound, ErrMigrationNotApplied or ErrMigrationNoRollback.
+func (s *MigrationService) Rollback(ctx context.Context, filename string, force bool) (*MigrationInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+	if err := s.ensureTables(ctx); err != nil {
+		return nil, err
+	}
+	records, err := s.loadRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if filename == "" {
+		for name := range records {
+			if name > filename {
+				filename = name
+			}
+		}
+		if filename == "" {
+			return nil, ErrMigrationNotApplied
+		}
+	} else if filepath.Base(filename) != filename || !strings.HasSuffix(filename, ".sql") {
+		return nil, ErrMigrationNotFound
+	}
+	if _, ok := records[filename]; !ok {
+		if _, err := os.Stat(filepath.Join(s.dir, filename)); err != nil {
+			return nil, ErrMigrationNotFound
+		}
+		return nil, ErrMigrationNotApplied
+	}
+
+	script, err := os.ReadFile(s.rollbackPath(filename))
+	switch {
+	case err == nil:
+		if _, err := s.db.Exec(ctx, string(script)); err != nil {
+			return nil, fmt.Errorf("failed to roll back migration %s: %w", filename, err)
+		}
+	case errors.Is(err, os.ErrNotExist):
+		if !force {
+			return nil, fmt.Errorf("%w: %s", ErrMigrationNoRollback, filename)
+		}
+	default:
+		return nil, fmt.Errorf("failed to read rollback script: %w", err)
+	}
+
+	if _, err := s.db.Exec(ctx, `DELETE FROM schema_migrations WHERE filename = $1`, filename); err != nil {
+		return nil, fmt.Errorf("failed to remove migration record: %w", err)
+	}
+	if _, err := s.db.Exec(ctx, `DELETE FROM schema_migration_checksums WHERE filename = $1`, filename); err != nil {
+		return nil, fmt.Errorf("failed to remove migration checksum: %w", err)
+	}
+
+	return &MigrationInfo{
+		Filename:          filename,
+		Status:            MigrationPending,
+		RollbackAvailable: err == nil,
+	}, nil
+}
+
+// ensureTables creates the tracking tables with definitions that work on
+// both SQLite and Postgres and match what the migration scripts expect.
+func (s *MigrationService) ensureTables(ctx context.Context) error {
+	if _, err := s.db.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			filename VARCHAR(255) PRIMARY KEY,
+			applied BOOLEAN DEFAULT TRUE,
+			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	if _, err := s.db.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migration_checksums (
+			filename VARCHAR(255) PRIMARY KEY,
+			checksum VARCHAR(64) NOT NULL,
+			recorded_at TIMESTAMP NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create schema_migration_checksums table: %w", err)
+	}
+	return nil
+}
+
+func (s *MigrationService) readFiles() ([]migrationFile, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	files := make([]migrationFile, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+		sum := sha256.Sum256(content)
+		files = append(files, migrationFile{
+			filename: entry.Name(),
+			checksum: hex.EncodeToString(sum[:]),
+			content:  string(content),
+		})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].filename < files[j].filename })
+	return files, nil
+}
+
+func (s *MigrationService) loadRecords(ctx context.Context) (map[string]migrationRecord, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT m.filename, m.applied_at, COALESCE(c.checksum, '')
+		FROM schema_migrations m
+		LEFT JOIN schema_migration_checksums c ON c.filename = m.filename
+		WHERE m.applied = TRUE
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	records := make(map[string]migrationRecord)
+	for rows.Next() {
+		var filename, checksum string
+		var appliedAt sql.NullTime
+		if err := rows.Scan(&filename, &appliedAt, &checksum); err != nil {
+			return nil, fmt.Errorf("failed to scan migration record: %w", err)
+		}
+		record := migrationRecord{checksum: checksum}
+		if appliedAt.Valid {
+			t := appliedAt.Time
+			record.appliedAt = &t
+		}
+		records[filename] = record
+	}
+	return records, rows.Err()
+}
+
+func (s *MigrationService) recordApplied(ctx context.Context, file migrationFile, appliedAt time.Time) error {
+	if _, err := s.db.Exec(ctx, `
+		INSERT INTO schema_migrations (filename, applied, applied_at)
+		VALUES ($1, TRUE, $2)
+		ON CONFLICT (filename) DO UPDATE SET applied = TRUE, applied_at = EXCLUDED.applied_at
+	`, file.filename, appliedAt); err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", file.filename, err)
+	}
+	if _, err := s.db.Exec(ctx, `
+		INSERT INTO schema_migration_checksums (filename, checksum, recorded_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (filename) DO UPDATE SET checksum = EXCLUDED.checksum, recorded_at = EXCLUDED.recorded_at
+	`, file.filename, file.checksum, appliedAt); err != nil {
+		return fmt.Errorf("failed to record checksum for %s: %w", file.filename, err)
+	}
+	return nil
+}
+
+func (s *MigrationService) rollbackPath(filename string) string {
+	return filepath.Join(s.dir, "rollback", filename)
+}
+
+func (s *MigrationService) hasRollback(filename string) bool {
+	_, err := os.Stat(s.rollbackPath(filename))
+	return err == nil
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeMigrationFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	return dir
+}
+
+func sqlChecksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func expectMigrationTables(mockPool pgxmock.PgxPoolIface) {
+	mockPool.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(pgxmock.NewResult("CREATE", 0))
+	mockPool.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migration_checksums").WillReturnResult(pgxmock.NewResult("CREATE", 0))
+}
+
+func TestMigrationService_Status(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+
+	dir := writeMigrationFiles(t, map[string]string{
+		"001_initial.sql":           "CREATE TABLE a (id INT);",
+		"002_add_b.sql":             "CREATE TABLE b (id INT);",
+		"003_add_c.sql":             "CREATE TABLE c (id INT);",
+		"004_disabled.sql.disabled": "DROP TABLE a;",
+		"rollback/003_add_c.sql":    "DROP TABLE c;",
+	})
+	appliedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	expectMigrationTables(mockPool)
+	mockPool.ExpectQuery("FROM schema_migrations").WillReturnRows(pgxmock.NewRows([]string{"filename", "applied_at", "checksum"}).
+		AddRow("001_initial.sql", appliedAt, sqlChecksum("CREATE TABLE a (id INT);")).
+		AddRow("002_add_b.sql", appliedAt, "stale").
+		AddRow("000_removed.sql", appliedAt, ""))
+
+	svc := NewMigrationService(database.NewMockDBPool(mockPool), dir)
+	report, err := svc.Status(t.Context())
+	require.NoError(t, err)
+	require.NoError(t, mockPool.ExpectationsWereMet())
+
+	assert.Equal(t, 3, report.Applied)
+	assert.Equal(t, 1, report.Pending)
+	assert.Equal(t, 1, report.Modified)
+	require.Len(t, report.Migrations, 4)
+
+	assert.Equal(t, "000_removed.sql", report.Migrations[0].Filename)
+	assert.Equal(t, MigrationMissing, report.Migrations[0].Status)
+	assert.Equal(t, MigrationApplied, report.Migrations[1].Status)
+	assert.False(t, report.Migrations[1].Modified)
+	assert.True(t, report.Migrations[2].Modified)
+	assert.Equal(t, MigrationPending, report.Migrations[3].Status)
+	assert.Equal(t, sqlChecksum("CREATE TABLE c (id INT);"), report.Migrations[3].Checksum)
+	assert.True(t, report.Migrations[3].RollbackAvailable)
+}
+
+func TestMigrationService_MigrateAndCheck(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+
+	dir := writeMigrationFiles(t, map[string]string{
+		"001_initial.sql": "CREATE TABLE a (id INT);",
+		"002_add_b.sql":   "CREATE TABLE b (id INT);",
+	})
+	svc := NewMigrationService(database.NewMockDBPool(mockPool), dir)
+	appliedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return appliedAt }
+
+	expectMigrationTables(mockPool)
+	mockPool.ExpectQuery("FROM schema_migrations").WillReturnRows(pgxmock.NewRows([]string{"filename", "applied_at", "checksum"}).
+		AddRow("001_initial.sql", appliedAt, ""))
+	err = svc.CheckNoPending(t.Context())
+	assert.ErrorIs(t, err, ErrMigrationsPending)
+
+	expectMigrationTables(mockPool)
+	mockPool.ExpectQuery("FROM schema_migrations").WillReturnRows(pgxmock.NewRows([]string{"filename", "applied_at", "checksum"}).
+		AddRow("001_initial.sql", appliedAt, ""))
+	mockPool.ExpectExec("CREATE TABLE b").WillReturnResult(pgxmock.NewResult("CREATE", 0))
+	mockPool.ExpectExec("INSERT INTO schema_migrations").
+		WithArgs("002_add_b.sql", appliedAt).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mockPool.ExpectExec("INSERT INTO schema_migration_checksums").
+		WithArgs("002_add_b.sql", sqlChecksum("CREATE TABLE b (id INT);"), appliedAt).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	applied, err := svc.Migrate(t.Context())
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, "002_add_b.sql", applied[0].Filename)
+
+	expectMigrationTables(mockPool)
+	mockPool.ExpectQuery("FROM schema_migrations").WillReturnRows(pgxmock.NewRows([]string{"filename", "applied_at", "checksum"}).
+		AddRow("001_initial.sql", appliedAt, "").
+		AddRow("002_add_b.sql", appliedAt, sqlChecksum("CREATE TABLE b (id INT);")))
+	assert.NoError(t, svc.CheckNoPending(t.Context()))
+	require.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestMigrationService_Rollback(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+
+	dir := writeMigrationFiles(t, map[string]string{
+		"001_initial.sql":        "CREATE TABLE a (id INT);",
+		"002_add_b.sql":          "CREATE TABLE b (id INT);",
+		"rollback/002_add_b.sql": "DROP TABLE b;",
+	})
+	svc := NewMigrationService(database.NewMockDBPool(mockPool), dir)
+	appliedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	appliedRows := func() *pgxmock.Rows {
+		return pgxmock.NewRows([]string{"filename", "applied_at", "checksum"}).
+			AddRow("001_initial.sql", appliedAt, "").
+			AddRow("002_add_b.sql", appliedAt, "")
+	}
+
+	// The latest migration is rolled back with its script.
+	expectMigrationTables(mockPool)
+	mockPool.ExpectQuery("FROM schema_migrations").WillReturnRows(appliedRows())
+	mockPool.ExpectExec("DROP TABLE b").WillReturnResult(pgxmock.NewResult("DROP", 0))
+	mockPool.ExpectExec("DELETE FROM schema_migrations").WithArgs("002_add_b.sql").WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mockPool.ExpectExec("DELETE FROM schema_migration_checksums").WithArgs("002_add_b.sql").WillReturnResult(pgxmock.NewResult("DELETE", 1))
+
+	migration, err := svc.Rollback(t.Context(), "", false)
+	require.NoError(t, err)
+	assert.Equal(t, "002_add_b.sql", migration.Filename)
+	assert.Equal(t, MigrationPending, migration.Status)
+
+	// Without a script the record is only removed when forced.
+	expectMigrationTables(mockPool)
+	mockPool.ExpectQuery("FROM schema_migrations").WillReturnRows(appliedRows())
+	_, err = svc.Rollback(t.Context(), "001_initial.sql", false)
+	assert.ErrorIs(t, err, ErrMigrationNoRollback)
+
+	expectMigrationTables(mockPool)
+	mockPool.ExpectQuery("FROM schema_migrations").WillReturnRows(appliedRows())
+	mockPool.ExpectExec("DELETE FROM schema_migrations").WithArgs("001_initial.sql").WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mockPool.ExpectExec("DELETE FROM schema_migration_checksums").WithArgs("001_initial.sql").WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	_, err = svc.Rollback(t.Context(), "001_initial.sql", true)
+	require.NoError(t, err)
+
+	expectMigrationTables(mockPool)
+	mockPool.ExpectQuery("FROM schema_migrations").WillReturnRows(appliedRows())
+	_, err = svc.Rollback(t.Context(), "../etc/passwd.sql", true)
+	assert.ErrorIs(t, err, ErrMigrationNotFound)
+	require.NoError(t, mockPool.ExpectationsWereMet())
+}
@@ -0,0 +1,454 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	listingKnownKeyPrefix = "listings:known:"
+	listingNewKey         = "listings:new"
+
+	// NewListingsWatchlistID is the watchlist new listings are added to when
+	// auto-add is enabled. Its symbols join the active universe like any
+	// other watchlist.
+	NewListingsWatchlistID = "new_listings"
+
+	defaultListingScanInterval    = time.Hour
+	defaultListingProbationPeriod = 7 * 24 * time.Hour
+	defaultListingMaxCapitalPct   = 1.0
+	defaultListingMinConfidence   = 0.85
+)
+
+// MarketLister lists the markets of an exchange.
+type MarketLister interface {
+	FetchMarkets(ctx context.Context, exchange string) (*ccxt.MarketsResponse, error)
+}
+
+// ListingWatchlist is the watchlist store new listings are added to.
+type ListingWatchlist interface {
+	AddSymbols(ctx context.Context, chatID string, symbols []string) (*Watchlist, error)
+	RemoveSymbols(ctx context.Context, chatID string, symbols []string) (*Watchlist, error)
+}
+
+// DirectMessenger sends a plain Telegram message to a chat.
+type DirectMessenger interface {
+	SendDirectMessage(ctx context.Context, chatID int64, text string) error
+}
+
+// ListingRiskProvider returns the risk limits for symbols that were listed recently.
+type ListingRiskProvider interface {
+	// ListingRiskLimits returns the limits for a symbol and whether it is
+	// still in its probation period.
+	ListingRiskLimits(ctx context.Context, symbol string) (ListingRiskLimits, bool)
+}
+
+// ListingRiskLimits are the conservative limits applied to a new listing.
+type ListingRiskLimits struct {
+	MaxCapitalPct float64 `json:"max_capital_pct"`
+	MinConfidence float64 `json:"min_confidence"`
+}
+
+// NewListing is a symbol first seen on an exchange after the initial scan.
+type NewListing struct {
+	Exchange   string    `json:"exchange"`
+	Symbol     string    `json:"symbol"`
+	DetectedAt time.Time `json:"detected_at"`
+	// ProbationEnds is when the conservative risk limits stop applying.
+	ProbationEnds time.Time `json:"probation_ends"`
+}
+
+// NewListingConfig configures new-listing detection.
+type NewListingConfig struct {
+	// Exchanges are the exchanges scanned for new symbols.
+	Exchanges []string
+	// Interval is how often the exchanges are scanned.
+	Interval time.Duration
+	// ProbationPeriod is how long a new listing trades under the conservative limits.
+	ProbationPeriod time.Duration
+	// AutoAdd adds new listings to the NewListingsWatchlistID watchlist for
+	// the probation period.
+	AutoAdd bool
+	// NotifyChatIDs are the operator chats told about new listings.
+	NotifyChatIDs []int64
+	// MaxCapitalPct caps the position size of a new listing, in percent of the balance.
+	MaxCapitalPct float64
+	// MinConfidence is the lowest decision confidence accepted for a new listing.
+	MinConfidence float64
+}
+
+// ListingDetector compares each exchange's markets with the symbols seen
+// before and reports the ones that appeared. The first scan of an exchange
+// only records a baseline so existing markets are not reported as new.
+type ListingDetector struct {
+	markets   MarketLister
+	redis     *redis.Client
+	config    NewListingConfig
+	watchlist ListingWatchlist
+	events    EventEmitter
+	messenger DirectMessenger
+	logger    *slog.Logger
+	now       func() time.Time
+	runMu     sync.Mutex
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// Ensure ListingDetector implements ListingRiskProvider.
+var _ ListingRiskProvider = (*ListingDetector)(nil)
+
+// NewListingDetector creates a new-listing detector.
+//
+// Parameters:
+//
+//	markets: Source of exchange markets, such as the CCXT service.
+//	client: Redis client used to persist known symbols and new listings.
+//	config: Detection configuration; zero values use defaults.
+//
+// Returns:
+//
+//	*ListingDetector: Initialized detector (scanning not started).
+func NewListingDetector(markets MarketLister, client *redis.Client, config NewListingConfig) *ListingDetector {
+	if config.Interval <= 0 {
+		config.Interval = defaultListingScanInterval
+	}
+	if config.ProbationPeriod <= 0 {
+		config.ProbationPeriod = defaultListingProbationPeriod
+	}
+	if config.MaxCapitalPct <= 0 {
+		config.MaxCapitalPct = defaultListingMaxCapitalPct
+	}
+	if config.MinConfidence <= 0 {
+		config.MinConfidence = defaultListingMinConfidence
+	}
+	return &ListingDetector{
+		markets: markets,
+		redis:   client,
+		config:  config,
+		logger:  telemetry.Logger(),
+		now:     time.Now,
+	}
+}
+
+// SetWatchlist sets the watchlist store used when auto-add is enabled.
+func (d *ListingDetector) SetWatchlist(watchlist ListingWatchlist) {
+	d.watchlist = watchlist
+}
+
+// SetEventEmitter publishes new listings, for example to outbound webhooks
+// and the event bus.
+func (d *ListingDetector) SetEventEmitter(events EventEmitter) {
+	d.events = events
+}
+
+// SetMessenger sets the Telegram sender used to notify the operator chats.
+func (d *ListingDetector) SetMessenger(messenger DirectMessenger) {
+	d.messenger = messenger
+}
+
+// Start scans the exchanges now and then once per interval until Stop is called.
+func (d *ListingDetector) Start(ctx context.Context) error {
+	d.runMu.Lock()
+	defer d.runMu.Unlock()
+	if d.cancel != nil {
+		return fmt.Errorf("listing detector already running")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	d.cancel = cancel
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		d.scan(ctx)
+		ticker := time.NewTicker(d.config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				d.scan(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops scanning and waits for the running scan to finish.
+func (d *ListingDetector) Stop() {
+	d.runMu.Lock()
+	cancel := d.cancel
+	d.cancel = nil
+	d.runMu.Unlock()
+	if cancel != nil {
+		cancel()
+		d.wg.Wait()
+	}
+}
+
+func (d *ListingDetector) scan(ctx context.Context) {
+	if _, err := d.Scan(ctx); err != nil {
+		d.logger.Warn("New listing scan failed", "error", err)
+	}
+}
+
+// Scan checks every configured exchange for new symbols and ends the
+// probation of listings older than the probation period.
+//
+// Parameters:
+//
+//	ctx: Context for the scan.
+//
+// Returns:
+//
+//	[]NewListing: The listings detected by this scan.
+//	error: Error if the listing store could not be read or written.
+func (d *ListingDetector) Scan(ctx context.Context) ([]NewListing, error) {
+	if err := d.expire(ctx); err != nil {
+		return nil, err
+	}
+
+	var detected []NewListing
+	for _, exchange := range d.config.Exchanges {
+		listings, err := d.scanExchange(ctx, exchange)
+		if err != nil {
+			// One unavailable exchange must not block the others.
+			d.logger.Warn("Failed to scan exchange for new listings", "exchange", exchange, "error", err)
+			continue
+		}
+		detected = append(detected, listings...)
+	}
+
+	if len(detected) > 0 {
+		d.announce(ctx, detected)
+	}
+	return detected, nil
+}
+
+func (d *ListingDetector) scanExchange(ctx context.Context, exchange string) ([]NewListing, error) {
+	markets, err := d.markets.FetchMarkets(ctx, exchange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch markets: %w", err)
+	}
+	if markets == nil {
+		return nil, nil
+	}
+	var symbols []string
+	for _, symbol := range markets.Symbols {
+		if isValidSymbolFormat(symbol) {
+			symbols = append(symbols, strings.ToUpper(strings.TrimSpace(symbol)))
+		}
+	}
+	if len(symbols) == 0 {
+		// An empty market list is an exchange problem, not delisting everything.
+		return nil, nil
+	}
+
+	knownKey := listingKnownKeyPrefix + exchange
+	known, err := d.redis.SMembers(ctx, knownKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load known symbols: %w", err)
+	}
+	members := make([]interface{}, len(symbols))
+	for i, symbol := range symbols {
+		members[i] = symbol
+	}
+	if len(known) == 0 {
+		if err := d.redis.SAdd(ctx, knownKey, members...).Err(); err != nil {
+			return nil, fmt.Errorf("failed to save known symbols: %w", err)
+		}
+		d.logger.Info("Recorded listing baseline", "exchange", exchange, "symbols", len(symbols))
+		return nil, nil
+	}
+
+	knownSet := toSymbolSet(known)
+	now := d.now().UTC()
+	var listings []NewListing
+	for _, symbol := range unionSymbols(nil, symbols) {
+		if _, ok := knownSet[symbol]; ok {
+			continue
+		}
+		listing := NewListing{
+			Exchange:      exchange,
+			Symbol:        symbol,
+			DetectedAt:    now,
+			ProbationEnds: now.Add(d.config.ProbationPeriod),
+		}
+		raw, err := json.Marshal(listing)
+		if err != nil {
+			return listings, err
+		}
+		if err := d.redis.HSet(ctx, listingNewKey, listingField(exchange, symbol), raw).Err(); err != nil {
+			return listings, fmt.Errorf("failed to save new listing: %w", err)
+		}
+		listings = append(listings, listing)
+	}
+	// Symbols are marked known only after their listings are saved, so a
+	// failed save is retried on the next scan.
+	if err := d.redis.SAdd(ctx, knownKey, members...).Err(); err != nil {
+		return listings, fmt.Errorf("failed to save known symbols: %w", err)
+	}
+	return listings, nil
+}
+
+// announce logs, notifies and optionally auto-adds newly detected listings.
+func (d *ListingDetector) announce(ctx context.Context, listings []NewListing) {
+	symbols := make([]string, 0, len(listings))
+	lines := make([]string, 0, len(listings))
+	for _, listing := range listings {
+		d.logger.Info("New listing detected", "exchange", listing.Exchange, "symbol", listing.Symbol)
+		symbols = append(symbols, listing.Symbol)
+		lines = append(lines, fmt.Sprintf("• %s on %s", listing.Symbol, listing.Exchange))
+		if d.events != nil {
+			d.events.Emit(ctx, WebhookEventListingDetected, listing)
+		}
+	}
+
+	if d.config.AutoAdd && d.watchlist != nil {
+		if _, err := d.watchlist.AddSymbols(ctx, NewListingsWatchlistID, symbols); err != nil {
+			d.logger.Warn("Failed to add new listings to watchlist", "symbols", symbols, "error", err)
+		}
+	}
+
+	if d.messenger == nil || len(d.config.NotifyChatIDs) == 0 {
+		return
+	}
+	text := fmt.Sprintf("🆕 New listings detected:\n%s\n\nFor the next %s they trade with at most %.2f%% of the balance and a minimum confidence of %.2f.",
+		strings.Join(lines, "\n"), formatListingPeriod(d.config.ProbationPeriod), d.config.MaxCapitalPct, d.config.MinConfidence)
+	if d.config.AutoAdd {
+		text += fmt.Sprintf("\nThey were added to the %q watchlist.", NewListingsWatchlistID)
+	}
+	for _, chatID := range d.config.NotifyChatIDs {
+		if err := d.messenger.SendDirectMessage(ctx, chatID, text); err != nil {
+			d.logger.Warn("Failed to notify operator of new listings", "chat_id", chatID, "error", err)
+		}
+	}
+}
+
+// expire removes listings whose probation has ended, also taking them off
+// the new listings watchlist unless they are still new on another exchange.
+func (d *ListingDetector) expire(ctx context.Context) error {
+	listings, err := d.loadListings(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := d.now()
+	var expiredFields []string
+	expired := make(map[string]struct{})
+	active := make(map[string]struct{})
+	for _, listing := range listings {
+		if now.Before(listing.ProbationEnds) {
+			active[listing.Symbol] = struct{}{}
+			continue
+		}
+		expiredFields = append(expiredFields, listingField(listing.Exchange, listing.Symbol))
+		expired[listing.Symbol] = struct{}{}
+	}
+	if len(expiredFields) == 0 {
+		return nil
+	}
+	if err := d.redis.HDel(ctx, listingNewKey, expiredFields...).Err(); err != nil {
+		return fmt.Errorf("failed to remove expired listings: %w", err)
+	}
+
+	var remove []string
+	for symbol := range expired {
+		if _, ok := active[symbol]; !ok {
+			remove = append(remove, symbol)
+		}
+	}
+	sort.Strings(remove)
+	if d.config.AutoAdd && d.watchlist != nil && len(remove) > 0 {
+		if _, err := d.watchlist.RemoveSymbols(ctx, NewListingsWatchlistID, remove); err != nil {
+			d.logger.Warn("Failed to remove expired listings from watchlist", "symbols", remove, "error", err)
+		}
+	}
+	return nil
+}
+
+// Listings returns the listings still in their probation period, newest first.
+func (d *ListingDetector) Listings(ctx context.Context) ([]NewListing, error) {
+	listings, err := d.loadListings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := d.now()
+	active := make([]NewListing, 0, len(listings))
+	for _, listing := range listings {
+		if now.Before(listing.ProbationEnds) {
+			active = append(active, listing)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool {
+		if !active[i].DetectedAt.Equal(active[j].DetectedAt) {
+			return active[i].DetectedAt.After(active[j].DetectedAt)
+		}
+		return listingField(active[i].Exchange, active[i].Symbol) < listingField(active[j].Exchange, active[j].Symbol)
+	})
+	return active, nil
+}
+
+// ListingRiskLimits returns the conservative limits when the symbol is a new
+// listing on any scanned exchange.
+func (d *ListingDetector) ListingRiskLimits(ctx context.Context, symbol string) (ListingRiskLimits, bool) {
+	listings, err := d.Listings(ctx)
+	if err != nil {
+		d.logger.Warn("Failed to load new listings", "error", err)
+		return ListingRiskLimits{}, false
+	}
+	target := normalizeSymbolForComparison(symbol)
+	for _, listing := range listings {
+		if normalizeSymbolForComparison(listing.Symbol) == target {
+			return ListingRiskLimits{
+				MaxCapitalPct: d.config.MaxCapitalPct,
+				MinConfidence: d.config.MinConfidence,
+			}, true
+		}
+	}
+	return ListingRiskLimits{}, false
+}
+
+func (d *ListingDetector) loadListings(ctx context.Context) ([]NewListing, error) {
+	raw, err := d.redis.HGetAll(ctx, listingNewKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load new listings: %w", err)
+	}
+	listings := make([]NewListing, 0, len(raw))
+	for field, value := range raw {
+		var listing NewListing
+		if err := json.Unmarshal([]byte(value), &listing); err != nil {
+			d.logger.Warn("Skipping corrupt new listing", "field", field, "error", err)
+			continue
+		}
+		listings = append(listings, listing)
+	}
+	return listings, nil
+}
+
+// listingField keys a listing by exchange and symbol; symbols may contain
+// ":" (e.g. "BTC/USDT:USDT") so "|" separates the two.
+func listingField(exchange, symbol string) string {
+	return exchange + "|" + symbol
+}
+
+func formatListingPeriod(period time.Duration) string {
+	if period%(24*time.Hour) == 0 {
+		days := int(period / (24 * time.Hour))
+		if days == 1 {
+			return "1 day"
+		}
+		return fmt.Sprintf("%d days", days)
+	}
+	return period.String()
+}
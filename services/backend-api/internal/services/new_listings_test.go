@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMarketLister struct {
+	mu      sync.Mutex
+	symbols map[string][]string
+}
+
+func (f *fakeMarketLister) FetchMarkets(_ context.Context, exchange string) (*ccxt.MarketsResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	symbols, ok := f.symbols[exchange]
+	if !ok {
+		return nil, errors.New("exchange unavailable")
+	}
+	return &ccxt.MarketsResponse{Exchange: exchange, Symbols: symbols, Count: len(symbols)}, nil
+}
+
+func (f *fakeMarketLister) set(exchange string, symbols ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.symbols[exchange] = symbols
+}
+
+type recordingMessenger struct {
+	chats []int64
+	texts []string
+}
+
+func (r *recordingMessenger) SendDirectMessage(_ context.Context, chatID int64, text string) error {
+	r.chats = append(r.chats, chatID)
+	r.texts = append(r.texts, text)
+	return nil
+}
+
+func newTestListingDetector(t *testing.T, markets MarketLister, config NewListingConfig) (*ListingDetector, *WatchlistService) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	watchlists := NewWatchlistService(nil, client, WatchlistConfig{})
+	detector := NewListingDetector(markets, client, config)
+	detector.SetWatchlist(watchlists)
+	return detector, watchlists
+}
+
+func TestListingDetector_DetectsNewSymbolsAfterBaseline(t *testing.T) {
+	markets := &fakeMarketLister{symbols: map[string][]string{"binance": {"BTC/USDT", "ETH/USDT", ":"}}}
+	detector, watchlists := newTestListingDetector(t, markets, NewListingConfig{
+		Exchanges:     []string{"binance", "kraken"},
+		AutoAdd:       true,
+		NotifyChatIDs: []int64{7},
+	})
+	messenger := &recordingMessenger{}
+	emitter := &recordingEmitter{}
+	detector.SetMessenger(messenger)
+	detector.SetEventEmitter(emitter)
+	ctx := t.Context()
+
+	// The first scan records a baseline; the unavailable exchange is skipped.
+	detected, err := detector.Scan(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, detected)
+	assert.Empty(t, messenger.texts)
+
+	markets.set("binance", "BTC/USDT", "ETH/USDT", "NEW/USDT")
+	detected, err = detector.Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, detected, 1)
+	assert.Equal(t, "binance", detected[0].Exchange)
+	assert.Equal(t, "NEW/USDT", detected[0].Symbol)
+	assert.Equal(t, detected[0].DetectedAt.Add(defaultListingProbationPeriod), detected[0].ProbationEnds)
+
+	assert.Equal(t, []int64{7}, messenger.chats)
+	assert.Contains(t, messenger.texts[0], "NEW/USDT on binance")
+	assert.Contains(t, messenger.texts[0], "7 days")
+	assert.Equal(t, []WebhookEventType{WebhookEventListingDetected}, emitter.events)
+
+	watchlist, err := watchlists.Get(ctx, NewListingsWatchlistID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"NEW/USDT"}, watchlist.Symbols)
+
+	// A known symbol is not reported twice.
+	detected, err = detector.Scan(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, detected)
+
+	listings, err := detector.Listings(ctx)
+	require.NoError(t, err)
+	require.Len(t, listings, 1)
+	assert.Equal(t, "NEW/USDT", listings[0].Symbol)
+}
+
+func TestListingDetector_ProbationLimitsAndExpiry(t *testing.T) {
+	markets := &fakeMarketLister{symbols: map[string][]string{"binance": {"BTC/USDT"}}}
+	detector, watchlists := newTestListingDetector(t, markets, NewListingConfig{
+		Exchanges:       []string{"binance"},
+		ProbationPeriod: 48 * time.Hour,
+		AutoAdd:         true,
+		MaxCapitalPct:   0.5,
+	})
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	detector.now = func() time.Time { return now }
+	ctx := t.Context()
+
+	_, err := detector.Scan(ctx)
+	require.NoError(t, err)
+	markets.set("binance", "BTC/USDT", "NEW/USDT:USDT")
+	_, err = detector.Scan(ctx)
+	require.NoError(t, err)
+
+	limits, ok := detector.ListingRiskLimits(ctx, "new/usdt")
+	require.True(t, ok)
+	assert.Equal(t, ListingRiskLimits{MaxCapitalPct: 0.5, MinConfidence: defaultListingMinConfidence}, limits)
+	_, ok = detector.ListingRiskLimits(ctx, "BTC/USDT")
+	assert.False(t, ok)
+
+	now = now.Add(48 * time.Hour)
+	_, ok = detector.ListingRiskLimits(ctx, "NEW/USDT")
+	assert.False(t, ok)
+
+	// The next scan drops the expired listing and takes it off the watchlist.
+	_, err = detector.Scan(ctx)
+	require.NoError(t, err)
+	listings, err := detector.loadListings(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, listings)
+	watchlist, err := watchlists.Get(ctx, NewListingsWatchlistID)
+	require.NoError(t, err)
+	assert.Empty(t, watchlist.Symbols)
+}
+
+func TestListingDetector_StartStop(t *testing.T) {
+	markets := &fakeMarketLister{symbols: map[string][]string{"binance": {"BTC/USDT"}}}
+	detector, _ := newTestListingDetector(t, markets, NewListingConfig{Exchanges: []string{"binance"}})
+
+	require.NoError(t, detector.Start(t.Context()))
+	assert.Error(t, detector.Start(t.Context()))
+	detector.Stop()
+	detector.Stop()
+}
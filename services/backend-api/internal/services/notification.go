@@ -15,6 +15,7 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/irfndi/neuratrade/internal/observability"
+	"github.com/irfndi/neuratrade/internal/services/jobqueue"
 	"github.com/irfndi/neuratrade/internal/telemetry"
 	pb "github.com/irfndi/neuratrade/pkg/pb/telegram"
 
@@ -40,6 +41,22 @@ type NotificationService struct {
 	adminAPIKey        string
 	logger             *slog.Logger
 	deadLetterService  *DeadLetterService
+	deliveryQueue      *NotificationDeliveryQueue
+	deliveryTracker    *NotificationDeliveryTracker
+	actionService      *NotificationActionService
+	hooks              *HookRegistry
+	kpis               KPIRecorder
+	changeDetector     *NotificationChangeDetector
+	lifecycle          *OpportunityLifecycleService
+	escalation         *CriticalEscalationService
+	// maxMessageLength is the longest message sent in one piece; zero uses
+	// Telegram's limit.
+	maxMessageLength int
+	// format is the parse mode messages are written in; empty means legacy Markdown.
+	format NotificationFormat
+	// quietHours holds back low priority notifications; nil disables it.
+	quietHours *QuietHours
+	now        func() time.Time
 }
 
 // ArbitrageOpportunity represents an arbitrage opportunity for notification.
@@ -199,25 +216,105 @@ func (ns *NotificationService) sendTelegramMessage(ctx context.Context, chatID i
 	return fmt.Errorf("%s: %s", result.ErrorCode, result.Error)
 }
 
+// SendDirectMessage sends a plain message to a single chat, bypassing the delivery queue.
+// It is used for time-sensitive messages such as login codes. The text is
+// escaped, so it is shown exactly as given.
+func (ns *NotificationService) SendDirectMessage(ctx context.Context, chatID int64, text string) error {
+	return ns.sendTelegramMessage(ctx, chatID, ns.formatter().Text(text))
+}
+
+// SetMessageFormat sets the Telegram parse mode notifications are written in.
+func (ns *NotificationService) SetMessageFormat(format NotificationFormat) {
+	ns.format = format
+}
+
+// formatter returns the formatter for the configured message format.
+func (ns *NotificationService) formatter() MessageFormatter {
+	return NewMessageFormatter(ns.format)
+}
+
+func (ns *NotificationService) currentTime() time.Time {
+	if ns.now != nil {
+		return ns.now()
+	}
+	return time.Now()
+}
+
 // sendTelegramMessageWithResult sends a message and returns structured result
 func (ns *NotificationService) sendTelegramMessageWithResult(ctx context.Context, chatID int64, text string) TelegramSendResult {
+	return ns.sendTelegramMessageWithMarkup(ctx, chatID, text, nil)
+}
+
+// sendTelegramMessageWithMarkup sends a normal priority message with an
+// optional inline keyboard.
+func (ns *NotificationService) sendTelegramMessageWithMarkup(ctx context.Context, chatID int64, text string, markup *InlineKeyboardMarkup) TelegramSendResult {
+	return ns.sendTelegramMessageWithPriority(ctx, chatID, text, markup, PriorityNotificationNormal)
+}
+
+// sendTelegramMessageWithPriority sends a message with an optional inline
+// keyboard. Messages over Telegram's length limit are split into threaded
+// parts; critical messages cannot be suppressed by hooks.
+func (ns *NotificationService) sendTelegramMessageWithPriority(ctx context.Context, chatID int64, text string, markup *InlineKeyboardMarkup, priority NotificationPriority) TelegramSendResult {
 	spanCtx, span := observability.StartSpanWithTags(ctx, observability.SpanOpNotification, "NotificationService.sendTelegramMessage", map[string]string{
-		"chat_id": fmt.Sprintf("%d", chatID),
+		"chat_id":  fmt.Sprintf("%d", chatID),
+		"priority": string(priority),
 	})
 	defer observability.FinishSpan(span, nil)
+	if ns.kpis != nil {
+		defer func(started time.Time) {
+			ns.kpis.RecordKPI(KPINotificationLatencyMs, float64(time.Since(started).Milliseconds()))
+		}(time.Now())
+	}
 
+	msg, send := ns.hooks.RunPreNotify(spanCtx, HookNotification{ChatID: chatID, Text: text, Priority: priority})
+	if !send {
+		// Suppression is a hook decision, not a delivery failure to retry
+		return TelegramSendResult{OK: true}
+	}
+	text = msg.Text
+
+	parts := splitTelegramMessage(text, ns.maxMessageLength, ns.formatter())
+	if len(parts) == 1 {
+		return ns.deliverTelegramMessage(spanCtx, chatID, text, markup, "")
+	}
+
+	// Thread the parts as replies to the first and keep the keyboard on the
+	// last, so its buttons sit under the end of the message
+	ns.logger.Info("Splitting long Telegram message", "chat_id", chatID, "length", telegramTextLength(text), "parts", len(parts))
+	var first TelegramSendResult
+	for i, part := range parts {
+		var partMarkup *InlineKeyboardMarkup
+		if i == len(parts)-1 {
+			partMarkup = markup
+		}
+		result := ns.deliverTelegramMessage(spanCtx, chatID, part, partMarkup, first.MessageID)
+		if !result.OK {
+			return result
+		}
+		if i == 0 {
+			first = result
+		}
+	}
+	return first
+}
+
+// deliverTelegramMessage sends one message that fits Telegram's length limit,
+// optionally as a reply to replyTo. The gRPC API has no reply markup or reply
+// fields, so keyboard messages and replies use HTTP when it is configured.
+func (ns *NotificationService) deliverTelegramMessage(ctx context.Context, chatID int64, text string, markup *InlineKeyboardMarkup, replyTo string) TelegramSendResult {
 	// Try gRPC first
-	if ns.grpcClient != nil {
-		grpcCtx, grpcSpan := observability.StartSpan(spanCtx, observability.SpanOpGRPC, "telegram.SendMessage")
+	if ns.grpcClient != nil && markup == nil && (replyTo == "" || ns.telegramServiceURL == "") {
+		grpcCtx, grpcSpan := observability.StartSpan(ctx, observability.SpanOpGRPC, "telegram.SendMessage")
 		resp, err := ns.grpcClient.SendMessage(grpcCtx, &pb.SendMessageRequest{
-			ChatId: fmt.Sprintf("%d", chatID),
-			Text:   text,
+			ChatId:    fmt.Sprintf("%d", chatID),
+			Text:      text,
+			ParseMode: string(ns.formatter().Format()),
 		})
 		observability.FinishSpan(grpcSpan, err)
 
 		if err == nil && resp != nil {
 			if resp.Ok {
-				observability.AddBreadcrumb(spanCtx, "notification", "Telegram message sent via gRPC", sentry.LevelInfo)
+				observability.AddBreadcrumb(ctx, "notification", "Telegram message sent via gRPC", sentry.LevelInfo)
 				return TelegramSendResult{
 					OK:        true,
 					MessageID: resp.MessageId,
@@ -238,7 +335,7 @@ func (ns *NotificationService) sendTelegramMessageWithResult(ctx context.Context
 
 		if err != nil {
 			ns.logger.Warn("Failed to send Telegram message via gRPC, falling back to HTTP", "error", err)
-			observability.AddBreadcrumb(spanCtx, "notification", "gRPC failed, falling back to HTTP", sentry.LevelWarning)
+			observability.AddBreadcrumb(ctx, "notification", "gRPC failed, falling back to HTTP", sentry.LevelWarning)
 		}
 	}
 
@@ -254,12 +351,18 @@ func (ns *NotificationService) sendTelegramMessageWithResult(ctx context.Context
 	payload := map[string]interface{}{
 		"chatId":    fmt.Sprintf("%d", chatID),
 		"text":      text,
-		"parseMode": "Markdown",
+		"parseMode": string(ns.formatter().Format()),
+	}
+	if markup != nil {
+		payload["replyMarkup"] = markup
+	}
+	if replyTo != "" {
+		payload["replyToMessageId"] = replyTo
 	}
 
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		observability.CaptureException(spanCtx, err)
+		observability.CaptureException(ctx, err)
 		return TelegramSendResult{
 			OK:        false,
 			Error:     err.Error(),
@@ -267,7 +370,7 @@ func (ns *NotificationService) sendTelegramMessageWithResult(ctx context.Context
 		}
 	}
 
-	httpCtx, httpSpan := observability.StartSpan(spanCtx, observability.SpanOpHTTPClient, "POST /send-message")
+	httpCtx, httpSpan := observability.StartSpan(ctx, observability.SpanOpHTTPClient, "POST /send-message")
 	req, err := http.NewRequestWithContext(httpCtx, "POST", ns.telegramServiceURL+"/send-message", bytes.NewBuffer(jsonData))
 	if err != nil {
 		observability.FinishSpan(httpSpan, err)
@@ -288,7 +391,7 @@ func (ns *NotificationService) sendTelegramMessageWithResult(ctx context.Context
 	resp, err := client.Do(req)
 	if err != nil {
 		observability.FinishSpan(httpSpan, err)
-		observability.CaptureExceptionWithContext(spanCtx, err, "telegram_http_send", map[string]interface{}{
+		observability.CaptureExceptionWithContext(ctx, err, "telegram_http_send", map[string]interface{}{
 			"chat_id": chatID,
 		})
 		return TelegramSendResult{
@@ -306,6 +409,7 @@ func (ns *NotificationService) sendTelegramMessageWithResult(ctx context.Context
 		OK        bool   `json:"ok"`
 		Error     string `json:"error"`
 		ErrorCode string `json:"errorCode"`
+		MessageID string `json:"messageId"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&respBody); err != nil {
 		// Couldn't parse response, but check status code
@@ -334,12 +438,74 @@ func (ns *NotificationService) sendTelegramMessageWithResult(ctx context.Context
 	}
 
 	observability.FinishSpan(httpSpan, nil)
-	observability.AddBreadcrumb(spanCtx, "notification", "Telegram message sent via HTTP", sentry.LevelInfo)
-	return TelegramSendResult{OK: true}
+	observability.AddBreadcrumb(ctx, "notification", "Telegram message sent via HTTP", sentry.LevelInfo)
+	return TelegramSendResult{OK: true, MessageID: respBody.MessageID}
 }
 
-// sendTelegramMessageWithRetry sends a message with retry logic for transient errors
+// EnableDeliveryQueue routes user notifications through a durable job queue
+// instead of sending them inline. Workers are not started here.
+//
+// Parameters:
+//
+//	queue: Redis-backed job queue.
+//	config: Delivery queue configuration.
+//
+// Returns:
+//
+//	*NotificationDeliveryQueue: The delivery queue now used by the service.
+func (ns *NotificationService) EnableDeliveryQueue(queue *jobqueue.Queue, config NotificationDeliveryQueueConfig) *NotificationDeliveryQueue {
+	var deadLetter notificationDeadLetterStore
+	if ns.deadLetterService != nil {
+		deadLetter = ns.deadLetterService
+	}
+	ns.deliveryQueue = NewNotificationDeliveryQueue(queue, config, ns.sendTelegramMessageWithPriority, ns.recordDeliveryResult, deadLetter)
+	return ns.deliveryQueue
+}
+
+// DeliveryQueue returns the durable delivery queue, or nil when sends are inline.
+func (ns *NotificationService) DeliveryQueue() *NotificationDeliveryQueue {
+	return ns.deliveryQueue
+}
+
+// GetDeliveryQueueStats returns delivery queue depth and counters.
+func (ns *NotificationService) GetDeliveryQueueStats(ctx context.Context) (*NotificationQueueStats, error) {
+	if ns.deliveryQueue == nil {
+		return nil, fmt.Errorf("notification delivery queue not enabled")
+	}
+	return ns.deliveryQueue.Stats(ctx)
+}
+
+// sendTelegramMessageWithRetry sends a message with retry logic for transient errors.
+// When a delivery queue is enabled the message is persisted and delivered by
+// the queue workers instead.
 func (ns *NotificationService) sendTelegramMessageWithRetry(ctx context.Context, chatID int64, text string, userID string) error {
+	return ns.sendTelegramMessageWithRetryAndMarkup(ctx, chatID, text, userID, nil)
+}
+
+// sendTelegramMessageWithRetryAndMarkup is sendTelegramMessageWithRetry with an optional inline keyboard.
+func (ns *NotificationService) sendTelegramMessageWithRetryAndMarkup(ctx context.Context, chatID int64, text string, userID string, markup *InlineKeyboardMarkup) error {
+	return ns.sendTelegramMessageWithRetryAndPriority(ctx, chatID, text, userID, markup, PriorityNotificationNormal)
+}
+
+// sendTelegramMessageWithRetryAndPriority is sendTelegramMessageWithRetryAndMarkup
+// for a given priority, which is kept on the queued job and the dead letter.
+func (ns *NotificationService) sendTelegramMessageWithRetryAndPriority(ctx context.Context, chatID int64, text string, userID string, markup *InlineKeyboardMarkup, priority NotificationPriority) error {
+	if ns.deliveryQueue != nil {
+		jobID, err := ns.deliveryQueue.Enqueue(ctx, QueuedNotification{
+			ChatID:      chatID,
+			UserID:      userID,
+			MessageType: "telegram_notification",
+			Text:        text,
+			ReplyMarkup: markup,
+			Priority:    priority,
+		})
+		if err == nil {
+			ns.logger.Debug("Queued Telegram message", "job_id", jobID, "chat_id", chatID, "priority", priority)
+			return nil
+		}
+		ns.logger.Warn("Failed to queue Telegram message, sending inline", "chat_id", chatID, "error", err)
+	}
+
 	const maxRetries = 3
 	baseDelay := time.Second
 
@@ -360,7 +526,8 @@ func (ns *NotificationService) sendTelegramMessageWithRetry(ctx context.Context,
 			time.Sleep(delay)
 		}
 
-		lastResult = ns.sendTelegramMessageWithResult(ctx, chatID, text)
+		lastResult = ns.sendTelegramMessageWithPriority(ctx, chatID, text, markup, priority)
+		ns.recordDeliveryResult(ctx, userID, chatID, lastResult)
 
 		if lastResult.OK {
 			if attempt > 0 {
@@ -377,15 +544,6 @@ func (ns *NotificationService) sendTelegramMessageWithRetry(ctx context.Context,
 				"chat_id", chatID,
 			)
 
-			// Handle blocked users - mark them in database
-			if lastResult.ErrorCode == TelegramErrorUserBlocked || lastResult.ErrorCode == TelegramErrorChatNotFound {
-				if userID != "" {
-					if err := ns.handleBlockedUser(ctx, userID, string(lastResult.ErrorCode)); err != nil {
-						ns.logger.Error("Failed to mark user as blocked", "user_id", userID, "error", err)
-					}
-				}
-			}
-
 			return fmt.Errorf("%s: %s", lastResult.ErrorCode, lastResult.Error)
 		}
 
@@ -407,6 +565,7 @@ func (ns *NotificationService) sendTelegramMessageWithRetry(ctx context.Context,
 			chatIDStr,
 			"telegram_notification",
 			text,
+			priority,
 			string(lastResult.ErrorCode),
 			lastResult.Error,
 		)
@@ -422,6 +581,160 @@ func (ns *NotificationService) sendTelegramMessageWithRetry(ctx context.Context,
 	return fmt.Errorf("failed after %d retries: %s: %s", maxRetries, lastResult.ErrorCode, lastResult.Error)
 }
 
+// SetActionService enables inline action buttons on opportunity alerts and
+// filters symbols the user has snoozed.
+func (ns *NotificationService) SetActionService(service *NotificationActionService) {
+	ns.actionService = service
+}
+
+// SetKPIRecorder records how long each Telegram message takes to send.
+func (ns *NotificationService) SetKPIRecorder(kpis KPIRecorder) {
+	ns.kpis = kpis
+}
+
+// SetHooks sets the registry whose pre-notify hooks may rewrite or suppress
+// Telegram messages before they are sent.
+func (ns *NotificationService) SetHooks(hooks *HookRegistry) {
+	ns.hooks = hooks
+}
+
+// SetChangeDetector suppresses alerts for symbols whose best spread or
+// signal has not materially changed since they were last alerted.
+func (ns *NotificationService) SetChangeDetector(detector *NotificationChangeDetector) {
+	ns.changeDetector = detector
+}
+
+// SetLifecycleService marks alerted arbitrage opportunities as notified in
+// their persisted lifecycle.
+func (ns *NotificationService) SetLifecycleService(lifecycle *OpportunityLifecycleService) {
+	ns.lifecycle = lifecycle
+}
+
+// SetQuietHours holds back low priority notifications, such as quest progress and
+// fund milestones, during a daily window. Nil disables quiet hours.
+func (ns *NotificationService) SetQuietHours(quietHours *QuietHours) {
+	ns.quietHours = quietHours
+}
+
+// SetEscalation escalates critical risk events that are not acknowledged in
+// time; the alerts get an acknowledge button when actions are enabled.
+func (ns *NotificationService) SetEscalation(escalation *CriticalEscalationService) {
+	ns.escalation = escalation
+}
+
+// sendPrioritizedMessage sends a message at the given priority. During quiet
+// hours low priority messages are queued until the window ends, or dropped when there
+// is no delivery queue; other priorities are sent right away.
+func (ns *NotificationService) sendPrioritizedMessage(ctx context.Context, chatID int64, text string, markup *InlineKeyboardMarkup, priority NotificationPriority) error {
+	if priority == PriorityNotificationLow {
+		if until, quiet := ns.quietHours.Until(ns.currentTime()); quiet {
+			if ns.deliveryQueue == nil {
+				ns.logger.Info("Dropping low priority notification during quiet hours", "chat_id", chatID)
+				return nil
+			}
+			_, err := ns.deliveryQueue.Enqueue(ctx, QueuedNotification{
+				ChatID:      chatID,
+				MessageType: "telegram_notification",
+				Text:        text,
+				Priority:    priority,
+				NotBefore:   until,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to defer notification until quiet hours end: %w", err)
+			}
+			ns.logger.Info("Deferred low priority notification until quiet hours end", "chat_id", chatID, "until", until)
+			return nil
+		}
+	}
+
+	result := ns.sendTelegramMessageWithPriority(ctx, chatID, text, markup, priority)
+	if result.OK {
+		return nil
+	}
+	return fmt.Errorf("%s: %s", result.ErrorCode, result.Error)
+}
+
+// filterSnoozedOpportunities drops opportunities whose symbol is snoozed for the chat.
+func (ns *NotificationService) filterSnoozedOpportunities(ctx context.Context, chatID int64, opportunities []ArbitrageOpportunity) []ArbitrageOpportunity {
+	if ns.actionService == nil {
+		return opportunities
+	}
+	filtered := make([]ArbitrageOpportunity, 0, len(opportunities))
+	for _, opp := range opportunities {
+		if !ns.actionService.IsSymbolSnoozed(ctx, chatID, opp.Symbol) {
+			filtered = append(filtered, opp)
+		}
+	}
+	return filtered
+}
+
+// SetDeliveryTracker enables delivery receipts and bounce-threshold handling.
+// Without a tracker, a single USER_BLOCKED/CHAT_NOT_FOUND result blocks the user.
+func (ns *NotificationService) SetDeliveryTracker(tracker *NotificationDeliveryTracker) {
+	ns.deliveryTracker = tracker
+}
+
+// GetUserDeliveryStats returns delivery receipts and counters for a user.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	userID: The user ID.
+//	recentLimit: Number of recent receipts to include.
+//
+// Returns:
+//
+//	*DeliveryStats: Aggregated delivery statistics.
+//	error: Error if the tracker is disabled or the lookup fails.
+func (ns *NotificationService) GetUserDeliveryStats(ctx context.Context, userID string, recentLimit int) (*DeliveryStats, error) {
+	if ns.deliveryTracker == nil {
+		return nil, fmt.Errorf("delivery tracking not enabled")
+	}
+	return ns.deliveryTracker.GetUserStats(ctx, userID, recentLimit)
+}
+
+// recordDeliveryResult stores a delivery receipt and disables notifications
+// for chats that keep bouncing.
+func (ns *NotificationService) recordDeliveryResult(ctx context.Context, userID string, chatID int64, result TelegramSendResult) {
+	bounced := result.ErrorCode == TelegramErrorUserBlocked || result.ErrorCode == TelegramErrorChatNotFound
+
+	if ns.deliveryTracker == nil {
+		if bounced && userID != "" {
+			if err := ns.handleBlockedUser(ctx, userID, string(result.ErrorCode)); err != nil {
+				ns.logger.Error("Failed to mark user as blocked", "user_id", userID, "error", err)
+			}
+		}
+		return
+	}
+
+	disable, err := ns.deliveryTracker.Record(ctx, userID, chatID, result)
+	if err != nil {
+		ns.logger.Error("Failed to record delivery receipt", "user_id", userID, "chat_id", chatID, "error", err)
+		return
+	}
+	if !disable {
+		return
+	}
+
+	ns.logger.Warn("Disabling notifications after consecutive bounces",
+		"user_id", userID,
+		"chat_id", chatID,
+		"threshold", ns.deliveryTracker.BounceThreshold(),
+		"error_code", result.ErrorCode,
+	)
+	if ns.redis != nil {
+		key := fmt.Sprintf("telegram:user:%d:notifications_enabled", chatID)
+		if err := ns.redis.Set(ctx, key, "false", 0); err != nil {
+			ns.logger.Error("Failed to disable chat notifications", "chat_id", chatID, "error", err)
+		}
+	}
+	if userID != "" {
+		if err := ns.handleBlockedUser(ctx, userID, string(result.ErrorCode)); err != nil {
+			ns.logger.Error("Failed to mark user as blocked", "user_id", userID, "error", err)
+		}
+	}
+}
+
 // handleBlockedUser marks a user as blocked in the database
 func (ns *NotificationService) handleBlockedUser(ctx context.Context, userID, reason string) error {
 	ns.logger.Info("Marking user as blocked", "user_id", userID, "reason", reason)
@@ -689,8 +1002,9 @@ func (ns *NotificationService) InvalidateOpportunityCache(ctx context.Context) {
 
 // formatTechnicalSignalMessage creates a formatted message for technical analysis signals
 func (ns *NotificationService) formatTechnicalSignalMessage(signals []TechnicalSignalNotification) string {
+	f := ns.formatter()
 	if len(signals) == 0 {
-		return "No technical analysis signals found."
+		return f.Text("No technical analysis signals found.")
 	}
 
 	// Take top 3 signals for the alert
@@ -699,46 +1013,45 @@ func (ns *NotificationService) formatTechnicalSignalMessage(signals []TechnicalS
 		topSignals = signals[:3]
 	}
 
-	header := "📊 *Technical Analysis Signals*\n\n"
+	header := "📊 " + f.Bold("Technical Analysis Signals") + "\n\n"
 	message := header
-	message += fmt.Sprintf("Found %d high-confidence signals:\n\n", len(signals))
+	message += f.Text(fmt.Sprintf("Found %d high-confidence signals:", len(signals))) + "\n\n"
 
 	for i, signal := range topSignals {
-		message += fmt.Sprintf("📊 *TA SIGNAL: %s*\n", signal.Symbol)
-		message += fmt.Sprintf("🎯 *Signal:* %s\n", signal.SignalText)
-		message += fmt.Sprintf("💲 *Current Price:* $%.4f\n", signal.CurrentPrice)
-		message += fmt.Sprintf("📈 *Entry:* %s\n", signal.EntryRange)
+		message += "📊 " + f.Bold("TA SIGNAL: "+signal.Symbol) + "\n"
+		message += formatField(f, "🎯", "Signal", signal.SignalText)
+		message += formatField(f, "💲", "Current Price", fmt.Sprintf("$%.4f", signal.CurrentPrice))
+		message += formatField(f, "📈", "Entry", signal.EntryRange)
 
 		// Add targets
 		for j, target := range signal.Targets {
-			message += fmt.Sprintf("🎯 *Target %d:* $%.4f (%.1f%% profit)\n", j+1, target.Price, target.Profit)
+			message += formatField(f, "🎯", fmt.Sprintf("Target %d", j+1), fmt.Sprintf("$%.4f (%.1f%% profit)", target.Price, target.Profit))
 		}
 
 		// Add stop loss
-		message += fmt.Sprintf("🛑 *Stop Loss:* $%.4f (%.1f%% risk)\n", signal.StopLoss.Price, signal.StopLoss.Risk)
-		message += fmt.Sprintf("📊 *Risk/Reward:* %s\n", signal.RiskReward)
+		message += formatField(f, "🛑", "Stop Loss", fmt.Sprintf("$%.4f (%.1f%% risk)", signal.StopLoss.Price, signal.StopLoss.Risk))
+		message += formatField(f, "📊", "Risk/Reward", signal.RiskReward)
 
 		// Add exchanges
 		if len(signal.Exchanges) > 0 {
-			exchangeList := strings.Join(signal.Exchanges, ", ")
-			message += fmt.Sprintf("🏪 *Exchanges:* %s\n", exchangeList)
+			message += formatField(f, "🏪", "Exchanges", strings.Join(signal.Exchanges, ", "))
 		}
 
-		message += fmt.Sprintf("⏰ *Timeframe:* %s\n", signal.Timeframe)
-		message += fmt.Sprintf("🎯 *Confidence:* %.1f%%\n", signal.Confidence*100)
+		message += formatField(f, "⏰", "Timeframe", signal.Timeframe)
+		message += formatField(f, "🎯", "Confidence", fmt.Sprintf("%.1f%%", signal.Confidence*100))
 
 		if i < len(topSignals)-1 {
-			message += "\n---\n\n"
+			message += "\n" + f.Text("---") + "\n\n"
 		}
 	}
 
 	if len(signals) > 3 {
-		message += fmt.Sprintf("\n...and %d more signals\n\n", len(signals)-3)
+		message += "\n" + f.Text(fmt.Sprintf("...and %d more signals", len(signals)-3)) + "\n\n"
 	}
 
-	message += "\n⚡ *Trade wisely!* Always manage your risk and position size.\n\n"
-	message += "Use /signals to see all current technical signals\n"
-	message += "Use /stop to pause these alerts"
+	message += "\n⚡ " + f.Bold("Trade wisely!") + f.Text(" Always manage your risk and position size.") + "\n\n"
+	message += f.Text("Use /signals to see all current technical signals") + "\n"
+	message += f.Text("Use /stop to pause these alerts")
 
 	return message
 }
@@ -931,6 +1244,15 @@ func (ns *NotificationService) getEligibleUsers(ctx context.Context) ([]userMode
 	return users, nil
 }
 
+// admitNotification applies the per-user rate limit to a notification of the
+// given priority; critical notifications are always admitted.
+func (ns *NotificationService) admitNotification(ctx context.Context, userID string, priority NotificationPriority) (bool, error) {
+	if priority == PriorityNotificationEmergency {
+		return true, nil
+	}
+	return ns.checkRateLimit(ctx, userID)
+}
+
 // checkRateLimit checks if a user has exceeded the notification rate limit (5 notifications per minute)
 // Uses fail-closed strategy: denies requests when Redis is unavailable to prevent abuse
 func (ns *NotificationService) checkRateLimit(ctx context.Context, userID string) (bool, error) {
@@ -1045,8 +1367,26 @@ func (ns *NotificationService) sendArbitrageAlert(ctx context.Context, user user
 		}
 	}
 
+	chatID, err := strconv.ParseInt(*user.TelegramChatID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid chat ID: %w", err)
+	}
+
+	opportunities = ns.filterSnoozedOpportunities(ctx, chatID, opportunities)
+	if len(opportunities) == 0 {
+		ns.logger.Info("All opportunities snoozed, skipping", "user_id", user.ID)
+		return nil
+	}
+	if ns.changeDetector != nil {
+		opportunities = ns.changeDetector.FilterOpportunities(chatID, opportunities)
+		if len(opportunities) == 0 {
+			ns.logger.Info("Opportunities unchanged since last alert, skipping", "user_id", user.ID)
+			return nil
+		}
+	}
+
 	// Check rate limit before sending
-	allowed, err := ns.checkRateLimit(ctx, user.ID)
+	allowed, err := ns.admitNotification(ctx, user.ID, PriorityNotificationNormal)
 	if err != nil {
 		ns.logger.Error("Rate limit check failed", "user_id", user.ID, "error", err)
 	}
@@ -1055,11 +1395,6 @@ func (ns *NotificationService) sendArbitrageAlert(ctx context.Context, user user
 		return fmt.Errorf("rate limit exceeded for user %s", user.ID)
 	}
 
-	chatID, err := strconv.ParseInt(*user.TelegramChatID, 10, 64)
-	if err != nil {
-		return fmt.Errorf("invalid chat ID: %w", err)
-	}
-
 	// Generate hash for opportunities to check cache
 	oppHash := ns.generateOpportunityHash(opportunities)
 
@@ -1075,13 +1410,32 @@ func (ns *NotificationService) sendArbitrageAlert(ctx context.Context, user user
 		ns.logger.Info("Formatted and cached new arbitrage message", "hash", oppHash[:8])
 	}
 
+	// Attach action buttons for the top opportunity
+	var markup *InlineKeyboardMarkup
+	if ns.actionService != nil {
+		markup, err = ns.actionService.BuildOpportunityKeyboard(ctx, chatID, user.ID, opportunities[0])
+		if err != nil {
+			ns.logger.Warn("Failed to build action keyboard", "user_id", user.ID, "error", err)
+			markup = nil
+		}
+	}
+
 	// Send the message with retry logic
-	err = ns.sendTelegramMessageWithRetry(ctx, chatID, message, user.ID)
+	err = ns.sendTelegramMessageWithRetryAndMarkup(ctx, chatID, message, user.ID, markup)
 
 	if err != nil {
 		return fmt.Errorf("failed to send telegram message: %w", err)
 	}
 
+	if ns.changeDetector != nil {
+		ns.changeDetector.RecordOpportunities(chatID, opportunities)
+	}
+	if ns.lifecycle != nil {
+		if err := ns.lifecycle.MarkNotified(ctx, opportunities); err != nil {
+			ns.logger.Warn("Failed to mark opportunities notified", "user_id", user.ID, "error", err)
+		}
+	}
+
 	// Log the notification
 	if err := ns.logNotification(ctx, user.ID, "telegram", "arbitrage_alert"); err != nil {
 		ns.logger.Error("Failed to log notification", "user_id", user.ID, "error", err)
@@ -1102,8 +1456,18 @@ func (ns *NotificationService) sendEnhancedArbitrageAlert(ctx context.Context, u
 		}
 	}
 
+	chatID, err := strconv.ParseInt(*user.TelegramChatID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid chat ID: %w", err)
+	}
+
+	if ns.changeDetector != nil && len(ns.changeDetector.FilterSignals(chatID, "enhanced_arbitrage", []*AggregatedSignal{signal})) == 0 {
+		ns.logger.Info("Signal unchanged since last alert, skipping", "user_id", user.ID, "symbol", signal.Symbol)
+		return nil
+	}
+
 	// Check rate limit before sending
-	allowed, err := ns.checkRateLimit(ctx, user.ID)
+	allowed, err := ns.admitNotification(ctx, user.ID, PriorityNotificationNormal)
 	if err != nil {
 		ns.logger.Error("Rate limit check failed", "user_id", user.ID, "error", err)
 	}
@@ -1112,11 +1476,6 @@ func (ns *NotificationService) sendEnhancedArbitrageAlert(ctx context.Context, u
 		return fmt.Errorf("rate limit exceeded for user %s", user.ID)
 	}
 
-	chatID, err := strconv.ParseInt(*user.TelegramChatID, 10, 64)
-	if err != nil {
-		return fmt.Errorf("invalid chat ID: %w", err)
-	}
-
 	// Generate hash for signal to check cache
 	signalHash := stableHash(fmt.Sprintf("%s:%s:%.4f", signal.Symbol, signal.SignalType, signal.Confidence.InexactFloat64()))
 
@@ -1138,6 +1497,10 @@ func (ns *NotificationService) sendEnhancedArbitrageAlert(ctx context.Context, u
 		return fmt.Errorf("failed to send telegram message: %w", err)
 	}
 
+	if ns.changeDetector != nil {
+		ns.changeDetector.RecordSignals(chatID, "enhanced_arbitrage", []*AggregatedSignal{signal})
+	}
+
 	// Log the notification
 	if err := ns.logNotification(ctx, user.ID, "telegram", "enhanced_arbitrage_alert"); err != nil {
 		ns.logger.Error("Failed to log notification", "user_id", user.ID, "error", err)
@@ -1198,8 +1561,9 @@ func (ns *NotificationService) NotifyEnhancedArbitrageSignals(ctx context.Contex
 
 // formatArbitrageMessage creates a formatted message for arbitrage opportunities
 func (ns *NotificationService) formatArbitrageMessage(opportunities []ArbitrageOpportunity) string {
+	f := ns.formatter()
 	if len(opportunities) == 0 {
-		return "No arbitrage opportunities found."
+		return f.Text("No arbitrage opportunities found.")
 	}
 
 	// Take top 3 opportunities for the alert
@@ -1209,44 +1573,45 @@ func (ns *NotificationService) formatArbitrageMessage(opportunities []ArbitrageO
 	}
 
 	// Determine message header based on opportunity type
-	header := "🚨 *Arbitrage Alert!*\n\n"
+	header := "🚨 " + f.Bold("Arbitrage Alert!") + "\n\n"
 	if len(opportunities) > 0 {
 		switch opportunities[0].OpportunityType {
 		case "arbitrage":
-			header = "🚀 *True Arbitrage Opportunities*\n\n"
+			header = "🚀 " + f.Bold("True Arbitrage Opportunities") + "\n\n"
 		case "technical":
-			header = "📊 *Technical Analysis Signals*\n\n"
+			header = "📊 " + f.Bold("Technical Analysis Signals") + "\n\n"
 		case "ai_generated":
-			header = "🤖 *AI-Generated Opportunities*\n\n"
+			header = "🤖 " + f.Bold("AI-Generated Opportunities") + "\n\n"
 		}
 	}
 
 	message := header
-	message += fmt.Sprintf("Found %d profitable opportunities:\n\n", len(opportunities))
+	message += f.Text(fmt.Sprintf("Found %d profitable opportunities:", len(opportunities))) + "\n\n"
 
 	for i, opp := range topOpportunities {
-		message += fmt.Sprintf("*%d. %s*\n", i+1, opp.Symbol)
-		message += fmt.Sprintf("💰 Profit: *%.2f%%*\n", opp.ProfitPercent)
-		message += fmt.Sprintf("📈 Buy: %s @ $%.4f\n", opp.BuyExchange, opp.BuyPrice)
-		message += fmt.Sprintf("📉 Sell: %s @ $%.4f\n", opp.SellExchange, opp.SellPrice)
+		message += f.Bold(fmt.Sprintf("%d. %s", i+1, opp.Symbol)) + "\n"
+		message += "💰 " + f.Text("Profit: ") + f.Bold(fmt.Sprintf("%.2f%%", opp.ProfitPercent)) + "\n"
+		message += f.Text(fmt.Sprintf("📈 Buy: %s @ $%.4f", opp.BuyExchange, opp.BuyPrice)) + "\n"
+		message += f.Text(fmt.Sprintf("📉 Sell: %s @ $%.4f", opp.SellExchange, opp.SellPrice)) + "\n"
 		message += "\n"
 	}
 
 	if len(opportunities) > 3 {
-		message += fmt.Sprintf("...and %d more opportunities\n\n", len(opportunities)-3)
+		message += f.Text(fmt.Sprintf("...and %d more opportunities", len(opportunities)-3)) + "\n\n"
 	}
 
-	message += "⚡ *Act fast!* These opportunities may disappear quickly.\n\n"
-	message += "Use /opportunities to see all current opportunities\n"
-	message += "Use /stop to pause these alerts"
+	message += "⚡ " + f.Bold("Act fast!") + f.Text(" These opportunities may disappear quickly.") + "\n\n"
+	message += f.Text("Use /opportunities to see all current opportunities") + "\n"
+	message += f.Text("Use /stop to pause these alerts")
 
 	return message
 }
 
 // formatEnhancedArbitrageMessage creates a formatted message for enhanced arbitrage signals with price ranges
 func (ns *NotificationService) formatEnhancedArbitrageMessage(signal *AggregatedSignal) string {
+	f := ns.formatter()
 	if signal == nil || signal.SignalType != SignalTypeArbitrage {
-		return "No arbitrage signal found."
+		return f.Text("No arbitrage signal found.")
 	}
 
 	// Extract metadata
@@ -1261,7 +1626,7 @@ func (ns *NotificationService) formatEnhancedArbitrageMessage(signal *Aggregated
 	validityMinutes, _ := metadata["validity_minutes"].(int)
 
 	// Build the message
-	message := fmt.Sprintf("🔄 *ARBITRAGE ALERT: %s*\n\n", signal.Symbol)
+	message := "🔄 " + f.Bold("ARBITRAGE ALERT: "+signal.Symbol) + "\n\n"
 
 	// Profit range
 	if profitRange != nil {
@@ -1272,12 +1637,13 @@ func (ns *NotificationService) formatEnhancedArbitrageMessage(signal *Aggregated
 		baseAmount, _ := profitRange["base_amount"].(decimal.Decimal)
 
 		if minPercent.Equal(maxPercent) {
-			message += fmt.Sprintf("💰 Profit: *%.2f%%* ($%.0f on $%.0f)\n",
-				minPercent.InexactFloat64(), minDollar.InexactFloat64(), baseAmount.InexactFloat64())
+			message += "💰 " + f.Text("Profit: ") + f.Bold(fmt.Sprintf("%.2f%%", minPercent.InexactFloat64())) +
+				f.Text(fmt.Sprintf(" ($%.0f on $%.0f)", minDollar.InexactFloat64(), baseAmount.InexactFloat64())) + "\n"
 		} else {
-			message += fmt.Sprintf("💰 Profit: *%.2f%% - %.2f%%* ($%.0f - $%.0f on $%.0f)\n",
-				minPercent.InexactFloat64(), maxPercent.InexactFloat64(),
-				minDollar.InexactFloat64(), maxDollar.InexactFloat64(), baseAmount.InexactFloat64())
+			message += "💰 " + f.Text("Profit: ") +
+				f.Bold(fmt.Sprintf("%.2f%% - %.2f%%", minPercent.InexactFloat64(), maxPercent.InexactFloat64())) +
+				f.Text(fmt.Sprintf(" ($%.0f - $%.0f on $%.0f)",
+					minDollar.InexactFloat64(), maxDollar.InexactFloat64(), baseAmount.InexactFloat64())) + "\n"
 		}
 	}
 
@@ -1288,10 +1654,10 @@ func (ns *NotificationService) formatEnhancedArbitrageMessage(signal *Aggregated
 
 		exchangeList := strings.Join(buyExchanges, ", ")
 		if buyMin.Equal(buyMax) {
-			message += fmt.Sprintf("📈 BUY: $%.4f (%s)\n", buyMin.InexactFloat64(), exchangeList)
+			message += f.Text(fmt.Sprintf("📈 BUY: $%.4f (%s)", buyMin.InexactFloat64(), exchangeList)) + "\n"
 		} else {
-			message += fmt.Sprintf("📈 BUY: $%.4f - $%.4f (%s)\n",
-				buyMin.InexactFloat64(), buyMax.InexactFloat64(), exchangeList)
+			message += f.Text(fmt.Sprintf("📈 BUY: $%.4f - $%.4f (%s)",
+				buyMin.InexactFloat64(), buyMax.InexactFloat64(), exchangeList)) + "\n"
 		}
 	}
 
@@ -1302,31 +1668,31 @@ func (ns *NotificationService) formatEnhancedArbitrageMessage(signal *Aggregated
 
 		exchangeList := strings.Join(sellExchanges, ", ")
 		if sellMin.Equal(sellMax) {
-			message += fmt.Sprintf("📉 SELL: $%.4f (%s)\n", sellMax.InexactFloat64(), exchangeList)
+			message += f.Text(fmt.Sprintf("📉 SELL: $%.4f (%s)", sellMax.InexactFloat64(), exchangeList)) + "\n"
 		} else {
-			message += fmt.Sprintf("📉 SELL: $%.4f - $%.4f (%s)\n",
-				sellMin.InexactFloat64(), sellMax.InexactFloat64(), exchangeList)
+			message += f.Text(fmt.Sprintf("📉 SELL: $%.4f - $%.4f (%s)",
+				sellMin.InexactFloat64(), sellMax.InexactFloat64(), exchangeList)) + "\n"
 		}
 	}
 
 	// Validity and volume info
 	if validityMinutes > 0 {
-		message += fmt.Sprintf("⏰ Valid for: *%d minutes*\n", validityMinutes)
+		message += "⏰ " + f.Text("Valid for: ") + f.Bold(fmt.Sprintf("%d minutes", validityMinutes)) + "\n"
 	}
 
 	if !minVolume.IsZero() {
-		message += fmt.Sprintf("🎯 Min Volume: *$%.0f*\n", minVolume.InexactFloat64())
+		message += "🎯 " + f.Text("Min Volume: ") + f.Bold(fmt.Sprintf("$%.0f", minVolume.InexactFloat64())) + "\n"
 	}
 
 	// Additional info
 	if opportunityCount > 1 {
-		message += fmt.Sprintf("📊 Opportunities: *%d*\n", opportunityCount)
+		message += "📊 " + f.Text("Opportunities: ") + f.Bold(fmt.Sprintf("%d", opportunityCount)) + "\n"
 	}
 
-	message += fmt.Sprintf("🎯 Confidence: *%.1f%%*\n", signal.Confidence.Mul(decimal.NewFromFloat(100)).InexactFloat64())
+	message += "🎯 " + f.Text("Confidence: ") + f.Bold(fmt.Sprintf("%.1f%%", signal.Confidence.Mul(decimal.NewFromFloat(100)).InexactFloat64())) + "\n"
 
-	message += "\n⚡ *Act fast!* Arbitrage opportunities disappear quickly.\n"
-	message += "💡 *Min Volume* helps filter out low-liquidity fake signals."
+	message += "\n⚡ " + f.Bold("Act fast!") + f.Text(" Arbitrage opportunities disappear quickly.") + "\n"
+	message += "💡 " + f.Bold("Min Volume") + f.Text(" helps filter out low-liquidity fake signals.")
 
 	return message
 }
@@ -1477,8 +1843,21 @@ func (ns *NotificationService) NotifyAggregatedSignals(ctx context.Context, sign
 
 // sendAggregatedArbitrageAlert sends a formatted aggregated arbitrage alert to a specific user
 func (ns *NotificationService) sendAggregatedArbitrageAlert(ctx context.Context, user userModels.User, signals []*AggregatedSignal) error {
+	chatID, err := strconv.ParseInt(*user.TelegramChatID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid chat ID: %w", err)
+	}
+
+	if ns.changeDetector != nil {
+		signals = ns.changeDetector.FilterSignals(chatID, "aggregated_arbitrage", signals)
+		if len(signals) == 0 {
+			ns.logger.Info("Signals unchanged since last alert, skipping", "user_id", user.ID)
+			return nil
+		}
+	}
+
 	// Check rate limit before sending
-	allowed, err := ns.checkRateLimit(ctx, user.ID)
+	allowed, err := ns.admitNotification(ctx, user.ID, PriorityNotificationNormal)
 	if err != nil {
 		ns.logger.Error("Rate limit check failed", "user_id", user.ID, "error", err)
 	}
@@ -1487,11 +1866,6 @@ func (ns *NotificationService) sendAggregatedArbitrageAlert(ctx context.Context,
 		return fmt.Errorf("rate limit exceeded for user %s", user.ID)
 	}
 
-	chatID, err := strconv.ParseInt(*user.TelegramChatID, 10, 64)
-	if err != nil {
-		return fmt.Errorf("invalid chat ID: %w", err)
-	}
-
 	// Generate hash for signals to check cache
 	signalsHash := ns.generateAggregatedSignalsHash(signals)
 
@@ -1514,6 +1888,10 @@ func (ns *NotificationService) sendAggregatedArbitrageAlert(ctx context.Context,
 		return fmt.Errorf("failed to send telegram message: %w", err)
 	}
 
+	if ns.changeDetector != nil {
+		ns.changeDetector.RecordSignals(chatID, "aggregated_arbitrage", signals)
+	}
+
 	// Log the notification
 	if err := ns.logNotification(ctx, user.ID, "telegram", "aggregated_arbitrage_alert"); err != nil {
 		ns.logger.Error("Failed to log notification", "user_id", user.ID, "error", err)
@@ -1524,8 +1902,21 @@ func (ns *NotificationService) sendAggregatedArbitrageAlert(ctx context.Context,
 
 // sendAggregatedTechnicalAlert sends a formatted aggregated technical alert to a specific user
 func (ns *NotificationService) sendAggregatedTechnicalAlert(ctx context.Context, user userModels.User, signals []*AggregatedSignal) error {
+	chatID, err := strconv.ParseInt(*user.TelegramChatID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid chat ID: %w", err)
+	}
+
+	if ns.changeDetector != nil {
+		signals = ns.changeDetector.FilterSignals(chatID, "aggregated_technical", signals)
+		if len(signals) == 0 {
+			ns.logger.Info("Signals unchanged since last alert, skipping", "user_id", user.ID)
+			return nil
+		}
+	}
+
 	// Check rate limit before sending
-	allowed, err := ns.checkRateLimit(ctx, user.ID)
+	allowed, err := ns.admitNotification(ctx, user.ID, PriorityNotificationNormal)
 	if err != nil {
 		ns.logger.Error("Rate limit check failed", "user_id", user.ID, "error", err)
 	}
@@ -1534,11 +1925,6 @@ func (ns *NotificationService) sendAggregatedTechnicalAlert(ctx context.Context,
 		return fmt.Errorf("rate limit exceeded for user %s", user.ID)
 	}
 
-	chatID, err := strconv.ParseInt(*user.TelegramChatID, 10, 64)
-	if err != nil {
-		return fmt.Errorf("invalid chat ID: %w", err)
-	}
-
 	// Generate hash for signals to check cache
 	signalsHash := ns.generateAggregatedSignalsHash(signals)
 
@@ -1561,6 +1947,10 @@ func (ns *NotificationService) sendAggregatedTechnicalAlert(ctx context.Context,
 		return fmt.Errorf("failed to send telegram message: %w", err)
 	}
 
+	if ns.changeDetector != nil {
+		ns.changeDetector.RecordSignals(chatID, "aggregated_technical", signals)
+	}
+
 	// Log the notification
 	if err := ns.logNotification(ctx, user.ID, "telegram", "aggregated_technical_alert"); err != nil {
 		ns.logger.Error("Failed to log notification", "user_id", user.ID, "error", err)
@@ -1597,8 +1987,9 @@ func (ns *NotificationService) generateAggregatedSignalsHash(signals []*Aggregat
 
 // formatAggregatedArbitrageMessage formats multiple arbitrage signals into a single message
 func (ns *NotificationService) formatAggregatedArbitrageMessage(signals []*AggregatedSignal) string {
+	f := ns.formatter()
 	if len(signals) == 0 {
-		return "🔍 No arbitrage opportunities available"
+		return f.Text("🔍 No arbitrage opportunities available")
 	}
 
 	// Sort signals by profit potential (highest first)
@@ -1607,7 +1998,7 @@ func (ns *NotificationService) formatAggregatedArbitrageMessage(signals []*Aggre
 	})
 
 	var message strings.Builder
-	message.WriteString("🚀 *Aggregated Arbitrage Opportunities*\n\n")
+	message.WriteString("🚀 " + f.Bold("Aggregated Arbitrage Opportunities") + "\n\n")
 
 	// Limit to top 5 opportunities to keep message manageable
 	maxSignals := len(signals)
@@ -1616,19 +2007,19 @@ func (ns *NotificationService) formatAggregatedArbitrageMessage(signals []*Aggre
 	}
 
 	for i, signal := range signals[:maxSignals] {
-		fmt.Fprintf(&message, "*%d. %s*\n", i+1, signal.Symbol)
-		fmt.Fprintf(&message, "💰 Profit: %.2f%%\n", signal.ProfitPotential.InexactFloat64())
-		fmt.Fprintf(&message, "🎯 Confidence: %.1f%%\n", signal.Confidence.InexactFloat64())
-		fmt.Fprintf(&message, "⚡ Action: %s\n", strings.ToUpper(signal.Action))
-		fmt.Fprintf(&message, "🏪 Exchanges: %s\n", strings.Join(signal.Exchanges, ", "))
+		message.WriteString(f.Bold(fmt.Sprintf("%d. %s", i+1, signal.Symbol)) + "\n")
+		writeTextLine(&message, f, "💰 Profit: %.2f%%", signal.ProfitPotential.InexactFloat64())
+		writeTextLine(&message, f, "🎯 Confidence: %.1f%%", signal.Confidence.InexactFloat64())
+		writeTextLine(&message, f, "⚡ Action: %s", strings.ToUpper(signal.Action))
+		writeTextLine(&message, f, "🏪 Exchanges: %s", strings.Join(signal.Exchanges, ", "))
 
 		// Add metadata if available
 		if signal.Metadata != nil {
 			if buyPrice, ok := signal.Metadata["buy_price"]; ok {
-				fmt.Fprintf(&message, "📈 Buy Price: %v\n", buyPrice)
+				writeTextLine(&message, f, "📈 Buy Price: %v", buyPrice)
 			}
 			if sellPrice, ok := signal.Metadata["sell_price"]; ok {
-				fmt.Fprintf(&message, "📉 Sell Price: %v\n", sellPrice)
+				writeTextLine(&message, f, "📉 Sell Price: %v", sellPrice)
 			}
 		}
 
@@ -1636,20 +2027,20 @@ func (ns *NotificationService) formatAggregatedArbitrageMessage(signals []*Aggre
 	}
 
 	if len(signals) > maxSignals {
-		fmt.Fprintf(&message, "... and %d more opportunities\n\n", len(signals)-maxSignals)
+		writeTextLine(&message, f, "... and %d more opportunities\n", len(signals)-maxSignals)
 	}
 
-	message.WriteString("⏰ Generated: ")
-	message.WriteString(time.Now().Format("15:04:05 MST"))
-	message.WriteString("\n\n⚠️ *Trade at your own risk*")
+	message.WriteString(f.Text("⏰ Generated: " + ns.currentTime().Format("15:04:05 MST")))
+	message.WriteString("\n\n⚠️ " + f.Bold("Trade at your own risk"))
 
 	return message.String()
 }
 
 // formatAggregatedTechnicalMessage formats multiple technical analysis signals into a single message
 func (ns *NotificationService) formatAggregatedTechnicalMessage(signals []*AggregatedSignal) string {
+	f := ns.formatter()
 	if len(signals) == 0 {
-		return "📊 No technical analysis signals available"
+		return f.Text("📊 No technical analysis signals available")
 	}
 
 	// Sort signals by strength (highest first)
@@ -1658,7 +2049,7 @@ func (ns *NotificationService) formatAggregatedTechnicalMessage(signals []*Aggre
 	})
 
 	var message strings.Builder
-	message.WriteString("📊 *Aggregated Technical Analysis*\n\n")
+	message.WriteString("📊 " + f.Bold("Aggregated Technical Analysis") + "\n\n")
 
 	// Limit to top 5 signals to keep message manageable
 	maxSignals := len(signals)
@@ -1667,29 +2058,27 @@ func (ns *NotificationService) formatAggregatedTechnicalMessage(signals []*Aggre
 	}
 
 	for i, signal := range signals[:maxSignals] {
-		fmt.Fprintf(&message, "*%d. %s*\n", i+1, signal.Symbol)
-		fmt.Fprintf(&message, "📈 Signal: %s\n", strings.ToUpper(signal.Action))
-		fmt.Fprintf(&message, "💪 Strength: %s\n", signal.Strength)
-		fmt.Fprintf(&message, "🎯 Confidence: %.1f%%\n", signal.Confidence.InexactFloat64())
-		fmt.Fprintf(&message, "⚠️ Risk: %.2f%%\n", signal.RiskLevel.InexactFloat64())
+		message.WriteString(f.Bold(fmt.Sprintf("%d. %s", i+1, signal.Symbol)) + "\n")
+		writeTextLine(&message, f, "📈 Signal: %s", strings.ToUpper(signal.Action))
+		writeTextLine(&message, f, "💪 Strength: %s", signal.Strength)
+		writeTextLine(&message, f, "🎯 Confidence: %.1f%%", signal.Confidence.InexactFloat64())
+		writeTextLine(&message, f, "⚠️ Risk: %.2f%%", signal.RiskLevel.InexactFloat64())
 
 		// Add indicators if available
 		if len(signal.Indicators) > 0 {
-			message.WriteString("📊 Indicators: ")
-			message.WriteString(strings.Join(signal.Indicators, ", "))
-			message.WriteString("\n")
+			writeTextLine(&message, f, "📊 Indicators: %s", strings.Join(signal.Indicators, ", "))
 		}
 
 		// Add metadata if available
 		if signal.Metadata != nil {
 			if entryPrice, ok := signal.Metadata["entry_price"]; ok {
-				fmt.Fprintf(&message, "🎯 Entry: %v\n", entryPrice)
+				writeTextLine(&message, f, "🎯 Entry: %v", entryPrice)
 			}
 			if stopLoss, ok := signal.Metadata["stop_loss"]; ok {
-				fmt.Fprintf(&message, "🛑 Stop Loss: %v\n", stopLoss)
+				writeTextLine(&message, f, "🛑 Stop Loss: %v", stopLoss)
 			}
 			if target, ok := signal.Metadata["target"]; ok {
-				fmt.Fprintf(&message, "🎯 Target: %v\n", target)
+				writeTextLine(&message, f, "🎯 Target: %v", target)
 			}
 		}
 
@@ -1697,12 +2086,11 @@ func (ns *NotificationService) formatAggregatedTechnicalMessage(signals []*Aggre
 	}
 
 	if len(signals) > maxSignals {
-		fmt.Fprintf(&message, "... and %d more signals\n\n", len(signals)-maxSignals)
+		writeTextLine(&message, f, "... and %d more signals\n", len(signals)-maxSignals)
 	}
 
-	message.WriteString("⏰ Generated: ")
-	message.WriteString(time.Now().Format("15:04:05 MST"))
-	message.WriteString("\n\n⚠️ *Trade at your own risk*")
+	message.WriteString(f.Text("⏰ Generated: " + ns.currentTime().Format("15:04:05 MST")))
+	message.WriteString("\n\n⚠️ " + f.Bold("Trade at your own risk"))
 
 	return message.String()
 }
@@ -1745,7 +2133,7 @@ func (ns *NotificationService) NotifyTechnicalSignals(ctx context.Context, signa
 // sendTechnicalAlert sends a formatted technical analysis alert to a specific user
 func (ns *NotificationService) sendTechnicalAlert(ctx context.Context, user userModels.User, signals []TechnicalSignalNotification) error {
 	// Check rate limit before sending
-	allowed, err := ns.checkRateLimit(ctx, user.ID)
+	allowed, err := ns.admitNotification(ctx, user.ID, PriorityNotificationNormal)
 	if err != nil {
 		ns.logger.Error("Rate limit check failed", "user_id", user.ID, "error", err)
 	}
@@ -1838,7 +2226,8 @@ func (ns *NotificationService) ProcessDeadLetterQueue(ctx context.Context, batch
 		}
 
 		// Attempt to send the message
-		result := ns.sendTelegramMessageWithResult(ctx, chatID, entry.MessageContent)
+		result := ns.sendTelegramMessageWithPriority(ctx, chatID, entry.MessageContent, nil, ParseNotificationPriority(entry.Priority))
+		ns.recordDeliveryResult(ctx, entry.UserID, chatID, result)
 
 		if result.OK {
 			// Success - update the dead letter entry
@@ -1853,13 +2242,6 @@ func (ns *NotificationService) ProcessDeadLetterQueue(ctx context.Context, batch
 				ns.logger.Error("Failed to update dead letter error", "id", entry.ID, "error", err)
 			}
 
-			// Handle blocked users
-			if result.ErrorCode == TelegramErrorUserBlocked || result.ErrorCode == TelegramErrorChatNotFound {
-				if err := ns.handleBlockedUser(ctx, entry.UserID, string(result.ErrorCode)); err != nil {
-					ns.logger.Error("Failed to mark user as blocked", "user_id", entry.UserID, "error", err)
-				}
-			}
-
 			failCount++
 			ns.logger.Warn("Failed to resend dead letter message",
 				"id", entry.ID,
@@ -1878,6 +2260,67 @@ func (ns *NotificationService) ProcessDeadLetterQueue(ctx context.Context, batch
 	return successCount, failCount, nil
 }
 
+// ReplayDeadLetters re-enqueues dead-lettered messages onto the delivery queue.
+// Without a delivery queue it falls back to ProcessDeadLetterQueue.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	limit: Maximum number of entries to replay.
+//	includeFailed: Also replay entries that exhausted their retries.
+//
+// Returns:
+//
+//	int: Number of entries replayed.
+//	error: Error if the operation fails.
+func (ns *NotificationService) ReplayDeadLetters(ctx context.Context, limit int, includeFailed bool) (int, error) {
+	if ns.deliveryQueue == nil {
+		success, _, err := ns.ProcessDeadLetterQueue(ctx, limit)
+		return success, err
+	}
+
+	replayed := 0
+	if ns.deadLetterService != nil {
+		entries, err := ns.deadLetterService.GetReplayCandidates(ctx, includeFailed, limit)
+		if err != nil {
+			return 0, err
+		}
+		for _, entry := range entries {
+			chatID, parseErr := strconv.ParseInt(entry.ChatID, 10, 64)
+			if parseErr != nil {
+				_ = ns.deadLetterService.UpdateDeadLetter(ctx, entry.ID, false, "INVALID_CHAT_ID", "Invalid chat ID format")
+				continue
+			}
+			if err := ns.deadLetterService.MarkAsRetrying(ctx, entry.ID); err != nil {
+				ns.logger.Error("Failed to mark entry as retrying", "id", entry.ID, "error", err)
+				continue
+			}
+			if _, err := ns.deliveryQueue.Enqueue(ctx, QueuedNotification{
+				ChatID:       chatID,
+				UserID:       entry.UserID,
+				MessageType:  entry.MessageType,
+				Text:         entry.MessageContent,
+				DeadLetterID: entry.ID,
+				Priority:     ParseNotificationPriority(entry.Priority),
+			}); err != nil {
+				return replayed, err
+			}
+			replayed++
+		}
+	}
+
+	if replayed < limit {
+		n, err := ns.deliveryQueue.ReplayQueueDeadLetters(ctx, limit-replayed)
+		replayed += n
+		if err != nil {
+			return replayed, err
+		}
+	}
+
+	ns.logger.Info("Replayed dead letter notifications", "count", replayed)
+	return replayed, nil
+}
+
 // GetDeadLetterStats returns statistics about the dead letter queue
 //
 // Parameters:
@@ -1932,7 +2375,7 @@ func (ns *NotificationService) NotifyQuestProgress(ctx context.Context, chatID i
 
 	message := ns.formatQuestProgressMessage(progress)
 
-	if err := ns.sendTelegramMessage(spanCtx, chatID, message); err != nil {
+	if err := ns.sendPrioritizedMessage(spanCtx, chatID, message, nil, PriorityNotificationLow); err != nil {
 		ns.logger.Error("Failed to send quest progress notification",
 			"chat_id", chatID,
 			"quest_id", progress.QuestID,
@@ -1979,7 +2422,7 @@ func (ns *NotificationService) formatQuestProgressMessage(progress QuestProgress
 	progressBar := ns.generateProgressBar(progress.Percent, 10)
 	lines = append(lines, "", progressBar)
 
-	return fmt.Sprintf("```\n%s\n```", joinNotificationLines(lines))
+	return ns.formatter().Pre(joinNotificationLines(lines))
 }
 
 type RiskEventNotification struct {
@@ -1999,7 +2442,18 @@ func (ns *NotificationService) NotifyRiskEvent(ctx context.Context, chatID int64
 
 	message := ns.formatRiskEventMessage(event)
 
-	if err := ns.sendTelegramMessage(spanCtx, chatID, message); err != nil {
+	// Critical events are escalated by phone unless acknowledged in time;
+	// the timer also runs when Telegram delivery fails
+	priority := RiskSeverityPriority(event.Severity)
+	var markup *InlineKeyboardMarkup
+	if priority == PriorityNotificationEmergency && ns.escalation != nil {
+		alert := ns.escalation.Track(chatID, event.EventType, event.Message)
+		if ns.actionService != nil {
+			markup = ns.actionService.BuildAcknowledgeKeyboard(chatID, alert.ID)
+		}
+	}
+
+	if err := ns.sendPrioritizedMessage(spanCtx, chatID, message, markup, priority); err != nil {
 		ns.logger.Error("Failed to send risk event notification",
 			"chat_id", chatID,
 			"event_type", event.EventType,
@@ -2043,14 +2497,19 @@ func (ns *NotificationService) formatRiskEventMessage(event RiskEventNotificatio
 
 	if len(event.Details) > 0 {
 		lines = append(lines, "", "**Details:**")
-		for key, value := range event.Details {
-			lines = append(lines, fmt.Sprintf("• %s: %s", key, value))
+		keys := make([]string, 0, len(event.Details))
+		for key := range event.Details {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			lines = append(lines, fmt.Sprintf("• %s: %s", key, event.Details[key]))
 		}
 	}
 
-	lines = append(lines, "", fmt.Sprintf("_Time: %s_", time.Now().UTC().Format(time.RFC3339)))
+	lines = append(lines, "", fmt.Sprintf("_Time: %s_", ns.currentTime().UTC().Format(time.RFC3339)))
 
-	return fmt.Sprintf("```\n%s\n```", joinNotificationLines(lines))
+	return ns.formatter().Pre(joinNotificationLines(lines))
 }
 
 type FundMilestoneNotification struct {
@@ -2070,7 +2529,7 @@ func (ns *NotificationService) NotifyFundMilestone(ctx context.Context, chatID i
 
 	message := ns.formatFundMilestoneMessage(milestone)
 
-	if err := ns.sendTelegramMessage(spanCtx, chatID, message); err != nil {
+	if err := ns.sendPrioritizedMessage(spanCtx, chatID, message, nil, PriorityNotificationLow); err != nil {
 		ns.logger.Error("Failed to send fund milestone notification",
 			"chat_id", chatID,
 			"milestone_type", milestone.MilestoneType,
@@ -2102,7 +2561,91 @@ func (ns *NotificationService) formatFundMilestoneMessage(milestone FundMileston
 	progressBar := ns.generateProgressBar(milestone.PercentReached, 20)
 	lines = append(lines, "", progressBar)
 
-	return fmt.Sprintf("```\n%s\n```", joinNotificationLines(lines))
+	return ns.formatter().Pre(joinNotificationLines(lines))
+}
+
+// NotifyGoLive sends a scheduled go-live countdown or outcome. While the
+// go-live is pending the message carries a one-tap cancel button.
+func (ns *NotificationService) NotifyGoLive(ctx context.Context, chatID int64, notification GoLiveNotification) error {
+	spanCtx, span := observability.StartSpanWithTags(ctx, observability.SpanOpNotification, "NotificationService.NotifyGoLive", map[string]string{
+		"chat_id": fmt.Sprintf("%d", chatID),
+		"stage":   string(notification.Stage),
+	})
+	defer observability.FinishSpan(span, nil)
+
+	message := ns.formatGoLiveMessage(notification)
+
+	var markup *InlineKeyboardMarkup
+	pending := notification.Stage == GoLiveStageScheduled || notification.Stage == GoLiveStageReminder
+	if pending && ns.actionService != nil {
+		markup = ns.actionService.BuildGoLiveCancelKeyboard(chatID, notification.ScheduleID)
+	}
+
+	if err := ns.sendPrioritizedMessage(spanCtx, chatID, message, markup, PriorityNotificationHigh); err != nil {
+		ns.logger.Error("Failed to send go-live notification",
+			"chat_id", chatID,
+			"stage", notification.Stage,
+			"error", err,
+		)
+		return err
+	}
+
+	ns.logger.Info("Sent go-live notification",
+		"chat_id", chatID,
+		"stage", notification.Stage,
+		"schedule_id", notification.ScheduleID,
+	)
+
+	return nil
+}
+
+func (ns *NotificationService) formatGoLiveMessage(notification GoLiveNotification) string {
+	activateAt := notification.ActivateAt.UTC().Format("2006-01-02 15:04 UTC")
+	var lines []string
+	switch notification.Stage {
+	case GoLiveStageScheduled:
+		lines = []string{
+			"⏳ **Live Trading Scheduled**",
+			"",
+			fmt.Sprintf("Live mode activates at %s (in %s).", activateAt, formatGoLiveCountdown(notification.Remaining)),
+			fmt.Sprintf("Scheduled by: %s", notification.Operator),
+		}
+	case GoLiveStageReminder:
+		lines = []string{
+			"⏳ **Going Live Soon**",
+			"",
+			fmt.Sprintf("Live mode activates in %s, at %s.", formatGoLiveCountdown(notification.Remaining), activateAt),
+		}
+	case GoLiveStageActivated:
+		lines = []string{
+			"🟢 **Live Trading Active**",
+			"",
+			fmt.Sprintf("The go-live scheduled by %s for %s is now active. Orders are real.", notification.Operator, activateAt),
+		}
+	case GoLiveStageFailed:
+		lines = []string{
+			"❌ **Scheduled Go-Live Failed**",
+			"",
+			fmt.Sprintf("Live mode was not activated at %s: %s", activateAt, notification.Error),
+			"The account stays in paper mode.",
+		}
+	default:
+		lines = []string{
+			"🚫 **Scheduled Go-Live Cancelled**",
+			"",
+			fmt.Sprintf("The go-live for %s was cancelled by %s. The account stays in paper mode.", activateAt, notification.Operator),
+		}
+	}
+	return ns.formatter().Pre(joinNotificationLines(lines))
+}
+
+// formatGoLiveCountdown rounds the time left to minutes, or seconds in the
+// last minute.
+func formatGoLiveCountdown(remaining time.Duration) string {
+	if remaining < time.Minute {
+		return remaining.Round(time.Second).String()
+	}
+	return strings.TrimSuffix(remaining.Round(time.Minute).String(), "0s")
 }
 
 type AIReasoningNotification struct {
@@ -2122,7 +2665,7 @@ func (ns *NotificationService) NotifyAIReasoning(ctx context.Context, chatID int
 
 	message := ns.formatAIReasoningMessage(reasoning)
 
-	if err := ns.sendTelegramMessage(spanCtx, chatID, message); err != nil {
+	if err := ns.sendPrioritizedMessage(spanCtx, chatID, message, nil, PriorityNotificationLow); err != nil {
 		ns.logger.Error("Failed to send AI reasoning notification",
 			"chat_id", chatID,
 			"decision_type", reasoning.DecisionType,
@@ -2178,7 +2721,7 @@ func (ns *NotificationService) formatAIReasoningMessage(reasoning AIReasoningNot
 		lines = append(lines, "", fmt.Sprintf("**Recommended Action:** %s", reasoning.Action))
 	}
 
-	return fmt.Sprintf("```\n%s\n```", joinNotificationLines(lines))
+	return ns.formatter().Pre(joinNotificationLines(lines))
 }
 
 func (ns *NotificationService) generateProgressBar(percent, width int) string {
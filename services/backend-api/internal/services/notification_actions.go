@@ -0,0 +1,539 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+)
+
+// NotificationActionType identifies the inline button that was pressed.
+type NotificationActionType string
+
+const (
+	NotificationActionExecute NotificationActionType = "x"
+	NotificationActionSnooze  NotificationActionType = "s"
+	NotificationActionDetails NotificationActionType = "d"
+	NotificationActionConfirm NotificationActionType = "c"
+	NotificationActionCancel  NotificationActionType = "n"
+	// NotificationActionAcknowledge acknowledges a critical alert; its ID is
+	// the alert ID rather than an action record.
+	NotificationActionAcknowledge NotificationActionType = "a"
+	// NotificationActionCancelGoLive cancels a scheduled go-live; its ID is
+	// the schedule ID.
+	NotificationActionCancelGoLive NotificationActionType = "g"
+)
+
+const (
+	notificationActionPrefix    = "na"
+	notificationActionIDBytes   = 6
+	notificationActionSigLength = 16
+	defaultActionTTL            = 15 * time.Minute
+	defaultSnoozeDuration       = time.Hour
+)
+
+var (
+	// ErrInvalidActionSignature is returned when callback data was not issued by this backend
+	// or was pressed from a different chat than it was sent to.
+	ErrInvalidActionSignature = errors.New("invalid notification action signature")
+	// ErrNotificationActionExpired is returned when the action record is gone.
+	ErrNotificationActionExpired = errors.New("notification action expired")
+	// ErrNotificationActionUsed is returned when an execution was already confirmed.
+	ErrNotificationActionUsed = errors.New("notification action already executed")
+)
+
+// InlineKeyboardButton is a Telegram inline keyboard button.
+type InlineKeyboardButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+// InlineKeyboardMarkup is a Telegram inline keyboard attached to a message.
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+// NotificationActionOrderPlacer places orders for executed opportunities.
+// CCXTOrderExecutor satisfies this interface.
+type NotificationActionOrderPlacer interface {
+	PlaceOrder(ctx context.Context, exchange, symbol, side, orderType string, amount decimal.Decimal, price *decimal.Decimal) (string, error)
+}
+
+// CriticalAlertAcknowledger acknowledges critical alerts.
+// CriticalEscalationService satisfies this interface.
+type CriticalAlertAcknowledger interface {
+	Acknowledge(id, by string) (*CriticalAlert, error)
+}
+
+// GoLiveCanceller cancels scheduled go-lives.
+// TradingModeService satisfies this interface.
+type GoLiveCanceller interface {
+	CancelGoLive(ctx context.Context, id, operator string) (*GoLiveSchedule, error)
+}
+
+// NotificationActionModeProvider reports the account execution mode.
+// TradingModeService satisfies this interface.
+type NotificationActionModeProvider interface {
+	IsLive(ctx context.Context) bool
+	KillSwitchEngaged(ctx context.Context) bool
+}
+
+// NotificationActionConfig configures inline notification actions.
+type NotificationActionConfig struct {
+	// Secret signs callback payloads. Required.
+	Secret string
+	// LiveTrading requires an explicit confirmation step before orders are placed.
+	// It is ignored once a mode provider is set.
+	LiveTrading bool
+	// ExecuteNotional is the quote amount offered by the execute button.
+	ExecuteNotional decimal.Decimal
+	// ActionTTL is how long buttons stay valid.
+	ActionTTL time.Duration
+	// SnoozeDuration is how long a snoozed symbol stays muted.
+	SnoozeDuration time.Duration
+}
+
+// notificationActionRecord is the server-side state behind a set of buttons.
+type notificationActionRecord struct {
+	ID          string               `json:"id"`
+	ChatID      int64                `json:"chat_id"`
+	UserID      string               `json:"user_id"`
+	Opportunity ArbitrageOpportunity `json:"opportunity"`
+	Notional    decimal.Decimal      `json:"notional"`
+	CreatedAt   time.Time            `json:"created_at"`
+}
+
+// NotificationActionResponse is returned to the Telegram service after a callback.
+type NotificationActionResponse struct {
+	// Text is shown to the user as a reply.
+	Text string `json:"text"`
+	// ReplyMarkup is an optional follow-up keyboard (e.g. confirmation buttons).
+	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	// Toast is a short acknowledgement for answerCallbackQuery.
+	Toast string `json:"toast"`
+	// OrderIDs lists orders placed by an execution.
+	OrderIDs []string `json:"order_ids,omitempty"`
+}
+
+// NotificationActionService issues signed inline keyboards for opportunity
+// notifications and handles the resulting callbacks.
+type NotificationActionService struct {
+	redis     *redis.Client
+	config    NotificationActionConfig
+	executor  NotificationActionOrderPlacer
+	modes     NotificationActionModeProvider
+	lifecycle *OpportunityLifecycleService
+	acks      CriticalAlertAcknowledger
+	goLive    GoLiveCanceller
+	logger    *slog.Logger
+}
+
+// NewNotificationActionService creates a notification action service.
+//
+// Parameters:
+//
+//	client: Redis client used for action records and snoozes.
+//	config: Action configuration.
+//	executor: Order placer for live executions (may be nil in paper mode).
+//
+// Returns:
+//
+//	*NotificationActionService: Initialized service.
+func NewNotificationActionService(client *redis.Client, config NotificationActionConfig, executor NotificationActionOrderPlacer) *NotificationActionService {
+	if config.ExecuteNotional.LessThanOrEqual(decimal.Zero) {
+		config.ExecuteNotional = decimal.NewFromInt(100)
+	}
+	if config.ActionTTL <= 0 {
+		config.ActionTTL = defaultActionTTL
+	}
+	if config.SnoozeDuration <= 0 {
+		config.SnoozeDuration = defaultSnoozeDuration
+	}
+	return &NotificationActionService{
+		redis:    client,
+		config:   config,
+		executor: executor,
+		logger:   telemetry.Logger(),
+	}
+}
+
+// SetModeProvider makes live/paper decisions follow the trading mode service.
+func (s *NotificationActionService) SetModeProvider(modes NotificationActionModeProvider) {
+	s.modes = modes
+}
+
+// SetLifecycleService closes the persisted lifecycle of executed opportunities.
+func (s *NotificationActionService) SetLifecycleService(lifecycle *OpportunityLifecycleService) {
+	s.lifecycle = lifecycle
+}
+
+// SetAcknowledger handles the acknowledge button on critical alerts.
+func (s *NotificationActionService) SetAcknowledger(acks CriticalAlertAcknowledger) {
+	s.acks = acks
+}
+
+// SetGoLiveCanceller handles the cancel button on go-live countdowns.
+func (s *NotificationActionService) SetGoLiveCanceller(goLive GoLiveCanceller) {
+	s.goLive = goLive
+}
+
+// BuildGoLiveCancelKeyboard returns the signed cancel button for a go-live
+// countdown sent to a chat.
+func (s *NotificationActionService) BuildGoLiveCancelKeyboard(chatID int64, scheduleID string) *InlineKeyboardMarkup {
+	return &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{{
+		{Text: "🛑 Cancel go-live", CallbackData: s.callbackData(scheduleID, NotificationActionCancelGoLive, chatID)},
+	}}}
+}
+
+// BuildAcknowledgeKeyboard returns the signed acknowledge button for a
+// critical alert sent to a chat.
+func (s *NotificationActionService) BuildAcknowledgeKeyboard(chatID int64, alertID string) *InlineKeyboardMarkup {
+	return &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{{
+		{Text: "✅ Acknowledge", CallbackData: s.callbackData(alertID, NotificationActionAcknowledge, chatID)},
+	}}}
+}
+
+// markExecuted records an execution in the opportunity's lifecycle. Fills are
+// not reported back, so the capture is the spread still observed.
+func (s *NotificationActionService) markExecuted(ctx context.Context, opp ArbitrageOpportunity) {
+	if s.lifecycle == nil {
+		return
+	}
+	if err := s.lifecycle.MarkExecuted(ctx, opp, nil); err != nil {
+		s.logger.Warn("Failed to mark opportunity executed", "symbol", opp.Symbol, "error", err)
+	}
+}
+
+func (s *NotificationActionService) isLive(ctx context.Context) bool {
+	if s.modes != nil {
+		return s.modes.IsLive(ctx)
+	}
+	return s.config.LiveTrading
+}
+
+func notificationActionKey(id string) string {
+	return fmt.Sprintf("notification:action:%s", id)
+}
+
+func notificationActionExecutedKey(id string) string {
+	return fmt.Sprintf("notification:action:%s:executed", id)
+}
+
+func notificationSnoozeKey(chatID int64, symbol string) string {
+	return fmt.Sprintf("notification:snooze:%d:%s", chatID, strings.ToUpper(symbol))
+}
+
+// sign returns a truncated HMAC of the action, bound to the chat it was sent to.
+func (s *NotificationActionService) sign(id string, action NotificationActionType, chatID int64) string {
+	mac := hmac.New(sha256.New, []byte(s.config.Secret))
+	_, _ = fmt.Fprintf(mac, "%s|%s|%d", id, action, chatID)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))[:notificationActionSigLength]
+}
+
+// callbackData encodes an action as "na:<id>:<action>:<sig>", well under Telegram's 64-byte limit.
+func (s *NotificationActionService) callbackData(id string, action NotificationActionType, chatID int64) string {
+	return strings.Join([]string{notificationActionPrefix, id, string(action), s.sign(id, action, chatID)}, ":")
+}
+
+// parseCallbackData verifies and decodes callback data.
+func (s *NotificationActionService) parseCallbackData(chatID int64, data string) (string, NotificationActionType, error) {
+	parts := strings.Split(data, ":")
+	if len(parts) != 4 || parts[0] != notificationActionPrefix {
+		return "", "", ErrInvalidActionSignature
+	}
+	id, action, sig := parts[1], NotificationActionType(parts[2]), parts[3]
+	if !hmac.Equal([]byte(sig), []byte(s.sign(id, action, chatID))) {
+		return "", "", ErrInvalidActionSignature
+	}
+	return id, action, nil
+}
+
+// BuildOpportunityKeyboard stores an action record and returns the signed keyboard for it.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	chatID: Chat the keyboard will be sent to.
+//	userID: Owner of the chat.
+//	opp: Opportunity the buttons act on.
+//
+// Returns:
+//
+//	*InlineKeyboardMarkup: Keyboard with execute, snooze and details buttons.
+//	error: Error if the record cannot be stored.
+func (s *NotificationActionService) BuildOpportunityKeyboard(ctx context.Context, chatID int64, userID string, opp ArbitrageOpportunity) (*InlineKeyboardMarkup, error) {
+	idBytes := make([]byte, notificationActionIDBytes)
+	if _, err := rand.Read(idBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate action id: %w", err)
+	}
+	record := notificationActionRecord{
+		ID:          hex.EncodeToString(idBytes),
+		ChatID:      chatID,
+		UserID:      userID,
+		Opportunity: opp,
+		Notional:    s.config.ExecuteNotional,
+		CreatedAt:   time.Now().UTC(),
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal action record: %w", err)
+	}
+	if err := s.redis.Set(ctx, notificationActionKey(record.ID), data, s.config.ActionTTL).Err(); err != nil {
+		return nil, fmt.Errorf("failed to store action record: %w", err)
+	}
+
+	return &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{
+		{
+			{Text: fmt.Sprintf("⚡ Execute %s USDT", s.config.ExecuteNotional.String()), CallbackData: s.callbackData(record.ID, NotificationActionExecute, chatID)},
+		},
+		{
+			{Text: fmt.Sprintf("🔕 Snooze %s %s", opp.Symbol, formatSnoozeDuration(s.config.SnoozeDuration)), CallbackData: s.callbackData(record.ID, NotificationActionSnooze, chatID)},
+			{Text: "ℹ️ Details", CallbackData: s.callbackData(record.ID, NotificationActionDetails, chatID)},
+		},
+	}}, nil
+}
+
+// HandleCallback verifies and executes a button press.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	chatID: Chat the button was pressed in.
+//	data: Raw callback data.
+//
+// Returns:
+//
+//	*NotificationActionResponse: Reply for the Telegram service.
+//	error: ErrInvalidActionSignature, ErrNotificationActionExpired, ErrNotificationActionUsed, or a processing error.
+func (s *NotificationActionService) HandleCallback(ctx context.Context, chatID int64, data string) (*NotificationActionResponse, error) {
+	id, action, err := s.parseCallbackData(chatID, data)
+	if err != nil {
+		s.logger.Warn("Rejected notification callback", "chat_id", chatID, "error", err)
+		return nil, err
+	}
+	if action == NotificationActionAcknowledge {
+		return s.acknowledge(chatID, id)
+	}
+	if action == NotificationActionCancelGoLive {
+		return s.cancelGoLive(ctx, chatID, id)
+	}
+
+	record, err := s.loadRecord(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if record.ChatID != chatID {
+		return nil, ErrInvalidActionSignature
+	}
+
+	switch action {
+	case NotificationActionDetails:
+		return &NotificationActionResponse{Text: formatOpportunityDetails(record), Toast: "Details"}, nil
+	case NotificationActionSnooze:
+		return s.snooze(ctx, record)
+	case NotificationActionExecute:
+		if s.isLive(ctx) {
+			return &NotificationActionResponse{
+				Text: fmt.Sprintf("⚠️ *Live trading*\n\nBuy %s USDT of %s on %s and sell on %s?\nThis places real orders.",
+					record.Notional.String(), record.Opportunity.Symbol, record.Opportunity.BuyExchange, record.Opportunity.SellExchange),
+				ReplyMarkup: &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{{
+					{Text: "✅ Confirm", CallbackData: s.callbackData(record.ID, NotificationActionConfirm, chatID)},
+					{Text: "❌ Cancel", CallbackData: s.callbackData(record.ID, NotificationActionCancel, chatID)},
+				}}},
+				Toast: "Confirmation required",
+			}, nil
+		}
+		return s.execute(ctx, record)
+	case NotificationActionConfirm:
+		return s.execute(ctx, record)
+	case NotificationActionCancel:
+		return &NotificationActionResponse{Text: "Execution cancelled.", Toast: "Cancelled"}, nil
+	default:
+		return nil, ErrInvalidActionSignature
+	}
+}
+
+// acknowledge stops the escalation of the critical alert behind a button.
+func (s *NotificationActionService) acknowledge(chatID int64, alertID string) (*NotificationActionResponse, error) {
+	if s.acks == nil {
+		return nil, ErrNotificationActionExpired
+	}
+	alert, err := s.acks.Acknowledge(alertID, fmt.Sprintf("telegram:%d", chatID))
+	if errors.Is(err, ErrCriticalAlertNotFound) {
+		return nil, ErrNotificationActionExpired
+	}
+	if err != nil {
+		return nil, err
+	}
+	text := "✅ Alert acknowledged; it will not be escalated."
+	if alert.EscalatedAt != nil {
+		text = "✅ Alert acknowledged. It was already escalated by phone."
+	}
+	return &NotificationActionResponse{Text: text, Toast: "Acknowledged"}, nil
+}
+
+// cancelGoLive cancels the scheduled go-live behind a countdown button.
+func (s *NotificationActionService) cancelGoLive(ctx context.Context, chatID int64, scheduleID string) (*NotificationActionResponse, error) {
+	if s.goLive == nil {
+		return nil, ErrNotificationActionExpired
+	}
+	_, err := s.goLive.CancelGoLive(ctx, scheduleID, fmt.Sprintf("telegram:%d", chatID))
+	if errors.Is(err, ErrGoLiveNotScheduled) {
+		return &NotificationActionResponse{Text: "This go-live is no longer scheduled.", Toast: "Not scheduled"}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &NotificationActionResponse{Text: "🛑 Go-live cancelled. The account stays in paper mode.", Toast: "Go-live cancelled"}, nil
+}
+
+// IsSymbolSnoozed reports whether a chat snoozed alerts for a symbol.
+func (s *NotificationActionService) IsSymbolSnoozed(ctx context.Context, chatID int64, symbol string) bool {
+	n, err := s.redis.Exists(ctx, notificationSnoozeKey(chatID, symbol)).Result()
+	return err == nil && n > 0
+}
+
+func (s *NotificationActionService) loadRecord(ctx context.Context, id string) (*notificationActionRecord, error) {
+	data, err := s.redis.Get(ctx, notificationActionKey(id)).Bytes()
+	if err == redis.Nil {
+		return nil, ErrNotificationActionExpired
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load action record: %w", err)
+	}
+	var record notificationActionRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to decode action record: %w", err)
+	}
+	return &record, nil
+}
+
+func (s *NotificationActionService) snooze(ctx context.Context, record *notificationActionRecord) (*NotificationActionResponse, error) {
+	symbol := record.Opportunity.Symbol
+	if err := s.redis.Set(ctx, notificationSnoozeKey(record.ChatID, symbol), "1", s.config.SnoozeDuration).Err(); err != nil {
+		return nil, fmt.Errorf("failed to snooze symbol: %w", err)
+	}
+	duration := formatSnoozeDuration(s.config.SnoozeDuration)
+	return &NotificationActionResponse{
+		Text:  fmt.Sprintf("🔕 %s alerts snoozed for %s.", symbol, duration),
+		Toast: fmt.Sprintf("Snoozed %s", duration),
+	}, nil
+}
+
+// execute places (live) or simulates (paper) the opportunity once per action record.
+func (s *NotificationActionService) execute(ctx context.Context, record *notificationActionRecord) (*NotificationActionResponse, error) {
+	if s.modes != nil && s.modes.KillSwitchEngaged(ctx) {
+		return &NotificationActionResponse{Text: "🛑 Trading is halted by the kill switch.", Toast: "Kill switch engaged"}, nil
+	}
+	live := s.isLive(ctx)
+
+	claimed, err := s.redis.SetNX(ctx, notificationActionExecutedKey(record.ID), time.Now().UTC().Format(time.RFC3339), s.config.ActionTTL).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim action: %w", err)
+	}
+	if !claimed {
+		return nil, ErrNotificationActionUsed
+	}
+
+	opp := record.Opportunity
+	if opp.BuyPrice <= 0 {
+		return nil, fmt.Errorf("opportunity has no buy price")
+	}
+	amount := record.Notional.Div(decimal.NewFromFloat(opp.BuyPrice)).Round(8)
+
+	if !live {
+		s.logger.Info("Paper execution from notification", "user_id", record.UserID, "symbol", opp.Symbol, "amount", amount.String())
+		s.markExecuted(ctx, opp)
+		return &NotificationActionResponse{
+			Text: fmt.Sprintf("📝 *Paper trade recorded*\n\nBuy %s %s on %s @ $%.4f\nSell on %s @ $%.4f",
+				amount.String(), opp.Symbol, opp.BuyExchange, opp.BuyPrice, opp.SellExchange, opp.SellPrice),
+			Toast: "Paper trade recorded",
+		}, nil
+	}
+
+	if s.executor == nil {
+		return nil, fmt.Errorf("order executor not configured")
+	}
+	buyID, err := s.executor.PlaceOrder(ctx, opp.BuyExchange, opp.Symbol, "buy", "market", amount, nil)
+	if err != nil {
+		if response := orderFailureResponse("Buy order failed", err); response != nil {
+			return response, nil
+		}
+		return nil, fmt.Errorf("failed to place buy order: %w", err)
+	}
+	orderIDs := []string{buyID}
+	if opp.SellExchange != "" && opp.SellExchange != opp.BuyExchange {
+		sellID, err := s.executor.PlaceOrder(ctx, opp.SellExchange, opp.Symbol, "sell", "market", amount, nil)
+		if err != nil {
+			if response := orderFailureResponse(fmt.Sprintf("Buy order %s placed but sell order failed", buyID), err); response != nil {
+				response.OrderIDs = orderIDs
+				return response, nil
+			}
+			return nil, fmt.Errorf("buy order %s placed but sell order failed: %w", buyID, err)
+		}
+		orderIDs = append(orderIDs, sellID)
+	}
+
+	s.logger.Info("Executed opportunity from notification", "user_id", record.UserID, "symbol", opp.Symbol, "orders", orderIDs)
+	s.markExecuted(ctx, opp)
+	return &NotificationActionResponse{
+		Text:     fmt.Sprintf("✅ *Orders placed*\n\n%s %s\nOrders: %s", amount.String(), opp.Symbol, strings.Join(orderIDs, ", ")),
+		Toast:    "Orders placed",
+		OrderIDs: orderIDs,
+	}, nil
+}
+
+// orderFailureResponse explains an order the exchange refused for a known
+// reason, with the remediation hint, instead of failing the callback.
+func orderFailureResponse(title string, err error) *NotificationActionResponse {
+	exchangeErr, ok := ccxt.AsExchangeError(err)
+	if !ok {
+		return nil
+	}
+	reason := exchangeErr.Kind.Label()
+	if exchangeErr.Code != "" {
+		reason += " (code " + exchangeErr.Code + ")"
+	}
+	return &NotificationActionResponse{
+		Text:  fmt.Sprintf("❌ *%s*\n\n%s: %s\n💡 %s", title, exchangeErr.Exchange, reason, exchangeErr.Hint),
+		Toast: title,
+	}
+}
+
+func formatOpportunityDetails(record *notificationActionRecord) string {
+	opp := record.Opportunity
+	lines := []string{
+		fmt.Sprintf("ℹ️ *%s*", opp.Symbol),
+		fmt.Sprintf("📈 Buy: %s @ $%.4f", opp.BuyExchange, opp.BuyPrice),
+		fmt.Sprintf("📉 Sell: %s @ $%.4f", opp.SellExchange, opp.SellPrice),
+		fmt.Sprintf("💰 Profit: %.2f%%", opp.ProfitPercent),
+	}
+	if opp.Volume > 0 {
+		lines = append(lines, fmt.Sprintf("📊 Volume: %.2f", opp.Volume))
+	}
+	if !opp.Timestamp.IsZero() {
+		lines = append(lines, fmt.Sprintf("🕒 Detected: %s", opp.Timestamp.UTC().Format("2006-01-02 15:04:05 UTC")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatSnoozeDuration(d time.Duration) string {
+	if d%time.Hour == 0 {
+		return strconv.Itoa(int(d/time.Hour)) + "h"
+	}
+	return strconv.Itoa(int(d/time.Minute)) + "m"
+}
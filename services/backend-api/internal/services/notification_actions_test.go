@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeOrderPlacer struct {
+	orders []string
+	// failures are returned instead of placing an order, by exchange
+	failures map[string]error
+}
+
+func (f *fakeOrderPlacer) PlaceOrder(ctx context.Context, exchange, symbol, side, orderType string, amount decimal.Decimal, price *decimal.Decimal) (string, error) {
+	if err := f.failures[exchange]; err != nil {
+		return "", err
+	}
+	f.orders = append(f.orders, exchange+":"+side+":"+amount.String())
+	return exchange + "-order", nil
+}
+
+func newTestActionService(t *testing.T, live bool, executor NotificationActionOrderPlacer) *NotificationActionService {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewNotificationActionService(client, NotificationActionConfig{Secret: "test-secret", LiveTrading: live}, executor)
+}
+
+var testActionOpportunity = ArbitrageOpportunity{
+	Symbol:        "BTC/USDT",
+	BuyExchange:   "binance",
+	SellExchange:  "kraken",
+	BuyPrice:      50000,
+	SellPrice:     50500,
+	ProfitPercent: 1,
+}
+
+func TestNotificationActionService_KeyboardLayout(t *testing.T) {
+	svc := newTestActionService(t, false, nil)
+
+	markup, err := svc.BuildOpportunityKeyboard(t.Context(), 42, "u1", testActionOpportunity)
+	require.NoError(t, err)
+	require.Len(t, markup.InlineKeyboard, 2)
+	assert.Equal(t, "⚡ Execute 100 USDT", markup.InlineKeyboard[0][0].Text)
+	assert.Equal(t, "🔕 Snooze BTC/USDT 1h", markup.InlineKeyboard[1][0].Text)
+	for _, row := range markup.InlineKeyboard {
+		for _, button := range row {
+			assert.LessOrEqual(t, len(button.CallbackData), 64)
+		}
+	}
+}
+
+func TestNotificationActionService_RejectsSpoofedCallbacks(t *testing.T) {
+	svc := newTestActionService(t, false, nil)
+	markup, err := svc.BuildOpportunityKeyboard(t.Context(), 42, "u1", testActionOpportunity)
+	require.NoError(t, err)
+	details := markup.InlineKeyboard[1][1].CallbackData
+
+	_, err = svc.HandleCallback(t.Context(), 43, details)
+	assert.ErrorIs(t, err, ErrInvalidActionSignature, "callback replayed from another chat")
+
+	tampered := details[:len(details)-1] + "A"
+	if tampered == details {
+		tampered = details[:len(details)-1] + "B"
+	}
+	_, err = svc.HandleCallback(t.Context(), 42, tampered)
+	assert.ErrorIs(t, err, ErrInvalidActionSignature)
+
+	_, err = svc.HandleCallback(t.Context(), 42, "na:garbage")
+	assert.ErrorIs(t, err, ErrInvalidActionSignature)
+
+	resp, err := svc.HandleCallback(t.Context(), 42, details)
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "BTC/USDT")
+}
+
+func TestNotificationActionService_ExpiredAction(t *testing.T) {
+	svc := newTestActionService(t, false, nil)
+
+	_, err := svc.HandleCallback(t.Context(), 42, svc.callbackData("deadbeef0000", NotificationActionDetails, 42))
+	assert.ErrorIs(t, err, ErrNotificationActionExpired)
+}
+
+func TestNotificationActionService_Snooze(t *testing.T) {
+	svc := newTestActionService(t, false, nil)
+	markup, err := svc.BuildOpportunityKeyboard(t.Context(), 42, "u1", testActionOpportunity)
+	require.NoError(t, err)
+
+	assert.False(t, svc.IsSymbolSnoozed(t.Context(), 42, "BTC/USDT"))
+	_, err = svc.HandleCallback(t.Context(), 42, markup.InlineKeyboard[1][0].CallbackData)
+	require.NoError(t, err)
+	assert.True(t, svc.IsSymbolSnoozed(t.Context(), 42, "btc/usdt"))
+	assert.False(t, svc.IsSymbolSnoozed(t.Context(), 7, "BTC/USDT"))
+}
+
+func TestNotificationActionService_PaperExecute(t *testing.T) {
+	executor := &fakeOrderPlacer{}
+	svc := newTestActionService(t, false, executor)
+	markup, err := svc.BuildOpportunityKeyboard(t.Context(), 42, "u1", testActionOpportunity)
+	require.NoError(t, err)
+	execute := markup.InlineKeyboard[0][0].CallbackData
+
+	resp, err := svc.HandleCallback(t.Context(), 42, execute)
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "Paper trade")
+	assert.Empty(t, executor.orders)
+
+	_, err = svc.HandleCallback(t.Context(), 42, execute)
+	assert.ErrorIs(t, err, ErrNotificationActionUsed)
+}
+
+func TestNotificationActionService_LiveExecuteRequiresConfirmation(t *testing.T) {
+	executor := &fakeOrderPlacer{}
+	svc := newTestActionService(t, true, executor)
+	markup, err := svc.BuildOpportunityKeyboard(t.Context(), 42, "u1", testActionOpportunity)
+	require.NoError(t, err)
+
+	resp, err := svc.HandleCallback(t.Context(), 42, markup.InlineKeyboard[0][0].CallbackData)
+	require.NoError(t, err)
+	require.NotNil(t, resp.ReplyMarkup)
+	assert.Empty(t, executor.orders, "no order before confirmation")
+
+	confirm := resp.ReplyMarkup.InlineKeyboard[0][0].CallbackData
+	resp, err = svc.HandleCallback(t.Context(), 42, confirm)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"binance:buy:0.002", "kraken:sell:0.002"}, executor.orders)
+	assert.Equal(t, []string{"binance-order", "kraken-order"}, resp.OrderIDs)
+
+	_, err = svc.HandleCallback(t.Context(), 42, confirm)
+	assert.ErrorIs(t, err, ErrNotificationActionUsed)
+	assert.Len(t, executor.orders, 2)
+}
+
+func TestNotificationActionService_ExplainsExchangeRejection(t *testing.T) {
+	executor := &fakeOrderPlacer{failures: map[string]error{
+		"kraken": fmt.Errorf("order placement failed: %w", ccxt.MapExchangeError("kraken", `kraken {"error":["EOrder:Insufficient funds"]}`)),
+	}}
+	svc := newTestActionService(t, true, executor)
+	markup, err := svc.BuildOpportunityKeyboard(t.Context(), 42, "u1", testActionOpportunity)
+	require.NoError(t, err)
+	resp, err := svc.HandleCallback(t.Context(), 42, markup.InlineKeyboard[0][0].CallbackData)
+	require.NoError(t, err)
+
+	resp, err = svc.HandleCallback(t.Context(), 42, resp.ReplyMarkup.InlineKeyboard[0][0].CallbackData)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"binance-order"}, resp.OrderIDs)
+	assert.Contains(t, resp.Text, "Buy order binance-order placed but sell order failed")
+	assert.Contains(t, resp.Text, "kraken: insufficient balance")
+	assert.Contains(t, resp.Text, "💡 top up the quote currency")
+
+	// Errors without a known cause still fail the callback
+	executor.failures["binance"] = errors.New("connection reset")
+	markup, err = svc.BuildOpportunityKeyboard(t.Context(), 42, "u1", testActionOpportunity)
+	require.NoError(t, err)
+	resp, err = svc.HandleCallback(t.Context(), 42, markup.InlineKeyboard[0][0].CallbackData)
+	require.NoError(t, err)
+	_, err = svc.HandleCallback(t.Context(), 42, resp.ReplyMarkup.InlineKeyboard[0][0].CallbackData)
+	assert.EqualError(t, err, "failed to place buy order: connection reset")
+}
+
+type fakeModeProvider struct {
+	live   bool
+	halted bool
+}
+
+func (f *fakeModeProvider) IsLive(ctx context.Context) bool            { return f.live }
+func (f *fakeModeProvider) KillSwitchEngaged(ctx context.Context) bool { return f.halted }
+
+func TestNotificationActionService_FollowsModeProvider(t *testing.T) {
+	executor := &fakeOrderPlacer{}
+	svc := newTestActionService(t, false, executor)
+	modes := &fakeModeProvider{live: true}
+	svc.SetModeProvider(modes)
+
+	markup, err := svc.BuildOpportunityKeyboard(t.Context(), 42, "u1", testActionOpportunity)
+	require.NoError(t, err)
+	resp, err := svc.HandleCallback(t.Context(), 42, markup.InlineKeyboard[0][0].CallbackData)
+	require.NoError(t, err)
+	require.NotNil(t, resp.ReplyMarkup, "live mode from provider requires confirmation")
+
+	modes.halted = true
+	resp, err = svc.HandleCallback(t.Context(), 42, resp.ReplyMarkup.InlineKeyboard[0][0].CallbackData)
+	require.NoError(t, err)
+	assert.Contains(t, resp.Text, "kill switch")
+	assert.Empty(t, executor.orders)
+}
+
+func TestNotificationActionService_CancelGoLive(t *testing.T) {
+	modes, _ := newTestTradingModeService(t, TradingModeConfig{Operators: []string{"op-1", "op-2"}})
+	pending, err := modes.ScheduleGoLive(t.Context(), time.Date(2026, 1, 1, 14, 30, 0, 0, time.UTC), "op-1")
+	require.NoError(t, err)
+	_, err = modes.Confirm(t.Context(), pending.ID, "op-2", "")
+	require.NoError(t, err)
+
+	svc := newTestActionService(t, false, nil)
+	svc.SetGoLiveCanceller(modes)
+	markup := svc.BuildGoLiveCancelKeyboard(42, pending.ID)
+	button := markup.InlineKeyboard[0][0]
+	assert.LessOrEqual(t, len(button.CallbackData), 64)
+
+	_, err = svc.HandleCallback(t.Context(), 43, button.CallbackData)
+	assert.ErrorIs(t, err, ErrInvalidActionSignature, "the button only works in the chat it was sent to")
+
+	resp, err := svc.HandleCallback(t.Context(), 42, button.CallbackData)
+	require.NoError(t, err)
+	assert.Equal(t, "Go-live cancelled", resp.Toast)
+	schedule, err := modes.GetGoLiveSchedule(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, GoLiveStatusCancelled, schedule.Status)
+	assert.Equal(t, "telegram:42", schedule.CancelledBy)
+
+	resp, err = svc.HandleCallback(t.Context(), 42, button.CallbackData)
+	require.NoError(t, err)
+	assert.Equal(t, "Not scheduled", resp.Toast)
+}
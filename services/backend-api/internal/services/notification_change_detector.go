@@ -0,0 +1,201 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/cache"
+)
+
+// NotificationChangeConfig configures when a repeated alert counts as new.
+type NotificationChangeConfig struct {
+	// SpreadDelta is how many percentage points the best spread or profit
+	// potential of a symbol must move before it is alerted again.
+	SpreadDelta float64
+	// ConfidenceDelta is how much a signal's confidence (0-1) must move
+	// before it is alerted again.
+	ConfidenceDelta float64
+	// RenotifyAfter re-alerts an unchanged symbol once this long has passed
+	// since its last alert.
+	RenotifyAfter time.Duration
+	// MaxEntries caps the alert snapshots kept in memory.
+	MaxEntries int
+}
+
+// notificationSnapshot is what was last alerted for a symbol in a chat.
+type notificationSnapshot struct {
+	route      string
+	spread     float64
+	confidence float64
+	alertedAt  time.Time
+}
+
+// NotificationChangeDetector suppresses alerts for symbols whose best spread
+// or signal has not materially changed since they were last alerted to the
+// same chat. Callers filter before sending and record after a successful
+// send, so a failed delivery is retried on the next cycle.
+type NotificationChangeDetector struct {
+	config     NotificationChangeConfig
+	snapshots  *cache.Bounded[string, notificationSnapshot]
+	now        func() time.Time
+	suppressed atomic.Int64
+}
+
+// NewNotificationChangeDetector creates a notification change detector.
+//
+// Parameters:
+//
+//	config: Delta thresholds; zero values use a 0.1 point spread delta, a
+//	0.05 confidence delta, re-alerting after an hour and 10000 snapshots.
+//
+// Returns:
+//
+//	*NotificationChangeDetector: Initialized detector.
+func NewNotificationChangeDetector(config NotificationChangeConfig) *NotificationChangeDetector {
+	if config.SpreadDelta <= 0 {
+		config.SpreadDelta = 0.1
+	}
+	if config.ConfidenceDelta <= 0 {
+		config.ConfidenceDelta = 0.05
+	}
+	if config.RenotifyAfter <= 0 {
+		config.RenotifyAfter = time.Hour
+	}
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = 10000
+	}
+	return &NotificationChangeDetector{
+		config: config,
+		snapshots: cache.NewBounded[string, notificationSnapshot](cache.BoundedConfig{
+			Name:       "notifications.change_detector",
+			MaxEntries: config.MaxEntries,
+			TTL:        config.RenotifyAfter,
+		}),
+		now: time.Now,
+	}
+}
+
+// Suppressed returns how many symbols have been left out of alerts as
+// unchanged.
+func (d *NotificationChangeDetector) Suppressed() int64 {
+	return d.suppressed.Load()
+}
+
+// FilterOpportunities drops the opportunities of symbols whose best
+// opportunity matches what was last alerted to the chat: same buy and sell
+// exchanges and a profit within SpreadDelta.
+//
+// Parameters:
+//
+//	chatID: Telegram chat the alert is for.
+//	opportunities: Opportunities about to be alerted.
+//
+// Returns:
+//
+//	[]ArbitrageOpportunity: Opportunities of changed symbols, in order.
+func (d *NotificationChangeDetector) FilterOpportunities(chatID int64, opportunities []ArbitrageOpportunity) []ArbitrageOpportunity {
+	best := bestOpportunities(opportunities)
+	changed := make(map[string]bool, len(best))
+	for symbol, opp := range best {
+		changed[symbol] = d.changed(opportunityChangeKey(chatID, opp), opportunitySnapshot(opp))
+		if !changed[symbol] {
+			d.suppressed.Add(1)
+		}
+	}
+
+	filtered := make([]ArbitrageOpportunity, 0, len(opportunities))
+	for _, opp := range opportunities {
+		if changed[opp.Symbol] {
+			filtered = append(filtered, opp)
+		}
+	}
+	return filtered
+}
+
+// RecordOpportunities remembers the best opportunity of each symbol as
+// alerted to the chat.
+func (d *NotificationChangeDetector) RecordOpportunities(chatID int64, opportunities []ArbitrageOpportunity) {
+	for _, opp := range bestOpportunities(opportunities) {
+		snapshot := opportunitySnapshot(opp)
+		snapshot.alertedAt = d.now()
+		d.snapshots.Set(opportunityChangeKey(chatID, opp), snapshot)
+	}
+}
+
+// FilterSignals drops signals whose action is unchanged since they were last
+// alerted to the chat and whose confidence and profit potential moved less
+// than ConfidenceDelta and SpreadDelta.
+//
+// Parameters:
+//
+//	chatID: Telegram chat the alert is for.
+//	kind: Alert the signals belong to, e.g. "aggregated_arbitrage".
+//	signals: Signals about to be alerted.
+//
+// Returns:
+//
+//	[]*AggregatedSignal: Changed signals, in order.
+func (d *NotificationChangeDetector) FilterSignals(chatID int64, kind string, signals []*AggregatedSignal) []*AggregatedSignal {
+	filtered := make([]*AggregatedSignal, 0, len(signals))
+	for _, signal := range signals {
+		if d.changed(signalChangeKey(chatID, kind, signal), signalSnapshot(signal)) {
+			filtered = append(filtered, signal)
+		} else {
+			d.suppressed.Add(1)
+		}
+	}
+	return filtered
+}
+
+// RecordSignals remembers the signals as alerted to the chat.
+func (d *NotificationChangeDetector) RecordSignals(chatID int64, kind string, signals []*AggregatedSignal) {
+	for _, signal := range signals {
+		snapshot := signalSnapshot(signal)
+		snapshot.alertedAt = d.now()
+		d.snapshots.Set(signalChangeKey(chatID, kind, signal), snapshot)
+	}
+}
+
+// changed compares current with the last alerted snapshot under key.
+func (d *NotificationChangeDetector) changed(key string, current notificationSnapshot) bool {
+	last, ok := d.snapshots.Get(key)
+	if !ok || d.now().Sub(last.alertedAt) >= d.config.RenotifyAfter {
+		return true
+	}
+	return current.route != last.route ||
+		math.Abs(current.spread-last.spread) >= d.config.SpreadDelta ||
+		math.Abs(current.confidence-last.confidence) >= d.config.ConfidenceDelta
+}
+
+// bestOpportunities returns the most profitable opportunity per symbol.
+func bestOpportunities(opportunities []ArbitrageOpportunity) map[string]ArbitrageOpportunity {
+	best := make(map[string]ArbitrageOpportunity, len(opportunities))
+	for _, opp := range opportunities {
+		if current, ok := best[opp.Symbol]; !ok || opp.ProfitPercent > current.ProfitPercent {
+			best[opp.Symbol] = opp
+		}
+	}
+	return best
+}
+
+func opportunityChangeKey(chatID int64, opp ArbitrageOpportunity) string {
+	return fmt.Sprintf("%d:opportunity:%s:%s", chatID, opp.OpportunityType, opp.Symbol)
+}
+
+func opportunitySnapshot(opp ArbitrageOpportunity) notificationSnapshot {
+	return notificationSnapshot{route: opp.BuyExchange + ">" + opp.SellExchange, spread: opp.ProfitPercent}
+}
+
+func signalChangeKey(chatID int64, kind string, signal *AggregatedSignal) string {
+	return fmt.Sprintf("%d:%s:%s:%s", chatID, kind, signal.SignalType, signal.Symbol)
+}
+
+func signalSnapshot(signal *AggregatedSignal) notificationSnapshot {
+	return notificationSnapshot{
+		route:      signal.Action,
+		spread:     signal.ProfitPotential.InexactFloat64(),
+		confidence: signal.Confidence.InexactFloat64(),
+	}
+}
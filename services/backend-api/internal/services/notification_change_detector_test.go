@@ -0,0 +1,115 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfndi/neuratrade/internal/database"
+	userModels "github.com/irfndi/neuratrade/internal/models"
+)
+
+func TestNotificationChangeDetector_Opportunities(t *testing.T) {
+	detector := NewNotificationChangeDetector(NotificationChangeConfig{SpreadDelta: 0.2, RenotifyAfter: time.Hour})
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	detector.now = func() time.Time { return now }
+
+	opportunities := []ArbitrageOpportunity{
+		{Symbol: "BTC/USDT", BuyExchange: "binance", SellExchange: "kraken", ProfitPercent: 1.0, OpportunityType: "arbitrage"},
+		{Symbol: "BTC/USDT", BuyExchange: "okx", SellExchange: "kraken", ProfitPercent: 0.6, OpportunityType: "arbitrage"},
+		{Symbol: "ETH/USDT", BuyExchange: "binance", SellExchange: "okx", ProfitPercent: 0.8, OpportunityType: "arbitrage"},
+	}
+	require.Len(t, detector.FilterOpportunities(42, opportunities), 3, "nothing alerted yet")
+	detector.RecordOpportunities(42, opportunities)
+
+	// Best spread within the delta on the same route is suppressed
+	opportunities[0].ProfitPercent = 1.1
+	opportunities[2].ProfitPercent = 1.1
+	filtered := detector.FilterOpportunities(42, opportunities)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, "ETH/USDT", filtered[0].Symbol)
+	assert.Equal(t, int64(1), detector.Suppressed())
+
+	// Another chat has not seen the alert
+	assert.Len(t, detector.FilterOpportunities(7, opportunities), 3)
+
+	// A new best route is a change, and keeps every opportunity of the symbol
+	opportunities[1].ProfitPercent = 1.2
+	filtered = detector.FilterOpportunities(42, opportunities[:2])
+	assert.Len(t, filtered, 2)
+
+	// Unchanged symbols are alerted again after RenotifyAfter
+	now = now.Add(time.Hour)
+	assert.Len(t, detector.FilterOpportunities(42, opportunities[:1]), 1)
+}
+
+func TestNotificationChangeDetector_Signals(t *testing.T) {
+	detector := NewNotificationChangeDetector(NotificationChangeConfig{ConfidenceDelta: 0.1, SpreadDelta: 0.5})
+	signal := &AggregatedSignal{
+		SignalType:      SignalTypeTechnical,
+		Symbol:          "SOL/USDT",
+		Action:          "buy",
+		Confidence:      decimal.NewFromFloat(0.7),
+		ProfitPotential: decimal.NewFromFloat(2),
+	}
+	detector.RecordSignals(42, "aggregated_technical", []*AggregatedSignal{signal})
+
+	same := *signal
+	same.Confidence = decimal.NewFromFloat(0.75)
+	assert.Empty(t, detector.FilterSignals(42, "aggregated_technical", []*AggregatedSignal{&same}))
+	assert.Len(t, detector.FilterSignals(42, "enhanced_arbitrage", []*AggregatedSignal{&same}), 1, "kinds are tracked separately")
+
+	flipped := same
+	flipped.Action = "sell"
+	assert.Len(t, detector.FilterSignals(42, "aggregated_technical", []*AggregatedSignal{&flipped}), 1)
+
+	confident := same
+	confident.Confidence = decimal.NewFromFloat(0.85)
+	assert.Len(t, detector.FilterSignals(42, "aggregated_technical", []*AggregatedSignal{&confident}), 1)
+
+	profitable := same
+	profitable.ProfitPotential = decimal.NewFromFloat(2.6)
+	assert.Len(t, detector.FilterSignals(42, "aggregated_technical", []*AggregatedSignal{&profitable}), 1)
+}
+
+func TestNotificationService_SuppressesUnchangedArbitrageAlert(t *testing.T) {
+	var sent atomic.Int32
+	telegram := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true,"messageId":"1"}`))
+	}))
+	defer telegram.Close()
+
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	mockPool.ExpectExec("INSERT INTO alert_notifications").
+		WithArgs("u1", "telegram", "arbitrage_alert", pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	ns := NewNotificationService(database.NewMockDBPool(mockPool), &database.RedisClient{Client: client}, telegram.URL, "", "")
+	ns.SetChangeDetector(NewNotificationChangeDetector(NotificationChangeConfig{}))
+
+	chatID := "42"
+	user := userModels.User{ID: "u1", TelegramChatID: &chatID}
+	opportunities := []ArbitrageOpportunity{{Symbol: "BTC/USDT", BuyExchange: "binance", SellExchange: "kraken", BuyPrice: 50000, SellPrice: 50500, ProfitPercent: 1, OpportunityType: "arbitrage"}}
+
+	require.NoError(t, ns.sendArbitrageAlert(t.Context(), user, opportunities))
+	require.NoError(t, ns.sendArbitrageAlert(t.Context(), user, opportunities))
+	assert.Equal(t, int32(1), sent.Load())
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
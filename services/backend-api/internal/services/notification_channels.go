@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"net/smtp"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
@@ -51,6 +53,9 @@ type Notification struct {
 	Metadata   map[string]string    `json:"metadata,omitempty"`
 	Timestamp  time.Time            `json:"timestamp"`
 	Recipients []string             `json:"recipients,omitempty"`
+	// HTML is an optional rich body; channels that support it send it
+	// alongside the plain-text Message.
+	HTML string `json:"html,omitempty"`
 }
 
 // NotificationChannel is the interface for notification channels.
@@ -323,28 +328,14 @@ Timestamp: %s
 		}
 	}
 
-	// Set up email headers
-	headers := make(map[string]string)
-	headers["From"] = from
-	headers["To"] = to
-	headers["Subject"] = subject
-	headers["MIME-Version"] = "1.0"
-	headers["Content-Type"] = "text/plain; charset=\"utf-8\""
-
-	// Build message
-	var msg strings.Builder
-	for k, v := range headers {
-		fmt.Fprintf(&msg, "%s: %s\r\n", k, v)
-	}
-	msg.WriteString("\r\n")
-	msg.WriteString(body)
+	msg := buildEmailMessage(from, to, subject, body, notification.HTML)
 
 	// Connect to SMTP server and send
 	addr := fmt.Sprintf("%s:%d", c.config.SMTPHost, c.config.SMTPPort)
 
 	auth := smtp.PlainAuth("", c.config.Username, c.config.Password, c.config.SMTPHost)
 
-	err := smtp.SendMail(addr, auth, c.config.FromAddress, notification.Recipients, []byte(msg.String()))
+	err := smtp.SendMail(addr, auth, c.config.FromAddress, notification.Recipients, []byte(msg))
 	if err != nil {
 		return fmt.Errorf("failed to send email: %w", err)
 	}
@@ -353,6 +344,25 @@ Timestamp: %s
 	return nil
 }
 
+// buildEmailMessage assembles the email headers and body; with an HTML part
+// the message is multipart/alternative so clients fall back to the text.
+func buildEmailMessage(from, to, subject, body, html string) string {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\n", from, to, subject)
+	if html == "" {
+		msg.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n\r\n")
+		msg.WriteString(body)
+		return msg.String()
+	}
+
+	boundary := "neuratrade-" + uuid.New().String()
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=\"%s\"\r\n\r\n", boundary)
+	fmt.Fprintf(&msg, "--%s\r\nContent-Type: text/plain; charset=\"utf-8\"\r\n\r\n%s\r\n", boundary, body)
+	fmt.Fprintf(&msg, "--%s\r\nContent-Type: text/html; charset=\"utf-8\"\r\n\r\n%s\r\n", boundary, html)
+	fmt.Fprintf(&msg, "--%s--\r\n", boundary)
+	return msg.String()
+}
+
 // WebhookChannelConfig holds custom webhook configuration.
 type WebhookChannelConfig struct {
 	URL        string
@@ -461,6 +471,135 @@ func (c *WebhookChannel) Send(ctx context.Context, notification *Notification) e
 	return fmt.Errorf("failed after %d retries: %w", c.config.RetryCount+1, lastErr)
 }
 
+// defaultTwilioBaseURL is Twilio's REST API.
+const defaultTwilioBaseURL = "https://api.twilio.com"
+
+// twilioSMSMaxLength keeps an SMS within two concatenated segments.
+const twilioSMSMaxLength = 306
+
+// TwilioChannelConfig holds Twilio SMS and voice configuration.
+type TwilioChannelConfig struct {
+	AccountSID string
+	AuthToken  string
+	// FromNumber is the Twilio number messages and calls come from.
+	FromNumber string
+	// ToNumbers are called when a notification has no recipients.
+	ToNumbers []string
+	// Voice also places a call that reads the message out.
+	Voice   bool
+	Enabled bool
+	// BaseURL overrides the Twilio API endpoint, for tests.
+	BaseURL string
+}
+
+// TwilioChannel sends emergency notifications as SMS and, optionally, phone
+// calls through Twilio.
+type TwilioChannel struct {
+	config TwilioChannelConfig
+	client *http.Client
+	logger *slog.Logger
+}
+
+// NewTwilioChannel creates a new Twilio channel.
+func NewTwilioChannel(config TwilioChannelConfig) *TwilioChannel {
+	if config.BaseURL == "" {
+		config.BaseURL = defaultTwilioBaseURL
+	}
+	return &TwilioChannel{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+		logger: telemetry.Logger().With("channel", "twilio"),
+	}
+}
+
+// Name returns the channel name.
+func (c *TwilioChannel) Name() string {
+	return "twilio"
+}
+
+// IsEnabled returns whether the channel is enabled.
+func (c *TwilioChannel) IsEnabled() bool {
+	return c.config.Enabled &&
+		c.config.AccountSID != "" &&
+		c.config.AuthToken != "" &&
+		c.config.FromNumber != ""
+}
+
+// Priorities returns the priority levels this channel handles.
+func (c *TwilioChannel) Priorities() []NotificationPriority {
+	return []NotificationPriority{PriorityNotificationEmergency}
+}
+
+// Send texts, and with Voice enabled calls, every recipient.
+func (c *TwilioChannel) Send(ctx context.Context, notification *Notification) error {
+	if !c.IsEnabled() {
+		return fmt.Errorf("twilio channel not enabled")
+	}
+	recipients := notification.Recipients
+	if len(recipients) == 0 {
+		recipients = c.config.ToNumbers
+	}
+	if len(recipients) == 0 {
+		return fmt.Errorf("no phone numbers provided")
+	}
+
+	text := notification.Message
+	if notification.Title != "" {
+		text = notification.Title + "\n" + text
+	}
+	sms := text
+	if runes := []rune(sms); len(runes) > twilioSMSMaxLength {
+		sms = string(runes[:twilioSMSMaxLength-1]) + "…"
+	}
+
+	var errors []string
+	for _, to := range recipients {
+		if err := c.post(ctx, "Messages.json", url.Values{"From": {c.config.FromNumber}, "To": {to}, "Body": {sms}}); err != nil {
+			errors = append(errors, fmt.Sprintf("sms to %s: %v", to, err))
+		}
+		if c.config.Voice {
+			twiml := fmt.Sprintf(`<Response><Say loop="2">%s</Say></Response>`, escapeTwiML(text))
+			if err := c.post(ctx, "Calls.json", url.Values{"From": {c.config.FromNumber}, "To": {to}, "Twiml": {twiml}}); err != nil {
+				errors = append(errors, fmt.Sprintf("call to %s: %v", to, err))
+			}
+		}
+	}
+	if len(errors) > 0 {
+		return fmt.Errorf("twilio delivery failed: %s", strings.Join(errors, "; "))
+	}
+
+	c.logger.Info("Sent notification via Twilio", "notification_id", notification.ID, "recipients", len(recipients), "voice", c.config.Voice)
+	return nil
+}
+
+func (c *TwilioChannel) post(ctx context.Context, resource string, form url.Values) error {
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/%s", strings.TrimRight(c.config.BaseURL, "/"), url.PathEscape(c.config.AccountSID), resource)
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(c.config.AccountSID, c.config.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("twilio returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// escapeTwiML escapes text for a TwiML <Say> element.
+func escapeTwiML(text string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(text))
+	return buf.String()
+}
+
 // NotificationChannelsService wraps the channel registry for easy injection.
 type NotificationChannelsService struct {
 	registry *ChannelRegistry
@@ -502,6 +641,12 @@ func (s *NotificationChannelsService) ConfigureWebhook(config WebhookChannelConf
 	s.registry.Register(channel)
 }
 
+// ConfigureTwilio configures the Twilio SMS and voice channel.
+func (s *NotificationChannelsService) ConfigureTwilio(config TwilioChannelConfig) {
+	channel := NewTwilioChannel(config)
+	s.registry.Register(channel)
+}
+
 // SendNotification sends a notification through appropriate channels.
 func (s *NotificationChannelsService) SendNotification(ctx context.Context, notification *Notification) error {
 	return s.registry.Send(ctx, notification)
@@ -234,6 +234,17 @@ func TestEmailChannel_Disabled(t *testing.T) {
 	assert.False(t, channel.IsEnabled())
 }
 
+func TestBuildEmailMessage(t *testing.T) {
+	plain := buildEmailMessage("a@example.com", "b@example.com", "Subject", "body", "")
+	assert.Contains(t, plain, "Content-Type: text/plain")
+	assert.NotContains(t, plain, "multipart")
+
+	rich := buildEmailMessage("a@example.com", "b@example.com", "Subject", "body", "<p>body</p>")
+	assert.Contains(t, rich, "Content-Type: multipart/alternative")
+	assert.Contains(t, rich, "Content-Type: text/html")
+	assert.Contains(t, rich, "<p>body</p>")
+}
+
 func TestWebhookChannel_Disabled(t *testing.T) {
 	channel := NewWebhookChannel(WebhookChannelConfig{
 		Enabled: false,
@@ -242,3 +253,46 @@ func TestWebhookChannel_Disabled(t *testing.T) {
 
 	assert.False(t, channel.IsEnabled())
 }
+
+func TestTwilioChannel_SendsSMSAndCall(t *testing.T) {
+	var paths []string
+	var forms []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "AC123", user)
+		assert.Equal(t, "secret", pass)
+		require.NoError(t, r.ParseForm())
+		paths = append(paths, r.URL.Path)
+		forms = append(forms, map[string]string{"From": r.PostForm.Get("From"), "To": r.PostForm.Get("To"), "Body": r.PostForm.Get("Body"), "Twiml": r.PostForm.Get("Twiml")})
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	channel := NewTwilioChannel(TwilioChannelConfig{
+		AccountSID: "AC123",
+		AuthToken:  "secret",
+		FromNumber: "+15550000000",
+		ToNumbers:  []string{"+15551111111"},
+		Voice:      true,
+		Enabled:    true,
+		BaseURL:    server.URL,
+	})
+	require.True(t, channel.IsEnabled())
+
+	err := channel.Send(context.Background(), &Notification{Title: "Critical", Message: "BTC & ETH <halted>"})
+	require.NoError(t, err)
+	require.Len(t, paths, 2)
+	assert.Equal(t, "/2010-04-01/Accounts/AC123/Messages.json", paths[0])
+	assert.Equal(t, "+15551111111", forms[0]["To"])
+	assert.Equal(t, "+15550000000", forms[0]["From"])
+	assert.Equal(t, "Critical\nBTC & ETH <halted>", forms[0]["Body"])
+	assert.Equal(t, "/2010-04-01/Accounts/AC123/Calls.json", paths[1])
+	assert.Contains(t, forms[1]["Twiml"], "BTC &amp; ETH &lt;halted&gt;")
+}
+
+func TestTwilioChannel_Disabled(t *testing.T) {
+	channel := NewTwilioChannel(TwilioChannelConfig{Enabled: true, AccountSID: "AC123"})
+	assert.False(t, channel.IsEnabled())
+	assert.Error(t, channel.Send(context.Background(), &Notification{Message: "test"}))
+}
@@ -0,0 +1,449 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/services/jobqueue"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+)
+
+// notificationDeliveryJobType is the job type used for queued Telegram sends.
+const notificationDeliveryJobType = "telegram_notification"
+
+// NotificationDeliveryQueueConfig configures the durable notification queue.
+type NotificationDeliveryQueueConfig struct {
+	// Namespace is the Redis key prefix for the underlying job queue.
+	Namespace string
+	// Workers is the number of concurrent delivery workers.
+	Workers int
+	// PollInterval is how long an idle worker waits before polling again.
+	PollInterval time.Duration
+	// MaxAttempts is the number of delivery attempts before dead-lettering.
+	MaxAttempts int
+	// BaseBackoff is the initial retry delay; it doubles on every attempt.
+	BaseBackoff time.Duration
+	// MaxBackoff caps the retry delay.
+	MaxBackoff time.Duration
+}
+
+// DefaultNotificationDeliveryQueueConfig returns sensible defaults.
+func DefaultNotificationDeliveryQueueConfig() NotificationDeliveryQueueConfig {
+	return NotificationDeliveryQueueConfig{
+		Namespace:    "notifications",
+		Workers:      4,
+		PollInterval: 500 * time.Millisecond,
+		MaxAttempts:  5,
+		BaseBackoff:  2 * time.Second,
+		MaxBackoff:   5 * time.Minute,
+	}
+}
+
+// QueuedNotification is a single message waiting for delivery.
+type QueuedNotification struct {
+	ChatID       int64
+	UserID       string
+	MessageType  string
+	Text         string
+	DeadLetterID string
+	ReplyMarkup  *InlineKeyboardMarkup
+	// Priority picks the queue lane; empty means normal.
+	Priority NotificationPriority
+	// NotBefore delays delivery, e.g. until quiet hours end.
+	NotBefore time.Time
+}
+
+// NotificationQueueStats summarizes the delivery queue state.
+type NotificationQueueStats struct {
+	Pending    map[string]int64 `json:"pending"`
+	Scheduled  int64            `json:"scheduled"`
+	DeadLetter int64            `json:"dead_letter"`
+	Workers    int              `json:"workers"`
+	Running    bool             `json:"running"`
+	Delivered  int64            `json:"delivered"`
+	Retried    int64            `json:"retried"`
+	Failed     int64            `json:"failed"`
+}
+
+// notificationDeadLetterStore is the subset of DeadLetterService used by the queue.
+type notificationDeadLetterStore interface {
+	AddToDeadLetter(ctx context.Context, userID, chatID, messageType, messageContent string, priority NotificationPriority, errorCode, errorMessage string) (string, error)
+	UpdateDeadLetter(ctx context.Context, id string, success bool, errorCode, errorMessage string) error
+}
+
+type notificationSendFunc func(ctx context.Context, chatID int64, text string, markup *InlineKeyboardMarkup, priority NotificationPriority) TelegramSendResult
+
+type notificationResultFunc func(ctx context.Context, userID string, chatID int64, result TelegramSendResult)
+
+// NotificationDeliveryQueue moves Telegram sends off the request path onto a
+// Redis-backed job queue so that messages survive process restarts.
+type NotificationDeliveryQueue struct {
+	queue      *jobqueue.Queue
+	config     NotificationDeliveryQueueConfig
+	send       notificationSendFunc
+	onResult   notificationResultFunc
+	deadLetter notificationDeadLetterStore
+	logger     *slog.Logger
+
+	mu      sync.Mutex
+	running bool
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+
+	statsMu   sync.Mutex
+	delivered int64
+	retried   int64
+	failed    int64
+}
+
+// NewNotificationDeliveryQueue creates a delivery queue on top of a job queue.
+//
+// Parameters:
+//
+//	queue: Redis-backed job queue.
+//	config: Queue configuration.
+//	send: Function performing a single delivery attempt.
+//	onResult: Optional callback invoked with every delivery result (receipts, bounce handling).
+//	deadLetter: Optional persistent dead-letter store.
+//
+// Returns:
+//
+//	*NotificationDeliveryQueue: Initialized queue (workers not started).
+func NewNotificationDeliveryQueue(queue *jobqueue.Queue, config NotificationDeliveryQueueConfig, send notificationSendFunc, onResult notificationResultFunc, deadLetter notificationDeadLetterStore) *NotificationDeliveryQueue {
+	defaults := DefaultNotificationDeliveryQueueConfig()
+	if config.Workers <= 0 {
+		config.Workers = defaults.Workers
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaults.PollInterval
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaults.MaxAttempts
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = defaults.BaseBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = defaults.MaxBackoff
+	}
+
+	return &NotificationDeliveryQueue{
+		queue:      queue,
+		config:     config,
+		send:       send,
+		onResult:   onResult,
+		deadLetter: deadLetter,
+		logger:     telemetry.Logger(),
+	}
+}
+
+// Enqueue persists a message for asynchronous delivery. Critical messages
+// are dequeued ahead of everything else.
+func (q *NotificationDeliveryQueue) Enqueue(ctx context.Context, msg QueuedNotification) (string, error) {
+	priority := ParseNotificationPriority(string(msg.Priority))
+	payload := map[string]interface{}{
+		"chat_id":      strconv.FormatInt(msg.ChatID, 10),
+		"user_id":      msg.UserID,
+		"message_type": msg.MessageType,
+		"text":         msg.Text,
+		"priority":     string(priority),
+	}
+	if msg.DeadLetterID != "" {
+		payload["dead_letter_id"] = msg.DeadLetterID
+	}
+	if msg.ReplyMarkup != nil {
+		markup, err := json.Marshal(msg.ReplyMarkup)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal reply markup: %w", err)
+		}
+		payload["reply_markup"] = string(markup)
+	}
+
+	options := jobqueue.EnqueueOptions{MaxAttempts: q.config.MaxAttempts}
+	if !msg.NotBefore.IsZero() {
+		options.ScheduleFor = &msg.NotBefore
+	}
+	job, err := q.queue.EnqueueWithOptions(ctx, notificationDeliveryJobType, payload, notificationJobPriority(priority), options)
+	if err != nil {
+		return "", fmt.Errorf("failed to enqueue notification: %w", err)
+	}
+	return job.ID, nil
+}
+
+// Start launches the delivery workers.
+func (q *NotificationDeliveryQueue) Start(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.running {
+		return fmt.Errorf("notification delivery queue already running")
+	}
+
+	// Deliveries a previous process dequeued but never finished go back on
+	// the queue before any worker starts.
+	if recovered, err := q.queue.RecoverStale(ctx); err != nil {
+		q.logger.Error("Failed to recover in-flight notifications", "error", err)
+	} else if recovered > 0 {
+		q.logger.Info("Recovered in-flight notifications", "count", recovered)
+	}
+
+	workerCtx, cancel := context.WithCancel(ctx)
+	q.cancel = cancel
+	q.running = true
+
+	for i := 0; i < q.config.Workers; i++ {
+		q.wg.Add(1)
+		go q.worker(workerCtx)
+	}
+
+	q.logger.Info("Notification delivery queue started", "workers", q.config.Workers)
+	return nil
+}
+
+// Stop signals workers to exit and waits for in-flight deliveries.
+func (q *NotificationDeliveryQueue) Stop() {
+	q.mu.Lock()
+	if !q.running {
+		q.mu.Unlock()
+		return
+	}
+	q.running = false
+	cancel := q.cancel
+	q.mu.Unlock()
+
+	cancel()
+	q.wg.Wait()
+	q.logger.Info("Notification delivery queue stopped")
+}
+
+// IsRunning reports whether workers are active.
+func (q *NotificationDeliveryQueue) IsRunning() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.running
+}
+
+// Stats returns queue depth and delivery counters.
+func (q *NotificationDeliveryQueue) Stats(ctx context.Context) (*NotificationQueueStats, error) {
+	depths, err := q.queue.GetQueueDepth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	scheduled, err := q.queue.GetScheduledDepth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deadLetter, err := q.queue.GetDeadLetterDepth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make(map[string]int64, len(depths))
+	for priority, depth := range depths {
+		pending[priority.String()] = depth
+	}
+
+	q.statsMu.Lock()
+	defer q.statsMu.Unlock()
+	return &NotificationQueueStats{
+		Pending:    pending,
+		Scheduled:  scheduled,
+		DeadLetter: deadLetter,
+		Workers:    q.config.Workers,
+		Running:    q.IsRunning(),
+		Delivered:  q.delivered,
+		Retried:    q.retried,
+		Failed:     q.failed,
+	}, nil
+}
+
+// ReplayQueueDeadLetters moves up to limit entries from the Redis dead letter
+// list back onto the live queue.
+func (q *NotificationDeliveryQueue) ReplayQueueDeadLetters(ctx context.Context, limit int) (int, error) {
+	replayed := 0
+	for replayed < limit {
+		depth, err := q.queue.GetDeadLetterDepth(ctx)
+		if err != nil {
+			return replayed, err
+		}
+		if depth == 0 {
+			break
+		}
+		if err := q.queue.RetryDeadLetter(ctx, depth-1); err != nil {
+			return replayed, err
+		}
+		replayed++
+	}
+	return replayed, nil
+}
+
+func (q *NotificationDeliveryQueue) worker(ctx context.Context) {
+	defer q.wg.Done()
+
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		job, err := q.queue.Dequeue(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			q.logger.Error("Failed to dequeue notification", "error", err)
+		}
+
+		if job == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(q.config.PollInterval):
+			}
+			continue
+		}
+
+		// Deliveries already pulled off the queue are finished even during
+		// shutdown so they are not lost between dequeue and send.
+		q.process(context.WithoutCancel(ctx), job)
+	}
+}
+
+func (q *NotificationDeliveryQueue) process(ctx context.Context, job *jobqueue.Job) {
+	msg, err := queuedNotificationFromPayload(job.Payload)
+	if err != nil {
+		q.logger.Error("Dropping malformed notification job", "job_id", job.ID, "error", err)
+		_ = q.queue.DeadLetter(ctx, job, err)
+		return
+	}
+
+	result := q.send(ctx, msg.ChatID, msg.Text, msg.ReplyMarkup, msg.Priority)
+	if q.onResult != nil {
+		q.onResult(ctx, msg.UserID, msg.ChatID, result)
+	}
+	if result.OK {
+		q.recordDelivered()
+		if msg.DeadLetterID != "" && q.deadLetter != nil {
+			if err := q.deadLetter.UpdateDeadLetter(ctx, msg.DeadLetterID, true, "", ""); err != nil {
+				q.logger.Error("Failed to mark dead letter as success", "id", msg.DeadLetterID, "error", err)
+			}
+		}
+		_ = q.queue.Complete(ctx, job)
+		return
+	}
+
+	sendErr := fmt.Errorf("%s: %s", result.ErrorCode, result.Error)
+
+	if isRetryableError(result.ErrorCode) && job.Attempts < job.MaxAttempts {
+		delay := q.backoff(job.Attempts, result.RetryAfter)
+		if err := q.queue.Reschedule(ctx, job, sendErr, delay); err != nil {
+			q.logger.Error("Failed to reschedule notification", "job_id", job.ID, "error", err)
+		} else {
+			q.recordRetried()
+			q.logger.Info("Rescheduled notification delivery",
+				"job_id", job.ID,
+				"attempt", job.Attempts,
+				"delay_ms", delay.Milliseconds(),
+				"error_code", result.ErrorCode,
+			)
+			return
+		}
+	}
+
+	q.recordFailed()
+	q.logger.Warn("Notification delivery failed permanently",
+		"job_id", job.ID,
+		"attempts", job.Attempts,
+		"error_code", result.ErrorCode,
+		"chat_id", msg.ChatID,
+		"priority", msg.Priority,
+	)
+
+	if q.deadLetter != nil && msg.UserID != "" {
+		if msg.DeadLetterID != "" {
+			err = q.deadLetter.UpdateDeadLetter(ctx, msg.DeadLetterID, false, string(result.ErrorCode), result.Error)
+		} else {
+			_, err = q.deadLetter.AddToDeadLetter(ctx, msg.UserID, strconv.FormatInt(msg.ChatID, 10), msg.MessageType, msg.Text, msg.Priority, string(result.ErrorCode), result.Error)
+		}
+		if err == nil {
+			_ = q.queue.Complete(ctx, job)
+			return
+		}
+		q.logger.Error("Failed to persist dead letter, keeping in queue dead letter list", "job_id", job.ID, "error", err)
+	}
+
+	if err := q.queue.DeadLetter(ctx, job, sendErr); err != nil {
+		q.logger.Error("Failed to dead-letter notification", "job_id", job.ID, "error", err)
+	}
+}
+
+// backoff returns the retry delay for an attempt, preferring the provider's
+// RetryAfter hint when present.
+func (q *NotificationDeliveryQueue) backoff(attempt int, retryAfter int32) time.Duration {
+	if retryAfter > 0 {
+		return time.Duration(retryAfter) * time.Second
+	}
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := q.config.BaseBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= q.config.MaxBackoff {
+			return q.config.MaxBackoff
+		}
+	}
+	return delay
+}
+
+func (q *NotificationDeliveryQueue) recordDelivered() {
+	q.statsMu.Lock()
+	q.delivered++
+	q.statsMu.Unlock()
+}
+
+func (q *NotificationDeliveryQueue) recordRetried() {
+	q.statsMu.Lock()
+	q.retried++
+	q.statsMu.Unlock()
+}
+
+func (q *NotificationDeliveryQueue) recordFailed() {
+	q.statsMu.Lock()
+	q.failed++
+	q.statsMu.Unlock()
+}
+
+func queuedNotificationFromPayload(payload map[string]interface{}) (QueuedNotification, error) {
+	var msg QueuedNotification
+
+	chatIDStr, _ := payload["chat_id"].(string)
+	chatID, err := strconv.ParseInt(chatIDStr, 10, 64)
+	if err != nil {
+		return msg, fmt.Errorf("invalid chat_id %q: %w", chatIDStr, err)
+	}
+	text, _ := payload["text"].(string)
+	if text == "" {
+		return msg, fmt.Errorf("empty notification text")
+	}
+
+	msg.ChatID = chatID
+	msg.Text = text
+	msg.UserID, _ = payload["user_id"].(string)
+	msg.MessageType, _ = payload["message_type"].(string)
+	msg.DeadLetterID, _ = payload["dead_letter_id"].(string)
+	priority, _ := payload["priority"].(string)
+	msg.Priority = ParseNotificationPriority(priority)
+	if raw, _ := payload["reply_markup"].(string); raw != "" {
+		var markup InlineKeyboardMarkup
+		if err := json.Unmarshal([]byte(raw), &markup); err != nil {
+			return msg, fmt.Errorf("invalid reply_markup: %w", err)
+		}
+		msg.ReplyMarkup = &markup
+	}
+	return msg, nil
+}
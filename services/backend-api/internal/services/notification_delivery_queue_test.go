@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/irfndi/neuratrade/internal/services/jobqueue"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDeadLetterStore struct {
+	mu         sync.Mutex
+	added      []string
+	priorities []NotificationPriority
+	updates    map[string]bool
+}
+
+func (f *fakeDeadLetterStore) AddToDeadLetter(ctx context.Context, userID, chatID, messageType, messageContent string, priority NotificationPriority, errorCode, errorMessage string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.added = append(f.added, errorCode)
+	f.priorities = append(f.priorities, priority)
+	return "dl-1", nil
+}
+
+func (f *fakeDeadLetterStore) UpdateDeadLetter(ctx context.Context, id string, success bool, errorCode, errorMessage string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.updates == nil {
+		f.updates = make(map[string]bool)
+	}
+	f.updates[id] = success
+	return nil
+}
+
+func newTestJobQueue(t *testing.T) *jobqueue.Queue {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return jobqueue.New(client, jobqueue.Config{Namespace: "test-notifications"})
+}
+
+func dequeueAndProcess(t *testing.T, q *NotificationDeliveryQueue) *jobqueue.Job {
+	job, err := q.queue.Dequeue(t.Context())
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	q.process(t.Context(), job)
+	return job
+}
+
+func TestNotificationDeliveryQueue_DeliversMessage(t *testing.T) {
+	var sent []string
+	send := func(ctx context.Context, chatID int64, text string, markup *InlineKeyboardMarkup, priority NotificationPriority) TelegramSendResult {
+		sent = append(sent, text)
+		return TelegramSendResult{OK: true}
+	}
+
+	q := NewNotificationDeliveryQueue(newTestJobQueue(t), NotificationDeliveryQueueConfig{}, send, nil, nil)
+	_, err := q.Enqueue(t.Context(), QueuedNotification{ChatID: 123456789012, UserID: "u1", Text: "hello"})
+	require.NoError(t, err)
+
+	dequeueAndProcess(t, q)
+
+	assert.Equal(t, []string{"hello"}, sent)
+	stats, err := q.Stats(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Delivered)
+	assert.Equal(t, int64(0), stats.DeadLetter)
+}
+
+func TestNotificationDeliveryQueue_RetryHonorsRetryAfter(t *testing.T) {
+	send := func(ctx context.Context, chatID int64, text string, markup *InlineKeyboardMarkup, priority NotificationPriority) TelegramSendResult {
+		return TelegramSendResult{ErrorCode: TelegramErrorRateLimited, Error: "slow down", RetryAfter: 30}
+	}
+
+	q := NewNotificationDeliveryQueue(newTestJobQueue(t), NotificationDeliveryQueueConfig{MaxAttempts: 3}, send, nil, nil)
+	_, err := q.Enqueue(t.Context(), QueuedNotification{ChatID: 1, UserID: "u1", Text: "retry me"})
+	require.NoError(t, err)
+
+	dequeueAndProcess(t, q)
+
+	stats, err := q.Stats(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Scheduled)
+	assert.Equal(t, int64(1), stats.Retried)
+
+	// Not due yet: RetryAfter pushed it 30s out.
+	job, err := q.queue.Dequeue(t.Context())
+	require.NoError(t, err)
+	assert.Nil(t, job)
+}
+
+func TestNotificationDeliveryQueue_NonRetryableGoesToDeadLetter(t *testing.T) {
+	var results []TelegramErrorCode
+	send := func(ctx context.Context, chatID int64, text string, markup *InlineKeyboardMarkup, priority NotificationPriority) TelegramSendResult {
+		return TelegramSendResult{ErrorCode: TelegramErrorUserBlocked, Error: "blocked"}
+	}
+	onResult := func(ctx context.Context, userID string, chatID int64, result TelegramSendResult) {
+		results = append(results, result.ErrorCode)
+	}
+	store := &fakeDeadLetterStore{}
+
+	q := NewNotificationDeliveryQueue(newTestJobQueue(t), NotificationDeliveryQueueConfig{}, send, onResult, store)
+	_, err := q.Enqueue(t.Context(), QueuedNotification{ChatID: 1, UserID: "u1", Text: "bye"})
+	require.NoError(t, err)
+
+	dequeueAndProcess(t, q)
+
+	assert.Equal(t, []TelegramErrorCode{TelegramErrorUserBlocked}, results)
+	assert.Equal(t, []string{string(TelegramErrorUserBlocked)}, store.added)
+
+	stats, err := q.Stats(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Failed)
+	assert.Equal(t, int64(0), stats.DeadLetter, "persisted dead letters should not also sit in the Redis list")
+}
+
+func TestNotificationDeliveryQueue_ExhaustedWithoutStoreUsesQueueDeadLetter(t *testing.T) {
+	send := func(ctx context.Context, chatID int64, text string, markup *InlineKeyboardMarkup, priority NotificationPriority) TelegramSendResult {
+		return TelegramSendResult{ErrorCode: TelegramErrorNetworkError, Error: "down"}
+	}
+
+	q := NewNotificationDeliveryQueue(newTestJobQueue(t), NotificationDeliveryQueueConfig{MaxAttempts: 1}, send, nil, nil)
+	_, err := q.Enqueue(t.Context(), QueuedNotification{ChatID: 1, Text: "lost?"})
+	require.NoError(t, err)
+
+	dequeueAndProcess(t, q)
+
+	stats, err := q.Stats(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.DeadLetter)
+
+	replayed, err := q.ReplayQueueDeadLetters(t.Context(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, replayed)
+
+	stats, err = q.Stats(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stats.DeadLetter)
+	assert.Equal(t, int64(1), stats.Pending["normal"])
+}
+
+func TestNotificationDeliveryQueue_ReplaySuccessUpdatesDeadLetter(t *testing.T) {
+	send := func(ctx context.Context, chatID int64, text string, markup *InlineKeyboardMarkup, priority NotificationPriority) TelegramSendResult {
+		return TelegramSendResult{OK: true}
+	}
+	store := &fakeDeadLetterStore{}
+
+	q := NewNotificationDeliveryQueue(newTestJobQueue(t), NotificationDeliveryQueueConfig{}, send, nil, store)
+	_, err := q.Enqueue(t.Context(), QueuedNotification{ChatID: 1, UserID: "u1", Text: "again", DeadLetterID: "dl-42"})
+	require.NoError(t, err)
+
+	dequeueAndProcess(t, q)
+
+	assert.True(t, store.updates["dl-42"])
+}
+
+func TestNotificationDeliveryQueue_Backoff(t *testing.T) {
+	q := NewNotificationDeliveryQueue(nil, NotificationDeliveryQueueConfig{
+		BaseBackoff: time.Second,
+		MaxBackoff:  5 * time.Second,
+	}, nil, nil, nil)
+
+	assert.Equal(t, time.Second, q.backoff(1, 0))
+	assert.Equal(t, 2*time.Second, q.backoff(2, 0))
+	assert.Equal(t, 4*time.Second, q.backoff(3, 0))
+	assert.Equal(t, 5*time.Second, q.backoff(4, 0))
+	assert.Equal(t, 7*time.Second, q.backoff(4, 7))
+}
+
+func TestNotificationDeliveryQueue_StartStop(t *testing.T) {
+	delivered := make(chan string, 1)
+	send := func(ctx context.Context, chatID int64, text string, markup *InlineKeyboardMarkup, priority NotificationPriority) TelegramSendResult {
+		delivered <- text
+		return TelegramSendResult{OK: true}
+	}
+
+	q := NewNotificationDeliveryQueue(newTestJobQueue(t), NotificationDeliveryQueueConfig{
+		Workers:      1,
+		PollInterval: 10 * time.Millisecond,
+	}, send, nil, nil)
+	require.NoError(t, q.Start(t.Context()))
+	assert.Error(t, q.Start(t.Context()))
+
+	_, err := q.Enqueue(t.Context(), QueuedNotification{ChatID: 1, Text: "async"})
+	require.NoError(t, err)
+
+	select {
+	case text := <-delivered:
+		assert.Equal(t, "async", text)
+	case <-time.After(2 * time.Second):
+		t.Fatal("message was not delivered")
+	}
+
+	q.Stop()
+	assert.False(t, q.IsRunning())
+}
+
+func TestQueuedNotificationFromPayload_Invalid(t *testing.T) {
+	_, err := queuedNotificationFromPayload(map[string]interface{}{"chat_id": "abc", "text": "x"})
+	assert.Error(t, err)
+
+	_, err = queuedNotificationFromPayload(map[string]interface{}{"chat_id": "1"})
+	assert.Error(t, err)
+}
+
+func TestNotificationDeliveryQueue_PreservesReplyMarkup(t *testing.T) {
+	var got *InlineKeyboardMarkup
+	send := func(ctx context.Context, chatID int64, text string, markup *InlineKeyboardMarkup, priority NotificationPriority) TelegramSendResult {
+		got = markup
+		return TelegramSendResult{OK: true}
+	}
+
+	q := NewNotificationDeliveryQueue(newTestJobQueue(t), NotificationDeliveryQueueConfig{}, send, nil, nil)
+	markup := &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{{{Text: "Details", CallbackData: "na:abc:d:sig"}}}}
+	_, err := q.Enqueue(t.Context(), QueuedNotification{ChatID: 1, Text: "with buttons", ReplyMarkup: markup})
+	require.NoError(t, err)
+
+	dequeueAndProcess(t, q)
+
+	assert.Equal(t, markup, got)
+}
+
+func TestNotificationDeliveryQueue_CriticalFirst(t *testing.T) {
+	var sent []string
+	var priorities []NotificationPriority
+	send := func(ctx context.Context, chatID int64, text string, markup *InlineKeyboardMarkup, priority NotificationPriority) TelegramSendResult {
+		sent = append(sent, text)
+		priorities = append(priorities, priority)
+		return TelegramSendResult{ErrorCode: TelegramErrorUserBlocked, Error: "blocked"}
+	}
+	store := &fakeDeadLetterStore{}
+
+	q := NewNotificationDeliveryQueue(newTestJobQueue(t), NotificationDeliveryQueueConfig{}, send, nil, store)
+	for _, msg := range []QueuedNotification{
+		{ChatID: 1, UserID: "u1", Text: "fyi", Priority: PriorityNotificationLow},
+		{ChatID: 1, UserID: "u1", Text: "alert"},
+		{ChatID: 1, UserID: "u1", Text: "halted", Priority: PriorityNotificationEmergency},
+	} {
+		_, err := q.Enqueue(t.Context(), msg)
+		require.NoError(t, err)
+	}
+
+	for range 3 {
+		dequeueAndProcess(t, q)
+	}
+
+	assert.Equal(t, []string{"halted", "alert", "fyi"}, sent)
+	assert.Equal(t, []NotificationPriority{PriorityNotificationEmergency, PriorityNotificationNormal, PriorityNotificationLow}, priorities)
+	assert.Equal(t, priorities, store.priorities, "dead letters keep the priority for replay")
+}
+
+func TestNotificationDeliveryQueue_NotBefore(t *testing.T) {
+	send := func(ctx context.Context, chatID int64, text string, markup *InlineKeyboardMarkup, priority NotificationPriority) TelegramSendResult {
+		return TelegramSendResult{OK: true}
+	}
+
+	q := NewNotificationDeliveryQueue(newTestJobQueue(t), NotificationDeliveryQueueConfig{}, send, nil, nil)
+	_, err := q.Enqueue(t.Context(), QueuedNotification{ChatID: 1, Text: "later", NotBefore: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+
+	job, err := q.queue.Dequeue(t.Context())
+	require.NoError(t, err)
+	assert.Nil(t, job, "the message is not due yet")
+	stats, err := q.Stats(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Scheduled)
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DeliveryStatus is the outcome of a single notification delivery attempt.
+type DeliveryStatus string
+
+const (
+	DeliveryStatusSent    DeliveryStatus = "sent"
+	DeliveryStatusFailed  DeliveryStatus = "failed"
+	DeliveryStatusBlocked DeliveryStatus = "blocked"
+)
+
+const (
+	defaultBounceThreshold   = 3
+	defaultReceiptHistory    = 50
+	deliveryReceiptRetainTTL = 30 * 24 * time.Hour
+)
+
+// DeliveryReceipt records the result of one delivery attempt.
+type DeliveryReceipt struct {
+	UserID      string         `json:"user_id"`
+	ChatID      string         `json:"chat_id"`
+	MessageID   string         `json:"message_id,omitempty"`
+	Status      DeliveryStatus `json:"status"`
+	ErrorCode   string         `json:"error_code,omitempty"`
+	Error       string         `json:"error,omitempty"`
+	AttemptedAt time.Time      `json:"attempted_at"`
+}
+
+// DeliveryStats aggregates delivery receipts for a user.
+type DeliveryStats struct {
+	UserID                string            `json:"user_id"`
+	Sent                  int64             `json:"sent"`
+	Failed                int64             `json:"failed"`
+	Blocked               int64             `json:"blocked"`
+	ConsecutiveBounces    int64             `json:"consecutive_bounces"`
+	BounceThreshold       int               `json:"bounce_threshold"`
+	NotificationsDisabled bool              `json:"notifications_disabled"`
+	LastStatus            DeliveryStatus    `json:"last_status,omitempty"`
+	LastErrorCode         string            `json:"last_error_code,omitempty"`
+	LastAttemptAt         *time.Time        `json:"last_attempt_at,omitempty"`
+	Recent                []DeliveryReceipt `json:"recent"`
+}
+
+// NotificationDeliveryTracker stores per-message delivery receipts in Redis and
+// tracks consecutive bounces so dead chats can be disabled automatically.
+type NotificationDeliveryTracker struct {
+	redis           *redis.Client
+	bounceThreshold int
+	historySize     int64
+}
+
+// NewNotificationDeliveryTracker creates a delivery tracker.
+//
+// Parameters:
+//
+//	client: Redis client used for receipts and counters.
+//	bounceThreshold: Consecutive USER_BLOCKED/CHAT_NOT_FOUND results before disabling (0 uses default).
+//
+// Returns:
+//
+//	*NotificationDeliveryTracker: Initialized tracker.
+func NewNotificationDeliveryTracker(client *redis.Client, bounceThreshold int) *NotificationDeliveryTracker {
+	if bounceThreshold <= 0 {
+		bounceThreshold = defaultBounceThreshold
+	}
+	return &NotificationDeliveryTracker{
+		redis:           client,
+		bounceThreshold: bounceThreshold,
+		historySize:     defaultReceiptHistory,
+	}
+}
+
+// BounceThreshold returns the configured consecutive-bounce limit.
+func (t *NotificationDeliveryTracker) BounceThreshold() int {
+	return t.bounceThreshold
+}
+
+// classifyDelivery maps a send result to a delivery status.
+func classifyDelivery(result TelegramSendResult) DeliveryStatus {
+	if result.OK {
+		return DeliveryStatusSent
+	}
+	if result.ErrorCode == TelegramErrorUserBlocked || result.ErrorCode == TelegramErrorChatNotFound {
+		return DeliveryStatusBlocked
+	}
+	return DeliveryStatusFailed
+}
+
+func deliveryStatsKey(userID string) string {
+	return fmt.Sprintf("notification:delivery:%s:stats", userID)
+}
+
+func deliveryRecentKey(userID string) string {
+	return fmt.Sprintf("notification:delivery:%s:recent", userID)
+}
+
+func deliveryBounceKey(chatID string) string {
+	return fmt.Sprintf("notification:delivery:chat:%s:bounces", chatID)
+}
+
+// Record stores a delivery receipt and updates counters.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	userID: The user the message was addressed to (may be empty for system chats).
+//	chatID: The Telegram chat ID.
+//	result: The send result.
+//
+// Returns:
+//
+//	bool: True when the chat just reached the bounce threshold and should be disabled.
+//	error: Error if Redis operations fail.
+func (t *NotificationDeliveryTracker) Record(ctx context.Context, userID string, chatID int64, result TelegramSendResult) (bool, error) {
+	chatIDStr := strconv.FormatInt(chatID, 10)
+	status := classifyDelivery(result)
+	receipt := DeliveryReceipt{
+		UserID:      userID,
+		ChatID:      chatIDStr,
+		MessageID:   result.MessageID,
+		Status:      status,
+		ErrorCode:   string(result.ErrorCode),
+		Error:       result.Error,
+		AttemptedAt: time.Now().UTC(),
+	}
+
+	var bounces *redis.IntCmd
+	pipe := t.redis.TxPipeline()
+	switch status {
+	case DeliveryStatusBlocked:
+		bounces = pipe.Incr(ctx, deliveryBounceKey(chatIDStr))
+		pipe.Expire(ctx, deliveryBounceKey(chatIDStr), deliveryReceiptRetainTTL)
+	case DeliveryStatusSent:
+		pipe.Del(ctx, deliveryBounceKey(chatIDStr))
+	}
+
+	if userID != "" {
+		data, err := json.Marshal(receipt)
+		if err != nil {
+			return false, fmt.Errorf("failed to marshal delivery receipt: %w", err)
+		}
+		statsKey := deliveryStatsKey(userID)
+		pipe.HIncrBy(ctx, statsKey, string(status), 1)
+		pipe.HSet(ctx, statsKey,
+			"last_status", string(status),
+			"last_error_code", receipt.ErrorCode,
+			"last_attempt_at", receipt.AttemptedAt.Format(time.RFC3339Nano),
+			"chat_id", chatIDStr,
+		)
+		pipe.Expire(ctx, statsKey, deliveryReceiptRetainTTL)
+		pipe.LPush(ctx, deliveryRecentKey(userID), data)
+		pipe.LTrim(ctx, deliveryRecentKey(userID), 0, t.historySize-1)
+		pipe.Expire(ctx, deliveryRecentKey(userID), deliveryReceiptRetainTTL)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, fmt.Errorf("failed to record delivery receipt: %w", err)
+	}
+
+	if bounces == nil {
+		return false, nil
+	}
+	// Only report the crossing itself so callers disable the chat once.
+	return bounces.Val() == int64(t.bounceThreshold), nil
+}
+
+// ConsecutiveBounces returns the current bounce streak for a chat.
+func (t *NotificationDeliveryTracker) ConsecutiveBounces(ctx context.Context, chatID string) (int64, error) {
+	n, err := t.redis.Get(ctx, deliveryBounceKey(chatID)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return n, err
+}
+
+// ResetBounces clears the bounce streak for a chat, e.g. after the user unblocks the bot.
+func (t *NotificationDeliveryTracker) ResetBounces(ctx context.Context, chatID string) error {
+	return t.redis.Del(ctx, deliveryBounceKey(chatID)).Err()
+}
+
+// GetUserStats returns aggregated delivery statistics for a user.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	userID: The user ID.
+//	recentLimit: Number of recent receipts to include.
+//
+// Returns:
+//
+//	*DeliveryStats: Aggregated statistics.
+//	error: Error if Redis operations fail.
+func (t *NotificationDeliveryTracker) GetUserStats(ctx context.Context, userID string, recentLimit int) (*DeliveryStats, error) {
+	values, err := t.redis.HGetAll(ctx, deliveryStatsKey(userID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load delivery stats: %w", err)
+	}
+
+	stats := &DeliveryStats{
+		UserID:          userID,
+		BounceThreshold: t.bounceThreshold,
+		Recent:          []DeliveryReceipt{},
+	}
+	stats.Sent, _ = strconv.ParseInt(values[string(DeliveryStatusSent)], 10, 64)
+	stats.Failed, _ = strconv.ParseInt(values[string(DeliveryStatusFailed)], 10, 64)
+	stats.Blocked, _ = strconv.ParseInt(values[string(DeliveryStatusBlocked)], 10, 64)
+	stats.LastStatus = DeliveryStatus(values["last_status"])
+	stats.LastErrorCode = values["last_error_code"]
+	if ts, err := time.Parse(time.RFC3339Nano, values["last_attempt_at"]); err == nil {
+		stats.LastAttemptAt = &ts
+	}
+
+	if chatID := values["chat_id"]; chatID != "" {
+		bounces, err := t.ConsecutiveBounces(ctx, chatID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load bounce count: %w", err)
+		}
+		stats.ConsecutiveBounces = bounces
+		stats.NotificationsDisabled = bounces >= int64(t.bounceThreshold)
+	}
+
+	if recentLimit <= 0 {
+		return stats, nil
+	}
+	items, err := t.redis.LRange(ctx, deliveryRecentKey(userID), 0, int64(recentLimit)-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recent receipts: %w", err)
+	}
+	for _, item := range items {
+		var receipt DeliveryReceipt
+		if err := json.Unmarshal([]byte(item), &receipt); err == nil {
+			stats.Recent = append(stats.Recent, receipt)
+		}
+	}
+
+	return stats, nil
+}
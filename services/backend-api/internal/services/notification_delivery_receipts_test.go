@@ -0,0 +1,90 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDeliveryTracker(t *testing.T, threshold int) *NotificationDeliveryTracker {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewNotificationDeliveryTracker(client, threshold)
+}
+
+func TestClassifyDelivery(t *testing.T) {
+	assert.Equal(t, DeliveryStatusSent, classifyDelivery(TelegramSendResult{OK: true}))
+	assert.Equal(t, DeliveryStatusBlocked, classifyDelivery(TelegramSendResult{ErrorCode: TelegramErrorUserBlocked}))
+	assert.Equal(t, DeliveryStatusBlocked, classifyDelivery(TelegramSendResult{ErrorCode: TelegramErrorChatNotFound}))
+	assert.Equal(t, DeliveryStatusFailed, classifyDelivery(TelegramSendResult{ErrorCode: TelegramErrorTimeout}))
+}
+
+func TestNotificationDeliveryTracker_DefaultThreshold(t *testing.T) {
+	tracker := NewNotificationDeliveryTracker(nil, 0)
+	assert.Equal(t, defaultBounceThreshold, tracker.BounceThreshold())
+}
+
+func TestNotificationDeliveryTracker_BounceThreshold(t *testing.T) {
+	tracker := newTestDeliveryTracker(t, 2)
+	ctx := t.Context()
+	blocked := TelegramSendResult{ErrorCode: TelegramErrorUserBlocked, Error: "Forbidden"}
+
+	disable, err := tracker.Record(ctx, "u1", 42, blocked)
+	require.NoError(t, err)
+	assert.False(t, disable)
+
+	disable, err = tracker.Record(ctx, "u1", 42, blocked)
+	require.NoError(t, err)
+	assert.True(t, disable, "second consecutive bounce reaches threshold")
+
+	disable, err = tracker.Record(ctx, "u1", 42, blocked)
+	require.NoError(t, err)
+	assert.False(t, disable, "threshold crossing is reported only once")
+
+	stats, err := tracker.GetUserStats(ctx, "u1", 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stats.Blocked)
+	assert.Equal(t, int64(3), stats.ConsecutiveBounces)
+	assert.True(t, stats.NotificationsDisabled)
+	assert.Equal(t, DeliveryStatusBlocked, stats.LastStatus)
+	assert.Len(t, stats.Recent, 3)
+}
+
+func TestNotificationDeliveryTracker_SuccessResetsBounces(t *testing.T) {
+	tracker := newTestDeliveryTracker(t, 3)
+	ctx := t.Context()
+
+	_, err := tracker.Record(ctx, "u1", 7, TelegramSendResult{ErrorCode: TelegramErrorChatNotFound})
+	require.NoError(t, err)
+	_, err = tracker.Record(ctx, "u1", 7, TelegramSendResult{ErrorCode: TelegramErrorNetworkError})
+	require.NoError(t, err)
+	_, err = tracker.Record(ctx, "u1", 7, TelegramSendResult{OK: true, MessageID: "m-1"})
+	require.NoError(t, err)
+
+	bounces, err := tracker.ConsecutiveBounces(ctx, "7")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), bounces)
+
+	stats, err := tracker.GetUserStats(ctx, "u1", 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Sent)
+	assert.Equal(t, int64(1), stats.Failed)
+	assert.Equal(t, int64(1), stats.Blocked)
+	assert.False(t, stats.NotificationsDisabled)
+	require.Len(t, stats.Recent, 1)
+	assert.Equal(t, "m-1", stats.Recent[0].MessageID)
+}
+
+func TestNotificationDeliveryTracker_UnknownUser(t *testing.T) {
+	tracker := newTestDeliveryTracker(t, 3)
+
+	stats, err := tracker.GetUserStats(t.Context(), "nobody", 5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stats.Sent)
+	assert.Empty(t, stats.Recent)
+	assert.Nil(t, stats.LastAttemptAt)
+}
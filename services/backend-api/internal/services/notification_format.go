@@ -0,0 +1,248 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// NotificationFormat is the Telegram parse mode notifications are written in.
+type NotificationFormat string
+
+const (
+	// NotificationFormatMarkdown is Telegram's legacy Markdown.
+	NotificationFormatMarkdown NotificationFormat = "Markdown"
+	// NotificationFormatMarkdownV2 is Telegram's MarkdownV2.
+	NotificationFormatMarkdownV2 NotificationFormat = "MarkdownV2"
+	// NotificationFormatHTML is Telegram's HTML subset.
+	NotificationFormatHTML NotificationFormat = "HTML"
+)
+
+// ParseNotificationFormat converts a configuration value into a notification
+// format. Matching is case-insensitive; empty means legacy Markdown.
+func ParseNotificationFormat(raw string) (NotificationFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", "markdown":
+		return NotificationFormatMarkdown, nil
+	case "markdownv2":
+		return NotificationFormatMarkdownV2, nil
+	case "html":
+		return NotificationFormatHTML, nil
+	default:
+		return NotificationFormatMarkdown, fmt.Errorf("unknown notification format %q", raw)
+	}
+}
+
+// MessageFormatter renders notification text for one Telegram parse mode.
+// Every method escapes its argument, so symbols, exchange names and user
+// content can never open or close an entity by accident.
+type MessageFormatter interface {
+	// Format returns the parse mode to send the text with.
+	Format() NotificationFormat
+	// Text escapes plain text.
+	Text(text string) string
+	// Bold renders text in bold.
+	Bold(text string) string
+	// Italic renders text in italics.
+	Italic(text string) string
+	// Code renders text as inline code.
+	Code(text string) string
+	// Pre renders text as a preformatted block.
+	Pre(text string) string
+}
+
+// NewMessageFormatter returns the formatter for a notification format;
+// unknown formats use legacy Markdown.
+func NewMessageFormatter(format NotificationFormat) MessageFormatter {
+	switch format {
+	case NotificationFormatMarkdownV2:
+		return markdownV2Formatter{}
+	case NotificationFormatHTML:
+		return htmlFormatter{}
+	default:
+		return markdownFormatter{}
+	}
+}
+
+// markdownFormatter writes legacy Markdown. Escaping is not allowed inside its
+// entities, so a marker inside an entity closes it, is escaped and reopens it.
+type markdownFormatter struct{}
+
+var markdownEscaper = strings.NewReplacer(`_`, `\_`, `*`, `\*`, "`", "\\`", `[`, `\[`)
+
+func (markdownFormatter) Format() NotificationFormat { return NotificationFormatMarkdown }
+
+func (markdownFormatter) Text(text string) string { return markdownEscaper.Replace(text) }
+
+func (markdownFormatter) Bold(text string) string { return wrapMarkdownEntity("*", text) }
+
+func (markdownFormatter) Italic(text string) string { return wrapMarkdownEntity("_", text) }
+
+func (markdownFormatter) Code(text string) string { return wrapMarkdownEntity("`", text) }
+
+func (markdownFormatter) Pre(text string) string {
+	return "```\n" + strings.ReplaceAll(text, "```", "'''") + "\n```"
+}
+
+func wrapMarkdownEntity(marker, text string) string {
+	return marker + strings.ReplaceAll(text, marker, marker+`\`+marker+marker) + marker
+}
+
+// markdownV2Formatter writes MarkdownV2, where every reserved character
+// outside code has to be escaped.
+type markdownV2Formatter struct{}
+
+var (
+	markdownV2Escaper = strings.NewReplacer(
+		`\`, `\\`, `_`, `\_`, `*`, `\*`, `[`, `\[`, `]`, `\]`, `(`, `\(`, `)`, `\)`, `~`, `\~`, "`", "\\`",
+		`>`, `\>`, `#`, `\#`, `+`, `\+`, `-`, `\-`, `=`, `\=`, `|`, `\|`, `{`, `\{`, `}`, `\}`, `.`, `\.`, `!`, `\!`,
+	)
+	markdownV2CodeEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`")
+)
+
+func (markdownV2Formatter) Format() NotificationFormat { return NotificationFormatMarkdownV2 }
+
+func (markdownV2Formatter) Text(text string) string { return markdownV2Escaper.Replace(text) }
+
+func (f markdownV2Formatter) Bold(text string) string { return "*" + f.Text(text) + "*" }
+
+func (f markdownV2Formatter) Italic(text string) string { return "_" + f.Text(text) + "_" }
+
+func (markdownV2Formatter) Code(text string) string {
+	return "`" + markdownV2CodeEscaper.Replace(text) + "`"
+}
+
+func (markdownV2Formatter) Pre(text string) string {
+	return "```\n" + markdownV2CodeEscaper.Replace(text) + "\n```"
+}
+
+// htmlFormatter writes Telegram's HTML subset.
+type htmlFormatter struct{}
+
+var htmlEscaper = strings.NewReplacer(`&`, `&amp;`, `<`, `&lt;`, `>`, `&gt;`)
+
+func (htmlFormatter) Format() NotificationFormat { return NotificationFormatHTML }
+
+func (htmlFormatter) Text(text string) string { return htmlEscaper.Replace(text) }
+
+func (f htmlFormatter) Bold(text string) string { return "<b>" + f.Text(text) + "</b>" }
+
+func (f htmlFormatter) Italic(text string) string { return "<i>" + f.Text(text) + "</i>" }
+
+func (f htmlFormatter) Code(text string) string { return "<code>" + f.Text(text) + "</code>" }
+
+func (f htmlFormatter) Pre(text string) string { return "<pre>" + f.Text(text) + "</pre>" }
+
+// markupEntity is an entity left open where a message is split, with the
+// markup that closes it and reopens it in the next part.
+type markupEntity struct {
+	open  string
+	close string
+}
+
+// openEntities returns the entities left open at the end of text, outermost first.
+func openEntities(format NotificationFormat, text string) []markupEntity {
+	switch format {
+	case NotificationFormatHTML:
+		return openHTMLEntities(text)
+	case NotificationFormatMarkdownV2:
+		return openMarkdownEntities(text, true)
+	default:
+		return openMarkdownEntities(text, false)
+	}
+}
+
+// openMarkdownEntities scans legacy Markdown or MarkdownV2. Legacy entities
+// cannot nest and only escape outside entities; MarkdownV2 entities nest and
+// backslash escapes work everywhere.
+func openMarkdownEntities(text string, v2 bool) []markupEntity {
+	var open []string
+	top := func() string {
+		if len(open) == 0 {
+			return ""
+		}
+		return open[len(open)-1]
+	}
+	for i := 0; i < len(text); i++ {
+		switch {
+		case top() == "```":
+			if v2 && text[i] == '\\' {
+				i++
+			} else if strings.HasPrefix(text[i:], "```") {
+				open = open[:len(open)-1]
+				i += 2
+			}
+		case top() == "`":
+			if v2 && text[i] == '\\' {
+				i++
+			} else if text[i] == '`' {
+				open = open[:len(open)-1]
+			}
+		case text[i] == '\\' && (v2 || len(open) == 0):
+			i++ // escaped character
+		case strings.HasPrefix(text[i:], "```"):
+			if v2 || len(open) == 0 {
+				open = append(open, "```")
+			}
+			i += 2
+		case text[i] == '`' && (v2 || len(open) == 0):
+			open = append(open, "`")
+		case text[i] == '*' || text[i] == '_' || (v2 && text[i] == '~'):
+			marker := string(text[i])
+			if v2 && strings.HasPrefix(text[i:], "__") {
+				marker = "__"
+				i++
+			}
+			switch {
+			case top() == marker:
+				open = open[:len(open)-1]
+			case len(open) == 0 || v2:
+				open = append(open, marker)
+			}
+		}
+	}
+
+	entities := make([]markupEntity, len(open))
+	for i, marker := range open {
+		if marker == "```" {
+			entities[i] = markupEntity{open: "```\n", close: "\n```"}
+		} else {
+			entities[i] = markupEntity{open: marker, close: marker}
+		}
+	}
+	return entities
+}
+
+var htmlTagPattern = regexp.MustCompile(`<(/?)([a-zA-Z-]+)[^>]*>`)
+
+// openHTMLEntities tracks the stack of open tags.
+func openHTMLEntities(text string) []markupEntity {
+	var open []markupEntity
+	var names []string
+	for _, match := range htmlTagPattern.FindAllStringSubmatch(text, -1) {
+		name := strings.ToLower(match[2])
+		if match[1] == "" {
+			open = append(open, markupEntity{open: match[0], close: "</" + name + ">"})
+			names = append(names, name)
+			continue
+		}
+		for i := len(names) - 1; i >= 0; i-- {
+			if names[i] == name {
+				open, names = open[:i], names[:i]
+				break
+			}
+		}
+	}
+	return open
+}
+
+// formatField renders a "emoji *Label:* value" line.
+func formatField(f MessageFormatter, emoji, label, value string) string {
+	return emoji + " " + f.Bold(label+":") + " " + f.Text(value) + "\n"
+}
+
+// writeTextLine writes a line of escaped plain text.
+func writeTextLine(w *strings.Builder, f MessageFormatter, format string, args ...interface{}) {
+	w.WriteString(f.Text(fmt.Sprintf(format, args...)))
+	w.WriteString("\n")
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the notification golden files")
+
+func TestParseNotificationFormat(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    NotificationFormat
+		wantErr bool
+	}{
+		{raw: "", want: NotificationFormatMarkdown},
+		{raw: "markdown", want: NotificationFormatMarkdown},
+		{raw: " MarkdownV2 ", want: NotificationFormatMarkdownV2},
+		{raw: "html", want: NotificationFormatHTML},
+		{raw: "rtf", want: NotificationFormatMarkdown, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseNotificationFormat(tt.raw)
+		assert.Equal(t, tt.want, got, tt.raw)
+		assert.Equal(t, tt.wantErr, err != nil, tt.raw)
+	}
+}
+
+func TestMessageFormatters_Escape(t *testing.T) {
+	const hostile = "1000_PEPE*[x](y) <b>&`.!"
+	tests := []struct {
+		format NotificationFormat
+		text   string
+		bold   string
+		code   string
+	}{
+		{
+			format: NotificationFormatMarkdown,
+			text:   "1000\\_PEPE\\*\\[x](y) <b>&\\`.!",
+			bold:   "*1000_PEPE*\\**[x](y) <b>&`.!*",
+			code:   "`1000_PEPE*[x](y) <b>&`\\``.!`",
+		},
+		{
+			format: NotificationFormatMarkdownV2,
+			text:   "1000\\_PEPE\\*\\[x\\]\\(y\\) <b\\>&\\`\\.\\!",
+			bold:   "*1000\\_PEPE\\*\\[x\\]\\(y\\) <b\\>&\\`\\.\\!*",
+			code:   "`1000_PEPE*[x](y) <b>&\\`.!`",
+		},
+		{
+			format: NotificationFormatHTML,
+			text:   "1000_PEPE*[x](y) &lt;b&gt;&amp;`.!",
+			bold:   "<b>1000_PEPE*[x](y) &lt;b&gt;&amp;`.!</b>",
+			code:   "<code>1000_PEPE*[x](y) &lt;b&gt;&amp;`.!</code>",
+		},
+	}
+	for _, tt := range tests {
+		f := NewMessageFormatter(tt.format)
+		assert.Equal(t, tt.format, f.Format())
+		assert.Equal(t, tt.text, f.Text(hostile), tt.format)
+		assert.Equal(t, tt.bold, f.Bold(hostile), tt.format)
+		assert.Equal(t, tt.code, f.Code(hostile), tt.format)
+		assert.Empty(t, openEntities(tt.format, f.Text(hostile)+f.Bold(hostile)+f.Italic(hostile)+f.Code(hostile)+f.Pre(hostile)), tt.format)
+	}
+}
+
+// TestNotificationFormatters_Golden renders every notification type in every
+// format from inputs full of reserved characters. Run with -update to
+// rewrite the golden files after an intended change.
+func TestNotificationFormatters_Golden(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	arbitrage := []ArbitrageOpportunity{
+		{Symbol: "1000_PEPE/USDT", BuyExchange: "gate_io", SellExchange: "okx", BuyPrice: 0.0123, SellPrice: 0.0125, ProfitPercent: 1.62, OpportunityType: "arbitrage"},
+		{Symbol: "BTC/USDT", BuyExchange: "binance", SellExchange: "kraken", BuyPrice: 64000, SellPrice: 64500, ProfitPercent: 0.78, OpportunityType: "arbitrage"},
+	}
+	technical := []TechnicalSignalNotification{{
+		Symbol: "ETH_USDT", SignalText: "RSI < 30 & *oversold*", CurrentPrice: 3100.5, EntryRange: "$3,085 - $3,115",
+		Targets:  []Target{{Price: 3200, Profit: 3.2}},
+		StopLoss: StopLoss{Price: 3000, Risk: 3.2}, RiskReward: "1:1", Exchanges: []string{"binance", "gate_io"},
+		Timeframe: "1h", Confidence: 0.82,
+	}}
+	enhanced := &AggregatedSignal{
+		SignalType: SignalTypeArbitrage, Symbol: "1000_PEPE/USDT", Confidence: decimal.RequireFromString("0.9"),
+		Metadata: map[string]interface{}{
+			"buy_price_range":  map[string]interface{}{"min": decimal.RequireFromString("0.0123"), "max": decimal.RequireFromString("0.0124")},
+			"sell_price_range": map[string]interface{}{"min": decimal.RequireFromString("0.0126"), "max": decimal.RequireFromString("0.0126")},
+			"profit_range": map[string]interface{}{
+				"min_percent": decimal.RequireFromString("1.5"), "max_percent": decimal.RequireFromString("2.1"),
+				"min_dollar": decimal.RequireFromString("30"), "max_dollar": decimal.RequireFromString("42"), "base_amount": decimal.RequireFromString("2000"),
+			},
+			"buy_exchanges":     []string{"gate_io"},
+			"sell_exchanges":    []string{"okx", "mexc"},
+			"opportunity_count": 2,
+			"min_volume":        decimal.RequireFromString("10000"),
+			"validity_minutes":  5,
+		},
+	}
+	aggregated := []*AggregatedSignal{{
+		SignalType: SignalTypeArbitrage, Symbol: "1000_PEPE/USDT", Action: "buy", Strength: SignalStrengthStrong,
+		Confidence: decimal.RequireFromString("85"), ProfitPotential: decimal.RequireFromString("1.6"), RiskLevel: decimal.RequireFromString("0.4"),
+		Exchanges: []string{"gate_io", "okx"}, Indicators: []string{"RSI(14)", "MACD_hist"},
+		Metadata: map[string]interface{}{"buy_price": "0.0123", "sell_price": "0.0125", "entry_price": "0.0123", "stop_loss": "0.0118", "target": "0.0131"},
+	}}
+
+	renders := map[string]func(ns *NotificationService) string{
+		"arbitrage":          func(ns *NotificationService) string { return ns.formatArbitrageMessage(arbitrage) },
+		"technical":          func(ns *NotificationService) string { return ns.formatTechnicalSignalMessage(technical) },
+		"enhanced_arbitrage": func(ns *NotificationService) string { return ns.formatEnhancedArbitrageMessage(enhanced) },
+		"aggregated_arbitrage": func(ns *NotificationService) string {
+			return ns.formatAggregatedArbitrageMessage(aggregated)
+		},
+		"aggregated_technical": func(ns *NotificationService) string {
+			return ns.formatAggregatedTechnicalMessage(aggregated)
+		},
+		"quest_progress": func(ns *NotificationService) string {
+			return ns.formatQuestProgressMessage(QuestProgressNotification{QuestName: "Scalp <10> trades ```fast```", Current: 4, Target: 10, Percent: 40, TimeRemaining: "2h"})
+		},
+		"risk_event": func(ns *NotificationService) string {
+			return ns.formatRiskEventMessage(RiskEventNotification{
+				EventType: "max_drawdown", Severity: "high", Message: "Drawdown hit 5% on *futures* <account>",
+				Details: map[string]string{"symbol": "1000_PEPE/USDT", "exchange": "gate_io"},
+			})
+		},
+		"fund_milestone": func(ns *NotificationService) string {
+			return ns.formatFundMilestoneMessage(FundMilestoneNotification{Achievement: "First $1,000 & counting!", CurrentValue: "$1,020", TargetValue: "$2,000", PercentReached: 51})
+		},
+		"ai_reasoning": func(ns *NotificationService) string {
+			return ns.formatAIReasoningMessage(AIReasoningNotification{
+				DecisionType: "open_long", Summary: "Momentum > resistance", Confidence: 0.72,
+				Reasons: []string{"RSI_14 < 30", "Funding (8h) negative"}, Action: "BUY 1000_PEPE",
+			})
+		},
+		"action": func(ns *NotificationService) string {
+			streamer := NewActionStreamer(ns)
+			return streamer.formatActionMessage(StreamingAction{
+				Type: ActionTypeRiskEvent, Priority: PriorityHigh, Status: StatusExecuted, Timestamp: now,
+				Title: "Stop_loss hit on *ETH*", Description: "Closed <1> position",
+				Data: map[string]interface{}{"symbol": "ETH_USDT", "pnl": "-12.5"},
+			})
+		},
+	}
+
+	formats := map[NotificationFormat]string{
+		NotificationFormatMarkdown:   "md",
+		NotificationFormatMarkdownV2: "mdv2",
+		NotificationFormatHTML:       "html",
+	}
+	for format, ext := range formats {
+		ns := &NotificationService{format: format, now: func() time.Time { return now }}
+		for name, render := range renders {
+			t.Run(name+"."+ext, func(t *testing.T) {
+				got := render(ns)
+				assert.Empty(t, openEntities(format, got), "rendered message leaves entities open")
+
+				path := filepath.Join("testdata", "notifications", name+"."+ext+".golden")
+				if *updateGolden {
+					require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+					require.NoError(t, os.WriteFile(path, []byte(got), 0o600))
+				}
+				want, err := os.ReadFile(path)
+				require.NoError(t, err, "run the test with -update to create the golden file")
+				assert.Equal(t, string(want), got)
+			})
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/services/jobqueue"
+)
+
+// Notification priority classes decide how a message is delivered:
+//
+//   - critical (PriorityNotificationEmergency) skips the rate limit, hook
+//     suppression and quiet hours, and jumps the delivery queue;
+//   - high and normal obey the rate limit and hooks;
+//   - low is informational and is held back during quiet hours.
+
+// ParseNotificationPriority returns the priority named by raw; unknown or
+// empty names are normal.
+func ParseNotificationPriority(raw string) NotificationPriority {
+	switch priority := NotificationPriority(strings.ToLower(strings.TrimSpace(raw))); priority {
+	case PriorityNotificationEmergency, PriorityNotificationHigh, PriorityNotificationLow:
+		return priority
+	default:
+		return PriorityNotificationNormal
+	}
+}
+
+// RiskSeverityPriority maps a risk event severity to a notification priority.
+func RiskSeverityPriority(severity string) NotificationPriority {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return PriorityNotificationEmergency
+	case "high":
+		return PriorityNotificationHigh
+	case "low":
+		return PriorityNotificationLow
+	default:
+		return PriorityNotificationNormal
+	}
+}
+
+// notificationJobPriority is the delivery queue lane of a priority.
+func notificationJobPriority(priority NotificationPriority) jobqueue.Priority {
+	switch priority {
+	case PriorityNotificationEmergency:
+		return jobqueue.CRITICAL
+	case PriorityNotificationHigh:
+		return jobqueue.HIGH
+	case PriorityNotificationLow:
+		return jobqueue.LOW
+	default:
+		return jobqueue.NORMAL
+	}
+}
+
+// QuietHours is a daily window in which low priority notifications are held
+// back.
+type QuietHours struct {
+	// Start and End are offsets from midnight; a window with End before
+	// Start spans midnight.
+	Start    time.Duration
+	End      time.Duration
+	Location *time.Location
+}
+
+// ParseQuietHours parses a window such as "22:00-07:00" in the named time
+// zone (UTC when empty).
+//
+// Parameters:
+//
+//	spec: Window as HH:MM-HH:MM.
+//	zone: IANA time zone name.
+//
+// Returns:
+//
+//	*QuietHours: Parsed window.
+//	error: Error if the window or zone is invalid.
+func ParseQuietHours(spec, zone string) (*QuietHours, error) {
+	from, to, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid quiet hours %q: want HH:MM-HH:MM", spec)
+	}
+	start, err := parseClock(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quiet hours %q: %w", spec, err)
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quiet hours %q: %w", spec, err)
+	}
+	if start == end {
+		return nil, fmt.Errorf("invalid quiet hours %q: start and end are equal", spec)
+	}
+	location := time.UTC
+	if zone = strings.TrimSpace(zone); zone != "" {
+		if location, err = time.LoadLocation(zone); err != nil {
+			return nil, fmt.Errorf("invalid quiet hours time zone %q: %w", zone, err)
+		}
+	}
+	return &QuietHours{Start: start, End: end, Location: location}, nil
+}
+
+func parseClock(raw string) (time.Duration, error) {
+	clock, err := time.Parse("15:04", strings.TrimSpace(raw))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", raw)
+	}
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
+
+// Until reports whether t falls in the quiet window and, if so, when the
+// window ends.
+func (q *QuietHours) Until(t time.Time) (time.Time, bool) {
+	if q == nil {
+		return time.Time{}, false
+	}
+	local := t.In(q.Location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, q.Location)
+	offset := local.Sub(midnight)
+
+	if q.Start < q.End {
+		if offset >= q.Start && offset < q.End {
+			return midnight.Add(q.End), true
+		}
+		return time.Time{}, false
+	}
+	// The window spans midnight
+	switch {
+	case offset >= q.Start:
+		return midnight.AddDate(0, 0, 1).Add(q.End), true
+	case offset < q.End:
+		return midnight.Add(q.End), true
+	default:
+		return time.Time{}, false
+	}
+}
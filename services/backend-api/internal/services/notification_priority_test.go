@@ -0,0 +1,124 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuietHours(t *testing.T) {
+	quiet, err := ParseQuietHours("22:00-07:30", "")
+	require.NoError(t, err)
+	at := func(hour, minute int) time.Time { return time.Date(2026, 3, 5, hour, minute, 0, 0, time.UTC) }
+
+	until, ok := quiet.Until(at(23, 15))
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2026, 3, 6, 7, 30, 0, 0, time.UTC), until, "the window spans midnight")
+	until, ok = quiet.Until(at(3, 0))
+	assert.True(t, ok)
+	assert.Equal(t, at(7, 30), until)
+	_, ok = quiet.Until(at(7, 30))
+	assert.False(t, ok, "the end is exclusive")
+	_, ok = quiet.Until(at(12, 0))
+	assert.False(t, ok)
+
+	daytime, err := ParseQuietHours("12:00-13:00", "Asia/Jakarta")
+	require.NoError(t, err)
+	until, ok = daytime.Until(at(5, 30)) // 12:30 in Jakarta
+	assert.True(t, ok)
+	assert.Equal(t, at(6, 0), until.UTC())
+	_, ok = daytime.Until(at(12, 30))
+	assert.False(t, ok)
+
+	var disabled *QuietHours
+	_, ok = disabled.Until(at(23, 0))
+	assert.False(t, ok)
+
+	for _, spec := range []string{"22:00", "25:00-07:00", "08:00-08:00"} {
+		_, err := ParseQuietHours(spec, "")
+		assert.Error(t, err, spec)
+	}
+	_, err = ParseQuietHours("22:00-07:00", "Mars/Olympus")
+	assert.Error(t, err)
+}
+
+func TestRiskSeverityPriority(t *testing.T) {
+	assert.Equal(t, PriorityNotificationEmergency, RiskSeverityPriority("critical"))
+	assert.Equal(t, PriorityNotificationHigh, RiskSeverityPriority("HIGH"))
+	assert.Equal(t, PriorityNotificationNormal, RiskSeverityPriority("medium"))
+	assert.Equal(t, PriorityNotificationLow, RiskSeverityPriority("low"))
+	assert.Equal(t, PriorityNotificationNormal, ParseNotificationPriority("urgent"))
+	assert.Equal(t, PriorityNotificationEmergency, ParseNotificationPriority(" Critical "))
+}
+
+func TestNotificationService_CriticalBypassesRateLimitAndHooks(t *testing.T) {
+	var texts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		texts = append(texts, body["text"].(string))
+		_, _ = w.Write([]byte(`{"ok":true,"messageId":"1"}`))
+	}))
+	defer server.Close()
+
+	var priorities []NotificationPriority
+	registry := NewHookRegistry(HookConfig{})
+	require.NoError(t, registry.Register("digest", &funcHook{preNotify: func(msg HookNotification) (PreNotifyDecision, error) {
+		priorities = append(priorities, msg.Priority)
+		return PreNotifyDecision{Suppress: true, Reason: "digested"}, nil
+	}}))
+	ns := NewNotificationService(nil, nil, server.URL, "", "")
+	ns.SetHooks(registry)
+
+	require.NoError(t, ns.NotifyRiskEvent(t.Context(), 7, RiskEventNotification{EventType: "drawdown", Severity: "medium", Message: "digest me"}))
+	require.NoError(t, ns.NotifyRiskEvent(t.Context(), 7, RiskEventNotification{EventType: "drawdown", Severity: "critical", Message: "halted"}))
+	require.Len(t, texts, 1, "only the critical event gets past the suppressing hook")
+	assert.Contains(t, texts[0], "halted")
+	assert.Equal(t, []NotificationPriority{PriorityNotificationNormal, PriorityNotificationEmergency}, priorities)
+
+	// Without Redis the rate limit fails closed, except for critical messages
+	allowed, err := ns.admitNotification(t.Context(), "u1", PriorityNotificationNormal)
+	assert.Error(t, err)
+	assert.False(t, allowed)
+	allowed, err = ns.admitNotification(t.Context(), "u1", PriorityNotificationEmergency)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+}
+
+func TestNotificationService_QuietHoursDeferLowPriority(t *testing.T) {
+	sent := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		_, _ = w.Write([]byte(`{"ok":true,"messageId":"1"}`))
+	}))
+	defer server.Close()
+
+	quiet, err := ParseQuietHours("22:00-07:00", "")
+	require.NoError(t, err)
+	ns := NewNotificationService(nil, nil, server.URL, "", "")
+	ns.SetQuietHours(quiet)
+	ns.now = func() time.Time { return time.Date(2026, 3, 5, 23, 0, 0, 0, time.UTC) }
+	progress := QuestProgressNotification{QuestID: "q1", QuestName: "Scan", Current: 1, Target: 2, Percent: 50}
+
+	// Without a delivery queue there is nowhere to hold the message
+	require.NoError(t, ns.NotifyQuestProgress(t.Context(), 7, progress))
+	assert.Equal(t, 0, sent)
+
+	queue := ns.EnableDeliveryQueue(newTestJobQueue(t), NotificationDeliveryQueueConfig{})
+	require.NoError(t, ns.NotifyQuestProgress(t.Context(), 7, progress))
+	require.NoError(t, ns.NotifyRiskEvent(t.Context(), 7, RiskEventNotification{EventType: "stop_loss", Severity: "critical", Message: "stopped out"}))
+	assert.Equal(t, 1, sent, "critical messages ignore quiet hours")
+
+	stats, err := queue.Stats(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Scheduled, "the progress update waits for the end of quiet hours")
+
+	ns.now = func() time.Time { return time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC) }
+	require.NoError(t, ns.NotifyQuestProgress(t.Context(), 7, progress))
+	assert.Equal(t, 2, sent)
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// telegramMaxMessageLength is Telegram's limit on the text of one message,
+	// counted in UTF-16 code units.
+	telegramMaxMessageLength = 4096
+	// telegramPartReserve leaves room in each part for the "(i/n)" label and
+	// the Markdown markers that close and reopen an entity cut by the split.
+	telegramPartReserve = 32
+)
+
+// telegramSplitUnit is a piece of a message that is kept whole when possible,
+// with the separator that precedes it.
+type telegramSplitUnit struct {
+	text string
+	sep  string
+}
+
+// splitTelegramMessage chunks text into messages within Telegram's length
+// limit. Sections separated by blank lines are kept together where they fit;
+// longer sections are split between lines, and only lines that are too long
+// on their own are cut mid-line. Markdown entities cut by a split are closed
+// at the end of one part and reopened at the start of the next, and every
+// part of a split message is labelled "(i/n)".
+//
+// Parameters:
+//
+//	text: The formatted message.
+//	limit: Maximum part length in UTF-16 code units; zero or less uses Telegram's limit.
+//	formatter: The formatter the message was written with.
+//
+// Returns:
+//
+//	[]string: The message itself if it fits, otherwise its labelled parts.
+func splitTelegramMessage(text string, limit int, formatter MessageFormatter) []string {
+	if limit <= 0 {
+		limit = telegramMaxMessageLength
+	}
+	if telegramTextLength(text) <= limit {
+		return []string{text}
+	}
+	budget := max(limit-telegramPartReserve, 1)
+
+	format := formatter.Format()
+	chunks := packTelegramUnits(splitTelegramUnits(text, budget, format), budget, format)
+	parts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		parts[i] = chunk + "\n\n" + formatter.Text(fmt.Sprintf("(%d/%d)", i+1, len(chunks)))
+	}
+	return parts
+}
+
+// splitTelegramUnits breaks text into sections, then lines, then runs of
+// runes, stopping at the coarsest level that fits the budget.
+func splitTelegramUnits(text string, budget int, format NotificationFormat) []telegramSplitUnit {
+	var units []telegramSplitUnit
+	for i, section := range strings.Split(text, "\n\n") {
+		sep := "\n\n"
+		if i == 0 {
+			sep = ""
+		}
+		if telegramTextLength(section) <= budget {
+			units = append(units, telegramSplitUnit{text: section, sep: sep})
+			continue
+		}
+		for j, line := range strings.Split(section, "\n") {
+			lineSep := "\n"
+			if j == 0 {
+				lineSep = sep
+			}
+			for k, piece := range cutTelegramLine(line, budget, format) {
+				if k > 0 {
+					lineSep = ""
+				}
+				units = append(units, telegramSplitUnit{text: piece, sep: lineSep})
+			}
+		}
+	}
+	return units
+}
+
+var (
+	// htmlTokenPattern keeps tags and character references whole.
+	htmlTokenPattern = regexp.MustCompile(`(?s)<[^>]*>|&#?[a-zA-Z0-9]+;|.`)
+	// markdownTokenPattern keeps backslash escapes whole.
+	markdownTokenPattern = regexp.MustCompile(`(?s)\\.|.`)
+)
+
+// cutTelegramLine cuts a line into runs of at most budget code units, never
+// inside an escape sequence or HTML tag.
+func cutTelegramLine(line string, budget int, format NotificationFormat) []string {
+	if telegramTextLength(line) <= budget {
+		return []string{line}
+	}
+	tokens := markdownTokenPattern
+	if format == NotificationFormatHTML {
+		tokens = htmlTokenPattern
+	}
+	var pieces []string
+	var current strings.Builder
+	length := 0
+	for _, token := range tokens.FindAllString(line, -1) {
+		size := telegramTextLength(token)
+		if length+size > budget && length > 0 {
+			pieces = append(pieces, current.String())
+			current.Reset()
+			length = 0
+		}
+		current.WriteString(token)
+		length += size
+	}
+	return append(pieces, current.String())
+}
+
+// packTelegramUnits greedily fills parts with units, balancing Markdown
+// entities across part boundaries.
+func packTelegramUnits(units []telegramSplitUnit, budget int, format NotificationFormat) []string {
+	var chunks []string
+	var current strings.Builder
+	length := 0
+	flush := func() {
+		chunk := current.String()
+		current.Reset()
+		length = 0
+		// Close the open entities here and reopen them in the next part
+		open := openEntities(format, chunk)
+		for i := len(open) - 1; i >= 0; i-- {
+			chunk += open[i].close
+		}
+		chunks = append(chunks, chunk)
+		for _, entity := range open {
+			current.WriteString(entity.open)
+			length += telegramTextLength(entity.open)
+		}
+	}
+
+	empty := true
+	for _, unit := range units {
+		size := telegramTextLength(unit.text)
+		if !empty && length+telegramTextLength(unit.sep)+size > budget {
+			flush()
+			empty = true
+		}
+		if !empty {
+			current.WriteString(unit.sep)
+			length += telegramTextLength(unit.sep)
+		}
+		current.WriteString(unit.text)
+		length += size
+		empty = false
+	}
+	if !empty {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
+
+// telegramTextLength returns the length of text as Telegram counts it.
+func telegramTextLength(text string) int {
+	length := 0
+	for _, r := range text {
+		length += telegramRuneLength(r)
+	}
+	return length
+}
+
+func telegramRuneLength(r rune) int {
+	if r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
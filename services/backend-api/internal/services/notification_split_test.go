@@ -0,0 +1,144 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitTelegramMessage_SectionBoundaries(t *testing.T) {
+	assert.Equal(t, []string{"short"}, splitTelegramMessage("short", 0, markdownFormatter{}))
+
+	sections := []string{
+		"*Header*\n" + strings.Repeat("a", 40),
+		strings.Repeat("b", 40),
+		strings.Repeat("c", 40),
+	}
+	parts := splitTelegramMessage(strings.Join(sections, "\n\n"), 130, markdownFormatter{})
+	require.Len(t, parts, 2)
+	assert.Equal(t, sections[0]+"\n\n"+sections[1]+"\n\n(1/2)", parts[0])
+	assert.Equal(t, sections[2]+"\n\n(2/2)", parts[1])
+}
+
+func TestSplitTelegramMessage_LongSectionsAndLines(t *testing.T) {
+	var lines []string
+	for i := range 20 {
+		lines = append(lines, fmt.Sprintf("line %02d %s", i, strings.Repeat("x", 20)))
+	}
+	text := strings.Join(lines, "\n") + "\n\n" + strings.Repeat("🚀", 150)
+
+	parts := splitTelegramMessage(text, 200, markdownFormatter{})
+	require.Greater(t, len(parts), 2)
+	var rebuilt []string
+	for i, part := range parts {
+		assert.LessOrEqual(t, telegramTextLength(part), 200, "part %d", i)
+		label := fmt.Sprintf("\n\n(%d/%d)", i+1, len(parts))
+		require.True(t, strings.HasSuffix(part, label))
+		rebuilt = append(rebuilt, strings.TrimSuffix(part, label))
+	}
+	// Lines are never cut; only the emoji run, which has no breaks, is
+	for _, line := range lines {
+		assert.Contains(t, strings.Join(rebuilt, "\n"), line)
+	}
+	assert.Contains(t, strings.Join(rebuilt, ""), strings.Repeat("🚀", 150))
+}
+
+func TestSplitTelegramMessage_BalancesMarkdown(t *testing.T) {
+	code := "```\n" + strings.Repeat("row of a table\n", 12) + "```"
+	parts := splitTelegramMessage("*Report*\n\n"+code+"\n\n_done_", 150, markdownFormatter{})
+	require.Greater(t, len(parts), 1)
+	for i, part := range parts {
+		assert.Empty(t, openEntities(NotificationFormatMarkdown, part), "part %d leaves an entity open: %q", i, part)
+	}
+	assert.True(t, strings.HasPrefix(parts[1], "```\n"), "the code block reopens in the next part")
+
+	bold := "*" + strings.Repeat("word ", 60) + "*"
+	parts = splitTelegramMessage(bold, 150, markdownFormatter{})
+	require.Greater(t, len(parts), 1)
+	for i, part := range parts {
+		assert.Empty(t, openEntities(NotificationFormatMarkdown, part), "part %d", i)
+		assert.True(t, strings.HasPrefix(part, "*"), "part %d", i)
+	}
+}
+
+func TestSplitTelegramMessage_OtherFormats(t *testing.T) {
+	html := "<b>" + strings.Repeat("bold &amp; ", 30) + "</b>"
+	parts := splitTelegramMessage(html, 150, htmlFormatter{})
+	require.Greater(t, len(parts), 1)
+	for i, part := range parts {
+		assert.Empty(t, openEntities(NotificationFormatHTML, part), "part %d", i)
+		assert.True(t, strings.HasPrefix(part, "<b>"), "part %d", i)
+	}
+
+	v2 := "*" + strings.Repeat("bold\\. ", 40) + "*"
+	parts = splitTelegramMessage(v2, 150, markdownV2Formatter{})
+	require.Greater(t, len(parts), 1)
+	assert.True(t, strings.HasSuffix(parts[0], "\\(1/"+fmt.Sprint(len(parts))+"\\)"), "labels are escaped")
+	for i, part := range parts {
+		assert.Empty(t, openEntities(NotificationFormatMarkdownV2, part), "part %d", i)
+	}
+}
+
+func TestOpenEntities(t *testing.T) {
+	tests := []struct {
+		format NotificationFormat
+		text   string
+		want   []string
+	}{
+		{NotificationFormatMarkdown, "*bold* and _italic_", nil},
+		{NotificationFormatMarkdown, "*bold", []string{"*"}},
+		{NotificationFormatMarkdown, "`code with * and _", []string{"`"}},
+		{NotificationFormatMarkdown, "```\nblock *", []string{"```\n"}},
+		{NotificationFormatMarkdown, "```\nblock\n``` _it", []string{"_"}},
+		{NotificationFormatMarkdown, `escaped \* and \_`, nil},
+		{NotificationFormatMarkdown, "*bold with _ inside*", nil},
+		{NotificationFormatMarkdown, "*a*\\**b*", nil},
+		{NotificationFormatMarkdownV2, "*bold _nested", []string{"*", "_"}},
+		{NotificationFormatMarkdownV2, `*escaped \* star`, []string{"*"}},
+		{NotificationFormatMarkdownV2, "__underline__ ~strike", []string{"~"}},
+		{NotificationFormatHTML, "<b>bold <i>nested</i>", []string{"<b>"}},
+		{NotificationFormatHTML, `<a href="https://x">link`, []string{`<a href="https://x">`}},
+		{NotificationFormatHTML, "<pre>done</pre> &lt;b&gt;", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, entity := range openEntities(tt.format, tt.text) {
+			got = append(got, entity.open)
+		}
+		assert.Equal(t, tt.want, got, "%s: %s", tt.format, tt.text)
+	}
+}
+
+func TestNotificationService_ThreadsLongMessages(t *testing.T) {
+	var requests []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, body)
+		_, _ = fmt.Fprintf(w, `{"ok":true,"messageId":"%d"}`, 100+len(requests))
+	}))
+	defer server.Close()
+
+	ns := NewNotificationService(nil, nil, server.URL, "", "")
+	ns.maxMessageLength = 100
+	markup := &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{{{Text: "Details", CallbackData: "d"}}}}
+	text := strings.Repeat("a", 60) + "\n\n" + strings.Repeat("b", 60) + "\n\n" + strings.Repeat("c", 60)
+
+	result := ns.sendTelegramMessageWithMarkup(t.Context(), 7, text, markup)
+	require.True(t, result.OK)
+	assert.Equal(t, "101", result.MessageID, "the first part identifies the message")
+	require.Len(t, requests, 3)
+	assert.NotContains(t, requests[0], "replyToMessageId")
+	assert.NotContains(t, requests[0], "replyMarkup")
+	assert.Equal(t, "101", requests[1]["replyToMessageId"])
+	assert.NotContains(t, requests[1], "replyMarkup")
+	assert.Equal(t, "101", requests[2]["replyToMessageId"])
+	assert.Contains(t, requests[2], "replyMarkup", "the keyboard stays under the last part")
+	assert.Equal(t, strings.Repeat("c", 60)+"\n\n(3/3)", requests[2]["text"])
+}
@@ -0,0 +1,485 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Built-in operating profiles.
+const (
+	ProfileConservative = "conservative"
+	ProfileBalanced     = "balanced"
+	ProfileAggressive   = "aggressive"
+)
+
+// AIUsageLevel is how much market data each AI trading decision considers.
+type AIUsageLevel string
+
+const (
+	AIUsageLow      AIUsageLevel = "low"
+	AIUsageStandard AIUsageLevel = "standard"
+	AIUsageHigh     AIUsageLevel = "high"
+)
+
+// aiUsagePairs is how many ranked pairs are sent to the AI per cycle at each
+// usage level.
+var aiUsagePairs = map[AIUsageLevel]int{
+	AIUsageLow:      5,
+	AIUsageStandard: 10,
+	AIUsageHigh:     20,
+}
+
+const (
+	operatingProfilesKey = "profiles:chats"
+
+	maxCustomProfiles = 10
+
+	minProfileScanInterval = 60
+	maxProfileScanInterval = 3600
+	maxProfileDailyLossPct = 20
+	maxProfilePositionPct  = 25
+	minProfileConfidence   = 0.5
+	maxProfileConfidence   = 0.95
+)
+
+var (
+	ErrProfileNotFound = errors.New("operating profile not found")
+	ErrProfileInvalid  = errors.New("invalid operating profile")
+	ErrProfileLimit    = errors.New("custom operating profile limit reached")
+)
+
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// OperatingProfile bundles the risk limits, position sizing, scan frequency
+// and AI usage autonomous trading runs with.
+type OperatingProfile struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	BuiltIn     bool   `json:"built_in"`
+	// DailyLossPct is the daily loss, in percent of the day's opening equity,
+	// that halts new entries.
+	DailyLossPct float64 `json:"daily_loss_pct"`
+	// MinConfidence is the AI confidence, between 0 and 1, a trade needs.
+	MinConfidence float64 `json:"min_confidence"`
+	// MaxPositionPct is the largest share, in percent of the capital
+	// allocated to the strategy, a single trade may use.
+	MaxPositionPct float64 `json:"max_position_pct"`
+	// ScanIntervalSeconds is how often trading quests scan the market.
+	ScanIntervalSeconds int          `json:"scan_interval_seconds"`
+	AIUsage             AIUsageLevel `json:"ai_usage"`
+}
+
+// ScanInterval returns how often trading quests scan the market.
+func (p OperatingProfile) ScanInterval() time.Duration {
+	return time.Duration(p.ScanIntervalSeconds) * time.Second
+}
+
+// ScalpingLimits returns the profile's limits for one scalping cycle.
+func (p OperatingProfile) ScalpingLimits() ScalpingLimits {
+	return ScalpingLimits{
+		MinConfidence: p.MinConfidence,
+		MaxCapitalPct: p.MaxPositionPct,
+		MaxPairs:      aiUsagePairs[p.AIUsage],
+	}
+}
+
+func (p OperatingProfile) validate() error {
+	switch {
+	case !profileNamePattern.MatchString(p.Name):
+		return fmt.Errorf("%w: name must be 1-32 lowercase letters, digits, - or _", ErrProfileInvalid)
+	case p.DailyLossPct <= 0 || p.DailyLossPct > maxProfileDailyLossPct:
+		return fmt.Errorf("%w: daily_loss_pct must be above 0 and at most %d", ErrProfileInvalid, maxProfileDailyLossPct)
+	case p.MinConfidence < minProfileConfidence || p.MinConfidence > maxProfileConfidence:
+		return fmt.Errorf("%w: min_confidence must be between %.2f and %.2f", ErrProfileInvalid, minProfileConfidence, maxProfileConfidence)
+	case p.MaxPositionPct <= 0 || p.MaxPositionPct > maxProfilePositionPct:
+		return fmt.Errorf("%w: max_position_pct must be above 0 and at most %d", ErrProfileInvalid, maxProfilePositionPct)
+	case p.ScanIntervalSeconds < minProfileScanInterval || p.ScanIntervalSeconds > maxProfileScanInterval:
+		return fmt.Errorf("%w: scan_interval_seconds must be between %d and %d", ErrProfileInvalid, minProfileScanInterval, maxProfileScanInterval)
+	}
+	if _, ok := aiUsagePairs[p.AIUsage]; !ok {
+		return fmt.Errorf("%w: ai_usage must be low, standard or high", ErrProfileInvalid)
+	}
+	return nil
+}
+
+// builtInProfiles are available to every chat, in display order.
+var builtInProfiles = []OperatingProfile{
+	{
+		Name:                ProfileConservative,
+		Description:         "Small positions, high-confidence trades only, scans every 5 minutes",
+		BuiltIn:             true,
+		DailyLossPct:        1,
+		MinConfidence:       0.8,
+		MaxPositionPct:      2.5,
+		ScanIntervalSeconds: 300,
+		AIUsage:             AIUsageLow,
+	},
+	{
+		Name:                ProfileBalanced,
+		Description:         "Moderate positions and confidence, scans every 2 minutes",
+		BuiltIn:             true,
+		DailyLossPct:        2,
+		MinConfidence:       0.7,
+		MaxPositionPct:      5,
+		ScanIntervalSeconds: 120,
+		AIUsage:             AIUsageStandard,
+	},
+	{
+		Name:                ProfileAggressive,
+		Description:         "Larger positions, lower confidence bar, scans every minute across more pairs",
+		BuiltIn:             true,
+		DailyLossPct:        4,
+		MinConfidence:       0.6,
+		MaxPositionPct:      10,
+		ScanIntervalSeconds: 60,
+		AIUsage:             AIUsageHigh,
+	},
+}
+
+func builtInProfile(name string) (OperatingProfile, bool) {
+	for _, profile := range builtInProfiles {
+		if profile.Name == name {
+			return profile, true
+		}
+	}
+	return OperatingProfile{}, false
+}
+
+// ChatProfiles is a chat's selected profile and its custom profiles.
+type ChatProfiles struct {
+	ChatID string `json:"chat_id"`
+	// Active is the selected profile; empty when the server defaults apply.
+	Active    string                      `json:"active"`
+	Custom    map[string]OperatingProfile `json:"custom"`
+	UpdatedAt time.Time                   `json:"updated_at"`
+}
+
+// ProfileList is every profile a chat can select.
+type ProfileList struct {
+	// Active is the selected profile; empty when the server defaults apply.
+	Active   string             `json:"active"`
+	Profiles []OperatingProfile `json:"profiles"`
+}
+
+// OperatingProfileSource returns the profile a chat trades with.
+type OperatingProfileSource interface {
+	// ActiveProfile returns the chat's selected profile; ok is false when
+	// the chat has not selected one and the server defaults apply.
+	ActiveProfile(ctx context.Context, chatID string) (profile *OperatingProfile, ok bool, err error)
+}
+
+// OperatingProfileService stores each chat's selected and custom operating
+// profiles in Redis and applies them to scan intervals and loss caps.
+type OperatingProfileService struct {
+	redis *redis.Client
+	now   func() time.Time
+	// mu serializes read-modify-write cycles of a chat's profiles.
+	mu sync.Mutex
+	// active caches each chat's selected profile for the quest scheduler,
+	// which must not block on Redis.
+	activeMu sync.RWMutex
+	active   map[string]OperatingProfile
+}
+
+// Ensure OperatingProfileService implements its consumers' interfaces.
+var (
+	_ OperatingProfileSource = (*OperatingProfileService)(nil)
+	_ QuestIntervalSource    = (*OperatingProfileService)(nil)
+	_ DailyLossLimitSource   = (*OperatingProfileService)(nil)
+)
+
+// NewOperatingProfileService creates an operating profile service.
+//
+// Parameters:
+//
+//	client: Redis client used to persist each chat's profiles.
+//
+// Returns:
+//
+//	*OperatingProfileService: Initialized service; call Load to warm the scheduler cache.
+func NewOperatingProfileService(client *redis.Client) *OperatingProfileService {
+	return &OperatingProfileService{
+		redis:  client,
+		now:    time.Now,
+		active: make(map[string]OperatingProfile),
+	}
+}
+
+// Load caches every chat's selected profile so scan intervals apply from
+// the first scheduler tick after a restart.
+func (s *OperatingProfileService) Load(ctx context.Context) error {
+	raw, err := s.redis.HGetAll(ctx, operatingProfilesKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to load operating profiles: %w", err)
+	}
+	for chatID, value := range raw {
+		var profiles ChatProfiles
+		if err := json.Unmarshal([]byte(value), &profiles); err != nil {
+			return fmt.Errorf("failed to decode operating profiles of chat %s: %w", chatID, err)
+		}
+		s.cache(chatID, &profiles)
+	}
+	return nil
+}
+
+// List returns the built-in and custom profiles a chat can select.
+func (s *OperatingProfileService) List(ctx context.Context, chatID string) (*ProfileList, error) {
+	profiles, err := s.load(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	list := &ProfileList{Active: profiles.Active, Profiles: append([]OperatingProfile{}, builtInProfiles...)}
+	names := make([]string, 0, len(profiles.Custom))
+	for name := range profiles.Custom {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		list.Profiles = append(list.Profiles, profiles.Custom[name])
+	}
+	return list, nil
+}
+
+// Resolve returns a built-in or custom profile of the chat by name.
+//
+// Returns:
+//
+//	*OperatingProfile: The profile.
+//	error: ErrProfileNotFound, or a persistence error.
+func (s *OperatingProfileService) Resolve(ctx context.Context, chatID, name string) (*OperatingProfile, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if profile, ok := builtInProfile(name); ok {
+		return &profile, nil
+	}
+	profiles, err := s.load(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	profile, ok := profiles.Custom[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+	return &profile, nil
+}
+
+// Select makes a profile the one the chat trades with.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	chatID: Telegram chat ID.
+//	name: A built-in profile or one of the chat's custom profiles.
+//
+// Returns:
+//
+//	*OperatingProfile: The selected profile.
+//	error: ErrProfileNotFound, or a persistence error.
+func (s *OperatingProfileService) Select(ctx context.Context, chatID, name string) (*OperatingProfile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	profile, err := s.Resolve(ctx, chatID, name)
+	if err != nil {
+		return nil, err
+	}
+	profiles, err := s.load(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	profiles.Active = profile.Name
+	if err := s.save(ctx, profiles); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// Save creates or replaces a custom profile of the chat. Fields left at zero
+// are taken from the base profile, so a custom profile can change only what
+// differs from a built-in one.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	chatID: Telegram chat ID.
+//	base: The built-in or custom profile to start from; empty means balanced.
+//	profile: The custom profile; its name may not be a built-in one.
+//
+// Returns:
+//
+//	*OperatingProfile: The stored profile.
+//	error: ErrProfileInvalid, ErrProfileNotFound for an unknown base, ErrProfileLimit, or a persistence error.
+func (s *OperatingProfileService) Save(ctx context.Context, chatID, base string, profile OperatingProfile) (*OperatingProfile, error) {
+	profile.Name = strings.ToLower(strings.TrimSpace(profile.Name))
+	if _, ok := builtInProfile(profile.Name); ok {
+		return nil, fmt.Errorf("%w: %s is a built-in profile", ErrProfileInvalid, profile.Name)
+	}
+	if strings.TrimSpace(base) == "" {
+		base = ProfileBalanced
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defaults, err := s.Resolve(ctx, chatID, base)
+	if err != nil {
+		return nil, err
+	}
+	if profile.DailyLossPct == 0 {
+		profile.DailyLossPct = defaults.DailyLossPct
+	}
+	if profile.MinConfidence == 0 {
+		profile.MinConfidence = defaults.MinConfidence
+	}
+	if profile.MaxPositionPct == 0 {
+		profile.MaxPositionPct = defaults.MaxPositionPct
+	}
+	if profile.ScanIntervalSeconds == 0 {
+		profile.ScanIntervalSeconds = defaults.ScanIntervalSeconds
+	}
+	if profile.AIUsage == "" {
+		profile.AIUsage = defaults.AIUsage
+	}
+	profile.AIUsage = AIUsageLevel(strings.ToLower(string(profile.AIUsage)))
+	profile.BuiltIn = false
+	if err := profile.validate(); err != nil {
+		return nil, err
+	}
+
+	profiles, err := s.load(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := profiles.Custom[profile.Name]; !exists && len(profiles.Custom) >= maxCustomProfiles {
+		return nil, fmt.Errorf("%w: at most %d per chat", ErrProfileLimit, maxCustomProfiles)
+	}
+	profiles.Custom[profile.Name] = profile
+	if err := s.save(ctx, profiles); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// Delete removes a custom profile of the chat. Deleting the selected profile
+// returns the chat to the server defaults.
+func (s *OperatingProfileService) Delete(ctx context.Context, chatID, name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if _, ok := builtInProfile(name); ok {
+		return fmt.Errorf("%w: %s is a built-in profile", ErrProfileInvalid, name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	profiles, err := s.load(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	if _, ok := profiles.Custom[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+	delete(profiles.Custom, name)
+	if profiles.Active == name {
+		profiles.Active = ""
+	}
+	return s.save(ctx, profiles)
+}
+
+// ActiveProfile implements OperatingProfileSource.
+func (s *OperatingProfileService) ActiveProfile(ctx context.Context, chatID string) (*OperatingProfile, bool, error) {
+	profiles, err := s.load(ctx, chatID)
+	if err != nil {
+		return nil, false, err
+	}
+	profile, ok := profiles.active()
+	if !ok {
+		return nil, false, nil
+	}
+	return &profile, true, nil
+}
+
+// QuestInterval implements QuestIntervalSource from the cached profiles.
+func (s *OperatingProfileService) QuestInterval(chatID string, base time.Duration) time.Duration {
+	s.activeMu.RLock()
+	profile, ok := s.active[chatID]
+	s.activeMu.RUnlock()
+	if !ok {
+		return base
+	}
+	return profile.ScanInterval()
+}
+
+// DailyLossPct implements DailyLossLimitSource.
+func (s *OperatingProfileService) DailyLossPct(ctx context.Context, chatID string) (float64, bool) {
+	profile, ok, err := s.ActiveProfile(ctx, chatID)
+	if err != nil || !ok {
+		return 0, false
+	}
+	return profile.DailyLossPct / 100, true
+}
+
+// active returns the selected profile, which may have been deleted or be a
+// built-in one.
+func (p *ChatProfiles) active() (OperatingProfile, bool) {
+	if p.Active == "" {
+		return OperatingProfile{}, false
+	}
+	if profile, ok := builtInProfile(p.Active); ok {
+		return profile, true
+	}
+	profile, ok := p.Custom[p.Active]
+	return profile, ok
+}
+
+func (s *OperatingProfileService) load(ctx context.Context, chatID string) (*ChatProfiles, error) {
+	raw, err := s.redis.HGet(ctx, operatingProfilesKey, chatID).Result()
+	if errors.Is(err, redis.Nil) {
+		return &ChatProfiles{ChatID: chatID, Custom: map[string]OperatingProfile{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load operating profiles: %w", err)
+	}
+	var profiles ChatProfiles
+	if err := json.Unmarshal([]byte(raw), &profiles); err != nil {
+		return nil, fmt.Errorf("failed to decode operating profiles: %w", err)
+	}
+	if profiles.Custom == nil {
+		profiles.Custom = map[string]OperatingProfile{}
+	}
+	return &profiles, nil
+}
+
+func (s *OperatingProfileService) save(ctx context.Context, profiles *ChatProfiles) error {
+	profiles.UpdatedAt = s.now().UTC()
+	raw, err := json.Marshal(profiles)
+	if err != nil {
+		return fmt.Errorf("failed to encode operating profiles: %w", err)
+	}
+	if err := s.redis.HSet(ctx, operatingProfilesKey, profiles.ChatID, raw).Err(); err != nil {
+		return fmt.Errorf("failed to save operating profiles: %w", err)
+	}
+	s.cache(profiles.ChatID, profiles)
+	return nil
+}
+
+// SelectedCount returns how many chats trade with a selected profile.
+func (s *OperatingProfileService) SelectedCount() int {
+	s.activeMu.RLock()
+	defer s.activeMu.RUnlock()
+	return len(s.active)
+}
+
+func (s *OperatingProfileService) cache(chatID string, profiles *ChatProfiles) {
+	s.activeMu.Lock()
+	defer s.activeMu.Unlock()
+	if profile, ok := profiles.active(); ok {
+		s.active[chatID] = profile
+	} else {
+		delete(s.active, chatID)
+	}
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOperatingProfileService(t *testing.T) (*OperatingProfileService, *redis.Client) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewOperatingProfileService(client), client
+}
+
+func TestOperatingProfileService_CustomProfiles(t *testing.T) {
+	service, client := newTestOperatingProfileService(t)
+	ctx := t.Context()
+
+	_, selected, err := service.ActiveProfile(ctx, "42")
+	require.NoError(t, err)
+	assert.False(t, selected, "the server defaults apply until a profile is selected")
+
+	profile, err := service.Save(ctx, "42", ProfileAggressive, OperatingProfile{Name: " Swing ", ScanIntervalSeconds: 600, AIUsage: "LOW"})
+	require.NoError(t, err)
+	assert.Equal(t, "swing", profile.Name)
+	assert.Equal(t, 4.0, profile.DailyLossPct, "unset fields come from the base")
+	assert.Equal(t, AIUsageLow, profile.AIUsage)
+	assert.Equal(t, ScalpingLimits{MinConfidence: 0.6, MaxCapitalPct: 10, MaxPairs: 5}, profile.ScalpingLimits())
+
+	_, err = service.Select(ctx, "42", "swing")
+	require.NoError(t, err)
+	list, err := service.List(ctx, "42")
+	require.NoError(t, err)
+	assert.Equal(t, "swing", list.Active)
+	require.Len(t, list.Profiles, 4)
+	assert.Equal(t, ProfileConservative, list.Profiles[0].Name)
+	assert.Equal(t, 10*time.Minute, service.QuestInterval("42", time.Minute))
+	pct, ok := service.DailyLossPct(ctx, "42")
+	assert.True(t, ok)
+	assert.InDelta(t, 0.04, pct, 1e-9)
+
+	// Other chats neither see the profile nor its cadence
+	_, err = service.Resolve(ctx, "7", "swing")
+	assert.ErrorIs(t, err, ErrProfileNotFound)
+	assert.Equal(t, time.Minute, service.QuestInterval("7", time.Minute))
+
+	// A restarted service serves the cadence once loaded
+	restarted := NewOperatingProfileService(client)
+	assert.Equal(t, time.Minute, restarted.QuestInterval("42", time.Minute))
+	require.NoError(t, restarted.Load(ctx))
+	assert.Equal(t, 10*time.Minute, restarted.QuestInterval("42", time.Minute))
+
+	require.NoError(t, service.Delete(ctx, "42", "swing"))
+	_, selected, err = service.ActiveProfile(ctx, "42")
+	require.NoError(t, err)
+	assert.False(t, selected, "deleting the selected profile returns to the defaults")
+	assert.Equal(t, time.Minute, service.QuestInterval("42", time.Minute))
+}
+
+func TestOperatingProfileService_Validation(t *testing.T) {
+	service, _ := newTestOperatingProfileService(t)
+	ctx := t.Context()
+
+	_, err := service.Save(ctx, "42", "", OperatingProfile{Name: ProfileBalanced})
+	assert.ErrorIs(t, err, ErrProfileInvalid)
+	_, err = service.Save(ctx, "42", "", OperatingProfile{Name: "fast", ScanIntervalSeconds: 10})
+	assert.ErrorIs(t, err, ErrProfileInvalid)
+	_, err = service.Save(ctx, "42", "", OperatingProfile{Name: "sure", MinConfidence: 0.99})
+	assert.ErrorIs(t, err, ErrProfileInvalid)
+	_, err = service.Save(ctx, "42", "", OperatingProfile{Name: "max", AIUsage: "unlimited"})
+	assert.ErrorIs(t, err, ErrProfileInvalid)
+	_, err = service.Save(ctx, "42", "missing", OperatingProfile{Name: "copy"})
+	assert.ErrorIs(t, err, ErrProfileNotFound)
+	assert.ErrorIs(t, service.Delete(ctx, "42", ProfileConservative), ErrProfileInvalid)
+
+	for i := range maxCustomProfiles {
+		_, err = service.Save(ctx, "42", "", OperatingProfile{Name: string(rune('a' + i))})
+		require.NoError(t, err)
+	}
+	_, err = service.Save(ctx, "42", "", OperatingProfile{Name: "one-more"})
+	assert.ErrorIs(t, err, ErrProfileLimit)
+	_, err = service.Save(ctx, "42", "", OperatingProfile{Name: "a", DailyLossPct: 3})
+	assert.NoError(t, err, "replacing a profile does not count against the limit")
+}
+
+func TestOperatingProfileService_AppliesToSchedulerAndLossCap(t *testing.T) {
+	service, _ := newTestOperatingProfileService(t)
+	_, err := service.Select(t.Context(), "42", ProfileConservative)
+	require.NoError(t, err)
+
+	engine := NewQuestEngine(nil)
+	engine.SetScanIntervals(service)
+	now := time.Now()
+	ranAgo := now.Add(-2 * time.Minute)
+	quest := &Quest{ID: "q1", Cadence: CadenceMicro, LastExecutedAt: &ranAgo, Metadata: map[string]string{"chat_id": "42"}}
+	assert.False(t, engine.shouldExecute(quest, now), "conservative scans every 5 minutes")
+	ranAgo = now.Add(-5 * time.Minute)
+	assert.True(t, engine.shouldExecute(quest, now))
+
+	circuit, _, _ := newTestDailyLossCircuit(t, &fakeEquitySource{equity: decimal.NewFromInt(1000)}, DailyLossConfig{})
+	circuit.SetLimitSource(service)
+	state, err := circuit.Evaluate(t.Context(), "42")
+	require.NoError(t, err)
+	assert.True(t, state.Limit.Equal(decimal.NewFromInt(10)), "conservative caps the daily loss at 1%")
+	state, err = circuit.Evaluate(t.Context(), "7")
+	require.NoError(t, err)
+	assert.True(t, state.Limit.Equal(decimal.NewFromInt(20)), "other chats keep the configured 2%")
+}
@@ -0,0 +1,466 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/irfndi/neuratrade/internal/models"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+)
+
+// Opportunity lifecycle states.
+const (
+	// OpportunityStateDetected is an edge seen by the arbitrage scan.
+	OpportunityStateDetected = "detected"
+	// OpportunityStateNotified is an open edge that was alerted to a user.
+	OpportunityStateNotified = "notified"
+	// OpportunityStateExecuted is an edge a user executed while it was open.
+	OpportunityStateExecuted = "executed"
+	// OpportunityStateExpired is an edge that disappeared from the scan
+	// before it was executed.
+	OpportunityStateExpired = "expired"
+)
+
+// OpportunityLifecycleConfig configures opportunity lifecycle persistence.
+type OpportunityLifecycleConfig struct {
+	// Retention is how long closed lifecycles are kept.
+	Retention time.Duration
+}
+
+// OpportunityLifecycle is one continuous sighting of a symbol's buy/sell
+// route, from first detection until it was executed or expired.
+type OpportunityLifecycle struct {
+	ID                    string     `json:"id"`
+	Symbol                string     `json:"symbol"`
+	BuyExchange           string     `json:"buy_exchange"`
+	SellExchange          string     `json:"sell_exchange"`
+	State                 string     `json:"state"`
+	DetectedAt            time.Time  `json:"detected_at"`
+	LastSeenAt            time.Time  `json:"last_seen_at"`
+	NotifiedAt            *time.Time `json:"notified_at,omitempty"`
+	ExecutedAt            *time.Time `json:"executed_at,omitempty"`
+	ClosedAt              *time.Time `json:"closed_at,omitempty"`
+	DurationSeconds       float64    `json:"duration_seconds"`
+	DetectedProfitPercent float64    `json:"detected_profit_percent"`
+	PeakProfitPercent     float64    `json:"peak_profit_percent"`
+	LastProfitPercent     float64    `json:"last_profit_percent"`
+	RealizedProfitPercent *float64   `json:"realized_profit_percent,omitempty"`
+	// CaptureRate is the realized profit as a fraction of the profit first
+	// detected.
+	CaptureRate *float64 `json:"capture_rate,omitempty"`
+}
+
+// OpportunityLifecycleFilter selects lifecycles for the history.
+type OpportunityLifecycleFilter struct {
+	// Since is the earliest detection time returned.
+	Since  time.Time
+	Symbol string
+	State  string
+	// Limit caps the lifecycles returned; the summary covers all matches.
+	Limit int
+}
+
+// OpportunityLifecycleSummary aggregates the lifecycles matching a filter.
+type OpportunityLifecycleSummary struct {
+	Total    int `json:"total"`
+	Open     int `json:"open"`
+	Notified int `json:"notified"`
+	Executed int `json:"executed"`
+	Expired  int `json:"expired"`
+	// AvgDurationSeconds is how long closed edges lasted on average.
+	AvgDurationSeconds float64 `json:"avg_duration_seconds"`
+	// AvgCaptureRate averages the capture rate of executed edges.
+	AvgCaptureRate float64 `json:"avg_capture_rate"`
+	// CapturedRatio is the share of closed edges that were executed before
+	// they expired.
+	CapturedRatio float64 `json:"captured_ratio"`
+}
+
+// openOpportunity is an open lifecycle tracked by the detecting scan.
+type openOpportunity struct {
+	id         string
+	detectedAt time.Time
+}
+
+// OpportunityLifecycleService persists arbitrage opportunities with their
+// lifecycle. The arbitrage scan opens and expires lifecycles each cycle,
+// notifications and executions advance them, and the history is served to
+// operators to judge how many detected edges were actually capturable.
+type OpportunityLifecycleService struct {
+	db        DBPool
+	config    OpportunityLifecycleConfig
+	logger    *slog.Logger
+	now       func() time.Time
+	mu        sync.Mutex
+	open      map[string]openOpportunity
+	loaded    bool
+	lastPrune time.Time
+}
+
+// NewOpportunityLifecycleService creates an opportunity lifecycle service.
+//
+// Parameters:
+//
+//	db: Database pool holding the opportunity_lifecycles table.
+//	config: Retention; the zero value keeps closed lifecycles for 30 days.
+//
+// Returns:
+//
+//	*OpportunityLifecycleService: Initialized service.
+func NewOpportunityLifecycleService(db DBPool, config OpportunityLifecycleConfig) *OpportunityLifecycleService {
+	if config.Retention <= 0 {
+		config.Retention = 30 * 24 * time.Hour
+	}
+	return &OpportunityLifecycleService{
+		db:     db,
+		config: config,
+		logger: telemetry.Logger(),
+		now:    time.Now,
+		open:   make(map[string]openOpportunity),
+	}
+}
+
+// ObserveCycle records the opportunities found by one arbitrage scan. Routes
+// seen for the first time open a lifecycle, routes still present update
+// their profit, and open routes missing from the scan expire.
+//
+// Parameters:
+//
+//	ctx: Context for the writes.
+//	opportunities: All opportunities of the cycle.
+//
+// Returns:
+//
+//	error: Error if a lifecycle could not be written.
+func (s *OpportunityLifecycleService) ObserveCycle(ctx context.Context, opportunities []models.ArbitrageOpportunity) error {
+	if isNilDBPool(s.db) {
+		return fmt.Errorf("database pool is not available")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.loaded {
+		if err := s.loadOpen(ctx); err != nil {
+			return err
+		}
+		s.loaded = true
+	}
+	now := s.now().UTC()
+
+	seen := make(map[string]models.ArbitrageOpportunity, len(opportunities))
+	for _, opp := range opportunities {
+		if opp.TradingPair == nil || opp.BuyExchange == nil || opp.SellExchange == nil {
+			continue
+		}
+		key := opportunityRouteKey(opp.TradingPair.Symbol, opp.BuyExchange.Name, opp.SellExchange.Name)
+		if current, ok := seen[key]; !ok || opp.ProfitPercentage.GreaterThan(current.ProfitPercentage) {
+			seen[key] = opp
+		}
+	}
+
+	for key, opp := range seen {
+		profit := opp.ProfitPercentage.InexactFloat64()
+		if open, ok := s.open[key]; ok {
+			result, err := s.db.Exec(ctx, `
+				UPDATE opportunity_lifecycles SET
+					last_seen_at = $1,
+					last_profit_pct = $2,
+					peak_profit_pct = CASE WHEN $2 > peak_profit_pct THEN $2 ELSE peak_profit_pct END
+				WHERE id = $3 AND closed_at IS NULL`,
+				now, profit, open.id)
+			if err != nil {
+				return fmt.Errorf("failed to update opportunity lifecycle: %w", err)
+			}
+			if affected, err := result.RowsAffected(); err == nil && affected > 0 {
+				continue
+			}
+			// Closed by an execution since the last cycle; the edge is
+			// still there, so it starts a new lifecycle.
+		}
+		id := uuid.New().String()
+		if _, err := s.db.Exec(ctx, `
+			INSERT INTO opportunity_lifecycles (
+				id, symbol, buy_exchange, sell_exchange, state, detected_at, last_seen_at,
+				detected_profit_pct, peak_profit_pct, last_profit_pct
+			) VALUES ($1, $2, $3, $4, $5, $6, $6, $7, $7, $7)`,
+			id, opp.TradingPair.Symbol, opp.BuyExchange.Name, opp.SellExchange.Name, OpportunityStateDetected, now, profit); err != nil {
+			return fmt.Errorf("failed to insert opportunity lifecycle: %w", err)
+		}
+		s.open[key] = openOpportunity{id: id, detectedAt: now}
+	}
+
+	for key, open := range s.open {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		if _, err := s.db.Exec(ctx, `
+			UPDATE opportunity_lifecycles SET state = $1, closed_at = $2, duration_seconds = $3
+			WHERE id = $4 AND closed_at IS NULL`,
+			OpportunityStateExpired, now, now.Sub(open.detectedAt).Seconds(), open.id); err != nil {
+			return fmt.Errorf("failed to expire opportunity lifecycle: %w", err)
+		}
+		delete(s.open, key)
+	}
+
+	if now.Sub(s.lastPrune) >= time.Hour {
+		if err := s.Prune(ctx); err != nil {
+			s.logger.Warn("Failed to prune opportunity lifecycles", "error", err)
+		}
+		s.lastPrune = now
+	}
+	return nil
+}
+
+// loadOpen picks up the lifecycles left open by a previous run.
+func (s *OpportunityLifecycleService) loadOpen(ctx context.Context) error {
+	rows, err := s.db.Query(ctx, `
+		SELECT id, symbol, buy_exchange, sell_exchange, detected_at
+		FROM opportunity_lifecycles
+		WHERE closed_at IS NULL`)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load open opportunity lifecycles: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var open openOpportunity
+		var symbol, buyExchange, sellExchange string
+		if err := rows.Scan(&open.id, &symbol, &buyExchange, &sellExchange, &open.detectedAt); err != nil {
+			return fmt.Errorf("failed to scan open opportunity lifecycle: %w", err)
+		}
+		s.open[opportunityRouteKey(symbol, buyExchange, sellExchange)] = open
+	}
+	return rows.Err()
+}
+
+// MarkNotified moves the open lifecycles of alerted opportunities to
+// notified. Opportunities without a buy/sell route are ignored.
+//
+// Parameters:
+//
+//	ctx: Context for the writes.
+//	opportunities: Opportunities included in an alert.
+//
+// Returns:
+//
+//	error: Error if a lifecycle could not be updated.
+func (s *OpportunityLifecycleService) MarkNotified(ctx context.Context, opportunities []ArbitrageOpportunity) error {
+	if isNilDBPool(s.db) {
+		return fmt.Errorf("database pool is not available")
+	}
+	now := s.now().UTC()
+	for _, opp := range opportunities {
+		if opp.BuyExchange == "" || opp.SellExchange == "" {
+			continue
+		}
+		if _, err := s.db.Exec(ctx, `
+			UPDATE opportunity_lifecycles SET state = $1, notified_at = $2
+			WHERE symbol = $3 AND buy_exchange = $4 AND sell_exchange = $5
+				AND closed_at IS NULL AND state = $6`,
+			OpportunityStateNotified, now, opp.Symbol, opp.BuyExchange, opp.SellExchange, OpportunityStateDetected); err != nil {
+			return fmt.Errorf("failed to mark opportunity notified: %w", err)
+		}
+	}
+	return nil
+}
+
+// MarkExecuted closes the lifecycle of an executed opportunity. When the
+// edge already expired, the latest expired lifecycle records the late
+// execution with nothing captured unless a realized profit is given.
+//
+// Parameters:
+//
+//	ctx: Context for the writes.
+//	opp: Executed opportunity.
+//	realizedProfitPercent: Profit the execution captured, or nil to use the
+//	profit still observed at execution time.
+//
+// Returns:
+//
+//	error: Error if the lifecycle could not be updated.
+func (s *OpportunityLifecycleService) MarkExecuted(ctx context.Context, opp ArbitrageOpportunity, realizedProfitPercent *float64) error {
+	if isNilDBPool(s.db) {
+		return fmt.Errorf("database pool is not available")
+	}
+	now := s.now().UTC()
+
+	var id, state string
+	var detectedAt time.Time
+	var closedAt *time.Time
+	var detectedProfit, lastProfit float64
+	err := s.db.QueryRow(ctx, `
+		SELECT id, state, detected_at, closed_at, detected_profit_pct, last_profit_pct
+		FROM opportunity_lifecycles
+		WHERE symbol = $1 AND buy_exchange = $2 AND sell_exchange = $3
+		ORDER BY detected_at DESC
+		LIMIT 1`,
+		opp.Symbol, opp.BuyExchange, opp.SellExchange).
+		Scan(&id, &state, &detectedAt, &closedAt, &detectedProfit, &lastProfit)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find opportunity lifecycle: %w", err)
+	}
+	if state == OpportunityStateExecuted {
+		return nil
+	}
+
+	realized := lastProfit
+	if closedAt != nil {
+		realized = 0
+	}
+	if realizedProfitPercent != nil {
+		realized = *realizedProfitPercent
+	}
+	var captureRate *float64
+	if detectedProfit > 0 {
+		rate := realized / detectedProfit
+		captureRate = &rate
+	}
+
+	if closedAt != nil {
+		_, err = s.db.Exec(ctx, `
+			UPDATE opportunity_lifecycles SET executed_at = $1, realized_profit_pct = $2, capture_rate = $3
+			WHERE id = $4`,
+			now, realized, captureRate, id)
+	} else {
+		_, err = s.db.Exec(ctx, `
+			UPDATE opportunity_lifecycles SET
+				state = $1, executed_at = $2, closed_at = $2, duration_seconds = $3,
+				realized_profit_pct = $4, capture_rate = $5
+			WHERE id = $6`,
+			OpportunityStateExecuted, now, now.Sub(detectedAt).Seconds(), realized, captureRate, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to mark opportunity executed: %w", err)
+	}
+	return nil
+}
+
+// History returns the lifecycles matching the filter, newest first, with a
+// summary over every match.
+//
+// Parameters:
+//
+//	ctx: Context for the queries.
+//	filter: Detection window, symbol, state and limit.
+//
+// Returns:
+//
+//	[]OpportunityLifecycle: Matching lifecycles, up to the limit.
+//	OpportunityLifecycleSummary: Counts, durations and capture rates.
+//	error: Error if a query fails.
+func (s *OpportunityLifecycleService) History(ctx context.Context, filter OpportunityLifecycleFilter) ([]OpportunityLifecycle, OpportunityLifecycleSummary, error) {
+	var summary OpportunityLifecycleSummary
+	if isNilDBPool(s.db) {
+		return nil, summary, fmt.Errorf("database pool is not available")
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 100
+	}
+
+	conditions := []string{"detected_at >= $1"}
+	args := []any{filter.Since.UTC()}
+	if filter.Symbol != "" {
+		args = append(args, filter.Symbol)
+		conditions = append(conditions, fmt.Sprintf("symbol = $%d", len(args)))
+	}
+	if filter.State != "" {
+		args = append(args, filter.State)
+		conditions = append(conditions, fmt.Sprintf("state = $%d", len(args)))
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var avgDuration, avgCapture *float64
+	err := s.db.QueryRow(ctx, `
+		SELECT
+			COUNT(*),
+			COALESCE(SUM(CASE WHEN closed_at IS NULL THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN notified_at IS NOT NULL THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN state = 'executed' THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN state = 'expired' THEN 1 ELSE 0 END), 0),
+			AVG(duration_seconds),
+			AVG(CASE WHEN state = 'executed' THEN capture_rate END)
+		FROM opportunity_lifecycles
+		WHERE `+where, args...).
+		Scan(&summary.Total, &summary.Open, &summary.Notified, &summary.Executed, &summary.Expired, &avgDuration, &avgCapture)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, summary, fmt.Errorf("failed to summarize opportunity lifecycles: %w", err)
+	}
+	if avgDuration != nil {
+		summary.AvgDurationSeconds = *avgDuration
+	}
+	if avgCapture != nil {
+		summary.AvgCaptureRate = *avgCapture
+	}
+	if closed := summary.Executed + summary.Expired; closed > 0 {
+		summary.CapturedRatio = float64(summary.Executed) / float64(closed)
+	}
+
+	args = append(args, filter.Limit)
+	rows, err := s.db.Query(ctx, `
+		SELECT id, symbol, buy_exchange, sell_exchange, state, detected_at, last_seen_at,
+			notified_at, executed_at, closed_at, duration_seconds,
+			detected_profit_pct, peak_profit_pct, last_profit_pct, realized_profit_pct, capture_rate
+		FROM opportunity_lifecycles
+		WHERE `+where+fmt.Sprintf(`
+		ORDER BY detected_at DESC
+		LIMIT $%d`, len(args)), args...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return []OpportunityLifecycle{}, summary, nil
+	}
+	if err != nil {
+		return nil, summary, fmt.Errorf("failed to query opportunity lifecycles: %w", err)
+	}
+	defer rows.Close()
+
+	now := s.now().UTC()
+	lifecycles := make([]OpportunityLifecycle, 0, filter.Limit)
+	for rows.Next() {
+		var lifecycle OpportunityLifecycle
+		var duration *float64
+		if err := rows.Scan(&lifecycle.ID, &lifecycle.Symbol, &lifecycle.BuyExchange, &lifecycle.SellExchange,
+			&lifecycle.State, &lifecycle.DetectedAt, &lifecycle.LastSeenAt,
+			&lifecycle.NotifiedAt, &lifecycle.ExecutedAt, &lifecycle.ClosedAt, &duration,
+			&lifecycle.DetectedProfitPercent, &lifecycle.PeakProfitPercent, &lifecycle.LastProfitPercent,
+			&lifecycle.RealizedProfitPercent, &lifecycle.CaptureRate); err != nil {
+			return nil, summary, fmt.Errorf("failed to scan opportunity lifecycle: %w", err)
+		}
+		if duration != nil {
+			lifecycle.DurationSeconds = *duration
+		} else {
+			lifecycle.DurationSeconds = now.Sub(lifecycle.DetectedAt).Seconds()
+		}
+		lifecycles = append(lifecycles, lifecycle)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, summary, fmt.Errorf("failed to read opportunity lifecycles: %w", err)
+	}
+	return lifecycles, summary, nil
+}
+
+// Prune deletes closed lifecycles older than the retention.
+func (s *OpportunityLifecycleService) Prune(ctx context.Context) error {
+	if isNilDBPool(s.db) {
+		return fmt.Errorf("database pool is not available")
+	}
+	cutoff := s.now().UTC().Add(-s.config.Retention)
+	if _, err := s.db.Exec(ctx, `DELETE FROM opportunity_lifecycles WHERE closed_at IS NOT NULL AND closed_at < $1`, cutoff); err != nil {
+		return fmt.Errorf("failed to prune opportunity lifecycles: %w", err)
+	}
+	return nil
+}
+
+func opportunityRouteKey(symbol, buyExchange, sellExchange string) string {
+	return symbol + "|" + buyExchange + "|" + sellExchange
+}
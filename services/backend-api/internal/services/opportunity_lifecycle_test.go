@@ -0,0 +1,162 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/irfndi/neuratrade/internal/models"
+)
+
+func lifecycleOpportunity(symbol, buy, sell, profit string) models.ArbitrageOpportunity {
+	return models.ArbitrageOpportunity{
+		TradingPair:      &models.TradingPair{Symbol: symbol},
+		BuyExchange:      &models.Exchange{Name: buy},
+		SellExchange:     &models.Exchange{Name: sell},
+		ProfitPercentage: decimal.RequireFromString(profit),
+	}
+}
+
+func TestOpportunityLifecycleService_ObserveCycle(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	service := NewOpportunityLifecycleService(database.NewMockDBPool(mockPool), OpportunityLifecycleConfig{})
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	// First cycle: a lifecycle left open by the previous run is picked up,
+	// the new route opens one and the stale one expires
+	mockPool.ExpectQuery("SELECT id, symbol, buy_exchange, sell_exchange, detected_at").
+		WillReturnRows(pgxmock.NewRows([]string{"id", "symbol", "buy_exchange", "sell_exchange", "detected_at"}).
+			AddRow("stale", "ETH/USDT", "okx", "kraken", now.Add(-5*time.Minute)))
+	mockPool.ExpectExec("INSERT INTO opportunity_lifecycles").
+		WithArgs(pgxmock.AnyArg(), "BTC/USDT", "binance", "kraken", OpportunityStateDetected, now, 1.2).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mockPool.ExpectExec("UPDATE opportunity_lifecycles SET state").
+		WithArgs(OpportunityStateExpired, now, 300.0, "stale").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mockPool.ExpectExec("DELETE FROM opportunity_lifecycles").
+		WithArgs(now.Add(-30 * 24 * time.Hour)).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	require.NoError(t, service.ObserveCycle(t.Context(), []models.ArbitrageOpportunity{
+		lifecycleOpportunity("BTC/USDT", "binance", "kraken", "0.8"),
+		lifecycleOpportunity("BTC/USDT", "binance", "kraken", "1.2"),
+		{ProfitPercentage: decimal.NewFromInt(5)}, // no route
+	}))
+
+	// Second cycle: the open route is updated in place
+	now = now.Add(time.Minute)
+	mockPool.ExpectExec("UPDATE opportunity_lifecycles SET\\s+last_seen_at").
+		WithArgs(now, 0.9, pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	require.NoError(t, service.ObserveCycle(t.Context(), []models.ArbitrageOpportunity{
+		lifecycleOpportunity("BTC/USDT", "binance", "kraken", "0.9"),
+	}))
+
+	// Third cycle: an execution closed it in the meantime, so it reopens
+	now = now.Add(time.Minute)
+	mockPool.ExpectExec("UPDATE opportunity_lifecycles SET\\s+last_seen_at").
+		WithArgs(now, 0.7, pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	mockPool.ExpectExec("INSERT INTO opportunity_lifecycles").
+		WithArgs(pgxmock.AnyArg(), "BTC/USDT", "binance", "kraken", OpportunityStateDetected, now, 0.7).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	require.NoError(t, service.ObserveCycle(t.Context(), []models.ArbitrageOpportunity{
+		lifecycleOpportunity("BTC/USDT", "binance", "kraken", "0.7"),
+	}))
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestOpportunityLifecycleService_MarkNotifiedAndExecuted(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	service := NewOpportunityLifecycleService(database.NewMockDBPool(mockPool), OpportunityLifecycleConfig{})
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	opp := ArbitrageOpportunity{Symbol: "BTC/USDT", BuyExchange: "binance", SellExchange: "kraken"}
+
+	mockPool.ExpectExec("UPDATE opportunity_lifecycles SET state = \\$1, notified_at").
+		WithArgs(OpportunityStateNotified, now, "BTC/USDT", "binance", "kraken", OpportunityStateDetected).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	require.NoError(t, service.MarkNotified(t.Context(), []ArbitrageOpportunity{opp, {Symbol: "RSI", OpportunityType: "technical"}}))
+
+	// Executed while open: the spread still observed is what was captured
+	columns := []string{"id", "state", "detected_at", "closed_at", "detected_profit_pct", "last_profit_pct"}
+	mockPool.ExpectQuery("SELECT id, state, detected_at").
+		WithArgs("BTC/USDT", "binance", "kraken").
+		WillReturnRows(pgxmock.NewRows(columns).AddRow("l1", OpportunityStateNotified, now.Add(-2*time.Minute), nil, 2.0, 1.5))
+	mockPool.ExpectExec("UPDATE opportunity_lifecycles SET\\s+state").
+		WithArgs(OpportunityStateExecuted, now, 120.0, 1.5, pgxmock.AnyArg(), "l1").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	require.NoError(t, service.MarkExecuted(t.Context(), opp, nil))
+
+	// Executed after the edge expired: nothing was captured
+	closedAt := now.Add(-time.Minute)
+	mockPool.ExpectQuery("SELECT id, state, detected_at").
+		WithArgs("BTC/USDT", "binance", "kraken").
+		WillReturnRows(pgxmock.NewRows(columns).AddRow("l2", OpportunityStateExpired, now.Add(-3*time.Minute), &closedAt, 2.0, 1.5))
+	mockPool.ExpectExec("UPDATE opportunity_lifecycles SET executed_at").
+		WithArgs(now, 0.0, pgxmock.AnyArg(), "l2").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	require.NoError(t, service.MarkExecuted(t.Context(), opp, nil))
+
+	// Already executed: left alone
+	mockPool.ExpectQuery("SELECT id, state, detected_at").
+		WithArgs("BTC/USDT", "binance", "kraken").
+		WillReturnRows(pgxmock.NewRows(columns).AddRow("l1", OpportunityStateExecuted, now.Add(-2*time.Minute), &now, 2.0, 1.5))
+	require.NoError(t, service.MarkExecuted(t.Context(), opp, nil))
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestOpportunityLifecycleService_History(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	service := NewOpportunityLifecycleService(database.NewMockDBPool(mockPool), OpportunityLifecycleConfig{})
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	since := now.Add(-24 * time.Hour)
+
+	avgDuration, avgCapture := 45.0, 0.8
+	mockPool.ExpectQuery("SELECT\\s+COUNT").
+		WithArgs(since, "BTC/USDT").
+		WillReturnRows(pgxmock.NewRows([]string{"total", "open", "notified", "executed", "expired", "avg_duration", "avg_capture"}).
+			AddRow(5, 1, 3, 1, 3, &avgDuration, &avgCapture))
+	captureRate, realized := 0.8, 1.6
+	executedAt := now.Add(-time.Hour)
+	mockPool.ExpectQuery("SELECT id, symbol").
+		WithArgs(since, "BTC/USDT", 2).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "symbol", "buy_exchange", "sell_exchange", "state", "detected_at", "last_seen_at",
+			"notified_at", "executed_at", "closed_at", "duration_seconds",
+			"detected_profit_pct", "peak_profit_pct", "last_profit_pct", "realized_profit_pct", "capture_rate",
+		}).
+			AddRow("open", "BTC/USDT", "binance", "kraken", OpportunityStateDetected, now.Add(-30*time.Second), now,
+				nil, nil, nil, nil, 1.0, 1.0, 1.0, nil, nil).
+			AddRow("done", "BTC/USDT", "okx", "kraken", OpportunityStateExecuted, executedAt.Add(-time.Minute), executedAt,
+				&executedAt, &executedAt, &executedAt, ptrFloat(60), 2.0, 2.2, 1.6, &realized, &captureRate))
+
+	lifecycles, summary, err := service.History(t.Context(), OpportunityLifecycleFilter{Since: since, Symbol: "BTC/USDT", Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, OpportunityLifecycleSummary{
+		Total: 5, Open: 1, Notified: 3, Executed: 1, Expired: 3,
+		AvgDurationSeconds: 45, AvgCaptureRate: 0.8, CapturedRatio: 0.25,
+	}, summary)
+	require.Len(t, lifecycles, 2)
+	assert.Equal(t, 30.0, lifecycles[0].DurationSeconds, "open lifecycles last until now")
+	assert.Nil(t, lifecycles[0].CaptureRate)
+	assert.Equal(t, 60.0, lifecycles[1].DurationSeconds)
+	assert.Equal(t, 0.8, *lifecycles[1].CaptureRate)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func ptrFloat(v float64) *float64 {
+	return &v
+}
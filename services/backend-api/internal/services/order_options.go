@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Time-in-force values accepted by the order API.
+const (
+	// TimeInForceGTC rests on the book until filled or cancelled.
+	TimeInForceGTC = "GTC"
+	// TimeInForceIOC fills what it can immediately and cancels the rest.
+	TimeInForceIOC = "IOC"
+	// TimeInForceFOK fills completely and immediately or not at all.
+	TimeInForceFOK = "FOK"
+)
+
+// ErrPostOnlyRejected is returned when a post-only order would have taken
+// liquidity and the exchange rejected or cancelled it.
+var ErrPostOnlyRejected = errors.New("post-only order rejected: it would have taken liquidity")
+
+// OrderOptions are execution flags for an order.
+type OrderOptions struct {
+	// TimeInForce is GTC, IOC or FOK; empty uses the exchange default.
+	TimeInForce string `json:"time_in_force,omitempty"`
+	// PostOnly only adds liquidity. It requires a limit order and cannot be
+	// combined with IOC or FOK.
+	PostOnly bool `json:"post_only,omitempty"`
+	// MakerFirst posts a post-only limit inside the spread and takes
+	// liquidity for whatever is left unfilled after a timeout. It replaces
+	// the other flags and needs an executor wrapped in MakerFirstExecutor.
+	MakerFirst bool `json:"maker_first,omitempty"`
+	// ClientOrderID tags the order so it can be found on the exchange when
+	// the placing process died before the order was acknowledged. It is
+	// not an execution flag and does not count towards IsZero.
+	ClientOrderID string `json:"client_order_id,omitempty"`
+}
+
+// IsZero reports whether no flags are set.
+func (o OrderOptions) IsZero() bool {
+	return o.TimeInForce == "" && !o.PostOnly && !o.MakerFirst
+}
+
+// Validate checks the flags against the order type.
+func (o OrderOptions) Validate(orderType string) error {
+	switch o.TimeInForce {
+	case "", TimeInForceGTC, TimeInForceIOC, TimeInForceFOK:
+	default:
+		return fmt.Errorf("time in force must be GTC, IOC or FOK, got %q", o.TimeInForce)
+	}
+	if o.MakerFirst {
+		if o.PostOnly || o.TimeInForce != "" {
+			return fmt.Errorf("maker-first cannot be combined with post-only or a time in force")
+		}
+		if !strings.EqualFold(orderType, "limit") {
+			return fmt.Errorf("maker-first requires a limit order")
+		}
+	}
+	if o.PostOnly {
+		if !strings.EqualFold(orderType, "limit") {
+			return fmt.Errorf("post-only requires a limit order")
+		}
+		if o.TimeInForce == TimeInForceIOC || o.TimeInForce == TimeInForceFOK {
+			return fmt.Errorf("post-only cannot be combined with %s", o.TimeInForce)
+		}
+	}
+	return nil
+}
+
+// StrategyOrderDefaults are the order flags each strategy places orders with,
+// keyed by strategy name.
+type StrategyOrderDefaults map[string]OrderOptions
+
+// For returns the strategy's order flags.
+func (d StrategyOrderDefaults) For(strategy string) OrderOptions {
+	return d[strategy]
+}
+
+// OrderOptionsExecutor places orders with execution flags.
+type OrderOptionsExecutor interface {
+	PlaceOrderWithOptions(ctx context.Context, exchange, symbol, side, orderType string, amount decimal.Decimal, price *decimal.Decimal, options OrderOptions) (string, error)
+}
+
+// placeOrderWithOptions places an order with the strategy's flags. A market
+// order with a known price becomes a limit order at that price so the flags
+// apply; without a price the flags are dropped, since a market order already
+// completes immediately. A client order ID is kept either way. Executors that
+// do not support flags place the order as given.
+func placeOrderWithOptions(ctx context.Context, executor ScalpingOrderExecutor, exchange, symbol, side, orderType string, amount decimal.Decimal, price *decimal.Decimal, options OrderOptions) (string, error) {
+	flagged, supported := executor.(OrderOptionsExecutor)
+	if !supported || (options.IsZero() && options.ClientOrderID == "") {
+		return executor.PlaceOrder(ctx, exchange, symbol, side, orderType, amount, price)
+	}
+	if orderType == "market" && !options.IsZero() {
+		if price != nil {
+			orderType = "limit"
+		} else if options.ClientOrderID == "" {
+			return executor.PlaceOrder(ctx, exchange, symbol, side, orderType, amount, price)
+		} else {
+			options = OrderOptions{ClientOrderID: options.ClientOrderID}
+		}
+	}
+	return flagged.PlaceOrderWithOptions(ctx, exchange, symbol, side, orderType, amount, price, options)
+}
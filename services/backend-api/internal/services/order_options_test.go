@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderOptions_Validate(t *testing.T) {
+	assert.NoError(t, OrderOptions{}.Validate("market"))
+	assert.NoError(t, OrderOptions{TimeInForce: TimeInForceIOC}.Validate("market"))
+	assert.NoError(t, OrderOptions{TimeInForce: TimeInForceGTC, PostOnly: true}.Validate("limit"))
+	assert.EqualError(t, OrderOptions{TimeInForce: "DAY"}.Validate("limit"), `time in force must be GTC, IOC or FOK, got "DAY"`)
+	assert.EqualError(t, OrderOptions{PostOnly: true}.Validate("market"), "post-only requires a limit order")
+	assert.EqualError(t, OrderOptions{TimeInForce: TimeInForceFOK, PostOnly: true}.Validate("limit"), "post-only cannot be combined with FOK")
+	assert.NoError(t, OrderOptions{MakerFirst: true}.Validate("limit"))
+	assert.EqualError(t, OrderOptions{MakerFirst: true}.Validate("market"), "maker-first requires a limit order")
+	assert.EqualError(t, OrderOptions{MakerFirst: true, PostOnly: true}.Validate("limit"), "maker-first cannot be combined with post-only or a time in force")
+}
+
+// flaggedOrderExecutor records the order type, price and flags of each order.
+type flaggedOrderExecutor struct {
+	recordingOrderExecutor
+	types   []string
+	options []OrderOptions
+	err     error
+}
+
+func (e *flaggedOrderExecutor) PlaceOrderWithOptions(ctx context.Context, exchange, symbol, side, orderType string, amount decimal.Decimal, price *decimal.Decimal, options OrderOptions) (string, error) {
+	e.types = append(e.types, orderType)
+	e.options = append(e.options, options)
+	if e.err != nil {
+		return "", e.err
+	}
+	return e.PlaceOrder(ctx, exchange, symbol, side, orderType, amount, price)
+}
+
+func TestPlaceOrderWithOptions(t *testing.T) {
+	executor := &flaggedOrderExecutor{}
+	price := decimal.NewFromInt(65000)
+	ioc := OrderOptions{TimeInForce: TimeInForceIOC}
+
+	_, err := placeOrderWithOptions(t.Context(), executor, "binance", "BTC/USDT", "buy", "market", decimal.NewFromInt(1), &price, ioc)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"limit"}, executor.types, "a priced market order becomes a limit order")
+	assert.Equal(t, []OrderOptions{ioc}, executor.options)
+
+	// Without a price or flags the order is placed as given
+	_, err = placeOrderWithOptions(t.Context(), executor, "binance", "BTC/USDT", "buy", "market", decimal.NewFromInt(1), nil, ioc)
+	require.NoError(t, err)
+	_, err = placeOrderWithOptions(t.Context(), executor, "binance", "BTC/USDT", "buy", "market", decimal.NewFromInt(1), &price, OrderOptions{})
+	require.NoError(t, err)
+	assert.Len(t, executor.types, 1)
+	assert.Len(t, executor.amounts, 3)
+
+	// A client order ID alone keeps the market order, and survives dropped flags
+	tagged := OrderOptions{ClientOrderID: "nt1"}
+	_, err = placeOrderWithOptions(t.Context(), executor, "binance", "BTC/USDT", "buy", "market", decimal.NewFromInt(1), &price, tagged)
+	require.NoError(t, err)
+	_, err = placeOrderWithOptions(t.Context(), executor, "binance", "BTC/USDT", "buy", "market", decimal.NewFromInt(1), nil, OrderOptions{TimeInForce: TimeInForceIOC, ClientOrderID: "nt1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"limit", "market", "market"}, executor.types)
+	assert.Equal(t, []OrderOptions{ioc, tagged, tagged}, executor.options)
+}
+
+func TestCCXTOrderExecutor_PlaceOrderWithOptions(t *testing.T) {
+	status := http.StatusOK
+	var req map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.WriteHeader(status)
+		if status == http.StatusConflict {
+			_, _ = w.Write([]byte(`{"error":"Post-only order rejected","code":"post_only_rejected"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"order":{"id":"order-1"}}`))
+	}))
+	defer server.Close()
+	executor := NewCCXTOrderExecutor(CCXTOrderExecutorConfig{ServiceURL: server.URL})
+	price := decimal.NewFromInt(2500)
+
+	orderID, err := executor.PlaceOrderWithOptions(t.Context(), "binance", "ETH/USDT", "buy", "limit", decimal.NewFromInt(1), &price, OrderOptions{TimeInForce: TimeInForceGTC, PostOnly: true})
+	require.NoError(t, err)
+	assert.Equal(t, "order-1", orderID)
+	assert.Equal(t, "GTC", req["timeInForce"])
+	assert.Equal(t, true, req["postOnly"])
+	assert.Nil(t, req["params"])
+
+	_, err = executor.PlaceOrderWithOptions(t.Context(), "binance", "ETH/USDT", "buy", "market", decimal.NewFromInt(1), nil, OrderOptions{ClientOrderID: "nt1"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"clientOrderId": "nt1"}, req["params"])
+
+	status = http.StatusConflict
+	_, err = executor.PlaceOrderWithOptions(t.Context(), "binance", "ETH/USDT", "buy", "limit", decimal.NewFromInt(1), &price, OrderOptions{PostOnly: true})
+	assert.ErrorIs(t, err, ErrPostOnlyRejected)
+
+	req = nil
+	_, err = executor.PlaceOrderWithOptions(t.Context(), "binance", "ETH/USDT", "buy", "market", decimal.NewFromInt(1), nil, OrderOptions{PostOnly: true})
+	assert.Error(t, err)
+	assert.Nil(t, req, "invalid flags are rejected before the request")
+}
+
+func TestAIScalpingService_ExecuteDecisionPostOnly(t *testing.T) {
+	executor := &flaggedOrderExecutor{err: ErrPostOnlyRejected}
+	service := NewAIScalpingService(DefaultAIScalpingConfig(), nil, nil, nil, executor, nil)
+	service.SetOrderOptions(OrderOptions{PostOnly: true})
+	decision := &AITradingDecision{Action: "buy", Symbol: "BTC/USDT", SizePercent: 2}
+	audit := &DecisionAudit{}
+
+	// A rejected post-only entry is skipped rather than failing the cycle
+	require.NoError(t, service.executeDecision(t.Context(), decision, TradingPortfolio{USDTBalance: 1000}, 0, 50000, audit))
+	assert.Equal(t, []string{"limit"}, executor.types)
+	require.NotNil(t, audit.Order)
+	assert.Equal(t, DecisionOrderSkipped, audit.Order.Status)
+
+	executor.err = nil
+	require.NoError(t, service.executeDecision(t.Context(), decision, TradingPortfolio{USDTBalance: 1000}, 0, 50000, &DecisionAudit{}))
+	require.Len(t, executor.amounts, 1)
+	assert.Equal(t, "0.0004", executor.amounts[0].String(), "limit entries are sized in the base asset")
+}
@@ -26,6 +26,11 @@ type PaperExecutionConfig struct {
 	RejectionProbability decimal.Decimal
 	// ExecutionDelayMs is the simulated execution delay in milliseconds
 	ExecutionDelayMs int
+	// FeeRate is the fee charged on the filled notional (e.g., 0.001 = 0.1%)
+	FeeRate decimal.Decimal
+	// FillRatio, when between 0 and 1, fills that fraction of every order
+	// instead of random partial fills
+	FillRatio decimal.Decimal
 	// EnableRandomness enables random simulation features
 	EnableRandomness bool
 }
@@ -88,6 +93,7 @@ type PaperOrder struct {
 	StopPrice    decimal.Decimal  `json:"stop_price"`     // Stop price
 	AvgFillPrice decimal.Decimal  `json:"avg_fill_price"` // Average fill price
 	Slippage     decimal.Decimal  `json:"slippage"`       // Actual slippage applied
+	Fee          decimal.Decimal  `json:"fee"`            // Fee charged on the fill
 	Status       PaperOrderStatus `json:"status"`
 	RejectReason string           `json:"reject_reason,omitempty"`
 	CreatedAt    time.Time        `json:"created_at"`
@@ -211,6 +217,10 @@ func (s *PaperExecutionSimulator) SimulateFill(ctx context.Context, order *Paper
 	// Apply partial fill if enabled (skip for IOC/FOK which handle fill logic above)
 	var filledSize decimal.Decimal
 	if order.Type != PaperOrderTypeIOC && order.Type != PaperOrderTypeFOK &&
+		s.config.FillRatio.IsPositive() && s.config.FillRatio.LessThan(decimal.NewFromInt(1)) {
+		filledSize = order.Size.Mul(s.config.FillRatio)
+		order.Status = PaperOrderStatusPartial
+	} else if order.Type != PaperOrderTypeIOC && order.Type != PaperOrderTypeFOK &&
 		s.config.EnableRandomness && s.shouldPartialFill() {
 		filledSize = s.calculatePartialFill(order.Size)
 		order.Status = PaperOrderStatusPartial
@@ -226,6 +236,7 @@ func (s *PaperExecutionSimulator) SimulateFill(ctx context.Context, order *Paper
 	order.Slippage = slippage
 	order.FilledSize = filledSize
 	order.AvgFillPrice = fillPrice
+	order.Fee = filledSize.Mul(fillPrice).Mul(s.config.FeeRate)
 	order.UpdatedAt = s.clock.Now()
 
 	return order, nil
@@ -0,0 +1,327 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	shareLinkRevokedKeyPrefix = "share:revoked:"
+	defaultShareLinkTTL       = 7 * 24 * time.Hour
+	maxShareLinkTTL           = 90 * 24 * time.Hour
+)
+
+var (
+	ErrShareLinkInvalid = errors.New("share link is invalid")
+	ErrShareLinkExpired = errors.New("share link has expired")
+	ErrShareLinkRevoked = errors.New("share link was revoked")
+)
+
+// ShareLink grants read-only access to a chat's performance until it
+// expires. The token carries the link itself, signed with the server
+// secret, so links need no storage; only revocations are kept.
+type ShareLink struct {
+	ID     string `json:"id"`
+	ChatID string `json:"chat_id"`
+	Period string `json:"period"`
+	// ShowAbsolute exposes PnL and equity in USDT; otherwise the page only
+	// shows percentages and counts.
+	ShowAbsolute bool      `json:"show_absolute"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	Token        string    `json:"token"`
+}
+
+// shareLinkClaims is the signed part of a share link token.
+type shareLinkClaims struct {
+	ID       string `json:"id"`
+	ChatID   string `json:"cid"`
+	Period   string `json:"p"`
+	Absolute bool   `json:"abs,omitempty"`
+	Expires  int64  `json:"exp"`
+}
+
+// SharedCurvePoint is one point of a shared equity curve.
+type SharedCurvePoint struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// SharedStrategy is the public result of one strategy.
+type SharedStrategy struct {
+	Strategy string  `json:"strategy"`
+	Trades   int     `json:"trades"`
+	WinRate  float64 `json:"win_rate"`
+}
+
+// SharedPerformance is the read-only performance behind a share link.
+type SharedPerformance struct {
+	Period      string    `json:"period"`
+	GeneratedAt time.Time `json:"generated_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	Trades      int       `json:"trades"`
+	Wins        int       `json:"wins"`
+	Losses      int       `json:"losses"`
+	// WinRate is in percent.
+	WinRate float64 `json:"win_rate"`
+	// ReturnPct is the net return over the period; nil when the equity is
+	// unknown.
+	ReturnPct *float64 `json:"return_pct,omitempty"`
+	// EquityCurve follows the cumulative net PnL: as a return in percent,
+	// or for links that opted in as equity in USDT (cumulative PnL when the
+	// equity is unknown). CurveUnit is "percent" or "usdt".
+	EquityCurve []SharedCurvePoint `json:"equity_curve"`
+	CurveUnit   string             `json:"curve_unit"`
+	Strategies  []SharedStrategy   `json:"strategies"`
+	// NetPnL and Equity are only set for links that opted in.
+	NetPnL *decimal.Decimal `json:"net_pnl,omitempty"`
+	Equity *decimal.Decimal `json:"equity,omitempty"`
+}
+
+// ShareLinkService issues, verifies and revokes share links and builds the
+// performance they expose.
+type ShareLinkService struct {
+	secret   []byte
+	redis    *redis.Client
+	reporter *PnLReporter
+	now      func() time.Time
+}
+
+// NewShareLinkService creates a share link service.
+//
+// Parameters:
+//
+//	secret: Signing secret of the tokens.
+//	client: Redis client used for revocations (may be nil).
+//	reporter: PnL reporter the shared performance is built from.
+//
+// Returns:
+//
+//	*ShareLinkService: The initialized service.
+func NewShareLinkService(secret string, client *redis.Client, reporter *PnLReporter) *ShareLinkService {
+	return &ShareLinkService{
+		secret:   []byte(secret),
+		redis:    client,
+		reporter: reporter,
+		now:      time.Now,
+	}
+}
+
+// Create issues a share link.
+//
+// Parameters:
+//
+//	chatID: Chat whose performance is shared.
+//	period: One of 24h, 7d or 30d.
+//	ttl: Link lifetime; zero uses 7 days, at most 90 days.
+//	showAbsolute: Whether absolute PnL and equity are exposed.
+//
+// Returns:
+//
+//	*ShareLink: The link with its token.
+//	error: Error if the period or lifetime is invalid.
+func (s *ShareLinkService) Create(chatID, period string, ttl time.Duration, showAbsolute bool) (*ShareLink, error) {
+	if chatID == "" {
+		return nil, fmt.Errorf("chat_id is required")
+	}
+	period, _, err := ParsePnLPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+	if ttl == 0 {
+		ttl = defaultShareLinkTTL
+	}
+	if ttl < time.Minute || ttl > maxShareLinkTTL {
+		return nil, fmt.Errorf("link lifetime must be between 1m and %s", maxShareLinkTTL)
+	}
+
+	expires := s.now().Add(ttl).UTC().Truncate(time.Second)
+	claims := shareLinkClaims{
+		ID:       uuid.New().String(),
+		ChatID:   chatID,
+		Period:   period,
+		Absolute: showAbsolute,
+		Expires:  expires.Unix(),
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return &ShareLink{
+		ID:           claims.ID,
+		ChatID:       chatID,
+		Period:       period,
+		ShowAbsolute: showAbsolute,
+		ExpiresAt:    expires,
+		Token:        encoded + "." + s.sign(encoded),
+	}, nil
+}
+
+// Verify checks a token's signature, expiry and revocation.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	token: Share link token.
+//
+// Returns:
+//
+//	*ShareLink: The link the token grants.
+//	error: ErrShareLinkInvalid, ErrShareLinkExpired or ErrShareLinkRevoked.
+func (s *ShareLinkService) Verify(ctx context.Context, token string) (*ShareLink, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(s.sign(encoded))) {
+		return nil, ErrShareLinkInvalid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrShareLinkInvalid
+	}
+	var claims shareLinkClaims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ID == "" || claims.ChatID == "" {
+		return nil, ErrShareLinkInvalid
+	}
+	expires := time.Unix(claims.Expires, 0).UTC()
+	if !s.now().Before(expires) {
+		return nil, ErrShareLinkExpired
+	}
+	if s.redis != nil {
+		revoked, err := s.redis.Exists(ctx, shareLinkRevokedKeyPrefix+claims.ID).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to check share link: %w", err)
+		}
+		if revoked > 0 {
+			return nil, ErrShareLinkRevoked
+		}
+	}
+	return &ShareLink{
+		ID:           claims.ID,
+		ChatID:       claims.ChatID,
+		Period:       claims.Period,
+		ShowAbsolute: claims.Absolute,
+		ExpiresAt:    expires,
+		Token:        token,
+	}, nil
+}
+
+// Revoke invalidates a share link before it expires.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	id: Link ID.
+//
+// Returns:
+//
+//	error: Error if revocations cannot be stored.
+func (s *ShareLinkService) Revoke(ctx context.Context, id string) error {
+	if s.redis == nil {
+		return fmt.Errorf("revoking share links requires Redis")
+	}
+	if id == "" {
+		return fmt.Errorf("id is required")
+	}
+	// Tokens outlive their revocation marker by at most the longest lifetime
+	if err := s.redis.Set(ctx, shareLinkRevokedKeyPrefix+id, "1", maxShareLinkTTL).Err(); err != nil {
+		return fmt.Errorf("failed to revoke share link: %w", err)
+	}
+	return nil
+}
+
+// Performance builds the read-only performance a link exposes.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	link: A verified link.
+//
+// Returns:
+//
+//	*SharedPerformance: The shared performance.
+//	error: Error if the PnL cannot be loaded.
+func (s *ShareLinkService) Performance(ctx context.Context, link *ShareLink) (*SharedPerformance, error) {
+	report, err := s.reporter.Report(ctx, link.ChatID, link.Period)
+	if err != nil {
+		return nil, err
+	}
+	shared := &SharedPerformance{
+		Period:      report.Period,
+		GeneratedAt: report.GeneratedAt,
+		ExpiresAt:   link.ExpiresAt,
+		Trades:      report.Trades,
+		Wins:        report.Wins,
+		Losses:      report.Losses,
+		WinRate:     percentOf(report.Wins, report.Trades),
+		EquityCurve: []SharedCurvePoint{},
+		CurveUnit:   "percent",
+		Strategies:  make([]SharedStrategy, 0, len(report.Strategies)),
+	}
+	for _, strategy := range report.Strategies {
+		shared.Strategies = append(shared.Strategies, SharedStrategy{
+			Strategy: strategy.Strategy,
+			Trades:   strategy.Trades,
+			WinRate:  percentOf(strategy.Wins, strategy.Trades),
+		})
+	}
+
+	start := decimal.Zero
+	if report.Equity.IsPositive() {
+		start = report.Equity.Sub(report.Net)
+	}
+	if start.IsPositive() {
+		returnPct := report.EquityChangePct
+		shared.ReturnPct = &returnPct
+	}
+	if link.ShowAbsolute {
+		net, equity := report.Net, report.Equity
+		shared.NetPnL = &net
+		if equity.IsPositive() {
+			shared.Equity = &equity
+		}
+		shared.CurveUnit = "usdt"
+	} else if !start.IsPositive() {
+		// Without the starting equity the curve can only be shown in USDT
+		return shared, nil
+	}
+
+	point := func(at time.Time, cumulative decimal.Decimal) SharedCurvePoint {
+		if !link.ShowAbsolute {
+			return SharedCurvePoint{Time: at, Value: cumulative.Div(start).Mul(decimal.NewFromInt(100)).InexactFloat64()}
+		}
+		return SharedCurvePoint{Time: at, Value: start.Add(cumulative).InexactFloat64()}
+	}
+	cumulative := decimal.Zero
+	shared.EquityCurve = append(shared.EquityCurve, point(report.Since, cumulative))
+	for _, trade := range report.closed {
+		cumulative = cumulative.Add(trade.PnL.Sub(trade.Fees))
+		shared.EquityCurve = append(shared.EquityCurve, point(trade.ClosedAt, cumulative))
+	}
+	// The last point marks open positions to market
+	shared.EquityCurve = append(shared.EquityCurve, point(report.GeneratedAt, report.Net))
+	return shared, nil
+}
+
+func (s *ShareLinkService) sign(encoded string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// percentOf returns part/total in percent, zero for an empty total.
+func percentOf(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareLinkService_Verify(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	service := NewShareLinkService("secret", client, nil)
+	now := time.Date(2026, 6, 10, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	link, err := service.Create("42", "30d", 48*time.Hour, true)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(48*time.Hour), link.ExpiresAt)
+
+	verified, err := service.Verify(ctx, link.Token)
+	require.NoError(t, err)
+	assert.Equal(t, link.ID, verified.ID)
+	assert.Equal(t, "42", verified.ChatID)
+	assert.Equal(t, "30d", verified.Period)
+	assert.True(t, verified.ShowAbsolute)
+
+	// Tampered claims or another secret break the signature
+	encoded, signature, _ := strings.Cut(link.Token, ".")
+	_, err = service.Verify(ctx, encoded[:len(encoded)-2]+"x."+signature)
+	assert.ErrorIs(t, err, ErrShareLinkInvalid)
+	_, err = NewShareLinkService("other", nil, nil).Verify(ctx, link.Token)
+	assert.ErrorIs(t, err, ErrShareLinkInvalid)
+	_, err = service.Verify(ctx, "garbage")
+	assert.ErrorIs(t, err, ErrShareLinkInvalid)
+
+	require.NoError(t, service.Revoke(ctx, link.ID))
+	_, err = service.Verify(ctx, link.Token)
+	assert.ErrorIs(t, err, ErrShareLinkRevoked)
+
+	other, err := service.Create("42", "", 0, false)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultPnLPeriod, other.Period)
+	now = now.Add(8 * 24 * time.Hour)
+	_, err = service.Verify(ctx, other.Token)
+	assert.ErrorIs(t, err, ErrShareLinkExpired)
+
+	_, err = service.Create("42", "1y", 0, false)
+	assert.Error(t, err)
+	_, err = service.Create("42", "7d", 365*24*time.Hour, false)
+	assert.Error(t, err)
+}
+
+func TestShareLinkService_Performance(t *testing.T) {
+	closedAt := time.Now().Add(-time.Hour)
+	trades := &staticClosedTrades{trades: []ClosedTrade{
+		{Strategy: "scalping", PnL: decimal.NewFromInt(32), Fees: decimal.NewFromInt(2), ClosedAt: closedAt},
+		{Strategy: "scalping", PnL: decimal.NewFromInt(-10), ClosedAt: closedAt.Add(time.Minute)},
+	}}
+	reporter := NewPnLReporter(trades, nil, &fakeEquitySource{equity: decimal.NewFromInt(1020)})
+	service := NewShareLinkService("secret", nil, reporter)
+	ctx := context.Background()
+
+	private, err := service.Create("42", "7d", 0, false)
+	require.NoError(t, err)
+	shared, err := service.Performance(ctx, private)
+	require.NoError(t, err)
+	assert.Equal(t, 2, shared.Trades)
+	assert.Equal(t, 50.0, shared.WinRate)
+	require.NotNil(t, shared.ReturnPct)
+	assert.InDelta(t, 2.0, *shared.ReturnPct, 1e-9)
+	assert.Nil(t, shared.NetPnL)
+	assert.Nil(t, shared.Equity)
+	assert.Equal(t, "percent", shared.CurveUnit)
+	require.Len(t, shared.EquityCurve, 4)
+	assert.InDelta(t, 0.0, shared.EquityCurve[0].Value, 1e-9)
+	assert.InDelta(t, 3.0, shared.EquityCurve[1].Value, 1e-9)
+	assert.InDelta(t, 2.0, shared.EquityCurve[3].Value, 1e-9)
+	require.Len(t, shared.Strategies, 1)
+	assert.Equal(t, 50.0, shared.Strategies[0].WinRate)
+
+	public, err := service.Create("42", "7d", 0, true)
+	require.NoError(t, err)
+	shared, err = service.Performance(ctx, public)
+	require.NoError(t, err)
+	assert.Equal(t, "usdt", shared.CurveUnit)
+	require.NotNil(t, shared.NetPnL)
+	assert.Equal(t, "20", shared.NetPnL.String())
+	assert.InDelta(t, 1000.0, shared.EquityCurve[0].Value, 1e-9)
+	assert.InDelta(t, 1020.0, shared.EquityCurve[3].Value, 1e-9)
+
+	// Without equity a private link has no curve to show
+	shared, err = NewShareLinkService("secret", nil, NewPnLReporter(trades, nil, nil)).Performance(ctx, private)
+	require.NoError(t, err)
+	assert.Nil(t, shared.ReturnPct)
+	assert.Empty(t, shared.EquityCurve)
+}
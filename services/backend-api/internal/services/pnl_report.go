@@ -0,0 +1,313 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/irfndi/neuratrade/pkg/interfaces"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultPnLPeriod is the report period used when none is requested.
+const DefaultPnLPeriod = "24h"
+
+// pnlPeriods are the report periods selectable from /pnl.
+var pnlPeriods = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+}
+
+// ClosedTrade is a realized trade counted in PnL reports. PnL is before fees.
+type ClosedTrade struct {
+	Strategy string          `json:"strategy"`
+	Exchange string          `json:"exchange"`
+	Symbol   string          `json:"symbol"`
+	Side     string          `json:"side"`
+	PnL      decimal.Decimal `json:"pnl"`
+	Fees     decimal.Decimal `json:"fees"`
+	ClosedAt time.Time       `json:"closed_at"`
+}
+
+// ClosedTradeSource lists the trades closed since a time.
+type ClosedTradeSource interface {
+	ClosedTrades(ctx context.Context, since time.Time) ([]ClosedTrade, error)
+}
+
+// MarkedPositionSource lists open positions marked to market. It is
+// implemented by PositionTracker.
+type MarkedPositionSource interface {
+	GetOpenPositions() []interfaces.Position
+}
+
+// TradeOutcomeSource reads closed trades from the trade_outcomes table.
+type TradeOutcomeSource struct {
+	db DBPool
+}
+
+// NewTradeOutcomeSource creates a closed trade source over trade_outcomes.
+//
+// Parameters:
+//
+//	db: Database pool.
+//
+// Returns:
+//
+//	*TradeOutcomeSource: The initialized source.
+func NewTradeOutcomeSource(db DBPool) *TradeOutcomeSource {
+	return &TradeOutcomeSource{db: db}
+}
+
+// ClosedTrades returns the trades that closed with a win, loss or breakeven
+// since the given time, oldest first.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	since: Earliest close time.
+//
+// Returns:
+//
+//	[]ClosedTrade: Closed trades.
+//	error: Error if the query fails.
+func (s *TradeOutcomeSource) ClosedTrades(ctx context.Context, since time.Time) ([]ClosedTrade, error) {
+	if isNilDBPool(s.db) {
+		return nil, fmt.Errorf("database pool is not available")
+	}
+	rows, err := s.db.Query(ctx, `
+		SELECT skill_id, exchange, symbol, side, COALESCE(pnl, 0), COALESCE(fees, 0), updated_at
+		FROM trade_outcomes
+		WHERE outcome IN ('win', 'loss', 'breakeven') AND updated_at >= $1
+		ORDER BY updated_at ASC`, since.UTC())
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trade outcomes: %w", err)
+	}
+	defer rows.Close()
+
+	var trades []ClosedTrade
+	for rows.Next() {
+		var trade ClosedTrade
+		if err := rows.Scan(&trade.Strategy, &trade.Exchange, &trade.Symbol, &trade.Side, &trade.PnL, &trade.Fees, &trade.ClosedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan trade outcome: %w", err)
+		}
+		trades = append(trades, trade)
+	}
+	return trades, rows.Err()
+}
+
+// PnLReport summarizes trading results over a period.
+type PnLReport struct {
+	Period        string          `json:"period"`
+	Since         time.Time       `json:"since"`
+	GeneratedAt   time.Time       `json:"generated_at"`
+	Trades        int             `json:"trades"`
+	Wins          int             `json:"wins"`
+	Losses        int             `json:"losses"`
+	Realized      decimal.Decimal `json:"realized"`
+	Fees          decimal.Decimal `json:"fees"`
+	Unrealized    decimal.Decimal `json:"unrealized"`
+	OpenPositions int             `json:"open_positions"`
+	// Net is realized PnL after fees plus unrealized PnL of open positions.
+	Net        decimal.Decimal `json:"net"`
+	BestTrade  *ClosedTrade    `json:"best_trade,omitempty"`
+	WorstTrade *ClosedTrade    `json:"worst_trade,omitempty"`
+	// Equity is the current account equity; zero when unknown. The equity
+	// change is Net relative to the equity at the period start.
+	Equity          decimal.Decimal `json:"equity"`
+	EquityChangePct float64         `json:"equity_change_pct"`
+	// Strategies attributes the realized PnL to strategies, best first.
+	Strategies []StrategyPnL `json:"strategies"`
+
+	closed []ClosedTrade
+}
+
+// StrategyPnL is the realized result of one strategy in a report.
+type StrategyPnL struct {
+	Strategy string          `json:"strategy"`
+	Trades   int             `json:"trades"`
+	Wins     int             `json:"wins"`
+	PnL      decimal.Decimal `json:"pnl"`
+	Fees     decimal.Decimal `json:"fees"`
+}
+
+// PnLReporter builds PnL reports for /pnl and the daily report quest.
+type PnLReporter struct {
+	trades    ClosedTradeSource
+	positions MarkedPositionSource
+	equity    EquitySource
+}
+
+// NewPnLReporter creates a PnL reporter.
+//
+// Parameters:
+//
+//	trades: Closed trade source.
+//	positions: Marked open position source (may be nil).
+//	equity: Account equity source (may be nil).
+//
+// Returns:
+//
+//	*PnLReporter: The initialized reporter.
+func NewPnLReporter(trades ClosedTradeSource, positions MarkedPositionSource, equity EquitySource) *PnLReporter {
+	return &PnLReporter{trades: trades, positions: positions, equity: equity}
+}
+
+// ParsePnLPeriod validates a report period.
+//
+// Parameters:
+//
+//	period: One of 24h, 7d or 30d; empty selects DefaultPnLPeriod.
+//
+// Returns:
+//
+//	string: The normalized period.
+//	time.Duration: The period length.
+//	error: Error if the period is not supported.
+func ParsePnLPeriod(period string) (string, time.Duration, error) {
+	period = strings.ToLower(strings.TrimSpace(period))
+	if period == "" {
+		period = DefaultPnLPeriod
+	}
+	duration, ok := pnlPeriods[period]
+	if !ok {
+		return "", 0, fmt.Errorf("unsupported period %q, use 24h, 7d or 30d", period)
+	}
+	return period, duration, nil
+}
+
+// Report builds the PnL report of a chat over a period.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	chatID: Chat whose equity is reported.
+//	period: One of 24h, 7d or 30d.
+//
+// Returns:
+//
+//	*PnLReport: The report.
+//	error: Error if the period is invalid or trades cannot be loaded.
+func (r *PnLReporter) Report(ctx context.Context, chatID, period string) (*PnLReport, error) {
+	period, duration, err := ParsePnLPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	report := &PnLReport{Period: period, Since: now.Add(-duration), GeneratedAt: now, Strategies: []StrategyPnL{}}
+
+	if r.trades != nil {
+		trades, err := r.trades.ClosedTrades(ctx, report.Since)
+		if err != nil {
+			return nil, err
+		}
+		report.closed = trades
+		strategies := make(map[string]*StrategyPnL)
+		for i := range trades {
+			trade := trades[i]
+			name := trade.Strategy
+			if name == "" {
+				name = "unknown"
+			}
+			strategy, ok := strategies[name]
+			if !ok {
+				strategy = &StrategyPnL{Strategy: name}
+				strategies[name] = strategy
+			}
+			strategy.Trades++
+			strategy.PnL = strategy.PnL.Add(trade.PnL)
+			strategy.Fees = strategy.Fees.Add(trade.Fees)
+			if trade.PnL.IsPositive() {
+				strategy.Wins++
+			}
+
+			report.Trades++
+			report.Realized = report.Realized.Add(trade.PnL)
+			report.Fees = report.Fees.Add(trade.Fees)
+			switch trade.PnL.Sign() {
+			case 1:
+				report.Wins++
+			case -1:
+				report.Losses++
+			}
+			if report.BestTrade == nil || trade.PnL.GreaterThan(report.BestTrade.PnL) {
+				report.BestTrade = &trade
+			}
+			if report.WorstTrade == nil || trade.PnL.LessThan(report.WorstTrade.PnL) {
+				report.WorstTrade = &trade
+			}
+		}
+		for _, strategy := range strategies {
+			report.Strategies = append(report.Strategies, *strategy)
+		}
+		sort.Slice(report.Strategies, func(i, j int) bool {
+			if !report.Strategies[i].PnL.Equal(report.Strategies[j].PnL) {
+				return report.Strategies[i].PnL.GreaterThan(report.Strategies[j].PnL)
+			}
+			return report.Strategies[i].Strategy < report.Strategies[j].Strategy
+		})
+	}
+
+	if r.positions != nil {
+		for _, position := range r.positions.GetOpenPositions() {
+			report.OpenPositions++
+			report.Unrealized = report.Unrealized.Add(position.UnrealizedPL)
+		}
+	}
+	report.Net = report.Realized.Sub(report.Fees).Add(report.Unrealized)
+
+	if r.equity != nil {
+		if equity, err := r.equity.Equity(ctx, chatID); err == nil && equity.IsPositive() {
+			report.Equity = equity
+			if start := equity.Sub(report.Net); start.IsPositive() {
+				report.EquityChangePct = report.Net.Div(start).Mul(decimal.NewFromInt(100)).InexactFloat64()
+			}
+		}
+	}
+	return report, nil
+}
+
+// FormatPnLReport renders a report as a compact plain-text message.
+//
+// Parameters:
+//
+//	report: The report.
+//	title: Heading of the message.
+//
+// Returns:
+//
+//	string: The message.
+func FormatPnLReport(report *PnLReport, title string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "📊 %s (%s)\n\n", title, report.Period)
+	fmt.Fprintf(&b, "Realized: %s USDT\n", signedAmount(report.Realized))
+	fmt.Fprintf(&b, "Unrealized: %s USDT (%d open)\n", signedAmount(report.Unrealized), report.OpenPositions)
+	fmt.Fprintf(&b, "Fees: %s USDT\n", report.Fees.StringFixed(2))
+	fmt.Fprintf(&b, "Net: %s USDT\n", signedAmount(report.Net))
+	if report.Trades == 0 {
+		b.WriteString("Trades: none closed\n")
+	} else {
+		fmt.Fprintf(&b, "Trades: %d (%dW / %dL)\n", report.Trades, report.Wins, report.Losses)
+		fmt.Fprintf(&b, "Best: %s %s\n", report.BestTrade.Symbol, signedAmount(report.BestTrade.PnL))
+		fmt.Fprintf(&b, "Worst: %s %s\n", report.WorstTrade.Symbol, signedAmount(report.WorstTrade.PnL))
+	}
+	if report.Equity.IsPositive() {
+		fmt.Fprintf(&b, "Equity: %s USDT (%+.2f%%)\n", report.Equity.StringFixed(2), report.EquityChangePct)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// signedAmount formats an amount with an explicit sign.
+func signedAmount(amount decimal.Decimal) string {
+	if amount.IsPositive() {
+		return "+" + amount.StringFixed(2)
+	}
+	return amount.StringFixed(2)
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/pkg/interfaces"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticClosedTrades struct {
+	trades []ClosedTrade
+	since  time.Time
+}
+
+func (s *staticClosedTrades) ClosedTrades(_ context.Context, since time.Time) ([]ClosedTrade, error) {
+	s.since = since
+	return s.trades, nil
+}
+
+type staticMarkedPositions []interfaces.Position
+
+func (s staticMarkedPositions) GetOpenPositions() []interfaces.Position { return s }
+
+func TestPnLReporter_Report(t *testing.T) {
+	trades := &staticClosedTrades{trades: []ClosedTrade{
+		{Symbol: "BTC/USDT", PnL: decimal.NewFromInt(40), Fees: decimal.NewFromInt(2)},
+		{Symbol: "ETH/USDT", PnL: decimal.NewFromInt(-15), Fees: decimal.NewFromInt(1)},
+		{Symbol: "SOL/USDT", PnL: decimal.NewFromInt(5), Fees: decimal.NewFromInt(1)},
+	}}
+	positions := staticMarkedPositions{{Symbol: "BTC/USDT", UnrealizedPL: decimal.NewFromInt(-6)}}
+	reporter := NewPnLReporter(trades, positions, &fakeEquitySource{equity: decimal.NewFromInt(1020)})
+
+	report, err := reporter.Report(context.Background(), "1", "7d")
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(-7*24*time.Hour), trades.since, time.Minute)
+	assert.Equal(t, 3, report.Trades)
+	assert.Equal(t, 2, report.Wins)
+	assert.Equal(t, 1, report.Losses)
+	assert.True(t, report.Realized.Equal(decimal.NewFromInt(30)))
+	assert.True(t, report.Fees.Equal(decimal.NewFromInt(4)))
+	assert.True(t, report.Net.Equal(decimal.NewFromInt(20)))
+	assert.Equal(t, "BTC/USDT", report.BestTrade.Symbol)
+	assert.Equal(t, "ETH/USDT", report.WorstTrade.Symbol)
+	assert.InDelta(t, 2.0, report.EquityChangePct, 1e-9)
+
+	text := FormatPnLReport(report, "PnL Report")
+	assert.Contains(t, text, "PnL Report (7d)")
+	assert.Contains(t, text, "Net: +20.00 USDT")
+	assert.Contains(t, text, "Worst: ETH/USDT -15.00")
+	assert.Contains(t, text, "Equity: 1020.00 USDT (+2.00%)")
+
+	_, err = reporter.Report(context.Background(), "1", "1y")
+	assert.Error(t, err)
+}
+
+func TestFormatPnLReport_NoTrades(t *testing.T) {
+	report, err := NewPnLReporter(nil, nil, nil).Report(context.Background(), "1", "")
+	require.NoError(t, err)
+	text := FormatPnLReport(report, "Daily Performance Report")
+	assert.Contains(t, text, "(24h)")
+	assert.Contains(t, text, "Trades: none closed")
+	assert.NotContains(t, text, "Equity")
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/shopspring/decimal"
+)
+
+// FlattenOrderPlacer places the market orders that close positions.
+type FlattenOrderPlacer interface {
+	PlaceOrder(ctx context.Context, exchange, symbol, side, orderType string, amount decimal.Decimal, price *decimal.Decimal) (string, error)
+}
+
+// ExchangeFlattener closes open positions on the exchange by placing a market
+// order on the opposite side of each one.
+type ExchangeFlattener struct {
+	positions MarginPositionSource
+	orders    FlattenOrderPlacer
+	logger    *slog.Logger
+}
+
+// NewExchangeFlattener creates an exchange flattener.
+//
+// Parameters:
+//
+//	positions: Source of the open positions to close.
+//	orders: Executor that places the closing orders.
+//
+// Returns:
+//
+//	*ExchangeFlattener: Initialized flattener.
+func NewExchangeFlattener(positions MarginPositionSource, orders FlattenOrderPlacer) *ExchangeFlattener {
+	return &ExchangeFlattener{
+		positions: positions,
+		orders:    orders,
+		logger:    telemetry.Logger(),
+	}
+}
+
+// Flatten closes every open position and matches PositionFlattener. All
+// chats trade one exchange account, so the chat is only logged. Every
+// position is attempted; the failures are returned together.
+func (f *ExchangeFlattener) Flatten(ctx context.Context, chatID string) error {
+	if f.positions == nil || f.orders == nil {
+		return errors.New("exchange flattener requires a position source and an order executor")
+	}
+	var errs []error
+	closed := 0
+	for _, position := range f.positions.GetOpenPositions() {
+		side := closingSide(position.Side)
+		if side == "" || !position.Size.IsPositive() {
+			errs = append(errs, fmt.Errorf("cannot close %s %s position %s: side %q, size %s",
+				position.Exchange, position.Symbol, position.PositionID, position.Side, position.Size))
+			continue
+		}
+		orderID, err := f.orders.PlaceOrder(ctx, position.Exchange, position.Symbol, side, "market", position.Size, nil)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to close %s %s position %s: %w",
+				position.Exchange, position.Symbol, position.PositionID, err))
+			continue
+		}
+		closed++
+		f.logger.Warn("Placed order to flatten position", "chat_id", chatID, "exchange", position.Exchange,
+			"symbol", position.Symbol, "position_id", position.PositionID, "side", side, "amount", position.Size.String(), "order_id", orderID)
+	}
+	if len(errs) > 0 {
+		f.logger.Error("Failed to flatten some positions", "chat_id", chatID, "closed", closed, "failed", len(errs))
+	}
+	return errors.Join(errs...)
+}
+
+// closingSide returns the order side that closes a position, or "" for an
+// unknown position side.
+func closingSide(positionSide string) string {
+	switch strings.ToLower(positionSide) {
+	case "buy", "long":
+		return "sell"
+	case "sell", "short":
+		return "buy"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/irfndi/neuratrade/pkg/interfaces"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// preTradeOrderPlacer runs a pre-trade hook before placing an order, like
+// the hooked order executor.
+type preTradeOrderPlacer struct {
+	hook PreTradeHook
+	next FlattenOrderPlacer
+}
+
+func (p *preTradeOrderPlacer) PlaceOrder(ctx context.Context, exchange, symbol, side, orderType string, amount decimal.Decimal, price *decimal.Decimal) (string, error) {
+	decision, err := p.hook.PreTrade(ctx, HookOrder{Exchange: exchange, Symbol: symbol, Side: side, OrderType: orderType, Amount: amount, Price: price})
+	if err != nil {
+		return "", err
+	}
+	if decision.Veto {
+		return "", errors.New(decision.Reason)
+	}
+	return p.next.PlaceOrder(ctx, exchange, symbol, side, orderType, amount, price)
+}
+
+func TestExchangeFlattener_PlacesClosingOrders(t *testing.T) {
+	orders := &fakeOrderPlacer{failures: map[string]error{"okx": errors.New("exchange down")}}
+	flattener := NewExchangeFlattener(marginTestPositions{
+		{PositionID: "p1", Exchange: "binance", Symbol: "BTC/USDT", Side: "BUY", Size: decimal.NewFromFloat(0.5), Status: interfaces.PositionStatusOpen},
+		{PositionID: "p2", Exchange: "bybit", Symbol: "ETH/USDT", Side: "short", Size: decimal.NewFromInt(3), Status: interfaces.PositionStatusOpen},
+		{PositionID: "p3", Exchange: "okx", Symbol: "SOL/USDT", Side: "long", Size: decimal.NewFromInt(10), Status: interfaces.PositionStatusOpen},
+	}, orders)
+
+	err := flattener.Flatten(t.Context(), "42")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "p3")
+	assert.Equal(t, []string{"binance:sell:0.5", "bybit:buy:3"}, orders.orders, "one failure does not stop the other closes")
+}
+
+func TestExchangeFlattener_PassesDailyLossHalt(t *testing.T) {
+	circuit, _, _ := newTestDailyLossCircuit(t, nil, DailyLossConfig{MaxLoss: decimal.NewFromInt(30)})
+	positions := marginTestPositions{
+		{PositionID: "p1", Exchange: "binance", Symbol: "BTC/USDT", Side: "long", Size: decimal.NewFromInt(1), Status: interfaces.PositionStatusOpen},
+	}
+	circuit.SetPositionSource(positions)
+	_, err := circuit.RecordRealized(t.Context(), "42", decimal.NewFromInt(-30))
+	require.NoError(t, err)
+	require.True(t, circuit.AccountHalted(t.Context()))
+
+	orders := &fakeOrderPlacer{}
+	guarded := &preTradeOrderPlacer{hook: circuit, next: orders}
+	require.NoError(t, NewExchangeFlattener(positions, guarded).Flatten(t.Context(), "42"))
+	assert.Equal(t, []string{"binance:sell:1"}, orders.orders, "closing orders pass the halt")
+}
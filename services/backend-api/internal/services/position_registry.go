@@ -0,0 +1,333 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/pkg/interfaces"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	strategyPositionsKey = "risk:strategy_positions"
+
+	defaultStrategyPositionStaleAfter = 24 * time.Hour
+)
+
+// PositionOverlapMode is how an order is treated when another strategy
+// already holds a position on the symbol.
+type PositionOverlapMode string
+
+const (
+	// PositionOverlapBlock rejects the order.
+	PositionOverlapBlock PositionOverlapMode = "block"
+	// PositionOverlapNet only accepts orders that offset the other strategies'
+	// net exposure, so exposure never stacks.
+	PositionOverlapNet PositionOverlapMode = "net"
+	// PositionOverlapAllow accepts overlapping positions.
+	PositionOverlapAllow PositionOverlapMode = "allow"
+)
+
+// StrategyPositionGuard checks and records which strategy holds each symbol.
+type StrategyPositionGuard interface {
+	CheckOverlap(ctx context.Context, strategy, exchange, symbol, side string) error
+	RecordFill(ctx context.Context, strategy, exchange, symbol, side string, amount decimal.Decimal) error
+}
+
+// PositionRegistryConfig configures the cross-strategy overlap rule.
+type PositionRegistryConfig struct {
+	// Mode is block, net or allow.
+	Mode PositionOverlapMode
+	// AllowSymbols may be held by several strategies at once whatever the mode.
+	AllowSymbols []string
+	// StaleAfter drops entries that were not updated for this long, so a
+	// position closed outside the tracker does not block the symbol forever.
+	StaleAfter time.Duration
+}
+
+// StrategyPosition is one strategy's position on a symbol and exchange.
+type StrategyPosition struct {
+	Strategy  string          `json:"strategy"`
+	Exchange  string          `json:"exchange"`
+	Symbol    string          `json:"symbol"`
+	Side      string          `json:"side"`
+	Amount    decimal.Decimal `json:"amount"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// PositionOverlapError is returned when an order would stack exposure on a
+// symbol another strategy holds.
+type PositionOverlapError struct {
+	Strategy string
+	Symbol   string
+	Holders  []StrategyPosition
+}
+
+// Error implements error.
+func (e *PositionOverlapError) Error() string {
+	holders := make([]string, 0, len(e.Holders))
+	for _, holder := range e.Holders {
+		holders = append(holders, fmt.Sprintf("%s %s on %s", holder.Strategy, holder.Side, holder.Exchange))
+	}
+	return fmt.Sprintf("%s already held by %s; %s may not open an overlapping position", e.Symbol, strings.Join(holders, ", "), e.Strategy)
+}
+
+// PositionRegistry is the central record of which strategy holds a position
+// on each symbol. Before a strategy opens a position it checks the registry,
+// which blocks, nets or allows overlap with other strategies per config.
+// Symbols match across exchanges and spot/perp notation, since two
+// strategies on different venues still double the exposure.
+type PositionRegistry struct {
+	redis  *redis.Client
+	config PositionRegistryConfig
+	allow  map[string]bool
+	now    func() time.Time
+	// mu serializes read-modify-write updates of a symbol's holders.
+	mu sync.Mutex
+}
+
+// Ensure PositionRegistry implements StrategyPositionGuard.
+var _ StrategyPositionGuard = (*PositionRegistry)(nil)
+
+// NewPositionRegistry creates the strategy position registry.
+//
+// Parameters:
+//
+//	client: Redis client used to persist holdings.
+//	config: Overlap rule; zero values block overlap and use defaults.
+//
+// Returns:
+//
+//	*PositionRegistry: Initialized registry.
+func NewPositionRegistry(client *redis.Client, config PositionRegistryConfig) *PositionRegistry {
+	switch config.Mode {
+	case PositionOverlapNet, PositionOverlapAllow:
+	default:
+		config.Mode = PositionOverlapBlock
+	}
+	if config.StaleAfter <= 0 {
+		config.StaleAfter = defaultStrategyPositionStaleAfter
+	}
+	allow := make(map[string]bool, len(config.AllowSymbols))
+	for _, symbol := range config.AllowSymbols {
+		if normalized := normalizeSymbolForComparison(symbol); normalized != "" {
+			allow[normalized] = true
+		}
+	}
+	return &PositionRegistry{
+		redis:  client,
+		config: config,
+		allow:  allow,
+		now:    time.Now,
+	}
+}
+
+// Config returns the overlap rule configuration.
+func (r *PositionRegistry) Config() PositionRegistryConfig {
+	return r.config
+}
+
+// CheckOverlap reports whether the strategy may place an order on the symbol.
+// Orders that reduce the strategy's own position are always accepted.
+//
+// Parameters:
+//
+//	ctx: Context for the lookup.
+//	strategy: The strategy placing the order.
+//	exchange: The exchange the order goes to.
+//	symbol: The order's symbol.
+//	side: buy or sell.
+//
+// Returns:
+//
+//	error: *PositionOverlapError if the order is not allowed, or a lookup error.
+func (r *PositionRegistry) CheckOverlap(ctx context.Context, strategy, exchange, symbol, side string) error {
+	symbol = normalizeSymbolForComparison(symbol)
+	exchange = strings.ToLower(strings.TrimSpace(exchange))
+	holders, err := r.load(ctx, symbol)
+	if err != nil {
+		return err
+	}
+	if r.config.Mode == PositionOverlapAllow || r.allow[symbol] {
+		return nil
+	}
+
+	direction := sideDirection(side)
+	var others []StrategyPosition
+	othersNet := decimal.Zero
+	for _, holder := range holders {
+		if holder.Strategy == strategy {
+			if holder.Exchange == exchange && sideDirection(holder.Side) == -direction {
+				return nil
+			}
+			continue
+		}
+		others = append(others, holder)
+		othersNet = othersNet.Add(holder.Amount.Mul(decimal.NewFromInt(sideDirection(holder.Side))))
+	}
+	if len(others) == 0 {
+		return nil
+	}
+	if r.config.Mode == PositionOverlapNet && othersNet.Sign() == -int(direction) {
+		return nil
+	}
+	return &PositionOverlapError{Strategy: strategy, Symbol: symbol, Holders: others}
+}
+
+// RecordFill adds a placed order to the strategy's position on the symbol.
+// An order on the opposite side reduces the position and removes it once
+// flat.
+//
+// Parameters:
+//
+//	ctx: Context for the update.
+//	strategy: The strategy that placed the order.
+//	exchange: The exchange the order went to.
+//	symbol: The order's symbol.
+//	side: buy or sell.
+//	amount: The order amount.
+//
+// Returns:
+//
+//	error: Error if the holdings could not be read or saved.
+func (r *PositionRegistry) RecordFill(ctx context.Context, strategy, exchange, symbol, side string, amount decimal.Decimal) error {
+	symbol = normalizeSymbolForComparison(symbol)
+	exchange = strings.ToLower(strings.TrimSpace(exchange))
+	direction := sideDirection(side)
+	if strategy == "" || symbol == "" || direction == 0 {
+		return fmt.Errorf("strategy, symbol and a buy or sell side are required")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	holders, err := r.load(ctx, symbol)
+	if err != nil {
+		return err
+	}
+	signed := amount.Abs().Mul(decimal.NewFromInt(direction))
+	updated := holders[:0]
+	for _, holder := range holders {
+		if holder.Strategy == strategy && holder.Exchange == exchange {
+			signed = signed.Add(holder.Amount.Mul(decimal.NewFromInt(sideDirection(holder.Side))))
+			continue
+		}
+		updated = append(updated, holder)
+	}
+	if !signed.IsZero() {
+		next := StrategyPosition{Strategy: strategy, Exchange: exchange, Symbol: symbol, Side: "buy", Amount: signed.Abs(), UpdatedAt: r.now().UTC()}
+		if signed.IsNegative() {
+			next.Side = "sell"
+		}
+		updated = append(updated, next)
+	}
+	return r.save(ctx, symbol, updated)
+}
+
+// Release removes every strategy's position on the symbol and exchange.
+func (r *PositionRegistry) Release(ctx context.Context, exchange, symbol string) error {
+	symbol = normalizeSymbolForComparison(symbol)
+	exchange = strings.ToLower(strings.TrimSpace(exchange))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	holders, err := r.load(ctx, symbol)
+	if err != nil {
+		return err
+	}
+	remaining := holders[:0]
+	for _, holder := range holders {
+		if holder.Exchange != exchange {
+			remaining = append(remaining, holder)
+		}
+	}
+	return r.save(ctx, symbol, remaining)
+}
+
+// OnPositionClosed releases a closed or liquidated position. It is
+// registered as a position tracker close callback.
+func (r *PositionRegistry) OnPositionClosed(ctx context.Context, position interfaces.Position) error {
+	return r.Release(ctx, position.Exchange, position.Symbol)
+}
+
+// Holdings returns every recorded strategy position, by symbol.
+func (r *PositionRegistry) Holdings(ctx context.Context) ([]StrategyPosition, error) {
+	entries, err := r.redis.HGetAll(ctx, strategyPositionsKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load strategy positions: %w", err)
+	}
+	var holdings []StrategyPosition
+	for _, raw := range entries {
+		var holders []StrategyPosition
+		if err := json.Unmarshal([]byte(raw), &holders); err != nil {
+			continue
+		}
+		holdings = append(holdings, r.fresh(holders)...)
+	}
+	sort.Slice(holdings, func(i, j int) bool {
+		if holdings[i].Symbol != holdings[j].Symbol {
+			return holdings[i].Symbol < holdings[j].Symbol
+		}
+		return holdings[i].Strategy < holdings[j].Strategy
+	})
+	return holdings, nil
+}
+
+// sideDirection is +1 for buy/long, -1 for sell/short and 0 otherwise.
+func sideDirection(side string) int64 {
+	switch strings.ToLower(strings.TrimSpace(side)) {
+	case "buy", "long":
+		return 1
+	case "sell", "short":
+		return -1
+	}
+	return 0
+}
+
+// fresh drops entries older than StaleAfter.
+func (r *PositionRegistry) fresh(holders []StrategyPosition) []StrategyPosition {
+	cutoff := r.now().Add(-r.config.StaleAfter)
+	kept := holders[:0]
+	for _, holder := range holders {
+		if holder.UpdatedAt.After(cutoff) {
+			kept = append(kept, holder)
+		}
+	}
+	return kept
+}
+
+func (r *PositionRegistry) load(ctx context.Context, symbol string) ([]StrategyPosition, error) {
+	raw, err := r.redis.HGet(ctx, strategyPositionsKey, symbol).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load strategy positions: %w", err)
+	}
+	var holders []StrategyPosition
+	if err := json.Unmarshal([]byte(raw), &holders); err != nil {
+		return nil, fmt.Errorf("failed to decode strategy positions: %w", err)
+	}
+	return r.fresh(holders), nil
+}
+
+func (r *PositionRegistry) save(ctx context.Context, symbol string, holders []StrategyPosition) error {
+	if len(holders) == 0 {
+		return r.redis.HDel(ctx, strategyPositionsKey, symbol).Err()
+	}
+	raw, err := json.Marshal(holders)
+	if err != nil {
+		return err
+	}
+	if err := r.redis.HSet(ctx, strategyPositionsKey, symbol, raw).Err(); err != nil {
+		return fmt.Errorf("failed to save strategy positions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/irfndi/neuratrade/pkg/interfaces"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPositionRegistry(t *testing.T, config PositionRegistryConfig) (*PositionRegistry, *time.Time) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	registry := NewPositionRegistry(client, config)
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	registry.now = func() time.Time { return now }
+	return registry, &now
+}
+
+func TestPositionRegistry_Block(t *testing.T) {
+	registry, now := newTestPositionRegistry(t, PositionRegistryConfig{})
+	ctx := t.Context()
+	assert.Equal(t, PositionOverlapBlock, registry.Config().Mode)
+
+	require.NoError(t, registry.CheckOverlap(ctx, StrategyScalping, "binance", "BTC/USDT", "buy"))
+	require.NoError(t, registry.RecordFill(ctx, StrategyScalping, "binance", "BTC/USDT", "buy", decimal.NewFromInt(50)))
+	// The same strategy may add to its own position
+	require.NoError(t, registry.CheckOverlap(ctx, StrategyScalping, "binance", "BTC/USDT", "buy"))
+
+	err := registry.CheckOverlap(ctx, StrategyArbitrage, "bybit", "BTC/USDT:USDT", "buy")
+	var overlap *PositionOverlapError
+	require.True(t, errors.As(err, &overlap), "symbols match across exchanges and notation")
+	assert.Equal(t, "BTC/USDT already held by scalping buy on binance; arbitrage may not open an overlapping position", err.Error())
+	assert.Error(t, registry.CheckOverlap(ctx, StrategyArbitrage, "binance", "BTC/USDT", "sell"), "block rejects offsetting orders too")
+	require.NoError(t, registry.CheckOverlap(ctx, StrategyArbitrage, "binance", "ETH/USDT", "buy"))
+
+	// Selling the position back down to flat releases the symbol
+	require.NoError(t, registry.RecordFill(ctx, StrategyScalping, "binance", "BTC/USDT", "sell", decimal.NewFromInt(20)))
+	holdings, err := registry.Holdings(ctx)
+	require.NoError(t, err)
+	require.Len(t, holdings, 1)
+	assert.Equal(t, "30", holdings[0].Amount.String())
+	require.NoError(t, registry.RecordFill(ctx, StrategyScalping, "binance", "BTC/USDT", "sell", decimal.NewFromInt(30)))
+	require.NoError(t, registry.CheckOverlap(ctx, StrategyArbitrage, "binance", "BTC/USDT", "buy"))
+
+	// Entries that are not updated expire
+	require.NoError(t, registry.RecordFill(ctx, StrategyScalping, "binance", "SOL/USDT", "buy", decimal.NewFromInt(10)))
+	*now = now.Add(25 * time.Hour)
+	require.NoError(t, registry.CheckOverlap(ctx, StrategyArbitrage, "binance", "SOL/USDT", "buy"))
+}
+
+func TestPositionRegistry_NetAndAllow(t *testing.T) {
+	registry, _ := newTestPositionRegistry(t, PositionRegistryConfig{Mode: PositionOverlapNet, AllowSymbols: []string{"eth/usdt"}})
+	ctx := t.Context()
+
+	require.NoError(t, registry.RecordFill(ctx, StrategyScalping, "binance", "BTC/USDT", "buy", decimal.NewFromInt(50)))
+	assert.Error(t, registry.CheckOverlap(ctx, StrategyArbitrage, "binance", "BTC/USDT", "buy"), "stacking the same side is rejected")
+	assert.NoError(t, registry.CheckOverlap(ctx, StrategyArbitrage, "bybit", "BTC/USDT", "sell"), "offsetting orders net the exposure")
+
+	require.NoError(t, registry.RecordFill(ctx, StrategyScalping, "binance", "ETH/USDT", "buy", decimal.NewFromInt(50)))
+	assert.NoError(t, registry.CheckOverlap(ctx, StrategyArbitrage, "binance", "ETH/USDT", "buy"), "allowed symbols may overlap")
+
+	require.NoError(t, registry.OnPositionClosed(ctx, interfaces.Position{Exchange: "binance", Symbol: "BTC/USDT"}))
+	assert.NoError(t, registry.CheckOverlap(ctx, StrategyArbitrage, "binance", "BTC/USDT", "buy"))
+}
+
+func TestIntegratedQuestHandlers_ArbitrageLegsAllowed(t *testing.T) {
+	registry, _ := newTestPositionRegistry(t, PositionRegistryConfig{})
+	ctx := t.Context()
+	require.NoError(t, registry.RecordFill(ctx, StrategyScalping, "binance", "BTC/USDT", "buy", decimal.NewFromInt(50)))
+
+	h := &IntegratedQuestHandlers{}
+	h.SetPositionGuard(registry)
+	quest := &Quest{Checkpoint: map[string]interface{}{}}
+	assert.False(t, h.arbitrageLegsAllowed(ctx, quest, StrategyArbitrage, "BTC/USDT", "binance", "okx"))
+	assert.Equal(t, "position_overlap_hold", quest.Checkpoint["status"])
+	assert.True(t, h.arbitrageLegsAllowed(ctx, quest, StrategyArbitrage, "ETH/USDT", "binance", "okx"))
+
+	h.recordStrategyFill(ctx, StrategyArbitrage, "okx", "ETH/USDT", "sell", decimal.NewFromInt(2))
+	assert.Error(t, registry.CheckOverlap(ctx, StrategyScalping, "binance", "ETH/USDT", "sell"))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -23,6 +24,14 @@ type PositionTrackerConfig struct {
 	RedisKeyPrefix string
 	// EnableRealTimeSync enables real-time position synchronization
 	EnableRealTimeSync bool
+	// LiquidationBuffers are distances to liquidation, as fractions of the
+	// mark price, that raise escalating risk alerts
+	LiquidationBuffers []float64
+	// MaintenanceTiers are the maintenance margin schedules used to estimate
+	// liquidation prices
+	MaintenanceTiers map[string][]MaintenanceTier
+	// NotifyChatID is the Telegram chat ID for liquidation alerts
+	NotifyChatID int64
 }
 
 // DefaultPositionTrackerConfig returns default configuration.
@@ -31,14 +40,32 @@ func DefaultPositionTrackerConfig() PositionTrackerConfig {
 		SyncInterval:       30 * time.Second,
 		RedisKeyPrefix:     "position_tracker",
 		EnableRealTimeSync: true,
+		LiquidationBuffers: []float64{0.20, 0.10, 0.05},
+		MaintenanceTiers:   DefaultMaintenanceTiers(),
 	}
 }
 
+// RiskEventNotifier sends risk alerts to a chat.
+type RiskEventNotifier interface {
+	NotifyRiskEvent(ctx context.Context, chatID int64, event RiskEventNotification) error
+}
+
 // TrackedPosition represents a position with real-time tracking state.
 type TrackedPosition struct {
 	Position     interfaces.Position `json:"position"`
 	LastSyncAt   time.Time           `json:"last_sync_at"`
 	PriceUpdated bool                `json:"price_updated"`
+	// LiquidationBuffer is the tightest liquidation buffer already alerted;
+	// zero once the mark is outside every buffer
+	LiquidationBuffer float64 `json:"liquidation_buffer,omitempty"`
+}
+
+// liquidationAlert is a position that moved into a tighter liquidation buffer.
+type liquidationAlert struct {
+	position interfaces.Position
+	distance float64
+	buffer   float64
+	severity string
 }
 
 // PositionTracker manages real-time position tracking with exchange synchronization.
@@ -55,8 +82,13 @@ type PositionTracker struct {
 	// Callbacks
 	onFillCallback        func(ctx context.Context, positionID string, fill FillData) error
 	onPriceUpdateCallback func(ctx context.Context, positionID string, newPrice decimal.Decimal) error
+	onCloseCallback       func(ctx context.Context, position interfaces.Position) error
 	callbacksMu           sync.RWMutex
 
+	// Liquidation alerts
+	riskNotifier RiskEventNotifier
+	events       EventEmitter
+
 	// Goroutine control
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -74,7 +106,9 @@ type FillData struct {
 	FillSize    decimal.Decimal `json:"fill_size"`
 	RealizedPnL decimal.Decimal `json:"realized_pnl,omitempty"`
 	Commission  decimal.Decimal `json:"commission,omitempty"`
-	Timestamp   time.Time       `json:"timestamp"`
+	// Leverage is the position leverage; zero keeps the tracked value
+	Leverage  decimal.Decimal `json:"leverage,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
 }
 
 // NewPositionTracker creates a new position tracker service.
@@ -84,6 +118,19 @@ func NewPositionTracker(
 	redisClient *redis.Client,
 	logger *zaplogrus.Logger,
 ) *PositionTracker {
+	// Alert from the widest buffer to the tightest
+	buffers := make([]float64, 0, len(config.LiquidationBuffers))
+	for _, buffer := range config.LiquidationBuffers {
+		if buffer > 0 && buffer < 1 {
+			buffers = append(buffers, buffer)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(buffers)))
+	config.LiquidationBuffers = buffers
+	if config.MaintenanceTiers == nil {
+		config.MaintenanceTiers = DefaultMaintenanceTiers()
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	return &PositionTracker{
 		config:      config,
@@ -208,9 +255,12 @@ func (pt *PositionTracker) SyncWithExchange(ctx context.Context) error {
 		// Copy needed values before releasing lock
 		unrealizedPL := tracked.Position.UnrealizedPL
 		symbol := position.Symbol
+		alert := pt.checkLiquidationBuffer(tracked)
 
 		pt.positionsMu.Unlock()
 
+		pt.sendLiquidationAlert(ctx, alert)
+
 		// Copy callback reference with proper locking
 		pt.callbacksMu.RLock()
 		onPriceUpdateCb := pt.onPriceUpdateCallback
@@ -255,6 +305,7 @@ func (pt *PositionTracker) OnFill(ctx context.Context, fill FillData) error {
 			Size:         fill.FillSize,
 			EntryPrice:   fill.FillPrice,
 			CurrentPrice: fill.FillPrice,
+			Leverage:     fill.Leverage,
 			Status:       interfaces.PositionStatusOpen,
 			OpenedAt:     fill.Timestamp,
 			UpdatedAt:    fill.Timestamp,
@@ -277,6 +328,9 @@ func (pt *PositionTracker) OnFill(ctx context.Context, fill FillData) error {
 		tracked.Position.OrderID = fill.OrderID
 		tracked.Position.Size = fill.FillSize
 		tracked.Position.EntryPrice = fill.FillPrice
+		if fill.Leverage.IsPositive() {
+			tracked.Position.Leverage = fill.Leverage
+		}
 		tracked.Position.UpdatedAt = fill.Timestamp
 		tracked.LastSyncAt = time.Now().UTC()
 
@@ -286,6 +340,8 @@ func (pt *PositionTracker) OnFill(ctx context.Context, fill FillData) error {
 			"new_entry_price", fill.FillPrice)
 	}
 
+	pt.updateLiquidationPrice(tracked)
+
 	// Copy callback reference and release lock before invoking
 	pt.callbacksMu.RLock()
 	onFillCb := pt.onFillCallback
@@ -305,13 +361,52 @@ func (pt *PositionTracker) OnFill(ctx context.Context, fill FillData) error {
 	return pt.savePositionsToRedis(ctx)
 }
 
+// OnOrderUpdate applies a streamed order update to the open position opened by
+// that order. Updates for untracked orders, or without a fill yet, are
+// ignored and left to the periodic sync.
+func (pt *PositionTracker) OnOrderUpdate(ctx context.Context, update OrderUpdate) error {
+	if !update.Filled.IsPositive() {
+		return nil
+	}
+
+	pt.positionsMu.RLock()
+	var position interfaces.Position
+	found := false
+	for _, tracked := range pt.positions {
+		if tracked.Position.OrderID == update.OrderID && tracked.Position.Exchange == update.Exchange &&
+			tracked.Position.Status == interfaces.PositionStatusOpen {
+			position, found = tracked.Position, true
+			break
+		}
+	}
+	pt.positionsMu.RUnlock()
+	if !found || position.Size.Equal(update.Filled) {
+		return nil
+	}
+
+	fillPrice := update.Average
+	if !fillPrice.IsPositive() {
+		fillPrice = position.EntryPrice
+	}
+	return pt.OnFill(ctx, FillData{
+		PositionID: position.PositionID,
+		OrderID:    update.OrderID,
+		Symbol:     position.Symbol,
+		Exchange:   position.Exchange,
+		Side:       position.Side,
+		FillPrice:  fillPrice,
+		FillSize:   update.Filled,
+		Timestamp:  update.Timestamp,
+	})
+}
+
 // OnPriceUpdate updates a position's price and calculates unrealized PnL.
 func (pt *PositionTracker) OnPriceUpdate(ctx context.Context, positionID string, newPrice decimal.Decimal) error {
 	pt.positionsMu.Lock()
-	defer pt.positionsMu.Unlock()
 
 	tracked, exists := pt.positions[positionID]
 	if !exists {
+		pt.positionsMu.Unlock()
 		return fmt.Errorf("position not found: %s", positionID)
 	}
 
@@ -329,14 +424,123 @@ func (pt *PositionTracker) OnPriceUpdate(ctx context.Context, positionID string,
 		"new_price", newPrice,
 		"unrealized_pl", tracked.Position.UnrealizedPL)
 
+	alert := pt.checkLiquidationBuffer(tracked)
+	pt.positionsMu.Unlock()
+
+	pt.sendLiquidationAlert(ctx, alert)
+
+	pt.callbacksMu.RLock()
+	onPriceUpdateCb := pt.onPriceUpdateCallback
+	pt.callbacksMu.RUnlock()
+
 	// Trigger price update callback
-	if pt.onPriceUpdateCallback != nil {
-		return pt.onPriceUpdateCallback(ctx, positionID, newPrice)
+	if onPriceUpdateCb != nil {
+		return onPriceUpdateCb(ctx, positionID, newPrice)
 	}
 
 	return nil
 }
 
+// updateLiquidationPrice estimates the liquidation price of a leveraged
+// position from the exchange's maintenance margin schedule.
+func (pt *PositionTracker) updateLiquidationPrice(tracked *TrackedPosition) {
+	position := &tracked.Position
+	if !position.Leverage.IsPositive() {
+		position.LiquidationPrice = decimal.Zero
+		return
+	}
+	notional := position.Size.Abs().Mul(position.EntryPrice)
+	rate := MaintenanceMarginRate(pt.config.MaintenanceTiers, position.Exchange, notional)
+	position.LiquidationPrice = EstimateLiquidationPrice(position.Side, position.EntryPrice, position.Leverage, rate)
+}
+
+// checkLiquidationBuffer returns an alert when the mark price moved into a
+// tighter liquidation buffer than already alerted. Leaving the widest buffer
+// re-arms the alerts. The caller must hold positionsMu.
+func (pt *PositionTracker) checkLiquidationBuffer(tracked *TrackedPosition) *liquidationAlert {
+	position := tracked.Position
+	buffers := pt.config.LiquidationBuffers
+	if len(buffers) == 0 || !position.LiquidationPrice.IsPositive() || !position.CurrentPrice.IsPositive() {
+		return nil
+	}
+
+	distance := LiquidationDistance(position.Side, position.CurrentPrice, position.LiquidationPrice).InexactFloat64()
+	level := -1
+	for i, buffer := range buffers {
+		if distance <= buffer {
+			level = i
+		}
+	}
+	if level < 0 {
+		tracked.LiquidationBuffer = 0
+		return nil
+	}
+	buffer := buffers[level]
+	if tracked.LiquidationBuffer != 0 && buffer >= tracked.LiquidationBuffer {
+		return nil
+	}
+	tracked.LiquidationBuffer = buffer
+
+	severity := "medium"
+	switch len(buffers) - 1 - level {
+	case 0:
+		severity = "critical"
+	case 1:
+		severity = "high"
+	}
+	return &liquidationAlert{position: position, distance: distance, buffer: buffer, severity: severity}
+}
+
+// sendLiquidationAlert emits the alert as a risk event and notifies the
+// configured chat. It is a no-op for a nil alert.
+func (pt *PositionTracker) sendLiquidationAlert(ctx context.Context, alert *liquidationAlert) {
+	if alert == nil {
+		return
+	}
+	p := alert.position
+	pt.logger.Warn("Position approaching liquidation",
+		"position_id", p.PositionID,
+		"symbol", p.Symbol,
+		"mark_price", p.CurrentPrice,
+		"liquidation_price", p.LiquidationPrice,
+		"distance", alert.distance)
+
+	if pt.events != nil {
+		pt.events.Emit(ctx, WebhookEventRisk, map[string]interface{}{
+			"type":              "liquidation_proximity",
+			"severity":          alert.severity,
+			"position_id":       p.PositionID,
+			"exchange":          p.Exchange,
+			"symbol":            p.Symbol,
+			"side":              p.Side,
+			"leverage":          p.Leverage.String(),
+			"mark_price":        p.CurrentPrice.String(),
+			"liquidation_price": p.LiquidationPrice.String(),
+			"distance_pct":      alert.distance * 100,
+			"buffer_pct":        alert.buffer * 100,
+		})
+	}
+
+	if pt.riskNotifier == nil || pt.config.NotifyChatID == 0 {
+		return
+	}
+	notification := RiskEventNotification{
+		EventType: "liquidation_proximity",
+		Severity:  alert.severity,
+		Message: fmt.Sprintf("%s %s is %.1f%% from its liquidation price (within the %.0f%% buffer)",
+			p.Side, p.Symbol, alert.distance*100, alert.buffer*100),
+		Details: map[string]string{
+			"exchange":          p.Exchange,
+			"leverage":          p.Leverage.String(),
+			"mark_price":        p.CurrentPrice.String(),
+			"liquidation_price": p.LiquidationPrice.StringFixed(4),
+		},
+	}
+	if err := pt.riskNotifier.NotifyRiskEvent(ctx, pt.config.NotifyChatID, notification); err != nil {
+		pt.logger.WithError(err).Error("Failed to send liquidation alert", "position_id", p.PositionID)
+	}
+}
+
 // calculateUnrealizedPL calculates the unrealized profit/loss for a position.
 func (pt *PositionTracker) calculateUnrealizedPL(tracked *TrackedPosition) {
 	position := &tracked.Position
@@ -413,6 +617,7 @@ func (pt *PositionTracker) ClosePosition(ctx context.Context, positionID string)
 
 	// Copy needed values before releasing lock
 	unrealizedPL := tracked.Position.UnrealizedPL
+	position := tracked.Position
 
 	pt.positionsMu.Unlock()
 
@@ -420,6 +625,7 @@ func (pt *PositionTracker) ClosePosition(ctx context.Context, positionID string)
 		"position_id", positionID,
 		"realized_pl", unrealizedPL)
 
+	pt.notifyClosed(ctx, position)
 	return pt.savePositionsToRedis(ctx)
 }
 
@@ -438,6 +644,7 @@ func (pt *PositionTracker) LiquidatePosition(ctx context.Context, positionID str
 
 	// Copy needed values before releasing lock
 	unrealizedPL := tracked.Position.UnrealizedPL
+	position := tracked.Position
 
 	pt.positionsMu.Unlock()
 
@@ -445,9 +652,34 @@ func (pt *PositionTracker) LiquidatePosition(ctx context.Context, positionID str
 		"position_id", positionID,
 		"unrealized_pl", unrealizedPL)
 
+	pt.notifyClosed(ctx, position)
 	return pt.savePositionsToRedis(ctx)
 }
 
+// notifyClosed triggers the close callback for a closed or liquidated position.
+func (pt *PositionTracker) notifyClosed(ctx context.Context, position interfaces.Position) {
+	pt.callbacksMu.RLock()
+	onCloseCb := pt.onCloseCallback
+	pt.callbacksMu.RUnlock()
+
+	if onCloseCb != nil {
+		if err := onCloseCb(ctx, position); err != nil {
+			pt.logger.WithError(err).Error("Position close callback failed",
+				"position_id", position.PositionID)
+		}
+	}
+}
+
+// SetRiskNotifier sends liquidation alerts to the configured chat.
+func (pt *PositionTracker) SetRiskNotifier(notifier RiskEventNotifier) {
+	pt.riskNotifier = notifier
+}
+
+// SetEventEmitter publishes liquidation alerts as risk events.
+func (pt *PositionTracker) SetEventEmitter(events EventEmitter) {
+	pt.events = events
+}
+
 // SetOnFillCallback sets the callback for fill events.
 func (pt *PositionTracker) SetOnFillCallback(callback func(ctx context.Context, positionID string, fill FillData) error) {
 	pt.callbacksMu.Lock()
@@ -462,6 +694,13 @@ func (pt *PositionTracker) SetOnPriceUpdateCallback(callback func(ctx context.Co
 	pt.onPriceUpdateCallback = callback
 }
 
+// SetOnCloseCallback sets the callback for closed and liquidated positions.
+func (pt *PositionTracker) SetOnCloseCallback(callback func(ctx context.Context, position interfaces.Position) error) {
+	pt.callbacksMu.Lock()
+	defer pt.callbacksMu.Unlock()
+	pt.onCloseCallback = callback
+}
+
 // loadPositionsFromRedis loads tracked positions from Redis.
 func (pt *PositionTracker) loadPositionsFromRedis(ctx context.Context) error {
 	if pt.redisClient == nil {
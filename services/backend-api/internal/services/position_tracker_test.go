@@ -451,10 +451,102 @@ func TestPositionTracker_CalculateUnrealizedPL_ZeroSize(t *testing.T) {
 	assert.True(t, position.UnrealizedPL.IsZero())
 }
 
+type riskEventRecorder struct {
+	chatIDs []int64
+	events  []RiskEventNotification
+}
+
+func (r *riskEventRecorder) NotifyRiskEvent(_ context.Context, chatID int64, event RiskEventNotification) error {
+	r.chatIDs = append(r.chatIDs, chatID)
+	r.events = append(r.events, event)
+	return nil
+}
+
+func TestPositionTracker_LiquidationAlertsEscalate(t *testing.T) {
+	config := DefaultPositionTrackerConfig()
+	config.EnableRealTimeSync = false
+	config.NotifyChatID = 42
+	tracker := NewPositionTracker(config, new(MockCCXTForTracker), nil, zaplogrus.New())
+	notifier := &riskEventRecorder{}
+	events := &hookEventCapture{}
+	tracker.SetRiskNotifier(notifier)
+	tracker.SetEventEmitter(events)
+	ctx := context.Background()
+
+	require.NoError(t, tracker.OnFill(ctx, FillData{
+		PositionID: "pos-1",
+		Symbol:     "BTC/USDT:USDT",
+		Exchange:   "binance",
+		Side:       "BUY",
+		FillPrice:  decimal.NewFromInt(50000),
+		FillSize:   decimal.NewFromFloat(0.5),
+		Leverage:   decimal.NewFromInt(10),
+		Timestamp:  time.Now().UTC(),
+	}))
+	position, _ := tracker.GetPosition("pos-1")
+	assert.InDelta(t, 45180.72, position.LiquidationPrice.InexactFloat64(), 0.01)
+
+	// Liquidation near 45180.72: 19.3%, 17.9%, 9.6%, 3.9% and 2.8% away
+	for _, price := range []int64{56000, 55000, 50000, 47000, 46500} {
+		require.NoError(t, tracker.OnPriceUpdate(ctx, "pos-1", decimal.NewFromInt(price)))
+	}
+	require.Len(t, notifier.events, 3)
+	assert.Equal(t, []int64{42, 42, 42}, notifier.chatIDs)
+	assert.Equal(t, "medium", notifier.events[0].Severity)
+	assert.Equal(t, "high", notifier.events[1].Severity)
+	assert.Equal(t, "critical", notifier.events[2].Severity)
+	assert.Equal(t, "liquidation_proximity", notifier.events[2].EventType)
+	require.Len(t, events.events, 3)
+	assert.Equal(t, WebhookEventRisk, events.events[0].event)
+
+	// Moving outside every buffer re-arms the alerts
+	require.NoError(t, tracker.OnPriceUpdate(ctx, "pos-1", decimal.NewFromInt(60000)))
+	require.NoError(t, tracker.OnPriceUpdate(ctx, "pos-1", decimal.NewFromInt(56000)))
+	assert.Len(t, notifier.events, 4)
+
+	// Unleveraged positions carry no liquidation price
+	require.NoError(t, tracker.OnFill(ctx, FillData{PositionID: "pos-2", Symbol: "ETH/USDT", Exchange: "binance", Side: "BUY", FillPrice: decimal.NewFromInt(3000), FillSize: decimal.NewFromInt(1)}))
+	position, _ = tracker.GetPosition("pos-2")
+	assert.True(t, position.LiquidationPrice.IsZero())
+}
+
+func TestPositionTracker_OnOrderUpdate(t *testing.T) {
+	tracker, _, cleanup := setupPositionTrackerTest(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	require.NoError(t, tracker.OnFill(ctx, FillData{
+		PositionID: "pos-1",
+		OrderID:    "order-1",
+		Symbol:     "BTC/USDT",
+		Exchange:   "binance",
+		Side:       "BUY",
+		FillPrice:  decimal.NewFromInt(50000),
+		FillSize:   decimal.NewFromFloat(0.2),
+		Timestamp:  time.Now().UTC(),
+	}))
+
+	// The rest of the entry order fills at a new average
+	require.NoError(t, tracker.OnOrderUpdate(ctx, OrderUpdate{
+		Exchange: "binance", OrderID: "order-1", Status: "closed",
+		Filled: decimal.NewFromFloat(0.5), Average: decimal.NewFromInt(50100), Timestamp: time.Now().UTC(),
+	}))
+	position, _ := tracker.GetPosition("pos-1")
+	assert.True(t, position.Size.Equal(decimal.NewFromFloat(0.5)))
+	assert.True(t, position.EntryPrice.Equal(decimal.NewFromInt(50100)))
+
+	// Other orders and other exchanges are left to the sync
+	require.NoError(t, tracker.OnOrderUpdate(ctx, OrderUpdate{Exchange: "binance", OrderID: "order-2", Filled: decimal.NewFromInt(1)}))
+	require.NoError(t, tracker.OnOrderUpdate(ctx, OrderUpdate{Exchange: "bybit", OrderID: "order-1", Filled: decimal.NewFromInt(1)}))
+	position, _ = tracker.GetPosition("pos-1")
+	assert.True(t, position.Size.Equal(decimal.NewFromFloat(0.5)))
+}
+
 func TestDefaultPositionTrackerConfig(t *testing.T) {
 	config := DefaultPositionTrackerConfig()
 
 	assert.Equal(t, 30*time.Second, config.SyncInterval)
 	assert.Equal(t, "position_tracker", config.RedisKeyPrefix)
 	assert.True(t, config.EnableRealTimeSync)
+	assert.Equal(t, []float64{0.20, 0.10, 0.05}, config.LiquidationBuffers)
 }
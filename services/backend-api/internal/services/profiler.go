@@ -0,0 +1,301 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/telemetry"
+)
+
+// Profile kinds captured by the profiler.
+const (
+	ProfileKindCPU  = "cpu"
+	ProfileKindHeap = "heap"
+)
+
+// maxProfileCaptures caps the capture history kept in memory.
+const maxProfileCaptures = 50
+
+// ProfilerConfig configures continuous profile capture.
+type ProfilerConfig struct {
+	// Destination is where profiles are stored: a directory, or an http(s)
+	// URL each profile is PUT under.
+	Destination string
+	// UploadToken is sent as a bearer token with HTTP uploads.
+	UploadToken string
+	// LatencyThreshold is the request latency that triggers a CPU profile;
+	// zero disables latency triggers.
+	LatencyThreshold time.Duration
+	// HeapThresholdMB is the heap in use that triggers a heap profile; zero
+	// disables memory triggers.
+	HeapThresholdMB uint64
+	// CheckInterval is how often heap usage is checked.
+	CheckInterval time.Duration
+	// CPUDuration is how long a CPU profile records.
+	CPUDuration time.Duration
+	// Cooldown is the minimum time between two captures of the same kind.
+	Cooldown time.Duration
+}
+
+// ProfileCapture records one captured profile.
+type ProfileCapture struct {
+	Kind       string    `json:"kind"`
+	Reason     string    `json:"reason"`
+	Location   string    `json:"location,omitempty"`
+	Bytes      int       `json:"bytes"`
+	CapturedAt time.Time `json:"captured_at"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Profiler captures CPU and heap profiles when request latency or heap usage
+// crosses a threshold and stores them at a configured destination, so
+// production performance regressions can be diagnosed after the fact.
+type Profiler struct {
+	config     ProfilerConfig
+	logger     *slog.Logger
+	httpClient *http.Client
+	now        func() time.Time
+	heapInUse  func() uint64
+
+	mu          sync.Mutex
+	lastCapture map[string]time.Time
+	inFlight    map[string]bool
+	captures    []ProfileCapture
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewProfiler creates the continuous profiler.
+//
+// Parameters:
+//
+//	config: Destination and triggers; zero values use a 15 second check
+//	interval, 10 second CPU profiles and a 10 minute cooldown.
+//
+// Returns:
+//
+//	*Profiler: Initialized profiler.
+func NewProfiler(config ProfilerConfig) *Profiler {
+	if config.Destination == "" {
+		config.Destination = "profiles"
+	}
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = 15 * time.Second
+	}
+	if config.CPUDuration <= 0 {
+		config.CPUDuration = 10 * time.Second
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = 10 * time.Minute
+	}
+	return &Profiler{
+		config:      config,
+		logger:      telemetry.Logger(),
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		now:         time.Now,
+		heapInUse:   readHeapInUse,
+		lastCapture: make(map[string]time.Time),
+		inFlight:    make(map[string]bool),
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// Config returns the profiler configuration.
+func (p *Profiler) Config() ProfilerConfig {
+	return p.config
+}
+
+// Start checks heap usage in the background.
+func (p *Profiler) Start() {
+	if p.config.HeapThresholdMB == 0 {
+		return
+	}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(p.config.CheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stopCh:
+				return
+			case <-ticker.C:
+				p.CheckHeap()
+			}
+		}
+	}()
+}
+
+// Stop ends the background loop, cuts a running CPU profile short and waits
+// for captures in flight to be stored.
+func (p *Profiler) Stop() {
+	p.stopOnce.Do(func() { close(p.stopCh) })
+	p.wg.Wait()
+}
+
+// ObserveLatency records a request latency and captures a CPU profile when
+// it reaches the latency threshold.
+func (p *Profiler) ObserveLatency(latency time.Duration) {
+	if p.config.LatencyThreshold <= 0 || latency < p.config.LatencyThreshold {
+		return
+	}
+	p.trigger(ProfileKindCPU, fmt.Sprintf("request latency %s over %s", latency.Round(time.Millisecond), p.config.LatencyThreshold))
+}
+
+// CheckHeap captures a heap profile when heap usage reaches the threshold.
+func (p *Profiler) CheckHeap() {
+	if p.config.HeapThresholdMB == 0 {
+		return
+	}
+	inUseMB := p.heapInUse() / (1024 * 1024)
+	if inUseMB < p.config.HeapThresholdMB {
+		return
+	}
+	p.trigger(ProfileKindHeap, fmt.Sprintf("heap in use %dMB over %dMB", inUseMB, p.config.HeapThresholdMB))
+}
+
+// Captures returns the most recent captures, newest first.
+func (p *Profiler) Captures() []ProfileCapture {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	captures := make([]ProfileCapture, len(p.captures))
+	for i, capture := range p.captures {
+		captures[len(p.captures)-1-i] = capture
+	}
+	return captures
+}
+
+// trigger starts a capture unless one of the kind is running or the kind is
+// cooling down.
+func (p *Profiler) trigger(kind, reason string) {
+	select {
+	case <-p.stopCh:
+		return
+	default:
+	}
+
+	p.mu.Lock()
+	now := p.now()
+	if p.inFlight[kind] || now.Sub(p.lastCapture[kind]) < p.config.Cooldown {
+		p.mu.Unlock()
+		return
+	}
+	p.inFlight[kind] = true
+	p.lastCapture[kind] = now
+	p.mu.Unlock()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		capture := p.capture(kind, reason)
+
+		p.mu.Lock()
+		delete(p.inFlight, kind)
+		p.captures = append(p.captures, capture)
+		if len(p.captures) > maxProfileCaptures {
+			p.captures = p.captures[len(p.captures)-maxProfileCaptures:]
+		}
+		p.mu.Unlock()
+
+		if capture.Error != "" {
+			p.logger.Warn("Failed to capture profile", "kind", kind, "reason", reason, "error", capture.Error)
+			return
+		}
+		p.logger.Info("Captured profile", "kind", kind, "reason", reason, "location", capture.Location, "bytes", capture.Bytes)
+	}()
+}
+
+// capture records a profile and stores it.
+func (p *Profiler) capture(kind, reason string) ProfileCapture {
+	capture := ProfileCapture{Kind: kind, Reason: reason, CapturedAt: p.now().UTC()}
+
+	var buf bytes.Buffer
+	switch kind {
+	case ProfileKindCPU:
+		if err := pprof.StartCPUProfile(&buf); err != nil {
+			// Another CPU profile, e.g. /debug/pprof/profile, is running
+			capture.Error = fmt.Sprintf("failed to start CPU profile: %v", err)
+			return capture
+		}
+		timer := time.NewTimer(p.config.CPUDuration)
+		select {
+		case <-timer.C:
+		case <-p.stopCh:
+			timer.Stop()
+		}
+		pprof.StopCPUProfile()
+	case ProfileKindHeap:
+		if err := pprof.Lookup("heap").WriteTo(&buf, 0); err != nil {
+			capture.Error = fmt.Sprintf("failed to write heap profile: %v", err)
+			return capture
+		}
+	default:
+		capture.Error = fmt.Sprintf("unknown profile kind %q", kind)
+		return capture
+	}
+
+	name := fmt.Sprintf("%s-%s.pprof", kind, capture.CapturedAt.Format("20060102T150405Z"))
+	location, err := p.store(name, buf.Bytes())
+	if err != nil {
+		capture.Error = err.Error()
+		return capture
+	}
+	capture.Location = location
+	capture.Bytes = buf.Len()
+	return capture
+}
+
+// store writes a profile to the destination and returns where it went.
+func (p *Profiler) store(name string, data []byte) (string, error) {
+	destination := p.config.Destination
+	if strings.HasPrefix(destination, "http://") || strings.HasPrefix(destination, "https://") {
+		url := strings.TrimRight(destination, "/") + "/" + name
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(data))
+		if err != nil {
+			return "", fmt.Errorf("failed to create upload request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/octet-stream")
+		if p.config.UploadToken != "" {
+			req.Header.Set("Authorization", "Bearer "+p.config.UploadToken)
+		}
+		resp, err := p.httpClient.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("failed to upload profile: %w", err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return "", fmt.Errorf("profile upload returned status %d", resp.StatusCode)
+		}
+		return url, nil
+	}
+
+	if err := os.MkdirAll(destination, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create profile directory: %w", err)
+	}
+	path := filepath.Join(destination, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write profile: %w", err)
+	}
+	return path, nil
+}
+
+// readHeapInUse returns the bytes in in-use heap spans.
+func readHeapInUse() uint64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapInuse
+}
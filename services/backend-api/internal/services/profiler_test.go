@@ -0,0 +1,90 @@
+package services
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfiler_CapturesHeapOverThreshold(t *testing.T) {
+	dir := t.TempDir()
+	profiler := NewProfiler(ProfilerConfig{Destination: dir, HeapThresholdMB: 64})
+	heap := uint64(32 * 1024 * 1024)
+	profiler.heapInUse = func() uint64 { return heap }
+
+	profiler.CheckHeap()
+	profiler.Stop()
+	assert.Empty(t, profiler.Captures(), "heap under the threshold should not be profiled")
+
+	profiler = NewProfiler(ProfilerConfig{Destination: dir, HeapThresholdMB: 64})
+	heap = 128 * 1024 * 1024
+	profiler.heapInUse = func() uint64 { return heap }
+	profiler.CheckHeap()
+	profiler.CheckHeap() // cooling down
+	profiler.Stop()
+
+	captures := profiler.Captures()
+	require.Len(t, captures, 1)
+	assert.Equal(t, ProfileKindHeap, captures[0].Kind)
+	assert.Contains(t, captures[0].Reason, "128MB over 64MB")
+	assert.Empty(t, captures[0].Error)
+	data, err := os.ReadFile(captures[0].Location)
+	require.NoError(t, err)
+	assert.Len(t, data, captures[0].Bytes)
+	assert.NotEmpty(t, data)
+}
+
+func TestProfiler_LatencyTriggersCPUProfileUpload(t *testing.T) {
+	var uploadedPath, authorization string
+	var uploaded []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		uploadedPath = r.URL.Path
+		authorization = r.Header.Get("Authorization")
+		uploaded, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	profiler := NewProfiler(ProfilerConfig{
+		Destination:      server.URL + "/profiles/",
+		UploadToken:      "secret",
+		LatencyThreshold: time.Second,
+		CPUDuration:      50 * time.Millisecond,
+	})
+	profiler.ObserveLatency(500 * time.Millisecond)
+	profiler.ObserveLatency(2 * time.Second)
+	profiler.Stop()
+
+	captures := profiler.Captures()
+	require.Len(t, captures, 1)
+	assert.Equal(t, ProfileKindCPU, captures[0].Kind)
+	assert.Empty(t, captures[0].Error)
+	assert.Equal(t, server.URL+uploadedPath, captures[0].Location)
+	assert.Regexp(t, `^/profiles/cpu-\d{8}T\d{6}Z\.pprof$`, uploadedPath)
+	assert.Equal(t, "Bearer secret", authorization)
+	assert.Len(t, uploaded, captures[0].Bytes)
+}
+
+func TestProfiler_RecordsFailedUpload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	profiler := NewProfiler(ProfilerConfig{Destination: server.URL, HeapThresholdMB: 1})
+	profiler.heapInUse = func() uint64 { return 2 * 1024 * 1024 }
+	profiler.CheckHeap()
+	profiler.Stop()
+
+	captures := profiler.Captures()
+	require.Len(t, captures, 1)
+	assert.Contains(t, captures[0].Error, "status 403")
+	assert.Empty(t, captures[0].Location)
+}
@@ -0,0 +1,438 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	promptCanaryKeyPrefix = "prompt_canary:"
+
+	defaultCanaryFraction        = 0.1
+	defaultCanaryHorizon         = 15 * time.Minute
+	defaultCanaryMinSamples      = 20
+	defaultCanaryMaxUnderperform = 0.5
+	defaultCanaryCheckInterval   = time.Minute
+
+	// PromptCanaryRunning and PromptCanaryRolledBack are the states of a
+	// canary rollout.
+	PromptCanaryRunning    = "running"
+	PromptCanaryRolledBack = "rolled_back"
+)
+
+// PromptVersion is a prompt/model combination used for AI decisions.
+type PromptVersion struct {
+	// Name identifies the version in statistics and decision records.
+	Name string `json:"name"`
+	// Model overrides the LLM client's default model when set.
+	Model string `json:"model,omitempty"`
+	// PromptNotes are extra instructions appended to the system prompt.
+	PromptNotes string `json:"prompt_notes,omitempty"`
+}
+
+// PromptRouter picks the prompt version of a decision cycle and is told the
+// decisions it produced. It is implemented by PromptCanary.
+type PromptRouter interface {
+	Assign(ctx context.Context) PromptVersion
+	RecordDecision(ctx context.Context, version string, decision *AITradingDecision, price float64)
+}
+
+// PromptCanaryConfig configures a canary rollout.
+type PromptCanaryConfig struct {
+	// Control is the current version; Canary is the version being rolled out.
+	Control PromptVersion
+	Canary  PromptVersion
+	// Exchange is where decision outcomes are priced.
+	Exchange string
+	// Fraction is the share of decision cycles routed to the canary.
+	Fraction float64
+	// Horizon is how long after a decision its outcome is measured.
+	Horizon time.Duration
+	// MinSamples is the number of measured trades both versions need before
+	// the canary can be rolled back.
+	MinSamples int
+	// MaxUnderperformancePct is how far, in percentage points of average
+	// return per trade, the canary may trail the control.
+	MaxUnderperformancePct float64
+	// CheckInterval is how often pending outcomes are measured.
+	CheckInterval time.Duration
+}
+
+// PromptVersionStats are the measured outcomes of one version.
+type PromptVersionStats struct {
+	// Decisions counts every decision, Trades the buys and sells among them.
+	Decisions int `json:"decisions"`
+	Trades    int `json:"trades"`
+	// Measured counts trades whose outcome has been priced.
+	Measured int `json:"measured"`
+	// TotalReturnPct and AvgReturnPct are the directional price moves over
+	// the horizon, in percent.
+	TotalReturnPct float64 `json:"total_return_pct"`
+	AvgReturnPct   float64 `json:"avg_return_pct"`
+	Wins           int     `json:"wins"`
+}
+
+// pendingCanaryOutcome is a trade decision waiting for its horizon.
+type pendingCanaryOutcome struct {
+	Version   string    `json:"version"`
+	Symbol    string    `json:"symbol"`
+	Action    string    `json:"action"`
+	Price     float64   `json:"price"`
+	DecidedAt time.Time `json:"decided_at"`
+}
+
+// PromptCanaryStatus is the persisted state of a canary rollout.
+type PromptCanaryStatus struct {
+	Control PromptVersion                  `json:"control"`
+	Canary  PromptVersion                  `json:"canary"`
+	State   string                         `json:"state"`
+	Stats   map[string]*PromptVersionStats `json:"stats"`
+	// DeltaPct is the canary's average return minus the control's.
+	DeltaPct       float64                `json:"delta_pct"`
+	StartedAt      time.Time              `json:"started_at"`
+	RolledBackAt   *time.Time             `json:"rolled_back_at,omitempty"`
+	RollbackReason string                 `json:"rollback_reason,omitempty"`
+	Pending        []pendingCanaryOutcome `json:"pending,omitempty"`
+}
+
+// PromptCanary routes a fraction of AI decision cycles to a new prompt/model
+// version, measures both versions' outcomes and rolls the canary back when it
+// underperforms the control.
+type PromptCanary struct {
+	prices TickerFetcher
+	redis  *redis.Client
+	config PromptCanaryConfig
+	events EventEmitter
+	logger *slog.Logger
+	now    func() time.Time
+	random func() float64
+	// mu guards status, which is loaded from Redis on first use.
+	mu     sync.Mutex
+	status *PromptCanaryStatus
+	runMu  sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Ensure PromptCanary implements PromptRouter.
+var _ PromptRouter = (*PromptCanary)(nil)
+
+// NewPromptCanary creates a prompt canary rollout.
+//
+// Parameters:
+//
+//	prices: Ticker source used to measure outcomes.
+//	client: Redis client used to persist the rollout.
+//	config: Rollout configuration; zero values use defaults.
+//
+// Returns:
+//
+//	*PromptCanary: Initialized canary (measurement not started).
+func NewPromptCanary(prices TickerFetcher, client *redis.Client, config PromptCanaryConfig) *PromptCanary {
+	if config.Control.Name == "" {
+		config.Control.Name = "control"
+	}
+	if config.Canary.Name == "" {
+		config.Canary.Name = "canary"
+	}
+	if config.Exchange == "" {
+		config.Exchange = "binance"
+	}
+	if config.Fraction <= 0 || config.Fraction > 1 {
+		config.Fraction = defaultCanaryFraction
+	}
+	if config.Horizon <= 0 {
+		config.Horizon = defaultCanaryHorizon
+	}
+	if config.MinSamples <= 0 {
+		config.MinSamples = defaultCanaryMinSamples
+	}
+	if config.MaxUnderperformancePct <= 0 {
+		config.MaxUnderperformancePct = defaultCanaryMaxUnderperform
+	}
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = defaultCanaryCheckInterval
+	}
+	return &PromptCanary{
+		prices: prices,
+		redis:  client,
+		config: config,
+		logger: telemetry.Logger(),
+		now:    time.Now,
+		random: rand.Float64,
+	}
+}
+
+// SetEventEmitter publishes rollbacks as risk events.
+func (c *PromptCanary) SetEventEmitter(events EventEmitter) {
+	c.events = events
+}
+
+// Start measures pending outcomes once per check interval until Stop is called.
+func (c *PromptCanary) Start(ctx context.Context) error {
+	c.runMu.Lock()
+	defer c.runMu.Unlock()
+	if c.cancel != nil {
+		return fmt.Errorf("prompt canary already running")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	c.cancel = cancel
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(c.config.CheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := c.Evaluate(ctx); err != nil {
+					c.logger.Warn("Prompt canary evaluation failed", "error", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the measurement and waits for the running evaluation to finish.
+func (c *PromptCanary) Stop() {
+	c.runMu.Lock()
+	cancel := c.cancel
+	c.cancel = nil
+	c.runMu.Unlock()
+	if cancel != nil {
+		cancel()
+		c.wg.Wait()
+	}
+}
+
+// Assign picks the version for a decision cycle: the canary for the
+// configured fraction of cycles while it is running, the control otherwise.
+func (c *PromptCanary) Assign(ctx context.Context) PromptVersion {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	status, err := c.loadLocked(ctx)
+	if err != nil {
+		c.logger.Warn("Failed to load prompt canary, using control", "error", err)
+		return c.config.Control
+	}
+	if status.State == PromptCanaryRunning && c.random() < c.config.Fraction {
+		return status.Canary
+	}
+	return status.Control
+}
+
+// RecordDecision counts a decision of a version and queues trades for
+// outcome measurement at the decision price.
+func (c *PromptCanary) RecordDecision(ctx context.Context, version string, decision *AITradingDecision, price float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	status, err := c.loadLocked(ctx)
+	if err != nil {
+		c.logger.Warn("Failed to load prompt canary", "error", err)
+		return
+	}
+	stats, ok := status.Stats[version]
+	if !ok || decision == nil {
+		return
+	}
+	stats.Decisions++
+	if (decision.Action == "buy" || decision.Action == "sell") && price > 0 {
+		stats.Trades++
+		status.Pending = append(status.Pending, pendingCanaryOutcome{
+			Version:   version,
+			Symbol:    decision.Symbol,
+			Action:    decision.Action,
+			Price:     price,
+			DecidedAt: c.now().UTC(),
+		})
+	}
+	if err := c.saveLocked(ctx); err != nil {
+		c.logger.Warn("Failed to save prompt canary", "error", err)
+	}
+}
+
+// Evaluate measures the outcomes of trades past the horizon and rolls the
+// canary back when it trails the control by more than the allowed margin.
+//
+// Parameters:
+//
+//	ctx: Context for price lookups and persistence.
+//
+// Returns:
+//
+//	PromptCanaryStatus: The updated rollout status.
+//	error: Error if the status could not be loaded or saved.
+func (c *PromptCanary) Evaluate(ctx context.Context) (PromptCanaryStatus, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	status, err := c.loadLocked(ctx)
+	if err != nil {
+		return PromptCanaryStatus{}, err
+	}
+
+	now := c.now().UTC()
+	remaining := status.Pending[:0]
+	for _, pending := range status.Pending {
+		if now.Sub(pending.DecidedAt) < c.config.Horizon {
+			remaining = append(remaining, pending)
+			continue
+		}
+		ticker, err := c.prices.FetchSingleTicker(ctx, c.config.Exchange, pending.Symbol)
+		if err != nil || ticker.GetPrice() <= 0 {
+			// Retry on the next evaluation.
+			remaining = append(remaining, pending)
+			continue
+		}
+		returnPct := (ticker.GetPrice() - pending.Price) / pending.Price * 100
+		if pending.Action == "sell" {
+			returnPct = -returnPct
+		}
+		if stats, ok := status.Stats[pending.Version]; ok {
+			stats.Measured++
+			stats.TotalReturnPct += returnPct
+			stats.AvgReturnPct = stats.TotalReturnPct / float64(stats.Measured)
+			if returnPct > 0 {
+				stats.Wins++
+			}
+		}
+	}
+	status.Pending = remaining
+
+	control, canary := status.Stats[status.Control.Name], status.Stats[status.Canary.Name]
+	status.DeltaPct = canary.AvgReturnPct - control.AvgReturnPct
+	if status.State == PromptCanaryRunning &&
+		control.Measured >= c.config.MinSamples && canary.Measured >= c.config.MinSamples &&
+		status.DeltaPct < -c.config.MaxUnderperformancePct {
+		c.rollbackLocked(ctx, fmt.Sprintf("canary averaged %.2f%% per trade vs %.2f%% for control over %d/%d trades",
+			canary.AvgReturnPct, control.AvgReturnPct, canary.Measured, control.Measured))
+	}
+
+	if err := c.saveLocked(ctx); err != nil {
+		return PromptCanaryStatus{}, err
+	}
+	return c.snapshotLocked(), nil
+}
+
+// Status returns the rollout status.
+func (c *PromptCanary) Status(ctx context.Context) (PromptCanaryStatus, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := c.loadLocked(ctx); err != nil {
+		return PromptCanaryStatus{}, err
+	}
+	return c.snapshotLocked(), nil
+}
+
+// Rollback stops routing cycles to the canary.
+//
+// Parameters:
+//
+//	ctx: Context for persistence.
+//	reason: Why the canary is rolled back.
+//
+// Returns:
+//
+//	PromptCanaryStatus: The updated rollout status.
+//	error: Error if the status could not be loaded or saved.
+func (c *PromptCanary) Rollback(ctx context.Context, reason string) (PromptCanaryStatus, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	status, err := c.loadLocked(ctx)
+	if err != nil {
+		return PromptCanaryStatus{}, err
+	}
+	if status.State == PromptCanaryRunning {
+		c.rollbackLocked(ctx, reason)
+		if err := c.saveLocked(ctx); err != nil {
+			return PromptCanaryStatus{}, err
+		}
+	}
+	return c.snapshotLocked(), nil
+}
+
+func (c *PromptCanary) rollbackLocked(ctx context.Context, reason string) {
+	now := c.now().UTC()
+	c.status.State = PromptCanaryRolledBack
+	c.status.RolledBackAt = &now
+	c.status.RollbackReason = reason
+	c.logger.Warn("Prompt canary rolled back", "canary", c.status.Canary.Name, "reason", reason)
+	if c.events != nil {
+		c.events.Emit(ctx, WebhookEventRisk, map[string]interface{}{
+			"type":      "prompt_canary_rollback",
+			"severity":  "warning",
+			"canary":    c.status.Canary.Name,
+			"control":   c.status.Control.Name,
+			"delta_pct": c.status.DeltaPct,
+			"reason":    reason,
+		})
+	}
+}
+
+// loadLocked returns the rollout status, reading it from Redis the first
+// time. A canary that replaces the stored one starts a fresh rollout.
+func (c *PromptCanary) loadLocked(ctx context.Context) (*PromptCanaryStatus, error) {
+	if c.status != nil {
+		return c.status, nil
+	}
+	raw, err := c.redis.Get(ctx, c.key()).Result()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to load prompt canary: %w", err)
+	}
+	if err == nil {
+		var stored PromptCanaryStatus
+		if err := json.Unmarshal([]byte(raw), &stored); err == nil &&
+			stored.Control == c.config.Control && stored.Canary == c.config.Canary {
+			c.status = &stored
+			return c.status, nil
+		}
+	}
+	c.status = &PromptCanaryStatus{
+		Control: c.config.Control,
+		Canary:  c.config.Canary,
+		State:   PromptCanaryRunning,
+		Stats: map[string]*PromptVersionStats{
+			c.config.Control.Name: {},
+			c.config.Canary.Name:  {},
+		},
+		StartedAt: c.now().UTC(),
+	}
+	return c.status, nil
+}
+
+func (c *PromptCanary) saveLocked(ctx context.Context) error {
+	raw, err := json.Marshal(c.status)
+	if err != nil {
+		return err
+	}
+	if err := c.redis.Set(ctx, c.key(), raw, 0).Err(); err != nil {
+		return fmt.Errorf("failed to save prompt canary: %w", err)
+	}
+	return nil
+}
+
+func (c *PromptCanary) snapshotLocked() PromptCanaryStatus {
+	snapshot := *c.status
+	snapshot.Stats = make(map[string]*PromptVersionStats, len(c.status.Stats))
+	for name, stats := range c.status.Stats {
+		copied := *stats
+		snapshot.Stats[name] = &copied
+	}
+	snapshot.Pending = append([]pendingCanaryOutcome(nil), c.status.Pending...)
+	return snapshot
+}
+
+func (c *PromptCanary) key() string {
+	return promptCanaryKeyPrefix + c.config.Canary.Name
+}
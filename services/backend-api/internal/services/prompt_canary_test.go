@@ -0,0 +1,129 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPromptCanary(t *testing.T, client *redis.Client, prices TickerFetcher, config PromptCanaryConfig) *PromptCanary {
+	if client == nil {
+		s := miniredis.RunT(t)
+		client = redis.NewClient(&redis.Options{Addr: s.Addr()})
+		t.Cleanup(func() { _ = client.Close() })
+	}
+	return NewPromptCanary(prices, client, config)
+}
+
+func TestPromptCanary_RollsBackUnderperformingCanary(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	prices := &fakeTickerFetcher{prices: map[string]float64{"binance:BTC/USDT": 100}}
+	canary := newTestPromptCanary(t, nil, prices, PromptCanaryConfig{
+		Control:    PromptVersion{Name: "v1"},
+		Canary:     PromptVersion{Name: "v2", Model: "gpt-4o-mini"},
+		Fraction:   0.25,
+		Horizon:    10 * time.Minute,
+		MinSamples: 2,
+	})
+	canary.now = func() time.Time { return now }
+	emitter := &recordingEmitter{}
+	canary.SetEventEmitter(emitter)
+	ctx := t.Context()
+
+	canary.random = func() float64 { return 0.2 }
+	assert.Equal(t, "v2", canary.Assign(ctx).Name)
+	canary.random = func() float64 { return 0.3 }
+	assert.Equal(t, "v1", canary.Assign(ctx).Name)
+
+	for i := 0; i < 2; i++ {
+		canary.RecordDecision(ctx, "v1", &AITradingDecision{Action: "buy", Symbol: "BTC/USDT"}, 100)
+		canary.RecordDecision(ctx, "v2", &AITradingDecision{Action: "sell", Symbol: "BTC/USDT"}, 100)
+	}
+	canary.RecordDecision(ctx, "v2", &AITradingDecision{Action: "hold"}, 0)
+	canary.RecordDecision(ctx, "unknown", &AITradingDecision{Action: "buy", Symbol: "BTC/USDT"}, 100)
+
+	// Nothing is due before the horizon.
+	status, err := canary.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.Len(t, status.Pending, 4)
+	assert.Equal(t, PromptCanaryRunning, status.State)
+
+	now = now.Add(11 * time.Minute)
+	prices.set("binance:BTC/USDT", 102)
+	status, err = canary.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, status.Pending)
+	assert.Equal(t, PromptVersionStats{Decisions: 2, Trades: 2, Measured: 2, TotalReturnPct: 4, AvgReturnPct: 2, Wins: 2}, *status.Stats["v1"])
+	assert.Equal(t, 3, status.Stats["v2"].Decisions)
+	assert.InDelta(t, -2, status.Stats["v2"].AvgReturnPct, 1e-9)
+	assert.InDelta(t, -4, status.DeltaPct, 1e-9)
+	assert.Equal(t, PromptCanaryRolledBack, status.State)
+	assert.Contains(t, status.RollbackReason, "canary averaged -2.00%")
+	assert.Equal(t, []WebhookEventType{WebhookEventRisk}, emitter.events)
+
+	// Every cycle uses the control after a rollback.
+	canary.random = func() float64 { return 0 }
+	assert.Equal(t, "v1", canary.Assign(ctx).Name)
+}
+
+func TestPromptCanary_KeepsCanaryWithinThreshold(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	prices := &fakeTickerFetcher{prices: map[string]float64{"binance:ETH/USDT": 10}}
+	canary := newTestPromptCanary(t, nil, prices, PromptCanaryConfig{MinSamples: 1, MaxUnderperformancePct: 1})
+	canary.now = func() time.Time { return now }
+	ctx := t.Context()
+
+	canary.RecordDecision(ctx, "control", &AITradingDecision{Action: "buy", Symbol: "ETH/USDT"}, 10)
+	canary.RecordDecision(ctx, "canary", &AITradingDecision{Action: "buy", Symbol: "ETH/USDT"}, 10.05)
+	now = now.Add(defaultCanaryHorizon)
+	prices.set("binance:ETH/USDT", 10.1)
+
+	status, err := canary.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, PromptCanaryRunning, status.State)
+	assert.InDelta(t, -0.5, status.DeltaPct, 0.01)
+
+	status, err = canary.Rollback(ctx, "manual")
+	require.NoError(t, err)
+	assert.Equal(t, PromptCanaryRolledBack, status.State)
+	assert.Equal(t, "manual", status.RollbackReason)
+}
+
+func TestPromptCanary_PersistsAndRestartsForNewVersion(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	prices := &fakeTickerFetcher{prices: map[string]float64{}}
+	config := PromptCanaryConfig{Canary: PromptVersion{Name: "v2", PromptNotes: "Prefer holds in chop."}}
+	ctx := t.Context()
+
+	first := newTestPromptCanary(t, client, prices, config)
+	_, err := first.Rollback(ctx, "manual")
+	require.NoError(t, err)
+
+	restored := newTestPromptCanary(t, client, prices, config)
+	status, err := restored.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, PromptCanaryRolledBack, status.State)
+
+	config.Canary.PromptNotes = "Prefer holds in low volume."
+	changed := newTestPromptCanary(t, client, prices, config)
+	status, err = changed.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, PromptCanaryRunning, status.State)
+}
+
+func TestAIScalpingService_PromptNotesFollowVersion(t *testing.T) {
+	config := DefaultAIScalpingConfig()
+	config.PromptNotes = "Live notes."
+	svc := NewAIScalpingService(config, nil, nil, nil, nil, nil)
+
+	assert.Contains(t, svc.buildSystemPrompt(PromptVersion{}), "Live notes.")
+	prompt := svc.buildSystemPrompt(PromptVersion{Name: "v2", PromptNotes: "Canary notes."})
+	assert.Contains(t, prompt, "Canary notes.")
+	assert.NotContains(t, prompt, "Live notes.")
+}
@@ -0,0 +1,356 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/irfndi/neuratrade/internal/ai"
+	"github.com/irfndi/neuratrade/internal/models"
+	"github.com/irfndi/neuratrade/pkg/interfaces"
+)
+
+// Names of the scalping prompt context sources.
+const (
+	ContextSourceIndicators       = "indicators"
+	ContextSourceRegime           = "regime"
+	ContextSourceSimilarDecisions = "similar_decisions"
+	ContextSourceNews             = "news"
+	ContextSourcePositions        = "positions"
+	ContextSourceRiskBudget       = "risk_budget"
+	ContextSourceTradeMemory      = "trade_memory"
+)
+
+// DefaultScalpingContextTokens bounds the context of a scalping prompt.
+const DefaultScalpingContextTokens = 6000
+
+// contextTruncatedMarker ends a section cut to its token budget.
+const contextTruncatedMarker = "\n[truncated]"
+
+// ContextInput is the state of a scalping cycle that context sources render.
+type ContextInput struct {
+	Exchange string
+	// Symbol is the top-ranked signal's symbol.
+	Symbol string
+	// Signals are the market signals of the cycle as indented JSON.
+	Signals json.RawMessage
+	// TopSignal is the top-ranked signal as JSON.
+	TopSignal json.RawMessage
+	Portfolio TradingPortfolio
+	// MinConfidence and MaxCapitalPct are the cycle's risk thresholds.
+	MinConfidence float64
+	MaxCapitalPct float64
+	Leverage      int
+}
+
+// ContextSource renders one section of a prompt.
+type ContextSource interface {
+	Name() string
+	// Build returns the section, or an empty string when there is nothing
+	// to add.
+	Build(ctx context.Context, input ContextInput) (string, error)
+}
+
+// ContextSectionTrace records what a source contributed to a prompt.
+type ContextSectionTrace struct {
+	Source    string `json:"source"`
+	Included  bool   `json:"included"`
+	Tokens    int    `json:"tokens"`
+	Budget    int    `json:"budget"`
+	Truncated bool   `json:"truncated,omitempty"`
+	// Skipped is why nothing was included: "empty", "error" or "no budget".
+	Skipped string `json:"skipped,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ContextTrace records what a built prompt context contains.
+type ContextTrace struct {
+	Sections []ContextSectionTrace `json:"sections"`
+	Tokens   int                   `json:"tokens"`
+	Budget   int                   `json:"budget"`
+}
+
+// String summarizes the trace as source=tokens/budget pairs.
+func (t ContextTrace) String() string {
+	parts := make([]string, 0, len(t.Sections))
+	for _, section := range t.Sections {
+		switch {
+		case section.Included && section.Truncated:
+			parts = append(parts, fmt.Sprintf("%s=%d/%d(truncated)", section.Source, section.Tokens, section.Budget))
+		case section.Included:
+			parts = append(parts, fmt.Sprintf("%s=%d/%d", section.Source, section.Tokens, section.Budget))
+		default:
+			parts = append(parts, section.Source+"="+section.Skipped)
+		}
+	}
+	return fmt.Sprintf("%d/%d tokens: %s", t.Tokens, t.Budget, strings.Join(parts, " "))
+}
+
+type contextSection struct {
+	source    ContextSource
+	maxTokens int
+}
+
+// ContextBuilder assembles a prompt from context sources in order. Each
+// source has a token budget and the prompt a total one; sections over their
+// budget are truncated and sections past the total are left out.
+type ContextBuilder struct {
+	sections  []contextSection
+	maxTokens int
+}
+
+// NewContextBuilder creates a context builder.
+//
+// Parameters:
+//
+//	maxTokens: Total token budget; zero or less uses DefaultScalpingContextTokens.
+//
+// Returns:
+//
+//	*ContextBuilder: Builder without sources.
+func NewContextBuilder(maxTokens int) *ContextBuilder {
+	if maxTokens <= 0 {
+		maxTokens = DefaultScalpingContextTokens
+	}
+	return &ContextBuilder{maxTokens: maxTokens}
+}
+
+// Add appends a source, replacing an earlier source of the same name in its
+// position.
+//
+// Parameters:
+//
+//	source: The source.
+//	maxTokens: The source's token budget; zero or less leaves it bounded
+//	only by the total budget.
+func (b *ContextBuilder) Add(source ContextSource, maxTokens int) {
+	section := contextSection{source: source, maxTokens: maxTokens}
+	for i := range b.sections {
+		if b.sections[i].source.Name() == source.Name() {
+			b.sections[i] = section
+			return
+		}
+	}
+	b.sections = append(b.sections, section)
+}
+
+// Build renders every source within its budget. A failing source is left
+// out and recorded in the trace; it does not fail the prompt.
+//
+// Parameters:
+//
+//	ctx: Context for the sources.
+//	input: The cycle state.
+//
+// Returns:
+//
+//	string: The sections, separated by blank lines.
+//	ContextTrace: What was included.
+func (b *ContextBuilder) Build(ctx context.Context, input ContextInput) (string, ContextTrace) {
+	trace := ContextTrace{Budget: b.maxTokens, Sections: make([]ContextSectionTrace, 0, len(b.sections))}
+	parts := make([]string, 0, len(b.sections))
+	for _, section := range b.sections {
+		entry := ContextSectionTrace{Source: section.source.Name(), Budget: section.maxTokens}
+		remaining := b.maxTokens - trace.Tokens
+		if entry.Budget <= 0 || entry.Budget > remaining {
+			entry.Budget = remaining
+		}
+
+		text, err := section.source.Build(ctx, input)
+		text = strings.TrimSpace(text)
+		switch {
+		case err != nil:
+			entry.Skipped = "error"
+			entry.Error = err.Error()
+		case text == "":
+			entry.Skipped = "empty"
+		case entry.Budget <= ai.EstimateTokens(contextTruncatedMarker):
+			entry.Skipped = "no budget"
+		default:
+			if ai.EstimateTokens(text) > entry.Budget {
+				text = truncateToTokens(text, entry.Budget)
+				entry.Truncated = true
+			}
+			entry.Included = true
+			entry.Tokens = ai.EstimateTokens(text)
+			trace.Tokens += entry.Tokens
+			parts = append(parts, text)
+		}
+		trace.Sections = append(trace.Sections, entry)
+	}
+	return strings.Join(parts, "\n\n"), trace
+}
+
+// truncateToTokens cuts text to the token budget, preferring a line break,
+// and marks the cut.
+func truncateToTokens(text string, budget int) string {
+	limit := (budget - ai.EstimateTokens(contextTruncatedMarker)) * 4
+	if limit >= len(text) {
+		return text
+	}
+	cut := text[:limit]
+	if i := strings.LastIndexByte(cut, '\n'); i > limit/2 {
+		cut = cut[:i]
+	}
+	return strings.ToValidUTF8(cut, "") + contextTruncatedMarker
+}
+
+// contextSourceFunc adapts a function to ContextSource.
+type contextSourceFunc struct {
+	name  string
+	build func(ctx context.Context, input ContextInput) (string, error)
+}
+
+func (f contextSourceFunc) Name() string { return f.name }
+
+func (f contextSourceFunc) Build(ctx context.Context, input ContextInput) (string, error) {
+	return f.build(ctx, input)
+}
+
+// NewContextSource creates a context source from a function.
+//
+// Parameters:
+//
+//	name: Source name used in traces and to replace sources.
+//	build: Renders the section.
+//
+// Returns:
+//
+//	ContextSource: The source.
+func NewContextSource(name string, build func(ctx context.Context, input ContextInput) (string, error)) ContextSource {
+	return contextSourceFunc{name: name, build: build}
+}
+
+// NewIndicatorsContextSource renders the cycle's market signals.
+func NewIndicatorsContextSource() ContextSource {
+	return NewContextSource(ContextSourceIndicators, func(_ context.Context, input ContextInput) (string, error) {
+		if len(input.Signals) == 0 {
+			return "", nil
+		}
+		return "## Market Signals\n" + string(input.Signals), nil
+	})
+}
+
+// NewRiskBudgetContextSource renders the cycle's risk limits.
+func NewRiskBudgetContextSource() ContextSource {
+	return NewContextSource(ContextSourceRiskBudget, func(_ context.Context, input ContextInput) (string, error) {
+		var b strings.Builder
+		b.WriteString("## Risk Budget\n")
+		fmt.Fprintf(&b, "- Minimum confidence to trade: %.2f\n", input.MinConfidence)
+		fmt.Fprintf(&b, "- Maximum position size: %.1f%% of portfolio", input.MaxCapitalPct)
+		if input.Leverage > 0 {
+			fmt.Fprintf(&b, "\n- Leverage: %dx", input.Leverage)
+		}
+		return b.String(), nil
+	})
+}
+
+// RegimeDetector classifies a symbol's trend and volatility. It is
+// implemented by AnalyticsService.
+type RegimeDetector interface {
+	DetectMarketRegime(ctx context.Context, exchange string, symbol string, limit int) (*models.MarketRegime, error)
+}
+
+// NewRegimeContextSource renders the top symbol's market regime.
+func NewRegimeContextSource(detector RegimeDetector) ContextSource {
+	return NewContextSource(ContextSourceRegime, func(ctx context.Context, input ContextInput) (string, error) {
+		if input.Symbol == "" {
+			return "", nil
+		}
+		regime, err := detector.DetectMarketRegime(ctx, input.Exchange, input.Symbol, 0)
+		if err != nil || regime == nil {
+			return "", err
+		}
+		return fmt.Sprintf("## Market Regime\n- %s: %s trend (strength %.2f), %s volatility (score %.2f), confidence %.2f",
+			input.Symbol, regime.Trend, regime.TrendStrength, regime.Volatility, regime.VolatilityScore, regime.Confidence), nil
+	})
+}
+
+// NewSimilarDecisionsContextSource renders what happened after past
+// decisions made in situations like the top signal's.
+func NewSimilarDecisionsContextSource(finder SimilarDecisionFinder) ContextSource {
+	return NewContextSource(ContextSourceSimilarDecisions, func(ctx context.Context, input ContextInput) (string, error) {
+		if input.Symbol == "" {
+			return "", nil
+		}
+		similar, err := finder.FindSimilarDecisions(ctx, DecisionContext{
+			Exchange:       input.Exchange,
+			Symbol:         input.Symbol,
+			MarketSnapshot: input.TopSignal,
+		}, 0)
+		if err != nil {
+			return "", err
+		}
+		return FormatSimilarDecisions(similar), nil
+	})
+}
+
+// RecentNewsReader returns the latest news about a symbol. It is implemented
+// by SentimentService.
+type RecentNewsReader interface {
+	RecentNews(ctx context.Context, symbol string, limit int) ([]NewsSentiment, error)
+}
+
+// recentNewsLimit is the number of headlines added to a prompt.
+const recentNewsLimit = 5
+
+// NewNewsContextSource renders the latest headlines about the top symbol.
+func NewNewsContextSource(news RecentNewsReader) ContextSource {
+	return NewContextSource(ContextSourceNews, func(ctx context.Context, input ContextInput) (string, error) {
+		if input.Symbol == "" {
+			return "", nil
+		}
+		articles, err := news.RecentNews(ctx, input.Symbol, recentNewsLimit)
+		if err != nil || len(articles) == 0 {
+			return "", err
+		}
+		var b strings.Builder
+		b.WriteString("## Recent News")
+		for _, article := range articles {
+			fmt.Fprintf(&b, "\n- %s [%s %+.2f] %s", article.PublishedAt.UTC().Format("2006-01-02 15:04"),
+				article.SentimentLabel, article.SentimentScore, article.Title)
+		}
+		return b.String(), nil
+	})
+}
+
+// OpenPositionReader lists open positions. It is implemented by
+// PositionTracker.
+type OpenPositionReader interface {
+	GetOpenPositions() []interfaces.Position
+}
+
+// NewPositionsContextSource renders the portfolio and, when positions is set,
+// each open position.
+func NewPositionsContextSource(positions OpenPositionReader) ContextSource {
+	return NewContextSource(ContextSourcePositions, func(_ context.Context, input ContextInput) (string, error) {
+		var open []interfaces.Position
+		openCount := input.Portfolio.OpenPositions
+		if positions != nil {
+			open = positions.GetOpenPositions()
+			openCount = max(openCount, len(open))
+		}
+		var b strings.Builder
+		b.WriteString("## Portfolio\n")
+		fmt.Fprintf(&b, "- USDT Balance: %.2f\n", input.Portfolio.USDTBalance)
+		fmt.Fprintf(&b, "- Total Value: %.2f\n", input.Portfolio.TotalValue)
+		fmt.Fprintf(&b, "- Open Positions: %d", openCount)
+		for _, position := range open {
+			fmt.Fprintf(&b, "\n  - %s %s size %s entry %s mark %s", position.Side, position.Symbol,
+				position.Size.String(), position.EntryPrice.String(), position.CurrentPrice.String())
+			if position.Leverage.IsPositive() {
+				fmt.Fprintf(&b, " %sx", position.Leverage.String())
+			}
+		}
+		return b.String(), nil
+	})
+}
+
+// NewTradeMemoryContextSource renders lessons from past trades of the top
+// symbol.
+func NewTradeMemoryContextSource(memory *TradeMemory) ContextSource {
+	return NewContextSource(ContextSourceTradeMemory, func(ctx context.Context, input ContextInput) (string, error) {
+		return memory.BuildMemoryContext(ctx, input.Symbol, string(input.Signals))
+	})
+}
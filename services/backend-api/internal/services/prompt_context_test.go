@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/models"
+	"github.com/irfndi/neuratrade/pkg/interfaces"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func staticContext(name, text string) ContextSource {
+	return NewContextSource(name, func(context.Context, ContextInput) (string, error) { return text, nil })
+}
+
+func TestContextBuilder_Build(t *testing.T) {
+	builder := NewContextBuilder(100)
+	builder.Add(staticContext("first", "## First\nalpha"), 0)
+	builder.Add(NewContextSource("broken", func(context.Context, ContextInput) (string, error) {
+		return "", errors.New("source down")
+	}), 50)
+	builder.Add(staticContext("empty", "  "), 50)
+	builder.Add(staticContext("long", "## Long\n"+strings.Repeat("line of context\n", 40)), 40)
+	builder.Add(staticContext("rest", strings.Repeat("x", 400)), 0)
+
+	text, trace := builder.Build(t.Context(), ContextInput{})
+
+	assert.True(t, strings.HasPrefix(text, "## First\nalpha\n\n## Long\nline of context\n"))
+	assert.NotContains(t, text, "source down")
+	require.Len(t, trace.Sections, 5)
+	assert.Equal(t, ContextSectionTrace{Source: "first", Included: true, Tokens: 4, Budget: 100}, trace.Sections[0])
+	assert.Equal(t, ContextSectionTrace{Source: "broken", Budget: 50, Skipped: "error", Error: "source down"}, trace.Sections[1])
+	assert.Equal(t, "empty", trace.Sections[2].Skipped)
+
+	long := trace.Sections[3]
+	assert.True(t, long.Included)
+	assert.True(t, long.Truncated)
+	assert.LessOrEqual(t, long.Tokens, 40)
+	assert.Contains(t, text, "line of context\n[truncated]", "sections are cut at a line break")
+
+	// The last section only gets what is left of the total budget
+	rest := trace.Sections[4]
+	assert.Equal(t, 100-4-long.Tokens, rest.Budget)
+	assert.True(t, rest.Truncated)
+	assert.LessOrEqual(t, trace.Tokens, 100)
+	assert.Equal(t, 4+long.Tokens+rest.Tokens, trace.Tokens)
+
+	assert.Contains(t, trace.String(), "first=4/100 broken=error empty=empty long=")
+}
+
+func TestContextBuilder_AddReplacesSource(t *testing.T) {
+	builder := NewContextBuilder(0)
+	builder.Add(staticContext("a", "one"), 0)
+	builder.Add(staticContext("b", "two"), 0)
+	builder.Add(staticContext("a", "three"), 0)
+
+	text, trace := builder.Build(t.Context(), ContextInput{})
+	assert.Equal(t, "three\n\ntwo", text)
+	assert.Equal(t, DefaultScalpingContextTokens, trace.Budget)
+}
+
+type fixedRegime struct{}
+
+func (fixedRegime) DetectMarketRegime(_ context.Context, exchange, symbol string, _ int) (*models.MarketRegime, error) {
+	return &models.MarketRegime{Symbol: symbol, Exchange: exchange, Trend: "bullish", Volatility: "high", TrendStrength: 0.62, VolatilityScore: 0.8, Confidence: 0.7}, nil
+}
+
+type fixedNews struct {
+	symbol string
+}
+
+func (f *fixedNews) RecentNews(_ context.Context, symbol string, limit int) ([]NewsSentiment, error) {
+	f.symbol = symbol
+	return []NewsSentiment{{Title: "ETF inflows hit a record", SentimentLabel: "bullish", SentimentScore: 0.6, PublishedAt: time.Date(2026, 9, 1, 8, 0, 0, 0, time.UTC)}}, nil
+}
+
+type fixedPositions []interfaces.Position
+
+func (f fixedPositions) GetOpenPositions() []interfaces.Position { return f }
+
+func TestAIScalpingService_BuildUserPrompt(t *testing.T) {
+	svc := NewAIScalpingService(DefaultAIScalpingConfig(), nil, nil, nil, nil, nil)
+	news := &fixedNews{}
+	svc.SetRegimeSource(fixedRegime{})
+	svc.SetNewsSource(news)
+	svc.SetOpenPositionSource(fixedPositions{{
+		Symbol: "ETH/USDT", Side: "BUY", Size: decimal.RequireFromString("0.5"),
+		EntryPrice: decimal.NewFromInt(2500), CurrentPrice: decimal.NewFromInt(2550), Leverage: decimal.NewFromInt(5),
+	}})
+	signals := []aiMarketSignal{{Symbol: "BTC/USDT", Price: 65000, OrderBookImbalance: 0.3}}
+
+	prompt, trace := svc.buildUserPrompt(t.Context(), signals, TradingPortfolio{USDTBalance: 1000, TotalValue: 1200})
+
+	assert.True(t, strings.HasPrefix(prompt, "Analyze these market signals and make a trading decision.\n\n## Portfolio\n- USDT Balance: 1000.00\n"))
+	assert.True(t, strings.HasSuffix(prompt, "Return only valid JSON."))
+	assert.Contains(t, prompt, "- Open Positions: 1\n  - BUY ETH/USDT size 0.5 entry 2500 mark 2550 5x")
+	assert.Contains(t, prompt, "## Risk Budget\n- Minimum confidence to trade: 0.70\n")
+	assert.Contains(t, prompt, "## Market Regime\n- BTC/USDT: bullish trend (strength 0.62), high volatility (score 0.80), confidence 0.70")
+	assert.Contains(t, prompt, "## Market Signals\n[\n  {\n    \"symbol\": \"BTC/USDT\"")
+	assert.Contains(t, prompt, "## Recent News\n- 2026-09-01 08:00 [bullish +0.60] ETF inflows hit a record")
+	assert.Equal(t, "BTC/USDT", news.symbol)
+
+	var sources []string
+	for _, section := range trace.Sections {
+		assert.True(t, section.Included, section.Source)
+		sources = append(sources, section.Source)
+	}
+	assert.Equal(t, []string{ContextSourcePositions, ContextSourceRiskBudget, ContextSourceRegime, ContextSourceIndicators, ContextSourceNews}, sources)
+
+	// A configured builder replaces the default sources
+	custom := NewContextBuilder(500)
+	custom.Add(NewIndicatorsContextSource(), 0)
+	svc.SetContextBuilder(custom)
+	prompt, trace = svc.buildUserPrompt(t.Context(), signals, TradingPortfolio{})
+	assert.NotContains(t, prompt, "## Portfolio")
+	require.Len(t, trace.Sections, 1)
+	assert.Equal(t, 500, trace.Budget)
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"time"
+)
+
+// QuestCatchUpPolicy decides what a quest does about runs it missed while the
+// server was down or quest execution was held.
+type QuestCatchUpPolicy string
+
+const (
+	// CatchUpRunOnce runs the quest once on resume, then keeps its cadence.
+	CatchUpRunOnce QuestCatchUpPolicy = "run_once"
+	// CatchUpSkip drops the missed runs and waits for the next cadence boundary.
+	CatchUpSkip QuestCatchUpPolicy = "skip"
+	// CatchUpBackfill runs the quest once per missed boundary, oldest first,
+	// one run per scheduler tick.
+	CatchUpBackfill QuestCatchUpPolicy = "backfill"
+)
+
+// questCatchUpKey is the quest metadata key holding its catch-up policy.
+const questCatchUpKey = "catch_up"
+
+const (
+	defaultQuestStartJitter = 2 * time.Minute
+	defaultQuestMaxBackfill = 24
+	questClockSkewTolerance = time.Second
+)
+
+var (
+	// ErrInvalidCatchUpPolicy is returned for an unknown catch-up policy.
+	ErrInvalidCatchUpPolicy = errors.New("invalid catch-up policy")
+	// ErrQuestNotFound is returned when a quest does not exist for the chat.
+	ErrQuestNotFound = errors.New("quest not found")
+)
+
+// ParseCatchUpPolicy validates a catch-up policy name.
+func ParseCatchUpPolicy(raw string) (QuestCatchUpPolicy, error) {
+	switch policy := QuestCatchUpPolicy(raw); policy {
+	case CatchUpRunOnce, CatchUpSkip, CatchUpBackfill:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("%w %q (use run_once, skip or backfill)", ErrInvalidCatchUpPolicy, raw)
+	}
+}
+
+// QuestResumeConfig controls how quests resume after a restart or a hold.
+type QuestResumeConfig struct {
+	// StartJitter spreads the first runs after a resume over this window, so
+	// quests do not all fire in the same tick.
+	StartJitter time.Duration
+	// MaxBackfillRuns caps the runs a backfill quest catches up; the most
+	// recent missed boundaries are kept.
+	MaxBackfillRuns int
+}
+
+type questScheduledForKey struct{}
+
+// QuestScheduledFor returns the cadence boundary a backfill run stands in for.
+// It reports false for regular runs.
+func QuestScheduledFor(ctx context.Context) (time.Time, bool) {
+	slot, ok := ctx.Value(questScheduledForKey{}).(time.Time)
+	return slot, ok
+}
+
+// SetResumeConfig sets the start-up jitter and backfill cap; zero values use
+// the defaults and a negative StartJitter disables the jitter.
+func (e *QuestEngine) SetResumeConfig(config QuestResumeConfig) {
+	if config.StartJitter < 0 {
+		config.StartJitter = 0
+	} else if config.StartJitter == 0 {
+		config.StartJitter = defaultQuestStartJitter
+	}
+	if config.MaxBackfillRuns <= 0 {
+		config.MaxBackfillRuns = defaultQuestMaxBackfill
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.resume = config
+}
+
+// SetQuestCatchUp sets the catch-up policy of one of a chat's quests.
+//
+// Parameters:
+//
+//	questID: Quest to update.
+//	chatID: Chat that owns the quest.
+//	policy: run_once, skip or backfill.
+//
+// Returns:
+//
+//	*Quest: Updated quest.
+//	error: ErrQuestNotFound or ErrInvalidCatchUpPolicy.
+func (e *QuestEngine) SetQuestCatchUp(questID, chatID string, policy QuestCatchUpPolicy) (*Quest, error) {
+	if _, err := ParseCatchUpPolicy(string(policy)); err != nil {
+		return nil, err
+	}
+	e.mu.Lock()
+	quest, ok := e.quests[questID]
+	if !ok || quest.Metadata["chat_id"] != chatID {
+		e.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrQuestNotFound, questID)
+	}
+	if quest.Metadata == nil {
+		quest.Metadata = make(map[string]string)
+	}
+	quest.Metadata[questCatchUpKey] = string(policy)
+	quest.UpdatedAt = time.Now()
+	e.mu.Unlock()
+
+	if e.store != nil {
+		if err := e.store.SaveQuest(context.Background(), quest); err != nil {
+			log.Printf("Failed to persist quest %s: %v", quest.ID, err)
+		}
+	}
+	return quest, nil
+}
+
+// questCatchUp returns a quest's catch-up policy, run_once when unset.
+func questCatchUp(quest *Quest) QuestCatchUpPolicy {
+	if policy, err := ParseCatchUpPolicy(quest.Metadata[questCatchUpKey]); err == nil {
+		return policy
+	}
+	return CatchUpRunOnce
+}
+
+// resumeLocked starts a new resume window: every active quest is planned
+// again on its next tick. Callers hold e.mu.
+func (e *QuestEngine) resumeLocked(at time.Time) {
+	e.resumedAt = at
+	e.planned = make(map[string]bool)
+	e.notBefore = make(map[string]time.Time)
+	e.backfill = make(map[string][]time.Time)
+}
+
+// planCatchUpLocked decides when a quest first runs after the last resume.
+// Boundaries that passed before the resume were missed; the quest's policy
+// decides whether they run once, are skipped or are backfilled. Immediate
+// runs get a random start offset within the jitter window. Measuring from
+// the last run rather than wall-clock boundaries keeps the plan stable when
+// the clock is corrected. Callers hold e.mu.
+func (e *QuestEngine) planCatchUpLocked(quest *Quest, now time.Time) {
+	e.planned[quest.ID] = true
+	interval, scheduled := e.cadenceInterval(quest)
+	if !scheduled {
+		return
+	}
+
+	startAt := e.resumedAt
+	if e.resume.StartJitter > 0 {
+		startAt = startAt.Add(e.jitter(e.resume.StartJitter))
+	}
+	if quest.LastExecutedAt == nil {
+		e.notBefore[quest.ID] = startAt
+		return
+	}
+	if skew := quest.LastExecutedAt.Sub(now); skew > questClockSkewTolerance {
+		// The clock moved backwards since the last run; without a reset the
+		// quest would wait until the clock catches up again
+		log.Printf("Quest %s last ran %s in the future; clock moved backwards, resetting", quest.ID, skew.Round(time.Second))
+		last := e.resumedAt.Add(-interval)
+		quest.LastExecutedAt = &last
+	}
+
+	last := *quest.LastExecutedAt
+	missed := 0
+	if e.resumedAt.After(last) {
+		missed = int(e.resumedAt.Sub(last) / interval)
+	}
+	if missed == 0 {
+		e.notBefore[quest.ID] = startAt
+		return
+	}
+
+	switch policy := questCatchUp(quest); policy {
+	case CatchUpSkip:
+		next := last.Add(time.Duration(missed+1) * interval)
+		e.notBefore[quest.ID] = next
+		log.Printf("Quest %s skipping %d missed run(s), next run at %s", quest.ID, missed, next.Format(time.RFC3339))
+	case CatchUpBackfill:
+		runs := missed
+		if runs > e.resume.MaxBackfillRuns {
+			runs = e.resume.MaxBackfillRuns
+		}
+		slots := make([]time.Time, 0, runs)
+		for k := missed - runs + 1; k <= missed; k++ {
+			slots = append(slots, last.Add(time.Duration(k)*interval))
+		}
+		e.backfill[quest.ID] = slots
+		e.notBefore[quest.ID] = startAt
+		log.Printf("Quest %s backfilling %d of %d missed run(s)", quest.ID, runs, missed)
+	default:
+		e.notBefore[quest.ID] = startAt
+		log.Printf("Quest %s missed %d run(s), running once on resume", quest.ID, missed)
+	}
+}
+
+// defaultQuestJitter returns a random offset in [0, max).
+func defaultQuestJitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return rand.N(max)
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuestEngine_PlanCatchUp(t *testing.T) {
+	resumed := time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC)
+	last := resumed.Add(-84 * time.Hour) // three and a half days ago
+	engine := NewQuestEngine(nil)
+	engine.SetResumeConfig(QuestResumeConfig{MaxBackfillRuns: 2})
+	offsets := 0
+	engine.jitter = func(max time.Duration) time.Duration {
+		assert.Equal(t, defaultQuestStartJitter, max)
+		offsets++
+		return time.Duration(offsets) * 10 * time.Second
+	}
+	daily := func(id string, policy QuestCatchUpPolicy) *Quest {
+		lastRun := last
+		return &Quest{ID: id, Cadence: CadenceDaily, Status: QuestStatusActive, LastExecutedAt: &lastRun,
+			Metadata: map[string]string{questCatchUpKey: string(policy)}}
+	}
+
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+	engine.resumeLocked(resumed)
+
+	engine.planCatchUpLocked(daily("once", ""), resumed)
+	assert.Equal(t, resumed.Add(10*time.Second), engine.notBefore["once"], "run_once is the default and starts within the jitter window")
+	assert.Empty(t, engine.backfill["once"])
+
+	engine.planCatchUpLocked(daily("skip", CatchUpSkip), resumed)
+	assert.Equal(t, last.Add(4*24*time.Hour), engine.notBefore["skip"], "skip waits for the next boundary")
+
+	engine.planCatchUpLocked(daily("fill", CatchUpBackfill), resumed)
+	assert.Equal(t, []time.Time{last.Add(48 * time.Hour), last.Add(72 * time.Hour)}, engine.backfill["fill"],
+		"only the most recent missed boundaries are backfilled")
+
+	fresh := &Quest{ID: "fresh", Cadence: CadenceMicro, Status: QuestStatusActive}
+	engine.planCatchUpLocked(fresh, resumed)
+	assert.Equal(t, resumed.Add(40*time.Second), engine.notBefore["fresh"], "first runs are jittered too")
+
+	future := resumed.Add(time.Hour)
+	skewed := &Quest{ID: "skewed", Cadence: CadenceHourly, Status: QuestStatusActive, LastExecutedAt: &future,
+		Metadata: map[string]string{questCatchUpKey: string(CatchUpSkip)}}
+	engine.planCatchUpLocked(skewed, resumed)
+	assert.Equal(t, resumed.Add(-time.Hour), *skewed.LastExecutedAt, "a run in the future is reset after the clock moved back")
+	assert.Equal(t, resumed.Add(time.Hour), engine.notBefore["skewed"])
+	assert.True(t, engine.planned["skewed"])
+}
+
+func TestQuestEngine_BackfillRunsOnePerTick(t *testing.T) {
+	engine := NewQuestEngine(nil)
+	engine.jitter = func(time.Duration) time.Duration { return 0 }
+	slots := make(chan time.Time, 4)
+	engine.RegisterHandler(QuestTypeRoutine, func(ctx context.Context, _ *Quest) error {
+		slot, _ := QuestScheduledFor(ctx)
+		slots <- slot
+		return nil
+	})
+	last := time.Now().Add(-150 * time.Minute)
+	engine.quests["report"] = &Quest{ID: "report", Type: QuestTypeRoutine, Cadence: CadenceHourly, Status: QuestStatusActive,
+		LastExecutedAt: &last, Metadata: map[string]string{questCatchUpKey: string(CatchUpBackfill)}}
+	engine.mu.Lock()
+	engine.resumeLocked(time.Now())
+	engine.mu.Unlock()
+
+	idle := func() bool {
+		engine.mu.RLock()
+		defer engine.mu.RUnlock()
+		return !engine.inFlight["report"]
+	}
+	for _, want := range []time.Time{last.Add(time.Hour), last.Add(2 * time.Hour)} {
+		engine.tick()
+		select {
+		case slot := <-slots:
+			assert.True(t, want.Equal(slot), "backfill slot %s, want %s", slot, want)
+		case <-time.After(time.Second):
+			t.Fatal("backfill run did not execute")
+		}
+		require.Eventually(t, idle, time.Second, 5*time.Millisecond)
+	}
+
+	// Caught up: the quest just ran, so the regular cadence waits an hour
+	engine.tick()
+	select {
+	case slot := <-slots:
+		t.Fatalf("unexpected run for %s after the backfill", slot)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestQuestEngine_SetQuestCatchUp(t *testing.T) {
+	engine := NewQuestEngine(NewInMemoryQuestStore())
+	quest, err := engine.CreateQuest("scalping_execution", "42")
+	require.NoError(t, err)
+	assert.Equal(t, CatchUpSkip, questCatchUp(quest), "the definition sets the default policy")
+
+	_, err = engine.SetQuestCatchUp(quest.ID, "42", "later")
+	assert.ErrorIs(t, err, ErrInvalidCatchUpPolicy)
+	_, err = engine.SetQuestCatchUp(quest.ID, "7", CatchUpBackfill)
+	assert.ErrorIs(t, err, ErrQuestNotFound)
+
+	updated, err := engine.SetQuestCatchUp(quest.ID, "42", CatchUpBackfill)
+	require.NoError(t, err)
+	assert.Equal(t, CatchUpBackfill, questCatchUp(updated))
+
+	updated.Status = QuestStatusActive
+	progress, err := engine.GetQuestProgress("42")
+	require.NoError(t, err)
+	require.Len(t, progress, 1)
+	assert.Equal(t, "backfill", progress[0].CatchUp)
+}
@@ -3,17 +3,24 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/irfndi/neuratrade/internal/cache"
 	"github.com/redis/go-redis/v9"
 )
 
+// DefaultMaxFinishedQuests caps the completed and failed quests kept in
+// memory until they age out.
+const DefaultMaxFinishedQuests = 1000
+
 // QuestType defines the type of quest
 type QuestType string
 
@@ -76,6 +83,7 @@ type QuestProgress struct {
 	Percent       int    `json:"percent"`
 	Status        string `json:"status"`
 	TimeRemaining string `json:"time_remaining,omitempty"`
+	CatchUp       string `json:"catch_up,omitempty"`
 }
 
 // AutonomousState tracks the autonomous mode state per user
@@ -97,6 +105,9 @@ type QuestDefinition struct {
 	Prompt      string
 	TargetCount int
 	Handler     QuestHandler
+	// CatchUp is the catch-up policy of quests created from the definition;
+	// empty means CatchUpRunOnce
+	CatchUp QuestCatchUpPolicy
 }
 
 // QuestHandler is the function that executes a quest
@@ -117,6 +128,44 @@ type QuestEngine struct {
 	notificationService *NotificationService
 	// chatIDForQuest maps quest IDs to their owner's chat ID
 	chatIDForQuest map[string]int64
+	// events publishes quest lifecycle events, for example to outbound webhooks
+	events EventEmitter
+	// holdReason, when set, keeps the scheduler from executing quests
+	holdReason string
+	// kpis records quest cycle durations
+	kpis KPIRecorder
+	// shedder lengthens cadences and pauses non-critical quests under load
+	shedder LoadShedder
+	// intervals sets the micro cadence of each chat's quests
+	intervals QuestIntervalSource
+	// maxFinishedQuests caps completed and failed quests kept in memory;
+	// the oldest are evicted first
+	maxFinishedQuests int
+	finishedEvicted   int64
+	finishedExpired   int64
+	// schedulerBeat and questBeats are the heartbeats the scheduler and each
+	// quest run refresh for the loop watchdog; startedAt and releasedAt keep
+	// quests from counting as stalled before their first run
+	startedAt     time.Time
+	releasedAt    time.Time
+	schedulerBeat time.Time
+	questBeats    map[string]time.Time
+	// resume spreads the first runs after a restart or release and applies
+	// each quest's catch-up policy to the runs it missed; see planCatchUpLocked
+	resume    QuestResumeConfig
+	resumedAt time.Time
+	planned   map[string]bool
+	notBefore map[string]time.Time
+	backfill  map[string][]time.Time
+	// inFlight keeps a slow run from being dispatched again by the next tick
+	inFlight map[string]bool
+	jitter   func(max time.Duration) time.Duration
+}
+
+// QuestIntervalSource returns how often a chat's micro-cadence quests run,
+// for example from its operating profile.
+type QuestIntervalSource interface {
+	QuestInterval(chatID string, base time.Duration) time.Duration
 }
 
 // QuestProgressNotifier defines the interface for sending quest progress notifications
@@ -244,6 +293,15 @@ func NewQuestEngineWithRedis(store QuestStore, redisClient *redis.Client) *Quest
 		redis:           redisClient,
 		stopCh:          make(chan struct{}),
 		chatIDForQuest:  make(map[string]int64),
+		questBeats:      make(map[string]time.Time),
+		planned:         make(map[string]bool),
+		notBefore:       make(map[string]time.Time),
+		backfill:        make(map[string][]time.Time),
+		inFlight:        make(map[string]bool),
+		jitter:          defaultQuestJitter,
+
+		maxFinishedQuests: DefaultMaxFinishedQuests,
+		resume:            QuestResumeConfig{StartJitter: defaultQuestStartJitter, MaxBackfillRuns: defaultQuestMaxBackfill},
 	}
 
 	engine.registerDefaultDefinitions()
@@ -268,6 +326,7 @@ func (e *QuestEngine) registerDefaultDefinitions() {
 		Type:        QuestTypeRoutine,
 		Cadence:     CadenceMicro,
 		Prompt:      "Scan all configured exchanges for price discrepancies and arbitrage opportunities",
+		CatchUp:     CatchUpSkip,
 	})
 
 	// Portfolio health check - runs hourly
@@ -290,6 +349,16 @@ func (e *QuestEngine) registerDefaultDefinitions() {
 		Prompt:      "Generate comprehensive daily report including PnL, win rate, and strategy performance",
 	})
 
+	// Weekly PnL report
+	e.RegisterDefinition(&QuestDefinition{
+		ID:          "weekly_report",
+		Name:        "Weekly Performance Report",
+		Description: "Generate weekly trading performance summary",
+		Type:        QuestTypeRoutine,
+		Cadence:     CadenceWeekly,
+		Prompt:      "Generate weekly report including PnL, win rate, strategy attribution and risk events",
+	})
+
 	// Funding rate check - runs every 5 minutes
 	e.RegisterDefinition(&QuestDefinition{
 		ID:          "funding_rate_scan",
@@ -298,6 +367,7 @@ func (e *QuestEngine) registerDefaultDefinitions() {
 		Type:        QuestTypeRoutine,
 		Cadence:     CadenceMicro,
 		Prompt:      "Check funding rates across futures exchanges for arbitrage opportunities",
+		CatchUp:     CatchUpSkip,
 	})
 
 	// Volatility watch - triggered by market conditions
@@ -318,17 +388,20 @@ func (e *QuestEngine) registerDefaultDefinitions() {
 		Type:        QuestTypeRoutine,
 		Cadence:     CadenceMicro,
 		Prompt:      "Scan for scalping opportunities using the scalping skill and execute trades when parameters are met",
+		// Missed scans are stale by the time the server is back
+		CatchUp: CatchUpSkip,
 	})
 
-	// Fund growth milestone
+	// Fund growth goal - the monetary target lives in the checkpoint (see
+	// CreateFundGrowthQuest); progress is the percentage of it reached
 	e.RegisterDefinition(&QuestDefinition{
-		ID:          "fund_growth",
+		ID:          FundGrowthDefinitionID,
 		Name:        "Fund Growth Target",
 		Description: "Track progress toward fund growth milestone",
 		Type:        QuestTypeGoal,
-		Cadence:     CadenceOnetime,
+		Cadence:     CadenceHourly,
 		Prompt:      "Grow trading fund to target value using diversified strategies",
-		TargetCount: 1000, // Default target, can be customized
+		TargetCount: 100,
 	})
 }
 
@@ -379,11 +452,15 @@ func (e *QuestEngine) CreateQuest(definitionID string, chatID string, customTarg
 			"definition_id": definitionID,
 		},
 	}
+	if def.CatchUp != "" {
+		quest.Metadata[questCatchUpKey] = string(def.CatchUp)
+	}
 
 	e.mu.Lock()
 	e.quests[quest.ID] = quest
 	chatIDInt, _ := strconv.ParseInt(chatID, 10, 64)
 	e.chatIDForQuest[quest.ID] = chatIDInt
+	e.emitQuestEvent(WebhookEventQuestCreated, quest, 0)
 	e.mu.Unlock()
 
 	if e.store != nil {
@@ -403,6 +480,8 @@ func (e *QuestEngine) Start() {
 		return
 	}
 	e.running = true
+	e.startedAt = time.Now()
+	e.resumeLocked(e.startedAt)
 	e.mu.Unlock()
 
 	// Load active quests from database
@@ -433,8 +512,8 @@ func (e *QuestEngine) loadActiveQuests() {
 		chatID := strings.TrimSpace(quest.Metadata["chat_id"])
 		defID := strings.TrimSpace(quest.Metadata["definition_id"])
 
-		// Scalping-first mode: only restore active scalping quests that have a valid chat owner.
-		if chatID == "" || defID != "scalping_execution" {
+		// Scalping-first mode: only restore active scalping quests and fund growth goals that have a valid chat owner.
+		if chatID == "" || (defID != "scalping_execution" && defID != FundGrowthDefinitionID) {
 			quest.Status = QuestStatusPaused
 			quest.UpdatedAt = now
 			pausedCount++
@@ -444,8 +523,9 @@ func (e *QuestEngine) loadActiveQuests() {
 			continue
 		}
 
-		// Keep only one active scalping quest per chat to prevent duplicate schedulers.
-		if _, exists := selectedByChat[chatID]; exists {
+		// Keep only one active quest per chat and definition to prevent duplicate schedulers.
+		key := chatID + "/" + defID
+		if _, exists := selectedByChat[key]; exists {
 			quest.Status = QuestStatusPaused
 			quest.UpdatedAt = now
 			pausedCount++
@@ -454,16 +534,19 @@ func (e *QuestEngine) loadActiveQuests() {
 			}
 			continue
 		}
-		selectedByChat[chatID] = quest
+		selectedByChat[key] = quest
 	}
 
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	for _, quest := range selectedByChat {
 		e.quests[quest.ID] = quest
-		log.Printf("Loaded active scalping quest: %s (chat: %s)", quest.ID, quest.Metadata["chat_id"])
+		if chatIDInt, err := strconv.ParseInt(quest.Metadata["chat_id"], 10, 64); err == nil {
+			e.chatIDForQuest[quest.ID] = chatIDInt
+		}
+		log.Printf("Loaded active %s quest: %s (chat: %s)", quest.Metadata["definition_id"], quest.ID, quest.Metadata["chat_id"])
 	}
-	log.Printf("Loaded %d active scalping quests, paused %d stale active quests", len(selectedByChat), pausedCount)
+	log.Printf("Loaded %d active quests, paused %d stale active quests", len(selectedByChat), pausedCount)
 }
 
 // Stop stops the quest engine
@@ -478,6 +561,69 @@ func (e *QuestEngine) Stop() {
 	log.Println("Quest engine stopped")
 }
 
+// Drain stops the scheduler, waits for in-flight quest runs to finish and
+// checkpoints every loaded quest to the store, so a rolling deploy resumes
+// them where they were.
+//
+// Parameters:
+//
+//	ctx: Bounds the wait and the checkpoint.
+//
+// Returns:
+//
+//	error: When runs were still in flight at the deadline or a quest failed to save.
+func (e *QuestEngine) Drain(ctx context.Context) error {
+	e.Stop()
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	var waitErr error
+	for waitErr == nil {
+		e.mu.RLock()
+		running := len(e.inFlight)
+		e.mu.RUnlock()
+		if running == 0 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			waitErr = fmt.Errorf("%d quest run(s) still in flight: %w", running, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+
+	if e.store == nil {
+		return waitErr
+	}
+	e.mu.RLock()
+	quests := make([]*Quest, 0, len(e.quests))
+	for _, quest := range e.quests {
+		quests = append(quests, quest)
+	}
+	states := make([]*AutonomousState, 0, len(e.autonomousState))
+	for _, state := range e.autonomousState {
+		states = append(states, state)
+	}
+	e.mu.RUnlock()
+
+	errs := []error{waitErr}
+	saved := 0
+	for _, quest := range quests {
+		if err := e.store.SaveQuest(ctx, quest); err != nil {
+			errs = append(errs, fmt.Errorf("checkpoint quest %s: %w", quest.ID, err))
+			continue
+		}
+		saved++
+	}
+	for _, state := range states {
+		if err := e.store.SaveAutonomousState(ctx, state); err != nil {
+			errs = append(errs, fmt.Errorf("checkpoint autonomous state for chat %s: %w", state.ChatID, err))
+		}
+	}
+	log.Printf("Quest engine drained: checkpointed %d of %d quests", saved, len(quests))
+	return errors.Join(errs...)
+}
+
 // schedulerLoop runs the periodic quest scheduling
 func (e *QuestEngine) schedulerLoop() {
 	log.Println("Quest scheduler loop started")
@@ -495,41 +641,134 @@ func (e *QuestEngine) schedulerLoop() {
 	}
 }
 
+// Hold stops the scheduler from executing quests until Release is called.
+// Quests stay active and loaded, so autonomous mode picks up where it was.
+func (e *QuestEngine) Hold(reason string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.holdReason = reason
+	log.Printf("Quest execution held: %s", reason)
+}
+
+// Release resumes quest execution after Hold. It reports whether the engine
+// was held.
+func (e *QuestEngine) Release() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.holdReason == "" {
+		return false
+	}
+	e.holdReason = ""
+	e.releasedAt = time.Now()
+	e.resumeLocked(e.releasedAt)
+	log.Println("Quest execution released")
+	return true
+}
+
+// HoldReason returns why quest execution is held, or "" when it is not.
+func (e *QuestEngine) HoldReason() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.holdReason
+}
+
 // tick processes scheduled quests
 func (e *QuestEngine) tick() {
 	now := time.Now()
+	e.mu.Lock()
+	e.schedulerBeat = now
+	e.mu.Unlock()
+	if reason := e.HoldReason(); reason != "" {
+		log.Printf("Quest scheduler tick skipped: execution held (%s)", reason)
+		return
+	}
 
 	// First, cleanup old completed/failed quests (need write lock)
 	e.mu.Lock()
 	cleanupThreshold := 24 * time.Hour
+	var finished []*Quest
 	for id, quest := range e.quests {
 		if quest.Status == QuestStatusCompleted || quest.Status == QuestStatusFailed {
 			if quest.UpdatedAt.Before(now.Add(-cleanupThreshold)) {
 				delete(e.quests, id)
 				delete(e.chatIDForQuest, id)
+				delete(e.questBeats, id)
+				delete(e.planned, id)
+				delete(e.notBefore, id)
+				delete(e.backfill, id)
+				e.finishedExpired++
 				log.Printf("Cleaned up old quest: %s (status: %s)", id, quest.Status)
+			} else {
+				finished = append(finished, quest)
 			}
 		}
 	}
+	// Then keep the number of finished quests bounded, dropping the oldest
+	if excess := len(finished) - e.maxFinishedQuests; excess > 0 {
+		sort.Slice(finished, func(i, j int) bool { return finished[i].UpdatedAt.Before(finished[j].UpdatedAt) })
+		for _, quest := range finished[:excess] {
+			delete(e.quests, quest.ID)
+			delete(e.chatIDForQuest, quest.ID)
+			delete(e.questBeats, quest.ID)
+			delete(e.planned, quest.ID)
+			delete(e.notBefore, quest.ID)
+			delete(e.backfill, quest.ID)
+		}
+		e.finishedEvicted += int64(excess)
+		log.Printf("Evicted %d finished quests over the limit of %d", excess, e.maxFinishedQuests)
+	}
 	e.mu.Unlock()
 
-	// Then, check quests for execution (read lock)
-	e.mu.RLock()
+	// Then, check quests for execution (write lock, for the resume plan)
+	e.mu.Lock()
 	log.Printf("Quest scheduler tick: checking %d quests", len(e.quests))
 	for _, quest := range e.quests {
 		if quest.Status != QuestStatusActive {
 			continue
 		}
 
+		if e.shedder != nil && !e.shedder.AllowQuest(quest.Metadata["definition_id"]) {
+			log.Printf("Quest %s paused by load shedding", quest.ID)
+			continue
+		}
+		if e.inFlight[quest.ID] {
+			log.Printf("Quest %s still running", quest.ID)
+			continue
+		}
+
+		// Apply the catch-up policy and start jitter after a resume
+		if !e.planned[quest.ID] {
+			e.planCatchUpLocked(quest, now)
+		}
+		if notBefore, ok := e.notBefore[quest.ID]; ok {
+			if now.Before(notBefore) {
+				log.Printf("Quest %s deferred until %s", quest.ID, notBefore.Format(time.RFC3339))
+				continue
+			}
+			delete(e.notBefore, quest.ID)
+		}
+		if slots := e.backfill[quest.ID]; len(slots) > 0 {
+			if len(slots) == 1 {
+				delete(e.backfill, quest.ID)
+			} else {
+				e.backfill[quest.ID] = slots[1:]
+			}
+			log.Printf("Backfilling quest %s for %s", quest.ID, slots[0].Format(time.RFC3339))
+			e.inFlight[quest.ID] = true
+			go e.runQuest(quest, slots[0])
+			continue
+		}
+
 		// Check if quest should execute based on cadence
 		if e.shouldExecute(quest, now) {
 			log.Printf("Executing quest: %s (type: %s)", quest.ID, quest.Type)
+			e.inFlight[quest.ID] = true
 			go e.executeQuest(quest)
 		} else {
 			log.Printf("Quest %s not ready (cadence: %s)", quest.ID, quest.Cadence)
 		}
 	}
-	e.mu.RUnlock()
+	e.mu.Unlock()
 }
 
 func (e *QuestEngine) shouldExecute(quest *Quest, now time.Time) bool {
@@ -539,36 +778,112 @@ func (e *QuestEngine) shouldExecute(quest *Quest, now time.Time) bool {
 		return false
 	}
 
+	interval, scheduled := e.cadenceInterval(quest)
+	if !scheduled {
+		// One-time and unknown cadences are never scheduled
+		return false
+	}
+	if quest.LastExecutedAt == nil {
+		return true
+	}
+	if e.shedder != nil {
+		interval = e.shedder.ShedInterval(interval)
+	}
+	return now.Sub(*quest.LastExecutedAt) >= interval
+}
+
+// cadenceInterval returns how often a quest runs before load shedding;
+// scheduled is false for cadences the scheduler never runs.
+func (e *QuestEngine) cadenceInterval(quest *Quest) (interval time.Duration, scheduled bool) {
 	switch quest.Cadence {
 	case CadenceMicro:
-		if quest.LastExecutedAt != nil {
-			return now.Sub(*quest.LastExecutedAt) >= 1*time.Minute
+		interval = 1 * time.Minute
+		if chatID := quest.Metadata["chat_id"]; chatID != "" && e.intervals != nil {
+			interval = e.intervals.QuestInterval(chatID, interval)
 		}
-		return true
 	case CadenceHourly:
-		if quest.LastExecutedAt != nil {
-			return now.Sub(*quest.LastExecutedAt) >= 1*time.Hour
-		}
-		return true
+		interval = 1 * time.Hour
 	case CadenceDaily:
-		if quest.LastExecutedAt != nil {
-			return now.Sub(*quest.LastExecutedAt) >= 24*time.Hour
-		}
-		return true
+		interval = 24 * time.Hour
 	case CadenceWeekly:
-		if quest.LastExecutedAt != nil {
-			return now.Sub(*quest.LastExecutedAt) >= 7*24*time.Hour
-		}
-		return true
-	case CadenceOnetime:
-		return false
+		interval = 7 * 24 * time.Hour
 	default:
-		return false
+		return 0, false
+	}
+	return interval, true
+}
+
+// Heartbeats returns when the scheduler last ticked and when each active
+// scheduled quest last finished a run, for the loop watchdog. Quests paused
+// by Hold or load shedding are left out, since they are not expected to run.
+//
+// Returns:
+//
+//	[]LoopHeartbeat: Heartbeats; nil when the engine is not running.
+func (e *QuestEngine) Heartbeats() []LoopHeartbeat {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if !e.running {
+		return nil
+	}
+
+	schedulerBeat := e.schedulerBeat
+	if schedulerBeat.IsZero() {
+		schedulerBeat = e.startedAt
+	}
+	beats := []LoopHeartbeat{{Loop: LoopScheduler, Name: "Quest scheduler", LastBeat: schedulerBeat, Interval: time.Minute}}
+	if e.holdReason != "" {
+		return beats
+	}
+
+	for id, quest := range e.quests {
+		if quest.Status != QuestStatusActive {
+			continue
+		}
+		interval, scheduled := e.cadenceInterval(quest)
+		if !scheduled {
+			continue
+		}
+		if e.shedder != nil {
+			if !e.shedder.AllowQuest(quest.Metadata["definition_id"]) {
+				continue
+			}
+			interval = e.shedder.ShedInterval(interval)
+		}
+		// Measure from the latest sign of life, so a quest that has not run
+		// since start-up, a release or being resumed is not reported stalled
+		lastBeat := e.questBeats[id]
+		for _, at := range []time.Time{e.startedAt, e.releasedAt, quest.UpdatedAt} {
+			if at.After(lastBeat) {
+				lastBeat = at
+			}
+		}
+		beats = append(beats, LoopHeartbeat{
+			Loop:     "quest:" + id,
+			Name:     quest.Name,
+			ChatID:   quest.Metadata["chat_id"],
+			LastBeat: lastBeat,
+			Interval: interval,
+		})
 	}
+	sort.Slice(beats[1:], func(i, j int) bool { return beats[i+1].Loop < beats[j+1].Loop })
+	return beats
 }
 
 // executeQuest executes a single quest
 func (e *QuestEngine) executeQuest(quest *Quest) {
+	e.runQuest(quest, time.Time{})
+}
+
+// runQuest executes a quest; a non-zero slot marks a backfill run for that
+// cadence boundary (see QuestScheduledFor).
+func (e *QuestEngine) runQuest(quest *Quest, slot time.Time) {
+	defer func() {
+		e.mu.Lock()
+		delete(e.inFlight, quest.ID)
+		e.mu.Unlock()
+	}()
+
 	e.mu.RLock()
 	handler, ok := e.handlers[quest.Type]
 	e.mu.RUnlock()
@@ -580,6 +895,9 @@ func (e *QuestEngine) executeQuest(quest *Quest) {
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer cancel()
+	if !slot.IsZero() {
+		ctx = context.WithValue(ctx, questScheduledForKey{}, slot)
+	}
 
 	lockKey := fmt.Sprintf("quest:lock:%s", quest.ID)
 	locked := e.acquireLock(ctx, lockKey, 5*time.Minute)
@@ -589,15 +907,28 @@ func (e *QuestEngine) executeQuest(quest *Quest) {
 	}
 	defer e.releaseLock(ctx, lockKey)
 
-	if err := handler(ctx, quest); err != nil {
+	started := time.Now()
+	previousCount := quest.CurrentCount
+	err := handler(ctx, quest)
+	e.mu.Lock()
+	e.questBeats[quest.ID] = time.Now()
+	e.mu.Unlock()
+	if e.kpis != nil {
+		e.kpis.RecordKPI(KPIQuestCycleMs, float64(time.Since(started).Milliseconds()))
+	}
+	if err != nil {
 		log.Printf("Quest %s (%s) failed: %v", quest.ID, quest.Name, err)
-		e.updateQuestStatus(quest.ID, QuestStatusFailed)
 		quest.LastError = err.Error()
+		e.updateQuestStatus(quest.ID, QuestStatusFailed)
 	} else {
 		log.Printf("Quest %s (%s) completed successfully", quest.ID, quest.Name)
 		now := time.Now()
 		e.updateLastExecuted(quest.ID, now)
-		if quest.Type == QuestTypeRoutine {
+		e.mu.Lock()
+		e.emitQuestMilestone(quest, previousCount)
+		e.mu.Unlock()
+		// Goal quests keep running until their target is reached
+		if quest.Type == QuestTypeRoutine || (quest.Type == QuestTypeGoal && quest.CurrentCount < quest.TargetCount) {
 			e.updateQuestStatus(quest.ID, QuestStatusActive)
 		} else {
 			e.updateQuestStatus(quest.ID, QuestStatusCompleted)
@@ -640,17 +971,146 @@ func (e *QuestEngine) updateLastExecuted(questID string, executedAt time.Time) {
 	}
 }
 
+// SetKPIRecorder records the duration of each quest execution.
+func (e *QuestEngine) SetKPIRecorder(kpis KPIRecorder) {
+	e.kpis = kpis
+}
+
+// SetMaxFinishedQuests caps the completed and failed quests kept in memory;
+// zero or less uses DefaultMaxFinishedQuests.
+func (e *QuestEngine) SetMaxFinishedQuests(max int) {
+	if max <= 0 {
+		max = DefaultMaxFinishedQuests
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.maxFinishedQuests = max
+}
+
+// BoundedStats reports the finished quests kept in memory and how many were
+// evicted over the limit or aged out.
+func (e *QuestEngine) BoundedStats() cache.BoundedStats {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	finished := 0
+	for _, quest := range e.quests {
+		if quest.Status == QuestStatusCompleted || quest.Status == QuestStatusFailed {
+			finished++
+		}
+	}
+	return cache.BoundedStats{
+		Name:        "quest_engine.finished_quests",
+		Entries:     finished,
+		MaxEntries:  e.maxFinishedQuests,
+		Evictions:   e.finishedEvicted,
+		Expirations: e.finishedExpired,
+	}
+}
+
+// SetLoadShedder lengthens quest cadences and pauses non-critical quests
+// while the system is under CPU/memory pressure.
+func (e *QuestEngine) SetLoadShedder(shedder LoadShedder) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.shedder = shedder
+}
+
+// SetScanIntervals sets the per-chat micro cadence of quests.
+func (e *QuestEngine) SetScanIntervals(source QuestIntervalSource) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.intervals = source
+}
+
+// SetEventEmitter publishes quest lifecycle events (created, milestone,
+// completed and failed), for example to outbound webhooks.
+func (e *QuestEngine) SetEventEmitter(events EventEmitter) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.events = events
+}
+
+// QuestMilestonePercents are the progress levels published as quest.milestone
+// events; reaching the target is published as quest.completed.
+var QuestMilestonePercents = []int{25, 50, 75}
+
+// QuestLifecycleEvent is the payload of quest lifecycle webhook events.
+type QuestLifecycleEvent struct {
+	QuestID   string    `json:"quest_id"`
+	QuestName string    `json:"quest_name"`
+	Type      QuestType `json:"type"`
+	Current   int       `json:"current"`
+	Target    int       `json:"target"`
+	ChatID    string    `json:"chat_id"`
+	// MilestonePercent is the progress level of a quest.milestone event.
+	MilestonePercent int `json:"milestone_percent,omitempty"`
+	// Error is why a quest.failed quest failed.
+	Error       string     `json:"error,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	OccurredAt  time.Time  `json:"occurred_at"`
+}
+
+// emitQuestEvent publishes a lifecycle event. Callers hold e.mu, so the event
+// is built from a snapshot and emitted off the lock.
+func (e *QuestEngine) emitQuestEvent(event WebhookEventType, quest *Quest, milestone int) {
+	if e.events == nil {
+		return
+	}
+	now := time.Now().UTC()
+	data := QuestLifecycleEvent{
+		QuestID:          quest.ID,
+		QuestName:        quest.Name,
+		Type:             quest.Type,
+		Current:          quest.CurrentCount,
+		Target:           quest.TargetCount,
+		ChatID:           quest.Metadata["chat_id"],
+		MilestonePercent: milestone,
+		OccurredAt:       now,
+	}
+	switch event {
+	case WebhookEventQuestCompleted:
+		data.CompletedAt = &now
+	case WebhookEventQuestFailed:
+		data.Error = quest.LastError
+	}
+	events := e.events
+	go events.Emit(context.Background(), event, data)
+}
+
+// emitQuestMilestone publishes the highest milestone a quest passed since it
+// was at previousCount. Callers hold e.mu.
+func (e *QuestEngine) emitQuestMilestone(quest *Quest, previousCount int) {
+	target := quest.TargetCount
+	if target <= 0 || quest.CurrentCount >= target {
+		return
+	}
+	reached := 0
+	for _, percent := range QuestMilestonePercents {
+		if previousCount*100 < percent*target && quest.CurrentCount*100 >= percent*target {
+			reached = percent
+		}
+	}
+	if reached > 0 {
+		e.emitQuestEvent(WebhookEventQuestMilestone, quest, reached)
+	}
+}
+
 // updateQuestStatus updates a quest's status
 func (e *QuestEngine) updateQuestStatus(questID string, status QuestStatus) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
 	if quest, ok := e.quests[questID]; ok {
+		previous := quest.Status
 		quest.Status = status
 		quest.UpdatedAt = time.Now()
-		if status == QuestStatusCompleted {
+		switch {
+		case status == QuestStatusCompleted:
 			now := time.Now()
 			quest.CompletedAt = &now
+			e.emitQuestEvent(WebhookEventQuestCompleted, quest, 0)
+		case status == QuestStatusFailed && previous != QuestStatusFailed:
+			e.emitQuestEvent(WebhookEventQuestFailed, quest, 0)
 		}
 
 		if e.store != nil {
@@ -667,9 +1127,10 @@ func (e *QuestEngine) BeginAutonomous(chatID string) (*AutonomousState, error) {
 	defer e.mu.Unlock()
 
 	// Pause existing active quests for this chat, and any unowned legacy active quests.
+	// Goal quests track the fund rather than trade, so they keep running.
 	for _, q := range e.quests {
 		questChatID := strings.TrimSpace(q.Metadata["chat_id"])
-		if q.Status == QuestStatusActive && (questChatID == chatID || questChatID == "") {
+		if q.Status == QuestStatusActive && q.Type != QuestTypeGoal && (questChatID == chatID || questChatID == "") {
 			q.Status = QuestStatusPaused
 			q.UpdatedAt = time.Now()
 			if e.store != nil {
@@ -687,8 +1148,9 @@ func (e *QuestEngine) BeginAutonomous(chatID string) (*AutonomousState, error) {
 	}
 
 	// Create default quests for autonomous mode.
-	// Current operating mode is scalping-first, so only enable scalping execution.
-	defaultQuests := []string{"scalping_execution"}
+	// Current operating mode is scalping-first, so scalping execution is the
+	// only trading quest; the reports only read results.
+	defaultQuests := []string{"scalping_execution", "daily_report", "weekly_report"}
 	for _, defID := range defaultQuests {
 		quest, err := e.createQuestInternal(defID, chatID)
 		if err != nil {
@@ -776,6 +1238,9 @@ func (e *QuestEngine) GetQuestProgress(chatID string) ([]QuestProgress, error) {
 			Target:    quest.TargetCount,
 			Status:    string(quest.Status),
 		}
+		if _, scheduled := e.cadenceInterval(quest); scheduled {
+			p.CatchUp = string(questCatchUp(quest))
+		}
 
 		if quest.TargetCount > 0 {
 			p.Percent = (quest.CurrentCount * 100) / quest.TargetCount
@@ -815,8 +1280,12 @@ func (e *QuestEngine) createQuestInternal(definitionID string, chatID string) (*
 			"definition_id": definitionID,
 		},
 	}
+	if def.CatchUp != "" {
+		quest.Metadata[questCatchUpKey] = string(def.CatchUp)
+	}
 
 	e.quests[quest.ID] = quest
+	e.emitQuestEvent(WebhookEventQuestCreated, quest, 0)
 
 	if e.store != nil {
 		if err := e.store.SaveQuest(context.Background(), quest); err != nil {
@@ -844,8 +1313,13 @@ func (e *QuestEngine) UpdateQuestProgress(questID string, current int, checkpoin
 
 	if current >= quest.TargetCount && quest.TargetCount > 0 {
 		now := time.Now()
+		if quest.Status != QuestStatusCompleted {
+			e.emitQuestEvent(WebhookEventQuestCompleted, quest, 0)
+		}
 		quest.Status = QuestStatusCompleted
 		quest.CompletedAt = &now
+	} else {
+		e.emitQuestMilestone(quest, previousCount)
 	}
 
 	chatID := e.chatIDForQuest[questID]
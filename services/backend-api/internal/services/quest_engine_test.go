@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -434,3 +436,216 @@ func TestNewQuestEngineWithRedis(t *testing.T) {
 func ptrTime(t time.Time) *time.Time {
 	return &t
 }
+
+func TestQuestEngine_Hold(t *testing.T) {
+	engine := NewQuestEngine(nil)
+	// Run as soon as the hold is released rather than within the start jitter
+	engine.jitter = func(time.Duration) time.Duration { return 0 }
+	executed := make(chan struct{}, 1)
+	engine.RegisterHandler(QuestTypeRoutine, func(context.Context, *Quest) error {
+		executed <- struct{}{}
+		return nil
+	})
+	engine.quests["q1"] = &Quest{ID: "q1", Type: QuestTypeRoutine, Cadence: CadenceMicro, Status: QuestStatusActive}
+
+	if engine.Release() {
+		t.Error("Release reported a hold that was never placed")
+	}
+	engine.Hold("unclean shutdown")
+	if got := engine.HoldReason(); got != "unclean shutdown" {
+		t.Errorf("HoldReason = %q, want %q", got, "unclean shutdown")
+	}
+	engine.tick()
+	select {
+	case <-executed:
+		t.Fatal("a held engine executed a quest")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if !engine.Release() {
+		t.Error("Release should report the hold")
+	}
+	if got := engine.HoldReason(); got != "" {
+		t.Errorf("HoldReason = %q after release", got)
+	}
+	engine.tick()
+	select {
+	case <-executed:
+	case <-time.After(time.Second):
+		t.Fatal("quest did not execute after release")
+	}
+}
+
+type stubLoadShedder struct {
+	factor time.Duration
+	paused string
+}
+
+func (s stubLoadShedder) ShedInterval(base time.Duration) time.Duration { return base * s.factor }
+
+func (s stubLoadShedder) AllowQuest(definitionID string) bool { return definitionID != s.paused }
+
+func TestQuestEngine_LoadShedding(t *testing.T) {
+	engine := NewQuestEngine(nil)
+	engine.SetLoadShedder(stubLoadShedder{factor: 3, paused: "daily_report"})
+
+	now := time.Now()
+	ranAgo := now.Add(-2 * time.Minute)
+	quest := &Quest{ID: "q1", Cadence: CadenceMicro, LastExecutedAt: &ranAgo}
+	if engine.shouldExecute(quest, now) {
+		t.Error("micro quest ran 2m ago should wait for the lengthened 3m cadence")
+	}
+	ranAgo = now.Add(-3 * time.Minute)
+	if !engine.shouldExecute(quest, now) {
+		t.Error("micro quest should run once the lengthened cadence has passed")
+	}
+
+	executed := make(chan string, 2)
+	engine.RegisterHandler(QuestTypeRoutine, func(_ context.Context, q *Quest) error {
+		executed <- q.ID
+		return nil
+	})
+	engine.quests["report"] = &Quest{ID: "report", Type: QuestTypeRoutine, Cadence: CadenceDaily, Status: QuestStatusActive,
+		Metadata: map[string]string{"definition_id": "daily_report"}}
+	engine.tick()
+	select {
+	case id := <-executed:
+		t.Fatalf("quest %s ran although its definition is paused", id)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestQuestEngine_EvictsFinishedQuestsOverLimit(t *testing.T) {
+	engine := NewQuestEngine(nil)
+	engine.SetMaxFinishedQuests(2)
+
+	now := time.Now()
+	for i, id := range []string{"oldest", "older", "newest"} {
+		engine.quests[id] = &Quest{ID: id, Status: QuestStatusCompleted, UpdatedAt: now.Add(time.Duration(i-3) * time.Minute)}
+	}
+	engine.quests["stale"] = &Quest{ID: "stale", Status: QuestStatusFailed, UpdatedAt: now.Add(-48 * time.Hour)}
+	engine.tick()
+
+	if _, ok := engine.quests["oldest"]; ok {
+		t.Error("oldest finished quest should be evicted over the limit")
+	}
+	if _, ok := engine.quests["stale"]; ok {
+		t.Error("finished quest older than 24h should be cleaned up")
+	}
+	stats := engine.BoundedStats()
+	if stats.Entries != 2 || stats.MaxEntries != 2 {
+		t.Errorf("expected 2 of 2 finished quests, got %d of %d", stats.Entries, stats.MaxEntries)
+	}
+	if stats.Evictions != 1 || stats.Expirations != 1 {
+		t.Errorf("expected 1 eviction and 1 expiration, got %d and %d", stats.Evictions, stats.Expirations)
+	}
+}
+
+type emittedEvent struct {
+	event WebhookEventType
+	data  QuestLifecycleEvent
+}
+
+type channelEmitter chan emittedEvent
+
+func (c channelEmitter) Emit(_ context.Context, event WebhookEventType, data interface{}) {
+	quest, _ := data.(QuestLifecycleEvent)
+	c <- emittedEvent{event: event, data: quest}
+}
+
+func (c channelEmitter) next(t *testing.T) emittedEvent {
+	t.Helper()
+	select {
+	case emitted := <-c:
+		return emitted
+	case <-time.After(time.Second):
+		t.Fatal("no quest event emitted")
+		return emittedEvent{}
+	}
+}
+
+func TestQuestEngine_EmitsLifecycleEvents(t *testing.T) {
+	engine := NewQuestEngine(nil)
+	events := make(channelEmitter, 8)
+	engine.SetEventEmitter(events)
+	engine.RegisterDefinition(&QuestDefinition{ID: "savings", Name: "Savings", Type: QuestTypeGoal, TargetCount: 4})
+	failing := false
+	engine.RegisterHandler(QuestTypeGoal, func(_ context.Context, q *Quest) error {
+		if failing {
+			return errors.New("exchange unavailable")
+		}
+		q.CurrentCount += 2
+		return nil
+	})
+
+	quest, err := engine.CreateQuest("savings", "42")
+	if err != nil {
+		t.Fatalf("CreateQuest: %v", err)
+	}
+	if got := events.next(t); got.event != WebhookEventQuestCreated || got.data.ChatID != "42" || got.data.Target != 4 {
+		t.Errorf("expected quest.created for chat 42, got %+v", got)
+	}
+
+	engine.executeQuest(quest)
+	if got := events.next(t); got.event != WebhookEventQuestMilestone || got.data.MilestonePercent != 50 {
+		t.Errorf("expected the 50%% milestone, got %+v", got)
+	}
+	engine.executeQuest(quest)
+	if got := events.next(t); got.event != WebhookEventQuestCompleted || got.data.CompletedAt == nil {
+		t.Errorf("expected quest.completed, got %+v", got)
+	}
+
+	failing = true
+	other, err := engine.CreateQuest("savings", "42")
+	if err != nil {
+		t.Fatalf("CreateQuest: %v", err)
+	}
+	events.next(t)
+	engine.executeQuest(other)
+	engine.executeQuest(other)
+	if got := events.next(t); got.event != WebhookEventQuestFailed || got.data.Error != "exchange unavailable" {
+		t.Errorf("expected quest.failed with the error, got %+v", got)
+	}
+	select {
+	case got := <-events:
+		t.Errorf("a quest that keeps failing is reported once, got %+v", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestQuestEngine_Drain(t *testing.T) {
+	store := NewInMemoryQuestStore()
+	engine := NewQuestEngine(store)
+	engine.jitter = func(time.Duration) time.Duration { return 0 }
+	release := make(chan struct{})
+	started := make(chan struct{})
+	engine.RegisterHandler(QuestTypeRoutine, func(_ context.Context, quest *Quest) error {
+		close(started)
+		<-release
+		quest.CurrentCount = 7
+		return nil
+	})
+	engine.quests["q1"] = &Quest{ID: "q1", Type: QuestTypeRoutine, Cadence: CadenceMicro, Status: QuestStatusActive}
+	engine.tick()
+	<-started
+
+	// The run is still in flight when the budget runs out
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := engine.Drain(ctx)
+	if err == nil || !strings.Contains(err.Error(), "1 quest run(s) still in flight") {
+		t.Fatalf("Drain error = %v, want in-flight run", err)
+	}
+
+	close(release)
+	if err := engine.Drain(context.Background()); err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+	saved, err := store.GetQuest(context.Background(), "q1")
+	if err != nil {
+		t.Fatalf("quest not checkpointed: %v", err)
+	}
+	if saved.CurrentCount != 7 {
+		t.Errorf("checkpointed CurrentCount = %d, want 7", saved.CurrentCount)
+	}
+}
@@ -2,8 +2,10 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/irfndi/neuratrade/internal/ai/llm"
@@ -27,6 +29,32 @@ type IntegratedQuestHandlers struct {
 	orderExecutor       ScalpingOrderExecutor
 	aiScalpingService   *AIScalpingService
 	tradeMemory         *TradeMemory
+	universe            SymbolUniverse
+	listingRisk         ListingRiskProvider
+	stableGuard         StablecoinGuard
+	cooldowns           SymbolCooldownGuard
+	positions           StrategyPositionGuard
+	orderDefaults       StrategyOrderDefaults
+	exchangeGuard       ExchangeGuard
+	promptRouter        PromptRouter
+	decisions           DecisionRecorder
+	similarDecisions    SimilarDecisionFinder
+	shadowConfig        *ShadowStrategyConfig
+	shadowStrategy      *ShadowStrategyRunner
+	allocator           CapitalAllocator
+	budget              TradingBudgetGuard
+	dailyLoss           DailyLossGuard
+	equity              EquitySource
+	lossStreak          StrategyThrottle
+	leverageGuard       LeverageGuard
+	positioning         PositioningReader
+	tradeFlow           TradeFlowReader
+	regime              RegimeDetector
+	news                RecentNewsReader
+	openPositions       OpenPositionReader
+	reports             *TradingReportGenerator
+	intents             IntentRecorder
+	profiles            OperatingProfileSource
 }
 
 // NewIntegratedQuestHandlers creates integrated quest handlers with actual implementations
@@ -53,6 +81,313 @@ func (h *IntegratedQuestHandlers) SetOrderExecutor(executor ScalpingOrderExecuto
 	h.orderExecutor = executor
 }
 
+// SetSymbolUniverse limits market scans and scalping to each chat's active symbols
+func (h *IntegratedQuestHandlers) SetSymbolUniverse(universe SymbolUniverse) {
+	h.universe = universe
+}
+
+// SetListingRiskProvider applies conservative scalping limits to recently listed symbols
+func (h *IntegratedQuestHandlers) SetListingRiskProvider(provider ListingRiskProvider) {
+	h.listingRisk = provider
+	if h.aiScalpingService != nil {
+		h.aiScalpingService.SetListingRiskProvider(provider)
+	}
+}
+
+// SetStablecoinGuard pauses scalping on symbols that trade against a depegged stablecoin
+func (h *IntegratedQuestHandlers) SetStablecoinGuard(guard StablecoinGuard) {
+	h.stableGuard = guard
+	if h.aiScalpingService != nil {
+		h.aiScalpingService.SetStablecoinGuard(guard)
+	}
+}
+
+// SetSymbolCooldownGuard stops scalping from re-entering symbols right after an exit
+func (h *IntegratedQuestHandlers) SetSymbolCooldownGuard(guard SymbolCooldownGuard) {
+	h.cooldowns = guard
+	if h.aiScalpingService != nil {
+		h.aiScalpingService.SetSymbolCooldownGuard(guard)
+	}
+}
+
+// SetPositionGuard stops strategies from opening overlapping positions on a symbol
+func (h *IntegratedQuestHandlers) SetPositionGuard(guard StrategyPositionGuard) {
+	h.positions = guard
+	if h.aiScalpingService != nil {
+		h.aiScalpingService.SetPositionGuard(guard)
+	}
+}
+
+// SetOrderDefaults sets the time-in-force and post-only flags each strategy's orders use
+func (h *IntegratedQuestHandlers) SetOrderDefaults(defaults StrategyOrderDefaults) {
+	h.orderDefaults = defaults
+	if h.aiScalpingService != nil {
+		h.aiScalpingService.SetOrderOptions(defaults.For(StrategyScalping))
+	}
+}
+
+// SetIntentLog records each decision's orders before they are placed so a
+// restart mid-execution can be reconciled
+func (h *IntegratedQuestHandlers) SetIntentLog(intents IntentRecorder) {
+	h.intents = intents
+	if h.aiScalpingService != nil {
+		h.aiScalpingService.SetIntentLog(intents)
+	}
+}
+
+// SetExchangeGuard pauses scalping while the scalping exchange is degraded
+func (h *IntegratedQuestHandlers) SetExchangeGuard(guard ExchangeGuard) {
+	h.exchangeGuard = guard
+	if h.aiScalpingService != nil {
+		h.aiScalpingService.SetExchangeGuard(guard)
+	}
+}
+
+// SetLeverageGuard checks scalping leverage is applied before entries
+func (h *IntegratedQuestHandlers) SetLeverageGuard(guard LeverageGuard) {
+	h.leverageGuard = guard
+	if h.aiScalpingService != nil {
+		h.aiScalpingService.SetLeverageGuard(guard)
+	}
+}
+
+// SetPositioningSource adds derivatives positioning to scalping prompts
+func (h *IntegratedQuestHandlers) SetPositioningSource(positioning PositioningReader) {
+	h.positioning = positioning
+	if h.aiScalpingService != nil {
+		h.aiScalpingService.SetPositioningSource(positioning)
+	}
+}
+
+// SetTradeFlowSource adds trade-tape flow to scalping prompts
+func (h *IntegratedQuestHandlers) SetTradeFlowSource(tradeFlow TradeFlowReader) {
+	h.tradeFlow = tradeFlow
+	if h.aiScalpingService != nil {
+		h.aiScalpingService.SetTradeFlowSource(tradeFlow)
+	}
+}
+
+// SetRegimeSource adds the market regime to scalping prompts
+func (h *IntegratedQuestHandlers) SetRegimeSource(regime RegimeDetector) {
+	h.regime = regime
+	if h.aiScalpingService != nil {
+		h.aiScalpingService.SetRegimeSource(regime)
+	}
+}
+
+// SetNewsSource adds recent headlines to scalping prompts
+func (h *IntegratedQuestHandlers) SetNewsSource(news RecentNewsReader) {
+	h.news = news
+	if h.aiScalpingService != nil {
+		h.aiScalpingService.SetNewsSource(news)
+	}
+}
+
+// SetOpenPositionSource lists open positions in scalping prompts
+func (h *IntegratedQuestHandlers) SetOpenPositionSource(positions OpenPositionReader) {
+	h.openPositions = positions
+	if h.aiScalpingService != nil {
+		h.aiScalpingService.SetOpenPositionSource(positions)
+	}
+}
+
+// SetReportGenerator sets the generator behind the daily and weekly report quests
+func (h *IntegratedQuestHandlers) SetReportGenerator(reports *TradingReportGenerator) {
+	h.reports = reports
+}
+
+// SetPromptRouter routes live scalping cycles between prompt/model versions
+// for a canary rollout
+func (h *IntegratedQuestHandlers) SetPromptRouter(router PromptRouter) {
+	h.promptRouter = router
+	if h.aiScalpingService != nil {
+		h.aiScalpingService.SetPromptRouter(router)
+	}
+}
+
+// StrategyParameters returns the live scalping strategy's parameters for
+// state fingerprints; it is empty until AI scalping is set up.
+func (h *IntegratedQuestHandlers) StrategyParameters(context.Context) (map[string]interface{}, error) {
+	if h.aiScalpingService == nil {
+		return nil, nil
+	}
+	return h.aiScalpingService.StrategyParameters(), nil
+}
+
+// SetDecisionRecorder keeps an audit record of every live scalping decision
+func (h *IntegratedQuestHandlers) SetDecisionRecorder(recorder DecisionRecorder) {
+	h.decisions = recorder
+	if h.aiScalpingService != nil {
+		h.aiScalpingService.SetDecisionRecorder(recorder)
+	}
+}
+
+// SetSimilarDecisionSource adds the outcomes of similar past decisions to
+// live scalping prompts
+func (h *IntegratedQuestHandlers) SetSimilarDecisionSource(similar SimilarDecisionFinder) {
+	h.similarDecisions = similar
+	if h.aiScalpingService != nil {
+		h.aiScalpingService.SetSimilarDecisionSource(similar)
+	}
+}
+
+// SetShadowStrategy evaluates a scalping variant in shadow mode next to live
+// scalping; it takes effect when AI scalping is configured
+func (h *IntegratedQuestHandlers) SetShadowStrategy(config ShadowStrategyConfig) {
+	h.shadowConfig = &config
+}
+
+// ShadowStrategy returns the shadow strategy runner, nil when none is running
+func (h *IntegratedQuestHandlers) ShadowStrategy() *ShadowStrategyRunner {
+	return h.shadowStrategy
+}
+
+// SetCapitalAllocator limits each strategy to its share of the chat's capital
+// when orders are sized
+func (h *IntegratedQuestHandlers) SetCapitalAllocator(allocator CapitalAllocator) {
+	h.allocator = allocator
+}
+
+// SetTradingBudget caps the capital each chat's strategies size orders with
+// at what is left of its trading budget
+func (h *IntegratedQuestHandlers) SetTradingBudget(budget TradingBudgetGuard) {
+	h.budget = budget
+}
+
+// SetDailyLossGuard halts new scalping and arbitrage entries for chats that
+// breached their daily loss cap
+func (h *IntegratedQuestHandlers) SetDailyLossGuard(guard DailyLossGuard) {
+	h.dailyLoss = guard
+}
+
+// SetEquitySource values the account for fund growth goals; without it
+// they track the USDT balance on binance
+func (h *IntegratedQuestHandlers) SetEquitySource(equity EquitySource) {
+	h.equity = equity
+}
+
+// entriesHalted reports whether the chat's daily loss cap halts new entries
+func (h *IntegratedQuestHandlers) entriesHalted(ctx context.Context, quest *Quest, chatID string) bool {
+	if h.dailyLoss == nil || chatID == "" || !h.dailyLoss.EntriesHalted(ctx, chatID) {
+		return false
+	}
+	log.Printf("[RISK] Daily loss cap reached for chat %s, skipping new entries", chatID)
+	quest.Checkpoint["status"] = "daily_loss_halt"
+	quest.Checkpoint["chat_id"] = chatID
+	return true
+}
+
+// SetStrategyThrottle reduces or pauses strategies after a losing streak
+func (h *IntegratedQuestHandlers) SetStrategyThrottle(throttle StrategyThrottle) {
+	h.lossStreak = throttle
+}
+
+// lossStreakMultiplier returns the position size multiplier of the strategy's
+// loss streak throttle; ok is false while the strategy is paused
+func (h *IntegratedQuestHandlers) lossStreakMultiplier(ctx context.Context, quest *Quest, chatID, strategy string) (multiplier float64, ok bool) {
+	if h.lossStreak == nil || chatID == "" {
+		return 1, true
+	}
+	multiplier, paused := h.lossStreak.StrategyThrottle(ctx, chatID, strategy)
+	if paused {
+		log.Printf("[RISK] %s paused for chat %s after consecutive losses, skipping cycle", strategy, chatID)
+		quest.Checkpoint["status"] = "loss_streak_pause"
+		quest.Checkpoint["chat_id"] = chatID
+		return 0, false
+	}
+	if multiplier < 1 {
+		quest.Checkpoint["loss_streak_multiplier"] = multiplier
+	}
+	return multiplier, true
+}
+
+// strategyFraction returns the share of the chat's capital a strategy may use.
+// ok is false when the allocation cannot be read; the cycle is then skipped
+// rather than sized against all capital.
+func (h *IntegratedQuestHandlers) strategyFraction(ctx context.Context, quest *Quest, chatID, strategy string) (fraction float64, limited bool, ok bool) {
+	if h.allocator == nil || chatID == "" {
+		return 1, false, true
+	}
+	fraction, limited, err := h.allocator.StrategyFraction(ctx, chatID, strategy)
+	if err != nil {
+		log.Printf("[ALLOCATION] Failed to load capital allocation for chat %s, skipping cycle: %v", chatID, err)
+		quest.Checkpoint["status"] = "allocation_unavailable_hold"
+		quest.Checkpoint["error"] = err.Error()
+		return 0, true, false
+	}
+	if limited {
+		quest.Checkpoint["allocation_strategy"] = strategy
+		quest.Checkpoint["allocation_fraction"] = fraction
+	}
+	return fraction, limited, true
+}
+
+// tradingCapital returns what is left of the chat's trading budget. ok is
+// false when the budget cannot be read or is fully deployed; the cycle is
+// then skipped rather than sized against the whole account.
+func (h *IntegratedQuestHandlers) tradingCapital(ctx context.Context, quest *Quest, chatID string) (capital decimal.Decimal, limited bool, ok bool) {
+	if h.budget == nil || chatID == "" {
+		return decimal.Zero, false, true
+	}
+	capital, limited, err := h.budget.TradingCapital(ctx, chatID)
+	if err != nil {
+		log.Printf("[BUDGET] Failed to load trading budget for chat %s, skipping cycle: %v", chatID, err)
+		quest.Checkpoint["status"] = "budget_unavailable_hold"
+		quest.Checkpoint["error"] = err.Error()
+		return decimal.Zero, true, false
+	}
+	if !limited {
+		return capital, false, true
+	}
+	quest.Checkpoint["budget_remaining"] = capital.String()
+	if !capital.IsPositive() {
+		log.Printf("[BUDGET] Trading budget of chat %s is fully deployed, skipping new entries", chatID)
+		quest.Checkpoint["status"] = "budget_exhausted_hold"
+		return capital, true, false
+	}
+	return capital, true, true
+}
+
+// SetOperatingProfiles applies each chat's operating profile to its scalping
+// confidence bar, position size and AI usage
+func (h *IntegratedQuestHandlers) SetOperatingProfiles(profiles OperatingProfileSource) {
+	h.profiles = profiles
+}
+
+// scalpingLimits returns the limits of the chat's operating profile. ok is
+// false when the profile cannot be read; the cycle is then skipped rather
+// than run with looser server defaults.
+func (h *IntegratedQuestHandlers) scalpingLimits(ctx context.Context, quest *Quest, chatID string) (limits ScalpingLimits, ok bool) {
+	if h.profiles == nil || chatID == "" {
+		return ScalpingLimits{}, true
+	}
+	profile, selected, err := h.profiles.ActiveProfile(ctx, chatID)
+	if err != nil {
+		log.Printf("[PROFILE] Failed to load operating profile for chat %s, skipping cycle: %v", chatID, err)
+		quest.Checkpoint["status"] = "profile_unavailable_hold"
+		quest.Checkpoint["error"] = err.Error()
+		return ScalpingLimits{}, false
+	}
+	if !selected {
+		return ScalpingLimits{}, true
+	}
+	quest.Checkpoint["operating_profile"] = profile.Name
+	return profile.ScalpingLimits(), true
+}
+
+// chatSymbols returns the chat's active symbols, or nil when every symbol may be used
+func (h *IntegratedQuestHandlers) chatSymbols(ctx context.Context, chatID string) []string {
+	if h.universe == nil {
+		return nil
+	}
+	symbols, err := h.universe.ChatSymbols(ctx, chatID)
+	if err != nil {
+		log.Printf("[WATCHLIST] Failed to load active symbols for chat %s: %v", chatID, err)
+		return nil
+	}
+	return symbols
+}
+
 // SetTradeMemory sets the trade memory for AI learning
 func (h *IntegratedQuestHandlers) SetTradeMemory(memory *TradeMemory) {
 	h.tradeMemory = memory
@@ -73,7 +408,81 @@ func (h *IntegratedQuestHandlers) SetAIScalping(llmClient llm.Client, skillRegis
 		h.orderExecutor,
 		h.tradeMemory,
 	)
+	if h.listingRisk != nil {
+		h.aiScalpingService.SetListingRiskProvider(h.listingRisk)
+	}
+	if h.stableGuard != nil {
+		h.aiScalpingService.SetStablecoinGuard(h.stableGuard)
+	}
+	if h.cooldowns != nil {
+		h.aiScalpingService.SetSymbolCooldownGuard(h.cooldowns)
+	}
+	if h.positions != nil {
+		h.aiScalpingService.SetPositionGuard(h.positions)
+	}
+	h.aiScalpingService.SetOrderOptions(h.orderDefaults.For(StrategyScalping))
+	if h.intents != nil {
+		h.aiScalpingService.SetIntentLog(h.intents)
+	}
+	if h.exchangeGuard != nil {
+		h.aiScalpingService.SetExchangeGuard(h.exchangeGuard)
+	}
+	if h.leverageGuard != nil {
+		h.aiScalpingService.SetLeverageGuard(h.leverageGuard)
+	}
+	if h.promptRouter != nil {
+		h.aiScalpingService.SetPromptRouter(h.promptRouter)
+	}
+	if h.positioning != nil {
+		h.aiScalpingService.SetPositioningSource(h.positioning)
+	}
+	if h.tradeFlow != nil {
+		h.aiScalpingService.SetTradeFlowSource(h.tradeFlow)
+	}
+	if h.decisions != nil {
+		h.aiScalpingService.SetDecisionRecorder(h.decisions)
+	}
+	if h.similarDecisions != nil {
+		h.aiScalpingService.SetSimilarDecisionSource(h.similarDecisions)
+	}
+	if h.regime != nil {
+		h.aiScalpingService.SetRegimeSource(h.regime)
+	}
+	if h.news != nil {
+		h.aiScalpingService.SetNewsSource(h.news)
+	}
+	if h.openPositions != nil {
+		h.aiScalpingService.SetOpenPositionSource(h.openPositions)
+	}
 	log.Printf("[SCALPING] AI-driven scalping service initialized")
+
+	if h.shadowConfig != nil {
+		variantConfig := DefaultAIScalpingConfig()
+		// The variant only decides; its fills are modeled by the runner.
+		variantConfig.AutoExecute = false
+		if h.shadowConfig.MinConfidence > 0 {
+			variantConfig.MinConfidence = h.shadowConfig.MinConfidence
+		}
+		if h.shadowConfig.MaxCapitalPct > 0 {
+			variantConfig.MaxCapitalPct = h.shadowConfig.MaxCapitalPct
+		}
+		variantConfig.PromptNotes = h.shadowConfig.PromptNotes
+		variant := NewAIScalpingService(variantConfig, llmClient, skillRegistry, ccxtSvc, nil, h.tradeMemory)
+		if h.listingRisk != nil {
+			variant.SetListingRiskProvider(h.listingRisk)
+		}
+		if h.stableGuard != nil {
+			variant.SetStablecoinGuard(h.stableGuard)
+		}
+		if h.cooldowns != nil {
+			variant.SetSymbolCooldownGuard(h.cooldowns)
+		}
+		if h.exchangeGuard != nil {
+			variant.SetExchangeGuard(h.exchangeGuard)
+		}
+		h.shadowStrategy = NewShadowStrategyRunner(variant, ccxtSvc, variantConfig.Exchange, *h.shadowConfig)
+		log.Printf("[SCALPING] Shadow strategy %q initialized", h.shadowStrategy.config.Name)
+	}
 }
 
 // RegisterIntegratedHandlers registers production-ready quest handlers
@@ -92,6 +501,10 @@ func (e *QuestEngine) RegisterIntegratedHandlers(handlers *IntegratedQuestHandle
 			err = handlers.handlePortfolioHealthWithRisk(ctx, quest)
 		case "scalping_execution":
 			err = handlers.handleScalpingExecution(ctx, quest)
+		case "daily_report":
+			err = handlers.handleReport(ctx, quest, ReportDaily)
+		case "weekly_report":
+			err = handlers.handleReport(ctx, quest, ReportWeekly)
 		default:
 			err = fmt.Errorf("unknown routine quest definition: %s", quest.Metadata["definition_id"])
 		}
@@ -99,6 +512,14 @@ func (e *QuestEngine) RegisterIntegratedHandlers(handlers *IntegratedQuestHandle
 		return err
 	})
 
+	// Goal quests - fund growth tracks equity towards a monetary target
+	e.RegisterHandler(QuestTypeGoal, func(ctx context.Context, quest *Quest) error {
+		if quest.Metadata["definition_id"] != FundGrowthDefinitionID {
+			return fmt.Errorf("unknown goal quest definition: %s", quest.Metadata["definition_id"])
+		}
+		return handlers.handleFundGrowth(ctx, quest)
+	})
+
 	// Arbitrage Execution - execute arbitrage opportunities when detected
 	e.RegisterHandler(QuestTypeArbitrage, func(ctx context.Context, quest *Quest) error {
 		err := handlers.handleArbitrageExecution(ctx, quest)
@@ -128,9 +549,12 @@ func (h *IntegratedQuestHandlers) handleMarketScanWithTA(ctx context.Context, qu
 	// Get chat ID from quest metadata
 	chatID := quest.Metadata["chat_id"]
 
-	// Scan major trading pairs
-	majorPairs := []string{
-		"BTC/USDT", "ETH/USDT", "BNB/USDT", "SOL/USDT", "XRP/USDT",
+	// Scan the chat's active universe, or the major trading pairs without one
+	majorPairs := h.chatSymbols(ctx, chatID)
+	if len(majorPairs) == 0 {
+		majorPairs = []string{
+			"BTC/USDT", "ETH/USDT", "BNB/USDT", "SOL/USDT", "XRP/USDT",
+		}
 	}
 
 	for range majorPairs {
@@ -250,6 +674,28 @@ func (h *IntegratedQuestHandlers) handlePortfolioHealthWithRisk(ctx context.Cont
 	return nil
 }
 
+// handleReport generates the chat's daily or weekly report and delivers it
+func (h *IntegratedQuestHandlers) handleReport(ctx context.Context, quest *Quest, kind ReportKind) error {
+	if h.reports == nil {
+		return fmt.Errorf("report generator not configured")
+	}
+	report, err := h.reports.Generate(ctx, quest.Metadata["chat_id"], kind)
+	if err != nil {
+		return fmt.Errorf("failed to build %s report: %w", kind, err)
+	}
+
+	quest.CurrentCount++
+	if quest.Checkpoint == nil {
+		quest.Checkpoint = make(map[string]interface{})
+	}
+	quest.Checkpoint["last_report_at"] = report.PnL.GeneratedAt.Format(time.RFC3339)
+	quest.Checkpoint["trades"] = report.PnL.Trades
+	quest.Checkpoint["net_pnl"] = report.PnL.Net.StringFixed(2)
+	quest.Checkpoint["risk_events"] = len(report.RiskEvents)
+
+	return h.reports.Deliver(ctx, report)
+}
+
 // handleScalpingExecution executes scalping trades using integrated services
 func (h *IntegratedQuestHandlers) handleScalpingExecution(ctx context.Context, quest *Quest) error {
 	log.Printf("[SCALPING] === START AI-DRIVEN SCALPING QUEST ===")
@@ -259,6 +705,9 @@ func (h *IntegratedQuestHandlers) handleScalpingExecution(ctx context.Context, q
 	}
 
 	chatID := quest.Metadata["chat_id"]
+	if h.entriesHalted(ctx, quest, chatID) {
+		return nil
+	}
 
 	if h.aiScalpingService != nil {
 		return h.executeAIScalping(ctx, quest, chatID)
@@ -306,15 +755,66 @@ func (h *IntegratedQuestHandlers) executeAIScalping(ctx context.Context, quest *
 		return nil
 	}
 
+	capital, budgeted, ok := h.tradingCapital(ctx, quest, chatID)
+	if !ok {
+		quest.Checkpoint["chat_id"] = chatID
+		return nil
+	}
+	if budgeted && capital.InexactFloat64() < usdtBalance {
+		usdtBalance = capital.InexactFloat64()
+	}
+
+	fraction, limited, ok := h.strategyFraction(ctx, quest, chatID, StrategyScalping)
+	if !ok {
+		quest.Checkpoint["chat_id"] = chatID
+		return nil
+	}
+	if limited {
+		usdtBalance *= fraction
+		quest.Checkpoint["allocated_usdt"] = usdtBalance
+		if usdtBalance <= 0 {
+			log.Printf("[SCALPING] No capital allocated to scalping for chat %s, skipping cycle", chatID)
+			quest.Checkpoint["status"] = "allocation_zero_hold"
+			quest.Checkpoint["chat_id"] = chatID
+			return nil
+		}
+	}
+
+	multiplier, ok := h.lossStreakMultiplier(ctx, quest, chatID, StrategyScalping)
+	if !ok {
+		return nil
+	}
+	usdtBalance *= multiplier
+
+	limits, ok := h.scalpingLimits(ctx, quest, chatID)
+	if !ok {
+		quest.Checkpoint["chat_id"] = chatID
+		return nil
+	}
+
 	portfolio := TradingPortfolio{
 		USDTBalance:   usdtBalance,
 		TotalValue:    usdtBalance,
 		OpenPositions: 0,
+		Limits:        limits,
 	}
 
 	log.Printf("[SCALPING] Portfolio: %.2f USDT available", usdtBalance)
 
-	decision, err := h.aiScalpingService.ExecuteTradingCycle(ctx, portfolio)
+	symbols := h.chatSymbols(ctx, chatID)
+	decision, err := h.aiScalpingService.ExecuteTradingCycleForSymbols(ctx, portfolio, symbols)
+	if h.shadowStrategy != nil {
+		// The shadow variant decides in the background so live scalping is not delayed.
+		liveDecision := decision
+		if err != nil {
+			liveDecision = nil
+		}
+		go func() {
+			shadowCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Minute)
+			defer cancel()
+			h.shadowStrategy.RunCycle(shadowCtx, symbols, liveDecision)
+		}()
+	}
 	if err != nil {
 		log.Printf("[SCALPING] AI decision error: %v", err)
 		quest.Checkpoint["status"] = "ai_error"
@@ -327,6 +827,9 @@ func (h *IntegratedQuestHandlers) executeAIScalping(ctx context.Context, quest *
 	quest.Checkpoint["ai_confidence"] = decision.Confidence
 	quest.Checkpoint["ai_reasoning"] = decision.Reasoning
 	quest.Checkpoint["ai_size_pct"] = decision.SizePercent
+	if decision.DecisionID != "" {
+		quest.Checkpoint["decision_id"] = decision.DecisionID
+	}
 
 	if decision.Action == "hold" {
 		log.Printf("[SCALPING] AI decided to hold: %s", decision.Reasoning)
@@ -373,6 +876,10 @@ func (h *IntegratedQuestHandlers) handleArbitrageExecution(ctx context.Context,
 		return err
 	}
 
+	if h.entriesHalted(ctx, quest, quest.Metadata["chat_id"]) {
+		return nil
+	}
+
 	// Get arbitrage parameters from quest checkpoint
 	arbType, ok := quest.Checkpoint["type"].(string)
 	if !ok {
@@ -460,11 +967,57 @@ func (h *IntegratedQuestHandlers) handleArbitrageExecution(ctx context.Context,
 		// First, buy on the cheaper exchange
 		amount := decimal.NewFromFloat(10.0) // Use a conservative amount for testing
 
+		capped, ok := h.capArbitrageAmount(ctx, quest, arbType, buyExchange, symbol, buyPrice, amount)
+		if !ok {
+			return nil
+		}
+		amount = capped
+
+		strategy := arbitrageStrategy(arbType)
+		if !h.arbitrageLegsAllowed(ctx, quest, strategy, symbol, buyExchange, sellExchange) {
+			return nil
+		}
+
 		log.Printf("[ARBITRAGE] Placing BUY order: %s on %s at %.4f, amount: %.2f",
 			symbol, buyExchange, buyPrice.InexactFloat64(), amount.InexactFloat64())
 
+		// With IOC or FOK defaults the legs are limit orders at the quoted
+		// prices, so neither fills worse than the opportunity
+		options := h.orderDefaults.For(strategy)
+		buyOptions, sellOptions := options, options
+
+		var intent *DecisionIntent
+		if h.intents != nil {
+			intent = &DecisionIntent{
+				Strategy: strategy,
+				QuestID:  quest.ID,
+				Orders: []IntentOrder{
+					{Exchange: buyExchange, Symbol: symbol, Side: "buy", OrderType: "market", Amount: amount, Price: &buyPrice},
+					{Exchange: sellExchange, Symbol: symbol, Side: "sell", OrderType: "market", Amount: amount, Price: &sellPrice},
+				},
+			}
+			if err := h.intents.Begin(ctx, intent); err != nil {
+				quest.Checkpoint["execution_status"] = "intent_log_unavailable"
+				return fmt.Errorf("intent log unavailable: %w", err)
+			}
+			defer func() {
+				if err := h.intents.Finish(ctx, intent); err != nil {
+					log.Printf("[ARBITRAGE] Failed to finish order intent: %v", err)
+				}
+			}()
+			buyOptions.ClientOrderID = intent.Orders[0].ClientOrderID
+			sellOptions.ClientOrderID = intent.Orders[1].ClientOrderID
+		}
+
 		// Place buy order
-		buyOrderID, err := h.orderExecutor.PlaceOrder(ctx, buyExchange, symbol, "buy", "market", amount, &buyPrice)
+		buyOrderID, err := placeOrderWithOptions(ctx, h.orderExecutor, buyExchange, symbol, "buy", "market", amount, &buyPrice, buyOptions)
+		h.recordIntentOrder(ctx, intent, 0, buyOrderID, err)
+		if errors.Is(err, ErrPostOnlyRejected) {
+			log.Printf("[ARBITRAGE] Post-only BUY on %s would have taken liquidity, skipping opportunity", buyExchange)
+			quest.Checkpoint["status"] = "post_only_rejected_hold"
+			quest.Checkpoint["buy_execution_status"] = "post_only_rejected"
+			return nil
+		}
 		if err != nil {
 			log.Printf("[ARBITRAGE] BUY ORDER FAILED: %v", err)
 			quest.Checkpoint["buy_execution_error"] = err.Error()
@@ -475,22 +1028,29 @@ func (h *IntegratedQuestHandlers) handleArbitrageExecution(ctx context.Context,
 		log.Printf("[ARBITRAGE] BUY ORDER PLACED: %s %s %s, orderID: %s", "buy", buyExchange, symbol, buyOrderID)
 		quest.Checkpoint["buy_order_id"] = buyOrderID
 		quest.Checkpoint["buy_execution_status"] = "placed"
+		h.recordStrategyFill(ctx, strategy, buyExchange, symbol, "buy", amount)
 
 		// Then, sell on the more expensive exchange
 		log.Printf("[ARBITRAGE] Placing SELL order: %s on %s at %.4f, amount: %.2f",
 			symbol, sellExchange, sellPrice.InexactFloat64(), amount.InexactFloat64())
 
-		sellOrderID, err := h.orderExecutor.PlaceOrder(ctx, sellExchange, symbol, "sell", "market", amount, &sellPrice)
+		sellOrderID, err := placeOrderWithOptions(ctx, h.orderExecutor, sellExchange, symbol, "sell", "market", amount, &sellPrice, sellOptions)
+		h.recordIntentOrder(ctx, intent, 1, sellOrderID, err)
 		if err != nil {
 			log.Printf("[ARBITRAGE] SELL ORDER FAILED: %v", err)
 			quest.Checkpoint["sell_execution_error"] = err.Error()
 			quest.Checkpoint["sell_execution_status"] = "failed"
+			if errors.Is(err, ErrPostOnlyRejected) {
+				// The buy leg is already open, so this is a failure rather than a skip
+				quest.Checkpoint["sell_execution_status"] = "post_only_rejected"
+			}
 			return fmt.Errorf("sell order execution failed: %w", err)
 		}
 
 		log.Printf("[ARBITRAGE] SELL ORDER PLACED: %s %s %s, orderID: %s", "sell", sellExchange, symbol, sellOrderID)
 		quest.Checkpoint["sell_order_id"] = sellOrderID
 		quest.Checkpoint["sell_execution_status"] = "placed"
+		h.recordStrategyFill(ctx, strategy, sellExchange, symbol, "sell", amount)
 
 		log.Printf("[ARBITRAGE] ARBITRAGE EXECUTED: Buy %s on %s, Sell %s on %s, Expected profit: %s%%",
 			symbol, buyExchange, symbol, sellExchange, profitPct.String())
@@ -517,6 +1077,123 @@ func (h *IntegratedQuestHandlers) handleArbitrageExecution(ctx context.Context,
 	return nil
 }
 
+// recordIntentOrder records a leg's outcome in the intent log when one is set
+func (h *IntegratedQuestHandlers) recordIntentOrder(ctx context.Context, intent *DecisionIntent, leg int, orderID string, err error) {
+	if intent == nil {
+		return
+	}
+	if recErr := h.intents.RecordOrder(ctx, intent, leg, orderID, err); recErr != nil {
+		log.Printf("[ARBITRAGE] Failed to record order intent: %v", recErr)
+	}
+}
+
+// arbitrageStrategy returns the strategy an arbitrage type belongs to
+func arbitrageStrategy(arbType string) string {
+	if strings.Contains(arbType, "funding") {
+		return StrategyFundingArbitrage
+	}
+	return StrategyArbitrage
+}
+
+// arbitrageLegsAllowed checks both legs against positions other strategies
+// hold; the opportunity is skipped when either leg would overlap
+func (h *IntegratedQuestHandlers) arbitrageLegsAllowed(ctx context.Context, quest *Quest, strategy, symbol, buyExchange, sellExchange string) bool {
+	if h.positions == nil {
+		return true
+	}
+	for _, leg := range []struct{ exchange, side string }{{buyExchange, "buy"}, {sellExchange, "sell"}} {
+		if err := h.positions.CheckOverlap(ctx, strategy, leg.exchange, symbol, leg.side); err != nil {
+			log.Printf("[RISK] Skipping %s opportunity on %s: %v", strategy, symbol, err)
+			quest.Checkpoint["status"] = "position_overlap_hold"
+			quest.Checkpoint["error"] = err.Error()
+			return false
+		}
+	}
+	return true
+}
+
+// recordStrategyFill adds a placed order to the strategy position registry
+func (h *IntegratedQuestHandlers) recordStrategyFill(ctx context.Context, strategy, exchange, symbol, side string, amount decimal.Decimal) {
+	if h.positions == nil {
+		return
+	}
+	if err := h.positions.RecordFill(ctx, strategy, exchange, symbol, side, amount); err != nil {
+		log.Printf("[RISK] Failed to record %s position on %s: %v", strategy, symbol, err)
+	}
+}
+
+// capArbitrageAmount limits an arbitrage order to the strategy's share of the
+// quote balance on the buy exchange and to what is left of the chat's trading
+// budget. ok is false when the opportunity must be skipped; the reason is
+// recorded in the quest checkpoint.
+func (h *IntegratedQuestHandlers) capArbitrageAmount(ctx context.Context, quest *Quest, arbType, exchange, symbol string, price, amount decimal.Decimal) (decimal.Decimal, bool) {
+	strategy := arbitrageStrategy(arbType)
+	multiplier, ok := h.lossStreakMultiplier(ctx, quest, quest.Metadata["chat_id"], strategy)
+	if !ok {
+		return amount, false
+	}
+	amount = amount.Mul(decimal.NewFromFloat(multiplier))
+
+	capital, budgeted, ok := h.tradingCapital(ctx, quest, quest.Metadata["chat_id"])
+	if !ok {
+		return amount, false
+	}
+	fraction, limited, ok := h.strategyFraction(ctx, quest, quest.Metadata["chat_id"], strategy)
+	if !ok || !limited && !budgeted {
+		return amount, ok
+	}
+	if !price.IsPositive() {
+		quest.Checkpoint["status"] = "balance_unavailable_hold"
+		return amount, false
+	}
+
+	// Without an allocation the whole remaining budget is available
+	available := capital
+	if limited {
+		balanceFetcher, isFetcher := h.ccxtService.(interface {
+			FetchBalance(ctx context.Context, exchange string) (*ccxt.BalanceResponse, error)
+		})
+		if !isFetcher {
+			quest.Checkpoint["status"] = "balance_unavailable_hold"
+			return amount, false
+		}
+		balanceCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		balance, err := balanceFetcher.FetchBalance(balanceCtx, exchange)
+		if err != nil || balance == nil {
+			log.Printf("[ARBITRAGE] Failed to fetch balance on %s, skipping opportunity: %v", exchange, err)
+			quest.Checkpoint["status"] = "balance_unavailable_hold"
+			return amount, false
+		}
+
+		quote := symbol
+		if i := strings.Index(quote, "/"); i >= 0 {
+			quote = quote[i+1:]
+		}
+		if i := strings.Index(quote, ":"); i >= 0 {
+			quote = quote[:i]
+		}
+		available = decimal.NewFromFloat(balance.Total[quote])
+		if budgeted {
+			available = decimal.Min(available, capital)
+		}
+	}
+	budget := available.Mul(decimal.NewFromFloat(fraction))
+	quest.Checkpoint["allocated_quote"] = budget.String()
+
+	maxAmount := budget.Div(price)
+	if !maxAmount.IsPositive() {
+		log.Printf("[ARBITRAGE] No capital allocated to %s, skipping opportunity", strategy)
+		quest.Checkpoint["status"] = "allocation_zero_hold"
+		return amount, false
+	}
+	if amount.GreaterThan(maxAmount) {
+		log.Printf("[ARBITRAGE] Capping amount from %s to %s by the %s allocation and trading budget", amount.String(), maxAmount.String(), strategy)
+		amount = maxAmount
+	}
+	return amount, true
+}
+
 // GetScalpingPerformanceStats returns current scalping performance
 func (h *IntegratedQuestHandlers) GetScalpingPerformanceStats() map[string]interface{} {
 	return GetScalpingPerformance().GetPerformance()
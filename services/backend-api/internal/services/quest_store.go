@@ -342,3 +342,43 @@ func (s *DBQuestStore) CountQuests(ctx context.Context, status QuestStatus) (int
 	}
 	return count, err
 }
+
+func (s *DBQuestStore) ListAutonomousStates(ctx context.Context) ([]*AutonomousState, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+
+	rows, err := s.db.Query(ctx, `
+		SELECT chat_id, is_active, started_at, paused_at, active_quests
+		FROM autonomous_state ORDER BY chat_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list autonomous states: %w", err)
+	}
+	defer rows.Close()
+
+	states := make([]*AutonomousState, 0)
+	for rows.Next() {
+		var state AutonomousState
+		var activeQuestsJSON []byte
+		var startedAt, pausedAt sql.NullTime
+
+		if err := rows.Scan(&state.ChatID, &state.IsActive, &startedAt, &pausedAt, &activeQuestsJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan autonomous state: %w", err)
+		}
+		if startedAt.Valid {
+			state.StartedAt = startedAt.Time
+		}
+		if pausedAt.Valid {
+			state.PausedAt = pausedAt.Time
+		}
+		if len(activeQuestsJSON) > 0 {
+			if err := json.Unmarshal(activeQuestsJSON, &state.ActiveQuests); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal active quests: %w", err)
+			}
+		}
+		states = append(states, &state)
+	}
+
+	return states, rows.Err()
+}
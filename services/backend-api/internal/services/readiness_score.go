@@ -0,0 +1,457 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	defaultReadinessMinScore = 70
+	// readinessTopBlockers is how many blockers the explanation lists.
+	readinessTopBlockers = 3
+	// readinessUnknownScore is the score of a component whose source failed.
+	readinessUnknownScore = 50
+	// readinessHealthyScore is the score at or above which a component is ok.
+	readinessHealthyScore = 80
+)
+
+// Readiness component names.
+const (
+	ReadinessDataFreshness  = "data_freshness"
+	ReadinessExchangeHealth = "exchange_health"
+	ReadinessRiskBudget     = "risk_budget"
+	ReadinessAIBudget       = "ai_budget"
+	ReadinessConfigValidity = "config_validity"
+)
+
+// Readiness component statuses.
+const (
+	ReadinessStatusOK       = "ok"
+	ReadinessStatusDegraded = "degraded"
+	// ReadinessStatusBlocking fails the go/no-go gate regardless of the score.
+	ReadinessStatusBlocking = "blocking"
+	// ReadinessStatusUnknown is reported when the component's source failed.
+	ReadinessStatusUnknown = "unknown"
+	// ReadinessStatusSkipped is reported when no source is configured; the
+	// component does not count towards the score.
+	ReadinessStatusSkipped = "skipped"
+)
+
+// readinessWeights are the share of the score each component carries.
+var readinessWeights = map[string]float64{
+	ReadinessDataFreshness:  25,
+	ReadinessExchangeHealth: 20,
+	ReadinessRiskBudget:     25,
+	ReadinessAIBudget:       10,
+	ReadinessConfigValidity: 20,
+}
+
+var readinessLabels = map[string]string{
+	ReadinessDataFreshness:  "Data freshness",
+	ReadinessExchangeHealth: "Exchange health",
+	ReadinessRiskBudget:     "Risk budget",
+	ReadinessAIBudget:       "AI budget",
+	ReadinessConfigValidity: "Config",
+}
+
+// ExchangeHealthSource reports the latest health of every collected
+// exchange. It is implemented by ExchangeOutageDetector.
+type ExchangeHealthSource interface {
+	Health(ctx context.Context) ([]ExchangeHealth, error)
+}
+
+// DailyLossSource reports a chat's profit and loss for the current UTC day.
+// It is implemented by DailyLossCircuit.
+type DailyLossSource interface {
+	Evaluate(ctx context.Context, chatID string) (*DailyLossState, error)
+}
+
+// AICostSource reports AI spend. It is implemented by
+// database.AIUsageRepository.
+type AICostSource interface {
+	GetDailyCost(ctx context.Context, date time.Time, userID *string) (decimal.Decimal, error)
+	GetMonthlyCost(ctx context.Context, date time.Time, userID *string) (decimal.Decimal, error)
+}
+
+// ReadinessIssue is a configuration problem found by the caller, with what
+// the operator can do about it.
+type ReadinessIssue struct {
+	Problem string
+	Remedy  string
+}
+
+// ReadinessConfig configures the readiness score.
+type ReadinessConfig struct {
+	// MinScore is the score, out of 100, below which autonomous mode is a
+	// no-go even without a blocking component.
+	MinScore int
+	// StaleAfter is how old an exchange's last ticker update may be.
+	StaleAfter time.Duration
+	// DailyAIBudget and MonthlyAIBudget are the AI spend limits in USD;
+	// zero leaves that period unchecked.
+	DailyAIBudget   decimal.Decimal
+	MonthlyAIBudget decimal.Decimal
+}
+
+// ReadinessComponent is one weighted input of the readiness score.
+type ReadinessComponent struct {
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+	// Score is out of 100.
+	Score  float64 `json:"score"`
+	Status string  `json:"status"`
+	Reason string  `json:"reason,omitempty"`
+	// Remedy is what the operator can do to raise the score.
+	Remedy string `json:"remedy,omitempty"`
+}
+
+// ReadinessReport is the go/no-go decision for autonomous mode.
+type ReadinessReport struct {
+	// Score is the weighted score out of 100 of the components that have a
+	// source.
+	Score    int  `json:"score"`
+	MinScore int  `json:"min_score"`
+	Go       bool `json:"go"`
+	// Components are in a fixed order: data freshness, exchange health,
+	// risk budget, AI budget and config validity.
+	Components []ReadinessComponent `json:"components"`
+	// Blockers are the components holding the score back, worst first.
+	Blockers    []ReadinessComponent `json:"blockers"`
+	Explanation string               `json:"explanation"`
+	CheckedAt   time.Time            `json:"checked_at"`
+}
+
+// ReadinessScorer weighs data freshness, exchange health, the remaining
+// risk and AI budgets, and config validity into one readiness score.
+type ReadinessScorer struct {
+	config    ReadinessConfig
+	exchanges ExchangeHealthSource
+	dailyLoss DailyLossSource
+	aiCosts   AICostSource
+	now       func() time.Time
+}
+
+// NewReadinessScorer creates a readiness scorer. Components without a
+// source are skipped until one is set.
+//
+// Parameters:
+//
+//	config: Score threshold, staleness and AI budgets; zero values use defaults.
+//
+// Returns:
+//
+//	*ReadinessScorer: The initialized scorer.
+func NewReadinessScorer(config ReadinessConfig) *ReadinessScorer {
+	if config.MinScore <= 0 {
+		config.MinScore = defaultReadinessMinScore
+	}
+	if config.StaleAfter <= 0 {
+		config.StaleAfter = defaultOutageStaleAfter
+	}
+	return &ReadinessScorer{config: config, now: time.Now}
+}
+
+// SetExchangeHealth scores data freshness and exchange health.
+func (s *ReadinessScorer) SetExchangeHealth(source ExchangeHealthSource) {
+	s.exchanges = source
+}
+
+// SetDailyLoss scores the remaining daily risk budget.
+func (s *ReadinessScorer) SetDailyLoss(source DailyLossSource) {
+	s.dailyLoss = source
+}
+
+// SetAICosts scores the remaining AI budget.
+func (s *ReadinessScorer) SetAICosts(source AICostSource) {
+	s.aiCosts = source
+}
+
+// Evaluate scores a chat's readiness for autonomous mode.
+//
+// Parameters:
+//
+//	ctx: Context for the source lookups.
+//	chatID: The chat whose risk budget is scored.
+//	issues: Configuration problems found by the caller; any of them blocks.
+//
+// Returns:
+//
+//	*ReadinessReport: The score, the go/no-go decision and its explanation.
+func (s *ReadinessScorer) Evaluate(ctx context.Context, chatID string, issues []ReadinessIssue) *ReadinessReport {
+	now := s.now().UTC()
+	freshness, health := s.exchangeComponents(ctx, now)
+	components := []ReadinessComponent{
+		freshness,
+		health,
+		s.riskComponent(ctx, chatID),
+		s.aiComponent(ctx, now),
+		configComponent(issues),
+	}
+
+	var total, weights float64
+	for i := range components {
+		components[i].Weight = readinessWeights[components[i].Name]
+		if components[i].Status == ReadinessStatusSkipped {
+			continue
+		}
+		total += components[i].Weight * components[i].Score
+		weights += components[i].Weight
+	}
+	report := &ReadinessReport{
+		Score:      100,
+		MinScore:   s.config.MinScore,
+		Components: components,
+		Blockers:   []ReadinessComponent{},
+		CheckedAt:  now,
+	}
+	if weights > 0 {
+		report.Score = int(math.Round(total / weights))
+	}
+
+	blocking := false
+	for _, component := range components {
+		switch component.Status {
+		case ReadinessStatusBlocking:
+			blocking = true
+			report.Blockers = append(report.Blockers, component)
+		case ReadinessStatusDegraded, ReadinessStatusUnknown:
+			report.Blockers = append(report.Blockers, component)
+		}
+	}
+	sort.SliceStable(report.Blockers, func(i, j int) bool {
+		a, b := report.Blockers[i], report.Blockers[j]
+		if (a.Status == ReadinessStatusBlocking) != (b.Status == ReadinessStatusBlocking) {
+			return a.Status == ReadinessStatusBlocking
+		}
+		return a.Weight*(100-a.Score) > b.Weight*(100-b.Score)
+	})
+	if len(report.Blockers) > readinessTopBlockers {
+		report.Blockers = report.Blockers[:readinessTopBlockers]
+	}
+	report.Go = !blocking && report.Score >= report.MinScore
+	report.Explanation = report.explain()
+	return report
+}
+
+func (r *ReadinessReport) explain() string {
+	var b strings.Builder
+	switch {
+	case r.Go && len(r.Blockers) == 0:
+		fmt.Fprintf(&b, "Go: readiness %d/100, every check passes.", r.Score)
+		return b.String()
+	case r.Go:
+		fmt.Fprintf(&b, "Go: readiness %d/100. Worth fixing:", r.Score)
+	case r.Score < r.MinScore:
+		fmt.Fprintf(&b, "No-go: readiness %d/100 is below the minimum of %d. Top blockers:", r.Score, r.MinScore)
+	default:
+		fmt.Fprintf(&b, "No-go: readiness %d/100, but a blocking check failed. Top blockers:", r.Score)
+	}
+	for i, blocker := range r.Blockers {
+		fmt.Fprintf(&b, "\n%d. %s: %s", i+1, readinessLabels[blocker.Name], blocker.Reason)
+		if blocker.Remedy != "" {
+			fmt.Fprintf(&b, " Fix: %s", blocker.Remedy)
+		}
+	}
+	return b.String()
+}
+
+func (s *ReadinessScorer) exchangeComponents(ctx context.Context, now time.Time) (ReadinessComponent, ReadinessComponent) {
+	freshness := ReadinessComponent{Name: ReadinessDataFreshness}
+	health := ReadinessComponent{Name: ReadinessExchangeHealth}
+	if s.exchanges == nil {
+		return skippedComponent(freshness), skippedComponent(health)
+	}
+	exchanges, err := s.exchanges.Health(ctx)
+	if err != nil {
+		return unknownComponent(freshness, "exchange health", err), unknownComponent(health, "exchange health", err)
+	}
+	if len(exchanges) == 0 {
+		freshness.Score, freshness.Status = 0, ReadinessStatusBlocking
+		freshness.Reason = "no exchange has reported market data yet."
+		freshness.Remedy = "check that the collector and the CCXT service are running."
+		health.Score, health.Status = 0, ReadinessStatusBlocking
+		health.Reason = "no exchange is being collected."
+		health.Remedy = "enable at least one exchange in the collector configuration."
+		return freshness, health
+	}
+
+	var stale, unhealthy, paused []string
+	var latest time.Time
+	for _, exchange := range exchanges {
+		if exchange.LastTickerAt.After(latest) {
+			latest = exchange.LastTickerAt
+		}
+		if exchange.LastTickerAt.IsZero() || now.Sub(exchange.LastTickerAt) > s.config.StaleAfter {
+			stale = append(stale, exchange.Exchange)
+		}
+		switch {
+		case exchange.Paused:
+			paused = append(paused, exchange.Exchange)
+			unhealthy = append(unhealthy, fmt.Sprintf("%s is paused (%s)", exchange.Exchange, strings.Join(exchange.Reasons, ", ")))
+		case exchange.Degraded:
+			unhealthy = append(unhealthy, fmt.Sprintf("%s is degraded (%s)", exchange.Exchange, strings.Join(exchange.Reasons, ", ")))
+		}
+	}
+
+	freshness.Score = scoreShare(len(exchanges)-len(stale), len(exchanges))
+	switch {
+	case len(stale) == len(exchanges):
+		freshness.Status = ReadinessStatusBlocking
+		if latest.IsZero() {
+			freshness.Reason = "no exchange has reported market data yet."
+		} else {
+			freshness.Reason = fmt.Sprintf("market data is stale on every exchange; the last update was %s ago.", now.Sub(latest).Round(time.Second))
+		}
+		freshness.Remedy = "check that the collector and the CCXT service are running; trading on stale prices is unsafe."
+	case len(stale) > 0:
+		freshness.Status = statusForScore(freshness.Score)
+		freshness.Reason = fmt.Sprintf("no update in %s from %s.", s.config.StaleAfter, strings.Join(stale, ", "))
+		freshness.Remedy = "check the collector logs for those exchanges."
+	default:
+		freshness.Status = ReadinessStatusOK
+	}
+
+	health.Score = scoreShare(len(exchanges)-len(unhealthy), len(exchanges))
+	switch {
+	case len(paused) == len(exchanges):
+		health.Status = ReadinessStatusBlocking
+		health.Reason = strings.Join(unhealthy, "; ") + "."
+		health.Remedy = "strategies resume automatically once an exchange stays healthy; follow it with GET /api/v1/admin/exchange-health."
+	case len(unhealthy) > 0:
+		health.Status = statusForScore(health.Score)
+		health.Reason = strings.Join(unhealthy, "; ") + "."
+		health.Remedy = "strategies on paused exchanges resume automatically once they stay healthy."
+	default:
+		health.Status = ReadinessStatusOK
+	}
+	return freshness, health
+}
+
+func (s *ReadinessScorer) riskComponent(ctx context.Context, chatID string) ReadinessComponent {
+	component := ReadinessComponent{Name: ReadinessRiskBudget}
+	if s.dailyLoss == nil {
+		return skippedComponent(component)
+	}
+	state, err := s.dailyLoss.Evaluate(ctx, chatID)
+	if err != nil {
+		return unknownComponent(component, "the daily loss state", err)
+	}
+	if state.Halted {
+		component.Score, component.Status = 0, ReadinessStatusBlocking
+		component.Reason = fmt.Sprintf("the daily loss cap of %s USDT was hit (PnL %s USDT).", state.Limit.StringFixed(2), state.PnL.StringFixed(2))
+		component.Remedy = "new entries resume at the next UTC day; review today's trades with /pnl."
+		return component
+	}
+	if !state.Limit.IsPositive() {
+		component.Score, component.Status = 100, ReadinessStatusOK
+		return component
+	}
+
+	loss := decimal.Max(state.PnL.Neg(), decimal.Zero)
+	used, _ := loss.Div(state.Limit).Float64()
+	component.Score = clampScore(100 * (1 - used))
+	component.Status = statusForScore(component.Score)
+	if component.Status != ReadinessStatusOK {
+		component.Reason = fmt.Sprintf("%s of the %s USDT daily loss budget is used.", loss.StringFixed(2), state.Limit.StringFixed(2))
+		component.Remedy = "reduce position sizes or wait for the UTC day to roll over."
+	}
+	return component
+}
+
+func (s *ReadinessScorer) aiComponent(ctx context.Context, now time.Time) ReadinessComponent {
+	component := ReadinessComponent{Name: ReadinessAIBudget}
+	if s.aiCosts == nil || (!s.config.DailyAIBudget.IsPositive() && !s.config.MonthlyAIBudget.IsPositive()) {
+		return skippedComponent(component)
+	}
+
+	type period struct {
+		name   string
+		budget decimal.Decimal
+		cost   func(context.Context, time.Time, *string) (decimal.Decimal, error)
+		env    string
+		reset  string
+	}
+	component.Score = 100
+	for _, p := range []period{
+		{"daily", s.config.DailyAIBudget, s.aiCosts.GetDailyCost, "AI_DAILY_BUDGET", "next UTC day"},
+		{"monthly", s.config.MonthlyAIBudget, s.aiCosts.GetMonthlyCost, "AI_MONTHLY_BUDGET", "next month"},
+	} {
+		if !p.budget.IsPositive() {
+			continue
+		}
+		spent, err := p.cost(ctx, now, nil)
+		if err != nil {
+			return unknownComponent(component, "AI spend", err)
+		}
+		used, _ := spent.Div(p.budget).Float64()
+		score := clampScore(100 * (1 - used))
+		if score >= component.Score {
+			continue
+		}
+		component.Score = score
+		component.Reason = fmt.Sprintf("$%s of the $%s %s AI budget is spent.", spent.StringFixed(2), p.budget.StringFixed(2), p.name)
+		component.Remedy = fmt.Sprintf("raise %s or wait for the %s.", p.env, p.reset)
+	}
+	component.Status = statusForScore(component.Score)
+	if component.Score == 0 {
+		component.Status = ReadinessStatusBlocking
+	}
+	if component.Status == ReadinessStatusOK {
+		component.Reason, component.Remedy = "", ""
+	}
+	return component
+}
+
+func configComponent(issues []ReadinessIssue) ReadinessComponent {
+	component := ReadinessComponent{Name: ReadinessConfigValidity, Score: 100, Status: ReadinessStatusOK}
+	if len(issues) == 0 {
+		return component
+	}
+	problems := make([]string, 0, len(issues))
+	remedies := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		problems = append(problems, issue.Problem)
+		if issue.Remedy != "" {
+			remedies = append(remedies, issue.Remedy)
+		}
+	}
+	component.Score, component.Status = 0, ReadinessStatusBlocking
+	component.Reason = strings.Join(problems, "; ") + "."
+	component.Remedy = strings.Join(remedies, "; ") + "."
+	return component
+}
+
+func skippedComponent(component ReadinessComponent) ReadinessComponent {
+	component.Status = ReadinessStatusSkipped
+	component.Reason = "not configured on this server."
+	return component
+}
+
+func unknownComponent(component ReadinessComponent, what string, err error) ReadinessComponent {
+	component.Score = readinessUnknownScore
+	component.Status = ReadinessStatusUnknown
+	component.Reason = fmt.Sprintf("could not read %s: %v.", what, err)
+	component.Remedy = "check the backend logs and retry."
+	return component
+}
+
+func statusForScore(score float64) string {
+	if score >= readinessHealthyScore {
+		return ReadinessStatusOK
+	}
+	return ReadinessStatusDegraded
+}
+
+func scoreShare(good, total int) float64 {
+	return clampScore(100 * float64(good) / float64(total))
+}
+
+func clampScore(score float64) float64 {
+	return math.Round(math.Max(0, math.Min(100, score)))
+}
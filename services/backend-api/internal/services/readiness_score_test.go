@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubExchangeHealth struct {
+	health []ExchangeHealth
+	err    error
+}
+
+func (s stubExchangeHealth) Health(context.Context) ([]ExchangeHealth, error) { return s.health, s.err }
+
+type stubDailyLoss struct{ state DailyLossState }
+
+func (s stubDailyLoss) Evaluate(context.Context, string) (*DailyLossState, error) {
+	return &s.state, nil
+}
+
+type stubAICosts struct{ daily, monthly decimal.Decimal }
+
+func (s stubAICosts) GetDailyCost(context.Context, time.Time, *string) (decimal.Decimal, error) {
+	return s.daily, nil
+}
+
+func (s stubAICosts) GetMonthlyCost(context.Context, time.Time, *string) (decimal.Decimal, error) {
+	return s.monthly, nil
+}
+
+func newTestReadinessScorer(now time.Time) *ReadinessScorer {
+	scorer := NewReadinessScorer(ReadinessConfig{
+		DailyAIBudget:   decimal.NewFromInt(10),
+		MonthlyAIBudget: decimal.NewFromInt(200),
+	})
+	scorer.now = func() time.Time { return now }
+	scorer.SetExchangeHealth(stubExchangeHealth{health: []ExchangeHealth{
+		{Exchange: "binance", LastTickerAt: now.Add(-time.Minute)},
+		{Exchange: "okx", LastTickerAt: now.Add(-time.Minute)},
+	}})
+	scorer.SetDailyLoss(stubDailyLoss{state: DailyLossState{Limit: decimal.NewFromInt(100)}})
+	scorer.SetAICosts(stubAICosts{daily: decimal.NewFromInt(1), monthly: decimal.NewFromInt(20)})
+	return scorer
+}
+
+func TestReadinessScorer_Go(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	report := newTestReadinessScorer(now).Evaluate(t.Context(), "7", nil)
+
+	assert.True(t, report.Go)
+	assert.Equal(t, 99, report.Score, "only the AI budget is partly spent")
+	assert.Empty(t, report.Blockers)
+	assert.Equal(t, "Go: readiness 99/100, every check passes.", report.Explanation)
+	require.Len(t, report.Components, 5)
+	assert.Equal(t, ReadinessAIBudget, report.Components[3].Name)
+	assert.Equal(t, 90.0, report.Components[3].Score)
+	assert.Equal(t, 10.0, report.Components[3].Weight)
+}
+
+func TestReadinessScorer_NoGo(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	scorer := newTestReadinessScorer(now)
+	scorer.SetExchangeHealth(stubExchangeHealth{health: []ExchangeHealth{
+		{Exchange: "binance", LastTickerAt: now.Add(-time.Minute), Paused: true, Reasons: []string{"error rate 80%"}},
+		{Exchange: "okx", LastTickerAt: now.Add(-20 * time.Minute), Degraded: true, Reasons: []string{"stale ticker"}},
+	}})
+	scorer.SetDailyLoss(stubDailyLoss{state: DailyLossState{Limit: decimal.NewFromInt(100), PnL: decimal.NewFromInt(-70)}})
+
+	report := scorer.Evaluate(t.Context(), "7", []ReadinessIssue{{Problem: "exchange minimum not met", Remedy: "connect an exchange with /connect_exchange"}})
+	assert.False(t, report.Go)
+	// freshness 50*25 + health 0*20 + risk 30*25 + AI 90*10 + config 0*20
+	assert.Equal(t, 29, report.Score)
+	require.Len(t, report.Blockers, 3)
+	assert.Equal(t, ReadinessConfigValidity, report.Blockers[0].Name, "blocking components come first")
+	assert.Equal(t, ReadinessExchangeHealth, report.Blockers[1].Name, "then the most points lost")
+	assert.Equal(t, ReadinessRiskBudget, report.Blockers[2].Name)
+	assert.Contains(t, report.Explanation, "No-go: readiness 29/100 is below the minimum of 70. Top blockers:\n1. Config: exchange minimum not met. Fix: connect an exchange with /connect_exchange.")
+	assert.Contains(t, report.Explanation, "\n2. Exchange health: binance is paused (error rate 80%); okx is degraded (stale ticker).")
+	assert.Contains(t, report.Explanation, "\n3. Risk budget: 70.00 of the 100.00 USDT daily loss budget is used.")
+}
+
+func TestReadinessScorer_BlockingComponentOverridesScore(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	scorer := newTestReadinessScorer(now)
+	scorer.SetDailyLoss(stubDailyLoss{state: DailyLossState{Limit: decimal.NewFromInt(100), PnL: decimal.NewFromInt(-101), Halted: true}})
+
+	report := scorer.Evaluate(t.Context(), "7", nil)
+	assert.Equal(t, 74, report.Score)
+	assert.False(t, report.Go)
+	require.Len(t, report.Blockers, 1)
+	assert.Equal(t, ReadinessStatusBlocking, report.Blockers[0].Status)
+	assert.Contains(t, report.Explanation, "but a blocking check failed")
+	assert.Contains(t, report.Explanation, "Fix: new entries resume at the next UTC day")
+}
+
+func TestReadinessScorer_SkipsAndUnknownSources(t *testing.T) {
+	scorer := NewReadinessScorer(ReadinessConfig{})
+	report := scorer.Evaluate(t.Context(), "7", nil)
+	assert.True(t, report.Go)
+	assert.Equal(t, 100, report.Score, "only config validity is scored without sources")
+	for _, component := range report.Components[:4] {
+		assert.Equal(t, ReadinessStatusSkipped, component.Status, component.Name)
+	}
+
+	scorer.SetExchangeHealth(stubExchangeHealth{err: errors.New("redis down")})
+	report = scorer.Evaluate(t.Context(), "7", nil)
+	// (50*25 + 50*20 + 100*20) / 65
+	assert.Equal(t, 65, report.Score)
+	assert.False(t, report.Go)
+	assert.Equal(t, ReadinessStatusUnknown, report.Components[0].Status)
+	assert.Contains(t, report.Explanation, "could not read exchange health: redis down.")
+}
@@ -60,6 +60,13 @@ type ResourceManager struct {
 	monitoringCancel context.CancelFunc
 	shutdownChan     chan struct{}
 	shutdownOnce     sync.Once
+
+	// Load shedding under CPU/memory pressure (see load_shedding.go)
+	sheddingMu      sync.RWMutex
+	sheddingEnabled bool
+	sheddingConfig  LoadSheddingConfig
+	shedding        LoadSheddingStatus
+	sampleLoad      func(ctx context.Context) (cpuPercent, memoryPercent float64, err error)
 }
 
 // NewResourceManager creates a new resource manager.
@@ -84,6 +91,8 @@ func NewResourceManager(logger *zaplogrus.Logger) *ResourceManager {
 		monitoringCtx:    ctx,
 		monitoringCancel: cancel,
 		shutdownChan:     make(chan struct{}),
+		shedding:         LoadSheddingStatus{Level: LoadSheddingNone},
+		sampleLoad:       sampleSystemLoad,
 	}
 
 	// Initialize stats for all resource types
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	riskEventLogKey              = "risk:events"
+	defaultRiskEventLogRetention = 8 * 24 * time.Hour
+)
+
+var _ EventEmitter = (*RiskEventLog)(nil)
+
+// RiskEventRecord is one recorded risk.event, such as a daily loss halt or a
+// liquidation proximity alert.
+type RiskEventRecord struct {
+	Type   string                 `json:"type"`
+	ChatID string                 `json:"chat_id,omitempty"`
+	Data   map[string]interface{} `json:"data"`
+	At     time.Time              `json:"at"`
+}
+
+// Subject names what the event is about: the strategy, symbol or exchange
+// it concerns, or an empty string.
+func (e RiskEventRecord) Subject() string {
+	for _, key := range []string{"strategy", "symbol", "exchange", "stablecoin"} {
+		if value, ok := e.Data[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// RiskEventSource lists recent risk events.
+type RiskEventSource interface {
+	RecentRiskEvents(ctx context.Context, since time.Time, limit int) ([]RiskEventRecord, error)
+}
+
+// RiskEventLog keeps the risk events of the last days in Redis so reports
+// can summarize them. It is registered as an event emitter and ignores
+// every other event.
+type RiskEventLog struct {
+	redis     *redis.Client
+	retention time.Duration
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+// NewRiskEventLog creates a risk event log.
+//
+// Parameters:
+//
+//	client: Redis client.
+//	retention: How long events are kept; zero keeps them for 8 days.
+//
+// Returns:
+//
+//	*RiskEventLog: The initialized log.
+func NewRiskEventLog(client *redis.Client, retention time.Duration) *RiskEventLog {
+	if retention <= 0 {
+		retention = defaultRiskEventLogRetention
+	}
+	return &RiskEventLog{
+		redis:     client,
+		retention: retention,
+		logger:    telemetry.Logger().With("component", "risk_event_log"),
+		now:       time.Now,
+	}
+}
+
+// Emit records risk events.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	event: Event type; only risk.event is recorded.
+//	data: Event payload.
+func (l *RiskEventLog) Emit(ctx context.Context, event WebhookEventType, data interface{}) {
+	if event != WebhookEventRisk {
+		return
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	entry := RiskEventRecord{At: l.now().UTC()}
+	if err := json.Unmarshal(raw, &entry.Data); err != nil {
+		return
+	}
+	entry.Type, _ = entry.Data["type"].(string)
+	entry.ChatID, _ = entry.Data["chat_id"].(string)
+	if err := l.record(context.WithoutCancel(ctx), entry); err != nil {
+		l.logger.Warn("Failed to record risk event", "type", entry.Type, "error", err)
+	}
+}
+
+// RecentRiskEvents returns the risk events since a time, oldest first.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	since: Earliest event time.
+//	limit: Maximum number of newest events; zero returns all.
+//
+// Returns:
+//
+//	[]RiskEventRecord: Events.
+//	error: Error if Redis fails.
+func (l *RiskEventLog) RecentRiskEvents(ctx context.Context, since time.Time, limit int) ([]RiskEventRecord, error) {
+	query := &redis.ZRangeBy{Min: strconv.FormatInt(since.UnixMilli(), 10), Max: "+inf"}
+	if limit > 0 {
+		query.Count = int64(limit)
+	}
+	raw, err := l.redis.ZRevRangeByScore(ctx, riskEventLogKey, query).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load risk events: %w", err)
+	}
+	events := make([]RiskEventRecord, 0, len(raw))
+	for i := len(raw) - 1; i >= 0; i-- {
+		var event RiskEventRecord
+		if err := json.Unmarshal([]byte(raw[i]), &event); err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (l *RiskEventLog) record(ctx context.Context, event RiskEventRecord) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	pipe := l.redis.TxPipeline()
+	pipe.ZAdd(ctx, riskEventLogKey, redis.Z{Score: float64(event.At.UnixMilli()), Member: raw})
+	pipe.ZRemRangeByScore(ctx, riskEventLogKey, "-inf", strconv.FormatInt(event.At.Add(-l.retention).UnixMilli(), 10))
+	_, err = pipe.Exec(ctx)
+	return err
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRiskEventLog(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	log := NewRiskEventLog(client, 48*time.Hour)
+	now := time.Date(2026, 6, 10, 12, 0, 0, 0, time.UTC)
+	log.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	log.Emit(ctx, WebhookEventRisk, map[string]interface{}{"type": "daily_loss_halt", "chat_id": "42", "pnl": "-120.00"})
+	log.Emit(ctx, WebhookEventTradeExecuted, map[string]interface{}{"symbol": "BTC/USDT"})
+	now = now.Add(time.Hour)
+	log.Emit(ctx, WebhookEventRisk, map[string]interface{}{"type": "consecutive_losses", "strategy": "scalping"})
+
+	events, err := log.RecentRiskEvents(ctx, now.Add(-24*time.Hour), 0)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "daily_loss_halt", events[0].Type)
+	assert.Equal(t, "42", events[0].ChatID)
+	assert.Equal(t, "scalping", events[1].Subject())
+
+	latest, err := log.RecentRiskEvents(ctx, now.Add(-24*time.Hour), 1)
+	require.NoError(t, err)
+	require.Len(t, latest, 1)
+	assert.Equal(t, "consecutive_losses", latest[0].Type)
+
+	// Older events are pruned past the retention
+	now = now.Add(72 * time.Hour)
+	log.Emit(ctx, WebhookEventRisk, map[string]interface{}{"type": "exchange_paused", "exchange": "binance"})
+	events, err = log.RecentRiskEvents(ctx, time.Time{}, 0)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "exchange_paused", events[0].Type)
+}
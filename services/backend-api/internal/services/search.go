@@ -0,0 +1,461 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// ErrInvalidSearchQuery is wrapped by errors for malformed queries.
+var ErrInvalidSearchQuery = errors.New("invalid search query")
+
+// SearchField scopes a query term to a kind of column.
+type SearchField string
+
+const (
+	SearchFieldText     SearchField = ""
+	SearchFieldSymbol   SearchField = "symbol"
+	SearchFieldStrategy SearchField = "strategy"
+	SearchFieldQuest    SearchField = "quest"
+	SearchFieldError    SearchField = "error"
+)
+
+// Search result kinds, also the values of the "in:" qualifier.
+const (
+	SearchKindTrade  = "trade"
+	SearchKindSignal = "signal"
+	SearchKindQuest  = "quest"
+	SearchKindLog    = "log"
+)
+
+// SearchQuery is a parsed search. Free terms match any column; scoped terms
+// only match columns of their field, and sources without such a column are
+// skipped.
+type SearchQuery struct {
+	Terms  []string
+	Scoped map[SearchField]string
+	// Kinds restricts the sources searched; empty searches all.
+	Kinds []string
+}
+
+// SearchResult is one ranked match.
+type SearchResult struct {
+	Kind    string    `json:"kind"`
+	ID      string    `json:"id"`
+	Title   string    `json:"title"`
+	Snippet string    `json:"snippet,omitempty"`
+	Matched []string  `json:"matched"`
+	Score   float64   `json:"score"`
+	At      time.Time `json:"at"`
+}
+
+// SearchResponse is the result of a search.
+type SearchResponse struct {
+	Query   string         `json:"query"`
+	Results []SearchResult `json:"results"`
+}
+
+// searchColumn is a column a source selects. Columns without a field are
+// only displayed.
+type searchColumn struct {
+	name   string
+	expr   string
+	field  SearchField
+	weight int
+}
+
+// searchSource is a table searched for one kind of result.
+type searchSource struct {
+	kind    string
+	table   string
+	id      string
+	at      string
+	where   string
+	columns []searchColumn
+	title   func(values map[string]string) string
+	snippet func(values map[string]string) string
+}
+
+var searchSources = []searchSource{
+	{
+		kind:  SearchKindTrade,
+		table: "trade_outcomes",
+		id:    "id",
+		at:    "updated_at",
+		columns: []searchColumn{
+			{name: "symbol", expr: "symbol", field: SearchFieldSymbol, weight: 3},
+			{name: "strategy", expr: "skill_id", field: SearchFieldStrategy, weight: 3},
+			{name: "exchange", expr: "exchange", weight: 1},
+			{name: "side", expr: "side", weight: 1},
+			{name: "outcome", expr: "outcome", weight: 1},
+			{name: "exit_reason", expr: "COALESCE(exit_reason, '')", weight: 1},
+			{name: "pnl", expr: "COALESCE(pnl, 0)::text"},
+		},
+		title: func(v map[string]string) string {
+			return fmt.Sprintf("%s %s %s (%s)", v["symbol"], v["side"], v["outcome"], v["strategy"])
+		},
+		snippet: func(v map[string]string) string {
+			snippet := "PnL " + formatSearchAmount(v["pnl"]) + " on " + v["exchange"]
+			if v["exit_reason"] != "" {
+				snippet += ", " + v["exit_reason"]
+			}
+			return snippet
+		},
+	},
+	{
+		kind:  SearchKindSignal,
+		table: "aggregated_signals",
+		id:    "id",
+		at:    "created_at",
+		columns: []searchColumn{
+			{name: "symbol", expr: "symbol", field: SearchFieldSymbol, weight: 3},
+			{name: "type", expr: "signal_type", field: SearchFieldStrategy, weight: 2},
+			{name: "source", expr: "COALESCE(metadata->>'source', '')", weight: 2},
+			{name: "action", expr: "action", weight: 1},
+			{name: "indicators", expr: "array_to_string(indicators, ' ')", weight: 1},
+			{name: "strength", expr: "strength"},
+		},
+		title: func(v map[string]string) string {
+			return fmt.Sprintf("%s %s %s signal (%s)", strings.ToUpper(v["action"]), v["symbol"], v["type"], v["strength"])
+		},
+		snippet: func(v map[string]string) string {
+			if v["source"] != "" {
+				return "From " + v["source"] + "; " + v["indicators"]
+			}
+			return v["indicators"]
+		},
+	},
+	{
+		kind:  SearchKindQuest,
+		table: "quests",
+		id:    "id",
+		at:    "updated_at",
+		columns: []searchColumn{
+			{name: "name", expr: "name", field: SearchFieldQuest, weight: 3},
+			{name: "type", expr: "type", field: SearchFieldQuest, weight: 1},
+			{name: "description", expr: "COALESCE(description, '')", weight: 1},
+			{name: "last_error", expr: "COALESCE(last_error, '')", field: SearchFieldError, weight: 3},
+			{name: "status", expr: "status", weight: 1},
+		},
+		title: func(v map[string]string) string {
+			return fmt.Sprintf("%s [%s]", v["name"], v["status"])
+		},
+		snippet: func(v map[string]string) string {
+			if v["last_error"] != "" {
+				return "Last error: " + v["last_error"]
+			}
+			return v["description"]
+		},
+	},
+	{
+		kind:  SearchKindLog,
+		table: "exchange_api_call_log",
+		id:    "id::text",
+		at:    "called_at",
+		where: "success = false",
+		columns: []searchColumn{
+			{name: "error", expr: "COALESCE(error_message, '')", field: SearchFieldError, weight: 3},
+			{name: "exchange", expr: "exchange", weight: 2},
+			{name: "endpoint", expr: "COALESCE(endpoint, '')", weight: 1},
+		},
+		title: func(v map[string]string) string {
+			return fmt.Sprintf("%s %s failed", v["exchange"], v["endpoint"])
+		},
+		snippet: func(v map[string]string) string { return v["error"] },
+	},
+}
+
+// ParseSearchQuery parses a query such as "symbol:btcusdt in:trade stop_loss"
+// into free terms, scoped terms and the kinds to search.
+//
+// Parameters:
+//
+//	raw: Query text.
+//
+// Returns:
+//
+//	SearchQuery: The parsed query.
+//	error: Error if the query is empty or has an unknown qualifier.
+func ParseSearchQuery(raw string) (SearchQuery, error) {
+	query := SearchQuery{Scoped: make(map[SearchField]string)}
+	for _, token := range strings.Fields(raw) {
+		qualifier, value, found := strings.Cut(token, ":")
+		if !found || value == "" {
+			query.Terms = append(query.Terms, token)
+			continue
+		}
+		switch qualifier = strings.ToLower(qualifier); qualifier {
+		case "in":
+			for _, kind := range strings.Split(strings.ToLower(value), ",") {
+				kind = strings.TrimSuffix(kind, "s")
+				if !isSearchKind(kind) {
+					return SearchQuery{}, fmt.Errorf("%w: unknown search scope %q", ErrInvalidSearchQuery, kind)
+				}
+				query.Kinds = append(query.Kinds, kind)
+			}
+		case string(SearchFieldSymbol), string(SearchFieldStrategy), string(SearchFieldQuest), string(SearchFieldError):
+			query.Scoped[SearchField(qualifier)] = value
+		default:
+			// Text such as "BTC/USDT:USDT" or "timeout:5s" is a free term
+			query.Terms = append(query.Terms, token)
+		}
+	}
+	if len(query.Terms) == 0 && len(query.Scoped) == 0 {
+		return SearchQuery{}, fmt.Errorf("%w: query is empty", ErrInvalidSearchQuery)
+	}
+	return query, nil
+}
+
+func isSearchKind(kind string) bool {
+	for _, source := range searchSources {
+		if source.kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// SearchService searches trades, signals, quests and exchange error logs.
+type SearchService struct {
+	db  DBPool
+	now func() time.Time
+}
+
+// NewSearchService creates a search service.
+//
+// Parameters:
+//
+//	db: Database pool.
+//
+// Returns:
+//
+//	*SearchService: The initialized service.
+func NewSearchService(db DBPool) *SearchService {
+	return &SearchService{db: db, now: time.Now}
+}
+
+// Search runs a query and ranks the matches of every source together.
+// Columns score by weight, an exact match above a prefix above a substring,
+// with a bonus for recent rows.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	raw: Query text, see ParseSearchQuery.
+//	kinds: Kinds to search in addition to "in:" qualifiers (may be empty).
+//	limit: Maximum results; zero uses 20, at most 100.
+//
+// Returns:
+//
+//	*SearchResponse: The ranked results.
+//	error: Error if the query is invalid or a source cannot be searched.
+func (s *SearchService) Search(ctx context.Context, raw string, kinds []string, limit int) (*SearchResponse, error) {
+	query, err := ParseSearchQuery(raw)
+	if err != nil {
+		return nil, err
+	}
+	for _, kind := range kinds {
+		if kind = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(kind)), "s"); kind != "" {
+			if !isSearchKind(kind) {
+				return nil, fmt.Errorf("%w: unknown search scope %q", ErrInvalidSearchQuery, kind)
+			}
+			query.Kinds = append(query.Kinds, kind)
+		}
+	}
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	limit = min(limit, maxSearchLimit)
+
+	results := []SearchResult{}
+	for _, source := range searchSources {
+		if len(query.Kinds) > 0 && !containsString(query.Kinds, source.kind) {
+			continue
+		}
+		matches, err := s.searchSource(ctx, source, query, limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search %ss: %w", source.kind, err)
+		}
+		results = append(results, matches...)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].At.After(results[j].At)
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return &SearchResponse{Query: raw, Results: results}, nil
+}
+
+// searchNeedle is one query term and the columns it may match.
+type searchNeedle struct {
+	variants []string
+	field    SearchField
+}
+
+func (s *SearchService) searchSource(ctx context.Context, source searchSource, query SearchQuery, limit int) ([]SearchResult, error) {
+	var needles []searchNeedle
+	for _, term := range query.Terms {
+		needles = append(needles, searchNeedle{variants: searchVariants(term), field: SearchFieldText})
+	}
+	for _, field := range []SearchField{SearchFieldSymbol, SearchFieldStrategy, SearchFieldQuest, SearchFieldError} {
+		value, ok := query.Scoped[field]
+		if !ok {
+			continue
+		}
+		if len(source.columnsFor(field)) == 0 {
+			return nil, nil
+		}
+		needles = append(needles, searchNeedle{variants: searchVariants(value), field: field})
+	}
+
+	selects := []string{source.id, source.at}
+	for _, column := range source.columns {
+		selects = append(selects, column.expr)
+	}
+	var conditions []string
+	if source.where != "" {
+		conditions = append(conditions, source.where)
+	}
+	var args []interface{}
+	for _, needle := range needles {
+		var matches []string
+		for _, column := range source.columnsFor(needle.field) {
+			for _, variant := range needle.variants {
+				args = append(args, "%"+escapeLikePattern(variant)+"%")
+				matches = append(matches, column.expr+" ILIKE $"+strconv.Itoa(len(args)))
+			}
+		}
+		conditions = append(conditions, "("+strings.Join(matches, " OR ")+")")
+	}
+	args = append(args, limit)
+	sql := fmt.Sprintf("SELECT %s FROM %s WHERE %s ORDER BY %s DESC LIMIT $%d",
+		strings.Join(selects, ", "), source.table, strings.Join(conditions, " AND "), source.at, len(args))
+
+	rows, err := s.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var id string
+		var at time.Time
+		values := make([]string, len(source.columns))
+		dest := []interface{}{&id, &at}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		byName := make(map[string]string, len(values))
+		for i, column := range source.columns {
+			byName[column.name] = values[i]
+		}
+		score, matched := source.score(byName, needles)
+		// Recent rows rank higher, halving over a week
+		score += 1 / (1 + s.now().Sub(at).Hours()/(7*24))
+		results = append(results, SearchResult{
+			Kind:    source.kind,
+			ID:      id,
+			Title:   source.title(byName),
+			Snippet: source.snippet(byName),
+			Matched: matched,
+			Score:   math.Round(score*100) / 100,
+			At:      at,
+		})
+	}
+	return results, rows.Err()
+}
+
+// columnsFor returns the searchable columns a needle of a field may match.
+func (source searchSource) columnsFor(field SearchField) []searchColumn {
+	var columns []searchColumn
+	for _, column := range source.columns {
+		if column.weight > 0 && (field == SearchFieldText || column.field == field) {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+func (source searchSource) score(values map[string]string, needles []searchNeedle) (float64, []string) {
+	score := 0.0
+	var matched []string
+	for _, needle := range needles {
+		for _, column := range source.columnsFor(needle.field) {
+			value := strings.ToLower(values[column.name])
+			best := 0
+			for _, variant := range needle.variants {
+				variant = strings.ToLower(variant)
+				switch {
+				case value == variant:
+					best = max(best, 3)
+				case strings.HasPrefix(value, variant):
+					best = max(best, 2)
+				case strings.Contains(value, variant):
+					best = max(best, 1)
+				}
+			}
+			if best > 0 {
+				score += float64(best * column.weight)
+				if !containsString(matched, column.name) {
+					matched = append(matched, column.name)
+				}
+			}
+		}
+	}
+	return score, matched
+}
+
+// searchVariants returns a term and, for compact symbols such as BTCUSDT,
+// its BASE/QUOTE form.
+func searchVariants(term string) []string {
+	if strings.ContainsFunc(term, func(r rune) bool {
+		return (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	}) {
+		return []string{term}
+	}
+	// A bare asset reads as ASSET/USDT, which would narrow the search
+	symbol := NormalizeSymbol(term)
+	if symbol == strings.ToUpper(term)+"/"+DefaultSymbolQuote {
+		return []string{term}
+	}
+	return []string{term, symbol}
+}
+
+func escapeLikePattern(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+func formatSearchAmount(value string) string {
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+	return fmt.Sprintf("%+.2f", amount)
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	query, err := ParseSearchQuery("symbol:btcusdt in:trades,logs stop_loss BTC/USDT:USDT")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"stop_loss", "BTC/USDT:USDT"}, query.Terms)
+	assert.Equal(t, "btcusdt", query.Scoped[SearchFieldSymbol])
+	assert.Equal(t, []string{SearchKindTrade, SearchKindLog}, query.Kinds)
+
+	_, err = ParseSearchQuery("in:orders btc")
+	assert.ErrorIs(t, err, ErrInvalidSearchQuery)
+	_, err = ParseSearchQuery("in:trades")
+	assert.ErrorIs(t, err, ErrInvalidSearchQuery)
+}
+
+func TestSearchService_Search(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+
+	svc := NewSearchService(database.NewMockDBPool(mockPool))
+	now := time.Date(2026, 6, 10, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := t.Context()
+
+	stopLoss := `%stop\_loss%`
+	mockPool.ExpectQuery(`FROM trade_outcomes WHERE \(symbol ILIKE \$1 OR .*\) AND \(symbol ILIKE \$7 OR symbol ILIKE \$8\) ORDER BY updated_at DESC LIMIT \$9`).
+		WithArgs(stopLoss, stopLoss, stopLoss, stopLoss, stopLoss, stopLoss, "%BTCUSDT%", "%BTC/USDT%", 5).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "updated_at", "symbol", "skill_id", "exchange", "side", "outcome", "exit_reason", "pnl"}).
+			AddRow("t-1", now.Add(-48*time.Hour), "BTC/USDT", "scalping", "binance", "long", "loss", "stop_loss", "-12.5").
+			AddRow("t-2", now.Add(-time.Hour), "BTC/USDT", "scalping", "binance", "long", "loss", "stop_loss_trailing", "-3"))
+
+	response, err := svc.Search(ctx, "symbol:BTCUSDT stop_loss", []string{"trades"}, 5)
+	require.NoError(t, err)
+	require.Len(t, response.Results, 2)
+	// The exact exit reason outranks the newer prefix match
+	first := response.Results[0]
+	assert.Equal(t, "t-1", first.ID)
+	assert.Equal(t, "BTC/USDT long loss (scalping)", first.Title)
+	assert.Equal(t, "PnL -12.50 on binance, stop_loss", first.Snippet)
+	assert.Equal(t, []string{"exit_reason", "symbol"}, first.Matched)
+	assert.Greater(t, first.Score, response.Results[1].Score)
+
+	// Error text skips the trade and signal tables
+	mockPool.ExpectQuery(`FROM quests WHERE \(COALESCE\(last_error, ''\) ILIKE \$1\)`).
+		WithArgs("%timeout%", 20).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "updated_at", "name", "type", "description", "last_error", "status"}).
+			AddRow("q-1", now.Add(-2*time.Hour), "Scalping Execution", "routine", "", "order timeout", "active"))
+	mockPool.ExpectQuery(`FROM exchange_api_call_log WHERE success = false AND \(COALESCE\(error_message, ''\) ILIKE \$1\)`).
+		WithArgs("%timeout%", 20).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "called_at", "error_message", "exchange", "endpoint"}).
+			AddRow("l-1", now.Add(-time.Hour), "timeout", "bybit", "fetchTicker"))
+
+	response, err = svc.Search(ctx, "error:timeout", nil, 0)
+	require.NoError(t, err)
+	require.Len(t, response.Results, 2)
+	assert.Equal(t, SearchKindLog, response.Results[0].Kind)
+	assert.Equal(t, "bybit fetchTicker failed", response.Results[0].Title)
+	assert.Equal(t, "Last error: order timeout", response.Results[1].Snippet)
+	require.NoError(t, mockPool.ExpectationsWereMet())
+
+	_, err = svc.Search(ctx, "btc", []string{"orders"}, 0)
+	assert.ErrorIs(t, err, ErrInvalidSearchQuery)
+}
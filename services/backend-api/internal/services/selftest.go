@@ -0,0 +1,363 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/irfndi/neuratrade/pkg/indicators"
+	"github.com/shopspring/decimal"
+)
+
+// SelfTestStageName identifies a stage of the end-to-end self-test.
+type SelfTestStageName string
+
+// Self-test stages, in the order they run. Each stage feeds the next.
+const (
+	SelfTestStageMarketData   SelfTestStageName = "market_data"
+	SelfTestStageIndicators   SelfTestStageName = "indicators"
+	SelfTestStageSignal       SelfTestStageName = "signal"
+	SelfTestStageOrder        SelfTestStageName = "order"
+	SelfTestStageNotification SelfTestStageName = "notification"
+)
+
+// SelfTestStatus is the outcome of a self-test stage.
+type SelfTestStatus string
+
+const (
+	SelfTestPassed  SelfTestStatus = "passed"
+	SelfTestFailed  SelfTestStatus = "failed"
+	SelfTestSkipped SelfTestStatus = "skipped"
+)
+
+// ErrSelfTestInvalidQuery is returned for a malformed self-test request.
+var ErrSelfTestInvalidQuery = errors.New("invalid self-test query")
+
+// selfTestOrderSize is the quantity of the simulated order; it never reaches
+// an exchange.
+var selfTestOrderSize = decimal.NewFromFloat(0.001)
+
+// SelfTestConfig holds the defaults of a self-test run.
+type SelfTestConfig struct {
+	Exchange  string
+	Symbol    string
+	Timeframe string
+	// Candles is how many candles are fetched; indicators need at least 50.
+	Candles int
+	// StageTimeout bounds each stage so one stall does not hang the run.
+	StageTimeout time.Duration
+}
+
+// DefaultSelfTestConfig returns the self-test defaults.
+func DefaultSelfTestConfig() SelfTestConfig {
+	return SelfTestConfig{
+		Exchange:     "binance",
+		Symbol:       "BTC/USDT",
+		Timeframe:    "5m",
+		Candles:      100,
+		StageTimeout: 15 * time.Second,
+	}
+}
+
+// SelfTestQuery selects the market a self-test runs against. Empty fields
+// use the configured defaults; without a ChatID no notification is sent.
+type SelfTestQuery struct {
+	Exchange  string
+	Symbol    string
+	Timeframe string
+	ChatID    int64
+}
+
+// SelfTestStage is the outcome and timing of one stage.
+type SelfTestStage struct {
+	Name       SelfTestStageName `json:"name"`
+	Status     SelfTestStatus    `json:"status"`
+	DurationMs float64           `json:"duration_ms"`
+	Message    string            `json:"message,omitempty"`
+}
+
+// SelfTestReport is the result of an end-to-end self-test run.
+type SelfTestReport struct {
+	// Passed is true when no stage failed.
+	Passed     bool            `json:"passed"`
+	Exchange   string          `json:"exchange"`
+	Symbol     string          `json:"symbol"`
+	Timeframe  string          `json:"timeframe"`
+	Stages     []SelfTestStage `json:"stages"`
+	StartedAt  time.Time       `json:"started_at"`
+	DurationMs float64         `json:"duration_ms"`
+}
+
+// selfTestRun carries what each stage produced to the next one.
+type selfTestRun struct {
+	query    SelfTestQuery
+	candles  *indicators.OHLCVData
+	analysis *indicators.MultiIndicatorResult
+	side     PaperOrderSide
+	stages   []SelfTestStage
+}
+
+// SelfTestService runs one dry-run trading cycle end to end: fetch market
+// data, compute indicators, generate a signal, simulate an order and send
+// a test notification, timing every stage. Nothing is traded.
+type SelfTestService struct {
+	candles   OHLCVSource
+	messenger DirectMessenger
+	stack     *indicators.MultiIndicatorStack
+	simulator *PaperExecutionSimulator
+	config    SelfTestConfig
+	now       func() time.Time
+}
+
+// NewSelfTestService creates a new self-test service.
+//
+// Parameters:
+//
+//	candles: Market data source (may be nil; the market data stage then fails).
+//	messenger: Telegram sender for the test notification (may be nil).
+//	config: Self-test defaults; zero fields use DefaultSelfTestConfig.
+//
+// Returns:
+//
+//	*SelfTestService: The initialized service.
+func NewSelfTestService(candles OHLCVSource, messenger DirectMessenger, config SelfTestConfig) *SelfTestService {
+	defaults := DefaultSelfTestConfig()
+	if config.Exchange == "" {
+		config.Exchange = defaults.Exchange
+	}
+	if config.Symbol == "" {
+		config.Symbol = defaults.Symbol
+	}
+	if config.Timeframe == "" {
+		config.Timeframe = defaults.Timeframe
+	}
+	if config.Candles < 50 {
+		config.Candles = defaults.Candles
+	}
+	if config.StageTimeout <= 0 {
+		config.StageTimeout = defaults.StageTimeout
+	}
+
+	// Deterministic fills: a self-test must not fail on a simulated rejection
+	execution := DefaultPaperExecutionConfig()
+	execution.EnableRandomness = false
+	execution.ExecutionDelayMs = 0
+
+	return &SelfTestService{
+		candles:   candles,
+		messenger: messenger,
+		stack:     indicators.NewMultiIndicatorStack(indicators.NewDefaultProvider(), nil, nil),
+		simulator: NewPaperExecutionSimulator(execution),
+		config:    config,
+		now:       time.Now,
+	}
+}
+
+// Run executes every stage in order. A failed stage skips the ones that
+// depend on it, except the notification, which reports the failure.
+//
+// Parameters:
+//
+//	ctx: Context for cancellation.
+//	query: Market to test against and the chat to notify.
+//
+// Returns:
+//
+//	*SelfTestReport: Per-stage outcome and timings.
+//	error: ErrSelfTestInvalidQuery for a malformed symbol.
+func (s *SelfTestService) Run(ctx context.Context, query SelfTestQuery) (*SelfTestReport, error) {
+	query.Exchange = strings.ToLower(strings.TrimSpace(query.Exchange))
+	if query.Exchange == "" {
+		query.Exchange = s.config.Exchange
+	}
+	query.Symbol = strings.ToUpper(strings.TrimSpace(query.Symbol))
+	if query.Symbol == "" {
+		query.Symbol = s.config.Symbol
+	}
+	if !strings.Contains(query.Symbol, "/") {
+		return nil, fmt.Errorf("%w: symbol must look like BTC/USDT", ErrSelfTestInvalidQuery)
+	}
+	query.Timeframe = strings.TrimSpace(query.Timeframe)
+	if query.Timeframe == "" {
+		query.Timeframe = s.config.Timeframe
+	}
+
+	run := &selfTestRun{query: query}
+	started := s.now()
+	stages := []struct {
+		name SelfTestStageName
+		fn   func(ctx context.Context, run *selfTestRun) (string, error)
+	}{
+		{SelfTestStageMarketData, s.fetchMarketData},
+		{SelfTestStageIndicators, s.computeIndicators},
+		{SelfTestStageSignal, s.generateSignal},
+		{SelfTestStageOrder, s.simulateOrder},
+	}
+	failed := false
+	for _, stage := range stages {
+		if failed {
+			run.stages = append(run.stages, SelfTestStage{Name: stage.name, Status: SelfTestSkipped, Message: "an earlier stage failed"})
+			continue
+		}
+		result := s.runStage(ctx, run, stage.name, stage.fn)
+		failed = result.Status == SelfTestFailed
+		run.stages = append(run.stages, result)
+	}
+
+	if query.ChatID == 0 {
+		run.stages = append(run.stages, SelfTestStage{Name: SelfTestStageNotification, Status: SelfTestSkipped, Message: "no chat to notify"})
+	} else {
+		run.stages = append(run.stages, s.runStage(ctx, run, SelfTestStageNotification, s.sendNotification))
+	}
+
+	report := &SelfTestReport{
+		Passed:     true,
+		Exchange:   query.Exchange,
+		Symbol:     query.Symbol,
+		Timeframe:  query.Timeframe,
+		Stages:     run.stages,
+		StartedAt:  started,
+		DurationMs: elapsedMs(started, s.now()),
+	}
+	for _, stage := range run.stages {
+		if stage.Status == SelfTestFailed {
+			report.Passed = false
+		}
+	}
+	return report, nil
+}
+
+func (s *SelfTestService) runStage(ctx context.Context, run *selfTestRun, name SelfTestStageName, fn func(ctx context.Context, run *selfTestRun) (string, error)) SelfTestStage {
+	stageCtx, cancel := context.WithTimeout(ctx, s.config.StageTimeout)
+	defer cancel()
+
+	started := s.now()
+	message, err := fn(stageCtx, run)
+	stage := SelfTestStage{Name: name, Status: SelfTestPassed, Message: message, DurationMs: elapsedMs(started, s.now())}
+	if err != nil {
+		stage.Status = SelfTestFailed
+		stage.Message = err.Error()
+	}
+	return stage
+}
+
+func (s *SelfTestService) fetchMarketData(ctx context.Context, run *selfTestRun) (string, error) {
+	if s.candles == nil {
+		return "", errors.New("market data service not configured")
+	}
+	response, err := s.candles.FetchOHLCV(ctx, run.query.Exchange, run.query.Symbol, run.query.Timeframe, s.config.Candles)
+	if err != nil {
+		return "", fmt.Errorf("fetch candles: %w", err)
+	}
+	if response == nil || len(response.OHLCV) < 50 {
+		got := 0
+		if response != nil {
+			got = len(response.OHLCV)
+		}
+		return "", fmt.Errorf("got %d candles, indicators need at least 50", got)
+	}
+
+	data := &indicators.OHLCVData{Symbol: run.query.Symbol, Exchange: run.query.Exchange, Timeframe: run.query.Timeframe}
+	for _, candle := range response.OHLCV {
+		data.Timestamps = append(data.Timestamps, candle.Timestamp)
+		data.Open = append(data.Open, candle.Open)
+		data.High = append(data.High, candle.High)
+		data.Low = append(data.Low, candle.Low)
+		data.Close = append(data.Close, candle.Close)
+		data.Volume = append(data.Volume, candle.Volume)
+	}
+	run.candles = data
+
+	last := response.OHLCV[len(response.OHLCV)-1]
+	if age := s.now().Sub(last.Timestamp); age > 24*time.Hour {
+		return "", fmt.Errorf("latest candle is %s old", age.Round(time.Minute))
+	}
+	return fmt.Sprintf("%d %s candles, last close %s", len(response.OHLCV), run.query.Timeframe, last.Close.String()), nil
+}
+
+func (s *SelfTestService) computeIndicators(ctx context.Context, run *selfTestRun) (string, error) {
+	analysis, err := s.stack.Analyze(ctx, run.candles)
+	if err != nil {
+		return "", fmt.Errorf("analyze candles: %w", err)
+	}
+	if len(analysis.Indicators) == 0 {
+		return "", errors.New("no indicator could be computed")
+	}
+	run.analysis = analysis
+	return fmt.Sprintf("%d indicators computed", len(analysis.Indicators)), nil
+}
+
+func (s *SelfTestService) generateSignal(_ context.Context, run *selfTestRun) (string, error) {
+	confidence := run.analysis.Confidence.InexactFloat64()
+	switch run.analysis.OverallSignal {
+	case indicators.SignalBuy:
+		run.side = PaperOrderSideBuy
+	case indicators.SignalSell:
+		run.side = PaperOrderSideSell
+	default:
+		// A hold still exercises execution, with a buy
+		run.side = PaperOrderSideBuy
+		return fmt.Sprintf("hold (confidence %.2f); simulating a buy anyway", confidence), nil
+	}
+	return fmt.Sprintf("%s (confidence %.2f)", run.side, confidence), nil
+}
+
+func (s *SelfTestService) simulateOrder(ctx context.Context, run *selfTestRun) (string, error) {
+	price := run.candles.Close[len(run.candles.Close)-1]
+	order, err := s.simulator.CreateOrder(PaperOrderRequest{
+		UserID:   "self-test",
+		Exchange: run.query.Exchange,
+		Symbol:   run.query.Symbol,
+		Type:     PaperOrderTypeMarket,
+		Side:     run.side,
+		Size:     selfTestOrderSize,
+	})
+	if err != nil {
+		return "", fmt.Errorf("create paper order: %w", err)
+	}
+	order, err = s.simulator.SimulateFill(ctx, order, price)
+	if err != nil {
+		return "", fmt.Errorf("simulate fill: %w", err)
+	}
+	if order.Status != PaperOrderStatusFilled {
+		return "", fmt.Errorf("paper order %s: %s", order.Status, order.RejectReason)
+	}
+	return fmt.Sprintf("paper %s %s %s filled at %s", order.Side, order.Size.String(), order.Symbol, order.AvgFillPrice.StringFixed(8)), nil
+}
+
+func (s *SelfTestService) sendNotification(ctx context.Context, run *selfTestRun) (string, error) {
+	if s.messenger == nil {
+		return "", errors.New("notification service not configured")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "🩺 NeuraTrade self-test on %s %s\n", run.query.Exchange, run.query.Symbol)
+	for _, stage := range run.stages {
+		fmt.Fprintf(&b, "%s %s (%.0f ms)\n", selfTestMarker(stage.Status), stage.Name, stage.DurationMs)
+	}
+	b.WriteString("This is a test message; nothing was traded.")
+
+	if err := s.messenger.SendDirectMessage(ctx, run.query.ChatID, b.String()); err != nil {
+		return "", fmt.Errorf("send test notification: %w", err)
+	}
+	return fmt.Sprintf("test message sent to chat %d", run.query.ChatID), nil
+}
+
+func selfTestMarker(status SelfTestStatus) string {
+	switch status {
+	case SelfTestPassed:
+		return "✅"
+	case SelfTestFailed:
+		return "❌"
+	default:
+		return "⏭️"
+	}
+}
+
+// elapsedMs returns the time between two instants in milliseconds, to the
+// microsecond.
+func elapsedMs(from, to time.Time) float64 {
+	return float64(to.Sub(from).Microseconds()) / 1000
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type selfTestCandles struct {
+	count int
+	end   time.Time
+	err   error
+}
+
+func (f *selfTestCandles) FetchOHLCV(_ context.Context, exchange, symbol, timeframe string, limit int) (*ccxt.OHLCVResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	response := &ccxt.OHLCVResponse{Exchange: exchange, Symbol: symbol, Timeframe: timeframe}
+	for i := 0; i < f.count && i < limit; i++ {
+		price := decimal.NewFromFloat(60000 + float64(i%7)*40 + float64(i)*5)
+		response.OHLCV = append(response.OHLCV, ccxt.OHLCV{
+			Timestamp: f.end.Add(time.Duration(i-f.count+1) * 5 * time.Minute),
+			Open:      price.Sub(decimal.NewFromInt(10)),
+			High:      price.Add(decimal.NewFromInt(30)),
+			Low:       price.Sub(decimal.NewFromInt(30)),
+			Close:     price,
+			Volume:    decimal.NewFromInt(int64(10 + i%5)),
+		})
+	}
+	return response, nil
+}
+
+func selfTestStatuses(report *SelfTestReport) map[SelfTestStageName]SelfTestStatus {
+	statuses := make(map[SelfTestStageName]SelfTestStatus, len(report.Stages))
+	for _, stage := range report.Stages {
+		statuses[stage.Name] = stage.Status
+	}
+	return statuses
+}
+
+func TestSelfTestService_RunPasses(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	messenger := &recordingMessenger{}
+	service := NewSelfTestService(&selfTestCandles{count: 100, end: now}, messenger, SelfTestConfig{})
+	service.now = func() time.Time { return now }
+
+	report, err := service.Run(context.Background(), SelfTestQuery{Symbol: "eth/usdt", ChatID: 42})
+	require.NoError(t, err)
+	assert.True(t, report.Passed)
+	assert.Equal(t, "binance", report.Exchange)
+	assert.Equal(t, "ETH/USDT", report.Symbol)
+	assert.Equal(t, "5m", report.Timeframe)
+	require.Len(t, report.Stages, 5)
+	for _, stage := range report.Stages {
+		assert.Equal(t, SelfTestPassed, stage.Status, "%s: %s", stage.Name, stage.Message)
+	}
+	assert.Contains(t, report.Stages[0].Message, "100 5m candles")
+	assert.Contains(t, report.Stages[3].Message, "paper buy 0.001 ETH/USDT filled")
+
+	require.Len(t, messenger.chats, 1)
+	assert.Equal(t, int64(42), messenger.chats[0])
+	assert.Contains(t, messenger.texts[0], "✅ order")
+	assert.Contains(t, messenger.texts[0], "nothing was traded")
+}
+
+func TestSelfTestService_RunReportsFailures(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	service := NewSelfTestService(&selfTestCandles{err: errors.New("exchange unavailable")}, nil, SelfTestConfig{})
+	report, err := service.Run(ctx, SelfTestQuery{})
+	require.NoError(t, err)
+	assert.False(t, report.Passed)
+	assert.Equal(t, map[SelfTestStageName]SelfTestStatus{
+		SelfTestStageMarketData:   SelfTestFailed,
+		SelfTestStageIndicators:   SelfTestSkipped,
+		SelfTestStageSignal:       SelfTestSkipped,
+		SelfTestStageOrder:        SelfTestSkipped,
+		SelfTestStageNotification: SelfTestSkipped,
+	}, selfTestStatuses(report))
+	assert.Equal(t, "fetch candles: exchange unavailable", report.Stages[0].Message)
+
+	// Stale candles fail the market data stage; the notification still reports it
+	messenger := &recordingMessenger{}
+	service = NewSelfTestService(&selfTestCandles{count: 100, end: now.Add(-48 * time.Hour)}, messenger, SelfTestConfig{})
+	service.now = func() time.Time { return now }
+	report, err = service.Run(ctx, SelfTestQuery{ChatID: 7})
+	require.NoError(t, err)
+	assert.False(t, report.Passed)
+	assert.Contains(t, report.Stages[0].Message, "latest candle is 48h0m0s old")
+	assert.Equal(t, SelfTestPassed, selfTestStatuses(report)[SelfTestStageNotification])
+	assert.Contains(t, messenger.texts[0], "❌ market_data")
+
+	service = NewSelfTestService(&selfTestCandles{count: 20, end: now}, nil, SelfTestConfig{})
+	service.now = func() time.Time { return now }
+	report, err = service.Run(ctx, SelfTestQuery{ChatID: 7})
+	require.NoError(t, err)
+	assert.Equal(t, "got 20 candles, indicators need at least 50", report.Stages[0].Message)
+	assert.Equal(t, "notification service not configured", report.Stages[4].Message)
+
+	_, err = service.Run(ctx, SelfTestQuery{Symbol: "BTCUSDT"})
+	assert.True(t, errors.Is(err, ErrSelfTestInvalidQuery))
+}
@@ -90,6 +90,7 @@ type SentimentService struct {
 	httpClient *http.Client
 	mu         sync.RWMutex
 	cache      map[string]cacheEntry
+	embeddings EmbeddingQueue
 }
 
 type cacheEntry struct {
@@ -107,6 +108,12 @@ func NewSentimentService(config SentimentServiceConfig, db DBPool) *SentimentSer
 	}
 }
 
+// SetEmbeddingQueue queues stored news headlines for embedding so that they
+// can be found by similarity.
+func (s *SentimentService) SetEmbeddingQueue(queue EmbeddingQueue) {
+	s.embeddings = queue
+}
+
 // FetchRedditSentiment fetches sentiment from Reddit subreddits
 func (s *SentimentService) FetchRedditSentiment(ctx context.Context, subreddits []string) ([]RedditSentiment, error) {
 	// Get access token
@@ -412,11 +419,70 @@ func (s *SentimentService) storeNewsSentiment(ctx context.Context, articles []Ne
 		if err != nil {
 			continue
 		}
+		if s.embeddings != nil {
+			text := article.Title
+			if len(article.Symbols) > 0 {
+				text += " (" + strings.Join(article.Symbols, ", ") + ")"
+			}
+			s.embeddings.Enqueue(EmbeddingJob{
+				Kind:      EmbeddingKindNews,
+				SourceID:  article.URL,
+				Text:      text,
+				CreatedAt: article.PublishedAt,
+			})
+		}
 	}
 
 	return nil
 }
 
+// RecentNews returns the latest news mentioning a symbol's base asset,
+// newest first.
+//
+// Parameters:
+//
+//	ctx: Context for the query.
+//	symbol: A base asset or pair, such as "BTC" or "BTC/USDT".
+//	limit: Maximum number of articles.
+//
+// Returns:
+//
+//	[]NewsSentiment: The articles.
+//	error: Error if the query failed.
+func (s *SentimentService) RecentNews(ctx context.Context, symbol string, limit int) ([]NewsSentiment, error) {
+	base := strings.ToUpper(symbol)
+	if i := strings.IndexAny(base, "/:-"); i > 0 {
+		base = base[:i]
+	}
+	rows, err := s.db.Query(ctx, `
+		SELECT id, COALESCE(source_id, 0), title, url, published_at, COALESCE(sentiment_score, 0),
+			COALESCE(sentiment_label, ''), fetched_at
+		FROM news_sentiment
+		WHERE symbols ? $1
+		ORDER BY published_at DESC NULLS LAST
+		LIMIT $2
+	`, base, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recent news: %w", err)
+	}
+	defer rows.Close()
+
+	var articles []NewsSentiment
+	for rows.Next() {
+		var article NewsSentiment
+		var publishedAt *time.Time
+		if err := rows.Scan(&article.ID, &article.SourceID, &article.Title, &article.URL, &publishedAt,
+			&article.SentimentScore, &article.SentimentLabel, &article.FetchedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan recent news: %w", err)
+		}
+		if publishedAt != nil {
+			article.PublishedAt = *publishedAt
+		}
+		articles = append(articles, article)
+	}
+	return articles, rows.Err()
+}
+
 // GetAggregatedSentiment retrieves aggregated sentiment for a symbol
 func (s *SentimentService) GetAggregatedSentiment(ctx context.Context, symbol string) (*AggregatedSentiment, error) {
 	upperSymbol := strings.ToUpper(symbol)
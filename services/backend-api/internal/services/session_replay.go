@@ -0,0 +1,403 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Session replay limits. The live signal processor evaluates the last 100
+// ticks of a pair and the indicators need 50 prices, so replays keep the same
+// window and start with 50 candles of the previous day.
+const (
+	DefaultReplayInterval = time.Minute
+	MaxReplayInterval     = time.Hour
+	replayWindowCandles   = 100
+	replayWarmupCandles   = 50
+)
+
+// ErrReplayNoData is returned when nothing was recorded for the replayed day.
+var ErrReplayNoData = errors.New("no market data recorded for the replay day")
+
+// TechnicalSignalEvaluator computes technical signals from closing prices. It
+// is implemented by SignalAggregator.
+type TechnicalSignalEvaluator interface {
+	EvaluateTechnicalSignals(symbol, exchange string, closes []float64) []*AggregatedSignal
+}
+
+// SessionReplayConfig configures the decision pipeline of replays.
+type SessionReplayConfig struct {
+	Trader TraderAgentConfig `json:"trader"`
+	// Capital is the portfolio value replayed decisions are sized against.
+	Capital float64 `json:"capital"`
+}
+
+// DefaultSessionReplayConfig returns the trader defaults with 10,000 USDT of
+// capital.
+func DefaultSessionReplayConfig() SessionReplayConfig {
+	return SessionReplayConfig{
+		Trader:  DefaultTraderAgentConfig(),
+		Capital: 10000,
+	}
+}
+
+// SessionReplayRequest selects the recorded day to replay.
+type SessionReplayRequest struct {
+	Exchange string
+	Symbol   string
+	// Day is any time on the replayed UTC day.
+	Day time.Time
+	// Interval is the candle size ticks are aggregated into.
+	Interval time.Duration
+	// At is an optional time to explain; the report's Focus is its candle.
+	At *time.Time
+}
+
+// SessionReplayEvent is a decision recorded in the audit trail during the
+// replayed day.
+type SessionReplayEvent struct {
+	ID          string    `json:"id"`
+	Source      string    `json:"source"`
+	Action      string    `json:"action"`
+	Confidence  float64   `json:"confidence"`
+	Price       float64   `json:"price"`
+	Reasoning   string    `json:"reasoning"`
+	OrderStatus string    `json:"order_status,omitempty"`
+	At          time.Time `json:"at"`
+}
+
+// SessionReplayStep is one candle fed through the signal and decision
+// pipeline.
+type SessionReplayStep struct {
+	// At is the start of the candle; the decision is made at its close.
+	At      time.Time       `json:"at"`
+	Price   float64         `json:"price"`
+	Ticks   int             `json:"ticks"`
+	Signals []TradingSignal `json:"signals"`
+	// Action is what the trader agent decided; no order is ever placed.
+	Action       TradingAction `json:"action"`
+	Confidence   float64       `json:"confidence"`
+	SizePercent  float64       `json:"size_percent"`
+	Reasoning    string        `json:"reasoning"`
+	WouldExecute bool          `json:"would_execute"`
+	// Recorded are the decisions the audit trail holds for this candle.
+	Recorded []SessionReplayEvent `json:"recorded,omitempty"`
+	// Diverged is true when a recorded decision disagrees with the replay.
+	Diverged bool `json:"diverged"`
+}
+
+// SessionReplayReport is the outcome of replaying a recorded day.
+type SessionReplayReport struct {
+	Exchange          string    `json:"exchange"`
+	Symbol            string    `json:"symbol"`
+	Day               time.Time `json:"day"`
+	Interval          string    `json:"interval"`
+	Candles           int       `json:"candles"`
+	Ticks             int       `json:"ticks"`
+	Entries           int       `json:"entries"`
+	RecordedDecisions int       `json:"recorded_decisions"`
+	Divergences       int       `json:"divergences"`
+	// Steps are the candles where the replay would have traded or a decision
+	// was recorded, oldest first.
+	Steps []SessionReplayStep `json:"steps"`
+	// Focus is the candle containing the requested time, with its signals.
+	Focus *SessionReplayStep `json:"focus,omitempty"`
+}
+
+type replayCandle struct {
+	start  time.Time
+	close  float64
+	volume float64
+	ticks  int
+}
+
+// SessionReplayService re-feeds a recorded day of market data through the
+// technical signal and trader agent pipeline, next to the decisions recorded
+// that day, to reproduce why the system acted when it did. Replays use the
+// candle times as their clock, so the same day always replays the same way,
+// and they have no order executor. The whole day is computed at once;
+// clients pace the steps at the speed they want.
+type SessionReplayService struct {
+	db      DBPool
+	signals TechnicalSignalEvaluator
+	config  SessionReplayConfig
+}
+
+// NewSessionReplayService creates a session replay service.
+//
+// Parameters:
+//
+//	db: Database holding market_data and decision_audits.
+//	signals: The technical signal rules (the SignalAggregator).
+//	config: Trader agent settings and capital.
+//
+// Returns:
+//
+//	*SessionReplayService: The initialized service.
+func NewSessionReplayService(db DBPool, signals TechnicalSignalEvaluator, config SessionReplayConfig) *SessionReplayService {
+	if config.Capital <= 0 {
+		config.Capital = DefaultSessionReplayConfig().Capital
+	}
+	return &SessionReplayService{db: db, signals: signals, config: config}
+}
+
+// Replay replays a recorded day.
+//
+// Parameters:
+//
+//	ctx: Context for the queries.
+//	req: The exchange, symbol and day to replay.
+//
+// Returns:
+//
+//	*SessionReplayReport: The replayed decisions next to the recorded ones.
+//	error: ErrReplayNoData when nothing was recorded that day.
+func (s *SessionReplayService) Replay(ctx context.Context, req SessionReplayRequest) (*SessionReplayReport, error) {
+	interval := req.Interval
+	if interval <= 0 {
+		interval = DefaultReplayInterval
+	}
+	if interval > MaxReplayInterval {
+		return nil, fmt.Errorf("replay interval must be at most %s", MaxReplayInterval)
+	}
+	day := req.Day.UTC().Truncate(24 * time.Hour)
+	end := day.Add(24 * time.Hour)
+
+	candles, err := s.loadCandles(ctx, req.Exchange, req.Symbol, day.Add(-replayWarmupCandles*interval), end, interval)
+	if err != nil {
+		return nil, err
+	}
+	first := 0
+	for first < len(candles) && candles[first].start.Before(day) {
+		first++
+	}
+	if first == len(candles) {
+		return nil, fmt.Errorf("%w: %s %s on %s", ErrReplayNoData, req.Exchange, req.Symbol, day.Format(time.DateOnly))
+	}
+	recorded, err := s.loadRecorded(ctx, req.Exchange, req.Symbol, day, end)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SessionReplayReport{
+		Exchange:          req.Exchange,
+		Symbol:            req.Symbol,
+		Day:               day,
+		Interval:          interval.String(),
+		Candles:           len(candles) - first,
+		RecordedDecisions: len(recorded),
+		Steps:             []SessionReplayStep{},
+	}
+
+	var clock time.Time
+	trader := NewTraderAgent(s.config.Trader)
+	trader.now = func() time.Time { return clock }
+	portfolio := PortfolioState{TotalValue: s.config.Capital, AvailableCash: s.config.Capital}
+
+	closes := make([]float64, len(candles))
+	for i, candle := range candles {
+		closes[i] = candle.close
+	}
+	next := 0
+	for i := first; i < len(candles); i++ {
+		candle := candles[i]
+		clock = candle.start.Add(interval)
+		window := closes[max(0, i+1-replayWindowCandles) : i+1]
+		market := s.marketContext(req.Exchange, req.Symbol, candle, window)
+		decision, err := trader.MakeDecision(ctx, market, portfolio)
+		if err != nil {
+			return nil, fmt.Errorf("replay decision at %s: %w", candle.start.Format(time.RFC3339), err)
+		}
+
+		step := SessionReplayStep{
+			At:           candle.start,
+			Price:        candle.close,
+			Ticks:        candle.ticks,
+			Signals:      market.Signals,
+			Action:       decision.Action,
+			Confidence:   decision.Confidence,
+			SizePercent:  decision.SizePercent,
+			Reasoning:    decision.Reasoning,
+			WouldExecute: trader.ShouldExecute(decision),
+		}
+		// Decisions recorded between candles belong to the one before them
+		for next < len(recorded) && (i == len(candles)-1 || recorded[next].At.Before(candles[i+1].start)) {
+			step.Recorded = append(step.Recorded, recorded[next])
+			next++
+		}
+		step.Diverged = replayDiverged(&step)
+
+		report.Ticks += candle.ticks
+		if step.WouldExecute {
+			report.Entries++
+		}
+		if step.Diverged {
+			report.Divergences++
+		}
+		if req.At != nil && !req.At.Before(candle.start) && (i == len(candles)-1 || req.At.Before(candles[i+1].start)) {
+			focus := step
+			report.Focus = &focus
+		}
+		if step.WouldExecute || len(step.Recorded) > 0 {
+			report.Steps = append(report.Steps, step)
+		}
+	}
+	return report, nil
+}
+
+// loadCandles aggregates the recorded tickers of [from, to) into candles
+// closing at the last price of each interval.
+func (s *SessionReplayService) loadCandles(ctx context.Context, exchange, symbol string, from, to time.Time, interval time.Duration) ([]replayCandle, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT md.last_price::FLOAT8, COALESCE(md.volume_24h, 0)::FLOAT8, md.timestamp
+		FROM market_data md
+		JOIN trading_pairs tp ON md.trading_pair_id = tp.id
+		JOIN exchanges e ON md.exchange_id = e.id
+		WHERE tp.symbol = $1 AND e.ccxt_id = $2 AND md.timestamp >= $3 AND md.timestamp < $4
+			AND md.last_price > 0
+		ORDER BY md.timestamp ASC, md.id ASC`, symbol, exchange, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recorded market data: %w", err)
+	}
+	defer rows.Close()
+
+	var candles []replayCandle
+	for rows.Next() {
+		var price, volume float64
+		var at time.Time
+		if err := rows.Scan(&price, &volume, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan recorded market data: %w", err)
+		}
+		start := at.UTC().Truncate(interval)
+		if n := len(candles); n > 0 && candles[n-1].start.Equal(start) {
+			candles[n-1].close = price
+			candles[n-1].volume = volume
+			candles[n-1].ticks++
+			continue
+		}
+		candles = append(candles, replayCandle{start: start, close: price, volume: volume, ticks: 1})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recorded market data: %w", err)
+	}
+	return candles, nil
+}
+
+// loadRecorded returns the audited decisions of [from, to), oldest first.
+func (s *SessionReplayService) loadRecorded(ctx context.Context, exchange, symbol string, from, to time.Time) ([]SessionReplayEvent, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT id, source, action, confidence, reasoning, price,
+			COALESCE(order_details->>'status', ''), created_at
+		FROM decision_audits
+		WHERE exchange = $1 AND symbol = $2 AND created_at >= $3 AND created_at < $4
+		ORDER BY created_at ASC, id ASC`, exchange, symbol, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recorded decisions: %w", err)
+	}
+	defer rows.Close()
+
+	var events []SessionReplayEvent
+	for rows.Next() {
+		var e SessionReplayEvent
+		if err := rows.Scan(&e.ID, &e.Source, &e.Action, &e.Confidence, &e.Reasoning, &e.Price, &e.OrderStatus, &e.At); err != nil {
+			return nil, fmt.Errorf("failed to scan recorded decision: %w", err)
+		}
+		e.At = e.At.UTC()
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recorded decisions: %w", err)
+	}
+	return events, nil
+}
+
+// marketContext builds what the trader agent sees at the close of a candle.
+func (s *SessionReplayService) marketContext(exchange, symbol string, candle replayCandle, window []float64) MarketContext {
+	market := MarketContext{
+		Symbol:       symbol,
+		CurrentPrice: candle.close,
+		Volatility:   replayVolatility(window),
+		Trend:        replayTrend(window),
+		Liquidity:    candle.volume * candle.close,
+		Volume24h:    candle.volume,
+		Signals:      []TradingSignal{},
+	}
+	if s.signals == nil {
+		return market
+	}
+	for _, signal := range s.signals.EvaluateTechnicalSignals(symbol, exchange, window) {
+		direction := "bullish"
+		if signal.Action == "sell" {
+			direction = "bearish"
+		}
+		description, _ := signal.Metadata["description"].(string)
+		market.Signals = append(market.Signals, TradingSignal{
+			Name:        strings.Join(signal.Indicators, "+"),
+			Value:       signal.Confidence.InexactFloat64(),
+			Weight:      1,
+			Direction:   direction,
+			Description: description,
+		})
+	}
+	return market
+}
+
+// replayVolatility is the standard deviation of the window's returns.
+func replayVolatility(closes []float64) float64 {
+	if len(closes) < 3 {
+		return 0
+	}
+	returns := make([]float64, 0, len(closes)-1)
+	mean := 0.0
+	for i := 1; i < len(closes); i++ {
+		r := closes[i]/closes[i-1] - 1
+		returns = append(returns, r)
+		mean += r
+	}
+	mean /= float64(len(returns))
+	variance := 0.0
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	return math.Sqrt(variance / float64(len(returns)-1))
+}
+
+// replayTrend compares the latest close with the start of the window.
+func replayTrend(closes []float64) string {
+	if len(closes) < 2 {
+		return "neutral"
+	}
+	change := closes[len(closes)-1]/closes[0] - 1
+	switch {
+	case change > 0.005:
+		return "bullish"
+	case change < -0.005:
+		return "bearish"
+	default:
+		return "neutral"
+	}
+}
+
+// replayDiverged reports whether a recorded buy, sell or hold disagrees with
+// what the replay would have done.
+func replayDiverged(step *SessionReplayStep) bool {
+	replayed := "hold"
+	if step.WouldExecute {
+		switch step.Action {
+		case ActionOpenLong, ActionAddToPos, ActionCloseShort:
+			replayed = "buy"
+		case ActionOpenShort, ActionCloseLong, ActionReducePos:
+			replayed = "sell"
+		}
+	}
+	for _, event := range step.Recorded {
+		action := strings.ToLower(event.Action)
+		if (action == "buy" || action == "sell" || action == "hold") && action != replayed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubReplaySignals signals a buy whenever the latest close reaches 105.
+type stubReplaySignals struct {
+	windows []int
+}
+
+func (s *stubReplaySignals) EvaluateTechnicalSignals(symbol, _ string, closes []float64) []*AggregatedSignal {
+	s.windows = append(s.windows, len(closes))
+	if closes[len(closes)-1] < 105 {
+		return nil
+	}
+	return []*AggregatedSignal{{
+		Symbol:     symbol,
+		Action:     "buy",
+		Confidence: decimal.NewFromFloat(0.9),
+		Indicators: []string{"rsi_oversold"},
+		Metadata:   map[string]interface{}{"description": "RSI oversold recovery"},
+	}}
+}
+
+var replayDay = time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+
+func expectSessionReplay(mockPool pgxmock.PgxPoolIface) {
+	at := func(clock string) time.Time {
+		d, _ := time.ParseDuration(clock)
+		return replayDay.Add(d)
+	}
+	mockPool.ExpectQuery("FROM market_data").
+		WithArgs("BTC/USDT", "binance", replayDay.Add(-50*time.Minute), replayDay.Add(24*time.Hour)).
+		WillReturnRows(pgxmock.NewRows([]string{"last_price", "volume", "timestamp"}).
+			AddRow(99.0, 1000.0, at("-50s")).
+			AddRow(100.0, 1000.0, at("5s")).
+			AddRow(101.0, 1000.0, at("40s")).
+			AddRow(105.0, 1000.0, at("1m10s")).
+			AddRow(106.0, 1000.0, at("2m30s")).
+			AddRow(107.0, 1000.0, at("7m")))
+	mockPool.ExpectQuery("FROM decision_audits").
+		WithArgs("binance", "BTC/USDT", replayDay, replayDay.Add(24*time.Hour)).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "source", "action", "confidence", "reasoning", "price", "status", "created_at"}).
+			AddRow("dec_1", DecisionSourceAIScalping, "buy", 0.8, "breakout", 105.0, DecisionOrderPlaced, at("1m30s")).
+			AddRow("dec_2", DecisionSourceAIScalping, "sell", 0.75, "reversal", 106.0, DecisionOrderPlaced, at("2m45s")))
+}
+
+func TestSessionReplayService_Replay(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	signals := &stubReplaySignals{}
+	svc := NewSessionReplayService(database.NewMockDBPool(mockPool), signals, DefaultSessionReplayConfig())
+	focusAt := replayDay.Add(2*time.Minute + 59*time.Second)
+	req := SessionReplayRequest{Exchange: "binance", Symbol: "BTC/USDT", Day: replayDay.Add(15 * time.Hour), At: &focusAt}
+
+	expectSessionReplay(mockPool)
+	report, err := svc.Replay(t.Context(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, replayDay, report.Day)
+	assert.Equal(t, "1m0s", report.Interval)
+	assert.Equal(t, 4, report.Candles, "the warmup candle is not replayed")
+	assert.Equal(t, 5, report.Ticks)
+	assert.Equal(t, 2, signals.windows[0], "the first window includes the warmup candle")
+	assert.Equal(t, 2, report.Entries)
+	assert.Equal(t, 2, report.RecordedDecisions)
+	assert.Equal(t, 1, report.Divergences)
+
+	require.Len(t, report.Steps, 3)
+	assert.Equal(t, replayDay.Add(time.Minute), report.Steps[0].At)
+	assert.Equal(t, ActionOpenLong, report.Steps[0].Action)
+	assert.True(t, report.Steps[0].WouldExecute)
+	assert.Equal(t, "dec_1", report.Steps[0].Recorded[0].ID)
+	assert.False(t, report.Steps[0].Diverged)
+
+	// The next buy signal falls in the trader's cooldown on the replayed clock
+	assert.Equal(t, ActionWait, report.Steps[1].Action)
+	assert.Equal(t, "dec_2", report.Steps[1].Recorded[0].ID)
+	assert.True(t, report.Steps[1].Diverged)
+	assert.Equal(t, replayDay.Add(7*time.Minute), report.Steps[2].At)
+	assert.True(t, report.Steps[2].WouldExecute)
+
+	require.NotNil(t, report.Focus)
+	assert.Equal(t, replayDay.Add(2*time.Minute), report.Focus.At)
+	assert.Equal(t, "In cooldown period", report.Focus.Reasoning)
+	assert.Equal(t, "rsi_oversold", report.Focus.Signals[0].Name)
+
+	// Replaying the same day gives the same result
+	expectSessionReplay(mockPool)
+	again, err := svc.Replay(t.Context(), req)
+	require.NoError(t, err)
+	assert.Equal(t, report, again)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestSessionReplayService_ReplayWithoutData(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	svc := NewSessionReplayService(database.NewMockDBPool(mockPool), &stubReplaySignals{}, SessionReplayConfig{})
+
+	mockPool.ExpectQuery("FROM market_data").WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"last_price", "volume", "timestamp"}).AddRow(99.0, 1000.0, replayDay.Add(-time.Minute)))
+	_, err = svc.Replay(t.Context(), SessionReplayRequest{Exchange: "binance", Symbol: "BTC/USDT", Day: replayDay})
+	assert.ErrorIs(t, err, ErrReplayNoData)
+
+	_, err = svc.Replay(t.Context(), SessionReplayRequest{Exchange: "binance", Symbol: "BTC/USDT", Day: replayDay, Interval: 2 * time.Hour})
+	assert.Error(t, err)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
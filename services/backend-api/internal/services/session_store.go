@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultLoginCodeTTL = 5 * time.Minute
+	// Wrong guesses and issued codes are counted per chat over a window that
+	// outlives a single code, so requesting a new code does not buy more guesses.
+	loginCodeWindow              = 15 * time.Minute
+	maxLoginCodeAttempts         = 5
+	maxLoginCodesPerWindow       = 3
+	sessionRevocationKeyBase     = "auth:revoked"
+	sessionRefreshKeyBase        = "auth:refresh"
+	sessionLoginCodeKeyBase      = "auth:login_code"
+	sessionLoginAttemptsKeyBase  = "auth:login_attempts"
+	sessionLoginCodeIssueKeyBase = "auth:login_issued"
+)
+
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found or already used")
+	ErrLoginCodeInvalid     = errors.New("invalid or expired login code")
+	ErrLoginCodeThrottled   = errors.New("too many login codes requested, try again later")
+)
+
+// SessionStore keeps session state for JWT auth in Redis: the token
+// revocation list, single-use refresh tokens, and Telegram login codes.
+type SessionStore struct {
+	redis        *redis.Client
+	loginCodeTTL time.Duration
+}
+
+// NewSessionStore creates a session store.
+//
+// Parameters:
+//
+//	client: Redis client.
+//
+// Returns:
+//
+//	*SessionStore: Initialized store.
+func NewSessionStore(client *redis.Client) *SessionStore {
+	return &SessionStore{
+		redis:        client,
+		loginCodeTTL: defaultLoginCodeTTL,
+	}
+}
+
+func revokedTokenKey(tokenID string) string {
+	return fmt.Sprintf("%s:%s", sessionRevocationKeyBase, tokenID)
+}
+
+func refreshTokenKey(tokenID string) string {
+	return fmt.Sprintf("%s:%s", sessionRefreshKeyBase, tokenID)
+}
+
+func loginCodeKey(chatID string) string {
+	return fmt.Sprintf("%s:%s", sessionLoginCodeKeyBase, chatID)
+}
+
+func loginAttemptsKey(chatID string) string {
+	return fmt.Sprintf("%s:%s", sessionLoginAttemptsKeyBase, chatID)
+}
+
+func loginCodeIssueKey(chatID string) string {
+	return fmt.Sprintf("%s:%s", sessionLoginCodeIssueKeyBase, chatID)
+}
+
+// RevokeToken adds a token ID to the revocation list until the token would have expired.
+func (s *SessionStore) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	if err := s.redis.Set(ctx, revokedTokenKey(tokenID), "1", ttl).Err(); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	return nil
+}
+
+// IsTokenRevoked reports whether a token ID is revoked. Redis errors are treated as revoked.
+func (s *SessionStore) IsTokenRevoked(ctx context.Context, tokenID string) bool {
+	n, err := s.redis.Exists(ctx, revokedTokenKey(tokenID)).Result()
+	return err != nil || n > 0
+}
+
+// StoreRefreshToken records an issued refresh token so it can be used exactly once.
+func (s *SessionStore) StoreRefreshToken(ctx context.Context, tokenID, userID string, expiresAt time.Time) error {
+	if err := s.redis.Set(ctx, refreshTokenKey(tokenID), userID, time.Until(expiresAt)).Err(); err != nil {
+		return fmt.Errorf("failed to store refresh token: %w", err)
+	}
+	return nil
+}
+
+// ConsumeRefreshToken atomically removes a refresh token and returns its user ID.
+func (s *SessionStore) ConsumeRefreshToken(ctx context.Context, tokenID string) (string, error) {
+	userID, err := s.redis.GetDel(ctx, refreshTokenKey(tokenID)).Result()
+	if err == redis.Nil {
+		return "", ErrRefreshTokenNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to consume refresh token: %w", err)
+	}
+	return userID, nil
+}
+
+// AllowLoginCode counts a login code request for a chat and rejects it with
+// ErrLoginCodeThrottled once the chat has asked for too many codes within
+// the window. Call it whether or not the chat is linked, so the throttle
+// does not reveal which chats have accounts.
+func (s *SessionStore) AllowLoginCode(ctx context.Context, chatID string) error {
+	key := loginCodeIssueKey(chatID)
+	pipe := s.redis.TxPipeline()
+	issued := pipe.Incr(ctx, key)
+	pipe.ExpireNX(ctx, key, loginCodeWindow)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to count login code request: %w", err)
+	}
+	if issued.Val() > maxLoginCodesPerWindow {
+		return ErrLoginCodeThrottled
+	}
+	return nil
+}
+
+// CreateLoginCode stores a hashed one-time login code for a Telegram chat.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	chatID: Telegram chat ID the code will be sent to.
+//
+// Returns:
+//
+//	string: The six-digit code.
+//	error: Error if the code cannot be stored.
+func (s *SessionStore) CreateLoginCode(ctx context.Context, chatID string) (string, error) {
+	code, err := generateConfirmationCode()
+	if err != nil {
+		return "", err
+	}
+	pipe := s.redis.TxPipeline()
+	pipe.Del(ctx, loginCodeKey(chatID))
+	pipe.HSet(ctx, loginCodeKey(chatID), "hash", hashLoginCode(chatID, code))
+	pipe.Expire(ctx, loginCodeKey(chatID), s.loginCodeTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", fmt.Errorf("failed to store login code: %w", err)
+	}
+	return code, nil
+}
+
+// VerifyLoginCode checks and consumes a login code. Every attempt is counted
+// before the code is compared, so concurrent guesses cannot all slip in
+// under the limit. Too many attempts within the window invalidate the
+// chat's codes, including ones issued later.
+func (s *SessionStore) VerifyLoginCode(ctx context.Context, chatID, code string) error {
+	key := loginCodeKey(chatID)
+	attemptsKey := loginAttemptsKey(chatID)
+	pipe := s.redis.TxPipeline()
+	attempts := pipe.Incr(ctx, attemptsKey)
+	pipe.ExpireNX(ctx, attemptsKey, loginCodeWindow)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to count login attempt: %w", err)
+	}
+	if attempts.Val() > maxLoginCodeAttempts {
+		_ = s.redis.Del(ctx, key).Err()
+		return ErrLoginCodeInvalid
+	}
+
+	stored, err := s.redis.HGet(ctx, key, "hash").Result()
+	if err == redis.Nil {
+		return ErrLoginCodeInvalid
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load login code: %w", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(stored), []byte(hashLoginCode(chatID, code))) != 1 {
+		if attempts.Val() >= maxLoginCodeAttempts {
+			_ = s.redis.Del(ctx, key).Err()
+		}
+		return ErrLoginCodeInvalid
+	}
+
+	if deleted, err := s.redis.Del(ctx, key).Result(); err != nil || deleted == 0 {
+		// Lost a race with a concurrent verification.
+		return ErrLoginCodeInvalid
+	}
+	_ = s.redis.Del(ctx, attemptsKey).Err()
+	return nil
+}
+
+func hashLoginCode(chatID, code string) string {
+	sum := sha256.Sum256([]byte(chatID + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSessionStore(t *testing.T) (*SessionStore, *miniredis.Miniredis) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewSessionStore(client), s
+}
+
+func TestSessionStore_Revocation(t *testing.T) {
+	store, mr := newTestSessionStore(t)
+
+	assert.False(t, store.IsTokenRevoked(t.Context(), "jti-1"))
+	require.NoError(t, store.RevokeToken(t.Context(), "jti-1", time.Now().Add(time.Minute)))
+	assert.True(t, store.IsTokenRevoked(t.Context(), "jti-1"))
+
+	mr.FastForward(2 * time.Minute)
+	assert.False(t, store.IsTokenRevoked(t.Context(), "jti-1"), "entry lives only until the token expires")
+
+	require.NoError(t, store.RevokeToken(t.Context(), "jti-2", time.Now().Add(-time.Minute)))
+	assert.False(t, store.IsTokenRevoked(t.Context(), "jti-2"))
+}
+
+func TestSessionStore_RefreshTokensAreSingleUse(t *testing.T) {
+	store, _ := newTestSessionStore(t)
+
+	require.NoError(t, store.StoreRefreshToken(t.Context(), "r1", "user-1", time.Now().Add(time.Hour)))
+	userID, err := store.ConsumeRefreshToken(t.Context(), "r1")
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", userID)
+
+	_, err = store.ConsumeRefreshToken(t.Context(), "r1")
+	assert.ErrorIs(t, err, ErrRefreshTokenNotFound)
+}
+
+func TestSessionStore_LoginCodes(t *testing.T) {
+	store, mr := newTestSessionStore(t)
+
+	code, err := store.CreateLoginCode(t.Context(), "42")
+	require.NoError(t, err)
+	assert.Len(t, code, 6)
+
+	assert.ErrorIs(t, store.VerifyLoginCode(t.Context(), "43", code), ErrLoginCodeInvalid)
+	require.NoError(t, store.VerifyLoginCode(t.Context(), "42", code))
+	assert.ErrorIs(t, store.VerifyLoginCode(t.Context(), "42", code), ErrLoginCodeInvalid, "codes are single-use")
+
+	code, err = store.CreateLoginCode(t.Context(), "42")
+	require.NoError(t, err)
+	mr.FastForward(defaultLoginCodeTTL + time.Second)
+	assert.ErrorIs(t, store.VerifyLoginCode(t.Context(), "42", code), ErrLoginCodeInvalid)
+}
+
+func TestSessionStore_LoginCodeAttemptLimit(t *testing.T) {
+	store, _ := newTestSessionStore(t)
+
+	code, err := store.CreateLoginCode(t.Context(), "42")
+	require.NoError(t, err)
+	for i := 0; i < maxLoginCodeAttempts; i++ {
+		assert.ErrorIs(t, store.VerifyLoginCode(t.Context(), "42", "bad"), ErrLoginCodeInvalid)
+	}
+	assert.ErrorIs(t, store.VerifyLoginCode(t.Context(), "42", code), ErrLoginCodeInvalid)
+}
+
+func TestSessionStore_LoginCodeAttemptsSurviveReissue(t *testing.T) {
+	store, mr := newTestSessionStore(t)
+
+	_, err := store.CreateLoginCode(t.Context(), "42")
+	require.NoError(t, err)
+	for i := 0; i < maxLoginCodeAttempts-1; i++ {
+		assert.ErrorIs(t, store.VerifyLoginCode(t.Context(), "42", "bad"), ErrLoginCodeInvalid)
+	}
+	code, err := store.CreateLoginCode(t.Context(), "42")
+	require.NoError(t, err)
+	assert.ErrorIs(t, store.VerifyLoginCode(t.Context(), "42", "bad"), ErrLoginCodeInvalid)
+	assert.ErrorIs(t, store.VerifyLoginCode(t.Context(), "42", code), ErrLoginCodeInvalid, "a new code does not reset the attempts")
+
+	mr.FastForward(loginCodeWindow + time.Second)
+	code, err = store.CreateLoginCode(t.Context(), "42")
+	require.NoError(t, err)
+	require.NoError(t, store.VerifyLoginCode(t.Context(), "42", code))
+}
+
+func TestSessionStore_AllowLoginCode(t *testing.T) {
+	store, mr := newTestSessionStore(t)
+
+	for i := 0; i < maxLoginCodesPerWindow; i++ {
+		require.NoError(t, store.AllowLoginCode(t.Context(), "42"))
+	}
+	assert.ErrorIs(t, store.AllowLoginCode(t.Context(), "42"), ErrLoginCodeThrottled)
+	assert.NoError(t, store.AllowLoginCode(t.Context(), "43"), "the throttle is per chat")
+
+	mr.FastForward(loginCodeWindow + time.Second)
+	assert.NoError(t, store.AllowLoginCode(t.Context(), "42"))
+}
+
+func TestSessionStore_LoginCodeConcurrentGuesses(t *testing.T) {
+	store, mr := newTestSessionStore(t)
+
+	code, err := store.CreateLoginCode(t.Context(), "42")
+	require.NoError(t, err)
+
+	const guesses = 50
+	var wg sync.WaitGroup
+	var accepted atomic.Int32
+	for i := 0; i < guesses; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			guess := fmt.Sprintf("%06d", i)
+			if guess == code {
+				guess = "bad"
+			}
+			if store.VerifyLoginCode(t.Context(), "42", guess) == nil {
+				accepted.Add(1)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Zero(t, accepted.Load())
+	attempts, err := mr.Get(loginAttemptsKey("42"))
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprint(guesses), attempts, "every concurrent guess is counted")
+	assert.False(t, mr.Exists(loginCodeKey("42")), "the code is deleted once the chat locks")
+	assert.ErrorIs(t, store.VerifyLoginCode(t.Context(), "42", code), ErrLoginCodeInvalid)
+
+	code, err = store.CreateLoginCode(t.Context(), "43")
+	require.NoError(t, err)
+	for i := 0; i < maxLoginCodeAttempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if store.VerifyLoginCode(t.Context(), "43", code) == nil {
+				accepted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), accepted.Load(), "a code is accepted once")
+}
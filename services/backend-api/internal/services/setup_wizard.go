@@ -0,0 +1,378 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const setupStateKey = "setup:state"
+
+// SetupStepName identifies a step of the first-run setup wizard.
+type SetupStepName string
+
+// Setup wizard steps, in the order the wizard walks through them.
+const (
+	SetupStepExchangeKeys SetupStepName = "exchange_keys"
+	SetupStepTelegram     SetupStepName = "telegram_binding"
+	SetupStepAIProvider   SetupStepName = "ai_provider"
+	SetupStepRiskLimits   SetupStepName = "risk_limits"
+	SetupStepDryRun       SetupStepName = "dry_run"
+)
+
+// SetupStepStatus is how far a setup step has got.
+type SetupStepStatus string
+
+const (
+	SetupStatusPending SetupStepStatus = "pending"
+	SetupStatusDone    SetupStepStatus = "done"
+	SetupStatusSkipped SetupStepStatus = "skipped"
+)
+
+var (
+	// ErrSetupUnknownStep is returned for a step the wizard does not have.
+	ErrSetupUnknownStep = errors.New("unknown setup step")
+	// ErrSetupInvalidStatus is returned when a step is set to an unknown
+	// status, or the dry run is marked done without passing verification.
+	ErrSetupInvalidStatus = errors.New("invalid setup step status")
+)
+
+// setupStepInfo describes a step and how to complete it.
+type setupStepInfo struct {
+	name  SetupStepName
+	title string
+	hint  string
+}
+
+var setupSteps = []setupStepInfo{
+	{SetupStepExchangeKeys, "Exchange API keys", "run neuratrade setup, or /connect_exchange in Telegram"},
+	{SetupStepTelegram, "Telegram binding", "send /start to the bot, then: neuratrade operator bind --auth-code <code> --chat-id <id>"},
+	{SetupStepAIProvider, "AI provider", "run neuratrade setup, then restart the backend"},
+	{SetupStepRiskLimits, "Risk limits", "neuratrade autonomous profile select conservative --chat-id <id>"},
+	{SetupStepDryRun, "Dry-run verification", "neuratrade setup --verify"},
+}
+
+// SetupStep is the progress of one wizard step.
+type SetupStep struct {
+	Name   SetupStepName   `json:"name"`
+	Title  string          `json:"title"`
+	Status SetupStepStatus `json:"status"`
+	// Detected is true when the step was found completed outside the
+	// wizard, e.g. exchange keys added through Telegram.
+	Detected bool   `json:"detected"`
+	Detail   string `json:"detail,omitempty"`
+	// Hint is how to complete the step by hand.
+	Hint      string     `json:"hint"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// SetupState is the progress of the first-run setup wizard.
+type SetupState struct {
+	Steps []SetupStep `json:"steps"`
+	// Completed counts done and skipped steps.
+	Completed int  `json:"completed"`
+	Total     int  `json:"total"`
+	Complete  bool `json:"complete"`
+	// Next is the first step still pending; empty once setup is complete.
+	Next SetupStepName `json:"next,omitempty"`
+}
+
+// SetupProbeResult is the outcome of one dry-run verification check.
+type SetupProbeResult struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
+}
+
+// SetupVerification is the result of a dry-run verification. Nothing is
+// traded: it checks connectivity and that every required step is done.
+type SetupVerification struct {
+	Passed bool               `json:"passed"`
+	Checks []SetupProbeResult `json:"checks"`
+	State  *SetupState        `json:"state"`
+}
+
+// SetupCheck reports whether a step was completed outside the wizard, with
+// a short description of what was found.
+type SetupCheck func(ctx context.Context) (done bool, detail string, err error)
+
+// SetupProbe checks one dependency during the dry-run verification.
+type SetupProbe func(ctx context.Context) error
+
+// SetupConfig describes the configuration the wizard can see.
+type SetupConfig struct {
+	// AIProvider is the configured AI provider name.
+	AIProvider string
+	// AIKeyConfigured is true when the AI provider has an API key.
+	AIKeyConfigured bool
+}
+
+type setupRecord struct {
+	Status    SetupStepStatus `json:"status"`
+	Detail    string          `json:"detail,omitempty"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+type namedSetupProbe struct {
+	name  string
+	probe SetupProbe
+}
+
+// SetupService tracks the first-run setup wizard in Redis. Steps count as
+// done when the wizard recorded them or when their completion is detected.
+type SetupService struct {
+	redis  *redis.Client
+	checks map[SetupStepName]SetupCheck
+	probes []namedSetupProbe
+	now    func() time.Time
+}
+
+// NewSetupService creates the setup wizard service.
+//
+// Parameters:
+//
+//	client: Redis client holding the wizard state.
+//	db: Database used to detect exchange keys and Telegram bindings (may be nil).
+//	config: AI provider configuration.
+//
+// Returns:
+//
+//	*SetupService: Initialized service.
+func NewSetupService(client *redis.Client, db DBPool, config SetupConfig) *SetupService {
+	s := &SetupService{
+		redis:  client,
+		checks: make(map[SetupStepName]SetupCheck),
+		now:    time.Now,
+	}
+	if !isNilDBPool(db) {
+		s.checks[SetupStepExchangeKeys] = countingSetupCheck(db, "%d exchange(s) connected",
+			"SELECT COUNT(*) FROM telegram_operator_wallets WHERE wallet_type = 'exchange' AND status = 'connected'",
+			"SELECT COUNT(*) FROM exchange_api_keys")
+		s.checks[SetupStepTelegram] = countingSetupCheck(db, "%d Telegram chat(s) bound",
+			"SELECT COUNT(*) FROM users WHERE telegram_chat_id IS NOT NULL AND telegram_chat_id <> ''")
+	}
+	if config.AIKeyConfigured {
+		provider := config.AIProvider
+		if provider == "" {
+			provider = "default"
+		}
+		s.checks[SetupStepAIProvider] = func(context.Context) (bool, string, error) {
+			return true, provider + " provider configured", nil
+		}
+	}
+	return s
+}
+
+// countingSetupCheck detects a step as done when any count query is
+// positive. Queries against tables that do not exist yet count as zero.
+func countingSetupCheck(db DBPool, detail string, queries ...string) SetupCheck {
+	return func(ctx context.Context) (bool, string, error) {
+		for _, query := range queries {
+			var count int
+			if err := db.QueryRow(ctx, query).Scan(&count); err == nil && count > 0 {
+				return true, fmt.Sprintf(detail, count), nil
+			}
+		}
+		return false, "", nil
+	}
+}
+
+// SetCheck replaces how a step's completion is detected.
+func (s *SetupService) SetCheck(step SetupStepName, check SetupCheck) {
+	s.checks[step] = check
+}
+
+// AddProbe adds a dependency checked by the dry-run verification.
+func (s *SetupService) AddProbe(name string, probe SetupProbe) {
+	s.probes = append(s.probes, namedSetupProbe{name: name, probe: probe})
+}
+
+// State returns the wizard progress.
+//
+// Parameters:
+//
+//	ctx: Context.
+//
+// Returns:
+//
+//	*SetupState: Every step with its status.
+//	error: Error if the state cannot be read.
+func (s *SetupService) State(ctx context.Context) (*SetupState, error) {
+	records, err := s.records(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &SetupState{Steps: make([]SetupStep, 0, len(setupSteps)), Total: len(setupSteps)}
+	for _, info := range setupSteps {
+		step := SetupStep{Name: info.name, Title: info.title, Status: SetupStatusPending, Hint: info.hint}
+		if record, ok := records[info.name]; ok {
+			step.Status = record.Status
+			step.Detail = record.Detail
+			updatedAt := record.UpdatedAt
+			step.UpdatedAt = &updatedAt
+		}
+		if step.Status == SetupStatusPending {
+			if check, ok := s.checks[info.name]; ok {
+				// A failed check leaves the step pending for the wizard
+				if done, detail, err := check(ctx); err == nil && done {
+					step.Status = SetupStatusDone
+					step.Detected = true
+					step.Detail = detail
+				}
+			}
+		}
+		if step.Status == SetupStatusPending {
+			if state.Next == "" {
+				state.Next = step.Name
+			}
+		} else {
+			state.Completed++
+		}
+		state.Steps = append(state.Steps, step)
+	}
+	state.Complete = state.Completed == state.Total
+	return state, nil
+}
+
+// UpdateStep records a step as done, skipped or pending again. The dry run
+// is only marked done by Verify.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	step: Step name.
+//	status: New status.
+//	detail: What was configured, without secrets.
+//
+// Returns:
+//
+//	*SetupState: The updated wizard progress.
+//	error: ErrSetupUnknownStep, ErrSetupInvalidStatus or a storage error.
+func (s *SetupService) UpdateStep(ctx context.Context, step SetupStepName, status SetupStepStatus, detail string) (*SetupState, error) {
+	if !knownSetupStep(step) {
+		return nil, fmt.Errorf("%w: %s", ErrSetupUnknownStep, step)
+	}
+	switch status {
+	case SetupStatusPending, SetupStatusSkipped:
+	case SetupStatusDone:
+		if step == SetupStepDryRun {
+			return nil, fmt.Errorf("%w: the dry run is done once verification passes", ErrSetupInvalidStatus)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrSetupInvalidStatus, status)
+	}
+	if err := s.save(ctx, step, setupRecord{Status: status, Detail: strings.TrimSpace(detail)}); err != nil {
+		return nil, err
+	}
+	return s.State(ctx)
+}
+
+// Verify runs the dry-run verification: every probe must pass and every
+// other step must be done or skipped. The dry-run step is recorded done
+// when it passes and pending otherwise.
+//
+// Parameters:
+//
+//	ctx: Context.
+//
+// Returns:
+//
+//	*SetupVerification: Each check with the resulting wizard progress.
+//	error: Error if the state cannot be read or saved.
+func (s *SetupService) Verify(ctx context.Context) (*SetupVerification, error) {
+	verification := &SetupVerification{Passed: true}
+	for _, probe := range s.probes {
+		result := SetupProbeResult{Name: probe.name, Passed: true}
+		if err := probe.probe(ctx); err != nil {
+			result.Passed = false
+			result.Message = err.Error()
+			verification.Passed = false
+		}
+		verification.Checks = append(verification.Checks, result)
+	}
+
+	state, err := s.State(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, step := range state.Steps {
+		if step.Name == SetupStepDryRun {
+			continue
+		}
+		result := SetupProbeResult{Name: string(step.Name), Passed: step.Status != SetupStatusPending}
+		switch {
+		case !result.Passed:
+			result.Message = "not configured: " + step.Hint
+			verification.Passed = false
+		case step.Status == SetupStatusSkipped:
+			result.Message = "skipped"
+		default:
+			result.Message = step.Detail
+		}
+		verification.Checks = append(verification.Checks, result)
+	}
+
+	record := setupRecord{Status: SetupStatusPending, Detail: "verification failed"}
+	if verification.Passed {
+		record = setupRecord{Status: SetupStatusDone, Detail: fmt.Sprintf("%d checks passed", len(verification.Checks))}
+	}
+	if err := s.save(ctx, SetupStepDryRun, record); err != nil {
+		return nil, err
+	}
+	if verification.State, err = s.State(ctx); err != nil {
+		return nil, err
+	}
+	return verification, nil
+}
+
+// Reset forgets the recorded progress so the wizard starts over. Detected
+// steps still count as done.
+func (s *SetupService) Reset(ctx context.Context) error {
+	if err := s.redis.Del(ctx, setupStateKey).Err(); err != nil {
+		return fmt.Errorf("failed to reset setup state: %w", err)
+	}
+	return nil
+}
+
+func (s *SetupService) records(ctx context.Context) (map[SetupStepName]setupRecord, error) {
+	raw, err := s.redis.HGetAll(ctx, setupStateKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load setup state: %w", err)
+	}
+	records := make(map[SetupStepName]setupRecord, len(raw))
+	for step, value := range raw {
+		var record setupRecord
+		if err := json.Unmarshal([]byte(value), &record); err != nil {
+			return nil, fmt.Errorf("failed to decode setup step %s: %w", step, err)
+		}
+		records[SetupStepName(step)] = record
+	}
+	return records, nil
+}
+
+func (s *SetupService) save(ctx context.Context, step SetupStepName, record setupRecord) error {
+	record.UpdatedAt = s.now().UTC()
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode setup step %s: %w", step, err)
+	}
+	if err := s.redis.HSet(ctx, setupStateKey, string(step), raw).Err(); err != nil {
+		return fmt.Errorf("failed to save setup step %s: %w", step, err)
+	}
+	return nil
+}
+
+func knownSetupStep(step SetupStepName) bool {
+	for _, info := range setupSteps {
+		if info.name == step {
+			return true
+		}
+	}
+	return false
+}
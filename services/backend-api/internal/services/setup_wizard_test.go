@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSetupService(t *testing.T, db DBPool, config SetupConfig) *SetupService {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewSetupService(client, db, config)
+}
+
+func TestSetupService_TracksSteps(t *testing.T) {
+	service := newTestSetupService(t, nil, SetupConfig{AIProvider: "openai", AIKeyConfigured: true})
+	ctx := context.Background()
+
+	state, err := service.State(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 5, state.Total)
+	assert.Equal(t, SetupStepExchangeKeys, state.Next)
+	ai := state.Steps[2]
+	assert.Equal(t, SetupStatusDone, ai.Status)
+	assert.True(t, ai.Detected)
+	assert.Equal(t, "openai provider configured", ai.Detail)
+
+	state, err = service.UpdateStep(ctx, SetupStepExchangeKeys, SetupStatusDone, "binance keys saved")
+	require.NoError(t, err)
+	assert.Equal(t, SetupStepTelegram, state.Next)
+	assert.Equal(t, "binance keys saved", state.Steps[0].Detail)
+	assert.NotNil(t, state.Steps[0].UpdatedAt)
+
+	_, err = service.UpdateStep(ctx, "wallets", SetupStatusDone, "")
+	assert.True(t, errors.Is(err, ErrSetupUnknownStep))
+	_, err = service.UpdateStep(ctx, SetupStepTelegram, "finished", "")
+	assert.True(t, errors.Is(err, ErrSetupInvalidStatus))
+	_, err = service.UpdateStep(ctx, SetupStepDryRun, SetupStatusDone, "")
+	assert.True(t, errors.Is(err, ErrSetupInvalidStatus))
+
+	require.NoError(t, service.Reset(ctx))
+	state, err = service.State(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, SetupStatusPending, state.Steps[0].Status)
+	assert.Equal(t, 1, state.Completed)
+}
+
+func TestSetupService_Verify(t *testing.T) {
+	service := newTestSetupService(t, nil, SetupConfig{})
+	ctx := context.Background()
+	healthy := true
+	service.AddProbe("ccxt", func(context.Context) error {
+		if !healthy {
+			return errors.New("ccxt unreachable")
+		}
+		return nil
+	})
+	service.SetCheck(SetupStepRiskLimits, func(context.Context) (bool, string, error) {
+		return true, "1 chat(s) with an operating profile", nil
+	})
+
+	verification, err := service.Verify(ctx)
+	require.NoError(t, err)
+	assert.False(t, verification.Passed)
+	assert.Equal(t, SetupStatusPending, verification.State.Steps[4].Status)
+
+	for _, step := range []SetupStepName{SetupStepExchangeKeys, SetupStepTelegram} {
+		_, err = service.UpdateStep(ctx, step, SetupStatusDone, "")
+		require.NoError(t, err)
+	}
+	_, err = service.UpdateStep(ctx, SetupStepAIProvider, SetupStatusSkipped, "")
+	require.NoError(t, err)
+
+	healthy = false
+	verification, err = service.Verify(ctx)
+	require.NoError(t, err)
+	assert.False(t, verification.Passed)
+	assert.Equal(t, SetupProbeResult{Name: "ccxt", Message: "ccxt unreachable"}, verification.Checks[0])
+
+	healthy = true
+	verification, err = service.Verify(ctx)
+	require.NoError(t, err)
+	assert.True(t, verification.Passed)
+	assert.True(t, verification.State.Complete)
+	assert.Equal(t, SetupStatusDone, verification.State.Steps[4].Status)
+	assert.Empty(t, verification.State.Next)
+}
+
+func TestSetupService_DetectsStepsFromDatabase(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	service := newTestSetupService(t, database.NewMockDBPool(mockPool), SetupConfig{})
+
+	mockPool.ExpectQuery("FROM telegram_operator_wallets").WillReturnError(errors.New("no such table"))
+	mockPool.ExpectQuery("FROM exchange_api_keys").WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(2))
+	mockPool.ExpectQuery("FROM users").WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(0))
+
+	state, err := service.State(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, SetupStatusDone, state.Steps[0].Status)
+	assert.Equal(t, "2 exchange(s) connected", state.Steps[0].Detail)
+	assert.Equal(t, SetupStatusPending, state.Steps[1].Status)
+	assert.Equal(t, SetupStepTelegram, state.Next)
+	require.NoError(t, mockPool.ExpectationsWereMet())
+}
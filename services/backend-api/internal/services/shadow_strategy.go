@@ -0,0 +1,347 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+const defaultShadowMaxComparisons = 500
+
+// ScalpingStrategy makes one trading decision for a portfolio. It is
+// implemented by AIScalpingService.
+type ScalpingStrategy interface {
+	ExecuteTradingCycleForSymbols(ctx context.Context, portfolio TradingPortfolio, symbols []string) (*AITradingDecision, error)
+}
+
+// ShadowStrategyConfig configures a strategy variant evaluated in shadow mode.
+type ShadowStrategyConfig struct {
+	// Name labels the variant in reports.
+	Name string
+	// MinConfidence and MaxCapitalPct override the live values when positive.
+	MinConfidence float64
+	MaxCapitalPct float64
+	// PromptNotes are extra instructions appended to the variant's system prompt.
+	PromptNotes string
+	// InitialCapital, CommissionPercent and SlippagePercent configure the
+	// modeled fills of both the live mirror and the variant.
+	InitialCapital    decimal.Decimal
+	CommissionPercent decimal.Decimal
+	SlippagePercent   decimal.Decimal
+	// MaxComparisons is the number of recent cycles kept for review.
+	MaxComparisons int
+}
+
+// ShadowDecision is one strategy's decision in a cycle and its modeled fill.
+type ShadowDecision struct {
+	Action      string  `json:"action"`
+	Symbol      string  `json:"symbol,omitempty"`
+	SizePercent float64 `json:"size_pct,omitempty"`
+	Confidence  float64 `json:"confidence,omitempty"`
+	Reasoning   string  `json:"reasoning,omitempty"`
+	// TradeID is the modeled fill, empty when nothing was filled.
+	TradeID string `json:"trade_id,omitempty"`
+	// Error explains why the decision failed or was not filled.
+	Error string `json:"error,omitempty"`
+}
+
+// ShadowComparison pairs the live and shadow decisions of one cycle.
+type ShadowComparison struct {
+	At     time.Time      `json:"at"`
+	Live   ShadowDecision `json:"live"`
+	Shadow ShadowDecision `json:"shadow"`
+	// Agreed is true when both chose the same action on the same symbol.
+	Agreed bool `json:"agreed"`
+}
+
+// ShadowLedger summarizes the modeled results of one strategy.
+type ShadowLedger struct {
+	Cash          decimal.Decimal `json:"cash"`
+	TotalValue    decimal.Decimal `json:"total_value"`
+	RealizedPNL   decimal.Decimal `json:"realized_pnl"`
+	UnrealizedPNL decimal.Decimal `json:"unrealized_pnl"`
+	Trades        int             `json:"trades"`
+	OpenPositions int             `json:"open_positions"`
+}
+
+// ShadowReport compares the live strategy with the shadow variant.
+type ShadowReport struct {
+	Name       string       `json:"name"`
+	Since      time.Time    `json:"since"`
+	Cycles     int          `json:"cycles"`
+	Agreements int          `json:"agreements"`
+	Live       ShadowLedger `json:"live"`
+	Shadow     ShadowLedger `json:"shadow"`
+}
+
+// ShadowStrategyRunner runs a strategy variant next to the live strategy.
+// Both the live decisions and the variant's decisions are filled on separate
+// ShadowModeEngine ledgers with the same fill model, so the comparison is not
+// skewed by live execution quality. The variant never places real orders.
+type ShadowStrategyRunner struct {
+	config   ShadowStrategyConfig
+	strategy ScalpingStrategy
+	prices   TickerFetcher
+	exchange string
+	live     *ShadowModeEngine
+	shadow   *ShadowModeEngine
+	logger   *slog.Logger
+	now      func() time.Time
+	// running skips a cycle while the previous one is still deciding.
+	running     atomic.Bool
+	mu          sync.Mutex
+	since       time.Time
+	cycles      int
+	agreements  int
+	comparisons []ShadowComparison
+}
+
+// NewShadowStrategyRunner creates a shadow strategy runner.
+//
+// Parameters:
+//
+//	strategy: The variant; it must not place orders.
+//	prices: Ticker source used for modeled fills and valuations.
+//	exchange: The exchange the live strategy trades on.
+//	config: Variant and fill configuration.
+//
+// Returns:
+//
+//	*ShadowStrategyRunner: Initialized runner.
+func NewShadowStrategyRunner(strategy ScalpingStrategy, prices TickerFetcher, exchange string, config ShadowStrategyConfig) *ShadowStrategyRunner {
+	if config.Name == "" {
+		config.Name = "shadow"
+	}
+	if config.MaxComparisons <= 0 {
+		config.MaxComparisons = defaultShadowMaxComparisons
+	}
+	engineConfig := DefaultShadowModeConfig()
+	if config.InitialCapital.IsPositive() {
+		engineConfig.InitialCapital = config.InitialCapital
+	}
+	if config.CommissionPercent.IsPositive() {
+		engineConfig.CommissionPercent = config.CommissionPercent
+	}
+	if config.SlippagePercent.IsPositive() {
+		engineConfig.SlippagePercent = config.SlippagePercent
+	}
+
+	r := &ShadowStrategyRunner{
+		config:   config,
+		strategy: strategy,
+		prices:   prices,
+		exchange: exchange,
+		live:     NewShadowModeEngine(engineConfig, zap.NewNop()),
+		shadow:   NewShadowModeEngine(engineConfig, zap.NewNop()),
+		logger:   telemetry.Logger(),
+		now:      time.Now,
+	}
+	r.live.Enable()
+	r.shadow.Enable()
+	r.since = r.now().UTC()
+	return r
+}
+
+// RunCycle lets the variant decide on the same symbols as the live strategy
+// and fills both decisions on their ledgers.
+//
+// Parameters:
+//
+//	ctx: Context for the variant's decision and price lookups.
+//	symbols: The symbols the live strategy considered (nil for all).
+//	live: The live decision, nil when the live cycle failed.
+//
+// Returns:
+//
+//	*ShadowComparison: The recorded comparison, nil when a cycle was already running.
+func (r *ShadowStrategyRunner) RunCycle(ctx context.Context, symbols []string, live *AITradingDecision) *ShadowComparison {
+	if !r.running.CompareAndSwap(false, true) {
+		r.logger.Info("Shadow strategy cycle still running, skipping", "name", r.config.Name)
+		return nil
+	}
+	defer r.running.Store(false)
+
+	portfolio := r.shadow.GetPortfolio()
+	decision, err := r.strategy.ExecuteTradingCycleForSymbols(ctx, TradingPortfolio{
+		USDTBalance:   portfolio.Cash.InexactFloat64(),
+		TotalValue:    portfolio.TotalValue.InexactFloat64(),
+		OpenPositions: len(portfolio.Positions),
+	}, symbols)
+
+	comparison := ShadowComparison{
+		At:   r.now().UTC(),
+		Live: r.fill(ctx, r.live, live, nil, 0),
+	}
+	comparison.Shadow = r.fill(ctx, r.shadow, decision, err, r.config.MaxCapitalPct)
+	comparison.Agreed = comparison.Live.Action == comparison.Shadow.Action &&
+		(comparison.Live.Action == "hold" || comparison.Live.Symbol == comparison.Shadow.Symbol)
+	r.revalue(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cycles++
+	if comparison.Agreed {
+		r.agreements++
+	}
+	r.comparisons = append(r.comparisons, comparison)
+	if over := len(r.comparisons) - r.config.MaxComparisons; over > 0 {
+		r.comparisons = append(r.comparisons[:0], r.comparisons[over:]...)
+	}
+	return &comparison
+}
+
+// fill models the execution of a decision on a ledger. Buys spend size_pct of
+// the ledger's cash; sells close the ledger's long position in the symbol.
+func (r *ShadowStrategyRunner) fill(ctx context.Context, ledger *ShadowModeEngine, decision *AITradingDecision, decisionErr error, maxCapitalPct float64) ShadowDecision {
+	if decision == nil {
+		result := ShadowDecision{Action: "hold"}
+		if decisionErr != nil {
+			result.Error = decisionErr.Error()
+		}
+		return result
+	}
+	result := ShadowDecision{
+		Action:      decision.Action,
+		Symbol:      decision.Symbol,
+		SizePercent: decision.SizePercent,
+		Confidence:  decision.Confidence,
+		Reasoning:   decision.Reasoning,
+	}
+	if decisionErr != nil {
+		// The strategy rejected its own decision, e.g. for low confidence.
+		result.Error = decisionErr.Error()
+		return result
+	}
+	if decision.Action != "buy" && decision.Action != "sell" {
+		return result
+	}
+
+	ticker, err := r.prices.FetchSingleTicker(ctx, r.exchange, decision.Symbol)
+	if err != nil || ticker.GetPrice() <= 0 {
+		result.Error = fmt.Sprintf("no price for %s", decision.Symbol)
+		return result
+	}
+	price := decimal.NewFromFloat(ticker.GetPrice())
+
+	var quantity decimal.Decimal
+	if decision.Action == "buy" {
+		size := decision.SizePercent
+		if maxCapitalPct > 0 && size > maxCapitalPct {
+			size = maxCapitalPct
+		}
+		if size <= 0 || size > 100 {
+			result.Error = fmt.Sprintf("invalid size_pct %.4f", decision.SizePercent)
+			return result
+		}
+		result.SizePercent = size
+		quantity = ledger.GetPortfolio().Cash.Mul(decimal.NewFromFloat(size / 100)).Div(price)
+	} else {
+		position, ok := ledger.GetPortfolio().Positions[decision.Symbol]
+		if !ok {
+			result.Error = "no long position to close"
+			return result
+		}
+		quantity = position.Quantity
+	}
+
+	trade, err := ledger.ExecuteTrade(ctx, decision.Symbol, decision.Action, quantity, price)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.TradeID = trade.ID
+	return result
+}
+
+// revalue marks the open positions of both ledgers to the latest prices.
+func (r *ShadowStrategyRunner) revalue(ctx context.Context) {
+	prices := make(map[string]decimal.Decimal)
+	for _, ledger := range []*ShadowModeEngine{r.live, r.shadow} {
+		for symbol := range ledger.GetPortfolio().Positions {
+			if _, ok := prices[symbol]; ok {
+				continue
+			}
+			ticker, err := r.prices.FetchSingleTicker(ctx, r.exchange, symbol)
+			if err != nil || ticker.GetPrice() <= 0 {
+				continue
+			}
+			prices[symbol] = decimal.NewFromFloat(ticker.GetPrice())
+		}
+	}
+	for _, ledger := range []*ShadowModeEngine{r.live, r.shadow} {
+		ledger.UpdatePrices(prices)
+		ledger.RecordSnapshot()
+	}
+}
+
+// Report compares the modeled results of the live strategy and the variant.
+func (r *ShadowStrategyRunner) Report() ShadowReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return ShadowReport{
+		Name:       r.config.Name,
+		Since:      r.since,
+		Cycles:     r.cycles,
+		Agreements: r.agreements,
+		Live:       shadowLedgerOf(r.live),
+		Shadow:     shadowLedgerOf(r.shadow),
+	}
+}
+
+// Comparisons returns the most recent cycles, newest first.
+//
+// Parameters:
+//
+//	limit: Maximum number of cycles; 0 or less returns all kept cycles.
+//
+// Returns:
+//
+//	[]ShadowComparison: The comparisons.
+func (r *ShadowStrategyRunner) Comparisons(limit int) []ShadowComparison {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if limit <= 0 || limit > len(r.comparisons) {
+		limit = len(r.comparisons)
+	}
+	result := make([]ShadowComparison, 0, limit)
+	for i := len(r.comparisons) - 1; i >= 0 && len(result) < limit; i-- {
+		result = append(result, r.comparisons[i])
+	}
+	return result
+}
+
+// Reset clears both ledgers and the comparison history, for example after the
+// variant's parameters changed.
+func (r *ShadowStrategyRunner) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.live.Reset()
+	r.shadow.Reset()
+	r.since = r.now().UTC()
+	r.cycles = 0
+	r.agreements = 0
+	r.comparisons = nil
+}
+
+func shadowLedgerOf(ledger *ShadowModeEngine) ShadowLedger {
+	portfolio := ledger.GetPortfolio()
+	totalValue := portfolio.TotalValue
+	if portfolio.LastUpdated.IsZero() {
+		totalValue = portfolio.Cash
+	}
+	return ShadowLedger{
+		Cash:          portfolio.Cash,
+		TotalValue:    totalValue,
+		RealizedPNL:   portfolio.RealizedPNL,
+		UnrealizedPNL: portfolio.UnrealizedPNL,
+		Trades:        len(ledger.GetTrades(0)),
+		OpenPositions: len(portfolio.Positions),
+	}
+}
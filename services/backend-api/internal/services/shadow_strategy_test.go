@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type scriptedStrategy struct {
+	decisions []*AITradingDecision
+	errs      []error
+	seen      []TradingPortfolio
+}
+
+func (s *scriptedStrategy) ExecuteTradingCycleForSymbols(_ context.Context, portfolio TradingPortfolio, _ []string) (*AITradingDecision, error) {
+	s.seen = append(s.seen, portfolio)
+	decision, err := s.decisions[0], s.errs[0]
+	s.decisions, s.errs = s.decisions[1:], s.errs[1:]
+	return decision, err
+}
+
+func TestShadowStrategyRunner_ComparesLiveAndShadow(t *testing.T) {
+	tickers := &fakeTickerFetcher{prices: map[string]float64{
+		"binance:BTC/USDT": 100,
+		"binance:ETH/USDT": 10,
+	}}
+	strategy := &scriptedStrategy{
+		decisions: []*AITradingDecision{
+			{Action: "buy", Symbol: "ETH/USDT", SizePercent: 50, Confidence: 0.9},
+			{Action: "sell", Symbol: "ETH/USDT", Confidence: 0.8},
+		},
+		errs: []error{nil, nil},
+	}
+	runner := NewShadowStrategyRunner(strategy, tickers, "binance", ShadowStrategyConfig{
+		Name:              "eth-bias",
+		MaxCapitalPct:     10,
+		InitialCapital:    decimal.NewFromInt(1000),
+		CommissionPercent: decimal.NewFromFloat(0.001),
+		SlippagePercent:   decimal.NewFromFloat(0.001),
+	})
+	ctx := t.Context()
+
+	comparison := runner.RunCycle(ctx, nil, &AITradingDecision{Action: "buy", Symbol: "BTC/USDT", SizePercent: 5, Confidence: 0.8})
+	require.NotNil(t, comparison)
+	assert.False(t, comparison.Agreed)
+	assert.NotEmpty(t, comparison.Live.TradeID)
+	assert.NotEmpty(t, comparison.Shadow.TradeID)
+	assert.Equal(t, 10.0, comparison.Shadow.SizePercent, "the variant's size is capped")
+	assert.Equal(t, 1000.0, strategy.seen[0].USDTBalance)
+
+	// Both ledgers use the same fill model: 0.2% of the notional on top.
+	report := runner.Report()
+	assert.Equal(t, "949.9", report.Live.Cash.String())
+	assert.Equal(t, "899.8", report.Shadow.Cash.String())
+
+	tickers.set("binance:ETH/USDT", 12)
+	comparison = runner.RunCycle(ctx, nil, nil)
+	require.NotNil(t, comparison)
+	assert.Equal(t, "hold", comparison.Live.Action)
+	assert.NotEmpty(t, comparison.Shadow.TradeID)
+
+	report = runner.Report()
+	assert.Equal(t, 2, report.Cycles)
+	assert.Equal(t, 0, report.Agreements)
+	assert.Equal(t, 2, report.Shadow.Trades)
+	assert.Equal(t, 0, report.Shadow.OpenPositions)
+	assert.Equal(t, "20", report.Shadow.RealizedPNL.String())
+	assert.Equal(t, 1, report.Live.OpenPositions)
+
+	comparisons := runner.Comparisons(1)
+	require.Len(t, comparisons, 1)
+	assert.Equal(t, "sell", comparisons[0].Shadow.Action)
+
+	runner.Reset()
+	assert.Zero(t, runner.Report().Cycles)
+	assert.Empty(t, runner.Comparisons(0))
+	assert.Equal(t, "1000", runner.Report().Shadow.Cash.String())
+}
+
+func TestShadowStrategyRunner_RecordsRejectedDecisions(t *testing.T) {
+	tickers := &fakeTickerFetcher{prices: map[string]float64{}}
+	strategy := &scriptedStrategy{
+		decisions: []*AITradingDecision{{Action: "buy", Symbol: "BTC/USDT", SizePercent: 5, Confidence: 0.4}, nil},
+		errs:      []error{errors.New("confidence below threshold"), errors.New("LLM completion failed")},
+	}
+	runner := NewShadowStrategyRunner(strategy, tickers, "binance", ShadowStrategyConfig{})
+
+	comparison := runner.RunCycle(t.Context(), nil, &AITradingDecision{Action: "hold"})
+	require.NotNil(t, comparison)
+	assert.Equal(t, "confidence below threshold", comparison.Shadow.Error)
+	assert.Empty(t, comparison.Shadow.TradeID)
+
+	comparison = runner.RunCycle(t.Context(), nil, &AITradingDecision{Action: "sell", Symbol: "BTC/USDT"})
+	require.NotNil(t, comparison)
+	assert.Equal(t, "hold", comparison.Shadow.Action)
+	assert.Equal(t, "LLM completion failed", comparison.Shadow.Error)
+	assert.Equal(t, "no price for BTC/USDT", comparison.Live.Error)
+	assert.Equal(t, "shadow", runner.Report().Name)
+}
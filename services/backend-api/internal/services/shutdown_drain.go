@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/telemetry"
+)
+
+// ShutdownDrainConfig bounds a graceful shutdown.
+type ShutdownDrainConfig struct {
+	// Budget bounds the whole drain, from the signal to exit.
+	Budget time.Duration
+	// ReadinessDelay keeps the server serving after readiness turns false,
+	// so load balancers stop routing new requests before the listener closes.
+	ReadinessDelay time.Duration
+}
+
+// DefaultShutdownDrainConfig returns a 30 second budget with a 5 second readiness delay.
+func DefaultShutdownDrainConfig() ShutdownDrainConfig {
+	return ShutdownDrainConfig{
+		Budget:         30 * time.Second,
+		ReadinessDelay: 5 * time.Second,
+	}
+}
+
+type drainStep struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// ShutdownDrain coordinates a zero-downtime shutdown: readiness turns false,
+// new requests stop, in-flight order submissions finish and registered
+// steps such as the quest checkpoint run, all within the drain budget.
+type ShutdownDrain struct {
+	config ShutdownDrainConfig
+	logger *slog.Logger
+
+	mu       sync.Mutex
+	draining bool
+	inFlight int
+	// idle is closed while no order submission is in flight.
+	idle  chan struct{}
+	steps []drainStep
+}
+
+// NewShutdownDrain creates a drain coordinator.
+//
+// Parameters:
+//
+//	config: Drain budget and readiness delay (zero values use the defaults).
+//
+// Returns:
+//
+//	*ShutdownDrain: Initialized coordinator.
+func NewShutdownDrain(config ShutdownDrainConfig) *ShutdownDrain {
+	defaults := DefaultShutdownDrainConfig()
+	if config.Budget <= 0 {
+		config.Budget = defaults.Budget
+	}
+	if config.ReadinessDelay < 0 {
+		config.ReadinessDelay = 0
+	}
+	if config.ReadinessDelay >= config.Budget {
+		config.ReadinessDelay = config.Budget / 4
+	}
+	idle := make(chan struct{})
+	close(idle)
+	return &ShutdownDrain{
+		config: config,
+		logger: telemetry.Logger(),
+		idle:   idle,
+	}
+}
+
+// Config returns the drain configuration.
+func (d *ShutdownDrain) Config() ShutdownDrainConfig {
+	return d.config
+}
+
+// Draining reports whether a shutdown drain has started. Readiness checks
+// fail while it is true.
+func (d *ShutdownDrain) Draining() bool {
+	if d == nil {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.draining
+}
+
+// Track marks an order submission as in flight until the returned func is
+// called. Submissions are never refused: a quest run that is finishing
+// may still need to place its exit.
+func (d *ShutdownDrain) Track() (done func()) {
+	if d == nil {
+		return func() {}
+	}
+	d.mu.Lock()
+	if d.inFlight == 0 {
+		d.idle = make(chan struct{})
+	}
+	d.inFlight++
+	d.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			d.inFlight--
+			if d.inFlight == 0 {
+				close(d.idle)
+			}
+		})
+	}
+}
+
+// OnDrain registers a step run after the server stopped accepting requests,
+// e.g. stopping the quest scheduler and checkpointing quest state. Steps run
+// in registration order.
+func (d *ShutdownDrain) OnDrain(name string, step func(ctx context.Context) error) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.steps = append(d.steps, drainStep{name: name, run: step})
+}
+
+// Drain runs the shutdown sequence. It turns readiness false, keeps serving
+// for the readiness delay, calls stopAccepting (typically http.Server.Shutdown,
+// which closes the listener and waits for in-flight requests), runs the
+// registered steps and waits for in-flight order submissions. Every stage
+// shares the drain budget.
+//
+// Parameters:
+//
+//	ctx: Parent context; the budget is applied on top of it.
+//	stopAccepting: Stops accepting new requests and waits for in-flight ones (may be nil).
+//
+// Returns:
+//
+//	error: The joined errors of the stages that failed or ran out of budget.
+func (d *ShutdownDrain) Drain(ctx context.Context, stopAccepting func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, d.config.Budget)
+	defer cancel()
+
+	d.mu.Lock()
+	d.draining = true
+	steps := append([]drainStep(nil), d.steps...)
+	d.mu.Unlock()
+	started := time.Now()
+	d.logger.Info("Shutdown drain started", "budget", d.config.Budget, "readiness_delay", d.config.ReadinessDelay)
+
+	var errs []error
+	timer := time.NewTimer(d.config.ReadinessDelay)
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+	timer.Stop()
+
+	if stopAccepting != nil {
+		if err := stopAccepting(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stop accepting requests: %w", err))
+		}
+	}
+	for _, step := range steps {
+		if err := step.run(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", step.name, err))
+		}
+	}
+	if err := d.waitOrders(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	err := errors.Join(errs...)
+	if err != nil {
+		d.logger.Error("Shutdown drain incomplete", "elapsed", time.Since(started), "error", err)
+	} else {
+		d.logger.Info("Shutdown drain complete", "elapsed", time.Since(started))
+	}
+	return err
+}
+
+// waitOrders waits for the in-flight order submissions to finish.
+func (d *ShutdownDrain) waitOrders(ctx context.Context) error {
+	d.mu.Lock()
+	idle := d.idle
+	d.mu.Unlock()
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		d.mu.Lock()
+		inFlight := d.inFlight
+		d.mu.Unlock()
+		return fmt.Errorf("%d order submission(s) still in flight: %w", inFlight, ctx.Err())
+	}
+}
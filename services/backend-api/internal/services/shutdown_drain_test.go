@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdownDrain_Sequence(t *testing.T) {
+	drain := NewShutdownDrain(ShutdownDrainConfig{Budget: time.Second, ReadinessDelay: 20 * time.Millisecond})
+	assert.False(t, drain.Draining())
+
+	orderDone := drain.Track()
+	var stages []string
+	drain.OnDrain("quests", func(context.Context) error {
+		stages = append(stages, "quests")
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			orderDone()
+		}()
+		return nil
+	})
+
+	started := time.Now()
+	err := drain.Drain(context.Background(), func(context.Context) error {
+		assert.True(t, drain.Draining(), "readiness must turn false before the listener closes")
+		assert.GreaterOrEqual(t, time.Since(started), 20*time.Millisecond)
+		stages = append(stages, "http")
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http", "quests"}, stages)
+	assert.True(t, drain.Draining())
+}
+
+func TestShutdownDrain_BudgetExceeded(t *testing.T) {
+	drain := NewShutdownDrain(ShutdownDrainConfig{Budget: 50 * time.Millisecond})
+	defer drain.Track()()
+	drain.OnDrain("quests", func(context.Context) error { return errors.New("save failed") })
+
+	started := time.Now()
+	err := drain.Drain(context.Background(), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "quests: save failed")
+	assert.Contains(t, err.Error(), "1 order submission(s) still in flight")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(started), time.Second)
+}
+
+func TestShutdownDrain_Defaults(t *testing.T) {
+	drain := NewShutdownDrain(ShutdownDrainConfig{Budget: 4 * time.Second, ReadinessDelay: 10 * time.Second})
+	assert.Equal(t, time.Second, drain.Config().ReadinessDelay)
+	assert.Equal(t, DefaultShutdownDrainConfig(), NewShutdownDrain(ShutdownDrainConfig{ReadinessDelay: 5 * time.Second}).Config())
+
+	var nilDrain *ShutdownDrain
+	assert.False(t, nilDrain.Draining())
+	nilDrain.Track()()
+	nilDrain.OnDrain("noop", nil)
+}
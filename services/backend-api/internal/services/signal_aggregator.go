@@ -28,6 +28,7 @@ type SignalType string
 const (
 	SignalTypeArbitrage SignalType = "arbitrage"
 	SignalTypeTechnical SignalType = "technical"
+	SignalTypeExternal  SignalType = "external"
 )
 
 // SignalStrength represents the strength of a trading signal
@@ -113,8 +114,17 @@ type SignalAggregator struct {
 	sigConfig     SignalAggregatorConfig
 	qualityScorer SignalQualityScorerInterface
 	cache         map[string]*AggregatedSignal
+	positioning   PositioningReader
+	kpis          KPIRecorder
 }
 
+// Long/short account ratios beyond which one side of a perp is crowded.
+const (
+	crowdedLongRatio           = 2.0
+	crowdedShortRatio          = 0.5
+	positioningConfidenceShift = 0.05
+)
+
 // NewSignalAggregator creates a new instance of SignalAggregator.
 //
 // Parameters:
@@ -142,6 +152,20 @@ func NewSignalAggregator(cfg *config.Config, db DBPool, logger *zaplogrus.Logger
 	}
 }
 
+// SetPositioningSource sets the derivatives positioning used to annotate
+// technical signals and adjust their confidence for crowded positioning.
+//
+// Parameters:
+//   - positioning: Open interest and long/short ratio reader (nil disables it).
+func (sa *SignalAggregator) SetPositioningSource(positioning PositioningReader) {
+	sa.positioning = positioning
+}
+
+// SetKPIRecorder records the number of arbitrage signals each aggregation finds.
+func (sa *SignalAggregator) SetKPIRecorder(kpis KPIRecorder) {
+	sa.kpis = kpis
+}
+
 // AggregateArbitrageSignals processes raw arbitrage opportunities into aggregated signals.
 // It groups opportunities by symbol, filters by volume and profit threshold, and creates enhanced signals with price ranges.
 //
@@ -264,6 +288,9 @@ func (sa *SignalAggregator) AggregateArbitrageSignals(ctx context.Context, input
 		"symbols_processed": len(symbolGroups),
 		"operation_result":  "success",
 	}).Info("Arbitrage signal aggregation completed")
+	if sa.kpis != nil {
+		sa.kpis.RecordKPI(KPIOpportunitiesFound, float64(len(signals)))
+	}
 
 	return signals, nil
 }
@@ -346,6 +373,7 @@ func (sa *SignalAggregator) AggregateTechnicalSignals(ctx context.Context, input
 
 	// Generate signals based on indicators
 	signals := sa.generateTechnicalSignals(input.Symbol, input.Exchange, indicators)
+	sa.applyPositioning(ctx, input.Symbol, input.Exchange, signals)
 
 	// Assess quality for each technical signal
 	var qualitySignals []*AggregatedSignal
@@ -404,6 +432,22 @@ func (sa *SignalAggregator) AggregateTechnicalSignals(ctx context.Context, input
 	return qualitySignals, nil
 }
 
+// EvaluateTechnicalSignals computes the technical signals of a closing price
+// history with the same indicators and rules as AggregateTechnicalSignals, but
+// without quality scoring, positioning or storage, so session replays get the
+// same signals from the same prices.
+//
+// Parameters:
+//   - symbol: The trading symbol.
+//   - exchange: The exchange the prices are from.
+//   - closes: Closing prices, oldest first (at least 50 for any signal).
+//
+// Returns:
+//   - The buy and sell signals the latest price triggers.
+func (sa *SignalAggregator) EvaluateTechnicalSignals(symbol, exchange string, closes []float64) []*AggregatedSignal {
+	return sa.generateTechnicalSignals(symbol, exchange, sa.calculateTechnicalIndicators(closes))
+}
+
 // DeduplicateSignals filters out signals that are considered duplicates of recently processed signals.
 // It uses a fingerprinting mechanism based on signal characteristics to identify duplicates within a configured time window.
 //
@@ -823,6 +867,52 @@ func (sa *SignalAggregator) createAggregatedTechnicalSignal(symbol, exchange, ac
 	}
 }
 
+// applyPositioning annotates signals with open interest and the long/short
+// ratio of the symbol's perp. Signals trading against a crowded side gain
+// confidence, signals joining it lose confidence.
+func (sa *SignalAggregator) applyPositioning(ctx context.Context, symbol, exchange string, signals []*AggregatedSignal) {
+	if sa.positioning == nil || len(signals) == 0 {
+		return
+	}
+	snapshot, err := sa.positioning.LatestPositioning(ctx, exchange, symbol)
+	if err != nil {
+		sa.logger.WithError(err).Warn("Failed to load derivatives positioning")
+		return
+	}
+	if snapshot == nil {
+		return
+	}
+
+	crowded := ""
+	switch {
+	case snapshot.LongShortRatio >= crowdedLongRatio:
+		crowded = "long"
+	case snapshot.LongShortRatio > 0 && snapshot.LongShortRatio <= crowdedShortRatio:
+		crowded = "short"
+	}
+
+	for _, signal := range signals {
+		if signal.Metadata == nil {
+			signal.Metadata = make(map[string]interface{})
+		}
+		signal.Metadata["open_interest_value"] = snapshot.OpenInterestValue
+		signal.Metadata["open_interest_change_pct"] = snapshot.OpenInterestChangePct
+		signal.Metadata["long_short_ratio"] = snapshot.LongShortRatio
+		if crowded == "" {
+			continue
+		}
+		signal.Metadata["crowded_side"] = crowded
+
+		shift := decimal.NewFromFloat(positioningConfidenceShift)
+		joinsCrowd := (crowded == "long" && signal.Action == "buy") || (crowded == "short" && signal.Action == "sell")
+		if joinsCrowd {
+			shift = shift.Neg()
+		}
+		signal.Confidence = decimal.Max(decimal.Zero, decimal.Min(decimal.NewFromInt(1), signal.Confidence.Add(shift)))
+		signal.Strength = sa.determineSignalStrength(signal.Confidence)
+	}
+}
+
 func (sa *SignalAggregator) calculateArbitrageConfidence(opp models.ArbitrageOpportunity) decimal.Decimal {
 	// Basic confidence based on profit and spread
 	// Higher profit = higher confidence (up to a point, then it looks suspicious)
@@ -910,6 +1000,44 @@ func (sa *SignalAggregator) storeFingerprint(ctx context.Context, fingerprint *S
 	}
 }
 
+// StoreAggregatedSignal persists a signal so that it is ranked by
+// GetActiveAggregatedSignals alongside internally generated ones.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//   - signal: The signal to store.
+//
+// Returns:
+//   - An error if the database is unavailable or the insert fails.
+func (sa *SignalAggregator) StoreAggregatedSignal(ctx context.Context, signal *AggregatedSignal) error {
+	if isNilDBPool(sa.db) {
+		return fmt.Errorf("signal aggregator requires a database connection")
+	}
+
+	metadataJSON, err := json.Marshal(signal.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal signal metadata: %w", err)
+	}
+
+	query := `
+		INSERT INTO aggregated_signals (
+			id, signal_type, symbol, action, strength, confidence,
+			profit_potential, risk_level, exchanges, indicators,
+			metadata, created_at, expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+	_, err = sa.db.Exec(ctx, query,
+		signal.ID, string(signal.SignalType), signal.Symbol, signal.Action,
+		string(signal.Strength), signal.Confidence, signal.ProfitPotential,
+		signal.RiskLevel, signal.Exchanges, signal.Indicators,
+		metadataJSON, signal.CreatedAt, signal.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store aggregated signal: %w", err)
+	}
+	return nil
+}
+
 // GetActiveAggregatedSignals retrieves active aggregated signals from the database, filtered by confidence.
 //
 // Parameters:
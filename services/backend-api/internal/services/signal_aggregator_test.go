@@ -319,3 +319,21 @@ func TestSignalAggregator_AggregateArbitrageSignals_MultipleOpportunities(t *tes
 		assert.NotEmpty(t, signal.Exchanges)
 	}
 }
+
+// TestSignalAggregator_EvaluateTechnicalSignals tests signals computed from closes alone
+func TestSignalAggregator_EvaluateTechnicalSignals(t *testing.T) {
+	sa := NewSignalAggregator(nil, nil, zaplogrus.New())
+
+	closes := make([]float64, 60)
+	for i := range closes {
+		closes[i] = 100 - float64(i)
+	}
+	assert.Empty(t, sa.EvaluateTechnicalSignals("BTC/USDT", "binance", closes[:40]), "fewer than 50 closes give no signal")
+
+	signals := sa.EvaluateTechnicalSignals("BTC/USDT", "binance", closes)
+	if assert.Len(t, signals, 1) {
+		assert.Equal(t, "buy", signals[0].Action)
+		assert.Contains(t, signals[0].Indicators, "rsi_oversold")
+		assert.Equal(t, []string{"binance"}, signals[0].Exchanges)
+	}
+}
@@ -0,0 +1,353 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/irfndi/neuratrade/internal/services/eventbus"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	signalSourceKeyPrefix = "signals:source:"
+	signalSourcesKey      = "signals:sources"
+	signalOwnerKeyPrefix  = "signals:external:"
+
+	// signalOutcomeWindow is how long an ingested signal can have its outcome reported.
+	signalOutcomeWindow = 7 * 24 * time.Hour
+
+	defaultInboundSignalTTL = 15 * time.Minute
+	maxInboundSignalTTL     = 24 * time.Hour
+)
+
+var (
+	// ErrInboundSignalInvalid is returned when a submitted signal fails validation.
+	ErrInboundSignalInvalid = errors.New("invalid inbound signal")
+	// ErrInboundSignalNotFound is returned when an outcome is reported for an
+	// unknown signal or one whose outcome was already recorded.
+	ErrInboundSignalNotFound = errors.New("inbound signal not found")
+
+	signalSourcePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,63}$`)
+)
+
+// InboundSignal is a signal submitted by an external strategy engine.
+type InboundSignal struct {
+	Source     string                 `json:"source" binding:"required"`
+	Symbol     string                 `json:"symbol" binding:"required"`
+	Side       string                 `json:"side" binding:"required"`
+	Confidence float64                `json:"confidence"`
+	TTLSeconds int                    `json:"ttl_seconds,omitempty"`
+	Exchange   string                 `json:"exchange,omitempty"`
+	Indicators []string               `json:"indicators,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// InboundSignalResult reports how a submitted signal entered the aggregator.
+type InboundSignalResult struct {
+	SignalID            string          `json:"signal_id"`
+	Accepted            bool            `json:"accepted"`
+	Duplicate           bool            `json:"duplicate"`
+	RawConfidence       decimal.Decimal `json:"raw_confidence"`
+	EffectiveConfidence decimal.Decimal `json:"effective_confidence"`
+	SourceReliability   float64         `json:"source_reliability"`
+	ExpiresAt           time.Time       `json:"expires_at"`
+}
+
+// SignalSourceStats tracks how often a source's signals are accepted and how
+// often they turn out to be profitable.
+type SignalSourceStats struct {
+	Source      string    `json:"source"`
+	Submitted   int64     `json:"submitted"`
+	Accepted    int64     `json:"accepted"`
+	Duplicates  int64     `json:"duplicates"`
+	Wins        int64     `json:"wins"`
+	Losses      int64     `json:"losses"`
+	Reliability float64   `json:"reliability"`
+	LastSeen    time.Time `json:"last_seen"`
+}
+
+// AggregatedSignalStore deduplicates and persists signals for the aggregator.
+type AggregatedSignalStore interface {
+	DeduplicateSignals(ctx context.Context, signals []*AggregatedSignal) ([]*AggregatedSignal, error)
+	StoreAggregatedSignal(ctx context.Context, signal *AggregatedSignal) error
+}
+
+// SignalIngestService accepts signals from external engines, weights them by
+// the reliability of their source and stores them in the aggregator.
+type SignalIngestService struct {
+	store AggregatedSignalStore
+	redis *redis.Client
+	bus   eventbus.Publisher
+	now   func() time.Time
+}
+
+// NewSignalIngestService creates a new inbound signal service.
+//
+// Parameters:
+//   - store: The aggregator store that ranks signals.
+//   - redisClient: Redis client for per-source reliability statistics.
+//
+// Returns:
+//   - *SignalIngestService: The initialized service.
+func NewSignalIngestService(store AggregatedSignalStore, redisClient *redis.Client) *SignalIngestService {
+	return &SignalIngestService{
+		store: store,
+		redis: redisClient,
+		now:   time.Now,
+	}
+}
+
+// SetEventBus publishes accepted signals on eventbus.SubjectOpportunity.
+func (s *SignalIngestService) SetEventBus(bus eventbus.Publisher) {
+	s.bus = bus
+}
+
+// sourceReliability is the Laplace-smoothed win rate, so a new source starts at 0.5.
+func sourceReliability(wins, losses int64) float64 {
+	return float64(wins+1) / float64(wins+losses+2)
+}
+
+// reliabilityWeight scales a source's confidence between 0.5x (never profitable)
+// and 1x (always profitable); an unproven source is weighted at 0.75x.
+func reliabilityWeight(reliability float64) decimal.Decimal {
+	return decimal.NewFromFloat(0.5 + reliability/2)
+}
+
+// Ingest validates a signal, weights its confidence by source reliability and
+// stores it so it competes with internally generated signals.
+//
+// Parameters:
+//   - ctx: Request context.
+//   - input: The submitted signal.
+//
+// Returns:
+//   - *InboundSignalResult: How the signal was accepted.
+//   - error: ErrInboundSignalInvalid on validation failure, or a storage error.
+func (s *SignalIngestService) Ingest(ctx context.Context, input InboundSignal) (*InboundSignalResult, error) {
+	source := strings.ToLower(strings.TrimSpace(input.Source))
+	if !signalSourcePattern.MatchString(source) {
+		return nil, fmt.Errorf("%w: source must be 1-64 lowercase letters, digits, '.', '_' or '-'", ErrInboundSignalInvalid)
+	}
+	symbol := NormalizeTradingViewTicker(input.Symbol)
+	if symbol == "" {
+		return nil, fmt.Errorf("%w: symbol is required", ErrInboundSignalInvalid)
+	}
+	side := strings.ToLower(strings.TrimSpace(input.Side))
+	if side != "buy" && side != "sell" && side != "hold" {
+		return nil, fmt.Errorf("%w: side must be buy, sell or hold", ErrInboundSignalInvalid)
+	}
+	if input.Confidence <= 0 || input.Confidence > 1 {
+		return nil, fmt.Errorf("%w: confidence must be in (0, 1]", ErrInboundSignalInvalid)
+	}
+	ttl := defaultInboundSignalTTL
+	if input.TTLSeconds < 0 || time.Duration(input.TTLSeconds)*time.Second > maxInboundSignalTTL {
+		return nil, fmt.Errorf("%w: ttl_seconds must be between 0 and %d", ErrInboundSignalInvalid, int(maxInboundSignalTTL.Seconds()))
+	}
+	if input.TTLSeconds > 0 {
+		ttl = time.Duration(input.TTLSeconds) * time.Second
+	}
+
+	stats, err := s.SourceStats(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	raw := decimal.NewFromFloat(input.Confidence)
+	effective := raw.Mul(reliabilityWeight(stats.Reliability)).Round(4)
+
+	now := s.now().UTC()
+	metadata := make(map[string]interface{}, len(input.Metadata)+3)
+	for k, v := range input.Metadata {
+		metadata[k] = v
+	}
+	metadata["source"] = source
+	metadata["raw_confidence"] = raw.String()
+	metadata["source_reliability"] = stats.Reliability
+
+	var exchanges []string
+	if exchange := strings.ToLower(strings.TrimSpace(input.Exchange)); exchange != "" {
+		exchanges = []string{exchange}
+	}
+	// The source is part of the indicators so deduplication only collapses
+	// repeats from the same source, not agreement between sources.
+	indicators := append([]string{"source:" + source}, input.Indicators...)
+
+	signal := &AggregatedSignal{
+		ID:              uuid.NewString(),
+		SignalType:      SignalTypeExternal,
+		Symbol:          symbol,
+		Action:          side,
+		Strength:        externalSignalStrength(effective),
+		Confidence:      effective,
+		ProfitPotential: decimal.Zero,
+		RiskLevel:       decimal.NewFromInt(1).Sub(effective),
+		Exchanges:       exchanges,
+		Indicators:      indicators,
+		Metadata:        metadata,
+		CreatedAt:       now,
+		ExpiresAt:       now.Add(ttl),
+	}
+
+	result := &InboundSignalResult{
+		SignalID:            signal.ID,
+		RawConfidence:       raw,
+		EffectiveConfidence: effective,
+		SourceReliability:   stats.Reliability,
+		ExpiresAt:           signal.ExpiresAt,
+	}
+
+	unique, err := s.store.DeduplicateSignals(ctx, []*AggregatedSignal{signal})
+	if err != nil {
+		return nil, fmt.Errorf("failed to deduplicate signal: %w", err)
+	}
+	if len(unique) == 0 {
+		result.Duplicate = true
+		s.recordSubmission(ctx, source, "duplicates", "", now)
+		return result, nil
+	}
+
+	if err := s.store.StoreAggregatedSignal(ctx, signal); err != nil {
+		return nil, err
+	}
+	result.Accepted = true
+	s.recordSubmission(ctx, source, "accepted", signal.ID, now)
+	publishEvent(ctx, s.bus, eventbus.SubjectOpportunity, signal)
+	return result, nil
+}
+
+func externalSignalStrength(confidence decimal.Decimal) SignalStrength {
+	switch {
+	case confidence.GreaterThanOrEqual(decimal.NewFromFloat(0.8)):
+		return SignalStrengthStrong
+	case confidence.GreaterThanOrEqual(decimal.NewFromFloat(0.6)):
+		return SignalStrengthMedium
+	default:
+		return SignalStrengthWeak
+	}
+}
+
+// recordSubmission updates the source counters and, for accepted signals,
+// remembers the source so that an outcome can be attributed later.
+func (s *SignalIngestService) recordSubmission(ctx context.Context, source, counter, signalID string, now time.Time) {
+	if s.redis == nil {
+		return
+	}
+	key := signalSourceKeyPrefix + source
+	pipe := s.redis.TxPipeline()
+	pipe.SAdd(ctx, signalSourcesKey, source)
+	pipe.HIncrBy(ctx, key, "submitted", 1)
+	pipe.HIncrBy(ctx, key, counter, 1)
+	pipe.HSet(ctx, key, "last_seen", now.Unix())
+	if signalID != "" {
+		pipe.Set(ctx, signalOwnerKeyPrefix+signalID, source, signalOutcomeWindow)
+	}
+	_, _ = pipe.Exec(ctx)
+}
+
+// RecordOutcome reports whether an ingested signal was profitable, which
+// updates its source's reliability. Each signal's outcome counts once.
+//
+// Parameters:
+//   - ctx: Request context.
+//   - signalID: The ID returned by Ingest.
+//   - profitable: Whether acting on the signal was profitable.
+//
+// Returns:
+//   - *SignalSourceStats: The source's updated statistics.
+//   - error: ErrInboundSignalNotFound if the signal is unknown or already scored.
+func (s *SignalIngestService) RecordOutcome(ctx context.Context, signalID string, profitable bool) (*SignalSourceStats, error) {
+	if s.redis == nil {
+		return nil, ErrInboundSignalNotFound
+	}
+	source, err := s.redis.GetDel(ctx, signalOwnerKeyPrefix+signalID).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrInboundSignalNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up signal source: %w", err)
+	}
+
+	field := "losses"
+	if profitable {
+		field = "wins"
+	}
+	if err := s.redis.HIncrBy(ctx, signalSourceKeyPrefix+source, field, 1).Err(); err != nil {
+		return nil, fmt.Errorf("failed to record signal outcome: %w", err)
+	}
+	return s.SourceStats(ctx, source)
+}
+
+// SourceStats returns the statistics for one source. Unknown sources have
+// zero counters and the default reliability.
+//
+// Parameters:
+//   - ctx: Request context.
+//   - source: The source name.
+//
+// Returns:
+//   - *SignalSourceStats: The statistics.
+//   - error: Error if Redis cannot be read.
+func (s *SignalIngestService) SourceStats(ctx context.Context, source string) (*SignalSourceStats, error) {
+	stats := &SignalSourceStats{Source: source}
+	if s.redis != nil {
+		values, err := s.redis.HGetAll(ctx, signalSourceKeyPrefix+source).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load signal source stats: %w", err)
+		}
+		parse := func(field string) int64 {
+			n, _ := strconv.ParseInt(values[field], 10, 64)
+			return n
+		}
+		stats.Submitted = parse("submitted")
+		stats.Accepted = parse("accepted")
+		stats.Duplicates = parse("duplicates")
+		stats.Wins = parse("wins")
+		stats.Losses = parse("losses")
+		if lastSeen := parse("last_seen"); lastSeen > 0 {
+			stats.LastSeen = time.Unix(lastSeen, 0).UTC()
+		}
+	}
+	stats.Reliability = sourceReliability(stats.Wins, stats.Losses)
+	return stats, nil
+}
+
+// ListSources returns statistics for every source that has submitted a signal,
+// most reliable first.
+//
+// Parameters:
+//   - ctx: Request context.
+//
+// Returns:
+//   - []SignalSourceStats: The statistics.
+//   - error: Error if Redis cannot be read.
+func (s *SignalIngestService) ListSources(ctx context.Context) ([]SignalSourceStats, error) {
+	if s.redis == nil {
+		return []SignalSourceStats{}, nil
+	}
+	sources, err := s.redis.SMembers(ctx, signalSourcesKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list signal sources: %w", err)
+	}
+	result := make([]SignalSourceStats, 0, len(sources))
+	for _, source := range sources {
+		stats, err := s.SourceStats(ctx, source)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Reliability != result[j].Reliability {
+			return result[i].Reliability > result[j].Reliability
+		}
+		return result[i].Source < result[j].Source
+	})
+	return result, nil
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/irfndi/neuratrade/internal/database"
+	zaplogrus "github.com/irfndi/neuratrade/internal/logging/zaplogrus"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySignalStore deduplicates on symbol, side and source like the aggregator's hash.
+type memorySignalStore struct {
+	stored []*AggregatedSignal
+	seen   map[string]bool
+}
+
+func (m *memorySignalStore) DeduplicateSignals(_ context.Context, signals []*AggregatedSignal) ([]*AggregatedSignal, error) {
+	if m.seen == nil {
+		m.seen = make(map[string]bool)
+	}
+	var unique []*AggregatedSignal
+	for _, signal := range signals {
+		key := signal.Symbol + signal.Action + signal.Indicators[0]
+		if !m.seen[key] {
+			m.seen[key] = true
+			unique = append(unique, signal)
+		}
+	}
+	return unique, nil
+}
+
+func (m *memorySignalStore) StoreAggregatedSignal(_ context.Context, signal *AggregatedSignal) error {
+	m.stored = append(m.stored, signal)
+	return nil
+}
+
+func newTestSignalIngestService(t *testing.T) (*SignalIngestService, *memorySignalStore) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	store := &memorySignalStore{}
+	return NewSignalIngestService(store, client), store
+}
+
+func TestSignalIngestService_Validation(t *testing.T) {
+	svc, _ := newTestSignalIngestService(t)
+	valid := InboundSignal{Source: "quant-a", Symbol: "BTCUSDT", Side: "buy", Confidence: 0.8}
+
+	for name, mutate := range map[string]func(*InboundSignal){
+		"source":     func(s *InboundSignal) { s.Source = "has spaces" },
+		"symbol":     func(s *InboundSignal) { s.Symbol = "" },
+		"side":       func(s *InboundSignal) { s.Side = "long" },
+		"confidence": func(s *InboundSignal) { s.Confidence = 1.5 },
+		"ttl":        func(s *InboundSignal) { s.TTLSeconds = 90000 },
+	} {
+		input := valid
+		mutate(&input)
+		_, err := svc.Ingest(t.Context(), input)
+		assert.ErrorIs(t, err, ErrInboundSignalInvalid, name)
+	}
+}
+
+func TestSignalIngestService_IngestWeightsBySourceReliability(t *testing.T) {
+	svc, store := newTestSignalIngestService(t)
+
+	result, err := svc.Ingest(t.Context(), InboundSignal{Source: "Quant-A", Symbol: "BTCUSDT", Side: "buy", Confidence: 0.8, TTLSeconds: 60, Exchange: "Binance"})
+	require.NoError(t, err)
+	assert.True(t, result.Accepted)
+	assert.Equal(t, 0.5, result.SourceReliability)
+	assert.True(t, result.EffectiveConfidence.Equal(decimal.NewFromFloat(0.6)), "unproven sources are weighted at 0.75x")
+
+	require.Len(t, store.stored, 1)
+	signal := store.stored[0]
+	assert.Equal(t, SignalTypeExternal, signal.SignalType)
+	assert.Equal(t, "BTC/USDT", signal.Symbol)
+	assert.Equal(t, []string{"binance"}, signal.Exchanges)
+	assert.Equal(t, "quant-a", signal.Metadata["source"])
+	assert.Equal(t, signal.CreatedAt.Add(time.Minute), signal.ExpiresAt)
+
+	// Repeats from the same source are duplicates; other sources still count.
+	result, err = svc.Ingest(t.Context(), InboundSignal{Source: "quant-a", Symbol: "BTC/USDT", Side: "buy", Confidence: 0.9})
+	require.NoError(t, err)
+	assert.True(t, result.Duplicate)
+	assert.False(t, result.Accepted)
+	result, err = svc.Ingest(t.Context(), InboundSignal{Source: "quant-b", Symbol: "BTC/USDT", Side: "buy", Confidence: 0.9})
+	require.NoError(t, err)
+	assert.True(t, result.Accepted)
+
+	stats, err := svc.SourceStats(t.Context(), "quant-a")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stats.Submitted)
+	assert.Equal(t, int64(1), stats.Accepted)
+	assert.Equal(t, int64(1), stats.Duplicates)
+	assert.False(t, stats.LastSeen.IsZero())
+}
+
+func TestSignalIngestService_RecordOutcome(t *testing.T) {
+	svc, _ := newTestSignalIngestService(t)
+
+	first, err := svc.Ingest(t.Context(), InboundSignal{Source: "quant-a", Symbol: "BTCUSDT", Side: "buy", Confidence: 0.8})
+	require.NoError(t, err)
+	second, err := svc.Ingest(t.Context(), InboundSignal{Source: "quant-a", Symbol: "ETHUSDT", Side: "sell", Confidence: 0.8})
+	require.NoError(t, err)
+
+	stats, err := svc.RecordOutcome(t.Context(), first.SignalID, true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Wins)
+	assert.InDelta(t, 2.0/3.0, stats.Reliability, 1e-9)
+
+	_, err = svc.RecordOutcome(t.Context(), first.SignalID, true)
+	assert.ErrorIs(t, err, ErrInboundSignalNotFound, "outcomes count once")
+	_, err = svc.RecordOutcome(t.Context(), "unknown", true)
+	assert.ErrorIs(t, err, ErrInboundSignalNotFound)
+
+	_, err = svc.RecordOutcome(t.Context(), second.SignalID, true)
+	require.NoError(t, err)
+
+	// A reliable source's next signal is weighted up.
+	result, err := svc.Ingest(t.Context(), InboundSignal{Source: "quant-a", Symbol: "SOLUSDT", Side: "buy", Confidence: 0.8})
+	require.NoError(t, err)
+	assert.InDelta(t, 0.75, result.SourceReliability, 1e-9)
+	assert.True(t, result.EffectiveConfidence.Equal(decimal.NewFromFloat(0.7)))
+
+	_, err = svc.Ingest(t.Context(), InboundSignal{Source: "quant-b", Symbol: "SOLUSDT", Side: "buy", Confidence: 0.8})
+	require.NoError(t, err)
+	sources, err := svc.ListSources(t.Context())
+	require.NoError(t, err)
+	require.Len(t, sources, 2)
+	assert.Equal(t, "quant-a", sources[0].Source, "most reliable first")
+}
+
+func TestSignalAggregator_StoreAggregatedSignal(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+
+	sa := NewSignalAggregator(nil, database.NewMockDBPool(mockPool), zaplogrus.New())
+	signal := &AggregatedSignal{
+		ID:         "sig-1",
+		SignalType: SignalTypeExternal,
+		Symbol:     "BTC/USDT",
+		Action:     "buy",
+		Strength:   SignalStrengthMedium,
+		Confidence: decimal.NewFromFloat(0.6),
+		Exchanges:  []string{"binance"},
+		Indicators: []string{"source:quant-a"},
+		Metadata:   map[string]interface{}{"source": "quant-a"},
+		CreatedAt:  time.Now(),
+		ExpiresAt:  time.Now().Add(time.Minute),
+	}
+
+	mockPool.ExpectExec("INSERT INTO aggregated_signals").
+		WithArgs("sig-1", "external", "BTC/USDT", "buy", "medium", signal.Confidence, signal.ProfitPotential,
+			signal.RiskLevel, signal.Exchanges, signal.Indicators, pgxmock.AnyArg(), signal.CreatedAt, signal.ExpiresAt).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	require.NoError(t, sa.StoreAggregatedSignal(t.Context(), signal))
+	require.NoError(t, mockPool.ExpectationsWereMet())
+
+	assert.Error(t, NewSignalAggregator(nil, nil, zaplogrus.New()).StoreAggregatedSignal(t.Context(), signal))
+}
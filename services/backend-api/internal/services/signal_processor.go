@@ -11,6 +11,7 @@ import (
 	"github.com/getsentry/sentry-go"
 	"github.com/shopspring/decimal"
 
+	"github.com/irfndi/neuratrade/internal/cache"
 	"github.com/irfndi/neuratrade/internal/logging"
 	"github.com/irfndi/neuratrade/internal/models"
 	"github.com/irfndi/neuratrade/internal/observability"
@@ -44,6 +45,9 @@ type SignalProcessor struct {
 	notificationService *NotificationService
 	collectorService    *CollectorService
 	circuitBreaker      *CircuitBreaker
+	universe            SymbolUniverse
+	freshness           StaleSymbolSource
+	customAlerts        CustomAlertEvaluator
 
 	// Processing state
 	ctx        context.Context
@@ -136,6 +140,41 @@ func NewSignalProcessor(
 	}
 }
 
+// SetSymbolUniverse restricts processing to the active symbol universe.
+//
+// Parameters:
+//   - universe: Source of the active symbols.
+func (sp *SignalProcessor) SetSymbolUniverse(universe SymbolUniverse) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.universe = universe
+}
+
+// CustomAlertEvaluator evaluates user-defined alerts once per processing cycle.
+type CustomAlertEvaluator interface {
+	EvaluateAlerts(ctx context.Context) (int, error)
+}
+
+// SetFreshness excludes pairs whose market data breaches its freshness SLA.
+//
+// Parameters:
+//   - freshness: Source of the stale pairs.
+func (sp *SignalProcessor) SetFreshness(freshness StaleSymbolSource) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.freshness = freshness
+}
+
+// SetCustomAlerts evaluates user-defined alerts on every processing cycle.
+//
+// Parameters:
+//   - evaluator: The custom alert service.
+func (sp *SignalProcessor) SetCustomAlerts(evaluator CustomAlertEvaluator) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.customAlerts = evaluator
+}
+
 // Start begins the signal processing pipeline in a background goroutine.
 //
 // Returns:
@@ -345,6 +384,10 @@ func (sp *SignalProcessor) executeSignalBatch(ctx context.Context, startTime tim
 	timeoutCtx, cancel := context.WithTimeout(ctx, sp.config.TimeoutDuration)
 	defer cancel()
 
+	// User-defined alerts run every cycle, even when there is no market data
+	// to build signals from
+	sp.evaluateCustomAlerts(timeoutCtx)
+
 	// Get market data for processing
 	marketData, err := sp.getMarketDataForProcessingWithContext(timeoutCtx)
 	if err != nil {
@@ -399,6 +442,25 @@ func (sp *SignalProcessor) executeSignalBatch(ctx context.Context, startTime tim
 	return nil
 }
 
+// evaluateCustomAlerts runs the user-defined alerts; failures are logged so
+// they never fail the signal batch.
+func (sp *SignalProcessor) evaluateCustomAlerts(ctx context.Context) {
+	sp.mu.RLock()
+	evaluator := sp.customAlerts
+	sp.mu.RUnlock()
+	if evaluator == nil {
+		return
+	}
+	fired, err := evaluator.EvaluateAlerts(ctx)
+	if err != nil {
+		sp.logger.WithError(err).Warn("Failed to evaluate custom alerts")
+		return
+	}
+	if fired > 0 {
+		sp.logger.WithFields(map[string]interface{}{"fired": fired}).Info("Custom alerts triggered")
+	}
+}
+
 // isRetryableError determines if a given error warrants a retry attempt.
 func (sp *SignalProcessor) isRetryableError(err error) bool {
 	if err == nil {
@@ -743,6 +805,71 @@ func (sp *SignalProcessor) processSignal(data models.MarketData) ProcessingResul
 	return result
 }
 
+// ProcessExternalSignal scores and dispatches a signal produced outside the
+// pipeline (for example a TradingView alert). The quality scorer is used when
+// configured; otherwise the signal's own confidence is taken as its quality.
+//
+// Parameters:
+//   - ctx: Context for notification delivery.
+//   - signal: The signal to process.
+//
+// Returns:
+//   - The processing result.
+func (sp *SignalProcessor) ProcessExternalSignal(ctx context.Context, signal *AggregatedSignal) ProcessingResult {
+	startTime := time.Now()
+	result := ProcessingResult{
+		SignalID:   signal.ID,
+		SignalType: signal.SignalType,
+		Symbol:     signal.Symbol,
+		Metadata:   map[string]interface{}{"aggregated_signal": signal},
+	}
+
+	result.QualityScore = signal.Confidence.InexactFloat64()
+	if sp.qualityScorer != nil {
+		metrics, err := sp.qualityScorer.AssessSignalQuality(ctx, &SignalQualityInput{
+			SignalType: string(signal.SignalType),
+			Symbol:     signal.Symbol,
+			Exchanges:  sp.extractExchanges(signal),
+		})
+		if err != nil {
+			result.Error = fmt.Errorf("quality assessment failed: %w", err)
+		} else {
+			result.QualityScore = metrics.OverallScore.InexactFloat64()
+		}
+	}
+	result.Processed = result.Error == nil
+	result.ProcessingTime = time.Since(startTime)
+
+	results := []ProcessingResult{result}
+	if result.Processed {
+		notifiable := sp.collectNotifiableSignals(results)
+		result.NotificationSent = len(notifiable) > 0 && sp.config.NotificationEnabled && sp.notificationService != nil
+		_ = sp.handleProcessingResultsWithContext(ctx, results)
+	}
+	sp.recordExternalResult(result)
+
+	return result
+}
+
+func (sp *SignalProcessor) recordExternalResult(result ProcessingResult) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	sp.metrics.TotalSignalsProcessed++
+	switch {
+	case result.Error != nil:
+		sp.metrics.FailedSignals++
+	case result.QualityScore <= sp.config.QualityThreshold:
+		sp.metrics.SuccessfulSignals++
+		sp.metrics.QualityFilteredSignals++
+	default:
+		sp.metrics.SuccessfulSignals++
+	}
+	if result.NotificationSent {
+		sp.metrics.NotificationsSent++
+	}
+}
+
 // generateArbitrageSignals identifies potential arbitrage opportunities for a symbol.
 func (sp *SignalProcessor) generateArbitrageSignals(data models.MarketData) ([]ArbitrageSignalInput, error) {
 	// Get trading pair symbol
@@ -1039,13 +1166,85 @@ func (sp *SignalProcessor) metricsLoop() {
 	// Metric collection logic
 }
 
-// getActiveTradingPairs Mock/Stub
+// getActiveTradingPairs returns the exchange pairs with recent market data
+// whose symbol is in the active universe, minus the pairs breaching their
+// freshness SLA. Without a universe nothing is processed.
 func (sp *SignalProcessor) getActiveTradingPairs() ([]struct {
 	Symbol   string
 	Exchange struct{ Name string }
 }, error) {
-	return []struct {
+	pairs := []struct {
 		Symbol   string
 		Exchange struct{ Name string }
-	}{}, nil
+	}{}
+
+	sp.mu.RLock()
+	universe := sp.universe
+	freshness := sp.freshness
+	sp.mu.RUnlock()
+	if universe == nil {
+		return pairs, nil
+	}
+
+	ctx, cancel := context.WithTimeout(sp.ctx, sp.config.TimeoutDuration)
+	defer cancel()
+
+	symbols, err := universe.ActiveSymbols(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active symbols: %w", err)
+	}
+	if len(symbols) == 0 {
+		return pairs, nil
+	}
+	active := toSymbolSet(symbols)
+
+	// Stale pairs are skipped; without a freshness view every pair is used
+	stale := make(map[string]struct{})
+	if freshness != nil {
+		staleSymbols, err := freshness.StaleSymbols(ctx)
+		if err != nil {
+			sp.logger.WithError(err).Warn("Failed to load stale symbols, not excluding any")
+		}
+		for _, symbol := range staleSymbols {
+			stale[cache.PairKey(symbol.Exchange, strings.ToUpper(symbol.Symbol))] = struct{}{}
+		}
+	}
+
+	rows, err := sp.db.Query(ctx, `
+		SELECT DISTINCT tp.symbol, e.ccxt_id
+		FROM market_data md
+		JOIN trading_pairs tp ON md.trading_pair_id = tp.id
+		JOIN exchanges e ON md.exchange_id = e.id
+		WHERE md.timestamp >= $1
+	`, time.Now().Add(-time.Hour))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trading pairs: %w", err)
+	}
+	defer rows.Close()
+
+	excluded := 0
+	for rows.Next() {
+		var pair struct {
+			Symbol   string
+			Exchange struct{ Name string }
+		}
+		if err := rows.Scan(&pair.Symbol, &pair.Exchange.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan trading pair: %w", err)
+		}
+		if _, ok := active[strings.ToUpper(pair.Symbol)]; !ok {
+			continue
+		}
+		if _, ok := stale[cache.PairKey(pair.Exchange.Name, strings.ToUpper(pair.Symbol))]; ok {
+			excluded++
+			continue
+		}
+		pairs = append(pairs, pair)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating trading pairs: %w", err)
+	}
+	if excluded > 0 {
+		sp.logger.WithFields(map[string]interface{}{"excluded": excluded}).Info("Skipped pairs with stale market data")
+	}
+	return pairs, nil
 }
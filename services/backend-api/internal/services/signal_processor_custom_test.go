@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -116,3 +117,71 @@ func TestSignalProcessor_ProcessSignal(t *testing.T) {
 	mockAggregator.AssertExpectations(t)
 	mockScorer.AssertExpectations(t)
 }
+
+type staticUniverse []string
+
+func (u staticUniverse) ActiveSymbols(ctx context.Context) ([]string, error) { return u, nil }
+
+func (u staticUniverse) ChatSymbols(ctx context.Context, chatID string) ([]string, error) {
+	return u, nil
+}
+
+func TestSignalProcessor_ActiveTradingPairsFollowUniverse(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer mockPool.Close()
+
+	sp := NewSignalProcessor(database.NewMockDBPool(mockPool), logging.NewStandardLogger("info", "test"), nil, nil, nil, nil, nil, nil)
+
+	// Without a universe nothing is processed and the database is not queried.
+	pairs, err := sp.getActiveTradingPairs()
+	assert.NoError(t, err)
+	assert.Empty(t, pairs)
+
+	sp.SetSymbolUniverse(staticUniverse{"BTC/USDT"})
+	mockPool.ExpectQuery("SELECT DISTINCT tp.symbol, e.ccxt_id").
+		WithArgs(pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"symbol", "ccxt_id"}).
+			AddRow("BTC/USDT", "binance").
+			AddRow("DOGE/USDT", "binance").
+			AddRow("BTC/USDT", "kraken"))
+
+	pairs, err = sp.getActiveTradingPairs()
+	assert.NoError(t, err)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+	if assert.Len(t, pairs, 2) {
+		assert.Equal(t, "binance", pairs[0].Exchange.Name)
+		assert.Equal(t, "kraken", pairs[1].Exchange.Name)
+	}
+}
+
+func TestSignalProcessor_ActiveTradingPairsSkipStaleSymbols(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer mockPool.Close()
+
+	sp := NewSignalProcessor(database.NewMockDBPool(mockPool), logging.NewStandardLogger("info", "test"), nil, nil, nil, nil, nil, nil)
+	sp.SetSymbolUniverse(staticUniverse{"BTC/USDT"})
+
+	freshness := NewSymbolFreshnessTracker(nil, SymbolFreshnessConfig{TickerSLA: time.Minute})
+	freshness.RecordTickers(context.Background(), "binance", []models.MarketPrice{{Symbol: "BTC/USDT", Timestamp: time.Now().Add(-time.Hour)}})
+	freshness.RecordTickers(context.Background(), "kraken", []models.MarketPrice{{Symbol: "BTC/USDT", Timestamp: time.Now()}})
+	sp.SetFreshness(freshness)
+
+	mockPool.ExpectQuery("SELECT DISTINCT tp.symbol, e.ccxt_id").
+		WithArgs(pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"symbol", "ccxt_id"}).
+			AddRow("BTC/USDT", "binance").
+			AddRow("BTC/USDT", "kraken"))
+
+	pairs, err := sp.getActiveTradingPairs()
+	assert.NoError(t, err)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+	if assert.Len(t, pairs, 1) {
+		assert.Equal(t, "kraken", pairs[0].Exchange.Name)
+	}
+}
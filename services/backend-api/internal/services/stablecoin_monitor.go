@@ -0,0 +1,459 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	stablecoinStatusKey = "stablecoin:status"
+
+	defaultStablecoinDepegThreshold  = 0.02
+	defaultStablecoinCheckInterval   = time.Minute
+	defaultStablecoinConvertExchange = "binance"
+)
+
+// DefaultStablecoinProxies prices USDT, USDC and DAI against USD markets.
+var DefaultStablecoinProxies = []StablecoinProxy{
+	{Stablecoin: "USDT", Exchange: "kraken", Symbol: "USDT/USD"},
+	{Stablecoin: "USDC", Exchange: "kraken", Symbol: "USDC/USD"},
+	{Stablecoin: "DAI", Exchange: "kraken", Symbol: "DAI/USD"},
+}
+
+// TickerFetcher fetches one ticker.
+type TickerFetcher interface {
+	FetchSingleTicker(ctx context.Context, exchange, symbol string) (ccxt.MarketPriceInterface, error)
+}
+
+// StablecoinBalanceFetcher fetches exchange balances for defensive conversions.
+type StablecoinBalanceFetcher interface {
+	FetchBalance(ctx context.Context, exchange string) (*ccxt.BalanceResponse, error)
+}
+
+// StablecoinOrderPlacer places the orders that convert a depegged balance.
+type StablecoinOrderPlacer interface {
+	PlaceOrder(ctx context.Context, exchange, symbol, side, orderType string, amount decimal.Decimal, price *decimal.Decimal) (string, error)
+}
+
+// StablecoinGuard reports whether a symbol trades against a depegged
+// stablecoin and strategies using it must pause.
+type StablecoinGuard interface {
+	// DepeggedStablecoin returns the depegged stablecoin the symbol uses, if any.
+	DepeggedStablecoin(ctx context.Context, symbol string) (string, bool)
+}
+
+// StablecoinProxy is a market that prices a stablecoin in USD.
+type StablecoinProxy struct {
+	Stablecoin string `json:"stablecoin"`
+	Exchange   string `json:"exchange"`
+	Symbol     string `json:"symbol"`
+	// Inverse marks markets quoted as USD per stablecoin inverted, such as "USD/USDT".
+	Inverse bool `json:"inverse,omitempty"`
+}
+
+// StablecoinStatus is the latest peg check of one stablecoin.
+type StablecoinStatus struct {
+	Stablecoin string  `json:"stablecoin"`
+	Price      float64 `json:"price"`
+	// Deviation is |price - 1|.
+	Deviation     float64    `json:"deviation"`
+	Depegged      bool       `json:"depegged"`
+	Sources       []string   `json:"sources"`
+	CheckedAt     time.Time  `json:"checked_at"`
+	DepeggedSince *time.Time `json:"depegged_since,omitempty"`
+}
+
+// StablecoinMonitorConfig configures depeg detection and the defensive response.
+type StablecoinMonitorConfig struct {
+	// Proxies are the markets used to price each stablecoin; a stablecoin with
+	// several proxies uses the average of the prices that could be fetched.
+	Proxies []StablecoinProxy
+	// Threshold is the deviation from 1 USD that counts as a depeg. A depeg
+	// ends once the deviation falls below half the threshold.
+	Threshold float64
+	// Interval is how often prices are checked.
+	Interval time.Duration
+	// PauseStrategies pauses strategies trading symbols that use a depegged stablecoin.
+	PauseStrategies bool
+	// ConvertBalances sells the free balance of a depegged stablecoin on
+	// ConvertExchange into the first stablecoin that is still pegged.
+	ConvertBalances bool
+	// ConvertExchange is the exchange whose balances are converted.
+	ConvertExchange string
+}
+
+// StablecoinMonitor checks stablecoin prices against USD proxies and raises a
+// critical risk event when one depegs, and again when it recovers.
+type StablecoinMonitor struct {
+	tickers  TickerFetcher
+	redis    *redis.Client
+	config   StablecoinMonitorConfig
+	balances StablecoinBalanceFetcher
+	orders   StablecoinOrderPlacer
+	events   EventEmitter
+	logger   *slog.Logger
+	now      func() time.Time
+	runMu    sync.Mutex
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// Ensure StablecoinMonitor implements StablecoinGuard.
+var _ StablecoinGuard = (*StablecoinMonitor)(nil)
+
+// NewStablecoinMonitor creates a stablecoin depeg monitor.
+//
+// Parameters:
+//
+//	tickers: Source of proxy prices, such as the CCXT service.
+//	client: Redis client used to persist the peg status.
+//	config: Monitor configuration; zero values use defaults.
+//
+// Returns:
+//
+//	*StablecoinMonitor: Initialized monitor (checks not started).
+func NewStablecoinMonitor(tickers TickerFetcher, client *redis.Client, config StablecoinMonitorConfig) *StablecoinMonitor {
+	if len(config.Proxies) == 0 {
+		config.Proxies = DefaultStablecoinProxies
+	}
+	if config.Threshold <= 0 {
+		config.Threshold = defaultStablecoinDepegThreshold
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaultStablecoinCheckInterval
+	}
+	if config.ConvertExchange == "" {
+		config.ConvertExchange = defaultStablecoinConvertExchange
+	}
+	return &StablecoinMonitor{
+		tickers: tickers,
+		redis:   client,
+		config:  config,
+		logger:  telemetry.Logger(),
+		now:     time.Now,
+	}
+}
+
+// SetEventEmitter publishes depeg and recovery risk events.
+func (m *StablecoinMonitor) SetEventEmitter(events EventEmitter) {
+	m.events = events
+}
+
+// SetConverter sets the balance source and order executor used when
+// ConvertBalances is enabled.
+func (m *StablecoinMonitor) SetConverter(balances StablecoinBalanceFetcher, orders StablecoinOrderPlacer) {
+	m.balances = balances
+	m.orders = orders
+}
+
+// Start checks the pegs now and then once per interval until Stop is called.
+func (m *StablecoinMonitor) Start(ctx context.Context) error {
+	m.runMu.Lock()
+	defer m.runMu.Unlock()
+	if m.cancel != nil {
+		return fmt.Errorf("stablecoin monitor already running")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	m.cancel = cancel
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		m.check(ctx)
+		ticker := time.NewTicker(m.config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.check(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the checks and waits for the running check to finish.
+func (m *StablecoinMonitor) Stop() {
+	m.runMu.Lock()
+	cancel := m.cancel
+	m.cancel = nil
+	m.runMu.Unlock()
+	if cancel != nil {
+		cancel()
+		m.wg.Wait()
+	}
+}
+
+func (m *StablecoinMonitor) check(ctx context.Context) {
+	if _, err := m.Check(ctx); err != nil {
+		m.logger.Warn("Stablecoin peg check failed", "error", err)
+	}
+}
+
+// Check prices every stablecoin, stores the result and responds to pegs
+// that broke or recovered since the previous check. A stablecoin none of
+// whose proxies could be priced keeps its previous status.
+//
+// Parameters:
+//
+//	ctx: Context for the check.
+//
+// Returns:
+//
+//	[]StablecoinStatus: The status of every stablecoin, sorted by name.
+//	error: Error if the status could not be read or saved.
+func (m *StablecoinMonitor) Check(ctx context.Context) ([]StablecoinStatus, error) {
+	previous, err := m.loadStatuses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make(map[string][]float64)
+	sources := make(map[string][]string)
+	var order []string
+	for _, proxy := range m.config.Proxies {
+		stable := strings.ToUpper(proxy.Stablecoin)
+		if _, ok := prices[stable]; !ok {
+			prices[stable] = nil
+			order = append(order, stable)
+		}
+		price, err := m.proxyPrice(ctx, proxy)
+		if err != nil {
+			m.logger.Warn("Failed to price stablecoin proxy",
+				"stablecoin", stable, "exchange", proxy.Exchange, "symbol", proxy.Symbol, "error", err)
+			continue
+		}
+		prices[stable] = append(prices[stable], price)
+		sources[stable] = append(sources[stable], proxy.Exchange+":"+proxy.Symbol)
+	}
+
+	now := m.now().UTC()
+	var changed []StablecoinStatus
+	for _, stable := range order {
+		if len(prices[stable]) == 0 {
+			continue
+		}
+		var sum float64
+		for _, price := range prices[stable] {
+			sum += price
+		}
+		status := StablecoinStatus{
+			Stablecoin: stable,
+			Price:      sum / float64(len(prices[stable])),
+			Sources:    sources[stable],
+			CheckedAt:  now,
+		}
+		status.Deviation = math.Abs(status.Price - 1)
+
+		last, seen := previous[stable]
+		wasDepegged := seen && last.Depegged
+		switch {
+		case status.Deviation >= m.config.Threshold:
+			status.Depegged = true
+		case wasDepegged && status.Deviation >= m.config.Threshold/2:
+			// Stay depegged until the price is well inside the band again.
+			status.Depegged = true
+		}
+		if status.Depegged {
+			since := now
+			if wasDepegged && last.DepeggedSince != nil {
+				since = *last.DepeggedSince
+			}
+			status.DepeggedSince = &since
+		}
+
+		raw, err := json.Marshal(status)
+		if err != nil {
+			return nil, err
+		}
+		if err := m.redis.HSet(ctx, stablecoinStatusKey, stable, raw).Err(); err != nil {
+			return nil, fmt.Errorf("failed to save stablecoin status: %w", err)
+		}
+		previous[stable] = status
+		if status.Depegged != wasDepegged {
+			changed = append(changed, status)
+		}
+	}
+
+	for _, status := range changed {
+		m.respond(ctx, status, previous)
+	}
+	return sortedStablecoinStatuses(previous), nil
+}
+
+func (m *StablecoinMonitor) proxyPrice(ctx context.Context, proxy StablecoinProxy) (float64, error) {
+	ticker, err := m.tickers.FetchSingleTicker(ctx, proxy.Exchange, proxy.Symbol)
+	if err != nil {
+		return 0, err
+	}
+	if ticker == nil {
+		return 0, fmt.Errorf("no ticker returned")
+	}
+	price := ticker.GetPrice()
+	if price <= 0 {
+		return 0, fmt.Errorf("invalid price %f", price)
+	}
+	if proxy.Inverse {
+		price = 1 / price
+	}
+	return price, nil
+}
+
+// respond raises the risk event for a peg change and, on a depeg, runs the
+// configured defensive actions.
+func (m *StablecoinMonitor) respond(ctx context.Context, status StablecoinStatus, statuses map[string]StablecoinStatus) {
+	data := map[string]interface{}{
+		"stablecoin": status.Stablecoin,
+		"price":      status.Price,
+		"deviation":  status.Deviation,
+		"threshold":  m.config.Threshold,
+		"sources":    status.Sources,
+	}
+	if !status.Depegged {
+		m.logger.Info("Stablecoin peg recovered", "stablecoin", status.Stablecoin, "price", status.Price)
+		data["type"] = "stablecoin_repeg"
+		data["severity"] = "info"
+		m.emit(ctx, data)
+		return
+	}
+
+	m.logger.Error("Stablecoin depeg detected",
+		"stablecoin", status.Stablecoin,
+		"price", status.Price,
+		"deviation", status.Deviation,
+		"threshold", m.config.Threshold)
+	data["type"] = "stablecoin_depeg"
+	data["severity"] = "critical"
+	data["strategies_paused"] = m.config.PauseStrategies
+
+	if m.config.ConvertBalances {
+		orderID, target, err := m.convert(ctx, status.Stablecoin, statuses)
+		switch {
+		case err != nil:
+			m.logger.Error("Failed to convert depegged stablecoin balance",
+				"stablecoin", status.Stablecoin, "exchange", m.config.ConvertExchange, "error", err)
+			data["conversion_error"] = err.Error()
+		case orderID != "":
+			m.logger.Warn("Converted depegged stablecoin balance",
+				"stablecoin", status.Stablecoin, "target", target, "order_id", orderID)
+			data["conversion_order_id"] = orderID
+			data["conversion_target"] = target
+		}
+	}
+	m.emit(ctx, data)
+}
+
+func (m *StablecoinMonitor) emit(ctx context.Context, data map[string]interface{}) {
+	if m.events != nil {
+		m.events.Emit(ctx, WebhookEventRisk, data)
+	}
+}
+
+// convert sells the free balance of a depegged stablecoin into the first
+// configured stablecoin that is still pegged. It returns an empty order ID
+// when there is nothing to convert.
+func (m *StablecoinMonitor) convert(ctx context.Context, stable string, statuses map[string]StablecoinStatus) (string, string, error) {
+	if m.balances == nil || m.orders == nil {
+		return "", "", fmt.Errorf("no balance source or order executor configured")
+	}
+
+	var target string
+	for _, proxy := range m.config.Proxies {
+		candidate := strings.ToUpper(proxy.Stablecoin)
+		if status, ok := statuses[candidate]; ok && candidate != stable && !status.Depegged {
+			target = candidate
+			break
+		}
+	}
+	if target == "" {
+		return "", "", fmt.Errorf("no pegged stablecoin to convert %s into", stable)
+	}
+
+	balance, err := m.balances.FetchBalance(ctx, m.config.ConvertExchange)
+	if err != nil {
+		return "", target, fmt.Errorf("failed to fetch balance: %w", err)
+	}
+	free := balance.Free[stable]
+	if free <= 0 {
+		return "", target, nil
+	}
+
+	symbol := stable + "/" + target
+	orderID, err := m.orders.PlaceOrder(ctx, m.config.ConvertExchange, symbol, "sell", "market", decimal.NewFromFloat(free), nil)
+	if err != nil {
+		return "", target, fmt.Errorf("failed to sell %s: %w", symbol, err)
+	}
+	return orderID, target, nil
+}
+
+// Statuses returns the latest status of every stablecoin, sorted by name.
+func (m *StablecoinMonitor) Statuses(ctx context.Context) ([]StablecoinStatus, error) {
+	statuses, err := m.loadStatuses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sortedStablecoinStatuses(statuses), nil
+}
+
+// DepeggedStablecoin returns the depegged stablecoin a symbol trades against
+// when PauseStrategies is enabled.
+func (m *StablecoinMonitor) DepeggedStablecoin(ctx context.Context, symbol string) (string, bool) {
+	if !m.config.PauseStrategies {
+		return "", false
+	}
+	statuses, err := m.loadStatuses(ctx)
+	if err != nil {
+		m.logger.Warn("Failed to load stablecoin status", "error", err)
+		return "", false
+	}
+	base, quote, ok := strings.Cut(normalizeSymbolForComparison(symbol), "/")
+	if !ok {
+		return "", false
+	}
+	for _, asset := range []string{quote, base} {
+		if status, ok := statuses[asset]; ok && status.Depegged {
+			return asset, true
+		}
+	}
+	return "", false
+}
+
+func (m *StablecoinMonitor) loadStatuses(ctx context.Context) (map[string]StablecoinStatus, error) {
+	raw, err := m.redis.HGetAll(ctx, stablecoinStatusKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load stablecoin status: %w", err)
+	}
+	statuses := make(map[string]StablecoinStatus, len(raw))
+	for stable, value := range raw {
+		var status StablecoinStatus
+		if err := json.Unmarshal([]byte(value), &status); err != nil {
+			m.logger.Warn("Skipping corrupt stablecoin status", "stablecoin", stable, "error", err)
+			continue
+		}
+		statuses[stable] = status
+	}
+	return statuses, nil
+}
+
+func sortedStablecoinStatuses(statuses map[string]StablecoinStatus) []StablecoinStatus {
+	result := make([]StablecoinStatus, 0, len(statuses))
+	for _, status := range statuses {
+		result = append(result, status)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Stablecoin < result[j].Stablecoin })
+	return result
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/irfndi/neuratrade/internal/models"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTickerFetcher struct {
+	mu     sync.Mutex
+	prices map[string]float64
+}
+
+func (f *fakeTickerFetcher) FetchSingleTicker(_ context.Context, exchange, symbol string) (ccxt.MarketPriceInterface, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	price, ok := f.prices[exchange+":"+symbol]
+	if !ok {
+		return nil, errors.New("ticker unavailable")
+	}
+	return &models.MarketPrice{ExchangeName: exchange, Symbol: symbol, Price: decimal.NewFromFloat(price)}, nil
+}
+
+func (f *fakeTickerFetcher) set(market string, price float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.prices[market] = price
+}
+
+type fakeBalanceFetcher struct {
+	free map[string]float64
+}
+
+func (f *fakeBalanceFetcher) FetchBalance(_ context.Context, exchange string) (*ccxt.BalanceResponse, error) {
+	return &ccxt.BalanceResponse{Exchange: exchange, Free: f.free}, nil
+}
+
+func newTestStablecoinMonitor(t *testing.T, tickers TickerFetcher, config StablecoinMonitorConfig) *StablecoinMonitor {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewStablecoinMonitor(tickers, client, config)
+}
+
+func TestStablecoinMonitor_DepegAndRecovery(t *testing.T) {
+	tickers := &fakeTickerFetcher{prices: map[string]float64{
+		"kraken:USDT/USD":   1.0,
+		"kraken:USDC/USD":   0.999,
+		"bitstamp:USD/USDC": 1.0,
+	}}
+	monitor := newTestStablecoinMonitor(t, tickers, StablecoinMonitorConfig{
+		Proxies: []StablecoinProxy{
+			{Stablecoin: "USDT", Exchange: "kraken", Symbol: "USDT/USD"},
+			{Stablecoin: "USDC", Exchange: "kraken", Symbol: "USDC/USD"},
+			{Stablecoin: "USDC", Exchange: "bitstamp", Symbol: "USD/USDC", Inverse: true},
+			{Stablecoin: "DAI", Exchange: "kraken", Symbol: "DAI/USD"},
+		},
+		PauseStrategies: true,
+	})
+	emitter := &recordingEmitter{}
+	monitor.SetEventEmitter(emitter)
+	ctx := t.Context()
+
+	statuses, err := monitor.Check(ctx)
+	require.NoError(t, err)
+	// DAI has no price and is left out.
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "USDC", statuses[0].Stablecoin)
+	assert.InDelta(t, 0.9995, statuses[0].Price, 1e-9)
+	assert.Equal(t, []string{"kraken:USDC/USD", "bitstamp:USD/USDC"}, statuses[0].Sources)
+	assert.False(t, statuses[0].Depegged)
+	assert.Empty(t, emitter.events)
+
+	// 0.90 and 1/1.05 average to about 0.926, well past the 2% threshold.
+	tickers.set("kraken:USDC/USD", 0.90)
+	tickers.set("bitstamp:USD/USDC", 1.05)
+	statuses, err = monitor.Check(ctx)
+	require.NoError(t, err)
+	assert.True(t, statuses[0].Depegged)
+	require.NotNil(t, statuses[0].DepeggedSince)
+	assert.Equal(t, []WebhookEventType{WebhookEventRisk}, emitter.events)
+
+	stable, paused := monitor.DepeggedStablecoin(ctx, "ETH/USDC:USDC")
+	assert.True(t, paused)
+	assert.Equal(t, "USDC", stable)
+	_, paused = monitor.DepeggedStablecoin(ctx, "USDC/USDT")
+	assert.True(t, paused, "a depegged base also pauses the symbol")
+	_, paused = monitor.DepeggedStablecoin(ctx, "BTC/USDT")
+	assert.False(t, paused)
+
+	// Inside the threshold but outside half of it: still depegged, no new event.
+	tickers.set("kraken:USDC/USD", 0.985)
+	tickers.set("bitstamp:USD/USDC", 1/0.985)
+	statuses, err = monitor.Check(ctx)
+	require.NoError(t, err)
+	assert.True(t, statuses[0].Depegged)
+	assert.Len(t, emitter.events, 1)
+
+	tickers.set("kraken:USDC/USD", 0.998)
+	tickers.set("bitstamp:USD/USDC", 1/0.998)
+	statuses, err = monitor.Check(ctx)
+	require.NoError(t, err)
+	assert.False(t, statuses[0].Depegged)
+	assert.Nil(t, statuses[0].DepeggedSince)
+	assert.Len(t, emitter.events, 2)
+	_, paused = monitor.DepeggedStablecoin(ctx, "ETH/USDC")
+	assert.False(t, paused)
+}
+
+func TestStablecoinMonitor_ConvertsDepeggedBalance(t *testing.T) {
+	tickers := &fakeTickerFetcher{prices: map[string]float64{
+		"kraken:USDT/USD": 1.0,
+		"kraken:USDC/USD": 1.0,
+	}}
+	monitor := newTestStablecoinMonitor(t, tickers, StablecoinMonitorConfig{
+		Proxies:         DefaultStablecoinProxies[:2],
+		ConvertBalances: true,
+	})
+	orders := &fakeOrderPlacer{}
+	monitor.SetConverter(&fakeBalanceFetcher{free: map[string]float64{"USDT": 250}}, orders)
+	ctx := t.Context()
+
+	_, err := monitor.Check(ctx)
+	require.NoError(t, err)
+	tickers.set("kraken:USDT/USD", 0.95)
+	_, err = monitor.Check(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"binance:sell:250"}, orders.orders)
+
+	// Pausing is off, so strategies keep trading.
+	_, paused := monitor.DepeggedStablecoin(ctx, "BTC/USDT")
+	assert.False(t, paused)
+}
+
+func TestStablecoinMonitor_StartStop(t *testing.T) {
+	monitor := newTestStablecoinMonitor(t, &fakeTickerFetcher{prices: map[string]float64{}}, StablecoinMonitorConfig{})
+
+	require.NoError(t, monitor.Start(t.Context()))
+	assert.Error(t, monitor.Start(t.Context()))
+	monitor.Stop()
+	monitor.Stop()
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/redis/go-redis/v9"
+)
+
+const startupGuardKey = "system:running_since"
+
+// StartupGuardConfig configures the start-up safety gate.
+type StartupGuardConfig struct {
+	// RequireResume holds autonomous trading after an unclean shutdown until
+	// the operator sends /resume. When false trading resumes on its own and
+	// the operator is only informed.
+	RequireResume bool
+}
+
+// UncleanShutdown describes a previous run that ended without a graceful
+// shutdown.
+type UncleanShutdown struct {
+	// StartedAt is when the crashed run started.
+	StartedAt time.Time `json:"started_at"`
+	// DetectedAt is when this run found the dirty flag.
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// StartupGuard detects unclean shutdowns with a dirty flag that is set on
+// start and cleared on graceful shutdown. A flag still present on start
+// means the previous run crashed or was killed.
+type StartupGuard struct {
+	redis     *redis.Client
+	config    StartupGuardConfig
+	messenger DirectMessenger
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+// NewStartupGuard creates the start-up safety gate.
+//
+// Parameters:
+//
+//	client: Redis client holding the dirty flag.
+//	config: Gate policy.
+//
+// Returns:
+//
+//	*StartupGuard: Initialized guard.
+func NewStartupGuard(client *redis.Client, config StartupGuardConfig) *StartupGuard {
+	return &StartupGuard{redis: client, config: config, logger: telemetry.Logger(), now: time.Now}
+}
+
+// SetMessenger sends the unclean shutdown summary to the operator on Telegram.
+func (g *StartupGuard) SetMessenger(messenger DirectMessenger) {
+	g.messenger = messenger
+}
+
+// Config returns the gate policy.
+func (g *StartupGuard) Config() StartupGuardConfig {
+	return g.config
+}
+
+// Open checks for a dirty flag left by the previous run and sets one for
+// this run. It returns the unclean shutdown it found, or nil after a
+// graceful shutdown or on first start.
+func (g *StartupGuard) Open(ctx context.Context) (*UncleanShutdown, error) {
+	now := g.now().UTC()
+	previous, err := g.redis.GetSet(ctx, startupGuardKey, now.Format(time.RFC3339)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check dirty flag: %w", err)
+	}
+	unclean := &UncleanShutdown{DetectedAt: now}
+	if startedAt, err := time.Parse(time.RFC3339, previous); err == nil {
+		unclean.StartedAt = startedAt
+	}
+	g.logger.Warn("Previous run ended without a graceful shutdown", "started_at", previous)
+	return unclean, nil
+}
+
+// Close clears the dirty flag on graceful shutdown.
+func (g *StartupGuard) Close(ctx context.Context) error {
+	if err := g.redis.Del(ctx, startupGuardKey).Err(); err != nil {
+		return fmt.Errorf("failed to clear dirty flag: %w", err)
+	}
+	return nil
+}
+
+// Notify sends the unclean shutdown summary to each operator chat.
+//
+// Parameters:
+//
+//	ctx: Context for sending.
+//	chatIDs: Operator chats with autonomous mode enabled.
+//	unclean: The detected unclean shutdown.
+//	reconciled: Decisions settled by startup reconciliation.
+func (g *StartupGuard) Notify(ctx context.Context, chatIDs []string, unclean *UncleanShutdown, reconciled []DecisionIntent) {
+	if g.messenger == nil || unclean == nil {
+		return
+	}
+	text := FormatUncleanShutdownSummary(unclean, reconciled, g.config.RequireResume)
+	for _, id := range chatIDs {
+		chatID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			continue
+		}
+		if err := g.messenger.SendDirectMessage(ctx, chatID, text); err != nil {
+			g.logger.Warn("Failed to send unclean shutdown summary", "chat_id", chatID, "error", err)
+		}
+	}
+}
+
+// FormatUncleanShutdownSummary renders the Telegram summary of an unclean
+// shutdown and the state reconciled after it.
+func FormatUncleanShutdownSummary(unclean *UncleanShutdown, reconciled []DecisionIntent, held bool) string {
+	var b strings.Builder
+	b.WriteString("⚠️ NeuraTrade restarted after an unclean shutdown")
+	if !unclean.StartedAt.IsZero() {
+		fmt.Fprintf(&b, " (previous run started %s UTC)", unclean.StartedAt.UTC().Format("2006-01-02 15:04"))
+	}
+	b.WriteString(".\n\n")
+
+	if len(reconciled) == 0 {
+		b.WriteString("No decisions were mid-execution.\n")
+	} else {
+		fmt.Fprintf(&b, "Reconciled %d interrupted decision(s):\n", len(reconciled))
+		for _, intent := range reconciled {
+			legs := make([]string, 0, len(intent.Orders))
+			for _, order := range intent.Orders {
+				legs = append(legs, fmt.Sprintf("%s %s on %s: %s", order.Side, order.Symbol, order.Exchange, order.Status))
+			}
+			fmt.Fprintf(&b, "• %s: %s\n  %s\n", intent.Strategy, intent.Note, strings.Join(legs, "; "))
+		}
+	}
+
+	if held {
+		b.WriteString("\n⏸️ Autonomous trading is paused. Review positions, then send /resume to continue.")
+	} else {
+		b.WriteString("\n▶️ Autonomous trading resumed automatically.")
+	}
+	return b.String()
+}
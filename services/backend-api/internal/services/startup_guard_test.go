@@ -0,0 +1,72 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStartupGuard(t *testing.T, config StartupGuardConfig) *StartupGuard {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	guard := NewStartupGuard(client, config)
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	guard.now = func() time.Time { return now }
+	return guard
+}
+
+func TestStartupGuard_DetectsUncleanShutdown(t *testing.T) {
+	guard := newTestStartupGuard(t, StartupGuardConfig{RequireResume: true})
+	ctx := t.Context()
+
+	unclean, err := guard.Open(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, unclean, "first start")
+
+	// The process died without clearing the flag
+	guard.now = func() time.Time { return time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC) }
+	unclean, err = guard.Open(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, unclean)
+	assert.Equal(t, time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), unclean.StartedAt)
+
+	require.NoError(t, guard.Close(ctx))
+	unclean, err = guard.Open(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, unclean, "graceful shutdown clears the flag")
+}
+
+func TestStartupGuard_Notify(t *testing.T) {
+	guard := newTestStartupGuard(t, StartupGuardConfig{RequireResume: true})
+	messenger := &recordingMessenger{}
+	guard.SetMessenger(messenger)
+	unclean := &UncleanShutdown{StartedAt: time.Date(2026, 3, 1, 8, 30, 0, 0, time.UTC)}
+	reconciled := []DecisionIntent{{
+		Strategy: StrategyArbitrage,
+		Note:     "1 of 2 orders placed, 1 abandoned, 1 resting orders cancelled",
+		Orders: []IntentOrder{
+			{Exchange: "binance", Symbol: "BTC/USDT", Side: "buy", Status: IntentOrderCancelled},
+			{Exchange: "bybit", Symbol: "BTC/USDT", Side: "sell", Status: IntentOrderAbandoned},
+		},
+	}}
+
+	guard.Notify(t.Context(), []string{"42", "not-a-chat"}, unclean, reconciled)
+	require.Equal(t, []int64{42}, messenger.chats)
+	text := messenger.texts[0]
+	assert.Contains(t, text, "previous run started 2026-03-01 08:30 UTC")
+	assert.Contains(t, text, "Reconciled 1 interrupted decision(s)")
+	assert.Contains(t, text, "buy BTC/USDT on binance: cancelled; sell BTC/USDT on bybit: abandoned")
+	assert.Contains(t, text, "send /resume")
+}
+
+func TestFormatUncleanShutdownSummary_AutoResume(t *testing.T) {
+	text := FormatUncleanShutdownSummary(&UncleanShutdown{}, nil, false)
+	assert.Contains(t, text, "No decisions were mid-execution")
+	assert.Contains(t, text, "resumed automatically")
+	assert.NotContains(t, text, "previous run started")
+}
@@ -0,0 +1,409 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/jackc/pgx/v5"
+)
+
+// Parts of the trading state compared by a state diff.
+const (
+	StateKindConfig   = "config"
+	StateKindStrategy = "strategy"
+	StateKindPrompt   = "prompt_version"
+	StateKindModel    = "model"
+)
+
+// ErrStateFingerprintNotFound is returned when no decision with a state
+// fingerprint was recorded at or before the requested time.
+var ErrStateFingerprintNotFound = errors.New("no state fingerprint recorded")
+
+// StateSource returns the current settings of one part of the trading
+// state. Secrets must be redacted: the settings are stored for diffs.
+type StateSource func(ctx context.Context) (map[string]interface{}, error)
+
+// StateStamper fingerprints the state a decision is made under. It is
+// implemented by StateFingerprintService.
+type StateStamper interface {
+	Stamp(ctx context.Context, audit *DecisionAudit) error
+}
+
+// StateFingerprint identifies the configuration, strategy parameters, prompt
+// and model behind a decision.
+type StateFingerprint struct {
+	// DecisionID is the decision the fingerprint was recorded with; empty
+	// for the live state.
+	DecisionID    string    `json:"decision_id,omitempty"`
+	ConfigHash    string    `json:"config_hash"`
+	StrategyHash  string    `json:"strategy_hash"`
+	PromptVersion string    `json:"prompt_version"`
+	Model         string    `json:"model"`
+	At            time.Time `json:"at"`
+}
+
+// StateChange is one difference between two fingerprints. Key is the
+// changed setting of a config or strategy change; it is empty, and From and
+// To are the hashes, when the settings behind a hash were not stored.
+type StateChange struct {
+	Kind string `json:"kind"`
+	Key  string `json:"key,omitempty"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// StateDiff compares the state behind decisions at two times.
+type StateDiff struct {
+	From    StateFingerprint `json:"from"`
+	To      StateFingerprint `json:"to"`
+	Changed bool             `json:"changed"`
+	Changes []StateChange    `json:"changes"`
+}
+
+// stateSnapshot is the flattened settings of one part of the state.
+type stateSnapshot struct {
+	hash     string
+	settings map[string]string
+}
+
+// StateFingerprintService stamps decisions with the hashes of the state they
+// were made under and diffs the state between two times.
+type StateFingerprintService struct {
+	db         DBPool
+	config     StateSource
+	strategies map[string]StateSource
+	logger     *slog.Logger
+	now        func() time.Time
+
+	// stored holds the snapshot hashes already written by this process.
+	mu     sync.Mutex
+	stored map[string]bool
+}
+
+// Ensure StateFingerprintService implements StateStamper.
+var _ StateStamper = (*StateFingerprintService)(nil)
+
+// NewStateFingerprintService creates a state fingerprint service.
+//
+// Parameters:
+//
+//	db: Database pool holding decision_audits and state_snapshots.
+//	config: Source of the effective configuration (may be nil).
+//
+// Returns:
+//
+//	*StateFingerprintService: Initialized service.
+func NewStateFingerprintService(db DBPool, config StateSource) *StateFingerprintService {
+	return &StateFingerprintService{
+		db:         db,
+		config:     config,
+		strategies: make(map[string]StateSource),
+		logger:     telemetry.Logger(),
+		now:        time.Now,
+		stored:     make(map[string]bool),
+	}
+}
+
+// AddStrategySource includes a strategy's parameters in the strategy hash,
+// under keys prefixed with its name.
+func (s *StateFingerprintService) AddStrategySource(name string, source StateSource) {
+	s.strategies[name] = source
+}
+
+// Stamp sets the config and strategy hashes of a decision about to be
+// recorded and stores the settings behind them.
+//
+// Parameters:
+//
+//	ctx: Context for the sources and the snapshot inserts.
+//	audit: The decision; its prompt version and model are set by the strategy.
+//
+// Returns:
+//
+//	error: Error if the state could not be read or stored.
+func (s *StateFingerprintService) Stamp(ctx context.Context, audit *DecisionAudit) error {
+	config, strategy, err := s.capture(ctx)
+	if err != nil {
+		return err
+	}
+	audit.ConfigHash = config.hash
+	audit.StrategyHash = strategy.hash
+	if err := s.storeSnapshot(ctx, StateKindConfig, config); err != nil {
+		return err
+	}
+	return s.storeSnapshot(ctx, StateKindStrategy, strategy)
+}
+
+// Diff compares the state behind the last decision at or before from with
+// the one at or before to. Without to, the live config and strategy
+// parameters are compared, with the prompt version and model of the latest
+// decision.
+//
+// Parameters:
+//
+//	ctx: Context for the lookups.
+//	from: The earlier time.
+//	to: The later time; nil for the live state.
+//
+// Returns:
+//
+//	*StateDiff: The fingerprints and their changes, config and strategy
+//	changes listed key by key.
+//	error: ErrStateFingerprintNotFound if no decision was stamped by then.
+func (s *StateFingerprintService) Diff(ctx context.Context, from time.Time, to *time.Time) (*StateDiff, error) {
+	before, err := s.recordedAt(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+
+	var after *StateFingerprint
+	live := map[string]*stateSnapshot{}
+	if to != nil {
+		if after, err = s.recordedAt(ctx, *to); err != nil {
+			return nil, err
+		}
+	} else {
+		config, strategy, err := s.capture(ctx)
+		if err != nil {
+			return nil, err
+		}
+		live[config.hash] = &config
+		live[strategy.hash] = &strategy
+		after = &StateFingerprint{ConfigHash: config.hash, StrategyHash: strategy.hash, At: s.now().UTC()}
+		if latest, err := s.recordedAt(ctx, after.At); err == nil {
+			after.PromptVersion = latest.PromptVersion
+			after.Model = latest.Model
+		} else if !errors.Is(err, ErrStateFingerprintNotFound) {
+			return nil, err
+		}
+	}
+
+	diff := &StateDiff{From: *before, To: *after, Changes: []StateChange{}}
+	for _, part := range []struct {
+		kind     string
+		from, to string
+	}{
+		{StateKindConfig, before.ConfigHash, after.ConfigHash},
+		{StateKindStrategy, before.StrategyHash, after.StrategyHash},
+	} {
+		if part.from == part.to {
+			continue
+		}
+		changes, err := s.diffSnapshots(ctx, part.kind, part.from, part.to, live)
+		if err != nil {
+			return nil, err
+		}
+		diff.Changes = append(diff.Changes, changes...)
+	}
+	if before.PromptVersion != after.PromptVersion {
+		diff.Changes = append(diff.Changes, StateChange{Kind: StateKindPrompt, From: before.PromptVersion, To: after.PromptVersion})
+	}
+	if before.Model != after.Model {
+		diff.Changes = append(diff.Changes, StateChange{Kind: StateKindModel, From: before.Model, To: after.Model})
+	}
+	diff.Changed = len(diff.Changes) > 0
+	return diff, nil
+}
+
+// capture reads and hashes the live config and strategy parameters.
+func (s *StateFingerprintService) capture(ctx context.Context) (config, strategy stateSnapshot, err error) {
+	if s.config != nil {
+		settings, err := s.config(ctx)
+		if err != nil {
+			return config, strategy, fmt.Errorf("read config state: %w", err)
+		}
+		config = newStateSnapshot(flattenStateSettings("", settings))
+	}
+	if len(s.strategies) > 0 {
+		parameters := make(map[string]string)
+		for name, source := range s.strategies {
+			settings, err := source(ctx)
+			if err != nil {
+				return config, strategy, fmt.Errorf("read %s parameters: %w", name, err)
+			}
+			for key, value := range flattenStateSettings(name, settings) {
+				parameters[key] = value
+			}
+		}
+		strategy = newStateSnapshot(parameters)
+	}
+	return config, strategy, nil
+}
+
+// storeSnapshot writes the settings behind a hash once.
+func (s *StateFingerprintService) storeSnapshot(ctx context.Context, kind string, snapshot stateSnapshot) error {
+	if snapshot.hash == "" {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stored[snapshot.hash] {
+		return nil
+	}
+	settings, err := json.Marshal(snapshot.settings)
+	if err != nil {
+		return fmt.Errorf("marshal %s state: %w", kind, err)
+	}
+	if _, err := s.db.Exec(ctx, `
+		INSERT INTO state_snapshots (hash, kind, settings, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (hash) DO NOTHING`,
+		snapshot.hash, kind, settings, s.now().UTC()); err != nil {
+		return fmt.Errorf("failed to store %s state: %w", kind, err)
+	}
+	s.stored[snapshot.hash] = true
+	return nil
+}
+
+// recordedAt returns the fingerprint of the last stamped decision at or
+// before a time, with the prompt version and model of the last AI decision.
+func (s *StateFingerprintService) recordedAt(ctx context.Context, at time.Time) (*StateFingerprint, error) {
+	var fingerprint StateFingerprint
+	err := s.db.QueryRow(ctx, `
+		SELECT id, config_hash, strategy_hash, created_at
+		FROM decision_audits
+		WHERE created_at <= $1 AND (config_hash <> '' OR strategy_hash <> '')
+		ORDER BY created_at DESC
+		LIMIT 1`, at.UTC()).Scan(
+		&fingerprint.DecisionID, &fingerprint.ConfigHash, &fingerprint.StrategyHash, &fingerprint.At,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w at %s", ErrStateFingerprintNotFound, at.UTC().Format(time.RFC3339))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state fingerprint: %w", err)
+	}
+
+	// Decisions without a prompt, such as external signals, do not reset it
+	err = s.db.QueryRow(ctx, `
+		SELECT prompt_version, model
+		FROM decision_audits
+		WHERE created_at <= $1 AND prompt_version <> ''
+		ORDER BY created_at DESC
+		LIMIT 1`, at.UTC()).Scan(&fingerprint.PromptVersion, &fingerprint.Model)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get prompt version: %w", err)
+	}
+	return &fingerprint, nil
+}
+
+// diffSnapshots lists the settings that differ between two hashes of a kind.
+func (s *StateFingerprintService) diffSnapshots(ctx context.Context, kind, from, to string, live map[string]*stateSnapshot) ([]StateChange, error) {
+	before, err := s.loadSnapshot(ctx, from, live)
+	if err != nil {
+		return nil, err
+	}
+	after, err := s.loadSnapshot(ctx, to, live)
+	if err != nil {
+		return nil, err
+	}
+	if before == nil || after == nil {
+		return []StateChange{{Kind: kind, From: from, To: to}}, nil
+	}
+
+	keys := make([]string, 0, len(before)+len(after))
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []StateChange
+	for _, key := range keys {
+		if before[key] != after[key] {
+			changes = append(changes, StateChange{Kind: kind, Key: key, From: before[key], To: after[key]})
+		}
+	}
+	return changes, nil
+}
+
+// loadSnapshot returns the settings behind a hash, or nil when they were not
+// stored.
+func (s *StateFingerprintService) loadSnapshot(ctx context.Context, hash string, live map[string]*stateSnapshot) (map[string]string, error) {
+	if hash == "" {
+		return map[string]string{}, nil
+	}
+	if snapshot, ok := live[hash]; ok {
+		return snapshot.settings, nil
+	}
+	var raw []byte
+	err := s.db.QueryRow(ctx, `SELECT settings FROM state_snapshots WHERE hash = $1`, hash).Scan(&raw)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state snapshot: %w", err)
+	}
+	var settings map[string]string
+	if err := json.Unmarshal(raw, &settings); err != nil {
+		return nil, fmt.Errorf("decode state snapshot: %w", err)
+	}
+	return settings, nil
+}
+
+// newStateSnapshot hashes flattened settings. Map keys are marshaled in
+// sorted order, so equal settings always have the same hash.
+func newStateSnapshot(settings map[string]string) stateSnapshot {
+	raw, _ := json.Marshal(settings)
+	sum := sha256.Sum256(raw)
+	return stateSnapshot{hash: hex.EncodeToString(sum[:]), settings: settings}
+}
+
+// flattenStateSettings flattens nested settings into dotted keys with
+// JSON-encoded values; strings are kept as they are.
+func flattenStateSettings(prefix string, settings map[string]interface{}) map[string]string {
+	flat := make(map[string]string)
+	if len(settings) == 0 {
+		return flat
+	}
+	var walk func(prefix string, value interface{})
+	walk = func(prefix string, value interface{}) {
+		if nested, ok := value.(map[string]interface{}); ok {
+			for key, child := range nested {
+				if prefix != "" {
+					key = prefix + "." + key
+				}
+				walk(key, child)
+			}
+			return
+		}
+		if str, ok := value.(string); ok {
+			flat[prefix] = str
+			return
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			raw = []byte(fmt.Sprint(value))
+		}
+		flat[prefix] = string(raw)
+	}
+	walk(prefix, normalizeStateValue(settings))
+	return flat
+}
+
+// normalizeStateValue round-trips settings through JSON so that structs and
+// typed maps are flattened like the maps they encode to.
+func normalizeStateValue(settings map[string]interface{}) interface{} {
+	raw, err := json.Marshal(settings)
+	if err != nil {
+		return settings
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return settings
+	}
+	return value
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStateFingerprints(t *testing.T, config *map[string]interface{}) (*StateFingerprintService, pgxmock.PgxPoolIface) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	t.Cleanup(mockPool.Close)
+	svc := NewStateFingerprintService(database.NewMockDBPool(mockPool), func(context.Context) (map[string]interface{}, error) {
+		return *config, nil
+	})
+	svc.AddStrategySource("ai_scalping", func(context.Context) (map[string]interface{}, error) {
+		return map[string]interface{}{"leverage": 5, "order_options": OrderOptions{PostOnly: true}}, nil
+	})
+	svc.now = func() time.Time { return time.Date(2026, 5, 2, 9, 0, 0, 0, time.UTC) }
+	return svc, mockPool
+}
+
+func expectStateFingerprint(mockPool pgxmock.PgxPoolIface, at time.Time, id, configHash, strategyHash, promptVersion string) {
+	mockPool.ExpectQuery("SELECT id, config_hash, strategy_hash").
+		WithArgs(at).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "config_hash", "strategy_hash", "created_at"}).
+			AddRow(id, configHash, strategyHash, at.Add(-time.Minute)))
+	mockPool.ExpectQuery("SELECT prompt_version, model").
+		WithArgs(at).
+		WillReturnRows(pgxmock.NewRows([]string{"prompt_version", "model"}).AddRow(promptVersion, "gpt-4o"))
+}
+
+func TestStateFingerprintService_Stamp(t *testing.T) {
+	config := map[string]interface{}{"trading": map[string]interface{}{"max_leverage": 5}, "api_key": "[redacted]"}
+	svc, mockPool := newTestStateFingerprints(t, &config)
+
+	mockPool.ExpectExec("INSERT INTO state_snapshots").
+		WithArgs(pgxmock.AnyArg(), StateKindConfig, []byte(`{"api_key":"[redacted]","trading.max_leverage":"5"}`), svc.now()).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mockPool.ExpectExec("INSERT INTO state_snapshots").
+		WithArgs(pgxmock.AnyArg(), StateKindStrategy, []byte(`{"ai_scalping.leverage":"5","ai_scalping.order_options.post_only":"true"}`), svc.now()).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	first := &DecisionAudit{}
+	require.NoError(t, svc.Stamp(t.Context(), first))
+	assert.Len(t, first.ConfigHash, 64)
+	assert.Len(t, first.StrategyHash, 64)
+
+	// The same state has the same hashes and is stored once
+	second := &DecisionAudit{}
+	require.NoError(t, svc.Stamp(t.Context(), second))
+	assert.Equal(t, first.ConfigHash, second.ConfigHash)
+	assert.Equal(t, first.StrategyHash, second.StrategyHash)
+
+	config = map[string]interface{}{"trading": map[string]interface{}{"max_leverage": 3}, "api_key": "[redacted]"}
+	mockPool.ExpectExec("INSERT INTO state_snapshots").
+		WithArgs(pgxmock.AnyArg(), StateKindConfig, pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	third := &DecisionAudit{}
+	require.NoError(t, svc.Stamp(t.Context(), third))
+	assert.NotEqual(t, first.ConfigHash, third.ConfigHash)
+	assert.Equal(t, first.StrategyHash, third.StrategyHash)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestStateFingerprintService_DiffWithLiveState(t *testing.T) {
+	config := map[string]interface{}{"trading": map[string]interface{}{"max_leverage": 3}, "mode": "live"}
+	svc, mockPool := newTestStateFingerprints(t, &config)
+	_, strategy, err := svc.capture(t.Context())
+	require.NoError(t, err)
+	before := newStateSnapshot(map[string]string{"trading.max_leverage": "5", "trading.paper": "true"})
+	from := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	expectStateFingerprint(mockPool, from, "dec_1", before.hash, strategy.hash, "v1@0a1b2c3d4e5f")
+	expectStateFingerprint(mockPool, svc.now(), "dec_2", before.hash, strategy.hash, "v2@0a1b2c3d4e5f")
+	mockPool.ExpectQuery("SELECT settings FROM state_snapshots").
+		WithArgs(before.hash).
+		WillReturnRows(pgxmock.NewRows([]string{"settings"}).AddRow([]byte(`{"trading.max_leverage":"5","trading.paper":"true"}`)))
+
+	diff, err := svc.Diff(t.Context(), from, nil)
+	require.NoError(t, err)
+	assert.True(t, diff.Changed)
+	assert.Equal(t, "dec_1", diff.From.DecisionID)
+	assert.Empty(t, diff.To.DecisionID, "the live state is not a decision")
+	assert.Equal(t, svc.now(), diff.To.At)
+	assert.Equal(t, []StateChange{
+		{Kind: StateKindConfig, Key: "mode", From: "", To: "live"},
+		{Kind: StateKindConfig, Key: "trading.max_leverage", From: "5", To: "3"},
+		{Kind: StateKindConfig, Key: "trading.paper", From: "true", To: ""},
+		{Kind: StateKindPrompt, From: "v1@0a1b2c3d4e5f", To: "v2@0a1b2c3d4e5f"},
+	}, diff.Changes)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestStateFingerprintService_DiffBetweenDecisions(t *testing.T) {
+	config := map[string]interface{}{}
+	svc, mockPool := newTestStateFingerprints(t, &config)
+	from := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	// Unchanged state
+	expectStateFingerprint(mockPool, from, "dec_1", "config-a", "strategy-a", "v1")
+	expectStateFingerprint(mockPool, to, "dec_2", "config-a", "strategy-a", "v1")
+	diff, err := svc.Diff(t.Context(), from, &to)
+	require.NoError(t, err)
+	assert.False(t, diff.Changed)
+	assert.Empty(t, diff.Changes)
+
+	// Settings that were not stored are reported by hash
+	expectStateFingerprint(mockPool, from, "dec_1", "config-a", "strategy-a", "v1")
+	expectStateFingerprint(mockPool, to, "dec_2", "config-a", "strategy-b", "v1")
+	mockPool.ExpectQuery("SELECT settings FROM state_snapshots").
+		WithArgs("strategy-a").
+		WillReturnRows(pgxmock.NewRows([]string{"settings"}))
+	mockPool.ExpectQuery("SELECT settings FROM state_snapshots").
+		WithArgs("strategy-b").
+		WillReturnRows(pgxmock.NewRows([]string{"settings"}).AddRow([]byte(`{"ai_scalping.leverage":"3"}`)))
+	diff, err = svc.Diff(t.Context(), from, &to)
+	require.NoError(t, err)
+	assert.Equal(t, []StateChange{{Kind: StateKindStrategy, From: "strategy-a", To: "strategy-b"}}, diff.Changes)
+
+	mockPool.ExpectQuery("SELECT id, config_hash, strategy_hash").
+		WithArgs(from).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "config_hash", "strategy_hash", "created_at"}))
+	_, err = svc.Diff(t.Context(), from, &to)
+	assert.ErrorIs(t, err, ErrStateFingerprintNotFound)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestPromptVersionID(t *testing.T) {
+	assert.Regexp(t, `^[0-9a-f]{12}$`, promptVersionID(PromptVersion{}, "system prompt"))
+	assert.Regexp(t, `^canary@[0-9a-f]{12}$`, promptVersionID(PromptVersion{Name: "canary"}, "system prompt"))
+	assert.NotEqual(t, promptVersionID(PromptVersion{}, "system prompt"), promptVersionID(PromptVersion{}, "new system prompt"))
+}
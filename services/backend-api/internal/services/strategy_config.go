@@ -0,0 +1,573 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/skill"
+	"github.com/redis/go-redis/v9"
+	"gopkg.in/yaml.v3"
+)
+
+// StrategyConfigFormat identifies version 1 of the shareable strategy
+// configuration format.
+const StrategyConfigFormat = "neuratrade.strategy/v1"
+
+const (
+	strategyConfigsKey = "strategies:chats"
+
+	// maxStrategyDocumentBytes bounds imported documents.
+	maxStrategyDocumentBytes = 64 << 10
+)
+
+var (
+	ErrStrategyConfigInvalid  = errors.New("invalid strategy configuration")
+	ErrStrategyConfigNotFound = errors.New("strategy configuration not found")
+)
+
+// shareableStrategies are the strategy types a configuration may bundle.
+var shareableStrategies = []string{StrategyScalping, StrategyArbitrage, StrategyFundingArbitrage}
+
+// secretKeyPattern matches parameter names that hold credentials.
+var secretKeyPattern = regexp.MustCompile(`(?i)(secret|passw|passphrase|private[_-]?key|api[_-]?key|token|mnemonic|seed[_-]?phrase|credential)`)
+
+// secretValuePatterns match values that look like credentials whatever
+// their key: PEM blocks, raw private keys and long opaque tokens.
+var secretValuePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`),
+	regexp.MustCompile(`\b(0x)?[0-9a-fA-F]{64}\b`),
+	regexp.MustCompile(`\b[A-Za-z0-9+/_-]{40,}={0,2}`),
+}
+
+// StrategyConfig is a portable strategy configuration that can be shared as
+// YAML or JSON. It never carries exchange keys, wallet keys or other secrets.
+type StrategyConfig struct {
+	// Format is always StrategyConfigFormat.
+	Format      string `json:"format" yaml:"format"`
+	Name        string `json:"name" yaml:"name"`
+	Version     string `json:"version,omitempty" yaml:"version,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Author      string `json:"author,omitempty" yaml:"author,omitempty"`
+	// Strategy is scalping, arbitrage or funding_arbitrage.
+	Strategy string `json:"strategy" yaml:"strategy"`
+	// Parameters are strategy settings; values are strings, numbers,
+	// booleans or lists of those.
+	Parameters map[string]interface{} `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Risk       StrategyRiskCaps       `json:"risk" yaml:"risk"`
+	// RequiredSkills are skill IDs that must be installed to run it.
+	RequiredSkills []string `json:"required_skills,omitempty" yaml:"required_skills,omitempty"`
+}
+
+// StrategyRiskCaps are the risk limits a strategy configuration runs with.
+// They become a custom operating profile when the configuration is installed.
+type StrategyRiskCaps struct {
+	DailyLossPct        float64      `json:"daily_loss_pct" yaml:"daily_loss_pct"`
+	MinConfidence       float64      `json:"min_confidence" yaml:"min_confidence"`
+	MaxPositionPct      float64      `json:"max_position_pct" yaml:"max_position_pct"`
+	ScanIntervalSeconds int          `json:"scan_interval_seconds" yaml:"scan_interval_seconds"`
+	AIUsage             AIUsageLevel `json:"ai_usage" yaml:"ai_usage"`
+}
+
+// profile returns the operating profile the caps install as.
+func (c StrategyConfig) profile() OperatingProfile {
+	return OperatingProfile{
+		Name:                c.Name,
+		Description:         c.Description,
+		DailyLossPct:        c.Risk.DailyLossPct,
+		MinConfidence:       c.Risk.MinConfidence,
+		MaxPositionPct:      c.Risk.MaxPositionPct,
+		ScanIntervalSeconds: c.Risk.ScanIntervalSeconds,
+		AIUsage:             c.Risk.AIUsage,
+	}
+}
+
+// StrategyValidation is the result of validating a strategy document.
+type StrategyValidation struct {
+	Valid    bool     `json:"valid"`
+	Errors   []string `json:"errors,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+	// Config is the decoded configuration; nil when it could not be parsed.
+	Config *StrategyConfig `json:"config,omitempty"`
+}
+
+func (v *StrategyValidation) errorf(format string, args ...interface{}) {
+	v.Errors = append(v.Errors, fmt.Sprintf(format, args...))
+}
+
+func (v *StrategyValidation) warnf(format string, args ...interface{}) {
+	v.Warnings = append(v.Warnings, fmt.Sprintf(format, args...))
+}
+
+// err returns the validation errors as an ErrStrategyConfigInvalid error.
+func (v *StrategyValidation) err() error {
+	return fmt.Errorf("%w: %s", ErrStrategyConfigInvalid, strings.Join(v.Errors, "; "))
+}
+
+// InstalledStrategy is a strategy configuration installed for a chat.
+type InstalledStrategy struct {
+	Config      StrategyConfig `json:"config"`
+	InstalledAt time.Time      `json:"installed_at"`
+}
+
+// StrategyExport is a strategy configuration rendered for sharing.
+type StrategyExport struct {
+	Name   string `json:"name"`
+	Format string `json:"format"`
+	// Document is the YAML or JSON configuration.
+	Document string `json:"document"`
+}
+
+// SkillCatalog looks up the skills a strategy configuration requires.
+type SkillCatalog interface {
+	Get(id string) (*skill.Skill, bool)
+}
+
+// StrategyProfileStore saves installed risk caps as operating profiles and
+// resolves profiles for export.
+type StrategyProfileStore interface {
+	Resolve(ctx context.Context, chatID, name string) (*OperatingProfile, error)
+	Save(ctx context.Context, chatID, base string, profile OperatingProfile) (*OperatingProfile, error)
+	Delete(ctx context.Context, chatID, name string) error
+}
+
+// Ensure the skill registry and profile service satisfy the interfaces.
+var (
+	_ SkillCatalog         = (*skill.Registry)(nil)
+	_ StrategyProfileStore = (*OperatingProfileService)(nil)
+)
+
+// StrategyConfigService validates, installs and exports shareable strategy
+// configurations. Installed configurations are kept per chat in Redis and
+// their risk caps become custom operating profiles.
+type StrategyConfigService struct {
+	redis    *redis.Client
+	profiles StrategyProfileStore
+	skills   SkillCatalog
+	now      func() time.Time
+	// mu serializes read-modify-write cycles of a chat's configurations.
+	mu sync.Mutex
+}
+
+// NewStrategyConfigService creates a strategy configuration service.
+//
+// Parameters:
+//
+//	client: Redis client used to persist installed configurations.
+//	profiles: Operating profile store the risk caps are installed into.
+//
+// Returns:
+//
+//	*StrategyConfigService: Initialized service.
+func NewStrategyConfigService(client *redis.Client, profiles StrategyProfileStore) *StrategyConfigService {
+	return &StrategyConfigService{
+		redis:    client,
+		profiles: profiles,
+		now:      time.Now,
+	}
+}
+
+// SetSkillCatalog checks required skills and parameter types against the
+// installed skills. Without a catalog required skills are only reported.
+func (s *StrategyConfigService) SetSkillCatalog(skills SkillCatalog) {
+	s.skills = skills
+}
+
+// Validate decodes a YAML or JSON strategy document and checks it without
+// installing it.
+//
+// Parameters:
+//
+//	document: The configuration; JSON when it starts with "{", YAML otherwise.
+//
+// Returns:
+//
+//	*StrategyValidation: Errors make the document invalid; warnings do not.
+func (s *StrategyConfigService) Validate(document string) *StrategyValidation {
+	validation := &StrategyValidation{}
+	if len(document) > maxStrategyDocumentBytes {
+		validation.errorf("document is larger than %d KiB", maxStrategyDocumentBytes>>10)
+		return validation
+	}
+	config, err := decodeStrategyConfig(document)
+	if err != nil {
+		validation.errorf("%v", err)
+		return validation
+	}
+	validation.Config = config
+	s.check(config, validation)
+	validation.Valid = len(validation.Errors) == 0
+	return validation
+}
+
+func decodeStrategyConfig(document string) (*StrategyConfig, error) {
+	trimmed := strings.TrimSpace(document)
+	if trimmed == "" {
+		return nil, errors.New("document is empty")
+	}
+	var config StrategyConfig
+	if strings.HasPrefix(trimmed, "{") {
+		decoder := json.NewDecoder(strings.NewReader(trimmed))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&config); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return &config, nil
+	}
+	decoder := yaml.NewDecoder(strings.NewReader(trimmed))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	return &config, nil
+}
+
+// check validates a decoded configuration, normalizing names in place.
+func (s *StrategyConfigService) check(config *StrategyConfig, validation *StrategyValidation) {
+	if config.Format != StrategyConfigFormat {
+		validation.errorf("format must be %q", StrategyConfigFormat)
+	}
+	config.Name = strings.ToLower(strings.TrimSpace(config.Name))
+	config.Strategy = strings.ToLower(strings.TrimSpace(config.Strategy))
+	config.Risk.AIUsage = AIUsageLevel(strings.ToLower(string(config.Risk.AIUsage)))
+	if !containsString(shareableStrategies, config.Strategy) {
+		validation.errorf("strategy must be one of %s", strings.Join(shareableStrategies, ", "))
+	}
+	if _, ok := builtInProfile(config.Name); ok {
+		validation.errorf("name %s is a built-in profile", config.Name)
+	}
+	if err := config.profile().validate(); err != nil {
+		validation.errorf("%s", strings.TrimPrefix(err.Error(), ErrProfileInvalid.Error()+": "))
+	}
+
+	for _, field := range []struct{ name, value string }{
+		{"name", config.Name}, {"version", config.Version}, {"description", config.Description}, {"author", config.Author},
+	} {
+		if looksLikeSecret(field.value) {
+			validation.errorf("%s looks like it contains a credential; remove secrets before sharing", field.name)
+		}
+	}
+	keys := make([]string, 0, len(config.Parameters))
+	for key := range config.Parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		checkStrategyParameter(key, config.Parameters[key], validation)
+	}
+	s.checkSkills(config, keys, validation)
+}
+
+func checkStrategyParameter(key string, value interface{}, validation *StrategyValidation) {
+	if secretKeyPattern.MatchString(key) {
+		validation.errorf("parameter %s looks like a credential; secrets must not be shared", key)
+		return
+	}
+	values := []interface{}{value}
+	if list, ok := value.([]interface{}); ok {
+		values = list
+	}
+	for _, item := range values {
+		switch v := item.(type) {
+		case string:
+			if looksLikeSecret(v) {
+				validation.errorf("parameter %s looks like a credential; secrets must not be shared", key)
+				return
+			}
+		case bool, int, int64, float64:
+		default:
+			validation.errorf("parameter %s must be a string, number, boolean or a list of those", key)
+			return
+		}
+	}
+}
+
+func looksLikeSecret(value string) bool {
+	for _, pattern := range secretValuePatterns {
+		if pattern.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSkills reports missing required skills and checks parameters against
+// the parameters the required skills declare.
+func (s *StrategyConfigService) checkSkills(config *StrategyConfig, keys []string, validation *StrategyValidation) {
+	if len(config.RequiredSkills) == 0 {
+		return
+	}
+	if s.skills == nil {
+		validation.warnf("installed skills are unknown; make sure %s are available", strings.Join(config.RequiredSkills, ", "))
+		return
+	}
+	declared := map[string]skill.Param{}
+	for _, id := range config.RequiredSkills {
+		required, ok := s.skills.Get(id)
+		if !ok {
+			validation.errorf("required skill %s is not installed", id)
+			continue
+		}
+		for name, param := range required.Parameters {
+			declared[name] = param
+		}
+	}
+	if len(declared) == 0 {
+		return
+	}
+	for _, key := range keys {
+		param, ok := declared[key]
+		if !ok {
+			validation.warnf("parameter %s is not declared by the required skills", key)
+			continue
+		}
+		if !parameterMatches(param, config.Parameters[key]) {
+			validation.errorf("parameter %s must be of type %s", key, param.Type)
+		}
+	}
+}
+
+func parameterMatches(param skill.Param, value interface{}) bool {
+	switch param.Type {
+	case "string":
+		text, ok := value.(string)
+		return ok && (len(param.Enum) == 0 || containsString(param.Enum, text))
+	case "number", "float":
+		switch value.(type) {
+		case int, int64, float64:
+			return true
+		}
+		return false
+	case "integer":
+		switch v := value.(type) {
+		case int, int64:
+			return true
+		case float64:
+			return v == float64(int64(v))
+		}
+		return false
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	}
+	return true
+}
+
+// Install validates a document and installs it for a chat. Its risk caps
+// become a custom operating profile of the same name, which the chat can then
+// select; installing never changes the selected profile.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	chatID: Telegram chat ID.
+//	document: YAML or JSON strategy configuration.
+//
+// Returns:
+//
+//	*InstalledStrategy: The installed configuration.
+//	*StrategyValidation: The validation, including warnings.
+//	error: ErrStrategyConfigInvalid, ErrProfileLimit, or a persistence error.
+func (s *StrategyConfigService) Install(ctx context.Context, chatID, document string) (*InstalledStrategy, *StrategyValidation, error) {
+	validation := s.Validate(document)
+	if !validation.Valid {
+		return nil, validation, validation.err()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	installed, err := s.load(ctx, chatID)
+	if err != nil {
+		return nil, validation, err
+	}
+	if _, err := s.profiles.Save(ctx, chatID, "", validation.Config.profile()); err != nil {
+		return nil, validation, err
+	}
+	strategy := InstalledStrategy{Config: *validation.Config, InstalledAt: s.now().UTC()}
+	installed[strategy.Config.Name] = strategy
+	if err := s.save(ctx, chatID, installed); err != nil {
+		return nil, validation, err
+	}
+	return &strategy, validation, nil
+}
+
+// List returns a chat's installed configurations by name.
+func (s *StrategyConfigService) List(ctx context.Context, chatID string) ([]InstalledStrategy, error) {
+	installed, err := s.load(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	strategies := make([]InstalledStrategy, 0, len(installed))
+	for _, strategy := range installed {
+		strategies = append(strategies, strategy)
+	}
+	sort.Slice(strategies, func(i, j int) bool { return strategies[i].Config.Name < strategies[j].Config.Name })
+	return strategies, nil
+}
+
+// Export renders an installed configuration, or any operating profile of the
+// chat as a scalping configuration, for sharing.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	chatID: Telegram chat ID.
+//	name: Installed configuration or operating profile name.
+//	format: "yaml" (default) or "json".
+//
+// Returns:
+//
+//	*StrategyExport: The rendered document.
+//	error: ErrStrategyConfigNotFound, ErrStrategyConfigInvalid for an unknown format, or a persistence error.
+func (s *StrategyConfigService) Export(ctx context.Context, chatID, name, format string) (*StrategyExport, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	installed, err := s.load(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	config, ok := installed[name]
+	if !ok {
+		profile, err := s.profiles.Resolve(ctx, chatID, name)
+		if errors.Is(err, ErrProfileNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrStrategyConfigNotFound, name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		config.Config = StrategyConfig{
+			Format:      StrategyConfigFormat,
+			Name:        profile.Name,
+			Description: profile.Description,
+			Strategy:    StrategyScalping,
+			Risk: StrategyRiskCaps{
+				DailyLossPct:        profile.DailyLossPct,
+				MinConfidence:       profile.MinConfidence,
+				MaxPositionPct:      profile.MaxPositionPct,
+				ScanIntervalSeconds: profile.ScanIntervalSeconds,
+				AIUsage:             profile.AIUsage,
+			},
+		}
+	}
+
+	var document []byte
+	switch strings.ToLower(format) {
+	case "", "yaml", "yml":
+		format = "yaml"
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(config.Config); err != nil {
+			return nil, fmt.Errorf("failed to encode strategy configuration: %w", err)
+		}
+		document = buf.Bytes()
+	case "json":
+		format = "json"
+		document, err = json.MarshalIndent(config.Config, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode strategy configuration: %w", err)
+		}
+		document = append(document, '\n')
+	default:
+		return nil, fmt.Errorf("%w: format must be yaml or json", ErrStrategyConfigInvalid)
+	}
+	return &StrategyExport{Name: config.Config.Name, Format: format, Document: string(document)}, nil
+}
+
+// Uninstall removes an installed configuration and its operating profile.
+func (s *StrategyConfigService) Uninstall(ctx context.Context, chatID, name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	installed, err := s.load(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	if _, ok := installed[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrStrategyConfigNotFound, name)
+	}
+	if err := s.profiles.Delete(ctx, chatID, name); err != nil && !errors.Is(err, ErrProfileNotFound) {
+		return err
+	}
+	delete(installed, name)
+	return s.save(ctx, chatID, installed)
+}
+
+// StrategyConfigSchema returns the JSON Schema of the strategy configuration
+// format, with the risk bounds enforced on import.
+func StrategyConfigSchema() map[string]interface{} {
+	number := func(minimum, maximum float64, description string) map[string]interface{} {
+		return map[string]interface{}{"type": "number", "minimum": minimum, "maximum": maximum, "description": description}
+	}
+	return map[string]interface{}{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"$id":                  StrategyConfigFormat,
+		"title":                "NeuraTrade strategy configuration",
+		"type":                 "object",
+		"additionalProperties": false,
+		"required":             []string{"format", "name", "strategy", "risk"},
+		"properties": map[string]interface{}{
+			"format":      map[string]interface{}{"const": StrategyConfigFormat},
+			"name":        map[string]interface{}{"type": "string", "pattern": profileNamePattern.String()},
+			"version":     map[string]interface{}{"type": "string"},
+			"description": map[string]interface{}{"type": "string"},
+			"author":      map[string]interface{}{"type": "string"},
+			"strategy":    map[string]interface{}{"enum": shareableStrategies},
+			"parameters": map[string]interface{}{
+				"type":        "object",
+				"description": "Strategy settings; never exchange keys, wallet keys or other secrets",
+				"additionalProperties": map[string]interface{}{
+					"type":  []string{"string", "number", "boolean", "array"},
+					"items": map[string]interface{}{"type": []string{"string", "number", "boolean"}},
+				},
+			},
+			"risk": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": false,
+				"required":             []string{"daily_loss_pct", "min_confidence", "max_position_pct", "scan_interval_seconds", "ai_usage"},
+				"properties": map[string]interface{}{
+					"daily_loss_pct":   number(0, maxProfileDailyLossPct, "Daily loss, in percent of the day's opening equity, that halts new entries"),
+					"min_confidence":   number(minProfileConfidence, maxProfileConfidence, "AI confidence a trade needs"),
+					"max_position_pct": number(0, maxProfilePositionPct, "Largest share of the strategy's capital, in percent, per trade"),
+					"scan_interval_seconds": map[string]interface{}{
+						"type": "integer", "minimum": minProfileScanInterval, "maximum": maxProfileScanInterval,
+					},
+					"ai_usage": map[string]interface{}{"enum": []AIUsageLevel{AIUsageLow, AIUsageStandard, AIUsageHigh}},
+				},
+			},
+			"required_skills": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+		},
+	}
+}
+
+func (s *StrategyConfigService) load(ctx context.Context, chatID string) (map[string]InstalledStrategy, error) {
+	raw, err := s.redis.HGet(ctx, strategyConfigsKey, chatID).Result()
+	if errors.Is(err, redis.Nil) {
+		return map[string]InstalledStrategy{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load strategy configurations: %w", err)
+	}
+	installed := map[string]InstalledStrategy{}
+	if err := json.Unmarshal([]byte(raw), &installed); err != nil {
+		return nil, fmt.Errorf("failed to decode strategy configurations: %w", err)
+	}
+	return installed, nil
+}
+
+func (s *StrategyConfigService) save(ctx context.Context, chatID string, installed map[string]InstalledStrategy) error {
+	raw, err := json.Marshal(installed)
+	if err != nil {
+		return fmt.Errorf("failed to encode strategy configurations: %w", err)
+	}
+	if err := s.redis.HSet(ctx, strategyConfigsKey, chatID, raw).Err(); err != nil {
+		return fmt.Errorf("failed to save strategy configurations: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/irfndi/neuratrade/internal/skill"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSkillCatalog map[string]*skill.Skill
+
+func (c fakeSkillCatalog) Get(id string) (*skill.Skill, bool) {
+	s, ok := c[id]
+	return s, ok
+}
+
+const testStrategyYAML = `format: neuratrade.strategy/v1
+name: Night-Scalper
+version: 1.2.0
+author: alice
+strategy: scalping
+parameters:
+  timeframe: 5m
+  take_profit_pct: 1.5
+  pairs: [BTC/USDT, ETH/USDT]
+risk:
+  daily_loss_pct: 1.5
+  min_confidence: 0.75
+  max_position_pct: 3
+  scan_interval_seconds: 300
+  ai_usage: low
+required_skills: [scalping]
+`
+
+func newTestStrategyConfigService(t *testing.T) (*StrategyConfigService, *OperatingProfileService) {
+	profiles, client := newTestOperatingProfileService(t)
+	service := NewStrategyConfigService(client, profiles)
+	service.SetSkillCatalog(fakeSkillCatalog{
+		"scalping": {ID: "scalping", Parameters: map[string]skill.Param{
+			"timeframe":       {Type: "string", Enum: []string{"1m", "5m", "15m"}},
+			"take_profit_pct": {Type: "number"},
+		}},
+	})
+	return service, profiles
+}
+
+func TestStrategyConfigService_InstallAndExport(t *testing.T) {
+	service, profiles := newTestStrategyConfigService(t)
+	ctx := t.Context()
+
+	installed, validation, err := service.Install(ctx, "42", testStrategyYAML)
+	require.NoError(t, err)
+	assert.Equal(t, "night-scalper", installed.Config.Name)
+	assert.Equal(t, []string{"parameter pairs is not declared by the required skills"}, validation.Warnings)
+
+	// The risk caps become a custom profile without being selected
+	profile, err := profiles.Resolve(ctx, "42", "night-scalper")
+	require.NoError(t, err)
+	assert.Equal(t, 300, profile.ScanIntervalSeconds)
+	assert.Equal(t, AIUsageLow, profile.AIUsage)
+	_, selected, err := profiles.ActiveProfile(ctx, "42")
+	require.NoError(t, err)
+	assert.False(t, selected)
+
+	list, err := service.List(ctx, "42")
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	others, err := service.List(ctx, "7")
+	require.NoError(t, err)
+	assert.Empty(t, others)
+
+	// Exports round-trip through both formats
+	for _, format := range []string{"yaml", "json"} {
+		exported, err := service.Export(ctx, "42", "night-scalper", format)
+		require.NoError(t, err)
+		assert.Equal(t, format, exported.Format)
+		roundTrip := service.Validate(exported.Document)
+		require.True(t, roundTrip.Valid, roundTrip.Errors)
+		assert.Equal(t, installed.Config.Risk, roundTrip.Config.Risk)
+		assert.Equal(t, []string{"scalping"}, roundTrip.Config.RequiredSkills)
+	}
+
+	// Built-in profiles export as scalping configurations
+	exported, err := service.Export(ctx, "42", ProfileConservative, "json")
+	require.NoError(t, err)
+	assert.Contains(t, exported.Document, `"scan_interval_seconds": 300`)
+	_, err = service.Export(ctx, "42", "missing", "yaml")
+	assert.ErrorIs(t, err, ErrStrategyConfigNotFound)
+	_, err = service.Export(ctx, "42", "night-scalper", "toml")
+	assert.ErrorIs(t, err, ErrStrategyConfigInvalid)
+
+	require.NoError(t, service.Uninstall(ctx, "42", "night-scalper"))
+	_, err = profiles.Resolve(ctx, "42", "night-scalper")
+	assert.ErrorIs(t, err, ErrProfileNotFound)
+	assert.ErrorIs(t, service.Uninstall(ctx, "42", "night-scalper"), ErrStrategyConfigNotFound)
+}
+
+func TestStrategyConfigService_RejectsUnsafeDocuments(t *testing.T) {
+	service, _ := newTestStrategyConfigService(t)
+
+	tests := []struct {
+		name     string
+		document string
+		want     string
+	}{
+		{"secret key", strings.Replace(testStrategyYAML, "timeframe: 5m", "api_key: abc", 1), "parameter api_key looks like a credential"},
+		{"secret value", strings.Replace(testStrategyYAML, "timeframe: 5m", "timeframe: 0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318", 1), "parameter timeframe looks like a credential"},
+		{"unknown field", testStrategyYAML + "exchange_secret: abc\n", "field exchange_secret not found"},
+		{"nested parameter", strings.Replace(testStrategyYAML, "timeframe: 5m", "timeframe: {a: 1}", 1), "parameter timeframe must be a string"},
+		{"enum", strings.Replace(testStrategyYAML, "timeframe: 5m", "timeframe: 4h", 1), "parameter timeframe must be of type string"},
+		{"missing skill", strings.Replace(testStrategyYAML, "[scalping]", "[scalping, grid]", 1), "required skill grid is not installed"},
+		{"risk", strings.Replace(testStrategyYAML, "min_confidence: 0.75", "min_confidence: 0.2", 1), "min_confidence must be between"},
+		{"format", strings.Replace(testStrategyYAML, "/v1", "/v9", 1), "format must be"},
+		{"built-in name", strings.Replace(testStrategyYAML, "Night-Scalper", "balanced", 1), "built-in profile"},
+		{"json unknown field", `{"format":"neuratrade.strategy/v1","password":"x"}`, "unknown field"},
+		{"empty", "  ", "document is empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validation := service.Validate(tt.document)
+			assert.False(t, validation.Valid)
+			assert.Contains(t, strings.Join(validation.Errors, "; "), tt.want)
+		})
+	}
+
+	_, _, err := service.Install(t.Context(), "42", strings.Replace(testStrategyYAML, "timeframe: 5m", "api_key: abc", 1))
+	assert.ErrorIs(t, err, ErrStrategyConfigInvalid)
+	list, err := service.List(t.Context(), "42")
+	require.NoError(t, err)
+	assert.Empty(t, list, "invalid documents are never installed")
+}
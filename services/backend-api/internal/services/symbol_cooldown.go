@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/irfndi/neuratrade/pkg/interfaces"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	symbolCooldownStateKey = "risk:symbol_cooldowns"
+
+	defaultSymbolCooldownAfterLoss = 30 * time.Minute
+)
+
+// Reasons a symbol is put on cooldown.
+const (
+	SymbolCooldownStopOut     = "stop_out"
+	SymbolCooldownLoss        = "loss"
+	SymbolCooldownExit        = "exit"
+	SymbolCooldownLiquidation = "liquidation"
+)
+
+// SymbolCooldownGuard reports whether a symbol may not be re-entered yet.
+type SymbolCooldownGuard interface {
+	SymbolCooldown(ctx context.Context, exchange, symbol string) (*SymbolCooldown, bool)
+}
+
+// SymbolCooldownConfig configures how long a symbol is blocked after an exit.
+type SymbolCooldownConfig struct {
+	// AfterLoss is the cooldown after a losing exit, stop-out or liquidation.
+	AfterLoss time.Duration
+	// AfterExit is the cooldown after any other exit; zero disables it.
+	AfterExit time.Duration
+}
+
+// SymbolCooldown is an active re-entry block on one symbol.
+type SymbolCooldown struct {
+	Exchange string          `json:"exchange"`
+	Symbol   string          `json:"symbol"`
+	Reason   string          `json:"reason"`
+	PnL      decimal.Decimal `json:"pnl"`
+	Until    time.Time       `json:"until"`
+	At       time.Time       `json:"at"`
+}
+
+// SymbolCooldownTracker blocks re-entry on a symbol for a while after a
+// position on it is closed, so the scalping engine cannot chase a loss on
+// the same market. Cooldowns are shared by every chat trading the exchange
+// account.
+type SymbolCooldownTracker struct {
+	redis  *redis.Client
+	config SymbolCooldownConfig
+	events EventEmitter
+	logger *slog.Logger
+	now    func() time.Time
+	// mu serializes read-modify-write updates of a symbol's cooldown.
+	mu sync.Mutex
+}
+
+// Ensure SymbolCooldownTracker implements SymbolCooldownGuard.
+var _ SymbolCooldownGuard = (*SymbolCooldownTracker)(nil)
+
+// NewSymbolCooldownTracker creates the per-symbol cooldown tracker.
+//
+// Parameters:
+//
+//	client: Redis client used to persist cooldowns.
+//	config: Cooldown durations; a zero AfterLoss uses the default.
+//
+// Returns:
+//
+//	*SymbolCooldownTracker: Initialized tracker.
+func NewSymbolCooldownTracker(client *redis.Client, config SymbolCooldownConfig) *SymbolCooldownTracker {
+	if config.AfterLoss <= 0 {
+		config.AfterLoss = defaultSymbolCooldownAfterLoss
+	}
+	if config.AfterExit < 0 {
+		config.AfterExit = 0
+	}
+	return &SymbolCooldownTracker{
+		redis:  client,
+		config: config,
+		logger: telemetry.Logger(),
+		now:    time.Now,
+	}
+}
+
+// Config returns the cooldown configuration.
+func (t *SymbolCooldownTracker) Config() SymbolCooldownConfig {
+	return t.config
+}
+
+// SetEventEmitter publishes new cooldowns as risk events.
+func (t *SymbolCooldownTracker) SetEventEmitter(events EventEmitter) {
+	t.events = events
+}
+
+// RecordExit starts the symbol's cooldown after a position on it is closed.
+// Losses, stop-outs and liquidations use AfterLoss, other exits AfterExit.
+// A longer cooldown that is already running is kept.
+//
+// Parameters:
+//
+//	ctx: Context for the update.
+//	exchange: Exchange the position was held on.
+//	symbol: The position's symbol.
+//	pnl: The exit's realized profit or loss.
+//	reason: Why the position was closed; empty derives it from pnl.
+//
+// Returns:
+//
+//	*SymbolCooldown: The symbol's cooldown, nil if the exit starts none.
+//	error: Error if the cooldown could not be read or saved.
+func (t *SymbolCooldownTracker) RecordExit(ctx context.Context, exchange, symbol string, pnl decimal.Decimal, reason string) (*SymbolCooldown, error) {
+	exchange = strings.ToLower(strings.TrimSpace(exchange))
+	symbol = normalizeSymbolForComparison(symbol)
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is required")
+	}
+	reason = strings.ToLower(strings.TrimSpace(reason))
+	if reason == "" {
+		reason = SymbolCooldownExit
+		if pnl.IsNegative() {
+			reason = SymbolCooldownLoss
+		}
+	}
+	duration := t.config.AfterExit
+	if pnl.IsNegative() || reason == SymbolCooldownStopOut || reason == SymbolCooldownLiquidation || reason == SymbolCooldownLoss {
+		duration = t.config.AfterLoss
+	}
+	if duration <= 0 {
+		return nil, nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now().UTC()
+	cooldown := &SymbolCooldown{Exchange: exchange, Symbol: symbol, Reason: reason, PnL: pnl, Until: now.Add(duration), At: now}
+	current, err := t.load(ctx, exchange, symbol)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil && current.Until.After(cooldown.Until) {
+		return current, nil
+	}
+	if err := t.save(ctx, cooldown); err != nil {
+		return nil, err
+	}
+	t.emit(ctx, *cooldown)
+	return cooldown, nil
+}
+
+// OnPositionClosed starts a cooldown for a closed or liquidated position. It
+// is registered as the position tracker's close callback.
+func (t *SymbolCooldownTracker) OnPositionClosed(ctx context.Context, position interfaces.Position) error {
+	reason := ""
+	if position.Status == interfaces.PositionStatusLiquidated {
+		reason = SymbolCooldownLiquidation
+	}
+	_, err := t.RecordExit(ctx, position.Exchange, position.Symbol, position.UnrealizedPL, reason)
+	return err
+}
+
+// SymbolCooldown returns the symbol's active cooldown, if any. Errors are
+// logged and leave the symbol tradable.
+func (t *SymbolCooldownTracker) SymbolCooldown(ctx context.Context, exchange, symbol string) (*SymbolCooldown, bool) {
+	cooldown, err := t.load(ctx, strings.ToLower(strings.TrimSpace(exchange)), normalizeSymbolForComparison(symbol))
+	if err != nil {
+		t.logger.Warn("Failed to load symbol cooldown", "exchange", exchange, "symbol", symbol, "error", err)
+		return nil, false
+	}
+	if cooldown == nil || !t.now().Before(cooldown.Until) {
+		return nil, false
+	}
+	return cooldown, true
+}
+
+// Active returns every running cooldown, soonest to end first. Expired
+// cooldowns are removed.
+func (t *SymbolCooldownTracker) Active(ctx context.Context) ([]SymbolCooldown, error) {
+	entries, err := t.redis.HGetAll(ctx, symbolCooldownStateKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load symbol cooldowns: %w", err)
+	}
+	now := t.now()
+	active := make([]SymbolCooldown, 0, len(entries))
+	var expired []string
+	for field, raw := range entries {
+		var cooldown SymbolCooldown
+		if err := json.Unmarshal([]byte(raw), &cooldown); err != nil || !now.Before(cooldown.Until) {
+			expired = append(expired, field)
+			continue
+		}
+		active = append(active, cooldown)
+	}
+	if len(expired) > 0 {
+		if err := t.redis.HDel(ctx, symbolCooldownStateKey, expired...).Err(); err != nil {
+			t.logger.Warn("Failed to remove expired symbol cooldowns", "error", err)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool { return active[i].Until.Before(active[j].Until) })
+	return active, nil
+}
+
+// Clear lifts the symbol's cooldown.
+func (t *SymbolCooldownTracker) Clear(ctx context.Context, exchange, symbol string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	field := symbolCooldownField(strings.ToLower(strings.TrimSpace(exchange)), normalizeSymbolForComparison(symbol))
+	return t.redis.HDel(ctx, symbolCooldownStateKey, field).Err()
+}
+
+func (t *SymbolCooldownTracker) emit(ctx context.Context, cooldown SymbolCooldown) {
+	t.logger.Warn("Symbol cooling down after exit",
+		"exchange", cooldown.Exchange, "symbol", cooldown.Symbol, "reason", cooldown.Reason, "until", cooldown.Until)
+	if t.events == nil {
+		return
+	}
+	t.events.Emit(ctx, WebhookEventRisk, map[string]interface{}{
+		"type":     "symbol_cooldown",
+		"exchange": cooldown.Exchange,
+		"symbol":   cooldown.Symbol,
+		"reason":   cooldown.Reason,
+		"pnl":      cooldown.PnL.String(),
+		"until":    cooldown.Until,
+	})
+}
+
+func symbolCooldownField(exchange, symbol string) string {
+	return exchange + ":" + symbol
+}
+
+func (t *SymbolCooldownTracker) load(ctx context.Context, exchange, symbol string) (*SymbolCooldown, error) {
+	raw, err := t.redis.HGet(ctx, symbolCooldownStateKey, symbolCooldownField(exchange, symbol)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load symbol cooldown: %w", err)
+	}
+	var cooldown SymbolCooldown
+	if err := json.Unmarshal([]byte(raw), &cooldown); err != nil {
+		return nil, fmt.Errorf("failed to decode symbol cooldown: %w", err)
+	}
+	return &cooldown, nil
+}
+
+func (t *SymbolCooldownTracker) save(ctx context.Context, cooldown *SymbolCooldown) error {
+	raw, err := json.Marshal(cooldown)
+	if err != nil {
+		return err
+	}
+	if err := t.redis.HSet(ctx, symbolCooldownStateKey, symbolCooldownField(cooldown.Exchange, cooldown.Symbol), raw).Err(); err != nil {
+		return fmt.Errorf("failed to save symbol cooldown: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/irfndi/neuratrade/pkg/interfaces"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSymbolCooldownTracker(t *testing.T, config SymbolCooldownConfig) (*SymbolCooldownTracker, *time.Time) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	tracker := NewSymbolCooldownTracker(client, config)
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+	return tracker, &now
+}
+
+func TestSymbolCooldownTracker_AfterLoss(t *testing.T) {
+	tracker, now := newTestSymbolCooldownTracker(t, SymbolCooldownConfig{})
+	events := &hookEventCapture{}
+	tracker.SetEventEmitter(events)
+	ctx := t.Context()
+
+	// Profitable exits start no cooldown while AfterExit is disabled
+	cooldown, err := tracker.RecordExit(ctx, "binance", "SOL/USDT", decimal.NewFromInt(4), "")
+	require.NoError(t, err)
+	assert.Nil(t, cooldown)
+
+	cooldown, err = tracker.RecordExit(ctx, "Binance", "sol-usdt", decimal.NewFromInt(-12), SymbolCooldownStopOut)
+	require.NoError(t, err)
+	require.NotNil(t, cooldown)
+	assert.Equal(t, "SOL/USDT", cooldown.Symbol)
+	assert.Equal(t, now.Add(30*time.Minute), cooldown.Until)
+	require.Len(t, events.events, 1)
+	assert.Equal(t, "symbol_cooldown", events.events[0].data.(map[string]interface{})["type"])
+
+	active, cooling := tracker.SymbolCooldown(ctx, "binance", "SOL/USDT:USDT")
+	assert.True(t, cooling, "symbols match across spot and perp notation")
+	assert.Equal(t, SymbolCooldownStopOut, active.Reason)
+	_, cooling = tracker.SymbolCooldown(ctx, "bybit", "SOL/USDT")
+	assert.False(t, cooling, "cooldowns are per exchange")
+
+	*now = now.Add(30 * time.Minute)
+	_, cooling = tracker.SymbolCooldown(ctx, "binance", "SOL/USDT")
+	assert.False(t, cooling)
+	list, err := tracker.Active(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestSymbolCooldownTracker_KeepsLongerCooldown(t *testing.T) {
+	tracker, now := newTestSymbolCooldownTracker(t, SymbolCooldownConfig{AfterLoss: time.Hour, AfterExit: 10 * time.Minute})
+	ctx := t.Context()
+
+	_, err := tracker.RecordExit(ctx, "binance", "ETH/USDT", decimal.NewFromInt(-3), "")
+	require.NoError(t, err)
+	*now = now.Add(5 * time.Minute)
+	cooldown, err := tracker.RecordExit(ctx, "binance", "ETH/USDT", decimal.NewFromInt(2), "")
+	require.NoError(t, err)
+	assert.Equal(t, SymbolCooldownLoss, cooldown.Reason, "a shorter exit cooldown does not replace a loss cooldown")
+
+	_, err = tracker.RecordExit(ctx, "binance", "BTC/USDT", decimal.NewFromInt(2), "")
+	require.NoError(t, err)
+	list, err := tracker.Active(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "BTC/USDT", list[0].Symbol, "cooldowns ending soonest come first")
+	assert.Equal(t, SymbolCooldownExit, list[0].Reason)
+
+	require.NoError(t, tracker.Clear(ctx, "binance", "ETH/USDT"))
+	_, cooling := tracker.SymbolCooldown(ctx, "binance", "ETH/USDT")
+	assert.False(t, cooling)
+}
+
+func TestSymbolCooldownTracker_OnPositionClosed(t *testing.T) {
+	tracker, _ := newTestSymbolCooldownTracker(t, SymbolCooldownConfig{})
+	ctx := t.Context()
+
+	require.NoError(t, tracker.OnPositionClosed(ctx, interfaces.Position{
+		Exchange: "binance", Symbol: "DOGE/USDT", Status: interfaces.PositionStatusLiquidated, UnrealizedPL: decimal.NewFromInt(-40),
+	}))
+	cooldown, cooling := tracker.SymbolCooldown(ctx, "binance", "DOGE/USDT")
+	require.True(t, cooling)
+	assert.Equal(t, SymbolCooldownLiquidation, cooldown.Reason)
+
+	// The scalping engine drops cooling-down symbols before asking the model
+	svc := NewAIScalpingService(DefaultAIScalpingConfig(), nil, nil, nil, nil, nil)
+	svc.SetSymbolCooldownGuard(tracker)
+	signals := svc.withoutCoolingDownSymbols(ctx, []aiMarketSignal{{Symbol: "DOGE/USDT"}, {Symbol: "BTC/USDT"}})
+	require.Len(t, signals, 1)
+	assert.Equal(t, "BTC/USDT", signals[0].Symbol)
+}
@@ -0,0 +1,339 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/cache"
+	"github.com/irfndi/neuratrade/internal/models"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// freshnessTickersKey and freshnessCandlesKey hash the exchange:symbol
+	// pairs to the unix milliseconds of their last ticker and candle.
+	freshnessTickersKey = "freshness:tickers"
+	freshnessCandlesKey = "freshness:candles"
+)
+
+// SymbolFreshnessConfig sets the freshness SLAs of collected market data.
+type SymbolFreshnessConfig struct {
+	// TickerSLA is the oldest a pair's last ticker may be.
+	TickerSLA time.Duration
+	// CandleSLA is the oldest a pair's last candle may be, for pairs whose
+	// candles are fetched at all.
+	CandleSLA time.Duration
+	// Overrides replaces the ticker SLA of a symbol ("BTC/USDT") or of one
+	// exchange pair ("binance:BTC/USDT"), which takes precedence.
+	Overrides map[string]time.Duration
+	// Retention forgets pairs that have not been seen for this long, e.g.
+	// delisted or dropped symbols.
+	Retention time.Duration
+}
+
+// DefaultSymbolFreshnessConfig returns a 15 minute ticker SLA, a 1 hour
+// candle SLA and a 24 hour retention.
+func DefaultSymbolFreshnessConfig() SymbolFreshnessConfig {
+	return SymbolFreshnessConfig{
+		TickerSLA: 15 * time.Minute,
+		CandleSLA: time.Hour,
+		Retention: 24 * time.Hour,
+	}
+}
+
+// ParseFreshnessOverrides parses "BTC/USDT=5m,binance:ETH/USDT=2m" into
+// per-symbol ticker SLAs. Malformed entries are returned as an error
+// alongside the entries that parsed.
+func ParseFreshnessOverrides(raw string) (map[string]time.Duration, error) {
+	overrides := make(map[string]time.Duration)
+	var invalid []string
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		pair, value, ok := strings.Cut(entry, "=")
+		sla, err := time.ParseDuration(strings.TrimSpace(value))
+		if !ok || err != nil || sla <= 0 || strings.TrimSpace(pair) == "" {
+			invalid = append(invalid, entry)
+			continue
+		}
+		overrides[strings.TrimSpace(pair)] = sla
+	}
+	if len(invalid) > 0 {
+		return overrides, fmt.Errorf("invalid freshness SLA overrides: %s", strings.Join(invalid, ", "))
+	}
+	return overrides, nil
+}
+
+// SymbolFreshness is the freshness of one exchange pair.
+type SymbolFreshness struct {
+	Exchange   string     `json:"exchange"`
+	Symbol     string     `json:"symbol"`
+	LastTicker *time.Time `json:"last_ticker,omitempty"`
+	LastCandle *time.Time `json:"last_candle,omitempty"`
+	// TickerSLASeconds is the ticker SLA applied to the pair.
+	TickerSLASeconds int64 `json:"ticker_sla_seconds"`
+	Stale            bool  `json:"stale"`
+	// Reason explains why the pair is stale.
+	Reason string `json:"reason,omitempty"`
+}
+
+// StaleSymbolSource lists the pairs whose data breaches its freshness SLA.
+type StaleSymbolSource interface {
+	StaleSymbols(ctx context.Context) ([]SymbolFreshness, error)
+}
+
+// SymbolFreshnessTracker records the last successful ticker and candle of
+// every exchange pair and reports the pairs that breach their SLA. The
+// timestamps live in Redis so every collector replica and signal
+// processor sees the same view; without Redis they are kept in memory.
+type SymbolFreshnessTracker struct {
+	redis  *redis.Client
+	config SymbolFreshnessConfig
+	logger *slog.Logger
+	now    func() time.Time
+
+	mu     sync.Mutex
+	memory map[string]map[string]int64
+}
+
+// NewSymbolFreshnessTracker creates a freshness tracker.
+//
+// Parameters:
+//
+//	redisClient: Shared Redis client (may be nil for an in-memory tracker).
+//	config: SLA configuration (zero values use the defaults).
+//
+// Returns:
+//
+//	*SymbolFreshnessTracker: Initialized tracker.
+func NewSymbolFreshnessTracker(redisClient *redis.Client, config SymbolFreshnessConfig) *SymbolFreshnessTracker {
+	defaults := DefaultSymbolFreshnessConfig()
+	if config.TickerSLA <= 0 {
+		config.TickerSLA = defaults.TickerSLA
+	}
+	if config.CandleSLA <= 0 {
+		config.CandleSLA = defaults.CandleSLA
+	}
+	if config.Retention <= 0 {
+		config.Retention = defaults.Retention
+	}
+	return &SymbolFreshnessTracker{
+		redis:  redisClient,
+		config: config,
+		logger: telemetry.Logger(),
+		now:    time.Now,
+		memory: make(map[string]map[string]int64),
+	}
+}
+
+// Config returns the SLA configuration.
+func (t *SymbolFreshnessTracker) Config() SymbolFreshnessConfig {
+	return t.config
+}
+
+// RecordTickers records the tickers successfully collected from an
+// exchange, at the exchange timestamp of each ticker.
+func (t *SymbolFreshnessTracker) RecordTickers(ctx context.Context, exchange string, tickers []models.MarketPrice) {
+	if t == nil || len(tickers) == 0 {
+		return
+	}
+	observed := make(map[string]int64, len(tickers))
+	for _, ticker := range tickers {
+		at := ticker.Timestamp
+		if at.IsZero() {
+			at = t.now()
+		}
+		observed[cache.PairKey(exchange, ticker.Symbol)] = at.UnixMilli()
+	}
+	t.write(ctx, freshnessTickersKey, observed)
+}
+
+// RecordCandle records the open time of the latest candle fetched for a pair.
+func (t *SymbolFreshnessTracker) RecordCandle(ctx context.Context, exchange, symbol string, at time.Time) {
+	if t == nil || at.IsZero() {
+		return
+	}
+	t.write(ctx, freshnessCandlesKey, map[string]int64{cache.PairKey(exchange, symbol): at.UnixMilli()})
+}
+
+// Snapshot returns the freshness of every tracked pair, sorted by exchange
+// and symbol. Pairs not seen within the retention are forgotten.
+func (t *SymbolFreshnessTracker) Snapshot(ctx context.Context) ([]SymbolFreshness, error) {
+	tickers, err := t.read(ctx, freshnessTickersKey)
+	if err != nil {
+		return nil, err
+	}
+	candles, err := t.read(ctx, freshnessCandlesKey)
+	if err != nil {
+		return nil, err
+	}
+
+	now := t.now()
+	cutoff := now.Add(-t.config.Retention).UnixMilli()
+	pairs := make(map[string]bool, len(tickers))
+	for key := range tickers {
+		pairs[key] = true
+	}
+	for key := range candles {
+		pairs[key] = true
+	}
+
+	var expired []string
+	snapshot := make([]SymbolFreshness, 0, len(pairs))
+	for key := range pairs {
+		if max(tickers[key], candles[key]) < cutoff {
+			expired = append(expired, key)
+			continue
+		}
+		exchange, symbol, ok := cache.SplitPairKey(key)
+		if !ok {
+			continue
+		}
+		snapshot = append(snapshot, t.evaluate(now, exchange, symbol, tickers[key], candles[key]))
+	}
+	if len(expired) > 0 {
+		t.remove(ctx, expired)
+	}
+
+	sort.Slice(snapshot, func(i, j int) bool {
+		if snapshot[i].Exchange != snapshot[j].Exchange {
+			return snapshot[i].Exchange < snapshot[j].Exchange
+		}
+		return snapshot[i].Symbol < snapshot[j].Symbol
+	})
+	return snapshot, nil
+}
+
+// StaleSymbols returns the tracked pairs that breach their SLA.
+func (t *SymbolFreshnessTracker) StaleSymbols(ctx context.Context) ([]SymbolFreshness, error) {
+	snapshot, err := t.Snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stale := make([]SymbolFreshness, 0)
+	for _, freshness := range snapshot {
+		if freshness.Stale {
+			stale = append(stale, freshness)
+		}
+	}
+	return stale, nil
+}
+
+// TickerSLA returns the ticker SLA of a pair.
+func (t *SymbolFreshnessTracker) TickerSLA(exchange, symbol string) time.Duration {
+	if sla, ok := t.config.Overrides[cache.PairKey(exchange, symbol)]; ok {
+		return sla
+	}
+	if sla, ok := t.config.Overrides[symbol]; ok {
+		return sla
+	}
+	return t.config.TickerSLA
+}
+
+// evaluate applies the SLAs to one pair's timestamps.
+func (t *SymbolFreshnessTracker) evaluate(now time.Time, exchange, symbol string, tickerMs, candleMs int64) SymbolFreshness {
+	tickerSLA := t.TickerSLA(exchange, symbol)
+	freshness := SymbolFreshness{
+		Exchange:         exchange,
+		Symbol:           symbol,
+		TickerSLASeconds: int64(tickerSLA.Seconds()),
+	}
+	var reasons []string
+	if tickerMs > 0 {
+		lastTicker := time.UnixMilli(tickerMs).UTC()
+		freshness.LastTicker = &lastTicker
+		if age := now.Sub(lastTicker); age > tickerSLA {
+			reasons = append(reasons, fmt.Sprintf("ticker %s old (SLA %s)", age.Round(time.Second), tickerSLA))
+		}
+	} else {
+		reasons = append(reasons, "no ticker collected")
+	}
+	if candleMs > 0 {
+		lastCandle := time.UnixMilli(candleMs).UTC()
+		freshness.LastCandle = &lastCandle
+		if age := now.Sub(lastCandle); age > t.config.CandleSLA {
+			reasons = append(reasons, fmt.Sprintf("candle %s old (SLA %s)", age.Round(time.Second), t.config.CandleSLA))
+		}
+	}
+	if len(reasons) > 0 {
+		freshness.Stale = true
+		freshness.Reason = strings.Join(reasons, "; ")
+	}
+	return freshness
+}
+
+// write stores observation timestamps; failures are logged, as freshness
+// must never block collection.
+func (t *SymbolFreshnessTracker) write(ctx context.Context, key string, observed map[string]int64) {
+	if t.redis == nil {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if t.memory[key] == nil {
+			t.memory[key] = make(map[string]int64)
+		}
+		for pair, at := range observed {
+			t.memory[key][pair] = at
+		}
+		return
+	}
+	values := make(map[string]interface{}, len(observed))
+	for pair, at := range observed {
+		values[pair] = at
+	}
+	if err := t.redis.HSet(ctx, key, values).Err(); err != nil {
+		t.logger.Warn("Failed to record symbol freshness", "key", key, "pairs", len(observed), "error", err)
+	}
+}
+
+// read loads the observation timestamps of one kind.
+func (t *SymbolFreshnessTracker) read(ctx context.Context, key string) (map[string]int64, error) {
+	if t.redis == nil {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		observed := make(map[string]int64, len(t.memory[key]))
+		for pair, at := range t.memory[key] {
+			observed[pair] = at
+		}
+		return observed, nil
+	}
+	values, err := t.redis.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load symbol freshness: %w", err)
+	}
+	observed := make(map[string]int64, len(values))
+	for pair, raw := range values {
+		if at, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			observed[pair] = at
+		}
+	}
+	return observed, nil
+}
+
+// remove forgets pairs past the retention.
+func (t *SymbolFreshnessTracker) remove(ctx context.Context, pairs []string) {
+	if t.redis == nil {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		for _, observed := range t.memory {
+			for _, pair := range pairs {
+				delete(observed, pair)
+			}
+		}
+		return
+	}
+	pipe := t.redis.Pipeline()
+	pipe.HDel(ctx, freshnessTickersKey, pairs...)
+	pipe.HDel(ctx, freshnessCandlesKey, pairs...)
+	if _, err := pipe.Exec(ctx); err != nil {
+		t.logger.Warn("Failed to prune symbol freshness", "pairs", len(pairs), "error", err)
+	}
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/irfndi/neuratrade/internal/models"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFreshnessOverrides(t *testing.T) {
+	overrides, err := ParseFreshnessOverrides(" BTC/USDT=5m, binance:ETH/USDT=2m ,,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{"BTC/USDT": 5 * time.Minute, "binance:ETH/USDT": 2 * time.Minute}, overrides)
+
+	overrides, err = ParseFreshnessOverrides("BTC/USDT=soon,SOL/USDT=1m,=2m")
+	assert.ErrorContains(t, err, "BTC/USDT=soon")
+	assert.Equal(t, map[string]time.Duration{"SOL/USDT": time.Minute}, overrides)
+}
+
+func TestSymbolFreshnessTracker_StaleSymbols(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	newTracker := func() *SymbolFreshnessTracker {
+		tracker := NewSymbolFreshnessTracker(client, SymbolFreshnessConfig{
+			TickerSLA: 10 * time.Minute,
+			CandleSLA: time.Hour,
+			Overrides: map[string]time.Duration{"BTC/USDT": 2 * time.Minute, "kraken:BTC/USDT": 30 * time.Minute},
+		})
+		tracker.now = func() time.Time { return now }
+		return tracker
+	}
+	collector, reader := newTracker(), newTracker()
+
+	collector.RecordTickers(ctx, "binance", []models.MarketPrice{
+		{Symbol: "BTC/USDT", Timestamp: now.Add(-5 * time.Minute)},
+		{Symbol: "ETH/USDT", Timestamp: now.Add(-5 * time.Minute)},
+		{Symbol: "SOL/USDT", Timestamp: now.Add(-time.Minute)},
+	})
+	collector.RecordTickers(ctx, "kraken", []models.MarketPrice{{Symbol: "BTC/USDT", Timestamp: now.Add(-5 * time.Minute)}})
+	collector.RecordCandle(ctx, "binance", "SOL/USDT", now.Add(-2*time.Hour))
+	collector.RecordTickers(ctx, "binance", []models.MarketPrice{{Symbol: "DELISTED/USDT", Timestamp: now.Add(-48 * time.Hour)}})
+
+	// Another instance sharing Redis sees the same view
+	snapshot, err := reader.Snapshot(ctx)
+	require.NoError(t, err)
+	require.Len(t, snapshot, 4, "pairs past the retention are forgotten")
+	assert.Equal(t, "binance", snapshot[0].Exchange)
+	assert.Equal(t, "BTC/USDT", snapshot[0].Symbol)
+	assert.Equal(t, int64(120), snapshot[0].TickerSLASeconds)
+	assert.Empty(t, mr.HGet(freshnessTickersKey, "binance:DELISTED/USDT"))
+
+	stale, err := reader.StaleSymbols(ctx)
+	require.NoError(t, err)
+	require.Len(t, stale, 2)
+	assert.Equal(t, "BTC/USDT", stale[0].Symbol, "the symbol override tightens the SLA")
+	assert.Equal(t, "ticker 5m0s old (SLA 2m0s)", stale[0].Reason)
+	assert.Equal(t, "SOL/USDT", stale[1].Symbol)
+	assert.Equal(t, "candle 2h0m0s old (SLA 1h0m0s)", stale[1].Reason)
+
+	// A fresh ticker clears the breach
+	collector.RecordTickers(ctx, "binance", []models.MarketPrice{{Symbol: "BTC/USDT", Timestamp: now}})
+	stale, err = reader.StaleSymbols(ctx)
+	require.NoError(t, err)
+	assert.Len(t, stale, 1)
+
+	var none *SymbolFreshnessTracker
+	none.RecordTickers(ctx, "binance", []models.MarketPrice{{Symbol: "BTC/USDT"}})
+	none.RecordCandle(ctx, "binance", "BTC/USDT", now)
+}
+
+func TestSymbolFreshnessTracker_InMemory(t *testing.T) {
+	tracker := NewSymbolFreshnessTracker(nil, SymbolFreshnessConfig{})
+	ctx := context.Background()
+	tracker.RecordTickers(ctx, "binance", []models.MarketPrice{{Symbol: "BTC/USDT:USDT", Timestamp: time.Now().Add(-time.Hour)}})
+
+	stale, err := tracker.StaleSymbols(ctx)
+	require.NoError(t, err)
+	require.Len(t, stale, 1)
+	assert.Equal(t, "BTC/USDT:USDT", stale[0].Symbol)
+	assert.Equal(t, int64(900), stale[0].TickerSLASeconds)
+}
@@ -0,0 +1,302 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/cache"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+)
+
+// Quarantine triggers, recorded as the reason prefix of a quarantine entry.
+const (
+	QuarantineTriggerOrderRejection = "order_rejection"
+	QuarantineTriggerStaleData      = "stale_data"
+	QuarantineTriggerAnomaly        = "anomaly"
+	QuarantineTriggerManual         = "manual"
+)
+
+const (
+	quarantineReasonPrefix = "quarantine:"
+
+	defaultQuarantineWindow             = 15 * time.Minute
+	defaultQuarantineTTL                = time.Hour
+	defaultQuarantineMaxOrderRejections = 3
+	defaultQuarantineMaxStaleData       = 3
+	defaultQuarantineMaxAnomalies       = 5
+	defaultQuarantineMaxTracked         = 10000
+)
+
+// SymbolQuarantineConfig configures automatic quarantine of exchange/symbol pairs.
+type SymbolQuarantineConfig struct {
+	// Window is how far back strikes against a pair are counted.
+	Window time.Duration
+	// TTL is how long a quarantined pair stays blacklisted.
+	TTL time.Duration
+	// MaxOrderRejections is the number of rejected orders within the window
+	// that quarantines a pair.
+	MaxOrderRejections int
+	// MaxStaleData is the number of stale tickers within the window that
+	// quarantines a pair.
+	MaxStaleData int
+	// MaxAnomalies is the number of anomaly-filter trips within the window
+	// that quarantines a pair.
+	MaxAnomalies int
+	// NotifyChatIDs are the Telegram chats told about quarantines.
+	NotifyChatIDs []int64
+}
+
+// QuarantinedPair is an exchange/symbol pair currently blacklisted.
+type QuarantinedPair struct {
+	Exchange  string     `json:"exchange"`
+	Symbol    string     `json:"symbol"`
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// SymbolQuarantine counts order rejections, stale data and anomaly-filter
+// trips per exchange/symbol pair and blacklists a pair for a TTL once any of
+// them repeats too often within the window. Quarantined pairs use the same
+// "exchange:symbol" blacklist keys as the collector, so they stop being
+// collected and scanned until they expire or are released.
+type SymbolQuarantine struct {
+	blacklist cache.BlacklistCache
+	config    SymbolQuarantineConfig
+	// mu serialises strike updates; strikes holds the recent strike times
+	// per trigger and pair.
+	mu        sync.Mutex
+	strikes   *cache.Bounded[string, []time.Time]
+	events    EventEmitter
+	messenger DirectMessenger
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+// NewSymbolQuarantine creates a symbol quarantine.
+//
+// Parameters:
+//
+//	blacklist: Blacklist cache quarantined pairs are added to.
+//	config: Quarantine configuration; zero values use defaults.
+//
+// Returns:
+//
+//	*SymbolQuarantine: Initialized quarantine.
+func NewSymbolQuarantine(blacklist cache.BlacklistCache, config SymbolQuarantineConfig) *SymbolQuarantine {
+	if config.Window <= 0 {
+		config.Window = defaultQuarantineWindow
+	}
+	if config.TTL <= 0 {
+		config.TTL = defaultQuarantineTTL
+	}
+	if config.MaxOrderRejections <= 0 {
+		config.MaxOrderRejections = defaultQuarantineMaxOrderRejections
+	}
+	if config.MaxStaleData <= 0 {
+		config.MaxStaleData = defaultQuarantineMaxStaleData
+	}
+	if config.MaxAnomalies <= 0 {
+		config.MaxAnomalies = defaultQuarantineMaxAnomalies
+	}
+	return &SymbolQuarantine{
+		blacklist: blacklist,
+		config:    config,
+		strikes: cache.NewBounded[string, []time.Time](cache.BoundedConfig{
+			Name:       "blacklist.quarantine_strikes",
+			MaxEntries: defaultQuarantineMaxTracked,
+			TTL:        config.Window,
+		}),
+		logger: telemetry.Logger(),
+		now:    time.Now,
+	}
+}
+
+// SetEventEmitter publishes quarantine risk events.
+func (q *SymbolQuarantine) SetEventEmitter(events EventEmitter) {
+	q.events = events
+}
+
+// SetMessenger sets the Telegram sender used for quarantine notifications.
+func (q *SymbolQuarantine) SetMessenger(messenger DirectMessenger) {
+	q.messenger = messenger
+}
+
+// RecordStrike counts one problem with a pair and quarantines the pair when
+// the trigger's threshold is reached within the window.
+//
+// Parameters:
+//
+//	ctx: Context for notifications.
+//	trigger: One of the QuarantineTrigger constants.
+//	exchange: The exchange name.
+//	symbol: The trading pair.
+//	detail: What went wrong, included in the quarantine reason.
+//
+// Returns:
+//
+//	bool: True if this strike quarantined the pair.
+func (q *SymbolQuarantine) RecordStrike(ctx context.Context, trigger, exchange, symbol, detail string) bool {
+	if q == nil || exchange == "" || symbol == "" {
+		return false
+	}
+	threshold := q.threshold(trigger)
+	if threshold <= 0 {
+		return false
+	}
+	key := cache.PairKey(exchange, symbol)
+	if quarantined, _ := q.blacklist.IsBlacklisted(key); quarantined {
+		return false
+	}
+
+	q.mu.Lock()
+	now := q.now()
+	strikeKey := trigger + "|" + key
+	previous, _ := q.strikes.Get(strikeKey)
+	cutoff := now.Add(-q.config.Window)
+	recent := make([]time.Time, 0, len(previous)+1)
+	for _, at := range previous {
+		if at.After(cutoff) {
+			recent = append(recent, at)
+		}
+	}
+	recent = append(recent, now)
+	if len(recent) < threshold {
+		q.strikes.Set(strikeKey, recent)
+		q.mu.Unlock()
+		return false
+	}
+	q.strikes.Delete(strikeKey)
+	q.mu.Unlock()
+
+	reason := fmt.Sprintf("%d× %s within %s", len(recent), strings.ReplaceAll(trigger, "_", " "), q.config.Window)
+	if detail != "" {
+		reason += ": " + detail
+	}
+	q.quarantine(ctx, trigger, exchange, symbol, reason, q.config.TTL)
+	return true
+}
+
+// Quarantine blacklists a pair manually.
+//
+// Parameters:
+//
+//	ctx: Context for notifications.
+//	exchange: The exchange name.
+//	symbol: The trading pair.
+//	reason: Why the pair is quarantined.
+//	ttl: How long the pair stays quarantined; zero or less uses the configured TTL.
+func (q *SymbolQuarantine) Quarantine(ctx context.Context, exchange, symbol, reason string, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = q.config.TTL
+	}
+	if reason == "" {
+		reason = "manual override"
+	}
+	q.quarantine(ctx, QuarantineTriggerManual, exchange, symbol, reason, ttl)
+}
+
+// Release lifts a pair's quarantine and forgets its strikes.
+//
+// Parameters:
+//
+//	exchange: The exchange name.
+//	symbol: The trading pair.
+//
+// Returns:
+//
+//	bool: True if the pair was blacklisted.
+func (q *SymbolQuarantine) Release(exchange, symbol string) bool {
+	key := cache.PairKey(exchange, symbol)
+	blacklisted, _ := q.blacklist.IsBlacklisted(key)
+	q.blacklist.Remove(key)
+	for _, trigger := range []string{QuarantineTriggerOrderRejection, QuarantineTriggerStaleData, QuarantineTriggerAnomaly} {
+		q.strikes.Delete(trigger + "|" + key)
+	}
+	if blacklisted {
+		q.logger.Info("Released symbol quarantine", "exchange", exchange, "symbol", symbol)
+	}
+	return blacklisted
+}
+
+// IsQuarantined reports whether a pair is blacklisted.
+func (q *SymbolQuarantine) IsQuarantined(exchange, symbol string) (bool, string) {
+	if q == nil {
+		return false, ""
+	}
+	return q.blacklist.IsBlacklisted(cache.PairKey(exchange, symbol))
+}
+
+// List returns every blacklisted exchange/symbol pair, sorted by exchange and
+// symbol. Pairs the collector blacklisted directly are included, so they can
+// be released too.
+func (q *SymbolQuarantine) List() ([]QuarantinedPair, error) {
+	entries, err := q.blacklist.GetBlacklistedSymbols()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blacklisted symbols: %w", err)
+	}
+	pairs := make([]QuarantinedPair, 0, len(entries))
+	for _, entry := range entries {
+		exchange, symbol, ok := cache.SplitPairKey(entry.Symbol)
+		if !ok {
+			continue
+		}
+		pairs = append(pairs, QuarantinedPair{
+			Exchange:  exchange,
+			Symbol:    symbol,
+			Reason:    entry.Reason,
+			ExpiresAt: entry.ExpiresAt,
+			CreatedAt: entry.CreatedAt,
+		})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Exchange != pairs[j].Exchange {
+			return pairs[i].Exchange < pairs[j].Exchange
+		}
+		return pairs[i].Symbol < pairs[j].Symbol
+	})
+	return pairs, nil
+}
+
+func (q *SymbolQuarantine) threshold(trigger string) int {
+	switch trigger {
+	case QuarantineTriggerOrderRejection:
+		return q.config.MaxOrderRejections
+	case QuarantineTriggerStaleData:
+		return q.config.MaxStaleData
+	case QuarantineTriggerAnomaly:
+		return q.config.MaxAnomalies
+	default:
+		return 0
+	}
+}
+
+func (q *SymbolQuarantine) quarantine(ctx context.Context, trigger, exchange, symbol, reason string, ttl time.Duration) {
+	q.blacklist.Add(cache.PairKey(exchange, symbol), quarantineReasonPrefix+trigger+": "+reason, ttl)
+	q.logger.Warn("Quarantined symbol", "exchange", exchange, "symbol", symbol, "trigger", trigger, "reason", reason, "ttl", ttl)
+
+	if q.events != nil {
+		q.events.Emit(ctx, WebhookEventRisk, map[string]interface{}{
+			"type":     "symbol_quarantined",
+			"exchange": exchange,
+			"symbol":   symbol,
+			"trigger":  trigger,
+			"reason":   reason,
+			"ttl":      ttl.String(),
+		})
+	}
+	if q.messenger == nil {
+		return
+	}
+	text := fmt.Sprintf("🚫 %s on %s quarantined for %s: %s.", symbol, exchange, ttl, reason)
+	for _, chatID := range q.config.NotifyChatIDs {
+		if err := q.messenger.SendDirectMessage(ctx, chatID, text); err != nil {
+			q.logger.Warn("Failed to send quarantine notification", "chat_id", chatID, "error", err)
+		}
+	}
+}
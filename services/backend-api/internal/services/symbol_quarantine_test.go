@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfndi/neuratrade/internal/cache"
+)
+
+func TestSymbolQuarantine_RecordStrike(t *testing.T) {
+	blacklist := cache.NewInMemoryBlacklistCache()
+	messenger := &recordingMessenger{}
+	quarantine := NewSymbolQuarantine(blacklist, SymbolQuarantineConfig{
+		Window:        10 * time.Minute,
+		TTL:           time.Hour,
+		MaxStaleData:  2,
+		NotifyChatIDs: []int64{7},
+	})
+	quarantine.SetMessenger(messenger)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	quarantine.now = func() time.Time { return now }
+
+	assert.False(t, quarantine.RecordStrike(t.Context(), QuarantineTriggerStaleData, "binance", "BTC/USDT", ""))
+	assert.False(t, quarantine.RecordStrike(t.Context(), QuarantineTriggerAnomaly, "binance", "BTC/USDT", ""), "triggers are counted separately")
+
+	// A strike outside the window does not count
+	now = now.Add(11 * time.Minute)
+	assert.False(t, quarantine.RecordStrike(t.Context(), QuarantineTriggerStaleData, "binance", "BTC/USDT", ""))
+	assert.Empty(t, messenger.texts)
+
+	now = now.Add(time.Minute)
+	assert.True(t, quarantine.RecordStrike(t.Context(), QuarantineTriggerStaleData, "binance", "BTC/USDT", "no update for 2h"))
+	quarantined, reason := quarantine.IsQuarantined("binance", "BTC/USDT")
+	assert.True(t, quarantined)
+	assert.Equal(t, "quarantine:stale_data: 2× stale data within 10m0s: no update for 2h", reason)
+	assert.Equal(t, []int64{7}, messenger.chats)
+	assert.Contains(t, messenger.texts[0], "BTC/USDT on binance quarantined for 1h0m0s")
+
+	// Other exchanges keep trading the symbol
+	quarantined, _ = quarantine.IsQuarantined("kraken", "BTC/USDT")
+	assert.False(t, quarantined)
+	assert.False(t, quarantine.RecordStrike(t.Context(), QuarantineTriggerStaleData, "binance", "BTC/USDT", ""), "already quarantined")
+	assert.False(t, quarantine.RecordStrike(t.Context(), QuarantineTriggerManual, "kraken", "BTC/USDT", ""), "manual is not a strike")
+}
+
+func TestSymbolQuarantine_ManualOverride(t *testing.T) {
+	blacklist := cache.NewInMemoryBlacklistCache()
+	blacklist.Add("kraken", "exchange blacklisted", time.Hour)
+	quarantine := NewSymbolQuarantine(blacklist, SymbolQuarantineConfig{})
+
+	quarantine.Quarantine(t.Context(), "okx", "SOL/USDT", "", 0)
+	quarantine.Quarantine(t.Context(), "binance", "ETH/USDT", "delisting", 2*time.Hour)
+
+	pairs, err := quarantine.List()
+	require.NoError(t, err)
+	require.Len(t, pairs, 2, "exchange-wide entries are not pairs")
+	assert.Equal(t, "binance", pairs[0].Exchange)
+	assert.Equal(t, "ETH/USDT", pairs[0].Symbol)
+	assert.Equal(t, "quarantine:manual: delisting", pairs[0].Reason)
+	require.NotNil(t, pairs[0].ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(2*time.Hour), *pairs[0].ExpiresAt, time.Minute)
+	assert.Equal(t, "quarantine:manual: manual override", pairs[1].Reason)
+
+	assert.True(t, quarantine.Release("binance", "ETH/USDT"))
+	assert.False(t, quarantine.Release("binance", "ETH/USDT"))
+	pairs, err = quarantine.List()
+	require.NoError(t, err)
+	assert.Len(t, pairs, 1)
+}
+
+func TestCCXTOrderExecutor_QuarantinesRejectedPair(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	executor := NewCCXTOrderExecutor(CCXTOrderExecutorConfig{ServiceURL: server.URL, Timeout: 5 * time.Second})
+	quarantine := NewSymbolQuarantine(cache.NewInMemoryBlacklistCache(), SymbolQuarantineConfig{MaxOrderRejections: 2})
+	executor.SetQuarantine(quarantine)
+
+	for range 2 {
+		_, err := executor.PlaceOrder(context.Background(), "binance", "BTC/USDT", "buy", "market", decimal.NewFromFloat(0.5), nil)
+		require.ErrorIs(t, err, ErrOrderRejected)
+		assert.EqualError(t, err, "order placement failed with status: 400")
+	}
+	quarantined, _ := quarantine.IsQuarantined("binance", "BTC/USDT")
+	assert.True(t, quarantined)
+}
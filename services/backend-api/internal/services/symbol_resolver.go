@@ -0,0 +1,380 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultSymbolQuote is appended to a bare asset such as "btc".
+	DefaultSymbolQuote    = "USDT"
+	symbolMarketsCacheTTL = time.Hour
+	maxSymbolSuggestions  = 3
+)
+
+// ErrUnknownSymbol is wrapped by SymbolNotFoundError.
+var ErrUnknownSymbol = errors.New("unknown symbol")
+
+// symbolQuoteCurrencies are split off compact symbols such as BTCUSDT, most
+// common first: XBTUSD reads as XBT/USD before XB/TUSD.
+var symbolQuoteCurrencies = []string{"USDT", "USDC", "FDUSD", "BUSD", "USD", "DAI", "EUR", "GBP", "TRY", "BTC", "ETH", "BNB", "TUSD"}
+
+// defaultSymbolAliases map asset codes users type to the unified codes, by
+// exchange; "*" applies to every exchange.
+var defaultSymbolAliases = map[string]map[string]string{
+	"*": {
+		"XBT":      "BTC",
+		"BITCOIN":  "BTC",
+		"ETHER":    "ETH",
+		"ETHEREUM": "ETH",
+		"SOLANA":   "SOL",
+		"DOGECOIN": "DOGE",
+		"RIPPLE":   "XRP",
+	},
+	"kraken": {
+		"XXBT": "BTC",
+		"XETH": "ETH",
+		"XDG":  "DOGE",
+		"ZUSD": "USD",
+		"ZEUR": "EUR",
+	},
+	"bitfinex": {
+		"UST": "USDT",
+		"DSH": "DASH",
+		"IOT": "IOTA",
+	},
+}
+
+// SymbolNotFoundError reports a symbol an exchange does not list, with the
+// closest listed symbols.
+type SymbolNotFoundError struct {
+	Symbol      string
+	Exchange    string
+	Suggestions []string
+}
+
+func (e *SymbolNotFoundError) Error() string {
+	message := fmt.Sprintf("unknown symbol %q", e.Symbol)
+	if e.Exchange != "" {
+		message += " on " + e.Exchange
+	}
+	if len(e.Suggestions) > 0 {
+		message += "; did you mean " + strings.Join(e.Suggestions, ", ") + "?"
+	}
+	return message
+}
+
+func (e *SymbolNotFoundError) Unwrap() error { return ErrUnknownSymbol }
+
+// SymbolSuggestions returns the closest listed symbols.
+func (e *SymbolNotFoundError) SymbolSuggestions() []string { return e.Suggestions }
+
+type cachedMarketSymbols struct {
+	set       map[string]struct{}
+	symbols   []string
+	fetchedAt time.Time
+}
+
+// SymbolResolver turns the symbols users type (BTCUSDT, btc-usdt, XBT/USD,
+// bitcoin) into an exchange's unified BASE/QUOTE symbol.
+type SymbolResolver struct {
+	markets MarketLister
+	aliases map[string]map[string]string
+
+	mu    sync.Mutex
+	cache map[string]cachedMarketSymbols
+	now   func() time.Time
+}
+
+// NewSymbolResolver creates a symbol resolver.
+//
+// Parameters:
+//
+//	markets: Market lister used to check symbols exist (may be nil).
+//	aliases: Asset aliases by exchange ("*" for all), merged over the defaults.
+//
+// Returns:
+//
+//	*SymbolResolver: The initialized resolver.
+func NewSymbolResolver(markets MarketLister, aliases map[string]map[string]string) *SymbolResolver {
+	merged := make(map[string]map[string]string, len(defaultSymbolAliases)+len(aliases))
+	for _, source := range []map[string]map[string]string{defaultSymbolAliases, aliases} {
+		for exchange, codes := range source {
+			exchange = strings.ToLower(exchange)
+			if merged[exchange] == nil {
+				merged[exchange] = make(map[string]string, len(codes))
+			}
+			for alias, code := range codes {
+				merged[exchange][strings.ToUpper(alias)] = strings.ToUpper(code)
+			}
+		}
+	}
+	return &SymbolResolver{
+		markets: markets,
+		aliases: merged,
+		cache:   make(map[string]cachedMarketSymbols),
+		now:     time.Now,
+	}
+}
+
+// NormalizeSymbol brings a symbol into BASE/QUOTE form with the aliases that
+// apply to every exchange, without checking that it is listed.
+//
+// Parameters:
+//
+//	symbol: Symbol as typed.
+//
+// Returns:
+//
+//	string: The normalized symbol, empty for an empty input.
+func NormalizeSymbol(symbol string) string {
+	candidates := symbolCandidates(symbol, defaultSymbolAliases["*"], nil)
+	if len(candidates) == 0 {
+		return ""
+	}
+	return candidates[0]
+}
+
+// Normalize brings a symbol into BASE/QUOTE form with the aliases of an
+// exchange, without checking that it is listed.
+//
+// Parameters:
+//
+//	exchange: Exchange the symbol is meant for (may be empty).
+//	symbol: Symbol as typed.
+//
+// Returns:
+//
+//	string: The normalized symbol, empty for an empty input.
+func (r *SymbolResolver) Normalize(exchange, symbol string) string {
+	candidates := symbolCandidates(symbol, r.aliases["*"], r.aliases[strings.ToLower(exchange)])
+	if len(candidates) == 0 {
+		return ""
+	}
+	return candidates[0]
+}
+
+// Resolve normalizes a symbol and, when the exchange's markets can be
+// listed, checks it is listed there. A perpetual BASE/QUOTE:QUOTE is
+// accepted for venues that only list the contract.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	exchange: Exchange the symbol is meant for (may be empty).
+//	symbol: Symbol as typed.
+//
+// Returns:
+//
+//	string: The unified symbol, empty for an empty input.
+//	error: *SymbolNotFoundError if the exchange does not list it.
+func (r *SymbolResolver) Resolve(ctx context.Context, exchange, symbol string) (string, error) {
+	exchange = strings.ToLower(strings.TrimSpace(exchange))
+	candidates := symbolCandidates(symbol, r.aliases["*"], r.aliases[exchange])
+	if len(candidates) == 0 {
+		return "", nil
+	}
+	markets, ok := r.marketSymbols(ctx, exchange)
+	if !ok {
+		return candidates[0], nil
+	}
+	for _, candidate := range candidates {
+		if _, listed := markets.set[candidate]; listed {
+			return candidate, nil
+		}
+		if !strings.Contains(candidate, ":") {
+			perpetual := candidate + ":" + candidate[strings.Index(candidate, "/")+1:]
+			if _, listed := markets.set[perpetual]; listed {
+				return perpetual, nil
+			}
+		}
+	}
+	return "", &SymbolNotFoundError{
+		Symbol:      strings.TrimSpace(symbol),
+		Exchange:    exchange,
+		Suggestions: suggestSymbols(symbol, candidates[0], markets.symbols, maxSymbolSuggestions),
+	}
+}
+
+// marketSymbols returns the cached markets of an exchange. Resolution
+// falls back to normalizing only when they cannot be listed.
+func (r *SymbolResolver) marketSymbols(ctx context.Context, exchange string) (cachedMarketSymbols, bool) {
+	if r.markets == nil || exchange == "" {
+		return cachedMarketSymbols{}, false
+	}
+	r.mu.Lock()
+	cached, ok := r.cache[exchange]
+	r.mu.Unlock()
+	if ok && r.now().Sub(cached.fetchedAt) < symbolMarketsCacheTTL {
+		return cached, true
+	}
+
+	response, err := r.markets.FetchMarkets(ctx, exchange)
+	if err != nil || response == nil || len(response.Symbols) == 0 {
+		return cached, ok
+	}
+	cached = cachedMarketSymbols{
+		set:       make(map[string]struct{}, len(response.Symbols)),
+		symbols:   response.Symbols,
+		fetchedAt: r.now(),
+	}
+	for _, listed := range response.Symbols {
+		cached.set[listed] = struct{}{}
+	}
+	r.mu.Lock()
+	r.cache[exchange] = cached
+	r.mu.Unlock()
+	return cached, true
+}
+
+// symbolCandidates returns the BASE/QUOTE readings of a symbol, most likely
+// first. A compact symbol can split more than one way (STETH is ST/ETH or
+// STETH/USDT), so every reading is kept for the market check.
+func symbolCandidates(symbol string, aliases, exchangeAliases map[string]string) []string {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" {
+		return nil
+	}
+	pair, settle, hasSettle := strings.Cut(symbol, ":")
+	pair = strings.NewReplacer("-", "/", "_", "/", " ", "/").Replace(pair)
+
+	var splits [][2]string
+	if base, quote, found := strings.Cut(pair, "/"); found {
+		splits = append(splits, [2]string{base, quote})
+	} else {
+		for _, quote := range symbolQuoteCurrencies {
+			base, found := strings.CutSuffix(pair, quote)
+			if found && base != "" {
+				splits = append(splits, [2]string{base, quote})
+			}
+		}
+		splits = append(splits, [2]string{pair, DefaultSymbolQuote})
+	}
+
+	alias := func(code string) string {
+		if canonical, ok := exchangeAliases[code]; ok {
+			return canonical
+		}
+		if canonical, ok := aliases[code]; ok {
+			return canonical
+		}
+		return code
+	}
+	candidates := make([]string, 0, len(splits))
+	for _, split := range splits {
+		candidate := alias(split[0]) + "/" + alias(split[1])
+		if hasSettle && settle != "" {
+			candidate += ":" + alias(settle)
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates
+}
+
+// suggestSymbols returns the listed symbols closest by edit distance to the
+// symbol as typed or its normalized reading, preferring those with the same
+// base asset.
+func suggestSymbols(input, normalized string, listed []string, limit int) []string {
+	compact := func(s string) string {
+		pair, _, _ := strings.Cut(strings.ToUpper(strings.TrimSpace(s)), ":")
+		return strings.NewReplacer("/", "", "-", "", "_", "", " ", "").Replace(pair)
+	}
+	typed, target := compact(input), compact(normalized)
+	base, _, _ := strings.Cut(normalized, "/")
+	maxDistance := max(2, len(typed)/3)
+
+	type scored struct {
+		symbol   string
+		distance int
+	}
+	var matches []scored
+	seen := make(map[string]struct{})
+	for _, candidate := range listed {
+		if _, dup := seen[candidate]; dup {
+			continue
+		}
+		seen[candidate] = struct{}{}
+		listedCompact := compact(candidate)
+		distance := min(levenshtein(typed, listedCompact), levenshtein(target, listedCompact))
+		if candidateBase, _, _ := strings.Cut(candidate, "/"); candidateBase == base {
+			distance--
+		}
+		if distance <= maxDistance {
+			matches = append(matches, scored{symbol: candidate, distance: distance})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].symbol < matches[j].symbol
+	})
+	suggestions := make([]string, 0, min(limit, len(matches)))
+	for _, match := range matches[:min(limit, len(matches))] {
+		suggestions = append(suggestions, match.symbol)
+	}
+	return suggestions
+}
+
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// ParseSymbolAliases parses "exchange:ALIAS=CODE,ALIAS=CODE;..." where
+// exchange "*" applies to every exchange.
+//
+// Parameters:
+//
+//	raw: Alias specification.
+//
+// Returns:
+//
+//	map[string]map[string]string: Aliases by exchange.
+//	error: Error if an entry is malformed.
+func ParseSymbolAliases(raw string) (map[string]map[string]string, error) {
+	aliases := make(map[string]map[string]string)
+	for _, entry := range strings.Split(raw, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		exchange, codes, found := strings.Cut(entry, ":")
+		exchange = strings.ToLower(strings.TrimSpace(exchange))
+		if !found || exchange == "" {
+			return nil, fmt.Errorf("symbol alias entry %q must be exchange:ALIAS=CODE", entry)
+		}
+		for _, pair := range strings.Split(codes, ",") {
+			alias, code, found := strings.Cut(pair, "=")
+			alias, code = strings.ToUpper(strings.TrimSpace(alias)), strings.ToUpper(strings.TrimSpace(code))
+			if !found || alias == "" || code == "" {
+				return nil, fmt.Errorf("symbol alias %q must be ALIAS=CODE", strings.TrimSpace(pair))
+			}
+			if aliases[exchange] == nil {
+				aliases[exchange] = make(map[string]string)
+			}
+			aliases[exchange][alias] = code
+		}
+	}
+	return aliases, nil
+}
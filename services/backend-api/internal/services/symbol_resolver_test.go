@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeSymbol(t *testing.T) {
+	cases := map[string]string{
+		"BTCUSDT":       "BTC/USDT",
+		" btc-usdt ":    "BTC/USDT",
+		"eth_btc":       "ETH/BTC",
+		"XBT/USD":       "BTC/USD",
+		"XBTUSD":        "BTC/USD",
+		"sol/usdt:usdt": "SOL/USDT:USDT",
+		"btc":           "BTC/USDT",
+		"bitcoin":       "BTC/USDT",
+		"":              "",
+	}
+	for input, expected := range cases {
+		assert.Equal(t, expected, NormalizeSymbol(input), input)
+	}
+}
+
+func TestSymbolResolver_Resolve(t *testing.T) {
+	markets := &fakeMarketLister{symbols: map[string][]string{
+		"kraken":  {"BTC/USD", "DOGE/USD", "ETH/USD"},
+		"binance": {"BTC/USDT", "BTC/USDC", "ETH/USDT", "STETH/USDT"},
+		"bybit":   {"BTC/USDT:USDT"},
+	}}
+	resolver := NewSymbolResolver(markets, map[string]map[string]string{"kraken": {"XXDG": "DOGE"}})
+	ctx := context.Background()
+
+	for _, tc := range []struct{ exchange, input, expected string }{
+		{"kraken", "XBTUSD", "BTC/USD"},
+		{"kraken", "xdg-usd", "DOGE/USD"},
+		{"kraken", "XXDGUSD", "DOGE/USD"},
+		{"binance", "btcusdt", "BTC/USDT"},
+		// STETH reads as ST/ETH first, which binance does not list
+		{"binance", "STETH", "STETH/USDT"},
+		// Venues that only list the perpetual take the spot spelling
+		{"bybit", "BTCUSDT", "BTC/USDT:USDT"},
+		// Without markets the symbol is only normalized
+		{"unknown", "DOGEUSDT", "DOGE/USDT"},
+		{"", "ethusdt", "ETH/USDT"},
+	} {
+		resolved, err := resolver.Resolve(ctx, tc.exchange, tc.input)
+		require.NoError(t, err, tc.input)
+		assert.Equal(t, tc.expected, resolved, tc.input)
+	}
+
+	_, err := resolver.Resolve(ctx, "binance", "BTCUSDX")
+	require.ErrorIs(t, err, ErrUnknownSymbol)
+	var notFound *SymbolNotFoundError
+	require.ErrorAs(t, err, &notFound)
+	assert.Equal(t, []string{"BTC/USDC", "BTC/USDT"}, notFound.Suggestions)
+	assert.Equal(t, `unknown symbol "BTCUSDX" on binance; did you mean BTC/USDC, BTC/USDT?`, err.Error())
+
+	_, err = resolver.Resolve(ctx, "kraken", "XDGUSD")
+	assert.NoError(t, err)
+	_, err = resolver.Resolve(ctx, "kraken", "PEPE/USD")
+	require.ErrorAs(t, err, &notFound)
+	assert.Empty(t, notFound.Suggestions)
+}
+
+func TestParseSymbolAliases(t *testing.T) {
+	aliases, err := ParseSymbolAliases("kraken:xdg=doge, XXBT=BTC; *:WBTC=BTC;")
+	require.NoError(t, err)
+	assert.Equal(t, "DOGE", aliases["kraken"]["XDG"])
+	assert.Equal(t, "BTC", aliases["*"]["WBTC"])
+
+	_, err = ParseSymbolAliases("XDG=DOGE")
+	assert.Error(t, err)
+	_, err = ParseSymbolAliases("kraken:XDG")
+	assert.Error(t, err)
+}
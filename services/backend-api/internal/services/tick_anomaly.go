@@ -0,0 +1,286 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/models"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	defaultTickAnomalyWindowSize     = 20
+	defaultTickAnomalyMinSamples     = 5
+	defaultTickAnomalyMaxDeviation   = 5.0
+	defaultTickAnomalyMinJumpPct     = 2.0
+	defaultTickAnomalyMaxJumpPct     = 20.0
+	defaultTickAnomalyMaxSpreadPct   = 5.0
+	defaultTickAnomalyConfirmTicks   = 3
+	defaultTickAnomalyQuarantineSize = 200
+
+	// madScale turns a median absolute deviation into a modified z-score
+	// comparable with a standard deviation for normally distributed prices.
+	madScale = 0.6745
+)
+
+// TickAnomalyConfig configures bad tick detection.
+type TickAnomalyConfig struct {
+	// WindowSize is the number of recent accepted prices kept per market.
+	WindowSize int
+	// MinSamples is the history needed before the deviation check applies;
+	// until then a tick is compared with the last accepted price.
+	MinSamples int
+	// MaxDeviation is the modified z-score (median absolute deviations from
+	// the median) above which a price is an outlier.
+	MaxDeviation float64
+	// MinJumpPct is the smallest move from the median, in percent, that can be
+	// an outlier, so that noise in very flat markets is not rejected.
+	MinJumpPct float64
+	// MaxJumpPct is the largest move from the last accepted price, in percent,
+	// allowed while the history is shorter than MinSamples.
+	MaxJumpPct float64
+	// MaxSpreadPct is the widest bid/ask spread, in percent of the mid price.
+	MaxSpreadPct float64
+	// ConfirmTicks is the number of consecutive outliers after which the move
+	// is treated as genuine and the history restarts from those prices.
+	ConfirmTicks int
+	// QuarantineSize is the number of rejected ticks kept for review.
+	QuarantineSize int
+}
+
+// QuarantinedTick is a tick that was held back from storage and the
+// arbitrage calculators.
+type QuarantinedTick struct {
+	Exchange string          `json:"exchange"`
+	Symbol   string          `json:"symbol"`
+	Price    decimal.Decimal `json:"price"`
+	Bid      decimal.Decimal `json:"bid"`
+	Ask      decimal.Decimal `json:"ask"`
+	// Median is the median of the recent accepted prices, zero without history.
+	Median        float64   `json:"median"`
+	Reason        string    `json:"reason"`
+	Timestamp     time.Time `json:"timestamp"`
+	QuarantinedAt time.Time `json:"quarantined_at"`
+}
+
+// tickHistory holds the recent accepted prices of one market.
+type tickHistory struct {
+	prices []float64
+	// pending are the consecutive outliers seen since the last accepted tick.
+	pending []float64
+}
+
+// TickAnomalyFilter rejects ticks whose price is far from the market's
+// recent history or whose quote is crossed or unusually wide. Rejected ticks
+// are quarantined instead of being stored.
+type TickAnomalyFilter struct {
+	config     TickAnomalyConfig
+	logger     *slog.Logger
+	now        func() time.Time
+	mu         sync.Mutex
+	history    map[string]*tickHistory
+	quarantine []QuarantinedTick
+}
+
+// NewTickAnomalyFilter creates a bad tick filter.
+//
+// Parameters:
+//
+//	config: Detection configuration; zero values use the defaults.
+//
+// Returns:
+//
+//	*TickAnomalyFilter: Initialized filter.
+func NewTickAnomalyFilter(config TickAnomalyConfig) *TickAnomalyFilter {
+	if config.WindowSize <= 0 {
+		config.WindowSize = defaultTickAnomalyWindowSize
+	}
+	if config.MinSamples <= 0 {
+		config.MinSamples = defaultTickAnomalyMinSamples
+	}
+	if config.MinSamples > config.WindowSize {
+		config.MinSamples = config.WindowSize
+	}
+	if config.MaxDeviation <= 0 {
+		config.MaxDeviation = defaultTickAnomalyMaxDeviation
+	}
+	if config.MinJumpPct <= 0 {
+		config.MinJumpPct = defaultTickAnomalyMinJumpPct
+	}
+	if config.MaxJumpPct <= 0 {
+		config.MaxJumpPct = defaultTickAnomalyMaxJumpPct
+	}
+	if config.MaxSpreadPct <= 0 {
+		config.MaxSpreadPct = defaultTickAnomalyMaxSpreadPct
+	}
+	if config.ConfirmTicks <= 0 {
+		config.ConfirmTicks = defaultTickAnomalyConfirmTicks
+	}
+	if config.QuarantineSize <= 0 {
+		config.QuarantineSize = defaultTickAnomalyQuarantineSize
+	}
+	return &TickAnomalyFilter{
+		config:  config,
+		logger:  telemetry.Logger(),
+		now:     time.Now,
+		history: make(map[string]*tickHistory),
+	}
+}
+
+// Check decides whether a tick may be used. Accepted prices join the
+// market's history; rejected ticks are quarantined.
+//
+// Parameters:
+//
+//	ticker: The tick to check.
+//
+// Returns:
+//
+//	string: Why the tick was rejected, or "" when it is accepted.
+func (f *TickAnomalyFilter) Check(ticker models.MarketPrice) string {
+	price := ticker.Price.InexactFloat64()
+	if price <= 0 {
+		// Non-positive prices are rejected by the collector's validation.
+		return ""
+	}
+
+	key := fmt.Sprintf("%s:%s", ticker.ExchangeName, ticker.Symbol)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if reason := f.spreadAnomaly(ticker); reason != "" {
+		f.quarantineLocked(ticker, 0, reason)
+		return reason
+	}
+
+	h := f.history[key]
+	if h == nil {
+		h = &tickHistory{}
+		f.history[key] = h
+	}
+	median, reason := f.priceAnomaly(h, price)
+	if reason == "" {
+		h.pending = h.pending[:0]
+		h.add(price, f.config.WindowSize)
+		return ""
+	}
+
+	h.pending = append(h.pending, price)
+	if len(h.pending) >= f.config.ConfirmTicks {
+		f.logger.Info("Sustained price move, restarting tick history",
+			"exchange", ticker.ExchangeName,
+			"symbol", ticker.Symbol,
+			"price", price,
+			"previous_median", median)
+		h.prices = append(h.prices[:0], h.pending...)
+		h.pending = h.pending[:0]
+		return ""
+	}
+	f.quarantineLocked(ticker, median, reason)
+	return reason
+}
+
+// Quarantined returns the quarantined ticks, newest first.
+func (f *TickAnomalyFilter) Quarantined() []QuarantinedTick {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ticks := make([]QuarantinedTick, len(f.quarantine))
+	for i, tick := range f.quarantine {
+		ticks[len(ticks)-1-i] = tick
+	}
+	return ticks
+}
+
+func (f *TickAnomalyFilter) spreadAnomaly(ticker models.MarketPrice) string {
+	if !ticker.Bid.IsPositive() || !ticker.Ask.IsPositive() {
+		return ""
+	}
+	if ticker.Bid.GreaterThan(ticker.Ask) {
+		return fmt.Sprintf("crossed quote: bid %s above ask %s", ticker.Bid, ticker.Ask)
+	}
+	bid, ask := ticker.Bid.InexactFloat64(), ticker.Ask.InexactFloat64()
+	spreadPct := (ask - bid) / ((ask + bid) / 2) * 100
+	if spreadPct > f.config.MaxSpreadPct {
+		return fmt.Sprintf("spread %.2f%% above %.2f%%", spreadPct, f.config.MaxSpreadPct)
+	}
+	return ""
+}
+
+// priceAnomaly compares a price with the history and returns the history's
+// median with the reason the price is an outlier, if it is one.
+func (f *TickAnomalyFilter) priceAnomaly(h *tickHistory, price float64) (float64, string) {
+	if len(h.prices) == 0 {
+		return 0, ""
+	}
+	if len(h.prices) < f.config.MinSamples {
+		last := h.prices[len(h.prices)-1]
+		jump := math.Abs(price-last) / last * 100
+		if jump > f.config.MaxJumpPct {
+			return last, fmt.Sprintf("price jumped %.1f%% from %g", jump, last)
+		}
+		return last, ""
+	}
+
+	median := medianOf(h.prices)
+	jump := math.Abs(price-median) / median * 100
+	if jump <= f.config.MinJumpPct {
+		return median, ""
+	}
+	deviations := make([]float64, len(h.prices))
+	for i, p := range h.prices {
+		deviations[i] = math.Abs(p - median)
+	}
+	mad := medianOf(deviations)
+	if mad == 0 {
+		// Every recent price was identical, so any real jump is an outlier.
+		return median, fmt.Sprintf("price %.1f%% from a flat median %g", jump, median)
+	}
+	if score := madScale * math.Abs(price-median) / mad; score > f.config.MaxDeviation {
+		return median, fmt.Sprintf("price %.1f%% from median %g (deviation score %.1f)", jump, median, score)
+	}
+	return median, ""
+}
+
+func (f *TickAnomalyFilter) quarantineLocked(ticker models.MarketPrice, median float64, reason string) {
+	f.logger.Warn("Quarantined anomalous tick",
+		"exchange", ticker.ExchangeName,
+		"symbol", ticker.Symbol,
+		"price", ticker.Price.String(),
+		"reason", reason)
+	f.quarantine = append(f.quarantine, QuarantinedTick{
+		Exchange:      ticker.ExchangeName,
+		Symbol:        ticker.Symbol,
+		Price:         ticker.Price,
+		Bid:           ticker.Bid,
+		Ask:           ticker.Ask,
+		Median:        median,
+		Reason:        reason,
+		Timestamp:     ticker.Timestamp,
+		QuarantinedAt: f.now().UTC(),
+	})
+	if over := len(f.quarantine) - f.config.QuarantineSize; over > 0 {
+		f.quarantine = append(f.quarantine[:0], f.quarantine[over:]...)
+	}
+}
+
+func (h *tickHistory) add(price float64, window int) {
+	h.prices = append(h.prices, price)
+	if over := len(h.prices) - window; over > 0 {
+		h.prices = append(h.prices[:0], h.prices[over:]...)
+	}
+}
+
+func medianOf(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTick(price float64) models.MarketPrice {
+	return models.MarketPrice{
+		ExchangeName: "binance",
+		Symbol:       "BTC/USDT",
+		Price:        decimal.NewFromFloat(price),
+		Timestamp:    time.Now(),
+	}
+}
+
+func TestTickAnomalyFilter_QuarantinesOutliers(t *testing.T) {
+	filter := NewTickAnomalyFilter(TickAnomalyConfig{})
+
+	for _, price := range []float64{100, 100.4, 99.8, 100.2, 99.9, 100.1} {
+		assert.Empty(t, filter.Check(testTick(price)))
+	}
+
+	// A 1.5% move stays under the minimum jump even though it is many MADs away.
+	assert.Empty(t, filter.Check(testTick(101.5)))
+
+	reason := filter.Check(testTick(120))
+	assert.Contains(t, reason, "from median 100.1")
+
+	// The bad tick did not join the history, so normal prices keep passing.
+	assert.Empty(t, filter.Check(testTick(100.3)))
+
+	quarantined := filter.Quarantined()
+	require.Len(t, quarantined, 1)
+	assert.Equal(t, "binance", quarantined[0].Exchange)
+	assert.True(t, quarantined[0].Price.Equal(decimal.NewFromInt(120)))
+	assert.InDelta(t, 100.1, quarantined[0].Median, 1e-9)
+}
+
+func TestTickAnomalyFilter_ShortHistoryUsesJumpThreshold(t *testing.T) {
+	filter := NewTickAnomalyFilter(TickAnomalyConfig{MaxJumpPct: 10})
+
+	assert.Empty(t, filter.Check(testTick(100)))
+	assert.Empty(t, filter.Check(testTick(108)))
+	assert.Contains(t, filter.Check(testTick(130)), "price jumped")
+
+	other := testTick(130)
+	other.ExchangeName = "kraken"
+	assert.Empty(t, filter.Check(other), "history is kept per market")
+}
+
+func TestTickAnomalyFilter_RejectsBadQuotes(t *testing.T) {
+	filter := NewTickAnomalyFilter(TickAnomalyConfig{MaxSpreadPct: 1})
+
+	crossed := testTick(100)
+	crossed.Bid = decimal.NewFromInt(101)
+	crossed.Ask = decimal.NewFromInt(99)
+	assert.Contains(t, filter.Check(crossed), "crossed quote")
+
+	wide := testTick(100)
+	wide.Bid = decimal.NewFromInt(98)
+	wide.Ask = decimal.NewFromInt(102)
+	assert.Contains(t, filter.Check(wide), "spread 4.00%")
+
+	tight := testTick(100)
+	tight.Bid = decimal.NewFromFloat(99.9)
+	tight.Ask = decimal.NewFromFloat(100.1)
+	assert.Empty(t, filter.Check(tight))
+	assert.Len(t, filter.Quarantined(), 2)
+}
+
+func TestTickAnomalyFilter_AcceptsSustainedMove(t *testing.T) {
+	filter := NewTickAnomalyFilter(TickAnomalyConfig{ConfirmTicks: 3, QuarantineSize: 1})
+
+	for i := 0; i < 5; i++ {
+		assert.Empty(t, filter.Check(testTick(100)))
+	}
+	assert.NotEmpty(t, filter.Check(testTick(150)))
+	assert.NotEmpty(t, filter.Check(testTick(151)))
+	assert.Empty(t, filter.Check(testTick(150.5)), "the third consecutive outlier confirms the move")
+	assert.Empty(t, filter.Check(testTick(150.2)))
+
+	// Only the newest quarantined tick is kept.
+	quarantined := filter.Quarantined()
+	require.Len(t, quarantined, 1)
+	assert.True(t, quarantined[0].Price.Equal(decimal.NewFromInt(151)))
+}
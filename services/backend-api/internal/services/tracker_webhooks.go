@@ -0,0 +1,196 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// WebhookKind selects how an endpoint receives events.
+type WebhookKind string
+
+const (
+	// WebhookKindGeneric POSTs signed JSON payloads.
+	WebhookKindGeneric WebhookKind = "generic"
+	// WebhookKindGitHub comments on a GitHub issue.
+	WebhookKindGitHub WebhookKind = "github"
+	// WebhookKindLinear comments on a Linear issue.
+	WebhookKindLinear WebhookKind = "linear"
+)
+
+const (
+	defaultGitHubAPIURL = "https://api.github.com"
+	defaultLinearAPIURL = "https://api.linear.app/graphql"
+
+	linearCommentMutation = `mutation CommentCreate($input: CommentCreateInput!) { commentCreate(input: $input) { success } }`
+)
+
+// QuestLifecycleEvents are the events issue tracker endpoints receive unless
+// they subscribe to others.
+var QuestLifecycleEvents = []WebhookEventType{
+	WebhookEventQuestCreated,
+	WebhookEventQuestMilestone,
+	WebhookEventQuestCompleted,
+	WebhookEventQuestFailed,
+}
+
+var (
+	// githubIssuePath matches https://github.com/<owner>/<repo>/issues/<number>.
+	githubIssuePath = regexp.MustCompile(`^/([^/]+)/([^/]+)/issues/(\d+)/?$`)
+	// linearIssuePath matches https://linear.app/<workspace>/issue/<KEY-123>[/<slug>].
+	linearIssuePath = regexp.MustCompile(`^/[^/]+/issue/([A-Za-z][A-Za-z0-9]*-\d+)(/[^/]*)?$`)
+)
+
+func (k WebhookKind) tracker() bool {
+	return k == WebhookKindGitHub || k == WebhookKindLinear
+}
+
+// trackerTarget returns the API URL an issue tracker endpoint posts to and
+// the issue it comments on.
+func (s *WebhookService) trackerTarget(endpoint *WebhookEndpoint) (string, string, error) {
+	parsed, err := url.Parse(endpoint.URL)
+	if err != nil {
+		return "", "", ErrWebhookInvalidURL
+	}
+	switch endpoint.Kind {
+	case WebhookKindGitHub:
+		match := githubIssuePath.FindStringSubmatch(parsed.Path)
+		if match == nil {
+			return "", "", fmt.Errorf("%w: github webhooks need an issue url such as https://github.com/owner/repo/issues/1", ErrWebhookInvalidURL)
+		}
+		api := s.config.GitHubAPIURL
+		if host := strings.TrimPrefix(parsed.Host, "www."); host != "github.com" {
+			// GitHub Enterprise Server serves its REST API under /api/v3
+			api = parsed.Scheme + "://" + parsed.Host + "/api/v3"
+		}
+		issue := fmt.Sprintf("%s/%s#%s", match[1], match[2], match[3])
+		return fmt.Sprintf("%s/repos/%s/%s/issues/%s/comments", strings.TrimSuffix(api, "/"), match[1], match[2], match[3]), issue, nil
+	case WebhookKindLinear:
+		match := linearIssuePath.FindStringSubmatch(parsed.Path)
+		if match == nil {
+			return "", "", fmt.Errorf("%w: linear webhooks need an issue url such as https://linear.app/team/issue/ENG-1", ErrWebhookInvalidURL)
+		}
+		return s.config.LinearAPIURL, strings.ToUpper(match[1]), nil
+	}
+	return "", "", fmt.Errorf("%w: %s", ErrWebhookBadKind, endpoint.Kind)
+}
+
+// postComment posts an event as a comment on the endpoint's issue. The
+// endpoint secret is the API token.
+func (s *WebhookService) postComment(ctx context.Context, endpoint *WebhookEndpoint, payload WebhookPayload) (bool, error) {
+	apiURL, issue, err := s.trackerTarget(endpoint)
+	if err != nil {
+		return false, err
+	}
+	comment := trackerComment(payload)
+
+	var body interface{} = map[string]string{"body": comment}
+	if endpoint.Kind == WebhookKindLinear {
+		body = map[string]interface{}{
+			"query": linearCommentMutation,
+			"variables": map[string]interface{}{
+				"input": map[string]string{"issueId": issue, "body": comment},
+			},
+		}
+	}
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode comment: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(raw))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	if endpoint.Kind == WebhookKindGitHub {
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("Authorization", "Bearer "+endpoint.Secret)
+	} else {
+		// Linear personal API keys are sent without a scheme
+		req.Header.Set("Authorization", endpoint.Secret)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retryable, fmt.Errorf("%s responded with status %d", endpoint.Kind, resp.StatusCode)
+	}
+	if endpoint.Kind == WebhookKindLinear {
+		// GraphQL reports failures in the body of a 200 response
+		var result struct {
+			Data struct {
+				CommentCreate struct {
+					Success bool `json:"success"`
+				} `json:"commentCreate"`
+			} `json:"data"`
+			Errors []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return false, fmt.Errorf("failed to decode linear response: %w", err)
+		}
+		if len(result.Errors) > 0 {
+			return false, fmt.Errorf("linear rejected the comment on %s: %s", issue, result.Errors[0].Message)
+		}
+		if !result.Data.CommentCreate.Success {
+			return false, errors.New("linear did not create the comment on " + issue)
+		}
+	}
+	return false, nil
+}
+
+// trackerComment renders an event as a Markdown issue comment.
+func trackerComment(payload WebhookPayload) string {
+	quest, ok := payload.Data.(QuestLifecycleEvent)
+	if !ok {
+		data, err := json.MarshalIndent(payload.Data, "", "  ")
+		if err != nil {
+			data = []byte(fmt.Sprintf("%v", payload.Data))
+		}
+		return fmt.Sprintf("**NeuraTrade %s**\n\n```json\n%s\n```", payload.Event, data)
+	}
+
+	var headline string
+	switch payload.Event {
+	case WebhookEventQuestCreated:
+		headline = fmt.Sprintf("🆕 Quest **%s** created", quest.QuestName)
+	case WebhookEventQuestMilestone:
+		headline = fmt.Sprintf("📍 Quest **%s** reached %d%%", quest.QuestName, quest.MilestonePercent)
+	case WebhookEventQuestCompleted:
+		headline = fmt.Sprintf("✅ Quest **%s** completed", quest.QuestName)
+	case WebhookEventQuestFailed:
+		headline = fmt.Sprintf("❌ Quest **%s** failed", quest.QuestName)
+	default:
+		headline = fmt.Sprintf("Quest **%s**: %s", quest.QuestName, payload.Event)
+	}
+
+	lines := []string{headline, ""}
+	if quest.Target > 0 {
+		lines = append(lines, fmt.Sprintf("- Progress: %d/%d", quest.Current, quest.Target))
+	}
+	if quest.Error != "" {
+		lines = append(lines, "- Error: "+quest.Error)
+	}
+	if quest.ChatID != "" {
+		lines = append(lines, "- Chat: "+quest.ChatID)
+	}
+	lines = append(lines,
+		fmt.Sprintf("- Quest: `%s` (%s)", quest.QuestID, quest.Type),
+		"- At: "+quest.OccurredAt.UTC().Format(time.RFC3339),
+	)
+	return strings.Join(lines, "\n")
+}
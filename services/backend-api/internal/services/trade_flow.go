@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/ccxt"
+)
+
+const (
+	defaultTradeFlowWindow          = 5 * time.Minute
+	defaultTradeFlowRefreshInterval = 15 * time.Second
+	defaultTradeFlowFetchLimit      = 500
+	defaultLargePrintMultiple       = 5.0
+	defaultMinLargePrintNotional    = 10000.0
+	maxTradeFlowLargePrints         = 5
+)
+
+// TradeFetcher fetches recent public trades of a symbol.
+type TradeFetcher interface {
+	FetchTrades(ctx context.Context, exchange, symbol string, limit int) (*ccxt.TradesResponse, error)
+}
+
+// TradeFlowReader provides the trade-tape indicator of a symbol.
+type TradeFlowReader interface {
+	TradeFlow(ctx context.Context, exchange, symbol string) (*TradeFlowIndicator, error)
+}
+
+// TradeFlowConfig configures the trade-tape analyzer.
+type TradeFlowConfig struct {
+	// Window is how far back trades count toward the indicator.
+	Window time.Duration
+	// RefreshInterval is how stale a symbol's tape may get before TradeFlow
+	// pulls recent trades from the fetcher. Pushed trades reset it.
+	RefreshInterval time.Duration
+	// FetchLimit is the number of trades pulled per refresh.
+	FetchLimit int
+	// LargePrintMultiple flags trades whose notional is this many times the
+	// window's average trade notional.
+	LargePrintMultiple float64
+	// MinLargePrintNotional is the smallest notional, in quote currency, that
+	// counts as a large print.
+	MinLargePrintNotional float64
+}
+
+// LargePrint is a single trade far larger than the symbol's typical trade.
+type LargePrint struct {
+	Side      string    `json:"side"`
+	Price     float64   `json:"price"`
+	Amount    float64   `json:"amount"`
+	Notional  float64   `json:"notional"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// TradeFlowIndicator summarizes aggressor flow on a symbol's trade tape.
+type TradeFlowIndicator struct {
+	Exchange string        `json:"exchange"`
+	Symbol   string        `json:"symbol"`
+	Window   time.Duration `json:"window"`
+	Trades   int           `json:"trades"`
+	// BuyNotional and SellNotional are quote volumes of taker buys and sells.
+	BuyNotional  float64 `json:"buy_notional"`
+	SellNotional float64 `json:"sell_notional"`
+	// Imbalance is (buy - sell) / (buy + sell), in [-1, 1].
+	Imbalance      float64 `json:"imbalance"`
+	LargeBuyCount  int     `json:"large_buy_count"`
+	LargeSellCount int     `json:"large_sell_count"`
+	// LargePrints are the most recent large prints, newest first.
+	LargePrints []LargePrint `json:"large_prints,omitempty"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// tradeTape holds the recent trades of one symbol.
+type tradeTape struct {
+	trades    []ccxt.Trade
+	seen      map[string]struct{}
+	refreshed time.Time
+}
+
+// TradeFlowAnalyzer computes buy/sell imbalance and large prints from recent
+// trades. Trades are pushed with Ingest by a stream, or pulled from the
+// fetcher when a symbol's tape is stale.
+type TradeFlowAnalyzer struct {
+	fetcher TradeFetcher
+	config  TradeFlowConfig
+	now     func() time.Time
+
+	mu    sync.Mutex
+	tapes map[string]*tradeTape
+}
+
+// Ensure TradeFlowAnalyzer implements TradeFlowReader.
+var _ TradeFlowReader = (*TradeFlowAnalyzer)(nil)
+
+// NewTradeFlowAnalyzer creates the trade-tape analyzer.
+//
+// Parameters:
+//
+//	fetcher: Recent trade source used for stale tapes (may be nil when trades
+//	are only pushed).
+//	config: Analyzer configuration; zero values use defaults.
+//
+// Returns:
+//
+//	*TradeFlowAnalyzer: Initialized analyzer.
+func NewTradeFlowAnalyzer(fetcher TradeFetcher, config TradeFlowConfig) *TradeFlowAnalyzer {
+	if config.Window <= 0 {
+		config.Window = defaultTradeFlowWindow
+	}
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = defaultTradeFlowRefreshInterval
+	}
+	if config.FetchLimit <= 0 {
+		config.FetchLimit = defaultTradeFlowFetchLimit
+	}
+	if config.LargePrintMultiple <= 1 {
+		config.LargePrintMultiple = defaultLargePrintMultiple
+	}
+	if config.MinLargePrintNotional <= 0 {
+		config.MinLargePrintNotional = defaultMinLargePrintNotional
+	}
+	return &TradeFlowAnalyzer{
+		fetcher: fetcher,
+		config:  config,
+		now:     time.Now,
+		tapes:   make(map[string]*tradeTape),
+	}
+}
+
+// Ingest adds trades to a symbol's tape. Trades already seen are skipped, so
+// overlapping batches from polling or a reconnecting stream are safe.
+//
+// Parameters:
+//
+//	exchange: Exchange identifier.
+//	symbol: Trading pair.
+//	trades: Recent trades, in any order.
+func (a *TradeFlowAnalyzer) Ingest(exchange, symbol string, trades []ccxt.Trade) {
+	key := tradeFlowKey(exchange, symbol)
+	now := a.now()
+	cutoff := now.Add(-a.config.Window)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	tape, ok := a.tapes[key]
+	if !ok {
+		tape = &tradeTape{seen: make(map[string]struct{})}
+		a.tapes[key] = tape
+	}
+	for _, trade := range trades {
+		if trade.Timestamp.Before(cutoff) {
+			continue
+		}
+		id := tradeIdentity(trade)
+		if _, dup := tape.seen[id]; dup {
+			continue
+		}
+		tape.seen[id] = struct{}{}
+		tape.trades = append(tape.trades, trade)
+	}
+	tape.refreshed = now
+	a.prune(tape, cutoff)
+}
+
+// prune drops trades that left the window.
+func (a *TradeFlowAnalyzer) prune(tape *tradeTape, cutoff time.Time) {
+	sort.Slice(tape.trades, func(i, j int) bool { return tape.trades[i].Timestamp.Before(tape.trades[j].Timestamp) })
+	keep := sort.Search(len(tape.trades), func(i int) bool { return !tape.trades[i].Timestamp.Before(cutoff) })
+	for _, trade := range tape.trades[:keep] {
+		delete(tape.seen, tradeIdentity(trade))
+	}
+	tape.trades = append(tape.trades[:0], tape.trades[keep:]...)
+}
+
+// TradeFlow returns the indicator of a symbol, refreshing a stale tape from
+// the fetcher first.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	exchange: Exchange identifier.
+//	symbol: Trading pair.
+//
+// Returns:
+//
+//	*TradeFlowIndicator: The indicator, or nil when no trades are known.
+//	error: Error if refreshing the tape fails.
+func (a *TradeFlowAnalyzer) TradeFlow(ctx context.Context, exchange, symbol string) (*TradeFlowIndicator, error) {
+	if a.fetcher != nil && a.stale(exchange, symbol) {
+		resp, err := a.fetcher.FetchTrades(ctx, exchange, symbol, a.config.FetchLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch trades for %s: %w", symbol, err)
+		}
+		if resp != nil {
+			a.Ingest(exchange, symbol, resp.Trades)
+		}
+	}
+	return a.Indicator(exchange, symbol), nil
+}
+
+func (a *TradeFlowAnalyzer) stale(exchange, symbol string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	tape, ok := a.tapes[tradeFlowKey(exchange, symbol)]
+	return !ok || a.now().Sub(tape.refreshed) >= a.config.RefreshInterval
+}
+
+// Indicator computes the indicator of a symbol from the trades already on
+// its tape, or returns nil when there are none.
+func (a *TradeFlowAnalyzer) Indicator(exchange, symbol string) *TradeFlowIndicator {
+	now := a.now()
+	a.mu.Lock()
+	var trades []ccxt.Trade
+	if tape, ok := a.tapes[tradeFlowKey(exchange, symbol)]; ok {
+		a.prune(tape, now.Add(-a.config.Window))
+		trades = append(trades, tape.trades...)
+	}
+	a.mu.Unlock()
+	if len(trades) == 0 {
+		return nil
+	}
+
+	indicator := &TradeFlowIndicator{
+		Exchange:  strings.ToLower(exchange),
+		Symbol:    symbol,
+		Window:    a.config.Window,
+		Trades:    len(trades),
+		UpdatedAt: now.UTC(),
+	}
+	notionals := make([]float64, len(trades))
+	total := 0.0
+	for i, trade := range trades {
+		notionals[i] = tradeNotional(trade)
+		total += notionals[i]
+		switch strings.ToLower(trade.Side) {
+		case "buy":
+			indicator.BuyNotional += notionals[i]
+		case "sell":
+			indicator.SellNotional += notionals[i]
+		}
+	}
+	if flow := indicator.BuyNotional + indicator.SellNotional; flow > 0 {
+		indicator.Imbalance = (indicator.BuyNotional - indicator.SellNotional) / flow
+	}
+
+	threshold := total / float64(len(trades)) * a.config.LargePrintMultiple
+	if threshold < a.config.MinLargePrintNotional {
+		threshold = a.config.MinLargePrintNotional
+	}
+	for i := len(trades) - 1; i >= 0; i-- {
+		if notionals[i] < threshold {
+			continue
+		}
+		side := strings.ToLower(trades[i].Side)
+		switch side {
+		case "buy":
+			indicator.LargeBuyCount++
+		case "sell":
+			indicator.LargeSellCount++
+		}
+		if len(indicator.LargePrints) < maxTradeFlowLargePrints {
+			indicator.LargePrints = append(indicator.LargePrints, LargePrint{
+				Side:      side,
+				Price:     trades[i].Price.InexactFloat64(),
+				Amount:    trades[i].Amount.InexactFloat64(),
+				Notional:  notionals[i],
+				Timestamp: trades[i].Timestamp,
+			})
+		}
+	}
+	return indicator
+}
+
+// tradeNotional is the quote volume of a trade, derived when the exchange
+// omits the cost.
+func tradeNotional(trade ccxt.Trade) float64 {
+	if trade.Cost.IsPositive() {
+		return trade.Cost.InexactFloat64()
+	}
+	return trade.Price.Mul(trade.Amount).InexactFloat64()
+}
+
+// tradeIdentity identifies a trade for deduplication; exchanges without trade
+// IDs fall back to time, side, price and amount.
+func tradeIdentity(trade ccxt.Trade) string {
+	if trade.ID != "" {
+		return trade.ID
+	}
+	return fmt.Sprintf("%d|%s|%s|%s", trade.Timestamp.UnixNano(), trade.Side, trade.Price.String(), trade.Amount.String())
+}
+
+// tradeFlowKey identifies a symbol's tape.
+func tradeFlowKey(exchange, symbol string) string {
+	return strings.ToLower(exchange) + "|" + strings.ToUpper(symbol)
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/ccxt"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingTradeFetcher struct {
+	trades []ccxt.Trade
+	calls  int
+}
+
+func (f *countingTradeFetcher) FetchTrades(_ context.Context, exchange, symbol string, _ int) (*ccxt.TradesResponse, error) {
+	f.calls++
+	return &ccxt.TradesResponse{Exchange: exchange, Symbol: symbol, Trades: f.trades}, nil
+}
+
+func tapeTrade(id, side string, price, amount float64, at time.Time) ccxt.Trade {
+	return ccxt.Trade{
+		ID:        id,
+		Side:      side,
+		Price:     decimal.NewFromFloat(price),
+		Amount:    decimal.NewFromFloat(amount),
+		Timestamp: at,
+	}
+}
+
+func TestTradeFlowAnalyzer_ImbalanceAndLargePrints(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	analyzer := NewTradeFlowAnalyzer(nil, TradeFlowConfig{MinLargePrintNotional: 1000})
+	analyzer.now = func() time.Time { return now }
+
+	var trades []ccxt.Trade
+	for i, side := range []string{"buy", "sell", "buy", "sell", "buy", "sell", "buy", "sell", "buy"} {
+		trades = append(trades, tapeTrade(string(rune('a'+i)), side, 100, 1, now.Add(-time.Duration(i+1)*time.Second)))
+	}
+	// One block buy of 50x the typical size, and a trade outside the window
+	trades = append(trades, tapeTrade("block", "buy", 100, 50, now.Add(-30*time.Second)))
+	trades = append(trades, tapeTrade("old", "sell", 100, 500, now.Add(-10*time.Minute)))
+	analyzer.Ingest("Binance", "BTC/USDT", trades)
+	// Overlapping batches are deduplicated
+	analyzer.Ingest("binance", "BTC/USDT", trades[:3])
+
+	indicator := analyzer.Indicator("binance", "BTC/USDT")
+	require.NotNil(t, indicator)
+	assert.Equal(t, 10, indicator.Trades)
+	assert.InDelta(t, 5500.0, indicator.BuyNotional, 1e-9)
+	assert.InDelta(t, 400.0, indicator.SellNotional, 1e-9)
+	assert.InDelta(t, 5100.0/5900.0, indicator.Imbalance, 1e-9)
+	assert.Equal(t, 1, indicator.LargeBuyCount)
+	assert.Equal(t, 0, indicator.LargeSellCount)
+	require.Len(t, indicator.LargePrints, 1)
+	assert.InDelta(t, 5000.0, indicator.LargePrints[0].Notional, 1e-9)
+
+	// Trades age out of the window
+	now = now.Add(10 * time.Minute)
+	assert.Nil(t, analyzer.Indicator("binance", "BTC/USDT"))
+}
+
+func TestTradeFlowAnalyzer_RefreshesStaleTapes(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	fetcher := &countingTradeFetcher{trades: []ccxt.Trade{
+		tapeTrade("1", "sell", 10, 5, now.Add(-time.Second)),
+		tapeTrade("2", "sell", 10, 5, now.Add(-2*time.Second)),
+	}}
+	analyzer := NewTradeFlowAnalyzer(fetcher, TradeFlowConfig{RefreshInterval: time.Minute})
+	analyzer.now = func() time.Time { return now }
+
+	indicator, err := analyzer.TradeFlow(t.Context(), "bybit", "ETH/USDT")
+	require.NoError(t, err)
+	require.NotNil(t, indicator)
+	assert.Equal(t, -1.0, indicator.Imbalance)
+
+	_, err = analyzer.TradeFlow(t.Context(), "bybit", "ETH/USDT")
+	require.NoError(t, err)
+	assert.Equal(t, 1, fetcher.calls)
+
+	now = now.Add(time.Minute)
+	_, err = analyzer.TradeFlow(t.Context(), "bybit", "ETH/USDT")
+	require.NoError(t, err)
+	assert.Equal(t, 2, fetcher.calls)
+}
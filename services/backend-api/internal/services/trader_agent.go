@@ -220,6 +220,7 @@ type TraderAgent struct {
 	metrics      TraderAgentMetrics
 	lastDecision map[string]time.Time
 	mu           sync.RWMutex
+	now          func() time.Time
 }
 
 func NewTraderAgent(config TraderAgentConfig) *TraderAgent {
@@ -227,6 +228,7 @@ func NewTraderAgent(config TraderAgentConfig) *TraderAgent {
 		config:       config,
 		lastDecision: make(map[string]time.Time),
 		metrics:      TraderAgentMetrics{DecisionsByAction: make(map[string]int64), DecisionsBySymbol: make(map[string]int64)},
+		now:          time.Now,
 	}
 }
 
@@ -237,7 +239,7 @@ func (t *TraderAgent) MakeDecision(ctx context.Context, market MarketContext, po
 	decision := &TradingDecision{
 		ID:        generateTraderID(),
 		Symbol:    market.Symbol,
-		CreatedAt: time.Now().UTC(),
+		CreatedAt: t.now().UTC(),
 		Metadata:  make(map[string]string),
 	}
 
@@ -280,7 +282,7 @@ func (t *TraderAgent) MakeDecision(ctx context.Context, market MarketContext, po
 	t.metrics.IncrementExecuted()
 
 	t.mu.Lock()
-	t.lastDecision[market.Symbol] = time.Now()
+	t.lastDecision[market.Symbol] = t.now()
 	t.mu.Unlock()
 
 	return decision, nil
@@ -385,7 +387,7 @@ func (t *TraderAgent) isInCooldown(symbol string) bool {
 	if !exists {
 		return false
 	}
-	return time.Since(lastTime) < t.config.CooldownPeriod
+	return t.now().Sub(lastTime) < t.config.CooldownPeriod
 }
 
 func (t *TraderAgent) GetMetrics() TraderAgentMetrics {
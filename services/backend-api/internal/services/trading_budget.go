@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+)
+
+const tradingBudgetKey = "budget:chats"
+
+var (
+	ErrTradingBudgetInvalid  = errors.New("invalid trading budget")
+	ErrTradingBudgetNotFound = errors.New("trading budget not found")
+)
+
+// AccountEquitySource values the whole trading account, before any reserve.
+type AccountEquitySource interface {
+	AccountEquity(ctx context.Context) (decimal.Decimal, error)
+}
+
+// TradingBudgetGuard limits the capital autonomous trading may deploy.
+type TradingBudgetGuard interface {
+	// TradingCapital returns how much of the chat's budget is not yet
+	// deployed in open positions. limited is false when the chat has no
+	// budget, in which case the whole account may be traded.
+	TradingCapital(ctx context.Context, chatID string) (capital decimal.Decimal, limited bool, err error)
+}
+
+// TradingReserveSource returns the part of the account a chat keeps out of
+// autonomous trading.
+type TradingReserveSource interface {
+	Reserve(ctx context.Context, chatID string) (reserve decimal.Decimal, limited bool, err error)
+}
+
+// TradingBudget is the virtual sub-account a chat trades autonomously. The
+// reserve is the account equity left over when the budget was set; it is
+// held constant, so trading gains and losses move the sub-account only.
+type TradingBudget struct {
+	ChatID    string          `json:"chat_id"`
+	Budget    decimal.Decimal `json:"budget"`
+	Reserve   decimal.Decimal `json:"reserve"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// TradingBudgetStatus is a chat's budget valued against the current account.
+type TradingBudgetStatus struct {
+	TradingBudget
+	AccountEquity decimal.Decimal `json:"account_equity"`
+	// TradingEquity is the account equity above the reserve.
+	TradingEquity decimal.Decimal `json:"trading_equity"`
+	// Deployed is the notional of the open positions.
+	Deployed decimal.Decimal `json:"deployed"`
+	// Remaining is what new entries may still use: the budget, or the
+	// trading equity when it fell below the budget, less Deployed.
+	Remaining decimal.Decimal `json:"remaining"`
+}
+
+// TradingBudgetService stores per-chat trading budgets in Redis and answers
+// the position sizer with the capital left in the budget.
+type TradingBudgetService struct {
+	redis     *redis.Client
+	equity    AccountEquitySource
+	positions MarkedPositionSource
+	now       func() time.Time
+}
+
+var (
+	_ TradingBudgetGuard   = (*TradingBudgetService)(nil)
+	_ TradingReserveSource = (*TradingBudgetService)(nil)
+)
+
+// NewTradingBudgetService creates a trading budget service.
+//
+// Parameters:
+//
+//	client: Redis client used to persist budgets.
+//	equity: Values the account when a budget is set and enforced.
+//
+// Returns:
+//
+//	*TradingBudgetService: Initialized service.
+func NewTradingBudgetService(client *redis.Client, equity AccountEquitySource) *TradingBudgetService {
+	return &TradingBudgetService{
+		redis:  client,
+		equity: equity,
+		now:    time.Now,
+	}
+}
+
+// SetPositionSource counts open positions against the budget.
+func (s *TradingBudgetService) SetPositionSource(positions MarkedPositionSource) {
+	s.positions = positions
+}
+
+// Get returns a chat's budget.
+//
+// Returns:
+//
+//	*TradingBudget: The budget.
+//	error: ErrTradingBudgetNotFound when the chat has none, or a persistence error.
+func (s *TradingBudgetService) Get(ctx context.Context, chatID string) (*TradingBudget, error) {
+	raw, err := s.redis.HGet(ctx, tradingBudgetKey, chatID).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrTradingBudgetNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trading budget: %w", err)
+	}
+	var budget TradingBudget
+	if err := json.Unmarshal([]byte(raw), &budget); err != nil {
+		return nil, fmt.Errorf("failed to decode trading budget: %w", err)
+	}
+	return &budget, nil
+}
+
+// Set allocates budget of the current account equity to autonomous trading
+// and reserves the rest. Setting it again re-bases the reserve, e.g. after a
+// deposit or withdrawal.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	chatID: Telegram chat ID.
+//	budget: Capital autonomous trading may use, in the equity currency.
+//
+// Returns:
+//
+//	*TradingBudget: The stored budget.
+//	error: ErrTradingBudgetInvalid, an account valuation or a persistence error.
+func (s *TradingBudgetService) Set(ctx context.Context, chatID string, budget decimal.Decimal) (*TradingBudget, error) {
+	if !budget.IsPositive() {
+		return nil, fmt.Errorf("%w: budget must be positive", ErrTradingBudgetInvalid)
+	}
+	account, err := s.equity.AccountEquity(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to value account: %w", err)
+	}
+	if budget.GreaterThan(account) {
+		return nil, fmt.Errorf("%w: budget %s exceeds account equity %s", ErrTradingBudgetInvalid, budget.String(), account.StringFixed(2))
+	}
+
+	stored := &TradingBudget{
+		ChatID:    chatID,
+		Budget:    budget,
+		Reserve:   account.Sub(budget),
+		UpdatedAt: s.now().UTC(),
+	}
+	raw, err := json.Marshal(stored)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.redis.HSet(ctx, tradingBudgetKey, chatID, raw).Err(); err != nil {
+		return nil, fmt.Errorf("failed to save trading budget: %w", err)
+	}
+	return stored, nil
+}
+
+// Clear deletes a chat's budget so autonomous trading may use the whole account again.
+func (s *TradingBudgetService) Clear(ctx context.Context, chatID string) error {
+	if err := s.redis.HDel(ctx, tradingBudgetKey, chatID).Err(); err != nil {
+		return fmt.Errorf("failed to clear trading budget: %w", err)
+	}
+	return nil
+}
+
+// Status values a chat's budget against the current account and open positions.
+//
+// Returns:
+//
+//	*TradingBudgetStatus: The valued budget.
+//	error: ErrTradingBudgetNotFound, an account valuation or a persistence error.
+func (s *TradingBudgetService) Status(ctx context.Context, chatID string) (*TradingBudgetStatus, error) {
+	budget, err := s.Get(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	account, err := s.equity.AccountEquity(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to value account: %w", err)
+	}
+
+	status := &TradingBudgetStatus{
+		TradingBudget: *budget,
+		AccountEquity: account,
+		TradingEquity: account.Sub(budget.Reserve),
+		Deployed:      s.deployed(),
+	}
+	capital := decimal.Min(budget.Budget, status.TradingEquity)
+	status.Remaining = decimal.Max(capital.Sub(status.Deployed), decimal.Zero)
+	return status, nil
+}
+
+// TradingCapital implements TradingBudgetGuard.
+func (s *TradingBudgetService) TradingCapital(ctx context.Context, chatID string) (decimal.Decimal, bool, error) {
+	status, err := s.Status(ctx, chatID)
+	if errors.Is(err, ErrTradingBudgetNotFound) {
+		return decimal.Zero, false, nil
+	}
+	if err != nil {
+		return decimal.Zero, true, err
+	}
+	return status.Remaining, true, nil
+}
+
+// Reserve implements TradingReserveSource.
+func (s *TradingBudgetService) Reserve(ctx context.Context, chatID string) (decimal.Decimal, bool, error) {
+	budget, err := s.Get(ctx, chatID)
+	if errors.Is(err, ErrTradingBudgetNotFound) {
+		return decimal.Zero, false, nil
+	}
+	if err != nil {
+		return decimal.Zero, true, err
+	}
+	return budget.Reserve, true, nil
+}
+
+// deployed returns the notional of the open positions.
+func (s *TradingBudgetService) deployed() decimal.Decimal {
+	deployed := decimal.Zero
+	if s.positions == nil {
+		return deployed
+	}
+	for _, position := range s.positions.GetOpenPositions() {
+		deployed = deployed.Add(position.Size.Mul(position.CurrentPrice).Abs())
+	}
+	return deployed
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedAccountEquity struct {
+	equity decimal.Decimal
+	err    error
+}
+
+func (f *fixedAccountEquity) AccountEquity(context.Context) (decimal.Decimal, error) {
+	return f.equity, f.err
+}
+
+func newTestTradingBudgetService(t *testing.T, equity AccountEquitySource) *TradingBudgetService {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewTradingBudgetService(client, equity)
+}
+
+func TestTradingBudgetService_SetAndCapital(t *testing.T) {
+	account := &fixedAccountEquity{equity: decimal.NewFromInt(10000)}
+	svc := newTestTradingBudgetService(t, account)
+	ctx := t.Context()
+
+	// Without a budget the whole account may be traded
+	_, limited, err := svc.TradingCapital(ctx, "42")
+	require.NoError(t, err)
+	assert.False(t, limited)
+
+	budget, err := svc.Set(ctx, "42", decimal.NewFromInt(500))
+	require.NoError(t, err)
+	assert.Equal(t, "9500", budget.Reserve.String())
+
+	svc.SetPositionSource(marginTestPositions{{Symbol: "BTC/USDT", Size: decimal.NewFromFloat(0.002), CurrentPrice: decimal.NewFromInt(50000)}})
+	capital, limited, err := svc.TradingCapital(ctx, "42")
+	require.NoError(t, err)
+	assert.True(t, limited)
+	assert.Equal(t, "400", capital.String(), "open positions count against the budget")
+
+	// A loss shrinks the sub-account, not the reserve
+	account.equity = decimal.NewFromInt(9800)
+	status, err := svc.Status(ctx, "42")
+	require.NoError(t, err)
+	assert.Equal(t, "300", status.TradingEquity.String())
+	assert.Equal(t, "200", status.Remaining.String())
+
+	// A gain is tradable only up to the budget
+	account.equity = decimal.NewFromInt(11000)
+	capital, _, err = svc.TradingCapital(ctx, "42")
+	require.NoError(t, err)
+	assert.Equal(t, "400", capital.String())
+
+	require.NoError(t, svc.Clear(ctx, "42"))
+	_, err = svc.Get(ctx, "42")
+	assert.ErrorIs(t, err, ErrTradingBudgetNotFound)
+}
+
+func TestTradingBudgetService_Invalid(t *testing.T) {
+	account := &fixedAccountEquity{equity: decimal.NewFromInt(100)}
+	svc := newTestTradingBudgetService(t, account)
+
+	_, err := svc.Set(t.Context(), "42", decimal.Zero)
+	assert.ErrorIs(t, err, ErrTradingBudgetInvalid)
+	_, err = svc.Set(t.Context(), "42", decimal.NewFromInt(500))
+	assert.ErrorIs(t, err, ErrTradingBudgetInvalid)
+
+	account.err = errors.New("timeout")
+	_, err = svc.Set(t.Context(), "42", decimal.NewFromInt(50))
+	assert.Error(t, err)
+}
+
+func TestEquityService_LeavesReserveOutOfTradingEquity(t *testing.T) {
+	balances := &marginTestBalances{free: map[string]float64{"USDT": 9000}, total: map[string]float64{"USDT": 10000}}
+	equity := NewEquityService(balances, nil, EquityConfig{})
+	budgets := newTestTradingBudgetService(t, equity)
+	equity.SetReserveSource(budgets)
+	_, err := budgets.Set(t.Context(), "42", decimal.NewFromInt(500))
+	require.NoError(t, err)
+
+	value, err := equity.Equity(t.Context(), "42")
+	require.NoError(t, err)
+	assert.Equal(t, "500", value.String())
+	value, err = equity.Equity(t.Context(), "7")
+	require.NoError(t, err)
+	assert.Equal(t, "10000", value.String(), "chats without a budget trade the whole account")
+
+	snapshot := equity.Snapshot(t.Context(), "42")
+	assert.Equal(t, "10000", snapshot.TotalEquity.String())
+	assert.Equal(t, "500", snapshot.TradingEquity.String())
+	assert.Equal(t, "9500", snapshot.Reserve.String())
+	assert.Equal(t, "0", snapshot.AvailableBalance.String())
+}
+
+type fixedTradingBudget decimal.Decimal
+
+func (f fixedTradingBudget) TradingCapital(context.Context, string) (decimal.Decimal, bool, error) {
+	return decimal.Decimal(f), true, nil
+}
+
+func TestIntegratedQuestHandlers_ArbitrageTradingBudget(t *testing.T) {
+	ctx := t.Context()
+	newQuest := func() *Quest {
+		return &Quest{
+			Name:     "arbitrage",
+			Metadata: map[string]string{"chat_id": "42"},
+			Checkpoint: map[string]interface{}{
+				"symbol":        "ETH/USDT",
+				"buy_exchange":  "binance",
+				"sell_exchange": "okx",
+				"buy_price":     "100",
+				"sell_price":    "101",
+				"profit_pct":    "1",
+			},
+		}
+	}
+
+	executor := &recordingOrderExecutor{}
+	handlers := NewIntegratedQuestHandlers(nil, &totalBalanceFetcher{total: map[string]float64{"USDT": 2000}}, nil, nil, nil, nil)
+	handlers.SetOrderExecutor(executor)
+
+	// 300 USDT left in the budget at 100 caps both legs at 3 instead of the default 10
+	handlers.SetTradingBudget(fixedTradingBudget(decimal.NewFromInt(300)))
+	require.NoError(t, handlers.handleArbitrageExecution(ctx, newQuest()))
+	require.Len(t, executor.amounts, 2)
+	assert.True(t, executor.amounts[0].Equal(decimal.NewFromInt(3)), executor.amounts[0].String())
+
+	// A fully deployed budget places no orders
+	handlers.SetTradingBudget(fixedTradingBudget(decimal.Zero))
+	quest := newQuest()
+	require.NoError(t, handlers.handleArbitrageExecution(ctx, quest))
+	assert.Len(t, executor.amounts, 2)
+	assert.Equal(t, "budget_exhausted_hold", quest.Checkpoint["status"])
+}
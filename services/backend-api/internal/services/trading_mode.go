@@ -0,0 +1,662 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/redis/go-redis/v9"
+)
+
+// ExecutionMode is the account-wide execution mode.
+// It is separate from TradingMode, which describes an agent's risk appetite.
+type ExecutionMode string
+
+const (
+	ExecutionModePaper ExecutionMode = "paper"
+	ExecutionModeLive  ExecutionMode = "live"
+)
+
+// ProtectedAction is a destructive action that needs a second confirmation in live mode.
+type ProtectedAction string
+
+const (
+	ProtectedActionLiquidateAll      ProtectedAction = "liquidate_all"
+	ProtectedActionSwitchToLive      ProtectedAction = "switch_to_live"
+	ProtectedActionKillSwitchRelease ProtectedAction = "kill_switch_release"
+)
+
+// ConfirmationStatus is the state of a pending confirmation.
+type ConfirmationStatus string
+
+const (
+	ConfirmationStatusPending   ConfirmationStatus = "pending"
+	ConfirmationStatusExecuted  ConfirmationStatus = "executed"
+	ConfirmationStatusFailed    ConfirmationStatus = "failed"
+	ConfirmationStatusCancelled ConfirmationStatus = "cancelled"
+	ConfirmationStatusExpired   ConfirmationStatus = "expired"
+)
+
+const (
+	tradingModeStateKey            = "trading:mode:state"
+	tradingModeOperatorsKey        = "trading:mode:operators"
+	defaultConfirmationDelay       = time.Minute
+	defaultConfirmationTTL         = 10 * time.Minute
+	confirmationRetention          = 24 * time.Hour
+	maxConfirmationAttempts        = 5
+	confirmationCodeDigits   int64 = 1000000
+)
+
+var (
+	ErrConfirmationNotFound    = errors.New("confirmation not found")
+	ErrConfirmationNotPending  = errors.New("confirmation is not pending")
+	ErrConfirmationExpired     = errors.New("confirmation expired")
+	ErrConfirmationTooEarly    = errors.New("confirmation code is not valid yet")
+	ErrConfirmationInvalidCode = errors.New("invalid confirmation code")
+	ErrOperatorNotBound        = errors.New("operator is not bound")
+	ErrInvalidExecutionMode    = errors.New("invalid execution mode")
+	ErrLiveTradingBlocked      = errors.New("live trading is blocked")
+)
+
+// TradingModeState is the persisted execution mode and kill-switch state.
+type TradingModeState struct {
+	Mode              ExecutionMode `json:"mode"`
+	KillSwitchEngaged bool          `json:"kill_switch_engaged"`
+	KillSwitchReason  string        `json:"kill_switch_reason,omitempty"`
+	UpdatedBy         string        `json:"updated_by,omitempty"`
+	UpdatedAt         time.Time     `json:"updated_at"`
+}
+
+// ActionConfirmation tracks a protected action through the two-man rule.
+//
+// A pending confirmation is completed either by the requester entering the
+// delayed code after NotBefore, or immediately by a different bound operator.
+type ActionConfirmation struct {
+	ID          string             `json:"id"`
+	Action      ProtectedAction    `json:"action"`
+	Status      ConfirmationStatus `json:"status"`
+	RequestedBy string             `json:"requested_by"`
+	ConfirmedBy string             `json:"confirmed_by,omitempty"`
+	RequestedAt time.Time          `json:"requested_at"`
+	NotBefore   time.Time          `json:"not_before"`
+	ExpiresAt   time.Time          `json:"expires_at"`
+	Attempts    int                `json:"attempts"`
+	Error       string             `json:"error,omitempty"`
+	// ActivateAt schedules a confirmed switch to live for later instead of
+	// switching immediately.
+	ActivateAt *time.Time `json:"activate_at,omitempty"`
+	// Code is only populated in the response to the requester and never persisted.
+	Code     string `json:"code,omitempty"`
+	codeHash string
+}
+
+// storedConfirmation is the persisted form of a confirmation, which keeps the
+// code hash but never the code.
+type storedConfirmation struct {
+	*ActionConfirmation
+	Code     string `json:"code,omitempty"`
+	CodeHash string `json:"code_hash"`
+}
+
+// TradingModeConfig configures the trading mode service.
+type TradingModeConfig struct {
+	// DefaultMode is used until a mode has been persisted.
+	DefaultMode ExecutionMode
+	// ConfirmationDelay is how long the requester must wait before using the code.
+	ConfirmationDelay time.Duration
+	// ConfirmationTTL is how long a confirmation stays valid.
+	ConfirmationTTL time.Duration
+	// Operators are bound operator IDs allowed to approve each other's actions.
+	Operators []string
+	// GoLiveCheckInterval is how often scheduled go-lives are checked.
+	GoLiveCheckInterval time.Duration
+	// GoLiveReminders are how long before a scheduled go-live the countdown
+	// notifications are sent.
+	GoLiveReminders []time.Duration
+	// NotifyChatIDs receive the go-live countdown with a cancel button.
+	NotifyChatIDs []int64
+}
+
+// ProtectedActionFunc performs a protected action once it is confirmed.
+type ProtectedActionFunc func(ctx context.Context) error
+
+// LiveTradingCheck reports why live trading must not be enabled, or nil if it may.
+type LiveTradingCheck func(ctx context.Context) error
+
+// TradingModeService owns the paper/live execution mode and kill switch, and
+// enforces a second confirmation for destructive actions on live accounts.
+type TradingModeService struct {
+	redis    *redis.Client
+	config   TradingModeConfig
+	logger   *slog.Logger
+	mu       sync.Mutex
+	handlers map[ProtectedAction]ProtectedActionFunc
+	checks   []LiveTradingCheck
+	events   EventEmitter
+	notifier GoLiveNotifier
+	now      func() time.Time
+
+	runMu  sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewTradingModeService creates a trading mode service.
+//
+// Parameters:
+//
+//	client: Redis client used to persist state and confirmations.
+//	config: Service configuration.
+//
+// Returns:
+//
+//	*TradingModeService: Initialized service.
+func NewTradingModeService(client *redis.Client, config TradingModeConfig) *TradingModeService {
+	if config.DefaultMode != ExecutionModeLive {
+		config.DefaultMode = ExecutionModePaper
+	}
+	if config.ConfirmationDelay <= 0 {
+		config.ConfirmationDelay = defaultConfirmationDelay
+	}
+	if config.ConfirmationTTL <= config.ConfirmationDelay {
+		config.ConfirmationTTL = config.ConfirmationDelay + defaultConfirmationTTL
+	}
+	if config.GoLiveCheckInterval <= 0 {
+		config.GoLiveCheckInterval = defaultGoLiveCheckInterval
+	}
+	config.GoLiveReminders = normalizeGoLiveReminders(config.GoLiveReminders)
+	return &TradingModeService{
+		redis:    client,
+		config:   config,
+		logger:   telemetry.Logger(),
+		handlers: make(map[ProtectedAction]ProtectedActionFunc),
+		now:      time.Now,
+	}
+}
+
+// SetEventEmitter publishes mode changes and kill-switch transitions, for
+// example to outbound webhooks.
+func (s *TradingModeService) SetEventEmitter(events EventEmitter) {
+	s.events = events
+}
+
+// RegisterActionHandler sets the function run when an externally executed action
+// (such as liquidate-all) is confirmed.
+func (s *TradingModeService) RegisterActionHandler(action ProtectedAction, fn ProtectedActionFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[action] = fn
+}
+
+// AddLiveTradingCheck registers a precondition for switching to live mode. It
+// is evaluated both when the switch is requested and when it is confirmed.
+func (s *TradingModeService) AddLiveTradingCheck(check LiveTradingCheck) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checks = append(s.checks, check)
+}
+
+func (s *TradingModeService) checkLiveTradingLocked(ctx context.Context) error {
+	for _, check := range s.checks {
+		if err := check(ctx); err != nil {
+			return fmt.Errorf("%w: %v", ErrLiveTradingBlocked, err)
+		}
+	}
+	return nil
+}
+
+func confirmationKey(id string) string {
+	return fmt.Sprintf("trading:mode:confirmation:%s", id)
+}
+
+// GetState returns the persisted mode state, falling back to the configured default.
+func (s *TradingModeService) GetState(ctx context.Context) (*TradingModeState, error) {
+	data, err := s.redis.Get(ctx, tradingModeStateKey).Bytes()
+	if err == redis.Nil {
+		return &TradingModeState{Mode: s.config.DefaultMode}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trading mode: %w", err)
+	}
+	var state TradingModeState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode trading mode: %w", err)
+	}
+	return &state, nil
+}
+
+// IsLive reports whether the account is in live mode. Errors are treated as not live.
+func (s *TradingModeService) IsLive(ctx context.Context) bool {
+	state, err := s.GetState(ctx)
+	return err == nil && state.Mode == ExecutionModeLive
+}
+
+// KillSwitchEngaged reports whether trading is halted. Errors are treated as engaged.
+func (s *TradingModeService) KillSwitchEngaged(ctx context.Context) bool {
+	state, err := s.GetState(ctx)
+	return err != nil || state.KillSwitchEngaged
+}
+
+func (s *TradingModeService) saveState(ctx context.Context, state *TradingModeState) error {
+	state.UpdatedAt = s.now().UTC()
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal trading mode: %w", err)
+	}
+	if err := s.redis.Set(ctx, tradingModeStateKey, data, 0).Err(); err != nil {
+		return fmt.Errorf("failed to persist trading mode: %w", err)
+	}
+	return nil
+}
+
+// BindOperator registers an operator who may approve other operators' actions.
+func (s *TradingModeService) BindOperator(ctx context.Context, operator string) error {
+	if operator == "" {
+		return fmt.Errorf("operator is required")
+	}
+	return s.redis.SAdd(ctx, tradingModeOperatorsKey, operator).Err()
+}
+
+// UnbindOperator removes a bound operator.
+func (s *TradingModeService) UnbindOperator(ctx context.Context, operator string) error {
+	return s.redis.SRem(ctx, tradingModeOperatorsKey, operator).Err()
+}
+
+// ListOperators returns the operators bound at runtime. Operators from the
+// configuration are not included.
+func (s *TradingModeService) ListOperators(ctx context.Context) ([]string, error) {
+	operators, err := s.redis.SMembers(ctx, tradingModeOperatorsKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list operators: %w", err)
+	}
+	sort.Strings(operators)
+	return operators, nil
+}
+
+// RestoreState applies a backed-up state without loosening the current one:
+// paper mode and an engaged kill switch are restored, but live mode and a
+// released kill switch still have to go through confirmation.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	backup: The state from the backup.
+//	operator: Operator performing the restore.
+//
+// Returns:
+//
+//	error: Error if persistence fails.
+func (s *TradingModeService) RestoreState(ctx context.Context, backup TradingModeState, operator string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.applyLocked(ctx, operator, func(state *TradingModeState) {
+		if backup.Mode == ExecutionModePaper {
+			state.Mode = ExecutionModePaper
+		}
+		if backup.KillSwitchEngaged {
+			state.KillSwitchEngaged = true
+			state.KillSwitchReason = backup.KillSwitchReason
+		}
+	})
+}
+
+func (s *TradingModeService) isBoundOperator(ctx context.Context, operator string) bool {
+	for _, configured := range s.config.Operators {
+		if configured == operator {
+			return true
+		}
+	}
+	bound, err := s.redis.SIsMember(ctx, tradingModeOperatorsKey, operator).Result()
+	return err == nil && bound
+}
+
+// SwitchMode changes the execution mode. Switching to live always requires
+// confirmation; switching to paper also cancels a scheduled go-live.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	mode: Target mode.
+//	operator: Operator requesting the switch.
+//
+// Returns:
+//
+//	*ActionConfirmation: Pending confirmation when switching to live, nil otherwise.
+//	error: Error if the mode is invalid or persistence fails.
+func (s *TradingModeService) SwitchMode(ctx context.Context, mode ExecutionMode, operator string) (*ActionConfirmation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch mode {
+	case ExecutionModeLive:
+		state, err := s.GetState(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if state.Mode == ExecutionModeLive {
+			return nil, nil
+		}
+		if err := s.checkLiveTradingLocked(ctx); err != nil {
+			return nil, err
+		}
+		return s.createConfirmation(ctx, ProtectedActionSwitchToLive, operator, nil)
+	case ExecutionModePaper:
+		schedule, err := s.cancelGoLiveLocked(ctx, "", operator)
+		switch {
+		case err == nil:
+			s.notifyGoLive(ctx, schedule, GoLiveNotification{Stage: GoLiveStageCancelled, Operator: operator})
+		case !errors.Is(err, ErrGoLiveNotScheduled):
+			return nil, err
+		}
+		return nil, s.applyLocked(ctx, operator, func(state *TradingModeState) {
+			state.Mode = ExecutionModePaper
+		})
+	default:
+		return nil, ErrInvalidExecutionMode
+	}
+}
+
+// EngageKillSwitch halts trading immediately. Engaging never needs confirmation.
+func (s *TradingModeService) EngageKillSwitch(ctx context.Context, operator, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.logger.Warn("Kill switch engaged", "operator", operator, "reason", reason)
+	return s.applyLocked(ctx, operator, func(state *TradingModeState) {
+		state.KillSwitchEngaged = true
+		state.KillSwitchReason = reason
+	})
+}
+
+// RequestAction starts a protected action. In paper mode the action runs
+// immediately; in live mode a pending confirmation is returned instead.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	action: The protected action.
+//	operator: Operator requesting the action.
+//
+// Returns:
+//
+//	*ActionConfirmation: Confirmation with status executed or pending.
+//	error: Error if the action fails or cannot be recorded.
+func (s *TradingModeService) RequestAction(ctx context.Context, action ProtectedAction, operator string) (*ActionConfirmation, error) {
+	if action == ProtectedActionSwitchToLive {
+		return s.SwitchMode(ctx, ExecutionModeLive, operator)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := s.GetState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if state.Mode == ExecutionModeLive {
+		return s.createConfirmation(ctx, action, operator, nil)
+	}
+
+	now := s.now().UTC()
+	confirmation := &ActionConfirmation{
+		ID:          uuid.NewString(),
+		Action:      action,
+		Status:      ConfirmationStatusExecuted,
+		RequestedBy: operator,
+		RequestedAt: now,
+		NotBefore:   now,
+		ExpiresAt:   now,
+	}
+	if err := s.executeLocked(ctx, action, operator); err != nil {
+		return nil, err
+	}
+	return confirmation, nil
+}
+
+// Confirm completes a pending confirmation.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	id: Confirmation ID.
+//	operator: Confirming operator. A different bound operator needs no code.
+//	code: Delayed code, required when the requester confirms their own action.
+//
+// Returns:
+//
+//	*ActionConfirmation: The updated confirmation.
+//	error: One of the ErrConfirmation* errors, or the action's own error.
+func (s *TradingModeService) Confirm(ctx context.Context, id, operator, code string) (*ActionConfirmation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	confirmation, err := s.loadConfirmation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if confirmation.Status != ConfirmationStatusPending {
+		return confirmation, ErrConfirmationNotPending
+	}
+
+	now := s.now().UTC()
+	if now.After(confirmation.ExpiresAt) {
+		confirmation.Status = ConfirmationStatusExpired
+		_ = s.saveConfirmation(ctx, confirmation)
+		return confirmation, ErrConfirmationExpired
+	}
+
+	if operator != confirmation.RequestedBy {
+		if !s.isBoundOperator(ctx, operator) {
+			return confirmation, ErrOperatorNotBound
+		}
+	} else {
+		if now.Before(confirmation.NotBefore) {
+			return confirmation, ErrConfirmationTooEarly
+		}
+		if subtle.ConstantTimeCompare([]byte(hashConfirmationCode(code)), []byte(confirmation.codeHash)) != 1 {
+			confirmation.Attempts++
+			if confirmation.Attempts >= maxConfirmationAttempts {
+				confirmation.Status = ConfirmationStatusCancelled
+				confirmation.Error = "too many invalid codes"
+			}
+			_ = s.saveConfirmation(ctx, confirmation)
+			return confirmation, ErrConfirmationInvalidCode
+		}
+	}
+
+	confirmation.ConfirmedBy = operator
+	if confirmation.ActivateAt != nil && now.Before(*confirmation.ActivateAt) {
+		schedule, err := s.scheduleGoLiveLocked(ctx, confirmation, operator)
+		if err != nil {
+			confirmation.Status = ConfirmationStatusFailed
+			confirmation.Error = err.Error()
+			_ = s.saveConfirmation(ctx, confirmation)
+			return confirmation, err
+		}
+		confirmation.Status = ConfirmationStatusExecuted
+		if err := s.saveConfirmation(ctx, confirmation); err != nil {
+			return nil, err
+		}
+		s.notifyGoLive(ctx, schedule, GoLiveNotification{Stage: GoLiveStageScheduled, Remaining: schedule.ActivateAt.Sub(now)})
+		return confirmation, nil
+	}
+	if err := s.executeLocked(ctx, confirmation.Action, operator); err != nil {
+		confirmation.Status = ConfirmationStatusFailed
+		confirmation.Error = err.Error()
+		_ = s.saveConfirmation(ctx, confirmation)
+		return confirmation, err
+	}
+	confirmation.Status = ConfirmationStatusExecuted
+	if err := s.saveConfirmation(ctx, confirmation); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Protected action confirmed", "action", confirmation.Action, "requested_by", confirmation.RequestedBy, "confirmed_by", operator)
+	return confirmation, nil
+}
+
+// Cancel aborts a pending confirmation.
+func (s *TradingModeService) Cancel(ctx context.Context, id, operator string) (*ActionConfirmation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	confirmation, err := s.loadConfirmation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if confirmation.Status != ConfirmationStatusPending {
+		return confirmation, ErrConfirmationNotPending
+	}
+	confirmation.Status = ConfirmationStatusCancelled
+	confirmation.ConfirmedBy = operator
+	if err := s.saveConfirmation(ctx, confirmation); err != nil {
+		return nil, err
+	}
+	return confirmation, nil
+}
+
+// GetConfirmation returns a confirmation by ID.
+func (s *TradingModeService) GetConfirmation(ctx context.Context, id string) (*ActionConfirmation, error) {
+	return s.loadConfirmation(ctx, id)
+}
+
+func (s *TradingModeService) createConfirmation(ctx context.Context, action ProtectedAction, operator string, activateAt *time.Time) (*ActionConfirmation, error) {
+	if operator == "" {
+		return nil, fmt.Errorf("operator is required")
+	}
+	code, err := generateConfirmationCode()
+	if err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+	confirmation := &ActionConfirmation{
+		ID:          uuid.NewString(),
+		Action:      action,
+		Status:      ConfirmationStatusPending,
+		RequestedBy: operator,
+		RequestedAt: now,
+		NotBefore:   now.Add(s.config.ConfirmationDelay),
+		ExpiresAt:   now.Add(s.config.ConfirmationTTL),
+		ActivateAt:  activateAt,
+		codeHash:    hashConfirmationCode(code),
+	}
+	if err := s.saveConfirmation(ctx, confirmation); err != nil {
+		return nil, err
+	}
+	s.logger.Warn("Protected action awaiting confirmation", "action", action, "operator", operator, "confirmation_id", confirmation.ID)
+
+	response := *confirmation
+	response.Code = code
+	return &response, nil
+}
+
+func (s *TradingModeService) executeLocked(ctx context.Context, action ProtectedAction, operator string) error {
+	switch action {
+	case ProtectedActionSwitchToLive:
+		if err := s.checkLiveTradingLocked(ctx); err != nil {
+			return err
+		}
+		return s.applyLocked(ctx, operator, func(state *TradingModeState) {
+			state.Mode = ExecutionModeLive
+		})
+	case ProtectedActionKillSwitchRelease:
+		return s.applyLocked(ctx, operator, func(state *TradingModeState) {
+			state.KillSwitchEngaged = false
+			state.KillSwitchReason = ""
+		})
+	default:
+		fn, ok := s.handlers[action]
+		if !ok {
+			return fmt.Errorf("no handler registered for %s", action)
+		}
+		return fn(ctx)
+	}
+}
+
+func (s *TradingModeService) applyLocked(ctx context.Context, operator string, mutate func(*TradingModeState)) error {
+	state, err := s.GetState(ctx)
+	if err != nil {
+		return err
+	}
+	previous := *state
+	mutate(state)
+	state.UpdatedBy = operator
+	if err := s.saveState(ctx, state); err != nil {
+		return err
+	}
+
+	if s.events != nil {
+		if state.Mode != previous.Mode {
+			s.events.Emit(ctx, WebhookEventModeChanged, map[string]interface{}{
+				"previous_mode": previous.Mode,
+				"mode":          state.Mode,
+				"operator":      operator,
+			})
+		}
+		if state.KillSwitchEngaged != previous.KillSwitchEngaged {
+			riskType := "kill_switch_released"
+			if state.KillSwitchEngaged {
+				riskType = "kill_switch_engaged"
+			}
+			s.events.Emit(ctx, WebhookEventRisk, map[string]interface{}{
+				"type":     riskType,
+				"reason":   state.KillSwitchReason,
+				"operator": operator,
+			})
+		}
+	}
+	return nil
+}
+
+func (s *TradingModeService) loadConfirmation(ctx context.Context, id string) (*ActionConfirmation, error) {
+	data, err := s.redis.Get(ctx, confirmationKey(id)).Bytes()
+	if err == redis.Nil {
+		return nil, ErrConfirmationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load confirmation: %w", err)
+	}
+	stored := storedConfirmation{ActionConfirmation: &ActionConfirmation{}}
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("failed to decode confirmation: %w", err)
+	}
+	stored.ActionConfirmation.codeHash = stored.CodeHash
+	return stored.ActionConfirmation, nil
+}
+
+func (s *TradingModeService) saveConfirmation(ctx context.Context, confirmation *ActionConfirmation) error {
+	data, err := json.Marshal(storedConfirmation{ActionConfirmation: confirmation, CodeHash: confirmation.codeHash})
+	if err != nil {
+		return fmt.Errorf("failed to marshal confirmation: %w", err)
+	}
+	ttl := confirmation.ExpiresAt.Sub(s.now()) + confirmationRetention
+	if err := s.redis.Set(ctx, confirmationKey(confirmation.ID), data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to persist confirmation: %w", err)
+	}
+	return nil
+}
+
+func generateConfirmationCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(confirmationCodeDigits))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate confirmation code: %w", err)
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+func hashConfirmationCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,420 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// GoLiveStatus is the state of a scheduled go-live.
+type GoLiveStatus string
+
+const (
+	GoLiveStatusScheduled GoLiveStatus = "scheduled"
+	GoLiveStatusActivated GoLiveStatus = "activated"
+	GoLiveStatusCancelled GoLiveStatus = "cancelled"
+	GoLiveStatusFailed    GoLiveStatus = "failed"
+)
+
+// GoLiveStage is the point in a scheduled go-live a notification is sent at.
+type GoLiveStage string
+
+const (
+	GoLiveStageScheduled GoLiveStage = "scheduled"
+	GoLiveStageReminder  GoLiveStage = "reminder"
+	GoLiveStageActivated GoLiveStage = "activated"
+	GoLiveStageFailed    GoLiveStage = "failed"
+	GoLiveStageCancelled GoLiveStage = "cancelled"
+)
+
+const (
+	tradingModeGoLiveKey       = "trading:mode:go_live"
+	defaultGoLiveCheckInterval = 10 * time.Second
+	maxGoLiveHorizon           = 7 * 24 * time.Hour
+)
+
+var (
+	ErrInvalidGoLiveTime      = errors.New("go-live time must be in the future and within 7 days")
+	ErrGoLiveAlreadyScheduled = errors.New("a go-live is already scheduled")
+	ErrGoLiveNotScheduled     = errors.New("no go-live is scheduled")
+)
+
+// defaultGoLiveReminders are the countdown notifications sent before a
+// scheduled go-live.
+var defaultGoLiveReminders = []time.Duration{time.Hour, 15 * time.Minute, 5 * time.Minute, time.Minute}
+
+// GoLiveSchedule is a confirmed switch to live mode that activates at a
+// future time. Its ID is the ID of the confirmation that approved it.
+type GoLiveSchedule struct {
+	ID          string       `json:"id"`
+	Status      GoLiveStatus `json:"status"`
+	ActivateAt  time.Time    `json:"activate_at"`
+	ScheduledBy string       `json:"scheduled_by"`
+	ConfirmedBy string       `json:"confirmed_by"`
+	ScheduledAt time.Time    `json:"scheduled_at"`
+	CancelledBy string       `json:"cancelled_by,omitempty"`
+	ActivatedAt *time.Time   `json:"activated_at,omitempty"`
+	Error       string       `json:"error,omitempty"`
+}
+
+// GoLiveNotification tells operators about a scheduled go-live.
+type GoLiveNotification struct {
+	Stage      GoLiveStage
+	ScheduleID string
+	ActivateAt time.Time
+	// Remaining is the time left until activation at a reminder.
+	Remaining time.Duration
+	Operator  string
+	Error     string
+}
+
+// GoLiveNotifier sends go-live countdown notifications to a chat.
+// NotificationService satisfies this interface.
+type GoLiveNotifier interface {
+	NotifyGoLive(ctx context.Context, chatID int64, notification GoLiveNotification) error
+}
+
+// SetGoLiveNotifier sends the countdown and outcome of scheduled go-lives to
+// the configured chats.
+func (s *TradingModeService) SetGoLiveNotifier(notifier GoLiveNotifier) {
+	s.notifier = notifier
+}
+
+func goLiveReminderKey(id string, before time.Duration) string {
+	return fmt.Sprintf("trading:mode:go_live:%s:reminder:%d", id, int64(before.Seconds()))
+}
+
+func goLiveClaimKey(id string) string {
+	return fmt.Sprintf("trading:mode:go_live:%s:claim", id)
+}
+
+// ScheduleGoLive requests a switch to live mode at a future time. Like an
+// immediate switch it needs confirmation; once confirmed, live mode is
+// activated at activateAt unless the schedule is cancelled first.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	activateAt: When live mode is activated.
+//	operator: Operator requesting the switch.
+//
+// Returns:
+//
+//	*ActionConfirmation: Pending confirmation, or nil when already live.
+//	error: ErrInvalidGoLiveTime, ErrGoLiveAlreadyScheduled, ErrLiveTradingBlocked or a persistence error.
+func (s *TradingModeService) ScheduleGoLive(ctx context.Context, activateAt time.Time, operator string) (*ActionConfirmation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now().UTC()
+	if !activateAt.After(now) || activateAt.Sub(now) > maxGoLiveHorizon {
+		return nil, ErrInvalidGoLiveTime
+	}
+	state, err := s.GetState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if state.Mode == ExecutionModeLive {
+		return nil, nil
+	}
+	schedule, err := s.loadGoLive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if schedule != nil && schedule.Status == GoLiveStatusScheduled {
+		return nil, ErrGoLiveAlreadyScheduled
+	}
+	if err := s.checkLiveTradingLocked(ctx); err != nil {
+		return nil, err
+	}
+	activateAt = activateAt.UTC()
+	return s.createConfirmation(ctx, ProtectedActionSwitchToLive, operator, &activateAt)
+}
+
+// GetGoLiveSchedule returns the latest go-live schedule.
+//
+// Returns:
+//
+//	*GoLiveSchedule: The schedule, which may already be activated or cancelled.
+//	error: ErrGoLiveNotScheduled when there is none.
+func (s *TradingModeService) GetGoLiveSchedule(ctx context.Context) (*GoLiveSchedule, error) {
+	schedule, err := s.loadGoLive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, ErrGoLiveNotScheduled
+	}
+	return schedule, nil
+}
+
+// CancelGoLive cancels the scheduled go-live. Cancelling only keeps the
+// account in paper mode, so it needs no confirmation.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	id: Schedule ID, or empty for whichever go-live is scheduled.
+//	operator: Operator cancelling.
+//
+// Returns:
+//
+//	*GoLiveSchedule: The cancelled schedule.
+//	error: ErrGoLiveNotScheduled when nothing matching is scheduled.
+func (s *TradingModeService) CancelGoLive(ctx context.Context, id, operator string) (*GoLiveSchedule, error) {
+	s.mu.Lock()
+	schedule, err := s.cancelGoLiveLocked(ctx, id, operator)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	s.notifyGoLive(ctx, schedule, GoLiveNotification{Stage: GoLiveStageCancelled, Operator: operator})
+	return schedule, nil
+}
+
+func (s *TradingModeService) cancelGoLiveLocked(ctx context.Context, id, operator string) (*GoLiveSchedule, error) {
+	schedule, err := s.loadGoLive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil || schedule.Status != GoLiveStatusScheduled || (id != "" && schedule.ID != id) {
+		return nil, ErrGoLiveNotScheduled
+	}
+	schedule.Status = GoLiveStatusCancelled
+	schedule.CancelledBy = operator
+	if err := s.saveGoLive(ctx, schedule); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Scheduled go-live cancelled", "schedule_id", schedule.ID, "operator", operator)
+	return schedule, nil
+}
+
+// scheduleGoLiveLocked records a confirmed go-live for activation at its time.
+func (s *TradingModeService) scheduleGoLiveLocked(ctx context.Context, confirmation *ActionConfirmation, operator string) (*GoLiveSchedule, error) {
+	existing, err := s.loadGoLive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Status == GoLiveStatusScheduled {
+		return nil, ErrGoLiveAlreadyScheduled
+	}
+	if err := s.checkLiveTradingLocked(ctx); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	schedule := &GoLiveSchedule{
+		ID:          confirmation.ID,
+		Status:      GoLiveStatusScheduled,
+		ActivateAt:  *confirmation.ActivateAt,
+		ScheduledBy: confirmation.RequestedBy,
+		ConfirmedBy: operator,
+		ScheduledAt: now,
+	}
+	if err := s.saveGoLive(ctx, schedule); err != nil {
+		return nil, err
+	}
+	// Reminders already inside the countdown are covered by the scheduled
+	// notification itself
+	remaining := schedule.ActivateAt.Sub(now)
+	for _, before := range s.config.GoLiveReminders {
+		if before >= remaining {
+			s.redis.SetNX(ctx, goLiveReminderKey(schedule.ID, before), now.Format(time.RFC3339), remaining+confirmationRetention)
+		}
+	}
+	s.logger.Warn("Go-live scheduled", "schedule_id", schedule.ID, "activate_at", schedule.ActivateAt, "requested_by", schedule.ScheduledBy, "confirmed_by", operator)
+	return schedule, nil
+}
+
+// Start runs the go-live scheduler, which sends the countdown reminders and
+// activates live mode when a scheduled go-live is due.
+func (s *TradingModeService) Start(ctx context.Context) error {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+	if s.cancel != nil {
+		return fmt.Errorf("go-live scheduler already running")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.config.GoLiveCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.CheckGoLive(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the go-live scheduler and waits for the running check to finish.
+func (s *TradingModeService) Stop() {
+	s.runMu.Lock()
+	cancel := s.cancel
+	s.cancel = nil
+	s.runMu.Unlock()
+	if cancel != nil {
+		cancel()
+		s.wg.Wait()
+	}
+}
+
+// CheckGoLive sends a due countdown reminder or activates a due go-live.
+// Each reminder and the activation are claimed in Redis, so they happen
+// once across replicas.
+func (s *TradingModeService) CheckGoLive(ctx context.Context) {
+	schedule, err := s.loadGoLive(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to load go-live schedule", "error", err)
+		return
+	}
+	if schedule == nil || schedule.Status != GoLiveStatusScheduled {
+		return
+	}
+
+	now := s.now().UTC()
+	remaining := schedule.ActivateAt.Sub(now)
+	if remaining > 0 {
+		before, ok := s.dueGoLiveReminder(remaining)
+		if !ok {
+			return
+		}
+		claimed, err := s.redis.SetNX(ctx, goLiveReminderKey(schedule.ID, before), now.Format(time.RFC3339), remaining+confirmationRetention).Result()
+		if err != nil || !claimed {
+			return
+		}
+		s.notifyGoLive(ctx, schedule, GoLiveNotification{Stage: GoLiveStageReminder, Remaining: remaining})
+		return
+	}
+
+	claimed, err := s.redis.SetNX(ctx, goLiveClaimKey(schedule.ID), now.Format(time.RFC3339), confirmationRetention).Result()
+	if err != nil || !claimed {
+		return
+	}
+	schedule, stage := s.activateGoLive(ctx, schedule.ID)
+	if schedule != nil {
+		s.notifyGoLive(ctx, schedule, GoLiveNotification{Stage: stage, Error: schedule.Error})
+	}
+}
+
+// dueGoLiveReminder returns the tightest reminder the countdown has reached.
+func (s *TradingModeService) dueGoLiveReminder(remaining time.Duration) (time.Duration, bool) {
+	for i := len(s.config.GoLiveReminders) - 1; i >= 0; i-- {
+		if before := s.config.GoLiveReminders[i]; remaining <= before {
+			return before, true
+		}
+	}
+	return 0, false
+}
+
+// activateGoLive switches to live mode once the live-trading checks pass
+// again; a failed check fails the schedule instead.
+func (s *TradingModeService) activateGoLive(ctx context.Context, id string) (*GoLiveSchedule, GoLiveStage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The schedule may have been cancelled since it was loaded
+	schedule, err := s.loadGoLive(ctx)
+	if err != nil || schedule == nil || schedule.ID != id || schedule.Status != GoLiveStatusScheduled {
+		return nil, ""
+	}
+
+	stage := GoLiveStageActivated
+	err = s.checkLiveTradingLocked(ctx)
+	if err == nil {
+		err = s.applyLocked(ctx, schedule.ScheduledBy, func(state *TradingModeState) {
+			state.Mode = ExecutionModeLive
+		})
+	}
+	if err != nil {
+		schedule.Status = GoLiveStatusFailed
+		schedule.Error = err.Error()
+		stage = GoLiveStageFailed
+		s.logger.Error("Scheduled go-live failed", "schedule_id", schedule.ID, "error", err)
+	} else {
+		activatedAt := s.now().UTC()
+		schedule.Status = GoLiveStatusActivated
+		schedule.ActivatedAt = &activatedAt
+		s.logger.Warn("Scheduled go-live activated", "schedule_id", schedule.ID, "scheduled_by", schedule.ScheduledBy)
+	}
+	if err := s.saveGoLive(ctx, schedule); err != nil {
+		s.logger.Error("Failed to record go-live outcome", "schedule_id", schedule.ID, "error", err)
+	}
+	return schedule, stage
+}
+
+// notifyGoLive sends a go-live notification to every configured chat.
+func (s *TradingModeService) notifyGoLive(ctx context.Context, schedule *GoLiveSchedule, notification GoLiveNotification) {
+	if s.notifier == nil {
+		return
+	}
+	notification.ScheduleID = schedule.ID
+	notification.ActivateAt = schedule.ActivateAt
+	if notification.Operator == "" {
+		notification.Operator = schedule.ScheduledBy
+	}
+	for _, chatID := range s.config.NotifyChatIDs {
+		if err := s.notifier.NotifyGoLive(ctx, chatID, notification); err != nil {
+			s.logger.Warn("Failed to send go-live notification", "chat_id", chatID, "stage", notification.Stage, "error", err)
+		}
+	}
+}
+
+func (s *TradingModeService) loadGoLive(ctx context.Context) (*GoLiveSchedule, error) {
+	data, err := s.redis.Get(ctx, tradingModeGoLiveKey).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load go-live schedule: %w", err)
+	}
+	var schedule GoLiveSchedule
+	if err := json.Unmarshal(data, &schedule); err != nil {
+		return nil, fmt.Errorf("failed to decode go-live schedule: %w", err)
+	}
+	return &schedule, nil
+}
+
+// saveGoLive persists a schedule; finished schedules are kept for a day.
+func (s *TradingModeService) saveGoLive(ctx context.Context, schedule *GoLiveSchedule) error {
+	data, err := json.Marshal(schedule)
+	if err != nil {
+		return fmt.Errorf("failed to marshal go-live schedule: %w", err)
+	}
+	var ttl time.Duration
+	if schedule.Status != GoLiveStatusScheduled {
+		ttl = confirmationRetention
+	}
+	if err := s.redis.Set(ctx, tradingModeGoLiveKey, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to persist go-live schedule: %w", err)
+	}
+	return nil
+}
+
+// normalizeGoLiveReminders sorts reminders from the earliest to the latest
+// and drops non-positive values.
+func normalizeGoLiveReminders(reminders []time.Duration) []time.Duration {
+	if len(reminders) == 0 {
+		reminders = defaultGoLiveReminders
+	}
+	normalized := make([]time.Duration, 0, len(reminders))
+	for _, before := range reminders {
+		if before > 0 {
+			normalized = append(normalized, before)
+		}
+	}
+	sort.Slice(normalized, func(i, j int) bool { return normalized[i] > normalized[j] })
+	return normalized
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingGoLiveNotifier struct {
+	mu     sync.Mutex
+	chats  []int64
+	stages []GoLiveStage
+}
+
+func (n *recordingGoLiveNotifier) NotifyGoLive(ctx context.Context, chatID int64, notification GoLiveNotification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.chats = append(n.chats, chatID)
+	n.stages = append(n.stages, notification.Stage)
+	return nil
+}
+
+func TestTradingModeService_ScheduledGoLiveCountdownAndActivation(t *testing.T) {
+	svc, now := newTestTradingModeService(t, TradingModeConfig{
+		Operators:       []string{"op-1", "op-2"},
+		GoLiveReminders: []time.Duration{time.Minute, 30 * time.Minute, time.Hour},
+		NotifyChatIDs:   []int64{42},
+	})
+	notifier := &recordingGoLiveNotifier{}
+	svc.SetGoLiveNotifier(notifier)
+
+	_, err := svc.ScheduleGoLive(t.Context(), now.Add(-time.Minute), "op-1")
+	assert.ErrorIs(t, err, ErrInvalidGoLiveTime)
+	_, err = svc.ScheduleGoLive(t.Context(), now.Add(8*24*time.Hour), "op-1")
+	assert.ErrorIs(t, err, ErrInvalidGoLiveTime)
+
+	activateAt := now.Add(45 * time.Minute)
+	pending, err := svc.ScheduleGoLive(t.Context(), activateAt, "op-1")
+	require.NoError(t, err)
+	assert.Equal(t, ConfirmationStatusPending, pending.Status)
+	require.NotNil(t, pending.ActivateAt)
+	_, err = svc.GetGoLiveSchedule(t.Context())
+	assert.ErrorIs(t, err, ErrGoLiveNotScheduled, "nothing is scheduled before confirmation")
+
+	confirmed, err := svc.Confirm(t.Context(), pending.ID, "op-2", "")
+	require.NoError(t, err)
+	assert.Equal(t, ConfirmationStatusExecuted, confirmed.Status)
+	assert.False(t, svc.IsLive(t.Context()), "confirmation schedules rather than switches")
+
+	schedule, err := svc.GetGoLiveSchedule(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, GoLiveStatusScheduled, schedule.Status)
+	assert.Equal(t, "op-1", schedule.ScheduledBy)
+	assert.Equal(t, "op-2", schedule.ConfirmedBy)
+
+	_, err = svc.ScheduleGoLive(t.Context(), activateAt, "op-1")
+	assert.ErrorIs(t, err, ErrGoLiveAlreadyScheduled)
+
+	// The 1h reminder was already inside the countdown; 30m and 1m fire once
+	svc.CheckGoLive(t.Context())
+	*now = now.Add(20 * time.Minute)
+	svc.CheckGoLive(t.Context())
+	svc.CheckGoLive(t.Context())
+	*now = now.Add(24*time.Minute + 30*time.Second)
+	svc.CheckGoLive(t.Context())
+	assert.False(t, svc.IsLive(t.Context()))
+
+	*now = now.Add(time.Minute)
+	svc.CheckGoLive(t.Context())
+	svc.CheckGoLive(t.Context())
+	assert.True(t, svc.IsLive(t.Context()))
+
+	schedule, err = svc.GetGoLiveSchedule(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, GoLiveStatusActivated, schedule.Status)
+	require.NotNil(t, schedule.ActivatedAt)
+	assert.Equal(t, []GoLiveStage{GoLiveStageScheduled, GoLiveStageReminder, GoLiveStageReminder, GoLiveStageActivated}, notifier.stages)
+	assert.Equal(t, []int64{42, 42, 42, 42}, notifier.chats)
+}
+
+func TestTradingModeService_CancelScheduledGoLive(t *testing.T) {
+	svc, now := newTestTradingModeService(t, TradingModeConfig{Operators: []string{"op-1", "op-2"}, NotifyChatIDs: []int64{42}})
+	notifier := &recordingGoLiveNotifier{}
+	svc.SetGoLiveNotifier(notifier)
+
+	schedule := func() *GoLiveSchedule {
+		pending, err := svc.ScheduleGoLive(t.Context(), now.Add(time.Hour), "op-1")
+		require.NoError(t, err)
+		_, err = svc.Confirm(t.Context(), pending.ID, "op-2", "")
+		require.NoError(t, err)
+		scheduled, err := svc.GetGoLiveSchedule(t.Context())
+		require.NoError(t, err)
+		return scheduled
+	}
+
+	scheduled := schedule()
+	_, err := svc.CancelGoLive(t.Context(), "other", "telegram:42")
+	assert.ErrorIs(t, err, ErrGoLiveNotScheduled)
+	cancelled, err := svc.CancelGoLive(t.Context(), scheduled.ID, "telegram:42")
+	require.NoError(t, err)
+	assert.Equal(t, GoLiveStatusCancelled, cancelled.Status)
+	assert.Equal(t, "telegram:42", cancelled.CancelledBy)
+	_, err = svc.CancelGoLive(t.Context(), scheduled.ID, "telegram:42")
+	assert.ErrorIs(t, err, ErrGoLiveNotScheduled)
+
+	*now = now.Add(2 * time.Hour)
+	svc.CheckGoLive(t.Context())
+	assert.False(t, svc.IsLive(t.Context()), "a cancelled go-live never activates")
+
+	// Switching to paper also cancels a scheduled go-live
+	schedule()
+	_, err = svc.SwitchMode(t.Context(), ExecutionModePaper, "op-2")
+	require.NoError(t, err)
+	cancelled, err = svc.GetGoLiveSchedule(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, GoLiveStatusCancelled, cancelled.Status)
+	assert.Equal(t, []GoLiveStage{GoLiveStageScheduled, GoLiveStageCancelled, GoLiveStageScheduled, GoLiveStageCancelled}, notifier.stages)
+}
+
+func TestTradingModeService_ScheduledGoLiveRechecksAtActivation(t *testing.T) {
+	svc, now := newTestTradingModeService(t, TradingModeConfig{Operators: []string{"op-1", "op-2"}})
+	var blocked error
+	svc.AddLiveTradingCheck(func(ctx context.Context) error { return blocked })
+
+	pending, err := svc.ScheduleGoLive(t.Context(), now.Add(time.Hour), "op-1")
+	require.NoError(t, err)
+	_, err = svc.Confirm(t.Context(), pending.ID, "op-2", "")
+	require.NoError(t, err)
+
+	blocked = ErrMigrationsPending
+	*now = now.Add(time.Hour)
+	svc.CheckGoLive(t.Context())
+	assert.False(t, svc.IsLive(t.Context()))
+
+	schedule, err := svc.GetGoLiveSchedule(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, GoLiveStatusFailed, schedule.Status)
+	assert.Contains(t, schedule.Error, "live trading is blocked")
+}
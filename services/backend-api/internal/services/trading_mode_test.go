@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTradingModeService(t *testing.T, config TradingModeConfig) (*TradingModeService, *time.Time) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc := NewTradingModeService(client, config)
+	svc.now = func() time.Time { return now }
+	return svc, &now
+}
+
+func TestTradingModeService_DefaultsToPaper(t *testing.T) {
+	svc, _ := newTestTradingModeService(t, TradingModeConfig{})
+
+	state, err := svc.GetState(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, ExecutionModePaper, state.Mode)
+	assert.False(t, svc.IsLive(t.Context()))
+	assert.False(t, svc.KillSwitchEngaged(t.Context()))
+}
+
+func TestTradingModeService_SwitchToLiveWithDelayedCode(t *testing.T) {
+	svc, now := newTestTradingModeService(t, TradingModeConfig{ConfirmationDelay: time.Minute})
+
+	pending, err := svc.SwitchMode(t.Context(), ExecutionModeLive, "op-1")
+	require.NoError(t, err)
+	require.NotNil(t, pending)
+	assert.Equal(t, ConfirmationStatusPending, pending.Status)
+	assert.Len(t, pending.Code, 6)
+	assert.False(t, svc.IsLive(t.Context()), "mode must not change before confirmation")
+
+	stored, err := svc.GetConfirmation(t.Context(), pending.ID)
+	require.NoError(t, err)
+	assert.Empty(t, stored.Code, "code must not be persisted")
+
+	_, err = svc.Confirm(t.Context(), pending.ID, "op-1", pending.Code)
+	assert.ErrorIs(t, err, ErrConfirmationTooEarly)
+
+	*now = now.Add(2 * time.Minute)
+	_, err = svc.Confirm(t.Context(), pending.ID, "op-1", "000000x")
+	assert.ErrorIs(t, err, ErrConfirmationInvalidCode)
+
+	confirmed, err := svc.Confirm(t.Context(), pending.ID, "op-1", pending.Code)
+	require.NoError(t, err)
+	assert.Equal(t, ConfirmationStatusExecuted, confirmed.Status)
+	assert.True(t, svc.IsLive(t.Context()))
+
+	_, err = svc.Confirm(t.Context(), pending.ID, "op-1", pending.Code)
+	assert.ErrorIs(t, err, ErrConfirmationNotPending)
+}
+
+func TestTradingModeService_SecondOperatorApproves(t *testing.T) {
+	svc, _ := newTestTradingModeService(t, TradingModeConfig{Operators: []string{"op-1"}})
+	require.NoError(t, svc.BindOperator(t.Context(), "op-2"))
+
+	pending, err := svc.SwitchMode(t.Context(), ExecutionModeLive, "op-1")
+	require.NoError(t, err)
+
+	_, err = svc.Confirm(t.Context(), pending.ID, "stranger", "")
+	assert.ErrorIs(t, err, ErrOperatorNotBound)
+
+	confirmed, err := svc.Confirm(t.Context(), pending.ID, "op-2", "")
+	require.NoError(t, err)
+	assert.Equal(t, "op-2", confirmed.ConfirmedBy)
+	assert.True(t, svc.IsLive(t.Context()))
+}
+
+func TestTradingModeService_ConfirmationExpires(t *testing.T) {
+	svc, now := newTestTradingModeService(t, TradingModeConfig{ConfirmationDelay: time.Minute, ConfirmationTTL: 5 * time.Minute})
+
+	pending, err := svc.SwitchMode(t.Context(), ExecutionModeLive, "op-1")
+	require.NoError(t, err)
+
+	*now = now.Add(10 * time.Minute)
+	_, err = svc.Confirm(t.Context(), pending.ID, "op-1", pending.Code)
+	assert.ErrorIs(t, err, ErrConfirmationExpired)
+
+	stored, err := svc.GetConfirmation(t.Context(), pending.ID)
+	require.NoError(t, err)
+	assert.Equal(t, ConfirmationStatusExpired, stored.Status)
+	assert.False(t, svc.IsLive(t.Context()))
+}
+
+func TestTradingModeService_TooManyInvalidCodesCancels(t *testing.T) {
+	svc, now := newTestTradingModeService(t, TradingModeConfig{})
+	pending, err := svc.SwitchMode(t.Context(), ExecutionModeLive, "op-1")
+	require.NoError(t, err)
+	*now = now.Add(2 * time.Minute)
+
+	for i := 0; i < maxConfirmationAttempts; i++ {
+		_, err = svc.Confirm(t.Context(), pending.ID, "op-1", "bad")
+		assert.ErrorIs(t, err, ErrConfirmationInvalidCode)
+	}
+	_, err = svc.Confirm(t.Context(), pending.ID, "op-1", pending.Code)
+	assert.ErrorIs(t, err, ErrConfirmationNotPending)
+}
+
+func TestTradingModeService_LiquidateAllPaperRunsImmediately(t *testing.T) {
+	svc, _ := newTestTradingModeService(t, TradingModeConfig{})
+	calls := 0
+	svc.RegisterActionHandler(ProtectedActionLiquidateAll, func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+
+	result, err := svc.RequestAction(t.Context(), ProtectedActionLiquidateAll, "op-1")
+	require.NoError(t, err)
+	assert.Equal(t, ConfirmationStatusExecuted, result.Status)
+	assert.Equal(t, 1, calls)
+}
+
+func TestTradingModeService_LiveKillSwitchReleaseNeedsConfirmation(t *testing.T) {
+	svc, now := newTestTradingModeService(t, TradingModeConfig{DefaultMode: ExecutionModeLive})
+	calls := 0
+	svc.RegisterActionHandler(ProtectedActionLiquidateAll, func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+
+	require.NoError(t, svc.EngageKillSwitch(t.Context(), "op-1", "drawdown"))
+	assert.True(t, svc.KillSwitchEngaged(t.Context()))
+
+	release, err := svc.RequestAction(t.Context(), ProtectedActionKillSwitchRelease, "op-1")
+	require.NoError(t, err)
+	assert.Equal(t, ConfirmationStatusPending, release.Status)
+	assert.True(t, svc.KillSwitchEngaged(t.Context()))
+
+	liquidate, err := svc.RequestAction(t.Context(), ProtectedActionLiquidateAll, "op-1")
+	require.NoError(t, err)
+	assert.Equal(t, ConfirmationStatusPending, liquidate.Status)
+	assert.Equal(t, 0, calls)
+
+	*now = now.Add(2 * time.Minute)
+	_, err = svc.Confirm(t.Context(), release.ID, "op-1", release.Code)
+	require.NoError(t, err)
+	assert.False(t, svc.KillSwitchEngaged(t.Context()))
+
+	cancelled, err := svc.Cancel(t.Context(), liquidate.ID, "op-1")
+	require.NoError(t, err)
+	assert.Equal(t, ConfirmationStatusCancelled, cancelled.Status)
+	assert.Equal(t, 0, calls)
+}
+
+func TestTradingModeService_SwitchToPaperIsImmediate(t *testing.T) {
+	svc, _ := newTestTradingModeService(t, TradingModeConfig{DefaultMode: ExecutionModeLive})
+
+	pending, err := svc.SwitchMode(t.Context(), ExecutionModePaper, "op-1")
+	require.NoError(t, err)
+	assert.Nil(t, pending)
+	assert.False(t, svc.IsLive(t.Context()))
+
+	_, err = svc.SwitchMode(t.Context(), "dry", "op-1")
+	assert.ErrorIs(t, err, ErrInvalidExecutionMode)
+}
+
+func TestTradingModeService_LiveTradingChecks(t *testing.T) {
+	svc, now := newTestTradingModeService(t, TradingModeConfig{Operators: []string{"op-1", "op-2"}})
+	var blocked error
+	svc.AddLiveTradingCheck(func(ctx context.Context) error { return blocked })
+
+	blocked = ErrMigrationsPending
+	_, err := svc.SwitchMode(t.Context(), ExecutionModeLive, "op-1")
+	assert.ErrorIs(t, err, ErrLiveTradingBlocked)
+
+	blocked = nil
+	pending, err := svc.SwitchMode(t.Context(), ExecutionModeLive, "op-1")
+	require.NoError(t, err)
+
+	// The check runs again at confirmation in case state changed meanwhile.
+	blocked = ErrMigrationsPending
+	*now = now.Add(2 * time.Minute)
+	confirmation, err := svc.Confirm(t.Context(), pending.ID, "op-2", "")
+	assert.ErrorIs(t, err, ErrLiveTradingBlocked)
+	assert.Equal(t, ConfirmationStatusFailed, confirmation.Status)
+	assert.False(t, svc.IsLive(t.Context()))
+}
@@ -0,0 +1,379 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+)
+
+// ReportKind selects the period of a scheduled trading report.
+type ReportKind string
+
+const (
+	ReportDaily  ReportKind = "daily"
+	ReportWeekly ReportKind = "weekly"
+)
+
+// CalendarEvent is an upcoming market event worth knowing before trading,
+// such as a rate decision or a token unlock.
+type CalendarEvent struct {
+	Time  time.Time `json:"time"`
+	Title string    `json:"title"`
+}
+
+// CalendarSource lists upcoming market events.
+type CalendarSource interface {
+	UpcomingEvents(ctx context.Context, from, to time.Time) ([]CalendarEvent, error)
+}
+
+// ConfiguredCalendar is an operator-maintained list of market events.
+type ConfiguredCalendar struct {
+	events []CalendarEvent
+}
+
+// NewConfiguredCalendar creates a calendar from a fixed list of events.
+//
+// Parameters:
+//
+//	events: The events.
+//
+// Returns:
+//
+//	*ConfiguredCalendar: The initialized calendar.
+func NewConfiguredCalendar(events []CalendarEvent) *ConfiguredCalendar {
+	sorted := append([]CalendarEvent(nil), events...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+	return &ConfiguredCalendar{events: sorted}
+}
+
+// ParseCalendarEvents parses "RFC3339 time|title" entries separated by
+// semicolons, e.g. "2026-11-04T18:00:00Z|FOMC rate decision".
+//
+// Parameters:
+//
+//	raw: The entries.
+//
+// Returns:
+//
+//	[]CalendarEvent: The events.
+//	error: Error naming the first malformed entry.
+func ParseCalendarEvents(raw string) ([]CalendarEvent, error) {
+	var events []CalendarEvent
+	for _, entry := range strings.Split(raw, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		at, title, ok := strings.Cut(entry, "|")
+		if !ok || strings.TrimSpace(title) == "" {
+			return nil, fmt.Errorf("calendar entry %q must be 'time|title'", entry)
+		}
+		parsed, err := time.Parse(time.RFC3339, strings.TrimSpace(at))
+		if err != nil {
+			return nil, fmt.Errorf("calendar entry %q: %w", entry, err)
+		}
+		events = append(events, CalendarEvent{Time: parsed.UTC(), Title: strings.TrimSpace(title)})
+	}
+	return events, nil
+}
+
+// UpcomingEvents returns the events between from and to.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	from: Window start.
+//	to: Window end.
+//
+// Returns:
+//
+//	[]CalendarEvent: Events in the window, soonest first.
+//	error: Always nil.
+func (c *ConfiguredCalendar) UpcomingEvents(_ context.Context, from, to time.Time) ([]CalendarEvent, error) {
+	var upcoming []CalendarEvent
+	for _, event := range c.events {
+		if !event.Time.Before(from) && !event.Time.After(to) {
+			upcoming = append(upcoming, event)
+		}
+	}
+	return upcoming, nil
+}
+
+// ReportMailer sends a notification by email. It is implemented by
+// EmailChannel.
+type ReportMailer interface {
+	Send(ctx context.Context, notification *Notification) error
+}
+
+// TradingReportConfig configures scheduled trading reports.
+type TradingReportConfig struct {
+	// EmailRecipients also receive the report as HTML email.
+	EmailRecipients []string
+	// CalendarLookahead is how far ahead calendar events are listed.
+	CalendarLookahead time.Duration
+	// MaxRiskEvents caps the risk events listed in a report.
+	MaxRiskEvents int
+}
+
+// DefaultTradingReportConfig returns the default trading report settings.
+func DefaultTradingReportConfig() TradingReportConfig {
+	return TradingReportConfig{
+		CalendarLookahead: 7 * 24 * time.Hour,
+		MaxRiskEvents:     5,
+	}
+}
+
+// TradingReport is a scheduled summary of a chat's trading.
+type TradingReport struct {
+	Kind       ReportKind        `json:"kind"`
+	ChatID     string            `json:"chat_id"`
+	PnL        *PnLReport        `json:"pnl"`
+	RiskEvents []RiskEventRecord `json:"risk_events"`
+	Calendar   []CalendarEvent   `json:"calendar"`
+}
+
+// TradingReportGenerator assembles and delivers the daily and weekly
+// reports: PnL, trade stats, strategy attribution, notable risk events and
+// upcoming calendar events.
+type TradingReportGenerator struct {
+	pnl       *PnLReporter
+	risk      RiskEventSource
+	calendar  CalendarSource
+	messenger DirectMessenger
+	mailer    ReportMailer
+	config    TradingReportConfig
+	logger    *slog.Logger
+}
+
+// NewTradingReportGenerator creates a trading report generator.
+//
+// Parameters:
+//
+//	pnl: PnL reporter.
+//	risk: Risk event source (may be nil).
+//	calendar: Calendar source (may be nil).
+//	config: Report settings; zero values use the defaults.
+//
+// Returns:
+//
+//	*TradingReportGenerator: The initialized generator.
+func NewTradingReportGenerator(pnl *PnLReporter, risk RiskEventSource, calendar CalendarSource, config TradingReportConfig) *TradingReportGenerator {
+	defaults := DefaultTradingReportConfig()
+	if config.CalendarLookahead <= 0 {
+		config.CalendarLookahead = defaults.CalendarLookahead
+	}
+	if config.MaxRiskEvents <= 0 {
+		config.MaxRiskEvents = defaults.MaxRiskEvents
+	}
+	return &TradingReportGenerator{
+		pnl:      pnl,
+		risk:     risk,
+		calendar: calendar,
+		config:   config,
+		logger:   telemetry.Logger().With("component", "trading_report"),
+	}
+}
+
+// SetMessenger sets the Telegram delivery of reports.
+func (g *TradingReportGenerator) SetMessenger(messenger DirectMessenger) {
+	g.messenger = messenger
+}
+
+// SetMailer sets the email delivery of reports to the configured recipients.
+func (g *TradingReportGenerator) SetMailer(mailer ReportMailer) {
+	g.mailer = mailer
+}
+
+// Generate assembles a report of a chat.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	chatID: Chat the report is for.
+//	kind: Daily (last 24h) or weekly (last 7d).
+//
+// Returns:
+//
+//	*TradingReport: The report.
+//	error: Error if the PnL cannot be loaded.
+func (g *TradingReportGenerator) Generate(ctx context.Context, chatID string, kind ReportKind) (*TradingReport, error) {
+	period := DefaultPnLPeriod
+	if kind == ReportWeekly {
+		period = "7d"
+	}
+	pnl, err := g.pnl.Report(ctx, chatID, period)
+	if err != nil {
+		return nil, err
+	}
+	report := &TradingReport{Kind: kind, ChatID: chatID, PnL: pnl}
+
+	if g.risk != nil {
+		events, err := g.risk.RecentRiskEvents(ctx, pnl.Since, 0)
+		if err != nil {
+			g.logger.Warn("Failed to load risk events for report", "chat_id", chatID, "error", err)
+		}
+		for _, event := range events {
+			// Events without a chat concern the shared account
+			if event.ChatID == "" || event.ChatID == chatID {
+				report.RiskEvents = append(report.RiskEvents, event)
+			}
+		}
+		if len(report.RiskEvents) > g.config.MaxRiskEvents {
+			report.RiskEvents = report.RiskEvents[len(report.RiskEvents)-g.config.MaxRiskEvents:]
+		}
+	}
+
+	if g.calendar != nil {
+		events, err := g.calendar.UpcomingEvents(ctx, pnl.GeneratedAt, pnl.GeneratedAt.Add(g.config.CalendarLookahead))
+		if err != nil {
+			g.logger.Warn("Failed to load calendar for report", "chat_id", chatID, "error", err)
+		}
+		report.Calendar = events
+	}
+	return report, nil
+}
+
+// Deliver sends a report to its chat and, when a mailer and recipients are
+// configured, as HTML email.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	report: The report.
+//
+// Returns:
+//
+//	error: Error if a delivery fails.
+func (g *TradingReportGenerator) Deliver(ctx context.Context, report *TradingReport) error {
+	text := FormatTradingReport(report)
+	if chatID, err := strconv.ParseInt(report.ChatID, 10, 64); err == nil && g.messenger != nil {
+		if err := g.messenger.SendDirectMessage(ctx, chatID, text); err != nil {
+			return fmt.Errorf("failed to send report to chat %s: %w", report.ChatID, err)
+		}
+	}
+	if g.mailer == nil || len(g.config.EmailRecipients) == 0 {
+		return nil
+	}
+	html, err := FormatTradingReportHTML(report)
+	if err != nil {
+		return err
+	}
+	notification := &Notification{
+		ID:         fmt.Sprintf("report-%s", uuid.New().String()),
+		Type:       NotificationTypeDailySummary,
+		Priority:   PriorityNotificationLow,
+		Title:      report.Title(),
+		Message:    text,
+		HTML:       html,
+		Timestamp:  report.PnL.GeneratedAt,
+		Recipients: g.config.EmailRecipients,
+	}
+	if err := g.mailer.Send(ctx, notification); err != nil {
+		return fmt.Errorf("failed to email report: %w", err)
+	}
+	return nil
+}
+
+// Title is the heading of the report.
+func (r *TradingReport) Title() string {
+	if r.Kind == ReportWeekly {
+		return "Weekly Performance Report"
+	}
+	return "Daily Performance Report"
+}
+
+// FormatTradingReport renders a report as a plain-text Telegram message.
+//
+// Parameters:
+//
+//	report: The report.
+//
+// Returns:
+//
+//	string: The message.
+func FormatTradingReport(report *TradingReport) string {
+	var b strings.Builder
+	b.WriteString(FormatPnLReport(report.PnL, report.Title()))
+
+	if len(report.PnL.Strategies) > 0 {
+		b.WriteString("\n\n🧩 Strategies\n")
+		for _, strategy := range report.PnL.Strategies {
+			fmt.Fprintf(&b, "%s: %s USDT, %d trades (%d wins)\n",
+				strategy.Strategy, signedAmount(strategy.PnL), strategy.Trades, strategy.Wins)
+		}
+	}
+	if len(report.RiskEvents) > 0 {
+		b.WriteString("\n\n⚠️ Risk events\n")
+		for _, event := range report.RiskEvents {
+			fmt.Fprintf(&b, "%s %s\n", event.At.Format("Jan 02 15:04"), riskEventLabel(event))
+		}
+	}
+	if len(report.Calendar) > 0 {
+		b.WriteString("\n\n📅 Upcoming\n")
+		for _, event := range report.Calendar {
+			fmt.Fprintf(&b, "%s UTC %s\n", event.Time.Format("Mon Jan 02 15:04"), event.Title)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+var tradingReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{"label": riskEventLabel}).Parse(`<html><body style="font-family:sans-serif">
+<h2>{{.Title}} ({{.PnL.Period}})</h2>
+<table cellpadding="4">
+<tr><td>Realized</td><td>{{.PnL.Realized.StringFixed 2}} USDT</td></tr>
+<tr><td>Unrealized</td><td>{{.PnL.Unrealized.StringFixed 2}} USDT ({{.PnL.OpenPositions}} open)</td></tr>
+<tr><td>Fees</td><td>{{.PnL.Fees.StringFixed 2}} USDT</td></tr>
+<tr><td><b>Net</b></td><td><b>{{.PnL.Net.StringFixed 2}} USDT</b></td></tr>
+<tr><td>Trades</td><td>{{.PnL.Trades}} ({{.PnL.Wins}}W / {{.PnL.Losses}}L)</td></tr>
+{{with .PnL.BestTrade}}<tr><td>Best</td><td>{{.Symbol}} {{.PnL.StringFixed 2}}</td></tr>{{end}}
+{{with .PnL.WorstTrade}}<tr><td>Worst</td><td>{{.Symbol}} {{.PnL.StringFixed 2}}</td></tr>{{end}}
+{{if .PnL.Equity.IsPositive}}<tr><td>Equity</td><td>{{.PnL.Equity.StringFixed 2}} USDT ({{printf "%+.2f" .PnL.EquityChangePct}}%)</td></tr>{{end}}
+</table>
+{{if .PnL.Strategies}}<h3>Strategies</h3>
+<table cellpadding="4"><tr><th align="left">Strategy</th><th>PnL</th><th>Trades</th><th>Wins</th></tr>
+{{range .PnL.Strategies}}<tr><td>{{.Strategy}}</td><td>{{.PnL.StringFixed 2}}</td><td>{{.Trades}}</td><td>{{.Wins}}</td></tr>
+{{end}}</table>{{end}}
+{{if .RiskEvents}}<h3>Risk events</h3>
+<ul>{{range .RiskEvents}}<li>{{.At.Format "Jan 02 15:04"}} {{label .}}</li>{{end}}</ul>{{end}}
+{{if .Calendar}}<h3>Upcoming</h3>
+<ul>{{range .Calendar}}<li>{{.Time.Format "Mon Jan 02 15:04"}} UTC {{.Title}}</li>{{end}}</ul>{{end}}
+</body></html>`))
+
+// FormatTradingReportHTML renders a report as an HTML email body.
+//
+// Parameters:
+//
+//	report: The report.
+//
+// Returns:
+//
+//	string: The HTML document.
+//	error: Error if rendering fails.
+func FormatTradingReportHTML(report *TradingReport) (string, error) {
+	var buf bytes.Buffer
+	if err := tradingReportTemplate.Execute(&buf, report); err != nil {
+		return "", fmt.Errorf("failed to render report: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// riskEventLabel describes a risk event in a few words.
+func riskEventLabel(event RiskEventRecord) string {
+	label := strings.ReplaceAll(event.Type, "_", " ")
+	if label == "" {
+		label = "risk event"
+	}
+	if subject := event.Subject(); subject != "" {
+		label += " (" + subject + ")"
+	}
+	return label
+}
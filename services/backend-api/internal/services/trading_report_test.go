@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticRiskEvents []RiskEventRecord
+
+func (s staticRiskEvents) RecentRiskEvents(context.Context, time.Time, int) ([]RiskEventRecord, error) {
+	return s, nil
+}
+
+type recordingMailer struct {
+	notifications []*Notification
+}
+
+func (r *recordingMailer) Send(_ context.Context, notification *Notification) error {
+	r.notifications = append(r.notifications, notification)
+	return nil
+}
+
+func TestTradingReportGenerator(t *testing.T) {
+	trades := &staticClosedTrades{trades: []ClosedTrade{
+		{Strategy: "scalping", Symbol: "BTC/USDT", PnL: decimal.NewFromInt(25), Fees: decimal.NewFromInt(1)},
+		{Strategy: "scalping", Symbol: "ETH/USDT", PnL: decimal.NewFromInt(-5), Fees: decimal.NewFromInt(1)},
+		{Strategy: "funding_arbitrage", Symbol: "SOL/USDT", PnL: decimal.NewFromInt(8)},
+	}}
+	risk := staticRiskEvents{
+		{Type: "daily_loss_halt", ChatID: "7", At: time.Now()},
+		{Type: "consecutive_losses", ChatID: "42", Data: map[string]interface{}{"strategy": "scalping"}, At: time.Now()},
+		{Type: "exchange_paused", Data: map[string]interface{}{"exchange": "binance"}, At: time.Now()},
+	}
+	calendar := NewConfiguredCalendar([]CalendarEvent{
+		{Time: time.Now().Add(30 * 24 * time.Hour), Title: "Too far"},
+		{Time: time.Now().Add(48 * time.Hour), Title: "FOMC rate decision"},
+	})
+	generator := NewTradingReportGenerator(NewPnLReporter(trades, nil, nil), risk, calendar, TradingReportConfig{
+		EmailRecipients: []string{"ops@example.com"},
+	})
+	messenger := &recordingMessenger{}
+	mailer := &recordingMailer{}
+	generator.SetMessenger(messenger)
+	generator.SetMailer(mailer)
+
+	report, err := generator.Generate(context.Background(), "42", ReportWeekly)
+	require.NoError(t, err)
+	assert.Equal(t, "7d", report.PnL.Period)
+	require.Len(t, report.PnL.Strategies, 2)
+	assert.Equal(t, "funding_arbitrage", report.PnL.Strategies[1].Strategy)
+	assert.Equal(t, 2, report.PnL.Strategies[0].Trades)
+	// The other chat's halt is left out
+	require.Len(t, report.RiskEvents, 2)
+	require.Len(t, report.Calendar, 1)
+
+	require.NoError(t, generator.Deliver(context.Background(), report))
+	require.Len(t, messenger.texts, 1)
+	assert.Equal(t, int64(42), messenger.chats[0])
+	text := messenger.texts[0]
+	assert.Contains(t, text, "Weekly Performance Report (7d)")
+	assert.Contains(t, text, "scalping: +20.00 USDT, 2 trades (1 wins)")
+	assert.Contains(t, text, "consecutive losses (scalping)")
+	assert.Contains(t, text, "exchange paused (binance)")
+	assert.Contains(t, text, "FOMC rate decision")
+
+	require.Len(t, mailer.notifications, 1)
+	email := mailer.notifications[0]
+	assert.Equal(t, []string{"ops@example.com"}, email.Recipients)
+	assert.Equal(t, text, email.Message)
+	assert.Contains(t, email.HTML, "<h2>Weekly Performance Report (7d)</h2>")
+	assert.Contains(t, email.HTML, "<td>funding_arbitrage</td>")
+}
+
+func TestParseCalendarEvents(t *testing.T) {
+	events, err := ParseCalendarEvents("2026-11-04T18:00:00Z|FOMC rate decision; 2026-11-12T13:30:00Z | US CPI ;")
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "US CPI", events[1].Title)
+	assert.Equal(t, 13, events[1].Time.Hour())
+
+	_, err = ParseCalendarEvents("2026-11-04|FOMC")
+	assert.Error(t, err)
+	_, err = ParseCalendarEvents("2026-11-04T18:00:00Z")
+	assert.Error(t, err)
+}
@@ -0,0 +1,434 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrUserNotFound is returned when no user has the requested ID.
+var ErrUserNotFound = errors.New("user not found")
+
+// Categories of personal data removed by a user data erasure.
+const (
+	UserDataTelegramBinding     = "telegram_binding"
+	UserDataNotificationLogs    = "notification_logs"
+	UserDataOperatorNotes       = "operator_notes"
+	UserDataAlerts              = "alerts"
+	UserDataCredentials         = "credentials"
+	UserDataPersonalIdentifiers = "personal_identifiers"
+)
+
+// Actions taken on personal data.
+const (
+	UserDataActionDelete    = "delete"
+	UserDataActionAnonymize = "anonymize"
+)
+
+// UserDataAction is what an erasure does, or would do, to one store.
+type UserDataAction struct {
+	Category string `json:"category"`
+	// Target is the table, column or Redis key affected.
+	Target string `json:"target"`
+	Action string `json:"action"`
+	Count  int64  `json:"count"`
+	// IDs lists the affected records where they are addressable, such as
+	// operator notes, so a dry run can be reviewed record by record.
+	IDs []string `json:"ids,omitempty"`
+}
+
+// UserDataReport lists what an erasure removed and what it kept.
+type UserDataReport struct {
+	UserID string `json:"user_id"`
+	// DryRun is true when nothing was changed.
+	DryRun  bool             `json:"dry_run"`
+	Actions []UserDataAction `json:"actions"`
+	// Removed is the total number of records deleted or anonymized.
+	Removed int64 `json:"removed"`
+	// Preserved lists the trading statistics kept under the anonymized user.
+	Preserved []UserDataAction `json:"preserved"`
+	ErasedAt  *time.Time       `json:"erased_at,omitempty"`
+}
+
+// OperatorRegistry lists and unbinds trading-mode operators.
+// TradingModeService satisfies this interface.
+type OperatorRegistry interface {
+	ListOperators(ctx context.Context) ([]string, error)
+	UnbindOperator(ctx context.Context, operator string) error
+}
+
+// userIdentity is the personal data a user's records are found by.
+type userIdentity struct {
+	id     string
+	email  string
+	chatID string
+}
+
+// userDataStep removes or anonymizes one kind of record in the database.
+type userDataStep struct {
+	category string
+	table    string
+	target   string
+	action   string
+	count    string
+	// list, when set, replaces count and returns the IDs of the records.
+	list  string
+	apply string
+	args  func(u userIdentity) []any
+}
+
+func userIDArg(u userIdentity) []any { return []any{u.id} }
+
+// userDataSteps run in order inside one transaction. The users row itself is
+// anonymized last rather than deleted, so trades and reasoning summaries
+// keep a valid, no longer identifying owner.
+var userDataSteps = []userDataStep{
+	{
+		category: UserDataTelegramBinding, table: "one_time_codes", action: UserDataActionDelete,
+		count: `SELECT COUNT(*) FROM one_time_codes WHERE user_id = $1`,
+		apply: `DELETE FROM one_time_codes WHERE user_id = $1`,
+		args:  userIDArg,
+	},
+	{
+		category: UserDataTelegramBinding, table: "users", target: "users.telegram_chat_id", action: UserDataActionAnonymize,
+		count: `SELECT COUNT(*) FROM users WHERE id = $1 AND telegram_chat_id IS NOT NULL`,
+		apply: `UPDATE users SET telegram_chat_id = NULL WHERE id = $1 AND telegram_chat_id IS NOT NULL`,
+		args:  userIDArg,
+	},
+	{
+		category: UserDataNotificationLogs, table: "alert_notifications", action: UserDataActionDelete,
+		count: `SELECT COUNT(*) FROM alert_notifications WHERE user_id = $1`,
+		apply: `DELETE FROM alert_notifications WHERE user_id = $1`,
+		args:  userIDArg,
+	},
+	{
+		category: UserDataNotificationLogs, table: "notification_dead_letters", action: UserDataActionDelete,
+		count: `SELECT COUNT(*) FROM notification_dead_letters WHERE user_id = $1 OR ($2::TEXT <> '' AND chat_id = $2::TEXT)`,
+		apply: `DELETE FROM notification_dead_letters WHERE user_id = $1 OR ($2::TEXT <> '' AND chat_id = $2::TEXT)`,
+		args:  func(u userIdentity) []any { return []any{u.id, u.chatID} },
+	},
+	{
+		// Notes are matched on the user they were attributed to, never on
+		// their text: chat IDs are plain numbers found in unrelated notes
+		category: UserDataOperatorNotes, table: "embedded_documents", target: "embedded_documents (notes)", action: UserDataActionDelete,
+		list:  `SELECT source_id FROM embedded_documents WHERE kind = 'note' AND user_id = $1 ORDER BY source_id`,
+		apply: `DELETE FROM embedded_documents WHERE kind = 'note' AND user_id = $1`,
+		args:  userIDArg,
+	},
+	{
+		category: UserDataAlerts, table: "user_alerts", action: UserDataActionDelete,
+		count: `SELECT COUNT(*) FROM user_alerts WHERE user_id = $1`,
+		apply: `DELETE FROM user_alerts WHERE user_id = $1`,
+		args:  userIDArg,
+	},
+	{
+		category: UserDataCredentials, table: "user_api_keys", action: UserDataActionDelete,
+		count: `SELECT COUNT(*) FROM user_api_keys WHERE user_id = $1`,
+		apply: `DELETE FROM user_api_keys WHERE user_id = $1`,
+		args:  userIDArg,
+	},
+	{
+		category: UserDataCredentials, table: "exchange_api_keys", action: UserDataActionDelete,
+		count: `SELECT COUNT(*) FROM exchange_api_keys WHERE user_id = $1`,
+		apply: `DELETE FROM exchange_api_keys WHERE user_id = $1`,
+		args:  userIDArg,
+	},
+	{
+		category: UserDataPersonalIdentifiers, table: "ai_usage", target: "ai_usage.user_id", action: UserDataActionAnonymize,
+		count: `SELECT COUNT(*) FROM ai_usage WHERE user_id = $1`,
+		apply: `UPDATE ai_usage SET user_id = NULL WHERE user_id = $1`,
+		args:  userIDArg,
+	},
+	{
+		category: UserDataPersonalIdentifiers, table: "users", target: "users.email, users.password_hash", action: UserDataActionAnonymize,
+		count: `SELECT COUNT(*) FROM users WHERE id = $1 AND email <> $2`,
+		apply: `UPDATE users SET email = $2, password_hash = '', updated_at = NOW() WHERE id = $1 AND email <> $2`,
+		args:  func(u userIdentity) []any { return []any{u.id, anonymizedEmail(u.id)} },
+	},
+}
+
+// userDataPreserved are the trading records kept, attributed to the
+// anonymized user, for aggregate statistics.
+var userDataPreserved = []struct{ table, count string }{
+	{"paper_trades", `SELECT COUNT(*) FROM paper_trades WHERE user_id = $1`},
+	{"ai_reasoning_summaries", `SELECT COUNT(*) FROM ai_reasoning_summaries WHERE user_id = $1`},
+	{"backtest_results", `SELECT COUNT(*) FROM backtest_results WHERE user_id = $1`},
+}
+
+// UserDataService erases a user's personal data while keeping anonymized
+// trading statistics.
+type UserDataService struct {
+	db        DBPool
+	redis     *redis.Client
+	operators OperatorRegistry
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+// NewUserDataService creates a user data service.
+//
+// Parameters:
+//
+//	db: Database pool holding the users and their records.
+//	client: Redis client holding user caches and delivery receipts (may be nil).
+//
+// Returns:
+//
+//	*UserDataService: Initialized service.
+func NewUserDataService(db DBPool, client *redis.Client) *UserDataService {
+	return &UserDataService{
+		db:     db,
+		redis:  client,
+		logger: telemetry.Logger(),
+		now:    time.Now,
+	}
+}
+
+// SetOperatorRegistry also unbinds the user's Telegram chat as a
+// trading-mode operator.
+func (s *UserDataService) SetOperatorRegistry(operators OperatorRegistry) {
+	s.operators = operators
+}
+
+// Erase removes or anonymizes a user's Telegram binding, notification logs,
+// operator notes attributed to them, alerts, credentials and personal
+// identifiers. Database changes are made in one transaction.
+//
+// Parameters:
+//
+//	ctx: Context for the queries.
+//	userID: The user ID.
+//	dryRun: Only report what would be removed.
+//
+// Returns:
+//
+//	*UserDataReport: What was, or would be, removed and preserved.
+//	error: ErrUserNotFound or a database error.
+func (s *UserDataService) Erase(ctx context.Context, userID string, dryRun bool) (*UserDataReport, error) {
+	var email, chatID sql.NullString
+	err := s.db.QueryRow(ctx, `SELECT email, telegram_chat_id::TEXT FROM users WHERE id = $1`, userID).Scan(&email, &chatID)
+	if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	user := userIdentity{id: userID, email: email.String, chatID: chatID.String}
+	if user.email == anonymizedEmail(userID) {
+		user.email = ""
+	}
+
+	report := &UserDataReport{UserID: userID, DryRun: dryRun, Actions: []UserDataAction{}, Preserved: []UserDataAction{}}
+	if err := s.eraseDatabase(ctx, user, report); err != nil {
+		return nil, err
+	}
+	s.eraseRedis(ctx, user, report)
+	s.unbindOperator(ctx, user, report)
+
+	if !dryRun {
+		erasedAt := s.now().UTC()
+		report.ErasedAt = &erasedAt
+		s.logger.Info("User data erased", "user_id", userID, "removed", report.Removed)
+	}
+	return report, nil
+}
+
+// eraseDatabase counts and, unless dry-running, applies each step. Tables
+// that do not exist in this deployment are skipped.
+func (s *UserDataService) eraseDatabase(ctx context.Context, user userIdentity, report *UserDataReport) error {
+	tables, err := s.existingTables(ctx)
+	if err != nil {
+		return err
+	}
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin erasure: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	for _, step := range userDataSteps {
+		if !tables[step.table] {
+			continue
+		}
+		args := step.args(user)
+		var count int64
+		var ids []string
+		if step.list != "" {
+			var err error
+			if ids, err = listUserDataIDs(ctx, tx, step.list, args); err != nil {
+				return fmt.Errorf("failed to list %s: %w", step.table, err)
+			}
+			count = int64(len(ids))
+		} else if err := tx.QueryRow(ctx, step.count, args...).Scan(&count); err != nil {
+			return fmt.Errorf("failed to count %s: %w", step.table, err)
+		}
+		if count == 0 {
+			continue
+		}
+		if !report.DryRun {
+			if _, err := tx.Exec(ctx, step.apply, args...); err != nil {
+				return fmt.Errorf("failed to erase %s: %w", step.table, err)
+			}
+		}
+		target := step.target
+		if target == "" {
+			target = step.table
+		}
+		report.addAction(UserDataAction{Category: step.category, Target: target, Action: step.action, Count: count, IDs: ids})
+	}
+
+	for _, kept := range userDataPreserved {
+		if !tables[kept.table] {
+			continue
+		}
+		var count int64
+		if err := tx.QueryRow(ctx, kept.count, user.id).Scan(&count); err != nil {
+			return fmt.Errorf("failed to count %s: %w", kept.table, err)
+		}
+		if count > 0 {
+			report.Preserved = append(report.Preserved, UserDataAction{Target: kept.table, Action: UserDataActionAnonymize, Count: count})
+		}
+	}
+
+	if report.DryRun {
+		return nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit erasure: %w", err)
+	}
+	return nil
+}
+
+// listUserDataIDs returns the IDs selected by a step's list query.
+func listUserDataIDs(ctx context.Context, tx database.Tx, query string, args []any) ([]string, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// existingTables returns which of the tables the erasure touches exist.
+func (s *UserDataService) existingTables(ctx context.Context) (map[string]bool, error) {
+	names := make([]string, 0, len(userDataSteps)+len(userDataPreserved))
+	for _, step := range userDataSteps {
+		if !slices.Contains(names, step.table) {
+			names = append(names, step.table)
+		}
+	}
+	for _, kept := range userDataPreserved {
+		names = append(names, kept.table)
+	}
+	rows, err := s.db.Query(ctx, `
+		SELECT table_name FROM information_schema.tables
+		WHERE table_schema = current_schema() AND table_name = ANY($1)`, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	defer rows.Close()
+	tables := make(map[string]bool, len(names))
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan table name: %w", err)
+		}
+		tables[name] = true
+	}
+	return tables, rows.Err()
+}
+
+// eraseRedis deletes the user's cached profile, notification preferences
+// and delivery receipts.
+// Failures are logged: the database erasure already happened.
+func (s *UserDataService) eraseRedis(ctx context.Context, user userIdentity, report *UserDataReport) {
+	if s.redis == nil {
+		return
+	}
+	keys := []struct{ category, key string }{
+		{UserDataPersonalIdentifiers, fmt.Sprintf("user:id:%s", user.id)},
+		{UserDataNotificationLogs, deliveryStatsKey(user.id)},
+		{UserDataNotificationLogs, deliveryRecentKey(user.id)},
+		{UserDataPersonalIdentifiers, fmt.Sprintf("user_preferences:%s:arbitrage", user.id)},
+	}
+	if user.chatID != "" {
+		keys = append(keys,
+			struct{ category, key string }{UserDataTelegramBinding, fmt.Sprintf("user:telegram:%s", user.chatID)},
+			struct{ category, key string }{UserDataTelegramBinding, fmt.Sprintf("telegram:user:%s:notifications_enabled", user.chatID)},
+			struct{ category, key string }{UserDataPersonalIdentifiers, fmt.Sprintf("user_preferences:%s:arbitrage", user.chatID)},
+			struct{ category, key string }{UserDataNotificationLogs, deliveryBounceKey(user.chatID)},
+		)
+	}
+	for _, k := range keys {
+		exists, err := s.redis.Exists(ctx, k.key).Result()
+		if err != nil {
+			s.logger.Warn("Failed to check user data key", "key", k.key, "error", err)
+			continue
+		}
+		if exists == 0 {
+			continue
+		}
+		if !report.DryRun {
+			if err := s.redis.Del(ctx, k.key).Err(); err != nil {
+				s.logger.Warn("Failed to delete user data key", "key", k.key, "error", err)
+				continue
+			}
+		}
+		report.addAction(UserDataAction{Category: k.category, Target: k.key, Action: UserDataActionDelete, Count: 1})
+	}
+}
+
+// unbindOperator removes the user's Telegram chat from the operators who may
+// approve protected actions.
+func (s *UserDataService) unbindOperator(ctx context.Context, user userIdentity, report *UserDataReport) {
+	if s.operators == nil || user.chatID == "" {
+		return
+	}
+	operator := "telegram:" + user.chatID
+	operators, err := s.operators.ListOperators(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to list operators", "error", err)
+		return
+	}
+	if !slices.Contains(operators, operator) {
+		return
+	}
+	if !report.DryRun {
+		if err := s.operators.UnbindOperator(ctx, operator); err != nil {
+			s.logger.Warn("Failed to unbind operator", "operator", operator, "error", err)
+			return
+		}
+	}
+	report.addAction(UserDataAction{Category: UserDataTelegramBinding, Target: "trading mode operator", Action: UserDataActionDelete, Count: 1})
+}
+
+func (r *UserDataReport) addAction(action UserDataAction) {
+	r.Actions = append(r.Actions, action)
+	r.Removed += action.Count
+}
+
+// anonymizedEmail is a stable, unique placeholder that keeps the users.email
+// constraints satisfied without identifying the user.
+func anonymizedEmail(userID string) string {
+	sum := sha256.Sum256([]byte(userID))
+	return "deleted-" + hex.EncodeToString(sum[:8]) + "@anonymized.invalid"
+}
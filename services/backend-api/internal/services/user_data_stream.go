@@ -0,0 +1,432 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/shopspring/decimal"
+)
+
+// OrderUpdate is an order change pushed by an exchange user-data stream.
+type OrderUpdate struct {
+	Exchange string `json:"exchange"`
+	OrderID  string `json:"order_id"`
+	Symbol   string `json:"symbol"`
+	Side     string `json:"side"`
+	// Status is the CCXT order status: open, closed, canceled, expired or rejected.
+	Status    string          `json:"status"`
+	Filled    decimal.Decimal `json:"filled"`
+	Average   decimal.Decimal `json:"average"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// BalanceUpdate is a balance change pushed by an exchange user-data stream.
+type BalanceUpdate struct {
+	Exchange  string             `json:"exchange"`
+	Total     map[string]float64 `json:"total"`
+	Free      map[string]float64 `json:"free"`
+	Used      map[string]float64 `json:"used"`
+	Timestamp time.Time          `json:"timestamp"`
+}
+
+// OrderUpdateWaiter wakes order pollers as soon as a streamed update for
+// their order arrives.
+type OrderUpdateWaiter interface {
+	// Streaming reports whether the exchange's user-data stream is connected.
+	Streaming(exchange string) bool
+	// WaitForOrder returns when an update for the order arrives or the
+	// timeout passes, or with the context's error once it is done.
+	WaitForOrder(ctx context.Context, exchange, orderID string, timeout time.Duration) error
+}
+
+// waitOrderUpdate waits for the next check of a resting order: until a
+// streamed update or the poll interval while the stream is connected, and for
+// the poll interval otherwise.
+func waitOrderUpdate(ctx context.Context, updates OrderUpdateWaiter, sleep func(context.Context, time.Duration) error, exchange, orderID string, poll time.Duration) error {
+	if updates == nil || !updates.Streaming(exchange) {
+		return sleep(ctx, poll)
+	}
+	return updates.WaitForOrder(ctx, exchange, orderID, poll)
+}
+
+// UserDataStreamConfig configures the user-data stream client.
+type UserDataStreamConfig struct {
+	// ServiceURL is the CCXT service base URL.
+	ServiceURL string
+	// APIKey authenticates against the CCXT service's admin endpoints.
+	APIKey string
+	// Exchanges are the exchanges to stream.
+	Exchanges []string
+	// ReconnectDelay is the first delay before reconnecting; it doubles up to
+	// MaxReconnectDelay, which is also how often unsupported exchanges are
+	// retried.
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+}
+
+// UserDataStreamStatus is one exchange's stream state.
+type UserDataStreamStatus struct {
+	Exchange  string `json:"exchange"`
+	Connected bool   `json:"connected"`
+	// Unsupported is set when the exchange has no user-data stream; its
+	// orders are polled.
+	Unsupported bool           `json:"unsupported"`
+	LastEventAt *time.Time     `json:"last_event_at,omitempty"`
+	LastError   string         `json:"last_error,omitempty"`
+	Balance     *BalanceUpdate `json:"balance,omitempty"`
+}
+
+// UserDataStream subscribes to exchange order and balance updates through the
+// CCXT service and pushes them to registered handlers and waiting order
+// pollers. Exchanges without a stream, or whose stream is down, are left to
+// the pollers' regular interval.
+type UserDataStream struct {
+	config UserDataStreamConfig
+	client *http.Client
+	logger *slog.Logger
+
+	mu              sync.Mutex
+	status          map[string]*UserDataStreamStatus
+	waiters         map[string][]chan struct{}
+	orderHandlers   []func(ctx context.Context, update OrderUpdate)
+	balanceHandlers []func(ctx context.Context, update BalanceUpdate)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewUserDataStream creates the user-data stream client.
+//
+// Parameters:
+//
+//	config: Service URL, credentials and exchanges to stream.
+//
+// Returns:
+//
+//	*UserDataStream: Initialized client; call Start to connect.
+func NewUserDataStream(config UserDataStreamConfig) *UserDataStream {
+	if config.ReconnectDelay <= 0 {
+		config.ReconnectDelay = 5 * time.Second
+	}
+	if config.MaxReconnectDelay < config.ReconnectDelay {
+		config.MaxReconnectDelay = 2 * time.Minute
+	}
+	status := make(map[string]*UserDataStreamStatus, len(config.Exchanges))
+	for _, exchange := range config.Exchanges {
+		status[exchange] = &UserDataStreamStatus{Exchange: exchange}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &UserDataStream{
+		config: config,
+		// Streams stay open indefinitely, so only the dial is bounded
+		client:  &http.Client{Transport: &http.Transport{ResponseHeaderTimeout: 30 * time.Second}},
+		logger:  telemetry.Logger(),
+		status:  status,
+		waiters: make(map[string][]chan struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// OnOrderUpdate registers a handler for streamed order updates.
+func (s *UserDataStream) OnOrderUpdate(handler func(ctx context.Context, update OrderUpdate)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.orderHandlers = append(s.orderHandlers, handler)
+}
+
+// OnBalanceUpdate registers a handler for streamed balance updates.
+func (s *UserDataStream) OnBalanceUpdate(handler func(ctx context.Context, update BalanceUpdate)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.balanceHandlers = append(s.balanceHandlers, handler)
+}
+
+// Start connects every configured exchange's stream in the background.
+func (s *UserDataStream) Start() {
+	for _, exchange := range s.config.Exchanges {
+		s.wg.Add(1)
+		go s.run(exchange)
+	}
+	s.logger.Info("User-data streams started", "exchanges", strings.Join(s.config.Exchanges, ","))
+}
+
+// Stop closes every stream and waits for them to finish.
+func (s *UserDataStream) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// Streaming reports whether the exchange's stream is connected.
+func (s *UserDataStream) Streaming(exchange string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status, ok := s.status[exchange]
+	return ok && status.Connected
+}
+
+// WaitForOrder returns when an update for the order arrives or the timeout
+// passes.
+func (s *UserDataStream) WaitForOrder(ctx context.Context, exchange, orderID string, timeout time.Duration) error {
+	key := exchange + "/" + orderID
+	wake := make(chan struct{}, 1)
+	s.mu.Lock()
+	s.waiters[key] = append(s.waiters[key], wake)
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		waiters := s.waiters[key]
+		for i, waiter := range waiters {
+			if waiter == wake {
+				waiters = append(waiters[:i], waiters[i+1:]...)
+				break
+			}
+		}
+		if len(waiters) == 0 {
+			delete(s.waiters, key)
+		} else {
+			s.waiters[key] = waiters
+		}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-wake:
+	case <-timer.C:
+	}
+	return nil
+}
+
+// Status returns each exchange's stream state.
+func (s *UserDataStream) Status() []UserDataStreamStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statuses := make([]UserDataStreamStatus, 0, len(s.status))
+	for _, status := range s.status {
+		statuses = append(statuses, *status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Exchange < statuses[j].Exchange })
+	return statuses
+}
+
+// run keeps one exchange's stream connected, backing off between attempts.
+func (s *UserDataStream) run(exchange string) {
+	defer s.wg.Done()
+	delay := s.config.ReconnectDelay
+	for {
+		connected, err := s.connect(exchange)
+		if s.ctx.Err() != nil {
+			return
+		}
+		if connected {
+			delay = s.config.ReconnectDelay
+		}
+		if err != nil {
+			s.logger.Warn("User-data stream disconnected, polling until it reconnects", "exchange", exchange, "error", err)
+		}
+		wait := delay
+		if s.isUnsupported(exchange) {
+			wait = s.config.MaxReconnectDelay
+		}
+		if sleepContext(s.ctx, wait) != nil {
+			return
+		}
+		delay = min(delay*2, s.config.MaxReconnectDelay)
+	}
+}
+
+// connect opens the stream and consumes it until it closes. It reports
+// whether the stream was established.
+func (s *UserDataStream) connect(exchange string) (bool, error) {
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodGet, fmt.Sprintf("%s/api/stream/%s", s.config.ServiceURL, exchange), nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if s.config.APIKey != "" {
+		req.Header.Set("X-API-Key", s.config.APIKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		s.setDisconnected(exchange, err, false)
+		return false, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotImplemented {
+		err := fmt.Errorf("exchange has no user-data stream")
+		if s.setDisconnected(exchange, err, true) {
+			s.logger.Info("User-data stream unsupported, polling for fills", "exchange", exchange)
+		}
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("stream failed with status: %d", resp.StatusCode)
+		s.setDisconnected(exchange, err, false)
+		return false, err
+	}
+
+	s.mu.Lock()
+	s.status[exchange].Connected = true
+	s.status[exchange].Unsupported = false
+	s.status[exchange].LastError = ""
+	s.mu.Unlock()
+	s.logger.Info("User-data stream connected", "exchange", exchange)
+
+	err = s.consume(exchange, resp.Body)
+	if err == nil {
+		err = fmt.Errorf("stream closed")
+	}
+	s.setDisconnected(exchange, err, false)
+	return true, err
+}
+
+// consume reads server-sent events until the stream ends.
+func (s *UserDataStream) consume(exchange string, body io.Reader) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	event := ""
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if data.Len() > 0 {
+				if err := s.dispatch(exchange, event, []byte(data.String())); err != nil {
+					return err
+				}
+			}
+			event = ""
+			data.Reset()
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	return scanner.Err()
+}
+
+// dispatch delivers one event. A stream "error" event ends the stream.
+func (s *UserDataStream) dispatch(exchange, event string, data []byte) error {
+	switch event {
+	case "order":
+		var payload struct {
+			Order struct {
+				ID        string   `json:"id"`
+				Symbol    string   `json:"symbol"`
+				Side      string   `json:"side"`
+				Status    string   `json:"status"`
+				Filled    *float64 `json:"filled"`
+				Average   *float64 `json:"average"`
+				Timestamp int64    `json:"timestamp"`
+			} `json:"order"`
+		}
+		if err := json.Unmarshal(data, &payload); err != nil {
+			s.logger.Warn("Ignoring malformed order update", "exchange", exchange, "error", err)
+			return nil
+		}
+		update := OrderUpdate{
+			Exchange:  exchange,
+			OrderID:   payload.Order.ID,
+			Symbol:    payload.Order.Symbol,
+			Side:      payload.Order.Side,
+			Status:    payload.Order.Status,
+			Timestamp: time.Now().UTC(),
+		}
+		if payload.Order.Filled != nil {
+			update.Filled = decimal.NewFromFloat(*payload.Order.Filled)
+		}
+		if payload.Order.Average != nil {
+			update.Average = decimal.NewFromFloat(*payload.Order.Average)
+		}
+		if payload.Order.Timestamp > 0 {
+			update.Timestamp = time.UnixMilli(payload.Order.Timestamp).UTC()
+		}
+		s.deliverOrder(update)
+	case "balance":
+		var update BalanceUpdate
+		if err := json.Unmarshal(data, &update); err != nil {
+			s.logger.Warn("Ignoring malformed balance update", "exchange", exchange, "error", err)
+			return nil
+		}
+		update.Exchange = exchange
+		update.Timestamp = time.Now().UTC()
+		s.deliverBalance(update)
+	case "error":
+		var payload struct {
+			Error string `json:"error"`
+		}
+		_ = json.Unmarshal(data, &payload)
+		return fmt.Errorf("stream error: %s", payload.Error)
+	}
+	return nil
+}
+
+func (s *UserDataStream) deliverOrder(update OrderUpdate) {
+	s.mu.Lock()
+	now := time.Now().UTC()
+	s.status[update.Exchange].LastEventAt = &now
+	for _, wake := range s.waiters[update.Exchange+"/"+update.OrderID] {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+	handlers := append([]func(context.Context, OrderUpdate){}, s.orderHandlers...)
+	s.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(s.ctx, update)
+	}
+}
+
+func (s *UserDataStream) deliverBalance(update BalanceUpdate) {
+	s.mu.Lock()
+	now := time.Now().UTC()
+	s.status[update.Exchange].LastEventAt = &now
+	s.status[update.Exchange].Balance = &update
+	handlers := append([]func(context.Context, BalanceUpdate){}, s.balanceHandlers...)
+	s.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(s.ctx, update)
+	}
+}
+
+// setDisconnected records a failed or closed stream and reports whether the
+// exchange just became unsupported.
+func (s *UserDataStream) setDisconnected(exchange string, err error, unsupported bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := s.status[exchange]
+	changed := unsupported && !status.Unsupported
+	status.Connected = false
+	status.Unsupported = unsupported
+	if err != nil {
+		status.LastError = err.Error()
+	}
+	return changed
+}
+
+func (s *UserDataStream) isUnsupported(exchange string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status[exchange].Unsupported
+}
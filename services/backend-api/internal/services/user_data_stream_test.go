@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserDataStream_DeliversOrderAndBalanceUpdates(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/stream/binance", r.URL.Path)
+		assert.Equal(t, "secret", r.Header.Get("X-API-Key"))
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, "event: order\ndata: {\"exchange\":\"binance\",\"order\":{\"id\":\"o-1\",\"symbol\":\"BTC/USDT\",\"side\":\"buy\",\"status\":\"closed\",\"filled\":0.5,\"average\":50100}}\n\n")
+		_, _ = fmt.Fprint(w, "event: balance\ndata: {\"exchange\":\"binance\",\"total\":{\"USDT\":900},\"free\":{\"USDT\":800},\"used\":{\"USDT\":100}}\n\n")
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	stream := NewUserDataStream(UserDataStreamConfig{ServiceURL: server.URL, APIKey: "secret", Exchanges: []string{"binance"}})
+	var mu sync.Mutex
+	var orders []OrderUpdate
+	stream.OnOrderUpdate(func(ctx context.Context, update OrderUpdate) {
+		mu.Lock()
+		defer mu.Unlock()
+		orders = append(orders, update)
+	})
+	stream.Start()
+	defer stream.Stop()
+
+	require.Eventually(t, func() bool {
+		status := stream.Status()
+		return len(status) == 1 && status[0].Balance != nil
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.True(t, stream.Streaming("binance"))
+	assert.Equal(t, 900.0, stream.Status()[0].Balance.Total["USDT"])
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, orders, 1)
+	assert.Equal(t, "o-1", orders[0].OrderID)
+	assert.Equal(t, "closed", orders[0].Status)
+	assert.Equal(t, "0.5", orders[0].Filled.String())
+	assert.Equal(t, "50100", orders[0].Average.String())
+}
+
+func TestUserDataStream_UnsupportedExchangeFallsBackToPolling(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotImplemented)
+		_, _ = fmt.Fprint(w, `{"error":"Exchange has no authenticated user-data stream","code":"stream_unsupported"}`)
+	}))
+	defer server.Close()
+
+	stream := NewUserDataStream(UserDataStreamConfig{ServiceURL: server.URL, Exchanges: []string{"kraken"}})
+	stream.Start()
+	defer stream.Stop()
+
+	require.Eventually(t, func() bool { return stream.Status()[0].Unsupported }, 2*time.Second, 10*time.Millisecond)
+	assert.False(t, stream.Streaming("kraken"))
+
+	slept := time.Duration(0)
+	err := waitOrderUpdate(t.Context(), stream, func(ctx context.Context, d time.Duration) error {
+		slept = d
+		return nil
+	}, "kraken", "o-1", 3*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, 3*time.Second, slept, "an unstreamed exchange waits out the poll interval")
+}
+
+func TestUserDataStream_WaitForOrderWakesOnUpdate(t *testing.T) {
+	stream := NewUserDataStream(UserDataStreamConfig{Exchanges: []string{"binance"}})
+	done := make(chan error, 1)
+	go func() {
+		done <- stream.WaitForOrder(t.Context(), "binance", "o-1", time.Minute)
+	}()
+
+	require.Eventually(t, func() bool {
+		stream.mu.Lock()
+		defer stream.mu.Unlock()
+		return len(stream.waiters["binance/o-1"]) == 1
+	}, time.Second, time.Millisecond)
+	stream.deliverOrder(OrderUpdate{Exchange: "binance", OrderID: "o-1", Status: "closed"})
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("waiter was not woken by the update")
+	}
+	assert.Empty(t, stream.waiters)
+}
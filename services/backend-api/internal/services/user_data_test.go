@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubOperatorRegistry struct {
+	operators []string
+	unbound   []string
+}
+
+func (s *stubOperatorRegistry) ListOperators(context.Context) ([]string, error) {
+	return s.operators, nil
+}
+
+func (s *stubOperatorRegistry) UnbindOperator(_ context.Context, operator string) error {
+	s.unbound = append(s.unbound, operator)
+	return nil
+}
+
+// expectUserDataErasure expects an erasure of user u-1 (chat 555) where only
+// the users, notification_dead_letters, embedded_documents and paper_trades
+// tables exist.
+func expectUserDataErasure(mockPool pgxmock.PgxPoolIface, dryRun bool) {
+	mockPool.ExpectQuery("SELECT email, telegram_chat_id").WithArgs("u-1").
+		WillReturnRows(pgxmock.NewRows([]string{"email", "telegram_chat_id"}).AddRow("ann@example.com", "555"))
+	mockPool.ExpectQuery("FROM information_schema.tables").WithArgs(pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"table_name"}).AddRow("users").AddRow("notification_dead_letters").AddRow("embedded_documents").AddRow("paper_trades"))
+	mockPool.ExpectBegin()
+
+	mockPool.ExpectQuery("SELECT COUNT.*FROM users WHERE id = \\$1 AND telegram_chat_id").WithArgs("u-1").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(int64(1)))
+	if !dryRun {
+		mockPool.ExpectExec("UPDATE users SET telegram_chat_id = NULL").WithArgs("u-1").
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	}
+	mockPool.ExpectQuery("FROM notification_dead_letters").WithArgs("u-1", "555").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(int64(3)))
+	if !dryRun {
+		mockPool.ExpectExec("DELETE FROM notification_dead_letters").WithArgs("u-1", "555").
+			WillReturnResult(pgxmock.NewResult("DELETE", 3))
+	}
+	// Notes are found by their owner, not by the chat ID appearing in the text
+	mockPool.ExpectQuery("SELECT source_id FROM embedded_documents WHERE kind = 'note' AND user_id = \\$1").WithArgs("u-1").
+		WillReturnRows(pgxmock.NewRows([]string{"source_id"}).AddRow("note-1").AddRow("note-2"))
+	if !dryRun {
+		mockPool.ExpectExec("DELETE FROM embedded_documents WHERE kind = 'note' AND user_id = \\$1").WithArgs("u-1").
+			WillReturnResult(pgxmock.NewResult("DELETE", 2))
+	}
+	mockPool.ExpectQuery("FROM users WHERE id = \\$1 AND email <>").WithArgs("u-1", anonymizedEmail("u-1")).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(int64(1)))
+	if !dryRun {
+		mockPool.ExpectExec("UPDATE users SET email").WithArgs("u-1", anonymizedEmail("u-1")).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	}
+	mockPool.ExpectQuery("FROM paper_trades").WithArgs("u-1").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(int64(12)))
+
+	if dryRun {
+		mockPool.ExpectRollback()
+	} else {
+		mockPool.ExpectCommit()
+	}
+}
+
+func TestUserDataService_Erase(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	require.NoError(t, client.Set(t.Context(), "user:telegram:555", "{}", 0).Err())
+	require.NoError(t, client.Set(t.Context(), deliveryBounceKey("555"), "2", 0).Err())
+	require.NoError(t, client.Set(t.Context(), "telegram:user:555:notifications_enabled", "true", 0).Err())
+	require.NoError(t, client.Set(t.Context(), "user_preferences:555:arbitrage", "true", 0).Err())
+	operators := &stubOperatorRegistry{operators: []string{"op-1", "telegram:555"}}
+
+	svc := NewUserDataService(database.NewMockDBPool(mockPool), client)
+	svc.SetOperatorRegistry(operators)
+
+	expectUserDataErasure(mockPool, true)
+	report, err := svc.Erase(t.Context(), "u-1", true)
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Nil(t, report.ErasedAt)
+	assert.Equal(t, int64(1+3+2+1+4+1), report.Removed)
+	assert.Contains(t, report.Actions, UserDataAction{
+		Category: UserDataOperatorNotes, Target: "embedded_documents (notes)", Action: UserDataActionDelete,
+		Count: 2, IDs: []string{"note-1", "note-2"},
+	})
+	assert.Equal(t, []UserDataAction{{Target: "paper_trades", Action: UserDataActionAnonymize, Count: 12}}, report.Preserved)
+	assert.True(t, mr.Exists("user:telegram:555"), "a dry run changes nothing")
+	assert.Empty(t, operators.unbound)
+
+	expectUserDataErasure(mockPool, false)
+	report, err = svc.Erase(t.Context(), "u-1", false)
+	require.NoError(t, err)
+	assert.False(t, report.DryRun)
+	assert.NotNil(t, report.ErasedAt)
+	categories := map[string]int64{}
+	for _, action := range report.Actions {
+		categories[action.Category] += action.Count
+	}
+	assert.Equal(t, map[string]int64{
+		UserDataTelegramBinding:     4,
+		UserDataNotificationLogs:    4,
+		UserDataOperatorNotes:       2,
+		UserDataPersonalIdentifiers: 2,
+	}, categories)
+	assert.False(t, mr.Exists("user:telegram:555"))
+	assert.False(t, mr.Exists(deliveryBounceKey("555")))
+	assert.False(t, mr.Exists("telegram:user:555:notifications_enabled"))
+	assert.False(t, mr.Exists("user_preferences:555:arbitrage"))
+	assert.Equal(t, []string{"telegram:555"}, operators.unbound)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestUserDataService_EraseUnknownUser(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+	svc := NewUserDataService(database.NewMockDBPool(mockPool), nil)
+
+	mockPool.ExpectQuery("SELECT email, telegram_chat_id").WithArgs("missing").
+		WillReturnRows(pgxmock.NewRows([]string{"email", "telegram_chat_id"}))
+	_, err = svc.Erase(t.Context(), "missing", true)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
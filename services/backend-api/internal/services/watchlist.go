@@ -0,0 +1,494 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	watchlistChatsKey      = "watchlist:chats"
+	watchlistUniverseKey   = "watchlist:universe"
+	watchlistExclusionsKey = "watchlist:exclusions"
+
+	defaultUniverseSize            = 50
+	defaultUniverseRefreshInterval = 24 * time.Hour
+	defaultMaxWatchlistSymbols     = 100
+)
+
+var (
+	ErrWatchlistInvalidSymbol = errors.New("invalid symbol")
+	ErrWatchlistFull          = errors.New("watchlist is full")
+)
+
+// SymbolUniverse decides which symbols the signal processor and the scalping
+// engine iterate.
+type SymbolUniverse interface {
+	// ActiveSymbols returns every symbol that is on the generated universe or
+	// any chat watchlist, minus the global exclusions.
+	ActiveSymbols(ctx context.Context) ([]string, error)
+	// ChatSymbols returns the symbols one chat trades: its watchlist, or the
+	// generated universe when the watchlist is empty, minus its exclusions.
+	ChatSymbols(ctx context.Context, chatID string) ([]string, error)
+}
+
+// Watchlist is the symbol selection of one chat.
+type Watchlist struct {
+	ChatID string `json:"chat_id"`
+	// Symbols replaces the generated universe for this chat when not empty.
+	Symbols []string `json:"symbols"`
+	// Excluded symbols are never traded for this chat.
+	Excluded  []string  `json:"excluded"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UniverseSnapshot is the generated "top N by volume" universe.
+type UniverseSnapshot struct {
+	Symbols     []string  `json:"symbols"`
+	Size        int       `json:"size"`
+	GeneratedAt time.Time `json:"generated_at"`
+	// Excluded lists the global exclusions applied when the universe was generated.
+	Excluded []string `json:"excluded"`
+}
+
+// WatchlistConfig configures universe generation.
+type WatchlistConfig struct {
+	// UniverseSize is the number of symbols in the generated universe.
+	UniverseSize int
+	// RefreshInterval is how often the universe is regenerated.
+	RefreshInterval time.Duration
+	// MaxSymbols caps the size of a chat watchlist.
+	MaxSymbols int
+}
+
+// WatchlistService stores per-chat watchlists and the global exclusion list in
+// Redis, and regenerates the symbol universe from recent market data volume.
+type WatchlistService struct {
+	db     DBPool
+	redis  *redis.Client
+	config WatchlistConfig
+	logger *slog.Logger
+	now    func() time.Time
+	// mu serializes watchlist read-modify-write cycles.
+	mu     sync.Mutex
+	runMu  sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Ensure WatchlistService implements SymbolUniverse.
+var _ SymbolUniverse = (*WatchlistService)(nil)
+
+// NewWatchlistService creates a watchlist service.
+//
+// Parameters:
+//
+//	db: Database connection used to rank symbols by volume.
+//	client: Redis client used to persist watchlists and the universe.
+//	config: Universe configuration; zero values use defaults.
+//
+// Returns:
+//
+//	*WatchlistService: Initialized service (refresh loop not started).
+func NewWatchlistService(db DBPool, client *redis.Client, config WatchlistConfig) *WatchlistService {
+	if config.UniverseSize <= 0 {
+		config.UniverseSize = defaultUniverseSize
+	}
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = defaultUniverseRefreshInterval
+	}
+	if config.MaxSymbols <= 0 {
+		config.MaxSymbols = defaultMaxWatchlistSymbols
+	}
+	return &WatchlistService{
+		db:     db,
+		redis:  client,
+		config: config,
+		logger: telemetry.Logger(),
+		now:    time.Now,
+	}
+}
+
+// Start regenerates the universe now if it is missing or stale and then once
+// per refresh interval until Stop is called.
+func (s *WatchlistService) Start(ctx context.Context) error {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+	if s.cancel != nil {
+		return fmt.Errorf("watchlist service already running")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if universe, err := s.Universe(ctx); err != nil || s.now().Sub(universe.GeneratedAt) >= s.config.RefreshInterval {
+			s.refresh(ctx)
+		}
+		ticker := time.NewTicker(s.config.RefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.refresh(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the refresh loop.
+func (s *WatchlistService) Stop() {
+	s.runMu.Lock()
+	cancel := s.cancel
+	s.cancel = nil
+	s.runMu.Unlock()
+	if cancel != nil {
+		cancel()
+		s.wg.Wait()
+	}
+}
+
+func (s *WatchlistService) refresh(ctx context.Context) {
+	universe, err := s.RefreshUniverse(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to refresh symbol universe", "error", err)
+		return
+	}
+	s.logger.Info("Symbol universe refreshed", "symbols", len(universe.Symbols))
+}
+
+// Get returns a chat's watchlist; a chat without one gets an empty watchlist.
+func (s *WatchlistService) Get(ctx context.Context, chatID string) (*Watchlist, error) {
+	raw, err := s.redis.HGet(ctx, watchlistChatsKey, chatID).Result()
+	if errors.Is(err, redis.Nil) {
+		return &Watchlist{ChatID: chatID, Symbols: []string{}, Excluded: []string{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load watchlist: %w", err)
+	}
+	var watchlist Watchlist
+	if err := json.Unmarshal([]byte(raw), &watchlist); err != nil {
+		return nil, fmt.Errorf("failed to decode watchlist: %w", err)
+	}
+	return &watchlist, nil
+}
+
+// AddSymbols adds symbols to a chat's watchlist and removes them from its exclusions.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	chatID: Telegram chat ID.
+//	symbols: Symbols such as "BTC/USDT".
+//
+// Returns:
+//
+//	*Watchlist: The updated watchlist.
+//	error: ErrWatchlistInvalidSymbol, ErrWatchlistFull or a persistence error.
+func (s *WatchlistService) AddSymbols(ctx context.Context, chatID string, symbols []string) (*Watchlist, error) {
+	return s.update(ctx, chatID, symbols, func(w *Watchlist, symbols []string) error {
+		w.Symbols = unionSymbols(w.Symbols, symbols)
+		w.Excluded = subtractSymbols(w.Excluded, symbols)
+		if len(w.Symbols) > s.config.MaxSymbols {
+			return fmt.Errorf("%w: at most %d symbols", ErrWatchlistFull, s.config.MaxSymbols)
+		}
+		return nil
+	})
+}
+
+// RemoveSymbols removes symbols from a chat's watchlist.
+func (s *WatchlistService) RemoveSymbols(ctx context.Context, chatID string, symbols []string) (*Watchlist, error) {
+	return s.update(ctx, chatID, symbols, func(w *Watchlist, symbols []string) error {
+		w.Symbols = subtractSymbols(w.Symbols, symbols)
+		return nil
+	})
+}
+
+// ExcludeSymbols excludes symbols for a chat, also removing them from its watchlist.
+func (s *WatchlistService) ExcludeSymbols(ctx context.Context, chatID string, symbols []string) (*Watchlist, error) {
+	return s.update(ctx, chatID, symbols, func(w *Watchlist, symbols []string) error {
+		w.Symbols = subtractSymbols(w.Symbols, symbols)
+		w.Excluded = unionSymbols(w.Excluded, symbols)
+		return nil
+	})
+}
+
+// IncludeSymbols lifts a chat's exclusion of symbols.
+func (s *WatchlistService) IncludeSymbols(ctx context.Context, chatID string, symbols []string) (*Watchlist, error) {
+	return s.update(ctx, chatID, symbols, func(w *Watchlist, symbols []string) error {
+		w.Excluded = subtractSymbols(w.Excluded, symbols)
+		return nil
+	})
+}
+
+// Clear deletes a chat's watchlist so it follows the generated universe again.
+func (s *WatchlistService) Clear(ctx context.Context, chatID string) error {
+	if err := s.redis.HDel(ctx, watchlistChatsKey, chatID).Err(); err != nil {
+		return fmt.Errorf("failed to clear watchlist: %w", err)
+	}
+	return nil
+}
+
+func (s *WatchlistService) update(ctx context.Context, chatID string, symbols []string, apply func(*Watchlist, []string) error) (*Watchlist, error) {
+	normalized, err := normalizeWatchlistSymbols(symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	watchlist, err := s.Get(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	if err := apply(watchlist, normalized); err != nil {
+		return nil, err
+	}
+	watchlist.UpdatedAt = s.now().UTC()
+
+	raw, err := json.Marshal(watchlist)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.redis.HSet(ctx, watchlistChatsKey, chatID, raw).Err(); err != nil {
+		return nil, fmt.Errorf("failed to save watchlist: %w", err)
+	}
+	return watchlist, nil
+}
+
+// Exclusions returns the global exclusion list.
+func (s *WatchlistService) Exclusions(ctx context.Context) ([]string, error) {
+	symbols, err := s.redis.SMembers(ctx, watchlistExclusionsKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load exclusions: %w", err)
+	}
+	sort.Strings(symbols)
+	return symbols, nil
+}
+
+// SetExclusions replaces the global exclusion list. Excluded symbols are
+// removed from the universe and from every chat's active symbols.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	symbols: The new exclusion list; empty clears it.
+//
+// Returns:
+//
+//	[]string: The stored exclusions.
+//	error: ErrWatchlistInvalidSymbol or a persistence error.
+func (s *WatchlistService) SetExclusions(ctx context.Context, symbols []string) ([]string, error) {
+	normalized, err := normalizeWatchlistSymbols(symbols)
+	if err != nil {
+		return nil, err
+	}
+	pipe := s.redis.TxPipeline()
+	pipe.Del(ctx, watchlistExclusionsKey)
+	if len(normalized) > 0 {
+		members := make([]interface{}, len(normalized))
+		for i, symbol := range normalized {
+			members[i] = symbol
+		}
+		pipe.SAdd(ctx, watchlistExclusionsKey, members...)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to save exclusions: %w", err)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// Universe returns the last generated universe, or an empty one if it was never generated.
+func (s *WatchlistService) Universe(ctx context.Context) (*UniverseSnapshot, error) {
+	raw, err := s.redis.Get(ctx, watchlistUniverseKey).Result()
+	if errors.Is(err, redis.Nil) {
+		return &UniverseSnapshot{Symbols: []string{}, Size: s.config.UniverseSize, Excluded: []string{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load universe: %w", err)
+	}
+	var universe UniverseSnapshot
+	if err := json.Unmarshal([]byte(raw), &universe); err != nil {
+		return nil, fmt.Errorf("failed to decode universe: %w", err)
+	}
+	return &universe, nil
+}
+
+// RefreshUniverse regenerates the universe from the symbols with the highest
+// quote volume over the last 24 hours of collected market data.
+//
+// Parameters:
+//
+//	ctx: Context.
+//
+// Returns:
+//
+//	*UniverseSnapshot: The new universe.
+//	error: Error if the query or persistence fails.
+func (s *WatchlistService) RefreshUniverse(ctx context.Context) (*UniverseSnapshot, error) {
+	exclusions, err := s.Exclusions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+
+	rows, err := s.db.Query(ctx, `
+		SELECT tp.symbol, MAX(md.last_price * md.volume_24h) AS quote_volume
+		FROM market_data md
+		JOIN trading_pairs tp ON md.trading_pair_id = tp.id
+		WHERE md.timestamp >= $1 AND md.volume_24h IS NOT NULL
+		GROUP BY tp.symbol
+		ORDER BY quote_volume DESC
+		LIMIT $2`,
+		now.Add(-24*time.Hour), s.config.UniverseSize+len(exclusions))
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank symbols by volume: %w", err)
+	}
+	defer rows.Close()
+
+	excluded := toSymbolSet(exclusions)
+	symbols := make([]string, 0, s.config.UniverseSize)
+	for rows.Next() {
+		var symbol string
+		var quoteVolume float64
+		if err := rows.Scan(&symbol, &quoteVolume); err != nil {
+			return nil, fmt.Errorf("failed to scan symbol volume: %w", err)
+		}
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if _, skip := excluded[symbol]; skip || len(symbols) >= s.config.UniverseSize {
+			continue
+		}
+		symbols = append(symbols, symbol)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read symbol volumes: %w", err)
+	}
+
+	universe := &UniverseSnapshot{
+		Symbols:     symbols,
+		Size:        s.config.UniverseSize,
+		GeneratedAt: now,
+		Excluded:    exclusions,
+	}
+	raw, err := json.Marshal(universe)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.redis.Set(ctx, watchlistUniverseKey, raw, 0).Err(); err != nil {
+		return nil, fmt.Errorf("failed to save universe: %w", err)
+	}
+	return universe, nil
+}
+
+// ActiveSymbols implements SymbolUniverse.
+func (s *WatchlistService) ActiveSymbols(ctx context.Context) ([]string, error) {
+	universe, err := s.Universe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	values, err := s.redis.HGetAll(ctx, watchlistChatsKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load watchlists: %w", err)
+	}
+	exclusions, err := s.Exclusions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	chatIDs := make([]string, 0, len(values))
+	for chatID := range values {
+		chatIDs = append(chatIDs, chatID)
+	}
+	sort.Strings(chatIDs)
+
+	symbols := universe.Symbols
+	for _, chatID := range chatIDs {
+		var watchlist Watchlist
+		if err := json.Unmarshal([]byte(values[chatID]), &watchlist); err != nil {
+			s.logger.Warn("Skipping unreadable watchlist", "chat_id", chatID, "error", err)
+			continue
+		}
+		symbols = unionSymbols(symbols, watchlist.Symbols)
+	}
+	return subtractSymbols(symbols, exclusions), nil
+}
+
+// ChatSymbols implements SymbolUniverse.
+func (s *WatchlistService) ChatSymbols(ctx context.Context, chatID string) ([]string, error) {
+	watchlist, err := s.Get(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	symbols := watchlist.Symbols
+	if len(symbols) == 0 {
+		universe, err := s.Universe(ctx)
+		if err != nil {
+			return nil, err
+		}
+		symbols = universe.Symbols
+	}
+	exclusions, err := s.Exclusions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return subtractSymbols(subtractSymbols(symbols, watchlist.Excluded), exclusions), nil
+}
+
+func normalizeWatchlistSymbols(symbols []string) ([]string, error) {
+	normalized := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		symbol = NormalizeSymbol(symbol)
+		if !isValidSymbolFormat(symbol) || strings.ContainsAny(symbol, " \t") || len(symbol) > 32 {
+			return nil, fmt.Errorf("%w: %q", ErrWatchlistInvalidSymbol, symbol)
+		}
+		normalized = append(normalized, symbol)
+	}
+	return unionSymbols(nil, normalized), nil
+}
+
+func toSymbolSet(symbols []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(symbols))
+	for _, symbol := range symbols {
+		set[symbol] = struct{}{}
+	}
+	return set
+}
+
+// unionSymbols appends the symbols of b missing from a, keeping order.
+func unionSymbols(a, b []string) []string {
+	seen := toSymbolSet(a)
+	result := append([]string{}, a...)
+	for _, symbol := range b {
+		if _, ok := seen[symbol]; !ok {
+			seen[symbol] = struct{}{}
+			result = append(result, symbol)
+		}
+	}
+	return result
+}
+
+// subtractSymbols returns the symbols of a not in b, keeping order.
+func subtractSymbols(a, b []string) []string {
+	remove := toSymbolSet(b)
+	result := make([]string, 0, len(a))
+	for _, symbol := range a {
+		if _, ok := remove[symbol]; !ok {
+			result = append(result, symbol)
+		}
+	}
+	return result
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/irfndi/neuratrade/internal/database"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWatchlistService(t *testing.T, db DBPool, config WatchlistConfig) *WatchlistService {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewWatchlistService(db, client, config)
+}
+
+func TestWatchlistService_ChatWatchlist(t *testing.T) {
+	svc := newTestWatchlistService(t, nil, WatchlistConfig{MaxSymbols: 3})
+	ctx := t.Context()
+
+	watchlist, err := svc.AddSymbols(ctx, "42", []string{" btc/usdt", "ETH/USDT", "BTC/USDT"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BTC/USDT", "ETH/USDT"}, watchlist.Symbols)
+
+	_, err = svc.AddSymbols(ctx, "42", []string{"SOL/USDT", "XRP/USDT"})
+	assert.ErrorIs(t, err, ErrWatchlistFull)
+	_, err = svc.AddSymbols(ctx, "42", []string{""})
+	assert.ErrorIs(t, err, ErrWatchlistInvalidSymbol)
+
+	watchlist, err = svc.ExcludeSymbols(ctx, "42", []string{"ETH/USDT"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BTC/USDT"}, watchlist.Symbols)
+	assert.Equal(t, []string{"ETH/USDT"}, watchlist.Excluded)
+
+	// Adding a symbol back lifts its exclusion.
+	watchlist, err = svc.AddSymbols(ctx, "42", []string{"ETH/USDT"})
+	require.NoError(t, err)
+	assert.Empty(t, watchlist.Excluded)
+
+	watchlist, err = svc.RemoveSymbols(ctx, "42", []string{"BTC/USDT"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ETH/USDT"}, watchlist.Symbols)
+
+	require.NoError(t, svc.Clear(ctx, "42"))
+	watchlist, err = svc.Get(ctx, "42")
+	require.NoError(t, err)
+	assert.Empty(t, watchlist.Symbols)
+}
+
+func TestWatchlistService_RefreshUniverse(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+
+	svc := newTestWatchlistService(t, database.NewMockDBPool(mockPool), WatchlistConfig{UniverseSize: 2})
+	now := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := t.Context()
+
+	_, err = svc.SetExclusions(ctx, []string{"luna/usdt"})
+	require.NoError(t, err)
+
+	// One extra row is requested per exclusion so the universe stays full.
+	mockPool.ExpectQuery("FROM market_data md").
+		WithArgs(now.Add(-24*time.Hour), 3).
+		WillReturnRows(pgxmock.NewRows([]string{"symbol", "quote_volume"}).
+			AddRow("BTC/USDT", 9e9).
+			AddRow("LUNA/USDT", 5e9).
+			AddRow("ETH/USDT", 4e9))
+
+	universe, err := svc.RefreshUniverse(ctx)
+	require.NoError(t, err)
+	require.NoError(t, mockPool.ExpectationsWereMet())
+	assert.Equal(t, []string{"BTC/USDT", "ETH/USDT"}, universe.Symbols)
+	assert.Equal(t, []string{"LUNA/USDT"}, universe.Excluded)
+	assert.Equal(t, now, universe.GeneratedAt)
+
+	stored, err := svc.Universe(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, universe.Symbols, stored.Symbols)
+}
+
+func TestWatchlistService_ActiveAndChatSymbols(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mockPool.Close()
+
+	svc := newTestWatchlistService(t, database.NewMockDBPool(mockPool), WatchlistConfig{UniverseSize: 2})
+	ctx := t.Context()
+
+	mockPool.ExpectQuery("FROM market_data md").
+		WithArgs(pgxmock.AnyArg(), 2).
+		WillReturnRows(pgxmock.NewRows([]string{"symbol", "quote_volume"}).
+			AddRow("BTC/USDT", 9e9).
+			AddRow("ETH/USDT", 4e9))
+	_, err = svc.RefreshUniverse(ctx)
+	require.NoError(t, err)
+
+	_, err = svc.AddSymbols(ctx, "1", []string{"SOL/USDT", "DOGE/USDT"})
+	require.NoError(t, err)
+	_, err = svc.ExcludeSymbols(ctx, "2", []string{"ETH/USDT"})
+	require.NoError(t, err)
+	_, err = svc.SetExclusions(ctx, []string{"DOGE/USDT"})
+	require.NoError(t, err)
+
+	active, err := svc.ActiveSymbols(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BTC/USDT", "ETH/USDT", "SOL/USDT"}, active)
+
+	// A chat with a watchlist trades only its watchlist.
+	symbols, err := svc.ChatSymbols(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"SOL/USDT"}, symbols)
+
+	// A chat with only exclusions follows the generated universe.
+	symbols, err = svc.ChatSymbols(ctx, "2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BTC/USDT"}, symbols)
+
+	symbols, err = svc.ChatSymbols(ctx, "3")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BTC/USDT", "ETH/USDT"}, symbols)
+}
@@ -0,0 +1,428 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/irfndi/neuratrade/internal/telemetry"
+	"github.com/redis/go-redis/v9"
+)
+
+// WebhookEventType names an event that can be delivered to outbound webhooks.
+type WebhookEventType string
+
+const (
+	WebhookEventTradeExecuted   WebhookEventType = "trade.executed"
+	WebhookEventRisk            WebhookEventType = "risk.event"
+	WebhookEventModeChanged     WebhookEventType = "mode.changed"
+	WebhookEventQuestCreated    WebhookEventType = "quest.created"
+	WebhookEventQuestMilestone  WebhookEventType = "quest.milestone"
+	WebhookEventQuestCompleted  WebhookEventType = "quest.completed"
+	WebhookEventQuestFailed     WebhookEventType = "quest.failed"
+	WebhookEventListingDetected WebhookEventType = "listing.detected"
+	// WebhookEventTest is only sent on request to check an endpoint.
+	WebhookEventTest WebhookEventType = "webhook.test"
+)
+
+// WebhookEventTypes lists the events endpoints can subscribe to.
+var WebhookEventTypes = []WebhookEventType{
+	WebhookEventTradeExecuted,
+	WebhookEventRisk,
+	WebhookEventModeChanged,
+	WebhookEventQuestCreated,
+	WebhookEventQuestMilestone,
+	WebhookEventQuestCompleted,
+	WebhookEventQuestFailed,
+	WebhookEventListingDetected,
+}
+
+const (
+	webhookEndpointsKey = "webhooks:endpoints"
+
+	// WebhookSignatureHeader carries "t=<unix seconds>,v1=<hex HMAC-SHA256>" where the
+	// HMAC is computed over "<t>.<body>" with the endpoint secret.
+	WebhookSignatureHeader = "X-NeuraTrade-Signature"
+	WebhookEventHeader     = "X-NeuraTrade-Event"
+	WebhookDeliveryHeader  = "X-NeuraTrade-Delivery"
+
+	webhookUserAgent = "NeuraTrade-Webhooks/1.0"
+)
+
+var (
+	ErrWebhookNotFound   = errors.New("webhook not found")
+	ErrWebhookInvalidURL = errors.New("webhook url must be an absolute http or https url")
+	ErrWebhookBadEvent   = errors.New("unknown webhook event")
+	ErrWebhookBadKind    = errors.New("webhook kind must be generic, github or linear")
+	ErrWebhookNoToken    = errors.New("github and linear webhooks need an API token as their secret")
+)
+
+// EventEmitter publishes domain events to external integrations.
+type EventEmitter interface {
+	Emit(ctx context.Context, event WebhookEventType, data interface{})
+}
+
+// WebhookEndpoint is a configured outbound webhook. An empty Events list
+// subscribes generic endpoints to every event and issue tracker endpoints to
+// the quest lifecycle events.
+type WebhookEndpoint struct {
+	ID string `json:"id"`
+	// Kind is generic (signed JSON payloads), github or linear (issue
+	// comments); empty means generic.
+	Kind WebhookKind `json:"kind,omitempty"`
+	// URL is the receiver for generic endpoints and the issue to comment on
+	// for github and linear endpoints.
+	URL string `json:"url"`
+	// Secret signs generic payloads and is the API token of github and
+	// linear endpoints.
+	Secret      string             `json:"secret,omitempty"`
+	Events      []WebhookEventType `json:"events,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Enabled     bool               `json:"enabled"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+// Accepts reports whether the endpoint subscribes to an event.
+func (e *WebhookEndpoint) Accepts(event WebhookEventType) bool {
+	if !e.Enabled {
+		return false
+	}
+	if event == WebhookEventTest || (len(e.Events) == 0 && !e.Kind.tracker()) {
+		return true
+	}
+	events := e.Events
+	if len(events) == 0 {
+		events = QuestLifecycleEvents
+	}
+	for _, subscribed := range events {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookPayload is the JSON body POSTed to endpoints.
+type WebhookPayload struct {
+	ID        string           `json:"id"`
+	Event     WebhookEventType `json:"event"`
+	CreatedAt time.Time        `json:"created_at"`
+	Data      interface{}      `json:"data"`
+}
+
+// WebhookConfig configures delivery behaviour.
+type WebhookConfig struct {
+	// Timeout bounds each delivery attempt.
+	Timeout time.Duration
+	// MaxAttempts is the number of tries for retryable failures.
+	MaxAttempts int
+	// RetryBackoff is the delay before the second attempt; it doubles after each retry.
+	RetryBackoff time.Duration
+	// GitHubAPIURL is the API of github.com issues; GitHub Enterprise issues
+	// use https://<host>/api/v3. Defaults to https://api.github.com.
+	GitHubAPIURL string
+	// LinearAPIURL is the Linear GraphQL API. Defaults to https://api.linear.app/graphql.
+	LinearAPIURL string
+}
+
+// WebhookService stores outbound webhook endpoints in Redis and delivers signed
+// event payloads to them asynchronously.
+type WebhookService struct {
+	redis      *redis.Client
+	httpClient *http.Client
+	config     WebhookConfig
+	logger     *slog.Logger
+	wg         sync.WaitGroup
+	now        func() time.Time
+}
+
+// NewWebhookService creates a webhook service.
+//
+// Parameters:
+//
+//	client: Redis client used to persist endpoints.
+//	config: Delivery configuration; zero values use defaults.
+//
+// Returns:
+//
+//	*WebhookService: Initialized service.
+func NewWebhookService(client *redis.Client, config WebhookConfig) *WebhookService {
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 3
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = time.Second
+	}
+	if config.GitHubAPIURL == "" {
+		config.GitHubAPIURL = defaultGitHubAPIURL
+	}
+	if config.LinearAPIURL == "" {
+		config.LinearAPIURL = defaultLinearAPIURL
+	}
+	return &WebhookService{
+		redis:      client,
+		httpClient: &http.Client{Timeout: config.Timeout},
+		config:     config,
+		logger:     telemetry.Logger(),
+		now:        time.Now,
+	}
+}
+
+// CreateEndpoint validates and stores a new endpoint. A secret is generated
+// for generic endpoints when none is given; the returned endpoint is the only
+// place it is shown.
+//
+// Parameters:
+//
+//	ctx: Context.
+//	endpoint: Kind, URL, optional secret, event filter and description.
+//
+// Returns:
+//
+//	*WebhookEndpoint: The stored endpoint including its secret.
+//	error: Error if validation or persistence fails.
+func (s *WebhookService) CreateEndpoint(ctx context.Context, endpoint WebhookEndpoint) (*WebhookEndpoint, error) {
+	parsed, err := url.Parse(endpoint.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, ErrWebhookInvalidURL
+	}
+	for _, event := range endpoint.Events {
+		if !isWebhookEventType(event) {
+			return nil, fmt.Errorf("%w: %s", ErrWebhookBadEvent, event)
+		}
+	}
+	if endpoint.Kind == WebhookKindGeneric {
+		endpoint.Kind = ""
+	}
+	switch endpoint.Kind {
+	case "":
+	case WebhookKindGitHub, WebhookKindLinear:
+		if _, _, err := s.trackerTarget(&endpoint); err != nil {
+			return nil, err
+		}
+		if endpoint.Secret == "" {
+			return nil, ErrWebhookNoToken
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrWebhookBadKind, endpoint.Kind)
+	}
+	if endpoint.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+		}
+		endpoint.Secret = hex.EncodeToString(secret)
+	}
+
+	endpoint.ID = uuid.New().String()
+	endpoint.Enabled = true
+	endpoint.CreatedAt = s.now().UTC()
+
+	raw, err := json.Marshal(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.redis.HSet(ctx, webhookEndpointsKey, endpoint.ID, raw).Err(); err != nil {
+		return nil, fmt.Errorf("failed to save webhook: %w", err)
+	}
+	return &endpoint, nil
+}
+
+// ListEndpoints returns all endpoints ordered by creation time, with secrets removed.
+func (s *WebhookService) ListEndpoints(ctx context.Context) ([]WebhookEndpoint, error) {
+	endpoints, err := s.loadEndpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range endpoints {
+		endpoints[i].Secret = ""
+	}
+	return endpoints, nil
+}
+
+// DeleteEndpoint removes an endpoint.
+func (s *WebhookService) DeleteEndpoint(ctx context.Context, id string) error {
+	removed, err := s.redis.HDel(ctx, webhookEndpointsKey, id).Result()
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if removed == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// SendTest delivers a webhook.test event synchronously so callers can report the result.
+func (s *WebhookService) SendTest(ctx context.Context, id string) error {
+	raw, err := s.redis.HGet(ctx, webhookEndpointsKey, id).Result()
+	if errors.Is(err, redis.Nil) {
+		return ErrWebhookNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load webhook: %w", err)
+	}
+	var endpoint WebhookEndpoint
+	if err := json.Unmarshal([]byte(raw), &endpoint); err != nil {
+		return fmt.Errorf("failed to decode webhook: %w", err)
+	}
+	return s.deliver(ctx, &endpoint, s.newPayload(WebhookEventTest, map[string]string{"message": "NeuraTrade webhook test"}))
+}
+
+// Emit delivers an event to every subscribed endpoint in the background.
+// Delivery failures are logged and never returned to the caller.
+func (s *WebhookService) Emit(ctx context.Context, event WebhookEventType, data interface{}) {
+	endpoints, err := s.loadEndpoints(ctx)
+	if err != nil {
+		s.logger.Error("Failed to load webhooks", "event", event, "error", err)
+		return
+	}
+
+	payload := s.newPayload(event, data)
+	for i := range endpoints {
+		endpoint := endpoints[i]
+		if !endpoint.Accepts(event) {
+			continue
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			if err := s.deliver(context.Background(), &endpoint, payload); err != nil {
+				s.logger.Warn("Webhook delivery failed", "webhook_id", endpoint.ID, "event", event, "delivery_id", payload.ID, "error", err)
+			}
+		}()
+	}
+}
+
+// Wait blocks until in-flight deliveries finish.
+func (s *WebhookService) Wait() {
+	s.wg.Wait()
+}
+
+// SignWebhookPayload returns the signature header value for a body.
+//
+// Parameters:
+//
+//	secret: Endpoint secret.
+//	timestamp: Unix seconds included in the signature to prevent replays.
+//	body: Raw JSON body.
+//
+// Returns:
+//
+//	string: Header value in the form "t=<timestamp>,v1=<hex hmac>".
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	ts := strconv.FormatInt(timestamp, 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *WebhookService) newPayload(event WebhookEventType, data interface{}) WebhookPayload {
+	return WebhookPayload{
+		ID:        uuid.New().String(),
+		Event:     event,
+		CreatedAt: s.now().UTC(),
+		Data:      data,
+	}
+}
+
+// deliver POSTs a payload, retrying network errors, 429 and 5xx responses
+// with exponential backoff.
+func (s *WebhookService) deliver(ctx context.Context, endpoint *WebhookEndpoint, payload WebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	backoff := s.config.RetryBackoff
+	var lastErr error
+	for attempt := 1; attempt <= s.config.MaxAttempts; attempt++ {
+		retryable, err := s.post(ctx, endpoint, payload, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retryable || attempt == s.config.MaxAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return lastErr
+}
+
+func (s *WebhookService) post(ctx context.Context, endpoint *WebhookEndpoint, payload WebhookPayload, body []byte) (bool, error) {
+	if endpoint.Kind.tracker() {
+		return s.postComment(ctx, endpoint, payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set(WebhookEventHeader, string(payload.Event))
+	req.Header.Set(WebhookDeliveryHeader, payload.ID)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(endpoint.Secret, s.now().Unix(), body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+}
+
+func (s *WebhookService) loadEndpoints(ctx context.Context) ([]WebhookEndpoint, error) {
+	values, err := s.redis.HGetAll(ctx, webhookEndpointsKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load webhooks: %w", err)
+	}
+	endpoints := make([]WebhookEndpoint, 0, len(values))
+	for id, raw := range values {
+		var endpoint WebhookEndpoint
+		if err := json.Unmarshal([]byte(raw), &endpoint); err != nil {
+			s.logger.Warn("Skipping malformed webhook", "webhook_id", id, "error", err)
+			continue
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].CreatedAt.Before(endpoints[j].CreatedAt) })
+	return endpoints, nil
+}
+
+func isWebhookEventType(event WebhookEventType) bool {
+	for _, known := range WebhookEventTypes {
+		if known == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWebhookService(t *testing.T) *WebhookService {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewWebhookService(client, WebhookConfig{RetryBackoff: time.Millisecond})
+}
+
+type capturedWebhook struct {
+	signature string
+	event     string
+	body      []byte
+}
+
+func newWebhookReceiver(t *testing.T, statuses ...int) (*httptest.Server, func() []capturedWebhook) {
+	var mu sync.Mutex
+	var received []capturedWebhook
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, capturedWebhook{r.Header.Get(WebhookSignatureHeader), r.Header.Get(WebhookEventHeader), body})
+		status := http.StatusOK
+		if len(received) <= len(statuses) {
+			status = statuses[len(received)-1]
+		}
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []capturedWebhook {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]capturedWebhook(nil), received...)
+	}
+}
+
+func TestWebhookService_CreateAndList(t *testing.T) {
+	svc := newTestWebhookService(t)
+
+	_, err := svc.CreateEndpoint(t.Context(), WebhookEndpoint{URL: "ftp://example.com"})
+	assert.ErrorIs(t, err, ErrWebhookInvalidURL)
+	_, err = svc.CreateEndpoint(t.Context(), WebhookEndpoint{URL: "https://example.com", Events: []WebhookEventType{"trade.unknown"}})
+	assert.ErrorIs(t, err, ErrWebhookBadEvent)
+
+	created, err := svc.CreateEndpoint(t.Context(), WebhookEndpoint{URL: "https://example.com/hook", Events: []WebhookEventType{WebhookEventRisk}})
+	require.NoError(t, err)
+	assert.Len(t, created.Secret, 64, "a secret is generated when none is given")
+	assert.True(t, created.Enabled)
+
+	listed, err := svc.ListEndpoints(t.Context())
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, created.ID, listed[0].ID)
+	assert.Empty(t, listed[0].Secret, "secrets are never listed")
+
+	require.NoError(t, svc.DeleteEndpoint(t.Context(), created.ID))
+	assert.ErrorIs(t, svc.DeleteEndpoint(t.Context(), created.ID), ErrWebhookNotFound)
+}
+
+func TestWebhookService_EmitSignsAndFilters(t *testing.T) {
+	svc := newTestWebhookService(t)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	all, receivedAll := newWebhookReceiver(t)
+	riskOnly, receivedRisk := newWebhookReceiver(t)
+	_, err := svc.CreateEndpoint(t.Context(), WebhookEndpoint{URL: all.URL, Secret: "s3cret"})
+	require.NoError(t, err)
+	_, err = svc.CreateEndpoint(t.Context(), WebhookEndpoint{URL: riskOnly.URL, Events: []WebhookEventType{WebhookEventRisk}})
+	require.NoError(t, err)
+
+	svc.Emit(t.Context(), WebhookEventTradeExecuted, map[string]string{"symbol": "BTC/USDT"})
+	svc.Wait()
+
+	assert.Empty(t, receivedRisk())
+	got := receivedAll()
+	require.Len(t, got, 1)
+	assert.Equal(t, "trade.executed", got[0].event)
+	assert.Equal(t, SignWebhookPayload("s3cret", now.Unix(), got[0].body), got[0].signature)
+	assert.True(t, strings.HasPrefix(got[0].signature, "t=1767268800,v1="))
+
+	var payload WebhookPayload
+	require.NoError(t, json.Unmarshal(got[0].body, &payload))
+	assert.Equal(t, WebhookEventTradeExecuted, payload.Event)
+	assert.NotEmpty(t, payload.ID)
+	assert.Equal(t, map[string]interface{}{"symbol": "BTC/USDT"}, payload.Data)
+}
+
+func TestWebhookService_Retries(t *testing.T) {
+	svc := newTestWebhookService(t)
+
+	flaky, receivedFlaky := newWebhookReceiver(t, http.StatusBadGateway, http.StatusTooManyRequests)
+	endpoint, err := svc.CreateEndpoint(t.Context(), WebhookEndpoint{URL: flaky.URL})
+	require.NoError(t, err)
+	require.NoError(t, svc.SendTest(t.Context(), endpoint.ID))
+	assert.Len(t, receivedFlaky(), 3, "retryable failures are retried")
+
+	rejecting, receivedRejected := newWebhookReceiver(t, http.StatusBadRequest)
+	endpoint, err = svc.CreateEndpoint(t.Context(), WebhookEndpoint{URL: rejecting.URL})
+	require.NoError(t, err)
+	assert.Error(t, svc.SendTest(t.Context(), endpoint.ID))
+	assert.Len(t, receivedRejected(), 1, "client errors are not retried")
+
+	assert.ErrorIs(t, svc.SendTest(t.Context(), "missing"), ErrWebhookNotFound)
+}
+
+type recordingEmitter struct {
+	mu     sync.Mutex
+	events []WebhookEventType
+}
+
+func (r *recordingEmitter) Emit(_ context.Context, event WebhookEventType, _ interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func TestTradingModeService_EmitsModeAndRiskEvents(t *testing.T) {
+	svc, _ := newTestTradingModeService(t, TradingModeConfig{})
+	emitter := &recordingEmitter{}
+	svc.SetEventEmitter(emitter)
+
+	_, err := svc.SwitchMode(t.Context(), ExecutionModePaper, "op-1")
+	require.NoError(t, err)
+	assert.Empty(t, emitter.events, "unchanged mode emits nothing")
+
+	require.NoError(t, svc.EngageKillSwitch(t.Context(), "op-1", "drawdown"))
+	_, err = svc.RequestAction(t.Context(), ProtectedActionKillSwitchRelease, "op-1")
+	require.NoError(t, err)
+	assert.Equal(t, []WebhookEventType{WebhookEventRisk, WebhookEventRisk}, emitter.events)
+}
+
+func TestWebhookService_IssueTrackerComments(t *testing.T) {
+	type request struct {
+		path, auth string
+		body       map[string]interface{}
+	}
+	var mu sync.Mutex
+	var requests []request
+	linearErrors := false
+	tracker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		requests = append(requests, request{r.URL.Path, r.Header.Get("Authorization"), body})
+		mu.Unlock()
+		if r.URL.Path == "/graphql" {
+			if linearErrors {
+				_, _ = io.WriteString(w, `{"errors":[{"message":"Entity not found"}]}`)
+				return
+			}
+			_, _ = io.WriteString(w, `{"data":{"commentCreate":{"success":true}}}`)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(tracker.Close)
+
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	svc := NewWebhookService(client, WebhookConfig{RetryBackoff: time.Millisecond, GitHubAPIURL: tracker.URL, LinearAPIURL: tracker.URL + "/graphql"})
+
+	_, err := svc.CreateEndpoint(t.Context(), WebhookEndpoint{Kind: WebhookKindGitHub, URL: "https://github.com/acme/desk/pulls", Secret: "ghp_x"})
+	assert.ErrorIs(t, err, ErrWebhookInvalidURL)
+	_, err = svc.CreateEndpoint(t.Context(), WebhookEndpoint{Kind: WebhookKindLinear, URL: "https://linear.app/acme/issue/OPS-7"})
+	assert.ErrorIs(t, err, ErrWebhookNoToken)
+	_, err = svc.CreateEndpoint(t.Context(), WebhookEndpoint{Kind: "jira", URL: "https://example.com"})
+	assert.ErrorIs(t, err, ErrWebhookBadKind)
+
+	_, err = svc.CreateEndpoint(t.Context(), WebhookEndpoint{Kind: WebhookKindGitHub, URL: "https://github.com/acme/desk/issues/12", Secret: "ghp_x"})
+	require.NoError(t, err)
+	linear, err := svc.CreateEndpoint(t.Context(), WebhookEndpoint{Kind: WebhookKindLinear, URL: "https://linear.app/acme/issue/ops-7/track-bot", Secret: "lin_api_x"})
+	require.NoError(t, err)
+
+	svc.Emit(t.Context(), WebhookEventTradeExecuted, map[string]string{"symbol": "BTC/USDT"})
+	svc.Emit(t.Context(), WebhookEventQuestMilestone, QuestLifecycleEvent{
+		QuestID: "q-1", QuestName: "Savings", Type: QuestTypeGoal, Current: 2, Target: 4, ChatID: "42", MilestonePercent: 50,
+	})
+	svc.Wait()
+
+	require.Len(t, requests, 2, "trackers only receive quest lifecycle events by default")
+	sort.Slice(requests, func(i, j int) bool { return requests[i].path < requests[j].path })
+	assert.Equal(t, "/graphql", requests[0].path)
+	assert.Equal(t, "lin_api_x", requests[0].auth)
+	input := requests[0].body["variables"].(map[string]interface{})["input"].(map[string]interface{})
+	assert.Equal(t, "OPS-7", input["issueId"])
+	assert.Contains(t, input["body"], "📍 Quest **Savings** reached 50%")
+	assert.Equal(t, "/repos/acme/desk/issues/12/comments", requests[1].path)
+	assert.Equal(t, "Bearer ghp_x", requests[1].auth)
+	assert.Contains(t, requests[1].body["body"], "- Progress: 2/4")
+
+	linearErrors = true
+	err = svc.SendTest(t.Context(), linear.ID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Entity not found")
+}
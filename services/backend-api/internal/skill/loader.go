@@ -288,6 +288,39 @@ func (r *Registry) LoadAll() error {
 	return nil
 }
 
+// LoadTree loads the skills directory and its subdirectories into the
+// registry. A skill without an id in its frontmatter is registered under its
+// file name.
+func (r *Registry) LoadTree() error {
+	root := r.loader.skillsDir
+	err := filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+			return nil
+		}
+		skill, err := r.loader.LoadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to load skill %s: %w", path, err)
+		}
+		if skill.ID == "unnamed-skill" {
+			skill.ID = strings.TrimSuffix(entry.Name(), ".md")
+		}
+		r.mu.Lock()
+		r.skills[skill.ID] = skill
+		r.mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to load skills tree: %w", err)
+	}
+	return nil
+}
+
 // Get retrieves a skill by ID.
 func (r *Registry) Get(id string) (*Skill, bool) {
 	r.mu.RLock()
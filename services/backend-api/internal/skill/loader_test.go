@@ -409,3 +409,20 @@ Content.
 	found = registry.Find("TEST")
 	assert.Len(t, found, 1)
 }
+
+func TestRegistry_LoadTree(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "tools"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "scalping.md"), []byte("---\nname: scalping\n---\n\nContent."), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "tools", "get_price.md"), []byte("---\nid: get_price\nname: Get Price\n---\n\nContent."), 0644))
+
+	registry := NewRegistry(tmpDir)
+	require.NoError(t, registry.LoadTree())
+
+	_, ok := registry.Get("scalping")
+	assert.True(t, ok, "skills without an id use the file name")
+	_, ok = registry.Get("get_price")
+	assert.True(t, ok, "subdirectories are loaded")
+
+	assert.NoError(t, NewRegistry(filepath.Join(tmpDir, "missing")).LoadTree())
+}
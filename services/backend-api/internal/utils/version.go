@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseVersion parses a dotted release version such as "1.4.2", "v2.0" or
+// "v1.4.2-3-gabc123" into major, minor and patch numbers. Anything after a
+// "-" or "+" (pre-release, build or git describe suffixes) is ignored.
+func ParseVersion(version string) ([3]int, error) {
+	var parts [3]int
+	core := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(core, "-+"); i >= 0 {
+		core = core[:i]
+	}
+	fields := strings.Split(core, ".")
+	if core == "" || len(fields) > 3 {
+		return parts, fmt.Errorf("invalid version %q", version)
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return parts, fmt.Errorf("invalid version %q", version)
+		}
+		parts[i] = n
+	}
+	return parts, nil
+}
+
+// CompareVersions returns -1, 0 or 1 when a is older than, equal to or newer
+// than b. It returns an error if either version cannot be parsed.
+func CompareVersions(a, b string) (int, error) {
+	va, err := ParseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := ParseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	for i := range va {
+		switch {
+		case va[i] < vb[i]:
+			return -1, nil
+		case va[i] > vb[i]:
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected [3]int
+		wantErr  bool
+	}{
+		{input: "1.4.2", expected: [3]int{1, 4, 2}},
+		{input: "v2.0", expected: [3]int{2, 0, 0}},
+		{input: "v1.4.2-3-gabc123", expected: [3]int{1, 4, 2}},
+		{input: "1.0.0+build.7", expected: [3]int{1, 0, 0}},
+		{input: "dev", wantErr: true},
+		{input: "", wantErr: true},
+		{input: "1.2.3.4", wantErr: true},
+		{input: "abc1234", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseVersion(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	cmp, err := CompareVersions("1.2.0", "1.10.0")
+	assert.NoError(t, err)
+	assert.Equal(t, -1, cmp)
+
+	cmp, err = CompareVersions("v2.0.0", "1.9.9")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cmp)
+
+	cmp, err = CompareVersions("1.2", "1.2.0")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, cmp)
+
+	_, err = CompareVersions("dev", "1.0.0")
+	assert.Error(t, err)
+}
@@ -39,6 +39,10 @@ type Position struct {
 	CurrentPrice decimal.Decimal `json:"current_price"`
 	// UnrealizedPL is the profit/loss if closed at current price
 	UnrealizedPL decimal.Decimal `json:"unrealized_pl"`
+	// Leverage is the position leverage; zero for unleveraged positions
+	Leverage decimal.Decimal `json:"leverage"`
+	// LiquidationPrice is the estimated liquidation price; zero if unleveraged
+	LiquidationPrice decimal.Decimal `json:"liquidation_price"`
 	// Status is the current state of the position
 	Status PositionStatus `json:"status"`
 	// OpenedAt is when the position was opened
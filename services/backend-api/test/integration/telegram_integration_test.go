@@ -80,7 +80,7 @@ func TestTelegramIntegration(t *testing.T) {
 
 	// Call SetupRoutes
 	// We pass nil for services not involved in this test flow
-	api.SetupRoutes(router, db, redisClient, mockCCXT, nil, nil, nil, nil, nil, cfg, nil, nil, authMiddleware, nil)
+	api.SetupRoutes(router, db, redisClient, mockCCXT, nil, nil, nil, nil, nil, cfg, nil, nil, authMiddleware, nil, nil, nil)
 
 	// Test Data
 	testTelegramChatID := fmt.Sprintf("tg_int_%s", uuid.New().String())
@@ -127,6 +127,123 @@ test("/api/funding-rates returns array", async () => {
   expect(body.count).toBeGreaterThan(0);
 });
 
+test("/api/derivatives lists unexpired options and dated futures", async () => {
+  const svc = await getService();
+  const res = await svc.fetch(
+    new Request("http://localhost/api/derivatives/binance?underlying=btc"),
+  );
+  expect(res.status).toBe(200);
+  const body = await res.json();
+  expect(body.count).toBe(2);
+  const symbols = body.instruments.map((i: any) => i.symbol);
+  expect(symbols).toContain("BTC/USDT:USDT-991231");
+  expect(symbols).not.toContain("BTC/USDT:USDT-200101-60000-P");
+
+  const option = body.instruments.find((i: any) => i.type === "option");
+  expect(option.strike).toBe(60000);
+  expect(option.optionType).toBe("call");
+  expect(option.markIV).toBe(0.55);
+  expect(option.markPrice).toBe(1200);
+});
+
+test("/api/derivatives filters by type and rejects unknown types", async () => {
+  const svc = await getService();
+  let res = await svc.fetch(
+    new Request("http://localhost/api/derivatives/binance?type=future"),
+  );
+  expect(res.status).toBe(200);
+  const body = await res.json();
+  expect(body.count).toBe(1);
+  expect(body.instruments[0].type).toBe("future");
+
+  res = await svc.fetch(
+    new Request("http://localhost/api/derivatives/binance?type=swap"),
+  );
+  expect(res.status).toBe(400);
+});
+
+test("/api/leverage sets margin mode and leverage then reads them back", async () => {
+  const svc = await getService();
+  const headers = {
+    "Content-Type": "application/json",
+    "X-API-Key": process.env.ADMIN_API_KEY as string,
+  };
+  let res = await svc.fetch(
+    new Request("http://localhost/api/leverage/binance", {
+      method: "POST",
+      headers,
+      body: JSON.stringify({
+        symbol: "BTC/USDT:USDT",
+        leverage: 3,
+        marginMode: "isolated",
+      }),
+    }),
+  );
+  expect(res.status).toBe(200);
+  let body = await res.json();
+  expect(body.settings.longLeverage).toBe(3);
+  expect(body.settings.marginMode).toBe("isolated");
+
+  res = await svc.fetch(
+    new Request(
+      "http://localhost/api/leverage/binance?symbol=BTC/USDT:USDT",
+      { headers },
+    ),
+  );
+  expect(res.status).toBe(200);
+  body = await res.json();
+  expect(body.settings.shortLeverage).toBe(3);
+
+  res = await svc.fetch(
+    new Request("http://localhost/api/leverage/binance", {
+      method: "POST",
+      headers,
+      body: JSON.stringify({ symbol: "BTC/USDT:USDT", leverage: 500 }),
+    }),
+  );
+  expect(res.status).toBe(400);
+
+  res = await svc.fetch(
+    new Request("http://localhost/api/leverage/binance?symbol=BTC/USDT:USDT"),
+  );
+  expect(res.status).toBe(401);
+});
+
+test("/api/positions includes margin ratio of open positions", async () => {
+  const svc = await getService();
+  const res = await svc.fetch(
+    new Request("http://localhost/api/positions/binance", {
+      headers: { "X-API-Key": process.env.ADMIN_API_KEY as string },
+    }),
+  );
+  expect(res.status).toBe(200);
+  const body = await res.json();
+  expect(body.positions.length).toBe(1);
+  expect(body.positions[0].symbol).toBe("BTC/USDT:USDT");
+  expect(body.positions[0].marginRatio).toBe(0.05);
+  expect(body.positions[0].liquidationPrice).toBe(41000);
+});
+
+test("/api/positioning returns open interest and long/short ratio", async () => {
+  const svc = await getService();
+  const res = await svc.fetch(
+    new Request(
+      "http://localhost/api/positioning/binance?symbols=BTC/USDT:USDT,ETH/USDT:USDT",
+    ),
+  );
+  expect(res.status).toBe(200);
+  const body = await res.json();
+  expect(body.count).toBe(1);
+  expect(body.positioning[0].openInterestValue).toBe(4000000000);
+  expect(body.positioning[0].longShortRatio).toBe(1.5);
+  expect(body.positioning[0].longAccount).toBe(0.6);
+
+  const missing = await svc.fetch(
+    new Request("http://localhost/api/positioning/binance"),
+  );
+  expect(missing.status).toBe(400);
+});
+
 test("/api/funding-rates with symbols filter", async () => {
   const svc = await getService();
   const res = await svc.fetch(
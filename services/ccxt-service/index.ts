@@ -15,6 +15,7 @@ import { logger } from "hono/logger";
 // import { compress } from 'hono/compress'; // Removed due to CompressionStream not available in Bun
 import { secureHeaders } from "hono/secure-headers";
 import { validator } from "hono/validator";
+import { streamSSE } from "hono/streaming";
 // Use ESM import so test mocks can intercept ccxt module
 import ccxt from "ccxt";
 import { readFileSync, writeFileSync, existsSync, mkdirSync } from "fs";
@@ -41,6 +42,9 @@ import type {
   ExchangeManager,
   FundingRate,
   FundingRateResponse,
+  DerivativeInstrument,
+  DerivativesResponse,
+  DerivativeType,
   PlaceOrderRequest,
   PlaceOrderResponse,
   CancelOrderResponse,
@@ -49,6 +53,16 @@ import type {
   GetClosedOrdersResponse,
   GetOrderTradesResponse,
   ExchangesListResponse,
+  MarginMode,
+  MarginSettings,
+  MarginSettingsResponse,
+  PositionInfo,
+  PositionsResponse,
+  PositioningInfo,
+  PositioningResponse,
+  SetLeverageRequest,
+  UserDataOrderEvent,
+  UserDataBalanceEvent,
 } from "./types";
 
 import { getEnvWithNeuratradeFallback } from "./config";
@@ -424,6 +438,7 @@ const exchangesToLoad =
         "coinbase",
         "bingx",
         "cryptocom",
+        "deribit",
       ];
 
 // Initialize supported exchanges dynamically
@@ -1477,6 +1492,334 @@ app.get("/api/funding-rate/:exchange/*", async (c) => {
   }
 });
 
+const DERIVATIVES_DEFAULT_LIMIT = 100;
+const DERIVATIVES_MAX_LIMIT = 500;
+
+function finiteOrUndefined(value: unknown): number | undefined {
+  const n = typeof value === "string" ? Number(value) : value;
+  return typeof n === "number" && Number.isFinite(n) ? n : undefined;
+}
+
+/**
+ * Lists the exchange's options and dated futures (perpetuals excluded),
+ * soonest expiry first.
+ */
+function listDerivativeMarkets(
+  markets: Record<string, any>,
+  types: readonly DerivativeType[],
+  underlying?: string,
+  now = Date.now(),
+): DerivativeInstrument[] {
+  return Object.values(markets)
+    .filter(
+      (m: any) =>
+        m &&
+        types.includes(m.type) &&
+        m.active !== false &&
+        Number.isFinite(m.expiry) &&
+        m.expiry > now &&
+        (!underlying || m.base === underlying),
+    )
+    .map((m: any) => ({
+      symbol: m.symbol,
+      type: m.type as DerivativeType,
+      base: m.base,
+      quote: m.quote,
+      settle: m.settle ?? undefined,
+      expiry: m.expiry,
+      expiryDatetime: m.expiryDatetime ?? new Date(m.expiry).toISOString(),
+      strike: finiteOrUndefined(m.strike),
+      optionType: m.optionType ?? undefined,
+      contractSize: finiteOrUndefined(m.contractSize),
+    }))
+    .sort((a, b) => a.expiry - b.expiry || a.symbol.localeCompare(b.symbol));
+}
+
+// IV is reported in percent by some exchanges (Deribit) and as a fraction by
+// others (OKX); normalize to a fraction
+function normalizeIV(value: unknown): number | undefined {
+  const iv = finiteOrUndefined(value);
+  if (iv === undefined) return undefined;
+  return iv > 5 ? iv / 100 : iv;
+}
+
+/**
+ * Adds mark prices from tickers and implied volatility and greeks from
+ * fetchGreeks where the exchange supports them. Missing data is left unset.
+ */
+async function collectDerivativeMarks(
+  exchange: any,
+  instruments: DerivativeInstrument[],
+): Promise<void> {
+  const symbols = instruments.map((i) => i.symbol);
+  if (symbols.length === 0) return;
+
+  if (exchange.has["fetchTickers"]) {
+    try {
+      const tickers = await exchange.fetchTickers(symbols);
+      for (const instrument of instruments) {
+        const t = tickers[instrument.symbol];
+        if (!t) continue;
+        instrument.markPrice =
+          finiteOrUndefined(t.markPrice) ??
+          finiteOrUndefined(t.info?.mark_price ?? t.info?.markPx);
+        instrument.indexPrice =
+          finiteOrUndefined(t.indexPrice) ??
+          finiteOrUndefined(t.info?.index_price ?? t.info?.idxPx);
+        instrument.underlyingPrice = finiteOrUndefined(
+          t.info?.underlying_price,
+        );
+        instrument.bid = finiteOrUndefined(t.bid);
+        instrument.ask = finiteOrUndefined(t.ask);
+        instrument.last = finiteOrUndefined(t.last);
+        instrument.markIV = normalizeIV(t.info?.mark_iv);
+        instrument.bidIV = normalizeIV(t.info?.bid_iv);
+        instrument.askIV = normalizeIV(t.info?.ask_iv);
+      }
+    } catch (error) {
+      console.warn(`Failed to fetch derivative tickers on ${exchange.id}:`, error);
+    }
+  }
+
+  const options = instruments.filter(
+    (i) => i.type === "option" && i.markIV === undefined,
+  );
+  if (options.length === 0) return;
+
+  let greeks: Record<string, any> = {};
+  try {
+    if (exchange.has["fetchAllGreeks"]) {
+      greeks = await exchange.fetchAllGreeks(options.map((i) => i.symbol));
+    } else if (exchange.has["fetchGreeks"]) {
+      const results = await Promise.allSettled(
+        options.map((i) => exchange.fetchGreeks(i.symbol)),
+      );
+      results.forEach((result, idx) => {
+        if (result.status === "fulfilled" && result.value) {
+          greeks[options[idx].symbol] = result.value;
+        }
+      });
+    }
+  } catch (error) {
+    console.warn(`Failed to fetch option greeks on ${exchange.id}:`, error);
+  }
+
+  for (const instrument of options) {
+    const g = greeks[instrument.symbol];
+    if (!g) continue;
+    instrument.markIV = normalizeIV(g.markImpliedVolatility);
+    instrument.bidIV = normalizeIV(g.bidImpliedVolatility);
+    instrument.askIV = normalizeIV(g.askImpliedVolatility);
+    instrument.markPrice ??= finiteOrUndefined(g.markPrice);
+    instrument.underlyingPrice ??= finiteOrUndefined(g.underlyingPrice);
+    instrument.delta = finiteOrUndefined(g.delta);
+    instrument.gamma = finiteOrUndefined(g.gamma);
+    instrument.theta = finiteOrUndefined(g.theta);
+    instrument.vega = finiteOrUndefined(g.vega);
+  }
+}
+
+// Get options and dated futures for an exchange, with mark and IV data
+app.get(
+  "/api/derivatives/:exchange",
+  validator("query", (value, _c) => {
+    const requested = value.type
+      ? (value.type as string).split(",").map((t) => t.trim().toLowerCase())
+      : ["option", "future"];
+    const types = requested.filter(
+      (t): t is DerivativeType => t === "option" || t === "future",
+    );
+    const underlying = value.underlying
+      ? (value.underlying as string).toUpperCase()
+      : undefined;
+    const parsed = Number.parseInt((value.limit as string) || "", 10);
+    const limit =
+      Number.isFinite(parsed) && parsed > 0
+        ? Math.min(parsed, DERIVATIVES_MAX_LIMIT)
+        : DERIVATIVES_DEFAULT_LIMIT;
+    return { types, underlying, limit };
+  }),
+  async (c) => {
+    try {
+      const exchange = c.req.param("exchange");
+      const { types, underlying, limit } = c.req.valid("query");
+
+      if (!exchanges[exchange]) {
+        const errorResponse: ErrorResponse = {
+          error: "Exchange not supported",
+          timestamp: new Date().toISOString(),
+        };
+        return c.json(errorResponse, 400);
+      }
+      if (types.length === 0) {
+        const errorResponse: ErrorResponse = {
+          error: "type must be option, future or both",
+          timestamp: new Date().toISOString(),
+        };
+        return c.json(errorResponse, 400);
+      }
+
+      const markets = await exchanges[exchange].loadMarkets();
+      const instruments = listDerivativeMarkets(
+        markets,
+        types,
+        underlying,
+      ).slice(0, limit);
+      await collectDerivativeMarks(exchanges[exchange], instruments);
+
+      const response: DerivativesResponse = {
+        exchange,
+        instruments,
+        count: instruments.length,
+        timestamp: new Date().toISOString(),
+      };
+
+      return c.json(response);
+    } catch (error) {
+      const errorResponse: ErrorResponse = {
+        error: error instanceof Error ? error.message : "Unknown error",
+        timestamp: new Date().toISOString(),
+      };
+      return c.json(errorResponse, 500);
+    }
+  },
+);
+
+const POSITIONING_MAX_SYMBOLS = 50;
+
+/**
+ * Fetches open interest and the latest long/short account ratio of each
+ * symbol. Symbols the exchange has no data for are left out.
+ */
+async function collectPositioning(
+  exchange: any,
+  symbols: string[],
+  period: string,
+): Promise<PositioningInfo[]> {
+  const results = await Promise.allSettled(
+    symbols.map(async (symbol): Promise<PositioningInfo | undefined> => {
+      const info: PositioningInfo = { symbol, timestamp: Date.now() };
+      if (exchange.has["fetchOpenInterest"]) {
+        try {
+          const oi = await exchange.fetchOpenInterest(symbol);
+          info.openInterestAmount = finiteOrUndefined(oi?.openInterestAmount);
+          info.openInterestValue = finiteOrUndefined(oi?.openInterestValue);
+          info.timestamp = finiteOrUndefined(oi?.timestamp) ?? info.timestamp;
+        } catch (error) {
+          console.warn(
+            `Failed to fetch open interest for ${symbol} on ${exchange.id}:`,
+            error,
+          );
+        }
+      }
+      if (exchange.has["fetchLongShortRatioHistory"]) {
+        try {
+          const history = await exchange.fetchLongShortRatioHistory(
+            symbol,
+            period,
+            undefined,
+            1,
+          );
+          const latest = history?.[history.length - 1];
+          info.longShortRatio = finiteOrUndefined(latest?.longShortRatio);
+          const longAccount = finiteOrUndefined(latest?.info?.longAccount);
+          const shortAccount = finiteOrUndefined(latest?.info?.shortAccount);
+          info.longAccount = longAccount;
+          info.shortAccount = shortAccount;
+          if (
+            info.longShortRatio === undefined &&
+            longAccount !== undefined &&
+            shortAccount
+          ) {
+            info.longShortRatio = longAccount / shortAccount;
+          }
+        } catch (error) {
+          console.warn(
+            `Failed to fetch long/short ratio for ${symbol} on ${exchange.id}:`,
+            error,
+          );
+        }
+      }
+      const hasData =
+        info.openInterestAmount !== undefined ||
+        info.openInterestValue !== undefined ||
+        info.longShortRatio !== undefined;
+      return hasData ? info : undefined;
+    }),
+  );
+  return results
+    .filter(
+      (r): r is PromiseFulfilledResult<PositioningInfo> =>
+        r.status === "fulfilled" && r.value !== undefined,
+    )
+    .map((r) => r.value);
+}
+
+// Get open interest and long/short account ratios for perpetual swaps
+app.get(
+  "/api/positioning/:exchange",
+  validator("query", (value, _c) => {
+    const symbols = value.symbols
+      ? (value.symbols as string)
+          .split(",")
+          .map((s) => s.trim())
+          .filter(Boolean)
+      : [];
+    const period = (value.period as string) || "5m";
+    return { symbols, period };
+  }),
+  async (c) => {
+    try {
+      const exchange = c.req.param("exchange");
+      const { symbols, period } = c.req.valid("query");
+
+      if (!exchanges[exchange]) {
+        const errorResponse: ErrorResponse = {
+          error: "Exchange not supported",
+          timestamp: new Date().toISOString(),
+        };
+        return c.json(errorResponse, 400);
+      }
+      if (symbols.length === 0 || symbols.length > POSITIONING_MAX_SYMBOLS) {
+        const errorResponse: ErrorResponse = {
+          error: `symbols must list between 1 and ${POSITIONING_MAX_SYMBOLS} perpetual swaps`,
+          timestamp: new Date().toISOString(),
+        };
+        return c.json(errorResponse, 400);
+      }
+
+      const ex = exchanges[exchange];
+      if (
+        !ex.has["fetchOpenInterest"] &&
+        !ex.has["fetchLongShortRatioHistory"]
+      ) {
+        const errorResponse: ErrorResponse = {
+          error: "Exchange does not expose open interest or long/short ratios",
+          timestamp: new Date().toISOString(),
+        };
+        return c.json(errorResponse, 400);
+      }
+
+      const positioning = await collectPositioning(ex, symbols, period);
+      const response: PositioningResponse = {
+        exchange,
+        period,
+        positioning,
+        count: positioning.length,
+        timestamp: new Date().toISOString(),
+      };
+
+      return c.json(response);
+    } catch (error) {
+      const errorResponse: ErrorResponse = {
+        error: error instanceof Error ? error.message : "Unknown error",
+        timestamp: new Date().toISOString(),
+      };
+      return c.json(errorResponse, 500);
+    }
+  },
+);
+
 // Order Execution Endpoints
 
 // Place an order
@@ -1508,6 +1851,20 @@ app.post(
     if (req.amount <= 0) {
       return c.text("amount must be greater than 0", 400);
     }
+    if (
+      req.timeInForce !== undefined &&
+      !["GTC", "IOC", "FOK"].includes(req.timeInForce)
+    ) {
+      return c.text("timeInForce must be 'GTC', 'IOC', or 'FOK'", 400);
+    }
+    if (req.postOnly) {
+      if (req.type !== "limit") {
+        return c.text("postOnly requires a limit order", 400);
+      }
+      if (req.timeInForce === "IOC" || req.timeInForce === "FOK") {
+        return c.text("postOnly cannot be combined with IOC or FOK", 400);
+      }
+    }
     return req;
   }),
   async (c) => {
@@ -1536,22 +1893,46 @@ app.post(
         );
       }
 
+      const params: Record<string, unknown> = { ...req.params };
+      if (req.timeInForce) {
+        params.timeInForce = req.timeInForce;
+      }
+      if (req.postOnly) {
+        params.postOnly = true;
+      }
+
       const order = await ex.createOrder(
         req.symbol,
         req.type,
         req.side,
         req.amount,
         req.price,
-        req.params,
+        params,
       );
 
+      // Some exchanges accept a crossing post-only order and cancel it
+      // instead of rejecting it
+      if (
+        req.postOnly &&
+        !order.filled &&
+        ["canceled", "expired", "rejected"].includes(order.status ?? "")
+      ) {
+        return c.json(postOnlyRejected(order.id), 409);
+      }
+
       const response: PlaceOrderResponse = {
         order,
         timestamp: new Date().toISOString(),
       };
 
       return c.json(response);
-    } catch (error) {
+    } catch (error: any) {
+      if (
+        error?.constructor?.name === "OrderImmediatelyFillable" ||
+        error?.name === "OrderImmediatelyFillable"
+      ) {
+        return c.json(postOnlyRejected(), 409);
+      }
       return c.json(
         {
           error: error instanceof Error ? error.message : "Unknown error",
@@ -1563,6 +1944,16 @@ app.post(
   },
 );
 
+function postOnlyRejected(orderId?: string): ErrorResponse {
+  return {
+    error: orderId
+      ? `Post-only order ${orderId} was cancelled because it would have taken liquidity`
+      : "Post-only order rejected because it would have taken liquidity",
+    code: "post_only_rejected",
+    timestamp: new Date().toISOString(),
+  };
+}
+
 // Cancel an order
 app.delete("/api/order/:exchange/:orderId", adminAuth, async (c) => {
   try {
@@ -1660,6 +2051,114 @@ app.get("/api/order/:exchange/:orderId", adminAuth, async (c) => {
   }
 });
 
+// WebSocket (ccxt.pro) instances for user-data streams, created on first use
+// with the same credentials as the REST instance
+const proExchanges: Record<string, any> = {};
+
+function proExchange(exchangeId: string): any | undefined {
+  const rest = exchanges[exchangeId];
+  const cached = proExchanges[exchangeId];
+  if (cached && cached.apiKey === rest?.apiKey) {
+    return cached;
+  }
+  const ProClass = (ccxt as any).pro?.[exchangeId];
+  if (!rest?.apiKey || !ProClass || typeof ProClass !== "function") {
+    return undefined;
+  }
+  const config = exchangeConfigs[exchangeId] || exchangeConfigs.default;
+  const ex = new ProClass({
+    ...config,
+    apiKey: rest.apiKey,
+    secret: rest.secret,
+  });
+  if (!ex.has["watchOrders"]) {
+    return undefined;
+  }
+  proExchanges[exchangeId] = ex;
+  return ex;
+}
+
+// Stream order and balance updates as server-sent events ("order",
+// "balance"). Returns 501 with code "stream_unsupported" when the exchange has
+// no authenticated WebSocket API, so callers can fall back to polling.
+app.get("/api/stream/:exchange", adminAuth, async (c) => {
+  const exchange = c.req.param("exchange");
+  if (!exchanges[exchange]) {
+    return c.json(
+      {
+        error: "Exchange not supported",
+        timestamp: new Date().toISOString(),
+      } as ErrorResponse,
+      400,
+    );
+  }
+  const ex = proExchange(exchange);
+  if (!ex) {
+    return c.json(
+      {
+        error: "Exchange has no authenticated user-data stream",
+        code: "stream_unsupported",
+        timestamp: new Date().toISOString(),
+      } as ErrorResponse,
+      501,
+    );
+  }
+
+  return streamSSE(c, async (stream) => {
+    let open = true;
+    stream.onAbort(() => {
+      open = false;
+    });
+
+    const watchOrders = async () => {
+      while (open) {
+        const orders = await ex.watchOrders();
+        for (const order of orders) {
+          const event: UserDataOrderEvent = {
+            exchange,
+            order,
+            timestamp: new Date().toISOString(),
+          };
+          await stream.writeSSE({
+            event: "order",
+            data: JSON.stringify(event),
+          });
+        }
+      }
+    };
+    const watchBalance = async () => {
+      while (open && ex.has["watchBalance"]) {
+        const balance = await ex.watchBalance();
+        const event: UserDataBalanceEvent = {
+          exchange,
+          total: balance.total || {},
+          free: balance.free || {},
+          used: balance.used || {},
+          timestamp: new Date().toISOString(),
+        };
+        await stream.writeSSE({
+          event: "balance",
+          data: JSON.stringify(event),
+        });
+      }
+    };
+
+    try {
+      await Promise.all([watchOrders(), watchBalance()]);
+    } catch (error) {
+      open = false;
+      // Closing the stream tells the backend to reconnect or poll
+      await stream.writeSSE({
+        event: "error",
+        data: JSON.stringify({
+          error: error instanceof Error ? error.message : "Unknown error",
+          timestamp: new Date().toISOString(),
+        }),
+      });
+    }
+  });
+});
+
 // Get open orders
 app.get("/api/orders/:exchange", adminAuth, async (c) => {
   try {
@@ -1809,6 +2308,269 @@ app.get("/api/order/:exchange/:orderId/trades", adminAuth, async (c) => {
   }
 });
 
+// Leverage and margin mode endpoints
+
+function normalizeMarginMode(value: unknown): MarginMode | undefined {
+  if (typeof value !== "string") {
+    return undefined;
+  }
+  const mode = value.toLowerCase();
+  if (mode === "isolated") {
+    return "isolated";
+  }
+  if (mode === "cross" || mode === "crossed") {
+    return "cross";
+  }
+  return undefined;
+}
+
+async function fetchMarginSettings(
+  ex: any,
+  symbol: string,
+): Promise<MarginSettings> {
+  const settings: MarginSettings = { symbol };
+  if (ex.has["fetchLeverage"]) {
+    const leverage = await ex.fetchLeverage(symbol);
+    settings.longLeverage = finiteOrUndefined(leverage?.longLeverage);
+    settings.shortLeverage = finiteOrUndefined(leverage?.shortLeverage);
+    settings.marginMode = normalizeMarginMode(leverage?.marginMode);
+  }
+  if (!settings.marginMode && ex.has["fetchMarginMode"]) {
+    const margin = await ex.fetchMarginMode(symbol);
+    settings.marginMode = normalizeMarginMode(margin?.marginMode);
+  }
+  return settings;
+}
+
+function toPositionInfo(p: any): PositionInfo {
+  const maintenanceMargin = finiteOrUndefined(p.maintenanceMargin);
+  const collateral = finiteOrUndefined(p.collateral);
+  let marginRatio = finiteOrUndefined(p.marginRatio);
+  if (marginRatio === undefined && maintenanceMargin && collateral) {
+    marginRatio = maintenanceMargin / collateral;
+  }
+  return {
+    symbol: p.symbol,
+    side: p.side,
+    contracts: finiteOrUndefined(p.contracts),
+    contractSize: finiteOrUndefined(p.contractSize),
+    notional: finiteOrUndefined(p.notional),
+    entryPrice: finiteOrUndefined(p.entryPrice),
+    markPrice: finiteOrUndefined(p.markPrice),
+    liquidationPrice: finiteOrUndefined(p.liquidationPrice),
+    unrealizedPnl: finiteOrUndefined(p.unrealizedPnl),
+    leverage: finiteOrUndefined(p.leverage),
+    marginMode: normalizeMarginMode(p.marginMode),
+    initialMargin: finiteOrUndefined(p.initialMargin),
+    maintenanceMargin,
+    collateral,
+    marginRatio,
+  };
+}
+
+// Get leverage and margin mode of a symbol
+app.get("/api/leverage/:exchange", adminAuth, async (c) => {
+  try {
+    const exchange = c.req.param("exchange");
+    const symbol = c.req.query("symbol");
+
+    if (!exchanges[exchange]) {
+      return c.json(
+        {
+          error: "Exchange not supported",
+          timestamp: new Date().toISOString(),
+        } as ErrorResponse,
+        400,
+      );
+    }
+    if (!symbol) {
+      return c.json(
+        {
+          error: "symbol is required",
+          timestamp: new Date().toISOString(),
+        } as ErrorResponse,
+        400,
+      );
+    }
+
+    const ex = exchanges[exchange];
+
+    if (!ex.has["fetchLeverage"] && !ex.has["fetchMarginMode"]) {
+      return c.json(
+        {
+          error: "Exchange does not support fetching leverage",
+          timestamp: new Date().toISOString(),
+        } as ErrorResponse,
+        400,
+      );
+    }
+
+    const response: MarginSettingsResponse = {
+      exchange,
+      settings: await fetchMarginSettings(ex, symbol),
+      timestamp: new Date().toISOString(),
+    };
+
+    return c.json(response);
+  } catch (error) {
+    return c.json(
+      {
+        error: error instanceof Error ? error.message : "Unknown error",
+        timestamp: new Date().toISOString(),
+      } as ErrorResponse,
+      500,
+    );
+  }
+});
+
+// Set leverage and margin mode of a symbol
+app.post(
+  "/api/leverage/:exchange",
+  adminAuth,
+  validator("json", (value, c) => {
+    const req = value as SetLeverageRequest;
+    if (!req.symbol || !req.leverage) {
+      return c.text("Missing required fields: symbol, leverage", 400);
+    }
+    if (req.leverage < 1 || req.leverage > 125) {
+      return c.text("leverage must be between 1 and 125", 400);
+    }
+    if (
+      req.marginMode !== undefined &&
+      req.marginMode !== "isolated" &&
+      req.marginMode !== "cross"
+    ) {
+      return c.text("marginMode must be 'isolated' or 'cross'", 400);
+    }
+    return req;
+  }),
+  async (c) => {
+    try {
+      const exchange = c.req.param("exchange");
+      const req = c.req.valid("json") as SetLeverageRequest;
+
+      if (!exchanges[exchange]) {
+        return c.json(
+          {
+            error: "Exchange not supported",
+            timestamp: new Date().toISOString(),
+          } as ErrorResponse,
+          400,
+        );
+      }
+
+      const ex = exchanges[exchange];
+
+      if (!ex.has["setLeverage"]) {
+        return c.json(
+          {
+            error: "Exchange does not support setting leverage",
+            timestamp: new Date().toISOString(),
+          } as ErrorResponse,
+          400,
+        );
+      }
+      if (req.marginMode && !ex.has["setMarginMode"]) {
+        return c.json(
+          {
+            error: "Exchange does not support setting margin mode",
+            timestamp: new Date().toISOString(),
+          } as ErrorResponse,
+          400,
+        );
+      }
+
+      // Margin mode first: some exchanges reset leverage when it changes
+      if (req.marginMode) {
+        const current = await fetchMarginSettings(ex, req.symbol);
+        if (current.marginMode !== req.marginMode) {
+          await ex.setMarginMode(req.marginMode, req.symbol);
+        }
+      }
+      await ex.setLeverage(req.leverage, req.symbol);
+
+      let settings: MarginSettings = {
+        symbol: req.symbol,
+        marginMode: req.marginMode,
+        longLeverage: req.leverage,
+        shortLeverage: req.leverage,
+      };
+      if (ex.has["fetchLeverage"] || ex.has["fetchMarginMode"]) {
+        settings = await fetchMarginSettings(ex, req.symbol);
+      }
+
+      const response: MarginSettingsResponse = {
+        exchange,
+        settings,
+        timestamp: new Date().toISOString(),
+      };
+
+      return c.json(response);
+    } catch (error) {
+      return c.json(
+        {
+          error: error instanceof Error ? error.message : "Unknown error",
+          timestamp: new Date().toISOString(),
+        } as ErrorResponse,
+        500,
+      );
+    }
+  },
+);
+
+// Get open positions with margin state
+app.get("/api/positions/:exchange", adminAuth, async (c) => {
+  try {
+    const exchange = c.req.param("exchange");
+    const symbols = c.req.query("symbols");
+
+    if (!exchanges[exchange]) {
+      return c.json(
+        {
+          error: "Exchange not supported",
+          timestamp: new Date().toISOString(),
+        } as ErrorResponse,
+        400,
+      );
+    }
+
+    const ex = exchanges[exchange];
+
+    if (!ex.has["fetchPositions"]) {
+      return c.json(
+        {
+          error: "Exchange does not support fetching positions",
+          timestamp: new Date().toISOString(),
+        } as ErrorResponse,
+        400,
+      );
+    }
+
+    const raw = await ex.fetchPositions(
+      symbols ? symbols.split(",").map((s: string) => s.trim()) : undefined,
+    );
+    const positions = (raw || [])
+      .filter((p: any) => (finiteOrUndefined(p.contracts) ?? 0) !== 0)
+      .map(toPositionInfo);
+
+    const response: PositionsResponse = {
+      exchange,
+      positions,
+      timestamp: new Date().toISOString(),
+    };
+
+    return c.json(response);
+  } catch (error) {
+    return c.json(
+      {
+        error: error instanceof Error ? error.message : "Unknown error",
+        timestamp: new Date().toISOString(),
+      } as ErrorResponse,
+      500,
+    );
+  }
+});
+
 // Exchange management endpoints
 
 // Add exchange to blacklist
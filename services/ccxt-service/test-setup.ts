@@ -22,8 +22,50 @@ mock.module("ccxt", () => {
       fetchTicker: true,
       fetchOrderBook: true,
       fetchOHLCV: true,
+      fetchGreeks: true,
+      fetchLeverage: true,
+      setLeverage: true,
+      setMarginMode: true,
+      fetchPositions: true,
+      fetchOpenInterest: true,
+      fetchLongShortRatioHistory: true,
+    };
+    leverage = 10;
+    marginMode = "cross";
+    markets: Record<string, any> = {
+      "BTC/USDT": {},
+      "ETH/USDT": {},
+      "BTC/USDT:USDT-991231": {
+        symbol: "BTC/USDT:USDT-991231",
+        type: "future",
+        base: "BTC",
+        quote: "USDT",
+        settle: "USDT",
+        expiry: Date.UTC(2099, 11, 31, 8),
+        contractSize: 1,
+      },
+      "BTC/USDT:USDT-991231-60000-C": {
+        symbol: "BTC/USDT:USDT-991231-60000-C",
+        type: "option",
+        base: "BTC",
+        quote: "USDT",
+        settle: "USDT",
+        expiry: Date.UTC(2099, 11, 31, 8),
+        strike: 60000,
+        optionType: "call",
+        contractSize: 0.01,
+      },
+      "BTC/USDT:USDT-200101-60000-P": {
+        symbol: "BTC/USDT:USDT-200101-60000-P",
+        type: "option",
+        base: "BTC",
+        quote: "USDT",
+        settle: "USDT",
+        expiry: Date.UTC(2020, 0, 1, 8),
+        strike: 60000,
+        optionType: "put",
+      },
     };
-    markets: Record<string, any> = { "BTC/USDT": {}, "ETH/USDT": {} };
     constructor(public config?: any) {}
 
     async fetchTicker(symbol: string) {
@@ -90,6 +132,80 @@ mock.module("ccxt", () => {
       };
     }
 
+    async fetchGreeks(symbol: string) {
+      return {
+        symbol,
+        markImpliedVolatility: 0.55,
+        bidImpliedVolatility: 0.54,
+        askImpliedVolatility: 0.56,
+        markPrice: 1200,
+        underlyingPrice: 50000,
+        delta: 0.4,
+        gamma: 0.00002,
+        theta: -15,
+        vega: 40,
+      };
+    }
+
+    async fetchLeverage(symbol: string) {
+      return {
+        symbol,
+        marginMode: this.marginMode,
+        longLeverage: this.leverage,
+        shortLeverage: this.leverage,
+      };
+    }
+
+    async setLeverage(leverage: number, _symbol: string) {
+      this.leverage = leverage;
+      return {};
+    }
+
+    async setMarginMode(marginMode: string, _symbol: string) {
+      this.marginMode = marginMode;
+      return {};
+    }
+
+    async fetchPositions(_symbols?: string[]) {
+      return [
+        {
+          symbol: "BTC/USDT:USDT",
+          side: "long",
+          contracts: 0.5,
+          notional: 25000,
+          entryPrice: 49000,
+          markPrice: 50000,
+          liquidationPrice: 41000,
+          leverage: this.leverage,
+          marginMode: this.marginMode,
+          maintenanceMargin: 125,
+          collateral: 2500,
+        },
+        { symbol: "ETH/USDT:USDT", contracts: 0 },
+      ];
+    }
+
+    async fetchOpenInterest(symbol: string) {
+      if (symbol !== "BTC/USDT:USDT") throw new Error("no open interest");
+      return {
+        symbol,
+        openInterestAmount: 80000,
+        openInterestValue: 4000000000,
+        timestamp: 1700000000000,
+      };
+    }
+
+    async fetchLongShortRatioHistory(symbol: string) {
+      if (symbol !== "BTC/USDT:USDT") return [];
+      return [
+        {
+          symbol,
+          longShortRatio: 1.5,
+          info: { longAccount: "0.6", shortAccount: "0.4" },
+        },
+      ];
+    }
+
     async fetchFundingRates(symbols?: string[]) {
       const all = ["BTC/USDT", "ETH/USDT"];
       const selected = symbols && symbols.length ? symbols : all;
@@ -12,6 +12,12 @@ export type OrderSide = "buy" | "sell";
  */
 export type OrderType = "market" | "limit" | "stop" | "stop_limit";
 
+/**
+ * Time-in-force enumeration: good-till-cancelled, immediate-or-cancel,
+ * fill-or-kill.
+ */
+export type TimeInForce = "GTC" | "IOC" | "FOK";
+
 /**
  * Request to place an order.
  */
@@ -22,6 +28,9 @@ export interface PlaceOrderRequest {
   type: OrderType;
   amount: number;
   price?: number;
+  timeInForce?: TimeInForce;
+  /** Only add liquidity; the order is rejected if it would match immediately. */
+  postOnly?: boolean;
   params?: Record<string, unknown>;
 }
 
@@ -68,6 +77,26 @@ export interface GetOrderResponse {
   timestamp: string;
 }
 
+/**
+ * Order update pushed on a user-data stream (SSE event "order").
+ */
+export interface UserDataOrderEvent {
+  exchange: string;
+  order: Order;
+  timestamp: string;
+}
+
+/**
+ * Balance update pushed on a user-data stream (SSE event "balance").
+ */
+export interface UserDataBalanceEvent {
+  exchange: string;
+  total: Record<string, number>;
+  free: Record<string, number>;
+  used: Record<string, number>;
+  timestamp: string;
+}
+
 /**
  * Request to get open orders.
  */
@@ -118,6 +147,72 @@ export interface GetOrderTradesResponse {
   timestamp: string;
 }
 
+// Leverage and Margin Types
+
+/**
+ * Margin mode of a derivatives position.
+ */
+export type MarginMode = "isolated" | "cross";
+
+/**
+ * Leverage and margin mode configured for a symbol on an account.
+ */
+export interface MarginSettings {
+  symbol: string;
+  marginMode?: MarginMode;
+  longLeverage?: number;
+  shortLeverage?: number;
+}
+
+/**
+ * Request to set the leverage and optionally the margin mode of a symbol.
+ */
+export interface SetLeverageRequest {
+  symbol: string;
+  leverage: number;
+  marginMode?: MarginMode;
+}
+
+/**
+ * Response containing the margin settings of a symbol.
+ */
+export interface MarginSettingsResponse {
+  exchange: string;
+  settings: MarginSettings;
+  timestamp: string;
+}
+
+/**
+ * An open derivatives position with its margin state. marginRatio is
+ * maintenance margin over collateral; liquidation happens near 1.
+ */
+export interface PositionInfo {
+  symbol: string;
+  side?: string;
+  contracts?: number;
+  contractSize?: number;
+  notional?: number;
+  entryPrice?: number;
+  markPrice?: number;
+  liquidationPrice?: number;
+  unrealizedPnl?: number;
+  leverage?: number;
+  marginMode?: MarginMode;
+  initialMargin?: number;
+  maintenanceMargin?: number;
+  collateral?: number;
+  marginRatio?: number;
+}
+
+/**
+ * Response containing the open positions of an account.
+ */
+export interface PositionsResponse {
+  exchange: string;
+  positions: PositionInfo[];
+  timestamp: string;
+}
+
 // API Response Types
 
 /**
@@ -218,6 +313,8 @@ export interface MultiTickerResponse {
  */
 export interface ErrorResponse {
   error: string;
+  /** Machine-readable reason, e.g. post_only_rejected. */
+  code?: string;
   message?: string;
   timestamp: string;
   availableExchanges?: string[];
@@ -248,6 +345,36 @@ export interface FundingRateResponse {
   timestamp: string;
 }
 
+/**
+ * Open interest and long/short account ratio of a perpetual swap. Fields are
+ * omitted when the exchange does not expose them.
+ */
+export interface PositioningInfo {
+  symbol: string;
+  /** Open interest in contracts or base currency. */
+  openInterestAmount?: number;
+  /** Open interest in quote currency. */
+  openInterestValue?: number;
+  /** Long accounts divided by short accounts. */
+  longShortRatio?: number;
+  /** Share of accounts that are net long, as a fraction. */
+  longAccount?: number;
+  /** Share of accounts that are net short, as a fraction. */
+  shortAccount?: number;
+  timestamp: number;
+}
+
+/**
+ * Response containing open interest and long/short ratios for an exchange.
+ */
+export interface PositioningResponse {
+  exchange: string;
+  period: string;
+  positioning: PositioningInfo[];
+  count: number;
+  timestamp: string;
+}
+
 /**
  * Query parameters for funding rate requests.
  */
@@ -255,6 +382,53 @@ export interface FundingRateQuery {
   symbols?: string[];
 }
 
+// Derivative Types
+
+/**
+ * Kinds of expiring instruments listed by the derivatives endpoint.
+ */
+export type DerivativeType = "option" | "future";
+
+/**
+ * An option or dated future with its latest mark and implied volatility data.
+ * Greeks and volatilities are only present for options on exchanges that report them.
+ */
+export interface DerivativeInstrument {
+  symbol: string;
+  type: DerivativeType;
+  base: string;
+  quote: string;
+  settle?: string;
+  expiry: number;
+  expiryDatetime: string;
+  strike?: number;
+  optionType?: "call" | "put";
+  contractSize?: number;
+  markPrice?: number;
+  indexPrice?: number;
+  underlyingPrice?: number;
+  bid?: number;
+  ask?: number;
+  last?: number;
+  markIV?: number;
+  bidIV?: number;
+  askIV?: number;
+  delta?: number;
+  gamma?: number;
+  theta?: number;
+  vega?: number;
+}
+
+/**
+ * Response containing the options and dated futures of an exchange.
+ */
+export interface DerivativesResponse {
+  exchange: string;
+  instruments: DerivativeInstrument[];
+  count: number;
+  timestamp: string;
+}
+
 // Exchange Management
 
 /**
@@ -98,15 +98,28 @@ app.post("/send-message", async (c) => {
   }
 
   const body = await c.req.json();
-  const { chatId, text, parseMode } = body;
+  const { chatId, text, parseMode, replyMarkup, replyToMessageId } = body;
 
   if (!chatId || !text) {
     return c.json({ error: "Missing chatId or text" }, 400);
   }
 
   try {
-    await bot.api.sendMessage(chatId, text, { parse_mode: parseMode });
-    return c.json({ ok: true });
+    const message = await bot.api.sendMessage(chatId, text, {
+      parse_mode: parseMode,
+      reply_markup: replyMarkup,
+      // Continuation parts of a split notification reply to the first part
+      reply_parameters: replyToMessageId
+        ? {
+            message_id: Number(replyToMessageId),
+            allow_sending_without_reply: true,
+          }
+        : undefined,
+    });
+    return c.json({
+      ok: true,
+      messageId: message?.message_id ? String(message.message_id) : undefined,
+    });
   } catch (error) {
     logger.error("Failed to send message", error as Error, { chatId });
     return c.json(
@@ -152,6 +165,20 @@ logger.info("Telegram service started", {
 
 const grpcServer = startGrpcServer(bot, config.grpcPort);
 
+api
+  .checkCompatibility()
+  .then((compat) => {
+    logger.info("Backend compatibility checked", {
+      serverVersion: compat.server_version,
+      compatible: compat.client?.compatible ?? true,
+    });
+  })
+  .catch((error) => {
+    logger.warn("Could not verify backend compatibility", {
+      error: String(error),
+    });
+  });
+
 const startBot = async () => {
   // Skip bot startup if token is not configured
   if (!bot) {
@@ -0,0 +1,71 @@
+import { describe, test, expect, afterEach } from "bun:test";
+import { BackendApiClient, ApiClientError, CLIENT_NAME } from "./client";
+
+const originalFetch = globalThis.fetch;
+
+const stubFetch = (
+  body: unknown,
+  headers: Record<string, string> = {},
+  seen: Request[] = [],
+) => {
+  globalThis.fetch = (async (input: RequestInfo | URL, init?: RequestInit) => {
+    seen.push(new Request(input, init));
+    return new Response(JSON.stringify(body), {
+      status: 200,
+      headers: { "Content-Type": "application/json", ...headers },
+    });
+  }) as typeof fetch;
+  return seen;
+};
+
+const newClient = () =>
+  new BackendApiClient({ baseUrl: "http://backend", adminKey: "key" });
+
+describe("BackendApiClient compatibility", () => {
+  afterEach(() => {
+    globalThis.fetch = originalFetch;
+  });
+
+  test("sends X-Client-Version on every request", async () => {
+    const seen = stubFetch({
+      server_version: "1.0.0",
+      min_client_versions: {},
+    });
+    await newClient().checkCompatibility();
+    expect(seen[0].headers.get("X-Client-Version")).toStartWith(
+      `${CLIENT_NAME}/`,
+    );
+  });
+
+  test("refuses destructive commands when the backend reports outdated", async () => {
+    const client = newClient();
+    stubFetch({
+      server_version: "2.0.0",
+      api_version: "v1",
+      min_client_versions: { [CLIENT_NAME]: "9.0.0" },
+      client: { name: CLIENT_NAME, version: "1.0.0", compatible: false },
+    });
+    await client.checkCompatibility();
+    expect(client.isOutdated()).toBe(true);
+
+    await expect(client.beginAutonomous("123")).rejects.toBeInstanceOf(
+      ApiClientError,
+    );
+
+    // Read-only commands keep working
+    stubFetch({ opportunities: [] });
+    await expect(client.getArbitrageOpportunities()).resolves.toEqual({
+      opportunities: [],
+    });
+  });
+
+  test("marks outdated from the X-Client-Compat response header", async () => {
+    const client = newClient();
+    stubFetch(
+      { opportunities: [] },
+      { "X-Client-Compat": "outdated; min=9.0.0" },
+    );
+    await client.getArbitrageOpportunities();
+    expect(client.isOutdated()).toBe(true);
+  });
+});
@@ -22,11 +22,45 @@ import type {
   AIRouteRequest,
   AIRouteResponse,
   GetAlertsResponse,
-  CreateAlertRequest,
   CreateAlertResponse,
+  PreviewAlertResponse,
+  NotificationCallbackResponse,
+  WatchlistAction,
+  WatchlistResponse,
+  AllocationAction,
+  AllocationResponse,
+  OperatingProfile,
+  OperatingProfileResponse,
+  ProfileListResponse,
+  LossStreaksResponse,
+  SymbolCooldownsResponse,
+  HedgeSuggestionsResponse,
+  ConfirmHedgeResponse,
+  ChartResponse,
+  PnLReportResponse,
+  SearchResponse,
+  CarryReportResponse,
+  ShareLinkResponse,
+  CompatResponse,
 } from "./types";
 import { API_ENDPOINTS } from "./types";
 import { RateLimiter, DEFAULT_RATE_LIMIT } from "./rate-limiter";
+import { logger } from "../utils/logger";
+
+// Sent as X-Client-Version so the backend can flag an outdated service
+export const CLIENT_NAME = "telegram-service";
+export const CLIENT_VERSION =
+  process.env.SERVICE_VERSION || process.env.npm_package_version || "dev";
+
+// Endpoints refused while the backend reports this service as outdated,
+// since a schema mismatch there can move funds or start trading
+const DESTRUCTIVE_ENDPOINTS: ReadonlySet<string> = new Set([
+  API_ENDPOINTS.BEGIN_AUTONOMOUS,
+  API_ENDPOINTS.LIQUIDATE,
+  API_ENDPOINTS.LIQUIDATE_ALL,
+  API_ENDPOINTS.REMOVE_WALLET,
+  API_ENDPOINTS.CONFIRM_HEDGE,
+]);
 
 export class ApiClientError extends Error {
   constructor(
@@ -49,6 +83,7 @@ export class BackendApiClient {
   private readonly baseUrl: string;
   private readonly adminKey: string;
   private readonly rateLimiter: RateLimiter;
+  private outdated = false;
 
   constructor(options: BackendApiClientOptions) {
     this.baseUrl = options.baseUrl.replace(/\/$/, "");
@@ -120,14 +155,28 @@ export class BackendApiClient {
     });
   }
 
-  async beginAutonomous(chatId: string): Promise<BeginAutonomousResponse> {
+  async beginAutonomous(
+    chatId: string,
+    profile?: string,
+  ): Promise<BeginAutonomousResponse> {
     return this.fetch<BeginAutonomousResponse>(API_ENDPOINTS.BEGIN_AUTONOMOUS, {
       method: "POST",
-      body: JSON.stringify({ chat_id: chatId }),
+      body: JSON.stringify({ chat_id: chatId, profile }),
       requireAdmin: true,
     });
   }
 
+  async resumeAutonomous(chatId: string): Promise<BeginAutonomousResponse> {
+    return this.fetch<BeginAutonomousResponse>(
+      API_ENDPOINTS.RESUME_AUTONOMOUS,
+      {
+        method: "POST",
+        body: JSON.stringify({ chat_id: chatId }),
+        requireAdmin: true,
+      },
+    );
+  }
+
   async pauseAutonomous(chatId: string): Promise<PauseAutonomousResponse> {
     return this.fetch<PauseAutonomousResponse>(API_ENDPOINTS.PAUSE_AUTONOMOUS, {
       method: "POST",
@@ -303,6 +352,36 @@ export class BackendApiClient {
     });
   }
 
+  /**
+   * Ask the backend which client versions it supports and warn when this
+   * service is older than the minimum. Destructive commands are refused
+   * until a compatible version is deployed.
+   */
+  async checkCompatibility(): Promise<CompatResponse> {
+    const response = await this.fetch<CompatResponse>(API_ENDPOINTS.COMPAT, {
+      requireAdmin: false,
+    });
+    if (response.client && !response.client.compatible) {
+      this.markOutdated(response.client.min_version ?? "unknown");
+    }
+    return response;
+  }
+
+  isOutdated(): boolean {
+    return this.outdated;
+  }
+
+  private markOutdated(minVersion: string): void {
+    if (this.outdated) {
+      return;
+    }
+    this.outdated = true;
+    logger.warn("Backend reports this telegram-service version as outdated", {
+      version: CLIENT_VERSION,
+      minVersion,
+    });
+  }
+
   private async fetch<T>(
     path: string,
     options: {
@@ -312,10 +391,19 @@ export class BackendApiClient {
       handle404AsNull?: boolean;
     } = {},
   ): Promise<T> {
+    if (this.outdated && DESTRUCTIVE_ENDPOINTS.has(path)) {
+      throw new ApiClientError(
+        `${CLIENT_NAME} ${CLIENT_VERSION} is older than the backend supports; upgrade before running this command`,
+        426,
+        path,
+      );
+    }
+
     await this.rateLimiter.acquireToken();
 
     const headers: Record<string, string> = {
       "Content-Type": "application/json",
+      "X-Client-Version": `${CLIENT_NAME}/${CLIENT_VERSION}`,
     };
 
     if (options.requireAdmin && this.adminKey) {
@@ -328,6 +416,11 @@ export class BackendApiClient {
       body: options.body,
     });
 
+    const compat = response.headers.get("X-Client-Compat");
+    if (compat?.startsWith("outdated")) {
+      this.markOutdated(compat.replace(/^outdated;\s*min=/, ""));
+    }
+
     if (options.handle404AsNull && response.status === 404) {
       return null as T;
     }
@@ -347,53 +440,216 @@ export class BackendApiClient {
     return payload as T;
   }
 
-  async getUserAlerts(userId: string): Promise<GetAlertsResponse> {
-    const endpoint = API_ENDPOINTS.GET_ALERTS(userId);
+  async getUserAlerts(chatId: string): Promise<GetAlertsResponse> {
+    const endpoint = API_ENDPOINTS.GET_ALERTS(chatId);
     return this.fetch<GetAlertsResponse>(endpoint, { requireAdmin: true });
   }
 
-  async createAlert(
-    userId: string,
-    alertType: string,
-    conditions: Record<string, unknown>,
-  ): Promise<CreateAlertResponse> {
-    const endpoint = API_ENDPOINTS.CREATE_ALERT;
-    const body: CreateAlertRequest & { user_id: string } = {
-      user_id: userId,
-      alert_type: alertType,
-      conditions,
-    };
-    return this.fetch<CreateAlertResponse>(endpoint, {
+  async createAlert(chatId: string, rule: string): Promise<CreateAlertResponse> {
+    return this.fetch<CreateAlertResponse>(API_ENDPOINTS.CREATE_ALERT, {
       method: "POST",
-      body: JSON.stringify(body),
+      body: JSON.stringify({ chat_id: chatId, rule }),
+      requireAdmin: true,
+    });
+  }
+
+  async previewAlert(rule: string): Promise<PreviewAlertResponse> {
+    return this.fetch<PreviewAlertResponse>(API_ENDPOINTS.PREVIEW_ALERT, {
+      method: "POST",
+      body: JSON.stringify({ rule }),
       requireAdmin: true,
     });
   }
 
   async updateAlert(
+    chatId: string,
     alertId: string,
     isActive: boolean,
-    conditions?: Record<string, unknown>,
-  ): Promise<{ status: string; message: string }> {
-    const endpoint = API_ENDPOINTS.UPDATE_ALERT(alertId);
-    const body: { is_active: boolean; conditions?: Record<string, unknown> } = {
-      is_active: isActive,
-    };
-    if (conditions) {
-      body.conditions = conditions;
-    }
-    return this.fetch(endpoint, {
+  ): Promise<{ status: string }> {
+    return this.fetch(API_ENDPOINTS.UPDATE_ALERT(alertId), {
       method: "PUT",
-      body: JSON.stringify(body),
+      body: JSON.stringify({ chat_id: chatId, is_active: isActive }),
+      requireAdmin: true,
+    });
+  }
+
+  async handleNotificationCallback(
+    chatId: string,
+    data: string,
+  ): Promise<NotificationCallbackResponse> {
+    return this.fetch<NotificationCallbackResponse>(
+      API_ENDPOINTS.NOTIFICATION_CALLBACK,
+      {
+        method: "POST",
+        body: JSON.stringify({ chat_id: chatId, data }),
+        requireAdmin: true,
+      },
+    );
+  }
+
+  async getWatchlist(chatId: string): Promise<WatchlistResponse> {
+    return this.fetch<WatchlistResponse>(API_ENDPOINTS.GET_WATCHLIST(chatId), {
+      requireAdmin: true,
+    });
+  }
+
+  async updateWatchlist(
+    chatId: string,
+    action: WatchlistAction,
+    symbols: readonly string[] = [],
+  ): Promise<WatchlistResponse> {
+    return this.fetch<WatchlistResponse>(API_ENDPOINTS.UPDATE_WATCHLIST, {
+      method: "POST",
+      body: JSON.stringify({ chat_id: chatId, action, symbols }),
+      requireAdmin: true,
+    });
+  }
+
+  async getAllocation(chatId: string): Promise<AllocationResponse> {
+    return this.fetch<AllocationResponse>(
+      API_ENDPOINTS.GET_ALLOCATION(chatId),
+      { requireAdmin: true },
+    );
+  }
+
+  async updateAllocation(
+    chatId: string,
+    action: AllocationAction,
+    targets: Readonly<Record<string, number>> = {},
+    performanceWeighted = false,
+  ): Promise<AllocationResponse> {
+    return this.fetch<AllocationResponse>(API_ENDPOINTS.UPDATE_ALLOCATION, {
+      method: "POST",
+      body: JSON.stringify({
+        chat_id: chatId,
+        action,
+        targets,
+        performance_weighted: performanceWeighted,
+      }),
+      requireAdmin: true,
+    });
+  }
+
+  async getProfiles(chatId: string): Promise<ProfileListResponse> {
+    return this.fetch<ProfileListResponse>(API_ENDPOINTS.GET_PROFILES(chatId), {
+      requireAdmin: true,
+    });
+  }
+
+  async selectProfile(
+    chatId: string,
+    profile: string,
+  ): Promise<OperatingProfileResponse> {
+    return this.fetch<OperatingProfileResponse>(API_ENDPOINTS.SELECT_PROFILE, {
+      method: "POST",
+      body: JSON.stringify({ chat_id: chatId, profile }),
+      requireAdmin: true,
+    });
+  }
+
+  async saveProfile(
+    chatId: string,
+    base: string,
+    profile: Partial<OperatingProfile> & { readonly name: string },
+  ): Promise<OperatingProfileResponse> {
+    return this.fetch<OperatingProfileResponse>(API_ENDPOINTS.SAVE_PROFILE, {
+      method: "POST",
+      body: JSON.stringify({ chat_id: chatId, base, profile }),
+      requireAdmin: true,
+    });
+  }
+
+  async deleteProfile(
+    chatId: string,
+    name: string,
+  ): Promise<{ status: string }> {
+    return this.fetch(API_ENDPOINTS.DELETE_PROFILE(name, chatId), {
+      method: "DELETE",
+      requireAdmin: true,
+    });
+  }
+
+  async getLossStreaks(chatId: string): Promise<LossStreaksResponse> {
+    return this.fetch<LossStreaksResponse>(
+      API_ENDPOINTS.GET_LOSS_STREAKS(chatId),
+      { requireAdmin: true },
+    );
+  }
+
+  async getSymbolCooldowns(): Promise<SymbolCooldownsResponse> {
+    return this.fetch<SymbolCooldownsResponse>(
+      API_ENDPOINTS.GET_SYMBOL_COOLDOWNS,
+      { requireAdmin: true },
+    );
+  }
+
+  async getHedgeSuggestions(chatId: string): Promise<HedgeSuggestionsResponse> {
+    return this.fetch<HedgeSuggestionsResponse>(
+      API_ENDPOINTS.GET_HEDGE_SUGGESTIONS(chatId),
+      { requireAdmin: true },
+    );
+  }
+
+  async confirmHedge(
+    chatId: string,
+    id: string,
+  ): Promise<ConfirmHedgeResponse> {
+    return this.fetch<ConfirmHedgeResponse>(API_ENDPOINTS.CONFIRM_HEDGE, {
+      method: "POST",
+      body: JSON.stringify({ chat_id: chatId, id }),
+      requireAdmin: true,
+    });
+  }
+
+  async getPnL(chatId: string, period: string): Promise<PnLReportResponse> {
+    return this.fetch<PnLReportResponse>(API_ENDPOINTS.GET_PNL(chatId, period), {
+      requireAdmin: true,
+    });
+  }
+
+  async search(query: string, limit: number): Promise<SearchResponse> {
+    return this.fetch<SearchResponse>(API_ENDPOINTS.SEARCH(query, limit), {
+      requireAdmin: true,
+    });
+  }
+
+  async getCarry(
+    symbol: string,
+    days: number,
+    limit: number,
+  ): Promise<CarryReportResponse> {
+    return this.fetch<CarryReportResponse>(
+      API_ENDPOINTS.GET_CARRY(symbol, days, limit),
+    );
+  }
+
+  async createShareLink(
+    chatId: string,
+    period: string,
+    showAbsolute: boolean,
+  ): Promise<ShareLinkResponse> {
+    return this.fetch<ShareLinkResponse>(API_ENDPOINTS.CREATE_SHARE_LINK, {
+      method: "POST",
+      body: JSON.stringify({
+        chat_id: chatId,
+        period,
+        show_absolute: showAbsolute,
+      }),
+      requireAdmin: true,
+    });
+  }
+
+  async getChart(symbol: string, timeframe: string): Promise<ChartResponse> {
+    return this.fetch<ChartResponse>(API_ENDPOINTS.GET_CHART(symbol, timeframe), {
       requireAdmin: true,
     });
   }
 
   async deleteAlert(
+    chatId: string,
     alertId: string,
-  ): Promise<{ status: string; message: string }> {
-    const endpoint = API_ENDPOINTS.DELETE_ALERT(alertId);
-    return this.fetch(endpoint, {
+  ): Promise<{ status: string }> {
+    return this.fetch(API_ENDPOINTS.DELETE_ALERT(alertId, chatId), {
       method: "DELETE",
       requireAdmin: true,
     });
@@ -88,6 +88,32 @@ export interface SendMessageRequest {
   readonly chatId: string | number;
   readonly text: string;
   readonly parseMode?: "HTML" | "Markdown" | "MarkdownV2";
+  readonly replyMarkup?: InlineKeyboardMarkup;
+  /** Message the new message replies to, threading multi-part notifications. */
+  readonly replyToMessageId?: string;
+}
+
+/**
+ * Inline keyboard attached to backend notifications.
+ */
+export interface InlineKeyboardMarkup {
+  readonly inline_keyboard: ReadonlyArray<
+    ReadonlyArray<{ readonly text: string; readonly callback_data: string }>
+  >;
+}
+
+/**
+ * Result of a notification button press.
+ * Returned by POST /api/v1/telegram/internal/callbacks
+ */
+export interface NotificationCallbackResponse {
+  readonly status: string;
+  readonly data: {
+    readonly text: string;
+    readonly toast: string;
+    readonly reply_markup?: InlineKeyboardMarkup;
+    readonly order_ids?: readonly string[];
+  };
 }
 
 /**
@@ -95,6 +121,29 @@ export interface SendMessageRequest {
  */
 export interface SendMessageResponse {
   readonly ok: boolean;
+  readonly messageId?: string;
+}
+
+export interface ReadinessComponent {
+  readonly name: string;
+  readonly weight: number;
+  readonly score: number;
+  readonly status: "ok" | "degraded" | "blocking" | "unknown" | "skipped";
+  readonly reason?: string;
+  readonly remedy?: string;
+}
+
+/**
+ * Weighted readiness score behind the /begin gate.
+ */
+export interface ReadinessReport {
+  readonly score: number;
+  readonly min_score: number;
+  readonly go: boolean;
+  readonly components: readonly ReadinessComponent[];
+  readonly blockers: readonly ReadinessComponent[];
+  readonly explanation: string;
+  readonly checked_at: string;
 }
 
 export interface BeginAutonomousResponse {
@@ -104,6 +153,8 @@ export interface BeginAutonomousResponse {
   readonly message?: string;
   readonly readiness_passed?: boolean;
   readonly failed_checks?: readonly string[];
+  readonly readiness?: ReadinessReport;
+  readonly profile?: string;
 }
 
 export interface PauseAutonomousResponse {
@@ -158,6 +209,9 @@ export interface PortfolioPosition {
   readonly entry_price?: string;
   readonly mark_price?: string;
   readonly unrealized_pnl?: string;
+  readonly leverage?: string;
+  readonly liquidation_price?: string;
+  readonly liquidation_distance_pct?: string;
 }
 
 export interface PortfolioResponse {
@@ -220,6 +274,7 @@ export interface DoctorResponse {
   readonly summary?: string;
   readonly checked_at?: string;
   readonly checks: readonly DoctorCheckResponse[];
+  readonly readiness?: ReadinessReport;
 }
 
 export interface AIModelInfo {
@@ -278,6 +333,328 @@ export interface WebhookUpdateResponse {
   readonly ok: boolean;
 }
 
+/**
+ * Watchlist update actions accepted by the backend.
+ */
+export type WatchlistAction = "add" | "remove" | "exclude" | "include" | "clear";
+
+/**
+ * A chat's watchlist and the symbols it currently trades.
+ * Returned by GET/POST /api/v1/telegram/internal/watchlist
+ */
+export interface WatchlistResponse {
+  readonly status: string;
+  readonly data: {
+    readonly watchlist: {
+      readonly chat_id: string;
+      readonly symbols: readonly string[];
+      readonly excluded: readonly string[];
+      readonly updated_at: string;
+    };
+    readonly active_symbols: readonly string[];
+    readonly uses_universe: boolean;
+  };
+}
+
+/**
+ * Capital allocation update actions accepted by the backend.
+ */
+export type AllocationAction = "set" | "rebalance" | "clear";
+
+/**
+ * A chat's capital split between strategies.
+ * Returned by GET/POST /api/v1/telegram/internal/allocation
+ */
+export interface AllocationResponse {
+  readonly status: string;
+  readonly data: {
+    readonly allocation: {
+      readonly chat_id: string;
+      readonly targets: Readonly<Record<string, number>>;
+      readonly weights: Readonly<Record<string, number>>;
+      readonly performance_weighted: boolean;
+      readonly results: Readonly<
+        Record<
+          string,
+          { readonly pnl: string; readonly trades: number; readonly wins: number }
+        >
+      >;
+      readonly updated_at: string;
+      readonly last_rebalanced_at: string;
+    } | null;
+    readonly enforced: boolean;
+    readonly strategies: readonly string[];
+  };
+}
+
+/**
+ * Risk limits, position sizing, scan frequency and AI usage autonomous
+ * trading runs with. Percentages are in percent, e.g. 2 for 2%.
+ */
+export interface OperatingProfile {
+  readonly name: string;
+  readonly description?: string;
+  readonly built_in: boolean;
+  readonly daily_loss_pct: number;
+  readonly min_confidence: number;
+  readonly max_position_pct: number;
+  readonly scan_interval_seconds: number;
+  readonly ai_usage: "low" | "standard" | "high";
+}
+
+/**
+ * The profiles a chat can select; active is empty while the server
+ * defaults apply.
+ * Returned by GET /api/v1/telegram/internal/profiles
+ */
+export interface ProfileListResponse {
+  readonly status: string;
+  readonly data: {
+    readonly active: string;
+    readonly profiles: readonly OperatingProfile[];
+  };
+}
+
+/**
+ * Returned when a profile is selected or saved.
+ */
+export interface OperatingProfileResponse {
+  readonly status: string;
+  readonly data: OperatingProfile;
+}
+
+/**
+ * A chat's consecutive-loss streaks and strategy throttles.
+ * Returned by GET /api/v1/telegram/internal/risk/loss-streaks
+ */
+export interface LossStreaksResponse {
+  readonly status: string;
+  readonly data: {
+    readonly state: {
+      readonly chat_id: string;
+      readonly strategies: Readonly<
+        Record<
+          string,
+          {
+            readonly consecutive_losses: number;
+            readonly action?: "reduce" | "pause";
+            readonly multiplier: number;
+            readonly throttled_until?: string;
+          }
+        >
+      >;
+      readonly triggers: readonly {
+        readonly strategy: string;
+        readonly losses: number;
+        readonly action: "reduce" | "pause";
+        readonly multiplier: number;
+        readonly until: string;
+        readonly at: string;
+      }[];
+    };
+    readonly max_losses: number;
+    readonly action: "reduce" | "pause";
+    readonly size_reduction: number;
+    readonly cooldown: string;
+  };
+}
+
+/**
+ * Symbols blocked from re-entry after a recent exit.
+ * Returned by GET /api/v1/telegram/internal/risk/cooldowns
+ */
+export interface SymbolCooldownsResponse {
+  readonly status: string;
+  readonly data: {
+    readonly cooldowns: readonly {
+      readonly exchange: string;
+      readonly symbol: string;
+      readonly reason: "stop_out" | "loss" | "exit" | "liquidation";
+      readonly pnl: string;
+      readonly until: string;
+      readonly at: string;
+    }[];
+    readonly after_loss: string;
+    readonly after_exit: string;
+  };
+}
+
+/**
+ * A suggested perpetual hedge against a cluster of correlated positions.
+ */
+export interface HedgeSuggestion {
+  readonly id: string;
+  readonly cluster: {
+    readonly exchange: string;
+    readonly symbols: readonly string[];
+    readonly direction: "long" | "short";
+    readonly exposure: string;
+    readonly avg_correlation: number;
+  };
+  readonly exchange: string;
+  readonly symbol: string;
+  readonly side: "BUY" | "SELL";
+  readonly amount: string;
+  readonly notional: string;
+  readonly reason: string;
+  readonly expires_at: string;
+}
+
+/**
+ * Hedge suggestions for the open positions.
+ * Returned by GET /api/v1/telegram/internal/hedge
+ */
+export interface HedgeSuggestionsResponse {
+  readonly status: string;
+  readonly data: {
+    readonly positions: number;
+    readonly suggestions: readonly HedgeSuggestion[];
+    readonly notes?: readonly string[];
+    readonly generated_at: string;
+  };
+}
+
+/**
+ * A confirmed hedge and its order.
+ * Returned by POST /api/v1/telegram/internal/hedge
+ */
+export interface ConfirmHedgeResponse {
+  readonly status: string;
+  readonly data: {
+    readonly suggestion: HedgeSuggestion;
+    readonly order_id: string;
+  };
+}
+
+/**
+ * A PnL report over a period with its formatted message.
+ * Returned by GET /api/v1/telegram/internal/pnl
+ */
+export interface PnLReportResponse {
+  readonly status: string;
+  readonly data: {
+    readonly report: {
+      readonly period: string;
+      readonly trades: number;
+      readonly wins: number;
+      readonly losses: number;
+      readonly realized: string;
+      readonly fees: string;
+      readonly unrealized: string;
+      readonly net: string;
+      readonly equity: string;
+      readonly equity_change_pct: number;
+    };
+    readonly text: string;
+  };
+}
+
+/**
+ * Ranked matches across trades, signals, quests and exchange error logs.
+ * Returned by GET /api/v1/search
+ */
+export interface SearchResponse {
+  readonly status: string;
+  readonly data: {
+    readonly query: string;
+    readonly results: ReadonlyArray<{
+      readonly kind: "trade" | "signal" | "quest" | "log";
+      readonly id: string;
+      readonly title: string;
+      readonly snippet?: string;
+      readonly matched: readonly string[];
+      readonly score: number;
+      readonly at: string;
+    }>;
+  };
+}
+
+/**
+ * Funding rate or basis distribution, in percent.
+ */
+export interface CarryDistribution {
+  readonly latest: number;
+  readonly mean: number;
+  readonly median: number;
+  readonly p10: number;
+  readonly p90: number;
+  readonly min: number;
+  readonly max: number;
+}
+
+/**
+ * Historical funding and spot-perp basis statistics per exchange and symbol,
+ * best annualized carry first.
+ * Returned by GET /api/v1/arbitrage/carry
+ */
+export interface CarryReportResponse {
+  readonly status: string;
+  readonly data: {
+    readonly days: number;
+    readonly from: string;
+    readonly generated_at: string;
+    readonly markets: ReadonlyArray<{
+      readonly exchange: string;
+      readonly symbol: string;
+      readonly samples: number;
+      readonly from: string;
+      readonly to: string;
+      readonly interval_hours: number;
+      readonly funding: CarryDistribution;
+      readonly positive_pct: number;
+      readonly annualized_carry_pct: number;
+      readonly basis?: CarryDistribution;
+    }>;
+  };
+}
+
+/**
+ * An expiring link to a read-only performance page.
+ * Returned by POST /api/v1/telegram/internal/share-links
+ */
+export interface ShareLinkResponse {
+  readonly status: string;
+  readonly data: {
+    readonly link: {
+      readonly id: string;
+      readonly period: string;
+      readonly show_absolute: boolean;
+      readonly expires_at: string;
+    };
+    readonly url: string;
+  };
+}
+
+/**
+ * A rendered candle chart with the positions and signals annotated on it.
+ * Returned by GET /api/v1/telegram/internal/chart
+ */
+export interface ChartResponse {
+  readonly status: string;
+  readonly data: {
+    readonly symbol: string;
+    readonly exchange: string;
+    readonly timeframe: string;
+    readonly candles: number;
+    readonly last_price: number;
+    readonly mime_type: string;
+    /** Base64-encoded image */
+    readonly image: string;
+    readonly indicators: Readonly<Record<string, unknown>>;
+    readonly positions: readonly {
+      readonly side: string;
+      readonly size: number;
+      readonly entry_price: number;
+    }[];
+    readonly signals: readonly {
+      readonly action: string;
+      readonly type: string;
+      readonly confidence: number;
+      readonly created_at: string;
+    }[];
+  };
+}
+
 /**
  * API endpoint paths for backend communication.
  */
@@ -293,6 +670,7 @@ export const API_ENDPOINTS = {
     `/api/v1/arbitrage/opportunities?limit=${limit}&min_profit=${minProfit}`,
   BEGIN_AUTONOMOUS: "/api/v1/telegram/internal/autonomous/begin",
   PAUSE_AUTONOMOUS: "/api/v1/telegram/internal/autonomous/pause",
+  RESUME_AUTONOMOUS: "/internal/telegram/autonomous/resume",
   GET_SUMMARY: (chatId: string, timeframe = "24h") =>
     `/api/v1/telegram/internal/performance/summary?chat_id=${encodeURIComponent(chatId)}&timeframe=${encodeURIComponent(timeframe)}`,
   GET_PERFORMANCE: (chatId: string, timeframe = "24h") =>
@@ -313,42 +691,130 @@ export const API_ENDPOINTS = {
     `/api/v1/telegram/internal/logs?chat_id=${encodeURIComponent(chatId)}&limit=${limit}`,
   GET_DOCTOR: (chatId: string) =>
     `/api/v1/telegram/internal/doctor?chat_id=${encodeURIComponent(chatId)}`,
+  NOTIFICATION_CALLBACK: "/api/v1/telegram/internal/callbacks",
+  GET_WATCHLIST: (chatId: string) =>
+    `/api/v1/telegram/internal/watchlist?chat_id=${encodeURIComponent(chatId)}`,
+  UPDATE_WATCHLIST: "/api/v1/telegram/internal/watchlist",
+  GET_ALLOCATION: (chatId: string) =>
+    `/api/v1/telegram/internal/allocation?chat_id=${encodeURIComponent(chatId)}`,
+  UPDATE_ALLOCATION: "/api/v1/telegram/internal/allocation",
+  GET_PROFILES: (chatId: string) =>
+    `/api/v1/telegram/internal/profiles?chat_id=${encodeURIComponent(chatId)}`,
+  SAVE_PROFILE: "/api/v1/telegram/internal/profiles",
+  SELECT_PROFILE: "/api/v1/telegram/internal/profiles/select",
+  DELETE_PROFILE: (name: string, chatId: string) =>
+    `/api/v1/telegram/internal/profiles/${encodeURIComponent(name)}?chat_id=${encodeURIComponent(chatId)}`,
+  GET_LOSS_STREAKS: (chatId: string) =>
+    `/api/v1/telegram/internal/risk/loss-streaks?chat_id=${encodeURIComponent(chatId)}`,
+  GET_SYMBOL_COOLDOWNS: "/api/v1/telegram/internal/risk/cooldowns",
+  GET_HEDGE_SUGGESTIONS: (chatId: string) =>
+    `/api/v1/telegram/internal/hedge?chat_id=${encodeURIComponent(chatId)}`,
+  CONFIRM_HEDGE: "/api/v1/telegram/internal/hedge",
+  GET_PNL: (chatId: string, period: string) =>
+    `/api/v1/telegram/internal/pnl?chat_id=${encodeURIComponent(chatId)}&period=${encodeURIComponent(period)}`,
+  CREATE_SHARE_LINK: "/api/v1/telegram/internal/share-links",
+  SEARCH: (query: string, limit: number) =>
+    `/api/v1/search?q=${encodeURIComponent(query)}&limit=${limit}`,
+  GET_CARRY: (symbol: string, days: number, limit: number) =>
+    `/api/v1/arbitrage/carry?symbol=${encodeURIComponent(symbol)}&days=${days}&limit=${limit}`,
+  GET_CHART: (symbol: string, timeframe: string) =>
+    `/api/v1/telegram/internal/chart?symbol=${encodeURIComponent(symbol)}&timeframe=${encodeURIComponent(timeframe)}`,
   GET_AI_MODELS: "/api/v1/ai/models",
   SELECT_AI_MODEL: (userId: string) =>
     `/api/v1/ai/select/${encodeURIComponent(userId)}`,
   GET_AI_STATUS: (userId: string) =>
     `/api/v1/ai/status/${encodeURIComponent(userId)}`,
   ROUTE_AI_MODEL: "/api/v1/ai/route",
-  GET_ALERTS: (userId: string) =>
-    `/api/v1/alerts?user_id=${encodeURIComponent(userId)}`,
-  CREATE_ALERT: "/api/v1/alerts",
+  GET_ALERTS: (chatId: string) =>
+    `/api/v1/telegram/internal/alerts/custom?chat_id=${encodeURIComponent(chatId)}`,
+  CREATE_ALERT: "/api/v1/telegram/internal/alerts/custom",
+  PREVIEW_ALERT: "/api/v1/telegram/internal/alerts/custom/preview",
   UPDATE_ALERT: (alertId: string) =>
-    `/api/v1/alerts/${encodeURIComponent(alertId)}`,
-  DELETE_ALERT: (alertId: string) =>
-    `/api/v1/alerts/${encodeURIComponent(alertId)}`,
+    `/api/v1/telegram/internal/alerts/custom/${encodeURIComponent(alertId)}`,
+  DELETE_ALERT: (alertId: string, chatId: string) =>
+    `/api/v1/telegram/internal/alerts/custom/${encodeURIComponent(alertId)}?chat_id=${encodeURIComponent(chatId)}`,
+  COMPAT: "/api/v1/compat",
 } as const;
 
-export interface UserAlert {
+export interface ClientCompat {
+  readonly name: string;
+  readonly version: string;
+  readonly min_version?: string;
+  readonly compatible: boolean;
+}
+
+export interface CompatResponse {
+  readonly server_version: string;
+  readonly api_version: string;
+  readonly min_client_versions: Record<string, string>;
+  readonly client?: ClientCompat;
+}
+
+export interface AlertOperand {
+  readonly indicator:
+    | "price"
+    | "rsi"
+    | "sma"
+    | "ema"
+    | "funding"
+    | "spread"
+    | "value";
+  readonly period?: number;
+  readonly timeframe?: string;
+  readonly exchange?: string;
+  readonly value?: number;
+}
+
+export interface AlertCondition {
+  readonly logic?: "and" | "or";
+  readonly terms?: readonly AlertCondition[];
+  readonly left?: AlertOperand;
+  readonly operator?: string;
+  readonly right?: AlertOperand;
+}
+
+export interface AlertRule {
+  readonly symbol: string;
+  readonly exchange?: string;
+  readonly condition: AlertCondition;
+}
+
+export interface CustomAlert {
   readonly id: string;
-  readonly user_id: string;
-  readonly alert_type: string;
-  readonly conditions: Record<string, unknown>;
+  readonly rule: AlertRule;
+  readonly expression: string;
   readonly is_active: boolean;
   readonly created_at: string;
+  readonly last_triggered_at?: string;
+  readonly last_value?: number;
 }
 
 export interface GetAlertsResponse {
   readonly status: string;
-  readonly data: readonly UserAlert[];
+  readonly data: {
+    readonly alerts: readonly CustomAlert[];
+  };
 }
 
-export interface CreateAlertRequest {
-  readonly alert_type: string;
-  readonly conditions: Record<string, unknown>;
+export interface CreateAlertResponse {
+  readonly status: string;
+  readonly data: CustomAlert;
 }
 
-export interface CreateAlertResponse {
+export interface AlertPreview {
+  readonly expression: string;
+  readonly exchange: string;
+  readonly resolution: string;
+  readonly from: string;
+  readonly to: string;
+  readonly evaluations: number;
+  readonly triggers: number;
+  readonly triggers_per_day: number;
+  readonly recent_triggers: readonly string[];
+  readonly notes?: readonly string[];
+}
+
+export interface PreviewAlertResponse {
   readonly status: string;
-  readonly message: string;
-  readonly data: UserAlert;
+  readonly data: AlertPreview;
 }
@@ -0,0 +1,37 @@
+import type { Bot } from "grammy";
+import { ApiClientError, type BackendApiClient } from "../api/client";
+
+// Callback data issued by the backend for opportunity notification buttons.
+const NOTIFICATION_ACTION_PATTERN = /^na:/;
+
+export function registerNotificationActions(
+  bot: Bot,
+  api: BackendApiClient,
+): void {
+  bot.callbackQuery(NOTIFICATION_ACTION_PATTERN, async (ctx) => {
+    const chatId = ctx.chat?.id;
+    const data = ctx.callbackQuery.data;
+    if (!chatId || !data) {
+      await ctx.answerCallbackQuery({ text: "Unable to process action." });
+      return;
+    }
+
+    try {
+      const response = await api.handleNotificationCallback(
+        String(chatId),
+        data,
+      );
+      await ctx.answerCallbackQuery({ text: response.data.toast });
+      await ctx.reply(response.data.text, {
+        parse_mode: "Markdown",
+        reply_markup: response.data.reply_markup,
+      });
+    } catch (error) {
+      const message =
+        error instanceof ApiClientError
+          ? error.message
+          : "Action failed. Please try again.";
+      await ctx.answerCallbackQuery({ text: message, show_alert: true });
+    }
+  });
+}
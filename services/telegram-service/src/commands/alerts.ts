@@ -1,106 +1,149 @@
 import type { Bot } from "grammy";
-import type { BackendApiClient } from "../api/client";
+import { ApiClientError, type BackendApiClient } from "../api/client";
+import type { AlertPreview, CustomAlert } from "../api/types";
+
+const ALERT_USAGE =
+  "*Create an alert:*\n" +
+  "/alert\\_add BTC/USDT price crosses 70000\n" +
+  "/alert\\_add ETH/USDT RSI(14,1h) < 30\n" +
+  "/alert\\_add SOL/USDT on okx price crosses above EMA(50,4h) AND funding < 0\n\n" +
+  "Operands: price, RSI, SMA, EMA, funding (%), spread(exchange) (%), numbers\n" +
+  "Operators: <, <=, >, >=, crosses, crosses above, crosses below\n" +
+  "Combine with AND, OR and parentheses\n\n" +
+  "/alert\\_preview [rule] - how often it fired in 30 days\n" +
+  "/alert\\_toggle [id] - pause or resume\n" +
+  "/alert\\_del [id] - delete";
+
+function escapeMarkdown(text: string): string {
+  return text.replace(/([_*`[])/g, "\\$1");
+}
+
+export function formatAlerts(alerts: readonly CustomAlert[]): string {
+  if (alerts.length === 0) {
+    return "🔔 *Your Alerts*\n\nNo alerts configured yet.\n\n" + ALERT_USAGE;
+  }
+
+  const alertList = alerts
+    .map((alert, i) => {
+      const status = alert.is_active ? "✅" : "⏸️";
+      const triggered = alert.last_triggered_at
+        ? `\n   Last triggered: ${new Date(alert.last_triggered_at).toUTCString()}`
+        : "";
+      return (
+        `${i + 1}. ${status} ${escapeMarkdown(alert.expression)}\n` +
+        `   ID: \`${alert.id.slice(0, 8)}\`${triggered}`
+      );
+    })
+    .join("\n\n");
 
-const ALERT_TYPES = [
-  { type: "arbitrage", label: "Arbitrage Opportunities", emoji: "📈" },
-  { type: "technical", label: "Technical Analysis", emoji: "📊" },
-  { type: "funding", label: "Funding Rate Changes", emoji: "💰" },
-  { type: "price", label: "Price Alerts", emoji: "🔔" },
-  { type: "risk", label: "Risk Events", emoji: "⚠️" },
-];
+  return `🔔 *Your Alerts* (${alerts.length})\n\n${alertList}\n\n${ALERT_USAGE}`;
+}
+
+export function formatPreview(preview: AlertPreview): string {
+  const recent = preview.recent_triggers
+    .slice(-5)
+    .map((at) => `• ${new Date(at).toUTCString()}`)
+    .join("\n");
+  const notes = (preview.notes ?? []).map((n) => `ℹ️ ${n}`).join("\n");
+
+  let msg =
+    `🔎 *Alert Preview* (${preview.exchange}, last 30 days)\n\n` +
+    `${escapeMarkdown(preview.expression)}\n\n` +
+    `Would have fired *${preview.triggers}* times ` +
+    `(${preview.triggers_per_day.toFixed(2)}/day) over ${preview.evaluations} ${preview.resolution} steps.`;
+  if (recent) {
+    msg += `\n\nMost recent:\n${recent}`;
+  }
+  if (notes) {
+    msg += `\n\n${escapeMarkdown(notes)}`;
+  }
+  return msg;
+}
+
+function errorMessage(error: unknown, fallback: string): string {
+  return error instanceof ApiClientError ? error.message : fallback;
+}
+
+async function findAlert(
+  api: BackendApiClient,
+  chatId: string,
+  prefix: string,
+): Promise<CustomAlert | undefined> {
+  const response = await api.getUserAlerts(chatId);
+  return response.data.alerts.find((a) => a.id.startsWith(prefix));
+}
 
 export function registerAlertsCommands(bot: Bot, api: BackendApiClient): void {
   bot.command("alerts", async (ctx) => {
-    const userId = String(ctx.from?.id);
-    if (!userId) {
+    const chatId = ctx.chat?.id;
+    if (!chatId) {
       await ctx.reply("Unable to fetch alerts.");
       return;
     }
 
     try {
-      const response = await api.getUserAlerts(userId);
-
-      if (!response.data || response.data.length === 0) {
-        const msg =
-          "🔔 *Your Alerts*\n\n" +
-          "No alerts configured yet.\n\n" +
-          "*Available Alert Types:*\n" +
-          ALERT_TYPES.map((a) => `${a.emoji} ${a.label}`).join("\n") +
-          "\n\n*To create an alert:*\n" +
-          "/alert_add arbitrage 1.0\n" +
-          "(Creates arbitrage alert with 1% min profit)\n\n" +
-          "Use /help for more commands.";
-
-        await ctx.reply(msg, { parse_mode: "Markdown" });
-        return;
-      }
-
-      const alertList = response.data
-        .map((alert, i) => {
-          const typeInfo = ALERT_TYPES.find((t) => t.type === alert.alert_type);
-          const emoji = typeInfo?.emoji || "🔔";
-          const status = alert.is_active ? "✅" : "❌";
-          return `${i + 1}. ${emoji} *${typeInfo?.label || alert.alert_type}*\n   Status: ${status}\n   ID: \`${alert.id.slice(0, 8)}\``;
-        })
-        .join("\n\n");
-
-      const msg =
-        `🔔 *Your Alerts* (${response.data.length})\n\n` +
-        alertList +
-        "\n\n*Commands:*\n" +
-        "/alert_add [type] [min_profit]\n" +
-        "/alert_toggle [id]\n" +
-        "/alert_del [id]";
-
-      await ctx.reply(msg, { parse_mode: "Markdown" });
-    } catch {
-      await ctx.reply("Unable to fetch alerts. Please try again.");
+      const response = await api.getUserAlerts(String(chatId));
+      await ctx.reply(formatAlerts(response.data.alerts), {
+        parse_mode: "Markdown",
+      });
+    } catch (error) {
+      await ctx.reply(
+        errorMessage(error, "Unable to fetch alerts. Please try again."),
+      );
     }
   });
 
   bot.command("alert_add", async (ctx) => {
-    const userId = String(ctx.from?.id);
-    if (!userId) {
+    const chatId = ctx.chat?.id;
+    if (!chatId) {
       await ctx.reply("Unable to create alert.");
       return;
     }
 
-    const args = ctx.message?.text.split(" ").slice(1) || [];
-    let alertType = args[0]?.toLowerCase() || "arbitrage";
-    const minProfit = parseFloat(args[1]) || 1.0;
-
-    if (!ALERT_TYPES.some((t) => t.type === alertType)) {
-      const available = ALERT_TYPES.map((t) => t.type).join(", ");
-      await ctx.reply(
-        `Invalid alert type: ${alertType}\n\nAvailable: ${available}`,
-      );
+    const rule = ctx.match?.toString().trim() ?? "";
+    if (!rule) {
+      await ctx.reply(ALERT_USAGE, { parse_mode: "Markdown" });
       return;
     }
 
     try {
-      const response = await api.createAlert(userId, alertType, {
-        min_profit: minProfit,
-      });
-
-      const typeInfo = ALERT_TYPES.find((t) => t.type === alertType);
+      const response = await api.createAlert(String(chatId), rule);
       const msg =
         `✅ *Alert Created*\n\n` +
-        `Type: ${typeInfo?.emoji || "🔔"} ${typeInfo?.label || alertType}\n` +
-        `Min Profit: ${minProfit}%\n` +
+        `${escapeMarkdown(response.data.expression)}\n` +
         `ID: \`${response.data.id.slice(0, 8)}\`\n\n` +
-        `Use /alerts to view all your alerts.`;
+        `You will be notified when it triggers. Use /alerts to view all your alerts.`;
 
       await ctx.reply(msg, { parse_mode: "Markdown" });
-    } catch {
-      await ctx.reply("Failed to create alert. Please try again.");
+    } catch (error) {
+      await ctx.reply(
+        errorMessage(error, "Failed to create alert. Please try again."),
+      );
+    }
+  });
+
+  bot.command("alert_preview", async (ctx) => {
+    const rule = ctx.match?.toString().trim() ?? "";
+    if (!rule) {
+      await ctx.reply(ALERT_USAGE, { parse_mode: "Markdown" });
+      return;
+    }
+
+    try {
+      const response = await api.previewAlert(rule);
+      await ctx.reply(formatPreview(response.data), { parse_mode: "Markdown" });
+    } catch (error) {
+      await ctx.reply(
+        errorMessage(error, "Failed to preview alert. Please try again."),
+      );
     }
   });
 
   bot.command("alert_toggle", async (ctx) => {
-    const args = ctx.message?.text.split(" ").slice(1) || [];
-    const alertId = args[0];
+    const chatId = ctx.chat?.id;
+    const alertId = ctx.message?.text.split(/\s+/)[1];
 
-    if (!alertId) {
+    if (!chatId || !alertId) {
       await ctx.reply(
         "Usage: /alert_toggle [alert_id]\n\nUse /alerts to see IDs.",
       );
@@ -108,28 +151,28 @@ export function registerAlertsCommands(bot: Bot, api: BackendApiClient): void {
     }
 
     try {
-      const response = await api.getUserAlerts(String(ctx.from?.id));
-      const alert = response.data.find((a) => a.id.startsWith(alertId));
-
+      const alert = await findAlert(api, String(chatId), alertId);
       if (!alert) {
         await ctx.reply("Alert not found. Use /alerts to see your alerts.");
         return;
       }
 
-      await api.updateAlert(alert.id, !alert.is_active);
-      const status = !alert.is_active ? "enabled" : "disabled";
+      await api.updateAlert(String(chatId), alert.id, !alert.is_active);
+      const status = !alert.is_active ? "resumed" : "paused";
 
-      await ctx.reply(`✅ Alert ${status}.`);
-    } catch {
-      await ctx.reply("Failed to update alert. Please try again.");
+      await ctx.reply(`✅ Alert ${status}: ${alert.expression}`);
+    } catch (error) {
+      await ctx.reply(
+        errorMessage(error, "Failed to update alert. Please try again."),
+      );
     }
   });
 
   bot.command("alert_del", async (ctx) => {
-    const args = ctx.message?.text.split(" ").slice(1) || [];
-    const alertId = args[0];
+    const chatId = ctx.chat?.id;
+    const alertId = ctx.message?.text.split(/\s+/)[1];
 
-    if (!alertId) {
+    if (!chatId || !alertId) {
       await ctx.reply(
         "Usage: /alert_del [alert_id]\n\nUse /alerts to see IDs.",
       );
@@ -137,19 +180,19 @@ export function registerAlertsCommands(bot: Bot, api: BackendApiClient): void {
     }
 
     try {
-      const response = await api.getUserAlerts(String(ctx.from?.id));
-      const alert = response.data.find((a) => a.id.startsWith(alertId));
-
+      const alert = await findAlert(api, String(chatId), alertId);
       if (!alert) {
         await ctx.reply("Alert not found. Use /alerts to see your alerts.");
         return;
       }
 
-      await api.deleteAlert(alert.id);
+      await api.deleteAlert(String(chatId), alert.id);
 
-      await ctx.reply("✅ Alert deleted.");
-    } catch {
-      await ctx.reply("Failed to delete alert. Please try again.");
+      await ctx.reply(`✅ Alert deleted: ${alert.expression}`);
+    } catch (error) {
+      await ctx.reply(
+        errorMessage(error, "Failed to delete alert. Please try again."),
+      );
     }
   });
 }
@@ -0,0 +1,138 @@
+import type { Bot } from "grammy";
+import { ApiClientError, type BackendApiClient } from "../api/client";
+import type { AllocationResponse } from "../api/types";
+
+const ALLOCATION_USAGE =
+  "*Commands:*\n" +
+  "/allocation set scalping=60 funding\\_arbitrage=30 [auto]\n" +
+  "/allocation rebalance\n" +
+  "/allocation clear\n\n" +
+  "Unallocated capital stays in reserve. Add `auto` to shift weights " +
+  "towards the best performing strategies at each scheduled rebalance.";
+
+function formatPercent(fraction: number | undefined): string {
+  return `${((fraction ?? 0) * 100).toFixed(1)}%`;
+}
+
+function escapeMarkdown(text: string): string {
+  return text.replace(/_/g, "\\_");
+}
+
+/**
+ * Parses "strategy=60" pairs into fractions. Values are percentages; a
+ * trailing % is accepted.
+ */
+export function parseAllocationTargets(
+  args: readonly string[],
+): Record<string, number> | string {
+  const targets: Record<string, number> = {};
+  for (const arg of args) {
+    const [strategy, raw] = arg.split("=");
+    const percent = Number((raw ?? "").replace(/%$/, ""));
+    if (!strategy || raw === undefined || !Number.isFinite(percent)) {
+      return `Invalid allocation: ${arg} (use strategy=percent)`;
+    }
+    targets[strategy.toLowerCase()] = percent / 100;
+  }
+  if (Object.keys(targets).length === 0) {
+    return "Usage: /allocation set scalping=60 funding_arbitrage=30 [auto]";
+  }
+  return targets;
+}
+
+export function formatAllocation(response: AllocationResponse): string {
+  const { allocation, strategies } = response.data;
+  if (!allocation) {
+    return (
+      "💰 *Capital Allocation*\n\n" +
+      "No allocation set: every strategy may use all capital.\n" +
+      `Strategies: ${escapeMarkdown(strategies.join(", "))}\n\n` +
+      ALLOCATION_USAGE
+    );
+  }
+
+  const lines = strategies
+    .filter((strategy) => allocation.targets[strategy] !== undefined)
+    .map((strategy) => {
+      const target = allocation.targets[strategy];
+      const weight = allocation.weights[strategy];
+      const shifted =
+        weight !== undefined && Math.abs(weight - target) > 1e-6
+          ? ` → ${formatPercent(weight)}`
+          : "";
+      const results = allocation.results[strategy];
+      const pnl = results ? ` (PnL ${results.pnl}, ${results.trades} trades)` : "";
+      return `${escapeMarkdown(strategy)}: ${formatPercent(target)}${shifted}${pnl}`;
+    });
+
+  const mode = allocation.performance_weighted
+    ? "Performance-weighted rebalancing: on"
+    : "Performance-weighted rebalancing: off";
+
+  return (
+    "💰 *Capital Allocation*\n\n" +
+    `${lines.join("\n")}\n\n` +
+    `${mode}\n` +
+    `Last rebalance: ${new Date(allocation.last_rebalanced_at).toUTCString()}\n\n` +
+    ALLOCATION_USAGE
+  );
+}
+
+export function registerAllocationCommand(
+  bot: Bot,
+  api: BackendApiClient,
+): void {
+  bot.command("allocation", async (ctx) => {
+    const chatId = ctx.chat?.id;
+    if (!chatId) {
+      await ctx.reply("Unable to load your capital allocation.");
+      return;
+    }
+
+    const args = ctx.message?.text.split(/\s+/).slice(1) || [];
+    const action = args[0]?.toLowerCase();
+
+    try {
+      let response: AllocationResponse;
+      switch (action) {
+        case undefined:
+          response = await api.getAllocation(String(chatId));
+          break;
+        case "set": {
+          const rest = args.slice(1);
+          const auto = rest.some((arg) => arg.toLowerCase() === "auto");
+          const targets = parseAllocationTargets(
+            rest.filter((arg) => arg.toLowerCase() !== "auto"),
+          );
+          if (typeof targets === "string") {
+            await ctx.reply(targets);
+            return;
+          }
+          response = await api.updateAllocation(
+            String(chatId),
+            "set",
+            targets,
+            auto,
+          );
+          break;
+        }
+        case "rebalance":
+        case "clear":
+          response = await api.updateAllocation(String(chatId), action);
+          break;
+        default:
+          await ctx.reply(`Unknown action: ${action}\n\n${ALLOCATION_USAGE}`, {
+            parse_mode: "Markdown",
+          });
+          return;
+      }
+      await ctx.reply(formatAllocation(response), { parse_mode: "Markdown" });
+    } catch (error) {
+      const message =
+        error instanceof ApiClientError
+          ? error.message
+          : "Unable to update your capital allocation. Please try again.";
+      await ctx.reply(message);
+    }
+  });
+}
@@ -1,8 +1,21 @@
 import type { Bot } from "grammy";
 import type { BackendApiClient } from "../../api/client";
+import type { BeginAutonomousResponse } from "../../api/types";
 import type { SessionManager } from "../../session";
 import { getChatId, persistChatIdToLocalConfig } from "./helpers";
 
+// formatReadinessFailure explains a failed readiness gate, preferring the
+// scored explanation with its fixes over the bare list of failed checks.
+function formatReadinessFailure(response: BeginAutonomousResponse): string {
+  if (response.readiness?.explanation) {
+    return `\n\n${response.readiness.explanation}`;
+  }
+  const failedChecks = response.failed_checks ?? [];
+  return failedChecks.length > 0
+    ? `\n\nFailed checks:\n- ${failedChecks.join("\n- ")}`
+    : "";
+}
+
 export function registerAutonomousCommands(
   bot: Bot,
   api: BackendApiClient,
@@ -20,26 +33,27 @@ export function registerAutonomousCommands(
     // Persist chat ownership as soon as command is received, even if readiness fails.
     await persistChatIdToLocalConfig(chatId);
 
+    // An optional argument selects the operating profile, e.g. /begin conservative
+    const profile = ctx.message?.text?.split(/\s+/)[1]?.toLowerCase();
+
     try {
-      const response = await api.beginAutonomous(chatId);
+      const response = await api.beginAutonomous(chatId, profile);
 
       if (response.readiness_passed === false) {
-        const failedChecks = response.failed_checks ?? [];
-        const checksText =
-          failedChecks.length > 0
-            ? `\n\nFailed checks:\n- ${failedChecks.join("\n- ")}`
-            : "";
-
         await ctx.reply(
-          `⚠️ Readiness gate blocked autonomous mode.${checksText}\n\nRun /doctor for guided diagnostics.`,
+          `⚠️ Readiness gate blocked autonomous mode.${formatReadinessFailure(response)}\n\nRun /doctor for guided diagnostics.`,
         );
         return;
       }
 
       sessions.setSession(chatId, { step: "idle", data: {} });
-      await ctx.reply(
+      const started =
         response.message ||
-          "✅ Autonomous mode started. Use /pause to stop and /summary for 24h results.",
+        "✅ Autonomous mode started. Use /pause to stop and /summary for 24h results.";
+      await ctx.reply(
+        response.profile
+          ? `${started}\nOperating profile: ${response.profile}`
+          : started,
       );
     } catch (error) {
       await ctx.reply(
@@ -48,6 +62,33 @@ export function registerAutonomousCommands(
     }
   });
 
+  bot.command("resume", async (ctx) => {
+    const chatId = getChatId(ctx);
+    if (!chatId) {
+      await ctx.reply(
+        "Unable to resume autonomous mode: missing chat information.",
+      );
+      return;
+    }
+
+    try {
+      const response = await api.resumeAutonomous(chatId);
+
+      if (response.readiness_passed === false) {
+        await ctx.reply(
+          `⚠️ Trading stays paused: readiness checks failed.${formatReadinessFailure(response)}\n\nRun /doctor for guided diagnostics.`,
+        );
+        return;
+      }
+
+      await ctx.reply(response.message || "▶️ Autonomous trading resumed.");
+    } catch (error) {
+      await ctx.reply(
+        `❌ Failed to resume autonomous mode (${(error as Error).message}).`,
+      );
+    }
+  });
+
   bot.command("pause", async (ctx) => {
     const chatId = getChatId(ctx);
     if (!chatId) {
@@ -108,6 +108,99 @@ describe("BD command handlers", () => {
     expect(cfg.services?.telegram?.chat_id).toBe("777");
   });
 
+  test("/begin explains the readiness score when blocked", async () => {
+    const bot = new MockBot();
+    const sessions = new SessionManager();
+    const explanation =
+      "No-go: readiness 40/100 is below the minimum of 70. Top blockers:\n1. Risk budget: the daily loss cap of 50.00 USDT was hit (PnL -60.00 USDT). Fix: new entries resume at the next UTC day; review today's trades with /pnl.";
+    const api = {
+      async beginAutonomous() {
+        return {
+          ok: false,
+          readiness_passed: false,
+          failed_checks: ["risk_budget"],
+          readiness: { score: 40, min_score: 70, go: false, explanation },
+        };
+      },
+    };
+
+    registerAutonomousCommands(
+      bot as unknown as Bot,
+      api as unknown as never,
+      sessions,
+    );
+
+    const ctx = createContext("/begin");
+    await runCommand(bot, "begin", ctx);
+
+    expect(ctx.replies).toHaveLength(1);
+    expect(ctx.replies[0]).toContain("readiness 40/100");
+    expect(ctx.replies[0]).toContain("Fix: new entries resume");
+    expect(ctx.replies[0]).not.toContain("Failed checks");
+  });
+
+  test("/begin passes the operating profile argument", async () => {
+    const bot = new MockBot();
+    const sessions = new SessionManager();
+    const calls: Array<[string, string | undefined]> = [];
+    const api = {
+      async beginAutonomous(chatId: string, profile?: string) {
+        calls.push([chatId, profile]);
+        return {
+          ok: true,
+          readiness_passed: true,
+          message: "Autonomous mode started",
+          profile,
+        };
+      },
+    };
+
+    registerAutonomousCommands(
+      bot as unknown as Bot,
+      api as unknown as never,
+      sessions,
+    );
+
+    const ctx = createContext("/begin Conservative");
+    await runCommand(bot, "begin", ctx);
+
+    expect(calls).toEqual([["777", "conservative"]]);
+    expect(ctx.replies[0]).toContain("Operating profile: conservative");
+
+    const plain = createContext("/begin");
+    await runCommand(bot, "begin", plain);
+    expect(calls[1]).toEqual(["777", undefined]);
+    expect(plain.replies[0]).not.toContain("Operating profile");
+  });
+
+  test("/resume keeps trading held when readiness fails", async () => {
+    const bot = new MockBot();
+    const sessions = new SessionManager();
+    const api = {
+      async resumeAutonomous() {
+        return {
+          ok: false,
+          status: "held",
+          readiness_passed: false,
+          failed_checks: ["exchange permissions"],
+        };
+      },
+    };
+
+    registerAutonomousCommands(
+      bot as unknown as Bot,
+      api as unknown as never,
+      sessions,
+    );
+
+    const ctx = createContext("/resume");
+    await runCommand(bot, "resume", ctx);
+
+    expect(ctx.replies).toHaveLength(1);
+    expect(ctx.replies[0]).toContain("Trading stays paused");
+    expect(ctx.replies[0]).toContain("exchange permissions");
+  });
+
   test("/liquidate_all requires confirmation before execution", async () => {
     const bot = new MockBot();
     const sessions = new SessionManager();
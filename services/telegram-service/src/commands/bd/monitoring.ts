@@ -51,6 +51,17 @@ function formatPosition(position: PortfolioPosition): string {
     lines.push(`  Unrealized PnL: ${position.unrealized_pnl}`);
   }
 
+  if (position.leverage) {
+    lines.push(`  Leverage: ${position.leverage}x`);
+  }
+
+  if (position.liquidation_price) {
+    const distance = position.liquidation_distance_pct
+      ? ` (${position.liquidation_distance_pct}% away)`
+      : "";
+    lines.push(`  Liquidation: ${position.liquidation_price}${distance}`);
+  }
+
   return lines.join("\n");
 }
 
@@ -0,0 +1,93 @@
+import type { Bot } from "grammy";
+import { ApiClientError, type BackendApiClient } from "../api/client";
+import type { CarryReportResponse } from "../api/types";
+
+const CARRY_DEFAULT_DAYS = 30;
+const CARRY_LIMIT = 8;
+const CARRY_USAGE =
+  "Usage: /carry [symbol] [days]\n" +
+  "Examples: /carry, /carry BTC/USDT, /carry ETH/USDT 7";
+
+function formatPct(value: number, digits: number): string {
+  return `${value >= 0 ? "+" : ""}${value.toFixed(digits)}%`;
+}
+
+/**
+ * Parses /carry arguments: an optional symbol and an optional window in days,
+ * in either order.
+ */
+export function parseCarryArgs(
+  args: readonly string[],
+): { symbol: string; days: number } | null {
+  let symbol = "";
+  let days = CARRY_DEFAULT_DAYS;
+  for (const arg of args) {
+    if (/^\d+d?$/i.test(arg)) {
+      days = Number.parseInt(arg, 10);
+    } else if (!symbol) {
+      symbol = arg.toUpperCase();
+    } else {
+      return null;
+    }
+  }
+  if (days < 1 || days > 365) {
+    return null;
+  }
+  return { symbol, days };
+}
+
+export function formatCarryReport(data: CarryReportResponse["data"]): string {
+  if (data.markets.length === 0) {
+    return `No funding history in the last ${data.days} days.`;
+  }
+  const lines = [`💰 Carry report (${data.days}d, best first)`, ""];
+  for (const market of data.markets) {
+    const funding = market.funding;
+    lines.push(
+      `${market.symbol} on ${market.exchange}: ${formatPct(market.annualized_carry_pct, 1)}/yr`,
+      `   Funding ${formatPct(funding.mean, 4)} avg, ${formatPct(funding.median, 4)} median ` +
+        `(p10 ${formatPct(funding.p10, 4)}, p90 ${formatPct(funding.p90, 4)}) ` +
+        `per ${market.interval_hours}h`,
+      `   Positive ${market.positive_pct.toFixed(0)}% of ${market.samples} intervals, ` +
+        `latest ${formatPct(funding.latest, 4)}`,
+    );
+    if (market.basis) {
+      lines.push(
+        `   Basis ${formatPct(market.basis.mean, 3)} avg ` +
+          `(p10 ${formatPct(market.basis.p10, 3)}, p90 ${formatPct(market.basis.p90, 3)}), ` +
+          `latest ${formatPct(market.basis.latest, 3)}`,
+      );
+    }
+  }
+  lines.push(
+    "",
+    "Carry is what a short perp hedged with spot earned from funding.",
+  );
+  return lines.join("\n");
+}
+
+export function registerCarryCommand(bot: Bot, api: BackendApiClient): void {
+  bot.command("carry", async (ctx) => {
+    const args = ctx.message?.text.split(/\s+/).slice(1).filter(Boolean) ?? [];
+    const parsed = parseCarryArgs(args);
+    if (!parsed) {
+      await ctx.reply(CARRY_USAGE);
+      return;
+    }
+
+    try {
+      const response = await api.getCarry(
+        parsed.symbol,
+        parsed.days,
+        CARRY_LIMIT,
+      );
+      await ctx.reply(formatCarryReport(response.data));
+    } catch (error) {
+      const message =
+        error instanceof ApiClientError
+          ? error.message
+          : "Unable to load the carry report. Please try again.";
+      await ctx.reply(message);
+    }
+  });
+}
@@ -0,0 +1,83 @@
+import { InputFile, type Bot } from "grammy";
+import { ApiClientError, type BackendApiClient } from "../api/client";
+import type { ChartResponse } from "../api/types";
+
+const CHART_USAGE =
+  "Usage: /chart <symbol> [timeframe]\nExample: /chart BTCUSDT 15m";
+const DEFAULT_TIMEFRAME = "15m";
+
+function escapeMarkdown(text: string): string {
+  return text.replace(/_/g, "\\_");
+}
+
+function formatIndicator(value: unknown): string | null {
+  return typeof value === "number" && Number.isFinite(value)
+    ? value.toFixed(2)
+    : null;
+}
+
+export function formatChartCaption(response: ChartResponse): string {
+  const {
+    symbol,
+    exchange,
+    timeframe,
+    last_price,
+    indicators,
+    positions,
+    signals,
+  } = response.data;
+  const lines = [
+    `📈 *${escapeMarkdown(symbol)}* ${timeframe} (${escapeMarkdown(exchange)})`,
+    `Last: ${last_price}`,
+  ];
+
+  const studies = [
+    ["SMA 20", formatIndicator(indicators.sma_20)],
+    ["EMA 12", formatIndicator(indicators.ema_12)],
+    ["RSI", formatIndicator(indicators.rsi_14)],
+  ]
+    .filter(([, value]) => value !== null)
+    .map(([name, value]) => `${name}: ${value}`);
+  if (studies.length > 0) {
+    lines.push(studies.join(" | "));
+  }
+
+  for (const position of positions) {
+    lines.push(
+      `Position: ${position.side} ${position.size} @ ${position.entry_price}`,
+    );
+  }
+  for (const signal of signals) {
+    lines.push(
+      `Signal: ${signal.action} (${escapeMarkdown(signal.type)}, ` +
+        `${(signal.confidence * 100).toFixed(0)}%)`,
+    );
+  }
+  return lines.join("\n");
+}
+
+export function registerChartCommand(bot: Bot, api: BackendApiClient): void {
+  bot.command("chart", async (ctx) => {
+    const args = ctx.message?.text.split(/\s+/).slice(1) || [];
+    const symbol = args[0];
+    if (!symbol) {
+      await ctx.reply(CHART_USAGE);
+      return;
+    }
+    const timeframe = args[1]?.toLowerCase() || DEFAULT_TIMEFRAME;
+
+    try {
+      const response = await api.getChart(symbol, timeframe);
+      await ctx.replyWithPhoto(
+        new InputFile(Buffer.from(response.data.image, "base64"), "chart.png"),
+        { caption: formatChartCaption(response), parse_mode: "Markdown" },
+      );
+    } catch (error) {
+      const message =
+        error instanceof ApiClientError
+          ? error.message
+          : "Unable to render chart. Please try again.";
+      await ctx.reply(message);
+    }
+  });
+}
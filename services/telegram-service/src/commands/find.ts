@@ -0,0 +1,52 @@
+import type { Bot } from "grammy";
+import { ApiClientError, type BackendApiClient } from "../api/client";
+import type { SearchResponse } from "../api/types";
+
+const FIND_LIMIT = 10;
+const FIND_USAGE =
+  "Usage: /find <query>\n" +
+  "Qualifiers: symbol: strategy: quest: error: in:trades,signals,quests,logs\n" +
+  "Example: /find symbol:BTCUSDT stop_loss";
+
+const KIND_ICONS: Record<string, string> = {
+  trade: "💹",
+  signal: "📡",
+  quest: "🎯",
+  log: "⚠️",
+};
+
+export function formatSearchResults(data: SearchResponse["data"]): string {
+  if (data.results.length === 0) {
+    return `No results for "${data.query}".`;
+  }
+  const lines = [`🔎 ${data.results.length} results for "${data.query}"`, ""];
+  for (const result of data.results) {
+    const at = new Date(result.at).toISOString().slice(0, 16).replace("T", " ");
+    lines.push(`${KIND_ICONS[result.kind] || "•"} ${result.title} (${at})`);
+    if (result.snippet) {
+      lines.push(`   ${result.snippet}`);
+    }
+  }
+  return lines.join("\n");
+}
+
+export function registerFindCommand(bot: Bot, api: BackendApiClient): void {
+  bot.command("find", async (ctx) => {
+    const query = ctx.message?.text.split(/\s+/).slice(1).join(" ").trim();
+    if (!query) {
+      await ctx.reply(FIND_USAGE);
+      return;
+    }
+
+    try {
+      const response = await api.search(query, FIND_LIMIT);
+      await ctx.reply(formatSearchResults(response.data));
+    } catch (error) {
+      const message =
+        error instanceof ApiClientError
+          ? error.message
+          : "Unable to search. Please try again.";
+      await ctx.reply(message);
+    }
+  });
+}
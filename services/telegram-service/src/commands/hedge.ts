@@ -0,0 +1,97 @@
+import type { Bot } from "grammy";
+import { ApiClientError, type BackendApiClient } from "../api/client";
+import type {
+  ConfirmHedgeResponse,
+  HedgeSuggestionsResponse,
+} from "../api/types";
+
+const HEDGE_USAGE =
+  "Confirm a hedge with /hedge confirm <id>. " +
+  "Nothing is traded until you confirm.";
+
+function escapeMarkdown(text: string): string {
+  return text.replace(/_/g, "\\_");
+}
+
+export function formatHedgeSuggestions(
+  response: HedgeSuggestionsResponse,
+): string {
+  const { positions, suggestions, notes } = response.data;
+  const header = "🛡️ *Hedge Suggestions*\n\n";
+  const noteLines =
+    notes && notes.length > 0
+      ? `\n\n${notes.map((note) => `⚠️ ${escapeMarkdown(note)}`).join("\n")}`
+      : "";
+
+  if (suggestions.length === 0) {
+    return (
+      header +
+      `No correlated clusters to hedge across ${positions} open position(s).` +
+      noteLines
+    );
+  }
+
+  const blocks = suggestions.map(
+    (s) =>
+      `*${s.id}*: ${s.side} ${s.amount} ${escapeMarkdown(s.symbol)} (~${s.notional} USDT)\n` +
+      `Cluster: ${escapeMarkdown(s.cluster.symbols.join(", "))} ` +
+      `(${s.cluster.direction}, ${s.cluster.exposure} USDT, ` +
+      `corr ${s.cluster.avg_correlation.toFixed(2)})\n` +
+      `Expires: ${new Date(s.expires_at).toUTCString()}`,
+  );
+  return header + blocks.join("\n\n") + noteLines + `\n\n${HEDGE_USAGE}`;
+}
+
+export function formatConfirmedHedge(response: ConfirmHedgeResponse): string {
+  const { suggestion, order_id } = response.data;
+  return (
+    "✅ *Hedge opened*\n\n" +
+    `${suggestion.side} ${suggestion.amount} ${escapeMarkdown(suggestion.symbol)}\n` +
+    `Order: ${escapeMarkdown(order_id)}`
+  );
+}
+
+export function registerHedgeCommand(bot: Bot, api: BackendApiClient): void {
+  bot.command("hedge", async (ctx) => {
+    const chatId = ctx.chat?.id;
+    if (!chatId) {
+      await ctx.reply("Unable to load hedge suggestions.");
+      return;
+    }
+
+    const args = ctx.message?.text.split(/\s+/).slice(1) || [];
+    const action = args[0]?.toLowerCase();
+
+    try {
+      switch (action) {
+        case undefined: {
+          const response = await api.getHedgeSuggestions(String(chatId));
+          await ctx.reply(formatHedgeSuggestions(response), {
+            parse_mode: "Markdown",
+          });
+          return;
+        }
+        case "confirm": {
+          const id = args[1];
+          if (!id) {
+            await ctx.reply("Usage: /hedge confirm <id>");
+            return;
+          }
+          const response = await api.confirmHedge(String(chatId), id);
+          await ctx.reply(formatConfirmedHedge(response), {
+            parse_mode: "Markdown",
+          });
+          return;
+        }
+        default:
+          await ctx.reply(`Unknown action: ${action}\n\n${HEDGE_USAGE}`);
+      }
+    } catch (error) {
+      const message =
+        error instanceof ApiClientError
+          ? error.message
+          : "Unable to load hedge suggestions. Please try again.";
+      await ctx.reply(message);
+    }
+  });
+}
@@ -13,13 +13,24 @@ export function registerHelpCommand(bot: Bot): void {
       "/ai_status - Show your AI configuration\n" +
       "/ai_route [fast|balanced|accurate] - Auto-select best model\n\n" +
       "⚡ Autonomous Trading\n" +
-      "/begin - Start autonomous mode\n" +
+      "/begin [profile] - Start autonomous mode\n" +
+      "/profile - Conservative, balanced, aggressive or custom limits\n" +
       "/pause - Pause autonomous mode\n" +
+      "/resume - Resume trading held after a crash\n" +
       "/doctor - Run diagnostics\n\n" +
       "📊 Portfolio & Performance\n" +
       "/summary - 24h performance summary\n" +
       "/performance - Strategy breakdown\n" +
-      "/portfolio - View current portfolio\n\n" +
+      "/pnl [24h|7d|30d] - PnL report\n" +
+      "/share [24h|7d|30d] [absolute] - Public performance link\n" +
+      "/portfolio - View current portfolio\n" +
+      "/watchlist - Manage the symbols you trade\n" +
+      "/alerts - Custom price and indicator alerts\n" +
+      "/allocation - Split capital between strategies\n" +
+      "/hedge - Hedge correlated positions\n" +
+      "/chart - Candle chart with your positions\n" +
+      "/find <query> - Search trades, signals, quests and logs\n" +
+      "/carry [symbol] [days] - Funding and basis carry report\n\n" +
       "💳 Wallets & Exchanges\n" +
       "/wallet - View connected wallets\n" +
       "/connect_exchange - Connect exchange\n" +
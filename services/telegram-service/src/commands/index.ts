@@ -9,6 +9,16 @@ import { registerSettingsCommands } from "./settings";
 import { registerBdCommands } from "./bd";
 import { registerAICommands } from "./ai";
 import { registerAlertsCommands } from "./alerts";
+import { registerNotificationActions } from "./actions";
+import { registerWatchlistCommand } from "./watchlist";
+import { registerAllocationCommand } from "./allocation";
+import { registerProfileCommand } from "./profile";
+import { registerHedgeCommand } from "./hedge";
+import { registerChartCommand } from "./chart";
+import { registerPnLCommand } from "./pnl";
+import { registerShareCommand } from "./share";
+import { registerFindCommand } from "./find";
+import { registerCarryCommand } from "./carry";
 
 export { registerStartCommand } from "./start";
 export { registerHelpCommand } from "./help";
@@ -18,6 +28,16 @@ export { registerSettingsCommands } from "./settings";
 export { registerBdCommands } from "./bd";
 export { registerAICommands } from "./ai";
 export { registerAlertsCommands } from "./alerts";
+export { registerNotificationActions } from "./actions";
+export { registerWatchlistCommand } from "./watchlist";
+export { registerAllocationCommand } from "./allocation";
+export { registerProfileCommand } from "./profile";
+export { registerHedgeCommand } from "./hedge";
+export { registerChartCommand } from "./chart";
+export { registerPnLCommand } from "./pnl";
+export { registerShareCommand } from "./share";
+export { registerFindCommand } from "./find";
+export { registerCarryCommand } from "./carry";
 
 export function registerAllCommands(
   bot: Bot,
@@ -32,4 +52,14 @@ export function registerAllCommands(
   registerBdCommands(bot, api, sessions);
   registerAICommands(bot, api);
   registerAlertsCommands(bot, api);
+  registerNotificationActions(bot, api);
+  registerWatchlistCommand(bot, api);
+  registerAllocationCommand(bot, api);
+  registerProfileCommand(bot, api);
+  registerHedgeCommand(bot, api);
+  registerChartCommand(bot, api);
+  registerPnLCommand(bot, api);
+  registerShareCommand(bot, api);
+  registerFindCommand(bot, api);
+  registerCarryCommand(bot, api);
 }
@@ -0,0 +1,32 @@
+import type { Bot } from "grammy";
+import { ApiClientError, type BackendApiClient } from "../api/client";
+
+const PNL_PERIODS = ["24h", "7d", "30d"];
+
+export function registerPnLCommand(bot: Bot, api: BackendApiClient): void {
+  bot.command("pnl", async (ctx) => {
+    const chatId = ctx.chat?.id;
+    if (!chatId) {
+      await ctx.reply("Unable to load PnL report.");
+      return;
+    }
+
+    const args = ctx.message?.text.split(/\s+/).slice(1) || [];
+    const period = args[0]?.toLowerCase() || "24h";
+    if (!PNL_PERIODS.includes(period)) {
+      await ctx.reply("Usage: /pnl [24h|7d|30d]");
+      return;
+    }
+
+    try {
+      const response = await api.getPnL(String(chatId), period);
+      await ctx.reply(response.data.text);
+    } catch (error) {
+      const message =
+        error instanceof ApiClientError
+          ? error.message
+          : "Unable to load PnL report. Please try again.";
+      await ctx.reply(message);
+    }
+  });
+}
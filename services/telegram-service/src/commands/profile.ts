@@ -0,0 +1,155 @@
+import type { Bot } from "grammy";
+import { ApiClientError, type BackendApiClient } from "../api/client";
+import type { OperatingProfile, ProfileListResponse } from "../api/types";
+
+const PROFILE_USAGE =
+  "*Commands:*\n" +
+  "/profile <name> - trade with a profile\n" +
+  "/profile save <name> [base=balanced] [loss=1.5] [confidence=0.75] " +
+  "[position=3] [scan=300] [ai=low|standard|high]\n" +
+  "/profile delete <name>\n\n" +
+  "Start with a profile directly: /begin conservative";
+
+// Setting keys accepted by /profile save and the profile fields they set.
+const PROFILE_SETTINGS: Readonly<Record<string, keyof OperatingProfile>> = {
+  loss: "daily_loss_pct",
+  confidence: "min_confidence",
+  position: "max_position_pct",
+  scan: "scan_interval_seconds",
+};
+
+function escapeMarkdown(text: string): string {
+  return text.replace(/[_*`[]/g, "\\$&");
+}
+
+export function formatProfileSettings(profile: OperatingProfile): string {
+  return (
+    `loss cap ${profile.daily_loss_pct}%/day, ` +
+    `confidence ≥ ${profile.min_confidence}, ` +
+    `position ≤ ${profile.max_position_pct}%, ` +
+    `scan every ${profile.scan_interval_seconds}s, ` +
+    `AI usage ${profile.ai_usage}`
+  );
+}
+
+export function formatProfiles(response: ProfileListResponse): string {
+  const { active, profiles } = response.data;
+  const lines = profiles.map((profile) => {
+    const marker = profile.name === active ? "✅" : "▫️";
+    const kind = profile.built_in ? "" : " (custom)";
+    return (
+      `${marker} *${escapeMarkdown(profile.name)}*${kind}\n` +
+      `   ${formatProfileSettings(profile)}`
+    );
+  });
+  const current = active
+    ? `Trading with: *${escapeMarkdown(active)}*`
+    : "No profile selected: the server defaults apply.";
+  return (
+    "🎛️ *Operating Profiles*\n\n" +
+    `${current}\n\n` +
+    `${lines.join("\n")}\n\n` +
+    PROFILE_USAGE
+  );
+}
+
+/**
+ * Parses "key=value" settings of /profile save into a base profile name and
+ * the fields of the custom profile.
+ */
+export function parseProfileSettings(
+  args: readonly string[],
+): { base: string; fields: Partial<OperatingProfile> } | string {
+  let base = "";
+  const fields: Record<string, string | number> = {};
+  for (const arg of args) {
+    const [key, raw] = arg.split("=");
+    const name = (key ?? "").toLowerCase();
+    if (!raw) {
+      return `Invalid setting: ${arg} (use key=value)`;
+    }
+    if (name === "base") {
+      base = raw.toLowerCase();
+      continue;
+    }
+    if (name === "ai") {
+      fields.ai_usage = raw.toLowerCase();
+      continue;
+    }
+    const field = PROFILE_SETTINGS[name];
+    const value = Number(raw.replace(/%$/, ""));
+    if (!field || !Number.isFinite(value)) {
+      return `Invalid setting: ${arg}`;
+    }
+    fields[field] = value;
+  }
+  return { base, fields: fields as Partial<OperatingProfile> };
+}
+
+export function registerProfileCommand(
+  bot: Bot,
+  api: BackendApiClient,
+): void {
+  bot.command("profile", async (ctx) => {
+    const chatId = ctx.chat?.id;
+    if (!chatId) {
+      await ctx.reply("Unable to load your operating profiles.");
+      return;
+    }
+
+    const args = ctx.message?.text.split(/\s+/).slice(1) || [];
+    const action = args[0]?.toLowerCase();
+
+    try {
+      switch (action) {
+        case undefined:
+          break;
+        case "save": {
+          const name = args[1];
+          const settings = parseProfileSettings(args.slice(2));
+          if (!name || typeof settings === "string") {
+            await ctx.reply(
+              typeof settings === "string"
+                ? settings
+                : "Usage: /profile save <name> [base=balanced] [loss=1.5] [scan=300]",
+            );
+            return;
+          }
+          const saved = await api.saveProfile(String(chatId), settings.base, {
+            ...settings.fields,
+            name,
+          });
+          await ctx.reply(
+            `💾 Saved ${saved.data.name}: ${formatProfileSettings(saved.data)}\n` +
+              `Trade with it using /profile ${saved.data.name}`,
+          );
+          return;
+        }
+        case "delete": {
+          if (!args[1]) {
+            await ctx.reply("Usage: /profile delete <name>");
+            return;
+          }
+          await api.deleteProfile(String(chatId), args[1]);
+          await ctx.reply(`🗑️ Profile ${args[1]} deleted.`);
+          return;
+        }
+        default: {
+          const selected = await api.selectProfile(String(chatId), action);
+          await ctx.reply(
+            `🎛️ Trading with the ${selected.data.name} profile: ${formatProfileSettings(selected.data)}`,
+          );
+          return;
+        }
+      }
+      const response = await api.getProfiles(String(chatId));
+      await ctx.reply(formatProfiles(response), { parse_mode: "Markdown" });
+    } catch (error) {
+      const message =
+        error instanceof ApiClientError
+          ? error.message
+          : "Unable to update your operating profile. Please try again.";
+      await ctx.reply(message);
+    }
+  });
+}
@@ -0,0 +1,49 @@
+import type { Bot } from "grammy";
+import { ApiClientError, type BackendApiClient } from "../api/client";
+
+const SHARE_PERIODS = ["24h", "7d", "30d"];
+const SHARE_USAGE = "Usage: /share [24h|7d|30d] [absolute]";
+
+export function registerShareCommand(bot: Bot, api: BackendApiClient): void {
+  bot.command("share", async (ctx) => {
+    const chatId = ctx.chat?.id;
+    if (!chatId) {
+      await ctx.reply("Unable to create share link.");
+      return;
+    }
+
+    const args = (ctx.message?.text.split(/\s+/).slice(1) || []).map((arg) =>
+      arg.toLowerCase(),
+    );
+    const period = args.find((arg) => SHARE_PERIODS.includes(arg)) || "7d";
+    const showAbsolute = args.includes("absolute");
+    if (
+      args.some((arg) => !SHARE_PERIODS.includes(arg) && arg !== "absolute")
+    ) {
+      await ctx.reply(SHARE_USAGE);
+      return;
+    }
+
+    try {
+      const response = await api.createShareLink(
+        String(chatId),
+        period,
+        showAbsolute,
+      );
+      const expires = new Date(response.data.link.expires_at);
+      await ctx.reply(
+        `🔗 Read-only performance (${period}):\n${response.data.url}\n\n` +
+          `Expires: ${expires.toUTCString()}\n` +
+          (showAbsolute
+            ? "Shows PnL and equity in USDT."
+            : "Balances are hidden; add 'absolute' to show them."),
+      );
+    } catch (error) {
+      const message =
+        error instanceof ApiClientError
+          ? error.message
+          : "Unable to create share link. Please try again.";
+      await ctx.reply(message);
+    }
+  });
+}
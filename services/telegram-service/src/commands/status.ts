@@ -1,6 +1,60 @@
 import type { Bot } from "grammy";
 import type { BackendApiClient } from "../api/client";
 
+// Lists strategies throttled after consecutive losses; empty when none are
+// or the lookup fails, so /status still answers
+async function formatLossStreaks(
+  api: BackendApiClient,
+  chatId: string,
+): Promise<string> {
+  try {
+    const { data } = await api.getLossStreaks(chatId);
+    const lines = Object.entries(data.state.strategies)
+      .filter(([, streak]) => streak.throttled_until)
+      .map(([strategy, streak]) => {
+        const until = new Date(streak.throttled_until!).toUTCString();
+        return streak.action === "pause"
+          ? `⏸ ${strategy}: paused until ${until}`
+          : `📉 ${strategy}: size ${Math.round(streak.multiplier * 100)}% until ${until}`;
+      });
+    if (lines.length === 0) {
+      return "";
+    }
+    return (
+      `\n\n🛡 Loss streak throttles (${data.max_losses} losses in a row):\n` +
+      lines.join("\n")
+    );
+  } catch {
+    return "";
+  }
+}
+
+const cooldownReasons: Record<string, string> = {
+  stop_out: "stop-out",
+  loss: "loss",
+  exit: "exit",
+  liquidation: "liquidation",
+};
+
+// Lists symbols that cannot be re-entered yet after a recent exit; empty when
+// none are or the lookup fails
+async function formatSymbolCooldowns(api: BackendApiClient): Promise<string> {
+  try {
+    const { data } = await api.getSymbolCooldowns();
+    if (data.cooldowns.length === 0) {
+      return "";
+    }
+    const lines = data.cooldowns.map((cooldown) => {
+      const until = new Date(cooldown.until).toUTCString();
+      const reason = cooldownReasons[cooldown.reason] ?? cooldown.reason;
+      return `⏳ ${cooldown.symbol} (${cooldown.exchange}): after ${reason}, until ${until}`;
+    });
+    return "\n\n🧊 Symbol cooldowns:\n" + lines.join("\n");
+  } catch {
+    return "";
+  }
+}
+
 export function registerStatusCommand(bot: Bot, api: BackendApiClient): void {
   bot.command("status", async (ctx) => {
     const chatId = ctx.chat?.id;
@@ -33,7 +87,9 @@ export function registerStatusCommand(bot: Bot, api: BackendApiClient): void {
         "📊 Account Status:\n\n" +
         `💰 Subscription: ${tier}\n` +
         `📅 Member since: ${createdAt}\n` +
-        `🔔 Notifications: ${notificationStatus}`;
+        `🔔 Notifications: ${notificationStatus}` +
+        (await formatLossStreaks(api, String(chatId))) +
+        (await formatSymbolCooldowns(api));
 
       await ctx.reply(msg);
     } catch {
@@ -0,0 +1,88 @@
+import type { Bot } from "grammy";
+import { ApiClientError, type BackendApiClient } from "../api/client";
+import type { WatchlistAction, WatchlistResponse } from "../api/types";
+
+const WATCHLIST_ACTIONS: readonly WatchlistAction[] = [
+  "add",
+  "remove",
+  "exclude",
+  "include",
+  "clear",
+];
+
+const WATCHLIST_USAGE =
+  "*Commands:*\n" +
+  "/watchlist add BTC/USDT ETH/USDT\n" +
+  "/watchlist remove BTC/USDT\n" +
+  "/watchlist exclude DOGE/USDT\n" +
+  "/watchlist include DOGE/USDT\n" +
+  "/watchlist clear";
+
+function formatSymbols(symbols: readonly string[]): string {
+  return symbols.length > 0 ? symbols.join(", ") : "none";
+}
+
+export function formatWatchlist(response: WatchlistResponse): string {
+  const { watchlist, active_symbols, uses_universe } = response.data;
+  const source = uses_universe
+    ? "Following the top-volume universe (refreshed daily)"
+    : `Watchlist: ${formatSymbols(watchlist.symbols)}`;
+
+  return (
+    "👀 *Your Watchlist*\n\n" +
+    `${source}\n` +
+    `Excluded: ${formatSymbols(watchlist.excluded)}\n\n` +
+    `*Active symbols* (${active_symbols.length}):\n` +
+    `${formatSymbols(active_symbols)}\n\n` +
+    WATCHLIST_USAGE
+  );
+}
+
+export function registerWatchlistCommand(
+  bot: Bot,
+  api: BackendApiClient,
+): void {
+  bot.command("watchlist", async (ctx) => {
+    const chatId = ctx.chat?.id;
+    if (!chatId) {
+      await ctx.reply("Unable to load your watchlist.");
+      return;
+    }
+
+    const args = ctx.message?.text.split(/\s+/).slice(1) || [];
+    const action = args[0]?.toLowerCase();
+    const symbols = args.slice(1).map((s) => s.toUpperCase());
+
+    try {
+      if (!action) {
+        const response = await api.getWatchlist(String(chatId));
+        await ctx.reply(formatWatchlist(response), { parse_mode: "Markdown" });
+        return;
+      }
+
+      if (!WATCHLIST_ACTIONS.includes(action as WatchlistAction)) {
+        await ctx.reply(`Unknown action: ${action}\n\n${WATCHLIST_USAGE}`, {
+          parse_mode: "Markdown",
+        });
+        return;
+      }
+      if (action !== "clear" && symbols.length === 0) {
+        await ctx.reply(`Usage: /watchlist ${action} BTC/USDT [ETH/USDT ...]`);
+        return;
+      }
+
+      const response = await api.updateWatchlist(
+        String(chatId),
+        action as WatchlistAction,
+        symbols,
+      );
+      await ctx.reply(formatWatchlist(response), { parse_mode: "Markdown" });
+    } catch (error) {
+      const message =
+        error instanceof ApiClientError
+          ? error.message
+          : "Unable to update your watchlist. Please try again.";
+      await ctx.reply(message);
+    }
+  });
+}